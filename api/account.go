@@ -1,29 +1,59 @@
 package api
 
 import (
-	"errors"
-	db "go-backend/db/sqlc"
+	"go-backend/service"
 	"go-backend/token"
 	"go-backend/util"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
-	"github.com/lib/pq"
 )
 
 // The `addAccountRoutes` function is a method of the `Server` struct that adds routes for
-// account-related HTTP requests to the `apiRouter` instance of the `gin.RouterGroup` type. It creates
-// a new `accountRouter` instance of the `gin.RouterGroup` type with the base path of "/accounts" and
-// then adds HTTP request handlers for creating, listing, retrieving, updating, and deleting accounts
-// using the `createAccount`, `listAccounts`, `getAccount`, `updateAccount`, and `deleteAccount`
-// methods of the `Server` struct, respectively.
-func (server *Server) addAccountRoutes(apiRouter *gin.RouterGroup) {
+// account-related HTTP requests to the `apiRouter` instance of the `routeGroup` type. It creates a new
+// `accountRouter` instance of the `routeGroup` type with the base path of "/accounts" and then adds HTTP
+// request handlers for creating, listing, retrieving, and deleting accounts using the `createAccount`,
+// `listAccounts`, `getAccount`, and `deleteAccount` methods of the `Server` struct, respectively, as
+// well as getting several accounts at once with `batchGetAccounts`, listing the entries of an account
+// with `listEntries`, its daily balances with `getBalanceHistory` and its daily transfer totals and top
+// counterparties with `getAccountActivity`, exporting its transactions for personal finance applications
+// with `exportAccount`, moving money to another account of its owner with `moveMoney`, changing its
+// currency with `convertAccount`, sharing it with another user with `inviteAccountMember`, listing the
+// holds reserving part of its balance with `listAccountHolds`, getting the QR code paying it with
+// `getReceiveQR`, searching its entries with `searchAccountEntries`, managing the monthly budgets of its
+// spending categories with `listBudgets`, `setBudget` and `deleteBudget`, and its low balance alert with
+// `getAccountAlert`, `setAccountAlert` and `deleteAccountAlert`, and naming it with `renameAccount`.
+// Admins also get the successive values of the fields of an account with `listAccountHistory`, import
+// its history from another system with `importEntries`, correct its balance against the suspense
+// account with `adjustAccount`, and move it to another tier with `setAccountTier`.
+func (server *Server) addAccountRoutes(apiRouter *routeGroup) {
 	accountRouter := apiRouter.Group("/accounts")
 	accountRouter.POST("", server.createAccount)
 	accountRouter.GET("", server.listAccounts)
+	accountRouter.POST("/batch_get", server.batchGetAccounts)
 	accountRouter.GET("/:id", server.getAccount)
-	accountRouter.PUT("/:id", server.updateAccount)
 	accountRouter.DELETE("/:id", server.deleteAccount)
+	accountRouter.GET("/:id/entries", server.listEntries)
+	accountRouter.GET("/:id/balance-history", server.getBalanceHistory)
+	accountRouter.GET("/:id/activity", server.getAccountActivity)
+	accountRouter.GET("/:id/export", server.exportAccount)
+	accountRouter.With(requireScopes(token.ScopeTransfersWrite)).POST("/:id/move", server.signatureMiddleware(), server.moveMoney)
+	accountRouter.POST("/:id/convert", server.convertAccount)
+	accountRouter.POST("/:id/members", server.inviteAccountMember)
+	accountRouter.GET("/:id/holds", server.listAccountHolds)
+	accountRouter.GET("/:id/receive-qr", server.getReceiveQR)
+	accountRouter.GET("/:id/search", server.searchAccountEntries)
+	accountRouter.GET("/:id/budgets", server.listBudgets)
+	accountRouter.PUT("/:id/budgets/:category", server.setBudget)
+	accountRouter.DELETE("/:id/budgets/:category", server.deleteBudget)
+	accountRouter.GET("/:id/alert", server.getAccountAlert)
+	accountRouter.PUT("/:id/alert", server.setAccountAlert)
+	accountRouter.DELETE("/:id/alert", server.deleteAccountAlert)
+	accountRouter.PUT("/:id/nickname", server.renameAccount)
+	accountRouter.With(requireRole(util.AdminRole)).PUT("/:id/tier", server.setAccountTier)
+	accountRouter.With(requireRole(util.AdminRole)).GET("/:id/history", server.listAccountHistory)
+	accountRouter.With(requireRole(util.AdminRole)).POST("/:id/import", server.importEntries)
+	accountRouter.With(requireRole(util.AdminRole)).POST("/:id/adjustments", server.adjustAccount)
 }
 
 // The `createAccountRequest` type is a struct that represents a request to create an account with
@@ -41,37 +71,23 @@ type createAccountRequest struct {
 
 // This is a function that creates a new account for a user. It receives a request with the owner's
 // name and the currency of the account, and then it creates a new account with a balance of 0 using
-// the `CreateAccountParams` struct from the database package. If there is an error during the creation
-// of the account, it returns a 500 Internal Server Error response. Otherwise, it returns a 200 OK
-// response with the newly created account.
+// the `CreateAccount` method of the service. If there is an error during the creation of the account,
+// it returns the response matching the service error. Otherwise, it returns a 200 OK response with the
+// newly created account.
 func (server *Server) createAccount(ctx *gin.Context) {
 	var req createAccountRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, util.ErrorResponse(err))
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
 		return
 	}
 
 	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
-	arg := db.CreateAccountParams{
-		Owner:    authPayload.Username,
-		Currency: req.Currency,
-		Balance:  0,
-	}
-
-	account, err := server.store.CreateAccount(ctx, arg)
-
+	account, err := server.service.CreateAccount(ctx, authPayload.Username, req.Currency)
 	if err != nil {
-		if pqError, ok := err.(*pq.Error); ok {
-			switch pqError.Code.Name() {
-			case "foreign_key_violation", "unique_violation":
-				ctx.JSON(http.StatusForbidden, util.ErrorResponse(err))
-				return
-			}
-		}
-		ctx.JSON(http.StatusInternalServerError, util.ErrorResponse(err))
+		writeError(ctx, err)
 		return
 	}
-	ctx.JSON(http.StatusOK, account)
+	renderJSON(ctx, http.StatusOK, newAccountResponse(account))
 }
 
 // The above code defines a struct type for a GET request to retrieve an account by its ID.
@@ -84,59 +100,106 @@ type getAccountRequest struct {
 
 // This is a function that retrieves an account by its ID. It first extracts the ID from the URI path
 // of the HTTP request. If there is an error during this process, it returns a 400 Bad Request response.
-// Otherwise, it uses the `GetAccount` method from the database package to retrieve the account with the given ID.
-// If there is an error during this process, it returns a 500 Internal Server Error response. Otherwise, it returns
-// a 200 OK response with the retrieved account.
+// Otherwise, it uses the `GetAccount` method of the service to retrieve the account with the given ID,
+// which must belong to the authenticated user. If there is an error during this process, it returns the
+// response matching the service error. Otherwise, it returns a 200 OK response with the retrieved account
+// and its ETag, or a 304 Not Modified response without a body when the If-None-Match header matches it.
 func (server *Server) getAccount(ctx *gin.Context) {
 	var req getAccountRequest
 	if err := ctx.ShouldBindUri(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, util.ErrorResponse(err))
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
 		return
 	}
 
-	account, err := server.store.GetAccount(ctx, req.ID)
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	account, err := server.service.GetAccount(ctx, authPayload.Username, req.ID)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
 
-	if !util.CheckError(ctx, err) {
+	etag := accountETag(account)
+	ctx.Header("ETag", etag)
+	if match := ctx.GetHeader("If-None-Match"); match != "" && etagMatches(match, etag) {
+		ctx.Status(http.StatusNotModified)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, newAccountResponse(account))
+}
+
+// The batchGetAccountsRequest type holds the ids of the accounts to get.
+// @property {[]int64} IDs - between 1 and 100 account ids.
+type batchGetAccountsRequest struct {
+	IDs []int64 `json:"ids" binding:"required,min=1,max=100,dive,min=1"`
+}
+
+// This is a function that gets up to 100 accounts at once, e.g. the accounts of a list of transfers. Only
+// the accounts of the authenticated user are returned, by id: the other ids, including those of accounts
+// that don't exist or belong to another user, are skipped rather than failing the whole request.
+func (server *Server) batchGetAccounts(ctx *gin.Context) {
+	var req batchGetAccountsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
 		return
 	}
 
 	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
-	if account.Owner != authPayload.Username {
-		err := errors.New("account doesn't belong to authenticated user")
-		ctx.JSON(http.StatusUnauthorized, util.ErrorResponse(err))
+	accounts, err := server.service.GetAccounts(ctx, authPayload.Username, req.IDs)
+	if err != nil {
+		writeError(ctx, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, account)
+	renderJSON(ctx, http.StatusOK, newAccountResponses(accounts))
 }
 
+// The listAccountsRequest type holds the query parameters of the account listing on top of the page.
+// @property {string} Sort - the field to sort by, one of balance, created_at or currency. Accounts are
+// listed by id when it is omitted.
+// @property {string} Order - the direction of the sort, asc (the default) or desc.
+// @property {string} Currency - only list the accounts in this currency.
+// @property {int64} MinBalance - only list the accounts with at least this balance.
 type listAccountsRequest struct {
-	PageID   int32 `form:"page_id" binding:"required,min=1"`
-	PageSize int32 `form:"page_size" binding:"required,min=5,max=10"`
+	pageRequest
+	Sort       string `form:"sort" binding:"omitempty,oneof=balance created_at currency"`
+	Order      string `form:"order" binding:"omitempty,oneof=asc desc"`
+	Currency   string `form:"currency" binding:"omitempty,currency"`
+	MinBalance *int64 `form:"min_balance"`
 }
 
+// This is a function that lists the accounts of the authenticated user, optionally filtered by
+// currency and minimum balance and sorted by balance, creation time or currency. Filtering and sorting
+// happen in the database so that pages stay consistent.
 func (server *Server) listAccounts(ctx *gin.Context) {
 	var req listAccountsRequest
 	if err := ctx.ShouldBindQuery(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, util.ErrorResponse(err))
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
 		return
 	}
 
-	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
-	args := db.ListAccountsParams{
-		Owner:  authPayload.Username,
-		Limit:  req.PageSize,
-		Offset: (req.PageID - 1) * req.PageSize,
+	limit, offset, err := server.paginate(paginationAccounts, req.pageRequest)
+	if err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
 	}
 
-	accounts, err := server.store.ListAccounts(ctx, args)
-
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	accounts, err := server.service.ListAccounts(ctx, service.ListAccountsParams{
+		Owner:      authPayload.Username,
+		Currency:   req.Currency,
+		MinBalance: req.MinBalance,
+		SortBy:     req.Sort,
+		SortDesc:   req.Order == "desc",
+		Limit:      limit,
+		Offset:     offset,
+	})
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, util.ErrorResponse(err))
+		writeError(ctx, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, accounts)
+	renderJSON(ctx, http.StatusOK, newAccountResponses(accounts))
 }
 
 // The deleteAccountRequest type is a struct that contains an ID field with URI binding and a minimum
@@ -151,68 +214,181 @@ type deleteAccountRequest struct {
 // The `deleteAccount` function is a method of the `Server` struct that handles HTTP requests to delete
 // an account. It first extracts the account ID from the URI path of the HTTP request using the
 // `ShouldBindUri` method. If there is an error during this process, it returns a 400 Bad Request
-// response. Otherwise, it uses the `DeleteAccount` method from the database package to delete the
-// account with the given ID. If there is an error during this process, it returns a 500 Internal
-// Server Error response. Otherwise, it returns a 200 OK response with a JSON message indicating that
-// the account was successfully deleted.
+// response. Otherwise, it uses the `DeleteAccount` method of the service to delete the account with
+// the given ID. Accounts with entries or transfers are not deleted and get a 409 Conflict response
+// explaining to transfer their balance to another account instead. If there is another error during this
+// process, it returns the response matching the service error. Otherwise, it returns a 200 OK response
+// with a JSON message indicating that the account was successfully deleted.
 func (server *Server) deleteAccount(ctx *gin.Context) {
 	var req deleteAccountRequest
 	if err := ctx.ShouldBindUri(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, util.ErrorResponse(err))
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
 		return
 	}
 
-	err := server.store.DeleteAccount(ctx, req.ID)
+	err := server.service.DeleteAccount(ctx, req.ID)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, gin.H{"message": "successfully delete user"})
+}
+
+type adjustAccountRequest struct {
+	Amount int64  `json:"amount" binding:"required"`
+	Memo   string `json:"memo" binding:"required,max=140"`
+}
+
+// This is a function that corrects the balance of an account by the amount, a debit when it is
+// negative, with a transfer from the suspense account of its currency, to it for a debit, so that the
+// money isn't created or destroyed. It is reserved to admins and works for any account but the system
+// ones, returning the adjusted account. The If-Match header must hold the ETag of the account as last
+// read, the adjustment failing with a 412 status when the account changed since, and a 428 status when
+// the header is missing, so that concurrent updates aren't lost.
+func (server *Server) adjustAccount(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
 
+	var req adjustAccountRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	version, err := ifMatchVersion(ctx.GetHeader("If-Match"))
+	if err != nil {
+		ifMatchError(ctx, err)
+		return
+	}
+
+	result, err := server.service.AdjustAccount(ctx, service.AdjustAccountParams{
+		AccountID: uri.ID,
+		Version:   version,
+		Amount:    req.Amount,
+		Memo:      req.Memo,
+	})
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, util.ErrorResponse(err))
+		writeError(ctx, err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{"message": "successfully delete user"})
+	account := result.ToAccount
+	if req.Amount < 0 {
+		account = result.FromAccount
+	}
+	ctx.Header("ETag", accountETag(account))
+	renderJSON(ctx, http.StatusOK, newAccountResponse(account))
 }
 
-// The above type defines a request to update an account's ID and balance in a Go program.
-// @property {int64} ID - ID is an integer field that represents the unique identifier of an account.
-// @property {int64} Balance - The `Balance` property is an integer that represents the current balance
-// of an account. It is used in the `updateAccountRequest` struct to update the balance of an account
-// identified by its `ID`.
-type updateAccountRequest struct {
-	ID      int64 `uri:"id" binding:"required,min=1"`
-	Balance int64 `json:"balance" binding:"min=0"`
+type renameAccountRequest struct {
+	// empty to remove the nickname
+	Nickname string `json:"nickname" binding:"max=50"`
 }
 
-// The `updateAccount` function is a method of the `Server` struct that handles HTTP requests to update
-// an account's balance. It first extracts the account ID from the URI path of the HTTP request using
-// the `ShouldBindUri` method. If there is an error during this process, it returns a 400 Bad Request
-// response. Otherwise, it uses the `ShouldBindJSON` method to extract the new balance from the request
-// body. If there is an error during this process, it returns a 400 Bad Request response. If the new
-// balance is less than 0, it returns a 400 Bad Request response with an error message. Otherwise, it
-// uses the `UpdateAccount` method from the database package to update the account with the given ID
-// and new balance. If there is an error during this process, it returns a 500 Internal Server Error
-// response. Otherwise, it returns a 200 OK response with the updated account.
-func (server *Server) updateAccount(ctx *gin.Context) {
-	var req updateAccountRequest
-	if err := ctx.ShouldBindUri(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, util.ErrorResponse(err))
+// This is a function that sets the nickname of an account the authenticated user can make transactions
+// with, returning the renamed account. Like an adjustment, it requires the If-Match header to hold the
+// ETag of the account as last read.
+func (server *Server) renameAccount(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
 		return
 	}
 
+	var req renameAccountRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, util.ErrorResponse(err))
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
 		return
 	}
 
-	arg := db.UpdateAccountParams{
-		ID:      req.ID,
-		Balance: req.Balance,
+	version, err := ifMatchVersion(ctx.GetHeader("If-Match"))
+	if err != nil {
+		ifMatchError(ctx, err)
+		return
 	}
 
-	account, err := server.store.UpdateAccount(ctx, arg)
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	account, err := server.service.RenameAccount(ctx, authPayload.Username, uri.ID, version, req.Nickname)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	ctx.Header("ETag", accountETag(account))
+	renderJSON(ctx, http.StatusOK, newAccountResponse(account))
+}
+
+type setAccountTierRequest struct {
+	Tier string `json:"tier" binding:"required,oneof=standard premium"`
+}
+
+// This is a function that moves an account to another tier, returning the account. It is reserved to
+// admins and, like an adjustment, requires the If-Match header to hold the ETag of the account as last
+// read.
+func (server *Server) setAccountTier(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	var req setAccountTierRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	version, err := ifMatchVersion(ctx.GetHeader("If-Match"))
+	if err != nil {
+		ifMatchError(ctx, err)
+		return
+	}
 
+	account, err := server.service.SetAccountTier(ctx, uri.ID, version, req.Tier)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, util.ErrorResponse(err))
+		writeError(ctx, err)
 		return
 	}
-	ctx.JSON(http.StatusOK, account)
+
+	ctx.Header("ETag", accountETag(account))
+	renderJSON(ctx, http.StatusOK, newAccountResponse(account))
+}
+
+type listAccountHistoryRequest struct {
+	pageRequest
+}
+
+// This is a function that lists the successive values of the fields of an account, oldest first, for
+// support and compliance investigations. It is reserved to admins and works for any account, including
+// deleted ones, whose history is kept.
+func (server *Server) listAccountHistory(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	var req listAccountHistoryRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	limit, offset, err := server.paginate(paginationAdmin, req.pageRequest)
+	if err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	versions, err := server.service.ListAccountHistory(ctx, uri.ID, limit, offset)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, versions)
 }
@@ -0,0 +1,108 @@
+package api
+
+import (
+	"go-backend/service"
+	"go-backend/token"
+	"go-backend/util"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// The `addAccountInvitationRoutes` function adds the routes of the invitations of the authenticated user
+// to share the accounts of other users, which they list and accept.
+func (server *Server) addAccountInvitationRoutes(apiRouter *routeGroup) {
+	invitationRouter := apiRouter.Group("/account_invitations")
+	invitationRouter.GET("", server.listAccountInvitations)
+	invitationRouter.POST("/:account_id/accept", server.acceptAccountInvitation)
+}
+
+// The inviteAccountMemberRequest type holds the user invited to share an account and their role.
+// @property {string} Role - owner, to let the user make transactions with the account, or viewer, to let
+// them read it only.
+type inviteAccountMemberRequest struct {
+	Username string `json:"username" binding:"required,alphanum"`
+	Role     string `json:"role" binding:"required,oneof=owner viewer"`
+}
+
+// This is a function that invites a user to share an account of the authenticated user, which they can
+// use with their role once they accept the invitation. Only the users who can make transactions with the
+// account invite, and a user can only be invited once to an account.
+func (server *Server) inviteAccountMember(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	var req inviteAccountMemberRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	member, err := server.service.InviteAccountMember(ctx, service.InviteAccountMemberParams{
+		Owner:     authPayload.Username,
+		AccountID: uri.ID,
+		Username:  req.Username,
+		Role:      req.Role,
+	})
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, member)
+}
+
+type listAccountInvitationsRequest struct {
+	pageRequest
+}
+
+// This is a function that lists the pending invitations of the authenticated user, oldest first.
+func (server *Server) listAccountInvitations(ctx *gin.Context) {
+	var req listAccountInvitationsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	limit, offset, err := server.paginate(paginationInvitations, req.pageRequest)
+	if err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	invitations, err := server.service.ListAccountInvitations(ctx, authPayload.Username, limit, offset)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, invitations)
+}
+
+type accountInvitationURI struct {
+	AccountID int64 `uri:"account_id" binding:"required,min=1"`
+}
+
+// This is a function that accepts the pending invitation of the authenticated user to an account, which
+// is listed with their accounts from then on.
+func (server *Server) acceptAccountInvitation(ctx *gin.Context) {
+	var uri accountInvitationURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	member, err := server.service.AcceptAccountInvitation(ctx, authPayload.Username, uri.AccountID)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, member)
+}
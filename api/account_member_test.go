@@ -0,0 +1,156 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/testutil/factory"
+	"go-backend/util"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInviteAccountMemberAPI(t *testing.T) {
+	user := factory.User()
+	invitee := factory.User()
+	account := factory.Account(factory.OwnedBy(user.Username))
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"username": invitee.Username, "role": "viewer"},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(invitee.Username)).Times(1).Return(invitee, nil)
+				store.EXPECT().
+					CreateAccountMember(gomock.Any(), gomock.Eq(db.CreateAccountMemberParams{
+						AccountID: account.ID,
+						Username:  invitee.Username,
+						Role:      db.AccountRoleViewer,
+						InvitedBy: user.Username,
+					})).
+					Times(1).
+					Return(db.AccountMember{AccountID: account.ID, Username: invitee.Username, Role: db.AccountRoleViewer}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var member db.AccountMember
+				err := json.Unmarshal(recorder.Body.Bytes(), &member)
+				require.NoError(t, err)
+				require.Equal(t, invitee.Username, member.Username)
+				require.False(t, member.AcceptedAt.Valid)
+			},
+		},
+		{
+			name: "InvalidRole",
+			body: gin.H{"username": invitee.Username, "role": "admin"},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireErrorBody(t, recorder.Body, util.ErrorCodeValidationFailed)
+			},
+		},
+		{
+			name: "AlreadyInvited",
+			body: gin.H{"username": invitee.Username, "role": "owner"},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(1).Return(invitee, nil)
+				store.EXPECT().CreateAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(db.AccountMember{}, db.ErrUniqueViolation)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				requireErrorBody(t, recorder.Body, util.ErrorCodeAlreadyExists)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/api/v1/accounts/%d/members", account.ID)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestAccountInvitationsAPI(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	user := factory.User()
+	invitation := db.AccountMember{AccountID: 42, Username: user.Username, Role: db.AccountRoleOwner, InvitedBy: util.RandomOwner()}
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		ListAccountInvitations(gomock.Any(), gomock.Eq(db.ListAccountInvitationsParams{Username: user.Username, Limit: 20})).
+		Times(1).
+		Return([]db.AccountMember{invitation}, nil)
+
+	accepted := invitation
+	accepted.AcceptedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+	acceptArg := db.AcceptAccountMemberParams{AccountID: 42, Username: user.Username}
+	gomock.InOrder(
+		store.EXPECT().AcceptAccountMember(gomock.Any(), gomock.Eq(acceptArg)).Times(1).Return(accepted, nil),
+		store.EXPECT().AcceptAccountMember(gomock.Any(), gomock.Eq(acceptArg)).Times(1).Return(db.AccountMember{}, db.ErrRecordNotFound),
+	)
+
+	server := newTestServer(t, store)
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, "/api/v1/account_invitations", nil)
+	require.NoError(t, err)
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var invitations []db.AccountMember
+	err = json.Unmarshal(recorder.Body.Bytes(), &invitations)
+	require.NoError(t, err)
+	require.Equal(t, []db.AccountMember{invitation}, invitations)
+
+	for _, status := range []int{http.StatusOK, http.StatusNotFound} {
+		recorder = httptest.NewRecorder()
+		request, err = http.NewRequest(http.MethodPost, "/api/v1/account_invitations/42/accept", nil)
+		require.NoError(t, err)
+		addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+		server.router.ServeHTTP(recorder, request)
+
+		require.Equal(t, status, recorder.Code)
+	}
+}
@@ -7,26 +7,32 @@ import (
 	"fmt"
 	mockdb "go-backend/db/mock"
 	db "go-backend/db/sqlc"
+	"go-backend/service"
+	"go-backend/testutil/factory"
 	"go-backend/token"
 	"go-backend/util"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/stretchr/testify/require"
 )
 
 func TestGetAccountAPI(t *testing.T) {
-	user, _ := randomUser(t)
-	account := randomAccount(user.Username)
+	user := factory.User()
+	account := factory.Account(factory.OwnedBy(user.Username))
 
 	testCases := []struct {
 		name          string
 		accountID     int64
+		ifNoneMatch   string
 		setupAuth     func(request *http.Request, tokenMaker token.Maker)
 		buildStub     func(store *mockdb.MockStore)
 		checkResponse func(recorder *httptest.ResponseRecorder)
@@ -40,6 +46,38 @@ func TestGetAccountAPI(t *testing.T) {
 			buildStub: func(store *mockdb.MockStore) {
 				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
 			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, accountETag(account), recorder.Header().Get("ETag"))
+				requireBodyMatchAccount(t, recorder.Body, account)
+			},
+		},
+		{
+			name:        "NotModified",
+			accountID:   account.ID,
+			ifNoneMatch: accountETag(account),
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotModified, recorder.Code)
+				require.Equal(t, accountETag(account), recorder.Header().Get("ETag"))
+				require.Empty(t, recorder.Body.String())
+			},
+		},
+		{
+			name:        "Modified",
+			accountID:   account.ID,
+			ifNoneMatch: `"0", W/"0"`,
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
 				requireBodyMatchAccount(t, recorder.Body, account)
@@ -53,6 +91,7 @@ func TestGetAccountAPI(t *testing.T) {
 			},
 			buildStub: func(store *mockdb.MockStore) {
 				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(db.AccountMember{}, db.ErrRecordNotFound)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
@@ -76,7 +115,7 @@ func TestGetAccountAPI(t *testing.T) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
 			},
 			buildStub: func(store *mockdb.MockStore) {
-				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(db.Account{}, sql.ErrNoRows)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(db.Account{}, db.ErrRecordNotFound)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusNotFound, recorder.Code)
@@ -127,6 +166,9 @@ func TestGetAccountAPI(t *testing.T) {
 			url := fmt.Sprintf("/api/v1/accounts/%d", tc.accountID)
 			request, err := http.NewRequest(http.MethodGet, url, nil)
 			require.NoError(t, err)
+			if tc.ifNoneMatch != "" {
+				request.Header.Set("If-None-Match", tc.ifNoneMatch)
+			}
 
 			tc.setupAuth(request, server.tokenMaker)
 			server.router.ServeHTTP(recorder, request)
@@ -136,8 +178,8 @@ func TestGetAccountAPI(t *testing.T) {
 }
 
 func TestCreateAccountAPI(t *testing.T) {
-	user, _ := randomUser(t)
-	account := randomAccount(user.Username)
+	user := factory.User()
+	account := factory.Account(factory.OwnedBy(user.Username))
 
 	testCases := []struct {
 		name          string
@@ -250,105 +292,158 @@ func TestCreateAccountAPI(t *testing.T) {
 	}
 }
 
-func TestUpdateAccountAPI(t *testing.T) {
-	user, _ := randomUser(t)
-	account := randomAccount(user.Username)
-	balance := util.RandomMoney()
+func TestAdjustAccountAPI(t *testing.T) {
+	admin := factory.User(factory.WithRole(util.AdminRole))
+	user := factory.User()
+	account := factory.Account(factory.OwnedBy(user.Username))
+	suspense := factory.Account(factory.OwnedBy(db.SystemOwner), factory.InCurrency(account.Currency))
+	amount := util.RandomMoney()
 
 	testCases := []struct {
 		name          string
+		username      string
 		body          gin.H
-		accountID     int64
-		setupAuth     func(request *http.Request, tokenMaker token.Maker)
+		ifMatch       string
 		buildStub     func(store *mockdb.MockStore)
 		checkResponse func(recorder *httptest.ResponseRecorder)
 	}{
 		{
-			name: "OK",
-			body: gin.H{
-				"balance": balance,
+			name:     "Credit",
+			username: admin.Username,
+			body:     gin.H{"amount": amount, "memo": "correction"},
+			ifMatch:  accountETag(account),
+			buildStub: func(store *mockdb.MockStore) {
+				updated := account
+				updated.Balance += amount
+				updated.Version++
+
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).Times(1).Return(admin, nil)
+				arg := db.AdjustAccountBalanceTxParams{
+					ID:      account.ID,
+					Amount:  amount,
+					Version: account.Version,
+					Memo:    "correction",
+				}
+				store.EXPECT().AdjustAccountBalanceTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(db.TransferTxResult{
+					FromAccount: suspense,
+					ToAccount:   updated,
+				}, nil)
 			},
-			accountID: account.ID,
-			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				updated := account
+				updated.Balance += amount
+				updated.Version++
+				require.Equal(t, accountETag(updated), recorder.Header().Get("ETag"))
+				requireBodyMatchAccount(t, recorder.Body, updated)
 			},
+		},
+		{
+			name:     "Debit",
+			username: admin.Username,
+			body:     gin.H{"amount": -1, "memo": "correction"},
+			ifMatch:  "*",
 			buildStub: func(store *mockdb.MockStore) {
-				arg := db.UpdateAccountParams{
-					ID:      account.ID,
-					Balance: balance,
+				updated := account
+				updated.Balance--
+				updated.Version++
+
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).Times(1).Return(admin, nil)
+				arg := db.AdjustAccountBalanceTxParams{
+					ID:     account.ID,
+					Amount: -1,
+					Memo:   "correction",
 				}
-				store.EXPECT().UpdateAccount(gomock.Any(), gomock.Eq(arg)).Times(1).Return(db.Account{
-					ID:        account.ID,
-					Balance:   balance,
-					CreatedAt: account.CreatedAt,
-					Owner:     account.Owner,
-					Currency:  account.Currency,
+				store.EXPECT().AdjustAccountBalanceTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(db.TransferTxResult{
+					FromAccount: updated,
+					ToAccount:   suspense,
 				}, nil)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
-				account.Balance = balance // test against a new balance
-				requireBodyMatchAccount(t, recorder.Body, account)
+				updated := account
+				updated.Balance--
+				updated.Version++
+				requireBodyMatchAccount(t, recorder.Body, updated)
 			},
 		},
 		{
-			name: "InternalError",
-			body: gin.H{
-				"balance": balance,
+			name:     "VersionMismatch",
+			username: admin.Username,
+			body:     gin.H{"amount": amount, "memo": "correction"},
+			ifMatch:  accountETag(account),
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).Times(1).Return(admin, nil)
+				store.EXPECT().AdjustAccountBalanceTx(gomock.Any(), gomock.Any()).Times(1).Return(db.TransferTxResult{}, db.ErrAccountVersionMismatch)
 			},
-			accountID: account.ID,
-			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusPreconditionFailed, recorder.Code)
+				requireErrorBody(t, recorder.Body, service.ReasonVersionMismatch)
 			},
+		},
+		{
+			name:     "MissingIfMatch",
+			username: admin.Username,
+			body:     gin.H{"amount": amount, "memo": "correction"},
 			buildStub: func(store *mockdb.MockStore) {
-				arg := db.UpdateAccountParams{
-					ID:      account.ID,
-					Balance: balance,
-				}
-				store.EXPECT().UpdateAccount(gomock.Any(), gomock.Eq(arg)).Times(1).Return(db.Account{}, sql.ErrConnDone)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).Times(1).Return(admin, nil)
+				store.EXPECT().AdjustAccountBalanceTx(gomock.Any(), gomock.Any()).Times(0)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+				require.Equal(t, http.StatusPreconditionRequired, recorder.Code)
+				requireErrorBody(t, recorder.Body, util.ErrorCodePreconditionRequired)
 			},
 		},
 		{
-			name: "InvalidBalance",
-			body: gin.H{
-				"balance": -1,
+			name:     "SystemAccount",
+			username: admin.Username,
+			body:     gin.H{"amount": amount, "memo": "correction"},
+			ifMatch:  "*",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).Times(1).Return(admin, nil)
+				store.EXPECT().AdjustAccountBalanceTx(gomock.Any(), gomock.Any()).Times(1).Return(db.TransferTxResult{}, db.ErrSystemAccount)
 			},
-			accountID: account.ID,
-			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
 			},
+		},
+		{
+			name:     "MissingMemo",
+			username: admin.Username,
+			body:     gin.H{"amount": amount},
+			ifMatch:  accountETag(account),
 			buildStub: func(store *mockdb.MockStore) {
-				arg := db.UpdateAccountParams{
-					ID:      account.ID,
-					Balance: -1,
-				}
-				store.EXPECT().UpdateAccount(gomock.Any(), gomock.Eq(arg)).Times(0)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).Times(1).Return(admin, nil)
+				store.EXPECT().AdjustAccountBalanceTx(gomock.Any(), gomock.Any()).Times(0)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
 		},
 		{
-			name: "InvalidID",
-			body: gin.H{
-				"balance": balance,
+			name:     "InternalError",
+			username: admin.Username,
+			body:     gin.H{"amount": amount, "memo": "correction"},
+			ifMatch:  accountETag(account),
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).Times(1).Return(admin, nil)
+				store.EXPECT().AdjustAccountBalanceTx(gomock.Any(), gomock.Any()).Times(1).Return(db.TransferTxResult{}, sql.ErrConnDone)
 			},
-			accountID: 0,
-			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
 			},
+		},
+		{
+			name:     "Owner",
+			username: user.Username,
+			body:     gin.H{"amount": amount, "memo": "correction"},
+			ifMatch:  accountETag(account),
 			buildStub: func(store *mockdb.MockStore) {
-				arg := db.UpdateAccountParams{
-					ID:      account.ID,
-					Balance: -1,
-				}
-				store.EXPECT().UpdateAccount(gomock.Any(), gomock.Eq(arg)).Times(0)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().AdjustAccountBalanceTx(gomock.Any(), gomock.Any()).Times(0)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Equal(t, http.StatusForbidden, recorder.Code)
 			},
 		},
 	}
@@ -363,18 +458,20 @@ func TestUpdateAccountAPI(t *testing.T) {
 			store := mockdb.NewMockStore(ctrl)
 			tc.buildStub(store)
 
-			// start test server and send request
 			server := newTestServer(t, store)
 			recorder := httptest.NewRecorder()
 
 			data, err := json.Marshal(tc.body)
 			require.NoError(t, err)
 
-			url := fmt.Sprintf("/api/v1/accounts/%d", tc.accountID)
-			request, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(data))
+			url := fmt.Sprintf("/api/v1/accounts/%d/adjustments", account.ID)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
 			require.NoError(t, err)
+			if tc.ifMatch != "" {
+				request.Header.Set("If-Match", tc.ifMatch)
+			}
 
-			tc.setupAuth(request, server.tokenMaker)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.username, time.Minute)
 			server.router.ServeHTTP(recorder, request)
 			tc.checkResponse(recorder)
 		})
@@ -382,8 +479,8 @@ func TestUpdateAccountAPI(t *testing.T) {
 }
 
 func TestDeleteAccountAPI(t *testing.T) {
-	user, _ := randomUser(t)
-	account := randomAccount(user.Username)
+	user := factory.User()
+	account := factory.Account(factory.OwnedBy(user.Username))
 
 	testCases := []struct {
 		name          string
@@ -399,6 +496,7 @@ func TestDeleteAccountAPI(t *testing.T) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
 			},
 			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().AccountHasHistory(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(false, nil)
 				store.EXPECT().DeleteAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(nil)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
@@ -413,12 +511,42 @@ func TestDeleteAccountAPI(t *testing.T) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
 			},
 			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().AccountHasHistory(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(false, nil)
 				store.EXPECT().DeleteAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(sql.ErrConnDone)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusInternalServerError, recorder.Code)
 			},
 		},
+		{
+			name:      "HasHistory",
+			accountID: account.ID,
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().AccountHasHistory(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(true, nil)
+				store.EXPECT().DeleteAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:      "ForeignKeyViolation",
+			accountID: account.ID,
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().AccountHasHistory(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(false, nil)
+				store.EXPECT().DeleteAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).
+					Return(&pgconn.PgError{Code: db.ForeignKeyViolation})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
 		{
 			name:      "InvalidID",
 			accountID: 0,
@@ -426,6 +554,7 @@ func TestDeleteAccountAPI(t *testing.T) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
 			},
 			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().AccountHasHistory(gomock.Any(), gomock.Any()).Times(0)
 				store.EXPECT().DeleteAccount(gomock.Any(), gomock.Any()).Times(0)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
@@ -460,16 +589,20 @@ func TestDeleteAccountAPI(t *testing.T) {
 }
 
 func TestListAccountAPI(t *testing.T) {
-	user, _ := randomUser(t)
+	user := factory.User()
 	n := 5
 	accounts := make([]db.Account, n)
 	for i := 0; i < n; i++ {
-		accounts[i] = randomAccount(user.Username)
+		accounts[i] = factory.Account(factory.OwnedBy(user.Username))
 	}
 
 	type Query struct {
-		pageID   int
-		pageSize int
+		pageID     int
+		pageSize   int
+		sort       string
+		order      string
+		currency   string
+		minBalance string
 	}
 
 	testCases := []struct {
@@ -489,18 +622,36 @@ func TestListAccountAPI(t *testing.T) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
 			},
 			buildStub: func(store *mockdb.MockStore) {
-				arg := db.ListAccountsParams{
-					Owner:  user.Username,
-					Limit:  int32(n),
-					Offset: 0,
+				arg := db.SearchAccountsParams{
+					Owner:     user.Username,
+					RowLimit:  int32(n),
+					RowOffset: 0,
 				}
-				store.EXPECT().ListAccounts(gomock.Any(), gomock.Eq(arg)).Times(1).Return(accounts, nil)
+				store.EXPECT().SearchAccounts(gomock.Any(), gomock.Eq(arg)).Times(1).Return(accounts, nil)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
 				requireBodyMatchAccounts(t, recorder.Body, accounts)
 			},
 		},
+		{
+			name:  "DefaultPagination",
+			query: Query{},
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				arg := db.SearchAccountsParams{
+					Owner:     user.Username,
+					RowLimit:  20,
+					RowOffset: 0,
+				}
+				store.EXPECT().SearchAccounts(gomock.Any(), gomock.Eq(arg)).Times(1).Return(accounts, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
 		{
 			name: "No Authorization",
 			query: Query{
@@ -509,7 +660,7 @@ func TestListAccountAPI(t *testing.T) {
 			},
 			setupAuth: func(request *http.Request, tokenMaker token.Maker) {},
 			buildStub: func(store *mockdb.MockStore) {
-				store.EXPECT().ListAccounts(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().SearchAccounts(gomock.Any(), gomock.Any()).Times(0)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
@@ -525,17 +676,92 @@ func TestListAccountAPI(t *testing.T) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
 			},
 			buildStub: func(store *mockdb.MockStore) {
-				arg := db.ListAccountsParams{
-					Owner:  user.Username,
-					Limit:  int32(n),
-					Offset: 0,
+				arg := db.SearchAccountsParams{
+					Owner:     user.Username,
+					RowLimit:  int32(n),
+					RowOffset: 0,
 				}
-				store.EXPECT().ListAccounts(gomock.Any(), gomock.Eq(arg)).Times(1).Return([]db.Account{}, sql.ErrConnDone)
+				store.EXPECT().SearchAccounts(gomock.Any(), gomock.Eq(arg)).Times(1).Return([]db.Account{}, sql.ErrConnDone)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusInternalServerError, recorder.Code)
 			},
 		},
+		{
+			name: "SortAndFilter",
+			query: Query{
+				pageID:     1,
+				pageSize:   n,
+				sort:       "balance",
+				order:      "desc",
+				currency:   util.USD,
+				minBalance: "100",
+			},
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				arg := db.SearchAccountsParams{
+					Owner:      user.Username,
+					Currency:   pgtype.Text{String: util.USD, Valid: true},
+					MinBalance: pgtype.Int8{Int64: 100, Valid: true},
+					SortBy:     "balance",
+					SortDesc:   true,
+					RowLimit:   int32(n),
+					RowOffset:  0,
+				}
+				store.EXPECT().SearchAccounts(gomock.Any(), gomock.Eq(arg)).Times(1).Return(accounts, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchAccounts(t, recorder.Body, accounts)
+			},
+		},
+		{
+			name: "InvalidSort",
+			query: Query{
+				sort: "owner",
+			},
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().SearchAccounts(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidCurrency",
+			query: Query{
+				currency: "XYZ",
+			},
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().SearchAccounts(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidMinBalance",
+			query: Query{
+				minBalance: "lots",
+			},
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().SearchAccounts(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
 		{
 			name: "InvalidPageID",
 			query: Query{
@@ -546,12 +772,12 @@ func TestListAccountAPI(t *testing.T) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
 			},
 			buildStub: func(store *mockdb.MockStore) {
-				arg := db.ListAccountsParams{
-					Owner:  user.Username,
-					Limit:  int32(n),
-					Offset: 0,
+				arg := db.SearchAccountsParams{
+					Owner:     user.Username,
+					RowLimit:  int32(n),
+					RowOffset: 0,
 				}
-				store.EXPECT().ListAccounts(gomock.Any(), gomock.Eq(arg)).Times(0)
+				store.EXPECT().SearchAccounts(gomock.Any(), gomock.Eq(arg)).Times(0)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
@@ -567,12 +793,12 @@ func TestListAccountAPI(t *testing.T) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
 			},
 			buildStub: func(store *mockdb.MockStore) {
-				arg := db.ListAccountsParams{
-					Owner:  user.Username,
-					Limit:  int32(n),
-					Offset: 0,
+				arg := db.SearchAccountsParams{
+					Owner:     user.Username,
+					RowLimit:  int32(n),
+					RowOffset: 0,
 				}
-				store.EXPECT().ListAccounts(gomock.Any(), gomock.Eq(arg)).Times(0)
+				store.EXPECT().SearchAccounts(gomock.Any(), gomock.Eq(arg)).Times(0)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
@@ -600,8 +826,22 @@ func TestListAccountAPI(t *testing.T) {
 
 			// Add query parameters to request URL
 			q := request.URL.Query()
-			q.Add("page_id", fmt.Sprintf("%d", tc.query.pageID))
-			q.Add("page_size", fmt.Sprintf("%d", tc.query.pageSize))
+			if tc.query.pageID != 0 {
+				q.Add("page_id", fmt.Sprintf("%d", tc.query.pageID))
+			}
+			if tc.query.pageSize != 0 {
+				q.Add("page_size", fmt.Sprintf("%d", tc.query.pageSize))
+			}
+			for key, value := range map[string]string{
+				"sort":        tc.query.sort,
+				"order":       tc.query.order,
+				"currency":    tc.query.currency,
+				"min_balance": tc.query.minBalance,
+			} {
+				if value != "" {
+					q.Add(key, value)
+				}
+			}
 			request.URL.RawQuery = q.Encode()
 
 			tc.setupAuth(request, server.tokenMaker)
@@ -611,12 +851,491 @@ func TestListAccountAPI(t *testing.T) {
 	}
 }
 
-func randomAccount(owner string) db.Account {
-	return db.Account{
-		ID:       util.RandomInt(1, 1000),
-		Owner:    owner,
-		Balance:  util.RandomMoney(),
-		Currency: util.RandomCurrency(),
+func TestBatchGetAccountsAPI(t *testing.T) {
+	user := factory.User()
+	accounts := []db.Account{factory.Account(factory.OwnedBy(user.Username)), factory.Account(factory.OwnedBy(user.Username))}
+	ids := []int64{accounts[0].ID, accounts[1].ID, accounts[1].ID + 1000}
+
+	tooMany := make([]int64, 101)
+	for i := range tooMany {
+		tooMany[i] = int64(i + 1)
+	}
+
+	testCases := []struct {
+		name          string
+		ids           []int64
+		standby       bool
+		setupAuth     func(request *http.Request, tokenMaker token.Maker)
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			ids:  ids,
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				arg := db.GetAccountsByIDsParams{
+					Owner: user.Username,
+					Ids:   ids,
+				}
+				store.EXPECT().GetAccountsByIDs(gomock.Any(), gomock.Eq(arg)).Times(1).Return(accounts, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchAccounts(t, recorder.Body, accounts)
+			},
+		},
+		{
+			name:    "Standby",
+			ids:     ids,
+			standby: true,
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountsByIDs(gomock.Any(), gomock.Any()).Times(1).Return(accounts, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:      "NoAuthorization",
+			ids:       ids,
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountsByIDs(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "NoIDs",
+			ids:  []int64{},
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountsByIDs(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "TooManyIDs",
+			ids:  tooMany,
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountsByIDs(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidID",
+			ids:  []int64{accounts[0].ID, 0},
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountsByIDs(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			ids:  ids,
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountsByIDs(gomock.Any(), gomock.Any()).Times(1).Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			if tc.standby {
+				server.SetLeadership(fakeLeadership{})
+			}
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{"ids": tc.ids})
+			require.NoError(t, err)
+
+			url := "/api/v1/accounts/batch_get"
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			tc.setupAuth(request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestListAccountHistoryAPI(t *testing.T) {
+	admin := factory.User(factory.WithRole(util.AdminRole))
+	user := factory.User()
+	account := factory.Account(factory.OwnedBy(user.Username))
+
+	versions := []db.AccountHistory{
+		{
+			ID:        1,
+			AccountID: account.ID,
+			Owner:     account.Owner,
+			Currency:  account.Currency,
+			Status:    db.AccountStatusOpen,
+			Nickname:  "Savings",
+			Tier:      db.AccountTierStandard,
+			ValidFrom: account.CreatedAt,
+		},
+	}
+
+	testCases := []struct {
+		name          string
+		username      string
+		query         string
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			username: admin.Username,
+			query:    "?page_id=1&page_size=5",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).Times(1).Return(admin, nil)
+				arg := db.ListAccountHistoryParams{
+					AccountID: account.ID,
+					Limit:     5,
+					Offset:    0,
+				}
+				store.EXPECT().ListAccountHistory(gomock.Any(), gomock.Eq(arg)).Times(1).Return(versions, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got []db.AccountHistory
+				err := json.Unmarshal(recorder.Body.Bytes(), &got)
+				require.NoError(t, err)
+				require.Len(t, got, 1)
+				require.Equal(t, account.Owner, got[0].Owner)
+				require.Equal(t, account.Currency, got[0].Currency)
+				require.Equal(t, db.AccountStatusOpen, got[0].Status)
+				require.Equal(t, "Savings", got[0].Nickname)
+				require.False(t, got[0].ValidTo.Valid)
+			},
+		},
+		{
+			name:     "NoHistory",
+			username: admin.Username,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).Times(1).Return(admin, nil)
+				store.EXPECT().ListAccountHistory(gomock.Any(), gomock.Any()).Times(1).Return([]db.AccountHistory{}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:     "Owner",
+			username: user.Username,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().ListAccountHistory(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/api/v1/accounts/%d/history%s", account.ID, tc.query)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestRenameAccountAPI(t *testing.T) {
+	user := factory.User()
+	account := factory.Account(factory.OwnedBy(user.Username))
+	otherAccount := factory.Account()
+
+	testCases := []struct {
+		name          string
+		accountID     int64
+		body          gin.H
+		ifMatch       string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "OK",
+			accountID: account.ID,
+			body:      gin.H{"nickname": " Savings "},
+			ifMatch:   accountETag(account),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				arg := db.UpdateAccountProfileParams{
+					Nickname: pgtype.Text{String: "Savings", Valid: true},
+					ID:       account.ID,
+					Version:  account.Version,
+				}
+				renamed := account
+				renamed.Nickname = "Savings"
+				store.EXPECT().UpdateAccountProfile(gomock.Any(), gomock.Eq(arg)).Times(1).Return(renamed, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got db.Account
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				require.Equal(t, "Savings", got.Nickname)
+			},
+		},
+		{
+			name:      "VersionMismatch",
+			accountID: account.ID,
+			body:      gin.H{"nickname": "Savings"},
+			ifMatch:   accountETag(account),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().UpdateAccountProfile(gomock.Any(), gomock.Any()).Times(1).Return(db.Account{}, db.ErrAccountVersionMismatch)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusPreconditionFailed, recorder.Code)
+				requireErrorBody(t, recorder.Body, service.ReasonVersionMismatch)
+			},
+		},
+		{
+			name:      "MissingIfMatch",
+			accountID: account.ID,
+			body:      gin.H{"nickname": "Savings"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateAccountProfile(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusPreconditionRequired, recorder.Code)
+				requireErrorBody(t, recorder.Body, util.ErrorCodePreconditionRequired)
+			},
+		},
+		{
+			name:      "UnauthorizedUser",
+			accountID: otherAccount.ID,
+			body:      gin.H{"nickname": "Savings"},
+			ifMatch:   "*",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(otherAccount.ID)).Times(1).Return(otherAccount, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).AnyTimes().Return(db.AccountMember{}, db.ErrRecordNotFound)
+				store.EXPECT().UpdateAccountProfile(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:      "TooLong",
+			accountID: account.ID,
+			body:      gin.H{"nickname": strings.Repeat("a", 51)},
+			ifMatch:   "*",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateAccountProfile(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/api/v1/accounts/%d/nickname", tc.accountID)
+			request, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(data))
+			require.NoError(t, err)
+			if tc.ifMatch != "" {
+				request.Header.Set("If-Match", tc.ifMatch)
+			}
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestSetAccountTierAPI(t *testing.T) {
+	admin := factory.User(factory.WithRole(util.AdminRole))
+	user := factory.User()
+	account := factory.Account(factory.OwnedBy(user.Username))
+
+	testCases := []struct {
+		name          string
+		username      string
+		body          gin.H
+		ifMatch       string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			username: admin.Username,
+			body:     gin.H{"tier": db.AccountTierPremium},
+			ifMatch:  accountETag(account),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).Times(1).Return(admin, nil)
+				arg := db.UpdateAccountProfileParams{
+					Tier:    pgtype.Text{String: db.AccountTierPremium, Valid: true},
+					ID:      account.ID,
+					Version: account.Version,
+				}
+				premium := account
+				premium.Tier = db.AccountTierPremium
+				store.EXPECT().UpdateAccountProfile(gomock.Any(), gomock.Eq(arg)).Times(1).Return(premium, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got db.Account
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				require.Equal(t, db.AccountTierPremium, got.Tier)
+			},
+		},
+		{
+			name:     "VersionMismatch",
+			username: admin.Username,
+			body:     gin.H{"tier": db.AccountTierPremium},
+			ifMatch:  accountETag(account),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).Times(1).Return(admin, nil)
+				store.EXPECT().UpdateAccountProfile(gomock.Any(), gomock.Any()).Times(1).Return(db.Account{}, db.ErrAccountVersionMismatch)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusPreconditionFailed, recorder.Code)
+				requireErrorBody(t, recorder.Body, service.ReasonVersionMismatch)
+			},
+		},
+		{
+			name:     "MissingIfMatch",
+			username: admin.Username,
+			body:     gin.H{"tier": db.AccountTierPremium},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).Times(1).Return(admin, nil)
+				store.EXPECT().UpdateAccountProfile(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusPreconditionRequired, recorder.Code)
+				requireErrorBody(t, recorder.Body, util.ErrorCodePreconditionRequired)
+			},
+		},
+		{
+			name:     "UnsupportedTier",
+			username: admin.Username,
+			body:     gin.H{"tier": "gold"},
+			ifMatch:  "*",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).Times(1).Return(admin, nil)
+				store.EXPECT().UpdateAccountProfile(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "Owner",
+			username: user.Username,
+			body:     gin.H{"tier": db.AccountTierPremium},
+			ifMatch:  "*",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().UpdateAccountProfile(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/api/v1/accounts/%d/tier", account.ID)
+			request, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(data))
+			require.NoError(t, err)
+			if tc.ifMatch != "" {
+				request.Header.Set("If-Match", tc.ifMatch)
+			}
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
 	}
 }
 
@@ -0,0 +1,116 @@
+package api
+
+import (
+	"go-backend/token"
+	"go-backend/util"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultActivityDays is the number of days of activity returned when no period is sent.
+const defaultActivityDays = 30
+
+type getAccountActivityURI struct {
+	AccountID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type getAccountActivityRequest struct {
+	From *time.Time `form:"from" time_format:"2006-01-02" time_utc:"1"`
+	To   *time.Time `form:"to" time_format:"2006-01-02" time_utc:"1"`
+}
+
+type activityDayResponse struct {
+	Date          string `json:"date"`
+	Credits       int64  `json:"credits"`
+	Debits        int64  `json:"debits"`
+	TransferCount int64  `json:"transfer_count"`
+}
+
+type activityCounterpartyResponse struct {
+	AccountID      int64     `json:"account_id"`
+	Owner          string    `json:"owner"`
+	Sent           int64     `json:"sent"`
+	Received       int64     `json:"received"`
+	TransferCount  int64     `json:"transfer_count"`
+	LastTransferAt time.Time `json:"last_transfer_at"`
+}
+
+type accountActivityResponse struct {
+	AccountID      int64                          `json:"account_id"`
+	From           string                         `json:"from"`
+	To             string                         `json:"to"`
+	Credits        int64                          `json:"credits"`
+	Debits         int64                          `json:"debits"`
+	TransferCount  int64                          `json:"transfer_count"`
+	Days           []activityDayResponse          `json:"days"`
+	Counterparties []activityCounterpartyResponse `json:"counterparties"`
+}
+
+// This is a function that returns the activity of an account owned by the authenticated user over a
+// period: the money it received and sent by transfer on each day, oldest first, the totals of the period
+// and the accounts it made the most transfers with. The period defaults to the last 30 days, today
+// included, and covers at most a year. The activity is read from the tables kept by the activity
+// projection of the worker rather than aggregated from the entries, so the latest transfers may be
+// missing for a moment.
+func (server *Server) getAccountActivity(ctx *gin.Context) {
+	var uri getAccountActivityURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	var req getAccountActivityRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	now := time.Now().UTC()
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if req.To != nil {
+		to = *req.To
+	}
+	from := to.AddDate(0, 0, -(defaultActivityDays - 1))
+	if req.From != nil {
+		from = *req.From
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	activity, err := server.service.GetAccountActivity(ctx, authPayload.Username, uri.AccountID, from, to)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	res := accountActivityResponse{
+		AccountID:      uri.AccountID,
+		From:           from.Format(dateFormat),
+		To:             to.Format(dateFormat),
+		Credits:        activity.Credits,
+		Debits:         activity.Debits,
+		TransferCount:  activity.TransferCount,
+		Days:           make([]activityDayResponse, 0, len(activity.Days)),
+		Counterparties: make([]activityCounterpartyResponse, 0, len(activity.Counterparties)),
+	}
+	for _, day := range activity.Days {
+		res.Days = append(res.Days, activityDayResponse{
+			Date:          day.Day.Format(dateFormat),
+			Credits:       day.Credits,
+			Debits:        day.Debits,
+			TransferCount: day.TransferCount,
+		})
+	}
+	for _, counterparty := range activity.Counterparties {
+		res.Counterparties = append(res.Counterparties, activityCounterpartyResponse{
+			AccountID:      counterparty.CounterpartyAccountID,
+			Owner:          counterparty.CounterpartyOwner,
+			Sent:           counterparty.Sent,
+			Received:       counterparty.Received,
+			TransferCount:  counterparty.TransferCount,
+			LastTransferAt: counterparty.LastTransferAt,
+		})
+	}
+	renderJSON(ctx, http.StatusOK, res)
+}
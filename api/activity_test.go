@@ -0,0 +1,199 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/testutil/factory"
+	"go-backend/token"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAccountActivityAPI(t *testing.T) {
+	user := factory.User()
+	otherUser := factory.User()
+	account := factory.Account(factory.OwnedBy(user.Username))
+	counterparty := factory.Account()
+
+	from := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, time.March, 3, 0, 0, 0, 0, time.UTC)
+	lastTransferAt := time.Date(2024, time.March, 3, 14, 30, 0, 0, time.UTC)
+	days := []db.AccountDailyActivity{
+		{AccountID: account.ID, Day: from, Credits: 100, Debits: 0, TransferCount: 1},
+		{AccountID: account.ID, Day: to, Credits: 20, Debits: 50, TransferCount: 3},
+	}
+	counterparties := []db.AccountCounterpartyActivity{
+		{
+			AccountID:             account.ID,
+			CounterpartyAccountID: counterparty.ID,
+			CounterpartyOwner:     counterparty.Owner,
+			Sent:                  50,
+			Received:              120,
+			TransferCount:         4,
+			LastTransferAt:        lastTransferAt,
+		},
+	}
+
+	testCases := []struct {
+		name          string
+		query         string
+		setupAuth     func(request *http.Request, tokenMaker token.Maker)
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			query: "from=2024-03-01&to=2024-03-03",
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				daysArg := db.ListAccountDailyActivityParams{AccountID: account.ID, FromDay: from, ToDay: to}
+				store.EXPECT().ListAccountDailyActivity(gomock.Any(), gomock.Eq(daysArg)).Times(1).Return(days, nil)
+				counterpartiesArg := db.ListAccountCounterpartyActivityParams{AccountID: account.ID, RowLimit: 10}
+				store.EXPECT().ListAccountCounterpartyActivity(gomock.Any(), gomock.Eq(counterpartiesArg)).Times(1).Return(counterparties, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res accountActivityResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &res)
+				require.NoError(t, err)
+				require.Equal(t, account.ID, res.AccountID)
+				require.Equal(t, "2024-03-01", res.From)
+				require.Equal(t, "2024-03-03", res.To)
+				require.Equal(t, int64(120), res.Credits)
+				require.Equal(t, int64(50), res.Debits)
+				require.Equal(t, int64(4), res.TransferCount)
+				require.Equal(t, []activityDayResponse{
+					{Date: "2024-03-01", Credits: 100, Debits: 0, TransferCount: 1},
+					{Date: "2024-03-03", Credits: 20, Debits: 50, TransferCount: 3},
+				}, res.Days)
+				require.Len(t, res.Counterparties, 1)
+				require.Equal(t, counterparty.ID, res.Counterparties[0].AccountID)
+				require.Equal(t, counterparty.Owner, res.Counterparties[0].Owner)
+				require.Equal(t, int64(50), res.Counterparties[0].Sent)
+				require.Equal(t, int64(120), res.Counterparties[0].Received)
+				require.Equal(t, int64(4), res.Counterparties[0].TransferCount)
+				require.True(t, lastTransferAt.Equal(res.Counterparties[0].LastTransferAt))
+			},
+		},
+		{
+			name:  "DefaultPeriod",
+			query: "",
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().
+					ListAccountDailyActivity(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.ListAccountDailyActivityParams) ([]db.AccountDailyActivity, error) {
+						now := time.Now().UTC()
+						today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+						require.Equal(t, today, arg.ToDay)
+						require.Equal(t, today.AddDate(0, 0, -29), arg.FromDay)
+						return []db.AccountDailyActivity{}, nil
+					})
+				store.EXPECT().ListAccountCounterpartyActivity(gomock.Any(), gomock.Any()).Times(1).Return([]db.AccountCounterpartyActivity{}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res accountActivityResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &res)
+				require.NoError(t, err)
+				require.Zero(t, res.TransferCount)
+				require.NotNil(t, res.Days)
+				require.NotNil(t, res.Counterparties)
+			},
+		},
+		{
+			name:  "FromAfterTo",
+			query: "from=2024-03-03&to=2024-03-01",
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "PeriodTooLong",
+			query: "from=2022-01-01&to=2024-01-01",
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "InvalidDate",
+			query: "to=tomorrow",
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "Unauthorized",
+			query: "",
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, otherUser.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(db.AccountMember{}, db.ErrRecordNotFound)
+				store.EXPECT().ListAccountDailyActivity(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().ListAccountCounterpartyActivity(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/api/v1/accounts/%d/activity?%s", account.ID, tc.query)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
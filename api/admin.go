@@ -0,0 +1,222 @@
+package api
+
+import (
+	db "go-backend/db/sqlc"
+	"go-backend/util"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// The `addAdminRoutes` function adds the routes reserved to users with the admin role.
+func (server *Server) addAdminRoutes(apiRouter *routeGroup) {
+	adminRouter := apiRouter.Group("/admin", requireRole(util.AdminRole))
+	adminRouter.GET("/slo", server.getSLOSummary)
+	adminRouter.GET("/analytics/activity", server.getActivityAnalytics)
+	adminRouter.GET("/users", server.listUserOverviews)
+	adminRouter.GET("/users/:username", server.getUserOverview)
+	adminRouter.POST("/users/:username/unlock", server.unlockUser)
+	adminRouter.PUT("/users/:username/api_plan", server.setAPIPlan)
+	adminRouter.DELETE("/users/:username/api_plan", server.removeAPIPlan)
+	adminRouter.GET("/accounts", server.listAccountOverviews)
+	adminRouter.POST("/organizations", server.createOrganization)
+	adminRouter.POST("/parameters", server.publishParameter)
+	adminRouter.GET("/parameters", server.listParameterVersions)
+	adminRouter.GET("/parameters/active", server.listActiveParameters)
+	adminRouter.POST("/ledger/reconcile", server.reconcileLedger)
+	adminRouter.GET("/ledger/anomalies", server.listLedgerAnomalies)
+	adminRouter.GET("/debug/*path", server.debugProcess)
+	server.addReviewRoutes(adminRouter)
+	server.addCardProcessingRoutes(adminRouter)
+	server.addChequeClearingRoutes(adminRouter)
+	server.addImpersonationRoutes(adminRouter)
+	server.addPendingTransferAdminRoutes(adminRouter)
+}
+
+// This is a function that serves the diagnostics of the process to troubleshoot it in production: its
+// runtime, database pool and task queue statistics at /debug/vars, and its profiles at /debug/pprof/.
+func (server *Server) debugProcess(ctx *gin.Context) {
+	// the diagnostics are mounted under /api/v1/admin, the paths of the other versions being rewritten
+	request := ctx.Request
+	if path := v1Path(request.URL.Path); path != request.URL.Path {
+		request = request.Clone(ctx)
+		request.URL.Path = path
+		request.URL.RawPath = ""
+	}
+	server.debug.ServeHTTP(ctx.Writer, request)
+}
+
+// This is a function that reports, for every route that received requests, its service level
+// objectives, how fast it burns its error budget over each window and which burn-rate alerts are firing.
+func (server *Server) getSLOSummary(ctx *gin.Context) {
+	renderJSON(ctx, http.StatusOK, server.metrics.slo.Summary())
+}
+
+type userOverviewResponse struct {
+	Username       string     `json:"username"`
+	FullName       string     `json:"full_name"`
+	Email          string     `json:"email"`
+	AccountCount   int32      `json:"account_count"`
+	LastActivityAt *time.Time `json:"last_activity_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+func newUserOverviewResponse(user db.UserOverview) userOverviewResponse {
+	return userOverviewResponse{
+		Username:       user.Username,
+		FullName:       user.FullName,
+		Email:          user.Email,
+		AccountCount:   user.AccountCount,
+		LastActivityAt: nullTime(user.LastActivityAt),
+		CreatedAt:      user.CreatedAt,
+	}
+}
+
+type accountOverviewResponse struct {
+	AccountID      int64      `json:"account_id"`
+	Owner          string     `json:"owner"`
+	Currency       string     `json:"currency"`
+	Balance        int64      `json:"balance"`
+	TransferCount  int64      `json:"transfer_count"`
+	Volume30d      int64      `json:"volume_30d"`
+	LastActivityAt *time.Time `json:"last_activity_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+func newAccountOverviewResponse(account db.AccountOverview) accountOverviewResponse {
+	return accountOverviewResponse{
+		AccountID:      account.AccountID,
+		Owner:          account.Owner,
+		Currency:       account.Currency,
+		Balance:        account.Balance,
+		TransferCount:  account.TransferCount,
+		Volume30d:      account.Volume30d,
+		LastActivityAt: nullTime(account.LastActivityAt),
+		CreatedAt:      account.CreatedAt,
+	}
+}
+
+// nullTime converts a nullable timestamp to a pointer so that it is rendered as null in json.
+func nullTime(t pgtype.Timestamptz) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+type listUserOverviewsRequest struct {
+	pageRequest
+}
+
+// This is a function that lists the users of the bank with their number of accounts and last activity,
+// read from the user_overview projection rather than the ledger.
+func (server *Server) listUserOverviews(ctx *gin.Context) {
+	var req listUserOverviewsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	limit, offset, err := server.paginate(paginationAdmin, req.pageRequest)
+	if err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	users, err := server.store.ListUserOverviews(ctx, db.ListUserOverviewsParams{
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		renderJSON(ctx, http.StatusInternalServerError, util.ErrorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	res := make([]userOverviewResponse, 0, len(users))
+	for _, user := range users {
+		res = append(res, newUserOverviewResponse(user))
+	}
+	renderJSON(ctx, http.StatusOK, res)
+}
+
+type getUserOverviewRequest struct {
+	Username string `uri:"username" binding:"required,alphanum"`
+}
+
+// This is a function that returns the overview of a single user.
+func (server *Server) getUserOverview(ctx *gin.Context) {
+	var req getUserOverviewRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	user, err := server.store.GetUserOverview(ctx, req.Username)
+	if !util.CheckError(ctx, db.TranslateError(err)) {
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, newUserOverviewResponse(user))
+}
+
+type unlockUserRequest struct {
+	Username string `uri:"username" binding:"required,alphanum"`
+}
+
+// This is a function that lets a user locked out after too many failed logins log in again before their
+// lockout is over.
+func (server *Server) unlockUser(ctx *gin.Context) {
+	var req unlockUserRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	err := server.service.UnlockUser(ctx, req.Username)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, gin.H{"message": "successfully unlocked user"})
+}
+
+type listAccountOverviewsRequest struct {
+	pageRequest
+	Owner string `form:"owner" binding:"omitempty,alphanum"`
+}
+
+// This is a function that lists the accounts of the bank, optionally those of a single owner, with the
+// most active accounts over the last 30 days first. It reads the account_overview projection so that
+// the dashboard doesn't aggregate the entries of every account.
+func (server *Server) listAccountOverviews(ctx *gin.Context) {
+	var req listAccountOverviewsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	limit, offset, err := server.paginate(paginationAdmin, req.pageRequest)
+	if err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	accounts, err := server.store.ListAccountOverviews(ctx, db.ListAccountOverviewsParams{
+		Owner:     pgtype.Text{String: req.Owner, Valid: req.Owner != ""},
+		RowLimit:  limit,
+		RowOffset: offset,
+	})
+	if err != nil {
+		renderJSON(ctx, http.StatusInternalServerError, util.ErrorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	res := make([]accountOverviewResponse, 0, len(accounts))
+	for _, account := range accounts {
+		res = append(res, newAccountOverviewResponse(account))
+	}
+	renderJSON(ctx, http.StatusOK, res)
+}
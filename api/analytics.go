@@ -0,0 +1,191 @@
+package api
+
+import (
+	"context"
+	db "go-backend/db/sqlc"
+	"go-backend/util"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const defaultAnalyticsDays = 7
+
+type requestVolumeKey struct {
+	route string
+	hour  time.Time
+}
+
+type requestVolumeCount struct {
+	requests int64
+	errors   int64
+}
+
+// The requestVolumes type counts the requests of every route per hour until they are flushed to the
+// route_request_volumes table, so that the volumes outlive the process and add up across replicas.
+type requestVolumes struct {
+	mu     sync.Mutex
+	counts map[requestVolumeKey]requestVolumeCount
+}
+
+func newRequestVolumes() *requestVolumes {
+	return &requestVolumes{counts: make(map[requestVolumeKey]requestVolumeCount)}
+}
+
+func (volumes *requestVolumes) observe(route string, status int, at time.Time) {
+	key := requestVolumeKey{route: route, hour: at.UTC().Truncate(time.Hour)}
+
+	volumes.mu.Lock()
+	defer volumes.mu.Unlock()
+
+	count := volumes.counts[key]
+	count.requests++
+	if status >= http.StatusInternalServerError {
+		count.errors++
+	}
+	volumes.counts[key] = count
+}
+
+// The `flush` function adds the pending counts to the database. Counts that could not be written are
+// kept for the next flush.
+func (volumes *requestVolumes) flush(ctx context.Context, store db.Store) error {
+	volumes.mu.Lock()
+	pending := volumes.counts
+	volumes.counts = make(map[requestVolumeKey]requestVolumeCount)
+	volumes.mu.Unlock()
+
+	var flushErr error
+	for key, count := range pending {
+		err := store.AddRouteRequestVolume(ctx, db.AddRouteRequestVolumeParams{
+			Route:    key.route,
+			Hour:     key.hour,
+			Requests: count.requests,
+			Errors:   count.errors,
+		})
+		if err != nil {
+			flushErr = err
+			continue
+		}
+		delete(pending, key)
+	}
+
+	if len(pending) > 0 {
+		volumes.mu.Lock()
+		for key, count := range pending {
+			current := volumes.counts[key]
+			current.requests += count.requests
+			current.errors += count.errors
+			volumes.counts[key] = current
+		}
+		volumes.mu.Unlock()
+	}
+
+	return flushErr
+}
+
+// The `RunRequestVolumeFlusher` function writes the request volumes of the server to the database every
+// `interval` until the context is cancelled, then flushes one last time.
+func (server *Server) RunRequestVolumeFlusher(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			err := server.metrics.volumes.flush(context.Background(), server.store)
+			if err != nil {
+				log.Printf("failed to flush request volumes: %v", err)
+			}
+			return
+		case <-ticker.C:
+			err := server.metrics.volumes.flush(ctx, server.store)
+			if err != nil && ctx.Err() == nil {
+				log.Printf("failed to flush request volumes: %v", err)
+			}
+		}
+	}
+}
+
+type getActivityAnalyticsRequest struct {
+	Days int32 `form:"days" binding:"omitempty,min=1,max=90"`
+}
+
+type transferActivityResponse struct {
+	Transfers int64                            `json:"transfers"`
+	Amount    int64                            `json:"amount"`
+	PeakHour  *db.ListTransferHeatmapRow       `json:"peak_hour"`
+	Heatmap   []db.ListTransferHeatmapRow      `json:"heatmap"`
+	Daily     []db.ListDailyTransferVolumesRow `json:"daily"`
+}
+
+type requestActivityResponse struct {
+	Requests int64                           `json:"requests"`
+	PeakHour *db.ListRequestHeatmapRow       `json:"peak_hour"`
+	Heatmap  []db.ListRequestHeatmapRow      `json:"heatmap"`
+	Routes   []db.ListRouteRequestVolumesRow `json:"routes"`
+}
+
+type activityAnalyticsResponse struct {
+	Since     time.Time                `json:"since"`
+	Transfers transferActivityResponse `json:"transfers"`
+	Requests  requestActivityResponse  `json:"requests"`
+}
+
+// This is a function that reports when the bank is busy over the last `days` days (7 by default), for
+// capacity planning and rate-limit tuning. Transfers and requests are bucketed by ISO day of the week
+// (1 is Monday) and hour of the day in UTC, along with the busiest of those buckets, the transfers of
+// every day and the volume of every route.
+func (server *Server) getActivityAnalytics(ctx *gin.Context) {
+	var req getActivityAnalyticsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+	if req.Days == 0 {
+		req.Days = defaultAnalyticsDays
+	}
+
+	since := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 1-int(req.Days))
+	res := activityAnalyticsResponse{Since: since}
+
+	var err error
+	res.Transfers.Heatmap, err = server.store.ListTransferHeatmap(ctx, since)
+	if err != nil {
+		renderJSON(ctx, http.StatusInternalServerError, util.ErrorResponse(http.StatusInternalServerError, err))
+		return
+	}
+	res.Transfers.Daily, err = server.store.ListDailyTransferVolumes(ctx, since)
+	if err != nil {
+		renderJSON(ctx, http.StatusInternalServerError, util.ErrorResponse(http.StatusInternalServerError, err))
+		return
+	}
+	res.Requests.Heatmap, err = server.store.ListRequestHeatmap(ctx, since)
+	if err != nil {
+		renderJSON(ctx, http.StatusInternalServerError, util.ErrorResponse(http.StatusInternalServerError, err))
+		return
+	}
+	res.Requests.Routes, err = server.store.ListRouteRequestVolumes(ctx, since)
+	if err != nil {
+		renderJSON(ctx, http.StatusInternalServerError, util.ErrorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	for i, bucket := range res.Transfers.Heatmap {
+		res.Transfers.Transfers += bucket.Transfers
+		res.Transfers.Amount += bucket.Amount
+		if res.Transfers.PeakHour == nil || bucket.Transfers > res.Transfers.PeakHour.Transfers {
+			res.Transfers.PeakHour = &res.Transfers.Heatmap[i]
+		}
+	}
+	for i, bucket := range res.Requests.Heatmap {
+		res.Requests.Requests += bucket.Requests
+		if res.Requests.PeakHour == nil || bucket.Requests > res.Requests.PeakHour.Requests {
+			res.Requests.PeakHour = &res.Requests.Heatmap[i]
+		}
+	}
+
+	renderJSON(ctx, http.StatusOK, res)
+}
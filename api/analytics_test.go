@@ -0,0 +1,163 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/testutil/factory"
+	"go-backend/util"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetActivityAnalyticsAPI(t *testing.T) {
+	admin := factory.User(factory.WithRole(util.AdminRole))
+
+	transferHeatmap := []db.ListTransferHeatmapRow{
+		{DayOfWeek: 1, HourOfDay: 9, Transfers: 3, Amount: 300},
+		{DayOfWeek: 1, HourOfDay: 12, Transfers: 7, Amount: 500},
+		{DayOfWeek: 5, HourOfDay: 17, Transfers: 2, Amount: 100},
+	}
+	requestHeatmap := []db.ListRequestHeatmapRow{
+		{DayOfWeek: 2, HourOfDay: 8, Requests: 40},
+		{DayOfWeek: 3, HourOfDay: 8, Requests: 10},
+	}
+	routes := []db.ListRouteRequestVolumesRow{
+		{Route: "POST /api/v1/transfers", Requests: 30, Errors: 1, PeakHourRequests: 20},
+		{Route: "GET /api/v1/accounts", Requests: 20, PeakHourRequests: 15},
+	}
+
+	expectQueries := func(store *mockdb.MockStore, days int) {
+		since := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 1-days)
+		store.EXPECT().ListTransferHeatmap(gomock.Any(), gomock.Eq(since)).Times(1).Return(transferHeatmap, nil)
+		store.EXPECT().ListDailyTransferVolumes(gomock.Any(), gomock.Eq(since)).Times(1).Return([]db.ListDailyTransferVolumesRow{}, nil)
+		store.EXPECT().ListRequestHeatmap(gomock.Any(), gomock.Eq(since)).Times(1).Return(requestHeatmap, nil)
+		store.EXPECT().ListRouteRequestVolumes(gomock.Any(), gomock.Eq(since)).Times(1).Return(routes, nil)
+	}
+
+	testCases := []struct {
+		name          string
+		query         string
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			query: "",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).Times(1).Return(admin, nil)
+				expectQueries(store, defaultAnalyticsDays)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got activityAnalyticsResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &got)
+				require.NoError(t, err)
+				require.Equal(t, int64(12), got.Transfers.Transfers)
+				require.Equal(t, int64(900), got.Transfers.Amount)
+				require.Equal(t, transferHeatmap[1], *got.Transfers.PeakHour)
+				require.Equal(t, int64(50), got.Requests.Requests)
+				require.Equal(t, requestHeatmap[0], *got.Requests.PeakHour)
+				require.Equal(t, routes, got.Requests.Routes)
+			},
+		},
+		{
+			name:  "Days",
+			query: "days=30",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).Times(1).Return(admin, nil)
+				expectQueries(store, 30)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:  "InvalidDays",
+			query: "days=91",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).Times(1).Return(admin, nil)
+				store.EXPECT().ListTransferHeatmap(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "InternalError",
+			query: "",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).Times(1).Return(admin, nil)
+				store.EXPECT().ListTransferHeatmap(gomock.Any(), gomock.Any()).Times(1).Return(nil, sql.ErrConnDone)
+				store.EXPECT().ListRouteRequestVolumes(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/admin/analytics/activity?%s", tc.query), nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, admin.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestFlushRequestVolumes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	volumes := newRequestVolumes()
+
+	at := time.Date(2023, 5, 1, 10, 15, 0, 0, time.UTC)
+	volumes.observe("GET /api/v1/accounts", http.StatusOK, at)
+	volumes.observe("GET /api/v1/accounts", http.StatusInternalServerError, at.Add(30*time.Minute))
+
+	arg := db.AddRouteRequestVolumeParams{
+		Route:    "GET /api/v1/accounts",
+		Hour:     time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC),
+		Requests: 2,
+		Errors:   1,
+	}
+
+	// a failed flush keeps the counts for the next one
+	store.EXPECT().AddRouteRequestVolume(gomock.Any(), gomock.Eq(arg)).Times(1).Return(errors.New("connection refused"))
+	err := volumes.flush(context.Background(), store)
+	require.Error(t, err)
+
+	store.EXPECT().AddRouteRequestVolume(gomock.Any(), gomock.Eq(arg)).Times(1).Return(nil)
+	err = volumes.flush(context.Background(), store)
+	require.NoError(t, err)
+
+	// nothing is left to flush
+	err = volumes.flush(context.Background(), store)
+	require.NoError(t, err)
+}
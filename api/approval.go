@@ -0,0 +1,134 @@
+package api
+
+import (
+	"go-backend/service"
+	"go-backend/token"
+	"go-backend/util"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// The `addApprovalRoutes` function adds the maker-checker approvals of the organization of the
+// authenticated user to its routes: the transfers reaching the approval threshold of the organization
+// await a second approver among its admins, who decide them from their queue.
+func (server *Server) addApprovalRoutes(organizationRouter *routeGroup) {
+	adminRouter := organizationRouter.With(requireRole(util.OrgAdminRole, util.AdminRole))
+	adminRouter.PUT("/approval_threshold", server.setApprovalThreshold)
+	adminRouter.GET("/approvals", server.listApprovals)
+	adminRouter.POST("/approvals/:id/approve", server.approveTransfer)
+	adminRouter.POST("/approvals/:id/reject", server.rejectTransfer)
+}
+
+type setApprovalThresholdRequest struct {
+	ApprovalThreshold *int64 `json:"approval_threshold" binding:"required,min=0"`
+}
+
+// This is a function that sets the amount from which the transfers from the accounts of the organization
+// of the authenticated user, an admin of it, await a second approver. 0 turns the approvals off.
+func (server *Server) setApprovalThreshold(ctx *gin.Context) {
+	var req setApprovalThresholdRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	organization, err := server.service.SetApprovalThreshold(ctx, authOrganization(authPayload), *req.ApprovalThreshold)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, newOrganizationResponse(organization))
+}
+
+type listApprovalsRequest struct {
+	pageRequest
+}
+
+// This is a function that lists the transfers awaiting the approval of the admins of the organization of
+// the authenticated user, oldest first.
+func (server *Server) listApprovals(ctx *gin.Context) {
+	var req listApprovalsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	limit, offset, err := server.paginate(paginationTransfers, req.pageRequest)
+	if err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	pending, err := server.service.ListApprovals(ctx, authOrganization(authPayload), limit, offset)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, pending)
+}
+
+// This is a function that makes a transfer awaiting the approval of the organization of the
+// authenticated user, who approves it as its second approver and can't be its owner. The transfer is
+// checked again before being made.
+func (server *Server) approveTransfer(ctx *gin.Context) {
+	var uri pendingTransferURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	result, err := server.service.ApproveTransfer(ctx, service.DecideApprovalParams{
+		OrgID:    authOrganization(authPayload),
+		Approver: authPayload.Username,
+		ID:       uri.ID,
+	})
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, result)
+}
+
+type rejectTransferRequest struct {
+	Reason string `json:"reason" binding:"max=500"`
+}
+
+// This is a function that rejects a transfer awaiting the approval of the organization of the
+// authenticated user, who can't be its owner, with an optional reason recorded in the audit log.
+func (server *Server) rejectTransfer(ctx *gin.Context) {
+	var uri pendingTransferURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	// the reason is optional, and so is the body
+	var req rejectTransferRequest
+	if ctx.Request.ContentLength != 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+			return
+		}
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	pending, err := server.service.RejectTransfer(ctx, service.DecideApprovalParams{
+		OrgID:    authOrganization(authPayload),
+		Approver: authPayload.Username,
+		ID:       uri.ID,
+		Reason:   req.Reason,
+	})
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, pending)
+}
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"fmt"
+	"go-backend/clock"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/testutil/factory"
+	"go-backend/util"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApproveTransferAPI(t *testing.T) {
+	const orgID = 2
+	orgAdmin := factory.User(factory.InOrganization(orgID), factory.WithRole(util.OrgAdminRole))
+	depositor := factory.User(factory.InOrganization(orgID))
+	fromAccount := factory.Account(factory.OwnedBy(depositor.Username), factory.InCurrency(util.USD), func(account *db.Account) {
+		account.OrgID = orgID
+	})
+	toAccount := factory.Account(factory.InCurrency(util.USD), func(account *db.Account) {
+		account.OrgID = orgID
+	})
+	pending := factory.PendingTransfer(factory.PendingBetween(fromAccount, toAccount), func(pending *db.PendingTransfer) {
+		pending.OrgID = pgtype.Int8{Int64: orgID, Valid: true}
+	})
+
+	now := time.Now()
+
+	testCases := []struct {
+		name       string
+		caller     db.User
+		buildStubs func(store *mockdb.MockStore)
+		status     int
+	}{
+		{
+			name:   "OK",
+			caller: orgAdmin,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(orgAdmin.Username)).Times(1).Return(orgAdmin, nil)
+				store.EXPECT().GetPendingTransfer(gomock.Any(), gomock.Eq(pending.ID)).Times(1).Return(pending, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(2).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().GetActiveBankParameter(gomock.Any(), gomock.Any()).Times(1).Return(db.BankParameter{}, db.ErrRecordNotFound)
+				store.EXPECT().
+					ApprovePendingTransferTx(gomock.Any(), gomock.Eq(db.ApprovePendingTransferTxParams{ID: pending.ID, DecidedBy: orgAdmin.Username, Currency: fromAccount.Currency, Now: now})).
+					Times(1).
+					Return(db.ApprovePendingTransferTxResult{PendingTransfer: pending}, nil)
+				store.EXPECT().CreateAuditEntry(gomock.Any(), gomock.Any()).Times(1).Return(db.AuditEntry{}, nil)
+			},
+			status: http.StatusOK,
+		},
+		{
+			// the maker of the transfer isn't an admin of the organization, so can't be its checker
+			name:   "NotOrgAdmin",
+			caller: depositor,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(depositor.Username)).Times(1).Return(depositor, nil)
+				store.EXPECT().GetPendingTransfer(gomock.Any(), gomock.Any()).Times(0)
+			},
+			status: http.StatusForbidden,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			server.service.SetClock(clock.NewFake(now))
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/api/v1/organizations/me/approvals/%d/approve", pending.ID)
+			request, err := http.NewRequest(http.MethodPost, url, nil)
+			require.NoError(t, err)
+
+			addOrganizationAuthorization(t, request, server.tokenMaker, tc.caller)
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, tc.status, recorder.Code)
+		})
+	}
+}
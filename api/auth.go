@@ -0,0 +1,255 @@
+package api
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-backend/oauth"
+	"go-backend/service"
+	"go-backend/util"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// oauthStateCookie holds the state sent to the identity provider, which must come back with the
+	// user so that a login can't be completed with a code from another browser
+	oauthStateCookie = "oauth_state"
+	oauthStateMaxAge = 10 * 60
+	// oauthLinkCookie holds the identity signed with the OAUTH_LINK_KEY config which is linked to the
+	// user owning its email once they give their password
+	oauthLinkCookie = "oauth_link"
+	oauthLinkMaxAge = 10 * 60
+)
+
+func (server *Server) addAuthRoutes(apiRouter *routeGroup) {
+	authRouter := apiRouter.Group("/auth/:provider", anonymous())
+	authRouter.GET("/login", server.loginWithProvider)
+	authRouter.GET("/callback", server.identityProviderCallback)
+	authRouter.POST("/link", server.linkIdentity)
+}
+
+// newIdentityProviders creates the identity providers of the config, which send the users back to
+// the callback route of the server at the OAUTH_CALLBACK_BASE_URL config.
+func newIdentityProviders(config util.Config) (oauth.Providers, error) {
+	baseURL := strings.TrimSuffix(config.OAuthCallbackBaseURL, "/")
+	providers := oauth.NewProviders(config, func(provider string) string {
+		return baseURL + "/api/v1/auth/" + provider + "/callback"
+	})
+	if len(providers) > 0 && baseURL == "" {
+		return nil, errors.New("OAUTH_CALLBACK_BASE_URL is required to log in with an identity provider")
+	}
+	if len(providers) > 0 && config.OAuthLinkKey == "" {
+		return nil, errors.New("OAUTH_LINK_KEY is required to log in with an identity provider")
+	}
+	return providers, nil
+}
+
+type identityProviderURI struct {
+	Provider string `uri:"provider" binding:"required"`
+}
+
+// identityProvider returns the provider of the route, writing the error response when it isn't
+// enabled.
+func (server *Server) identityProvider(ctx *gin.Context) (string, oauth.Provider, bool) {
+	var uri identityProviderURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return "", nil, false
+	}
+
+	provider, ok := server.identityProviders[uri.Provider]
+	if !ok {
+		err := fmt.Errorf("cannot log in with %s", uri.Provider)
+		renderJSON(ctx, http.StatusNotFound, util.ErrorResponse(http.StatusNotFound, err))
+		return "", nil, false
+	}
+	return uri.Provider, provider, true
+}
+
+// This is a function that sends the user to the login page of an identity provider, e.g. google or
+// github, which sends them back to the callback route.
+func (server *Server) loginWithProvider(ctx *gin.Context) {
+	name, provider, ok := server.identityProvider(ctx)
+	if !ok {
+		return
+	}
+
+	state, err := newOAuthState()
+	if err != nil {
+		renderJSON(ctx, http.StatusInternalServerError, util.ErrorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	// the provider sends the user back with a top-level navigation, which lax cookies are sent with
+	ctx.SetSameSite(http.SameSiteLaxMode)
+	ctx.SetCookie(oauthStateCookie, state, oauthStateMaxAge, "/api/v1/auth/"+name, "", ctx.Request.TLS != nil, true)
+	ctx.Redirect(http.StatusFound, provider.AuthCodeURL(state))
+}
+
+type identityProviderCallbackRequest struct {
+	Code             string `form:"code"`
+	State            string `form:"state" binding:"required"`
+	Error            string `form:"error"`
+	ErrorDescription string `form:"error_description"`
+}
+
+// This is a function that completes a login with an identity provider: the code the provider sent the
+// user back with is exchanged for their identity, which logs in the user linked to it, or registers a
+// user with it, and returns their tokens as `loginUser` does. When a user already owns the email of the
+// identity, the identity is kept in a cookie until they give their password to `linkIdentity`.
+func (server *Server) identityProviderCallback(ctx *gin.Context) {
+	name, provider, ok := server.identityProvider(ctx)
+	if !ok {
+		return
+	}
+
+	var req identityProviderCallbackRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	// the state is used once
+	state, err := ctx.Cookie(oauthStateCookie)
+	ctx.SetSameSite(http.SameSiteLaxMode)
+	ctx.SetCookie(oauthStateCookie, "", -1, "/api/v1/auth/"+name, "", ctx.Request.TLS != nil, true)
+	if err != nil || !hmac.Equal([]byte(state), []byte(req.State)) {
+		err := errors.New("the state of the login doesn't match the one of this browser")
+		renderJSON(ctx, http.StatusUnauthorized, util.ErrorResponse(http.StatusUnauthorized, err))
+		return
+	}
+
+	if req.Error != "" {
+		err := fmt.Errorf("the login was refused by %s: %s %s", name, req.Error, req.ErrorDescription)
+		renderJSON(ctx, http.StatusUnauthorized, util.ErrorResponse(http.StatusUnauthorized, err))
+		return
+	}
+	if req.Code == "" {
+		err := errors.New("code is required")
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	identity, err := provider.Exchange(ctx, req.Code)
+	if err != nil {
+		err := fmt.Errorf("cannot verify the login with %s: %w", name, err)
+		renderJSON(ctx, http.StatusUnauthorized, util.ErrorResponse(http.StatusUnauthorized, err))
+		return
+	}
+
+	result, err := server.service.LoginWithIdentity(ctx, service.LoginWithIdentityParams{
+		Identity:  identity,
+		UserAgent: ctx.Request.UserAgent(),
+		ClientIP:  ctx.ClientIP(),
+	})
+	if err != nil {
+		if service.ErrorReason(err) == service.ReasonIdentityLinkRequired {
+			ctx.SetSameSite(http.SameSiteStrictMode)
+			ctx.SetCookie(oauthLinkCookie, server.signIdentity(identity, time.Now().Add(oauthLinkMaxAge*time.Second)), oauthLinkMaxAge, "/api/v1/auth/"+name, "", ctx.Request.TLS != nil, true)
+		}
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, newLoginUserResponse(result))
+}
+
+type linkIdentityRequest struct {
+	Password string `json:"password" binding:"required"`
+}
+
+// This is a function that links the identity a login with an identity provider was refused for, kept in
+// a cookie by `identityProviderCallback`, to the user owning its email given their password, and returns
+// their tokens as `loginUser` does. The user can then log in with either their password or the provider.
+func (server *Server) linkIdentity(ctx *gin.Context) {
+	name, _, ok := server.identityProvider(ctx)
+	if !ok {
+		return
+	}
+
+	var req linkIdentityRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	cookie, err := ctx.Cookie(oauthLinkCookie)
+	var identity oauth.Identity
+	if err == nil {
+		identity, err = server.verifyIdentity(cookie, time.Now())
+	}
+	if err != nil || identity.Provider != name {
+		err := fmt.Errorf("no %s login is waiting to be linked", name)
+		renderJSON(ctx, http.StatusUnauthorized, util.ErrorResponse(http.StatusUnauthorized, err))
+		return
+	}
+
+	result, err := server.service.LoginWithIdentity(ctx, service.LoginWithIdentityParams{
+		Identity:  identity,
+		Password:  req.Password,
+		UserAgent: ctx.Request.UserAgent(),
+		ClientIP:  ctx.ClientIP(),
+	})
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	// the identity is linked once
+	ctx.SetSameSite(http.SameSiteStrictMode)
+	ctx.SetCookie(oauthLinkCookie, "", -1, "/api/v1/auth/"+name, "", ctx.Request.TLS != nil, true)
+	renderJSON(ctx, http.StatusOK, newLoginUserResponse(result))
+}
+
+// linkedIdentity is the content of the oauth_link cookie.
+type linkedIdentity struct {
+	Identity  oauth.Identity `json:"identity"`
+	ExpiresAt time.Time      `json:"expires_at"`
+}
+
+// signIdentity returns the value of the oauth_link cookie holding the identity until `expiresAt`.
+func (server *Server) signIdentity(identity oauth.Identity, expiresAt time.Time) string {
+	// the identity marshals without error
+	data, _ := json.Marshal(linkedIdentity{Identity: identity, ExpiresAt: expiresAt})
+	message := base64.RawURLEncoding.EncodeToString(data)
+	return message + "." + util.Sign(server.config.OAuthLinkKey, message)
+}
+
+// verifyIdentity returns the identity of an oauth_link cookie, checking that it was signed by
+// `signIdentity` and hasn't expired.
+func (server *Server) verifyIdentity(value string, now time.Time) (oauth.Identity, error) {
+	message, signature, ok := strings.Cut(value, ".")
+	if !ok || server.config.OAuthLinkKey == "" || !util.VerifySignature(server.config.OAuthLinkKey, message, signature) {
+		return oauth.Identity{}, errors.New("invalid signature")
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(message)
+	if err != nil {
+		return oauth.Identity{}, err
+	}
+	var linked linkedIdentity
+	err = json.Unmarshal(data, &linked)
+	if err != nil {
+		return oauth.Identity{}, err
+	}
+	if now.After(linked.ExpiresAt) {
+		return oauth.Identity{}, errors.New("expired")
+	}
+	return linked.Identity, nil
+}
+
+func newOAuthState() (string, error) {
+	b := make([]byte, 32)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
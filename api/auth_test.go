@@ -0,0 +1,405 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/oauth"
+	"go-backend/service"
+	"go-backend/testutil/factory"
+	"go-backend/util"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeProvider is an identity provider whose code "code" is exchanged for its identity.
+type fakeProvider struct {
+	identity oauth.Identity
+}
+
+func (provider fakeProvider) AuthCodeURL(state string) string {
+	return "https://provider.example.com/authorize?state=" + url.QueryEscape(state)
+}
+
+func (provider fakeProvider) Exchange(ctx context.Context, code string) (oauth.Identity, error) {
+	if code != "code" {
+		return oauth.Identity{}, errors.New("invalid code")
+	}
+	return provider.identity, nil
+}
+
+func TestLoginWithProviderAPI(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := newTestServer(t, mockdb.NewMockStore(ctrl))
+	server.identityProviders = oauth.Providers{"fake": fakeProvider{}}
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, "/api/v1/auth/fake/login", nil)
+	require.NoError(t, err)
+	server.router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusFound, recorder.Code)
+	location, err := url.Parse(recorder.Header().Get("Location"))
+	require.NoError(t, err)
+	require.Equal(t, "provider.example.com", location.Host)
+
+	cookies := recorder.Result().Cookies()
+	require.Len(t, cookies, 1)
+	require.Equal(t, oauthStateCookie, cookies[0].Name)
+	require.Equal(t, location.Query().Get("state"), cookies[0].Value)
+	require.Equal(t, "/api/v1/auth/fake", cookies[0].Path)
+	require.True(t, cookies[0].HttpOnly)
+
+	// the providers that aren't configured can't be used
+	recorder = httptest.NewRecorder()
+	request, err = http.NewRequest(http.MethodGet, "/api/v1/auth/google/login", nil)
+	require.NoError(t, err)
+	server.router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusNotFound, recorder.Code)
+	requireErrorBody(t, recorder.Body, util.ErrorCodeNotFound)
+}
+
+func TestIdentityProviderCallbackAPI(t *testing.T) {
+	user := factory.User()
+	identity := oauth.Identity{
+		Provider:      "fake",
+		Subject:       "42",
+		Email:         user.Email,
+		EmailVerified: true,
+	}
+
+	testCases := []struct {
+		name          string
+		query         url.Values
+		state         string
+		unverified    bool
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			query: url.Values{"code": {"code"}, "state": {"state"}},
+			state: "state",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserIdentity(gomock.Any(), gomock.Eq(db.GetUserIdentityParams{Provider: "fake", Subject: "42"})).
+					Times(1).
+					Return(db.UserIdentity{Provider: "fake", Subject: "42", Username: user.Username}, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Body.String(), `"access_token"`)
+
+				// the state cookie is cleared
+				cookies := recorder.Result().Cookies()
+				require.Len(t, cookies, 1)
+				require.Equal(t, oauthStateCookie, cookies[0].Name)
+				require.Negative(t, cookies[0].MaxAge)
+			},
+		},
+		{
+			name:  "LinkRequired",
+			query: url.Values{"code": {"code"}, "state": {"state"}},
+			state: "state",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserIdentity(gomock.Any(), gomock.Any()).Times(1).Return(db.UserIdentity{}, db.ErrRecordNotFound)
+				store.EXPECT().GetUserByEmail(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().CreateUserIdentity(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				requireErrorBody(t, recorder.Body, service.ReasonIdentityLinkRequired)
+
+				// the identity waits for the password of the user
+				var link *http.Cookie
+				for _, cookie := range recorder.Result().Cookies() {
+					if cookie.Name == oauthLinkCookie {
+						link = cookie
+					}
+				}
+				require.NotNil(t, link)
+				require.Equal(t, "/api/v1/auth/fake", link.Path)
+				require.True(t, link.HttpOnly)
+			},
+		},
+		{
+			name:  "StateMismatch",
+			query: url.Values{"code": {"code"}, "state": {"state"}},
+			state: "other",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserIdentity(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				requireErrorBody(t, recorder.Body, util.ErrorCodeUnauthenticated)
+			},
+		},
+		{
+			name:  "NoStateCookie",
+			query: url.Values{"code": {"code"}, "state": {"state"}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserIdentity(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:  "Refused",
+			query: url.Values{"error": {"access_denied"}, "state": {"state"}},
+			state: "state",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserIdentity(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				body := requireErrorBody(t, recorder.Body, util.ErrorCodeUnauthenticated)
+				require.Contains(t, body.Message, "access_denied")
+			},
+		},
+		{
+			name:  "InvalidCode",
+			query: url.Values{"code": {"wrong"}, "state": {"state"}},
+			state: "state",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserIdentity(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:  "NoState",
+			query: url.Values{"code": {"code"}},
+			state: "state",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserIdentity(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:       "EmailNotVerified",
+			query:      url.Values{"code": {"code"}, "state": {"state"}},
+			state:      "state",
+			unverified: true,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserIdentity(gomock.Any(), gomock.Any()).Times(1).Return(db.UserIdentity{}, db.ErrRecordNotFound)
+				store.EXPECT().GetUserByEmail(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				requireErrorBody(t, recorder.Body, util.ErrorCodePermissionDenied)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			provider := fakeProvider{identity: identity}
+			provider.identity.EmailVerified = !tc.unverified
+
+			server := newTestServer(t, store)
+			server.identityProviders = oauth.Providers{"fake": provider}
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/api/v1/auth/fake/callback?"+tc.query.Encode(), nil)
+			require.NoError(t, err)
+			if tc.state != "" {
+				request.AddCookie(&http.Cookie{Name: oauthStateCookie, Value: tc.state})
+			}
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestLinkIdentityAPI(t *testing.T) {
+	user, password := factory.UserWithPassword(t)
+	identity := oauth.Identity{
+		Provider:      "fake",
+		Subject:       "42",
+		Email:         user.Email,
+		EmailVerified: true,
+	}
+
+	testCases := []struct {
+		name          string
+		password      string
+		cookie        func(server *Server) string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			password: password,
+			cookie: func(server *Server) string {
+				return server.signIdentity(identity, time.Now().Add(time.Minute))
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserIdentity(gomock.Any(), gomock.Any()).Times(1).Return(db.UserIdentity{}, db.ErrRecordNotFound)
+				store.EXPECT().GetUserByEmail(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().
+					CreateUserIdentity(gomock.Any(), gomock.Eq(db.CreateUserIdentityParams{
+						Provider: "fake",
+						Subject:  "42",
+						Username: user.Username,
+						Email:    user.Email,
+					})).
+					Times(1)
+				store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Body.String(), `"access_token"`)
+
+				// the link cookie is cleared
+				cookies := recorder.Result().Cookies()
+				require.Len(t, cookies, 1)
+				require.Equal(t, oauthLinkCookie, cookies[0].Name)
+				require.Negative(t, cookies[0].MaxAge)
+			},
+		},
+		{
+			name:     "WrongPassword",
+			password: "wrong-password",
+			cookie: func(server *Server) string {
+				return server.signIdentity(identity, time.Now().Add(time.Minute))
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserIdentity(gomock.Any(), gomock.Any()).Times(1).Return(db.UserIdentity{}, db.ErrRecordNotFound)
+				store.EXPECT().GetUserByEmail(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().CreateUserIdentity(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				requireErrorBody(t, recorder.Body, service.ReasonInvalidCredentials)
+			},
+		},
+		{
+			name:     "NoCookie",
+			password: password,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserIdentity(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:     "Expired",
+			password: password,
+			cookie: func(server *Server) string {
+				return server.signIdentity(identity, time.Now().Add(-time.Second))
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserIdentity(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:     "Forged",
+			password: password,
+			cookie: func(server *Server) string {
+				forged := &Server{config: util.Config{OAuthLinkKey: util.RandomString(32)}}
+				return forged.signIdentity(identity, time.Now().Add(time.Minute))
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserIdentity(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:     "OtherProvider",
+			password: password,
+			cookie: func(server *Server) string {
+				other := identity
+				other.Provider = oauth.ProviderGoogle
+				return server.signIdentity(other, time.Now().Add(time.Minute))
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserIdentity(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServerWithConfig(t, store, nil, func(config *util.Config) {
+				config.OAuthLinkKey = util.RandomString(32)
+			})
+			server.identityProviders = oauth.Providers{"fake": fakeProvider{identity: identity}}
+			recorder := httptest.NewRecorder()
+
+			body, err := json.Marshal(gin.H{"password": tc.password})
+			require.NoError(t, err)
+			request, err := http.NewRequest(http.MethodPost, "/api/v1/auth/fake/link", bytes.NewReader(body))
+			require.NoError(t, err)
+			if tc.cookie != nil {
+				request.AddCookie(&http.Cookie{Name: oauthLinkCookie, Value: tc.cookie(server)})
+			}
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestNewIdentityProviders(t *testing.T) {
+	config := util.Config{GoogleClientID: "client-id", GoogleClientSecret: "client-secret"}
+	_, err := newIdentityProviders(config)
+	require.ErrorContains(t, err, "OAUTH_CALLBACK_BASE_URL")
+
+	config.OAuthCallbackBaseURL = "https://bank.example.com/"
+	_, err = newIdentityProviders(config)
+	require.ErrorContains(t, err, "OAUTH_LINK_KEY")
+
+	config.OAuthLinkKey = util.RandomString(32)
+	providers, err := newIdentityProviders(config)
+	require.NoError(t, err)
+
+	authURL, err := url.Parse(providers[oauth.ProviderGoogle].AuthCodeURL("state"))
+	require.NoError(t, err)
+	require.Equal(t, "https://bank.example.com/api/v1/auth/google/callback", authURL.Query().Get("redirect_uri"))
+}
@@ -0,0 +1,85 @@
+package api
+
+import (
+	"go-backend/token"
+	"go-backend/util"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultBalanceHistoryDays is the number of days of balance history returned when no period is sent.
+const defaultBalanceHistoryDays = 30
+
+const dateFormat = "2006-01-02"
+
+type getBalanceHistoryURI struct {
+	AccountID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type getBalanceHistoryRequest struct {
+	From *time.Time `form:"from" time_format:"2006-01-02" time_utc:"1"`
+	To   *time.Time `form:"to" time_format:"2006-01-02" time_utc:"1"`
+}
+
+type balancePointResponse struct {
+	Date    string `json:"date"`
+	Balance int64  `json:"balance"`
+}
+
+type balanceHistoryResponse struct {
+	AccountID int64                  `json:"account_id"`
+	From      string                 `json:"from"`
+	To        string                 `json:"to"`
+	Balances  []balancePointResponse `json:"balances"`
+}
+
+// This is a function that returns the closing balance of every day of a period for an account owned by
+// the authenticated user, oldest first, for balance charts. The period defaults to the last 30 days that
+// are over and covers at most a year. Balances are read from the snapshots taken by the end-of-day
+// batch rather than from the entries, so days it didn't close yet are missing.
+func (server *Server) getBalanceHistory(ctx *gin.Context) {
+	var uri getBalanceHistoryURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	var req getBalanceHistoryRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	now := time.Now().UTC()
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, -1)
+	if req.To != nil {
+		to = *req.To
+	}
+	from := to.AddDate(0, 0, -(defaultBalanceHistoryDays - 1))
+	if req.From != nil {
+		from = *req.From
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	snapshots, err := server.service.BalanceHistory(ctx, authPayload.Username, uri.AccountID, from, to)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	res := balanceHistoryResponse{
+		AccountID: uri.AccountID,
+		From:      from.Format(dateFormat),
+		To:        to.Format(dateFormat),
+		Balances:  make([]balancePointResponse, 0, len(snapshots)),
+	}
+	for _, snapshot := range snapshots {
+		res.Balances = append(res.Balances, balancePointResponse{
+			Date:    snapshot.BusinessDate.Format(dateFormat),
+			Balance: snapshot.Balance,
+		})
+	}
+	renderJSON(ctx, http.StatusOK, res)
+}
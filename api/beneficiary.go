@@ -0,0 +1,157 @@
+package api
+
+import (
+	"go-backend/service"
+	"go-backend/token"
+	"go-backend/util"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// The `addBeneficiaryRoutes` function adds the routes managing the beneficiaries of the authenticated
+// user, the accounts they save to send transfers to by nickname.
+func (server *Server) addBeneficiaryRoutes(apiRouter *routeGroup) {
+	beneficiaryRouter := apiRouter.Group("/beneficiaries")
+	beneficiaryRouter.POST("", server.createBeneficiary)
+	beneficiaryRouter.GET("", server.listBeneficiaries)
+	beneficiaryRouter.GET("/:id", server.getBeneficiary)
+	beneficiaryRouter.PUT("/:id", server.updateBeneficiary)
+	beneficiaryRouter.DELETE("/:id", server.deleteBeneficiary)
+}
+
+// The createBeneficiaryRequest type holds the account to save and its nickname.
+// @property {int64} AccountID - the account saved.
+// @property {string} Nickname - the name given to the account, unique among the beneficiaries of the
+// user.
+// @property {string} Category - the spending category of the transfers to the account, for budgets.
+type createBeneficiaryRequest struct {
+	AccountID int64  `json:"account_id" binding:"required,min=1"`
+	Nickname  string `json:"nickname" binding:"required,max=64"`
+	Category  string `json:"category" binding:"omitempty,max=64"`
+}
+
+// This is a function that saves an account as a beneficiary of the authenticated user, for their
+// transfers to reference it by `beneficiary_id`. Saving the same account or nickname twice is a conflict,
+// and the system accounts of the bank can't be saved.
+func (server *Server) createBeneficiary(ctx *gin.Context) {
+	var req createBeneficiaryRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	beneficiary, err := server.service.CreateBeneficiary(ctx, service.CreateBeneficiaryParams{
+		Owner:     authPayload.Username,
+		AccountID: req.AccountID,
+		Nickname:  req.Nickname,
+		Category:  req.Category,
+	})
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, beneficiary)
+}
+
+type listBeneficiariesRequest struct {
+	pageRequest
+}
+
+// This is a function that lists the beneficiaries of the authenticated user by nickname.
+func (server *Server) listBeneficiaries(ctx *gin.Context) {
+	var req listBeneficiariesRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	limit, offset, err := server.paginate(paginationBeneficiaries, req.pageRequest)
+	if err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	beneficiaries, err := server.service.ListBeneficiaries(ctx, authPayload.Username, limit, offset)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, beneficiaries)
+}
+
+type beneficiaryURIRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// This is a function that gets a beneficiary of the authenticated user.
+func (server *Server) getBeneficiary(ctx *gin.Context) {
+	var req beneficiaryURIRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	beneficiary, err := server.service.GetBeneficiary(ctx, authPayload.Username, req.ID)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, beneficiary)
+}
+
+// The updateBeneficiaryRequest type holds the new nickname and category of a beneficiary, an omitted
+// category leaving it uncategorized. Its account can't be changed, another beneficiary is saved instead.
+type updateBeneficiaryRequest struct {
+	Nickname string `json:"nickname" binding:"required,max=64"`
+	Category string `json:"category" binding:"omitempty,max=64"`
+}
+
+// This is a function that renames or recategorizes a beneficiary of the authenticated user.
+func (server *Server) updateBeneficiary(ctx *gin.Context) {
+	var uri beneficiaryURIRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	var req updateBeneficiaryRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	beneficiary, err := server.service.UpdateBeneficiary(ctx, authPayload.Username, uri.ID, req.Nickname, req.Category)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, beneficiary)
+}
+
+// This is a function that deletes a beneficiary of the authenticated user. The transfers already sent to
+// it are kept.
+func (server *Server) deleteBeneficiary(ctx *gin.Context) {
+	var req beneficiaryURIRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	err := server.service.DeleteBeneficiary(ctx, authPayload.Username, req.ID)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, gin.H{"message": "successfully deleted beneficiary"})
+}
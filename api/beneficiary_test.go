@@ -0,0 +1,309 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/testutil/factory"
+	"go-backend/util"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/require"
+)
+
+func requireBodyMatchBeneficiary(t *testing.T, body *bytes.Buffer, beneficiary db.Beneficiary) {
+	var got db.Beneficiary
+	err := json.Unmarshal(body.Bytes(), &got)
+	require.NoError(t, err)
+	require.Equal(t, beneficiary, got)
+}
+
+func TestCreateBeneficiaryAPI(t *testing.T) {
+	user := factory.User()
+	account := factory.Account()
+	beneficiary := factory.Beneficiary(factory.SavedBy(user.Username), func(beneficiary *db.Beneficiary) {
+		beneficiary.AccountID = account.ID
+	})
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"account_id": account.ID, "nickname": beneficiary.Nickname},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				arg := db.CreateBeneficiaryParams{
+					Owner:     user.Username,
+					AccountID: account.ID,
+					Nickname:  beneficiary.Nickname,
+				}
+				store.EXPECT().CreateBeneficiary(gomock.Any(), gomock.Eq(arg)).Times(1).Return(beneficiary, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchBeneficiary(t, recorder.Body, beneficiary)
+			},
+		},
+		{
+			name: "AccountNotFound",
+			body: gin.H{"account_id": account.ID, "nickname": beneficiary.Nickname},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(db.Account{}, db.ErrRecordNotFound)
+				store.EXPECT().CreateBeneficiary(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "SystemAccount",
+			body: gin.H{"account_id": account.ID, "nickname": beneficiary.Nickname},
+			buildStub: func(store *mockdb.MockStore) {
+				systemAccount := factory.Account(factory.OwnedBy(db.SystemOwner))
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(systemAccount, nil)
+				store.EXPECT().CreateBeneficiary(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "AlreadySaved",
+			body: gin.H{"account_id": account.ID, "nickname": beneficiary.Nickname},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().CreateBeneficiary(gomock.Any(), gomock.Any()).Times(1).Return(db.Beneficiary{}, &pgconn.PgError{Code: db.UniqueViolation})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				requireErrorBody(t, recorder.Body, util.ErrorCodeAlreadyExists)
+			},
+		},
+		{
+			name: "MissingNickname",
+			body: gin.H{"account_id": account.ID},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateBeneficiary(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/api/v1/beneficiaries", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestListBeneficiariesAPI(t *testing.T) {
+	user := factory.User()
+	beneficiaries := []db.Beneficiary{
+		factory.Beneficiary(factory.SavedBy(user.Username)),
+		factory.Beneficiary(factory.SavedBy(user.Username)),
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	arg := db.ListBeneficiariesParams{Owner: user.Username, Limit: 5, Offset: 5}
+	store.EXPECT().ListBeneficiaries(gomock.Any(), gomock.Eq(arg)).Times(1).Return(beneficiaries, nil)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/api/v1/beneficiaries?page_id=2&page_size=5", nil)
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var got []db.Beneficiary
+	err = json.Unmarshal(recorder.Body.Bytes(), &got)
+	require.NoError(t, err)
+	require.Equal(t, beneficiaries, got)
+}
+
+func TestBeneficiaryAPI(t *testing.T) {
+	user := factory.User()
+	beneficiary := factory.Beneficiary(factory.SavedBy(user.Username))
+	renamed := beneficiary
+	renamed.Nickname = util.RandomString(8)
+	renamed.Category = "groceries"
+
+	testCases := []struct {
+		name          string
+		method        string
+		beneficiaryID int64
+		body          gin.H
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:          "Get",
+			method:        http.MethodGet,
+			beneficiaryID: beneficiary.ID,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetBeneficiary(gomock.Any(), gomock.Eq(beneficiary.ID)).Times(1).Return(beneficiary, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchBeneficiary(t, recorder.Body, beneficiary)
+			},
+		},
+		{
+			name:          "GetNotFound",
+			method:        http.MethodGet,
+			beneficiaryID: beneficiary.ID,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetBeneficiary(gomock.Any(), gomock.Eq(beneficiary.ID)).Times(1).Return(db.Beneficiary{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:          "GetOtherUser",
+			method:        http.MethodGet,
+			beneficiaryID: beneficiary.ID,
+			buildStub: func(store *mockdb.MockStore) {
+				other := factory.Beneficiary()
+				store.EXPECT().GetBeneficiary(gomock.Any(), gomock.Eq(beneficiary.ID)).Times(1).Return(other, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:          "Update",
+			method:        http.MethodPut,
+			beneficiaryID: beneficiary.ID,
+			body:          gin.H{"nickname": renamed.Nickname, "category": renamed.Category},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetBeneficiary(gomock.Any(), gomock.Eq(beneficiary.ID)).Times(1).Return(beneficiary, nil)
+				arg := db.UpdateBeneficiaryParams{ID: beneficiary.ID, Nickname: renamed.Nickname, Category: renamed.Category}
+				store.EXPECT().UpdateBeneficiary(gomock.Any(), gomock.Eq(arg)).Times(1).Return(renamed, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchBeneficiary(t, recorder.Body, renamed)
+			},
+		},
+		{
+			name:          "UpdateOtherUser",
+			method:        http.MethodPut,
+			beneficiaryID: beneficiary.ID,
+			body:          gin.H{"nickname": renamed.Nickname},
+			buildStub: func(store *mockdb.MockStore) {
+				other := factory.Beneficiary()
+				store.EXPECT().GetBeneficiary(gomock.Any(), gomock.Eq(beneficiary.ID)).Times(1).Return(other, nil)
+				store.EXPECT().UpdateBeneficiary(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:          "Delete",
+			method:        http.MethodDelete,
+			beneficiaryID: beneficiary.ID,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetBeneficiary(gomock.Any(), gomock.Eq(beneficiary.ID)).Times(1).Return(beneficiary, nil)
+				store.EXPECT().DeleteBeneficiary(gomock.Any(), gomock.Eq(beneficiary.ID)).Times(1).Return(nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:          "DeleteInternalError",
+			method:        http.MethodDelete,
+			beneficiaryID: beneficiary.ID,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetBeneficiary(gomock.Any(), gomock.Eq(beneficiary.ID)).Times(1).Return(beneficiary, nil)
+				store.EXPECT().DeleteBeneficiary(gomock.Any(), gomock.Eq(beneficiary.ID)).Times(1).Return(sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name:          "InvalidID",
+			method:        http.MethodDelete,
+			beneficiaryID: 0,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetBeneficiary(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().DeleteBeneficiary(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			var body bytes.Buffer
+			if tc.body != nil {
+				err := json.NewEncoder(&body).Encode(tc.body)
+				require.NoError(t, err)
+			}
+
+			url := fmt.Sprintf("/api/v1/beneficiaries/%d", tc.beneficiaryID)
+			request, err := http.NewRequest(tc.method, url, &body)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package api
+
+import (
+	"go-backend/service"
+	"go-backend/token"
+	"go-backend/util"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type budgetURIRequest struct {
+	ID       int64  `uri:"id" binding:"required,min=1"`
+	Category string `uri:"category" binding:"required,max=64"`
+}
+
+type setBudgetRequest struct {
+	MonthlyLimit int64 `json:"monthly_limit" binding:"required,gt=0"`
+}
+
+// The budgetResponse type is a budget of an account along with the spending of its category this month.
+// @property {int64} Spent - the transfers sent to the beneficiaries of the category since the start of
+// the month, in UTC.
+type budgetResponse struct {
+	AccountID    int64     `json:"account_id"`
+	Category     string    `json:"category"`
+	MonthlyLimit int64     `json:"monthly_limit"`
+	Spent        int64     `json:"spent"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// This is a function that lists the monthly budgets of an account by category, with what was spent in
+// each this month. Every user the account is shared with can read them.
+func (server *Server) listBudgets(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	budgets, err := server.service.ListBudgets(ctx, authPayload.Username, uri.ID)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	response := make([]budgetResponse, len(budgets))
+	for i, budget := range budgets {
+		response[i] = budgetResponse{
+			AccountID:    budget.Budget.AccountID,
+			Category:     budget.Budget.Category,
+			MonthlyLimit: budget.Budget.MonthlyLimit,
+			Spent:        budget.Spent,
+			UpdatedAt:    budget.Budget.UpdatedAt,
+		}
+	}
+
+	renderJSON(ctx, http.StatusOK, response)
+}
+
+// This is a function that sets the monthly budget of a category of an account, the category of the
+// beneficiaries its transfers are sent to. Its owner is notified with a budget.exceeded notification when
+// a transfer takes the spending of the category beyond the budget.
+func (server *Server) setBudget(ctx *gin.Context) {
+	var uri budgetURIRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	var req setBudgetRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	budget, err := server.service.SetBudget(ctx, service.SetBudgetParams{
+		Owner:        authPayload.Username,
+		AccountID:    uri.ID,
+		Category:     uri.Category,
+		MonthlyLimit: req.MonthlyLimit,
+	})
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, budget)
+}
+
+// This is a function that deletes the budget of a category of an account.
+func (server *Server) deleteBudget(ctx *gin.Context) {
+	var uri budgetURIRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	err := server.service.DeleteBudget(ctx, authPayload.Username, uri.ID, uri.Category)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, gin.H{"message": "successfully deleted budget"})
+}
+
+// This is a function that gets the low balance alert set on an account. An account without one isn't
+// found, the threshold of the notification preferences of its owner applying to it.
+func (server *Server) getAccountAlert(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	alert, err := server.service.GetAccountAlert(ctx, authPayload.Username, uri.ID)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, alert)
+}
+
+type setAccountAlertRequest struct {
+	LowBalanceThreshold int64 `json:"low_balance_threshold" binding:"min=0"`
+}
+
+// This is a function that sets the low balance threshold of an account, overriding the one of the
+// notification preferences of its owner. A transfer leaving the account below it notifies its owner with
+// an account.low_balance notification, and a threshold of 0 never notifies.
+func (server *Server) setAccountAlert(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	var req setAccountAlertRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	alert, err := server.service.SetAccountAlert(ctx, authPayload.Username, uri.ID, req.LowBalanceThreshold)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, alert)
+}
+
+// This is a function that deletes the low balance alert of an account, the threshold of the notification
+// preferences of its owner applying to it again.
+func (server *Server) deleteAccountAlert(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	err := server.service.DeleteAccountAlert(ctx, authPayload.Username, uri.ID)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, gin.H{"message": "successfully deleted alert"})
+}
@@ -0,0 +1,171 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/testutil/factory"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBudgetsAPI(t *testing.T) {
+	user := factory.User()
+	account := factory.Account(factory.OwnedBy(user.Username))
+	budget := db.Budget{AccountID: account.ID, Category: "groceries", MonthlyLimit: 400}
+
+	testCases := []struct {
+		name          string
+		method        string
+		category      string
+		body          gin.H
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "Set",
+			method:   http.MethodPut,
+			category: "groceries",
+			body:     gin.H{"monthly_limit": 400},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				arg := db.UpsertBudgetParams{AccountID: account.ID, Category: "groceries", MonthlyLimit: 400}
+				store.EXPECT().UpsertBudget(gomock.Any(), gomock.Eq(arg)).Times(1).Return(budget, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got db.Budget
+				err := json.Unmarshal(recorder.Body.Bytes(), &got)
+				require.NoError(t, err)
+				require.Equal(t, budget, got)
+			},
+		},
+		{
+			name:     "SetInvalidLimit",
+			method:   http.MethodPut,
+			category: "groceries",
+			body:     gin.H{"monthly_limit": 0},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "List",
+			method: http.MethodGet,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().ListBudgets(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return([]db.Budget{budget}, nil)
+				store.EXPECT().SumCategorySpending(gomock.Any(), gomock.Any()).Times(1).Return(int64(150), nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var budgets []budgetResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &budgets)
+				require.NoError(t, err)
+				require.Len(t, budgets, 1)
+				require.Equal(t, "groceries", budgets[0].Category)
+				require.Equal(t, int64(400), budgets[0].MonthlyLimit)
+				require.Equal(t, int64(150), budgets[0].Spent)
+			},
+		},
+		{
+			name:     "DeleteNotFound",
+			method:   http.MethodDelete,
+			category: "travel",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetBudget(gomock.Any(), gomock.Any()).Times(1).Return(db.Budget{}, db.ErrRecordNotFound)
+				store.EXPECT().DeleteBudget(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/api/v1/accounts/%d/budgets", account.ID)
+			if tc.category != "" {
+				url += "/" + tc.category
+			}
+			request, err := http.NewRequest(tc.method, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestAccountAlertAPI(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	user := factory.User()
+	account := factory.Account(factory.OwnedBy(user.Username))
+	alert := db.AccountAlert{AccountID: account.ID, LowBalanceThreshold: 50}
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).AnyTimes().Return(account, nil)
+	store.EXPECT().
+		UpsertAccountAlert(gomock.Any(), gomock.Eq(db.UpsertAccountAlertParams{AccountID: account.ID, LowBalanceThreshold: 50})).
+		Times(1).
+		Return(alert, nil)
+	gomock.InOrder(
+		store.EXPECT().GetAccountAlert(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(alert, nil),
+		store.EXPECT().DeleteAccountAlert(gomock.Any(), gomock.Eq(account.ID)).Times(1),
+		store.EXPECT().GetAccountAlert(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(db.AccountAlert{}, db.ErrRecordNotFound),
+	)
+
+	server := newTestServer(t, store)
+	url := fmt.Sprintf("/api/v1/accounts/%d/alert", account.ID)
+
+	for _, step := range []struct {
+		method string
+		body   string
+		status int
+	}{
+		{http.MethodPut, `{"low_balance_threshold":-1}`, http.StatusBadRequest},
+		{http.MethodPut, `{"low_balance_threshold":50}`, http.StatusOK},
+		{http.MethodGet, "", http.StatusOK},
+		{http.MethodDelete, "", http.StatusOK},
+		{http.MethodGet, "", http.StatusNotFound},
+	} {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(step.method, url, bytes.NewReader([]byte(step.body)))
+		require.NoError(t, err)
+
+		addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+		server.router.ServeHTTP(recorder, request)
+		require.Equal(t, step.status, recorder.Code, "%s %s", step.method, step.body)
+	}
+}
@@ -0,0 +1,288 @@
+package api
+
+import (
+	"context"
+	db "go-backend/db/sqlc"
+	"go-backend/service"
+	"go-backend/token"
+	"go-backend/util"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// The `addCardRoutes` function adds the routes of the virtual cards, issued on the accounts of the
+// authenticated user, whose authorizations place holds reserving their amount on the account.
+func (server *Server) addCardRoutes(apiRouter *routeGroup) {
+	cardRouter := apiRouter.Group("/cards")
+	cardRouter.POST("", server.issueCard)
+	cardRouter.GET("", server.listCards)
+	cardRouter.GET("/:id", server.getCard)
+	cardRouter.POST("/:id/freeze", server.freezeCard)
+	cardRouter.POST("/:id/unfreeze", server.unfreezeCard)
+	cardRouter.GET("/:id/holds", server.listCardHolds)
+}
+
+// The `addCardProcessingRoutes` function adds the card processing of the card network to the admin
+// routes: the authorizations of the cards, and the capture or release of the holds they placed.
+func (server *Server) addCardProcessingRoutes(adminRouter *routeGroup) {
+	adminRouter.POST("/cards/:id/authorize", server.authorizeCard)
+	adminRouter.POST("/card_holds/:id/capture", server.captureCardHold)
+	adminRouter.POST("/card_holds/:id/release", server.releaseCardHold)
+}
+
+// The cardResponse type is a card as returned to its owner.
+// @property {string} Number - the full number of the card, only returned when it is issued.
+type cardResponse struct {
+	ID          int64     `json:"id"`
+	AccountID   int64     `json:"account_id"`
+	Number      string    `json:"number,omitempty"`
+	Last4       string    `json:"last4"`
+	ExpiryMonth int32     `json:"expiry_month"`
+	ExpiryYear  int32     `json:"expiry_year"`
+	Status      string    `json:"status"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func newCardResponse(card db.Card) cardResponse {
+	return cardResponse{
+		ID:          card.ID,
+		AccountID:   card.AccountID,
+		Last4:       card.Last4,
+		ExpiryMonth: card.ExpiryMonth,
+		ExpiryYear:  card.ExpiryYear,
+		Status:      card.Status,
+		CreatedAt:   card.CreatedAt,
+	}
+}
+
+type issueCardRequest struct {
+	AccountID int64 `json:"account_id" binding:"required,min=1"`
+}
+
+// This is a function that issues a virtual card on an account the authenticated user owns. The full
+// number of the card is only returned here, the bank keeping its last 4 digits only.
+func (server *Server) issueCard(ctx *gin.Context) {
+	var req issueCardRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	issued, err := server.service.IssueCard(ctx, authPayload.Username, req.AccountID)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	res := newCardResponse(issued.Card)
+	res.Number = issued.Number
+	renderJSON(ctx, http.StatusOK, res)
+}
+
+type listCardsRequest struct {
+	pageRequest
+}
+
+// This is a function that lists the cards issued to the authenticated user, oldest first.
+func (server *Server) listCards(ctx *gin.Context) {
+	var req listCardsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	limit, offset, err := server.paginate(paginationCards, req.pageRequest)
+	if err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	cards, err := server.service.ListCards(ctx, authPayload.Username, limit, offset)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	res := make([]cardResponse, 0, len(cards))
+	for _, card := range cards {
+		res = append(res, newCardResponse(card))
+	}
+	renderJSON(ctx, http.StatusOK, res)
+}
+
+type cardURIRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// This is a function that gets a card issued to the authenticated user.
+func (server *Server) getCard(ctx *gin.Context) {
+	var uri cardURIRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	card, err := server.service.GetCard(ctx, authPayload.Username, uri.ID)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, newCardResponse(card))
+}
+
+// This is a function that freezes a card issued to the authenticated user, which declines every
+// authorization until it is unfrozen.
+func (server *Server) freezeCard(ctx *gin.Context) {
+	server.setCardStatus(ctx, server.service.FreezeCard)
+}
+
+// This is a function that unfreezes a card issued to the authenticated user, which can be authorized
+// again.
+func (server *Server) unfreezeCard(ctx *gin.Context) {
+	server.setCardStatus(ctx, server.service.UnfreezeCard)
+}
+
+func (server *Server) setCardStatus(ctx *gin.Context, set func(ctx context.Context, owner string, id int64) (db.Card, error)) {
+	var uri cardURIRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	card, err := set(ctx, authPayload.Username, uri.ID)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, newCardResponse(card))
+}
+
+type listCardHoldsRequest struct {
+	pageRequest
+}
+
+// This is a function that lists the holds placed by the authorizations of a card issued to the
+// authenticated user, newest first. Only the authorized holds reserve their amount on the account.
+func (server *Server) listCardHolds(ctx *gin.Context) {
+	var uri cardURIRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+	var req listCardHoldsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	limit, offset, err := server.paginate(paginationCards, req.pageRequest)
+	if err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	holds, err := server.service.ListCardHolds(ctx, authPayload.Username, uri.ID, limit, offset)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, holds)
+}
+
+type authorizeCardRequest struct {
+	Amount   int64  `json:"amount" binding:"required,gt=0"`
+	Currency string `json:"currency" binding:"required,currency"`
+	Merchant string `json:"merchant" binding:"required,max=140"`
+}
+
+// This is a function that authorizes a card for a merchant on behalf of the card network, placing a hold
+// that reserves the amount on the account of the card until it is captured, released or expires. Frozen
+// and expired cards, and amounts above the available balance of the account, are declined with a 409.
+func (server *Server) authorizeCard(ctx *gin.Context) {
+	var uri cardURIRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+	var req authorizeCardRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	result, err := server.service.AuthorizeCard(ctx, service.AuthorizeCardParams{
+		CardID:   uri.ID,
+		Amount:   req.Amount,
+		Currency: req.Currency,
+		Merchant: req.Merchant,
+	})
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, result)
+}
+
+type cardHoldURIRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type captureCardHoldRequest struct {
+	Amount int64 `json:"amount" binding:"min=0"`
+}
+
+// This is a function that captures an authorized card hold on behalf of the card network, moving the
+// amount from the account of the card to the card settlement account. The amount is optional, the whole
+// hold being captured without it, and so is the body.
+func (server *Server) captureCardHold(ctx *gin.Context) {
+	var uri cardHoldURIRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	var req captureCardHoldRequest
+	if ctx.Request.ContentLength != 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+			return
+		}
+	}
+
+	result, err := server.service.CaptureCardHold(ctx, uri.ID, req.Amount)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, result)
+}
+
+// This is a function that releases an authorized card hold on behalf of the card network, e.g. for a
+// cancelled purchase, which then no longer reserves its amount.
+func (server *Server) releaseCardHold(ctx *gin.Context) {
+	var uri cardHoldURIRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	hold, err := server.service.ReleaseCardHold(ctx, uri.ID)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, hold)
+}
@@ -0,0 +1,177 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/testutil/factory"
+	"go-backend/util"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIssueCardAPI(t *testing.T) {
+	user := factory.User()
+	account := factory.Account(factory.OwnedBy(user.Username))
+	otherAccount := factory.Account()
+
+	testCases := []struct {
+		name          string
+		accountID     int64
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "OK",
+			accountID: account.ID,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().CreateCard(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateCardParams) (db.Card, error) {
+						return db.Card{ID: 1, AccountID: arg.AccountID, Owner: arg.Owner, Last4: arg.Last4, Status: db.CardActive}, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got cardResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &got)
+				require.NoError(t, err)
+				require.Equal(t, account.ID, got.AccountID)
+				// the full number is returned on issuance only
+				require.Len(t, got.Number, 16)
+				require.Equal(t, got.Number[12:], got.Last4)
+			},
+		},
+		{
+			name:      "NotOwner",
+			accountID: otherAccount.ID,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(otherAccount.ID)).Times(1).Return(otherAccount, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).AnyTimes().Return(db.AccountMember{}, db.ErrRecordNotFound)
+				store.EXPECT().CreateCard(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{"account_id": tc.accountID})
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/api/v1/cards", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestAuthorizeCardAPI(t *testing.T) {
+	admin := factory.User(factory.WithRole(util.AdminRole))
+	depositor := factory.User()
+	account := factory.Account(factory.InCurrency(util.USD))
+	card := db.Card{
+		ID:          1,
+		AccountID:   account.ID,
+		Owner:       account.Owner,
+		ExpiryMonth: 12,
+		ExpiryYear:  int32(time.Now().Year() + 1),
+		Status:      db.CardActive,
+	}
+	body := gin.H{"amount": 100, "currency": util.USD, "merchant": "Coffee Shop"}
+
+	testCases := []struct {
+		name      string
+		caller    db.User
+		buildStub func(store *mockdb.MockStore)
+		status    int
+	}{
+		{
+			name:   "OK",
+			caller: admin,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).Times(1).Return(admin, nil)
+				store.EXPECT().GetCard(gomock.Any(), gomock.Eq(card.ID)).Times(1).Return(card, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(2).Return(account, nil)
+				store.EXPECT().AuthorizeCardTx(gomock.Any(), gomock.Any()).Times(1).
+					Return(db.AuthorizeCardTxResult{CardHold: db.Hold{ID: 1, CardID: pgtype.Int8{Int64: card.ID, Valid: true}, Amount: 100, Status: db.HoldAuthorized}}, nil)
+			},
+			status: http.StatusOK,
+		},
+		{
+			// the amount is declined above the available balance of the account
+			name:   "InsufficientFunds",
+			caller: admin,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).Times(1).Return(admin, nil)
+				store.EXPECT().GetCard(gomock.Any(), gomock.Eq(card.ID)).Times(1).Return(card, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(2).Return(account, nil)
+				store.EXPECT().AuthorizeCardTx(gomock.Any(), gomock.Any()).Times(1).Return(db.AuthorizeCardTxResult{}, db.ErrInsufficientAvailableBalance)
+			},
+			status: http.StatusConflict,
+		},
+		{
+			// the card network is the only one to authorize cards
+			name:   "NotAdmin",
+			caller: depositor,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(depositor.Username)).Times(1).Return(depositor, nil)
+				store.EXPECT().AuthorizeCardTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			status: http.StatusForbidden,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(body)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/api/v1/admin/cards/%d/authorize", card.ID)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.caller.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, tc.status, recorder.Code)
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"fmt"
+	"go-backend/doc/changelog"
+	"net/http"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+)
+
+// changelogLink is the Link header of the deprecated routes, pointing to the changelog describing them.
+const changelogLink = `</api/v1/changelog>; rel="deprecation"; type="application/json"`
+
+var pathParam = regexp.MustCompile(`\{(\w+)\}`)
+
+// The `ginPath` function writes the parameters of a path documented as `{name}` the way gin registers
+// them.
+func ginPath(path string) string {
+	return pathParam.ReplaceAllString(path, ":$1")
+}
+
+// The deprecation type holds the headers sent with the responses of a deprecated route.
+type deprecation struct {
+	deprecation string
+	sunset      string
+}
+
+// The `newDeprecations` function returns the headers of the deprecated routes of the changelog, by
+// `METHOD /path` as gin registers them. The Deprecation header is the structured date of RFC 9745 and the
+// Sunset header the HTTP date of RFC 8594.
+func newDeprecations(log changelog.Changelog) (map[string]deprecation, error) {
+	deprecations := make(map[string]deprecation)
+	for _, change := range log.Changes {
+		if change.Type != changelog.TypeDeprecated {
+			continue
+		}
+
+		date, err := change.DateTime()
+		if err != nil {
+			return nil, err
+		}
+		sunset, err := change.SunsetTime()
+		if err != nil {
+			return nil, err
+		}
+
+		deprecations[change.Method+" "+ginPath(change.Path)] = deprecation{
+			deprecation: fmt.Sprintf("@%d", date.Unix()),
+			sunset:      sunset.Format(http.TimeFormat),
+		}
+	}
+	return deprecations, nil
+}
+
+// The `deprecationMiddleware` function sends the Deprecation, Sunset and Link headers with the responses
+// of the deprecated routes, for the clients to migrate before the routes are removed.
+func deprecationMiddleware(deprecations map[string]deprecation) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		deprecation, ok := deprecations[ctx.Request.Method+" "+v1Path(ctx.FullPath())]
+		if ok {
+			ctx.Header("Deprecation", deprecation.deprecation)
+			ctx.Header("Sunset", deprecation.sunset)
+			ctx.Header("Link", changelogLink)
+		}
+		ctx.Next()
+	}
+}
+
+// The `addChangelogRoutes` function adds the route serving the changelog of the API.
+func (server *Server) addChangelogRoutes(apiRouter *routeGroup) {
+	apiRouter.With(anonymous()).GET("/changelog", server.getChangelog)
+}
+
+// This is a function that serves the changelog of the API, newest change first.
+func (server *Server) getChangelog(ctx *gin.Context) {
+	renderJSON(ctx, http.StatusOK, server.changelog)
+}
@@ -0,0 +1,103 @@
+package api
+
+import (
+	"encoding/json"
+	mockdb "go-backend/db/mock"
+	"go-backend/doc/changelog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangelogAPI(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := newTestServer(t, mockdb.NewMockStore(ctrl))
+	expected, err := changelog.Load()
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, "/api/v1/changelog", nil)
+	require.NoError(t, err)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Empty(t, recorder.Header().Get("Deprecation"))
+
+	var got changelog.Changelog
+	err = json.Unmarshal(recorder.Body.Bytes(), &got)
+	require.NoError(t, err)
+	require.Equal(t, expected, got)
+}
+
+func TestDeprecatedRoutesAreRegistered(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := newTestServer(t, mockdb.NewMockStore(ctrl))
+	log, err := changelog.Load()
+	require.NoError(t, err)
+
+	registered := make(map[string]bool)
+	for _, route := range server.router.Routes() {
+		registered[route.Method+" "+route.Path] = true
+	}
+
+	// the routes removed since they were deprecated are no longer registered
+	removed := make(map[string]bool)
+	for _, change := range log.Changes {
+		if change.Type == changelog.TypeRemoved {
+			removed[change.Method+" "+ginPath(change.Path)] = true
+		}
+	}
+
+	for _, change := range log.Changes {
+		if change.Type == changelog.TypeDeprecated {
+			route := change.Method + " " + ginPath(change.Path)
+			require.True(t, registered[route] || removed[route], "deprecated route %s is not registered", route)
+			require.False(t, registered[route] && removed[route], "removed route %s is still registered", route)
+		}
+	}
+}
+
+func TestDeprecationMiddleware(t *testing.T) {
+	deprecations, err := newDeprecations(changelog.Changelog{Changes: []changelog.Change{
+		{
+			Date:        "2026-10-16",
+			Type:        changelog.TypeDeprecated,
+			Method:      http.MethodPut,
+			Path:        "/api/v1/widgets/{id}",
+			Description: "Widgets are replaced by gadgets.",
+			Sunset:      "2027-04-16",
+		},
+	}})
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.Use(deprecationMiddleware(deprecations))
+	reject := func(ctx *gin.Context) { ctx.AbortWithStatus(http.StatusUnauthorized) }
+	router.PUT("/api/v1/widgets/:id", reject)
+	router.GET("/api/v1/widgets/:id", reject)
+
+	// the headers are sent even when the request is rejected
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodPut, "/api/v1/widgets/1", nil)
+	require.NoError(t, err)
+	router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+	require.Equal(t, "@1792108800", recorder.Header().Get("Deprecation"))
+	require.Equal(t, "Fri, 16 Apr 2027 00:00:00 GMT", recorder.Header().Get("Sunset"))
+	require.Equal(t, changelogLink, recorder.Header().Get("Link"))
+
+	recorder = httptest.NewRecorder()
+	request, err = http.NewRequest(http.MethodGet, "/api/v1/widgets/1", nil)
+	require.NoError(t, err)
+	router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+	require.Empty(t, recorder.Header().Get("Deprecation"))
+	require.Empty(t, recorder.Header().Get("Sunset"))
+}
@@ -0,0 +1,96 @@
+package api
+
+import (
+	"go-backend/service"
+	"go-backend/token"
+	"go-backend/util"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// The `addChequeDepositRoutes` function adds the routes of the cheques deposited on the accounts of the
+// authenticated user, whose amount is held until they clear.
+func (server *Server) addChequeDepositRoutes(apiRouter *routeGroup) {
+	chequeDepositRouter := apiRouter.Group("/cheque_deposits")
+	chequeDepositRouter.POST("", server.depositCheque)
+	chequeDepositRouter.GET("/:id", server.getChequeDeposit)
+}
+
+// The `addChequeClearingRoutes` function adds the clearing of the cheques to the admin routes: the
+// bounce of the cheques the bank of their drawer refused to pay.
+func (server *Server) addChequeClearingRoutes(adminRouter *routeGroup) {
+	adminRouter.POST("/cheque_deposits/:id/bounce", server.bounceChequeDeposit)
+}
+
+type depositChequeRequest struct {
+	AccountID    int64  `json:"account_id" binding:"required,min=1"`
+	Amount       int64  `json:"amount" binding:"required,gt=0"`
+	ChequeNumber string `json:"cheque_number" binding:"required,max=34"`
+}
+
+// This is a function that deposits a cheque on an account the authenticated user owns. The amount is
+// credited to the balance of the account at once but held until the cheque clears, at the end of the
+// clearing period, so that it doesn't count in the available balance meanwhile.
+func (server *Server) depositCheque(ctx *gin.Context) {
+	var req depositChequeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	result, err := server.service.DepositCheque(ctx, service.DepositChequeParams{
+		Owner:        authPayload.Username,
+		AccountID:    req.AccountID,
+		Amount:       req.Amount,
+		ChequeNumber: req.ChequeNumber,
+	})
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, result)
+}
+
+type chequeDepositURIRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// This is a function that gets a cheque deposited by the authenticated user.
+func (server *Server) getChequeDeposit(ctx *gin.Context) {
+	var uri chequeDepositURIRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	deposit, err := server.service.GetChequeDeposit(ctx, authPayload.Username, uri.ID)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, deposit)
+}
+
+// This is a function that bounces a pending cheque the bank of its drawer refused to pay, reversing its
+// credit with a transfer back to the cheque clearing account. Cheques that cleared or bounced already
+// are refused with a 409.
+func (server *Server) bounceChequeDeposit(ctx *gin.Context) {
+	var uri chequeDepositURIRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	result, err := server.service.BounceChequeDeposit(ctx, uri.ID)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, result)
+}
@@ -0,0 +1,141 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/testutil/factory"
+	"go-backend/util"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDepositChequeAPI(t *testing.T) {
+	user := factory.User()
+	account := factory.Account(factory.OwnedBy(user.Username))
+
+	testCases := []struct {
+		name      string
+		body      gin.H
+		buildStub func(store *mockdb.MockStore)
+		status    int
+	}{
+		{
+			name: "OK",
+			body: gin.H{"account_id": account.ID, "amount": 100, "cheque_number": "0001"},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().DepositChequeTx(gomock.Any(), gomock.Any()).Times(1).
+					Return(db.DepositChequeTxResult{ChequeDeposit: db.ChequeDeposit{ID: 1, AccountID: account.ID, Status: db.ChequeDepositPending}}, nil)
+			},
+			status: http.StatusOK,
+		},
+		{
+			name: "MissingChequeNumber",
+			body: gin.H{"account_id": account.ID, "amount": 100},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().DepositChequeTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			status: http.StatusBadRequest,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/api/v1/cheque_deposits", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, tc.status, recorder.Code)
+		})
+	}
+}
+
+func TestBounceChequeDepositAPI(t *testing.T) {
+	admin := factory.User(factory.WithRole(util.AdminRole))
+	depositor := factory.User()
+
+	testCases := []struct {
+		name      string
+		caller    db.User
+		buildStub func(store *mockdb.MockStore)
+		status    int
+	}{
+		{
+			name:   "OK",
+			caller: admin,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).Times(1).Return(admin, nil)
+				store.EXPECT().BounceChequeDepositTx(gomock.Any(), gomock.Eq(int64(1))).Times(1).
+					Return(db.BounceChequeDepositTxResult{ChequeDeposit: db.ChequeDeposit{ID: 1, Status: db.ChequeDepositBounced}}, nil)
+			},
+			status: http.StatusOK,
+		},
+		{
+			// the cheque cleared already
+			name:   "Closed",
+			caller: admin,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).Times(1).Return(admin, nil)
+				store.EXPECT().BounceChequeDepositTx(gomock.Any(), gomock.Eq(int64(1))).Times(1).
+					Return(db.BounceChequeDepositTxResult{}, db.ErrChequeDepositClosed)
+			},
+			status: http.StatusConflict,
+		},
+		{
+			name:   "NotAdmin",
+			caller: depositor,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(depositor.Username)).Times(1).Return(depositor, nil)
+				store.EXPECT().BounceChequeDepositTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			status: http.StatusForbidden,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/api/v1/admin/cheque_deposits/%d/bounce", 1)
+			request, err := http.NewRequest(http.MethodPost, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.caller.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, tc.status, recorder.Code)
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package api
+
+import (
+	"compress/gzip"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// gzipWriters are the gzip writers of the compressed responses, reused across responses as each holds
+// a few hundred KB of compression state.
+var gzipWriters = sync.Pool{
+	New: func() interface{} {
+		return gzip.NewWriter(nil)
+	},
+}
+
+// The gzipResponseWriter type compresses the body of a response with gzip, unless the handler already
+// encoded it, e.g. the Prometheus metrics, or the response has no body. The choice is made on the first
+// write, once the headers of the handler are set.
+// @property gzip - the writer compressing the body, nil until the first write and when the body is sent
+// as is.
+type gzipResponseWriter struct {
+	gin.ResponseWriter
+	gzip    *gzip.Writer
+	started bool
+}
+
+func (w *gzipResponseWriter) start() {
+	if w.started {
+		return
+	}
+	w.started = true
+
+	header := w.Header()
+	status := w.Status()
+	if header.Get("Content-Encoding") != "" || status == http.StatusNoContent || status == http.StatusNotModified {
+		return
+	}
+
+	header.Set("Content-Encoding", "gzip")
+	header.Del("Content-Length")
+	w.gzip = gzipWriters.Get().(*gzip.Writer)
+	w.gzip.Reset(w.ResponseWriter)
+}
+
+func (w *gzipResponseWriter) Write(data []byte) (int, error) {
+	w.start()
+	if w.gzip == nil {
+		return w.ResponseWriter.Write(data)
+	}
+	return w.gzip.Write(data)
+}
+
+func (w *gzipResponseWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// The `Flush` method sends the body compressed so far, for the streamed responses.
+func (w *gzipResponseWriter) Flush() {
+	if w.gzip != nil {
+		w.gzip.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// The `close` method writes the end of the compressed body and releases the gzip writer.
+func (w *gzipResponseWriter) close() {
+	if w.gzip == nil {
+		return
+	}
+	w.gzip.Close()
+	gzipWriters.Put(w.gzip)
+	w.gzip = nil
+}
+
+// The `compressionMiddleware` function compresses the responses with gzip for the clients accepting it
+// in their Accept-Encoding header, which shrinks the JSON listings and statements several times over.
+func compressionMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Writer.Header().Add("Vary", "Accept-Encoding")
+		if !acceptsGzip(ctx.GetHeader("Accept-Encoding")) || ctx.Request.Method == http.MethodHead {
+			ctx.Next()
+			return
+		}
+
+		writer := &gzipResponseWriter{ResponseWriter: ctx.Writer}
+		ctx.Writer = writer
+		defer writer.close()
+
+		ctx.Next()
+	}
+}
+
+// The `acceptsGzip` function reports whether an Accept-Encoding header accepts gzip, by naming it or *
+// with a quality above zero, gzip itself taking precedence over *.
+func acceptsGzip(header string) bool {
+	accepted := false
+	for _, encoding := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(encoding, ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+
+		quality := 1.0
+		params = strings.ReplaceAll(params, " ", "")
+		if strings.HasPrefix(params, "q=") {
+			if q, err := strconv.ParseFloat(params[len("q="):], 64); err == nil {
+				quality = q
+			}
+		}
+
+		switch name {
+		case "gzip":
+			return quality > 0
+		case "*":
+			accepted = quality > 0
+		}
+	}
+	return accepted
+}
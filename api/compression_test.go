@@ -0,0 +1,186 @@
+package api
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/testutil/factory"
+	"go-backend/util"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcceptsGzip(t *testing.T) {
+	require.True(t, acceptsGzip("gzip"))
+	require.True(t, acceptsGzip("deflate, gzip;q=0.5"))
+	require.True(t, acceptsGzip("*"))
+	require.True(t, acceptsGzip("br;q=1.0, GZIP"))
+	require.False(t, acceptsGzip(""))
+	require.False(t, acceptsGzip("identity"))
+	require.False(t, acceptsGzip("gzip;q=0"))
+	require.False(t, acceptsGzip("*, gzip;q=0"))
+}
+
+func TestCompressionMiddleware(t *testing.T) {
+	user := factory.User()
+	account := factory.Account(factory.OwnedBy(user.Username))
+
+	// a page far larger than the items flushed at once
+	entries := make([]db.ListEntriesWithCounterpartyRow, 1000)
+	for i := range entries {
+		entries[i] = db.ListEntriesWithCounterpartyRow{
+			Entry:    factory.Entry(factory.OfAccount(account)),
+			Currency: account.Currency,
+		}
+	}
+
+	testCases := []struct {
+		name           string
+		acceptEncoding string
+		checkResponse  func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:           "Gzip",
+			acceptEncoding: "gzip, deflate",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, "gzip", recorder.Header().Get("Content-Encoding"))
+
+				reader, err := gzip.NewReader(recorder.Body)
+				require.NoError(t, err)
+				data, err := io.ReadAll(reader)
+				require.NoError(t, err)
+				requireEntriesBody(t, data, entries)
+			},
+		},
+		{
+			name: "Identity",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Empty(t, recorder.Header().Get("Content-Encoding"))
+				requireEntriesBody(t, recorder.Body.Bytes(), entries)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+			store.EXPECT().ListEntriesWithCounterparty(gomock.Any(), gomock.Any()).Times(1).Return(entries, nil)
+
+			server := newTestServerWithConfig(t, store, nil, func(config *util.Config) {
+				config.PaginationPolicies = "entries=1:20:1000"
+			})
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/api/v1/accounts/%d/entries?page_size=%d", account.ID, len(entries))
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+			if tc.acceptEncoding != "" {
+				request.Header.Set("Accept-Encoding", tc.acceptEncoding)
+			}
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, http.StatusOK, recorder.Code)
+			require.Contains(t, recorder.Header().Values("Vary"), "Accept-Encoding")
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestCompressionMiddlewareWithoutBody(t *testing.T) {
+	user := factory.User()
+	account := factory.Account(factory.OwnedBy(user.Username))
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/accounts/%d", account.ID), nil)
+	require.NoError(t, err)
+	request.Header.Set("Accept-Encoding", "gzip")
+	request.Header.Set("If-None-Match", accountETag(account))
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusNotModified, recorder.Code)
+	require.Empty(t, recorder.Header().Get("Content-Encoding"))
+	require.Empty(t, recorder.Body.Bytes())
+}
+
+func TestCompressionMiddlewareKeepsEncodedBodies(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := newTestServer(t, mockdb.NewMockStore(ctrl))
+	recorder := httptest.NewRecorder()
+
+	// the metrics handler compresses its body itself
+	request, err := http.NewRequest(http.MethodGet, "/metrics", nil)
+	require.NoError(t, err)
+	request.Header.Set("Accept-Encoding", "gzip")
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, "gzip", recorder.Header().Get("Content-Encoding"))
+
+	reader, err := gzip.NewReader(recorder.Body)
+	require.NoError(t, err)
+	data, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	require.Contains(t, string(data), "# HELP")
+}
+
+func TestStreamJSONMatchesRenderJSON(t *testing.T) {
+	account := factory.Account()
+	items := []accountResponse{newAccountResponse(account), newAccountResponse(factory.Account())}
+
+	for _, casing := range []fieldCasing{snakeCase, camelCase} {
+		bodies := make([]string, 2)
+		for i, render := range []func(ctx *gin.Context){
+			func(ctx *gin.Context) { renderJSON(ctx, http.StatusOK, items) },
+			func(ctx *gin.Context) {
+				streamJSON(ctx, http.StatusOK, items, func(item accountResponse) interface{} { return item })
+			},
+		} {
+			recorder := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = httptest.NewRequest(http.MethodGet, "/api/v1/accounts", nil)
+			ctx.Set(fieldCasingKey, casing)
+
+			render(ctx)
+			require.Equal(t, http.StatusOK, recorder.Code)
+			require.Equal(t, "application/json; charset=utf-8", recorder.Header().Get("Content-Type"))
+			bodies[i] = recorder.Body.String()
+		}
+		require.Equal(t, bodies[0], bodies[1], casing)
+	}
+}
+
+func requireEntriesBody(t *testing.T, data []byte, entries []db.ListEntriesWithCounterpartyRow) {
+	var got []entryResponse
+	err := json.Unmarshal(data, &got)
+	require.NoError(t, err)
+	require.Len(t, got, len(entries))
+	for i := range entries {
+		require.Equal(t, entries[i].Entry.ID, got[i].Entry.ID)
+	}
+}
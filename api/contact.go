@@ -0,0 +1,69 @@
+package api
+
+import (
+	"go-backend/service"
+	"go-backend/util"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// The lookupUserRequest type identifies the user looked up, by exactly one of their email and username.
+type lookupUserRequest struct {
+	Email    string `form:"email" binding:"required_without=Username,excluded_with=Username,omitempty,email"`
+	Username string `form:"username" binding:"omitempty,alphanum"`
+}
+
+// The contactResponse type is the public profile of a user looked up, without their email or any balance.
+// @property {map[string][]contactAccountResponse} Accounts - the accounts the user can receive money on,
+// by currency.
+type contactResponse struct {
+	Username string                              `json:"username"`
+	FullName string                              `json:"full_name"`
+	Accounts map[string][]contactAccountResponse `json:"accounts"`
+}
+
+// The contactAccountResponse type is an account of a user looked up, given by its id or its number to send
+// them money.
+type contactAccountResponse struct {
+	ID            int64  `json:"id"`
+	AccountNumber string `json:"account_number"`
+}
+
+func newContactResponse(contact service.Contact) contactResponse {
+	res := contactResponse{
+		Username: contact.User.Username,
+		FullName: contact.User.FullName,
+		Accounts: make(map[string][]contactAccountResponse),
+	}
+	for _, account := range contact.Accounts {
+		res.Accounts[account.Currency] = append(res.Accounts[account.Currency], contactAccountResponse{
+			ID:            account.ID,
+			AccountNumber: account.AccountNumber,
+		})
+	}
+	return res
+}
+
+// This is a function that looks up a user by their email or username, so that the authenticated user can
+// send them money without knowing their accounts, e.g. a friend. It returns a minimal public profile of the
+// user along with the accounts they own by currency, a 404 Not Found response being returned when nobody
+// has this email or username.
+func (server *Server) lookupUser(ctx *gin.Context) {
+	var req lookupUserRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	contact, err := server.service.LookupContact(ctx, service.LookupContactParams{
+		Email:    req.Email,
+		Username: req.Username,
+	})
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, newContactResponse(contact))
+}
@@ -0,0 +1,124 @@
+package api
+
+import (
+	"encoding/json"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/testutil/factory"
+	"go-backend/util"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookupUserAPI(t *testing.T) {
+	user := factory.User()
+	friend := factory.User()
+	usd := factory.Account(factory.OwnedBy(friend.Username), factory.InCurrency(util.USD))
+	otherUSD := factory.Account(factory.OwnedBy(friend.Username), factory.InCurrency(util.USD))
+	otherUSD.ID = usd.ID + 1
+	otherUSD.AccountNumber = util.AccountNumber(otherUSD.ID)
+	cad := factory.Account(factory.OwnedBy(friend.Username), factory.InCurrency(util.CAD))
+
+	testCases := []struct {
+		name          string
+		query         url.Values
+		authorized    bool
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:       "OK",
+			query:      url.Values{"email": {friend.Email}},
+			authorized: true,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserByEmail(gomock.Any(), gomock.Eq(friend.Email)).Times(1).Return(friend, nil)
+				store.EXPECT().ListAccounts(gomock.Any(), gomock.Any()).Times(1).Return([]db.Account{usd, otherUSD, cad}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got contactResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				require.Equal(t, contactResponse{
+					Username: friend.Username,
+					FullName: friend.FullName,
+					Accounts: map[string][]contactAccountResponse{
+						util.USD: {
+							{ID: usd.ID, AccountNumber: usd.AccountNumber},
+							{ID: otherUSD.ID, AccountNumber: otherUSD.AccountNumber},
+						},
+						util.CAD: {{ID: cad.ID, AccountNumber: cad.AccountNumber}},
+					},
+				}, got)
+
+				// neither the email nor the balances of the user are exposed
+				require.NotContains(t, recorder.Body.String(), friend.Email)
+				require.NotContains(t, recorder.Body.String(), "balance")
+			},
+		},
+		{
+			name:       "NotFound",
+			query:      url.Values{"username": {"nobody"}},
+			authorized: true,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq("nobody")).Times(1).Return(db.User{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:       "MissingQuery",
+			query:      url.Values{},
+			authorized: true,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().GetUserByEmail(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireErrorBody(t, recorder.Body, util.ErrorCodeValidationFailed)
+			},
+		},
+		{
+			name:  "NoAuthorization",
+			query: url.Values{"email": {friend.Email}},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserByEmail(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/api/v1/users/lookup?"+tc.query.Encode(), nil)
+			require.NoError(t, err)
+
+			if tc.authorized {
+				addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			}
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package api
+
+import (
+	db "go-backend/db/sqlc"
+	"go-backend/token"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// The `addDashboardRoutes` function adds the route returning the dashboard of the authenticated user.
+func (server *Server) addDashboardRoutes(apiRouter *routeGroup) {
+	apiRouter.GET("/dashboard", server.getDashboard)
+}
+
+type spendingTotalResponse struct {
+	Currency string `json:"currency"`
+	Amount   int64  `json:"amount"`
+}
+
+// The spendingResponse type is what left the accounts of the user this month, by currency.
+type spendingResponse struct {
+	Since  time.Time               `json:"since"`
+	Totals []spendingTotalResponse `json:"totals"`
+}
+
+type dashboardResponse struct {
+	Balances      []db.SumOwnerBalancesRow     `json:"balances"`
+	RecentEntries []entryResponse              `json:"recent_entries"`
+	Spending      spendingResponse             `json:"spending"`
+	Pending       db.CountOwnerPendingItemsRow `json:"pending"`
+}
+
+// This is a function that returns the dashboard of the authenticated user: the total balance of their
+// accounts per currency, their latest entries, what they spent this month and the number of their
+// pending items, which the apps would otherwise fetch with several calls.
+func (server *Server) getDashboard(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	dashboard, err := server.service.GetDashboard(ctx, authPayload.Username)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	res := dashboardResponse{
+		Balances:      dashboard.Balances,
+		RecentEntries: make([]entryResponse, 0, len(dashboard.RecentEntries)),
+		Spending: spendingResponse{
+			Since:  dashboard.SpendingSince,
+			Totals: make([]spendingTotalResponse, 0, len(dashboard.Spending)),
+		},
+		Pending: dashboard.Pending,
+	}
+	for _, entry := range dashboard.RecentEntries {
+		res.RecentEntries = append(res.RecentEntries, newEntryResponse(entry))
+	}
+	for _, total := range dashboard.Spending {
+		res.Spending.Totals = append(res.Spending.Totals, spendingTotalResponse{Currency: total.Currency, Amount: total.Spent})
+	}
+	renderJSON(ctx, http.StatusOK, res)
+}
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/testutil/factory"
+	"go-backend/util"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetDashboardAPI(t *testing.T) {
+	user := factory.User()
+
+	testCases := []struct {
+		name          string
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().SumOwnerBalances(gomock.Any(), gomock.Eq(user.Username)).Times(1).
+					Return([]db.SumOwnerBalancesRow{{Currency: util.EUR, AccountCount: 1, Balance: 1234, AvailableBalance: 1234}}, nil)
+				store.EXPECT().ListOwnerRecentEntries(gomock.Any(), gomock.Any()).Times(1).
+					Return([]db.ListOwnerRecentEntriesRow{{Entry: db.Entry{ID: 1, Amount: -20}, Currency: util.EUR}}, nil)
+				store.EXPECT().SumOwnerSpending(gomock.Any(), gomock.Any()).Times(1).
+					Return([]db.SumOwnerSpendingRow{{Currency: util.EUR, Spent: 20}}, nil)
+				store.EXPECT().CountOwnerPendingItems(gomock.Any(), gomock.Eq(user.Username)).Times(1).
+					Return(db.CountOwnerPendingItemsRow{Holds: 2}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got map[string]interface{}
+				err := json.Unmarshal(recorder.Body.Bytes(), &got)
+				require.NoError(t, err)
+				balance := got["balances"].([]interface{})[0].(map[string]interface{})
+				require.Equal(t, "1,234.00 EUR", balance["balance_display"])
+				require.Len(t, got["recent_entries"], 1)
+				total := got["spending"].(map[string]interface{})["totals"].([]interface{})[0].(map[string]interface{})
+				require.Equal(t, float64(20), total["amount"])
+				require.Equal(t, float64(2), got["pending"].(map[string]interface{})["holds"])
+			},
+		},
+		{
+			name: "InternalError",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().SumOwnerBalances(gomock.Any(), gomock.Any()).Times(1).Return(nil, sql.ErrConnDone)
+				store.EXPECT().CountOwnerPendingItems(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/api/v1/dashboard", nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
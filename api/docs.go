@@ -0,0 +1,25 @@
+package api
+
+import (
+	"go-backend/doc/openapi"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// The `addDocsRoutes` function adds the routes serving the OpenAPI spec of the API and its Swagger UI.
+func (server *Server) addDocsRoutes(apiRouter *routeGroup) {
+	docsRouter := apiRouter.Group("/docs", anonymous())
+	docsRouter.GET("", server.getDocsUI)
+	docsRouter.GET("/openapi.json", server.getOpenAPISpec)
+}
+
+// This is a function that serves the Swagger UI rendering the OpenAPI spec of the API.
+func (server *Server) getDocsUI(ctx *gin.Context) {
+	ctx.Data(http.StatusOK, "text/html; charset=utf-8", openapi.UI)
+}
+
+// This is a function that serves the OpenAPI spec of the API.
+func (server *Server) getOpenAPISpec(ctx *gin.Context) {
+	ctx.Data(http.StatusOK, "application/json", openapi.Spec)
+}
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"encoding/json"
+	mockdb "go-backend/db/mock"
+	"go-backend/doc/openapi"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+// documentedPrefixes are the route groups the OpenAPI spec must describe exhaustively.
+var documentedPrefixes = []string{
+	"/api/v1/users",
+	"/api/v1/auth",
+	"/api/v1/tokens",
+	"/api/v1/accounts",
+	"/api/v1/account_invitations",
+	"/api/v1/transfers",
+	"/api/v1/beneficiaries",
+	"/api/v1/payment_requests",
+	"/api/v1/payment_links",
+	"/api/v1/mandates",
+	"/api/v1/standing_orders",
+	"/api/v1/external_transfers",
+	"/api/v1/pending_transfers",
+	"/api/v1/cards",
+	"/api/v1/cheque_deposits",
+	"/api/v1/notifications",
+	"/api/v1/organizations",
+	"/api/v1/usage",
+	"/api/v1/dashboard",
+	"/api/v1/signing_keys",
+	"/api/v1/changelog",
+	"/api/v1/graphql",
+}
+
+// specRoutes returns the routes of the OpenAPI spec as `METHOD /path`, with the path parameters written
+// the way gin registers them.
+func specRoutes(t *testing.T) map[string]bool {
+	var spec struct {
+		Servers []struct {
+			URL string `json:"url"`
+		} `json:"servers"`
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	err := json.Unmarshal(openapi.Spec, &spec)
+	require.NoError(t, err)
+	require.Len(t, spec.Servers, 1)
+
+	routes := make(map[string]bool)
+	for path, operations := range spec.Paths {
+		for method := range operations {
+			route := spec.Servers[0].URL + ginPath(path)
+			routes[strings.ToUpper(method)+" "+route] = true
+		}
+	}
+	return routes
+}
+
+func TestOpenAPISpecMatchesRoutes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := newTestServer(t, mockdb.NewMockStore(ctrl))
+	documented := specRoutes(t)
+
+	registered := make(map[string]bool)
+	for _, route := range server.router.Routes() {
+		for _, prefix := range documentedPrefixes {
+			if strings.HasPrefix(route.Path, prefix) {
+				registered[route.Method+" "+route.Path] = true
+			}
+		}
+	}
+
+	for route := range registered {
+		require.True(t, documented[route], "route %s is missing from the OpenAPI spec", route)
+	}
+	for route := range documented {
+		require.True(t, registered[route], "route %s of the OpenAPI spec is not registered", route)
+	}
+}
+
+func TestDocsAPI(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := newTestServer(t, mockdb.NewMockStore(ctrl))
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, "/api/v1/docs", nil)
+	require.NoError(t, err)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Contains(t, recorder.Header().Get("Content-Type"), "text/html")
+	require.Contains(t, recorder.Body.String(), "swagger-ui")
+
+	recorder = httptest.NewRecorder()
+	request, err = http.NewRequest(http.MethodGet, "/api/v1/docs/openapi.json", nil)
+	require.NoError(t, err)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.JSONEq(t, string(openapi.Spec), recorder.Body.String())
+}
@@ -0,0 +1,97 @@
+package api
+
+import (
+	db "go-backend/db/sqlc"
+	"go-backend/token"
+	"go-backend/util"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type listEntriesURI struct {
+	AccountID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type listEntriesRequest struct {
+	keysetPageRequest
+}
+
+// The counterpartyResponse type is the other account of a transfer and its owner, so that clients can
+// render the history of an account without looking them up.
+// @property {string} Nickname - the name the owner of the other account gave it, empty when they gave
+// none.
+type counterpartyResponse struct {
+	AccountID int64  `json:"account_id"`
+	Username  string `json:"username"`
+	FullName  string `json:"full_name"`
+	Nickname  string `json:"nickname"`
+}
+
+type entryResponse struct {
+	db.Entry
+	Currency     string                `json:"currency"`
+	Counterparty *counterpartyResponse `json:"counterparty"`
+}
+
+func newEntryResponse(row db.ListEntriesWithCounterpartyRow) entryResponse {
+	res := entryResponse{Entry: row.Entry, Currency: row.Currency}
+	if row.CounterpartyAccountID.Valid {
+		res.Counterparty = &counterpartyResponse{
+			AccountID: row.CounterpartyAccountID.Int64,
+			Username:  row.CounterpartyUsername.String,
+			FullName:  row.CounterpartyFullName.String,
+			Nickname:  row.CounterpartyNickname.String,
+		}
+	}
+	return res
+}
+
+// This is a function that lists the entries of an account owned by the authenticated user, oldest
+// first, each with the counterparty of the transfer it was made for. The page defaults to the first one
+// with the default page size of the entries endpoint. Requests sending a cursor get keyset pages instead,
+// newest first, with the cursor of the next page in the X-Next-Cursor header. The entries are streamed,
+// pages of thousands of them not being buffered whole.
+func (server *Server) listEntries(ctx *gin.Context) {
+	var uri listEntriesURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	var req listEntriesRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	limit, offset, err := server.paginate(paginationEntries, req.pageRequest)
+	if err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+	cursor, keyset, err := req.keysetCursor()
+	if err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	var entries []db.ListEntriesWithCounterpartyRow
+	if keyset {
+		entries, err = server.service.ListEntriesBefore(ctx, authPayload.Username, uri.AccountID, cursor, limit)
+	} else {
+		entries, err = server.service.ListEntries(ctx, authPayload.Username, uri.AccountID, limit, offset)
+	}
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	if keyset && len(entries) > 0 {
+		setNextCursor(ctx, len(entries), limit, entries[len(entries)-1].Entry.ID)
+	}
+	streamJSON(ctx, http.StatusOK, entries, func(entry db.ListEntriesWithCounterpartyRow) interface{} {
+		return newEntryResponse(entry)
+	})
+}
@@ -0,0 +1,45 @@
+package api
+
+import (
+	"go-backend/service"
+	"go-backend/util"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type importEntriesRequest struct {
+	DryRun bool `form:"dry_run"`
+}
+
+// This is a function that imports the entries of a CSV file sent as the body of the request into an
+// account, e.g. when migrating it from another system. It is reserved to admins and works for any
+// account but the system ones. The file is validated as a whole and imported in a single transaction, a
+// 400 Bad Request response naming the first problems found being returned otherwise. With `dry_run` it
+// returns a 200 OK response with the preview of the import and all the problems found, without applying
+// it.
+func (server *Server) importEntries(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	var req importEntriesRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	res, err := server.service.ImportEntries(ctx, service.ImportEntriesParams{
+		AccountID: uri.ID,
+		CSV:       ctx.Request.Body,
+		DryRun:    req.DryRun,
+	})
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, res)
+}
@@ -0,0 +1,179 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/service"
+	"go-backend/testutil/factory"
+	"go-backend/util"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportEntriesAPI(t *testing.T) {
+	admin := factory.User(factory.WithRole(util.AdminRole))
+	user := factory.User()
+	account := factory.Account(factory.OwnedBy(user.Username))
+	account.Balance = 100
+
+	csv := "date,memo,amount\n2024-01-01,opening,50\n2024-01-02,fee,-20\n"
+	entries := []db.ImportedEntry{
+		{Amount: 50, CreatedAt: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{Amount: -20, CreatedAt: time.Date(2024, time.January, 2, 0, 0, 0, 0, time.UTC)},
+	}
+
+	testCases := []struct {
+		name          string
+		username      string
+		query         string
+		body          string
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			username: admin.Username,
+			body:     csv,
+			buildStub: func(store *mockdb.MockStore) {
+				updated := account
+				updated.Balance = 130
+
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).Times(1).Return(admin, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				arg := db.ImportEntriesTxParams{AccountID: account.ID, Entries: entries}
+				store.EXPECT().ImportEntriesTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(db.ImportEntriesTxResult{Account: updated}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res service.EntryImport
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.False(t, res.DryRun)
+				require.Equal(t, int64(30), res.Total)
+				require.Equal(t, int64(100), res.Balance)
+				require.Equal(t, int64(130), res.NewBalance)
+				require.Empty(t, res.Errors)
+			},
+		},
+		{
+			name:     "DryRun",
+			username: admin.Username,
+			query:    "?dry_run=true",
+			body:     csv + "2024-01-03,typo,1O\n",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).Times(1).Return(admin, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().ImportEntriesTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res service.EntryImport
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.True(t, res.DryRun)
+				require.Equal(t, entries, res.Entries)
+				require.Equal(t, int64(130), res.NewBalance)
+				require.Len(t, res.Errors, 1)
+				require.Equal(t, 4, res.Errors[0].Line)
+			},
+		},
+		{
+			name:     "InvalidRows",
+			username: admin.Username,
+			body:     csv + "2024-01-03,typo,1O\n",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).Times(1).Return(admin, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().ImportEntriesTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				body := requireErrorBody(t, recorder.Body, util.ErrorCodeInvalidArgument)
+				require.Contains(t, body.Message, "line 4")
+			},
+		},
+		{
+			name:     "NegativeBalance",
+			username: admin.Username,
+			body:     "date,amount\n2024-01-01,-101\n",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).Times(1).Return(admin, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().ImportEntriesTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireErrorBody(t, recorder.Body, service.ReasonNegativeBalance)
+			},
+		},
+		{
+			name:     "MissingColumns",
+			username: admin.Username,
+			body:     "when,value\n2024-01-01,10\n",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).Times(1).Return(admin, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireErrorBody(t, recorder.Body, util.ErrorCodeInvalidArgument)
+			},
+		},
+		{
+			name:     "AccountNotFound",
+			username: admin.Username,
+			body:     csv,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).Times(1).Return(admin, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(db.Account{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:     "Owner",
+			username: user.Username,
+			body:     csv,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/api/v1/accounts/%d/import%s", account.ID, tc.query)
+			request, err := http.NewRequest(http.MethodPost, url, strings.NewReader(tc.body))
+			require.NoError(t, err)
+			request.Header.Set("Content-Type", "text/csv")
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
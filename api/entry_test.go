@@ -0,0 +1,273 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/testutil/factory"
+	"go-backend/token"
+	"go-backend/util"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListEntriesAPI(t *testing.T) {
+	user := factory.User()
+	otherUser := factory.User()
+	account := factory.Account(factory.OwnedBy(user.Username))
+
+	counterparty := factory.User()
+	entries := []db.ListEntriesWithCounterpartyRow{
+		{
+			Entry:    factory.Entry(factory.OfAccount(account)),
+			Currency: account.Currency,
+		},
+		{
+			Entry: db.Entry{
+				ID:         2,
+				AccountID:  account.ID,
+				Amount:     -util.RandomMoney(),
+				TransferID: pgtype.Int8{Int64: 7, Valid: true},
+			},
+			CounterpartyAccountID: pgtype.Int8{Int64: account.ID + 1, Valid: true},
+			CounterpartyUsername:  pgtype.Text{String: counterparty.Username, Valid: true},
+			CounterpartyFullName:  pgtype.Text{String: counterparty.FullName, Valid: true},
+			CounterpartyNickname:  pgtype.Text{String: "Rent", Valid: true},
+			Currency:              account.Currency,
+		},
+	}
+
+	// the keyset pages list the newest entries first
+	newestEntries := []db.ListEntriesWithCounterpartyBeforeRow{
+		db.ListEntriesWithCounterpartyBeforeRow(entries[1]),
+		db.ListEntriesWithCounterpartyBeforeRow(entries[0]),
+	}
+
+	testCases := []struct {
+		name          string
+		query         string
+		setupAuth     func(request *http.Request, tokenMaker token.Maker)
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "DefaultPagination",
+			query: "",
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				arg := db.ListEntriesWithCounterpartyParams{AccountID: account.ID, Limit: 20, Offset: 0}
+				store.EXPECT().ListEntriesWithCounterparty(gomock.Any(), gomock.Eq(arg)).Times(1).Return(entries, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got []entryResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &got)
+				require.NoError(t, err)
+				require.Len(t, got, 2)
+				require.Equal(t, entries[0].Entry, got[0].Entry)
+				require.Nil(t, got[0].Counterparty)
+				require.Equal(t, account.Currency, got[0].Currency)
+				require.Equal(t, entries[1].Entry, got[1].Entry)
+				require.Equal(t, &counterpartyResponse{
+					AccountID: account.ID + 1,
+					Username:  counterparty.Username,
+					FullName:  counterparty.FullName,
+					Nickname:  "Rent",
+				}, got[1].Counterparty)
+			},
+		},
+		{
+			name:  "OK",
+			query: "page_id=3&page_size=10",
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				arg := db.ListEntriesWithCounterpartyParams{AccountID: account.ID, Limit: 10, Offset: 20}
+				store.EXPECT().ListEntriesWithCounterparty(gomock.Any(), gomock.Eq(arg)).Times(1).Return(entries, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:  "Unauthorized",
+			query: "",
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, otherUser.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(db.AccountMember{}, db.ErrRecordNotFound)
+				store.EXPECT().ListEntriesWithCounterparty(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:  "NotFound",
+			query: "",
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(db.Account{}, db.ErrRecordNotFound)
+				store.EXPECT().ListEntriesWithCounterparty(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:  "InternalError",
+			query: "",
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().ListEntriesWithCounterparty(gomock.Any(), gomock.Any()).Times(1).Return([]db.ListEntriesWithCounterpartyRow{}, sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name:  "InvalidPageID",
+			query: "page_id=0",
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().ListEntriesWithCounterparty(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "InvalidPageSize",
+			query: "page_size=101",
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().ListEntriesWithCounterparty(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "KeysetFirstPage",
+			query: "cursor=&page_size=2",
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				arg := db.ListEntriesWithCounterpartyBeforeParams{AccountID: account.ID, RowLimit: 2}
+				store.EXPECT().ListEntriesWithCounterpartyBefore(gomock.Any(), gomock.Eq(arg)).Times(1).Return(newestEntries, nil)
+				store.EXPECT().ListEntriesWithCounterparty(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, util.EncodeCursor(newestEntries[1].Entry.ID), recorder.Header().Get(nextCursorHeaderKey))
+
+				var got []entryResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &got)
+				require.NoError(t, err)
+				require.Len(t, got, 2)
+				require.Equal(t, newestEntries[0].Entry, got[0].Entry)
+			},
+		},
+		{
+			name:  "KeysetLastPage",
+			query: "cursor=" + util.EncodeCursor(7),
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				arg := db.ListEntriesWithCounterpartyBeforeParams{
+					AccountID: account.ID,
+					Cursor:    pgtype.Int8{Int64: 7, Valid: true},
+					RowLimit:  20,
+				}
+				store.EXPECT().ListEntriesWithCounterpartyBefore(gomock.Any(), gomock.Eq(arg)).Times(1).Return(newestEntries, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				// the page isn't full, so it is the last one
+				require.Empty(t, recorder.Header().Get(nextCursorHeaderKey))
+			},
+		},
+		{
+			name:  "InvalidCursor",
+			query: "cursor=42",
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().ListEntriesWithCounterpartyBefore(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "CursorWithPageID",
+			query: "cursor=&page_id=2",
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().ListEntriesWithCounterpartyBefore(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/api/v1/accounts/%d/entries?%s", account.ID, tc.query)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package api
+
+import (
+	"go-backend/service"
+	"go-backend/util"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// errorStatuses maps the codes of the service errors to HTTP statuses. Resources of other users are
+// reported as 401 and resources that already exist as 403, as the API always did.
+var errorStatuses = map[service.Code]int{
+	service.CodeInternal:           http.StatusInternalServerError,
+	service.CodeInvalidArgument:    http.StatusBadRequest,
+	service.CodeNotFound:           http.StatusNotFound,
+	service.CodeUnauthenticated:    http.StatusUnauthorized,
+	service.CodePermissionDenied:   http.StatusUnauthorized,
+	service.CodeAlreadyExists:      http.StatusForbidden,
+	service.CodeFailedPrecondition: http.StatusConflict,
+	service.CodeUnavailable:        http.StatusServiceUnavailable,
+	service.CodeLocked:             http.StatusLocked,
+	service.CodeDeadlineExceeded:   http.StatusGatewayTimeout,
+	service.CodeResourceExhausted:  http.StatusTooManyRequests,
+}
+
+// errorCodes maps the codes of the service errors to the codes of the error responses, for the errors
+// without a reason.
+var errorCodes = map[service.Code]string{
+	service.CodeInternal:           util.ErrorCodeInternal,
+	service.CodeInvalidArgument:    util.ErrorCodeInvalidArgument,
+	service.CodeNotFound:           util.ErrorCodeNotFound,
+	service.CodeUnauthenticated:    util.ErrorCodeUnauthenticated,
+	service.CodePermissionDenied:   util.ErrorCodePermissionDenied,
+	service.CodeAlreadyExists:      util.ErrorCodeAlreadyExists,
+	service.CodeFailedPrecondition: util.ErrorCodeFailedPrecondition,
+	service.CodeUnavailable:        util.ErrorCodeUnavailable,
+	service.CodeLocked:             util.ErrorCodeLocked,
+	service.CodeDeadlineExceeded:   util.ErrorCodeDeadlineExceeded,
+	service.CodeResourceExhausted:  util.ErrorCodeResourceExhausted,
+}
+
+// reasonStatuses maps the reasons of the service errors whose status differs from the one of their code
+// to HTTP statuses, e.g. the registrations colliding with another user, reported as 409 rather than 403,
+// and the conditional updates of resources changed since their If-Match version, reported as 412.
+var reasonStatuses = map[string]int{
+	service.ReasonUsernameTaken:     http.StatusConflict,
+	service.ReasonEmailTaken:        http.StatusConflict,
+	service.ReasonVersionMismatch:   http.StatusPreconditionFailed,
+	service.ReasonDeletionScheduled: http.StatusConflict,
+}
+
+// The `writeError` function responds with the status matching an error returned by the service, and its
+// reason as the code of the response when it has one.
+func writeError(ctx *gin.Context, err error) {
+	// the error is reported to the error tracking when it is a server error
+	ctx.Error(err)
+
+	status, body := serviceErrorResponse(err)
+	renderJSON(ctx, status, body)
+}
+
+// The `serviceErrorResponse` function returns the status and body describing an error returned by the
+// service, for the responses that report errors besides their own status. The field the error is about,
+// if any, is listed in the field errors of the body.
+func serviceErrorResponse(err error) (int, util.ErrorBody) {
+	reason := service.ErrorReason(err)
+	code := reason
+	if code == "" {
+		code = errorCodes[service.ErrorCode(err)]
+	}
+
+	status, ok := reasonStatuses[reason]
+	if !ok {
+		status = errorStatuses[service.ErrorCode(err)]
+	}
+
+	body := util.ErrorResponse(status, util.WithErrorCode(code, err))
+	if field := service.ErrorField(err); field != "" {
+		body.FieldErrors = append(body.FieldErrors, util.FieldError{
+			Field:   field,
+			Code:    code,
+			Message: err.Error(),
+		})
+	}
+	return status, body
+}
@@ -0,0 +1,70 @@
+package api
+
+import (
+	"errors"
+	db "go-backend/db/sqlc"
+	"go-backend/util"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	errIfMatchRequired = errors.New("the If-Match header is required, holding the ETag of the account as last read")
+	errIfMatchList     = errors.New("the If-Match header must hold a single entity tag")
+)
+
+// The `accountETag` function returns the entity tag of an account, a strong one made of its version, which
+// every update of the account increments.
+func accountETag(account db.Account) string {
+	return strconv.Quote(strconv.FormatInt(account.Version, 10))
+}
+
+// The `etagMatches` function reports whether an If-None-Match header matches an entity tag, comparing
+// the tags weakly as the header requires: W/"3" matches "3". A * matches any tag.
+func etagMatches(header string, etag string) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+// The `ifMatchVersion` function returns the version of the account an If-Match header expects, 0 for a *
+// matching any version. Weak and unknown tags can't match the strong tags of the accounts, and expect a
+// version that never exists, -1.
+func ifMatchVersion(header string) (int64, error) {
+	header = strings.TrimSpace(header)
+	switch {
+	case header == "":
+		return 0, errIfMatchRequired
+	case strings.Contains(header, ","):
+		return 0, errIfMatchList
+	case header == "*":
+		return 0, nil
+	}
+
+	unquoted, err := strconv.Unquote(header)
+	if err != nil || !strings.HasPrefix(header, `"`) {
+		return -1, nil
+	}
+	version, err := strconv.ParseInt(unquoted, 10, 64)
+	if err != nil || version < 1 {
+		return -1, nil
+	}
+	return version, nil
+}
+
+// The `ifMatchError` function responds to an update whose If-Match header is missing with a 428 status,
+// and to one whose header holds several tags with a 400 status.
+func ifMatchError(ctx *gin.Context, err error) {
+	status := http.StatusBadRequest
+	if errors.Is(err, errIfMatchRequired) {
+		status = http.StatusPreconditionRequired
+	}
+	renderJSON(ctx, status, util.ErrorResponse(status, err))
+}
@@ -0,0 +1,72 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"go-backend/statement"
+	"go-backend/token"
+	"go-backend/util"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultExportDays is the number of days exported when no period is sent.
+const defaultExportDays = 30
+
+type exportAccountURI struct {
+	AccountID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type exportAccountRequest struct {
+	Format string     `form:"format" binding:"required,oneof=ofx qif csv"`
+	From   *time.Time `form:"from" time_format:"2006-01-02" time_utc:"1"`
+	To     *time.Time `form:"to" time_format:"2006-01-02" time_utc:"1"`
+}
+
+// This is a function that exports the transactions of an account owned by the authenticated user over a
+// period as an OFX, QIF or CSV file, so that they can be imported into personal finance applications
+// such as Quicken or YNAB. The period covers whole days in UTC, the last 30 ones up to today by default,
+// and at most a year. Longer histories are left to the export jobs.
+func (server *Server) exportAccount(ctx *gin.Context) {
+	var uri exportAccountURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	var req exportAccountRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	now := time.Now().UTC()
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if req.To != nil {
+		to = *req.To
+	}
+	from := to.AddDate(0, 0, -(defaultExportDays - 1))
+	if req.From != nil {
+		from = *req.From
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	res, err := server.service.Statement(ctx, authPayload.Username, uri.AccountID, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	format := statement.Format(req.Format)
+	var buffer bytes.Buffer
+	if err := statement.Write(&buffer, format, res); err != nil {
+		renderJSON(ctx, http.StatusInternalServerError, util.ErrorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	filename := fmt.Sprintf("account-%d-%s-%s.%s", uri.AccountID, from.Format(dateFormat), to.Format(dateFormat), format)
+	ctx.Header("Content-Disposition", "attachment; filename="+filename)
+	ctx.Data(http.StatusOK, format.ContentType(), buffer.Bytes())
+}
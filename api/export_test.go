@@ -0,0 +1,203 @@
+package api
+
+import (
+	"fmt"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/testutil/factory"
+	"go-backend/token"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportAccountAPI(t *testing.T) {
+	user := factory.User()
+	otherUser := factory.User()
+	account := factory.Account(factory.OwnedBy(user.Username))
+
+	from := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+	entry := factory.Entry(factory.OfAccount(account))
+	entry.Amount = -25
+	entry.CreatedAt = from.Add(10 * time.Hour)
+
+	testCases := []struct {
+		name          string
+		query         string
+		setupAuth     func(request *http.Request, tokenMaker token.Maker)
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "QIF",
+			query: "format=qif&from=2024-03-01&to=2024-03-03",
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				// the last day is included in the period
+				to := from.AddDate(0, 0, 3)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				arg := db.ListEntriesForExportParams{AccountID: account.ID, FromTime: from, ToTime: to, RowLimit: 5001}
+				store.EXPECT().ListEntriesForExport(gomock.Any(), gomock.Eq(arg)).Times(1).Return([]db.ListEntriesForExportRow{{Entry: entry}}, nil)
+				store.EXPECT().SumEntriesSince(gomock.Any(), gomock.Eq(db.SumEntriesSinceParams{AccountID: account.ID, CreatedAt: to})).Times(1).Return(int64(0), nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "application/qif", recorder.Header().Get("Content-Type"))
+				require.Equal(t, fmt.Sprintf("attachment; filename=account-%d-2024-03-01-2024-03-03.qif", account.ID), recorder.Header().Get("Content-Disposition"))
+				require.Equal(t, "!Type:Bank\nD03/01/2024\nT-25.00\n^\n", recorder.Body.String())
+			},
+		},
+		{
+			name:  "OFX",
+			query: "format=ofx",
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().
+					ListEntriesForExport(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.ListEntriesForExportParams) ([]db.ListEntriesForExportRow, error) {
+						// the period defaults to the last 30 days, today included
+						now := time.Now().UTC()
+						tomorrow := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, 1)
+						require.Equal(t, tomorrow, arg.ToTime)
+						require.Equal(t, tomorrow.AddDate(0, 0, -30), arg.FromTime)
+						return []db.ListEntriesForExportRow{{Entry: entry}}, nil
+					})
+				store.EXPECT().SumEntriesSince(gomock.Any(), gomock.Any()).Times(1).Return(int64(0), nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "application/x-ofx", recorder.Header().Get("Content-Type"))
+				require.Contains(t, recorder.Body.String(), fmt.Sprintf("<ACCTID>%d</ACCTID>", account.ID))
+				require.Contains(t, recorder.Body.String(), fmt.Sprintf("<FITID>%d</FITID>", entry.ID))
+			},
+		},
+		{
+			name:  "CSV",
+			query: "format=csv&from=2024-03-01&to=2024-03-01",
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().ListEntriesForExport(gomock.Any(), gomock.Any()).Times(1).Return([]db.ListEntriesForExportRow{}, nil)
+				store.EXPECT().SumEntriesSince(gomock.Any(), gomock.Any()).Times(1).Return(int64(0), nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "text/csv", recorder.Header().Get("Content-Type"))
+				require.Equal(t, "id,date,amount,balance,payee,memo,reference,counterparty_account_id\n", recorder.Body.String())
+			},
+		},
+		{
+			name:  "UnsupportedFormat",
+			query: "format=xlsx",
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "MissingFormat",
+			query: "",
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "FromAfterTo",
+			query: "format=ofx&from=2024-03-03&to=2024-03-01",
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "PeriodTooLong",
+			query: "format=ofx&from=2022-01-01&to=2024-01-01",
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "Unauthorized",
+			query: "format=ofx",
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, otherUser.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(db.AccountMember{}, db.ErrRecordNotFound)
+				store.EXPECT().ListEntriesForExport(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:      "NoAuthorization",
+			query:     "format=ofx",
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/api/v1/accounts/%d/export?%s", account.ID, tc.query)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package api
+
+import (
+	"go-backend/service"
+	"go-backend/token"
+	"go-backend/util"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// The `addExternalTransferRoutes` function adds the routes of the external transfers, which send money out
+// of the bank through the ACH or wire rails and settle in the background.
+func (server *Server) addExternalTransferRoutes(apiRouter *routeGroup) {
+	externalTransferRouter := apiRouter.Group("/external_transfers")
+	externalTransferRouter.POST("", server.signatureMiddleware(), server.initiateExternalTransfer)
+	externalTransferRouter.GET("", server.listExternalTransfers)
+	externalTransferRouter.GET("/:id", server.getExternalTransfer)
+}
+
+// The initiateExternalTransferRequest type holds the money the authenticated user sends out of the bank.
+// @property {string} Rail - ach or wire, a wire settling faster.
+// @property {string} RoutingNumber - the 9 digit routing number of the receiving bank.
+// @property {string} AccountNumber - the account at the receiving bank.
+// @property {string} BeneficiaryName - the holder of the account at the receiving bank.
+// @property {string} Memo - optional free text kept on the transfer.
+type initiateExternalTransferRequest struct {
+	AccountID       int64  `json:"account_id" binding:"required,min=1"`
+	Rail            string `json:"rail" binding:"required,oneof=ach wire"`
+	Amount          int64  `json:"amount" binding:"required,gt=0"`
+	Currency        string `json:"currency" binding:"required,currency"`
+	RoutingNumber   string `json:"routing_number" binding:"required,numeric,len=9"`
+	AccountNumber   string `json:"account_number" binding:"required,numeric,min=4,max=17"`
+	BeneficiaryName string `json:"beneficiary_name" binding:"required,max=140"`
+	Memo            string `json:"memo" binding:"omitempty,max=140"`
+}
+
+// This is a function that sends money from an account of the authenticated user to an account at another
+// bank. The amount leaves the account right away, and the transfer settles in the background after the
+// delay of its rail, so it returns a 202 Accepted response with the initiated transfer. The user is
+// notified with an external_transfer.updated notification on every change of its status, and the amount
+// is returned to the account when the receiving bank fails the transfer.
+func (server *Server) initiateExternalTransfer(ctx *gin.Context) {
+	var req initiateExternalTransferRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	external, err := server.service.InitiateExternalTransfer(ctx, service.InitiateExternalTransferParams{
+		Owner:           authPayload.Username,
+		AccountID:       req.AccountID,
+		Rail:            req.Rail,
+		Amount:          req.Amount,
+		Currency:        req.Currency,
+		RoutingNumber:   req.RoutingNumber,
+		AccountNumber:   req.AccountNumber,
+		BeneficiaryName: req.BeneficiaryName,
+		Memo:            req.Memo,
+	})
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusAccepted, external)
+}
+
+type listExternalTransfersRequest struct {
+	pageRequest
+}
+
+// This is a function that lists the external transfers sent by the authenticated user, oldest first.
+func (server *Server) listExternalTransfers(ctx *gin.Context) {
+	var req listExternalTransfersRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	limit, offset, err := server.paginate(paginationExternalTransfers, req.pageRequest)
+	if err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	transfers, err := server.service.ListExternalTransfers(ctx, authPayload.Username, limit, offset)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, transfers)
+}
+
+type externalTransferURIRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// This is a function that gets an external transfer sent by the authenticated user or from an account
+// they share, so that its status can be followed until it settles or fails.
+func (server *Server) getExternalTransfer(ctx *gin.Context) {
+	var req externalTransferURIRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	external, err := server.service.GetExternalTransfer(ctx, authPayload.Username, req.ID)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, external)
+}
@@ -0,0 +1,187 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/testutil/factory"
+	"go-backend/util"
+	"go-backend/worker"
+	mockwk "go-backend/worker/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInitiateExternalTransferAPI(t *testing.T) {
+	user := factory.User()
+	account := factory.Account(factory.OwnedBy(user.Username), factory.InCurrency(util.USD))
+	external := db.ExternalTransfer{
+		ID:              7,
+		Owner:           user.Username,
+		AccountID:       account.ID,
+		Rail:            db.ExternalTransferWire,
+		Amount:          100,
+		Currency:        util.USD,
+		RoutingNumber:   "021000021",
+		AccountNumber:   "123456789",
+		BeneficiaryName: "Bob",
+		Status:          db.ExternalTransferInitiated,
+	}
+	body := gin.H{
+		"account_id":       account.ID,
+		"rail":             external.Rail,
+		"amount":           external.Amount,
+		"currency":         external.Currency,
+		"routing_number":   external.RoutingNumber,
+		"account_number":   external.AccountNumber,
+		"beneficiary_name": external.BeneficiaryName,
+	}
+
+	testCases := []struct {
+		name          string
+		body          func() gin.H
+		buildStub     func(store *mockdb.MockStore, distributor *mockwk.MockTaskDistributor)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Accepted",
+			body: func() gin.H { return body },
+			buildStub: func(store *mockdb.MockStore, distributor *mockwk.MockTaskDistributor) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetActiveBankParameter(gomock.Any(), gomock.Any()).Times(1).Return(db.BankParameter{}, db.ErrRecordNotFound)
+				store.EXPECT().
+					InitiateExternalTransferTx(gomock.Any(), gomock.Eq(db.InitiateExternalTransferTxParams{
+						Owner:           user.Username,
+						AccountID:       account.ID,
+						Rail:            external.Rail,
+						Amount:          external.Amount,
+						Currency:        external.Currency,
+						RoutingNumber:   external.RoutingNumber,
+						AccountNumber:   external.AccountNumber,
+						BeneficiaryName: external.BeneficiaryName,
+					})).
+					Times(1).
+					Return(db.InitiateExternalTransferTxResult{ExternalTransfer: external}, nil)
+				distributor.EXPECT().
+					DistributeTaskExternalTransfer(gomock.Any(), gomock.Eq(&worker.PayloadExternalTransfer{ID: external.ID, Status: db.ExternalTransferInitiated}), gomock.Any()).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+
+				var got db.ExternalTransfer
+				err := json.Unmarshal(recorder.Body.Bytes(), &got)
+				require.NoError(t, err)
+				require.Equal(t, external, got)
+			},
+		},
+		{
+			name: "InvalidRail",
+			body: func() gin.H {
+				invalid := gin.H{}
+				for key, value := range body {
+					invalid[key] = value
+				}
+				invalid["rail"] = "swift"
+				return invalid
+			},
+			buildStub: func(store *mockdb.MockStore, distributor *mockwk.MockTaskDistributor) {
+				store.EXPECT().InitiateExternalTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireErrorBody(t, recorder.Body, util.ErrorCodeValidationFailed)
+			},
+		},
+		{
+			name: "InvalidRoutingNumber",
+			body: func() gin.H {
+				invalid := gin.H{}
+				for key, value := range body {
+					invalid[key] = value
+				}
+				invalid["routing_number"] = "12345"
+				return invalid
+			},
+			buildStub: func(store *mockdb.MockStore, distributor *mockwk.MockTaskDistributor) {
+				store.EXPECT().InitiateExternalTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireErrorBody(t, recorder.Body, util.ErrorCodeValidationFailed)
+			},
+		},
+		{
+			name: "NotOwner",
+			body: func() gin.H { return body },
+			buildStub: func(store *mockdb.MockStore, distributor *mockwk.MockTaskDistributor) {
+				other := account
+				other.Owner = util.RandomOwner()
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(other, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(db.AccountMember{}, db.ErrRecordNotFound)
+				store.EXPECT().InitiateExternalTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			distributor := mockwk.NewMockTaskDistributor(ctrl)
+			tc.buildStub(store, distributor)
+
+			server := newTestServerWithDistributor(t, store, distributor)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body())
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/api/v1/external_transfers", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestGetExternalTransferAPI(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	user := factory.User()
+	external := db.ExternalTransfer{ID: 7, Owner: user.Username, AccountID: 42, Status: db.ExternalTransferSettled}
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetExternalTransfer(gomock.Any(), gomock.Eq(external.ID)).Times(1).Return(external, nil)
+	store.EXPECT().GetExternalTransfer(gomock.Any(), gomock.Eq(external.ID+1)).Times(1).Return(db.ExternalTransfer{}, db.ErrRecordNotFound)
+
+	server := newTestServer(t, store)
+	for id, status := range map[int64]int{external.ID: http.StatusOK, external.ID + 1: http.StatusNotFound} {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/external_transfers/%d", id), nil)
+		require.NoError(t, err)
+
+		addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+		server.router.ServeHTTP(recorder, request)
+		require.Equal(t, status, recorder.Code)
+	}
+}
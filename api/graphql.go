@@ -0,0 +1,67 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"go-backend/graph"
+	"go-backend/token"
+	"go-backend/util"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// The `addGraphQLRoutes` function adds the GraphQL endpoint, serving the graph of the authenticated user
+// in one round trip, and the SDL of its schema for the clients generating their types from it.
+func (server *Server) addGraphQLRoutes(apiRouter *routeGroup) {
+	graphRouter := apiRouter.Group("/graphql")
+	graphRouter.POST("", server.executeGraphQL)
+	graphRouter.GET("/schema.graphql", server.getGraphQLSchema)
+}
+
+// The `newGraphQLPagination` function bounds the lists of the GraphQL schema with the pagination
+// policies of the matching REST listings.
+func newGraphQLPagination(policies map[string]util.PaginationPolicy) graph.Pagination {
+	return graph.Pagination{
+		Accounts:  policies[paginationAccounts],
+		Entries:   policies[paginationEntries],
+		Transfers: policies[paginationTransfers],
+	}
+}
+
+// This is a function that executes a GraphQL query for the authenticated user. The response follows the
+// GraphQL conventions rather than those of the REST API: it has a 200 status once the query could be
+// read, the errors being listed besides the data, and its fields are those of the query, so it is
+// written as is rather than with `renderJSON`.
+func (server *Server) executeGraphQL(ctx *gin.Context) {
+	var req graph.Request
+	decoder := json.NewDecoder(ctx.Request.Body)
+	// the variables keep their integers whole, e.g. the ids beyond the precision of a float64
+	decoder.UseNumber()
+	if err := decoder.Decode(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+	if req.Query == "" {
+		err := errors.New("query is required")
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	res := server.graph.Execute(graph.WithUser(ctx, authPayload.Username), req)
+
+	for _, err := range res.Errors {
+		// the internal errors of the fields are reported to the error tracking, like those of the REST API
+		if err.Code() == util.ErrorCodeInternal {
+			ctx.Error(err)
+		}
+	}
+	ctx.JSON(http.StatusOK, res)
+}
+
+// This is a function that serves the schema of the GraphQL endpoint in the GraphQL schema definition
+// language.
+func (server *Server) getGraphQLSchema(ctx *gin.Context) {
+	ctx.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(server.graph.SDL()))
+}
@@ -0,0 +1,302 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/testutil/factory"
+	"go-backend/token"
+	"go-backend/util"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+type graphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string         `json:"message"`
+		Path       []any          `json:"path"`
+		Extensions map[string]any `json:"extensions"`
+	} `json:"errors"`
+}
+
+func TestExecuteGraphQLAPI(t *testing.T) {
+	user := factory.User()
+	admin := factory.User(factory.WithRole(util.AdminRole))
+	account := factory.Account(factory.OwnedBy(user.Username))
+	counterparty := factory.Account()
+	transfer := factory.Transfer(factory.Between(account, counterparty))
+	entry := factory.Entry(factory.OfAccount(account))
+	entry.Amount = -transfer.Amount
+	entry.TransferID = pgtype.Int8{Int64: transfer.ID, Valid: true}
+
+	entries := []db.ListEntriesWithCounterpartyBeforeRow{
+		{
+			Entry:                 entry,
+			CounterpartyAccountID: pgtype.Int8{Int64: counterparty.ID, Valid: true},
+			CounterpartyUsername:  pgtype.Text{String: counterparty.Owner, Valid: true},
+			CounterpartyFullName:  pgtype.Text{String: "Counter Party", Valid: true},
+			Currency:              account.Currency,
+		},
+	}
+	transfers := []db.SearchTransfersBeforeRow{
+		{
+			Transfer:              transfer,
+			CounterpartyAccountID: counterparty.ID,
+			CounterpartyUsername:  counterparty.Owner,
+			CounterpartyFullName:  "Counter Party",
+			Currency:              account.Currency,
+		},
+	}
+
+	testCases := []struct {
+		name          string
+		body          string
+		setupAuth     func(request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: `{"query": "query Me($first: Int) { me { username role accounts { id balance entries(first: $first) { nodes { amount transferId counterparty { username } } nextCursor } transfers { nodes { id amount counterparty { fullName } } nextCursor } } } }", "variables": {"first": 1}}`,
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				accountsArg := db.SearchAccountsParams{Owner: user.Username, RowLimit: 20, RowOffset: 0}
+				store.EXPECT().SearchAccounts(gomock.Any(), gomock.Eq(accountsArg)).Times(1).Return([]db.Account{account}, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(2).Return(account, nil)
+				entriesArg := db.ListEntriesWithCounterpartyBeforeParams{AccountID: account.ID, RowLimit: 1}
+				store.EXPECT().ListEntriesWithCounterpartyBefore(gomock.Any(), gomock.Eq(entriesArg)).Times(1).Return(entries, nil)
+				transfersArg := db.SearchTransfersBeforeParams{AccountID: account.ID, RowLimit: 20}
+				store.EXPECT().SearchTransfersBefore(gomock.Any(), gomock.Eq(transfersArg)).Times(1).Return(transfers, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				res := decodeGraphQLResponse(t, recorder.Body)
+				require.Empty(t, res.Errors)
+				require.JSONEq(t, fmt.Sprintf(`{"me": {"username": %q, "role": "DEPOSITOR", "accounts": [{
+					"id": "%d",
+					"balance": %d,
+					"entries": {"nodes": [{"amount": %d, "transferId": "%d", "counterparty": {"username": %q}}], "nextCursor": %q},
+					"transfers": {"nodes": [{"id": "%d", "amount": %d, "counterparty": {"fullName": "Counter Party"}}], "nextCursor": null}
+				}]}}`,
+					user.Username, account.ID, account.Balance,
+					entry.Amount, transfer.ID, counterparty.Owner, util.EncodeCursor(entry.ID),
+					transfer.ID, transfer.Amount,
+				), string(res.Data))
+			},
+		},
+		{
+			name: "AccountOfAnotherUser",
+			body: fmt.Sprintf(`{"query": "{ account(id: %d) { id balance } }"}`, counterparty.ID),
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(counterparty.ID)).Times(1).Return(counterparty, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(db.AccountMember{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				res := decodeGraphQLResponse(t, recorder.Body)
+				require.JSONEq(t, `{"account": null}`, string(res.Data))
+				require.Len(t, res.Errors, 1)
+				require.Equal(t, []any{"account"}, res.Errors[0].Path)
+				require.Equal(t, util.ErrorCodePermissionDenied, res.Errors[0].Extensions["code"])
+			},
+		},
+		{
+			name: "AdminReadsUser",
+			body: fmt.Sprintf(`{"query": "{ user(username: \"%s\") { username email } }"}`, user.Username),
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, admin.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).Times(1).Return(admin, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				res := decodeGraphQLResponse(t, recorder.Body)
+				require.Empty(t, res.Errors)
+				require.JSONEq(t, fmt.Sprintf(`{"user": {"username": %q, "email": %q}}`, user.Username, user.Email), string(res.Data))
+			},
+		},
+		{
+			name: "DepositorReadsUser",
+			body: fmt.Sprintf(`{"query": "{ user(username: \"%s\") { username } }"}`, admin.Username),
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				res := decodeGraphQLResponse(t, recorder.Body)
+				require.JSONEq(t, `{"user": null}`, string(res.Data))
+				require.Len(t, res.Errors, 1)
+				require.Equal(t, util.ErrorCodePermissionDenied, res.Errors[0].Extensions["code"])
+			},
+		},
+		{
+			name: "PageTooLarge",
+			body: fmt.Sprintf(`{"query": "{ account(id: %d) { entries(first: 1000) { nodes { id } } } }"}`, account.ID),
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				// the query is rejected before any field is resolved
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().ListEntriesWithCounterpartyBefore(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				res := decodeGraphQLResponse(t, recorder.Body)
+				require.Nil(t, res.Data)
+				require.Len(t, res.Errors, 1)
+				require.Equal(t, util.ErrorCodeInvalidArgument, res.Errors[0].Extensions["code"])
+			},
+		},
+		{
+			name: "TooComplex",
+			body: `{"query": "{ me { accounts(first: 100) { entries(first: 100) { nodes { id amount } } transfers(first: 100) { nodes { id } } } } }"}`,
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				res := decodeGraphQLResponse(t, recorder.Body)
+				require.Nil(t, res.Data)
+				require.Len(t, res.Errors, 1)
+				require.Contains(t, res.Errors[0].Message, "more complex")
+			},
+		},
+		{
+			name: "InternalError",
+			body: `{"query": "{ me { username } }"}`,
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(1).Return(db.User{}, fmt.Errorf("connection reset"))
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				res := decodeGraphQLResponse(t, recorder.Body)
+				require.JSONEq(t, `null`, string(res.Data))
+				require.Len(t, res.Errors, 1)
+				require.Equal(t, "internal error", res.Errors[0].Message)
+				require.Equal(t, util.ErrorCodeInternal, res.Errors[0].Extensions["code"])
+			},
+		},
+		{
+			name: "InvalidQuery",
+			body: `{"query": "{ me { password } }"}`,
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				res := decodeGraphQLResponse(t, recorder.Body)
+				require.Nil(t, res.Data)
+				require.Len(t, res.Errors, 1)
+			},
+		},
+		{
+			name: "MissingQuery",
+			body: `{"variables": {}}`,
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:       "NoAuthorization",
+			body:       `{"query": "{ me { username } }"}`,
+			setupAuth:  func(request *http.Request, tokenMaker token.Maker) {},
+			buildStubs: func(store *mockdb.MockStore) {},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodPost, "/api/v1/graphql", bytes.NewBufferString(tc.body))
+			require.NoError(t, err)
+
+			tc.setupAuth(request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestGetGraphQLSchemaAPI(t *testing.T) {
+	user := factory.User()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := newTestServer(t, mockdb.NewMockStore(ctrl))
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/api/v1/graphql/schema.graphql", nil)
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Contains(t, recorder.Body.String(), "type Query {")
+	require.Contains(t, recorder.Body.String(), "@auth(role: ADMIN)")
+}
+
+func decodeGraphQLResponse(t *testing.T, body io.Reader) graphQLResponse {
+	var res graphQLResponse
+	err := json.NewDecoder(body).Decode(&res)
+	require.NoError(t, err)
+	return res
+}
@@ -0,0 +1,175 @@
+package api
+
+import (
+	db "go-backend/db/sqlc"
+	"go-backend/service"
+	"go-backend/token"
+	"go-backend/util"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// impersonatorHeaderKey is the header the responses to the requests made with an impersonation token
+// name the impersonating admin in, for the apps to show the support is acting as the user.
+const impersonatorHeaderKey = "X-Impersonator"
+
+// The `addImpersonationRoutes` function adds the routes of the admins impersonating users and of the
+// audit log flagging what they did.
+func (server *Server) addImpersonationRoutes(adminRouter *routeGroup) {
+	adminRouter.POST("/users/:username/impersonate", server.impersonateUser)
+	adminRouter.GET("/audit_entries", server.listAuditEntries)
+}
+
+// The `impersonationMiddleware` function records in the audit log every request made with an
+// impersonation token, flagged with the impersonating admin, once it is served. The requests of the users
+// themselves aren't recorded. It must be registered directly after `authMiddleware`, so the requests
+// rejected by the later middlewares are recorded as well.
+func (server *Server) impersonationMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+		if authPayload.Impersonator == "" {
+			ctx.Next()
+			return
+		}
+
+		ctx.Header(impersonatorHeaderKey, authPayload.Impersonator)
+		ctx.Next()
+
+		route := ctx.FullPath()
+		if route == "" {
+			route = ctx.Request.URL.Path
+		}
+		_, err := server.service.RecordAuditEntry(ctx, service.AuditEntryParams{
+			Username:     authPayload.Username,
+			Impersonator: authPayload.Impersonator,
+			Action:       ctx.Request.Method + " " + v1Path(route),
+			Detail:       ctx.Request.URL.RequestURI(),
+			Status:       int32(ctx.Writer.Status()),
+		})
+		if err != nil {
+			log.Printf("cannot record the request of %s impersonating %s: %v", authPayload.Impersonator, authPayload.Username, err)
+		}
+	}
+}
+
+type impersonateUserURI struct {
+	Username string `uri:"username" binding:"required,alphanum"`
+}
+
+type impersonateUserRequest struct {
+	// why the user is impersonated, e.g. the support ticket
+	Reason          string `json:"reason" binding:"required,max=500"`
+	DurationMinutes int64  `json:"duration_minutes" binding:"omitempty,min=1"`
+}
+
+type impersonateUserResponse struct {
+	Username             string    `json:"username"`
+	Impersonator         string    `json:"impersonator"`
+	AccessToken          string    `json:"access_token"`
+	AccessTokenExpiresAt time.Time `json:"access_token_expires_at"`
+}
+
+// This is a function that issues the authenticated admin a time-boxed access token of a user, for the
+// support to reproduce the issues the user reported. Every request made with the token is recorded in
+// the audit log, flagged with the admin.
+func (server *Server) impersonateUser(ctx *gin.Context) {
+	var uri impersonateUserURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+	var req impersonateUserRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	result, err := server.service.Impersonate(ctx, service.ImpersonateParams{
+		Impersonator: authPayload.Username,
+		Username:     uri.Username,
+		Reason:       req.Reason,
+		Duration:     time.Duration(req.DurationMinutes) * time.Minute,
+	})
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, impersonateUserResponse{
+		Username:             result.AccessPayload.Username,
+		Impersonator:         result.AccessPayload.Impersonator,
+		AccessToken:          result.AccessToken,
+		AccessTokenExpiresAt: result.AccessPayload.ExpiredAt,
+	})
+}
+
+type listAuditEntriesRequest struct {
+	pageRequest
+	Username     string `form:"username" binding:"omitempty,alphanum"`
+	Impersonator string `form:"impersonator" binding:"omitempty,alphanum"`
+}
+
+// The auditEntryResponse type is an action of the audit log.
+// @property {*string} Impersonator - the admin who took the action impersonating the user, null when
+// the user took it.
+type auditEntryResponse struct {
+	ID           int64     `json:"id"`
+	Username     string    `json:"username"`
+	Impersonator *string   `json:"impersonator"`
+	Action       string    `json:"action"`
+	Detail       string    `json:"detail"`
+	Status       int32     `json:"status"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+func newAuditEntryResponse(entry db.AuditEntry) auditEntryResponse {
+	res := auditEntryResponse{
+		ID:        entry.ID,
+		Username:  entry.Username,
+		Action:    entry.Action,
+		Detail:    entry.Detail,
+		Status:    entry.Status,
+		CreatedAt: entry.CreatedAt,
+	}
+	if entry.Impersonator.Valid {
+		res.Impersonator = &entry.Impersonator.String
+	}
+	return res
+}
+
+// This is a function that lists the actions of the audit log, the newest first, only those taken as the
+// user of the `username` query parameter or by the admin of the `impersonator` one when they are set.
+func (server *Server) listAuditEntries(ctx *gin.Context) {
+	var req listAuditEntriesRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	limit, offset, err := server.paginate(paginationAdmin, req.pageRequest)
+	if err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	entries, err := server.service.ListAuditEntries(ctx, service.ListAuditEntriesParams{
+		Username:     req.Username,
+		Impersonator: req.Impersonator,
+		Limit:        limit,
+		Offset:       offset,
+	})
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	res := make([]auditEntryResponse, len(entries))
+	for i, entry := range entries {
+		res[i] = newAuditEntryResponse(entry)
+	}
+	renderJSON(ctx, http.StatusOK, res)
+}
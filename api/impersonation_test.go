@@ -0,0 +1,220 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/testutil/factory"
+	"go-backend/token"
+	"go-backend/util"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImpersonateUserAPI(t *testing.T) {
+	admin := factory.User(factory.WithRole(util.AdminRole))
+	user := factory.User()
+
+	testCases := []struct {
+		name          string
+		body          string
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: `{"reason": "ticket 42"}`,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().
+					CreateAuditEntry(gomock.Any(), gomock.Eq(db.CreateAuditEntryParams{
+						Username:     user.Username,
+						Impersonator: pgtype.Text{String: admin.Username, Valid: true},
+						Action:       "impersonation.started",
+						Detail:       "ticket 42",
+					})).
+					Times(1)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res impersonateUserResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.Equal(t, user.Username, res.Username)
+				require.Equal(t, admin.Username, res.Impersonator)
+
+				payload, err := server.tokenMaker.VerifyToken(res.AccessToken)
+				require.NoError(t, err)
+				require.Equal(t, user.Username, payload.Username)
+				require.Equal(t, admin.Username, payload.Impersonator)
+			},
+		},
+		{
+			name: "MissingReason",
+			body: `{}`,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateAuditEntry(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "AdminImpersonated",
+			body: `{"reason": "ticket 42"}`,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(factory.User(factory.WithRole(util.AdminRole)), nil)
+				store.EXPECT().CreateAuditEntry(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				requireErrorBody(t, recorder.Body, util.ErrorCodePermissionDenied)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).Times(1).Return(admin, nil)
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/api/v1/admin/users/%s/impersonate", user.Username)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewBufferString(tc.body))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, admin.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, server, recorder)
+		})
+	}
+}
+
+func TestImpersonationMiddleware(t *testing.T) {
+	admin := factory.User(factory.WithRole(util.AdminRole))
+	user := factory.User()
+	account := factory.Account(factory.OwnedBy(user.Username))
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+	store.EXPECT().
+		CreateAuditEntry(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, arg db.CreateAuditEntryParams) (db.AuditEntry, error) {
+			require.Equal(t, user.Username, arg.Username)
+			require.Equal(t, admin.Username, arg.Impersonator.String)
+			require.Equal(t, "GET /api/v1/accounts/:id", arg.Action)
+			require.Equal(t, fmt.Sprintf("/api/v2/accounts/%d", account.ID), arg.Detail)
+			require.Equal(t, int32(http.StatusOK), arg.Status)
+			return db.AuditEntry{ID: 1}, nil
+		})
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	// the actions are recorded by their v1 route, whatever the version they were sent to
+	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/api/v2/accounts/%d", account.ID), nil)
+	require.NoError(t, err)
+
+	accessToken, _, err := server.tokenMaker.CreateToken(token.Claims{Username: user.Username, Impersonator: admin.Username}, time.Minute)
+	require.NoError(t, err)
+	request.Header.Set(authorizationHeaderKey, fmt.Sprintf("%s %s", authorizationTypeBearer, accessToken))
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, admin.Username, recorder.Header().Get(impersonatorHeaderKey))
+}
+
+func TestImpersonationMiddlewareRejectedRequest(t *testing.T) {
+	admin := factory.User(factory.WithRole(util.AdminRole))
+	user := factory.User()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// the requests the later middlewares reject are recorded too
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+	store.EXPECT().
+		CreateAuditEntry(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, arg db.CreateAuditEntryParams) (db.AuditEntry, error) {
+			require.Equal(t, admin.Username, arg.Impersonator.String)
+			require.Equal(t, "GET /api/v1/accounts/:id", arg.Action)
+			require.Equal(t, int32(http.StatusForbidden), arg.Status)
+			return db.AuditEntry{ID: 1}, nil
+		})
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/api/v1/accounts/1", nil)
+	require.NoError(t, err)
+
+	claims := token.Claims{Username: user.Username, Impersonator: admin.Username, Scopes: []string{token.ScopeTransfersRead}}
+	accessToken, _, err := server.tokenMaker.CreateToken(claims, time.Minute)
+	require.NoError(t, err)
+	request.Header.Set(authorizationHeaderKey, fmt.Sprintf("%s %s", authorizationTypeBearer, accessToken))
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusForbidden, recorder.Code)
+	require.Equal(t, admin.Username, recorder.Header().Get(impersonatorHeaderKey))
+}
+
+func TestListAuditEntriesAPI(t *testing.T) {
+	admin := factory.User(factory.WithRole(util.AdminRole))
+	user := factory.User()
+	entries := []db.AuditEntry{
+		{ID: 2, Username: user.Username, Impersonator: pgtype.Text{String: admin.Username, Valid: true}, Action: "POST /api/v1/transfers", Status: http.StatusCreated},
+		{ID: 1, Username: user.Username, Action: "impersonation.started", Detail: "ticket 42"},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).Times(1).Return(admin, nil)
+	store.EXPECT().
+		ListAuditEntries(gomock.Any(), gomock.Eq(db.ListAuditEntriesParams{
+			Username: pgtype.Text{String: user.Username, Valid: true},
+			RowLimit: 20,
+		})).
+		Times(1).
+		Return(entries, nil)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/api/v1/admin/audit_entries?username="+user.Username, nil)
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, admin.Username, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var res []auditEntryResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+	require.Len(t, res, 2)
+	require.Equal(t, admin.Username, *res[0].Impersonator)
+	require.Nil(t, res[1].Impersonator)
+}
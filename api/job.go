@@ -0,0 +1,193 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	db "go-backend/db/sqlc"
+	"go-backend/service"
+	"go-backend/token"
+	"go-backend/util"
+	"go-backend/worker"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// The `addJobRoutes` function adds the routes used to create, track and cancel export jobs, and the one
+// serving finished exports. The latter is anonymous, authorized by the signature of the url instead of
+// the authorization header so that it can be handed to a browser.
+func (server *Server) addJobRoutes(apiRouter *routeGroup) {
+	jobRouter := apiRouter.Group("/jobs")
+	jobRouter.POST("", server.createJob)
+	jobRouter.GET("/:id", server.getJob)
+	jobRouter.DELETE("/:id", server.cancelJob)
+	jobRouter.With(anonymous()).GET("/:id/download", server.downloadJob)
+}
+
+// The jobResponse type is the representation of an export job returned to clients. The download url is
+// only set once the job succeeded, and expires after the configured duration.
+type jobResponse struct {
+	ID          uuid.UUID  `json:"id"`
+	Kind        string     `json:"kind"`
+	Status      string     `json:"status"`
+	Progress    int32      `json:"progress"`
+	Error       string     `json:"error,omitempty"`
+	DownloadURL string     `json:"download_url,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+func (server *Server) newJobResponse(job db.Job) jobResponse {
+	res := jobResponse{
+		ID:        job.ID,
+		Kind:      job.Kind,
+		Status:    job.Status,
+		Progress:  job.Progress,
+		Error:     job.Error,
+		CreatedAt: job.CreatedAt,
+		UpdatedAt: job.UpdatedAt,
+	}
+
+	if job.CompletedAt.Valid {
+		res.CompletedAt = &job.CompletedAt.Time
+	}
+
+	if job.Status == worker.JobStatusSucceeded {
+		expires := time.Now().Add(server.config.ExportURLDuration).Unix()
+		signature := util.Sign(server.config.ExportSigningKey, downloadMessage(job.ID, expires))
+		res.DownloadURL = fmt.Sprintf("/api/v1/jobs/%s/download?expires=%d&signature=%s", job.ID, expires, signature)
+	}
+
+	return res
+}
+
+// downloadMessage is the message signed in a download url.
+func downloadMessage(jobID uuid.UUID, expires int64) string {
+	return fmt.Sprintf("%s:%d", jobID, expires)
+}
+
+// The createJobRequest type is a request to export data in the background. The account is required for
+// statements and entries, and statements also need the period to cover.
+type createJobRequest struct {
+	Kind      string    `json:"kind" binding:"required,oneof=statement gdpr entries"`
+	AccountID int64     `json:"account_id" binding:"required_unless=Kind gdpr,omitempty,min=1"`
+	From      time.Time `json:"from" binding:"required_if=Kind statement"`
+	To        time.Time `json:"to" binding:"required_if=Kind statement,omitempty,gtfield=From"`
+}
+
+// This is a function that creates an export job for the authenticated user and enqueues the task
+// generating its file. It returns a 202 Accepted response with the job so that its progress can be
+// tracked with `GET /jobs/:id`.
+func (server *Server) createJob(ctx *gin.Context) {
+	var req createJobRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	job, err := server.service.CreateJob(ctx, service.CreateJobParams{
+		Username:  authPayload.Username,
+		Kind:      req.Kind,
+		AccountID: req.AccountID,
+		From:      req.From,
+		To:        req.To,
+	})
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusAccepted, server.newJobResponse(job))
+}
+
+type jobRequest struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+// This is a function that reports the status and progress of an export job.
+func (server *Server) getJob(ctx *gin.Context) {
+	var req jobRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	job, err := server.service.GetJob(ctx, authPayload.Username, uuid.MustParse(req.ID))
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, server.newJobResponse(job))
+}
+
+// This is a function that cancels a pending or running export job. A job that already finished can't be
+// cancelled anymore, in which case it returns a 409 Conflict response.
+func (server *Server) cancelJob(ctx *gin.Context) {
+	var req jobRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	job, err := server.service.CancelJob(ctx, authPayload.Username, uuid.MustParse(req.ID))
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, server.newJobResponse(job))
+}
+
+type downloadJobRequest struct {
+	Expires   int64  `form:"expires" binding:"required"`
+	Signature string `form:"signature" binding:"required,hexadecimal"`
+}
+
+// This is a function that serves the file of a finished export job, provided the url was signed by the
+// server and has not expired yet.
+func (server *Server) downloadJob(ctx *gin.Context) {
+	var uriReq jobRequest
+	if err := ctx.ShouldBindUri(&uriReq); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	var req downloadJobRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	jobID := uuid.MustParse(uriReq.ID)
+	if !util.VerifySignature(server.config.ExportSigningKey, downloadMessage(jobID, req.Expires), req.Signature) {
+		err := errors.New("invalid download signature")
+		renderJSON(ctx, http.StatusForbidden, util.ErrorResponse(http.StatusForbidden, err))
+		return
+	}
+
+	if time.Now().Unix() > req.Expires {
+		err := errors.New("download url has expired")
+		renderJSON(ctx, http.StatusForbidden, util.ErrorResponse(http.StatusForbidden, err))
+		return
+	}
+
+	job, err := server.service.GetJobResult(ctx, jobID)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	extension := "csv"
+	if job.ResultContentType == "application/json" {
+		extension = "json"
+	}
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s-%s.%s", job.Kind, job.ID, extension))
+	ctx.Data(http.StatusOK, job.ResultContentType, job.Result)
+}
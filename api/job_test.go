@@ -0,0 +1,472 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/testutil/factory"
+	"go-backend/token"
+	"go-backend/util"
+	"go-backend/worker"
+	mockwk "go-backend/worker/mock"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateJobAPI(t *testing.T) {
+	user := factory.User()
+	account := factory.Account(factory.OwnedBy(user.Username))
+	job := randomJob(user.Username, worker.ExportKindEntries)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		setupAuth     func(request *http.Request, tokenMaker token.Maker)
+		buildStub     func(store *mockdb.MockStore, distributor *mockwk.MockTaskDistributor)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"kind":       worker.ExportKindEntries,
+				"account_id": account.ID,
+			},
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore, distributor *mockwk.MockTaskDistributor) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().CreateJob(gomock.Any(), gomock.Any()).Times(1).Return(job, nil)
+				distributor.EXPECT().
+					DistributeTaskRunExport(gomock.Any(), gomock.Eq(&worker.PayloadRunExport{JobID: job.ID}), gomock.Any()).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+				requireBodyMatchJob(t, recorder.Body, job)
+			},
+		},
+		{
+			name: "GDPRWithoutAccount",
+			body: gin.H{
+				"kind": worker.ExportKindGDPR,
+			},
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore, distributor *mockwk.MockTaskDistributor) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateJob(gomock.Any(), gomock.Any()).Times(1).Return(job, nil)
+				distributor.EXPECT().DistributeTaskRunExport(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+			},
+		},
+		{
+			name: "Unauthorized",
+			body: gin.H{
+				"kind":       worker.ExportKindEntries,
+				"account_id": account.ID,
+			},
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "not user", time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore, distributor *mockwk.MockTaskDistributor) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(db.AccountMember{}, db.ErrRecordNotFound)
+				store.EXPECT().CreateJob(gomock.Any(), gomock.Any()).Times(0)
+				distributor.EXPECT().DistributeTaskRunExport(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "StatementWithoutPeriod",
+			body: gin.H{
+				"kind":       worker.ExportKindStatement,
+				"account_id": account.ID,
+			},
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore, distributor *mockwk.MockTaskDistributor) {
+				store.EXPECT().CreateJob(gomock.Any(), gomock.Any()).Times(0)
+				distributor.EXPECT().DistributeTaskRunExport(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidKind",
+			body: gin.H{
+				"kind":       "invalid",
+				"account_id": account.ID,
+			},
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore, distributor *mockwk.MockTaskDistributor) {
+				store.EXPECT().CreateJob(gomock.Any(), gomock.Any()).Times(0)
+				distributor.EXPECT().DistributeTaskRunExport(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "DistributeError",
+			body: gin.H{
+				"kind": worker.ExportKindGDPR,
+			},
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore, distributor *mockwk.MockTaskDistributor) {
+				store.EXPECT().CreateJob(gomock.Any(), gomock.Any()).Times(1).Return(job, nil)
+				distributor.EXPECT().
+					DistributeTaskRunExport(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(fmt.Errorf("redis is down"))
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			distributor := mockwk.NewMockTaskDistributor(ctrl)
+			tc.buildStub(store, distributor)
+
+			// start test server and send request
+			server := newTestServerWithDistributor(t, store, distributor)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := "/api/v1/jobs"
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			tc.setupAuth(request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestGetJobAPI(t *testing.T) {
+	user := factory.User()
+	job := randomJob(user.Username, worker.ExportKindGDPR)
+
+	testCases := []struct {
+		name          string
+		jobID         string
+		setupAuth     func(request *http.Request, tokenMaker token.Maker)
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			jobID: job.ID.String(),
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetJob(gomock.Any(), gomock.Eq(job.ID)).Times(1).Return(job, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchJob(t, recorder.Body, job)
+			},
+		},
+		{
+			name:  "Unauthorized",
+			jobID: job.ID.String(),
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "not user", time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetJob(gomock.Any(), gomock.Eq(job.ID)).Times(1).Return(job, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:  "NotFound",
+			jobID: job.ID.String(),
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetJob(gomock.Any(), gomock.Eq(job.ID)).Times(1).Return(db.Job{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:  "InvalidID",
+			jobID: "not-a-uuid",
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetJob(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			// start test server and send request
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/api/v1/jobs/%s", tc.jobID)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestCancelJobAPI(t *testing.T) {
+	user := factory.User()
+	job := randomJob(user.Username, worker.ExportKindGDPR)
+	cancelled := job
+	cancelled.Status = worker.JobStatusCancelled
+
+	testCases := []struct {
+		name          string
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetJob(gomock.Any(), gomock.Eq(job.ID)).Times(1).Return(job, nil)
+				store.EXPECT().CancelJob(gomock.Any(), gomock.Eq(job.ID)).Times(1).Return(cancelled, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchJob(t, recorder.Body, cancelled)
+			},
+		},
+		{
+			name: "AlreadyFinished",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetJob(gomock.Any(), gomock.Eq(job.ID)).Times(1).Return(job, nil)
+				store.EXPECT().CancelJob(gomock.Any(), gomock.Eq(job.ID)).Times(1).Return(db.Job{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetJob(gomock.Any(), gomock.Eq(job.ID)).Times(1).Return(job, nil)
+				store.EXPECT().CancelJob(gomock.Any(), gomock.Eq(job.ID)).Times(1).Return(db.Job{}, sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			// start test server and send request
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/api/v1/jobs/%s", job.ID)
+			request, err := http.NewRequest(http.MethodDelete, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestDownloadJobAPI(t *testing.T) {
+	user := factory.User()
+	job := randomJob(user.Username, worker.ExportKindEntries)
+	job.Status = worker.JobStatusSucceeded
+	job.Progress = 100
+	job.Result = []byte("id,account_id,amount,created_at\n")
+	job.ResultContentType = "text/csv"
+
+	testCases := []struct {
+		name          string
+		buildURL      func(server *Server) string
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildURL: func(server *Server) string {
+				return server.newJobResponse(job).DownloadURL
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetJob(gomock.Any(), gomock.Eq(job.ID)).Times(1).Return(job, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, job.Result, recorder.Body.Bytes())
+				require.Equal(t, "text/csv", recorder.Header().Get("Content-Type"))
+			},
+		},
+		{
+			name: "InvalidSignature",
+			buildURL: func(server *Server) string {
+				expires := time.Now().Add(time.Minute).Unix()
+				signature := util.Sign(util.RandomString(32), downloadMessage(job.ID, expires))
+				return fmt.Sprintf("/api/v1/jobs/%s/download?expires=%d&signature=%s", job.ID, expires, signature)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetJob(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "SignedWithTokenKey",
+			buildURL: func(server *Server) string {
+				expires := time.Now().Add(time.Minute).Unix()
+				signature := util.Sign(server.config.TokenSymmetricKey, downloadMessage(job.ID, expires))
+				return fmt.Sprintf("/api/v1/jobs/%s/download?expires=%d&signature=%s", job.ID, expires, signature)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetJob(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "Expired",
+			buildURL: func(server *Server) string {
+				expires := time.Now().Add(-time.Minute).Unix()
+				signature := util.Sign(server.config.ExportSigningKey, downloadMessage(job.ID, expires))
+				return fmt.Sprintf("/api/v1/jobs/%s/download?expires=%d&signature=%s", job.ID, expires, signature)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetJob(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "NotFinished",
+			buildURL: func(server *Server) string {
+				return server.newJobResponse(job).DownloadURL
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				running := job
+				running.Status = worker.JobStatusRunning
+				store.EXPECT().GetJob(gomock.Any(), gomock.Eq(job.ID)).Times(1).Return(running, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			// start test server and send request without authorization header
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, tc.buildURL(server), nil)
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func randomJob(username string, kind string) db.Job {
+	return db.Job{
+		ID:       uuid.New(),
+		Username: username,
+		Kind:     kind,
+		Params:   json.RawMessage(`{}`),
+		Status:   worker.JobStatusPending,
+	}
+}
+
+func requireBodyMatchJob(t *testing.T, body *bytes.Buffer, job db.Job) {
+	data, err := io.ReadAll(body)
+	require.NoError(t, err)
+
+	var gotJob jobResponse
+	err = json.Unmarshal(data, &gotJob)
+	require.NoError(t, err)
+
+	require.Equal(t, job.ID, gotJob.ID)
+	require.Equal(t, job.Kind, gotJob.Kind)
+	require.Equal(t, job.Status, gotJob.Status)
+	require.Equal(t, job.Progress, gotJob.Progress)
+}
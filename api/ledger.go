@@ -0,0 +1,89 @@
+package api
+
+import (
+	db "go-backend/db/sqlc"
+	"go-backend/util"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ledgerAnomalyResponse struct {
+	ID              int64      `json:"id"`
+	Kind            string     `json:"kind"`
+	SubjectID       int64      `json:"subject_id"`
+	Expected        int64      `json:"expected"`
+	Actual          int64      `json:"actual"`
+	FirstDetectedAt time.Time  `json:"first_detected_at"`
+	LastDetectedAt  time.Time  `json:"last_detected_at"`
+	ResolvedAt      *time.Time `json:"resolved_at"`
+}
+
+func newLedgerAnomalyResponses(anomalies []db.LedgerAnomaly) []ledgerAnomalyResponse {
+	res := make([]ledgerAnomalyResponse, 0, len(anomalies))
+	for _, anomaly := range anomalies {
+		res = append(res, ledgerAnomalyResponse{
+			ID:              anomaly.ID,
+			Kind:            anomaly.Kind,
+			SubjectID:       anomaly.SubjectID,
+			Expected:        anomaly.Expected,
+			Actual:          anomaly.Actual,
+			FirstDetectedAt: anomaly.FirstDetectedAt,
+			LastDetectedAt:  anomaly.LastDetectedAt,
+			ResolvedAt:      nullTime(anomaly.ResolvedAt),
+		})
+	}
+	return res
+}
+
+type reconcileLedgerResponse struct {
+	Anomalies []ledgerAnomalyResponse `json:"anomalies"`
+	Resolved  int64                   `json:"resolved"`
+}
+
+// This is a function that reconciles the ledger now rather than waiting for the end-of-day batches: the
+// balance of every account is checked against its entries and the entries of every transfer must net to
+// zero. It returns the anomalies found, along with the number of anomalies found before that are
+// resolved.
+func (server *Server) reconcileLedger(ctx *gin.Context) {
+	result, err := server.service.ReconcileLedger(ctx)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, reconcileLedgerResponse{
+		Anomalies: newLedgerAnomalyResponses(result.Anomalies),
+		Resolved:  result.Resolved,
+	})
+}
+
+type listLedgerAnomaliesRequest struct {
+	pageRequest
+	Open bool `form:"open"`
+}
+
+// This is a function that lists the anomalies found by the reconciliations of the ledger, the last
+// detected first, only those still open when the `open` query parameter is true.
+func (server *Server) listLedgerAnomalies(ctx *gin.Context) {
+	var req listLedgerAnomaliesRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	limit, offset, err := server.paginate(paginationAdmin, req.pageRequest)
+	if err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	anomalies, err := server.service.ListLedgerAnomalies(ctx, req.Open, limit, offset)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, newLedgerAnomalyResponses(anomalies))
+}
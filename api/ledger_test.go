@@ -0,0 +1,141 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/testutil/factory"
+	"go-backend/util"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReconcileLedgerAPI(t *testing.T) {
+	admin := factory.User(factory.WithRole(util.AdminRole))
+	depositor := factory.User()
+
+	result := db.ReconcileLedgerTxResult{
+		Anomalies: []db.LedgerAnomaly{
+			{ID: 1, Kind: db.AnomalyAccountBalance, SubjectID: 7, Expected: 100, Actual: 150},
+			{ID: 2, Kind: db.AnomalyTransferImbalance, SubjectID: 9, Expected: 0, Actual: -5},
+		},
+		Resolved: 3,
+	}
+
+	testCases := []struct {
+		name          string
+		user          db.User
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			user: admin,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).Times(1).Return(admin, nil)
+				store.EXPECT().ReconcileLedgerTx(gomock.Any()).Times(1).Return(result, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got reconcileLedgerResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &got)
+				require.NoError(t, err)
+				require.Equal(t, result.Resolved, got.Resolved)
+				require.Len(t, got.Anomalies, 2)
+				require.Equal(t, db.AnomalyAccountBalance, got.Anomalies[0].Kind)
+				require.Equal(t, int64(150), got.Anomalies[0].Actual)
+				require.Nil(t, got.Anomalies[0].ResolvedAt)
+			},
+		},
+		{
+			name: "Forbidden",
+			user: depositor,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(depositor.Username)).Times(1).Return(depositor, nil)
+				store.EXPECT().ReconcileLedgerTx(gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			user: admin,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).Times(1).Return(admin, nil)
+				store.EXPECT().ReconcileLedgerTx(gomock.Any()).Times(1).Return(db.ReconcileLedgerTxResult{}, sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodPost, "/api/v1/admin/ledger/reconcile", nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestListLedgerAnomaliesAPI(t *testing.T) {
+	admin := factory.User(factory.WithRole(util.AdminRole))
+
+	resolvedAt := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	anomalies := []db.LedgerAnomaly{
+		{ID: 2, Kind: db.AnomalyAccountBalance, SubjectID: 7, Expected: 100, Actual: 150},
+		{ID: 1, Kind: db.AnomalyTransferImbalance, SubjectID: 9, Actual: -5, ResolvedAt: pgtype.Timestamptz{Time: resolvedAt, Valid: true}},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).Times(1).Return(admin, nil)
+	store.EXPECT().ListLedgerAnomalies(gomock.Any(), gomock.Eq(db.ListLedgerAnomaliesParams{
+		OnlyOpen:  true,
+		RowLimit:  20,
+		RowOffset: 0,
+	})).Times(1).Return(anomalies, nil)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/api/v1/admin/ledger/anomalies?open=true", nil)
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, admin.Username, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var got []ledgerAnomalyResponse
+	err = json.Unmarshal(recorder.Body.Bytes(), &got)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	require.Nil(t, got[0].ResolvedAt)
+	require.Equal(t, resolvedAt, got[1].ResolvedAt.UTC())
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"go-backend/util"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultRouteTimeouts are the timeouts of the routes that legitimately take longer than the
+// REQUEST_TIMEOUT, which can be overridden per route with the ROUTE_TIMEOUTS config.
+var defaultRouteTimeouts = map[string]time.Duration{
+	"POST /api/v1/transfers/batch":        30 * time.Second,
+	"POST /api/v1/transfers/pain001":      30 * time.Second,
+	"GET /api/v1/jobs/:id/download":       time.Minute,
+	"POST /api/v1/accounts/:id/import":    time.Minute,
+	"POST /api/v1/admin/ledger/reconcile": time.Minute,
+	// CPU profiles and traces last 30 seconds by default, and may be asked for longer
+	"GET /api/v1/admin/debug/*path": 5 * time.Minute,
+}
+
+// The `newRouteTimeouts` function merges the timeouts of the config over the default ones, every route
+// of the config having to be one of `routes`. The routes are keyed by their v1 path, their timeout
+// applying to every version.
+func newRouteTimeouts(config util.Config, routes gin.RoutesInfo) (map[string]time.Duration, error) {
+	overrides, err := util.ParseRouteTimeouts(config.RouteTimeouts)
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[string]bool, len(routes))
+	for _, route := range routes {
+		known[route.Method+" "+v1Path(route.Path)] = true
+	}
+
+	timeouts := make(map[string]time.Duration, len(defaultRouteTimeouts))
+	for route, timeout := range defaultRouteTimeouts {
+		timeouts[route] = timeout
+	}
+	for route, timeout := range overrides {
+		if !known[route] {
+			return nil, fmt.Errorf("unknown route %s in ROUTE_TIMEOUTS", route)
+		}
+		timeouts[route] = timeout
+	}
+
+	return timeouts, nil
+}
+
+// The `timeoutMiddleware` function cancels the context of the request once it took longer than the
+// timeout of its route, or than the REQUEST_TIMEOUT for the routes without one, so that a slow query
+// can't hold on to a connection of the pool. The handlers pass the context on to the store, whose
+// cancelled queries make the request fail with 504.
+func (server *Server) timeoutMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		timeout, ok := server.timeouts[ctx.Request.Method+" "+v1Path(ctx.FullPath())]
+		if !ok {
+			timeout = server.config.RequestTimeout
+		}
+
+		request, cancel := util.WithRequestTimeout(ctx.Request, timeout)
+		defer cancel()
+
+		ctx.Request = request
+		ctx.Next()
+	}
+}
+
+// The `bodyLimitMiddleware` function rejects with 413 the requests whose body is larger than the
+// MAX_REQUEST_BODY_BYTES, before any handler reads it.
+func (server *Server) bodyLimitMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		err := util.ReadLimitedBody(ctx.Writer, ctx.Request, server.config.MaxRequestBodyBytes)
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				abortWithJSON(ctx, http.StatusRequestEntityTooLarge, util.ErrorResponse(http.StatusRequestEntityTooLarge, err))
+				return
+			}
+			abortWithJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+			return
+		}
+
+		ctx.Next()
+	}
+}
@@ -0,0 +1,135 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/testutil/factory"
+	"go-backend/util"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestTimeout(t *testing.T) {
+	user := factory.User()
+	account := factory.Account(factory.OwnedBy(user.Username))
+
+	testCases := []struct {
+		name          string
+		routeTimeouts string
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "DeadlineExceeded",
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusGatewayTimeout, recorder.Code)
+				requireErrorBody(t, recorder.Body, util.ErrorCodeDeadlineExceeded)
+			},
+		},
+		{
+			name:          "RouteTimeout",
+			routeTimeouts: "GET /api/v1/accounts/:id=1s",
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// the query takes longer than the REQUEST_TIMEOUT, but not than the timeout of the route
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().
+				GetAccount(gomock.Any(), gomock.Eq(account.ID)).
+				Times(1).
+				DoAndReturn(func(ctx context.Context, id int64) (db.Account, error) {
+					select {
+					case <-ctx.Done():
+						return db.Account{}, ctx.Err()
+					case <-time.After(100 * time.Millisecond):
+						return account, nil
+					}
+				})
+
+			server := newTestServerWithConfig(t, store, nil, func(config *util.Config) {
+				config.RequestTimeout = 10 * time.Millisecond
+				config.RouteTimeouts = tc.routeTimeouts
+			})
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/api/v1/accounts/%d", account.ID)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestUnknownRouteTimeout(t *testing.T) {
+	config := util.Config{
+		TokenSymmetricKey: util.RandomString(32),
+		ExportSigningKey:  util.RandomString(32),
+		RouteTimeouts:     "GET /api/v1/unknown=1s",
+	}
+
+	_, err := NewServer(config, nil, nil)
+	require.ErrorContains(t, err, "unknown route GET /api/v1/unknown")
+}
+
+func TestRequestBodyLimit(t *testing.T) {
+	testCases := []struct {
+		name          string
+		body          []byte
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "TooLarge",
+			body: bytes.Repeat([]byte("a"), 1025),
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusRequestEntityTooLarge, recorder.Code)
+				requireErrorBody(t, recorder.Body, util.ErrorCodePayloadTooLarge)
+			},
+		},
+		{
+			// the body fits, and is still read by the handler
+			name: "WithinLimit",
+			body: []byte(`{"username":"ada"}`),
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireErrorBody(t, recorder.Body, util.ErrorCodeValidationFailed)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			server := newTestServerWithConfig(t, nil, nil, func(config *util.Config) {
+				config.MaxRequestBodyBytes = 1024
+			})
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodPost, "/api/v1/users", bytes.NewReader(tc.body))
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
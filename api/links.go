@@ -0,0 +1,90 @@
+package api
+
+import (
+	"fmt"
+	db "go-backend/db/sqlc"
+)
+
+// linkPrefix is the path the links of the responses start with, relative to the host the request was
+// sent to.
+const linkPrefix = "/api/v1"
+
+// The link type is a link of a response to a related resource, in the HAL format.
+// @property {bool} Templated - set when the href is a URI template whose query parameters are filled in
+// by the client, e.g. the period of a statement.
+type link struct {
+	Href      string `json:"href"`
+	Templated bool   `json:"templated,omitempty"`
+}
+
+// The links type holds the links of a resource by relation, sent in its _links field so that clients can
+// navigate the API without building its URLs themselves.
+type links map[string]link
+
+// The `linkTo` function returns the link to a path of the API, written like fmt.Sprintf.
+func linkTo(format string, a ...any) link {
+	return link{Href: linkPrefix + fmt.Sprintf(format, a...)}
+}
+
+// The `templatedLinkTo` function returns the link to a path of the API taking the query parameters
+// named in `params`, which the client fills in.
+func templatedLinkTo(params string, format string, a ...any) link {
+	l := linkTo(format, a...)
+	l.Href += "{?" + params + "}"
+	l.Templated = true
+	return l
+}
+
+// The `accountLinks` function returns the links of an account: itself, its entries, its transfers and
+// its statement.
+func accountLinks(id int64) links {
+	return links{
+		"self":      linkTo("/accounts/%d", id),
+		"entries":   linkTo("/accounts/%d/entries", id),
+		"transfers": linkTo("/transfers?account_id=%d", id),
+		"statement": templatedLinkTo("format,from,to", "/accounts/%d/export", id),
+	}
+}
+
+// The `transferLinks` function returns the links of a transfer seen from an account of the authenticated
+// user, the one it was sent from or received by: that account, its entries, including the one of the
+// transfer, its transfers and its statement. Transfers have no route of their own to link to, and the
+// other account usually belongs to another user.
+func transferLinks(accountID int64) links {
+	l := accountLinks(accountID)
+	l["account"] = l["self"]
+	delete(l, "self")
+	return l
+}
+
+// The accountResponse type is an account along with its links.
+// @property {int64} AvailableBalance - what the account can spend, its balance, the ledger balance, less
+// its held balance.
+type accountResponse struct {
+	db.Account
+	AvailableBalance int64 `json:"available_balance"`
+	Links            links `json:"_links"`
+}
+
+func newAccountResponse(account db.Account) accountResponse {
+	return accountResponse{Account: account, AvailableBalance: db.AvailableBalance(account), Links: accountLinks(account.ID)}
+}
+
+func newAccountResponses(accounts []db.Account) []accountResponse {
+	res := make([]accountResponse, len(accounts))
+	for i, account := range accounts {
+		res[i] = newAccountResponse(account)
+	}
+	return res
+}
+
+// The transferResultResponse type is the result of a transfer along with the links of the transfer, seen
+// from the account it was sent from.
+type transferResultResponse struct {
+	db.TransferTxResult
+	Links links `json:"_links"`
+}
+
+func newTransferResultResponse(result db.TransferTxResult) transferResultResponse {
+	return transferResultResponse{TransferTxResult: result, Links: transferLinks(result.Transfer.FromAccountID)}
+}
@@ -0,0 +1,46 @@
+package api
+
+import (
+	"encoding/json"
+	db "go-backend/db/sqlc"
+	"go-backend/util"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountLinks(t *testing.T) {
+	require.Equal(t, links{
+		"self":      {Href: "/api/v1/accounts/7"},
+		"entries":   {Href: "/api/v1/accounts/7/entries"},
+		"transfers": {Href: "/api/v1/transfers?account_id=7"},
+		"statement": {Href: "/api/v1/accounts/7/export{?format,from,to}", Templated: true},
+	}, accountLinks(7))
+
+	transfer := transferLinks(7)
+	require.Equal(t, accountLinks(7)["self"], transfer["account"])
+	require.NotContains(t, transfer, "self")
+}
+
+func TestPresentTransferResultResponse(t *testing.T) {
+	result := db.TransferTxResult{
+		Transfer:    db.Transfer{FromAccountID: 1, ToAccountID: 2, Amount: 10},
+		FromAccount: db.Account{ID: 1, Balance: 90, Currency: util.EUR},
+		ToAccount:   db.Account{ID: 2, Balance: 10, Currency: util.EUR},
+	}
+
+	var presented map[string]json.RawMessage
+	data, err := json.Marshal(present(reflect.ValueOf(newTransferResultResponse(result)), camelCase))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &presented))
+
+	// the fields of the result stay at the top, with the amounts in the currency of its accounts
+	require.Contains(t, string(presented["transfer"]), `"amountDisplay":"10.00 EUR"`)
+	require.JSONEq(t, `{
+		"account": {"href": "/api/v1/accounts/1"},
+		"entries": {"href": "/api/v1/accounts/1/entries"},
+		"transfers": {"href": "/api/v1/transfers?account_id=1"},
+		"statement": {"href": "/api/v1/accounts/1/export{?format,from,to}", "templated": true}
+	}`, string(presented["_links"]))
+}
@@ -0,0 +1,34 @@
+package api
+
+import (
+	"go-backend/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	acceptLanguageHeaderKey = "Accept-Language"
+	languageKey             = "language"
+)
+
+// The `localeMiddleware` function picks the language of the messages of the response, the supported one
+// the client prefers according to its Accept-Language header, and names it in the Content-Language
+// header of the response.
+func localeMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Writer.Header().Add("Vary", acceptLanguageHeaderKey)
+
+		language := i18n.MatchLanguage(ctx.GetHeader(acceptLanguageHeaderKey))
+		ctx.Set(languageKey, language)
+		ctx.Header("Content-Language", language)
+		ctx.Next()
+	}
+}
+
+// The `requestLanguage` function returns the language picked for the response by `localeMiddleware`.
+func requestLanguage(ctx *gin.Context) string {
+	if language, ok := ctx.Value(languageKey).(string); ok {
+		return language
+	}
+	return i18n.DefaultLanguage
+}
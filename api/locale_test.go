@@ -0,0 +1,117 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/i18n"
+	"go-backend/testutil/factory"
+	"go-backend/util"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalizedErrorsAPI(t *testing.T) {
+	user := factory.User()
+	account := factory.Account(factory.OwnedBy(user.Username))
+
+	testCases := []struct {
+		name           string
+		acceptLanguage string
+		method         string
+		url            string
+		body           gin.H
+		buildStub      func(store *mockdb.MockStore)
+		checkResponse  func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:           "ValidationInFrench",
+			acceptLanguage: "fr-CA,fr;q=0.9,en;q=0.8",
+			method:         http.MethodPost,
+			url:            "/api/v1/accounts",
+			body:           gin.H{"currency": "XYZ"},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Equal(t, i18n.French, recorder.Header().Get("Content-Language"))
+				require.Contains(t, recorder.Header().Values("Vary"), acceptLanguageHeaderKey)
+
+				body := requireErrorBody(t, recorder.Body, util.ErrorCodeValidationFailed)
+				require.Equal(t, "currency doit être l'une des valeurs USD, EUR, CAD", body.Message)
+				require.Len(t, body.FieldErrors, 1)
+				require.Equal(t, "CURRENCY", body.FieldErrors[0].Code)
+				require.Equal(t, body.Message, body.FieldErrors[0].Message)
+			},
+		},
+		{
+			name:           "ValidationInEnglish",
+			acceptLanguage: "de, en;q=0.5",
+			method:         http.MethodPost,
+			url:            "/api/v1/accounts",
+			body:           gin.H{"currency": "XYZ"},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Equal(t, i18n.English, recorder.Header().Get("Content-Language"))
+
+				body := requireErrorBody(t, recorder.Body, util.ErrorCodeValidationFailed)
+				require.Equal(t, "currency must be one of USD, EUR, CAD", body.Message)
+			},
+		},
+		{
+			name:           "ServiceErrorInFrench",
+			acceptLanguage: "fr",
+			method:         http.MethodGet,
+			url:            "/api/v1/accounts/42",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(int64(42))).Times(1).Return(db.Account{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+				body := requireErrorBody(t, recorder.Body, util.ErrorCodeNotFound)
+				require.Equal(t, "la ressource n'existe pas", body.Message)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			var data []byte
+			if tc.body != nil {
+				var err error
+				data, err = json.Marshal(tc.body)
+				require.NoError(t, err)
+			}
+
+			request, err := http.NewRequest(tc.method, tc.url, bytes.NewReader(data))
+			require.NoError(t, err)
+			request.Header.Set(acceptLanguageHeaderKey, tc.acceptLanguage)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, account.Owner, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
@@ -1,24 +1,40 @@
 package api
 
 import (
+	"bytes"
+	"encoding/json"
 	db "go-backend/db/sqlc"
 	"go-backend/util"
+	"go-backend/worker"
 	"os"
 	"testing"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	_ "github.com/lib/pq"
 	"github.com/stretchr/testify/require"
 )
 
 func newTestServer(t *testing.T, store db.Store) *Server {
+	return newTestServerWithDistributor(t, store, nil)
+}
+
+func newTestServerWithDistributor(t *testing.T, store db.Store, taskDistributor worker.TaskDistributor) *Server {
+	return newTestServerWithConfig(t, store, taskDistributor, func(config *util.Config) {})
+}
+
+// newTestServerWithConfig creates a test server whose config is changed by `configure` first.
+func newTestServerWithConfig(t *testing.T, store db.Store, taskDistributor worker.TaskDistributor, configure func(config *util.Config)) *Server {
 	config := util.Config{
-		TokenSymmetricKey:   util.RandomString(32),
-		AccessTokenDuration: time.Minute,
+		TokenSymmetricKey:          util.RandomString(32),
+		AccessTokenDuration:        time.Minute,
+		ExportURLDuration:          time.Minute,
+		ExportSigningKey:           util.RandomString(32),
+		ImpersonationTokenDuration: time.Minute,
+		UserDeletionCoolingOff:     time.Hour,
 	}
+	configure(&config)
 
-	server, err := NewServer(config, store)
+	server, err := NewServer(config, store, taskDistributor)
 	require.NoError(t, err)
 
 	return server
@@ -28,3 +44,14 @@ func TestMain(m *testing.M) {
 	gin.SetMode(gin.TestMode)
 	os.Exit(m.Run())
 }
+
+// requireErrorBody checks that the body is an error response with the code, and returns it.
+func requireErrorBody(t *testing.T, body *bytes.Buffer, code string) util.ErrorBody {
+	var errorBody util.ErrorBody
+	err := json.Unmarshal(body.Bytes(), &errorBody)
+	require.NoError(t, err)
+
+	require.Equal(t, code, errorBody.Code)
+	require.NotEmpty(t, errorBody.Message)
+	return errorBody
+}
@@ -0,0 +1,170 @@
+package api
+
+import (
+	"go-backend/service"
+	"go-backend/token"
+	"go-backend/util"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// The `addMandateRoutes` function adds the routes of the direct-debit mandates, which a user grants to a
+// merchant account to let its owners pull funds from one of their accounts, up to a maximum per pull.
+func (server *Server) addMandateRoutes(apiRouter *routeGroup) {
+	mandateRouter := apiRouter.Group("/mandates")
+	mandateRouter.POST("", server.createMandate)
+	mandateRouter.GET("", server.listMandates)
+	mandateRouter.GET("/:id", server.getMandate)
+	mandateRouter.POST("/:id/revoke", server.revokeMandate)
+	mandateRouter.POST("/:id/pull", server.signatureMiddleware(), server.pullMandate)
+}
+
+// The createMandateRequest type holds the mandate granted by the authenticated user.
+// @property {int64} FromAccountID - the account of the authenticated user the funds are pulled from.
+// @property {int64} MerchantAccountID - the account the funds are pulled to, in the same currency.
+// @property {int64} MaxAmount - the most a single pull takes.
+// @property {int64} CapAmount - the most pulled with the mandate in total, at least the maximum of a pull.
+type createMandateRequest struct {
+	FromAccountID     int64 `json:"from_account_id" binding:"required,min=1"`
+	MerchantAccountID int64 `json:"merchant_account_id" binding:"required,min=1"`
+	MaxAmount         int64 `json:"max_amount" binding:"required,gt=0"`
+	CapAmount         int64 `json:"cap_amount" binding:"required,gtefield=MaxAmount"`
+}
+
+// This is a function that authorizes the owners of a merchant account to pull funds from an account of
+// the authenticated user, up to a maximum per pull and a cap in total, until the mandate is revoked.
+func (server *Server) createMandate(ctx *gin.Context) {
+	var req createMandateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	mandate, err := server.service.CreateMandate(ctx, service.CreateMandateParams{
+		Payer:             authPayload.Username,
+		FromAccountID:     req.FromAccountID,
+		MerchantAccountID: req.MerchantAccountID,
+		MaxAmount:         req.MaxAmount,
+		CapAmount:         req.CapAmount,
+	})
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, mandate)
+}
+
+type listMandatesRequest struct {
+	pageRequest
+}
+
+// This is a function that lists the mandates granted by the authenticated user, oldest first.
+func (server *Server) listMandates(ctx *gin.Context) {
+	var req listMandatesRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	limit, offset, err := server.paginate(paginationMandates, req.pageRequest)
+	if err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	mandates, err := server.service.ListMandates(ctx, authPayload.Username, limit, offset)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, mandates)
+}
+
+type mandateURIRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// This is a function that gets a mandate granted by the authenticated user or to an account they share.
+func (server *Server) getMandate(ctx *gin.Context) {
+	var req mandateURIRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	mandate, err := server.service.GetMandate(ctx, authPayload.Username, req.ID)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, mandate)
+}
+
+// This is a function that revokes an active mandate, granted by the authenticated user or to an account
+// they own. No funds are pulled with it afterwards.
+func (server *Server) revokeMandate(ctx *gin.Context) {
+	var req mandateURIRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	mandate, err := server.service.RevokeMandate(ctx, authPayload.Username, req.ID)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, mandate)
+}
+
+// The pullMandateRequest type holds the funds a merchant pulls with a mandate.
+// @property {int64} Amount - the amount pulled, at most the maximum of the mandate.
+// @property {string} Memo - optional free text kept on the transfer.
+// @property {string} ExternalReference - optional reference of the payment, e.g. an invoice number.
+type pullMandateRequest struct {
+	Amount            int64  `json:"amount" binding:"required,gt=0"`
+	Memo              string `json:"memo" binding:"omitempty,max=140"`
+	ExternalReference string `json:"external_reference" binding:"omitempty,max=64"`
+}
+
+// This is a function that pulls funds with a mandate from the account of its payer to the merchant
+// account, owned by the authenticated user. The pull is a transfer checked like any transfer of the payer,
+// and can't exceed the maximum of the mandate, take the total pulled over its cap or be made once it is
+// revoked.
+func (server *Server) pullMandate(ctx *gin.Context) {
+	var uri mandateURIRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	var req pullMandateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	result, err := server.service.PullMandate(ctx, service.PullMandateParams{
+		Merchant:          authPayload.Username,
+		ID:                uri.ID,
+		Amount:            req.Amount,
+		Memo:              req.Memo,
+		ExternalReference: req.ExternalReference,
+	})
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, result)
+}
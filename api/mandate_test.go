@@ -0,0 +1,178 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/service"
+	"go-backend/testutil/factory"
+	"go-backend/util"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateMandateAPI(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	payer := factory.User()
+	fromAccount := factory.Account(factory.OwnedBy(payer.Username), factory.InCurrency(util.CAD))
+	merchantAccount := factory.Account(factory.InCurrency(util.CAD))
+	merchantAccount.ID = fromAccount.ID + 1
+	mandate := factory.Mandate(fromAccount, merchantAccount)
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(merchantAccount.ID)).Times(1).Return(merchantAccount, nil)
+	store.EXPECT().
+		CreateMandate(gomock.Any(), gomock.Eq(db.CreateMandateParams{
+			Payer:             payer.Username,
+			FromAccountID:     fromAccount.ID,
+			MerchantAccountID: merchantAccount.ID,
+			MaxAmount:         mandate.MaxAmount,
+			CapAmount:         mandate.CapAmount,
+		})).
+		Times(1).
+		Return(mandate, nil)
+
+	server := newTestServer(t, store)
+	for _, tc := range []struct {
+		body   gin.H
+		status int
+	}{
+		{gin.H{"from_account_id": fromAccount.ID, "merchant_account_id": merchantAccount.ID, "max_amount": 0}, http.StatusBadRequest},
+		{gin.H{"from_account_id": fromAccount.ID, "merchant_account_id": merchantAccount.ID, "max_amount": mandate.MaxAmount}, http.StatusBadRequest},
+		{gin.H{"from_account_id": fromAccount.ID, "merchant_account_id": merchantAccount.ID, "max_amount": mandate.MaxAmount, "cap_amount": mandate.MaxAmount - 1}, http.StatusBadRequest},
+		{gin.H{"from_account_id": fromAccount.ID, "merchant_account_id": merchantAccount.ID, "max_amount": mandate.MaxAmount, "cap_amount": mandate.CapAmount.Int64}, http.StatusOK},
+	} {
+		data, err := json.Marshal(tc.body)
+		require.NoError(t, err)
+
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodPost, "/api/v1/mandates", bytes.NewReader(data))
+		require.NoError(t, err)
+
+		addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, payer.Username, time.Minute)
+		server.router.ServeHTTP(recorder, request)
+		require.Equal(t, tc.status, recorder.Code)
+
+		if tc.status == http.StatusOK {
+			var got db.Mandate
+			err = json.Unmarshal(recorder.Body.Bytes(), &got)
+			require.NoError(t, err)
+			require.Equal(t, mandate, got)
+		}
+	}
+}
+
+func TestPullMandateAPI(t *testing.T) {
+	merchant := factory.User()
+	fromAccount := factory.Account(factory.InCurrency(util.USD))
+	merchantAccount := factory.Account(factory.OwnedBy(merchant.Username), factory.InCurrency(util.USD))
+	merchantAccount.ID = fromAccount.ID + 1
+	mandate := factory.Mandate(fromAccount, merchantAccount)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"amount": 250, "external_reference": "INV-7"},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetMandate(gomock.Any(), gomock.Eq(mandate.ID)).Times(1).Return(mandate, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(merchantAccount.ID)).Times(2).Return(merchantAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(2).Return(fromAccount, nil)
+				store.EXPECT().GetActiveBankParameter(gomock.Any(), gomock.Any()).Times(1).Return(db.BankParameter{}, db.ErrRecordNotFound)
+				store.EXPECT().
+					PullMandateTx(gomock.Any(), gomock.Eq(db.PullMandateTxParams{ID: mandate.ID, Amount: 250, ExternalReference: "INV-7", Currency: util.USD})).
+					Times(1).
+					Return(db.PullMandateTxResult{Mandate: mandate, Transfer: db.TransferTxResult{Transfer: db.Transfer{Amount: 250}}}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var result db.PullMandateTxResult
+				err := json.Unmarshal(recorder.Body.Bytes(), &result)
+				require.NoError(t, err)
+				require.Equal(t, int64(250), result.Transfer.Transfer.Amount)
+			},
+		},
+		{
+			name: "ExceedsMaximum",
+			body: gin.H{"amount": mandate.MaxAmount + 1},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetMandate(gomock.Any(), gomock.Eq(mandate.ID)).Times(1).Return(mandate, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(merchantAccount.ID)).Times(1).Return(merchantAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().PullMandateTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireErrorBody(t, recorder.Body, service.ReasonMandateLimitExceeded)
+			},
+		},
+		{
+			name: "Revoked",
+			body: gin.H{"amount": 250},
+			buildStub: func(store *mockdb.MockStore) {
+				revoked := mandate
+				revoked.Status = db.MandateRevoked
+				store.EXPECT().GetMandate(gomock.Any(), gomock.Eq(mandate.ID)).Times(1).Return(revoked, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(merchantAccount.ID)).Times(1).Return(merchantAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().PullMandateTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				requireErrorBody(t, recorder.Body, service.ReasonMandateRevoked)
+			},
+		},
+		{
+			name: "InvalidAmount",
+			body: gin.H{"amount": 0},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetMandate(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/api/v1/mandates/%d/pull", mandate.ID)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, merchant.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package api
+
+import (
+	db "go-backend/db/sqlc"
+	"go-backend/slo"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// routeObjectives are the service level objectives of the routes that need a stricter (or looser)
+// objective than `slo.DefaultObjective`. Routes are identified by their method and path template.
+var routeObjectives = map[string]slo.Objective{
+	"POST /api/v1/transfers": {
+		Availability:  0.9995,
+		Latency:       500 * time.Millisecond,
+		LatencyTarget: 0.99,
+	},
+	"POST /api/v1/users/login": {
+		Availability:  0.999,
+		Latency:       time.Second,
+		LatencyTarget: 0.95,
+	},
+	"POST /api/v1/jobs": {
+		Availability:  0.99,
+		Latency:       time.Second,
+		LatencyTarget: 0.9,
+	},
+}
+
+// The metrics type holds the prometheus registry of the server along with the collectors fed by the
+// `metricsMiddleware`. Each server has its own registry so that several servers can live in one process.
+type metrics struct {
+	registry        *prometheus.Registry
+	requests        *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+	slo             *slo.Tracker
+	volumes         *requestVolumes
+}
+
+func newMetrics() *metrics {
+	m := &metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "Number of HTTP requests by route and status code.",
+		}, []string{"method", "route", "status"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "Duration of HTTP requests by route.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+		slo:     slo.NewTracker(slo.DefaultObjective, routeObjectives),
+		volumes: newRequestVolumes(),
+	}
+
+	m.registry.MustRegister(
+		prometheus.NewGoCollector(),
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+		m.requests,
+		m.requestDuration,
+		m.slo,
+		db.TransferTxStepDuration,
+		db.DBFailovers,
+		db.DBActiveSource,
+		db.DBRetries,
+	)
+
+	return m
+}
+
+// The `metricsMiddleware` function records the status and duration of every request, labelled by the
+// route template rather than the raw path so that ids don't blow up the number of series.
+func (m *metrics) metricsMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+		ctx.Next()
+		duration := time.Since(start)
+
+		route := ctx.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		method := ctx.Request.Method
+		status := ctx.Writer.Status()
+
+		m.requests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+		m.requestDuration.WithLabelValues(method, route).Observe(duration.Seconds())
+		// the objectives are those of the route in v1, whichever version was requested
+		m.slo.Observe(method+" "+v1Path(route), status, duration)
+		m.volumes.observe(method+" "+route, status, start)
+	}
+}
+
+// The `metricsHandler` function serves the registry in the prometheus exposition format.
+func (m *metrics) metricsHandler() gin.HandlerFunc {
+	return gin.WrapH(promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{}))
+}
@@ -3,7 +3,10 @@ package api
 import (
 	"errors"
 	"fmt"
+	db "go-backend/db/sqlc"
+	"go-backend/service"
 	"go-backend/token"
+	"go-backend/tracking"
 	"go-backend/util"
 	"net/http"
 	"strings"
@@ -15,39 +18,190 @@ const (
 	authorizationHeaderKey  = "authorization"
 	authorizationTypeBearer = "bearer"
 	authorizationPayloadKey = "authorization_payload"
+	leaderAddressHeaderKey  = "X-Leader-Address"
 )
 
+// readOnlyPostRoutes are the routes taking POST requests that only read, which standbys serve, by their
+// v1 path.
+var readOnlyPostRoutes = map[string]bool{
+	"/api/v1/accounts/batch_get": true,
+	"/api/v1/graphql":            true,
+}
+
+// errStandby is the error of the writes sent to a standby instance.
+var errStandby = errors.New("this instance is a standby and only serves reads, send writes to the leader")
+
 func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		authorizationHeader := ctx.GetHeader(authorizationHeaderKey)
 		if len(authorizationHeader) == 0 {
 			err := errors.New("authorization header is not provided")
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, util.ErrorResponse(err))
+			abortWithJSON(ctx, http.StatusUnauthorized, util.ErrorResponse(http.StatusUnauthorized, err))
 			return
 		}
 
 		fields := strings.Fields(authorizationHeader)
 		if len(fields) < 2 {
 			err := errors.New("invalid authorization format")
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, util.ErrorResponse(err))
+			abortWithJSON(ctx, http.StatusUnauthorized, util.ErrorResponse(http.StatusUnauthorized, err))
 			return
 		}
 
 		authorizationType := strings.ToLower(fields[0])
 		if authorizationType != authorizationTypeBearer {
 			err := fmt.Errorf("unsupported authorization type %s", authorizationType)
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, util.ErrorResponse(err))
+			abortWithJSON(ctx, http.StatusUnauthorized, util.ErrorResponse(http.StatusUnauthorized, err))
 			return
 		}
 
 		accessToken := fields[1]
 		payload, err := tokenMaker.VerifyToken(accessToken)
 		if err != nil {
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, util.ErrorResponse(err))
+			abortWithJSON(ctx, http.StatusUnauthorized, util.ErrorResponse(http.StatusUnauthorized, err))
 			return
 		}
 
+		// the payload is also carried by the context of the request, for the service and the resolvers
+		// only given a context.Context to read the claims of the token
 		ctx.Set(authorizationPayloadKey, payload)
+		ctx.Request = ctx.Request.WithContext(token.NewContext(ctx.Request.Context(), payload))
+		ctx.Next()
+	}
+}
+
+// The `sessionActivityMiddleware` function rejects the requests whose access token belongs to a session
+// idle for longer than the SESSION_IDLE_TIMEOUT config with 401 and the SESSION_IDLE code, and records
+// the others as a use of the session, so that the session doesn't expire while its user is active. It
+// must be registered after `authMiddleware`.
+func sessionActivityMiddleware(service *service.Service) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+		if err := service.CheckSessionIdle(ctx, authPayload.SessionID); err != nil {
+			ctx.Error(err)
+			status, body := serviceErrorResponse(err)
+			abortWithJSON(ctx, status, body)
+			return
+		}
+
+		service.TouchSession(authPayload.SessionID)
+		ctx.Next()
+	}
+}
+
+// The `organizationMiddleware` function scopes the queries of the request to the organization of its
+// user, the store then not finding the users and accounts of the other organizations. The tokens issued
+// before the organizations are of the default one, and those of the admins of the platform aren't scoped.
+// It must be registered after `authMiddleware`.
+func organizationMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+		if authPayload.Role != util.AdminRole {
+			ctx.Request = ctx.Request.WithContext(db.WithOrganization(ctx.Request.Context(), authOrganization(authPayload)))
+		}
+		ctx.Next()
+	}
+}
+
+// The `standbyMiddleware` function rejects the writes, i.e. the requests other than GET, HEAD and OPTIONS
+// apart from the read-only POST routes, with 503 while the instance is a standby. The address of the leader is sent in the X-Leader-Address
+// header when it is known, for the clients to retry there.
+func (server *Server) standbyMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		switch ctx.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			ctx.Next()
+			return
+		case http.MethodPost:
+			if readOnlyPostRoutes[v1Path(ctx.FullPath())] {
+				ctx.Next()
+				return
+			}
+		}
+
+		if server.leadership == nil || server.leadership.IsLeader() {
+			ctx.Next()
+			return
+		}
+
+		if address := server.leadership.LeaderAddress(); address != "" {
+			ctx.Header(leaderAddressHeaderKey, address)
+		}
+		abortWithJSON(ctx, http.StatusServiceUnavailable, util.ErrorResponse(http.StatusServiceUnavailable, errStandby))
+	}
+}
+
+// The `trackingMiddleware` function reports the panics of the handlers, before the recovery of gin
+// responds with 500, and the responses with a 5xx status to the error tracking, along with the request
+// and its authenticated user. The error attached to the context by `writeError` is reported when there is
+// one. The 503 responses without one, such as the writes rejected by a standby, are expected and left out.
+func trackingMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				tracking.CaptureRequestPanic(trackedRequest(ctx), recovered)
+				panic(recovered)
+			}
+		}()
+
 		ctx.Next()
+
+		status := ctx.Writer.Status()
+		if status < http.StatusInternalServerError {
+			return
+		}
+
+		err := ctx.Errors.Last()
+		if err == nil {
+			if status == http.StatusServiceUnavailable {
+				return
+			}
+			tracking.CaptureRequestError(trackedRequest(ctx), fmt.Errorf("%s %s responded with %d", ctx.Request.Method, ctx.FullPath(), status))
+			return
+		}
+		tracking.CaptureRequestError(trackedRequest(ctx), err.Err)
+	}
+}
+
+// trackedRequest describes the request of `ctx` to the error tracking.
+func trackedRequest(ctx *gin.Context) tracking.Request {
+	req := tracking.Request{
+		Request: ctx.Request,
+		Route:   ctx.Request.Method + " " + ctx.FullPath(),
+	}
+	if ctx.Writer.Written() {
+		req.Status = ctx.Writer.Status()
+	}
+	if payload, ok := ctx.Get(authorizationPayloadKey); ok {
+		req.Username = payload.(*token.Payload).Username
+	}
+	return req
+}
+
+// The `roleMiddleware` function only lets through authenticated users holding one of the given roles.
+// The role is read from the database so that revoking it takes effect without waiting for the access
+// token to expire. It must be registered after `authMiddleware`.
+func roleMiddleware(store db.Store, roles ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+		user, err := store.GetUser(ctx, authPayload.Username)
+		if err != nil {
+			if errors.Is(err, db.ErrRecordNotFound) {
+				abortWithJSON(ctx, http.StatusUnauthorized, util.ErrorResponse(http.StatusUnauthorized, err))
+				return
+			}
+			abortWithJSON(ctx, http.StatusInternalServerError, util.ErrorResponse(http.StatusInternalServerError, err))
+			return
+		}
+
+		for _, role := range roles {
+			if user.Role == role {
+				ctx.Next()
+				return
+			}
+		}
+
+		err = fmt.Errorf("role %s is not allowed to access this resource", user.Role)
+		abortWithJSON(ctx, http.StatusForbidden, util.ErrorResponse(http.StatusForbidden, err))
 	}
 }
@@ -1,19 +1,30 @@
 package api
 
 import (
+	"errors"
 	"fmt"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/service"
+	"go-backend/testutil/factory"
+	"go-backend/testutil/trackingtest"
 	"go-backend/token"
+	"go-backend/util"
+	"go-backend/worker"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
+	"github.com/getsentry/sentry-go"
 	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 )
 
 func addAuthorization(t *testing.T, request *http.Request, tokenMaker token.Maker, authorizationType string, username string, duration time.Duration) {
-	token, payload, err := tokenMaker.CreateToken(username, duration)
+	token, payload, err := tokenMaker.CreateToken(token.Claims{Username: username}, duration)
 	require.NoError(t, err)
 	require.NotEmpty(t, payload)
 
@@ -94,3 +105,259 @@ func TestAuthMiddleware(t *testing.T) {
 
 	}
 }
+
+func TestAuthMiddlewareThreadsClaims(t *testing.T) {
+	server := newTestServer(t, nil)
+	claims := token.Claims{
+		Username:  util.RandomOwner(),
+		Role:      util.DepositorRole,
+		SessionID: uuid.New(),
+		Scopes:    []string{token.ScopeAccountsRead},
+		Metadata:  map[string]string{token.MetadataAuthMethod: "google"},
+	}
+	accessToken, _, err := server.tokenMaker.CreateToken(claims, time.Minute)
+	require.NoError(t, err)
+
+	var handlerPayload, contextPayload *token.Payload
+	authPath := "/auth"
+	server.router.GET(authPath, authMiddleware(server.tokenMaker), func(ctx *gin.Context) {
+		handlerPayload = ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+		contextPayload, _ = token.FromContext(ctx)
+		ctx.JSON(http.StatusOK, gin.H{})
+	})
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, authPath, nil)
+	require.NoError(t, err)
+	request.Header.Set(authorizationHeaderKey, authorizationTypeBearer+" "+accessToken)
+	server.router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, claims, handlerPayload.Claims())
+	require.Same(t, handlerPayload, contextPayload)
+}
+
+func TestSessionActivityMiddleware(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	server := newTestServerWithConfig(t, store, nil, func(config *util.Config) {
+		config.SessionIdleTimeout = 10 * time.Minute
+	})
+
+	idle := db.Session{ID: uuid.New(), LastUsedAt: time.Now().Add(-time.Hour)}
+	active := db.Session{ID: uuid.New(), LastUsedAt: time.Now().Add(-time.Minute)}
+	store.EXPECT().GetSession(gomock.Any(), gomock.Eq(idle.ID)).Times(1).Return(idle, nil)
+	store.EXPECT().GetSession(gomock.Any(), gomock.Eq(active.ID)).Times(1).Return(active, nil)
+
+	path := "/activity"
+	server.router.GET(path, authMiddleware(server.tokenMaker), sessionActivityMiddleware(server.service), func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, gin.H{})
+	})
+
+	// the access token of an idle session is rejected before it expires
+	for _, tc := range []struct {
+		session db.Session
+		status  int
+	}{
+		{idle, http.StatusUnauthorized},
+		{active, http.StatusOK},
+		// the use just recorded is read rather than the session
+		{active, http.StatusOK},
+	} {
+		accessToken, _, err := server.tokenMaker.CreateToken(token.Claims{Username: util.RandomOwner(), SessionID: tc.session.ID}, time.Minute)
+		require.NoError(t, err)
+
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, path, nil)
+		require.NoError(t, err)
+		request.Header.Set(authorizationHeaderKey, authorizationTypeBearer+" "+accessToken)
+		server.router.ServeHTTP(recorder, request)
+
+		require.Equal(t, tc.status, recorder.Code)
+		if tc.status == http.StatusUnauthorized {
+			requireErrorBody(t, recorder.Body, service.ReasonSessionIdle)
+		}
+	}
+}
+
+type fakeLeadership struct {
+	leader        bool
+	leaderAddress string
+}
+
+func (leadership fakeLeadership) IsLeader() bool {
+	return leadership.leader
+}
+
+func (leadership fakeLeadership) LeaderAddress() string {
+	return leadership.leaderAddress
+}
+
+func TestStandbyMiddleware(t *testing.T) {
+	testCases := []struct {
+		name          string
+		leadership    worker.Leadership
+		method        string
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "NoLeaderElection",
+			method: http.MethodPost,
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:       "Leader",
+			leadership: fakeLeadership{leader: true},
+			method:     http.MethodPost,
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:       "StandbyRead",
+			leadership: fakeLeadership{leaderAddress: "https://bank.eu.example.com"},
+			method:     http.MethodGet,
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:       "StandbyWrite",
+			leadership: fakeLeadership{leaderAddress: "https://bank.eu.example.com"},
+			method:     http.MethodPost,
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+				require.Equal(t, "https://bank.eu.example.com", recorder.Header().Get(leaderAddressHeaderKey))
+				requireErrorBody(t, recorder.Body, util.ErrorCodeUnavailable)
+			},
+		},
+		{
+			name:       "NoLeader",
+			leadership: fakeLeadership{},
+			method:     http.MethodDelete,
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+				require.Empty(t, recorder.Header().Get(leaderAddressHeaderKey))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			server := newTestServer(t, nil)
+			server.SetLeadership(tc.leadership)
+			server.router.Handle(tc.method, "/standby", func(ctx *gin.Context) {
+				ctx.JSON(http.StatusOK, gin.H{})
+			})
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(tc.method, "/standby", nil)
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestTrackingMiddleware(t *testing.T) {
+	user := factory.User()
+	account := factory.Account(factory.OwnedBy(user.Username))
+
+	testCases := []struct {
+		name       string
+		leadership worker.Leadership
+		buildStub  func(store *mockdb.MockStore)
+		method     string
+		url        string
+		status     int
+		checkEvent func(events []*sentry.Event)
+	}{
+		{
+			name: "ServerError",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(db.Account{}, errors.New("connection reset"))
+			},
+			method: http.MethodGet,
+			url:    fmt.Sprintf("/api/v1/accounts/%d", account.ID),
+			status: http.StatusInternalServerError,
+			checkEvent: func(events []*sentry.Event) {
+				require.Len(t, events, 1)
+				require.Equal(t, "connection reset", events[0].Exception[len(events[0].Exception)-1].Value)
+				require.Equal(t, user.Username, events[0].User.Username)
+				require.Equal(t, "GET /api/v1/accounts/:id", events[0].Tags["route"])
+				require.Equal(t, "500", events[0].Tags["status"])
+			},
+		},
+		{
+			name:   "Panic",
+			method: http.MethodGet,
+			url:    "/panic",
+			status: http.StatusInternalServerError,
+			checkEvent: func(events []*sentry.Event) {
+				require.Len(t, events, 1)
+				require.Equal(t, "boom", events[0].Message)
+				require.Equal(t, "GET /panic", events[0].Tags["route"])
+			},
+		},
+		{
+			name: "ClientError",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(db.Account{}, db.ErrRecordNotFound)
+			},
+			method: http.MethodGet,
+			url:    fmt.Sprintf("/api/v1/accounts/%d", account.ID),
+			status: http.StatusNotFound,
+			checkEvent: func(events []*sentry.Event) {
+				require.Empty(t, events)
+			},
+		},
+		{
+			name:       "StandbyWrite",
+			leadership: fakeLeadership{},
+			method:     http.MethodDelete,
+			url:        fmt.Sprintf("/api/v1/accounts/%d", account.ID),
+			status:     http.StatusServiceUnavailable,
+			checkEvent: func(events []*sentry.Event) {
+				require.Empty(t, events)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			transport := trackingtest.Record(t)
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			if tc.buildStub != nil {
+				tc.buildStub(store)
+			}
+
+			server := newTestServer(t, store)
+			server.SetLeadership(tc.leadership)
+			server.router.GET("/panic", func(ctx *gin.Context) {
+				panic("boom")
+			})
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(tc.method, tc.url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, tc.status, recorder.Code)
+			tc.checkEvent(transport.Events())
+		})
+	}
+}
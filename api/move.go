@@ -0,0 +1,168 @@
+package api
+
+import (
+	db "go-backend/db/sqlc"
+	"go-backend/service"
+	"go-backend/token"
+	"go-backend/util"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type moveMoneyURI struct {
+	FromAccountID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type moveMoneyRequest struct {
+	ToAccountID int64  `json:"to_account_id" binding:"required,min=1"`
+	Amount      int64  `json:"amount" binding:"required,gt=0"`
+	Memo        string `json:"memo" binding:"omitempty,max=140"`
+}
+
+type moveMoneyResponse struct {
+	FromAccount            db.Account             `json:"from_account"`
+	ToAccount              db.Account             `json:"to_account"`
+	Amount                 int64                  `json:"amount"`
+	AmountDisplay          string                 `json:"amount_display"`
+	ConvertedAmount        int64                  `json:"converted_amount"`
+	ConvertedAmountDisplay string                 `json:"converted_amount_display"`
+	ExchangeRatePPM        int64                  `json:"exchange_rate_ppm"`
+	Transfers              []moveTransferResponse `json:"transfers"`
+}
+
+// The moveTransferResponse type is a transfer of a move with its currency, the one of the from account
+// for the transfer leaving it and the one of the to account for the transfer reaching it when the amount
+// was converted.
+type moveTransferResponse struct {
+	db.Transfer
+	Currency string `json:"currency"`
+}
+
+func newMoveMoneyResponse(result service.MoveMoneyResult) moveMoneyResponse {
+	res := moveMoneyResponse{
+		FromAccount:            result.FromAccount,
+		ToAccount:              result.ToAccount,
+		Amount:                 result.Amount,
+		AmountDisplay:          util.FormatAmount(result.Amount, result.FromAccount.Currency),
+		ConvertedAmount:        result.ConvertedAmount,
+		ConvertedAmountDisplay: util.FormatAmount(result.ConvertedAmount, result.ToAccount.Currency),
+		ExchangeRatePPM:        result.ExchangeRate,
+		Transfers:              make([]moveTransferResponse, 0, len(result.Transfers)),
+	}
+	for _, transfer := range result.Transfers {
+		currency := result.ToAccount.Currency
+		if transfer.FromAccountID == result.FromAccount.ID {
+			currency = result.FromAccount.Currency
+		}
+		res.Transfers = append(res.Transfers, moveTransferResponse{Transfer: transfer, Currency: currency})
+	}
+	return res
+}
+
+// This is a function that moves money from an account of the authenticated user to another of their
+// accounts. The amount is in the currency of the from account and is converted at the exchange rate in
+// effect, in millionths, when the to account holds another currency. Unlike transfers, moves aren't
+// subject to the transfer limit, the screening or the approval of large transfers.
+func (server *Server) moveMoney(ctx *gin.Context) {
+	var uri moveMoneyURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	var req moveMoneyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	result, err := server.service.MoveMoney(ctx, service.MoveMoneyParams{
+		Owner:         authPayload.Username,
+		FromAccountID: uri.FromAccountID,
+		ToAccountID:   req.ToAccountID,
+		Amount:        req.Amount,
+		Memo:          req.Memo,
+	})
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, newMoveMoneyResponse(result))
+}
+
+type convertAccountURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type convertAccountRequest struct {
+	Currency string `json:"currency" binding:"required,currency"`
+	Memo     string `json:"memo" binding:"omitempty,max=140"`
+}
+
+type convertAccountResponse struct {
+	Account                db.Account             `json:"account"`
+	PreviousCurrency       string                 `json:"previous_currency"`
+	Amount                 int64                  `json:"amount"`
+	AmountDisplay          string                 `json:"amount_display"`
+	ConvertedAmount        int64                  `json:"converted_amount"`
+	ConvertedAmountDisplay string                 `json:"converted_amount_display"`
+	ExchangeRatePPM        int64                  `json:"exchange_rate_ppm"`
+	Transfers              []moveTransferResponse `json:"transfers"`
+}
+
+func newConvertAccountResponse(result service.ConvertAccountResult) convertAccountResponse {
+	res := convertAccountResponse{
+		Account:                result.Account,
+		PreviousCurrency:       result.PreviousCurrency,
+		Amount:                 result.Amount,
+		AmountDisplay:          util.FormatAmount(result.Amount, result.PreviousCurrency),
+		ConvertedAmount:        result.ConvertedAmount,
+		ConvertedAmountDisplay: util.FormatAmount(result.ConvertedAmount, result.Account.Currency),
+		ExchangeRatePPM:        result.ExchangeRate,
+		Transfers:              make([]moveTransferResponse, 0, len(result.Transfers)),
+	}
+	for _, transfer := range result.Transfers {
+		// the balance leaves the account in the previous currency and comes back in the new one
+		currency := result.Account.Currency
+		if transfer.FromAccountID == result.Account.ID {
+			currency = result.PreviousCurrency
+		}
+		res.Transfers = append(res.Transfers, moveTransferResponse{Transfer: transfer, Currency: currency})
+	}
+	return res
+}
+
+// This is a function that changes the currency of an account of the authenticated user, converting its
+// balance at the exchange rate in effect, in millionths, in one transaction. The conversion is posted as
+// two transfers through the fx_spread system accounts, so that the entries of the account keep netting
+// to its balance.
+func (server *Server) convertAccount(ctx *gin.Context) {
+	var uri convertAccountURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	var req convertAccountRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	result, err := server.service.ConvertAccount(ctx, service.ConvertAccountParams{
+		Owner:     authPayload.Username,
+		AccountID: uri.ID,
+		Currency:  req.Currency,
+		Memo:      req.Memo,
+	})
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, newConvertAccountResponse(result))
+}
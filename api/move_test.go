@@ -0,0 +1,294 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/service"
+	"go-backend/testutil/factory"
+	"go-backend/token"
+	"go-backend/util"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMoveMoneyAPI(t *testing.T) {
+	user := factory.User()
+	fromAccount := factory.Account(factory.OwnedBy(user.Username), factory.InCurrency(util.CAD))
+	toAccount := factory.Account(factory.OwnedBy(user.Username), factory.InCurrency(util.USD))
+	toAccount.ID = fromAccount.ID + 1
+	otherAccount := factory.Account(factory.OwnedBy(util.RandomOwner()), factory.InCurrency(util.CAD))
+	otherAccount.ID = fromAccount.ID + 2
+
+	testCases := []struct {
+		name          string
+		accountID     int64
+		body          gin.H
+		setupAuth     func(request *http.Request, tokenMaker token.Maker)
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "OK",
+			accountID: fromAccount.ID,
+			body:      gin.H{"to_account_id": toAccount.ID, "amount": 1_000, "memo": "trip"},
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().
+					GetActiveBankParameter(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.BankParameter{Name: db.FXRateParameter(util.CAD, util.USD), Value: 730_000}, nil)
+				store.EXPECT().
+					ExchangeTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ExchangeTxResult{
+						Sold:   db.TransferTxResult{Transfer: db.Transfer{FromAccountID: fromAccount.ID, Amount: 1_000}, FromAccount: fromAccount},
+						Bought: db.TransferTxResult{Transfer: db.Transfer{ToAccountID: toAccount.ID, Amount: 730}, ToAccount: toAccount},
+					}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response moveMoneyResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &response)
+				require.NoError(t, err)
+				require.Equal(t, fromAccount.ID, response.FromAccount.ID)
+				require.Equal(t, toAccount.ID, response.ToAccount.ID)
+				require.Equal(t, int64(1_000), response.Amount)
+				require.Equal(t, int64(730), response.ConvertedAmount)
+				require.Equal(t, int64(730_000), response.ExchangeRatePPM)
+
+				// each amount is displayed in its currency
+				require.Equal(t, "1,000.00 CAD", response.AmountDisplay)
+				require.Equal(t, "730.00 USD", response.ConvertedAmountDisplay)
+				require.Len(t, response.Transfers, 2)
+				require.Equal(t, util.CAD, response.Transfers[0].Currency)
+				require.Equal(t, util.USD, response.Transfers[1].Currency)
+			},
+		},
+		{
+			name:      "NoExchangeRate",
+			accountID: fromAccount.ID,
+			body:      gin.H{"to_account_id": toAccount.ID, "amount": 1_000},
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().GetActiveBankParameter(gomock.Any(), gomock.Any()).Times(1).Return(db.BankParameter{}, db.ErrRecordNotFound)
+				store.EXPECT().ExchangeTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				requireErrorBody(t, recorder.Body, service.ReasonNoExchangeRate)
+			},
+		},
+		{
+			name:      "OtherOwner",
+			accountID: fromAccount.ID,
+			body:      gin.H{"to_account_id": otherAccount.ID, "amount": 1_000},
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(otherAccount.ID)).Times(1).Return(otherAccount, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(db.AccountMember{}, db.ErrRecordNotFound)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				requireErrorBody(t, recorder.Body, util.ErrorCodePermissionDenied)
+			},
+		},
+		{
+			name:      "InvalidAmount",
+			accountID: fromAccount.ID,
+			body:      gin.H{"to_account_id": toAccount.ID, "amount": -1},
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:      "NoAuthorization",
+			accountID: fromAccount.ID,
+			body:      gin.H{"to_account_id": toAccount.ID, "amount": 1_000},
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/api/v1/accounts/%d/move", tc.accountID)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			tc.setupAuth(request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestConvertAccountAPI(t *testing.T) {
+	user := factory.User()
+	account := factory.Account(factory.OwnedBy(user.Username), factory.InCurrency(util.CAD))
+	converted := account
+	converted.Currency = util.USD
+	converted.Balance = 730
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		setupAuth     func(request *http.Request, tokenMaker token.Maker)
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"currency": util.USD},
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().
+					GetActiveBankParameter(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.BankParameter{Name: db.FXRateParameter(util.CAD, util.USD), Value: 730_000}, nil)
+				store.EXPECT().
+					ConvertAccountTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ConvertAccountTxResult{
+						Account:         converted,
+						Amount:          1_000,
+						ConvertedAmount: 730,
+						Transfers: []db.Transfer{
+							{FromAccountID: account.ID, Amount: 1_000},
+							{ToAccountID: account.ID, Amount: 730},
+						},
+					}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response convertAccountResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &response)
+				require.NoError(t, err)
+				require.Equal(t, util.USD, response.Account.Currency)
+				require.Equal(t, util.CAD, response.PreviousCurrency)
+				require.Equal(t, "1,000.00 CAD", response.AmountDisplay)
+				require.Equal(t, "730.00 USD", response.ConvertedAmountDisplay)
+				require.Equal(t, int64(730_000), response.ExchangeRatePPM)
+				require.Len(t, response.Transfers, 2)
+				require.Equal(t, util.CAD, response.Transfers[0].Currency)
+				require.Equal(t, util.USD, response.Transfers[1].Currency)
+			},
+		},
+		{
+			name: "NoExchangeRate",
+			body: gin.H{"currency": util.USD},
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetActiveBankParameter(gomock.Any(), gomock.Any()).Times(1).Return(db.BankParameter{}, db.ErrRecordNotFound)
+				store.EXPECT().ConvertAccountTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				requireErrorBody(t, recorder.Body, service.ReasonNoExchangeRate)
+			},
+		},
+		{
+			name: "UnsupportedCurrency",
+			body: gin.H{"currency": "XYZ"},
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:      "NoAuthorization",
+			body:      gin.H{"currency": util.USD},
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/api/v1/accounts/%d/convert", account.ID)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			tc.setupAuth(request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package api
+
+import (
+	"go-backend/service"
+	"go-backend/token"
+	"go-backend/util"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// The `addNotificationRoutes` function adds the routes listing the notifications of the authenticated
+// user, marking them as read, and setting the channels they are notified through.
+func (server *Server) addNotificationRoutes(apiRouter *routeGroup) {
+	notificationRouter := apiRouter.Group("/notifications")
+	notificationRouter.GET("", server.listNotifications)
+	notificationRouter.POST("/read", server.markAllNotificationsRead)
+	notificationRouter.POST("/:id/read", server.markNotificationRead)
+	notificationRouter.GET("/preferences", server.getNotificationPreferences)
+	notificationRouter.PUT("/preferences", server.updateNotificationPreferences)
+}
+
+type listNotificationsRequest struct {
+	pageRequest
+}
+
+// This is a function that lists the notifications of the authenticated user, newest first. Both sides of
+// a transfer are notified: the sender with a transfer.sent notification and the recipient with a
+// transfer.received one, each describing the transfer from their own account. The payer of a payment
+// request is notified with a payment_request.created notification, a login from a new device with a
+// session.new_device notification and a transfer leaving an account below the low balance threshold of
+// the user with an account.low_balance notification. Only the notifications projected while the in-app
+// channel was enabled are listed.
+func (server *Server) listNotifications(ctx *gin.Context) {
+	var req listNotificationsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	limit, offset, err := server.paginate(paginationNotifications, req.pageRequest)
+	if err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	notifications, err := server.service.ListNotifications(ctx, authPayload.Username, limit, offset)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, notifications)
+}
+
+type notificationURIRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// This is a function that marks a notification of the authenticated user as read.
+func (server *Server) markNotificationRead(ctx *gin.Context) {
+	var req notificationURIRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	notification, err := server.service.MarkNotificationRead(ctx, authPayload.Username, req.ID)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, notification)
+}
+
+// The markAllNotificationsReadResponse type holds how many notifications were marked as read.
+type markAllNotificationsReadResponse struct {
+	Marked int64 `json:"marked"`
+}
+
+// This is a function that marks every unread notification of the authenticated user as read.
+func (server *Server) markAllNotificationsRead(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	marked, err := server.service.MarkAllNotificationsRead(ctx, authPayload.Username)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, markAllNotificationsReadResponse{Marked: marked})
+}
+
+// This is a function that gets the channels the authenticated user is notified through.
+func (server *Server) getNotificationPreferences(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	preferences, err := server.service.GetNotificationPreferences(ctx, authPayload.Username)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, preferences)
+}
+
+// The updateNotificationPreferencesRequest type holds the channels a user is notified through, all of
+// them being replaced.
+// @property {string} WebhookURL - the URL the notifications are posted to, empty to not post them.
+// @property {int64} LowBalanceThreshold - a transfer leaving an account below it notifies the user, 0 to
+// never notify.
+type updateNotificationPreferencesRequest struct {
+	InApp               *bool  `json:"in_app" binding:"required"`
+	Email               *bool  `json:"email" binding:"required"`
+	WebhookURL          string `json:"webhook_url" binding:"omitempty,url"`
+	LowBalanceThreshold int64  `json:"low_balance_threshold" binding:"min=0"`
+}
+
+// This is a function that sets the channels the authenticated user is notified through: in the app, by
+// email and with a webhook. The events already projected keep the channels they were sent through.
+func (server *Server) updateNotificationPreferences(ctx *gin.Context) {
+	var req updateNotificationPreferencesRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	preferences, err := server.service.UpdateNotificationPreferences(ctx, service.UpdateNotificationPreferencesParams{
+		Username:            authPayload.Username,
+		InApp:               *req.InApp,
+		Email:               *req.Email,
+		WebhookURL:          req.WebhookURL,
+		LowBalanceThreshold: req.LowBalanceThreshold,
+	})
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, preferences)
+}
@@ -0,0 +1,436 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/testutil/factory"
+	"go-backend/token"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListNotificationsAPI(t *testing.T) {
+	user := factory.User()
+
+	notifications := []db.Notification{
+		{ID: 2, Username: user.Username, EventID: 8, Type: db.EventTransferReceived, Payload: json.RawMessage(`{"amount":10}`)},
+		{ID: 1, Username: user.Username, EventID: 5, Type: db.EventTransferSent, Payload: json.RawMessage(`{"amount":-10}`)},
+	}
+
+	testCases := []struct {
+		name          string
+		query         string
+		setupAuth     func(request *http.Request, tokenMaker token.Maker)
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "DefaultPagination",
+			query: "",
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				arg := db.ListNotificationsParams{Username: user.Username, Limit: 20, Offset: 0}
+				store.EXPECT().ListNotifications(gomock.Any(), gomock.Eq(arg)).Times(1).Return(notifications, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got []db.Notification
+				err := json.Unmarshal(recorder.Body.Bytes(), &got)
+				require.NoError(t, err)
+				require.Equal(t, notifications, got)
+			},
+		},
+		{
+			name:  "OK",
+			query: "page_id=2&page_size=5",
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				arg := db.ListNotificationsParams{Username: user.Username, Limit: 5, Offset: 5}
+				store.EXPECT().ListNotifications(gomock.Any(), gomock.Eq(arg)).Times(1).Return(notifications, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:      "NoAuthorization",
+			query:     "",
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().ListNotifications(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:  "InternalError",
+			query: "",
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().ListNotifications(gomock.Any(), gomock.Any()).Times(1).Return([]db.Notification{}, sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name:  "InvalidPageSize",
+			query: "page_size=101",
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().ListNotifications(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/api/v1/notifications?%s", tc.query)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestMarkNotificationReadAPI(t *testing.T) {
+	user := factory.User()
+	notification := db.Notification{
+		ID:       3,
+		Username: user.Username,
+		EventID:  8,
+		Type:     db.EventTransferReceived,
+		Payload:  json.RawMessage(`{"amount":10}`),
+		ReadAt:   pgtype.Timestamptz{Time: time.Now().UTC().Truncate(time.Second), Valid: true},
+	}
+
+	testCases := []struct {
+		name          string
+		id            string
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			id:   "3",
+			buildStub: func(store *mockdb.MockStore) {
+				arg := db.MarkNotificationReadParams{ID: notification.ID, Username: user.Username}
+				store.EXPECT().MarkNotificationRead(gomock.Any(), gomock.Eq(arg)).Times(1).Return(notification, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got db.Notification
+				err := json.Unmarshal(recorder.Body.Bytes(), &got)
+				require.NoError(t, err)
+				require.Equal(t, notification.ID, got.ID)
+				require.True(t, got.ReadAt.Valid)
+				require.WithinDuration(t, notification.ReadAt.Time, got.ReadAt.Time, time.Second)
+			},
+		},
+		{
+			// the notifications of other users aren't found either
+			name: "NotFound",
+			id:   "3",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().MarkNotificationRead(gomock.Any(), gomock.Any()).Times(1).Return(db.Notification{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidID",
+			id:   "0",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().MarkNotificationRead(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/api/v1/notifications/%s/read", tc.id)
+			request, err := http.NewRequest(http.MethodPost, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestMarkAllNotificationsReadAPI(t *testing.T) {
+	user := factory.User()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().MarkAllNotificationsRead(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(int64(4), nil)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodPost, "/api/v1/notifications/read", nil)
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.JSONEq(t, `{"marked":4}`, recorder.Body.String())
+}
+
+func TestGetNotificationPreferencesAPI(t *testing.T) {
+	user := factory.User()
+
+	testCases := []struct {
+		name          string
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetNotificationPreferences(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(db.NotificationPreference{
+					Username:            user.Username,
+					Email:               true,
+					WebhookURL:          "https://example.com/hook",
+					LowBalanceThreshold: 50,
+				}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got db.NotificationPreference
+				err := json.Unmarshal(recorder.Body.Bytes(), &got)
+				require.NoError(t, err)
+				require.False(t, got.InApp)
+				require.True(t, got.Email)
+				require.Equal(t, "https://example.com/hook", got.WebhookURL)
+				require.Equal(t, int64(50), got.LowBalanceThreshold)
+			},
+		},
+		{
+			name: "Default",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetNotificationPreferences(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(db.NotificationPreference{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got db.NotificationPreference
+				err := json.Unmarshal(recorder.Body.Bytes(), &got)
+				require.NoError(t, err)
+				require.Equal(t, user.Username, got.Username)
+				require.True(t, got.InApp)
+				require.False(t, got.Email)
+				require.Empty(t, got.WebhookURL)
+			},
+		},
+		{
+			name: "InternalError",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetNotificationPreferences(gomock.Any(), gomock.Any()).Times(1).Return(db.NotificationPreference{}, sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/api/v1/notifications/preferences", nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestUpdateNotificationPreferencesAPI(t *testing.T) {
+	user := factory.User()
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"in_app":                false,
+				"email":                 true,
+				"webhook_url":           "https://example.com/hook",
+				"low_balance_threshold": 100,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				arg := db.UpsertNotificationPreferencesParams{
+					Username:            user.Username,
+					InApp:               false,
+					Email:               true,
+					WebhookURL:          "https://example.com/hook",
+					LowBalanceThreshold: 100,
+				}
+				store.EXPECT().UpsertNotificationPreferences(gomock.Any(), gomock.Eq(arg)).Times(1).Return(db.NotificationPreference{
+					Username:            arg.Username,
+					InApp:               arg.InApp,
+					Email:               arg.Email,
+					WebhookURL:          arg.WebhookURL,
+					LowBalanceThreshold: arg.LowBalanceThreshold,
+				}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "MissingChannel",
+			body: gin.H{
+				"in_app": true,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().UpsertNotificationPreferences(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NegativeThreshold",
+			body: gin.H{
+				"in_app":                true,
+				"email":                 false,
+				"low_balance_threshold": -1,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().UpsertNotificationPreferences(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "WebhookNotHTTP",
+			body: gin.H{
+				"in_app":      true,
+				"email":       false,
+				"webhook_url": "ftp://example.com/hook",
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().UpsertNotificationPreferences(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "WebhookNotPublic",
+			body: gin.H{
+				"in_app":      true,
+				"email":       false,
+				"webhook_url": "http://169.254.169.254/latest/meta-data",
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().UpsertNotificationPreferences(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPut, "/api/v1/notifications/preferences", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
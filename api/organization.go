@@ -0,0 +1,166 @@
+package api
+
+import (
+	db "go-backend/db/sqlc"
+	"go-backend/service"
+	"go-backend/token"
+	"go-backend/util"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// The `addOrganizationRoutes` function adds the routes of the organization of the authenticated user,
+// whose users only its admins manage.
+func (server *Server) addOrganizationRoutes(apiRouter *routeGroup) {
+	organizationRouter := apiRouter.Group("/organizations/me")
+	organizationRouter.GET("", server.getOrganization)
+
+	adminRouter := organizationRouter.With(requireRole(util.OrgAdminRole, util.AdminRole))
+	adminRouter.GET("/users", server.listOrganizationUsers)
+	adminRouter.PUT("/users/:username/role", server.setOrganizationRole)
+	server.addApprovalRoutes(organizationRouter)
+}
+
+// The `authOrganization` function returns the organization of the user of a token, the default one for
+// the tokens issued before the organizations.
+func authOrganization(payload *token.Payload) int64 {
+	if payload.OrgID == 0 {
+		return db.DefaultOrganizationID
+	}
+	return payload.OrgID
+}
+
+type organizationResponse struct {
+	ID                int64     `json:"id"`
+	Slug              string    `json:"slug"`
+	Name              string    `json:"name"`
+	ApprovalThreshold int64     `json:"approval_threshold"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+func newOrganizationResponse(organization db.Organization) organizationResponse {
+	return organizationResponse{
+		ID:                organization.ID,
+		Slug:              organization.Slug,
+		Name:              organization.Name,
+		ApprovalThreshold: organization.ApprovalThreshold,
+		CreatedAt:         organization.CreatedAt,
+	}
+}
+
+type createOrganizationRequest struct {
+	Slug string `json:"slug" binding:"required"`
+	Name string `json:"name" binding:"required"`
+}
+
+// This is a function that creates an organization, which the users then sign up to with its slug to have
+// their users and accounts apart from those of the other organizations.
+func (server *Server) createOrganization(ctx *gin.Context) {
+	var req createOrganizationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	organization, err := server.service.CreateOrganization(ctx, service.CreateOrganizationParams{
+		Slug: req.Slug,
+		Name: req.Name,
+	})
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, newOrganizationResponse(organization))
+}
+
+// This is a function that returns the organization of the authenticated user.
+func (server *Server) getOrganization(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	organization, err := server.service.GetOrganization(ctx, authOrganization(authPayload))
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, newOrganizationResponse(organization))
+}
+
+type listOrganizationUsersRequest struct {
+	pageRequest
+}
+
+// This is a function that lists the users of the organization of the authenticated user, an admin of it,
+// by username.
+func (server *Server) listOrganizationUsers(ctx *gin.Context) {
+	var req listOrganizationUsersRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	limit, offset, err := server.paginate(paginationAdmin, req.pageRequest)
+	if err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	users, err := server.service.ListOrganizationUsers(ctx, authOrganization(authPayload), limit, offset)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	res := make([]userResponse, 0, len(users))
+	for _, user := range users {
+		res = append(res, newUserResponse(user))
+	}
+	renderJSON(ctx, http.StatusOK, res)
+}
+
+type organizationUserURI struct {
+	Username string `uri:"username" binding:"required,alphanum"`
+}
+
+type setOrganizationRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=depositor org_admin"`
+}
+
+type organizationRoleResponse struct {
+	Username string `json:"username"`
+	Role     string `json:"role"`
+}
+
+// This is a function that makes a user of the organization of the authenticated user, an admin of it, an
+// admin of the organization too or a depositor again. The users of the other organizations aren't found.
+func (server *Server) setOrganizationRole(ctx *gin.Context) {
+	var uri organizationUserURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+	var req setOrganizationRoleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	user, err := server.service.SetOrganizationRole(ctx, service.SetOrganizationRoleParams{
+		OrgID:    authOrganization(authPayload),
+		Username: uri.Username,
+		Role:     req.Role,
+	})
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, organizationRoleResponse{
+		Username: user.Username,
+		Role:     user.Role,
+	})
+}
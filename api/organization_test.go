@@ -0,0 +1,191 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/testutil/factory"
+	"go-backend/token"
+	"go-backend/util"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func addOrganizationAuthorization(t *testing.T, request *http.Request, tokenMaker token.Maker, user db.User) {
+	accessToken, _, err := tokenMaker.CreateToken(token.Claims{Username: user.Username, Role: user.Role, OrgID: user.OrgID}, time.Minute)
+	require.NoError(t, err)
+	request.Header.Set(authorizationHeaderKey, fmt.Sprintf("%s %s", authorizationTypeBearer, accessToken))
+}
+
+func TestOrganizationScopeAPI(t *testing.T) {
+	user := factory.User()
+	admin := factory.User(factory.WithRole(util.AdminRole))
+	account := factory.Account(factory.OwnedBy(admin.Username), func(account *db.Account) {
+		account.OrgID = 2
+	})
+
+	testCases := []struct {
+		name   string
+		user   db.User
+		status int
+	}{
+		{
+			// the accounts of another organization aren't found, even by their id
+			name:   "OtherOrganization",
+			user:   user,
+			status: http.StatusNotFound,
+		},
+		{
+			// the admins of the platform aren't scoped to an organization
+			name:   "PlatformAdmin",
+			user:   admin,
+			status: http.StatusOK,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).AnyTimes().Return(account, nil)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/accounts/%d", account.ID), nil)
+			require.NoError(t, err)
+
+			addOrganizationAuthorization(t, request, server.tokenMaker, tc.user)
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, tc.status, recorder.Code)
+		})
+	}
+}
+
+func TestGetOrganizationAPI(t *testing.T) {
+	user := factory.User(factory.InOrganization(2))
+	organization := db.Organization{ID: 2, Slug: "acme", Name: "Acme"}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetOrganization(gomock.Any(), gomock.Eq(organization.ID)).Times(1).Return(organization, nil)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/api/v1/organizations/me", nil)
+	require.NoError(t, err)
+
+	addOrganizationAuthorization(t, request, server.tokenMaker, user)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var got organizationResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+	require.Equal(t, organization.Slug, got.Slug)
+}
+
+func TestSetOrganizationRoleAPI(t *testing.T) {
+	orgAdmin := factory.User(factory.InOrganization(2), factory.WithRole(util.OrgAdminRole))
+	depositor := factory.User(factory.InOrganization(2))
+	outsider := factory.User()
+
+	testCases := []struct {
+		name       string
+		caller     db.User
+		username   string
+		role       string
+		buildStubs func(store *mockdb.MockStore)
+		status     int
+	}{
+		{
+			name:     "OK",
+			caller:   orgAdmin,
+			username: depositor.Username,
+			role:     util.OrgAdminRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(orgAdmin.Username)).Times(1).Return(orgAdmin, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(depositor.Username)).Times(1).Return(depositor, nil)
+				promoted := depositor
+				promoted.Role = util.OrgAdminRole
+				store.EXPECT().
+					UpdateUserRole(gomock.Any(), gomock.Eq(db.UpdateUserRoleParams{Username: depositor.Username, Role: util.OrgAdminRole})).
+					Times(1).
+					Return(promoted, nil)
+			},
+			status: http.StatusOK,
+		},
+		{
+			name:     "NotOrgAdmin",
+			caller:   depositor,
+			username: depositor.Username,
+			role:     util.OrgAdminRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(depositor.Username)).Times(1).Return(depositor, nil)
+				store.EXPECT().UpdateUserRole(gomock.Any(), gomock.Any()).Times(0)
+			},
+			status: http.StatusForbidden,
+		},
+		{
+			name:     "OtherOrganization",
+			caller:   orgAdmin,
+			username: outsider.Username,
+			role:     util.OrgAdminRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(orgAdmin.Username)).Times(1).Return(orgAdmin, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(outsider.Username)).Times(1).Return(outsider, nil)
+				store.EXPECT().UpdateUserRole(gomock.Any(), gomock.Any()).Times(0)
+			},
+			status: http.StatusNotFound,
+		},
+		{
+			name:     "InvalidRole",
+			caller:   orgAdmin,
+			username: depositor.Username,
+			role:     util.AdminRole,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(orgAdmin.Username)).Times(1).Return(orgAdmin, nil)
+				store.EXPECT().UpdateUserRole(gomock.Any(), gomock.Any()).Times(0)
+			},
+			status: http.StatusBadRequest,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			body, err := json.Marshal(map[string]string{"role": tc.role})
+			require.NoError(t, err)
+			url := fmt.Sprintf("/api/v1/organizations/me/users/%s/role", tc.username)
+			request, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(body))
+			require.NoError(t, err)
+
+			addOrganizationAuthorization(t, request, server.tokenMaker, tc.caller)
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, tc.status, recorder.Code)
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"go-backend/util"
+	"math"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Endpoints with their own pagination policy. The bounds can be overridden per endpoint with the
+// PAGINATION_POLICIES config.
+const (
+	paginationAccounts          = "accounts"
+	paginationInvitations       = "account_invitations"
+	paginationEntries           = "entries"
+	paginationTransfers         = "transfers"
+	paginationNotifications     = "notifications"
+	paginationBeneficiaries     = "beneficiaries"
+	paginationPaymentRequests   = "payment_requests"
+	paginationMandates          = "mandates"
+	paginationStandingOrders    = "standing_orders"
+	paginationExternalTransfers = "external_transfers"
+	paginationCards             = "cards"
+	paginationHolds             = "holds"
+	paginationAdmin             = "admin"
+)
+
+var defaultPaginationPolicies = map[string]util.PaginationPolicy{
+	paginationAccounts:          {MinPageSize: 1, DefaultPageSize: 20, MaxPageSize: 100},
+	paginationInvitations:       {MinPageSize: 1, DefaultPageSize: 20, MaxPageSize: 100},
+	paginationEntries:           {MinPageSize: 1, DefaultPageSize: 20, MaxPageSize: 100},
+	paginationTransfers:         {MinPageSize: 1, DefaultPageSize: 20, MaxPageSize: 100},
+	paginationNotifications:     {MinPageSize: 1, DefaultPageSize: 20, MaxPageSize: 100},
+	paginationBeneficiaries:     {MinPageSize: 1, DefaultPageSize: 20, MaxPageSize: 100},
+	paginationPaymentRequests:   {MinPageSize: 1, DefaultPageSize: 20, MaxPageSize: 100},
+	paginationMandates:          {MinPageSize: 1, DefaultPageSize: 20, MaxPageSize: 100},
+	paginationStandingOrders:    {MinPageSize: 1, DefaultPageSize: 20, MaxPageSize: 100},
+	paginationExternalTransfers: {MinPageSize: 1, DefaultPageSize: 20, MaxPageSize: 100},
+	paginationCards:             {MinPageSize: 1, DefaultPageSize: 20, MaxPageSize: 100},
+	paginationHolds:             {MinPageSize: 1, DefaultPageSize: 20, MaxPageSize: 100},
+	paginationAdmin:             {MinPageSize: 1, DefaultPageSize: 20, MaxPageSize: 100},
+}
+
+// The `newPaginationPolicies` function merges the policies of the config over the default ones.
+func newPaginationPolicies(config util.Config) (map[string]util.PaginationPolicy, error) {
+	overrides, err := util.ParsePaginationPolicies(config.PaginationPolicies)
+	if err != nil {
+		return nil, err
+	}
+
+	policies := make(map[string]util.PaginationPolicy, len(defaultPaginationPolicies))
+	for endpoint, policy := range defaultPaginationPolicies {
+		policies[endpoint] = policy
+	}
+	for endpoint, policy := range overrides {
+		if _, ok := policies[endpoint]; !ok {
+			return nil, fmt.Errorf("unknown pagination endpoint %s", endpoint)
+		}
+		policies[endpoint] = policy
+	}
+
+	return policies, nil
+}
+
+// nextCursorHeaderKey is the header holding the cursor of the next keyset page, sent when there may be
+// one.
+const nextCursorHeaderKey = "X-Next-Cursor"
+
+// The pageRequest type holds the optional pagination query parameters of a listing endpoint. It is
+// embedded in the request of the endpoint, and resolved against its policy with `paginate`.
+type pageRequest struct {
+	PageID   *int32 `form:"page_id" binding:"omitempty,min=1"`
+	PageSize *int32 `form:"page_size" binding:"omitempty,min=1"`
+}
+
+// The keysetPageRequest type holds the pagination query parameters of the listing endpoints which can
+// also be paginated by keyset, with a cursor instead of a page id, see `keysetCursor`. Keyset pages stay
+// fast however deep they are, unlike the pages at an offset.
+type keysetPageRequest struct {
+	pageRequest
+	Cursor *string `form:"cursor"`
+}
+
+// The `paginate` function returns the limit and offset of the requested page, using the first page and
+// the default page size of the endpoint when they are omitted. A page size outside of the bounds of the
+// endpoint is an error rather than being silently clamped, as is a page too deep for its offset to fit
+// in the int32 the queries take.
+func (server *Server) paginate(endpoint string, req pageRequest) (limit int32, offset int32, err error) {
+	policy := server.pagination[endpoint]
+
+	pageID := int32(1)
+	if req.PageID != nil {
+		pageID = *req.PageID
+	}
+
+	pageSize := policy.DefaultPageSize
+	if req.PageSize != nil {
+		pageSize = *req.PageSize
+		if pageSize < policy.MinPageSize || pageSize > policy.MaxPageSize {
+			err = fmt.Errorf("page_size must be between %d and %d", policy.MinPageSize, policy.MaxPageSize)
+			return
+		}
+	}
+
+	pageOffset := (int64(pageID) - 1) * int64(pageSize)
+	if pageOffset > math.MaxInt32 {
+		err = errors.New("page_id is too large")
+		return
+	}
+
+	return pageSize, int32(pageOffset), nil
+}
+
+// The `keysetCursor` function returns whether the request asks for a keyset page, newest first, by
+// sending a cursor, and the id the cursor holds. The cursor of the first page is empty, those of the next
+// ones are sent in the X-Next-Cursor header of the previous page.
+func (req keysetPageRequest) keysetCursor() (cursor int64, ok bool, err error) {
+	if req.Cursor == nil {
+		return 0, false, nil
+	}
+	if req.PageID != nil {
+		return 0, true, errors.New("page_id can't be set along with cursor")
+	}
+	if *req.Cursor == "" {
+		return 0, true, nil
+	}
+
+	cursor, err = util.DecodeCursor(*req.Cursor)
+	return cursor, true, err
+}
+
+// The `setNextCursor` function sends the cursor of the page after a keyset page whose last item has the
+// id, unless the page wasn't full and so was the last one.
+func setNextCursor(ctx *gin.Context, size int, limit int32, lastID int64) {
+	if size == int(limit) {
+		ctx.Header(nextCursorHeaderKey, util.EncodeCursor(lastID))
+	}
+}
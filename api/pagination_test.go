@@ -0,0 +1,55 @@
+package api
+
+import (
+	"go-backend/util"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPaginationPolicies(t *testing.T) {
+	policies, err := newPaginationPolicies(util.Config{PaginationPolicies: "accounts=1:25:50"})
+	require.NoError(t, err)
+	require.Equal(t, util.PaginationPolicy{MinPageSize: 1, DefaultPageSize: 25, MaxPageSize: 50}, policies[paginationAccounts])
+	require.Equal(t, defaultPaginationPolicies[paginationAdmin], policies[paginationAdmin])
+
+	_, err = newPaginationPolicies(util.Config{PaginationPolicies: "unknown=1:25:50"})
+	require.Error(t, err)
+}
+
+func TestPaginate(t *testing.T) {
+	server := &Server{pagination: map[string]util.PaginationPolicy{
+		paginationAccounts: {MinPageSize: 5, DefaultPageSize: 10, MaxPageSize: 50},
+	}}
+
+	limit, offset, err := server.paginate(paginationAccounts, pageRequest{})
+	require.NoError(t, err)
+	require.Equal(t, int32(10), limit)
+	require.Equal(t, int32(0), offset)
+
+	pageID, pageSize := int32(3), int32(20)
+	limit, offset, err = server.paginate(paginationAccounts, pageRequest{PageID: &pageID, PageSize: &pageSize})
+	require.NoError(t, err)
+	require.Equal(t, int32(20), limit)
+	require.Equal(t, int32(40), offset)
+
+	pageSize = 51
+	_, _, err = server.paginate(paginationAccounts, pageRequest{PageSize: &pageSize})
+	require.Error(t, err)
+
+	pageSize = 4
+	_, _, err = server.paginate(paginationAccounts, pageRequest{PageSize: &pageSize})
+	require.Error(t, err)
+
+	// the offset of the page would overflow an int32
+	pageID, pageSize = math.MaxInt32, 50
+	_, _, err = server.paginate(paginationAccounts, pageRequest{PageID: &pageID, PageSize: &pageSize})
+	require.EqualError(t, err, "page_id is too large")
+
+	pageID = math.MaxInt32/50 + 1
+	limit, offset, err = server.paginate(paginationAccounts, pageRequest{PageID: &pageID, PageSize: &pageSize})
+	require.NoError(t, err)
+	require.Equal(t, int32(50), limit)
+	require.Equal(t, int32(math.MaxInt32/50*50), offset)
+}
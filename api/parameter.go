@@ -0,0 +1,98 @@
+package api
+
+import (
+	"go-backend/service"
+	"go-backend/token"
+	"go-backend/util"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type publishParameterRequest struct {
+	Name        string    `json:"name" binding:"required"`
+	Value       *int64    `json:"value" binding:"required,min=0"`
+	EffectiveAt time.Time `json:"effective_at"`
+}
+
+// This is a function that publishes a new version of a parameter of the bank, e.g. the transfer limit,
+// which applies from its effective date, immediately when it is omitted. The previous versions are kept
+// and transactions resolve the version in effect when they run.
+func (server *Server) publishParameter(ctx *gin.Context) {
+	var req publishParameterRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	parameter, err := server.service.PublishParameter(ctx, service.PublishParameterParams{
+		Username:    authPayload.Username,
+		Name:        req.Name,
+		Value:       *req.Value,
+		EffectiveAt: req.EffectiveAt,
+	})
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, parameter)
+}
+
+type listActiveParametersRequest struct {
+	At time.Time `form:"at" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+// This is a function that lists the version of every parameter in effect now, or at the time given by
+// the `at` query parameter.
+func (server *Server) listActiveParameters(ctx *gin.Context) {
+	var req listActiveParametersRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	at := req.At
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	parameters, err := server.service.ListActiveParameters(ctx, at)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, parameters)
+}
+
+type listParameterVersionsRequest struct {
+	pageRequest
+	Name string `form:"name"`
+}
+
+// This is a function that lists the history of the parameters, optionally of a single one, the latest
+// version first.
+func (server *Server) listParameterVersions(ctx *gin.Context) {
+	var req listParameterVersionsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	limit, offset, err := server.paginate(paginationAdmin, req.pageRequest)
+	if err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	parameters, err := server.service.ListParameterVersions(ctx, req.Name, limit, offset)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, parameters)
+}
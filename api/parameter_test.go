@@ -0,0 +1,158 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/testutil/factory"
+	"go-backend/util"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublishParameterAPI(t *testing.T) {
+	admin := factory.User(factory.WithRole(util.AdminRole))
+	depositor := factory.User()
+
+	effectiveAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	parameter := db.BankParameter{
+		Name:        db.ParameterTransferLimit,
+		Version:     3,
+		Value:       10000,
+		EffectiveAt: effectiveAt,
+		PublishedBy: admin.Username,
+	}
+
+	testCases := []struct {
+		name          string
+		user          db.User
+		body          gin.H
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			user: admin,
+			body: gin.H{"name": parameter.Name, "value": parameter.Value, "effective_at": effectiveAt},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).Times(1).Return(admin, nil)
+				arg := db.CreateBankParameterParams{
+					Name:        parameter.Name,
+					Value:       parameter.Value,
+					EffectiveAt: effectiveAt,
+					PublishedBy: admin.Username,
+				}
+				store.EXPECT().CreateBankParameter(gomock.Any(), gomock.Eq(arg)).Times(1).Return(parameter, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got db.BankParameter
+				err := json.Unmarshal(recorder.Body.Bytes(), &got)
+				require.NoError(t, err)
+				require.Equal(t, parameter.Version, got.Version)
+				require.Equal(t, parameter.Value, got.Value)
+			},
+		},
+		{
+			name: "Forbidden",
+			user: depositor,
+			body: gin.H{"name": parameter.Name, "value": parameter.Value},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(depositor.Username)).Times(1).Return(depositor, nil)
+				store.EXPECT().CreateBankParameter(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "MissingValue",
+			user: admin,
+			body: gin.H{"name": parameter.Name},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).Times(1).Return(admin, nil)
+				store.EXPECT().CreateBankParameter(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "UnknownParameter",
+			user: admin,
+			body: gin.H{"name": "overdraft", "value": 10},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).Times(1).Return(admin, nil)
+				store.EXPECT().CreateBankParameter(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/api/v1/admin/parameters", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestListActiveParametersAPI(t *testing.T) {
+	admin := factory.User(factory.WithRole(util.AdminRole))
+
+	at := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	parameters := []db.BankParameter{
+		{Name: db.ParameterTransferFee, Version: 1, Value: 25},
+		{Name: db.ParameterTransferLimit, Version: 4, Value: 10000},
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).Times(1).Return(admin, nil)
+	store.EXPECT().ListActiveBankParameters(gomock.Any(), gomock.Eq(at)).Times(1).Return(parameters, nil)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/api/v1/admin/parameters/active?at=2024-01-01T00:00:00Z", nil)
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, admin.Username, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var got []db.BankParameter
+	err = json.Unmarshal(recorder.Body.Bytes(), &got)
+	require.NoError(t, err)
+	require.Equal(t, parameters, got)
+}
@@ -0,0 +1,35 @@
+package api
+
+import (
+	"bytes"
+	"go-backend/iso20022"
+	"go-backend/token"
+	"go-backend/util"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// This is a function that makes the credit transfers of an ISO 20022 pain.001 file sent as the body of
+// the request, the way corporate customers send their payrolls to their bank, as a batch transfer from
+// accounts of the authenticated user. The accounts are identified by their id in the file. The response
+// is a pain.002 status report giving the status of each transaction: settled, pending when held for
+// review or awaiting approval, or rejected with its reason code. It is OK even when transactions, or the
+// whole file, were rejected, only a file which isn't a pain.001 document getting a 400 Bad Request
+// response.
+func (server *Server) importPaymentInitiation(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	report, err := server.service.ImportPaymentInitiation(ctx, authPayload.Username, ctx.Request.Body)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	var buffer bytes.Buffer
+	if err := iso20022.WriteStatusReport(&buffer, report); err != nil {
+		renderJSON(ctx, http.StatusInternalServerError, util.ErrorResponse(http.StatusInternalServerError, err))
+		return
+	}
+
+	ctx.Data(http.StatusOK, "application/xml", buffer.Bytes())
+}
@@ -0,0 +1,125 @@
+package api
+
+import (
+	"fmt"
+	"go-backend/clock"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/testutil/factory"
+	"go-backend/util"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportPaymentInitiationAPI(t *testing.T) {
+	user := factory.User()
+	fromAccount := factory.Account(factory.OwnedBy(user.Username), factory.InCurrency(util.USD))
+	toAccount := factory.Account(factory.InCurrency(util.USD))
+	toAccount.ID = fromAccount.ID + 1
+
+	file := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<Document xmlns="urn:iso:std:iso:20022:tech:xsd:pain.001.001.03">
+  <CstmrCdtTrfInitn>
+    <GrpHdr><MsgId>PAYROLL-1</MsgId><NbOfTxs>1</NbOfTxs><CtrlSum>100.00</CtrlSum></GrpHdr>
+    <PmtInf>
+      <PmtInfId>SALARIES</PmtInfId>
+      <DbtrAcct><Id><Othr><Id>%d</Id></Othr></Id></DbtrAcct>
+      <CdtTrfTxInf>
+        <PmtId><EndToEndId>E2E-1</EndToEndId></PmtId>
+        <Amt><InstdAmt Ccy="USD">100.00</InstdAmt></Amt>
+        <CdtrAcct><Id><Othr><Id>%d</Id></Othr></Id></CdtrAcct>
+      </CdtTrfTxInf>
+    </PmtInf>
+  </CstmrCdtTrfInitn>
+</Document>`, fromAccount.ID, toAccount.ID)
+
+	now := time.Now()
+
+	testCases := []struct {
+		name          string
+		body          string
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: file,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetActiveBankParameter(gomock.Any(), gomock.Any()).Times(1).Return(db.BankParameter{}, db.ErrRecordNotFound)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().
+					BatchTransferTx(gomock.Any(), gomock.Eq(db.BatchTransferTxParams{
+						Transfers: []db.TransferTxParams{
+							{FromAccountID: fromAccount.ID, ToAccountID: toAccount.ID, Amount: 100, ExternalReference: "E2E-1", Currency: fromAccount.Currency, ChargeFee: true, Now: now},
+						},
+					})).
+					Times(1).
+					Return([]db.BatchTransferTxItem{{Result: db.TransferTxResult{Transfer: db.Transfer{ID: 7}}}}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "application/xml", recorder.Header().Get("Content-Type"))
+
+				body := recorder.Body.String()
+				require.Contains(t, body, "<OrgnlMsgId>PAYROLL-1</OrgnlMsgId>")
+				require.Contains(t, body, "<GrpSts>ACSC</GrpSts>")
+				require.Contains(t, body, "<AcctSvcrRef>transfer:7</AcctSvcrRef>")
+			},
+		},
+		{
+			name: "ControlSumMismatch",
+			body: strings.Replace(file, "<CtrlSum>100.00</CtrlSum>", "<CtrlSum>10.00</CtrlSum>", 1),
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().BatchTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				body := recorder.Body.String()
+				require.Contains(t, body, "<GrpSts>RJCT</GrpSts>")
+				require.Contains(t, body, "<Cd>AM10</Cd>")
+			},
+		},
+		{
+			name: "InvalidFile",
+			body: "date,amount\n2024-01-01,10\n",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().BatchTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			server.service.SetClock(clock.NewFake(now))
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodPost, "/api/v1/transfers/pain001", strings.NewReader(tc.body))
+			require.NoError(t, err)
+			request.Header.Set("Content-Type", "application/xml")
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package api
+
+import (
+	"go-backend/service"
+	"go-backend/token"
+	"go-backend/util"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// The `addPaymentLinkRoutes` function adds the routes of the payment links, which a merchant shares for
+// any authenticated user to pay once with a transfer.
+func (server *Server) addPaymentLinkRoutes(apiRouter *routeGroup) {
+	paymentLinkRouter := apiRouter.Group("/payment_links")
+	paymentLinkRouter.POST("", server.createPaymentLink)
+	paymentLinkRouter.GET("/:code", server.getPaymentLink)
+	paymentLinkRouter.POST("/:code/pay", server.signatureMiddleware(), server.payPaymentLink)
+	paymentLinkRouter.POST("/:code/cancel", server.cancelPaymentLink)
+}
+
+// The createPaymentLinkRequest type holds the payment a link collects.
+// @property {int64} ToAccountID - the account of the authenticated user the money is sent to.
+// @property {int64} Amount - the amount to pay, in the currency of the account.
+// @property {string} Memo - optional free text shown to the payer.
+// @property {time.Time} ExpiresAt - optional expiry of the link, the PAYMENT_LINK_TTL config from now
+// when unset.
+type createPaymentLinkRequest struct {
+	ToAccountID int64     `json:"to_account_id" binding:"required,min=1"`
+	Amount      int64     `json:"amount" binding:"required,gt=0"`
+	Currency    string    `json:"currency" binding:"required,currency"`
+	Memo        string    `json:"memo" binding:"omitempty,max=140"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// This is a function that creates a payment link collecting money for an account of the authenticated
+// user. Any authenticated user the code of the link is shared with can pay it, once, until it expires.
+func (server *Server) createPaymentLink(ctx *gin.Context) {
+	var req createPaymentLinkRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	link, err := server.service.CreatePaymentLink(ctx, service.CreatePaymentLinkParams{
+		Merchant:    authPayload.Username,
+		ToAccountID: req.ToAccountID,
+		Amount:      req.Amount,
+		Currency:    req.Currency,
+		Memo:        req.Memo,
+		ExpiresAt:   req.ExpiresAt,
+	})
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, link)
+}
+
+type paymentLinkURIRequest struct {
+	Code string `uri:"code" binding:"required,max=64"`
+}
+
+// This is a function that looks up a payment link by its code, for the user it was shared with to see
+// what they are asked to pay before paying it.
+func (server *Server) getPaymentLink(ctx *gin.Context) {
+	var uri paymentLinkURIRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	link, err := server.service.GetPaymentLink(ctx, uri.Code)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, link)
+}
+
+// The payPaymentLinkRequest type holds the account the payer pays a link from.
+type payPaymentLinkRequest struct {
+	FromAccountID int64 `json:"from_account_id" binding:"required,min=1"`
+}
+
+// This is a function that pays an active payment link with a transfer from an account of the
+// authenticated user in the currency of the link. The link can't be paid once paid, cancelled or
+// expired.
+func (server *Server) payPaymentLink(ctx *gin.Context) {
+	var uri paymentLinkURIRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	var req payPaymentLinkRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	result, err := server.service.PayPaymentLink(ctx, service.PayPaymentLinkParams{
+		Payer:         authPayload.Username,
+		Code:          uri.Code,
+		FromAccountID: req.FromAccountID,
+	})
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, result)
+}
+
+// This is a function that cancels an active payment link of the authenticated user, which can then no
+// longer be paid.
+func (server *Server) cancelPaymentLink(ctx *gin.Context) {
+	var uri paymentLinkURIRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	link, err := server.service.CancelPaymentLink(ctx, authPayload.Username, uri.Code)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, link)
+}
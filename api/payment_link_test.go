@@ -0,0 +1,205 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/testutil/factory"
+	"go-backend/util"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func randomPaymentLink(toAccount db.Account) db.PaymentLink {
+	return db.PaymentLink{
+		ID:          util.RandomInt(1, 1000),
+		Code:        util.RandomString(22),
+		Merchant:    toAccount.Owner,
+		ToAccountID: toAccount.ID,
+		Amount:      10,
+		Currency:    toAccount.Currency,
+		Status:      db.PaymentLinkActive,
+		ExpiresAt:   time.Now().Add(time.Hour),
+		CreatedAt:   time.Now(),
+	}
+}
+
+func TestPayPaymentLinkAPI(t *testing.T) {
+	payer := factory.User()
+	fromAccount := factory.Account(factory.OwnedBy(payer.Username), factory.InCurrency(util.USD))
+	toAccount := factory.Account(factory.InCurrency(util.USD))
+	link := randomPaymentLink(toAccount)
+
+	expired := link
+	expired.ExpiresAt = time.Now().Add(-time.Minute)
+
+	paid := link
+	paid.Status = db.PaymentLinkPaid
+	result := db.PayPaymentLinkTxResult{
+		PaymentLink: paid,
+		Transfer: db.TransferTxResult{
+			Transfer: factory.Transfer(factory.Between(fromAccount, toAccount)),
+		},
+	}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"from_account_id": fromAccount.ID},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPaymentLinkByCode(gomock.Any(), gomock.Eq(link.Code)).Times(1).Return(link, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(2).Return(toAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetActiveBankParameter(gomock.Any(), gomock.Any()).Times(1).Return(db.BankParameter{}, db.ErrRecordNotFound)
+
+				arg := db.PayPaymentLinkTxParams{
+					ID:            link.ID,
+					Payer:         payer.Username,
+					FromAccountID: fromAccount.ID,
+					Currency:      link.Currency,
+				}
+				store.EXPECT().PayPaymentLinkTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(result, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got db.PayPaymentLinkTxResult
+				err := json.Unmarshal(recorder.Body.Bytes(), &got)
+				require.NoError(t, err)
+				require.Equal(t, db.PaymentLinkPaid, got.PaymentLink.Status)
+				require.Equal(t, result.Transfer.Transfer.ID, got.Transfer.Transfer.ID)
+			},
+		},
+		{
+			name: "Expired",
+			body: gin.H{"from_account_id": fromAccount.ID},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPaymentLinkByCode(gomock.Any(), gomock.Eq(link.Code)).Times(1).Return(expired, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().PayPaymentLinkTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name: "MissingFromAccount",
+			body: gin.H{},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().PayPaymentLinkTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := "/api/v1/payment_links/" + link.Code + "/pay"
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, payer.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestCancelPaymentLinkAPI(t *testing.T) {
+	merchant := factory.User()
+	toAccount := factory.Account(factory.OwnedBy(merchant.Username))
+	link := randomPaymentLink(toAccount)
+
+	cancelled := link
+	cancelled.Status = db.PaymentLinkCancelled
+
+	testCases := []struct {
+		name      string
+		caller    string
+		buildStub func(store *mockdb.MockStore)
+		status    int
+	}{
+		{
+			name:   "OK",
+			caller: merchant.Username,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPaymentLinkByCode(gomock.Any(), gomock.Eq(link.Code)).Times(1).Return(link, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().CancelPaymentLink(gomock.Any(), gomock.Eq(link.ID)).Times(1).Return(cancelled, nil)
+			},
+			status: http.StatusOK,
+		},
+		{
+			// the link was paid meanwhile
+			name:   "Closed",
+			caller: merchant.Username,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPaymentLinkByCode(gomock.Any(), gomock.Eq(link.Code)).Times(1).Return(link, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().CancelPaymentLink(gomock.Any(), gomock.Eq(link.ID)).Times(1).Return(db.PaymentLink{}, db.ErrRecordNotFound)
+			},
+			status: http.StatusConflict,
+		},
+		{
+			name:   "NotMerchant",
+			caller: util.RandomOwner(),
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPaymentLinkByCode(gomock.Any(), gomock.Eq(link.Code)).Times(1).Return(link, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().CancelPaymentLink(gomock.Any(), gomock.Any()).Times(0)
+			},
+			status: http.StatusUnauthorized,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := "/api/v1/payment_links/" + link.Code + "/cancel"
+			request, err := http.NewRequest(http.MethodPost, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.caller, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, tc.status, recorder.Code)
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package api
+
+import (
+	"go-backend/service"
+	"go-backend/token"
+	"go-backend/util"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// The `addPaymentRequestRoutes` function adds the routes of the payment requests, the money a user asks
+// another user for, which the payer accepts with a transfer or declines.
+func (server *Server) addPaymentRequestRoutes(apiRouter *routeGroup) {
+	paymentRequestRouter := apiRouter.Group("/payment_requests")
+	paymentRequestRouter.POST("", server.createPaymentRequest)
+	paymentRequestRouter.GET("", server.listPaymentRequests)
+	paymentRequestRouter.GET("/:id", server.getPaymentRequest)
+	paymentRequestRouter.POST("/:id/accept", server.signatureMiddleware(), server.acceptPaymentRequest)
+	paymentRequestRouter.POST("/:id/decline", server.declinePaymentRequest)
+}
+
+// The createPaymentRequestRequest type holds the money asked for.
+// @property {string} Payer - the user asked to pay.
+// @property {int64} ToAccountID - the account of the authenticated user the money is sent to.
+// @property {int64} Amount - the amount asked for, in the currency of the account.
+// @property {string} Memo - optional free text shown to the payer.
+type createPaymentRequestRequest struct {
+	Payer       string `json:"payer" binding:"required,alphanum"`
+	ToAccountID int64  `json:"to_account_id" binding:"required,min=1"`
+	Amount      int64  `json:"amount" binding:"required,gt=0"`
+	Memo        string `json:"memo" binding:"omitempty,max=140"`
+}
+
+// This is a function that asks another user for money, sent to an account of the authenticated user once
+// the payer accepts the request. The payer is notified and the request expires if left pending.
+func (server *Server) createPaymentRequest(ctx *gin.Context) {
+	var req createPaymentRequestRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	request, err := server.service.CreatePaymentRequest(ctx, service.CreatePaymentRequestParams{
+		Requester:   authPayload.Username,
+		Payer:       req.Payer,
+		ToAccountID: req.ToAccountID,
+		Amount:      req.Amount,
+		Memo:        req.Memo,
+	})
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, request)
+}
+
+// The listPaymentRequestsRequest type holds the filters of a payment request listing.
+// @property {string} Side - received (the default) for the requests the authenticated user is asked to
+// pay, sent for the requests they made.
+// @property {string} Status - only list the requests with this status when it is set.
+type listPaymentRequestsRequest struct {
+	pageRequest
+	Side   string `form:"side" binding:"omitempty,oneof=received sent"`
+	Status string `form:"status" binding:"omitempty,oneof=pending accepted declined expired"`
+}
+
+// This is a function that lists the payment requests received or sent by the authenticated user, oldest
+// first.
+func (server *Server) listPaymentRequests(ctx *gin.Context) {
+	var req listPaymentRequestsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	limit, offset, err := server.paginate(paginationPaymentRequests, req.pageRequest)
+	if err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	side := req.Side
+	if side == "" {
+		side = service.PaymentRequestsReceived
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	requests, err := server.service.ListPaymentRequests(ctx, service.ListPaymentRequestsParams{
+		Username: authPayload.Username,
+		Side:     side,
+		Status:   req.Status,
+		Limit:    limit,
+		Offset:   offset,
+	})
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, requests)
+}
+
+type paymentRequestURIRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// This is a function that gets a payment request made or received by the authenticated user.
+func (server *Server) getPaymentRequest(ctx *gin.Context) {
+	var req paymentRequestURIRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	request, err := server.service.GetPaymentRequest(ctx, authPayload.Username, req.ID)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, request)
+}
+
+// The acceptPaymentRequestRequest type holds the account the payer pays a request from.
+type acceptPaymentRequestRequest struct {
+	FromAccountID int64 `json:"from_account_id" binding:"required,min=1"`
+}
+
+// This is a function that accepts a pending payment request addressed to the authenticated user, paying
+// it with a transfer from one of their accounts in the currency of the request. The request can't be
+// accepted once declined or expired.
+func (server *Server) acceptPaymentRequest(ctx *gin.Context) {
+	var uri paymentRequestURIRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	var req acceptPaymentRequestRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	result, err := server.service.AcceptPaymentRequest(ctx, service.AcceptPaymentRequestParams{
+		Payer:         authPayload.Username,
+		ID:            uri.ID,
+		FromAccountID: req.FromAccountID,
+	})
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, result)
+}
+
+// This is a function that declines a pending payment request addressed to the authenticated user.
+func (server *Server) declinePaymentRequest(ctx *gin.Context) {
+	var req paymentRequestURIRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	request, err := server.service.DeclinePaymentRequest(ctx, authPayload.Username, req.ID)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, request)
+}
@@ -0,0 +1,541 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/service"
+	"go-backend/testutil/factory"
+	"go-backend/util"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func requireBodyMatchPaymentRequest(t *testing.T, body *bytes.Buffer, request db.PaymentRequest) {
+	var got db.PaymentRequest
+	err := json.Unmarshal(body.Bytes(), &got)
+	require.NoError(t, err)
+
+	require.Equal(t, request.ID, got.ID)
+	require.Equal(t, request.Requester, got.Requester)
+	require.Equal(t, request.Payer, got.Payer)
+	require.Equal(t, request.ToAccountID, got.ToAccountID)
+	require.Equal(t, request.Amount, got.Amount)
+	require.Equal(t, request.Status, got.Status)
+	require.WithinDuration(t, request.ExpiresAt, got.ExpiresAt, time.Millisecond)
+}
+
+func TestCreatePaymentRequestAPI(t *testing.T) {
+	requester := factory.User()
+	payer := factory.User()
+	account := factory.Account(factory.OwnedBy(requester.Username))
+	paymentRequest := factory.PaymentRequest(factory.PaidInto(account), factory.PaidBy(payer.Username))
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"payer": payer.Username, "to_account_id": account.ID, "amount": paymentRequest.Amount, "memo": paymentRequest.Memo},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(payer.Username)).Times(1).Return(payer, nil)
+				store.EXPECT().CreatePaymentRequest(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ interface{}, arg db.CreatePaymentRequestParams) (db.PaymentRequest, error) {
+						require.Equal(t, requester.Username, arg.Requester)
+						require.Equal(t, payer.Username, arg.Payer)
+						require.Equal(t, account.ID, arg.ToAccountID)
+						require.Equal(t, paymentRequest.Amount, arg.Amount)
+						require.Equal(t, paymentRequest.Memo, arg.Memo)
+						return paymentRequest, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchPaymentRequest(t, recorder.Body, paymentRequest)
+			},
+		},
+		{
+			name: "AccountOfAnotherUser",
+			body: gin.H{"payer": payer.Username, "to_account_id": account.ID, "amount": paymentRequest.Amount},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(factory.Account(), nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(db.AccountMember{}, db.ErrRecordNotFound)
+				store.EXPECT().CreatePaymentRequest(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "PayerNotFound",
+			body: gin.H{"payer": payer.Username, "to_account_id": account.ID, "amount": paymentRequest.Amount},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(payer.Username)).Times(1).Return(db.User{}, db.ErrRecordNotFound)
+				store.EXPECT().CreatePaymentRequest(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "RequestFromOneself",
+			body: gin.H{"payer": requester.Username, "to_account_id": account.ID, "amount": paymentRequest.Amount},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().CreatePaymentRequest(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireErrorBody(t, recorder.Body, util.ErrorCodeInvalidArgument)
+			},
+		},
+		{
+			name: "InvalidAmount",
+			body: gin.H{"payer": payer.Username, "to_account_id": account.ID, "amount": -1},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().CreatePaymentRequest(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/api/v1/payment_requests", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, requester.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestListPaymentRequestsAPI(t *testing.T) {
+	user := factory.User()
+	paymentRequests := []db.PaymentRequest{
+		factory.PaymentRequest(factory.PaidBy(user.Username)),
+		factory.PaymentRequest(factory.PaidBy(user.Username)),
+	}
+
+	testCases := []struct {
+		name          string
+		query         string
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "Received",
+			query: "",
+			buildStub: func(store *mockdb.MockStore) {
+				arg := db.ListPaymentRequestsParams{
+					Payer:     pgtype.Text{String: user.Username, Valid: true},
+					RowLimit:  20,
+					RowOffset: 0,
+				}
+				store.EXPECT().ListPaymentRequests(gomock.Any(), gomock.Eq(arg)).Times(1).Return(paymentRequests, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got []db.PaymentRequest
+				err := json.Unmarshal(recorder.Body.Bytes(), &got)
+				require.NoError(t, err)
+				require.Len(t, got, len(paymentRequests))
+			},
+		},
+		{
+			name:  "SentPending",
+			query: "?side=sent&status=pending&page_id=2&page_size=5",
+			buildStub: func(store *mockdb.MockStore) {
+				arg := db.ListPaymentRequestsParams{
+					Requester: pgtype.Text{String: user.Username, Valid: true},
+					Status:    pgtype.Text{String: db.PaymentRequestPending, Valid: true},
+					RowLimit:  5,
+					RowOffset: 5,
+				}
+				store.EXPECT().ListPaymentRequests(gomock.Any(), gomock.Eq(arg)).Times(1).Return([]db.PaymentRequest{}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:  "InvalidSide",
+			query: "?side=both",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().ListPaymentRequests(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "InvalidStatus",
+			query: "?status=paid",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().ListPaymentRequests(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/api/v1/payment_requests"+tc.query, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestGetPaymentRequestAPI(t *testing.T) {
+	payer := factory.User()
+	toAccount := factory.Account()
+	paymentRequest := factory.PaymentRequest(factory.PaidInto(toAccount), factory.PaidBy(payer.Username))
+
+	testCases := []struct {
+		name          string
+		username      string
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "Payer",
+			username: payer.Username,
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchPaymentRequest(t, recorder.Body, paymentRequest)
+			},
+		},
+		{
+			name:     "Requester",
+			username: paymentRequest.Requester,
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchPaymentRequest(t, recorder.Body, paymentRequest)
+			},
+		},
+		{
+			name:     "AnotherUser",
+			username: util.RandomOwner(),
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetPaymentRequest(gomock.Any(), gomock.Eq(paymentRequest.ID)).Times(1).Return(paymentRequest, nil)
+			store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/api/v1/payment_requests/%d", paymentRequest.ID)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestAcceptPaymentRequestAPI(t *testing.T) {
+	payer := factory.User()
+	fromAccount := factory.Account(factory.OwnedBy(payer.Username), factory.InCurrency(util.USD))
+	toAccount := factory.Account(factory.InCurrency(util.USD))
+	paymentRequest := factory.PaymentRequest(factory.PaidInto(toAccount), factory.PaidBy(payer.Username))
+
+	accepted := paymentRequest
+	accepted.Status = db.PaymentRequestAccepted
+	result := db.AcceptPaymentRequestTxResult{
+		PaymentRequest: accepted,
+		Transfer: db.TransferTxResult{
+			Transfer: factory.Transfer(factory.Between(fromAccount, toAccount)),
+		},
+	}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"from_account_id": fromAccount.ID},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPaymentRequest(gomock.Any(), gomock.Eq(paymentRequest.ID)).Times(1).Return(paymentRequest, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(3).Return(toAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetActiveBankParameter(gomock.Any(), gomock.Any()).Times(1).Return(db.BankParameter{}, db.ErrRecordNotFound)
+
+				arg := db.AcceptPaymentRequestTxParams{
+					ID:            paymentRequest.ID,
+					FromAccountID: fromAccount.ID,
+					Currency:      toAccount.Currency,
+				}
+				store.EXPECT().AcceptPaymentRequestTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(result, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got db.AcceptPaymentRequestTxResult
+				err := json.Unmarshal(recorder.Body.Bytes(), &got)
+				require.NoError(t, err)
+				require.Equal(t, db.PaymentRequestAccepted, got.PaymentRequest.Status)
+				require.Equal(t, result.Transfer.Transfer.ID, got.Transfer.Transfer.ID)
+			},
+		},
+		{
+			name: "NotPayer",
+			body: gin.H{"from_account_id": fromAccount.ID},
+			buildStub: func(store *mockdb.MockStore) {
+				other := paymentRequest
+				other.Payer = util.RandomOwner()
+				store.EXPECT().GetPaymentRequest(gomock.Any(), gomock.Eq(paymentRequest.ID)).Times(1).Return(other, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().AcceptPaymentRequestTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "Expired",
+			body: gin.H{"from_account_id": fromAccount.ID},
+			buildStub: func(store *mockdb.MockStore) {
+				expired := paymentRequest
+				expired.ExpiresAt = time.Now().Add(-time.Minute)
+				store.EXPECT().GetPaymentRequest(gomock.Any(), gomock.Eq(paymentRequest.ID)).Times(1).Return(expired, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().AcceptPaymentRequestTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				requireErrorBody(t, recorder.Body, service.ReasonPaymentRequestExpired)
+			},
+		},
+		{
+			name: "Declined",
+			body: gin.H{"from_account_id": fromAccount.ID},
+			buildStub: func(store *mockdb.MockStore) {
+				declined := paymentRequest
+				declined.Status = db.PaymentRequestDeclined
+				store.EXPECT().GetPaymentRequest(gomock.Any(), gomock.Eq(paymentRequest.ID)).Times(1).Return(declined, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().AcceptPaymentRequestTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				requireErrorBody(t, recorder.Body, service.ReasonPaymentRequestClosed)
+			},
+		},
+		{
+			name: "AcceptedMeanwhile",
+			body: gin.H{"from_account_id": fromAccount.ID},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPaymentRequest(gomock.Any(), gomock.Eq(paymentRequest.ID)).Times(1).Return(paymentRequest, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(3).Return(toAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetActiveBankParameter(gomock.Any(), gomock.Any()).Times(1).Return(db.BankParameter{}, db.ErrRecordNotFound)
+				store.EXPECT().AcceptPaymentRequestTx(gomock.Any(), gomock.Any()).Times(1).Return(db.AcceptPaymentRequestTxResult{}, db.ErrPaymentRequestClosed)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				requireErrorBody(t, recorder.Body, service.ReasonPaymentRequestClosed)
+			},
+		},
+		{
+			name: "CurrencyMismatch",
+			body: gin.H{"from_account_id": fromAccount.ID},
+			buildStub: func(store *mockdb.MockStore) {
+				cadAccount := fromAccount
+				cadAccount.Currency = util.CAD
+				store.EXPECT().GetPaymentRequest(gomock.Any(), gomock.Eq(paymentRequest.ID)).Times(1).Return(paymentRequest, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(2).Return(toAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(cadAccount, nil)
+				store.EXPECT().AcceptPaymentRequestTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireErrorBody(t, recorder.Body, service.ReasonCurrencyMismatch)
+			},
+		},
+		{
+			name: "MissingFromAccount",
+			body: gin.H{},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPaymentRequest(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/api/v1/payment_requests/%d/accept", paymentRequest.ID)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, payer.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestDeclinePaymentRequestAPI(t *testing.T) {
+	payer := factory.User()
+	toAccount := factory.Account()
+	paymentRequest := factory.PaymentRequest(factory.PaidInto(toAccount), factory.PaidBy(payer.Username))
+
+	declined := paymentRequest
+	declined.Status = db.PaymentRequestDeclined
+
+	testCases := []struct {
+		name          string
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPaymentRequest(gomock.Any(), gomock.Eq(paymentRequest.ID)).Times(1).Return(paymentRequest, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().DeclinePaymentRequest(gomock.Any(), gomock.Eq(paymentRequest.ID)).Times(1).Return(declined, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchPaymentRequest(t, recorder.Body, declined)
+			},
+		},
+		{
+			name: "AlreadyDeclined",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPaymentRequest(gomock.Any(), gomock.Eq(paymentRequest.ID)).Times(1).Return(declined, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().DeclinePaymentRequest(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				requireErrorBody(t, recorder.Body, service.ReasonPaymentRequestClosed)
+			},
+		},
+		{
+			name: "AcceptedMeanwhile",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPaymentRequest(gomock.Any(), gomock.Eq(paymentRequest.ID)).Times(1).Return(paymentRequest, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().DeclinePaymentRequest(gomock.Any(), gomock.Eq(paymentRequest.ID)).Times(1).Return(db.PaymentRequest{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				requireErrorBody(t, recorder.Body, service.ReasonPaymentRequestClosed)
+			},
+		},
+		{
+			name: "NotFound",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPaymentRequest(gomock.Any(), gomock.Eq(paymentRequest.ID)).Times(1).Return(db.PaymentRequest{}, db.ErrRecordNotFound)
+				store.EXPECT().DeclinePaymentRequest(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/api/v1/payment_requests/%d/decline", paymentRequest.ID)
+			request, err := http.NewRequest(http.MethodPost, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, payer.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
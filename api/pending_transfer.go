@@ -0,0 +1,182 @@
+package api
+
+import (
+	"go-backend/service"
+	"go-backend/token"
+	"go-backend/util"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// The `addPendingTransferRoutes` function adds the routes of the large transfers awaiting approval, which
+// their owner confirms or cancels.
+func (server *Server) addPendingTransferRoutes(apiRouter *routeGroup) {
+	pendingTransferRouter := apiRouter.Group("/pending_transfers")
+	pendingTransferRouter.GET("", server.listPendingTransfers)
+	pendingTransferRouter.GET("/:id", server.getPendingTransfer)
+	pendingTransferRouter.POST("/:id/confirm", server.confirmPendingTransfer)
+	pendingTransferRouter.POST("/:id/cancel", server.cancelPendingTransfer)
+}
+
+// The `addPendingTransferAdminRoutes` function adds the approval of the pending transfers by a banker to
+// the admin routes.
+func (server *Server) addPendingTransferAdminRoutes(adminRouter *routeGroup) {
+	pendingTransferRouter := adminRouter.Group("/pending_transfers")
+	pendingTransferRouter.GET("", server.listAllPendingTransfers)
+	pendingTransferRouter.POST("/:id/approve", server.approvePendingTransfer)
+	pendingTransferRouter.POST("/:id/reject", server.rejectPendingTransfer)
+}
+
+// The listPendingTransfersRequest type holds the filter of a pending transfer listing.
+// @property {string} Status - only list the transfers with this status when it is set.
+type listPendingTransfersRequest struct {
+	pageRequest
+	Status string `form:"status" binding:"omitempty,oneof=pending_approval approved rejected expired"`
+}
+
+// This is a function that lists the pending transfers of the authenticated user, oldest first.
+func (server *Server) listPendingTransfers(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	server.renderPendingTransfers(ctx, paginationTransfers, authPayload.Username)
+}
+
+// This is a function that lists the pending transfers of every user, oldest first, for the bankers
+// approving them.
+func (server *Server) listAllPendingTransfers(ctx *gin.Context) {
+	server.renderPendingTransfers(ctx, paginationAdmin, "")
+}
+
+func (server *Server) renderPendingTransfers(ctx *gin.Context, endpoint string, owner string) {
+	var req listPendingTransfersRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	limit, offset, err := server.paginate(endpoint, req.pageRequest)
+	if err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	pending, err := server.service.ListPendingTransfers(ctx, service.ListPendingTransfersParams{
+		Owner:  owner,
+		Status: req.Status,
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, pending)
+}
+
+type pendingTransferURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// This is a function that gets a pending transfer of the authenticated user.
+func (server *Server) getPendingTransfer(ctx *gin.Context) {
+	var uri pendingTransferURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	pending, err := server.service.GetPendingTransfer(ctx, authPayload.Username, uri.ID)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, pending)
+}
+
+// The confirmPendingTransferRequest type holds the password the owner confirms a transfer with.
+type confirmPendingTransferRequest struct {
+	Password string `json:"password" binding:"required"`
+}
+
+// This is a function that makes a transfer of the authenticated user awaiting approval, once they
+// confirmed it with their password. The transfer is checked again before being made.
+func (server *Server) confirmPendingTransfer(ctx *gin.Context) {
+	var uri pendingTransferURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	var req confirmPendingTransferRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	result, err := server.service.ConfirmPendingTransfer(ctx, authPayload.Username, uri.ID, req.Password)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, result)
+}
+
+// This is a function that cancels a transfer of the authenticated user awaiting approval.
+func (server *Server) cancelPendingTransfer(ctx *gin.Context) {
+	var uri pendingTransferURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	pending, err := server.service.CancelPendingTransfer(ctx, authPayload.Username, uri.ID)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, pending)
+}
+
+// This is a function that makes a transfer awaiting approval on behalf of its owner, for a banker who
+// checked it with them.
+func (server *Server) approvePendingTransfer(ctx *gin.Context) {
+	var uri pendingTransferURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	result, err := server.service.ApprovePendingTransfer(ctx, authPayload.Username, uri.ID)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, result)
+}
+
+// This is a function that rejects a transfer awaiting approval, which is then never made.
+func (server *Server) rejectPendingTransfer(ctx *gin.Context) {
+	var uri pendingTransferURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	pending, err := server.service.RejectPendingTransfer(ctx, authPayload.Username, uri.ID)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, pending)
+}
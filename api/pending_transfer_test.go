@@ -0,0 +1,340 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go-backend/clock"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/service"
+	"go-backend/testutil/factory"
+	"go-backend/util"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfirmPendingTransferAPI(t *testing.T) {
+	owner, password := factory.UserWithPassword(t)
+	fromAccount := factory.Account(factory.OwnedBy(owner.Username), factory.InCurrency(util.USD))
+	toAccount := factory.Account(factory.InCurrency(util.USD))
+	toAccount.ID = fromAccount.ID + 1
+	pending := factory.PendingTransfer(factory.PendingBetween(fromAccount, toAccount))
+
+	approved := pending
+	approved.Status = db.PendingTransferApproved
+	result := db.ApprovePendingTransferTxResult{
+		PendingTransfer: approved,
+		Transfer: db.TransferTxResult{
+			Transfer: factory.Transfer(factory.Between(fromAccount, toAccount)),
+		},
+	}
+
+	now := time.Now()
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"password": password},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPendingTransfer(gomock.Any(), gomock.Eq(pending.ID)).Times(1).Return(pending, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(owner.Username)).Times(1).Return(owner, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(2).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().GetActiveBankParameter(gomock.Any(), gomock.Any()).Times(1).Return(db.BankParameter{}, db.ErrRecordNotFound)
+
+				arg := db.ApprovePendingTransferTxParams{
+					ID:        pending.ID,
+					DecidedBy: owner.Username,
+					Currency:  fromAccount.Currency,
+					Now:       now,
+				}
+				store.EXPECT().ApprovePendingTransferTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(result, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got db.ApprovePendingTransferTxResult
+				err := json.Unmarshal(recorder.Body.Bytes(), &got)
+				require.NoError(t, err)
+				require.Equal(t, db.PendingTransferApproved, got.PendingTransfer.Status)
+				require.Equal(t, result.Transfer.Transfer.ID, got.Transfer.Transfer.ID)
+			},
+		},
+		{
+			name: "WrongPassword",
+			body: gin.H{"password": password + "x"},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPendingTransfer(gomock.Any(), gomock.Eq(pending.ID)).Times(1).Return(pending, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(owner.Username)).Times(1).Return(owner, nil)
+				store.EXPECT().ApprovePendingTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				requireErrorBody(t, recorder.Body, service.ReasonInvalidCredentials)
+			},
+		},
+		{
+			name: "NotOwner",
+			body: gin.H{"password": password},
+			buildStub: func(store *mockdb.MockStore) {
+				other := pending
+				other.Owner = util.RandomOwner()
+				store.EXPECT().GetPendingTransfer(gomock.Any(), gomock.Eq(pending.ID)).Times(1).Return(other, nil)
+				store.EXPECT().ApprovePendingTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "Expired",
+			body: gin.H{"password": password},
+			buildStub: func(store *mockdb.MockStore) {
+				expired := pending
+				expired.ExpiresAt = time.Now().Add(-time.Minute)
+				store.EXPECT().GetPendingTransfer(gomock.Any(), gomock.Eq(pending.ID)).Times(1).Return(expired, nil)
+				store.EXPECT().ApprovePendingTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				requireErrorBody(t, recorder.Body, service.ReasonPendingTransferExpired)
+			},
+		},
+		{
+			name: "Rejected",
+			body: gin.H{"password": password},
+			buildStub: func(store *mockdb.MockStore) {
+				rejected := pending
+				rejected.Status = db.PendingTransferRejected
+				store.EXPECT().GetPendingTransfer(gomock.Any(), gomock.Eq(pending.ID)).Times(1).Return(rejected, nil)
+				store.EXPECT().ApprovePendingTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				requireErrorBody(t, recorder.Body, service.ReasonPendingTransferClosed)
+			},
+		},
+		{
+			name: "MissingPassword",
+			body: gin.H{},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPendingTransfer(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			server.service.SetClock(clock.NewFake(now))
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/api/v1/pending_transfers/%d/confirm", pending.ID)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, owner.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestCancelPendingTransferAPI(t *testing.T) {
+	owner := factory.User()
+	pending := factory.PendingTransfer(func(pending *db.PendingTransfer) {
+		pending.Owner = owner.Username
+	})
+
+	rejected := pending
+	rejected.Status = db.PendingTransferRejected
+
+	testCases := []struct {
+		name          string
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPendingTransfer(gomock.Any(), gomock.Eq(pending.ID)).Times(1).Return(pending, nil)
+				store.EXPECT().RejectPendingTransfer(gomock.Any(), gomock.Eq(db.RejectPendingTransferParams{
+					DecidedBy: owner.Username,
+					ID:        pending.ID,
+				})).Times(1).Return(rejected, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got db.PendingTransfer
+				err := json.Unmarshal(recorder.Body.Bytes(), &got)
+				require.NoError(t, err)
+				require.Equal(t, db.PendingTransferRejected, got.Status)
+			},
+		},
+		{
+			name: "ApprovedMeanwhile",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPendingTransfer(gomock.Any(), gomock.Eq(pending.ID)).Times(1).Return(pending, nil)
+				store.EXPECT().RejectPendingTransfer(gomock.Any(), gomock.Any()).Times(1).Return(db.PendingTransfer{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				requireErrorBody(t, recorder.Body, service.ReasonPendingTransferClosed)
+			},
+		},
+		{
+			name: "NotFound",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPendingTransfer(gomock.Any(), gomock.Eq(pending.ID)).Times(1).Return(db.PendingTransfer{}, db.ErrRecordNotFound)
+				store.EXPECT().RejectPendingTransfer(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/api/v1/pending_transfers/%d/cancel", pending.ID)
+			request, err := http.NewRequest(http.MethodPost, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, owner.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestDecidePendingTransferAPI(t *testing.T) {
+	admin := factory.User(factory.WithRole(util.AdminRole))
+	depositor := factory.User()
+	fromAccount := factory.Account(factory.InCurrency(util.USD))
+	toAccount := factory.Account(factory.InCurrency(util.USD))
+	toAccount.ID = fromAccount.ID + 1
+	pending := factory.PendingTransfer(factory.PendingBetween(fromAccount, toAccount))
+
+	now := time.Now()
+
+	testCases := []struct {
+		name          string
+		user          db.User
+		action        string
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "Approve",
+			user:   admin,
+			action: "approve",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPendingTransfer(gomock.Any(), gomock.Eq(pending.ID)).Times(1).Return(pending, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(2).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().GetActiveBankParameter(gomock.Any(), gomock.Any()).Times(1).Return(db.BankParameter{}, db.ErrRecordNotFound)
+				store.EXPECT().ApprovePendingTransferTx(gomock.Any(), gomock.Eq(db.ApprovePendingTransferTxParams{
+					ID:        pending.ID,
+					DecidedBy: admin.Username,
+					Currency:  fromAccount.Currency,
+					Now:       now,
+				})).Times(1).Return(db.ApprovePendingTransferTxResult{
+					PendingTransfer: db.PendingTransfer{ID: pending.ID, Status: db.PendingTransferApproved},
+				}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "Reject",
+			user:   admin,
+			action: "reject",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPendingTransfer(gomock.Any(), gomock.Eq(pending.ID)).Times(1).Return(pending, nil)
+				store.EXPECT().RejectPendingTransfer(gomock.Any(), gomock.Eq(db.RejectPendingTransferParams{
+					DecidedBy: admin.Username,
+					ID:        pending.ID,
+				})).Times(1).Return(db.PendingTransfer{ID: pending.ID, Status: db.PendingTransferRejected}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "Forbidden",
+			user:   depositor,
+			action: "approve",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPendingTransfer(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().ApprovePendingTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetUser(gomock.Any(), gomock.Eq(tc.user.Username)).Times(1).Return(tc.user, nil)
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			server.service.SetClock(clock.NewFake(now))
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/api/v1/admin/pending_transfers/%d/%s", pending.ID, tc.action)
+			request, err := http.NewRequest(http.MethodPost, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
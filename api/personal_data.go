@@ -0,0 +1,118 @@
+package api
+
+import (
+	db "go-backend/db/sqlc"
+	"go-backend/service"
+	"go-backend/token"
+	"go-backend/util"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// The `addPersonalDataRoutes` function adds the routes of the authenticated user exporting their personal
+// data and asking for its deletion.
+func (server *Server) addPersonalDataRoutes(apiRouter *routeGroup) {
+	meRouter := apiRouter.Group("/users/me")
+	meRouter.POST("/export", server.exportPersonalData)
+	meRouter.POST("/deletion", server.requestUserDeletion)
+	meRouter.GET("/deletion", server.getUserDeletion)
+	meRouter.DELETE("/deletion", server.cancelUserDeletion)
+}
+
+// This is a function that creates a job exporting all the personal data kept about the authenticated
+// user as a JSON file. It returns a 202 Accepted response with the job, whose progress is tracked with
+// `GET /jobs/:id` and whose file is downloaded once it succeeded.
+func (server *Server) exportPersonalData(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	job, err := server.service.ExportPersonalData(ctx, authPayload.Username)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusAccepted, server.newJobResponse(job))
+}
+
+type requestUserDeletionRequest struct {
+	Password string `json:"password" binding:"required"`
+}
+
+// The userDeletionResponse type is the deletion of the data of a user.
+// @property {time.Time} ScheduledFor - the end of the cooling-off period, when the data is deleted unless
+// the user cancels.
+// @property {string} Error - why the deletion failed, only set when it did.
+type userDeletionResponse struct {
+	Username     string     `json:"username"`
+	Status       string     `json:"status"`
+	ScheduledFor time.Time  `json:"scheduled_for"`
+	Error        string     `json:"error,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	CompletedAt  *time.Time `json:"completed_at,omitempty"`
+}
+
+func newUserDeletionResponse(deletion db.UserDeletion) userDeletionResponse {
+	res := userDeletionResponse{
+		Username:     deletion.Username,
+		Status:       deletion.Status,
+		ScheduledFor: deletion.ScheduledFor,
+		Error:        deletion.Error,
+		CreatedAt:    deletion.CreatedAt,
+	}
+	if deletion.CompletedAt.Valid {
+		res.CompletedAt = &deletion.CompletedAt.Time
+	}
+	return res
+}
+
+// This is a function that schedules the deletion of the data of the authenticated user, who confirms it
+// with their password. The accounts are closed and the personal data anonymized at the end of the
+// cooling-off period, until which the user can cancel. It returns a 202 Accepted response with the
+// deletion, a 409 Conflict response when one is already scheduled or an account still holds money.
+func (server *Server) requestUserDeletion(ctx *gin.Context) {
+	var req requestUserDeletionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	deletion, err := server.service.RequestUserDeletion(ctx, service.RequestUserDeletionParams{
+		Username: authPayload.Username,
+		Password: req.Password,
+	})
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusAccepted, newUserDeletionResponse(deletion))
+}
+
+// This is a function that reports the latest deletion of the data of the authenticated user, a 404 Not
+// Found response being returned when they never asked for one.
+func (server *Server) getUserDeletion(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	deletion, err := server.service.GetUserDeletion(ctx, authPayload.Username)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, newUserDeletionResponse(deletion))
+}
+
+// This is a function that cancels the deletion of the data of the authenticated user during its
+// cooling-off period. A deletion that isn't scheduled anymore can't be cancelled, in which case it
+// returns a 409 Conflict response.
+func (server *Server) cancelUserDeletion(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	deletion, err := server.service.CancelUserDeletion(ctx, authPayload.Username)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, newUserDeletionResponse(deletion))
+}
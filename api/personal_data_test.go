@@ -0,0 +1,299 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/service"
+	"go-backend/testutil/factory"
+	"go-backend/token"
+	"go-backend/worker"
+	mockwk "go-backend/worker/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportPersonalDataAPI(t *testing.T) {
+	user := factory.User()
+	job := randomJob(user.Username, worker.ExportKindGDPR)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	distributor := mockwk.NewMockTaskDistributor(ctrl)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+	store.EXPECT().
+		CreateJob(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ interface{}, arg db.CreateJobParams) (db.Job, error) {
+			require.Equal(t, user.Username, arg.Username)
+			require.Equal(t, worker.ExportKindGDPR, arg.Kind)
+			return job, nil
+		})
+	distributor.EXPECT().
+		DistributeTaskRunExport(gomock.Any(), gomock.Eq(&worker.PayloadRunExport{JobID: job.ID}), gomock.Any()).
+		Times(1).
+		Return(nil)
+
+	server := newTestServerWithDistributor(t, store, distributor)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodPost, "/api/v1/users/me/export", nil)
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusAccepted, recorder.Code)
+	requireBodyMatchJob(t, recorder.Body, job)
+}
+
+func TestRequestUserDeletionAPI(t *testing.T) {
+	user, password := factory.UserWithPassword(t)
+	deletion := db.UserDeletion{
+		Username:     user.Username,
+		Status:       db.UserDeletionScheduled,
+		ScheduledFor: time.Now().Add(time.Hour).Truncate(time.Second),
+		CreatedAt:    time.Now(),
+	}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		setupAuth     func(request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore, distributor *mockwk.MockTaskDistributor)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"password": password},
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore, distributor *mockwk.MockTaskDistributor) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().OwnerHasBalance(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(false, nil)
+				store.EXPECT().
+					ScheduleUserDeletion(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.ScheduleUserDeletionParams) (db.UserDeletion, error) {
+						require.Equal(t, user.Username, arg.Username)
+						// the cooling-off period of the config
+						require.WithinDuration(t, time.Now().Add(time.Hour), arg.ScheduledFor, time.Second)
+						return deletion, nil
+					})
+				distributor.EXPECT().
+					DistributeTaskDeleteUserData(gomock.Any(), gomock.Eq(&worker.PayloadDeleteUserData{
+						Username:     user.Username,
+						ScheduledFor: deletion.ScheduledFor.Unix(),
+					}), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, _ *worker.PayloadDeleteUserData, opts ...asynq.Option) error {
+						require.Contains(t, opts, asynq.ProcessAt(deletion.ScheduledFor))
+						return nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+
+				var res userDeletionResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.Equal(t, db.UserDeletionScheduled, res.Status)
+				require.True(t, deletion.ScheduledFor.Equal(res.ScheduledFor))
+				require.Nil(t, res.CompletedAt)
+			},
+		},
+		{
+			name: "WrongPassword",
+			body: gin.H{"password": "wrong-password"},
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore, distributor *mockwk.MockTaskDistributor) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().ScheduleUserDeletion(gomock.Any(), gomock.Any()).Times(0)
+				distributor.EXPECT().DistributeTaskDeleteUserData(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				requireErrorBody(t, recorder.Body, service.ReasonInvalidCredentials)
+			},
+		},
+		{
+			name: "AccountNotEmpty",
+			body: gin.H{"password": password},
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore, distributor *mockwk.MockTaskDistributor) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().OwnerHasBalance(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(true, nil)
+				store.EXPECT().ScheduleUserDeletion(gomock.Any(), gomock.Any()).Times(0)
+				distributor.EXPECT().DistributeTaskDeleteUserData(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				requireErrorBody(t, recorder.Body, service.ReasonAccountNotEmpty)
+			},
+		},
+		{
+			name: "AlreadyScheduled",
+			body: gin.H{"password": password},
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore, distributor *mockwk.MockTaskDistributor) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().OwnerHasBalance(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(false, nil)
+				store.EXPECT().ScheduleUserDeletion(gomock.Any(), gomock.Any()).Times(1).Return(db.UserDeletion{}, db.ErrRecordNotFound)
+				distributor.EXPECT().DistributeTaskDeleteUserData(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				requireErrorBody(t, recorder.Body, service.ReasonDeletionScheduled)
+			},
+		},
+		{
+			name: "DistributeError",
+			body: gin.H{"password": password},
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore, distributor *mockwk.MockTaskDistributor) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().OwnerHasBalance(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(false, nil)
+				store.EXPECT().ScheduleUserDeletion(gomock.Any(), gomock.Any()).Times(1).Return(deletion, nil)
+				distributor.EXPECT().
+					DistributeTaskDeleteUserData(gomock.Any(), gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(fmt.Errorf("redis is down"))
+				// the deletion no task would complete is cancelled
+				store.EXPECT().CancelUserDeletion(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(deletion, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name: "MissingPassword",
+			body: gin.H{},
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore, distributor *mockwk.MockTaskDistributor) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NoAuthorization",
+			body: gin.H{"password": password},
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+			},
+			buildStubs: func(store *mockdb.MockStore, distributor *mockwk.MockTaskDistributor) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			distributor := mockwk.NewMockTaskDistributor(ctrl)
+			tc.buildStubs(store, distributor)
+
+			server := newTestServerWithDistributor(t, store, distributor)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/api/v1/users/me/deletion", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			tc.setupAuth(request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestCancelUserDeletionAPI(t *testing.T) {
+	user := factory.User()
+	cancelled := db.UserDeletion{
+		Username:     user.Username,
+		Status:       db.UserDeletionCancelled,
+		ScheduledFor: time.Now().Add(time.Hour),
+	}
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CancelUserDeletion(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(cancelled, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res userDeletionResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.Equal(t, db.UserDeletionCancelled, res.Status)
+			},
+		},
+		{
+			name: "NotScheduled",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CancelUserDeletion(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(db.UserDeletion{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				requireErrorBody(t, recorder.Body, service.ReasonDeletionNotScheduled)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodDelete, "/api/v1/users/me/deletion", nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
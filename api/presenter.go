@@ -0,0 +1,365 @@
+package api
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	db "go-backend/db/sqlc"
+	"go-backend/util"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// The fieldCasing type is the casing of the field names of the JSON responses.
+type fieldCasing string
+
+const (
+	snakeCase fieldCasing = "snake_case"
+	camelCase fieldCasing = "camelCase"
+
+	fieldCasingHeaderKey = "X-JSON-Casing"
+	fieldCasingKey       = "field_casing"
+
+	// timeFormat is the format of every time of the responses: RFC 3339 in UTC with milliseconds.
+	timeFormat = "2006-01-02T15:04:05.000Z07:00"
+)
+
+// moneyFields are the fields of the responses holding amounts of money, which are followed by their
+// display string, e.g. "1,234.00 CAD", in a field of the same name suffixed with _display.
+var moneyFields = map[string]bool{
+	"amount":            true,
+	"balance":           true,
+	"held_balance":      true,
+	"available_balance": true,
+}
+
+// The `parseFieldCasing` function returns the casing named by `value`, or `fallback` when it is empty.
+func parseFieldCasing(value string, fallback fieldCasing) (fieldCasing, error) {
+	switch fieldCasing(value) {
+	case "":
+		return fallback, nil
+	case snakeCase, camelCase:
+		return fieldCasing(value), nil
+	}
+	return fallback, fmt.Errorf("unsupported JSON field casing %q, must be %s or %s", value, snakeCase, camelCase)
+}
+
+// The `serializationMiddleware` function picks the casing of the fields of the response, the one asked
+// for in the X-JSON-Casing header or else `casing`, the one configured for the server.
+func serializationMiddleware(casing fieldCasing) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Writer.Header().Add("Vary", fieldCasingHeaderKey)
+
+		requested, err := parseFieldCasing(ctx.GetHeader(fieldCasingHeaderKey), casing)
+		ctx.Set(fieldCasingKey, requested)
+		if err != nil {
+			abortWithJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// The `renderJSON` function responds with `obj` as JSON, in the field casing of the request and with
+// its times in `timeFormat`. Error bodies are written in the language of the request. Every JSON
+// response of the API is written by it, mapped to the response of the version of the request.
+func renderJSON(ctx *gin.Context, status int, obj interface{}) {
+	if body, ok := obj.(util.ErrorBody); ok {
+		obj = body.Localize(requestLanguage(ctx))
+	}
+	if version := requestVersion(ctx); version.mapResponse != nil {
+		obj = version.mapResponse(ctx, status, obj)
+	}
+
+	casing, _ := ctx.Value(fieldCasingKey).(fieldCasing)
+	ctx.JSON(status, present(reflect.ValueOf(obj), casing))
+}
+
+// streamFlushItems is the number of items of a streamed listing sent to the client at once.
+const streamFlushItems = 100
+
+// The `streamJSON` function responds with the items as a JSON array, like `renderJSON` would respond
+// with the slice of their responses, but presents, encodes and sends them one at a time instead of
+// buffering the whole body, so that the listings of thousands of rows take little memory. `response`
+// returns the response of an item. Only the error bodies being mapped by the versions, the items are sent
+// as they are in every version. An item failing to encode cuts the body short, the status having been
+// sent already.
+func streamJSON[T any](ctx *gin.Context, status int, items []T, response func(item T) interface{}) {
+	casing, _ := ctx.Value(fieldCasingKey).(fieldCasing)
+
+	ctx.Header("Content-Type", "application/json; charset=utf-8")
+	ctx.Status(status)
+	writer := ctx.Writer
+	writer.WriteString("[")
+	for i, item := range items {
+		data, err := json.Marshal(present(reflect.ValueOf(response(item)), casing))
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+
+		if i > 0 {
+			writer.WriteString(",")
+		}
+		writer.Write(data)
+		if (i+1)%streamFlushItems == 0 {
+			writer.Flush()
+		}
+	}
+	writer.WriteString("]")
+}
+
+// The `abortWithJSON` function stops the handlers chain and responds like `renderJSON`.
+func abortWithJSON(ctx *gin.Context, status int, obj interface{}) {
+	ctx.Abort()
+	renderJSON(ctx, status, obj)
+}
+
+// The `present` function returns a value encoding to the same JSON as `value` would, apart from the
+// names of the fields being in `casing` and the times in `timeFormat`. The keys of `gin.H` maps are
+// field names, those of other maps are data and left as is. The amounts of money are followed by their
+// display string, see `presentFields`.
+func present(value reflect.Value, casing fieldCasing) interface{} {
+	return presentValue(value, casing, "")
+}
+
+// The `presentValue` function presents a value like `present`, its amounts being in `currency` unless
+// it holds its own.
+func presentValue(value reflect.Value, casing fieldCasing, currency string) interface{} {
+	if !value.IsValid() {
+		return nil
+	}
+
+	switch value.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if value.IsNil() {
+			return nil
+		}
+		return presentValue(value.Elem(), casing, currency)
+	}
+
+	switch v := value.Interface().(type) {
+	case time.Time:
+		return v.UTC().Format(timeFormat)
+	case pgtype.Timestamptz:
+		if !v.Valid {
+			return nil
+		}
+		return v.Time.UTC().Format(timeFormat)
+	case json.Marshaler, encoding.TextMarshaler:
+		return v
+	}
+
+	switch value.Kind() {
+	case reflect.Struct:
+		return presentFields(value, casing, object{}, amountCurrency(value, currency))
+	case reflect.Map:
+		if value.IsNil() {
+			return nil
+		}
+		return presentMap(value, casing)
+	case reflect.Slice:
+		if value.IsNil() {
+			return nil
+		}
+		if value.Type().Elem().Kind() == reflect.Uint8 {
+			// encoded in base64
+			return value.Interface()
+		}
+		fallthrough
+	case reflect.Array:
+		items := make([]interface{}, value.Len())
+		for i := range items {
+			items[i] = presentValue(value.Index(i), casing, currency)
+		}
+		return items
+	}
+	return value.Interface()
+}
+
+// The `presentFields` function appends the exported fields of a struct to `fields`, the fields of its
+// embedded structs included as if they were its own. The amounts of money in `moneyFields` are followed
+// by their display string in `currency`, e.g. "balance_display": "1,234.00 CAD" after the balance.
+func presentFields(value reflect.Value, casing fieldCasing, fields object, currency string) object {
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		tag := field.Tag.Get("json")
+		if !field.IsExported() || tag == "-" {
+			continue
+		}
+
+		name, options, _ := strings.Cut(tag, ",")
+		fieldValue := value.Field(i)
+		if field.Anonymous && name == "" {
+			embedded := fieldValue
+			if embedded.Kind() == reflect.Pointer {
+				if embedded.IsNil() {
+					continue
+				}
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				fields = presentFields(embedded, casing, fields, currency)
+				continue
+			}
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		if hasOption(options, "omitempty") && isEmptyValue(fieldValue) {
+			continue
+		}
+		fields = append(fields, member{key: casing.fieldName(name), value: presentValue(fieldValue, casing, currency)})
+		if moneyFields[name] && currency != "" && fieldValue.Kind() == reflect.Int64 {
+			fields = append(fields, member{key: casing.fieldName(name + "_display"), value: util.FormatAmount(fieldValue.Int(), currency)})
+		}
+	}
+	return fields
+}
+
+// The `amountCurrency` function returns the currency of the amounts of a struct: the one of its currency
+// field, the one of the accounts of a transfer result, which hold the same currency, or else `inherited`,
+// the currency of the value it belongs to.
+func amountCurrency(value reflect.Value, inherited string) string {
+	switch result := value.Interface().(type) {
+	case db.TransferTxResult:
+		return result.FromAccount.Currency
+	case transferResultResponse:
+		return result.FromAccount.Currency
+	}
+	if currency, ok := currencyField(value); ok {
+		return currency
+	}
+	return inherited
+}
+
+// currencyField returns the value of the currency field of a struct, looked up in its embedded structs
+// too.
+func currencyField(value reflect.Value) (string, bool) {
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "currency" && field.Type.Kind() == reflect.String {
+			return value.Field(i).String(), true
+		}
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			if currency, ok := currencyField(value.Field(i)); ok {
+				return currency, true
+			}
+		}
+	}
+	return "", false
+}
+
+func presentMap(value reflect.Value, casing fieldCasing) object {
+	fieldNames := value.Type() == reflect.TypeOf(gin.H{})
+
+	fields := make(object, 0, value.Len())
+	iter := value.MapRange()
+	for iter.Next() {
+		key := fmt.Sprint(iter.Key().Interface())
+		if fieldNames {
+			key = casing.fieldName(key)
+		}
+		fields = append(fields, member{key: key, value: present(iter.Value(), casing)})
+	}
+
+	// like encoding/json, the keys of maps are sorted
+	sort.Slice(fields, func(i, j int) bool {
+		return fields[i].key < fields[j].key
+	})
+	return fields
+}
+
+// The `fieldName` function writes a snake_case field name in the casing. The leading underscore of the
+// reserved fields, e.g. _links, is kept.
+func (casing fieldCasing) fieldName(name string) string {
+	if casing != camelCase {
+		return name
+	}
+
+	if strings.HasPrefix(name, "_") {
+		return "_" + casing.fieldName(name[1:])
+	}
+	words := strings.Split(name, "_")
+	for i := 1; i < len(words); i++ {
+		if words[i] != "" {
+			words[i] = strings.ToUpper(words[i][:1]) + words[i][1:]
+		}
+	}
+	return strings.Join(words, "")
+}
+
+func hasOption(options string, option string) bool {
+	for _, o := range strings.Split(options, ",") {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
+
+// isEmptyValue reports whether a field tagged omitempty is left out, as encoding/json does.
+func isEmptyValue(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return value.Len() == 0
+	case reflect.Bool:
+		return !value.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return value.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return value.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return value.Float() == 0
+	case reflect.Interface, reflect.Pointer:
+		return value.IsNil()
+	}
+	return false
+}
+
+// The member type is a field of an object, with its name already in the casing of the response.
+type member struct {
+	key   string
+	value interface{}
+}
+
+// The object type is a JSON object whose fields keep their order when encoded, the order of the fields
+// of the struct it presents.
+type object []member
+
+func (o object) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, m := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		key, err := json.Marshal(m.key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(m.value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,234 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/testutil/factory"
+	"go-backend/util"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPresent(t *testing.T) {
+	type Embedded struct {
+		AccountID int64 `json:"account_id"`
+	}
+	type response struct {
+		Embedded
+		CreatedAt   time.Time          `json:"created_at"`
+		DecidedAt   pgtype.Timestamptz `json:"decided_at"`
+		CompletedAt *time.Time         `json:"completed_at,omitempty"`
+		TransferID  pgtype.Int8        `json:"transfer_id"`
+		Balances    map[string]int64   `json:"balances"`
+		Details     gin.H              `json:"details"`
+		Secret      string             `json:"-"`
+		hidden      string
+	}
+
+	value := response{
+		Embedded:  Embedded{AccountID: 1},
+		CreatedAt: time.Date(2026, 10, 16, 9, 30, 0, 123456789, time.FixedZone("EDT", -4*60*60)),
+		TransferID: pgtype.Int8{
+			Int64: 2,
+			Valid: true,
+		},
+		Balances: map[string]int64{"USD_savings": 3},
+		Details:  gin.H{"review_reason": "large_amount"},
+		Secret:   "secret",
+		hidden:   "hidden",
+	}
+
+	testCases := []struct {
+		casing   fieldCasing
+		expected string
+	}{
+		{
+			casing:   snakeCase,
+			expected: `{"account_id":1,"created_at":"2026-10-16T13:30:00.123Z","decided_at":null,"transfer_id":2,"balances":{"USD_savings":3},"details":{"review_reason":"large_amount"}}`,
+		},
+		{
+			casing:   camelCase,
+			expected: `{"accountId":1,"createdAt":"2026-10-16T13:30:00.123Z","decidedAt":null,"transferId":2,"balances":{"USD_savings":3},"details":{"reviewReason":"large_amount"}}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.casing), func(t *testing.T) {
+			data, err := json.Marshal(present(reflect.ValueOf(value), tc.casing))
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, string(data))
+
+			data, err = json.Marshal(present(reflect.ValueOf([]response{}), tc.casing))
+			require.NoError(t, err)
+			require.Equal(t, `[]`, string(data))
+		})
+	}
+}
+
+func TestPresentAmounts(t *testing.T) {
+	type line struct {
+		Amount int64 `json:"amount"`
+	}
+	type response struct {
+		Lines    []line           `json:"lines"`
+		Balance  int64            `json:"balance"`
+		Currency string           `json:"currency"`
+		Totals   map[string]int64 `json:"totals"`
+	}
+
+	// the amounts of nested values are in the currency of the value they belong to, unlike the data of
+	// maps
+	value := response{Lines: []line{{Amount: 1234}}, Balance: -5, Currency: util.CAD, Totals: map[string]int64{"amount": 7}}
+	data, err := json.Marshal(present(reflect.ValueOf(value), camelCase))
+	require.NoError(t, err)
+	require.Equal(t, `{"lines":[{"amount":1234,"amountDisplay":"1,234.00 CAD"}],"balance":-5,"balanceDisplay":"-5.00 CAD","currency":"CAD","totals":{"amount":7}}`, string(data))
+
+	// without a currency, the amounts aren't displayed
+	data, err = json.Marshal(present(reflect.ValueOf(line{Amount: 1}), snakeCase))
+	require.NoError(t, err)
+	require.Equal(t, `{"amount":1}`, string(data))
+
+	// the transfers and entries of a transfer result are in the currency of its accounts
+	result := db.TransferTxResult{
+		Transfer:    db.Transfer{Amount: 10},
+		FromAccount: db.Account{Balance: 90, Currency: util.EUR},
+		ToEntry:     db.Entry{Amount: 10},
+	}
+	var presented map[string]map[string]interface{}
+	data, err = json.Marshal(present(reflect.ValueOf(result), snakeCase))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &presented))
+	require.Equal(t, "10.00 EUR", presented["transfer"]["amount_display"])
+	require.Equal(t, "90.00 EUR", presented["from_account"]["balance_display"])
+	require.Equal(t, "10.00 EUR", presented["to_entry"]["amount_display"])
+}
+
+func TestFieldCasingAPI(t *testing.T) {
+	user := factory.User()
+	account := factory.Account(factory.OwnedBy(user.Username))
+	account.CreatedAt = time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name          string
+		configCasing  string
+		headerCasing  string
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Default",
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.JSONEq(t, accountJSON(account, false), recorder.Body.String())
+			},
+		},
+		{
+			name:         "HeaderCamelCase",
+			headerCasing: "camelCase",
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.JSONEq(t, accountJSON(account, true), recorder.Body.String())
+			},
+		},
+		{
+			name:         "ConfigCamelCase",
+			configCasing: "camelCase",
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.JSONEq(t, accountJSON(account, true), recorder.Body.String())
+			},
+		},
+		{
+			name:         "HeaderOverridesConfig",
+			configCasing: "camelCase",
+			headerCasing: "snake_case",
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.JSONEq(t, accountJSON(account, false), recorder.Body.String())
+			},
+		},
+		{
+			name:         "UnsupportedCasing",
+			headerCasing: "kebab-case",
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireErrorBody(t, recorder.Body, util.ErrorCodeInvalidArgument)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).AnyTimes().Return(account, nil)
+
+			config := util.Config{
+				TokenSymmetricKey:   util.RandomString(32),
+				ExportSigningKey:    util.RandomString(32),
+				AccessTokenDuration: time.Minute,
+				JSONFieldCasing:     tc.configCasing,
+			}
+			server, err := NewServer(config, store, nil)
+			require.NoError(t, err)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/api/v1/accounts/%d", account.ID)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+			if tc.headerCasing != "" {
+				request.Header.Set(fieldCasingHeaderKey, tc.headerCasing)
+			}
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			require.Contains(t, recorder.Header().Values("Vary"), fieldCasingHeaderKey)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestUnsupportedConfigFieldCasing(t *testing.T) {
+	config := util.Config{
+		TokenSymmetricKey: util.RandomString(32),
+		ExportSigningKey:  util.RandomString(32),
+		JSONFieldCasing:   "PascalCase",
+	}
+	_, err := NewServer(config, nil, nil)
+	require.Error(t, err)
+}
+
+// accountJSON returns the JSON of an account, its multi-word fields being in camelCase or else in
+// snake_case, its closing time being null while it is open. The relations of its links are data, left as
+// is in every casing.
+func accountJSON(account db.Account, camelCase bool) string {
+	keys := []string{"balance_display", "created_at", "account_number", "closed_at", "org_id",
+		"held_balance", "held_balance_display", "available_balance", "available_balance_display"}
+	if camelCase {
+		keys = []string{"balanceDisplay", "createdAt", "accountNumber", "closedAt", "orgId",
+			"heldBalance", "heldBalanceDisplay", "availableBalance", "availableBalanceDisplay"}
+	}
+	available := account.Balance - account.HeldBalance
+
+	return fmt.Sprintf(`{"id":%d,"owner":%q,"balance":%d,%q:%q,"currency":%q,%q:"2026-10-16T09:30:00.000Z",%q:%q,"version":%d,%q:null,%q:%d,`+
+		`%q:%d,%q:%q,"nickname":%q,"tier":%q,%q:%d,%q:%q,"_links":{`+
+		`"self":{"href":"/api/v1/accounts/%[1]d"},"entries":{"href":"/api/v1/accounts/%[1]d/entries"},`+
+		`"transfers":{"href":"/api/v1/transfers?account_id=%[1]d"},"statement":{"href":"/api/v1/accounts/%[1]d/export{?format,from,to}","templated":true}}}`,
+		account.ID, account.Owner, account.Balance, keys[0], util.FormatAmount(account.Balance, account.Currency),
+		account.Currency, keys[1], keys[2], account.AccountNumber, account.Version, keys[3], keys[4], account.OrgID,
+		keys[5], account.HeldBalance, keys[6], util.FormatAmount(account.HeldBalance, account.Currency),
+		account.Nickname, account.Tier, keys[7], available, keys[8], util.FormatAmount(available, account.Currency))
+}
@@ -0,0 +1,37 @@
+package api
+
+import (
+	"go-backend/token"
+	"go-backend/util"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// idempotencyKeyHeaderKey is the header identifying a transfer request, so that a transfer queued while
+// the database is unavailable and sent again is queued and made once.
+const idempotencyKeyHeaderKey = "Idempotency-Key"
+
+type queuedTransferRequest struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+// This is a function that reports the status of a transfer of the authenticated user queued while the
+// database was unavailable: queued until it is processed, then its outcome.
+func (server *Server) getQueuedTransfer(ctx *gin.Context) {
+	var req queuedTransferRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	queued, err := server.service.GetQueuedTransfer(ctx, authPayload.Username, uuid.MustParse(req.ID))
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, queued)
+}
@@ -0,0 +1,214 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/testutil/factory"
+	"go-backend/util"
+	"go-backend/worker"
+	mockwk "go-backend/worker/mock"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+// errDatabaseDown is the error of a query made while the database can't be reached.
+var errDatabaseDown = &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+
+func TestCreateTransferQueuedAPI(t *testing.T) {
+	user := factory.User()
+	body := gin.H{
+		"from_account_id": 1,
+		"to_account_id":   2,
+		"amount":          10,
+		"currency":        util.USD,
+	}
+
+	testCases := []struct {
+		name          string
+		queueEnabled  bool
+		buildStub     func(store *mockdb.MockStore, distributor *mockwk.MockTaskDistributor)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:         "Queued",
+			queueEnabled: true,
+			buildStub: func(store *mockdb.MockStore, distributor *mockwk.MockTaskDistributor) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(1).Return(db.Account{}, errDatabaseDown)
+				distributor.EXPECT().DistributeTaskQueuedTransfer(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ interface{}, payload *worker.PayloadQueuedTransfer, _ ...interface{}) error {
+						require.Equal(t, user.Username, payload.Owner)
+						require.Equal(t, int64(10), payload.Amount)
+						return nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+
+				var got db.QueuedTransfer
+				err := json.Unmarshal(recorder.Body.Bytes(), &got)
+				require.NoError(t, err)
+				require.NotEqual(t, uuid.Nil, got.ID)
+				require.Equal(t, db.QueuedTransferQueued, got.Status)
+			},
+		},
+		{
+			name: "QueueDisabled",
+			buildStub: func(store *mockdb.MockStore, distributor *mockwk.MockTaskDistributor) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(1).Return(db.Account{}, errDatabaseDown)
+				distributor.EXPECT().DistributeTaskQueuedTransfer(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+				requireErrorBody(t, recorder.Body, util.ErrorCodeUnavailable)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			distributor := mockwk.NewMockTaskDistributor(ctrl)
+			tc.buildStub(store, distributor)
+
+			server := newTestServerWithConfig(t, store, distributor, func(config *util.Config) {
+				config.TransferQueueEnabled = tc.queueEnabled
+			})
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/api/v1/transfers", bytes.NewReader(data))
+			require.NoError(t, err)
+			request.Header.Set(idempotencyKeyHeaderKey, "key")
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestGetQueuedTransferAPI(t *testing.T) {
+	user := factory.User()
+	queued := db.QueuedTransfer{
+		ID:         uuid.New(),
+		Owner:      user.Username,
+		Status:     db.QueuedTransferSucceeded,
+		TransferID: pgtype.Int8{Int64: 1, Valid: true},
+		QueuedAt:   time.Now().Add(-time.Minute),
+	}
+
+	testCases := []struct {
+		name          string
+		id            string
+		buildStub     func(store *mockdb.MockStore, distributor *mockwk.MockTaskDistributor)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Processed",
+			id:   queued.ID.String(),
+			buildStub: func(store *mockdb.MockStore, distributor *mockwk.MockTaskDistributor) {
+				store.EXPECT().GetQueuedTransfer(gomock.Any(), gomock.Eq(queued.ID)).Times(1).Return(queued, nil)
+				distributor.EXPECT().QueuedTransfer(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got db.QueuedTransfer
+				err := json.Unmarshal(recorder.Body.Bytes(), &got)
+				require.NoError(t, err)
+				require.Equal(t, db.QueuedTransferSucceeded, got.Status)
+				require.Equal(t, int64(1), got.TransferID.Int64)
+			},
+		},
+		{
+			name: "StillQueued",
+			id:   queued.ID.String(),
+			buildStub: func(store *mockdb.MockStore, distributor *mockwk.MockTaskDistributor) {
+				store.EXPECT().GetQueuedTransfer(gomock.Any(), gomock.Eq(queued.ID)).Times(1).Return(db.QueuedTransfer{}, db.ErrRecordNotFound)
+				distributor.EXPECT().QueuedTransfer(gomock.Any(), gomock.Eq(queued.ID)).Times(1).
+					Return(worker.QueuedTransferTask{Payload: worker.PayloadQueuedTransfer{ID: queued.ID, Owner: user.Username}}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Body.String(), `"status":"queued"`)
+			},
+		},
+		{
+			name: "NotFound",
+			id:   queued.ID.String(),
+			buildStub: func(store *mockdb.MockStore, distributor *mockwk.MockTaskDistributor) {
+				store.EXPECT().GetQueuedTransfer(gomock.Any(), gomock.Eq(queued.ID)).Times(1).Return(db.QueuedTransfer{}, db.ErrRecordNotFound)
+				distributor.EXPECT().QueuedTransfer(gomock.Any(), gomock.Eq(queued.ID)).Times(1).Return(worker.QueuedTransferTask{}, worker.ErrTaskNotFound)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "OtherOwner",
+			id:   queued.ID.String(),
+			buildStub: func(store *mockdb.MockStore, distributor *mockwk.MockTaskDistributor) {
+				other := queued
+				other.Owner = util.RandomOwner()
+				store.EXPECT().GetQueuedTransfer(gomock.Any(), gomock.Eq(queued.ID)).Times(1).Return(other, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidID",
+			id:   "1",
+			buildStub: func(store *mockdb.MockStore, distributor *mockwk.MockTaskDistributor) {
+				store.EXPECT().GetQueuedTransfer(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			distributor := mockwk.NewMockTaskDistributor(ctrl)
+			tc.buildStub(store, distributor)
+
+			server := newTestServerWithDistributor(t, store, distributor)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/api/v1/transfers/queued/%s", tc.id)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package api
+
+import (
+	"fmt"
+	"go-backend/emvqr"
+	"go-backend/token"
+	"go-backend/util"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultReceiveQRSize is the size in pixels of the PNG images of the QR codes when none is sent.
+const defaultReceiveQRSize = 256
+
+type receiveQRURI struct {
+	AccountID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// The receiveQRRequest type holds the options of a QR code paying an account.
+// @property {int64} Amount - the amount to pay, the payer entering it when it is 0.
+// @property {string} Format - json for the payload, png for the image of the QR code encoding it.
+// @property {int} Size - the size in pixels of the PNG image.
+type receiveQRRequest struct {
+	Amount int64  `form:"amount" binding:"min=0"`
+	Format string `form:"format" binding:"omitempty,oneof=json png"`
+	Size   int    `form:"size" binding:"omitempty,min=64,max=1024"`
+}
+
+// The receiveQRResponse type is the payload of a QR code paying an account.
+type receiveQRResponse struct {
+	Payload       string `json:"payload"`
+	AccountNumber string `json:"account_number"`
+	Currency      string `json:"currency"`
+	Amount        int64  `json:"amount,omitempty"`
+}
+
+// This is a function that returns the EMVCo payload of a QR code paying an account the authenticated
+// user can see, which mobile apps scan to pay it, along with the amount to pay when one is sent. The
+// image of the QR code is returned as a PNG instead with format=png.
+func (server *Server) getReceiveQR(ctx *gin.Context) {
+	var uri receiveQRURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	var req receiveQRRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	qr, err := server.service.ReceiveQR(ctx, authPayload.Username, uri.AccountID, req.Amount)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	if req.Format == "png" {
+		size := req.Size
+		if size == 0 {
+			size = defaultReceiveQRSize
+		}
+		image, err := emvqr.PNG(qr.Payload, size)
+		if err != nil {
+			renderJSON(ctx, http.StatusInternalServerError, util.ErrorResponse(http.StatusInternalServerError, err))
+			return
+		}
+
+		ctx.Header("Content-Disposition", fmt.Sprintf("inline; filename=account-%d-qr.png", uri.AccountID))
+		ctx.Data(http.StatusOK, "image/png", image)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, receiveQRResponse{
+		Payload:       qr.Payload,
+		AccountNumber: qr.AccountNumber,
+		Currency:      qr.Currency,
+		Amount:        qr.Amount,
+	})
+}
@@ -0,0 +1,227 @@
+package api
+
+import (
+	db "go-backend/db/sqlc"
+	"go-backend/service"
+	"go-backend/token"
+	"go-backend/util"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// The `addReviewRoutes` function adds the review queue of the transfers held by the screening to the
+// admin routes.
+func (server *Server) addReviewRoutes(adminRouter *routeGroup) {
+	reviewRouter := adminRouter.Group("/reviews")
+	reviewRouter.GET("", server.listTransferReviews)
+	reviewRouter.POST("/:id/assign", server.assignTransferReview)
+	reviewRouter.POST("/:id/escalate", server.escalateTransferReview)
+	reviewRouter.POST("/:id/approve", server.approveTransferReview)
+	reviewRouter.POST("/:id/deny", server.denyTransferReview)
+}
+
+type transferReviewResponse struct {
+	ID                int64      `json:"id"`
+	FromAccountID     int64      `json:"from_account_id"`
+	ToAccountID       int64      `json:"to_account_id"`
+	Amount            int64      `json:"amount"`
+	Memo              string     `json:"memo"`
+	ExternalReference string     `json:"external_reference"`
+	HoldReason        string     `json:"hold_reason"`
+	Status            string     `json:"status"`
+	Assignee          *string    `json:"assignee"`
+	DueAt             time.Time  `json:"due_at"`
+	Overdue           bool       `json:"overdue"`
+	Note              string     `json:"note"`
+	TransferID        *int64     `json:"transfer_id"`
+	DecidedBy         *string    `json:"decided_by"`
+	DecidedAt         *time.Time `json:"decided_at"`
+	CreatedAt         time.Time  `json:"created_at"`
+}
+
+// newTransferReviewResponse renders a review, which is overdue when it is still open past its due date
+// at `now`.
+func newTransferReviewResponse(review db.TransferReview, now time.Time) transferReviewResponse {
+	res := transferReviewResponse{
+		ID:                review.ID,
+		FromAccountID:     review.FromAccountID,
+		ToAccountID:       review.ToAccountID,
+		Amount:            review.Amount,
+		Memo:              review.Memo,
+		ExternalReference: review.ExternalReference,
+		HoldReason:        review.HoldReason,
+		Status:            review.Status,
+		DueAt:             review.DueAt,
+		Overdue:           db.IsTransferReviewOpen(review.Status) && now.After(review.DueAt),
+		Note:              review.Note,
+		DecidedAt:         nullTime(review.DecidedAt),
+		CreatedAt:         review.CreatedAt,
+	}
+	if review.Assignee.Valid {
+		res.Assignee = &review.Assignee.String
+	}
+	if review.TransferID.Valid {
+		res.TransferID = &review.TransferID.Int64
+	}
+	if review.DecidedBy.Valid {
+		res.DecidedBy = &review.DecidedBy.String
+	}
+	return res
+}
+
+type listTransferReviewsRequest struct {
+	pageRequest
+	Status   string `form:"status" binding:"omitempty,oneof=pending escalated approved denied"`
+	Assignee string `form:"assignee" binding:"omitempty,alphanum"`
+	Overdue  bool   `form:"overdue"`
+}
+
+// This is a function that lists the reviews of the transfers held by the screening, the first due first,
+// optionally only those with a status, assigned to a reviewer or still open past their due date.
+func (server *Server) listTransferReviews(ctx *gin.Context) {
+	var req listTransferReviewsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	limit, offset, err := server.paginate(paginationAdmin, req.pageRequest)
+	if err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	reviews, err := server.service.ListTransferReviews(ctx, service.ListTransferReviewsParams{
+		Status:   req.Status,
+		Assignee: req.Assignee,
+		Overdue:  req.Overdue,
+		Limit:    limit,
+		Offset:   offset,
+	})
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	now := time.Now()
+	res := make([]transferReviewResponse, 0, len(reviews))
+	for _, review := range reviews {
+		res = append(res, newTransferReviewResponse(review, now))
+	}
+	renderJSON(ctx, http.StatusOK, res)
+}
+
+type transferReviewURI struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type assignTransferReviewRequest struct {
+	Assignee string `json:"assignee" binding:"omitempty,alphanum"`
+}
+
+// This is a function that assigns an open review to an admin, or unassigns it when no assignee is sent.
+func (server *Server) assignTransferReview(ctx *gin.Context) {
+	var uri transferReviewURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	var req assignTransferReviewRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	review, err := server.service.AssignTransferReview(ctx, uri.ID, req.Assignee)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, newTransferReviewResponse(review, time.Now()))
+}
+
+type escalateTransferReviewRequest struct {
+	Assignee string `json:"assignee" binding:"omitempty,alphanum"`
+	Note     string `json:"note" binding:"required,max=500"`
+}
+
+// This is a function that escalates an open review to another admin, restarting its SLA.
+func (server *Server) escalateTransferReview(ctx *gin.Context) {
+	var uri transferReviewURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	var req escalateTransferReviewRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	review, err := server.service.EscalateTransferReview(ctx, service.EscalateTransferReviewParams{
+		ID:       uri.ID,
+		Assignee: req.Assignee,
+		Note:     req.Note,
+	})
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, newTransferReviewResponse(review, time.Now()))
+}
+
+type decideTransferReviewRequest struct {
+	Note string `json:"note" binding:"max=500"`
+}
+
+type decideTransferReviewResponse struct {
+	Review   transferReviewResponse `json:"review"`
+	Transfer *db.TransferTxResult   `json:"transfer,omitempty"`
+}
+
+// This is a function that approves an open review, making the held transfer.
+func (server *Server) approveTransferReview(ctx *gin.Context) {
+	server.decideTransferReview(ctx, true)
+}
+
+// This is a function that denies an open review, refunding the held amount to the from account.
+func (server *Server) denyTransferReview(ctx *gin.Context) {
+	server.decideTransferReview(ctx, false)
+}
+
+func (server *Server) decideTransferReview(ctx *gin.Context, approve bool) {
+	var uri transferReviewURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	var req decideTransferReviewRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	result, err := server.service.DecideTransferReview(ctx, service.DecideTransferReviewParams{
+		ID:       uri.ID,
+		Reviewer: authPayload.Username,
+		Approve:  approve,
+		Note:     req.Note,
+	})
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, decideTransferReviewResponse{
+		Review:   newTransferReviewResponse(result.Review, time.Now()),
+		Transfer: result.Transfer,
+	})
+}
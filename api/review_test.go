@@ -0,0 +1,292 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go-backend/clock"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/service"
+	"go-backend/testutil/factory"
+	"go-backend/util"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateTransferHeldAPI(t *testing.T) {
+	fromUser := factory.User()
+	toUser := factory.User()
+	fromAccount := factory.Account(factory.OwnedBy(fromUser.Username))
+	toAccount := factory.Account(factory.OwnedBy(toUser.Username))
+	toAccount.ID = fromAccount.ID + 1
+	fromAccount.Currency = util.USD
+	toAccount.Currency = util.USD
+
+	review := db.TransferReview{
+		ID:            1,
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   toAccount.ID,
+		Amount:        500,
+		HoldReason:    "amount 500 is at least the review threshold of 100",
+		Status:        db.TransferReviewPending,
+		DueAt:         time.Now().Add(time.Hour),
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+	store.EXPECT().GetActiveBankParameter(gomock.Any(), gomock.Any()).Times(1).Return(db.BankParameter{}, db.ErrRecordNotFound)
+	store.EXPECT().HoldTransferTx(gomock.Any(), gomock.Any()).Times(1).Return(db.HoldTransferTxResult{Review: review}, nil)
+	store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+
+	server := newTestServer(t, store)
+	server.service.RegisterScreener(service.AmountScreener{Threshold: 100})
+
+	data, err := json.Marshal(gin.H{
+		"from_account_id": fromAccount.ID,
+		"to_account_id":   toAccount.ID,
+		"amount":          500,
+		"currency":        util.USD,
+	})
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodPost, "/api/v1/transfers", bytes.NewReader(data))
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, fromUser.Username, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusAccepted, recorder.Code)
+
+	var res transferReviewResponse
+	err = json.Unmarshal(recorder.Body.Bytes(), &res)
+	require.NoError(t, err)
+	require.Equal(t, review.ID, res.ID)
+	require.Equal(t, db.TransferReviewPending, res.Status)
+	require.False(t, res.Overdue)
+}
+
+func TestListTransferReviewsAPI(t *testing.T) {
+	admin := factory.User(factory.WithRole(util.AdminRole))
+
+	overdue := db.TransferReview{ID: 1, Status: db.TransferReviewPending, DueAt: time.Now().Add(-time.Hour)}
+	decided := db.TransferReview{
+		ID:         2,
+		Status:     db.TransferReviewApproved,
+		DueAt:      time.Now().Add(-time.Hour),
+		TransferID: pgtype.Int8{Int64: 10, Valid: true},
+		DecidedBy:  pgtype.Text{String: admin.Username, Valid: true},
+	}
+
+	testCases := []struct {
+		name          string
+		query         string
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			query: "",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListTransferReviews(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]db.TransferReview{overdue, decided}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res []transferReviewResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &res)
+				require.NoError(t, err)
+				require.Len(t, res, 2)
+
+				// only open reviews are overdue
+				require.True(t, res[0].Overdue)
+				require.Nil(t, res[0].TransferID)
+				require.False(t, res[1].Overdue)
+				require.Equal(t, int64(10), *res[1].TransferID)
+				require.Equal(t, admin.Username, *res[1].DecidedBy)
+			},
+		},
+		{
+			name:  "Filters",
+			query: "?status=escalated&assignee=" + admin.Username + "&overdue=true",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListTransferReviews(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.ListTransferReviewsParams) ([]db.TransferReview, error) {
+						require.Equal(t, pgtype.Text{String: db.TransferReviewEscalated, Valid: true}, arg.Status)
+						require.Equal(t, pgtype.Text{String: admin.Username, Valid: true}, arg.Assignee)
+						require.True(t, arg.DueBefore.Valid)
+						return []db.TransferReview{}, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:  "InvalidStatus",
+			query: "?status=held",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().ListTransferReviews(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).AnyTimes().Return(admin, nil)
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/api/v1/admin/reviews"+tc.query, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, admin.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestDecideTransferReviewAPI(t *testing.T) {
+	admin := factory.User(factory.WithRole(util.AdminRole))
+	depositor := factory.User()
+
+	now := time.Now()
+
+	testCases := []struct {
+		name          string
+		user          db.User
+		action        string
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "Approve",
+			user:   admin,
+			action: "approve",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					DecideTransferReviewTx(gomock.Any(), gomock.Eq(db.DecideTransferReviewTxParams{
+						ID:        1,
+						Status:    db.TransferReviewApproved,
+						DecidedBy: admin.Username,
+						Note:      "checked",
+						Now:       now,
+					})).
+					Times(1).
+					Return(db.DecideTransferReviewTxResult{
+						Review:   db.TransferReview{ID: 1, Status: db.TransferReviewApproved},
+						Transfer: &db.TransferTxResult{Transfer: db.Transfer{ID: 10}},
+					}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res decideTransferReviewResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &res)
+				require.NoError(t, err)
+				require.Equal(t, db.TransferReviewApproved, res.Review.Status)
+				require.Equal(t, int64(10), res.Transfer.Transfer.ID)
+			},
+		},
+		{
+			name:   "Deny",
+			user:   admin,
+			action: "deny",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					DecideTransferReviewTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.DecideTransferReviewTxParams) (db.DecideTransferReviewTxResult, error) {
+						require.Equal(t, db.TransferReviewDenied, arg.Status)
+						return db.DecideTransferReviewTxResult{Review: db.TransferReview{ID: 1, Status: db.TransferReviewDenied}}, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.NotContains(t, recorder.Body.String(), `"transfer"`)
+			},
+		},
+		{
+			name:   "AlreadyDecided",
+			user:   admin,
+			action: "approve",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					DecideTransferReviewTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.DecideTransferReviewTxResult{}, db.ErrTransferReviewClosed)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				requireErrorBody(t, recorder.Body, service.ReasonReviewClosed)
+			},
+		},
+		{
+			name:   "Forbidden",
+			user:   depositor,
+			action: "approve",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().DecideTransferReviewTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetUser(gomock.Any(), gomock.Eq(tc.user.Username)).Times(1).Return(tc.user, nil)
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			server.service.SetClock(clock.NewFake(now))
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{"note": "checked"})
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/api/v1/admin/reviews/%d/%s", 1, tc.action)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// The routeAuth type is the authentication a route requires. It is declared with the route, or the group
+// registering it, instead of by the order of the registrations, so that public and authenticated routes
+// can share a group.
+// @property {bool} anonymous - the route is public, served without an access token.
+// @property {[]string} roles - the roles of the users allowed, any when empty.
+// @property {[]string} scopes - the scopes a scoped access token needs, derived from the path of the
+// route by `requiredScopes` unless `declaredScopes`. No scopes lets any token use the route.
+type routeAuth struct {
+	anonymous      bool
+	roles          []string
+	scopes         []string
+	declaredScopes bool
+}
+
+// The authOption type changes the authentication a route or a group requires.
+type authOption func(auth *routeAuth)
+
+// The `anonymous` function makes a route public, served without an access token.
+func anonymous() authOption {
+	return func(auth *routeAuth) {
+		*auth = routeAuth{anonymous: true}
+	}
+}
+
+// The `authenticated` function makes a route require an access token, e.g. in a public group.
+func authenticated() authOption {
+	return func(auth *routeAuth) {
+		auth.anonymous = false
+	}
+}
+
+// The `requireRole` function makes a route require an access token of a user holding one of the roles.
+func requireRole(roles ...string) authOption {
+	return func(auth *routeAuth) {
+		auth.anonymous = false
+		auth.roles = roles
+	}
+}
+
+// The `requireScopes` function makes a route require the scopes from the scoped access tokens, instead of
+// those of its path. Without scopes, any token can use the route whatever its scopes.
+func requireScopes(scopes ...string) authOption {
+	return func(auth *routeAuth) {
+		auth.anonymous = false
+		auth.scopes = scopes
+		auth.declaredScopes = true
+	}
+}
+
+// The routeGroup type registers routes, like a `gin.RouterGroup`, with the middlewares enforcing the
+// authentication they require. Its routes require an access token unless declared anonymous.
+type routeGroup struct {
+	server *Server
+	router *gin.RouterGroup
+	auth   routeAuth
+}
+
+// The function creates a group registering its routes in `router`, requiring the authentication of
+// `options`.
+func (server *Server) newRouteGroup(router *gin.RouterGroup, options ...authOption) *routeGroup {
+	group := &routeGroup{server: server, router: router}
+	return group.With(options...)
+}
+
+// The `With` function returns a group registering its routes at the same path, requiring the
+// authentication of the group changed by `options`, e.g. for a single admin route.
+func (group *routeGroup) With(options ...authOption) *routeGroup {
+	auth := group.auth
+	for _, option := range options {
+		option(&auth)
+	}
+	return &routeGroup{server: group.server, router: group.router, auth: auth}
+}
+
+// The `Group` function returns a group registering its routes under `path`, requiring the authentication
+// of the group changed by `options`.
+func (group *routeGroup) Group(path string, options ...authOption) *routeGroup {
+	subgroup := group.With(options...)
+	subgroup.router = group.router.Group(path)
+	return subgroup
+}
+
+func (group *routeGroup) GET(path string, handlers ...gin.HandlerFunc) {
+	group.router.GET(path, group.handlers(handlers)...)
+}
+
+func (group *routeGroup) POST(path string, handlers ...gin.HandlerFunc) {
+	group.router.POST(path, group.handlers(handlers)...)
+}
+
+func (group *routeGroup) PUT(path string, handlers ...gin.HandlerFunc) {
+	group.router.PUT(path, group.handlers(handlers)...)
+}
+
+func (group *routeGroup) DELETE(path string, handlers ...gin.HandlerFunc) {
+	group.router.DELETE(path, group.handlers(handlers)...)
+}
+
+// The `handlers` function returns the handlers of a route of the group, preceded by the middlewares
+// enforcing the authentication it requires.
+func (group *routeGroup) handlers(handlers []gin.HandlerFunc) []gin.HandlerFunc {
+	if group.auth.anonymous {
+		return handlers
+	}
+
+	server := group.server
+	chain := []gin.HandlerFunc{
+		authMiddleware(server.tokenMaker),
+		server.impersonationMiddleware(),
+		sessionActivityMiddleware(server.service),
+		organizationMiddleware(),
+	}
+	if group.auth.declaredScopes {
+		chain = append(chain, declaredScopeMiddleware(group.auth.scopes))
+	} else {
+		chain = append(chain, scopeMiddleware())
+	}
+	chain = append(chain, server.usageMiddleware())
+	if len(group.auth.roles) > 0 {
+		chain = append(chain, roleMiddleware(server.store, group.auth.roles...))
+	}
+	return append(chain, handlers...)
+}
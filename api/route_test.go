@@ -0,0 +1,112 @@
+package api
+
+import (
+	mockdb "go-backend/db/mock"
+	"go-backend/testutil/factory"
+	"go-backend/util"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouteGroupAuth(t *testing.T) {
+	depositor := factory.User(factory.WithRole(util.DepositorRole))
+	admin := factory.User(factory.WithRole(util.AdminRole))
+
+	testCases := []struct {
+		name       string
+		path       string
+		username   string
+		buildStubs func(store *mockdb.MockStore)
+		status     int
+	}{
+		{
+			name:   "AnonymousWithoutToken",
+			path:   "/mixed/public",
+			status: http.StatusOK,
+		},
+		{
+			name:   "PrivateWithoutToken",
+			path:   "/mixed/private",
+			status: http.StatusUnauthorized,
+		},
+		{
+			name:     "PrivateWithToken",
+			path:     "/mixed/private",
+			username: depositor.Username,
+			status:   http.StatusOK,
+		},
+		{
+			name:     "RoleDenied",
+			path:     "/mixed/admin",
+			username: depositor.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(depositor.Username)).Times(1).Return(depositor, nil)
+			},
+			status: http.StatusForbidden,
+		},
+		{
+			name:     "RoleAllowed",
+			path:     "/mixed/admin",
+			username: admin.Username,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).Times(1).Return(admin, nil)
+			},
+			status: http.StatusOK,
+		},
+		{
+			name:   "AuthenticatedInPublicGroup",
+			path:   "/public/private",
+			status: http.StatusUnauthorized,
+		},
+		{
+			name:   "PublicGroup",
+			path:   "/public/open",
+			status: http.StatusOK,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			if tc.buildStubs != nil {
+				tc.buildStubs(store)
+			}
+
+			server := newTestServer(t, store)
+			ok := func(ctx *gin.Context) {
+				ctx.JSON(http.StatusOK, gin.H{})
+			}
+
+			routes := server.newRouteGroup(server.router.Group(""))
+			mixedRouter := routes.Group("/mixed")
+			mixedRouter.With(anonymous()).GET("/public", ok)
+			mixedRouter.GET("/private", ok)
+			mixedRouter.With(requireRole(util.AdminRole)).GET("/admin", ok)
+
+			publicRouter := routes.Group("/public", anonymous())
+			publicRouter.GET("/open", ok)
+			publicRouter.With(authenticated()).GET("/private", ok)
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, tc.path, nil)
+			require.NoError(t, err)
+
+			if tc.username != "" {
+				addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.username, time.Minute)
+			}
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, tc.status, recorder.Code)
+		})
+	}
+}
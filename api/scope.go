@@ -0,0 +1,111 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"go-backend/token"
+	"go-backend/util"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	scopeResourceAccounts      = "accounts"
+	scopeResourceTransfers     = "transfers"
+	scopeResourceNotifications = "notifications"
+)
+
+// scopeResources are the resources whose scopes the routes require, by the first segment of their v1
+// path. The routes of the other segments, e.g. the admin ones, are refused to the tokens with scopes,
+// unless they declare the scopes they require with `requireScopes`.
+var scopeResources = map[string][]string{
+	"accounts":            {scopeResourceAccounts},
+	"account_invitations": {scopeResourceAccounts},
+	"users":               {scopeResourceAccounts},
+	"jobs":                {scopeResourceAccounts},
+	"cards":               {scopeResourceAccounts},
+	"cheque_deposits":     {scopeResourceAccounts},
+	"transfers":           {scopeResourceTransfers},
+	"beneficiaries":       {scopeResourceTransfers},
+	"payment_requests":    {scopeResourceTransfers},
+	"payment_links":       {scopeResourceTransfers},
+	"mandates":            {scopeResourceTransfers},
+	"standing_orders":     {scopeResourceTransfers},
+	"external_transfers":  {scopeResourceTransfers},
+	"pending_transfers":   {scopeResourceTransfers},
+	"notifications":       {scopeResourceNotifications},
+	"dashboard":           {scopeResourceAccounts, scopeResourceTransfers},
+	"graphql":             {scopeResourceAccounts, scopeResourceTransfers},
+}
+
+// The `requiredScopes` function returns the scopes a token needs for a request to the route at `path`,
+// a v1 path, with `method`, and whether tokens with scopes can use the route at all.
+func requiredScopes(method string, path string) ([]string, bool) {
+	segment := strings.TrimPrefix(path, "/api/"+apiVersions[0].name+"/")
+	if i := strings.Index(segment, "/"); i >= 0 {
+		segment = segment[:i]
+	}
+	resources, ok := scopeResources[segment]
+	if !ok {
+		return nil, false
+	}
+
+	access := "write"
+	if method == http.MethodGet || method == http.MethodHead || readOnlyPostRoutes[path] {
+		access = "read"
+	}
+
+	scopes := make([]string, len(resources))
+	for i, resource := range resources {
+		scopes[i] = resource + ":" + access
+	}
+	return scopes, true
+}
+
+// The `scopeMiddleware` function refuses with 403 the requests the scopes of their access token don't
+// allow, e.g. the transfers of a read-only integration, the scopes being derived from the path of the
+// route. The tokens without scopes are allowed every request. It must be registered after
+// `authMiddleware`.
+func scopeMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+		if !authPayload.Scoped() {
+			ctx.Next()
+			return
+		}
+
+		scopes, ok := requiredScopes(ctx.Request.Method, v1Path(ctx.FullPath()))
+		if !ok {
+			err := errors.New("the route can't be used with a scoped access token")
+			abortWithJSON(ctx, http.StatusForbidden, util.ErrorResponse(http.StatusForbidden, util.WithErrorCode(util.ErrorCodeInsufficientScope, err)))
+			return
+		}
+		checkScopes(ctx, authPayload, scopes)
+	}
+}
+
+// The `declaredScopeMiddleware` function is the `scopeMiddleware` of the routes declaring the scopes they
+// require, e.g. the moves between accounts, which move money. Without scopes, it allows every token.
+func declaredScopeMiddleware(scopes []string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		checkScopes(ctx, ctx.MustGet(authorizationPayloadKey).(*token.Payload), scopes)
+	}
+}
+
+// The `checkScopes` function refuses the request with 403 unless its access token has every scope, or no
+// scopes at all.
+func checkScopes(ctx *gin.Context, authPayload *token.Payload, scopes []string) {
+	if authPayload.Scoped() {
+		for _, scope := range scopes {
+			if !authPayload.HasScope(scope) {
+				err := fmt.Errorf("the access token doesn't have the %s scope", scope)
+				abortWithJSON(ctx, http.StatusForbidden, util.ErrorResponse(http.StatusForbidden, util.WithErrorCode(util.ErrorCodeInsufficientScope, err)))
+				return
+			}
+		}
+	}
+
+	ctx.Next()
+}
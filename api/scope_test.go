@@ -0,0 +1,184 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	mockdb "go-backend/db/mock"
+	"go-backend/testutil/factory"
+	"go-backend/token"
+	"go-backend/util"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func addScopedAuthorization(t *testing.T, request *http.Request, tokenMaker token.Maker, username string, scopes []string) {
+	accessToken, _, err := tokenMaker.CreateToken(token.Claims{Username: username, Scopes: scopes}, time.Minute)
+	require.NoError(t, err)
+	request.Header.Set(authorizationHeaderKey, fmt.Sprintf("%s %s", authorizationTypeBearer, accessToken))
+}
+
+func TestRequiredScopes(t *testing.T) {
+	scopes, ok := requiredScopes(http.MethodGet, "/api/v1/accounts/:id")
+	require.True(t, ok)
+	require.Equal(t, []string{token.ScopeAccountsRead}, scopes)
+
+	scopes, ok = requiredScopes(http.MethodPost, "/api/v1/transfers")
+	require.True(t, ok)
+	require.Equal(t, []string{token.ScopeTransfersWrite}, scopes)
+
+	scopes, ok = requiredScopes(http.MethodPost, "/api/v1/accounts/batch_get")
+	require.True(t, ok)
+	require.Equal(t, []string{token.ScopeAccountsRead}, scopes)
+
+	scopes, ok = requiredScopes(http.MethodPost, "/api/v1/graphql")
+	require.True(t, ok)
+	require.Equal(t, []string{token.ScopeAccountsRead, token.ScopeTransfersRead}, scopes)
+
+	_, ok = requiredScopes(http.MethodGet, "/api/v1/admin/users")
+	require.False(t, ok)
+}
+
+func TestScopeMiddleware(t *testing.T) {
+	user := factory.User()
+	account := factory.Account(factory.OwnedBy(user.Username))
+	readOnly := []string{token.ScopeAccountsRead, token.ScopeTransfersRead}
+
+	testCases := []struct {
+		name          string
+		method        string
+		url           string
+		body          interface{}
+		scopes        []string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "ReadAllowed",
+			method: http.MethodGet,
+			url:    fmt.Sprintf("/api/v1/accounts/%d", account.ID),
+			scopes: readOnly,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "TransferDenied",
+			method: http.MethodPost,
+			url:    "/api/v1/transfers",
+			body: map[string]interface{}{
+				"from_account_id": account.ID,
+				"to_account_id":   account.ID + 1,
+				"amount":          10,
+				"currency":        account.Currency,
+			},
+			scopes: readOnly,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				requireErrorBody(t, recorder.Body, util.ErrorCodeInsufficientScope)
+				require.Contains(t, recorder.Body.String(), token.ScopeTransfersWrite)
+			},
+		},
+		{
+			// the moves between accounts declare the scope of the transfers, as they move money
+			name:   "MoveDenied",
+			method: http.MethodPost,
+			url:    fmt.Sprintf("/api/v1/accounts/%d/move", account.ID),
+			scopes: []string{token.ScopeAccountsRead, token.ScopeAccountsWrite, token.ScopeTransfersRead},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				requireErrorBody(t, recorder.Body, util.ErrorCodeInsufficientScope)
+				require.Contains(t, recorder.Body.String(), token.ScopeTransfersWrite)
+			},
+		},
+		{
+			name:   "OtherResourceDenied",
+			method: http.MethodGet,
+			url:    "/api/v1/notifications",
+			scopes: readOnly,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListNotifications(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				requireErrorBody(t, recorder.Body, util.ErrorCodeInsufficientScope)
+			},
+		},
+		{
+			name:   "AdminDenied",
+			method: http.MethodGet,
+			url:    "/api/v1/admin/users",
+			scopes: token.Scopes,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				requireErrorBody(t, recorder.Body, util.ErrorCodeInsufficientScope)
+			},
+		},
+		{
+			name:   "Unscoped",
+			method: http.MethodGet,
+			url:    fmt.Sprintf("/api/v1/accounts/%d", account.ID),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "UsageAllowed",
+			method: http.MethodGet,
+			url:    "/api/v1/usage",
+			scopes: []string{token.ScopeNotificationsRead},
+			buildStubs: func(store *mockdb.MockStore) {
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			var body bytes.Buffer
+			if tc.body != nil {
+				require.NoError(t, json.NewEncoder(&body).Encode(tc.body))
+			}
+			request, err := http.NewRequest(tc.method, tc.url, &body)
+			require.NoError(t, err)
+
+			addScopedAuthorization(t, request, server.tokenMaker, user.Username, tc.scopes)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
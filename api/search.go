@@ -0,0 +1,95 @@
+package api
+
+import (
+	db "go-backend/db/sqlc"
+	"go-backend/token"
+	"go-backend/util"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type searchEntriesRequest struct {
+	Query string `form:"q" binding:"required"`
+	pageRequest
+}
+
+// The searchResultResponse type is an entry matching a search, with the transfer it was made for.
+// @property {map[string]string} highlights - the matched fields among memo, counterparty and category,
+// their matched words wrapped in <mark> tags. The text of the fields is HTML escaped, so the tags are the
+// only markup.
+type searchResultResponse struct {
+	Entry        db.Entry             `json:"entry"`
+	Transfer     db.Transfer          `json:"transfer"`
+	Currency     string               `json:"currency"`
+	Counterparty counterpartyResponse `json:"counterparty"`
+	Category     string               `json:"category"`
+	Highlights   map[string]string    `json:"highlights"`
+}
+
+func newSearchResultResponse(row db.SearchAccountEntriesRow) searchResultResponse {
+	res := searchResultResponse{
+		Entry:    row.Entry,
+		Transfer: row.Transfer,
+		Currency: row.Currency,
+		Counterparty: counterpartyResponse{
+			AccountID: row.Transfer.FromAccountID,
+			Username:  row.CounterpartyUsername,
+			FullName:  row.CounterpartyFullName,
+			Nickname:  row.CounterpartyNickname,
+		},
+		Category:   row.Category,
+		Highlights: map[string]string{},
+	}
+	if row.Transfer.FromAccountID == row.Entry.AccountID {
+		res.Counterparty.AccountID = row.Transfer.ToAccountID
+	}
+
+	highlights := map[string]string{
+		"memo":         row.MemoHighlight,
+		"counterparty": row.CounterpartyHighlight,
+		"category":     row.CategoryHighlight,
+	}
+	for field, highlight := range highlights {
+		if highlight != "" {
+			res.Highlights[field] = highlight
+		}
+	}
+	return res
+}
+
+// This is a function that searches the entries of an account owned by the authenticated user, matching
+// the memo, counterparty and category of the transfers they were made for against the `q` web search
+// query. The best matches come first, each with the transfer it was made for and the matched fields
+// highlighted.
+func (server *Server) searchAccountEntries(ctx *gin.Context) {
+	var uri getAccountRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+	var req searchEntriesRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	limit, offset, err := server.paginate(paginationEntries, req.pageRequest)
+	if err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	results, err := server.service.SearchEntries(ctx, authPayload.Username, uri.ID, req.Query, limit, offset)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	res := make([]searchResultResponse, 0, len(results))
+	for _, result := range results {
+		res = append(res, newSearchResultResponse(result))
+	}
+	renderJSON(ctx, http.StatusOK, res)
+}
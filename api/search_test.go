@@ -0,0 +1,93 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/testutil/factory"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchAccountEntriesAPI(t *testing.T) {
+	user := factory.User()
+	account := factory.Account(factory.OwnedBy(user.Username))
+	counterparty := factory.Account()
+	transfer := db.Transfer{ID: 7, FromAccountID: account.ID, ToAccountID: counterparty.ID, Amount: 10, Memo: "Rent for March"}
+	row := db.SearchAccountEntriesRow{
+		Entry:                db.Entry{ID: 3, AccountID: account.ID, Amount: -10},
+		Transfer:             transfer,
+		CounterpartyUsername: counterparty.Owner,
+		Category:             "housing",
+		Currency:             account.Currency,
+		MemoHighlight:        "<mark>Rent</mark> for March",
+	}
+
+	testCases := []struct {
+		name          string
+		query         string
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			query: "rent",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().SearchAccountEntries(gomock.Any(), gomock.Any()).Times(1).Return([]db.SearchAccountEntriesRow{row}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got []searchResultResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &got)
+				require.NoError(t, err)
+				require.Len(t, got, 1)
+				require.Equal(t, transfer.ID, got[0].Transfer.ID)
+				// the counterparty of a debit is the recipient
+				require.Equal(t, counterparty.ID, got[0].Counterparty.AccountID)
+				// only the matched fields are highlighted
+				require.Equal(t, map[string]string{"memo": row.MemoHighlight}, got[0].Highlights)
+			},
+		},
+		{
+			name: "MissingQuery",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().SearchAccountEntries(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			path := fmt.Sprintf("/api/v1/accounts/%d/search?q=%s", account.ID, url.QueryEscape(tc.query))
+			request, err := http.NewRequest(http.MethodGet, path, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
@@ -1,10 +1,23 @@
 package api
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"go-backend/captcha"
 	db "go-backend/db/sqlc"
+	"go-backend/diagnostics"
+	"go-backend/doc/changelog"
+	"go-backend/graph"
+	"go-backend/oauth"
+	"go-backend/service"
+	"go-backend/signing"
 	"go-backend/token"
+	"go-backend/usage"
 	"go-backend/util"
+	"go-backend/worker"
+	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
@@ -25,46 +38,178 @@ type Server struct {
 	config     util.Config
 	store      db.Store
 	tokenMaker token.Maker
+	service    *service.Service
+	metrics    *metrics
+	pagination map[string]util.PaginationPolicy
+	graph      *graph.Schema
+	timeouts   map[string]time.Duration
+	changelog  changelog.Changelog
+	readiness  *util.Readiness
+	leadership worker.Leadership
+	debug      http.Handler
+	// identityProviders are the providers users can log in with, by name
+	identityProviders oauth.Providers
+	// captcha checks that the users signing up solved the CAPTCHA
+	captcha captcha.Verifier
+	// nonces are the signatures of the signed requests already served
+	nonces     signing.NonceStore
 	router     *gin.Engine
+	httpServer *http.Server
 }
 
-// The `Start` function is a method of the `Server` struct that starts the server by running the router
+// The `Start` function is a method of the `Server` struct that starts the server by serving the router
 // on a specified address. It takes in an `address` string parameter and returns an error if there is
-// any issue starting the server. The `server.router.Run(address)` method is called to start the server
-// on the specified address.
+// any issue starting the server. Once `Shutdown` is called it returns `http.ErrServerClosed`.
 func (server *Server) Start(address string) error {
-	return server.router.Run(address)
+	server.httpServer = &http.Server{
+		Addr:    address,
+		Handler: server.router,
+	}
+	return server.httpServer.ListenAndServe()
+}
+
+// The `Drain` function makes the readiness probe fail so that the load balancer stops routing new
+// requests to the server, which keeps serving until `Shutdown` is called.
+func (server *Server) Drain() {
+	server.readiness.Drain()
+}
+
+// The `SetLeadership` function makes the server a standby, rejecting the writes, whenever the instance
+// isn't the leader. Every instance serves writes when it is never called.
+func (server *Server) SetLeadership(leadership worker.Leadership) {
+	server.leadership = leadership
+}
+
+// The `SetDiagnostics` function reports the diagnostics of `collector` at /api/v1/admin/debug/vars.
+// Only the runtime statistics are reported when it is never called.
+func (server *Server) SetDiagnostics(collector *diagnostics.Collector) {
+	server.debug = http.StripPrefix("/api/v1/admin", diagnostics.NewHandler(collector))
+}
+
+// The `SetUsageMeter` function counts the calls of the users to the API with `meter`, e.g. in Redis so
+// that the quotas of their API plans hold across the replicas. They are counted in memory when it is
+// never called.
+func (server *Server) SetUsageMeter(meter usage.Meter) {
+	server.service.SetUsageMeter(meter)
+}
+
+// The `SetNonceStore` function remembers the signatures of the signed requests with `store`, e.g. in
+// Redis so that a request can't be replayed against another replica. They are remembered in memory when
+// it is never called.
+func (server *Server) SetNonceStore(store signing.NonceStore) {
+	server.nonces = store
+}
+
+// The `Shutdown` function stops accepting new connections and waits for in-flight requests (such as
+// transfers) to finish, or for the context to expire, before returning.
+func (server *Server) Shutdown(ctx context.Context) error {
+	if server.httpServer == nil {
+		return nil
+	}
+	return server.httpServer.Shutdown(ctx)
 }
 
 // The function creates a new server instance with a given database store and sets up a router with
 // routes.
-func NewServer(config util.Config, store db.Store) (*Server, error) {
-	tokenMaker, err := token.NewPasetoMaker(config.TokenSymmetricKey)
+func NewServer(config util.Config, store db.Store, taskDistributor worker.TaskDistributor) (*Server, error) {
+	tokenMaker, err := token.NewMaker(token.MakerConfig{
+		Kind:             config.TokenKind,
+		SymmetricKey:     config.TokenSymmetricKey,
+		PrivateKey:       config.TokenPrivateKey,
+		PublicKey:        config.TokenPublicKey,
+		KeyID:            config.TokenKeyID,
+		VerificationKeys: config.TokenVerificationKeys,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("cannot create token maker: %w", err)
 	}
 
+	if config.ExportSigningKey == "" {
+		return nil, errors.New("EXPORT_SIGNING_KEY is required to sign the download urls of the export jobs")
+	}
+
+	pagination, err := newPaginationPolicies(config)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load pagination policies: %w", err)
+	}
+
+	casing, err := parseFieldCasing(config.JSONFieldCasing, snakeCase)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load JSON field casing: %w", err)
+	}
+
+	apiChangelog, err := changelog.Load()
+	if err != nil {
+		return nil, err
+	}
+	deprecations, err := newDeprecations(apiChangelog)
+	if err != nil {
+		return nil, err
+	}
+
+	identityProviders, err := newIdentityProviders(config)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load identity providers: %w", err)
+	}
+
+	apiPlans, err := util.ParseAPIPlans(config.APIPlans)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load API plans: %w", err)
+	}
+
+	captchaVerifier, err := captcha.NewVerifier(config)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create CAPTCHA verifier: %w", err)
+	}
+
+	// the users and accounts are scoped to the organization of the authenticated user of each request
+	store = db.NewOrganizationStore(store)
+
 	server := &Server{
 		config:     config,
 		store:      store,
 		tokenMaker: tokenMaker,
+		service:    service.New(config, store, tokenMaker, taskDistributor),
+		metrics:    newMetrics(),
+		pagination: pagination,
+		changelog:  apiChangelog,
+		readiness:  &util.Readiness{},
+		debug:      http.StripPrefix("/api/v1/admin", diagnostics.NewHandler(diagnostics.NewCollector(nil))),
+
+		identityProviders: identityProviders,
+		captcha:           captchaVerifier,
+		nonces:            signing.NewMemoryNonceStore(),
 	}
+	server.service.SetAPIPlans(apiPlans)
+	server.graph, err = graph.New(server.service, newGraphQLPagination(pagination))
+	if err != nil {
+		return nil, fmt.Errorf("cannot create GraphQL schema: %w", err)
+	}
+
 	router := gin.Default()
+	// the handlers pass the gin context on to the store, which must then carry the deadline of the request
+	router.ContextWithFallback = true
+	router.Use(trackingMiddleware(), server.metrics.metricsMiddleware(), compressionMiddleware(), localeMiddleware(), serializationMiddleware(casing), server.bodyLimitMiddleware(), server.timeoutMiddleware(), server.standbyMiddleware(), deprecationMiddleware(deprecations))
+	router.GET("/metrics", server.metrics.metricsHandler())
+	router.GET("/ready", gin.WrapH(server.readiness))
+	router.GET("/version", gin.WrapF(util.VersionHandler))
 
 	// register custom validators
 	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
 		v.RegisterValidation("currency", validCurrency)
+		v.RegisterValidation("account_number", validAccountNumber)
+		v.RegisterTagNameFunc(requestFieldName)
 	}
 
-	// routes
-	apiRouter := router.Group("/api/v1")
-	server.addUserRoutes(apiRouter)
-	server.addTokenRoutes(apiRouter)
+	// routes, the same in every version
+	for _, version := range apiVersions {
+		server.addVersionRoutes(router.Group("/api/"+version.name, version.middleware...))
+	}
 
-	// auth routes
-	apiRouter.Use(authMiddleware(server.tokenMaker))
-	server.addAccountRoutes(apiRouter)
-	server.addTransferRoutes(apiRouter)
+	server.timeouts, err = newRouteTimeouts(config, router.Routes())
+	if err != nil {
+		return nil, fmt.Errorf("cannot load route timeouts: %w", err)
+	}
 
 	server.router = router
 	return server, nil
@@ -0,0 +1,141 @@
+package api
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go-backend/service"
+	"go-backend/signing"
+	"go-backend/token"
+	"go-backend/util"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	signatureHeaderKey          = "X-Signature"
+	signatureKeyIDHeaderKey     = "X-Signature-Key-Id"
+	signatureTimestampHeaderKey = "X-Signature-Timestamp"
+	// signingKeyIDKey is the key of the id of the signing key a request was signed with in the context.
+	signingKeyIDKey = "signing_key_id"
+)
+
+var (
+	errSignatureRequired = errors.New("the request must be signed with a signing key, in the X-Signature, X-Signature-Key-Id and X-Signature-Timestamp headers")
+	// errSignatureInvalid wraps the errors of the signatures that don't prove the request was made by the
+	// holder of the signing key.
+	errSignatureInvalid = errors.New("invalid signature")
+)
+
+// The `signatureMiddleware` function verifies the HMAC signature of the high-risk requests, e.g. the
+// creation of transfers, against the signing key of their user. The signature is required once the user
+// is a machine client with an active signing key, and verified whenever it is sent. A signature is only
+// accepted once, within 5 minutes of its timestamp, so that a captured request can't be replayed. The id
+// of the key is set in the context under `signingKeyIDKey`. It must be registered after `authMiddleware`.
+func (server *Server) signatureMiddleware() gin.HandlerFunc {
+	return server.checkSignature(true)
+}
+
+// The `optionalSignatureMiddleware` function verifies the signature of a request like
+// `signatureMiddleware`, but never requires one: the handlers accept another proof, e.g. the password
+// of the user, from the requests that aren't signed.
+func (server *Server) optionalSignatureMiddleware() gin.HandlerFunc {
+	return server.checkSignature(false)
+}
+
+func (server *Server) checkSignature(enforce bool) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+		signature := ctx.GetHeader(signatureHeaderKey)
+		if signature == "" {
+			if !enforce {
+				ctx.Next()
+				return
+			}
+			required, err := server.service.SignatureRequired(ctx, authPayload.Username)
+			if err != nil {
+				ctx.Abort()
+				writeError(ctx, err)
+				return
+			}
+			if required {
+				abortWithJSON(ctx, http.StatusUnauthorized, util.ErrorResponse(http.StatusUnauthorized, util.WithErrorCode(util.ErrorCodeSignatureRequired, errSignatureRequired)))
+				return
+			}
+			ctx.Next()
+			return
+		}
+
+		keyID, err := server.verifySignature(ctx, authPayload.Username, signature)
+		if err == nil {
+			// the signatures are remembered for as long as their timestamp is accepted
+			var first bool
+			first, err = server.nonces.Claim(ctx, strconv.FormatInt(keyID, 10)+":"+signature, 2*signing.Tolerance)
+			if err != nil {
+				ctx.Error(err)
+				abortWithJSON(ctx, http.StatusServiceUnavailable, util.ErrorResponse(http.StatusServiceUnavailable, err))
+				return
+			}
+			if !first {
+				err = fmt.Errorf("%w: it was already used, every request must be signed anew", errSignatureInvalid)
+			}
+		}
+		if err != nil {
+			if errors.Is(err, errSignatureInvalid) {
+				abortWithJSON(ctx, http.StatusUnauthorized, util.ErrorResponse(http.StatusUnauthorized, util.WithErrorCode(util.ErrorCodeSignatureInvalid, err)))
+				return
+			}
+			ctx.Abort()
+			writeError(ctx, err)
+			return
+		}
+
+		ctx.Set(signingKeyIDKey, keyID)
+		ctx.Next()
+	}
+}
+
+// The `verifySignature` function checks that `signature` was made over the request with the active
+// signing key of the user it names, and returns the id of the key. The body of the request is read and
+// replaced for the handlers to read it again.
+func (server *Server) verifySignature(ctx *gin.Context, username string, signature string) (int64, error) {
+	keyID, err := strconv.ParseInt(ctx.GetHeader(signatureKeyIDHeaderKey), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: the X-Signature-Key-Id header must be the id of a signing key", errSignatureInvalid)
+	}
+	timestamp, err := strconv.ParseInt(ctx.GetHeader(signatureTimestampHeaderKey), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: the X-Signature-Timestamp header must be a Unix time", errSignatureInvalid)
+	}
+	if !signing.Fresh(timestamp, time.Now()) {
+		return 0, fmt.Errorf("%w: its timestamp is more than %v away from the time of the server", errSignatureInvalid, signing.Tolerance)
+	}
+
+	key, err := server.service.SigningKey(ctx, username, keyID)
+	if err != nil {
+		if service.ErrorCode(err) == service.CodeUnauthenticated {
+			return 0, fmt.Errorf("%w: %v", errSignatureInvalid, err)
+		}
+		return 0, err
+	}
+
+	var body []byte
+	if ctx.Request.Body != nil {
+		body, err = io.ReadAll(ctx.Request.Body)
+		if err != nil {
+			return 0, err
+		}
+		ctx.Request.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	message := signing.Message(timestamp, ctx.Request.Method, ctx.Request.URL.RequestURI(), body)
+	if !util.VerifySignature(key.Secret, message, signature) {
+		return 0, fmt.Errorf("%w: it doesn't match the request", errSignatureInvalid)
+	}
+	return keyID, nil
+}
@@ -0,0 +1,260 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/signing"
+	"go-backend/testutil/factory"
+	"go-backend/util"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+// signRequest signs the request with `key` as of `at`.
+func signRequest(t *testing.T, request *http.Request, key db.SigningKey, body []byte, at time.Time) {
+	message := signing.Message(at.Unix(), request.Method, request.URL.RequestURI(), body)
+	request.Header.Set(signatureHeaderKey, util.Sign(key.Secret, message))
+	request.Header.Set(signatureKeyIDHeaderKey, strconv.FormatInt(key.ID, 10))
+	request.Header.Set(signatureTimestampHeaderKey, strconv.FormatInt(at.Unix(), 10))
+}
+
+func TestSignatureMiddleware(t *testing.T) {
+	user := factory.User()
+	key := db.SigningKey{ID: 7, Username: user.Username, Secret: util.RandomString(64), CreatedAt: time.Now()}
+	// the body fails the validation of the handler, which is only reached once the signature is verified
+	body := []byte(`{"amount": 10}`)
+
+	testCases := []struct {
+		name          string
+		sign          func(t *testing.T, request *http.Request)
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "UnsignedWithoutKey",
+			sign: func(t *testing.T, request *http.Request) {},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().CountActiveSigningKeys(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(int64(0), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "SignatureRequired",
+			sign: func(t *testing.T, request *http.Request) {},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().CountActiveSigningKeys(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(int64(1), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				requireErrorBody(t, recorder.Body, util.ErrorCodeSignatureRequired)
+			},
+		},
+		{
+			name: "Signed",
+			sign: func(t *testing.T, request *http.Request) {
+				signRequest(t, request, key, body, time.Now())
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetSigningKey(gomock.Any(), gomock.Eq(key.ID)).Times(1).Return(key, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireErrorBody(t, recorder.Body, util.ErrorCodeValidationFailed)
+			},
+		},
+		{
+			name: "OtherBody",
+			sign: func(t *testing.T, request *http.Request) {
+				signRequest(t, request, key, []byte(`{"amount": 10000}`), time.Now())
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetSigningKey(gomock.Any(), gomock.Eq(key.ID)).Times(1).Return(key, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				requireErrorBody(t, recorder.Body, util.ErrorCodeSignatureInvalid)
+			},
+		},
+		{
+			name: "Expired",
+			sign: func(t *testing.T, request *http.Request) {
+				signRequest(t, request, key, body, time.Now().Add(-10*time.Minute))
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetSigningKey(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				requireErrorBody(t, recorder.Body, util.ErrorCodeSignatureInvalid)
+			},
+		},
+		{
+			name: "RevokedKey",
+			sign: func(t *testing.T, request *http.Request) {
+				signRequest(t, request, key, body, time.Now())
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				revoked := key
+				revoked.RevokedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+				store.EXPECT().GetSigningKey(gomock.Any(), gomock.Eq(key.ID)).Times(1).Return(revoked, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				requireErrorBody(t, recorder.Body, util.ErrorCodeSignatureInvalid)
+			},
+		},
+		{
+			name: "KeyOfAnotherUser",
+			sign: func(t *testing.T, request *http.Request) {
+				signRequest(t, request, key, body, time.Now())
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				other := key
+				other.Username = util.RandomOwner()
+				store.EXPECT().GetSigningKey(gomock.Any(), gomock.Eq(key.ID)).Times(1).Return(other, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				requireErrorBody(t, recorder.Body, util.ErrorCodeSignatureInvalid)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// the user is a machine client, with an API plan
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().
+				GetUserAPIPlan(gomock.Any(), gomock.Eq(user.Username)).
+				AnyTimes().
+				Return(db.UserApiPlan{Username: user.Username, Plan: "basic"}, nil)
+			tc.buildStub(store)
+
+			server := newTestServerWithConfig(t, store, nil, func(config *util.Config) {
+				config.APIPlans = "basic=1000"
+			})
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodPost, "/api/v1/transfers", bytes.NewReader(body))
+			require.NoError(t, err)
+			tc.sign(t, request)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestSignatureReplay(t *testing.T) {
+	user := factory.User()
+	key := db.SigningKey{ID: 7, Username: user.Username, Secret: util.RandomString(64), CreatedAt: time.Now()}
+	body := []byte(`{"amount": 10}`)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetSigningKey(gomock.Any(), gomock.Eq(key.ID)).Times(2).Return(key, nil)
+	server := newTestServer(t, store)
+
+	signedAt := time.Now()
+	for i, status := range []int{http.StatusBadRequest, http.StatusUnauthorized} {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodPost, "/api/v1/transfers", bytes.NewReader(body))
+		require.NoError(t, err)
+		signRequest(t, request, key, body, signedAt)
+
+		addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+		server.router.ServeHTTP(recorder, request)
+		require.Equal(t, status, recorder.Code, i)
+	}
+}
+
+func TestSigningKeysAPI(t *testing.T) {
+	user, password := factory.UserWithPassword(t)
+	key := db.SigningKey{ID: 7, Username: user.Username, Secret: util.RandomString(64), CreatedAt: time.Now()}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(4).Return(user, nil)
+	store.EXPECT().
+		CreateSigningKey(gomock.Any(), gomock.Any()).
+		Times(2).
+		DoAndReturn(func(_ interface{}, arg db.CreateSigningKeyParams) (db.SigningKey, error) {
+			require.Equal(t, user.Username, arg.Username)
+			require.Len(t, arg.Secret, 64)
+			return key, nil
+		})
+	store.EXPECT().GetSigningKey(gomock.Any(), gomock.Eq(key.ID)).Times(1).Return(key, nil)
+	store.EXPECT().ListSigningKeys(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return([]db.SigningKey{key}, nil)
+	store.EXPECT().
+		RevokeSigningKey(gomock.Any(), gomock.Eq(db.RevokeSigningKeyParams{ID: key.ID, Username: user.Username})).
+		Times(1).
+		Return(db.SigningKey{}, db.ErrRecordNotFound)
+
+	server := newTestServer(t, store)
+	send := func(method string, url string, body []byte, sign bool) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(method, url, bytes.NewReader(body))
+		require.NoError(t, err)
+		if sign {
+			signRequest(t, request, key, body, time.Now())
+		}
+		addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+		server.router.ServeHTTP(recorder, request)
+		return recorder
+	}
+	withPassword := func(password string) []byte {
+		body, err := json.Marshal(gin.H{"password": password})
+		require.NoError(t, err)
+		return body
+	}
+
+	// a stolen access token isn't enough to create a key
+	recorder := send(http.MethodPost, "/api/v1/signing_keys", nil, false)
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+	recorder = send(http.MethodPost, "/api/v1/signing_keys", withPassword("wrong password"), false)
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+
+	// the secret is only returned when the key is created
+	recorder = send(http.MethodPost, "/api/v1/signing_keys", withPassword(password), false)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var created map[string]interface{}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &created))
+	require.Equal(t, key.Secret, created["secret"])
+	require.Equal(t, float64(key.ID), created["id"])
+
+	// a request signed with an active key needs no password
+	recorder = send(http.MethodPost, "/api/v1/signing_keys", nil, true)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	recorder = send(http.MethodGet, "/api/v1/signing_keys", nil, false)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var listed []map[string]interface{}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &listed))
+	require.Len(t, listed, 1)
+	require.NotContains(t, listed[0], "secret")
+
+	recorder = send(http.MethodDelete, "/api/v1/signing_keys/7", withPassword(password), false)
+	require.Equal(t, http.StatusNotFound, recorder.Code)
+}
@@ -0,0 +1,135 @@
+package api
+
+import (
+	"errors"
+	db "go-backend/db/sqlc"
+	"go-backend/service"
+	"go-backend/token"
+	"go-backend/util"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// The `addSigningKeyRoutes` function adds the routes managing the keys the user signs their high-risk
+// requests with. The keys are only created and revoked by requests signed with an active key or carrying
+// the password of the user.
+func (server *Server) addSigningKeyRoutes(apiRouter *routeGroup) {
+	signingKeyRouter := apiRouter.Group("/signing_keys")
+	signingKeyRouter.POST("", server.optionalSignatureMiddleware(), server.createSigningKey)
+	signingKeyRouter.GET("", server.listSigningKeys)
+	signingKeyRouter.DELETE("/:id", server.optionalSignatureMiddleware(), server.revokeSigningKey)
+}
+
+// The signingKeyProofRequest type is the body of the requests changing the signing keys, whose password
+// is required when they aren't signed with an active key.
+type signingKeyProofRequest struct {
+	Password string `json:"password"`
+}
+
+// The `bindSigningKeyProof` function reads the proof that the request changing the signing keys is made
+// by the user, from its signature or its optional body. It renders the error and returns false when the
+// body is invalid.
+func bindSigningKeyProof(ctx *gin.Context) (service.SigningKeyProof, bool) {
+	var req signingKeyProofRequest
+	if ctx.Request.ContentLength != 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+			renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+			return service.SigningKeyProof{}, false
+		}
+	}
+
+	_, signed := ctx.Get(signingKeyIDKey)
+	return service.SigningKeyProof{Signed: signed, Password: req.Password}, true
+}
+
+// The signingKeyResponse type is a signing key of the user, without its secret which is only returned
+// once, when the key is created.
+type signingKeyResponse struct {
+	ID        int64      `json:"id"`
+	CreatedAt time.Time  `json:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at"`
+}
+
+func newSigningKeyResponse(key db.SigningKey) signingKeyResponse {
+	return signingKeyResponse{
+		ID:        key.ID,
+		CreatedAt: key.CreatedAt,
+		RevokedAt: nullTime(key.RevokedAt),
+	}
+}
+
+type createSigningKeyResponse struct {
+	ID        int64     `json:"id"`
+	Secret    string    `json:"secret"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// This is a function that creates a signing key for the authenticated user and returns its secret, the
+// only time it is returned. Once a machine client has an active key, the transfers they create must be
+// signed with one. The request must be signed with an active key or carry the password of the user.
+func (server *Server) createSigningKey(ctx *gin.Context) {
+	proof, ok := bindSigningKeyProof(ctx)
+	if !ok {
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	key, err := server.service.CreateSigningKey(ctx, authPayload.Username, proof)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, createSigningKeyResponse{
+		ID:        key.ID,
+		Secret:    key.Secret,
+		CreatedAt: key.CreatedAt,
+	})
+}
+
+// This is a function that lists the signing keys of the authenticated user, revoked ones included.
+func (server *Server) listSigningKeys(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	keys, err := server.service.ListSigningKeys(ctx, authPayload.Username)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	res := make([]signingKeyResponse, len(keys))
+	for i, key := range keys {
+		res[i] = newSigningKeyResponse(key)
+	}
+	renderJSON(ctx, http.StatusOK, res)
+}
+
+type revokeSigningKeyRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// This is a function that revokes a signing key of the authenticated user, the requests signed with it
+// being rejected from then on. The request must be signed with an active key or carry the password of
+// the user.
+func (server *Server) revokeSigningKey(ctx *gin.Context) {
+	var req revokeSigningKeyRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+	proof, ok := bindSigningKeyProof(ctx)
+	if !ok {
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	key, err := server.service.RevokeSigningKey(ctx, authPayload.Username, req.ID, proof)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, newSigningKeyResponse(key))
+}
@@ -0,0 +1,137 @@
+package api
+
+import (
+	"go-backend/service"
+	"go-backend/token"
+	"go-backend/util"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// The `addStandingOrderRoutes` function adds the routes of the standing orders, which transfer a fixed
+// amount between two accounts every week or month until they are cancelled.
+func (server *Server) addStandingOrderRoutes(apiRouter *routeGroup) {
+	standingOrderRouter := apiRouter.Group("/standing_orders")
+	standingOrderRouter.POST("", server.signatureMiddleware(), server.createStandingOrder)
+	standingOrderRouter.GET("", server.listStandingOrders)
+	standingOrderRouter.GET("/:id", server.getStandingOrder)
+	standingOrderRouter.POST("/:id/cancel", server.cancelStandingOrder)
+}
+
+// The createStandingOrderRequest type holds the standing order set up by the authenticated user.
+// @property {int64} FromAccountID - the account of the authenticated user the money is sent from.
+// @property {int64} ToAccountID - the account the money is sent to, in the same currency.
+// @property {int64} Amount - the amount sent on every run.
+// @property {string} Currency - the currency of the amount, which both accounts must hold.
+// @property {string} Memo - optional free text kept on the transfers.
+// @property {string} Frequency - weekly or monthly.
+// @property {time.Time} StartsAt - optional first run, now when unset.
+type createStandingOrderRequest struct {
+	FromAccountID int64     `json:"from_account_id" binding:"required,min=1"`
+	ToAccountID   int64     `json:"to_account_id" binding:"required,min=1"`
+	Amount        int64     `json:"amount" binding:"required,gt=0"`
+	Currency      string    `json:"currency" binding:"required,currency"`
+	Memo          string    `json:"memo" binding:"omitempty,max=140"`
+	Frequency     string    `json:"frequency" binding:"required,oneof=weekly monthly"`
+	StartsAt      time.Time `json:"starts_at"`
+}
+
+// This is a function that sets up a standing order sending money from an account of the authenticated
+// user every week or month, checked like a transfer of the user, until it is cancelled.
+func (server *Server) createStandingOrder(ctx *gin.Context) {
+	var req createStandingOrderRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	order, err := server.service.CreateStandingOrder(ctx, service.CreateStandingOrderParams{
+		Owner:         authPayload.Username,
+		FromAccountID: req.FromAccountID,
+		ToAccountID:   req.ToAccountID,
+		Amount:        req.Amount,
+		Currency:      req.Currency,
+		Memo:          req.Memo,
+		Frequency:     req.Frequency,
+		StartsAt:      req.StartsAt,
+	})
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, order)
+}
+
+type listStandingOrdersRequest struct {
+	pageRequest
+}
+
+// This is a function that lists the standing orders set up by the authenticated user, oldest first.
+func (server *Server) listStandingOrders(ctx *gin.Context) {
+	var req listStandingOrdersRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	limit, offset, err := server.paginate(paginationStandingOrders, req.pageRequest)
+	if err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	orders, err := server.service.ListStandingOrders(ctx, authPayload.Username, limit, offset)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, orders)
+}
+
+type standingOrderURIRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// This is a function that gets a standing order set up by the authenticated user or from an account they
+// own.
+func (server *Server) getStandingOrder(ctx *gin.Context) {
+	var req standingOrderURIRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	order, err := server.service.GetStandingOrder(ctx, authPayload.Username, req.ID)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, order)
+}
+
+// This is a function that cancels an active standing order, set up by the authenticated user or from an
+// account they own. It doesn't run afterwards.
+func (server *Server) cancelStandingOrder(ctx *gin.Context) {
+	var req standingOrderURIRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	order, err := server.service.CancelStandingOrder(ctx, authPayload.Username, req.ID)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, order)
+}
@@ -0,0 +1,119 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/service"
+	"go-backend/testutil/factory"
+	"go-backend/util"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateStandingOrderAPI(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	owner := factory.User()
+	fromAccount := factory.Account(factory.OwnedBy(owner.Username), factory.InCurrency(util.EUR))
+	toAccount := factory.Account(factory.InCurrency(util.EUR))
+	toAccount.ID = fromAccount.ID + 1
+	order := factory.StandingOrder(fromAccount, toAccount)
+	order.StartsAt = order.StartsAt.Add(time.Hour).UTC().Truncate(time.Second)
+	order.NextRunAt = order.StartsAt
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+	store.EXPECT().GetActiveBankParameter(gomock.Any(), gomock.Any()).Times(1).Return(db.BankParameter{}, db.ErrRecordNotFound)
+	store.EXPECT().
+		CreateStandingOrder(gomock.Any(), gomock.Eq(db.CreateStandingOrderParams{
+			Owner:         owner.Username,
+			FromAccountID: fromAccount.ID,
+			ToAccountID:   toAccount.ID,
+			Amount:        order.Amount,
+			Currency:      util.EUR,
+			Frequency:     db.StandingOrderMonthly,
+			StartsAt:      order.StartsAt,
+		})).
+		Times(1).
+		Return(order, nil)
+
+	server := newTestServer(t, store)
+	body := gin.H{
+		"from_account_id": fromAccount.ID,
+		"to_account_id":   toAccount.ID,
+		"amount":          order.Amount,
+		"currency":        util.EUR,
+		"frequency":       db.StandingOrderMonthly,
+		"starts_at":       order.StartsAt,
+	}
+	for _, tc := range []struct {
+		frequency string
+		status    int
+	}{
+		{"daily", http.StatusBadRequest},
+		{db.StandingOrderMonthly, http.StatusOK},
+	} {
+		body["frequency"] = tc.frequency
+		data, err := json.Marshal(body)
+		require.NoError(t, err)
+
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodPost, "/api/v1/standing_orders", bytes.NewReader(data))
+		require.NoError(t, err)
+
+		addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, owner.Username, time.Minute)
+		server.router.ServeHTTP(recorder, request)
+		require.Equal(t, tc.status, recorder.Code)
+
+		if tc.status == http.StatusOK {
+			var got db.StandingOrder
+			err = json.Unmarshal(recorder.Body.Bytes(), &got)
+			require.NoError(t, err)
+			require.Equal(t, order.ID, got.ID)
+			require.Equal(t, db.StandingOrderActive, got.Status)
+		}
+	}
+}
+
+func TestCancelStandingOrderAPI(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	owner := factory.User()
+	fromAccount := factory.Account(factory.OwnedBy(owner.Username))
+	order := factory.StandingOrder(fromAccount, factory.Account())
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetStandingOrder(gomock.Any(), gomock.Eq(order.ID)).Times(2).Return(order, nil)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(2).Return(fromAccount, nil)
+	gomock.InOrder(
+		store.EXPECT().CancelStandingOrder(gomock.Any(), gomock.Eq(order.ID)).Times(1).Return(db.StandingOrder{ID: order.ID, Status: db.StandingOrderCancelled}, nil),
+		store.EXPECT().CancelStandingOrder(gomock.Any(), gomock.Eq(order.ID)).Times(1).Return(db.StandingOrder{}, db.ErrRecordNotFound),
+	)
+
+	server := newTestServer(t, store)
+	for _, status := range []int{http.StatusOK, http.StatusConflict} {
+		recorder := httptest.NewRecorder()
+		url := fmt.Sprintf("/api/v1/standing_orders/%d/cancel", order.ID)
+		request, err := http.NewRequest(http.MethodPost, url, nil)
+		require.NoError(t, err)
+
+		addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, owner.Username, time.Minute)
+		server.router.ServeHTTP(recorder, request)
+		require.Equal(t, status, recorder.Code)
+		if status == http.StatusConflict {
+			requireErrorBody(t, recorder.Body, service.ReasonStandingOrderCancelled)
+		}
+	}
+}
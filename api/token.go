@@ -1,7 +1,7 @@
 package api
 
 import (
-	"fmt"
+	"context"
 	"go-backend/util"
 	"net/http"
 	"time"
@@ -9,8 +9,8 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func (server *Server) addTokenRoutes(apiRouter *gin.RouterGroup) {
-	accountRouter := apiRouter.Group("/tokens")
+func (server *Server) addTokenRoutes(apiRouter *routeGroup) {
+	accountRouter := apiRouter.Group("/tokens", anonymous())
 	accountRouter.POST("/renew_access", server.renewAccessToken)
 }
 
@@ -26,47 +26,13 @@ type renewAccessTokenResponse struct {
 func (server *Server) renewAccessToken(ctx *gin.Context) {
 	var req renewAccessTokenRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, util.ErrorResponse(err))
-	}
-
-	refreshPayload, err := server.tokenMaker.VerifyToken(req.RefreshToken)
-	if err != nil {
-		ctx.JSON(http.StatusUnauthorized, util.ErrorResponse(err))
-		return
-	}
-
-	session, err := server.store.GetSession(ctx, refreshPayload.ID)
-	if !util.CheckError(ctx, err) {
-		return
-	}
-
-	if session.IsBlocked {
-		err := fmt.Errorf("blocked session")
-		ctx.JSON(http.StatusUnauthorized, util.ErrorResponse(err))
-		return
-	}
-
-	if session.Username != refreshPayload.Username {
-		err := fmt.Errorf("incorrect session user")
-		ctx.JSON(http.StatusUnauthorized, util.ErrorResponse(err))
-		return
-	}
-
-	if session.RefreshToken != req.RefreshToken {
-		err := fmt.Errorf("mismatched session token")
-		ctx.JSON(http.StatusUnauthorized, util.ErrorResponse(err))
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
 		return
 	}
 
-	if time.Now().After(session.ExpiresAt) {
-		err := fmt.Errorf("expired session")
-		ctx.JSON(http.StatusUnauthorized, util.ErrorResponse(err))
-		return
-	}
-
-	accessToken, accessPayload, err := server.tokenMaker.CreateToken(refreshPayload.Username, server.config.AccessTokenDuration)
+	accessToken, accessPayload, err := server.service.RenewAccessToken(ctx, req.RefreshToken)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, util.ErrorResponse(err))
+		writeError(ctx, err)
 		return
 	}
 
@@ -74,5 +40,11 @@ func (server *Server) renewAccessToken(ctx *gin.Context) {
 		AccessToken:          accessToken,
 		AccessTokenExpiresAt: accessPayload.ExpiredAt,
 	}
-	ctx.JSON(http.StatusOK, res)
+	renderJSON(ctx, http.StatusOK, res)
+}
+
+// The `RunSessionActivityFlusher` function writes the last use of the sessions authenticated by the
+// server to the database every `interval` until the context is cancelled.
+func (server *Server) RunSessionActivityFlusher(ctx context.Context, interval time.Duration) {
+	server.service.RunSessionActivityFlusher(ctx, interval)
 }
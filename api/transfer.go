@@ -1,19 +1,23 @@
 package api
 
 import (
-	"errors"
-	"fmt"
 	db "go-backend/db/sqlc"
+	"go-backend/service"
 	"go-backend/token"
 	"go-backend/util"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
-func (server *Server) addTransferRoutes(apiRouter *gin.RouterGroup) {
+func (server *Server) addTransferRoutes(apiRouter *routeGroup) {
 	accountRouter := apiRouter.Group("/transfers")
-	accountRouter.POST("", server.createTransfer)
+	accountRouter.POST("", server.signatureMiddleware(), server.createTransfer)
+	accountRouter.POST("/batch", server.signatureMiddleware(), server.createBatchTransfer)
+	accountRouter.POST("/pain001", server.signatureMiddleware(), server.importPaymentInitiation)
+	accountRouter.GET("", server.listTransfers)
+	accountRouter.GET("/queued/:id", server.getQueuedTransfer)
 }
 
 // This is a Go struct type for creating a transfer request with required fields for from and to
@@ -22,85 +26,253 @@ func (server *Server) addTransferRoutes(apiRouter *gin.RouterGroup) {
 // transfer is being initiated. It is of type int64 and is required, meaning it must be provided in the
 // request body. The binding tag is used to specify validation rules for this property. In this case,
 // it must be a positive integer (
+// @property {string} FromAccountNumber - the account number of the account the transfer is made from,
+// instead of FromAccountID.
 // @property {int64} ToAccountID - ToAccountID is an integer property that represents the ID of the
-// account to which the transfer request is being made. It is a required field and must have a minimum
-// value of 1.
+// account to which the transfer request is being made. It is required unless BeneficiaryID or
+// ToAccountNumber is set, and must have a minimum value of 1.
+// @property {string} ToAccountNumber - the account number of the account the transfer is sent to,
+// instead of ToAccountID.
+// @property {int64} BeneficiaryID - the beneficiary of the authenticated user whose account the transfer
+// is sent to, instead of ToAccountID.
 // @property {int64} Amount - The amount property represents the amount of money that is being
 // transferred from one account to another. It is of type int64, which means it can hold integer values
 // up to 64 bits in size. The value of this property must be greater than zero, as specified by the
 // binding tag "gt=
 // @property {string} Currency - Currency is a string property that represents the currency of the
 // transfer amount. It is a required field and can only have one of the three values: CAD, USD, or EUR.
+// @property {string} Memo - optional free text shown to both parties, e.g. what the payment is for.
+// @property {string} ExternalReference - optional reference of the payment outside the bank, e.g. an
+// invoice number, which the transfers can be searched by.
 type createTransferRequest struct {
-	FromAccountID int64  `json:"from_account_id" binding:"required,min=1"`
-	ToAccountID   int64  `json:"to_account_id" binding:"required,min=1"`
-	Amount        int64  `json:"amount" binding:"required,gt=0"`
-	Currency      string `json:"currency" binding:"required,currency"`
+	FromAccountID     int64  `json:"from_account_id" binding:"required_without=FromAccountNumber,excluded_with=FromAccountNumber,omitempty,min=1"`
+	FromAccountNumber string `json:"from_account_number" binding:"omitempty,account_number"`
+	ToAccountID       int64  `json:"to_account_id" binding:"required_without_all=BeneficiaryID ToAccountNumber,excluded_with=BeneficiaryID ToAccountNumber,omitempty,min=1"`
+	ToAccountNumber   string `json:"to_account_number" binding:"excluded_with=BeneficiaryID,omitempty,account_number"`
+	BeneficiaryID     int64  `json:"beneficiary_id" binding:"omitempty,min=1"`
+	Amount            int64  `json:"amount" binding:"required,gt=0"`
+	Currency          string `json:"currency" binding:"required,currency"`
+	Memo              string `json:"memo" binding:"omitempty,max=140"`
+	ExternalReference string `json:"external_reference" binding:"omitempty,max=64"`
 }
 
 // This is a function that handles the creation of a transfer request. It first binds the request body
-// to a `createTransferRequest` struct and validates it using the `binding` tag rules. It then checks
-// if the `FromAccountID` and `ToAccountID` are valid accounts with matching currencies using the
-// `validAccount` function. If both accounts are valid, it creates a `db.TransferTxParams` struct with
-// the necessary parameters and calls the `TransferTx` function from the `store` to execute the
-// transfer transaction. If there are any errors during this process, it returns an error response with
-// the appropriate status code. If the transfer is successful, it returns a success response with the
-// transfer details.
+// to a `createTransferRequest` struct and validates it using the `binding` tag rules. It then calls the
+// `CreateTransfer` method of the service, which checks that the `FromAccountID` and `ToAccountID` are
+// valid accounts with matching currencies, the from account belonging to the authenticated user, before
+// executing the transfer transaction. If there are any errors during this process, it returns an error
+// response with the status code matching the service error. If the transfer is successful, it returns a
+// success response with the transfer details, while a transfer held for review by the screening is
+// accepted with the review it awaits and a large transfer is accepted as a pending transfer awaiting
+// approval. A transfer made while the database is unavailable is accepted as a queued transfer, whose
+// tracking ID tells its status, when the bank queues them.
 func (server *Server) createTransfer(ctx *gin.Context) {
 	var req createTransferRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, util.ErrorResponse(err))
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
 		return
 	}
 
-	fromAccount, valid := server.validAccount(ctx, req.FromAccountID, req.Currency)
-	if !valid {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	result, err := server.service.CreateTransfer(ctx, service.CreateTransferParams{
+		Owner:             authPayload.Username,
+		FromAccountID:     req.FromAccountID,
+		FromAccountNumber: req.FromAccountNumber,
+		ToAccountID:       req.ToAccountID,
+		ToAccountNumber:   req.ToAccountNumber,
+		BeneficiaryID:     req.BeneficiaryID,
+		Amount:            req.Amount,
+		Currency:          req.Currency,
+		Memo:              req.Memo,
+		ExternalReference: req.ExternalReference,
+		IdempotencyKey:    ctx.GetHeader(idempotencyKeyHeaderKey),
+	})
+	if err != nil {
+		writeError(ctx, err)
 		return
 	}
-
-	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
-	if fromAccount.Owner != authPayload.Username {
-		err := errors.New("from account doesn't belong to authenticated user")
-		ctx.JSON(http.StatusUnauthorized, util.ErrorResponse(err))
+	if result.Queued != nil {
+		renderJSON(ctx, http.StatusAccepted, result.Queued)
 		return
 	}
-
-	_, valid = server.validAccount(ctx, req.ToAccountID, req.Currency)
-	if !valid {
+	if result.Review != nil {
+		renderJSON(ctx, http.StatusAccepted, newTransferReviewResponse(*result.Review, time.Now()))
 		return
 	}
+	if result.Pending != nil {
+		renderJSON(ctx, http.StatusAccepted, result.Pending)
+		return
+	}
+	renderJSON(ctx, http.StatusOK, newTransferResultResponse(result.Result))
+}
+
+type createBatchTransferRequest struct {
+	Transfers []createTransferRequest `json:"transfers" binding:"required,min=1,max=100,dive"`
+}
 
-	arg := db.TransferTxParams{
-		FromAccountID: req.FromAccountID,
-		ToAccountID:   req.ToAccountID,
-		Amount:        req.Amount,
+// Outcomes of the transfers of a batch.
+const (
+	batchTransferSucceeded = "succeeded"
+	batchTransferHeld      = "held"
+	batchTransferPending   = "pending_approval"
+	batchTransferFailed    = "failed"
+)
+
+type batchTransferItemResponse struct {
+	Index   int                     `json:"index"`
+	Status  string                  `json:"status"`
+	Result  *db.TransferTxResult    `json:"result,omitempty"`
+	Review  *transferReviewResponse `json:"review,omitempty"`
+	Pending *db.PendingTransfer     `json:"pending,omitempty"`
+	Error   *util.ErrorBody         `json:"error,omitempty"`
+}
+
+type createBatchTransferResponse struct {
+	Succeeded int                         `json:"succeeded"`
+	Held      int                         `json:"held"`
+	Pending   int                         `json:"pending"`
+	Failed    int                         `json:"failed"`
+	Transfers []batchTransferItemResponse `json:"transfers"`
+}
+
+// This is a function that makes a batch of up to 100 transfers from accounts of the authenticated user,
+// e.g. the salaries of a payroll. Each transfer is checked and made on its own within one transaction,
+// so the response is OK even when some of them failed: every transfer is reported in the order of the
+// request, with its result, the review it is held for, the pending transfer awaiting approval or the
+// error that prevented it.
+func (server *Server) createBatchTransfer(ctx *gin.Context) {
+	var req createBatchTransferRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
 	}
 
-	result, err := server.store.TransferTx(ctx, arg)
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	transfers := make([]service.CreateTransferParams, 0, len(req.Transfers))
+	for _, transfer := range req.Transfers {
+		transfers = append(transfers, service.CreateTransferParams{
+			FromAccountID:     transfer.FromAccountID,
+			FromAccountNumber: transfer.FromAccountNumber,
+			ToAccountID:       transfer.ToAccountID,
+			ToAccountNumber:   transfer.ToAccountNumber,
+			BeneficiaryID:     transfer.BeneficiaryID,
+			Amount:            transfer.Amount,
+			Currency:          transfer.Currency,
+			Memo:              transfer.Memo,
+			ExternalReference: transfer.ExternalReference,
+		})
+	}
 
+	outcomes, err := server.service.CreateBatchTransfer(ctx, authPayload.Username, transfers)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, util.ErrorResponse(err))
+		writeError(ctx, err)
 		return
 	}
-	ctx.JSON(http.StatusOK, result)
+
+	now := time.Now()
+	res := createBatchTransferResponse{
+		Transfers: make([]batchTransferItemResponse, 0, len(outcomes)),
+	}
+	for i, outcome := range outcomes {
+		item := batchTransferItemResponse{Index: i}
+		if outcome.Err != nil {
+			_, body := serviceErrorResponse(outcome.Err)
+			body = body.Localize(requestLanguage(ctx))
+			item.Status = batchTransferFailed
+			item.Error = &body
+			res.Failed++
+		} else if outcome.Review != nil {
+			review := newTransferReviewResponse(*outcome.Review, now)
+			item.Status = batchTransferHeld
+			item.Review = &review
+			res.Held++
+		} else if outcome.Pending != nil {
+			item.Status = batchTransferPending
+			item.Pending = outcome.Pending
+			res.Pending++
+		} else {
+			result := outcome.Result
+			item.Status = batchTransferSucceeded
+			item.Result = &result
+			res.Succeeded++
+		}
+		res.Transfers = append(res.Transfers, item)
+	}
+	renderJSON(ctx, http.StatusOK, res)
+}
+
+type transferResponse struct {
+	db.Transfer
+	Currency     string               `json:"currency"`
+	Counterparty counterpartyResponse `json:"counterparty"`
+	Links        links                `json:"_links"`
 }
 
-// The `validAccount` function is a helper function that checks if an account with the given
-// `accountID` and `currency` exists in the database. It takes in a `gin.Context` object, an
-// `accountID` of type `int64`, and a `currency` of type `string`. It returns a boolean value
-// indicating whether the account is valid or not.
-func (server *Server) validAccount(ctx *gin.Context, accountID int64, currency string) (db.Account, bool) {
-	account, err := server.store.GetAccount(ctx, accountID)
+type listTransfersRequest struct {
+	keysetPageRequest
+	AccountID         int64  `form:"account_id" binding:"required,min=1"`
+	ExternalReference string `form:"external_reference" binding:"omitempty,max=64"`
+	Memo              string `form:"memo" binding:"omitempty,max=140"`
+}
 
-	if !util.CheckError(ctx, err) {
-		return account, false
+// This is a function that lists the transfers sent or received by an account owned by the authenticated
+// user, oldest first, optionally only those with an external reference or whose memo contains a text.
+// Each transfer comes with its counterparty, the other account and its owner. The page defaults to the first one with the default page size of the transfers endpoint.
+// Requests sending a cursor get keyset pages instead, newest first, with the cursor of the next page in
+// the X-Next-Cursor header. The transfers are streamed, pages of thousands of them not being buffered
+// whole.
+func (server *Server) listTransfers(ctx *gin.Context) {
+	var req listTransfersRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	limit, offset, err := server.paginate(paginationTransfers, req.pageRequest)
+	if err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+	cursor, keyset, err := req.keysetCursor()
+	if err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
 	}
 
-	if account.Currency != currency {
-		err := fmt.Errorf("account [%d] currency mismatch: %s vs %s", accountID, account.Currency, currency)
-		ctx.JSON(http.StatusBadRequest, util.ErrorResponse(err))
-		return account, false
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	arg := service.ListTransfersParams{
+		Owner:             authPayload.Username,
+		AccountID:         req.AccountID,
+		ExternalReference: req.ExternalReference,
+		Memo:              req.Memo,
+		Limit:             limit,
+		Offset:            offset,
+	}
+	var transfers []db.SearchTransfersRow
+	if keyset {
+		transfers, err = server.service.ListTransfersBefore(ctx, arg, cursor)
+	} else {
+		transfers, err = server.service.ListTransfers(ctx, arg)
+	}
+	if err != nil {
+		writeError(ctx, err)
+		return
 	}
 
-	return account, true
+	if keyset && len(transfers) > 0 {
+		setNextCursor(ctx, len(transfers), limit, transfers[len(transfers)-1].Transfer.ID)
+	}
+	streamJSON(ctx, http.StatusOK, transfers, func(transfer db.SearchTransfersRow) interface{} {
+		return transferResponse{
+			Transfer: transfer.Transfer,
+			Currency: transfer.Currency,
+			Counterparty: counterpartyResponse{
+				AccountID: transfer.CounterpartyAccountID,
+				Username:  transfer.CounterpartyUsername,
+				FullName:  transfer.CounterpartyFullName,
+				Nickname:  transfer.CounterpartyNickname,
+			},
+			Links: transferLinks(req.AccountID),
+		}
+	})
 }
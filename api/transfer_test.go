@@ -4,8 +4,12 @@ import (
 	"bytes"
 	"database/sql"
 	"encoding/json"
+	"fmt"
+	"go-backend/clock"
 	mockdb "go-backend/db/mock"
 	db "go-backend/db/sqlc"
+	"go-backend/service"
+	"go-backend/testutil/factory"
 	"go-backend/token"
 	"go-backend/util"
 	"net/http"
@@ -15,19 +19,25 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/stretchr/testify/require"
 )
 
 func TestCreateTransferAPI(t *testing.T) {
-	toUser, _ := randomUser(t)
-	fromUser, _ := randomUser(t)
+	toUser := factory.User()
+	fromUser := factory.User()
 
-	toAccount := randomAccount(toUser.Username)
-	fromAccount := randomAccount(fromUser.Username)
+	toAccount := factory.Account(factory.OwnedBy(toUser.Username))
+	fromAccount := factory.Account(factory.OwnedBy(fromUser.Username))
 
 	toAccount.Currency = util.CAD
 	fromAccount.Currency = util.CAD
 	amount := util.RandomMoney()
+	beneficiary := factory.Beneficiary(factory.SavedBy(fromUser.Username), func(beneficiary *db.Beneficiary) {
+		beneficiary.AccountID = toAccount.ID
+	})
+
+	now := time.Now()
 
 	testCases := []struct {
 		name          string
@@ -55,13 +65,254 @@ func TestCreateTransferAPI(t *testing.T) {
 					FromAccountID: fromAccount.ID,
 					ToAccountID:   toAccount.ID,
 					Amount:        amount,
+					Currency:      fromAccount.Currency,
+					ChargeFee:     true,
+					Now:           now,
+				}
+				store.EXPECT().GetActiveBankParameter(gomock.Any(), gomock.Any()).Times(1).Return(db.BankParameter{}, db.ErrRecordNotFound)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Eq(arg)).Times(1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "WithMemoAndReference",
+			body: gin.H{
+				"from_account_id":    fromAccount.ID,
+				"to_account_id":      toAccount.ID,
+				"amount":             amount,
+				"currency":           "CAD",
+				"memo":               "March rent",
+				"external_reference": "INV-042",
+			},
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, fromUser.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+
+				arg := db.TransferTxParams{
+					FromAccountID:     fromAccount.ID,
+					ToAccountID:       toAccount.ID,
+					Amount:            amount,
+					Memo:              "March rent",
+					ExternalReference: "INV-042",
+					Currency:          fromAccount.Currency,
+					ChargeFee:         true,
+					Now:               now,
+				}
+				store.EXPECT().GetActiveBankParameter(gomock.Any(), gomock.Any()).Times(1).Return(db.BankParameter{}, db.ErrRecordNotFound)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Eq(arg)).Times(1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "ByAccountNumber",
+			body: gin.H{
+				"from_account_number": fromAccount.AccountNumber,
+				"to_account_number":   toAccount.AccountNumber,
+				"amount":              amount,
+				"currency":            "CAD",
+			},
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, fromUser.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountByNumber(gomock.Any(), gomock.Eq(fromAccount.AccountNumber)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccountByNumber(gomock.Any(), gomock.Eq(toAccount.AccountNumber)).Times(1).Return(toAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+
+				arg := db.TransferTxParams{
+					FromAccountID: fromAccount.ID,
+					ToAccountID:   toAccount.ID,
+					Amount:        amount,
+					Currency:      fromAccount.Currency,
+					ChargeFee:     true,
+					Now:           now,
+				}
+				store.EXPECT().GetActiveBankParameter(gomock.Any(), gomock.Any()).Times(1).Return(db.BankParameter{}, db.ErrRecordNotFound)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Eq(arg)).Times(1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "UnknownAccountNumber",
+			body: gin.H{
+				"from_account_id":   fromAccount.ID,
+				"to_account_number": util.AccountNumber(toAccount.ID + 1000),
+				"amount":            amount,
+				"currency":          "CAD",
+			},
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, fromUser.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountByNumber(gomock.Any(), gomock.Any()).Times(1).Return(db.Account{}, db.ErrRecordNotFound)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidAccountNumber",
+			body: gin.H{
+				"from_account_id":   fromAccount.ID,
+				"to_account_number": toAccount.AccountNumber[:len(toAccount.AccountNumber)-1] + "x",
+				"amount":            amount,
+				"currency":          "CAD",
+			},
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, fromUser.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountByNumber(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireErrorBody(t, recorder.Body, util.ErrorCodeValidationFailed)
+			},
+		},
+		{
+			name: "AccountNumberAndID",
+			body: gin.H{
+				"from_account_id":     fromAccount.ID,
+				"from_account_number": fromAccount.AccountNumber,
+				"to_account_id":       toAccount.ID,
+				"amount":              amount,
+				"currency":            "CAD",
+			},
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, fromUser.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountByNumber(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireErrorBody(t, recorder.Body, util.ErrorCodeValidationFailed)
+			},
+		},
+		{
+			name: "MemoTooLong",
+			body: gin.H{
+				"from_account_id": fromAccount.ID,
+				"to_account_id":   toAccount.ID,
+				"amount":          amount,
+				"currency":        "CAD",
+				"memo":            util.RandomString(141),
+			},
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, fromUser.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireErrorBody(t, recorder.Body, util.ErrorCodeValidationFailed)
+			},
+		},
+		{
+			name: "ToBeneficiary",
+			body: gin.H{
+				"from_account_id": fromAccount.ID,
+				"beneficiary_id":  beneficiary.ID,
+				"amount":          amount,
+				"currency":        "CAD",
+			},
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, fromUser.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetBeneficiary(gomock.Any(), gomock.Eq(beneficiary.ID)).Times(1).Return(beneficiary, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+
+				arg := db.TransferTxParams{
+					FromAccountID: fromAccount.ID,
+					ToAccountID:   toAccount.ID,
+					Amount:        amount,
+					Currency:      fromAccount.Currency,
+					ChargeFee:     true,
+					Now:           now,
 				}
+				store.EXPECT().GetActiveBankParameter(gomock.Any(), gomock.Any()).Times(1).Return(db.BankParameter{}, db.ErrRecordNotFound)
 				store.EXPECT().TransferTx(gomock.Any(), gomock.Eq(arg)).Times(1)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
 			},
 		},
+		{
+			name: "BeneficiaryOfOtherUser",
+			body: gin.H{
+				"from_account_id": fromAccount.ID,
+				"beneficiary_id":  beneficiary.ID,
+				"amount":          amount,
+				"currency":        "CAD",
+			},
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, fromUser.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				other := factory.Beneficiary(func(other *db.Beneficiary) {
+					other.ID = beneficiary.ID
+				})
+				store.EXPECT().GetBeneficiary(gomock.Any(), gomock.Eq(beneficiary.ID)).Times(1).Return(other, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "AccountAndBeneficiary",
+			body: gin.H{
+				"from_account_id": fromAccount.ID,
+				"to_account_id":   toAccount.ID,
+				"beneficiary_id":  beneficiary.ID,
+				"amount":          amount,
+				"currency":        "CAD",
+			},
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, fromUser.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetBeneficiary(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NoRecipient",
+			body: gin.H{
+				"from_account_id": fromAccount.ID,
+				"amount":          amount,
+				"currency":        "CAD",
+			},
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, fromUser.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetBeneficiary(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
 		{
 			name: "Unauthorized",
 			body: gin.H{
@@ -75,12 +326,16 @@ func TestCreateTransferAPI(t *testing.T) {
 			},
 			buildStub: func(store *mockdb.MockStore) {
 				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(db.AccountMember{}, db.ErrRecordNotFound)
 				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(0)
 
 				arg := db.TransferTxParams{
 					FromAccountID: fromAccount.ID,
 					ToAccountID:   toAccount.ID,
 					Amount:        amount,
+					Currency:      fromAccount.Currency,
+					ChargeFee:     true,
+					Now:           now,
 				}
 				store.EXPECT().TransferTx(gomock.Any(), gomock.Eq(arg)).Times(0)
 			},
@@ -126,12 +381,16 @@ func TestCreateTransferAPI(t *testing.T) {
 					FromAccountID: fromAccount.ID,
 					ToAccountID:   toAccount.ID,
 					Amount:        amount,
+					Currency:      fromAccount.Currency,
+					ChargeFee:     true,
+					Now:           now,
 				}
 				store.EXPECT().TransferTx(gomock.Any(), gomock.Eq(arg)).Times(0)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				fromAccount.Currency = util.CAD
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireErrorBody(t, recorder.Body, service.ReasonCurrencyMismatch)
 			},
 		},
 		{
@@ -154,6 +413,9 @@ func TestCreateTransferAPI(t *testing.T) {
 					FromAccountID: fromAccount.ID,
 					ToAccountID:   toAccount.ID,
 					Amount:        amount,
+					Currency:      fromAccount.Currency,
+					ChargeFee:     true,
+					Now:           now,
 				}
 				store.EXPECT().TransferTx(gomock.Any(), gomock.Eq(arg)).Times(0)
 			},
@@ -181,7 +443,11 @@ func TestCreateTransferAPI(t *testing.T) {
 					FromAccountID: fromAccount.ID,
 					ToAccountID:   toAccount.ID,
 					Amount:        amount,
+					Currency:      fromAccount.Currency,
+					ChargeFee:     true,
+					Now:           now,
 				}
+				store.EXPECT().GetActiveBankParameter(gomock.Any(), gomock.Any()).Times(1).Return(db.BankParameter{}, db.ErrRecordNotFound)
 				store.EXPECT().TransferTx(gomock.Any(), gomock.Eq(arg)).Times(1).Return(db.TransferTxResult{}, sql.ErrConnDone)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
@@ -207,11 +473,20 @@ func TestCreateTransferAPI(t *testing.T) {
 					FromAccountID: fromAccount.ID,
 					ToAccountID:   toAccount.ID,
 					Amount:        -10,
+					Currency:      fromAccount.Currency,
+					ChargeFee:     true,
+					Now:           now,
 				}
 				store.EXPECT().TransferTx(gomock.Any(), gomock.Eq(arg)).Times(0)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
+
+				body := requireErrorBody(t, recorder.Body, util.ErrorCodeValidationFailed)
+				require.Len(t, body.FieldErrors, 1)
+				require.Equal(t, "amount", body.FieldErrors[0].Field)
+				require.Equal(t, "GT", body.FieldErrors[0].Code)
+				require.Equal(t, "amount must be greater than 0", body.FieldErrors[0].Message)
 			},
 		},
 		{
@@ -226,13 +501,16 @@ func TestCreateTransferAPI(t *testing.T) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, fromUser.Username, time.Minute)
 			},
 			buildStub: func(store *mockdb.MockStore) {
-				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(db.Account{}, sql.ErrNoRows)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(db.Account{}, db.ErrRecordNotFound)
 				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(0)
 
 				arg := db.TransferTxParams{
 					FromAccountID: fromAccount.ID,
 					ToAccountID:   toAccount.ID,
 					Amount:        amount,
+					Currency:      fromAccount.Currency,
+					ChargeFee:     true,
+					Now:           now,
 				}
 				store.EXPECT().TransferTx(gomock.Any(), gomock.Eq(arg)).Times(0)
 			},
@@ -254,6 +532,7 @@ func TestCreateTransferAPI(t *testing.T) {
 
 			// start test server and send request
 			server := newTestServer(t, store)
+			server.service.SetClock(clock.NewFake(now))
 			recorder := httptest.NewRecorder()
 
 			data, err := json.Marshal(tc.body)
@@ -269,3 +548,467 @@ func TestCreateTransferAPI(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateBatchTransferAPI(t *testing.T) {
+	fromUser := factory.User()
+	toUser := factory.User()
+
+	fromAccount := factory.Account(factory.OwnedBy(fromUser.Username))
+	toAccount := factory.Account(factory.OwnedBy(toUser.Username))
+	toAccount.ID = fromAccount.ID + 1
+	fromAccount.Currency = util.CAD
+	toAccount.Currency = util.CAD
+
+	transfer := gin.H{
+		"from_account_id": fromAccount.ID,
+		"to_account_id":   toAccount.ID,
+		"amount":          10,
+		"currency":        util.CAD,
+	}
+	mismatch := gin.H{
+		"from_account_id": fromAccount.ID,
+		"to_account_id":   toAccount.ID,
+		"amount":          10,
+		"currency":        util.USD,
+	}
+
+	now := time.Now()
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		setupAuth     func(request *http.Request, tokenMaker token.Maker)
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "PartialFailure",
+			body: gin.H{"transfers": []gin.H{transfer, mismatch}},
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, fromUser.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetActiveBankParameter(gomock.Any(), gomock.Any()).Times(1).Return(db.BankParameter{}, db.ErrRecordNotFound)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(2).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+
+				arg := db.BatchTransferTxParams{
+					Transfers: []db.TransferTxParams{{
+						FromAccountID: fromAccount.ID,
+						ToAccountID:   toAccount.ID,
+						Amount:        10,
+						Currency:      fromAccount.Currency,
+						ChargeFee:     true,
+						Now:           now,
+					}},
+				}
+				store.EXPECT().BatchTransferTx(gomock.Any(), gomock.Eq(arg)).Times(1).
+					Return([]db.BatchTransferTxItem{{Result: db.TransferTxResult{Transfer: db.Transfer{ID: 7}}}}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res createBatchTransferResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &res)
+				require.NoError(t, err)
+				require.Equal(t, 1, res.Succeeded)
+				require.Equal(t, 1, res.Failed)
+				require.Len(t, res.Transfers, 2)
+
+				require.Equal(t, batchTransferSucceeded, res.Transfers[0].Status)
+				require.Equal(t, int64(7), res.Transfers[0].Result.Transfer.ID)
+				require.Nil(t, res.Transfers[0].Error)
+
+				require.Equal(t, 1, res.Transfers[1].Index)
+				require.Equal(t, batchTransferFailed, res.Transfers[1].Status)
+				require.Nil(t, res.Transfers[1].Result)
+				require.Equal(t, service.ReasonCurrencyMismatch, res.Transfers[1].Error.Code)
+			},
+		},
+		{
+			name: "EmptyBatch",
+			body: gin.H{"transfers": []gin.H{}},
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, fromUser.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().BatchTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireErrorBody(t, recorder.Body, util.ErrorCodeValidationFailed)
+			},
+		},
+		{
+			name: "TooManyTransfers",
+			body: func() gin.H {
+				transfers := make([]gin.H, service.MaxBatchTransfers+1)
+				for i := range transfers {
+					transfers[i] = transfer
+				}
+				return gin.H{"transfers": transfers}
+			}(),
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, fromUser.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().BatchTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireErrorBody(t, recorder.Body, util.ErrorCodeValidationFailed)
+			},
+		},
+		{
+			name: "InvalidTransfer",
+			body: gin.H{"transfers": []gin.H{transfer, {"from_account_id": fromAccount.ID, "amount": 10, "currency": util.CAD}}},
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, fromUser.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().BatchTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireErrorBody(t, recorder.Body, util.ErrorCodeValidationFailed)
+			},
+		},
+		{
+			name: "BatchFailed",
+			body: gin.H{"transfers": []gin.H{transfer}},
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, fromUser.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetActiveBankParameter(gomock.Any(), gomock.Any()).Times(1).Return(db.BankParameter{}, db.ErrRecordNotFound)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().BatchTransferTx(gomock.Any(), gomock.Any()).Times(1).Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name: "NoAuthorization",
+			body: gin.H{"transfers": []gin.H{transfer}},
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().BatchTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			server.service.SetClock(clock.NewFake(now))
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/api/v1/transfers/batch", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			tc.setupAuth(request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestListTransfersAPI(t *testing.T) {
+	user := factory.User()
+	otherUser := factory.User()
+	account := factory.Account(factory.OwnedBy(user.Username))
+
+	counterparty := factory.User()
+	counterpartyAccount := factory.Account(factory.OwnedBy(counterparty.Username))
+	transfers := []db.SearchTransfersRow{
+		{
+			Transfer:              factory.Transfer(factory.Between(account, counterpartyAccount)),
+			CounterpartyAccountID: counterpartyAccount.ID,
+			CounterpartyUsername:  counterparty.Username,
+			CounterpartyFullName:  counterparty.FullName,
+			CounterpartyNickname:  "Rent",
+			Currency:              account.Currency,
+		},
+		{
+			Transfer:              factory.Transfer(factory.Between(counterpartyAccount, account)),
+			CounterpartyAccountID: counterpartyAccount.ID,
+			CounterpartyUsername:  counterparty.Username,
+			CounterpartyFullName:  counterparty.FullName,
+			CounterpartyNickname:  "Rent",
+			Currency:              account.Currency,
+		},
+	}
+
+	// the keyset pages list the newest transfers first
+	newestTransfers := []db.SearchTransfersBeforeRow{
+		db.SearchTransfersBeforeRow(transfers[1]),
+		db.SearchTransfersBeforeRow(transfers[0]),
+	}
+
+	testCases := []struct {
+		name          string
+		query         string
+		setupAuth     func(request *http.Request, tokenMaker token.Maker)
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "DefaultPagination",
+			query: fmt.Sprintf("account_id=%d", account.ID),
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				arg := db.SearchTransfersParams{
+					AccountID: account.ID,
+					RowLimit:  20,
+					RowOffset: 0,
+				}
+				store.EXPECT().SearchTransfers(gomock.Any(), gomock.Eq(arg)).Times(1).Return(transfers, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got []transferResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &got)
+				require.NoError(t, err)
+				require.Len(t, got, 2)
+				for i, transfer := range transfers {
+					require.Equal(t, transfer.Transfer, got[i].Transfer)
+					require.Equal(t, account.Currency, got[i].Currency)
+					require.Equal(t, counterpartyResponse{
+						AccountID: counterpartyAccount.ID,
+						Username:  counterparty.Username,
+						FullName:  counterparty.FullName,
+						Nickname:  "Rent",
+					}, got[i].Counterparty)
+				}
+			},
+		},
+		{
+			name:  "OK",
+			query: fmt.Sprintf("account_id=%d&page_id=2&page_size=50", account.ID),
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				arg := db.SearchTransfersParams{
+					AccountID: account.ID,
+					RowLimit:  50,
+					RowOffset: 50,
+				}
+				store.EXPECT().SearchTransfers(gomock.Any(), gomock.Eq(arg)).Times(1).Return(transfers, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:  "SearchByReferenceAndMemo",
+			query: fmt.Sprintf("account_id=%d&external_reference=INV-042&memo=rent", account.ID),
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				arg := db.SearchTransfersParams{
+					AccountID:         account.ID,
+					ExternalReference: pgtype.Text{String: "INV-042", Valid: true},
+					Memo:              pgtype.Text{String: "rent", Valid: true},
+					RowLimit:          20,
+					RowOffset:         0,
+				}
+				store.EXPECT().SearchTransfers(gomock.Any(), gomock.Eq(arg)).Times(1).Return(transfers, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:  "ReferenceTooLong",
+			query: fmt.Sprintf("account_id=%d&external_reference=%s", account.ID, util.RandomString(65)),
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().SearchTransfers(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireErrorBody(t, recorder.Body, util.ErrorCodeValidationFailed)
+			},
+		},
+		{
+			name:  "Unauthorized",
+			query: fmt.Sprintf("account_id=%d", account.ID),
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, otherUser.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(db.AccountMember{}, db.ErrRecordNotFound)
+				store.EXPECT().SearchTransfers(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:  "MissingAccountID",
+			query: "",
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().SearchTransfers(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "InvalidPageSize",
+			query: fmt.Sprintf("account_id=%d&page_size=0", account.ID),
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().SearchTransfers(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "InternalError",
+			query: fmt.Sprintf("account_id=%d", account.ID),
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().SearchTransfers(gomock.Any(), gomock.Any()).Times(1).Return([]db.SearchTransfersRow{}, sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name:  "KeysetFirstPage",
+			query: fmt.Sprintf("account_id=%d&cursor=&page_size=2", account.ID),
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				arg := db.SearchTransfersBeforeParams{AccountID: account.ID, RowLimit: 2}
+				store.EXPECT().SearchTransfersBefore(gomock.Any(), gomock.Eq(arg)).Times(1).Return(newestTransfers, nil)
+				store.EXPECT().SearchTransfers(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, util.EncodeCursor(newestTransfers[1].Transfer.ID), recorder.Header().Get(nextCursorHeaderKey))
+
+				var got []transferResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &got)
+				require.NoError(t, err)
+				require.Len(t, got, 2)
+				require.Equal(t, newestTransfers[0].Transfer, got[0].Transfer)
+			},
+		},
+		{
+			name:  "KeysetLastPage",
+			query: fmt.Sprintf("account_id=%d&memo=rent&cursor=%s", account.ID, util.EncodeCursor(7)),
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				arg := db.SearchTransfersBeforeParams{
+					AccountID: account.ID,
+					Memo:      pgtype.Text{String: "rent", Valid: true},
+					Cursor:    pgtype.Int8{Int64: 7, Valid: true},
+					RowLimit:  20,
+				}
+				store.EXPECT().SearchTransfersBefore(gomock.Any(), gomock.Eq(arg)).Times(1).Return(newestTransfers, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				// the page isn't full, so it is the last one
+				require.Empty(t, recorder.Header().Get(nextCursorHeaderKey))
+			},
+		},
+		{
+			name:  "InvalidCursor",
+			query: fmt.Sprintf("account_id=%d&cursor=42", account.ID),
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().SearchTransfersBefore(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "CursorWithPageID",
+			query: fmt.Sprintf("account_id=%d&cursor=&page_id=2", account.ID),
+			setupAuth: func(request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().SearchTransfersBefore(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/api/v1/transfers?"+tc.query, nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package api
+
+import (
+	"go-backend/service"
+	"go-backend/token"
+	"go-backend/util"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	quotaLimitHeaderKey     = "X-Quota-Limit"
+	quotaRemainingHeaderKey = "X-Quota-Remaining"
+	quotaResetHeaderKey     = "X-Quota-Reset"
+)
+
+// usagePath is the v1 path of the route reporting the usage of the API, whose calls aren't counted so
+// that the clients can still check their usage once their quota is used up.
+const usagePath = "/api/v1/usage"
+
+// The `addUsageRoutes` function adds the route reporting the usage of the API by the user.
+func (server *Server) addUsageRoutes(apiRouter *routeGroup) {
+	apiRouter.With(requireScopes()).GET("/usage", server.getUsage)
+}
+
+// The `usageMiddleware` function counts every authenticated request as a call of its user, and rejects
+// the calls of the users past the quota of their API plan with 429. The quota of the users with a plan is
+// sent in the X-Quota-Limit, X-Quota-Remaining and X-Quota-Reset headers. The calls aren't limited while
+// they can't be counted, e.g. when Redis is down. It must be registered after `authMiddleware`.
+func (server *Server) usageMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if v1Path(ctx.FullPath()) == usagePath {
+			ctx.Next()
+			return
+		}
+
+		authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+		apiUsage, err := server.service.RecordAPICall(ctx, authPayload.Username)
+		if err != nil && service.ErrorCode(err) != service.CodeResourceExhausted {
+			log.Printf("cannot count the API call of %s: %v", authPayload.Username, err)
+			ctx.Next()
+			return
+		}
+
+		if apiUsage.Quota > 0 {
+			ctx.Header(quotaLimitHeaderKey, strconv.FormatInt(apiUsage.Quota, 10))
+			ctx.Header(quotaRemainingHeaderKey, strconv.FormatInt(apiUsage.Remaining(), 10))
+			ctx.Header(quotaResetHeaderKey, strconv.FormatInt(apiUsage.ResetsAt.Unix(), 10))
+		}
+		if err != nil {
+			retryAfter := time.Until(apiUsage.ResetsAt).Round(time.Second)
+			ctx.Header("Retry-After", strconv.FormatInt(int64(retryAfter/time.Second), 10))
+			status, body := serviceErrorResponse(err)
+			abortWithJSON(ctx, status, body)
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// The usageResponse type is the usage of the API by a user in the current month.
+// @property {*string} Plan - the API plan of the user, null when their calls aren't limited, along with
+// its Quota and the Remaining calls.
+type usageResponse struct {
+	Period    string    `json:"period"`
+	Calls     int64     `json:"calls"`
+	Plan      *string   `json:"plan"`
+	Quota     *int64    `json:"quota"`
+	Remaining *int64    `json:"remaining"`
+	ResetsAt  time.Time `json:"resets_at"`
+}
+
+func newUsageResponse(apiUsage service.APIUsage) usageResponse {
+	res := usageResponse{
+		Period:   apiUsage.Period,
+		Calls:    apiUsage.Calls,
+		ResetsAt: apiUsage.ResetsAt,
+	}
+	if apiUsage.Plan != "" {
+		res.Plan = &apiUsage.Plan
+	}
+	if apiUsage.Quota > 0 {
+		remaining := apiUsage.Remaining()
+		res.Quota = &apiUsage.Quota
+		res.Remaining = &remaining
+	}
+	return res
+}
+
+// This is a function that reports the calls the authenticated user made to the API in the current month
+// and, when they have an API plan, how many they can still make before it is over.
+func (server *Server) getUsage(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	apiUsage, err := server.service.GetAPIUsage(ctx, authPayload.Username)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, newUsageResponse(apiUsage))
+}
+
+type apiPlanURI struct {
+	Username string `uri:"username" binding:"required,alphanum"`
+}
+
+type setAPIPlanRequest struct {
+	Plan string `json:"plan" binding:"required"`
+}
+
+type apiPlanResponse struct {
+	Username  string    `json:"username"`
+	Plan      string    `json:"plan"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// This is a function that gives a user an API plan, making them a machine client whose calls are
+// rejected past the monthly quota of the plan.
+func (server *Server) setAPIPlan(ctx *gin.Context) {
+	var uri apiPlanURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+	var req setAPIPlanRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	plan, err := server.service.SetAPIPlan(ctx, uri.Username, req.Plan)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, apiPlanResponse{
+		Username:  plan.Username,
+		Plan:      plan.Plan,
+		UpdatedAt: plan.UpdatedAt,
+	})
+}
+
+// This is a function that takes the API plan of a user away, their calls no longer being limited.
+func (server *Server) removeAPIPlan(ctx *gin.Context) {
+	var uri apiPlanURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
+		return
+	}
+
+	err := server.service.RemoveAPIPlan(ctx, uri.Username)
+	if err != nil {
+		writeError(ctx, err)
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, gin.H{"message": "successfully removed API plan"})
+}
@@ -0,0 +1,252 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/service"
+	"go-backend/testutil/factory"
+	"go-backend/usage"
+	"go-backend/util"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUsageQuotaAPI(t *testing.T) {
+	user := factory.User()
+	account := factory.Account(factory.OwnedBy(user.Username))
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(2).Return(account, nil)
+	store.EXPECT().
+		GetUserAPIPlan(gomock.Any(), gomock.Eq(user.Username)).
+		AnyTimes().
+		Return(db.UserApiPlan{Username: user.Username, Plan: "basic"}, nil)
+
+	server := newTestServerWithConfig(t, store, nil, func(config *util.Config) {
+		config.APIPlans = "basic=2,pro=1000"
+	})
+	get := func(url string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, url, nil)
+		require.NoError(t, err)
+		addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+		server.router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	accountURL := fmt.Sprintf("/api/v1/accounts/%d", account.ID)
+	for remaining := 1; remaining >= 0; remaining-- {
+		recorder := get(accountURL)
+		require.Equal(t, http.StatusOK, recorder.Code)
+		require.Equal(t, "2", recorder.Header().Get(quotaLimitHeaderKey))
+		require.Equal(t, strconv.Itoa(remaining), recorder.Header().Get(quotaRemainingHeaderKey))
+		require.Equal(t, strconv.FormatInt(usage.PeriodEnd(time.Now()).Unix(), 10), recorder.Header().Get(quotaResetHeaderKey))
+	}
+
+	// the quota is used up until the end of the month
+	recorder := get(accountURL)
+	require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+	require.Equal(t, "0", recorder.Header().Get(quotaRemainingHeaderKey))
+	require.NotEmpty(t, recorder.Header().Get("Retry-After"))
+	requireErrorBody(t, recorder.Body, service.ReasonQuotaExceeded)
+
+	// the usage can still be checked, and isn't counted
+	for i := 0; i < 2; i++ {
+		recorder = get("/api/v1/usage")
+		require.Equal(t, http.StatusOK, recorder.Code)
+
+		var res usageResponse
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+		require.Equal(t, usage.Period(time.Now()), res.Period)
+		require.Equal(t, int64(3), res.Calls)
+		require.Equal(t, "basic", *res.Plan)
+		require.Equal(t, int64(2), *res.Quota)
+		require.Zero(t, *res.Remaining)
+		require.True(t, res.ResetsAt.Equal(usage.PeriodEnd(time.Now())))
+	}
+}
+
+func TestUsageWithoutPlanAPI(t *testing.T) {
+	user := factory.User()
+	account := factory.Account(factory.OwnedBy(user.Username))
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+	server := newTestServer(t, store)
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/accounts/%d", account.ID), nil)
+	require.NoError(t, err)
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Empty(t, recorder.Header().Get(quotaLimitHeaderKey))
+
+	recorder = httptest.NewRecorder()
+	request, err = http.NewRequest(http.MethodGet, "/api/v1/usage", nil)
+	require.NoError(t, err)
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var res map[string]interface{}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+	require.Equal(t, float64(1), res["calls"])
+	require.Nil(t, res["plan"])
+	require.Nil(t, res["quota"])
+	require.Nil(t, res["remaining"])
+}
+
+// unavailableMeter fails to count the calls, like a meter whose Redis is down.
+type unavailableMeter struct{}
+
+func (unavailableMeter) Increment(ctx context.Context, username string, at time.Time) (int64, error) {
+	return 0, errors.New("connection refused")
+}
+
+func (unavailableMeter) Count(ctx context.Context, username string, at time.Time) (int64, error) {
+	return 0, errors.New("connection refused")
+}
+
+func TestUsageMeterUnavailableAPI(t *testing.T) {
+	user := factory.User()
+	account := factory.Account(factory.OwnedBy(user.Username))
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+	server := newTestServerWithConfig(t, store, nil, func(config *util.Config) {
+		config.APIPlans = "basic=1"
+	})
+	server.SetUsageMeter(unavailableMeter{})
+
+	// the calls aren't limited while they can't be counted
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/accounts/%d", account.ID), nil)
+	require.NoError(t, err)
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	request, err = http.NewRequest(http.MethodGet, "/api/v1/usage", nil)
+	require.NoError(t, err)
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+}
+
+func TestSetAPIPlanAPI(t *testing.T) {
+	admin := factory.User(factory.WithRole(util.AdminRole))
+	user := factory.User()
+
+	testCases := []struct {
+		name          string
+		method        string
+		body          string
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "OK",
+			method: http.MethodPut,
+			body:   `{"plan": "pro"}`,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().
+					UpsertUserAPIPlan(gomock.Any(), gomock.Eq(db.UpsertUserAPIPlanParams{Username: user.Username, Plan: "pro"})).
+					Times(1).
+					Return(db.UserApiPlan{Username: user.Username, Plan: "pro", UpdatedAt: time.Now()}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var res apiPlanResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &res))
+				require.Equal(t, user.Username, res.Username)
+				require.Equal(t, "pro", res.Plan)
+			},
+		},
+		{
+			name:   "UnknownPlan",
+			method: http.MethodPut,
+			body:   `{"plan": "enterprise"}`,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().UpsertUserAPIPlan(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				body := requireErrorBody(t, recorder.Body, util.ErrorCodeInvalidArgument)
+				require.Equal(t, "plan", body.FieldErrors[0].Field)
+			},
+		},
+		{
+			name:   "UserNotFound",
+			method: http.MethodPut,
+			body:   `{"plan": "pro"}`,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(db.User{}, db.ErrRecordNotFound)
+				store.EXPECT().UpsertUserAPIPlan(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:   "Remove",
+			method: http.MethodDelete,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().DeleteUserAPIPlan(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).Times(1).Return(admin, nil)
+			// the admin has no plan, their calls aren't limited
+			store.EXPECT().GetUserAPIPlan(gomock.Any(), gomock.Eq(admin.Username)).Times(1).Return(db.UserApiPlan{}, db.ErrRecordNotFound)
+			tc.buildStub(store)
+
+			server := newTestServerWithConfig(t, store, nil, func(config *util.Config) {
+				config.APIPlans = "basic=10000,pro=1000000"
+			})
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(tc.method, "/api/v1/admin/users/"+user.Username+"/api_plan", bytes.NewBufferString(tc.body))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, admin.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
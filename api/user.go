@@ -1,21 +1,26 @@
 package api
 
 import (
+	"errors"
+	"go-backend/captcha"
 	db "go-backend/db/sqlc"
+	"go-backend/service"
 	"go-backend/util"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
-	"github.com/lib/pq"
 )
 
-func (server *Server) addUserRoutes(apiRouter *gin.RouterGroup) {
-	accountRouter := apiRouter.Group("/users")
+// The `addUserRoutes` function adds the public routes signing up and logging in users and getting their
+// profile, and the one looking up a user to send them money, which is reserved to authenticated users.
+func (server *Server) addUserRoutes(apiRouter *routeGroup) {
+	accountRouter := apiRouter.Group("/users", anonymous())
 	accountRouter.POST("", server.createUser)
 	accountRouter.POST("/login", server.loginUser)
 	accountRouter.GET("/:username", server.getUser)
+	accountRouter.With(authenticated()).GET("/lookup", server.lookupUser)
 }
 
 func newUserResponse(user db.User) userResponse {
@@ -25,14 +30,22 @@ func newUserResponse(user db.User) userResponse {
 		Email:             user.Email,
 		PasswordChangedAt: user.PasswordChangedAt,
 		CreatedAt:         user.CreatedAt,
+		OrgID:             user.OrgID,
 	}
 }
 
+// The createUserRequest type holds the registration of a user.
+// @property {string} CaptchaToken - the token the CAPTCHA widget gave the user once they solved it,
+// required when the CAPTCHA_PROVIDER config is set.
+// @property {string} Organization - the slug of the organization the user signs up to, the default one
+// when empty.
 type createUserRequest struct {
-	Username string `json:"username" binding:"required,alphanum"`
-	Password string `json:"password" binding:"required,min=6"`
-	FullName string `json:"full_name" binding:"required"`
-	Email    string `json:"email" binding:"required,email"`
+	Username     string `json:"username" binding:"required,alphanum"`
+	Password     string `json:"password" binding:"required,min=6"`
+	FullName     string `json:"full_name" binding:"required"`
+	Email        string `json:"email" binding:"required,email"`
+	CaptchaToken string `json:"captcha_token"`
+	Organization string `json:"organization"`
 }
 
 type userResponse struct {
@@ -41,44 +54,51 @@ type userResponse struct {
 	Email             string    `json:"email"`
 	PasswordChangedAt time.Time `json:"password_changed_at"`
 	CreatedAt         time.Time `json:"created_at"`
+	OrgID             int64     `json:"org_id"`
 }
 
+// This is a function that registers a user. When a CAPTCHA is configured, the user must have solved it,
+// so that bots can't open accounts in bulk: a missing or invalid token is rejected with 400 and the
+// CAPTCHA_FAILED code, and the registrations fail with 503 while the CAPTCHA provider can't be reached.
+// A client registering too many users is rejected with 429 and the TOO_MANY_SIGNUPS code.
 func (server *Server) createUser(ctx *gin.Context) {
 	var req createUserRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, util.ErrorResponse(err))
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
 		return
 	}
 
-	hashedPassword, err := util.HashPassword(req.Password)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, util.ErrorResponse(err))
+	if err := server.captcha.Verify(ctx, req.CaptchaToken, ctx.ClientIP()); err != nil {
+		if errors.Is(err, captcha.ErrFailed) {
+			body := util.ErrorResponse(http.StatusBadRequest, util.WithErrorCode(util.ErrorCodeCaptchaFailed, err))
+			body.FieldErrors = append(body.FieldErrors, util.FieldError{
+				Field:   "captcha_token",
+				Code:    util.ErrorCodeCaptchaFailed,
+				Message: err.Error(),
+			})
+			renderJSON(ctx, http.StatusBadRequest, body)
+			return
+		}
+		ctx.Error(err)
+		renderJSON(ctx, http.StatusServiceUnavailable, util.ErrorResponse(http.StatusServiceUnavailable, err))
 		return
 	}
 
-	arg := db.CreateUserParams{
-		Username:       req.Username,
-		HashedPassword: hashedPassword,
-		FullName:       req.FullName,
-		Email:          req.Email,
-	}
-
-	user, err := server.store.CreateUser(ctx, arg)
-
+	user, err := server.service.CreateUser(ctx, service.CreateUserParams{
+		Username:     req.Username,
+		Password:     req.Password,
+		FullName:     req.FullName,
+		Email:        req.Email,
+		Organization: req.Organization,
+		ClientIP:     ctx.ClientIP(),
+	})
 	if err != nil {
-		if pqError, ok := err.(*pq.Error); ok {
-			switch pqError.Code.Name() {
-			case "unique_violation":
-				ctx.JSON(http.StatusForbidden, util.ErrorResponse(err))
-				return
-			}
-		}
-		ctx.JSON(http.StatusInternalServerError, util.ErrorResponse(err))
+		writeError(ctx, err)
 		return
 	}
 
 	res := newUserResponse(user)
-	ctx.JSON(http.StatusOK, res)
+	renderJSON(ctx, http.StatusOK, res)
 }
 
 type getUserRequest struct {
@@ -88,23 +108,28 @@ type getUserRequest struct {
 func (server *Server) getUser(ctx *gin.Context) {
 	var req getUserRequest
 	if err := ctx.ShouldBindUri(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, util.ErrorResponse(err))
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
 		return
 	}
 
-	user, err := server.store.GetUser(ctx, req.Username)
-
-	if !util.CheckError(ctx, err) {
+	user, err := server.service.GetUser(ctx, req.Username)
+	if err != nil {
+		writeError(ctx, err)
 		return
 	}
 
 	res := newUserResponse(user)
-	ctx.JSON(http.StatusOK, res)
+	renderJSON(ctx, http.StatusOK, res)
 }
 
 type loginUserRequest struct {
-	Username string `json:"username" binding:"required,alphanum"`
+	// the username or the email of the user
+	Identifier string `json:"identifier" binding:"required_without=Username,omitempty,alphanum|email"`
+	// deprecated, the username sent before users could log in with their email
+	Username string `json:"username" binding:"omitempty,alphanum"`
 	Password string `json:"password" binding:"required,min=6"`
+	// the scopes to limit the tokens to, e.g. accounts:read, empty for tokens allowed every request
+	Scopes []string `json:"scopes" binding:"omitempty,max=6"`
 }
 
 type loginUserResponse struct {
@@ -113,59 +138,45 @@ type loginUserResponse struct {
 	AccessTokenExpiresAt  time.Time    `json:"access_token_expires_at"`
 	RefreshToken          string       `json:"refresh_token"`
 	RefreshTokenExpiresAt time.Time    `json:"refresh_token_expires_at"`
+	Scopes                []string     `json:"scopes"`
 	UserResponse          userResponse `json:"user"`
 }
 
+func newLoginUserResponse(result service.LoginUserResult) loginUserResponse {
+	return loginUserResponse{
+		SessionID:             result.Session.ID,
+		AccessToken:           result.AccessToken,
+		AccessTokenExpiresAt:  result.AccessPayload.ExpiredAt,
+		RefreshToken:          result.RefreshToken,
+		RefreshTokenExpiresAt: result.RefreshPayload.ExpiredAt,
+		Scopes:                result.AccessPayload.Scopes,
+		UserResponse:          newUserResponse(result.User),
+	}
+}
+
 func (server *Server) loginUser(ctx *gin.Context) {
 	var req loginUserRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, util.ErrorResponse(err))
-	}
-
-	user, err := server.store.GetUser(ctx, req.Username)
-	if !util.CheckError(ctx, err) {
+		renderJSON(ctx, http.StatusBadRequest, util.ErrorResponse(http.StatusBadRequest, err))
 		return
 	}
 
-	err = util.Checkpassword(req.Password, user.HashedPassword)
-	if err != nil {
-		ctx.JSON(http.StatusUnauthorized, util.ErrorResponse(err))
-		return
+	identifier := req.Identifier
+	if identifier == "" {
+		identifier = req.Username
 	}
 
-	accessToken, accessPayload, err := server.tokenMaker.CreateToken(user.Username, server.config.AccessTokenDuration)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, util.ErrorResponse(err))
-		return
-	}
-
-	refreshToken, refreshPayload, err := server.tokenMaker.CreateToken(user.Username, server.config.RefreshTokenDuration)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, util.ErrorResponse(err))
-		return
-	}
-
-	session, err := server.store.CreateSession(ctx, db.CreateSessionParams{
-		ID:           refreshPayload.ID,
-		Username:     user.Username,
-		RefreshToken: refreshToken,
-		UserAgent:    ctx.Request.UserAgent(),
-		ClientIp:     ctx.ClientIP(),
-		IsBlocked:    false,
-		ExpiresAt:    refreshPayload.ExpiredAt,
+	result, err := server.service.LoginUser(ctx, service.LoginUserParams{
+		Identifier: identifier,
+		Password:   req.Password,
+		Scopes:     req.Scopes,
+		UserAgent:  ctx.Request.UserAgent(),
+		ClientIP:   ctx.ClientIP(),
 	})
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, util.ErrorResponse(err))
+		writeError(ctx, err)
 		return
 	}
 
-	res := loginUserResponse{
-		SessionID:             session.ID,
-		AccessToken:           accessToken,
-		AccessTokenExpiresAt:  accessPayload.ExpiredAt,
-		RefreshToken:          refreshToken,
-		RefreshTokenExpiresAt: refreshPayload.ExpiredAt,
-		UserResponse:          newUserResponse(user),
-	}
-	ctx.JSON(http.StatusOK, res)
+	renderJSON(ctx, http.StatusOK, newLoginUserResponse(result))
 }
@@ -2,26 +2,32 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"go-backend/captcha"
 	mockdb "go-backend/db/mock"
 	db "go-backend/db/sqlc"
+	"go-backend/service"
+	"go-backend/testutil/factory"
 	"go-backend/util"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
-	"github.com/lib/pq"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/stretchr/testify/require"
 )
 
 func TestCreateUserAPI(t *testing.T) {
-	user, password := randomUser(t)
+	user, password := factory.UserWithPassword(t)
 
 	testCases := []struct {
 		name          string
@@ -43,6 +49,7 @@ func TestCreateUserAPI(t *testing.T) {
 					HashedPassword: user.HashedPassword,
 					FullName:       user.FullName,
 					Email:          user.Email,
+					OrgID:          db.DefaultOrganizationID,
 				}
 				store.EXPECT().
 					CreateUser(gomock.Any(), EqCreateUserParams(arg, password)).
@@ -73,7 +80,7 @@ func TestCreateUserAPI(t *testing.T) {
 			},
 		},
 		{
-			name: "DuplicateUser",
+			name: "SameUser",
 			body: gin.H{
 				"username":  user.Username,
 				"password":  password,
@@ -84,10 +91,81 @@ func TestCreateUserAPI(t *testing.T) {
 				store.EXPECT().
 					CreateUser(gomock.Any(), gomock.Any()).
 					Times(1).
-					Return(db.User{}, &pq.Error{Code: "23505"})
+					Return(db.User{}, &pgconn.PgError{Code: db.UniqueViolation})
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchUser(t, recorder.Body, user)
+			},
+		},
+		{
+			name: "UsernameTaken",
+			body: gin.H{
+				"username":  user.Username,
+				"password":  "another password",
+				"full_name": user.FullName,
+				"email":     user.Email,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateUser(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.User{}, &pgconn.PgError{Code: db.UniqueViolation})
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().GetUserByEmail(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				body := requireErrorBody(t, recorder.Body, service.ReasonUsernameTaken)
+				require.Len(t, body.FieldErrors, 1)
+				require.Equal(t, "username", body.FieldErrors[0].Field)
+				require.Equal(t, service.ReasonUsernameTaken, body.FieldErrors[0].Code)
+			},
+		},
+		{
+			name: "EmailTaken",
+			body: gin.H{
+				"username":  "anotheruser",
+				"password":  password,
+				"full_name": user.FullName,
+				"email":     user.Email,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateUser(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.User{}, &pgconn.PgError{Code: db.UniqueViolation})
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq("anotheruser")).Times(1).Return(db.User{}, db.ErrRecordNotFound)
+				store.EXPECT().GetUserByEmail(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				body := requireErrorBody(t, recorder.Body, service.ReasonEmailTaken)
+				require.Len(t, body.FieldErrors, 1)
+				require.Equal(t, "email", body.FieldErrors[0].Field)
+				require.NotContains(t, body.Message, "SQLSTATE")
+			},
+		},
+		{
+			name: "CollidingUserDeleted",
+			body: gin.H{
+				"username":  user.Username,
+				"password":  password,
+				"full_name": user.FullName,
+				"email":     user.Email,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateUser(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.User{}, &pgconn.PgError{Code: db.UniqueViolation})
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(1).Return(db.User{}, db.ErrRecordNotFound)
+				store.EXPECT().GetUserByEmail(gomock.Any(), gomock.Any()).Times(1).Return(db.User{}, db.ErrRecordNotFound)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusForbidden, recorder.Code)
+				requireErrorBody(t, recorder.Body, util.ErrorCodeAlreadyExists)
 			},
 		},
 		{
@@ -187,8 +265,96 @@ func TestCreateUserAPI(t *testing.T) {
 	}
 }
 
+// fakeCaptcha accepts the token "solved", and fails like an unreachable provider with the token "down".
+type fakeCaptcha struct{}
+
+func (fakeCaptcha) Verify(ctx context.Context, token string, remoteIP string) error {
+	switch token {
+	case "solved":
+		return nil
+	case "down":
+		return errors.New("CAPTCHA provider responded with status 502")
+	}
+	return fmt.Errorf("%w: invalid-input-response", captcha.ErrFailed)
+}
+
+func TestCreateUserCaptchaAPI(t *testing.T) {
+	user, password := factory.UserWithPassword(t)
+
+	testCases := []struct {
+		name          string
+		captchaToken  string
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:         "Solved",
+			captchaToken: "solved",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Times(1).Return(user, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchUser(t, recorder.Body, user)
+			},
+		},
+		{
+			name: "Missing",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				body := requireErrorBody(t, recorder.Body, util.ErrorCodeCaptchaFailed)
+				require.Equal(t, "captcha_token", body.FieldErrors[0].Field)
+			},
+		},
+		{
+			name:         "ProviderUnavailable",
+			captchaToken: "down",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			server.captcha = fakeCaptcha{}
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{
+				"username":      user.Username,
+				"password":      password,
+				"full_name":     user.FullName,
+				"email":         user.Email,
+				"captcha_token": tc.captchaToken,
+			})
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/api/v1/users", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
 func TestGetUser(t *testing.T) {
-	user, _ := randomUser(t)
+	user := factory.User()
 
 	testCases := []struct {
 		name          string
@@ -250,7 +416,7 @@ func TestGetUser(t *testing.T) {
 }
 
 func TestLoginUserAPI(t *testing.T) {
-	user, password := randomUser(t)
+	user, password := factory.UserWithPassword(t)
 
 	testCases := []struct {
 		name          string
@@ -277,6 +443,43 @@ func TestLoginUserAPI(t *testing.T) {
 				require.Equal(t, http.StatusOK, recorder.Code)
 			},
 		},
+		{
+			name: "Scoped",
+			body: gin.H{
+				"identifier": user.Username,
+				"password":   password,
+				"scopes":     []string{"accounts:read"},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Body.String(), `"scopes":["accounts:read"]`)
+			},
+		},
+		{
+			name: "InvalidScope",
+			body: gin.H{
+				"identifier": user.Username,
+				"password":   password,
+				"scopes":     []string{"admin"},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
 		{
 			name: "UserNotFound",
 			body: gin.H{
@@ -287,7 +490,7 @@ func TestLoginUserAPI(t *testing.T) {
 				store.EXPECT().
 					GetUser(gomock.Any(), gomock.Any()).
 					Times(1).
-					Return(db.User{}, sql.ErrNoRows)
+					Return(db.User{}, db.ErrRecordNotFound)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusNotFound, recorder.Code)
@@ -325,6 +528,95 @@ func TestLoginUserAPI(t *testing.T) {
 				require.Equal(t, http.StatusInternalServerError, recorder.Code)
 			},
 		},
+		{
+			name: "Identifier",
+			body: gin.H{
+				"identifier": user.Username,
+				"password":   password,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "Email",
+			body: gin.H{
+				"identifier": user.Email,
+				"password":   password,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Any()).
+					Times(0)
+				store.EXPECT().
+					GetUserByEmail(gomock.Any(), gomock.Eq(user.Email)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					CreateSession(gomock.Any(), gomock.Any()).
+					Times(1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "EmailNotFound",
+			body: gin.H{
+				"identifier": "notfound@email.com",
+				"password":   password,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByEmail(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.User{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidIdentifier",
+			body: gin.H{
+				"identifier": "invalid-user#1",
+				"password":   password,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Any()).
+					Times(0)
+				store.EXPECT().
+					GetUserByEmail(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NoIdentifier",
+			body: gin.H{
+				"password": password,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
 		{
 			name: "InvalidUsername",
 			body: gin.H{
@@ -334,8 +626,7 @@ func TestLoginUserAPI(t *testing.T) {
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
 					GetUser(gomock.Any(), gomock.Any()).
-					Times(1).
-					Return(db.User{}, sql.ErrNoRows)
+					Times(0)
 			},
 			checkResponse: func(recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
@@ -370,6 +661,142 @@ func TestLoginUserAPI(t *testing.T) {
 	}
 }
 
+func TestLoginUserLockoutAPI(t *testing.T) {
+	user, password := factory.UserWithPassword(t)
+	key := db.GetLoginLockoutParams{Username: user.Username, ClientIp: "10.0.0.1"}
+
+	testCases := []struct {
+		name          string
+		password      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "Locked",
+			password: password,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().CountClientLoginFailures(gomock.Any(), gomock.Any()).Times(1).Return(int64(3), nil)
+				store.EXPECT().
+					GetLoginLockout(gomock.Any(), gomock.Eq(key)).
+					Times(1).
+					Return(db.LoginLockout{Username: user.Username, LockedUntil: time.Now().Add(time.Minute)}, nil)
+				store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusLocked, recorder.Code)
+				requireErrorBody(t, recorder.Body, service.ReasonAccountLocked)
+			},
+		},
+		{
+			name:     "LockoutOver",
+			password: password,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().CountClientLoginFailures(gomock.Any(), gomock.Any()).Times(1).Return(int64(3), nil)
+				store.EXPECT().
+					GetLoginLockout(gomock.Any(), gomock.Eq(key)).
+					Times(1).
+					Return(db.LoginLockout{Username: user.Username, LockedUntil: time.Now().Add(-time.Minute)}, nil)
+				store.EXPECT().
+					DeleteClientLoginFailures(gomock.Any(), gomock.Eq(db.DeleteClientLoginFailuresParams{Username: user.Username, ClientIp: key.ClientIp})).
+					Times(1)
+				store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(1)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:     "IncorrectPassword",
+			password: "incorrect",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().CountClientLoginFailures(gomock.Any(), gomock.Any()).Times(1).Return(int64(3), nil)
+				store.EXPECT().GetLoginLockout(gomock.Any(), gomock.Eq(key)).Times(1).Return(db.LoginLockout{}, db.ErrRecordNotFound)
+				store.EXPECT().
+					RecordLoginFailureTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.RecordLoginFailureTxResult{Failures: 1}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				requireErrorBody(t, recorder.Body, service.ReasonInvalidCredentials)
+			},
+		},
+		{
+			name:     "LockedByFailure",
+			password: "incorrect",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().CountClientLoginFailures(gomock.Any(), gomock.Any()).Times(1).Return(int64(3), nil)
+				store.EXPECT().GetLoginLockout(gomock.Any(), gomock.Eq(key)).Times(1).Return(db.LoginLockout{}, db.ErrRecordNotFound)
+				store.EXPECT().
+					RecordLoginFailureTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.RecordLoginFailureTxResult{
+						Failures: 3,
+						Lockout:  &db.LoginLockout{Username: user.Username, Failures: 3, LockedUntil: time.Now().Add(time.Hour)},
+					}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusLocked, recorder.Code)
+				requireErrorBody(t, recorder.Body, service.ReasonAccountLocked)
+			},
+		},
+		{
+			name:     "TooManyClientFailures",
+			password: password,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().
+					CountClientLoginFailures(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CountClientLoginFailuresParams) (int64, error) {
+						require.Equal(t, key.ClientIp, arg.ClientIp)
+						return 10, nil
+					})
+				store.EXPECT().GetLoginLockout(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+				requireErrorBody(t, recorder.Body, service.ReasonTooManyLoginFailures)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServerWithConfig(t, store, nil, func(config *util.Config) {
+				config.LoginMaxFailures = 3
+				config.LoginFailureWindow = time.Minute
+				config.LoginLockoutDuration = time.Hour
+				config.LoginClientMaxFailures = 10
+			})
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{"username": user.Username, "password": tc.password})
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/api/v1/users/login", bytes.NewReader(data))
+			require.NoError(t, err)
+			request.RemoteAddr = key.ClientIp + ":4321"
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
 type eqCreateUserParamsMatcher struct {
 	arg      db.CreateUserParams
 	password string
@@ -400,20 +827,6 @@ func (e eqCreateUserParamsMatcher) String() string {
 	return fmt.Sprintf("matches arg %v and password %v", e.arg, e.password)
 }
 
-func randomUser(t *testing.T) (user db.User, password string) {
-	password = util.RandomString(6)
-	hashedPassword, err := util.HashPassword(password)
-	require.NoError(t, err)
-
-	user = db.User{
-		Username:       util.RandomOwner(),
-		HashedPassword: hashedPassword,
-		FullName:       util.RandomOwner(),
-		Email:          util.RandomEmail(),
-	}
-	return
-}
-
 func requireBodyMatchUser(t *testing.T, body *bytes.Buffer, user db.User) {
 	data, err := io.ReadAll(body)
 	require.NoError(t, err)
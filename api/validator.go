@@ -2,6 +2,8 @@ package api
 
 import (
 	"go-backend/util"
+	"reflect"
+	"strings"
 
 	"github.com/go-playground/validator/v10"
 )
@@ -16,3 +18,27 @@ var validCurrency validator.Func = func(fieldLevel validator.FieldLevel) bool {
 	}
 	return false
 }
+
+// The `validAccountNumber` function validates the account numbers of the requests, checking their check
+// digits.
+var validAccountNumber validator.Func = func(fieldLevel validator.FieldLevel) bool {
+	if number, ok := fieldLevel.Field().Interface().(string); ok {
+		return util.ValidAccountNumber(number)
+	}
+	return false
+}
+
+// The `requestFieldName` function names the fields of the requests in validation errors after their
+// json, form or uri tag, i.e. as the client sent them, falling back to the name of the Go field.
+func requestFieldName(field reflect.StructField) string {
+	for _, key := range []string{"json", "form", "uri"} {
+		name, _, _ := strings.Cut(field.Tag.Get(key), ",")
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
@@ -0,0 +1,126 @@
+package api
+
+import (
+	"go-backend/util"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// problemContentType is the content type of the error responses of v2, the problem details of RFC 9457.
+const problemContentType = "application/problem+json"
+
+// The apiVersion type is a version of the API, served under /api/<name>. Every version has the same
+// routes, served by the same handlers, and differs by the mappers between its requests and responses and
+// those of the handlers.
+// @property {[]gin.HandlerFunc} middleware - maps the requests of the version to those the handlers
+// take, e.g. renamed parameters, before they run.
+// @property mapResponse - returns the body of a response of the version given the one written by the
+// handlers, which is sent as is when nil. It can set headers, e.g. the content type.
+type apiVersion struct {
+	name        string
+	middleware  []gin.HandlerFunc
+	mapResponse func(ctx *gin.Context, status int, obj interface{}) interface{}
+}
+
+// apiVersions are the versions of the API, oldest first. v1 is stable: its responses don't change, the
+// breaking changes being shipped in the newer versions.
+var apiVersions = []apiVersion{
+	{name: "v1"},
+	{name: "v2", mapResponse: problemResponses},
+}
+
+// The `requestVersion` function returns the version of the API a request was sent to, told by its path
+// so that the middlewares responding before the routing, e.g. with a 413 status, respond like the
+// version too. The requests outside of the API, e.g. /ready, are taken as v1 ones.
+func requestVersion(ctx *gin.Context) apiVersion {
+	path := ctx.Request.URL.Path
+	for _, version := range apiVersions {
+		prefix := "/api/" + version.name
+		if path == prefix || strings.HasPrefix(path, prefix+"/") {
+			return version
+		}
+	}
+	return apiVersions[0]
+}
+
+// The `v1Path` function returns the path a route of any version has in v1. The settings of the routes,
+// e.g. their timeout or their deprecation, are keyed by it so that they apply to every version.
+func v1Path(path string) string {
+	for _, version := range apiVersions[1:] {
+		prefix := "/api/" + version.name
+		if path == prefix || strings.HasPrefix(path, prefix+"/") {
+			return "/api/" + apiVersions[0].name + path[len(prefix):]
+		}
+	}
+	return path
+}
+
+// The `addVersionRoutes` function adds the routes of the API to the router of a version. The routes
+// require an access token unless they are declared anonymous, whatever the order they are added in.
+func (server *Server) addVersionRoutes(router *gin.RouterGroup) {
+	apiRouter := server.newRouteGroup(router)
+	server.addUserRoutes(apiRouter)
+	server.addAuthRoutes(apiRouter)
+	server.addTokenRoutes(apiRouter)
+	server.addDocsRoutes(apiRouter)
+	server.addChangelogRoutes(apiRouter)
+	server.addPersonalDataRoutes(apiRouter)
+	server.addUsageRoutes(apiRouter)
+	server.addSigningKeyRoutes(apiRouter)
+	server.addDashboardRoutes(apiRouter)
+	server.addAccountRoutes(apiRouter)
+	server.addAccountInvitationRoutes(apiRouter)
+	server.addTransferRoutes(apiRouter)
+	server.addBeneficiaryRoutes(apiRouter)
+	server.addPaymentRequestRoutes(apiRouter)
+	server.addPaymentLinkRoutes(apiRouter)
+	server.addMandateRoutes(apiRouter)
+	server.addStandingOrderRoutes(apiRouter)
+	server.addExternalTransferRoutes(apiRouter)
+	server.addPendingTransferRoutes(apiRouter)
+	server.addCardRoutes(apiRouter)
+	server.addChequeDepositRoutes(apiRouter)
+	server.addJobRoutes(apiRouter)
+	server.addNotificationRoutes(apiRouter)
+	server.addOrganizationRoutes(apiRouter)
+	server.addAdminRoutes(apiRouter)
+	server.addGraphQLRoutes(apiRouter)
+}
+
+// The problemResponse type is the body of the error responses of v2, the problem details of RFC 9457
+// with the code and the field errors of the error as extension members.
+// @property {string} Type - always about:blank, the errors being told apart by their code.
+// @property {string} Title - the text of the status, e.g. Bad Request.
+// @property {string} Detail - the description of the error, in the language of the request.
+// @property {string} Instance - the path the request was sent to.
+type problemResponse struct {
+	Type     string            `json:"type"`
+	Title    string            `json:"title"`
+	Status   int               `json:"status"`
+	Detail   string            `json:"detail"`
+	Instance string            `json:"instance"`
+	Code     string            `json:"code"`
+	Errors   []util.FieldError `json:"errors"`
+}
+
+// The `problemResponses` function maps the error bodies written by the handlers to the problem details
+// of v2, leaving the other bodies as they are.
+func problemResponses(ctx *gin.Context, status int, obj interface{}) interface{} {
+	body, ok := obj.(util.ErrorBody)
+	if !ok {
+		return obj
+	}
+
+	ctx.Header("Content-Type", problemContentType)
+	return problemResponse{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   body.Message,
+		Instance: ctx.Request.URL.Path,
+		Code:     body.Code,
+		Errors:   body.FieldErrors,
+	}
+}
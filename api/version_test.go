@@ -0,0 +1,158 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	mockdb "go-backend/db/mock"
+	"go-backend/testutil/factory"
+	"go-backend/util"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersionsHaveSameRoutes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := newTestServer(t, mockdb.NewMockStore(ctrl))
+
+	routes := make(map[string]map[string]bool)
+	for _, route := range server.router.Routes() {
+		for _, version := range apiVersions {
+			prefix := "/api/" + version.name + "/"
+			if strings.HasPrefix(route.Path, prefix) {
+				if routes[version.name] == nil {
+					routes[version.name] = make(map[string]bool)
+				}
+				routes[version.name][route.Method+" /"+strings.TrimPrefix(route.Path, prefix)] = true
+			}
+		}
+	}
+
+	require.NotEmpty(t, routes["v1"])
+	for _, version := range apiVersions[1:] {
+		require.Equal(t, routes["v1"], routes[version.name], "routes of %s", version.name)
+	}
+}
+
+func TestV1Path(t *testing.T) {
+	require.Equal(t, "/api/v1/accounts/:id", v1Path("/api/v1/accounts/:id"))
+	require.Equal(t, "/api/v1/accounts/:id", v1Path("/api/v2/accounts/:id"))
+	require.Equal(t, "/api/v1", v1Path("/api/v2"))
+	require.Equal(t, "/api/v2x/accounts", v1Path("/api/v2x/accounts"))
+	require.Equal(t, "/ready", v1Path("/ready"))
+}
+
+func TestVersionErrorResponses(t *testing.T) {
+	user := factory.User()
+	account := factory.Account(factory.OwnedBy(user.Username))
+
+	testCases := []struct {
+		name          string
+		version       string
+		body          string
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "V1ValidationError",
+			version: "v1",
+			body:    `{"from_account_id": 0}`,
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Equal(t, "application/json; charset=utf-8", recorder.Header().Get("Content-Type"))
+
+				body := requireErrorBody(t, recorder.Body, util.ErrorCodeValidationFailed)
+				require.NotEmpty(t, body.FieldErrors)
+			},
+		},
+		{
+			name:    "V2ValidationError",
+			version: "v2",
+			body:    `{"from_account_id": 0}`,
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Equal(t, problemContentType, recorder.Header().Get("Content-Type"))
+
+				problem := requireProblem(t, recorder.Body, http.StatusBadRequest, util.ErrorCodeValidationFailed)
+				require.Equal(t, "/api/v2/transfers", problem.Instance)
+				require.NotEmpty(t, problem.Errors)
+				require.NotEmpty(t, problem.Errors[0].Field)
+			},
+		},
+		{
+			name:    "V2PayloadTooLarge",
+			version: "v2",
+			body:    fmt.Sprintf(`{"memo": %q}`, strings.Repeat("a", 2<<20)),
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				// rejected before the routing, and still in the format of the version
+				require.Equal(t, http.StatusRequestEntityTooLarge, recorder.Code)
+				requireProblem(t, recorder.Body, http.StatusRequestEntityTooLarge, util.ErrorCodePayloadTooLarge)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			server := newTestServerWithConfig(t, store, nil, func(config *util.Config) {
+				config.MaxRequestBodyBytes = 1 << 20
+			})
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/api/%s/transfers", tc.version)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewBufferString(tc.body))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+
+	t.Run("V2SuccessUnchanged", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		store := mockdb.NewMockStore(ctrl)
+		store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(2).Return(account, nil)
+		server := newTestServer(t, store)
+
+		bodies := make(map[string]string)
+		for _, version := range []string{"v1", "v2"} {
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/api/%s/accounts/%d", version, account.ID), nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, http.StatusOK, recorder.Code)
+			bodies[version] = recorder.Body.String()
+		}
+		require.JSONEq(t, bodies["v1"], bodies["v2"])
+	})
+}
+
+func requireProblem(t *testing.T, body *bytes.Buffer, status int, code string) problemResponse {
+	var problem problemResponse
+	err := json.Unmarshal(body.Bytes(), &problem)
+	require.NoError(t, err)
+
+	require.Equal(t, "about:blank", problem.Type)
+	require.Equal(t, http.StatusText(status), problem.Title)
+	require.Equal(t, status, problem.Status)
+	require.Equal(t, code, problem.Code)
+	require.NotEmpty(t, problem.Detail)
+	return problem
+}
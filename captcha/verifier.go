@@ -0,0 +1,143 @@
+// Package captcha tells people apart from bots at signup, by checking with the provider of the CAPTCHA
+// widget, hCaptcha or reCAPTCHA, the token the widget gave the user once they solved it.
+package captcha
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-backend/util"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Providers of the CAPTCHA, selected with the CAPTCHA_PROVIDER config.
+const (
+	ProviderHCaptcha  = "hcaptcha"
+	ProviderReCAPTCHA = "recaptcha"
+)
+
+const (
+	hCaptchaURL   = "https://api.hcaptcha.com/siteverify"
+	reCAPTCHAURL  = "https://www.google.com/recaptcha/api/siteverify"
+	verifyTimeout = 10 * time.Second
+)
+
+// ErrFailed matches the errors of the tokens that don't prove the CAPTCHA was solved: missing, invalid,
+// expired or already used ones.
+var ErrFailed = errors.New("CAPTCHA verification failed")
+
+// The Verifier interface checks the tokens of the CAPTCHA widget.
+type Verifier interface {
+	// Verify checks the token the widget gave the user at `remoteIP`. The error matches ErrFailed when the
+	// token doesn't prove the CAPTCHA was solved, other errors meaning the provider couldn't tell.
+	Verify(ctx context.Context, token string, remoteIP string) error
+}
+
+// The function creates the verifier of the CAPTCHA_PROVIDER config, one accepting every request when it
+// is empty.
+func NewVerifier(config util.Config) (Verifier, error) {
+	switch config.CaptchaProvider {
+	case "":
+		return NoopVerifier{}, nil
+	case ProviderHCaptcha:
+		return NewSiteVerifier(hCaptchaURL, config.CaptchaSecret)
+	case ProviderReCAPTCHA:
+		return NewSiteVerifier(reCAPTCHAURL, config.CaptchaSecret)
+	}
+
+	return nil, fmt.Errorf("unknown CAPTCHA provider %s", config.CaptchaProvider)
+}
+
+// The NoopVerifier type accepts every request, for the deployments without a CAPTCHA.
+type NoopVerifier struct{}
+
+func (NoopVerifier) Verify(ctx context.Context, token string, remoteIP string) error {
+	return nil
+}
+
+// The SiteVerifier type checks the tokens with the siteverify endpoint of the provider, which hCaptcha
+// and reCAPTCHA have in common.
+type SiteVerifier struct {
+	url    string
+	secret string
+	client *http.Client
+}
+
+// The function creates a verifier authenticating with the secret key of the site at the siteverify
+// endpoint at `url`.
+func NewSiteVerifier(url string, secret string) (Verifier, error) {
+	if secret == "" {
+		return nil, errors.New("CAPTCHA secret is required")
+	}
+
+	return &SiteVerifier{
+		url:    url,
+		secret: secret,
+		client: &http.Client{Timeout: verifyTimeout},
+	}, nil
+}
+
+type siteVerifyResponse struct {
+	Success    bool     `json:"success"`
+	ErrorCodes []string `json:"error-codes"`
+}
+
+// secretErrorCodes are the error codes of the siteverify endpoint blaming the configuration of the
+// bank rather than the token of the user.
+var secretErrorCodes = map[string]bool{
+	"missing-input-secret":    true,
+	"invalid-input-secret":    true,
+	"sitekey-secret-mismatch": true,
+}
+
+func (verifier *SiteVerifier) Verify(ctx context.Context, token string, remoteIP string) error {
+	if token == "" {
+		return fmt.Errorf("%w: the token is missing", ErrFailed)
+	}
+
+	form := url.Values{
+		"secret":   {verifier.secret},
+		"response": {token},
+	}
+	if remoteIP != "" {
+		form.Set("remoteip", remoteIP)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, verifier.url, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	response, err := verifier.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		detail, _ := io.ReadAll(io.LimitReader(response.Body, 1024))
+		return fmt.Errorf("CAPTCHA provider responded with status %d: %s", response.StatusCode, detail)
+	}
+
+	var res siteVerifyResponse
+	err = json.NewDecoder(response.Body).Decode(&res)
+	if err != nil {
+		return fmt.Errorf("cannot decode the response of the CAPTCHA provider: %w", err)
+	}
+	if res.Success {
+		return nil
+	}
+
+	for _, code := range res.ErrorCodes {
+		if secretErrorCodes[code] {
+			return fmt.Errorf("CAPTCHA provider rejected the secret: %s", code)
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrFailed, strings.Join(res.ErrorCodes, ", "))
+}
@@ -0,0 +1,74 @@
+package captcha
+
+import (
+	"context"
+	"go-backend/util"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSiteVerifier(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		require.Equal(t, "test-secret", r.PostForm.Get("secret"))
+		require.Equal(t, "203.0.113.7", r.PostForm.Get("remoteip"))
+
+		switch r.PostForm.Get("response") {
+		case "solved":
+			_, _ = w.Write([]byte(`{"success": true, "hostname": "bank.test"}`))
+		case "expired":
+			_, _ = w.Write([]byte(`{"success": false, "error-codes": ["timeout-or-duplicate"]}`))
+		case "misconfigured":
+			_, _ = w.Write([]byte(`{"success": false, "error-codes": ["invalid-input-secret"]}`))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	verifier, err := NewSiteVerifier(server.URL, "test-secret")
+	require.NoError(t, err)
+
+	err = verifier.Verify(context.Background(), "solved", "203.0.113.7")
+	require.NoError(t, err)
+
+	err = verifier.Verify(context.Background(), "expired", "203.0.113.7")
+	require.ErrorIs(t, err, ErrFailed)
+	require.ErrorContains(t, err, "timeout-or-duplicate")
+
+	// the secret of the bank is to blame, not the user
+	err = verifier.Verify(context.Background(), "misconfigured", "203.0.113.7")
+	require.Error(t, err)
+	require.NotErrorIs(t, err, ErrFailed)
+
+	err = verifier.Verify(context.Background(), "unavailable", "203.0.113.7")
+	require.ErrorContains(t, err, "status 500")
+	require.NotErrorIs(t, err, ErrFailed)
+
+	// missing tokens aren't sent to the provider
+	err = verifier.Verify(context.Background(), "", "203.0.113.7")
+	require.ErrorIs(t, err, ErrFailed)
+}
+
+func TestNewVerifier(t *testing.T) {
+	verifier, err := NewVerifier(util.Config{})
+	require.NoError(t, err)
+	require.NoError(t, verifier.Verify(context.Background(), "", ""))
+
+	verifier, err = NewVerifier(util.Config{CaptchaProvider: ProviderHCaptcha, CaptchaSecret: "secret"})
+	require.NoError(t, err)
+	require.Equal(t, hCaptchaURL, verifier.(*SiteVerifier).url)
+
+	verifier, err = NewVerifier(util.Config{CaptchaProvider: ProviderReCAPTCHA, CaptchaSecret: "secret"})
+	require.NoError(t, err)
+	require.Equal(t, reCAPTCHAURL, verifier.(*SiteVerifier).url)
+
+	_, err = NewVerifier(util.Config{CaptchaProvider: ProviderHCaptcha})
+	require.Error(t, err)
+
+	_, err = NewVerifier(util.Config{CaptchaProvider: "turnstile", CaptchaSecret: "secret"})
+	require.Error(t, err)
+}
@@ -0,0 +1,145 @@
+package clock
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// The Clock interface tells the time and ticks, so that the code depending on the time is tested by
+// advancing a `Fake` clock rather than with sleeps.
+type Clock interface {
+	Now() time.Time
+	NewTicker(interval time.Duration) Ticker
+}
+
+// The Ticker interface delivers the ticks of a clock on C, like a `time.Ticker`.
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// Real is the clock of the system.
+var Real Clock = realClock{}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) NewTicker(interval time.Duration) Ticker {
+	return realTicker{ticker: time.NewTicker(interval)}
+}
+
+type realTicker struct {
+	ticker *time.Ticker
+}
+
+func (ticker realTicker) C() <-chan time.Time {
+	return ticker.ticker.C
+}
+
+func (ticker realTicker) Stop() {
+	ticker.ticker.Stop()
+}
+
+// The Fake type is a clock whose time only moves when it is advanced, firing the tickers due by then.
+// Like those of a `time.Ticker`, the ticks of a ticker whose previous one wasn't received are dropped.
+type Fake struct {
+	mu      sync.Mutex
+	now     time.Time
+	tickers []*fakeTicker
+}
+
+// The function creates a fake clock telling `now` until it is advanced.
+func NewFake(now time.Time) *Fake {
+	return &Fake{now: now}
+}
+
+func (clock *Fake) Now() time.Time {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+
+	return clock.now
+}
+
+func (clock *Fake) NewTicker(interval time.Duration) Ticker {
+	if interval <= 0 {
+		panic("non-positive interval for NewTicker")
+	}
+
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+
+	ticker := &fakeTicker{
+		clock:    clock,
+		c:        make(chan time.Time, 1),
+		interval: interval,
+		next:     clock.now.Add(interval),
+	}
+	clock.tickers = append(clock.tickers, ticker)
+	return ticker
+}
+
+// The `Advance` function moves the time of the clock forward by `duration`, firing the tickers in the
+// order of their ticks.
+func (clock *Fake) Advance(duration time.Duration) {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+
+	end := clock.now.Add(duration)
+	for {
+		due := make([]*fakeTicker, 0, len(clock.tickers))
+		for _, ticker := range clock.tickers {
+			if !ticker.next.After(end) {
+				due = append(due, ticker)
+			}
+		}
+		if len(due) == 0 {
+			break
+		}
+
+		sort.Slice(due, func(i, j int) bool { return due[i].next.Before(due[j].next) })
+		ticker := due[0]
+		clock.now = ticker.next
+		select {
+		case ticker.c <- ticker.next:
+		default:
+		}
+		ticker.next = ticker.next.Add(ticker.interval)
+	}
+	clock.now = end
+}
+
+// The `Set` function moves the clock to `now`, firing the tickers due by then when it is in the future.
+func (clock *Fake) Set(now time.Time) {
+	clock.Advance(now.Sub(clock.Now()))
+}
+
+func (clock *Fake) stop(stopped *fakeTicker) {
+	clock.mu.Lock()
+	defer clock.mu.Unlock()
+
+	for i, ticker := range clock.tickers {
+		if ticker == stopped {
+			clock.tickers = append(clock.tickers[:i], clock.tickers[i+1:]...)
+			return
+		}
+	}
+}
+
+type fakeTicker struct {
+	clock    *Fake
+	c        chan time.Time
+	interval time.Duration
+	next     time.Time
+}
+
+func (ticker *fakeTicker) C() <-chan time.Time {
+	return ticker.c
+}
+
+func (ticker *fakeTicker) Stop() {
+	ticker.clock.stop(ticker)
+}
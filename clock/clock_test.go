@@ -0,0 +1,49 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFakeClock(t *testing.T) {
+	start := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	clock := NewFake(start)
+	require.Equal(t, start, clock.Now())
+
+	clock.Advance(time.Hour)
+	require.Equal(t, start.Add(time.Hour), clock.Now())
+
+	clock.Set(start)
+	require.Equal(t, start, clock.Now())
+}
+
+func TestFakeTicker(t *testing.T) {
+	start := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	clock := NewFake(start)
+	ticker := clock.NewTicker(time.Minute)
+
+	clock.Advance(59 * time.Second)
+	require.Empty(t, ticker.C())
+
+	clock.Advance(time.Second)
+	require.Equal(t, start.Add(time.Minute), <-ticker.C())
+
+	// the ticks that weren't received are dropped, like those of a time.Ticker
+	clock.Advance(3 * time.Minute)
+	require.Equal(t, start.Add(2*time.Minute), <-ticker.C())
+	require.Empty(t, ticker.C())
+
+	ticker.Stop()
+	clock.Advance(time.Hour)
+	require.Empty(t, ticker.C())
+}
+
+func TestRealClock(t *testing.T) {
+	require.WithinDuration(t, time.Now(), Real.Now(), time.Second)
+
+	ticker := Real.NewTicker(time.Millisecond)
+	defer ticker.Stop()
+	<-ticker.C()
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"go-backend/service"
+	"io"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/spf13/cobra"
+)
+
+// The superuserInput type holds the admin created by the `createsuperuser` command, validated like the
+// registration of a user by the API.
+type superuserInput struct {
+	Username string `validate:"required,alphanum"`
+	Password string `validate:"required,min=6"`
+	FullName string `validate:"required"`
+	Email    string `validate:"required,email"`
+}
+
+// The `newCreateSuperuserCommand` function creates the `createsuperuser` command, which registers a user
+// with the admin role. The password is read from the standard input when the flag isn't set, so that it
+// doesn't show in the list of processes.
+func newCreateSuperuserCommand(cli *cli) *cobra.Command {
+	var input superuserInput
+
+	cmd := &cobra.Command{
+		Use:   "createsuperuser",
+		Short: "Create a user with the admin role",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if input.Password == "" {
+				password, err := readPassword(cmd.InOrStdin())
+				if err != nil {
+					return err
+				}
+				input.Password = password
+			}
+
+			err := validator.New().Struct(input)
+			if err != nil {
+				return err
+			}
+
+			service, closePool, err := newService(cmd.Context(), cli.config)
+			if err != nil {
+				return err
+			}
+			defer closePool()
+
+			user, err := service.CreateSuperuser(cmd.Context(), serviceUserParams(input))
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "created admin %s\n", user.Username)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&input.Username, "username", "", "username of the admin")
+	cmd.Flags().StringVar(&input.FullName, "full-name", "", "full name of the admin")
+	cmd.Flags().StringVar(&input.Email, "email", "", "email of the admin")
+	cmd.Flags().StringVar(&input.Password, "password", "", "password of the admin, read from the standard input when empty")
+
+	return cmd
+}
+
+func serviceUserParams(input superuserInput) service.CreateUserParams {
+	return service.CreateUserParams{
+		Username: input.Username,
+		Password: input.Password,
+		FullName: input.FullName,
+		Email:    input.Email,
+	}
+}
+
+// The `readPassword` function reads the password from the first line of the input.
+func readPassword(in io.Reader) (string, error) {
+	line, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("cannot read password: %w", err)
+	}
+
+	return strings.TrimRight(line, "\r\n"), nil
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// The `newGenTokenCommand` function creates the `gen-token` command, which issues an access token of a
+// user without their password, e.g. for an operator checking what the user sees.
+func newGenTokenCommand(cli *cli) *cobra.Command {
+	var username string
+	var duration time.Duration
+	var scopes []string
+
+	cmd := &cobra.Command{
+		Use:   "gen-token",
+		Short: "Issue an access token of a user",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			service, closePool, err := newService(cmd.Context(), cli.config)
+			if err != nil {
+				return err
+			}
+			defer closePool()
+
+			accessToken, payload, err := service.IssueAccessToken(cmd.Context(), username, scopes, duration)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintln(cmd.OutOrStdout(), accessToken)
+			fmt.Fprintf(cmd.ErrOrStderr(), "token of %s expires at %s\n", payload.Username, payload.ExpiredAt.Format(time.RFC3339))
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&username, "username", "", "user the token is issued to")
+	cmd.Flags().DurationVar(&duration, "duration", time.Hour, "how long the token is valid")
+	cmd.Flags().StringSliceVar(&scopes, "scope", nil, "scope the token is limited to, e.g. accounts:read, every request being allowed when none is given")
+	cmd.MarkFlagRequired("username")
+
+	return cmd
+}
+
+// The `newGenTokenKeyCommand` function creates the `gen-token-key` command, which generates the Ed25519
+// key pair of the paseto_v4_public tokens: the private key signing them, kept by the bank, and the public
+// key handed to the services verifying them.
+func newGenTokenKeyCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "gen-token-key",
+		Short: "Generate the key pair of the public access tokens",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "TOKEN_PRIVATE_KEY=%s\n", hex.EncodeToString(privateKey.Seed()))
+			fmt.Fprintf(cmd.OutOrStdout(), "TOKEN_PUBLIC_KEY=%s\n", hex.EncodeToString(publicKey))
+			return nil
+		},
+	}
+}
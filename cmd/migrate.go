@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"go-backend/db/migration"
+	"log"
+	"strconv"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/spf13/cobra"
+)
+
+// The `newMigrateCommand` function creates the `migrate` command, which migrates the database with the
+// embedded migrations.
+func newMigrateCommand(cli *cli) *cobra.Command {
+	return &cobra.Command{
+		Use:   "migrate up [N] | down [N] | version | force VERSION",
+		Short: "Migrate the database",
+		Args:  cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runMigrateCommand(cli.config.DBSource, args)
+		},
+	}
+}
+
+// The `runMigrateCommand` function runs the `migrate` subcommand against the database with the embedded
+// migrations, e.g. `main migrate up`, `main migrate down 1`, `main migrate version` or `main migrate force 3`.
+func runMigrateCommand(dbSource string, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: migrate up [N] | down [N] | version | force VERSION")
+	}
+
+	m, err := migration.New(dbSource)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	var n int
+	if len(args) > 1 {
+		n, err = strconv.Atoi(args[1])
+		if err != nil || n <= 0 {
+			return fmt.Errorf("invalid number of migrations %q", args[1])
+		}
+	}
+
+	switch args[0] {
+	case "up":
+		if n > 0 {
+			err = m.Steps(n)
+		} else {
+			err = m.Up()
+		}
+	case "down":
+		if n > 0 {
+			err = m.Steps(-n)
+		} else {
+			err = m.Down()
+		}
+	case "version":
+		version, dirty, err := m.Version()
+		if err != nil {
+			return err
+		}
+		log.Printf("version %d (dirty: %t)", version, dirty)
+		return nil
+	case "force":
+		if n == 0 {
+			return errors.New("force requires the version to set")
+		}
+		err = m.Force(n)
+	default:
+		return fmt.Errorf("unknown migrate command %q", args[0])
+	}
+
+	if err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return err
+	}
+
+	log.Println("migrate", args[0], "completed")
+	return nil
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	db "go-backend/db/sqlc"
+	"go-backend/service"
+	"go-backend/token"
+	"go-backend/tracking"
+	"go-backend/util"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// The cli type holds the flags shared by every command, and the config they load from the file at
+// configPath before running. flushTracking sends the errors still buffered by the error tracking.
+type cli struct {
+	configPath    string
+	config        util.Config
+	flushTracking func()
+}
+
+// The `Execute` function runs the command given on the command line, serving the bank when there is
+// none, and exits with a non-zero status when it fails.
+func Execute() {
+	err := newRootCommand().Execute()
+	if err != nil {
+		os.Exit(1)
+	}
+}
+
+// The `newRootCommand` function creates the root command, whose subcommands share the config loader.
+func newRootCommand() *cobra.Command {
+	cli := &cli{}
+
+	root := &cobra.Command{
+		Use:          "main",
+		Short:        "Run and operate the bank",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			var err error
+			cli.config, err = util.LoadConfig(cli.configPath)
+			if err != nil {
+				return fmt.Errorf("cannot load config: %w", err)
+			}
+
+			cli.flushTracking, err = tracking.Init(cli.config)
+			return err
+		},
+		PersistentPostRun: func(cmd *cobra.Command, args []string) {
+			cli.flushTracking()
+		},
+		// the container runs the binary without a command
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return serve(cmd.Context(), cli.config, defaultServeOptions)
+		},
+	}
+	root.PersistentFlags().StringVar(&cli.configPath, "config", "app.env", "path of the config file")
+
+	root.AddCommand(
+		newServeCommand(cli),
+		newWorkerCommand(cli),
+		newMigrateCommand(cli),
+		newCreateSuperuserCommand(cli),
+		newGenTokenCommand(cli),
+		newGenTokenKeyCommand(),
+		newSeedCommand(cli),
+	)
+
+	return root
+}
+
+// The `newService` function creates a service on a single connection pool to the primary database, for
+// the commands operating the bank. The returned function closes the pool.
+func newService(ctx context.Context, config util.Config) (*service.Service, func(), error) {
+	tokenMaker, err := token.NewMaker(token.MakerConfig{
+		Kind:             config.TokenKind,
+		SymmetricKey:     config.TokenSymmetricKey,
+		PrivateKey:       config.TokenPrivateKey,
+		PublicKey:        config.TokenPublicKey,
+		KeyID:            config.TokenKeyID,
+		VerificationKeys: config.TokenVerificationKeys,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot create token maker: %w", err)
+	}
+
+	isolation, err := transferIsolation(config.TransferIsolation)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	connPool, err := newConnPool(ctx, config, config.DBSource)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot connect to db: %w", err)
+	}
+
+	store := db.NewRetryStore(db.NewStore(connPool, db.WithTransferIsolation(isolation)), retryPolicy(config))
+	return service.New(config, store, tokenMaker, nil), connPool.Close, nil
+}
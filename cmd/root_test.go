@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// runCommand runs the root command with the args and a config file of its own.
+func runCommand(t *testing.T, stdin string, args ...string) error {
+	configPath := filepath.Join(t.TempDir(), "app.env")
+	err := os.WriteFile(configPath, []byte("TOKEN_SYMMETRIC_KEY=12345678901234567890123456789012\n"), 0o600)
+	require.NoError(t, err)
+
+	root := newRootCommand()
+	root.SetArgs(append(args, "--config", configPath))
+	root.SetIn(strings.NewReader(stdin))
+	root.SetOut(&bytes.Buffer{})
+	root.SetErr(&bytes.Buffer{})
+	return root.Execute()
+}
+
+func TestCreateSuperuserCommandValidatesInput(t *testing.T) {
+	testCases := []struct {
+		name  string
+		stdin string
+		args  []string
+		field string
+	}{
+		{
+			name:  "InvalidUsername",
+			args:  []string{"--username", "bad-name", "--full-name", "Ada", "--email", "ada@bank.com", "--password", "secret"},
+			field: "Username",
+		},
+		{
+			name:  "InvalidEmail",
+			args:  []string{"--username", "ada", "--full-name", "Ada", "--email", "ada", "--password", "secret"},
+			field: "Email",
+		},
+		{
+			name:  "ShortPasswordFromStdin",
+			stdin: "abc\n",
+			args:  []string{"--username", "ada", "--full-name", "Ada", "--email", "ada@bank.com"},
+			field: "Password",
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			err := runCommand(t, tc.stdin, append([]string{"createsuperuser"}, tc.args...)...)
+			require.ErrorContains(t, err, tc.field)
+		})
+	}
+}
+
+func TestCommandArgs(t *testing.T) {
+	err := runCommand(t, "", "migrate")
+	require.Error(t, err)
+
+	err = runCommand(t, "", "gen-token")
+	require.ErrorContains(t, err, "username")
+
+	err = runCommand(t, "", "unknown")
+	require.Error(t, err)
+}
+
+func TestReadPassword(t *testing.T) {
+	password, err := readPassword(strings.NewReader("secret\r\nignored\n"))
+	require.NoError(t, err)
+	require.Equal(t, "secret", password)
+
+	password, err = readPassword(strings.NewReader("secret"))
+	require.NoError(t, err)
+	require.Equal(t, "secret", password)
+}
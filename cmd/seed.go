@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"fmt"
+	"go-backend/service"
+
+	"github.com/spf13/cobra"
+)
+
+// The `newSeedCommand` function creates the `seed` command, which fills a local database with random
+// users, accounts and transfers for developing against meaningful data. It isn't meant to be run
+// against a production database.
+func newSeedCommand(cli *cli) *cobra.Command {
+	arg := service.SeedParams{}
+
+	cmd := &cobra.Command{
+		Use:   "seed",
+		Short: "Seed the database with random users, accounts and transfers for local development",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(arg.Password) < 6 {
+				return fmt.Errorf("password must be at least 6 characters")
+			}
+
+			service, closePool, err := newService(cmd.Context(), cli.config)
+			if err != nil {
+				return err
+			}
+			defer closePool()
+
+			result, err := service.Seed(cmd.Context(), arg)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			for _, user := range result.Users {
+				fmt.Fprintf(out, "%s\t%s\n", user.Username, user.FullName)
+			}
+			fmt.Fprintf(out, "seeded %d users with %d accounts and %d transfers, who log in with the password %q\n",
+				len(result.Users), len(result.Accounts), len(result.Transfers), arg.Password)
+			return nil
+		},
+	}
+	cmd.Flags().IntVar(&arg.Users, "users", 10, "number of users created")
+	cmd.Flags().IntVar(&arg.MaxAccountsPerUser, "accounts", 3, "maximum number of accounts per user, in distinct currencies")
+	cmd.Flags().IntVar(&arg.Transfers, "transfers", 100, "number of transfers between the users")
+	cmd.Flags().StringVar(&arg.Password, "password", "secret", "password of every seeded user")
+
+	return cmd
+}
@@ -0,0 +1,605 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-backend/api"
+	"go-backend/db/migration"
+	db "go-backend/db/sqlc"
+	"go-backend/diagnostics"
+	"go-backend/gapi"
+	"go-backend/pb"
+	"go-backend/signing"
+	"go-backend/token"
+	"go-backend/tracking"
+	"go-backend/usage"
+	"go-backend/util"
+	"go-backend/worker"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/hibiken/asynq"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+)
+
+// requestVolumeFlushInterval is how often the HTTP server writes its per route request volumes to the
+// database for the ops analytics.
+const requestVolumeFlushInterval = time.Minute
+
+// sessionActivityFlushInterval is how often the HTTP server writes the last use of its sessions to the
+// database, it should be well below SESSION_IDLE_TIMEOUT since a replica only sees the uses recorded by
+// the others once they are flushed.
+const sessionActivityFlushInterval = 30 * time.Second
+
+// dbHealthCheckInterval is how often the primary database is pinged when it has failover sources, so
+// that it fails over even while no query is made.
+const dbHealthCheckInterval = 5 * time.Second
+
+var interruptSignals = []os.Signal{
+	os.Interrupt,
+	syscall.SIGTERM,
+	syscall.SIGINT,
+}
+
+// The serveOptions type holds the flags of the `serve` command.
+// @property {bool} Workers - whether the background workers run in the same process.
+// @property {bool} HTTPAPI - whether the gin HTTP API, with the account and transfer routes, is served on
+// SERVER_ADDRESS in place of the HTTP gateway, e.g. for the load tests of the transfers.
+// @property {string} PprofAddress - the address the pprof endpoints are served on, not at all when empty.
+// It should only be reachable by the operators as the profiles expose the internals of the process.
+type serveOptions struct {
+	Workers      bool
+	HTTPAPI      bool
+	PprofAddress string
+}
+
+// defaultServeOptions are the options the bank is served with when no command is given.
+var defaultServeOptions = serveOptions{Workers: true}
+
+// The `newServeCommand` function creates the `serve` command, which runs the gRPC and HTTP gateway servers
+// along with the background workers unless they run on their own with the `worker` command.
+func newServeCommand(cli *cli) *cobra.Command {
+	options := defaultServeOptions
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve the bank over gRPC and HTTP",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return serve(cmd.Context(), cli.config, options)
+		},
+	}
+	cmd.Flags().BoolVar(&options.Workers, "workers", options.Workers, "run the background workers in the same process")
+	cmd.Flags().BoolVar(&options.HTTPAPI, "http-api", options.HTTPAPI, "serve the gin HTTP API in place of the HTTP gateway")
+	cmd.Flags().StringVar(&options.PprofAddress, "pprof-address", options.PprofAddress, "address of the pprof endpoints, e.g. localhost:6060, disabled when empty")
+
+	return cmd
+}
+
+// The `serve` function runs the servers until an interrupt signal, then drains them before closing the
+// connections to the database.
+func serve(ctx context.Context, config util.Config, options serveOptions) error {
+	err := runMigrations(config)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, interruptSignals...)
+	defer stop()
+
+	go func() {
+		// a second signal stops the process without waiting for the drain
+		<-ctx.Done()
+		stop()
+	}()
+
+	backend, err := openBackend(ctx, config)
+	if err != nil {
+		return err
+	}
+
+	redisOpt := asynq.RedisClientOpt{
+		Addr: config.RedisAddress,
+	}
+
+	taskDistributor := worker.NewRedisTaskDistributor(redisOpt)
+	queueInspector := asynq.NewInspector(redisOpt)
+	collector := backend.diagnostics(queueInspector)
+
+	waitGroup := &sync.WaitGroup{}
+
+	leadership := runLeaderElector(ctx, waitGroup, config, backend.store)
+	if options.Workers {
+		runWorkers(ctx, waitGroup, config, redisOpt, backend.store)
+	}
+	if options.HTTPAPI {
+		runHTTPServer(ctx, waitGroup, config, backend.store, taskDistributor, leadership, collector, backend.redisClient)
+	} else {
+		runGatewayServer(ctx, waitGroup, config, backend.store, leadership, collector)
+	}
+	runGRPCServer(ctx, waitGroup, config, backend.store, leadership)
+	if options.PprofAddress != "" {
+		runPprofServer(ctx, waitGroup, config, options.PprofAddress)
+	}
+
+	// wait until every server has drained its in-flight requests before closing the pool
+	waitGroup.Wait()
+
+	log.Println("closing task distributor")
+	if err := taskDistributor.Close(); err != nil {
+		log.Println("cannot close task distributor: ", err)
+	}
+	if err := queueInspector.Close(); err != nil {
+		log.Println("cannot close queue inspector: ", err)
+	}
+
+	backend.Close()
+	log.Println("shutdown complete")
+	return nil
+}
+
+// The `runMigrations` function migrates the database up before serving when RUN_MIGRATIONS is set.
+func runMigrations(config util.Config) error {
+	if !config.RunMigrations {
+		return nil
+	}
+
+	log.Println("running db migrations")
+	err := migration.Up(config.DBSource)
+	if err != nil {
+		return fmt.Errorf("cannot run db migrations: %w", err)
+	}
+	log.Println("db migrated successfully")
+	return nil
+}
+
+// The backend type holds the store of the servers and workers along with the connections it is built
+// on, which are closed on shutdown.
+type backend struct {
+	store       db.Store
+	connPool    primaryPool
+	replicaPool *pgxpool.Pool
+	cacheClient *redis.Client
+	redisClient *redis.Client
+}
+
+// The `openBackend` function connects to the primary database and to the Redis counting the API calls and
+// remembering the signatures of the signed requests, and to the replica and the account cache when they
+// are enabled.
+func openBackend(ctx context.Context, config util.Config) (*backend, error) {
+	isolation, err := transferIsolation(config.TransferIsolation)
+	if err != nil {
+		return nil, err
+	}
+	options := []db.StoreOption{db.WithTransferIsolation(isolation)}
+
+	connPool, err := newPrimaryPool(ctx, config)
+	if err != nil {
+		return nil, fmt.Errorf("cannot connect to db: %w", err)
+	}
+
+	backend := &backend{
+		store:    db.NewStore(connPool, options...),
+		connPool: connPool,
+		redisClient: redis.NewClient(&redis.Options{
+			Addr: config.RedisAddress,
+		}),
+	}
+
+	if config.ReadFromReplica {
+		backend.replicaPool, err = newConnPool(ctx, config, config.DBReplicaSource)
+		if err != nil {
+			connPool.Close()
+			return nil, fmt.Errorf("cannot connect to db replica: %w", err)
+		}
+
+		backend.store = db.NewStoreWithReplica(connPool, backend.replicaPool, options...)
+	}
+
+	// the retries are made below the cache, which is only invalidated once a transaction committed
+	backend.store = db.NewRetryStore(backend.store, retryPolicy(config))
+
+	if config.AccountCacheTTL > 0 {
+		backend.cacheClient = redis.NewClient(&redis.Options{
+			Addr: config.RedisAddress,
+		})
+		backend.store = db.NewCachedStore(backend.store, backend.cacheClient, config.AccountCacheTTL)
+	}
+
+	return backend, nil
+}
+
+// The `diagnostics` method creates the collector of the diagnostics of the process, reporting the pools
+// to the databases and the task queues of `queues`.
+func (backend *backend) diagnostics(queues diagnostics.QueueInspector) *diagnostics.Collector {
+	collector := diagnostics.NewCollector(queues)
+	collector.AddPool("primary", backend.connPool)
+	if backend.replicaPool != nil {
+		collector.AddPool("replica", backend.replicaPool)
+	}
+	return collector
+}
+
+// The `Close` method closes the account cache, the Redis of the HTTP server and the pools to the
+// databases.
+func (backend *backend) Close() {
+	log.Println("closing redis client")
+	if err := backend.redisClient.Close(); err != nil {
+		log.Println("cannot close redis client: ", err)
+	}
+
+	if backend.cacheClient != nil {
+		log.Println("closing account cache")
+		if err := backend.cacheClient.Close(); err != nil {
+			log.Println("cannot close account cache: ", err)
+		}
+	}
+
+	if backend.replicaPool != nil {
+		log.Println("closing db replica connection pool")
+		backend.replicaPool.Close()
+	}
+
+	log.Println("closing db connection pool")
+	backend.connPool.Close()
+}
+
+// The primaryPool interface is the pool of connections to the primary database, which is closed on
+// shutdown.
+type primaryPool interface {
+	db.ConnPool
+	Stat() *pgxpool.Stat
+	Close()
+}
+
+// The `transferIsolation` function returns the isolation level of the transfers set by the
+// TRANSFER_ISOLATION config, read committed when empty.
+func transferIsolation(value string) (pgx.TxIsoLevel, error) {
+	switch value {
+	case "", "read_committed":
+		return pgx.ReadCommitted, nil
+	case "repeatable_read":
+		return pgx.RepeatableRead, nil
+	case "serializable":
+		return pgx.Serializable, nil
+	}
+	return "", fmt.Errorf("invalid TRANSFER_ISOLATION %q: must be read_committed, repeatable_read or serializable", value)
+}
+
+// The `retryPolicy` function returns how the queries and transactions of the store are timed out and
+// retried according to the config.
+func retryPolicy(config util.Config) db.RetryPolicy {
+	return db.RetryPolicy{
+		QueryTimeout: config.DBQueryTimeout,
+		TxTimeout:    config.DBTxTimeout,
+		MaxRetries:   config.DBMaxRetries,
+		Backoff:      config.DBRetryBackoff,
+		MaxBackoff:   config.DBRetryMaxBackoff,
+	}
+}
+
+// The `newPrimaryPool` function opens the pool to the primary database at DB_SOURCE, which fails over
+// to the DB_FAILOVER_SOURCES in order when any are configured.
+func newPrimaryPool(ctx context.Context, config util.Config) (primaryPool, error) {
+	failoverSources := strings.Fields(config.DBFailoverSources)
+	if len(failoverSources) == 0 {
+		return newConnPool(ctx, config, config.DBSource)
+	}
+
+	sources := append([]string{config.DBSource}, failoverSources...)
+	pool, err := db.NewFailoverPool(ctx, sources, func(ctx context.Context, source string) (*pgxpool.Pool, error) {
+		return newConnPool(ctx, config, source)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	go pool.Run(ctx, dbHealthCheckInterval)
+	return pool, nil
+}
+
+// The `newConnPool` function opens a connection pool to the database at `source`, sized by the config
+// when it overrides the pgx defaults. The connections use the current database credentials of the
+// secrets when they hold some, so that they keep connecting once the credentials are rotated.
+func newConnPool(ctx context.Context, config util.Config, source string) (*pgxpool.Pool, error) {
+	poolConfig, err := pgxpool.ParseConfig(source)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.DBMaxConns > 0 {
+		poolConfig.MaxConns = config.DBMaxConns
+	}
+	if config.DBMinConns > 0 {
+		poolConfig.MinConns = config.DBMinConns
+	}
+	if config.DBMaxConnLifetime > 0 {
+		poolConfig.MaxConnLifetime = config.DBMaxConnLifetime
+	}
+	if config.DBMaxConnIdleTime > 0 {
+		poolConfig.MaxConnIdleTime = config.DBMaxConnIdleTime
+	}
+	if config.Secrets != nil {
+		poolConfig.BeforeConnect = func(ctx context.Context, connConfig *pgx.ConnConfig) error {
+			username, password, ok, err := config.Secrets.DBCredentials(ctx)
+			if err != nil || !ok {
+				return err
+			}
+
+			connConfig.User = username
+			connConfig.Password = password
+			return nil
+		}
+	}
+
+	return pgxpool.NewWithConfig(ctx, poolConfig)
+}
+
+// The `drain` function waits for the drain period once the server stopped being ready, so that the load
+// balancer stops routing new requests to it before it stops accepting connections. Rolling deploys then
+// don't drop the requests, such as transfers, sent in the meantime.
+func drain(name string, period time.Duration) {
+	if period <= 0 {
+		return
+	}
+
+	log.Printf("draining %s for %s", name, period)
+	time.Sleep(period)
+}
+
+// The `runLeaderElector` function campaigns for the leader lease when the leader election is enabled, the
+// servers then reject writes while the instance is a standby. It returns nil when it is disabled, every
+// instance serving writes.
+func runLeaderElector(ctx context.Context, waitGroup *sync.WaitGroup, config util.Config, store db.Store) worker.Leadership {
+	if !config.LeaderElection {
+		return nil
+	}
+
+	instanceID := config.InstanceID
+	if instanceID == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			log.Fatal("cannot get instance id: ", err)
+		}
+		instanceID = hostname
+	}
+
+	elector := worker.NewLeaderElector(store, instanceID, config.AdvertiseAddress, config.LeaderLeaseDuration)
+
+	waitGroup.Add(1)
+	go func() {
+		defer waitGroup.Done()
+
+		log.Println("starting leader election as ", instanceID)
+		elector.Run(ctx)
+		log.Println("leader election is stopped")
+	}()
+
+	return elector
+}
+
+func runGRPCServer(ctx context.Context, waitGroup *sync.WaitGroup, config util.Config, store db.Store, leadership worker.Leadership) {
+	server, err := gapi.NewServer(config, store)
+	if err != nil {
+		log.Fatal("cannot create server: ", err)
+	}
+	server.SetLeadership(leadership)
+
+	grpcServer := grpc.NewServer(grpc.UnaryInterceptor(tracking.UnaryServerInterceptor()))
+	pb.RegisterSimpleBankServer(grpcServer, server)
+	reflection.Register(grpcServer)
+
+	listener, err := net.Listen("tcp", config.GRPCServerAddress)
+	if err != nil {
+		log.Fatal("cannot create listener: ", err)
+	}
+
+	waitGroup.Add(2)
+	go func() {
+		defer waitGroup.Done()
+
+		log.Println("starting gRPC server at ", listener.Addr().String())
+		err := grpcServer.Serve(listener)
+		if err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			log.Fatal("cannot start gRPC server", err)
+		}
+	}()
+
+	go func() {
+		defer waitGroup.Done()
+
+		<-ctx.Done()
+		drain("gRPC server", config.DrainPeriod)
+		log.Println("gracefully shutting down gRPC server")
+
+		stopped := make(chan struct{})
+		go func() {
+			grpcServer.GracefulStop()
+			close(stopped)
+		}()
+
+		select {
+		case <-stopped:
+		case <-time.After(config.ShutdownTimeout):
+			log.Println("gRPC server drain timed out, forcing stop")
+			grpcServer.Stop()
+		}
+		log.Println("gRPC server is stopped")
+	}()
+}
+
+func runGatewayServer(ctx context.Context, waitGroup *sync.WaitGroup, config util.Config, store db.Store, leadership worker.Leadership, collector *diagnostics.Collector) {
+	server, err := gapi.NewServer(config, store)
+	if err != nil {
+		log.Fatal("cannot create server: ", err)
+	}
+	server.SetLeadership(leadership)
+
+	grpcMux := runtime.NewServeMux(runtime.WithErrorHandler(tracking.GatewayErrorHandler))
+
+	err = pb.RegisterSimpleBankHandlerServer(ctx, grpcMux, server)
+	if err != nil {
+		log.Fatal("cannot register handler server: ", err)
+	}
+
+	tokenMaker, err := token.NewMaker(token.MakerConfig{
+		Kind:             config.TokenKind,
+		SymmetricKey:     config.TokenSymmetricKey,
+		PrivateKey:       config.TokenPrivateKey,
+		PublicKey:        config.TokenPublicKey,
+		KeyID:            config.TokenKeyID,
+		VerificationKeys: config.TokenVerificationKeys,
+	})
+	if err != nil {
+		log.Fatal("cannot create token maker: ", err)
+	}
+
+	readiness := &util.Readiness{}
+
+	mux := http.NewServeMux()
+	mux.Handle("/", tracking.RecoverHandler(util.LimitRequests(grpcMux, config.RequestTimeout, config.MaxRequestBodyBytes)))
+	mux.Handle("/ready", readiness)
+	mux.HandleFunc("/version", util.VersionHandler)
+	mux.Handle("/debug/", diagnostics.AdminOnly(tokenMaker, store, diagnostics.NewHandler(collector)))
+
+	httpServer := &http.Server{
+		Addr:    config.ServerAddress,
+		Handler: mux,
+	}
+
+	waitGroup.Add(2)
+	go func() {
+		defer waitGroup.Done()
+
+		log.Println("starting HTTP gateway server at ", httpServer.Addr)
+		err := httpServer.ListenAndServe()
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal("cannot start HTTP gateway server", err)
+		}
+	}()
+
+	go func() {
+		defer waitGroup.Done()
+
+		<-ctx.Done()
+		readiness.Drain()
+		drain("HTTP gateway server", config.DrainPeriod)
+		log.Println("gracefully shutting down HTTP gateway server")
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), config.ShutdownTimeout)
+		defer cancel()
+
+		err := httpServer.Shutdown(shutdownCtx)
+		if err != nil {
+			log.Println("failed to shutdown HTTP gateway server: ", err)
+			return
+		}
+		log.Println("HTTP gateway server is stopped")
+	}()
+}
+
+func runHTTPServer(ctx context.Context, waitGroup *sync.WaitGroup, config util.Config, store db.Store, taskDistributor worker.TaskDistributor, leadership worker.Leadership, collector *diagnostics.Collector, redisClient *redis.Client) {
+	server, err := api.NewServer(config, store, taskDistributor)
+	if err != nil {
+		log.Fatal("cannot create server: ", err)
+	}
+	server.SetLeadership(leadership)
+	server.SetDiagnostics(collector)
+	server.SetUsageMeter(usage.NewRedisMeter(redisClient))
+	server.SetNonceStore(signing.NewRedisNonceStore(redisClient))
+
+	waitGroup.Add(2)
+	go func() {
+		defer waitGroup.Done()
+
+		err := server.Start(config.ServerAddress)
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal("cannot run server: ", err)
+		}
+	}()
+
+	waitGroup.Add(1)
+	go func() {
+		defer waitGroup.Done()
+
+		server.RunRequestVolumeFlusher(ctx, requestVolumeFlushInterval)
+	}()
+
+	waitGroup.Add(1)
+	go func() {
+		defer waitGroup.Done()
+
+		server.RunSessionActivityFlusher(ctx, sessionActivityFlushInterval)
+	}()
+
+	go func() {
+		defer waitGroup.Done()
+
+		<-ctx.Done()
+		server.Drain()
+		drain("HTTP server", config.DrainPeriod)
+		log.Println("gracefully shutting down HTTP server")
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), config.ShutdownTimeout)
+		defer cancel()
+
+		err := server.Shutdown(shutdownCtx)
+		if err != nil {
+			log.Println("failed to shutdown HTTP server: ", err)
+			return
+		}
+		log.Println("HTTP server is stopped")
+	}()
+}
+
+// The `runPprofServer` function serves the profiles of the process at /debug/pprof/ on `address`, e.g.
+// `go tool pprof http://localhost:6060/debug/pprof/profile?seconds=30` while a load test runs.
+func runPprofServer(ctx context.Context, waitGroup *sync.WaitGroup, config util.Config, address string) {
+	mux := http.NewServeMux()
+	diagnostics.RegisterPprof(mux)
+
+	httpServer := &http.Server{
+		Addr:    address,
+		Handler: mux,
+	}
+
+	waitGroup.Add(2)
+	go func() {
+		defer waitGroup.Done()
+
+		log.Println("starting pprof server at ", httpServer.Addr)
+		err := httpServer.ListenAndServe()
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal("cannot start pprof server", err)
+		}
+	}()
+
+	go func() {
+		defer waitGroup.Done()
+
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), config.ShutdownTimeout)
+		defer cancel()
+
+		err := httpServer.Shutdown(shutdownCtx)
+		if err != nil {
+			log.Println("failed to shutdown pprof server: ", err)
+			return
+		}
+		log.Println("pprof server is stopped")
+	}()
+}
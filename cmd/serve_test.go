@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransferIsolation(t *testing.T) {
+	testCases := []struct {
+		value string
+		level pgx.TxIsoLevel
+	}{
+		{value: "", level: pgx.ReadCommitted},
+		{value: "read_committed", level: pgx.ReadCommitted},
+		{value: "repeatable_read", level: pgx.RepeatableRead},
+		{value: "serializable", level: pgx.Serializable},
+	}
+
+	for _, tc := range testCases {
+		level, err := transferIsolation(tc.value)
+		require.NoError(t, err, tc.value)
+		require.Equal(t, tc.level, level, tc.value)
+	}
+
+	_, err := transferIsolation("SERIALIZABLE")
+	require.ErrorContains(t, err, "TRANSFER_ISOLATION")
+}
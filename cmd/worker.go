@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"context"
+	db "go-backend/db/sqlc"
+	"go-backend/mail"
+	"go-backend/service"
+	"go-backend/token"
+	"go-backend/util"
+	"go-backend/worker"
+	"log"
+	"os/signal"
+	"sync"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/spf13/cobra"
+)
+
+// webhookTimeout is how long the webhook of a user is given to acknowledge a notification before the
+// delivery is retried.
+const webhookTimeout = 10 * time.Second
+
+// kafkaTimeout is how long the Kafka REST Proxy is given to acknowledge a streamed event before it is
+// produced again.
+const kafkaTimeout = 10 * time.Second
+
+// The `newWorkerCommand` function creates the `worker` command, which runs the background workers without
+// the servers, e.g. to scale them apart from the servers started with `serve --workers=false`.
+func newWorkerCommand(cli *cli) *cobra.Command {
+	return &cobra.Command{
+		Use:   "worker",
+		Short: "Run the background workers: tasks, projections, end of day batches and notifications",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return work(cmd.Context(), cli.config)
+		},
+	}
+}
+
+// The `work` function runs the workers until an interrupt signal, then waits for them to stop before
+// closing the connections to the database.
+func work(ctx context.Context, config util.Config) error {
+	err := runMigrations(config)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, interruptSignals...)
+	defer stop()
+
+	backend, err := openBackend(ctx, config)
+	if err != nil {
+		return err
+	}
+
+	redisOpt := asynq.RedisClientOpt{
+		Addr: config.RedisAddress,
+	}
+
+	waitGroup := &sync.WaitGroup{}
+	runWorkers(ctx, waitGroup, config, redisOpt, backend.store)
+	waitGroup.Wait()
+
+	backend.Close()
+	log.Println("shutdown complete")
+	return nil
+}
+
+// The `runWorkers` function starts every background worker, which stop once the context is done.
+func runWorkers(ctx context.Context, waitGroup *sync.WaitGroup, config util.Config, redisOpt asynq.RedisClientOpt, store db.Store) {
+	runTaskProcessor(ctx, waitGroup, config, redisOpt, store)
+	runProjector(ctx, waitGroup, config, store)
+	runEndOfDay(ctx, waitGroup, config, redisOpt, store)
+	runNotificationDispatcher(ctx, waitGroup, config, store)
+}
+
+func runTaskProcessor(ctx context.Context, waitGroup *sync.WaitGroup, config util.Config, redisOpt asynq.RedisClientOpt, store db.Store) {
+	tokenMaker, err := token.NewMaker(token.MakerConfig{
+		Kind:             config.TokenKind,
+		SymmetricKey:     config.TokenSymmetricKey,
+		PrivateKey:       config.TokenPrivateKey,
+		PublicKey:        config.TokenPublicKey,
+		KeyID:            config.TokenKeyID,
+		VerificationKeys: config.TokenVerificationKeys,
+	})
+	if err != nil {
+		log.Fatal("cannot create token maker: ", err)
+	}
+
+	// the queued transfers are made by the service, which doesn't queue them again while the database
+	// is still unavailable as it has no task distributor
+	transfers := service.New(config, store, tokenMaker, nil)
+	taskProcessor := worker.NewRedisTaskProcessor(redisOpt, store, transfers)
+
+	log.Println("starting task processor")
+	err = taskProcessor.Start()
+	if err != nil {
+		log.Fatal("failed to start task processor: ", err)
+	}
+
+	waitGroup.Add(1)
+	go func() {
+		defer waitGroup.Done()
+
+		<-ctx.Done()
+		log.Println("gracefully shutting down task processor")
+
+		// waits for the active tasks to finish before returning
+		taskProcessor.Shutdown()
+		log.Println("task processor is stopped")
+	}()
+}
+
+func runProjector(ctx context.Context, waitGroup *sync.WaitGroup, config util.Config, store db.Store) {
+	projections := []worker.Projection{worker.OverviewProjection, worker.NotificationProjection, worker.ActivityProjection}
+	if config.KafkaRESTProxyURL != "" {
+		producer := worker.NewKafkaRESTProducer(config.KafkaRESTProxyURL, kafkaTimeout)
+		projections = append(projections, worker.NewEventStreamProjection(producer, config.KafkaTopicPrefix))
+	}
+	projector := worker.NewProjector(store, config.ProjectionInterval, projections...)
+
+	waitGroup.Add(1)
+	go func() {
+		defer waitGroup.Done()
+
+		log.Println("starting projector")
+		projector.Run(ctx)
+		log.Println("projector is stopped")
+	}()
+}
+
+func runEndOfDay(ctx context.Context, waitGroup *sync.WaitGroup, config util.Config, redisOpt asynq.RedisClientOpt, store db.Store) {
+	distributor := worker.NewRedisTaskDistributor(redisOpt)
+	endOfDay := worker.NewEndOfDay(store, config.EndOfDayInterval)
+	endOfDay.SetTaskDistributor(distributor)
+
+	waitGroup.Add(1)
+	go func() {
+		defer waitGroup.Done()
+
+		log.Println("starting end of day batches")
+		endOfDay.Run(ctx)
+		if err := distributor.Close(); err != nil {
+			log.Println("cannot close task distributor: ", err)
+		}
+		log.Println("end of day batches are stopped")
+	}()
+}
+
+func runNotificationDispatcher(ctx context.Context, waitGroup *sync.WaitGroup, config util.Config, store db.Store) {
+	mailer, err := mail.NewSender(config)
+	if err != nil {
+		log.Fatal("cannot create mail sender: ", err)
+	}
+	if config.WebhookSigningKey == "" {
+		log.Fatal("WEBHOOK_SIGNING_KEY is required to post the notifications to webhooks")
+	}
+
+	dispatcher := worker.NewNotificationDispatcher(store, config.NotificationDispatchInterval, map[string]worker.NotificationSender{
+		db.NotificationChannelEmail:   worker.NewEmailSender(mailer),
+		db.NotificationChannelWebhook: worker.NewWebhookSender(webhookTimeout, config.WebhookSigningKey),
+	})
+
+	waitGroup.Add(1)
+	go func() {
+		defer waitGroup.Done()
+
+		log.Println("starting notification dispatcher")
+		dispatcher.Run(ctx)
+		log.Println("notification dispatcher is stopped")
+	}()
+}
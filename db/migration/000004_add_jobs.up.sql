@@ -0,0 +1,24 @@
+CREATE TABLE "jobs" (
+  "id" uuid PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "kind" varchar NOT NULL,
+  "params" jsonb NOT NULL DEFAULT '{}',
+  "status" varchar NOT NULL DEFAULT 'pending',
+  "progress" int NOT NULL DEFAULT 0,
+  "result" bytea,
+  "result_content_type" varchar NOT NULL DEFAULT '',
+  "error" varchar NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  "completed_at" timestamptz
+);
+
+CREATE INDEX ON "jobs" ("username");
+
+COMMENT ON COLUMN "jobs"."kind" IS 'statement, gdpr or entries';
+
+COMMENT ON COLUMN "jobs"."status" IS 'pending, running, succeeded, failed or cancelled';
+
+COMMENT ON COLUMN "jobs"."progress" IS 'percentage between 0 and 100';
+
+ALTER TABLE "jobs" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS "account_daily_volume";
+
+DROP TABLE IF EXISTS "account_overview";
+
+DROP TABLE IF EXISTS "user_overview";
+
+DROP TABLE IF EXISTS "projection_checkpoints";
+
+DROP TABLE IF EXISTS "events";
@@ -0,0 +1,60 @@
+CREATE TABLE "events" (
+  "id" bigserial PRIMARY KEY,
+  "type" varchar NOT NULL,
+  "payload" jsonb NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "projection_checkpoints" (
+  "name" varchar PRIMARY KEY,
+  "last_event_id" bigint NOT NULL DEFAULT 0,
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "user_overview" (
+  "username" varchar PRIMARY KEY,
+  "full_name" varchar NOT NULL,
+  "email" varchar NOT NULL,
+  "account_count" int NOT NULL DEFAULT 0,
+  "last_activity_at" timestamptz,
+  "created_at" timestamptz NOT NULL
+);
+
+CREATE TABLE "account_overview" (
+  "account_id" bigint PRIMARY KEY,
+  "owner" varchar NOT NULL,
+  "currency" varchar NOT NULL,
+  "balance" bigint NOT NULL,
+  "transfer_count" bigint NOT NULL DEFAULT 0,
+  "volume_30d" bigint NOT NULL DEFAULT 0,
+  "last_activity_at" timestamptz,
+  "created_at" timestamptz NOT NULL
+);
+
+CREATE TABLE "account_daily_volume" (
+  "account_id" bigint NOT NULL,
+  "day" date NOT NULL,
+  "volume" bigint NOT NULL DEFAULT 0,
+  PRIMARY KEY ("account_id", "day")
+);
+
+CREATE INDEX ON "account_overview" ("owner");
+
+CREATE INDEX ON "account_overview" ("volume_30d");
+
+COMMENT ON COLUMN "events"."type" IS 'e.g. user.created, account.created, transfer.completed';
+
+COMMENT ON COLUMN "projection_checkpoints"."last_event_id" IS 'id of the last event applied by the projection';
+
+COMMENT ON COLUMN "account_overview"."volume_30d" IS 'sum of the absolute amounts transferred over the last 30 days';
+
+-- seed the read models with the existing users and accounts
+INSERT INTO "events" ("type", "payload", "created_at")
+SELECT 'user.created', json_build_object('username', username, 'full_name', full_name, 'email', email), created_at
+FROM "users"
+ORDER BY created_at;
+
+INSERT INTO "events" ("type", "payload", "created_at")
+SELECT 'account.created', json_build_object('account_id', id, 'owner', owner, 'currency', currency, 'balance', balance), created_at
+FROM "accounts"
+ORDER BY id;
@@ -0,0 +1,17 @@
+CREATE TABLE "route_request_volumes" (
+  "route" varchar NOT NULL,
+  "hour" timestamptz NOT NULL,
+  "requests" bigint NOT NULL DEFAULT 0,
+  "errors" bigint NOT NULL DEFAULT 0,
+  PRIMARY KEY ("route", "hour")
+);
+
+CREATE INDEX ON "route_request_volumes" ("hour");
+
+CREATE INDEX ON "transfers" ("created_at");
+
+COMMENT ON COLUMN "route_request_volumes"."route" IS 'method and path template of the route';
+
+COMMENT ON COLUMN "route_request_volumes"."hour" IS 'start of the hour the requests were received in';
+
+COMMENT ON COLUMN "route_request_volumes"."errors" IS 'requests answered with a 5xx status';
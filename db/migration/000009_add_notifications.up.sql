@@ -0,0 +1,16 @@
+CREATE TABLE "notifications" (
+  "id" bigserial PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "event_id" bigint UNIQUE NOT NULL,
+  "type" varchar NOT NULL,
+  "payload" jsonb NOT NULL,
+  "created_at" timestamptz NOT NULL
+);
+
+CREATE INDEX ON "notifications" ("username", "id");
+
+COMMENT ON COLUMN "notifications"."event_id" IS 'event the notification was projected from';
+
+COMMENT ON COLUMN "notifications"."type" IS 'e.g. transfer.sent, transfer.received';
+
+ALTER TABLE "notifications" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
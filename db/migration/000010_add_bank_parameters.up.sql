@@ -0,0 +1,19 @@
+CREATE TABLE "bank_parameters" (
+  "name" varchar NOT NULL,
+  "version" int NOT NULL,
+  "value" bigint NOT NULL,
+  "effective_at" timestamptz NOT NULL,
+  "published_by" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("name", "version")
+);
+
+CREATE INDEX ON "bank_parameters" ("name", "effective_at");
+
+COMMENT ON COLUMN "bank_parameters"."name" IS 'transfer_limit, transfer_fee or interest_rate_bps';
+
+COMMENT ON COLUMN "bank_parameters"."version" IS 'starts at 1 and increases with every publication of the parameter';
+
+COMMENT ON COLUMN "bank_parameters"."effective_at" IS 'the version applies from then until the next effective version';
+
+ALTER TABLE "bank_parameters" ADD FOREIGN KEY ("published_by") REFERENCES "users" ("username");
@@ -0,0 +1,7 @@
+CREATE TABLE "processed_tasks" (
+  "id" varchar PRIMARY KEY,
+  "type" varchar NOT NULL,
+  "processed_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "processed_tasks"."id" IS 'derived from the business keys of the task, e.g. task:run_export:<job id>';
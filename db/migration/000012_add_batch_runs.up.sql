@@ -0,0 +1,14 @@
+CREATE TABLE "batch_runs" (
+  "name" varchar NOT NULL,
+  "business_date" date NOT NULL,
+  "last_account_id" bigint NOT NULL DEFAULT 0,
+  "processed_accounts" bigint NOT NULL DEFAULT 0,
+  "started_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  "completed_at" timestamptz,
+  PRIMARY KEY ("name", "business_date")
+);
+
+COMMENT ON COLUMN "batch_runs"."last_account_id" IS 'the batch resumes after this account when restarted';
+
+COMMENT ON COLUMN "batch_runs"."completed_at" IS 'null until every account was processed';
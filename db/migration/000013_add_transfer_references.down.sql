@@ -0,0 +1,3 @@
+ALTER TABLE "transfers" DROP COLUMN IF EXISTS "external_reference";
+
+ALTER TABLE "transfers" DROP COLUMN IF EXISTS "memo";
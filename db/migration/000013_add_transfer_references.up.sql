@@ -0,0 +1,9 @@
+ALTER TABLE "transfers" ADD COLUMN "memo" varchar NOT NULL DEFAULT '';
+
+ALTER TABLE "transfers" ADD COLUMN "external_reference" varchar NOT NULL DEFAULT '';
+
+CREATE INDEX ON "transfers" ("external_reference");
+
+COMMENT ON COLUMN "transfers"."memo" IS 'free text written by the sender, empty when omitted';
+
+COMMENT ON COLUMN "transfers"."external_reference" IS 'reference of the payment outside the bank, e.g. an invoice number';
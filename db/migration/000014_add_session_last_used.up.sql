@@ -0,0 +1,3 @@
+ALTER TABLE "sessions" ADD COLUMN "last_used_at" timestamptz NOT NULL DEFAULT (now());
+
+COMMENT ON COLUMN "sessions"."last_used_at" IS 'last time a token of the session was used, written behind so it may lag by the flush interval';
@@ -0,0 +1,41 @@
+CREATE TABLE "transfer_reviews" (
+  "id" bigserial PRIMARY KEY,
+  "from_account_id" bigint NOT NULL,
+  "to_account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "memo" varchar NOT NULL DEFAULT '',
+  "external_reference" varchar NOT NULL DEFAULT '',
+  "hold_reason" varchar NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'pending',
+  "assignee" varchar,
+  "due_at" timestamptz NOT NULL,
+  "note" varchar NOT NULL DEFAULT '',
+  "transfer_id" bigint,
+  "decided_by" varchar,
+  "decided_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "transfer_reviews" ("status", "due_at");
+
+CREATE INDEX ON "transfer_reviews" ("assignee", "due_at");
+
+COMMENT ON COLUMN "transfer_reviews"."amount" IS 'taken from the from account while the review is open';
+
+COMMENT ON COLUMN "transfer_reviews"."hold_reason" IS 'why the screening held the transfer';
+
+COMMENT ON COLUMN "transfer_reviews"."status" IS 'pending, escalated, approved or denied';
+
+COMMENT ON COLUMN "transfer_reviews"."due_at" IS 'the review is overdue once open past this time';
+
+COMMENT ON COLUMN "transfer_reviews"."transfer_id" IS 'the transfer made once the review was approved';
+
+ALTER TABLE "transfer_reviews" ADD FOREIGN KEY ("from_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "transfer_reviews" ADD FOREIGN KEY ("to_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "transfer_reviews" ADD FOREIGN KEY ("assignee") REFERENCES "users" ("username");
+
+ALTER TABLE "transfer_reviews" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "transfer_reviews" ADD FOREIGN KEY ("decided_by") REFERENCES "users" ("username");
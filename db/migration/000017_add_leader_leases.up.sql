@@ -0,0 +1,11 @@
+CREATE TABLE "leader_leases" (
+  "name" varchar PRIMARY KEY,
+  "holder" varchar NOT NULL,
+  "address" varchar NOT NULL,
+  "expires_at" timestamptz NOT NULL,
+  "acquired_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "leader_leases"."holder" IS 'id of the instance holding the lease';
+
+COMMENT ON COLUMN "leader_leases"."address" IS 'address the holder serves writes at, sent to clients of the standby instances';
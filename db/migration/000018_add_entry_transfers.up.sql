@@ -0,0 +1,5 @@
+ALTER TABLE "entries" ADD COLUMN "transfer_id" bigint;
+
+COMMENT ON COLUMN "entries"."transfer_id" IS 'transfer the entry was made for, null for the other entries such as interest and holds, and for the entries made before it was recorded';
+
+ALTER TABLE "entries" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS "system_accounts";
+
+DELETE FROM "accounts" WHERE "owner" = 'system';
+
+DELETE FROM "users" WHERE "username" = 'system';
+
+COMMENT ON COLUMN "users"."role" IS 'depositor or admin';
@@ -0,0 +1,34 @@
+COMMENT ON COLUMN "users"."role" IS 'depositor, admin or system';
+
+-- the password hash matches no password, so the owner of the system accounts can't log in
+INSERT INTO "users" ("username", "hashed_password", "full_name", "email", "role")
+VALUES ('system', '!', 'Bank', 'system@bank.invalid', 'system');
+
+CREATE TABLE "system_accounts" (
+  "purpose" varchar NOT NULL,
+  "currency" varchar NOT NULL,
+  "account_id" bigint UNIQUE NOT NULL,
+  PRIMARY KEY ("purpose", "currency")
+);
+
+COMMENT ON COLUMN "system_accounts"."purpose" IS 'fees, fx_spread or suspense';
+
+ALTER TABLE "system_accounts" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+DO $$
+DECLARE
+  system_purpose varchar;
+  system_currency varchar;
+BEGIN
+  FOREACH system_purpose IN ARRAY ARRAY['fees', 'fx_spread', 'suspense'] LOOP
+    FOREACH system_currency IN ARRAY ARRAY['USD', 'EUR', 'CAD'] LOOP
+      WITH account AS (
+        INSERT INTO "accounts" ("owner", "balance", "currency")
+        VALUES ('system', 0, system_currency)
+        RETURNING "id"
+      )
+      INSERT INTO "system_accounts" ("purpose", "currency", "account_id")
+      SELECT system_purpose, system_currency, "id" FROM account;
+    END LOOP;
+  END LOOP;
+END $$;
@@ -0,0 +1,22 @@
+CREATE TABLE "ledger_anomalies" (
+  "id" bigserial PRIMARY KEY,
+  "kind" varchar NOT NULL,
+  "subject_id" bigint NOT NULL,
+  "expected" bigint NOT NULL,
+  "actual" bigint NOT NULL,
+  "first_detected_at" timestamptz NOT NULL DEFAULT (now()),
+  "last_detected_at" timestamptz NOT NULL DEFAULT (now()),
+  "resolved_at" timestamptz
+);
+
+CREATE UNIQUE INDEX ON "ledger_anomalies" ("kind", "subject_id");
+
+COMMENT ON COLUMN "ledger_anomalies"."kind" IS 'account_balance or transfer_imbalance';
+
+COMMENT ON COLUMN "ledger_anomalies"."subject_id" IS 'id of the account for account_balance, of the transfer for transfer_imbalance';
+
+COMMENT ON COLUMN "ledger_anomalies"."expected" IS 'sum of the entries of the account, or 0 for a transfer';
+
+COMMENT ON COLUMN "ledger_anomalies"."actual" IS 'balance of the account, or sum of the entries of the transfer';
+
+COMMENT ON COLUMN "ledger_anomalies"."resolved_at" IS 'set once a reconciliation no longer finds the anomaly';
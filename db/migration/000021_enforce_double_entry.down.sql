@@ -0,0 +1,7 @@
+DROP TRIGGER IF EXISTS "accounts_check_balance_posted" ON "accounts";
+DROP TRIGGER IF EXISTS "accounts_change_balance" ON "accounts";
+DROP TRIGGER IF EXISTS "entries_post_entry" ON "entries";
+DROP FUNCTION IF EXISTS "check_balance_posted";
+DROP FUNCTION IF EXISTS "change_balance";
+DROP FUNCTION IF EXISTS "post_entry";
+DROP FUNCTION IF EXISTS "add_unposted_amount";
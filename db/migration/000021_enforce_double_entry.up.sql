@@ -0,0 +1,53 @@
+-- Every change of a balance must be posted as entries of the account in the same transaction. The
+-- amount of the balance changes not yet matched by entries is kept per account in a setting local to
+-- the transaction, and must be back to zero when it commits. Entries may still be recorded without
+-- changing the balance, and accounts opened with a balance, which the reconciliation of the ledger
+-- reports.
+CREATE FUNCTION "add_unposted_amount"("account_id" bigint, "amount" bigint) RETURNS bigint AS $$
+DECLARE
+  "setting" text := 'bank.unposted_' || "account_id";
+  "unposted" bigint;
+BEGIN
+  "unposted" := COALESCE(NULLIF(current_setting("setting", true), ''), '0')::bigint + "amount";
+  PERFORM set_config("setting", "unposted"::text, true);
+  RETURN "unposted";
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE FUNCTION "post_entry"() RETURNS trigger AS $$
+BEGIN
+  PERFORM add_unposted_amount(NEW."account_id", -NEW."amount");
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE FUNCTION "change_balance"() RETURNS trigger AS $$
+BEGIN
+  PERFORM add_unposted_amount(NEW."id", NEW."balance" - OLD."balance");
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE FUNCTION "check_balance_posted"() RETURNS trigger AS $$
+BEGIN
+  IF add_unposted_amount(NEW."id", 0) <> 0 THEN
+    RAISE EXCEPTION 'balance of account % changed without matching entries', NEW."id"
+      USING ERRCODE = 'check_violation';
+  END IF;
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER "entries_post_entry"
+  AFTER INSERT ON "entries"
+  FOR EACH ROW EXECUTE FUNCTION post_entry();
+
+CREATE TRIGGER "accounts_change_balance"
+  BEFORE UPDATE OF "balance" ON "accounts"
+  FOR EACH ROW EXECUTE FUNCTION change_balance();
+
+-- checked at commit, once the entries of the transaction are recorded
+CREATE CONSTRAINT TRIGGER "accounts_check_balance_posted"
+  AFTER UPDATE OF "balance" ON "accounts"
+  DEFERRABLE INITIALLY DEFERRED
+  FOR EACH ROW EXECUTE FUNCTION check_balance_posted();
@@ -0,0 +1,20 @@
+CREATE TABLE "beneficiaries" (
+  "id" bigserial PRIMARY KEY,
+  "owner" varchar NOT NULL,
+  "account_id" bigint NOT NULL,
+  "nickname" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE UNIQUE INDEX ON "beneficiaries" ("owner", "account_id");
+
+CREATE UNIQUE INDEX ON "beneficiaries" ("owner", "nickname");
+
+COMMENT ON COLUMN "beneficiaries"."owner" IS 'the user who saved the account';
+
+COMMENT ON COLUMN "beneficiaries"."account_id" IS 'the account the transfers to the beneficiary are sent to';
+
+ALTER TABLE "beneficiaries" ADD FOREIGN KEY ("owner") REFERENCES "users" ("username");
+
+-- a deleted account is no longer a beneficiary of anyone
+ALTER TABLE "beneficiaries" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id") ON DELETE CASCADE;
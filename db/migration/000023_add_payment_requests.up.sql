@@ -0,0 +1,44 @@
+CREATE TABLE "payment_requests" (
+  "id" bigserial PRIMARY KEY,
+  "requester" varchar NOT NULL,
+  "payer" varchar NOT NULL,
+  "to_account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "memo" varchar NOT NULL DEFAULT '',
+  "status" varchar NOT NULL DEFAULT 'pending',
+  "expires_at" timestamptz NOT NULL,
+  "from_account_id" bigint,
+  "transfer_id" bigint,
+  "decided_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "payment_requests" ("payer", "status");
+
+CREATE INDEX ON "payment_requests" ("requester", "status");
+
+CREATE INDEX ON "payment_requests" ("status", "expires_at");
+
+COMMENT ON COLUMN "payment_requests"."requester" IS 'the user asking for the money';
+
+COMMENT ON COLUMN "payment_requests"."payer" IS 'the user asked to pay';
+
+COMMENT ON COLUMN "payment_requests"."to_account_id" IS 'the account of the requester the money is sent to';
+
+COMMENT ON COLUMN "payment_requests"."status" IS 'pending, accepted, declined or expired';
+
+COMMENT ON COLUMN "payment_requests"."expires_at" IS 'a pending request can no longer be accepted past this time';
+
+COMMENT ON COLUMN "payment_requests"."from_account_id" IS 'the account of the payer the money was taken from once accepted';
+
+COMMENT ON COLUMN "payment_requests"."transfer_id" IS 'the transfer made once the request was accepted';
+
+ALTER TABLE "payment_requests" ADD FOREIGN KEY ("requester") REFERENCES "users" ("username");
+
+ALTER TABLE "payment_requests" ADD FOREIGN KEY ("payer") REFERENCES "users" ("username");
+
+ALTER TABLE "payment_requests" ADD FOREIGN KEY ("to_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "payment_requests" ADD FOREIGN KEY ("from_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "payment_requests" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
@@ -0,0 +1,24 @@
+-- the successive values of the fields of the accounts, except their balance which the entries keep
+CREATE TABLE "account_history" (
+  "id" bigserial PRIMARY KEY,
+  "account_id" bigint NOT NULL,
+  "owner" varchar NOT NULL,
+  "currency" varchar NOT NULL,
+  "valid_from" timestamptz NOT NULL DEFAULT (now()),
+  "valid_to" timestamptz
+);
+
+CREATE INDEX ON "account_history" ("account_id", "valid_from");
+
+-- an account has a single current version
+CREATE UNIQUE INDEX ON "account_history" ("account_id") WHERE "valid_to" IS NULL;
+
+COMMENT ON COLUMN "account_history"."account_id" IS 'the account, kept once deleted';
+
+COMMENT ON COLUMN "account_history"."valid_from" IS 'the account had these values from this time';
+
+COMMENT ON COLUMN "account_history"."valid_to" IS 'the account had these values until this time, null for its current values';
+
+-- the accounts opened before the history have a single version
+INSERT INTO "account_history" ("account_id", "owner", "currency", "valid_from")
+SELECT "id", "owner", "currency", "created_at" FROM "accounts";
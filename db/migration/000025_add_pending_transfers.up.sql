@@ -0,0 +1,39 @@
+CREATE TABLE "pending_transfers" (
+  "id" bigserial PRIMARY KEY,
+  "owner" varchar NOT NULL,
+  "from_account_id" bigint NOT NULL,
+  "to_account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "memo" varchar NOT NULL DEFAULT '',
+  "external_reference" varchar NOT NULL DEFAULT '',
+  "status" varchar NOT NULL DEFAULT 'pending_approval',
+  "expires_at" timestamptz NOT NULL,
+  "decided_by" varchar,
+  "transfer_id" bigint,
+  "decided_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "pending_transfers" ("owner", "status");
+
+CREATE INDEX ON "pending_transfers" ("status", "expires_at");
+
+COMMENT ON COLUMN "pending_transfers"."owner" IS 'the user who requested the transfer';
+
+COMMENT ON COLUMN "pending_transfers"."status" IS 'pending_approval, approved, rejected or expired';
+
+COMMENT ON COLUMN "pending_transfers"."expires_at" IS 'the transfer can no longer be approved past this time';
+
+COMMENT ON COLUMN "pending_transfers"."decided_by" IS 'the owner or the banker who approved or rejected the transfer';
+
+COMMENT ON COLUMN "pending_transfers"."transfer_id" IS 'the transfer made once approved';
+
+ALTER TABLE "pending_transfers" ADD FOREIGN KEY ("owner") REFERENCES "users" ("username");
+
+ALTER TABLE "pending_transfers" ADD FOREIGN KEY ("from_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "pending_transfers" ADD FOREIGN KEY ("to_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "pending_transfers" ADD FOREIGN KEY ("decided_by") REFERENCES "users" ("username");
+
+ALTER TABLE "pending_transfers" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
@@ -0,0 +1,29 @@
+CREATE TABLE "queued_transfers" (
+  "id" uuid PRIMARY KEY,
+  "owner" varchar NOT NULL,
+  "status" varchar NOT NULL,
+  "transfer_id" bigint,
+  "review_id" bigint,
+  "pending_transfer_id" bigint,
+  "error" varchar NOT NULL DEFAULT '',
+  "queued_at" timestamptz NOT NULL,
+  "processed_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "queued_transfers" ("owner");
+
+COMMENT ON COLUMN "queued_transfers"."id" IS 'the tracking ID returned when the transfer was queued';
+
+COMMENT ON COLUMN "queued_transfers"."status" IS 'succeeded, held, pending_approval or failed';
+
+COMMENT ON COLUMN "queued_transfers"."error" IS 'why the transfer failed';
+
+COMMENT ON COLUMN "queued_transfers"."queued_at" IS 'when the transfer was accepted while the database was unavailable';
+
+ALTER TABLE "queued_transfers" ADD FOREIGN KEY ("owner") REFERENCES "users" ("username");
+
+ALTER TABLE "queued_transfers" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "queued_transfers" ADD FOREIGN KEY ("review_id") REFERENCES "transfer_reviews" ("id");
+
+ALTER TABLE "queued_transfers" ADD FOREIGN KEY ("pending_transfer_id") REFERENCES "pending_transfers" ("id");
@@ -0,0 +1,11 @@
+DROP TABLE IF EXISTS "notification_deliveries";
+
+DROP TABLE IF EXISTS "notification_preferences";
+
+DELETE FROM "notifications" WHERE "type" = 'account.low_balance';
+
+ALTER TABLE "notifications" DROP CONSTRAINT "notifications_event_id_type_key";
+
+ALTER TABLE "notifications" ADD CONSTRAINT "notifications_event_id_key" UNIQUE ("event_id");
+
+ALTER TABLE "notifications" DROP COLUMN "read_at";
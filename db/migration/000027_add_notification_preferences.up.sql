@@ -0,0 +1,60 @@
+ALTER TABLE "notifications" ADD COLUMN "read_at" timestamptz;
+
+ALTER TABLE "notifications" DROP CONSTRAINT "notifications_event_id_key";
+
+ALTER TABLE "notifications" ADD CONSTRAINT "notifications_event_id_type_key" UNIQUE ("event_id", "type");
+
+COMMENT ON COLUMN "notifications"."type" IS 'e.g. transfer.sent, transfer.received, account.low_balance';
+
+COMMENT ON COLUMN "notifications"."read_at" IS 'when the user marked the notification as read, null while unread';
+
+CREATE TABLE "notification_preferences" (
+  "username" varchar PRIMARY KEY,
+  "in_app" boolean NOT NULL DEFAULT true,
+  "email" boolean NOT NULL DEFAULT false,
+  "webhook_url" varchar NOT NULL DEFAULT '',
+  "low_balance_threshold" bigint NOT NULL DEFAULT 0,
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "notification_preferences"."in_app" IS 'whether the notifications are listed in the app';
+
+COMMENT ON COLUMN "notification_preferences"."email" IS 'whether the notifications are sent to the email of the user';
+
+COMMENT ON COLUMN "notification_preferences"."webhook_url" IS 'where the notifications are posted, empty to not post them';
+
+COMMENT ON COLUMN "notification_preferences"."low_balance_threshold" IS 'a transfer leaving an account below it notifies its owner, 0 to never notify';
+
+ALTER TABLE "notification_preferences" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+CREATE TABLE "notification_deliveries" (
+  "id" bigserial PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "event_id" bigint NOT NULL,
+  "type" varchar NOT NULL,
+  "channel" varchar NOT NULL,
+  "destination" varchar NOT NULL,
+  "payload" jsonb NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'pending',
+  "attempts" int NOT NULL DEFAULT 0,
+  "last_error" varchar NOT NULL DEFAULT '',
+  "next_attempt_at" timestamptz NOT NULL DEFAULT (now()),
+  "delivered_at" timestamptz,
+  "created_at" timestamptz NOT NULL
+);
+
+CREATE UNIQUE INDEX ON "notification_deliveries" ("event_id", "type", "channel");
+
+CREATE INDEX ON "notification_deliveries" ("next_attempt_at") WHERE "status" = 'pending';
+
+COMMENT ON COLUMN "notification_deliveries"."channel" IS 'email or webhook';
+
+COMMENT ON COLUMN "notification_deliveries"."destination" IS 'the email address or webhook URL, as set when the event was projected';
+
+COMMENT ON COLUMN "notification_deliveries"."status" IS 'pending, delivered or failed';
+
+COMMENT ON COLUMN "notification_deliveries"."next_attempt_at" IS 'when the dispatcher sends the notification next, pushed back while it is being sent';
+
+ALTER TABLE "notification_deliveries" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+ALTER TABLE "notification_deliveries" ADD FOREIGN KEY ("event_id") REFERENCES "events" ("id");
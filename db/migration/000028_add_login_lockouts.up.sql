@@ -0,0 +1,28 @@
+CREATE TABLE "login_failures" (
+  "id" bigserial PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "client_ip" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "login_failures" ("username", "created_at");
+
+CREATE TABLE "login_lockouts" (
+  "username" varchar PRIMARY KEY,
+  "failures" bigint NOT NULL,
+  "client_ip" varchar NOT NULL,
+  "locked_until" timestamptz NOT NULL,
+  "locked_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "login_failures"."client_ip" IS 'the client the wrong password was sent from';
+
+COMMENT ON COLUMN "login_lockouts"."failures" IS 'the failed logins within the window that locked the user';
+
+COMMENT ON COLUMN "login_lockouts"."client_ip" IS 'the client of the failed login that locked the user';
+
+COMMENT ON COLUMN "login_lockouts"."locked_until" IS 'the user can''t log in until then, unless an admin unlocks them';
+
+ALTER TABLE "login_failures" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+ALTER TABLE "login_lockouts" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
@@ -0,0 +1,18 @@
+CREATE TABLE "user_identities" (
+  "provider" varchar NOT NULL,
+  "subject" varchar NOT NULL,
+  "username" varchar NOT NULL,
+  "email" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("provider", "subject")
+);
+
+CREATE UNIQUE INDEX ON "user_identities" ("username", "provider");
+
+COMMENT ON COLUMN "user_identities"."provider" IS 'the identity provider the user logs in with, e.g. google or github';
+
+COMMENT ON COLUMN "user_identities"."subject" IS 'the id of the user at the provider, which never changes unlike their email';
+
+COMMENT ON COLUMN "user_identities"."email" IS 'the email the provider gave when the identity was linked';
+
+ALTER TABLE "user_identities" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
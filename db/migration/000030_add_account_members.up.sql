@@ -0,0 +1,23 @@
+CREATE TABLE "account_members" (
+  "account_id" bigint NOT NULL,
+  "username" varchar NOT NULL,
+  "role" varchar NOT NULL,
+  "invited_by" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "accepted_at" timestamptz,
+  PRIMARY KEY ("account_id", "username")
+);
+
+CREATE INDEX ON "account_members" ("username");
+
+COMMENT ON COLUMN "account_members"."username" IS 'a user sharing the account with its owner';
+
+COMMENT ON COLUMN "account_members"."role" IS 'owner or viewer, viewers can only read the account';
+
+COMMENT ON COLUMN "account_members"."accepted_at" IS 'when the user accepted the invitation, null while it is pending';
+
+ALTER TABLE "account_members" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "account_members" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+ALTER TABLE "account_members" ADD FOREIGN KEY ("invited_by") REFERENCES "users" ("username");
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS "account_alerts";
+
+DROP TABLE IF EXISTS "budgets";
+
+ALTER TABLE "beneficiaries" DROP COLUMN "category";
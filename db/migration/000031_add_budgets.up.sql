@@ -0,0 +1,25 @@
+ALTER TABLE "beneficiaries" ADD COLUMN "category" varchar NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN "beneficiaries"."category" IS 'the spending category of the transfers to the beneficiary, e.g. groceries, empty when uncategorized';
+
+CREATE TABLE "budgets" (
+  "account_id" bigint NOT NULL,
+  "category" varchar NOT NULL,
+  "monthly_limit" bigint NOT NULL,
+  "updated_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("account_id", "category")
+);
+
+COMMENT ON COLUMN "budgets"."monthly_limit" IS 'must be positive, the transfers to the beneficiaries of the category sent from the account in a calendar month exceeding it notify its owner';
+
+ALTER TABLE "budgets" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id") ON DELETE CASCADE;
+
+CREATE TABLE "account_alerts" (
+  "account_id" bigint PRIMARY KEY,
+  "low_balance_threshold" bigint NOT NULL,
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "account_alerts"."low_balance_threshold" IS 'a transfer leaving the account below it notifies its owner, overriding their own threshold, 0 to never notify';
+
+ALTER TABLE "account_alerts" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id") ON DELETE CASCADE;
@@ -0,0 +1,34 @@
+CREATE TABLE "mandates" (
+  "id" bigserial PRIMARY KEY,
+  "payer" varchar NOT NULL,
+  "from_account_id" bigint NOT NULL,
+  "merchant_account_id" bigint NOT NULL,
+  "max_amount" bigint NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'active',
+  "pulled_amount" bigint NOT NULL DEFAULT 0,
+  "last_pulled_at" timestamptz,
+  "revoked_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "mandates" ("payer");
+
+CREATE INDEX ON "mandates" ("merchant_account_id");
+
+COMMENT ON COLUMN "mandates"."payer" IS 'the user authorizing the merchant to pull funds from their account';
+
+COMMENT ON COLUMN "mandates"."from_account_id" IS 'the account of the payer the funds are pulled from';
+
+COMMENT ON COLUMN "mandates"."merchant_account_id" IS 'the account the funds are pulled to, whose owners pull them';
+
+COMMENT ON COLUMN "mandates"."max_amount" IS 'must be positive, the most a single pull takes from the account';
+
+COMMENT ON COLUMN "mandates"."status" IS 'active or revoked, only active mandates are pulled from';
+
+COMMENT ON COLUMN "mandates"."pulled_amount" IS 'the total pulled with the mandate';
+
+ALTER TABLE "mandates" ADD FOREIGN KEY ("payer") REFERENCES "users" ("username");
+
+ALTER TABLE "mandates" ADD FOREIGN KEY ("from_account_id") REFERENCES "accounts" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "mandates" ADD FOREIGN KEY ("merchant_account_id") REFERENCES "accounts" ("id") ON DELETE CASCADE;
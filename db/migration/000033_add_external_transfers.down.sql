@@ -0,0 +1,14 @@
+DROP TABLE IF EXISTS "external_transfers";
+
+CREATE TEMPORARY TABLE "clearing_accounts" AS
+SELECT "account_id" FROM "system_accounts" WHERE "purpose" = 'external_clearing';
+
+DELETE FROM "system_accounts" WHERE "purpose" = 'external_clearing';
+
+DELETE FROM "account_history" WHERE "account_id" IN (SELECT "account_id" FROM "clearing_accounts");
+
+DELETE FROM "accounts" WHERE "id" IN (SELECT "account_id" FROM "clearing_accounts");
+
+DROP TABLE "clearing_accounts";
+
+COMMENT ON COLUMN "system_accounts"."purpose" IS 'fees, fx_spread or suspense';
@@ -0,0 +1,68 @@
+CREATE TABLE "external_transfers" (
+  "id" bigserial PRIMARY KEY,
+  "owner" varchar NOT NULL,
+  "account_id" bigint NOT NULL,
+  "rail" varchar NOT NULL,
+  "amount" bigint NOT NULL,
+  "currency" varchar NOT NULL,
+  "routing_number" varchar NOT NULL,
+  "account_number" varchar NOT NULL,
+  "beneficiary_name" varchar NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'initiated',
+  "failure_reason" varchar NOT NULL DEFAULT '',
+  "transfer_id" bigint NOT NULL,
+  "return_transfer_id" bigint,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "external_transfers" ("owner");
+
+CREATE INDEX ON "external_transfers" ("account_id");
+
+COMMENT ON COLUMN "external_transfers"."owner" IS 'the user sending the money out of the bank';
+
+COMMENT ON COLUMN "external_transfers"."rail" IS 'ach or wire, the network the money is sent through';
+
+COMMENT ON COLUMN "external_transfers"."amount" IS 'must be positive';
+
+COMMENT ON COLUMN "external_transfers"."routing_number" IS 'the routing number of the receiving bank';
+
+COMMENT ON COLUMN "external_transfers"."account_number" IS 'the account at the receiving bank';
+
+COMMENT ON COLUMN "external_transfers"."status" IS 'initiated, processing, settled or failed, settled and failed being final';
+
+COMMENT ON COLUMN "external_transfers"."failure_reason" IS 'why the receiving bank returned the money, when failed';
+
+COMMENT ON COLUMN "external_transfers"."transfer_id" IS 'the transfer of the amount to the external_clearing account, made when initiated';
+
+COMMENT ON COLUMN "external_transfers"."return_transfer_id" IS 'the transfer of the amount back to the account, made when failed';
+
+ALTER TABLE "external_transfers" ADD FOREIGN KEY ("owner") REFERENCES "users" ("username");
+
+ALTER TABLE "external_transfers" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "external_transfers" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "external_transfers" ADD FOREIGN KEY ("return_transfer_id") REFERENCES "transfers" ("id");
+
+COMMENT ON COLUMN "system_accounts"."purpose" IS 'fees, fx_spread, suspense or external_clearing';
+
+-- the money sent out of the bank is held by the external_clearing accounts until it settles
+DO $$
+DECLARE
+  system_currency varchar;
+BEGIN
+  FOREACH system_currency IN ARRAY ARRAY['USD', 'EUR', 'CAD'] LOOP
+    WITH account AS (
+      INSERT INTO "accounts" ("owner", "balance", "currency")
+      VALUES ('system', 0, system_currency)
+      RETURNING "id", "owner", "currency", "created_at"
+    ), history AS (
+      INSERT INTO "account_history" ("account_id", "owner", "currency", "valid_from")
+      SELECT "id", "owner", "currency", "created_at" FROM account
+    )
+    INSERT INTO "system_accounts" ("purpose", "currency", "account_id")
+    SELECT 'external_clearing', system_currency, "id" FROM account;
+  END LOOP;
+END $$;
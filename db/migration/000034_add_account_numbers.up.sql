@@ -0,0 +1,10 @@
+ALTER TABLE "accounts" ADD COLUMN "account_number" varchar NOT NULL GENERATED ALWAYS AS (
+  'GO' ||
+  lpad((98 - ('11102320' || lpad("id"::text, 10, '0') || '162400')::numeric % 97)::text, 2, '0') ||
+  'BANK' ||
+  lpad("id"::text, 10, '0')
+) STORED;
+
+CREATE UNIQUE INDEX ON "accounts" ("account_number");
+
+COMMENT ON COLUMN "accounts"."account_number" IS 'IBAN-like number of the account derived from its id, GO + 2 ISO 7064 MOD 97-10 check digits + BANK + the id on 10 digits, see util.AccountNumber';
@@ -0,0 +1,5 @@
+DELETE FROM "projection_checkpoints" WHERE "name" = 'activity';
+
+DROP TABLE IF EXISTS "account_counterparty_activity";
+
+DROP TABLE IF EXISTS "account_daily_activity";
@@ -0,0 +1,43 @@
+CREATE TABLE "account_daily_activity" (
+  "account_id" bigint NOT NULL,
+  "day" date NOT NULL,
+  "credits" bigint NOT NULL DEFAULT 0,
+  "debits" bigint NOT NULL DEFAULT 0,
+  "transfer_count" bigint NOT NULL DEFAULT 0,
+  PRIMARY KEY ("account_id", "day")
+);
+
+CREATE TABLE "account_counterparty_activity" (
+  "account_id" bigint NOT NULL,
+  "counterparty_account_id" bigint NOT NULL,
+  "counterparty_owner" varchar NOT NULL,
+  "sent" bigint NOT NULL DEFAULT 0,
+  "received" bigint NOT NULL DEFAULT 0,
+  "transfer_count" bigint NOT NULL DEFAULT 0,
+  "last_transfer_at" timestamptz NOT NULL,
+  PRIMARY KEY ("account_id", "counterparty_account_id")
+);
+
+CREATE INDEX ON "account_counterparty_activity" ("account_id", "transfer_count");
+
+COMMENT ON COLUMN "account_daily_activity"."credits" IS 'sum of the amounts received by transfer on the day';
+
+COMMENT ON COLUMN "account_daily_activity"."debits" IS 'sum of the amounts sent by transfer on the day, positive';
+
+-- seed the activity with the existing transfers, the projection starting after the events recorded so far
+INSERT INTO "account_daily_activity" ("account_id", "day", "credits", "debits", "transfer_count")
+SELECT account_id, (created_at AT TIME ZONE 'UTC')::date, SUM(GREATEST(amount, 0)), SUM(GREATEST(-amount, 0)), COUNT(*)
+FROM "entries"
+WHERE transfer_id IS NOT NULL
+GROUP BY 1, 2;
+
+INSERT INTO "account_counterparty_activity" ("account_id", "counterparty_account_id", "counterparty_owner", "sent", "received", "transfer_count", "last_transfer_at")
+SELECT e.account_id, c.id, c.owner, SUM(GREATEST(-e.amount, 0)), SUM(GREATEST(e.amount, 0)), COUNT(*), MAX(e.created_at)
+FROM "entries" e
+JOIN "transfers" t ON t.id = e.transfer_id
+JOIN "accounts" c ON c.id = CASE WHEN t.from_account_id = e.account_id THEN t.to_account_id ELSE t.from_account_id END
+GROUP BY e.account_id, c.id, c.owner;
+
+INSERT INTO "projection_checkpoints" ("name", "last_event_id")
+SELECT 'activity', COALESCE(MAX(id), 0) FROM "events"
+ON CONFLICT ("name") DO NOTHING;
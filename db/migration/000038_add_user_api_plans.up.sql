@@ -0,0 +1,9 @@
+CREATE TABLE "user_api_plans" (
+  "username" varchar PRIMARY KEY,
+  "plan" varchar NOT NULL,
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "user_api_plans"."plan" IS 'a plan of the API_PLANS config, whose monthly quota of calls the user is held to';
+
+ALTER TABLE "user_api_plans" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
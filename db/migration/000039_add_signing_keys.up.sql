@@ -0,0 +1,15 @@
+CREATE TABLE "signing_keys" (
+  "id" bigserial PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "secret" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "revoked_at" timestamptz
+);
+
+CREATE INDEX ON "signing_keys" ("username");
+
+COMMENT ON COLUMN "signing_keys"."secret" IS 'the HMAC key the requests of the user are signed with, kept as is since the server signs with it too';
+
+COMMENT ON COLUMN "signing_keys"."revoked_at" IS 'the requests signed with the key are rejected once set';
+
+ALTER TABLE "signing_keys" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
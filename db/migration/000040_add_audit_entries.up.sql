@@ -0,0 +1,25 @@
+CREATE TABLE "audit_entries" (
+  "id" bigserial PRIMARY KEY,
+  "username" varchar NOT NULL,
+  "impersonator" varchar,
+  "action" varchar NOT NULL,
+  "detail" varchar NOT NULL DEFAULT '',
+  "status" int NOT NULL DEFAULT 0,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "audit_entries" ("username", "id");
+
+CREATE INDEX ON "audit_entries" ("impersonator", "id") WHERE "impersonator" IS NOT NULL;
+
+COMMENT ON COLUMN "audit_entries"."username" IS 'the user the action was taken as';
+
+COMMENT ON COLUMN "audit_entries"."impersonator" IS 'the admin who took the action while impersonating the user, null when the user took it';
+
+COMMENT ON COLUMN "audit_entries"."action" IS 'e.g. impersonation.started, or the method and route of a request such as POST /api/v1/transfers';
+
+COMMENT ON COLUMN "audit_entries"."status" IS 'the HTTP status of the request, 0 for the actions that are not requests';
+
+ALTER TABLE "audit_entries" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
+
+ALTER TABLE "audit_entries" ADD FOREIGN KEY ("impersonator") REFERENCES "users" ("username");
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "user_deletions";
+
+ALTER TABLE "accounts" DROP COLUMN IF EXISTS "closed_at";
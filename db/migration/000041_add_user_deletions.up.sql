@@ -0,0 +1,22 @@
+ALTER TABLE "accounts" ADD COLUMN "closed_at" timestamptz;
+
+COMMENT ON COLUMN "accounts"."closed_at" IS 'when the account was closed by the deletion of its owner''s data, null while it is open';
+
+CREATE TABLE "user_deletions" (
+  "username" varchar PRIMARY KEY,
+  "status" varchar NOT NULL DEFAULT 'scheduled',
+  "scheduled_for" timestamptz NOT NULL,
+  "error" varchar NOT NULL DEFAULT '',
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "completed_at" timestamptz
+);
+
+CREATE INDEX ON "user_deletions" ("scheduled_for") WHERE "status" = 'scheduled';
+
+COMMENT ON COLUMN "user_deletions"."status" IS 'scheduled, cancelled, completed or failed';
+
+COMMENT ON COLUMN "user_deletions"."scheduled_for" IS 'the end of the cooling-off period, when the data of the user is deleted unless they cancel';
+
+COMMENT ON COLUMN "user_deletions"."error" IS 'why the deletion failed, e.g. an account of the user holding money';
+
+ALTER TABLE "user_deletions" ADD FOREIGN KEY ("username") REFERENCES "users" ("username");
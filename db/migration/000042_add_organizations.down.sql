@@ -0,0 +1,7 @@
+ALTER TABLE "accounts" DROP COLUMN IF EXISTS "org_id";
+
+ALTER TABLE "users" DROP COLUMN IF EXISTS "org_id";
+
+COMMENT ON COLUMN "users"."role" IS 'depositor, admin or system';
+
+DROP TABLE IF EXISTS "organizations";
@@ -0,0 +1,31 @@
+CREATE TABLE "organizations" (
+  "id" bigserial PRIMARY KEY,
+  "slug" varchar UNIQUE NOT NULL,
+  "name" varchar NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "organizations"."slug" IS 'the name of the organization in urls and at the sign up of its users, e.g. acme';
+
+-- the users and accounts from before the organizations belong to the default one
+INSERT INTO "organizations" ("id", "slug", "name") VALUES (1, 'default', 'Default');
+
+SELECT setval('organizations_id_seq', 1);
+
+ALTER TABLE "users" ADD COLUMN "org_id" bigint NOT NULL DEFAULT 1;
+
+ALTER TABLE "accounts" ADD COLUMN "org_id" bigint NOT NULL DEFAULT 1;
+
+COMMENT ON COLUMN "users"."org_id" IS 'the organization of the user, whose data the user can''t see outside of it';
+
+COMMENT ON COLUMN "accounts"."org_id" IS 'the organization of the owner of the account';
+
+COMMENT ON COLUMN "users"."role" IS 'depositor, org_admin, admin or system';
+
+CREATE INDEX ON "users" ("org_id");
+
+CREATE INDEX ON "accounts" ("org_id");
+
+ALTER TABLE "users" ADD FOREIGN KEY ("org_id") REFERENCES "organizations" ("id");
+
+ALTER TABLE "accounts" ADD FOREIGN KEY ("org_id") REFERENCES "organizations" ("id");
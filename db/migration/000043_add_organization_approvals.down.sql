@@ -0,0 +1,5 @@
+COMMENT ON COLUMN "pending_transfers"."decided_by" IS 'the owner or the banker who approved or rejected the transfer';
+
+ALTER TABLE "pending_transfers" DROP COLUMN IF EXISTS "org_id";
+
+ALTER TABLE "organizations" DROP COLUMN IF EXISTS "approval_threshold";
@@ -0,0 +1,13 @@
+ALTER TABLE "organizations" ADD COLUMN "approval_threshold" bigint NOT NULL DEFAULT 0;
+
+ALTER TABLE "pending_transfers" ADD COLUMN "org_id" bigint;
+
+COMMENT ON COLUMN "organizations"."approval_threshold" IS 'the transfers of at least this amount from the accounts of the organization await a second approver, none when 0';
+
+COMMENT ON COLUMN "pending_transfers"."org_id" IS 'the organization whose admins approve the transfer, a second approver other than its owner; null for the transfers the owner confirms';
+
+COMMENT ON COLUMN "pending_transfers"."decided_by" IS 'the owner, the banker or the admin of the organization who approved or rejected the transfer';
+
+CREATE INDEX ON "pending_transfers" ("org_id", "status");
+
+ALTER TABLE "pending_transfers" ADD FOREIGN KEY ("org_id") REFERENCES "organizations" ("id");
@@ -0,0 +1,16 @@
+DROP TABLE IF EXISTS "card_holds";
+
+DROP TABLE IF EXISTS "cards";
+
+CREATE TEMPORARY TABLE "settlement_accounts" AS
+SELECT "account_id" FROM "system_accounts" WHERE "purpose" = 'card_settlement';
+
+DELETE FROM "system_accounts" WHERE "purpose" = 'card_settlement';
+
+DELETE FROM "account_history" WHERE "account_id" IN (SELECT "account_id" FROM "settlement_accounts");
+
+DELETE FROM "accounts" WHERE "id" IN (SELECT "account_id" FROM "settlement_accounts");
+
+DROP TABLE "settlement_accounts";
+
+COMMENT ON COLUMN "system_accounts"."purpose" IS 'fees, fx_spread, suspense or external_clearing';
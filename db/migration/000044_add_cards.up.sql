@@ -0,0 +1,83 @@
+CREATE TABLE "cards" (
+  "id" bigserial PRIMARY KEY,
+  "account_id" bigint NOT NULL,
+  "owner" varchar NOT NULL,
+  "last4" varchar NOT NULL,
+  "expiry_month" int NOT NULL,
+  "expiry_year" int NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'active',
+  "created_at" timestamptz NOT NULL DEFAULT (now()),
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "card_holds" (
+  "id" bigserial PRIMARY KEY,
+  "card_id" bigint NOT NULL,
+  "account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "currency" varchar NOT NULL,
+  "merchant" varchar NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'authorized',
+  "captured_amount" bigint NOT NULL DEFAULT 0,
+  "transfer_id" bigint,
+  "expires_at" timestamptz NOT NULL,
+  "settled_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "cards" ("owner");
+
+CREATE INDEX ON "cards" ("account_id");
+
+CREATE INDEX ON "card_holds" ("card_id");
+
+CREATE INDEX ON "card_holds" ("account_id", "status");
+
+COMMENT ON COLUMN "cards"."owner" IS 'the user the card was issued to';
+
+COMMENT ON COLUMN "cards"."last4" IS 'the last 4 digits of the card number, the only ones kept';
+
+COMMENT ON COLUMN "cards"."status" IS 'active or frozen, a frozen card declining every authorization';
+
+COMMENT ON COLUMN "card_holds"."amount" IS 'the amount reserved on the account by the authorization, must be positive';
+
+COMMENT ON COLUMN "card_holds"."merchant" IS 'the merchant the card was authorized for';
+
+COMMENT ON COLUMN "card_holds"."status" IS 'authorized, captured, released or expired, only authorized holds reserving their amount';
+
+COMMENT ON COLUMN "card_holds"."captured_amount" IS 'the amount settled when captured, at most the amount of the hold';
+
+COMMENT ON COLUMN "card_holds"."transfer_id" IS 'the transfer of the captured amount to the card_settlement account';
+
+COMMENT ON COLUMN "card_holds"."expires_at" IS 'the hold is released past this time unless captured';
+
+ALTER TABLE "cards" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "cards" ADD FOREIGN KEY ("owner") REFERENCES "users" ("username");
+
+ALTER TABLE "card_holds" ADD FOREIGN KEY ("card_id") REFERENCES "cards" ("id");
+
+ALTER TABLE "card_holds" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "card_holds" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+COMMENT ON COLUMN "system_accounts"."purpose" IS 'fees, fx_spread, suspense, external_clearing or card_settlement';
+
+-- the card payments captured are held by the card_settlement accounts until the card network settles them
+DO $$
+DECLARE
+  system_currency varchar;
+BEGIN
+  FOREACH system_currency IN ARRAY ARRAY['USD', 'EUR', 'CAD'] LOOP
+    WITH account AS (
+      INSERT INTO "accounts" ("owner", "balance", "currency")
+      VALUES ('system', 0, system_currency)
+      RETURNING "id", "owner", "currency", "created_at"
+    ), history AS (
+      INSERT INTO "account_history" ("account_id", "owner", "currency", "valid_from")
+      SELECT "id", "owner", "currency", "created_at" FROM account
+    )
+    INSERT INTO "system_accounts" ("purpose", "currency", "account_id")
+    SELECT 'card_settlement', system_currency, "id" FROM account;
+  END LOOP;
+END $$;
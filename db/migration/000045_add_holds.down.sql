@@ -0,0 +1,30 @@
+ALTER TABLE "accounts" DROP CONSTRAINT IF EXISTS "held_balance_non_negative";
+
+ALTER TABLE "accounts" DROP COLUMN IF EXISTS "held_balance";
+
+COMMENT ON COLUMN "accounts"."balance" IS NULL;
+
+-- the holds placed without a card can't be kept as card holds
+DELETE FROM "holds" WHERE "card_id" IS NULL;
+
+ALTER TABLE "holds" RENAME COLUMN "memo" TO "merchant";
+
+ALTER TABLE "holds" ALTER COLUMN "card_id" SET NOT NULL;
+
+ALTER INDEX "holds_account_id_status_idx" RENAME TO "card_holds_account_id_status_idx";
+
+ALTER INDEX "holds_card_id_idx" RENAME TO "card_holds_card_id_idx";
+
+ALTER INDEX "holds_pkey" RENAME TO "card_holds_pkey";
+
+ALTER SEQUENCE "holds_id_seq" RENAME TO "card_holds_id_seq";
+
+ALTER TABLE "holds" RENAME TO "card_holds";
+
+COMMENT ON COLUMN "card_holds"."card_id" IS NULL;
+
+COMMENT ON COLUMN "card_holds"."amount" IS 'the amount reserved on the account by the authorization, must be positive';
+
+COMMENT ON COLUMN "card_holds"."merchant" IS 'the merchant the card was authorized for';
+
+COMMENT ON COLUMN "card_holds"."transfer_id" IS 'the transfer of the captured amount to the card_settlement account';
@@ -0,0 +1,38 @@
+-- the card holds become the holds of any kind, a card authorization being one of them
+ALTER TABLE "card_holds" RENAME TO "holds";
+
+ALTER SEQUENCE "card_holds_id_seq" RENAME TO "holds_id_seq";
+
+ALTER INDEX "card_holds_pkey" RENAME TO "holds_pkey";
+
+ALTER INDEX "card_holds_card_id_idx" RENAME TO "holds_card_id_idx";
+
+ALTER INDEX "card_holds_account_id_status_idx" RENAME TO "holds_account_id_status_idx";
+
+ALTER TABLE "holds" ALTER COLUMN "card_id" DROP NOT NULL;
+
+ALTER TABLE "holds" RENAME COLUMN "merchant" TO "memo";
+
+COMMENT ON COLUMN "holds"."card_id" IS 'the card whose authorization placed the hold, if any';
+
+COMMENT ON COLUMN "holds"."amount" IS 'the amount reserved on the account, must be positive';
+
+COMMENT ON COLUMN "holds"."memo" IS 'what the amount is reserved for, e.g. the merchant a card was authorized for';
+
+COMMENT ON COLUMN "holds"."transfer_id" IS 'the transfer of the captured amount out of the account';
+
+ALTER TABLE "accounts" ADD COLUMN "held_balance" bigint NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN "accounts"."balance" IS 'the ledger balance, the sum of the entries of the account';
+
+COMMENT ON COLUMN "accounts"."held_balance" IS 'the sum of the authorized holds, which the available balance is the ledger balance less of';
+
+UPDATE "accounts" SET "held_balance" = "held"."amount"
+FROM (
+  SELECT "account_id", SUM("amount") AS "amount" FROM "holds"
+  WHERE "status" = 'authorized'
+  GROUP BY "account_id"
+) AS "held"
+WHERE "accounts"."id" = "held"."account_id";
+
+ALTER TABLE "accounts" ADD CONSTRAINT "held_balance_non_negative" CHECK ("held_balance" >= 0);
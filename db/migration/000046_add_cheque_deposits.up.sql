@@ -0,0 +1,66 @@
+CREATE TABLE "cheque_deposits" (
+  "id" bigserial PRIMARY KEY,
+  "account_id" bigint NOT NULL,
+  "owner" varchar NOT NULL,
+  "amount" bigint NOT NULL,
+  "currency" varchar NOT NULL,
+  "cheque_number" varchar NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'pending',
+  "transfer_id" bigint NOT NULL,
+  "hold_id" bigint NOT NULL,
+  "reversal_transfer_id" bigint,
+  "clears_at" timestamptz NOT NULL,
+  "settled_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "cheque_deposits" ("account_id");
+
+CREATE INDEX ON "cheque_deposits" ("status", "clears_at");
+
+COMMENT ON COLUMN "cheque_deposits"."owner" IS 'the user who deposited the cheque';
+
+COMMENT ON COLUMN "cheque_deposits"."amount" IS 'the amount of the cheque credited to the account, must be positive';
+
+COMMENT ON COLUMN "cheque_deposits"."cheque_number" IS 'the number printed on the cheque';
+
+COMMENT ON COLUMN "cheque_deposits"."status" IS 'pending, cleared or bounced, the amount of a pending cheque being held on the account';
+
+COMMENT ON COLUMN "cheque_deposits"."transfer_id" IS 'the transfer crediting the amount from the cheque_clearing account';
+
+COMMENT ON COLUMN "cheque_deposits"."hold_id" IS 'the hold reserving the amount credited until the cheque clears';
+
+COMMENT ON COLUMN "cheque_deposits"."reversal_transfer_id" IS 'the transfer reversing the credit of a bounced cheque';
+
+COMMENT ON COLUMN "cheque_deposits"."clears_at" IS 'the hold is released at this time unless the cheque bounced';
+
+ALTER TABLE "cheque_deposits" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "cheque_deposits" ADD FOREIGN KEY ("owner") REFERENCES "users" ("username");
+
+ALTER TABLE "cheque_deposits" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
+
+ALTER TABLE "cheque_deposits" ADD FOREIGN KEY ("hold_id") REFERENCES "holds" ("id");
+
+ALTER TABLE "cheque_deposits" ADD FOREIGN KEY ("reversal_transfer_id") REFERENCES "transfers" ("id");
+
+COMMENT ON COLUMN "system_accounts"."purpose" IS 'fees, fx_spread, suspense, external_clearing, card_settlement or cheque_clearing';
+
+-- the cheques deposited are credited from the cheque_clearing accounts until the bank of the drawer pays them
+DO $$
+DECLARE
+  system_currency varchar;
+BEGIN
+  FOREACH system_currency IN ARRAY ARRAY['USD', 'EUR', 'CAD'] LOOP
+    WITH account AS (
+      INSERT INTO "accounts" ("owner", "balance", "currency")
+      VALUES ('system', 0, system_currency)
+      RETURNING "id", "owner", "currency", "created_at"
+    ), history AS (
+      INSERT INTO "account_history" ("account_id", "owner", "currency", "valid_from")
+      SELECT "id", "owner", "currency", "created_at" FROM account
+    )
+    INSERT INTO "system_accounts" ("purpose", "currency", "account_id")
+    SELECT 'cheque_clearing', system_currency, "id" FROM account;
+  END LOOP;
+END $$;
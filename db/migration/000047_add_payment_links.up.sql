@@ -0,0 +1,50 @@
+CREATE TABLE "payment_links" (
+  "id" bigserial PRIMARY KEY,
+  "code" varchar UNIQUE NOT NULL,
+  "merchant" varchar NOT NULL,
+  "to_account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "currency" varchar NOT NULL,
+  "memo" varchar NOT NULL DEFAULT '',
+  "status" varchar NOT NULL DEFAULT 'active',
+  "expires_at" timestamptz NOT NULL,
+  "payer" varchar,
+  "from_account_id" bigint,
+  "transfer_id" bigint,
+  "closed_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "payment_links" ("merchant");
+
+CREATE INDEX ON "payment_links" ("status", "expires_at");
+
+COMMENT ON COLUMN "payment_links"."code" IS 'the random code the link is shared and looked up with';
+
+COMMENT ON COLUMN "payment_links"."merchant" IS 'the user the link collects money for';
+
+COMMENT ON COLUMN "payment_links"."to_account_id" IS 'the account of the merchant the money is sent to';
+
+COMMENT ON COLUMN "payment_links"."amount" IS 'the amount to pay, must be positive';
+
+COMMENT ON COLUMN "payment_links"."status" IS 'active, paid, cancelled or expired, only an active link can be paid';
+
+COMMENT ON COLUMN "payment_links"."expires_at" IS 'an active link can no longer be paid past this time';
+
+COMMENT ON COLUMN "payment_links"."payer" IS 'the user who paid the link';
+
+COMMENT ON COLUMN "payment_links"."from_account_id" IS 'the account of the payer the money was taken from once paid';
+
+COMMENT ON COLUMN "payment_links"."transfer_id" IS 'the transfer made once the link was paid';
+
+COMMENT ON COLUMN "payment_links"."closed_at" IS 'when the link was paid, cancelled or expired';
+
+ALTER TABLE "payment_links" ADD FOREIGN KEY ("merchant") REFERENCES "users" ("username");
+
+ALTER TABLE "payment_links" ADD FOREIGN KEY ("to_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "payment_links" ADD FOREIGN KEY ("payer") REFERENCES "users" ("username");
+
+ALTER TABLE "payment_links" ADD FOREIGN KEY ("from_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "payment_links" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfers" ("id");
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS "transfers_memo_search_idx";
@@ -0,0 +1,2 @@
+-- the search of the entries of an account matches the memos of their transfers with this index
+CREATE INDEX "transfers_memo_search_idx" ON "transfers" USING gin (to_tsvector('simple', "memo"));
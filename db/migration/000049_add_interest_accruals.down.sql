@@ -0,0 +1,14 @@
+DROP TABLE IF EXISTS "interest_accruals";
+
+CREATE TEMPORARY TABLE "interest_accounts" AS
+SELECT "account_id" FROM "system_accounts" WHERE "purpose" = 'interest';
+
+DELETE FROM "system_accounts" WHERE "purpose" = 'interest';
+
+DELETE FROM "account_history" WHERE "account_id" IN (SELECT "account_id" FROM "interest_accounts");
+
+DELETE FROM "accounts" WHERE "id" IN (SELECT "account_id" FROM "interest_accounts");
+
+DROP TABLE "interest_accounts";
+
+COMMENT ON COLUMN "system_accounts"."purpose" IS 'fees, fx_spread, suspense, external_clearing, card_settlement or cheque_clearing';
@@ -0,0 +1,30 @@
+CREATE TABLE "interest_accruals" (
+  "account_id" bigint PRIMARY KEY,
+  "remainder" bigint NOT NULL DEFAULT 0,
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "interest_accruals"."remainder" IS 'the interest accrued but not yet credited to the account, in millionths of its smallest unit';
+
+ALTER TABLE "interest_accruals" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id") ON DELETE CASCADE;
+
+COMMENT ON COLUMN "system_accounts"."purpose" IS 'fees, fx_spread, suspense, external_clearing, card_settlement, cheque_clearing or interest';
+
+-- the interest credited to the accounts is paid from the interest accounts, the interest expense of the bank
+DO $$
+DECLARE
+  system_currency varchar;
+BEGIN
+  FOREACH system_currency IN ARRAY ARRAY['USD', 'EUR', 'CAD'] LOOP
+    WITH account AS (
+      INSERT INTO "accounts" ("owner", "balance", "currency")
+      VALUES ('system', 0, system_currency)
+      RETURNING "id", "owner", "currency", "created_at"
+    ), history AS (
+      INSERT INTO "account_history" ("account_id", "owner", "currency", "valid_from")
+      SELECT "id", "owner", "currency", "created_at" FROM account
+    )
+    INSERT INTO "system_accounts" ("purpose", "currency", "account_id")
+    SELECT 'interest', system_currency, "id" FROM account;
+  END LOOP;
+END $$;
@@ -0,0 +1,11 @@
+DROP TRIGGER IF EXISTS "entries_check_balanced" ON "entries";
+DROP FUNCTION IF EXISTS "check_entries_balanced";
+
+CREATE OR REPLACE FUNCTION "post_entry"() RETURNS trigger AS $$
+BEGIN
+  PERFORM add_unposted_amount(NEW."account_id", -NEW."amount");
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP FUNCTION IF EXISTS "add_unbalanced_amount";
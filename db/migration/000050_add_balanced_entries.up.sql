@@ -0,0 +1,37 @@
+-- The entries of a transaction must sum to zero, so that money moves between accounts rather than being
+-- created or destroyed by a one-sided entry. The sum of the entries recorded so far is kept in a setting
+-- local to the transaction, along with the unposted amount of their account, and must be back to zero
+-- when it commits. The transfers post the same amount on both sides, so they balance in every currency.
+CREATE FUNCTION "add_unbalanced_amount"("amount" bigint) RETURNS bigint AS $$
+DECLARE
+  "unbalanced" bigint;
+BEGIN
+  "unbalanced" := COALESCE(NULLIF(current_setting('bank.unbalanced', true), ''), '0')::bigint + "amount";
+  PERFORM set_config('bank.unbalanced', "unbalanced"::text, true);
+  RETURN "unbalanced";
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION "post_entry"() RETURNS trigger AS $$
+BEGIN
+  PERFORM add_unposted_amount(NEW."account_id", -NEW."amount");
+  PERFORM add_unbalanced_amount(NEW."amount");
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE FUNCTION "check_entries_balanced"() RETURNS trigger AS $$
+BEGIN
+  IF add_unbalanced_amount(0) <> 0 THEN
+    RAISE EXCEPTION 'entries of the transaction don''t sum to zero, entry % unbalanced', NEW."id"
+      USING ERRCODE = 'check_violation';
+  END IF;
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+-- checked at commit, once every entry of the transaction is recorded
+CREATE CONSTRAINT TRIGGER "entries_check_balanced"
+  AFTER INSERT ON "entries"
+  DEFERRABLE INITIALLY DEFERRED
+  FOR EACH ROW EXECUTE FUNCTION check_entries_balanced();
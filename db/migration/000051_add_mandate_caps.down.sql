@@ -0,0 +1 @@
+ALTER TABLE "mandates" DROP COLUMN IF EXISTS "cap_amount";
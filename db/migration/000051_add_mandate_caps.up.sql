@@ -0,0 +1,3 @@
+ALTER TABLE "mandates" ADD COLUMN "cap_amount" bigint;
+
+COMMENT ON COLUMN "mandates"."cap_amount" IS 'the most pulled with the mandate in total, at least the maximum of a pull, none for the mandates granted before the caps';
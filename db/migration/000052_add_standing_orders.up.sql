@@ -0,0 +1,42 @@
+CREATE TABLE "standing_orders" (
+  "id" bigserial PRIMARY KEY,
+  "owner" varchar NOT NULL,
+  "from_account_id" bigint NOT NULL,
+  "to_account_id" bigint NOT NULL,
+  "amount" bigint NOT NULL,
+  "currency" varchar NOT NULL,
+  "memo" varchar NOT NULL DEFAULT '',
+  "frequency" varchar NOT NULL,
+  "starts_at" timestamptz NOT NULL,
+  "run_count" integer NOT NULL DEFAULT 0,
+  "next_run_at" timestamptz NOT NULL,
+  "status" varchar NOT NULL DEFAULT 'active',
+  "last_run_at" timestamptz,
+  "last_failure" varchar NOT NULL DEFAULT '',
+  "cancelled_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "standing_orders" ("owner");
+
+CREATE INDEX ON "standing_orders" ("status", "next_run_at");
+
+COMMENT ON COLUMN "standing_orders"."owner" IS 'the user who set up the standing order, who must be able to use the from account as an owner';
+
+COMMENT ON COLUMN "standing_orders"."amount" IS 'must be positive, the amount transferred on every run';
+
+COMMENT ON COLUMN "standing_orders"."frequency" IS 'weekly or monthly';
+
+COMMENT ON COLUMN "standing_orders"."starts_at" IS 'the first run, the later ones falling on the same weekday or day of the month, or the last day of a shorter month';
+
+COMMENT ON COLUMN "standing_orders"."run_count" IS 'the runs due so far, whether their transfer was made or failed';
+
+COMMENT ON COLUMN "standing_orders"."status" IS 'active or cancelled, only active standing orders run';
+
+COMMENT ON COLUMN "standing_orders"."last_failure" IS 'why the transfer of the last run failed, empty when it was made';
+
+ALTER TABLE "standing_orders" ADD FOREIGN KEY ("owner") REFERENCES "users" ("username");
+
+ALTER TABLE "standing_orders" ADD FOREIGN KEY ("from_account_id") REFERENCES "accounts" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "standing_orders" ADD FOREIGN KEY ("to_account_id") REFERENCES "accounts" ("id") ON DELETE CASCADE;
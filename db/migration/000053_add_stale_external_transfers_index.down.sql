@@ -0,0 +1 @@
+DROP INDEX IF EXISTS "external_transfers_updated_at_idx";
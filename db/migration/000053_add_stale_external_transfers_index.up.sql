@@ -0,0 +1 @@
+CREATE INDEX ON "external_transfers" ("updated_at") WHERE "status" IN ('initiated', 'processing');
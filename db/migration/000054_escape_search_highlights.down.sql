@@ -0,0 +1,3 @@
+DROP FUNCTION IF EXISTS "html_escape";
+
+CREATE INDEX "transfers_memo_search_idx" ON "transfers" USING gin (to_tsvector('simple', "memo"));
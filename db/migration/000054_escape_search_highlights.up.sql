@@ -0,0 +1,8 @@
+-- the search of the entries of an account is driven by the entries of the account, the memo matched
+-- being one of several alternatives, so the index of the memos was never used
+DROP INDEX IF EXISTS "transfers_memo_search_idx";
+
+-- the highlights of the search are HTML, so the text they are made of is escaped first
+CREATE FUNCTION "html_escape"("text" text) RETURNS text AS $$
+  SELECT replace(replace(replace(replace(replace("text", '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&quot;'), '''', '&#39;');
+$$ LANGUAGE sql IMMUTABLE STRICT;
@@ -0,0 +1,16 @@
+-- only the latest lockout of each user is kept
+DELETE FROM "login_lockouts" AS "older" USING "login_lockouts" AS "newer"
+WHERE "older"."username" = "newer"."username"
+  AND ("older"."locked_until", "older"."client_ip") < ("newer"."locked_until", "newer"."client_ip");
+
+ALTER TABLE "login_lockouts" DROP CONSTRAINT "login_lockouts_pkey";
+
+ALTER TABLE "login_lockouts" ADD PRIMARY KEY ("username");
+
+DROP INDEX IF EXISTS "login_failures_client_ip_created_at_idx";
+
+DROP INDEX IF EXISTS "login_failures_username_client_ip_created_at_idx";
+
+CREATE INDEX ON "login_failures" ("username", "created_at");
+
+COMMENT ON COLUMN "login_lockouts"."client_ip" IS 'the client of the failed login that locked the user';
@@ -0,0 +1,15 @@
+-- a user is locked out of logging in from the client that failed, rather than from everywhere, so that
+-- someone guessing their password can't keep them out
+ALTER TABLE "login_lockouts" DROP CONSTRAINT "login_lockouts_pkey";
+
+ALTER TABLE "login_lockouts" ADD PRIMARY KEY ("username", "client_ip");
+
+DROP INDEX IF EXISTS "login_failures_username_created_at_idx";
+
+CREATE INDEX ON "login_failures" ("username", "client_ip", "created_at");
+
+-- the failed logins from a client are also counted whatever the user, to limit the guesses of a client
+-- trying many users
+CREATE INDEX ON "login_failures" ("client_ip", "created_at");
+
+COMMENT ON COLUMN "login_lockouts"."client_ip" IS 'the client locked out of logging in as the user, the other clients still can';
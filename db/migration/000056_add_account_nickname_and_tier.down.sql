@@ -0,0 +1,21 @@
+-- the closed versions are dropped, the versions they replaced becoming current again
+DELETE FROM "account_history" WHERE "status" = 'closed';
+
+UPDATE "account_history" SET "valid_to" = NULL
+FROM "accounts"
+WHERE "account_history"."account_id" = "accounts"."id"
+AND "account_history"."valid_to" = "accounts"."closed_at"
+AND NOT EXISTS (
+  SELECT 1 FROM "account_history" AS "current"
+  WHERE "current"."account_id" = "accounts"."id" AND "current"."valid_to" IS NULL
+);
+
+ALTER TABLE "account_history" DROP COLUMN IF EXISTS "tier";
+
+ALTER TABLE "account_history" DROP COLUMN IF EXISTS "nickname";
+
+ALTER TABLE "account_history" DROP COLUMN IF EXISTS "status";
+
+ALTER TABLE "accounts" DROP COLUMN IF EXISTS "tier";
+
+ALTER TABLE "accounts" DROP COLUMN IF EXISTS "nickname";
@@ -0,0 +1,27 @@
+ALTER TABLE "accounts" ADD COLUMN "nickname" varchar NOT NULL DEFAULT '';
+
+ALTER TABLE "accounts" ADD COLUMN "tier" varchar NOT NULL DEFAULT 'standard';
+
+COMMENT ON COLUMN "accounts"."nickname" IS 'the name the owner gave the account, empty when they gave none';
+
+COMMENT ON COLUMN "accounts"."tier" IS 'the service tier of the account, set by admins, e.g. standard or premium';
+
+-- the history also versions the status, the nickname and the tier of the accounts
+ALTER TABLE "account_history" ADD COLUMN "status" varchar NOT NULL DEFAULT 'open';
+
+ALTER TABLE "account_history" ADD COLUMN "nickname" varchar NOT NULL DEFAULT '';
+
+ALTER TABLE "account_history" ADD COLUMN "tier" varchar NOT NULL DEFAULT 'standard';
+
+COMMENT ON COLUMN "account_history"."status" IS 'open, or closed once the data of its owner was deleted';
+
+-- the accounts closed before their closure was versioned get a closed version from their closure
+UPDATE "account_history" SET "valid_to" = "accounts"."closed_at"
+FROM "accounts"
+WHERE "account_history"."account_id" = "accounts"."id"
+AND "account_history"."valid_to" IS NULL
+AND "accounts"."closed_at" IS NOT NULL;
+
+INSERT INTO "account_history" ("account_id", "owner", "currency", "status", "valid_from")
+SELECT "id", "owner", "currency", 'closed', "closed_at" FROM "accounts"
+WHERE "closed_at" IS NOT NULL;
@@ -0,0 +1,70 @@
+package migration
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/pgx/v5"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+)
+
+// driverScheme is the scheme of the database urls migrate runs with its pgx v5 driver, the one of the
+// store, rather than the database/sql driver of lib/pq.
+const driverScheme = "pgx5"
+
+// files holds the sql migrations so that the binary can migrate the schema without the
+// migration directory or the migrate cli being shipped alongside it.
+//
+//go:embed *.sql
+var files embed.FS
+
+// The `New` function creates a migrate instance reading the embedded migrations and applying them to
+// the database at `dbSource`. The caller is responsible for closing it.
+func New(dbSource string) (*migrate.Migrate, error) {
+	source, err := iofs.New(files, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	databaseURL, err := driverURL(dbSource)
+	if err != nil {
+		return nil, err
+	}
+
+	return migrate.NewWithSourceInstance("iofs", source, databaseURL)
+}
+
+// The `driverURL` function returns the url of the database at `dbSource` with the scheme of the pgx v5
+// driver of migrate, which connects with the postgres scheme again.
+func driverURL(dbSource string) (string, error) {
+	databaseURL, err := url.Parse(dbSource)
+	if err != nil {
+		return "", err
+	}
+	if databaseURL.Scheme != "postgres" && databaseURL.Scheme != "postgresql" {
+		return "", fmt.Errorf("unsupported database url scheme %q", databaseURL.Scheme)
+	}
+
+	databaseURL.Scheme = driverScheme
+	return databaseURL.String(), nil
+}
+
+// The `Up` function applies every pending migration to the database at `dbSource`. A schema that is
+// already up to date is not an error.
+func Up(dbSource string) error {
+	m, err := New(dbSource)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	err = m.Up()
+	if err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return err
+	}
+
+	return nil
+}
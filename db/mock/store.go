@@ -8,9 +8,11 @@ import (
 	context "context"
 	db "go-backend/db/sqlc"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	uuid "github.com/google/uuid"
+	pgtype "github.com/jackc/pgx/v5/pgtype"
 )
 
 // MockStore is a mock of Store interface.
@@ -36,6 +38,81 @@ func (m *MockStore) EXPECT() *MockStoreMockRecorder {
 	return m.recorder
 }
 
+// AcceptAccountMember mocks base method.
+func (m *MockStore) AcceptAccountMember(arg0 context.Context, arg1 db.AcceptAccountMemberParams) (db.AccountMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcceptAccountMember", arg0, arg1)
+	ret0, _ := ret[0].(db.AccountMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AcceptAccountMember indicates an expected call of AcceptAccountMember.
+func (mr *MockStoreMockRecorder) AcceptAccountMember(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptAccountMember", reflect.TypeOf((*MockStore)(nil).AcceptAccountMember), arg0, arg1)
+}
+
+// AcceptPaymentRequest mocks base method.
+func (m *MockStore) AcceptPaymentRequest(arg0 context.Context, arg1 db.AcceptPaymentRequestParams) (db.PaymentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcceptPaymentRequest", arg0, arg1)
+	ret0, _ := ret[0].(db.PaymentRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AcceptPaymentRequest indicates an expected call of AcceptPaymentRequest.
+func (mr *MockStoreMockRecorder) AcceptPaymentRequest(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptPaymentRequest", reflect.TypeOf((*MockStore)(nil).AcceptPaymentRequest), arg0, arg1)
+}
+
+// AcceptPaymentRequestTx mocks base method.
+func (m *MockStore) AcceptPaymentRequestTx(arg0 context.Context, arg1 db.AcceptPaymentRequestTxParams) (db.AcceptPaymentRequestTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcceptPaymentRequestTx", arg0, arg1)
+	ret0, _ := ret[0].(db.AcceptPaymentRequestTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AcceptPaymentRequestTx indicates an expected call of AcceptPaymentRequestTx.
+func (mr *MockStoreMockRecorder) AcceptPaymentRequestTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcceptPaymentRequestTx", reflect.TypeOf((*MockStore)(nil).AcceptPaymentRequestTx), arg0, arg1)
+}
+
+// AccountHasHistory mocks base method.
+func (m *MockStore) AccountHasHistory(arg0 context.Context, arg1 int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AccountHasHistory", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AccountHasHistory indicates an expected call of AccountHasHistory.
+func (mr *MockStoreMockRecorder) AccountHasHistory(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccountHasHistory", reflect.TypeOf((*MockStore)(nil).AccountHasHistory), arg0, arg1)
+}
+
+// AcquireLeaderLease mocks base method.
+func (m *MockStore) AcquireLeaderLease(arg0 context.Context, arg1 db.AcquireLeaderLeaseParams) (db.LeaderLease, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AcquireLeaderLease", arg0, arg1)
+	ret0, _ := ret[0].(db.LeaderLease)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AcquireLeaderLease indicates an expected call of AcquireLeaderLease.
+func (mr *MockStoreMockRecorder) AcquireLeaderLease(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AcquireLeaderLease", reflect.TypeOf((*MockStore)(nil).AcquireLeaderLease), arg0, arg1)
+}
+
 // AddAccountBalance mocks base method.
 func (m *MockStore) AddAccountBalance(arg0 context.Context, arg1 db.AddAccountBalanceParams) (db.Account, error) {
 	m.ctrl.T.Helper()
@@ -51,256 +128,4099 @@ func (mr *MockStoreMockRecorder) AddAccountBalance(arg0, arg1 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAccountBalance", reflect.TypeOf((*MockStore)(nil).AddAccountBalance), arg0, arg1)
 }
 
-// CreateAccount mocks base method.
-func (m *MockStore) CreateAccount(arg0 context.Context, arg1 db.CreateAccountParams) (db.Account, error) {
+// AddAccountCounterpartyActivity mocks base method.
+func (m *MockStore) AddAccountCounterpartyActivity(arg0 context.Context, arg1 db.AddAccountCounterpartyActivityParams) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateAccount", arg0, arg1)
+	ret := m.ctrl.Call(m, "AddAccountCounterpartyActivity", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddAccountCounterpartyActivity indicates an expected call of AddAccountCounterpartyActivity.
+func (mr *MockStoreMockRecorder) AddAccountCounterpartyActivity(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAccountCounterpartyActivity", reflect.TypeOf((*MockStore)(nil).AddAccountCounterpartyActivity), arg0, arg1)
+}
+
+// AddAccountDailyActivity mocks base method.
+func (m *MockStore) AddAccountDailyActivity(arg0 context.Context, arg1 db.AddAccountDailyActivityParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddAccountDailyActivity", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddAccountDailyActivity indicates an expected call of AddAccountDailyActivity.
+func (mr *MockStoreMockRecorder) AddAccountDailyActivity(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAccountDailyActivity", reflect.TypeOf((*MockStore)(nil).AddAccountDailyActivity), arg0, arg1)
+}
+
+// AddAccountDailyVolume mocks base method.
+func (m *MockStore) AddAccountDailyVolume(arg0 context.Context, arg1 db.AddAccountDailyVolumeParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddAccountDailyVolume", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddAccountDailyVolume indicates an expected call of AddAccountDailyVolume.
+func (mr *MockStoreMockRecorder) AddAccountDailyVolume(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAccountDailyVolume", reflect.TypeOf((*MockStore)(nil).AddAccountDailyVolume), arg0, arg1)
+}
+
+// AddAccountHeldBalance mocks base method.
+func (m *MockStore) AddAccountHeldBalance(arg0 context.Context, arg1 db.AddAccountHeldBalanceParams) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddAccountHeldBalance", arg0, arg1)
 	ret0, _ := ret[0].(db.Account)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateAccount indicates an expected call of CreateAccount.
-func (mr *MockStoreMockRecorder) CreateAccount(arg0, arg1 interface{}) *gomock.Call {
+// AddAccountHeldBalance indicates an expected call of AddAccountHeldBalance.
+func (mr *MockStoreMockRecorder) AddAccountHeldBalance(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccount", reflect.TypeOf((*MockStore)(nil).CreateAccount), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAccountHeldBalance", reflect.TypeOf((*MockStore)(nil).AddAccountHeldBalance), arg0, arg1)
 }
 
-// CreateEntry mocks base method.
-func (m *MockStore) CreateEntry(arg0 context.Context, arg1 db.CreateEntryParams) (db.Entry, error) {
+// AddRouteRequestVolume mocks base method.
+func (m *MockStore) AddRouteRequestVolume(arg0 context.Context, arg1 db.AddRouteRequestVolumeParams) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateEntry", arg0, arg1)
-	ret0, _ := ret[0].(db.Entry)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "AddRouteRequestVolume", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
-// CreateEntry indicates an expected call of CreateEntry.
-func (mr *MockStoreMockRecorder) CreateEntry(arg0, arg1 interface{}) *gomock.Call {
+// AddRouteRequestVolume indicates an expected call of AddRouteRequestVolume.
+func (mr *MockStoreMockRecorder) AddRouteRequestVolume(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEntry", reflect.TypeOf((*MockStore)(nil).CreateEntry), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddRouteRequestVolume", reflect.TypeOf((*MockStore)(nil).AddRouteRequestVolume), arg0, arg1)
 }
 
-// CreateSession mocks base method.
-func (m *MockStore) CreateSession(arg0 context.Context, arg1 db.CreateSessionParams) (db.Session, error) {
+// AddUserOverviewAccountCount mocks base method.
+func (m *MockStore) AddUserOverviewAccountCount(arg0 context.Context, arg1 db.AddUserOverviewAccountCountParams) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateSession", arg0, arg1)
-	ret0, _ := ret[0].(db.Session)
+	ret := m.ctrl.Call(m, "AddUserOverviewAccountCount", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddUserOverviewAccountCount indicates an expected call of AddUserOverviewAccountCount.
+func (mr *MockStoreMockRecorder) AddUserOverviewAccountCount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddUserOverviewAccountCount", reflect.TypeOf((*MockStore)(nil).AddUserOverviewAccountCount), arg0, arg1)
+}
+
+// AdjustAccountBalanceTx mocks base method.
+func (m *MockStore) AdjustAccountBalanceTx(arg0 context.Context, arg1 db.AdjustAccountBalanceTxParams) (db.TransferTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdjustAccountBalanceTx", arg0, arg1)
+	ret0, _ := ret[0].(db.TransferTxResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateSession indicates an expected call of CreateSession.
-func (mr *MockStoreMockRecorder) CreateSession(arg0, arg1 interface{}) *gomock.Call {
+// AdjustAccountBalanceTx indicates an expected call of AdjustAccountBalanceTx.
+func (mr *MockStoreMockRecorder) AdjustAccountBalanceTx(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSession", reflect.TypeOf((*MockStore)(nil).CreateSession), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdjustAccountBalanceTx", reflect.TypeOf((*MockStore)(nil).AdjustAccountBalanceTx), arg0, arg1)
 }
 
-// CreateTransfer mocks base method.
-func (m *MockStore) CreateTransfer(arg0 context.Context, arg1 db.CreateTransferParams) (db.Transfer, error) {
+// AdvanceExternalTransferTx mocks base method.
+func (m *MockStore) AdvanceExternalTransferTx(arg0 context.Context, arg1 db.AdvanceExternalTransferTxParams) (db.AdvanceExternalTransferTxResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateTransfer", arg0, arg1)
-	ret0, _ := ret[0].(db.Transfer)
+	ret := m.ctrl.Call(m, "AdvanceExternalTransferTx", arg0, arg1)
+	ret0, _ := ret[0].(db.AdvanceExternalTransferTxResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateTransfer indicates an expected call of CreateTransfer.
-func (mr *MockStoreMockRecorder) CreateTransfer(arg0, arg1 interface{}) *gomock.Call {
+// AdvanceExternalTransferTx indicates an expected call of AdvanceExternalTransferTx.
+func (mr *MockStoreMockRecorder) AdvanceExternalTransferTx(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransfer", reflect.TypeOf((*MockStore)(nil).CreateTransfer), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdvanceExternalTransferTx", reflect.TypeOf((*MockStore)(nil).AdvanceExternalTransferTx), arg0, arg1)
 }
 
-// CreateUser mocks base method.
-func (m *MockStore) CreateUser(arg0 context.Context, arg1 db.CreateUserParams) (db.User, error) {
+// AnonymizeUser mocks base method.
+func (m *MockStore) AnonymizeUser(arg0 context.Context, arg1 string) (db.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateUser", arg0, arg1)
+	ret := m.ctrl.Call(m, "AnonymizeUser", arg0, arg1)
 	ret0, _ := ret[0].(db.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateUser indicates an expected call of CreateUser.
-func (mr *MockStoreMockRecorder) CreateUser(arg0, arg1 interface{}) *gomock.Call {
+// AnonymizeUser indicates an expected call of AnonymizeUser.
+func (mr *MockStoreMockRecorder) AnonymizeUser(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockStore)(nil).CreateUser), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AnonymizeUser", reflect.TypeOf((*MockStore)(nil).AnonymizeUser), arg0, arg1)
 }
 
-// DeleteAccount mocks base method.
-func (m *MockStore) DeleteAccount(arg0 context.Context, arg1 int64) error {
+// AnonymizeUserOverview mocks base method.
+func (m *MockStore) AnonymizeUserOverview(arg0 context.Context, arg1 string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteAccount", arg0, arg1)
+	ret := m.ctrl.Call(m, "AnonymizeUserOverview", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// DeleteAccount indicates an expected call of DeleteAccount.
-func (mr *MockStoreMockRecorder) DeleteAccount(arg0, arg1 interface{}) *gomock.Call {
+// AnonymizeUserOverview indicates an expected call of AnonymizeUserOverview.
+func (mr *MockStoreMockRecorder) AnonymizeUserOverview(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccount", reflect.TypeOf((*MockStore)(nil).DeleteAccount), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AnonymizeUserOverview", reflect.TypeOf((*MockStore)(nil).AnonymizeUserOverview), arg0, arg1)
 }
 
-// GetAccount mocks base method.
-func (m *MockStore) GetAccount(arg0 context.Context, arg1 int64) (db.Account, error) {
+// ApprovePendingTransfer mocks base method.
+func (m *MockStore) ApprovePendingTransfer(arg0 context.Context, arg1 db.ApprovePendingTransferParams) (db.PendingTransfer, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAccount", arg0, arg1)
-	ret0, _ := ret[0].(db.Account)
+	ret := m.ctrl.Call(m, "ApprovePendingTransfer", arg0, arg1)
+	ret0, _ := ret[0].(db.PendingTransfer)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAccount indicates an expected call of GetAccount.
-func (mr *MockStoreMockRecorder) GetAccount(arg0, arg1 interface{}) *gomock.Call {
+// ApprovePendingTransfer indicates an expected call of ApprovePendingTransfer.
+func (mr *MockStoreMockRecorder) ApprovePendingTransfer(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccount", reflect.TypeOf((*MockStore)(nil).GetAccount), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApprovePendingTransfer", reflect.TypeOf((*MockStore)(nil).ApprovePendingTransfer), arg0, arg1)
 }
 
-// GetAccountForUpdate mocks base method.
-func (m *MockStore) GetAccountForUpdate(arg0 context.Context, arg1 int64) (db.Account, error) {
+// ApprovePendingTransferTx mocks base method.
+func (m *MockStore) ApprovePendingTransferTx(arg0 context.Context, arg1 db.ApprovePendingTransferTxParams) (db.ApprovePendingTransferTxResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAccountForUpdate", arg0, arg1)
-	ret0, _ := ret[0].(db.Account)
+	ret := m.ctrl.Call(m, "ApprovePendingTransferTx", arg0, arg1)
+	ret0, _ := ret[0].(db.ApprovePendingTransferTxResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAccountForUpdate indicates an expected call of GetAccountForUpdate.
-func (mr *MockStoreMockRecorder) GetAccountForUpdate(arg0, arg1 interface{}) *gomock.Call {
+// ApprovePendingTransferTx indicates an expected call of ApprovePendingTransferTx.
+func (mr *MockStoreMockRecorder) ApprovePendingTransferTx(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountForUpdate", reflect.TypeOf((*MockStore)(nil).GetAccountForUpdate), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApprovePendingTransferTx", reflect.TypeOf((*MockStore)(nil).ApprovePendingTransferTx), arg0, arg1)
 }
 
-// GetEntry mocks base method.
-func (m *MockStore) GetEntry(arg0 context.Context, arg1 int64) (db.Entry, error) {
+// AssignTransferReview mocks base method.
+func (m *MockStore) AssignTransferReview(arg0 context.Context, arg1 db.AssignTransferReviewParams) (db.TransferReview, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetEntry", arg0, arg1)
-	ret0, _ := ret[0].(db.Entry)
+	ret := m.ctrl.Call(m, "AssignTransferReview", arg0, arg1)
+	ret0, _ := ret[0].(db.TransferReview)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetEntry indicates an expected call of GetEntry.
-func (mr *MockStoreMockRecorder) GetEntry(arg0, arg1 interface{}) *gomock.Call {
+// AssignTransferReview indicates an expected call of AssignTransferReview.
+func (mr *MockStoreMockRecorder) AssignTransferReview(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntry", reflect.TypeOf((*MockStore)(nil).GetEntry), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignTransferReview", reflect.TypeOf((*MockStore)(nil).AssignTransferReview), arg0, arg1)
 }
 
-// GetSession mocks base method.
-func (m *MockStore) GetSession(arg0 context.Context, arg1 uuid.UUID) (db.Session, error) {
+// AuthorizeCardTx mocks base method.
+func (m *MockStore) AuthorizeCardTx(arg0 context.Context, arg1 db.AuthorizeCardTxParams) (db.AuthorizeCardTxResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetSession", arg0, arg1)
-	ret0, _ := ret[0].(db.Session)
+	ret := m.ctrl.Call(m, "AuthorizeCardTx", arg0, arg1)
+	ret0, _ := ret[0].(db.AuthorizeCardTxResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetSession indicates an expected call of GetSession.
-func (mr *MockStoreMockRecorder) GetSession(arg0, arg1 interface{}) *gomock.Call {
+// AuthorizeCardTx indicates an expected call of AuthorizeCardTx.
+func (mr *MockStoreMockRecorder) AuthorizeCardTx(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSession", reflect.TypeOf((*MockStore)(nil).GetSession), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthorizeCardTx", reflect.TypeOf((*MockStore)(nil).AuthorizeCardTx), arg0, arg1)
 }
 
-// GetTransfer mocks base method.
-func (m *MockStore) GetTransfer(arg0 context.Context, arg1 int64) (db.Transfer, error) {
+// BatchTransferTx mocks base method.
+func (m *MockStore) BatchTransferTx(arg0 context.Context, arg1 db.BatchTransferTxParams) ([]db.BatchTransferTxItem, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTransfer", arg0, arg1)
-	ret0, _ := ret[0].(db.Transfer)
+	ret := m.ctrl.Call(m, "BatchTransferTx", arg0, arg1)
+	ret0, _ := ret[0].([]db.BatchTransferTxItem)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetTransfer indicates an expected call of GetTransfer.
-func (mr *MockStoreMockRecorder) GetTransfer(arg0, arg1 interface{}) *gomock.Call {
+// BatchTransferTx indicates an expected call of BatchTransferTx.
+func (mr *MockStoreMockRecorder) BatchTransferTx(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransfer", reflect.TypeOf((*MockStore)(nil).GetTransfer), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchTransferTx", reflect.TypeOf((*MockStore)(nil).BatchTransferTx), arg0, arg1)
 }
 
-// GetUser mocks base method.
-func (m *MockStore) GetUser(arg0 context.Context, arg1 string) (db.User, error) {
+// BounceChequeDeposit mocks base method.
+func (m *MockStore) BounceChequeDeposit(arg0 context.Context, arg1 db.BounceChequeDepositParams) (db.ChequeDeposit, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUser", arg0, arg1)
-	ret0, _ := ret[0].(db.User)
+	ret := m.ctrl.Call(m, "BounceChequeDeposit", arg0, arg1)
+	ret0, _ := ret[0].(db.ChequeDeposit)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetUser indicates an expected call of GetUser.
-func (mr *MockStoreMockRecorder) GetUser(arg0, arg1 interface{}) *gomock.Call {
+// BounceChequeDeposit indicates an expected call of BounceChequeDeposit.
+func (mr *MockStoreMockRecorder) BounceChequeDeposit(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockStore)(nil).GetUser), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BounceChequeDeposit", reflect.TypeOf((*MockStore)(nil).BounceChequeDeposit), arg0, arg1)
 }
 
-// ListAccounts mocks base method.
-func (m *MockStore) ListAccounts(arg0 context.Context, arg1 db.ListAccountsParams) ([]db.Account, error) {
+// BounceChequeDepositTx mocks base method.
+func (m *MockStore) BounceChequeDepositTx(arg0 context.Context, arg1 int64) (db.BounceChequeDepositTxResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListAccounts", arg0, arg1)
-	ret0, _ := ret[0].([]db.Account)
+	ret := m.ctrl.Call(m, "BounceChequeDepositTx", arg0, arg1)
+	ret0, _ := ret[0].(db.BounceChequeDepositTxResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListAccounts indicates an expected call of ListAccounts.
-func (mr *MockStoreMockRecorder) ListAccounts(arg0, arg1 interface{}) *gomock.Call {
+// BounceChequeDepositTx indicates an expected call of BounceChequeDepositTx.
+func (mr *MockStoreMockRecorder) BounceChequeDepositTx(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccounts", reflect.TypeOf((*MockStore)(nil).ListAccounts), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BounceChequeDepositTx", reflect.TypeOf((*MockStore)(nil).BounceChequeDepositTx), arg0, arg1)
 }
 
-// ListEntries mocks base method.
-func (m *MockStore) ListEntries(arg0 context.Context, arg1 db.ListEntriesParams) ([]db.Entry, error) {
+// CancelJob mocks base method.
+func (m *MockStore) CancelJob(arg0 context.Context, arg1 uuid.UUID) (db.Job, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListEntries", arg0, arg1)
-	ret0, _ := ret[0].([]db.Entry)
+	ret := m.ctrl.Call(m, "CancelJob", arg0, arg1)
+	ret0, _ := ret[0].(db.Job)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListEntries indicates an expected call of ListEntries.
-func (mr *MockStoreMockRecorder) ListEntries(arg0, arg1 interface{}) *gomock.Call {
+// CancelJob indicates an expected call of CancelJob.
+func (mr *MockStoreMockRecorder) CancelJob(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEntries", reflect.TypeOf((*MockStore)(nil).ListEntries), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelJob", reflect.TypeOf((*MockStore)(nil).CancelJob), arg0, arg1)
 }
 
-// ListTransfers mocks base method.
-func (m *MockStore) ListTransfers(arg0 context.Context, arg1 db.ListTransfersParams) ([]db.Transfer, error) {
+// CancelPaymentLink mocks base method.
+func (m *MockStore) CancelPaymentLink(arg0 context.Context, arg1 int64) (db.PaymentLink, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListTransfers", arg0, arg1)
-	ret0, _ := ret[0].([]db.Transfer)
+	ret := m.ctrl.Call(m, "CancelPaymentLink", arg0, arg1)
+	ret0, _ := ret[0].(db.PaymentLink)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListTransfers indicates an expected call of ListTransfers.
-func (mr *MockStoreMockRecorder) ListTransfers(arg0, arg1 interface{}) *gomock.Call {
+// CancelPaymentLink indicates an expected call of CancelPaymentLink.
+func (mr *MockStoreMockRecorder) CancelPaymentLink(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransfers", reflect.TypeOf((*MockStore)(nil).ListTransfers), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelPaymentLink", reflect.TypeOf((*MockStore)(nil).CancelPaymentLink), arg0, arg1)
 }
 
-// TransferTx mocks base method.
-func (m *MockStore) TransferTx(arg0 context.Context, arg1 db.TransferTxParams) (db.TransferTxResult, error) {
+// CancelStandingOrder mocks base method.
+func (m *MockStore) CancelStandingOrder(arg0 context.Context, arg1 int64) (db.StandingOrder, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "TransferTx", arg0, arg1)
-	ret0, _ := ret[0].(db.TransferTxResult)
+	ret := m.ctrl.Call(m, "CancelStandingOrder", arg0, arg1)
+	ret0, _ := ret[0].(db.StandingOrder)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// TransferTx indicates an expected call of TransferTx.
-func (mr *MockStoreMockRecorder) TransferTx(arg0, arg1 interface{}) *gomock.Call {
+// CancelStandingOrder indicates an expected call of CancelStandingOrder.
+func (mr *MockStoreMockRecorder) CancelStandingOrder(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransferTx", reflect.TypeOf((*MockStore)(nil).TransferTx), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelStandingOrder", reflect.TypeOf((*MockStore)(nil).CancelStandingOrder), arg0, arg1)
 }
 
-// UpdateAccount mocks base method.
-func (m *MockStore) UpdateAccount(arg0 context.Context, arg1 db.UpdateAccountParams) (db.Account, error) {
+// CancelUserDeletion mocks base method.
+func (m *MockStore) CancelUserDeletion(arg0 context.Context, arg1 string) (db.UserDeletion, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateAccount", arg0, arg1)
-	ret0, _ := ret[0].(db.Account)
+	ret := m.ctrl.Call(m, "CancelUserDeletion", arg0, arg1)
+	ret0, _ := ret[0].(db.UserDeletion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelUserDeletion indicates an expected call of CancelUserDeletion.
+func (mr *MockStoreMockRecorder) CancelUserDeletion(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelUserDeletion", reflect.TypeOf((*MockStore)(nil).CancelUserDeletion), arg0, arg1)
+}
+
+// CancelUserPaymentLinks mocks base method.
+func (m *MockStore) CancelUserPaymentLinks(arg0 context.Context, arg1 string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelUserPaymentLinks", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelUserPaymentLinks indicates an expected call of CancelUserPaymentLinks.
+func (mr *MockStoreMockRecorder) CancelUserPaymentLinks(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelUserPaymentLinks", reflect.TypeOf((*MockStore)(nil).CancelUserPaymentLinks), arg0, arg1)
+}
+
+// CancelUserStandingOrders mocks base method.
+func (m *MockStore) CancelUserStandingOrders(arg0 context.Context, arg1 string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelUserStandingOrders", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelUserStandingOrders indicates an expected call of CancelUserStandingOrders.
+func (mr *MockStoreMockRecorder) CancelUserStandingOrders(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelUserStandingOrders", reflect.TypeOf((*MockStore)(nil).CancelUserStandingOrders), arg0, arg1)
+}
+
+// CapitalizeInterestTx mocks base method.
+func (m *MockStore) CapitalizeInterestTx(arg0 context.Context, arg1 db.CapitalizeInterestTxParams) (db.BatchTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CapitalizeInterestTx", arg0, arg1)
+	ret0, _ := ret[0].(db.BatchTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CapitalizeInterestTx indicates an expected call of CapitalizeInterestTx.
+func (mr *MockStoreMockRecorder) CapitalizeInterestTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CapitalizeInterestTx", reflect.TypeOf((*MockStore)(nil).CapitalizeInterestTx), arg0, arg1)
+}
+
+// CaptureCardHoldTx mocks base method.
+func (m *MockStore) CaptureCardHoldTx(arg0 context.Context, arg1 db.CaptureCardHoldTxParams) (db.CaptureCardHoldTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CaptureCardHoldTx", arg0, arg1)
+	ret0, _ := ret[0].(db.CaptureCardHoldTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CaptureCardHoldTx indicates an expected call of CaptureCardHoldTx.
+func (mr *MockStoreMockRecorder) CaptureCardHoldTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CaptureCardHoldTx", reflect.TypeOf((*MockStore)(nil).CaptureCardHoldTx), arg0, arg1)
+}
+
+// CaptureHold mocks base method.
+func (m *MockStore) CaptureHold(arg0 context.Context, arg1 db.CaptureHoldParams) (db.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CaptureHold", arg0, arg1)
+	ret0, _ := ret[0].(db.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CaptureHold indicates an expected call of CaptureHold.
+func (mr *MockStoreMockRecorder) CaptureHold(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CaptureHold", reflect.TypeOf((*MockStore)(nil).CaptureHold), arg0, arg1)
+}
+
+// ChargeMonthlyFeeTx mocks base method.
+func (m *MockStore) ChargeMonthlyFeeTx(arg0 context.Context, arg1 db.ChargeMonthlyFeeTxParams) (db.BatchTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChargeMonthlyFeeTx", arg0, arg1)
+	ret0, _ := ret[0].(db.BatchTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChargeMonthlyFeeTx indicates an expected call of ChargeMonthlyFeeTx.
+func (mr *MockStoreMockRecorder) ChargeMonthlyFeeTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChargeMonthlyFeeTx", reflect.TypeOf((*MockStore)(nil).ChargeMonthlyFeeTx), arg0, arg1)
+}
+
+// ClaimNotificationDeliveries mocks base method.
+func (m *MockStore) ClaimNotificationDeliveries(arg0 context.Context, arg1 db.ClaimNotificationDeliveriesParams) ([]db.NotificationDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimNotificationDeliveries", arg0, arg1)
+	ret0, _ := ret[0].([]db.NotificationDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimNotificationDeliveries indicates an expected call of ClaimNotificationDeliveries.
+func (mr *MockStoreMockRecorder) ClaimNotificationDeliveries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimNotificationDeliveries", reflect.TypeOf((*MockStore)(nil).ClaimNotificationDeliveries), arg0, arg1)
+}
+
+// ClearChequeDeposit mocks base method.
+func (m *MockStore) ClearChequeDeposit(arg0 context.Context, arg1 int64) (db.ChequeDeposit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClearChequeDeposit", arg0, arg1)
+	ret0, _ := ret[0].(db.ChequeDeposit)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// UpdateAccount indicates an expected call of UpdateAccount.
-func (mr *MockStoreMockRecorder) UpdateAccount(arg0, arg1 interface{}) *gomock.Call {
+// ClearChequeDeposit indicates an expected call of ClearChequeDeposit.
+func (mr *MockStoreMockRecorder) ClearChequeDeposit(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearChequeDeposit", reflect.TypeOf((*MockStore)(nil).ClearChequeDeposit), arg0, arg1)
+}
+
+// ClearChequeDepositTx mocks base method.
+func (m *MockStore) ClearChequeDepositTx(arg0 context.Context, arg1 int64) (db.ChequeDeposit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClearChequeDepositTx", arg0, arg1)
+	ret0, _ := ret[0].(db.ChequeDeposit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClearChequeDepositTx indicates an expected call of ClearChequeDepositTx.
+func (mr *MockStoreMockRecorder) ClearChequeDepositTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearChequeDepositTx", reflect.TypeOf((*MockStore)(nil).ClearChequeDepositTx), arg0, arg1)
+}
+
+// CloseAccountVersion mocks base method.
+func (m *MockStore) CloseAccountVersion(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseAccountVersion", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CloseAccountVersion indicates an expected call of CloseAccountVersion.
+func (mr *MockStoreMockRecorder) CloseAccountVersion(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseAccountVersion", reflect.TypeOf((*MockStore)(nil).CloseAccountVersion), arg0, arg1)
+}
+
+// CloseOwnerAccounts mocks base method.
+func (m *MockStore) CloseOwnerAccounts(arg0 context.Context, arg1 string) ([]db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseOwnerAccounts", arg0, arg1)
+	ret0, _ := ret[0].([]db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CloseOwnerAccounts indicates an expected call of CloseOwnerAccounts.
+func (mr *MockStoreMockRecorder) CloseOwnerAccounts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseOwnerAccounts", reflect.TypeOf((*MockStore)(nil).CloseOwnerAccounts), arg0, arg1)
+}
+
+// CompleteBatchRun mocks base method.
+func (m *MockStore) CompleteBatchRun(arg0 context.Context, arg1 db.CompleteBatchRunParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteBatchRun", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteBatchRun indicates an expected call of CompleteBatchRun.
+func (mr *MockStoreMockRecorder) CompleteBatchRun(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteBatchRun", reflect.TypeOf((*MockStore)(nil).CompleteBatchRun), arg0, arg1)
+}
+
+// CompleteJob mocks base method.
+func (m *MockStore) CompleteJob(arg0 context.Context, arg1 db.CompleteJobParams) (db.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteJob", arg0, arg1)
+	ret0, _ := ret[0].(db.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompleteJob indicates an expected call of CompleteJob.
+func (mr *MockStoreMockRecorder) CompleteJob(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteJob", reflect.TypeOf((*MockStore)(nil).CompleteJob), arg0, arg1)
+}
+
+// CompleteUserDeletion mocks base method.
+func (m *MockStore) CompleteUserDeletion(arg0 context.Context, arg1 string) (db.UserDeletion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteUserDeletion", arg0, arg1)
+	ret0, _ := ret[0].(db.UserDeletion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompleteUserDeletion indicates an expected call of CompleteUserDeletion.
+func (mr *MockStoreMockRecorder) CompleteUserDeletion(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteUserDeletion", reflect.TypeOf((*MockStore)(nil).CompleteUserDeletion), arg0, arg1)
+}
+
+// ConvertAccountTx mocks base method.
+func (m *MockStore) ConvertAccountTx(arg0 context.Context, arg1 db.ConvertAccountTxParams) (db.ConvertAccountTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConvertAccountTx", arg0, arg1)
+	ret0, _ := ret[0].(db.ConvertAccountTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConvertAccountTx indicates an expected call of ConvertAccountTx.
+func (mr *MockStoreMockRecorder) ConvertAccountTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConvertAccountTx", reflect.TypeOf((*MockStore)(nil).ConvertAccountTx), arg0, arg1)
+}
+
+// CountActiveSigningKeys mocks base method.
+func (m *MockStore) CountActiveSigningKeys(arg0 context.Context, arg1 string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountActiveSigningKeys", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountActiveSigningKeys indicates an expected call of CountActiveSigningKeys.
+func (mr *MockStoreMockRecorder) CountActiveSigningKeys(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountActiveSigningKeys", reflect.TypeOf((*MockStore)(nil).CountActiveSigningKeys), arg0, arg1)
+}
+
+// CountClientLoginFailures mocks base method.
+func (m *MockStore) CountClientLoginFailures(arg0 context.Context, arg1 db.CountClientLoginFailuresParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountClientLoginFailures", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountClientLoginFailures indicates an expected call of CountClientLoginFailures.
+func (mr *MockStoreMockRecorder) CountClientLoginFailures(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountClientLoginFailures", reflect.TypeOf((*MockStore)(nil).CountClientLoginFailures), arg0, arg1)
+}
+
+// CountEntries mocks base method.
+func (m *MockStore) CountEntries(arg0 context.Context, arg1 int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountEntries", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountEntries indicates an expected call of CountEntries.
+func (mr *MockStoreMockRecorder) CountEntries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountEntries", reflect.TypeOf((*MockStore)(nil).CountEntries), arg0, arg1)
+}
+
+// CountEntriesInRange mocks base method.
+func (m *MockStore) CountEntriesInRange(arg0 context.Context, arg1 db.CountEntriesInRangeParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountEntriesInRange", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountEntriesInRange indicates an expected call of CountEntriesInRange.
+func (mr *MockStoreMockRecorder) CountEntriesInRange(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountEntriesInRange", reflect.TypeOf((*MockStore)(nil).CountEntriesInRange), arg0, arg1)
+}
+
+// CountLoginFailures mocks base method.
+func (m *MockStore) CountLoginFailures(arg0 context.Context, arg1 db.CountLoginFailuresParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountLoginFailures", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountLoginFailures indicates an expected call of CountLoginFailures.
+func (mr *MockStoreMockRecorder) CountLoginFailures(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountLoginFailures", reflect.TypeOf((*MockStore)(nil).CountLoginFailures), arg0, arg1)
+}
+
+// CountOwnerPendingItems mocks base method.
+func (m *MockStore) CountOwnerPendingItems(arg0 context.Context, arg1 string) (db.CountOwnerPendingItemsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountOwnerPendingItems", arg0, arg1)
+	ret0, _ := ret[0].(db.CountOwnerPendingItemsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountOwnerPendingItems indicates an expected call of CountOwnerPendingItems.
+func (mr *MockStoreMockRecorder) CountOwnerPendingItems(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountOwnerPendingItems", reflect.TypeOf((*MockStore)(nil).CountOwnerPendingItems), arg0, arg1)
+}
+
+// CountUserSessions mocks base method.
+func (m *MockStore) CountUserSessions(arg0 context.Context, arg1 db.CountUserSessionsParams) (db.CountUserSessionsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUserSessions", arg0, arg1)
+	ret0, _ := ret[0].(db.CountUserSessionsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUserSessions indicates an expected call of CountUserSessions.
+func (mr *MockStoreMockRecorder) CountUserSessions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUserSessions", reflect.TypeOf((*MockStore)(nil).CountUserSessions), arg0, arg1)
+}
+
+// CreateAccount mocks base method.
+func (m *MockStore) CreateAccount(arg0 context.Context, arg1 db.CreateAccountParams) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAccount", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAccount indicates an expected call of CreateAccount.
+func (mr *MockStoreMockRecorder) CreateAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccount", reflect.TypeOf((*MockStore)(nil).CreateAccount), arg0, arg1)
+}
+
+// CreateAccountMember mocks base method.
+func (m *MockStore) CreateAccountMember(arg0 context.Context, arg1 db.CreateAccountMemberParams) (db.AccountMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAccountMember", arg0, arg1)
+	ret0, _ := ret[0].(db.AccountMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAccountMember indicates an expected call of CreateAccountMember.
+func (mr *MockStoreMockRecorder) CreateAccountMember(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccountMember", reflect.TypeOf((*MockStore)(nil).CreateAccountMember), arg0, arg1)
+}
+
+// CreateAccountVersion mocks base method.
+func (m *MockStore) CreateAccountVersion(arg0 context.Context, arg1 db.CreateAccountVersionParams) (db.AccountHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAccountVersion", arg0, arg1)
+	ret0, _ := ret[0].(db.AccountHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAccountVersion indicates an expected call of CreateAccountVersion.
+func (mr *MockStoreMockRecorder) CreateAccountVersion(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccountVersion", reflect.TypeOf((*MockStore)(nil).CreateAccountVersion), arg0, arg1)
+}
+
+// CreateAuditEntry mocks base method.
+func (m *MockStore) CreateAuditEntry(arg0 context.Context, arg1 db.CreateAuditEntryParams) (db.AuditEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAuditEntry", arg0, arg1)
+	ret0, _ := ret[0].(db.AuditEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAuditEntry indicates an expected call of CreateAuditEntry.
+func (mr *MockStoreMockRecorder) CreateAuditEntry(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAuditEntry", reflect.TypeOf((*MockStore)(nil).CreateAuditEntry), arg0, arg1)
+}
+
+// CreateBalanceSnapshot mocks base method.
+func (m *MockStore) CreateBalanceSnapshot(arg0 context.Context, arg1 db.CreateBalanceSnapshotParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBalanceSnapshot", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateBalanceSnapshot indicates an expected call of CreateBalanceSnapshot.
+func (mr *MockStoreMockRecorder) CreateBalanceSnapshot(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBalanceSnapshot", reflect.TypeOf((*MockStore)(nil).CreateBalanceSnapshot), arg0, arg1)
+}
+
+// CreateBankParameter mocks base method.
+func (m *MockStore) CreateBankParameter(arg0 context.Context, arg1 db.CreateBankParameterParams) (db.BankParameter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBankParameter", arg0, arg1)
+	ret0, _ := ret[0].(db.BankParameter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBankParameter indicates an expected call of CreateBankParameter.
+func (mr *MockStoreMockRecorder) CreateBankParameter(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBankParameter", reflect.TypeOf((*MockStore)(nil).CreateBankParameter), arg0, arg1)
+}
+
+// CreateBeneficiary mocks base method.
+func (m *MockStore) CreateBeneficiary(arg0 context.Context, arg1 db.CreateBeneficiaryParams) (db.Beneficiary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBeneficiary", arg0, arg1)
+	ret0, _ := ret[0].(db.Beneficiary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBeneficiary indicates an expected call of CreateBeneficiary.
+func (mr *MockStoreMockRecorder) CreateBeneficiary(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBeneficiary", reflect.TypeOf((*MockStore)(nil).CreateBeneficiary), arg0, arg1)
+}
+
+// CreateCard mocks base method.
+func (m *MockStore) CreateCard(arg0 context.Context, arg1 db.CreateCardParams) (db.Card, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCard", arg0, arg1)
+	ret0, _ := ret[0].(db.Card)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCard indicates an expected call of CreateCard.
+func (mr *MockStoreMockRecorder) CreateCard(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCard", reflect.TypeOf((*MockStore)(nil).CreateCard), arg0, arg1)
+}
+
+// CreateChequeDeposit mocks base method.
+func (m *MockStore) CreateChequeDeposit(arg0 context.Context, arg1 db.CreateChequeDepositParams) (db.ChequeDeposit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateChequeDeposit", arg0, arg1)
+	ret0, _ := ret[0].(db.ChequeDeposit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateChequeDeposit indicates an expected call of CreateChequeDeposit.
+func (mr *MockStoreMockRecorder) CreateChequeDeposit(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateChequeDeposit", reflect.TypeOf((*MockStore)(nil).CreateChequeDeposit), arg0, arg1)
+}
+
+// CreateEntry mocks base method.
+func (m *MockStore) CreateEntry(arg0 context.Context, arg1 db.CreateEntryParams) (db.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateEntry", arg0, arg1)
+	ret0, _ := ret[0].(db.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateEntry indicates an expected call of CreateEntry.
+func (mr *MockStoreMockRecorder) CreateEntry(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEntry", reflect.TypeOf((*MockStore)(nil).CreateEntry), arg0, arg1)
+}
+
+// CreateEvent mocks base method.
+func (m *MockStore) CreateEvent(arg0 context.Context, arg1 db.CreateEventParams) (db.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateEvent", arg0, arg1)
+	ret0, _ := ret[0].(db.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateEvent indicates an expected call of CreateEvent.
+func (mr *MockStoreMockRecorder) CreateEvent(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEvent", reflect.TypeOf((*MockStore)(nil).CreateEvent), arg0, arg1)
+}
+
+// CreateExternalTransfer mocks base method.
+func (m *MockStore) CreateExternalTransfer(arg0 context.Context, arg1 db.CreateExternalTransferParams) (db.ExternalTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateExternalTransfer", arg0, arg1)
+	ret0, _ := ret[0].(db.ExternalTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateExternalTransfer indicates an expected call of CreateExternalTransfer.
+func (mr *MockStoreMockRecorder) CreateExternalTransfer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateExternalTransfer", reflect.TypeOf((*MockStore)(nil).CreateExternalTransfer), arg0, arg1)
+}
+
+// CreateHistoricalEntry mocks base method.
+func (m *MockStore) CreateHistoricalEntry(arg0 context.Context, arg1 db.CreateHistoricalEntryParams) (db.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateHistoricalEntry", arg0, arg1)
+	ret0, _ := ret[0].(db.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateHistoricalEntry indicates an expected call of CreateHistoricalEntry.
+func (mr *MockStoreMockRecorder) CreateHistoricalEntry(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateHistoricalEntry", reflect.TypeOf((*MockStore)(nil).CreateHistoricalEntry), arg0, arg1)
+}
+
+// CreateHold mocks base method.
+func (m *MockStore) CreateHold(arg0 context.Context, arg1 db.CreateHoldParams) (db.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateHold", arg0, arg1)
+	ret0, _ := ret[0].(db.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateHold indicates an expected call of CreateHold.
+func (mr *MockStoreMockRecorder) CreateHold(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateHold", reflect.TypeOf((*MockStore)(nil).CreateHold), arg0, arg1)
+}
+
+// CreateJob mocks base method.
+func (m *MockStore) CreateJob(arg0 context.Context, arg1 db.CreateJobParams) (db.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateJob", arg0, arg1)
+	ret0, _ := ret[0].(db.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateJob indicates an expected call of CreateJob.
+func (mr *MockStoreMockRecorder) CreateJob(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateJob", reflect.TypeOf((*MockStore)(nil).CreateJob), arg0, arg1)
+}
+
+// CreateLoginFailure mocks base method.
+func (m *MockStore) CreateLoginFailure(arg0 context.Context, arg1 db.CreateLoginFailureParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateLoginFailure", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateLoginFailure indicates an expected call of CreateLoginFailure.
+func (mr *MockStoreMockRecorder) CreateLoginFailure(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLoginFailure", reflect.TypeOf((*MockStore)(nil).CreateLoginFailure), arg0, arg1)
+}
+
+// CreateMandate mocks base method.
+func (m *MockStore) CreateMandate(arg0 context.Context, arg1 db.CreateMandateParams) (db.Mandate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateMandate", arg0, arg1)
+	ret0, _ := ret[0].(db.Mandate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateMandate indicates an expected call of CreateMandate.
+func (mr *MockStoreMockRecorder) CreateMandate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMandate", reflect.TypeOf((*MockStore)(nil).CreateMandate), arg0, arg1)
+}
+
+// CreateNotification mocks base method.
+func (m *MockStore) CreateNotification(arg0 context.Context, arg1 db.CreateNotificationParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateNotification", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateNotification indicates an expected call of CreateNotification.
+func (mr *MockStoreMockRecorder) CreateNotification(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateNotification", reflect.TypeOf((*MockStore)(nil).CreateNotification), arg0, arg1)
+}
+
+// CreateNotificationDelivery mocks base method.
+func (m *MockStore) CreateNotificationDelivery(arg0 context.Context, arg1 db.CreateNotificationDeliveryParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateNotificationDelivery", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateNotificationDelivery indicates an expected call of CreateNotificationDelivery.
+func (mr *MockStoreMockRecorder) CreateNotificationDelivery(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateNotificationDelivery", reflect.TypeOf((*MockStore)(nil).CreateNotificationDelivery), arg0, arg1)
+}
+
+// CreateOrganization mocks base method.
+func (m *MockStore) CreateOrganization(arg0 context.Context, arg1 db.CreateOrganizationParams) (db.Organization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOrganization", arg0, arg1)
+	ret0, _ := ret[0].(db.Organization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOrganization indicates an expected call of CreateOrganization.
+func (mr *MockStoreMockRecorder) CreateOrganization(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrganization", reflect.TypeOf((*MockStore)(nil).CreateOrganization), arg0, arg1)
+}
+
+// CreatePaymentLink mocks base method.
+func (m *MockStore) CreatePaymentLink(arg0 context.Context, arg1 db.CreatePaymentLinkParams) (db.PaymentLink, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePaymentLink", arg0, arg1)
+	ret0, _ := ret[0].(db.PaymentLink)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePaymentLink indicates an expected call of CreatePaymentLink.
+func (mr *MockStoreMockRecorder) CreatePaymentLink(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePaymentLink", reflect.TypeOf((*MockStore)(nil).CreatePaymentLink), arg0, arg1)
+}
+
+// CreatePaymentRequest mocks base method.
+func (m *MockStore) CreatePaymentRequest(arg0 context.Context, arg1 db.CreatePaymentRequestParams) (db.PaymentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePaymentRequest", arg0, arg1)
+	ret0, _ := ret[0].(db.PaymentRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePaymentRequest indicates an expected call of CreatePaymentRequest.
+func (mr *MockStoreMockRecorder) CreatePaymentRequest(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePaymentRequest", reflect.TypeOf((*MockStore)(nil).CreatePaymentRequest), arg0, arg1)
+}
+
+// CreatePendingTransfer mocks base method.
+func (m *MockStore) CreatePendingTransfer(arg0 context.Context, arg1 db.CreatePendingTransferParams) (db.PendingTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePendingTransfer", arg0, arg1)
+	ret0, _ := ret[0].(db.PendingTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePendingTransfer indicates an expected call of CreatePendingTransfer.
+func (mr *MockStoreMockRecorder) CreatePendingTransfer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePendingTransfer", reflect.TypeOf((*MockStore)(nil).CreatePendingTransfer), arg0, arg1)
+}
+
+// CreatePendingTransferTx mocks base method.
+func (m *MockStore) CreatePendingTransferTx(arg0 context.Context, arg1 db.CreatePendingTransferTxParams) (db.PendingTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePendingTransferTx", arg0, arg1)
+	ret0, _ := ret[0].(db.PendingTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePendingTransferTx indicates an expected call of CreatePendingTransferTx.
+func (mr *MockStoreMockRecorder) CreatePendingTransferTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePendingTransferTx", reflect.TypeOf((*MockStore)(nil).CreatePendingTransferTx), arg0, arg1)
+}
+
+// CreateProcessedTask mocks base method.
+func (m *MockStore) CreateProcessedTask(arg0 context.Context, arg1 db.CreateProcessedTaskParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateProcessedTask", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateProcessedTask indicates an expected call of CreateProcessedTask.
+func (mr *MockStoreMockRecorder) CreateProcessedTask(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateProcessedTask", reflect.TypeOf((*MockStore)(nil).CreateProcessedTask), arg0, arg1)
+}
+
+// CreateQueuedTransfer mocks base method.
+func (m *MockStore) CreateQueuedTransfer(arg0 context.Context, arg1 db.CreateQueuedTransferParams) (db.QueuedTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateQueuedTransfer", arg0, arg1)
+	ret0, _ := ret[0].(db.QueuedTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateQueuedTransfer indicates an expected call of CreateQueuedTransfer.
+func (mr *MockStoreMockRecorder) CreateQueuedTransfer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateQueuedTransfer", reflect.TypeOf((*MockStore)(nil).CreateQueuedTransfer), arg0, arg1)
+}
+
+// CreateSession mocks base method.
+func (m *MockStore) CreateSession(arg0 context.Context, arg1 db.CreateSessionParams) (db.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSession", arg0, arg1)
+	ret0, _ := ret[0].(db.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSession indicates an expected call of CreateSession.
+func (mr *MockStoreMockRecorder) CreateSession(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSession", reflect.TypeOf((*MockStore)(nil).CreateSession), arg0, arg1)
+}
+
+// CreateSigningKey mocks base method.
+func (m *MockStore) CreateSigningKey(arg0 context.Context, arg1 db.CreateSigningKeyParams) (db.SigningKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSigningKey", arg0, arg1)
+	ret0, _ := ret[0].(db.SigningKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSigningKey indicates an expected call of CreateSigningKey.
+func (mr *MockStoreMockRecorder) CreateSigningKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSigningKey", reflect.TypeOf((*MockStore)(nil).CreateSigningKey), arg0, arg1)
+}
+
+// CreateStandingOrder mocks base method.
+func (m *MockStore) CreateStandingOrder(arg0 context.Context, arg1 db.CreateStandingOrderParams) (db.StandingOrder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateStandingOrder", arg0, arg1)
+	ret0, _ := ret[0].(db.StandingOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateStandingOrder indicates an expected call of CreateStandingOrder.
+func (mr *MockStoreMockRecorder) CreateStandingOrder(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateStandingOrder", reflect.TypeOf((*MockStore)(nil).CreateStandingOrder), arg0, arg1)
+}
+
+// CreateTransfer mocks base method.
+func (m *MockStore) CreateTransfer(arg0 context.Context, arg1 db.CreateTransferParams) (db.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTransfer", arg0, arg1)
+	ret0, _ := ret[0].(db.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTransfer indicates an expected call of CreateTransfer.
+func (mr *MockStoreMockRecorder) CreateTransfer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransfer", reflect.TypeOf((*MockStore)(nil).CreateTransfer), arg0, arg1)
+}
+
+// CreateTransferReview mocks base method.
+func (m *MockStore) CreateTransferReview(arg0 context.Context, arg1 db.CreateTransferReviewParams) (db.TransferReview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTransferReview", arg0, arg1)
+	ret0, _ := ret[0].(db.TransferReview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTransferReview indicates an expected call of CreateTransferReview.
+func (mr *MockStoreMockRecorder) CreateTransferReview(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransferReview", reflect.TypeOf((*MockStore)(nil).CreateTransferReview), arg0, arg1)
+}
+
+// CreateUser mocks base method.
+func (m *MockStore) CreateUser(arg0 context.Context, arg1 db.CreateUserParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUser", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUser indicates an expected call of CreateUser.
+func (mr *MockStoreMockRecorder) CreateUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockStore)(nil).CreateUser), arg0, arg1)
+}
+
+// CreateUserIdentity mocks base method.
+func (m *MockStore) CreateUserIdentity(arg0 context.Context, arg1 db.CreateUserIdentityParams) (db.UserIdentity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUserIdentity", arg0, arg1)
+	ret0, _ := ret[0].(db.UserIdentity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUserIdentity indicates an expected call of CreateUserIdentity.
+func (mr *MockStoreMockRecorder) CreateUserIdentity(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUserIdentity", reflect.TypeOf((*MockStore)(nil).CreateUserIdentity), arg0, arg1)
+}
+
+// CreateUserWithIdentityTx mocks base method.
+func (m *MockStore) CreateUserWithIdentityTx(arg0 context.Context, arg1 db.CreateUserWithIdentityTxParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUserWithIdentityTx", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUserWithIdentityTx indicates an expected call of CreateUserWithIdentityTx.
+func (mr *MockStoreMockRecorder) CreateUserWithIdentityTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUserWithIdentityTx", reflect.TypeOf((*MockStore)(nil).CreateUserWithIdentityTx), arg0, arg1)
+}
+
+// CreateUserWithRoleTx mocks base method.
+func (m *MockStore) CreateUserWithRoleTx(arg0 context.Context, arg1 db.CreateUserParams, arg2 string) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUserWithRoleTx", arg0, arg1, arg2)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUserWithRoleTx indicates an expected call of CreateUserWithRoleTx.
+func (mr *MockStoreMockRecorder) CreateUserWithRoleTx(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUserWithRoleTx", reflect.TypeOf((*MockStore)(nil).CreateUserWithRoleTx), arg0, arg1, arg2)
+}
+
+// DecideTransferReview mocks base method.
+func (m *MockStore) DecideTransferReview(arg0 context.Context, arg1 db.DecideTransferReviewParams) (db.TransferReview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DecideTransferReview", arg0, arg1)
+	ret0, _ := ret[0].(db.TransferReview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DecideTransferReview indicates an expected call of DecideTransferReview.
+func (mr *MockStoreMockRecorder) DecideTransferReview(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecideTransferReview", reflect.TypeOf((*MockStore)(nil).DecideTransferReview), arg0, arg1)
+}
+
+// DecideTransferReviewTx mocks base method.
+func (m *MockStore) DecideTransferReviewTx(arg0 context.Context, arg1 db.DecideTransferReviewTxParams) (db.DecideTransferReviewTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DecideTransferReviewTx", arg0, arg1)
+	ret0, _ := ret[0].(db.DecideTransferReviewTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DecideTransferReviewTx indicates an expected call of DecideTransferReviewTx.
+func (mr *MockStoreMockRecorder) DecideTransferReviewTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecideTransferReviewTx", reflect.TypeOf((*MockStore)(nil).DecideTransferReviewTx), arg0, arg1)
+}
+
+// DeclinePaymentRequest mocks base method.
+func (m *MockStore) DeclinePaymentRequest(arg0 context.Context, arg1 int64) (db.PaymentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeclinePaymentRequest", arg0, arg1)
+	ret0, _ := ret[0].(db.PaymentRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeclinePaymentRequest indicates an expected call of DeclinePaymentRequest.
+func (mr *MockStoreMockRecorder) DeclinePaymentRequest(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeclinePaymentRequest", reflect.TypeOf((*MockStore)(nil).DeclinePaymentRequest), arg0, arg1)
+}
+
+// DeleteAccount mocks base method.
+func (m *MockStore) DeleteAccount(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAccount", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAccount indicates an expected call of DeleteAccount.
+func (mr *MockStoreMockRecorder) DeleteAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccount", reflect.TypeOf((*MockStore)(nil).DeleteAccount), arg0, arg1)
+}
+
+// DeleteAccountAlert mocks base method.
+func (m *MockStore) DeleteAccountAlert(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAccountAlert", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAccountAlert indicates an expected call of DeleteAccountAlert.
+func (mr *MockStoreMockRecorder) DeleteAccountAlert(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccountAlert", reflect.TypeOf((*MockStore)(nil).DeleteAccountAlert), arg0, arg1)
+}
+
+// DeleteAccountCounterpartyActivity mocks base method.
+func (m *MockStore) DeleteAccountCounterpartyActivity(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAccountCounterpartyActivity", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAccountCounterpartyActivity indicates an expected call of DeleteAccountCounterpartyActivity.
+func (mr *MockStoreMockRecorder) DeleteAccountCounterpartyActivity(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccountCounterpartyActivity", reflect.TypeOf((*MockStore)(nil).DeleteAccountCounterpartyActivity), arg0, arg1)
+}
+
+// DeleteAccountDailyActivity mocks base method.
+func (m *MockStore) DeleteAccountDailyActivity(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAccountDailyActivity", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAccountDailyActivity indicates an expected call of DeleteAccountDailyActivity.
+func (mr *MockStoreMockRecorder) DeleteAccountDailyActivity(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccountDailyActivity", reflect.TypeOf((*MockStore)(nil).DeleteAccountDailyActivity), arg0, arg1)
+}
+
+// DeleteAccountMember mocks base method.
+func (m *MockStore) DeleteAccountMember(arg0 context.Context, arg1 db.DeleteAccountMemberParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAccountMember", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAccountMember indicates an expected call of DeleteAccountMember.
+func (mr *MockStoreMockRecorder) DeleteAccountMember(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccountMember", reflect.TypeOf((*MockStore)(nil).DeleteAccountMember), arg0, arg1)
+}
+
+// DeleteAccountOverview mocks base method.
+func (m *MockStore) DeleteAccountOverview(arg0 context.Context, arg1 int64) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAccountOverview", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteAccountOverview indicates an expected call of DeleteAccountOverview.
+func (mr *MockStoreMockRecorder) DeleteAccountOverview(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccountOverview", reflect.TypeOf((*MockStore)(nil).DeleteAccountOverview), arg0, arg1)
+}
+
+// DeleteBeneficiary mocks base method.
+func (m *MockStore) DeleteBeneficiary(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBeneficiary", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteBeneficiary indicates an expected call of DeleteBeneficiary.
+func (mr *MockStoreMockRecorder) DeleteBeneficiary(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBeneficiary", reflect.TypeOf((*MockStore)(nil).DeleteBeneficiary), arg0, arg1)
+}
+
+// DeleteBudget mocks base method.
+func (m *MockStore) DeleteBudget(arg0 context.Context, arg1 db.DeleteBudgetParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBudget", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteBudget indicates an expected call of DeleteBudget.
+func (mr *MockStoreMockRecorder) DeleteBudget(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBudget", reflect.TypeOf((*MockStore)(nil).DeleteBudget), arg0, arg1)
+}
+
+// DeleteClientLoginFailures mocks base method.
+func (m *MockStore) DeleteClientLoginFailures(arg0 context.Context, arg1 db.DeleteClientLoginFailuresParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteClientLoginFailures", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteClientLoginFailures indicates an expected call of DeleteClientLoginFailures.
+func (mr *MockStoreMockRecorder) DeleteClientLoginFailures(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteClientLoginFailures", reflect.TypeOf((*MockStore)(nil).DeleteClientLoginFailures), arg0, arg1)
+}
+
+// DeleteLoginFailures mocks base method.
+func (m *MockStore) DeleteLoginFailures(arg0 context.Context, arg1 string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteLoginFailures", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteLoginFailures indicates an expected call of DeleteLoginFailures.
+func (mr *MockStoreMockRecorder) DeleteLoginFailures(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLoginFailures", reflect.TypeOf((*MockStore)(nil).DeleteLoginFailures), arg0, arg1)
+}
+
+// DeleteLoginLockouts mocks base method.
+func (m *MockStore) DeleteLoginLockouts(arg0 context.Context, arg1 string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteLoginLockouts", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteLoginLockouts indicates an expected call of DeleteLoginLockouts.
+func (mr *MockStoreMockRecorder) DeleteLoginLockouts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLoginLockouts", reflect.TypeOf((*MockStore)(nil).DeleteLoginLockouts), arg0, arg1)
+}
+
+// DeleteNotificationPreferences mocks base method.
+func (m *MockStore) DeleteNotificationPreferences(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteNotificationPreferences", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteNotificationPreferences indicates an expected call of DeleteNotificationPreferences.
+func (mr *MockStoreMockRecorder) DeleteNotificationPreferences(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteNotificationPreferences", reflect.TypeOf((*MockStore)(nil).DeleteNotificationPreferences), arg0, arg1)
+}
+
+// DeleteOwnerBeneficiaries mocks base method.
+func (m *MockStore) DeleteOwnerBeneficiaries(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOwnerBeneficiaries", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOwnerBeneficiaries indicates an expected call of DeleteOwnerBeneficiaries.
+func (mr *MockStoreMockRecorder) DeleteOwnerBeneficiaries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOwnerBeneficiaries", reflect.TypeOf((*MockStore)(nil).DeleteOwnerBeneficiaries), arg0, arg1)
+}
+
+// DeleteStaleAccountDailyVolume mocks base method.
+func (m *MockStore) DeleteStaleAccountDailyVolume(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteStaleAccountDailyVolume", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteStaleAccountDailyVolume indicates an expected call of DeleteStaleAccountDailyVolume.
+func (mr *MockStoreMockRecorder) DeleteStaleAccountDailyVolume(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteStaleAccountDailyVolume", reflect.TypeOf((*MockStore)(nil).DeleteStaleAccountDailyVolume), arg0)
+}
+
+// DeleteUserAPIPlan mocks base method.
+func (m *MockStore) DeleteUserAPIPlan(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserAPIPlan", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserAPIPlan indicates an expected call of DeleteUserAPIPlan.
+func (mr *MockStoreMockRecorder) DeleteUserAPIPlan(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserAPIPlan", reflect.TypeOf((*MockStore)(nil).DeleteUserAPIPlan), arg0, arg1)
+}
+
+// DeleteUserAccountMembers mocks base method.
+func (m *MockStore) DeleteUserAccountMembers(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserAccountMembers", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserAccountMembers indicates an expected call of DeleteUserAccountMembers.
+func (mr *MockStoreMockRecorder) DeleteUserAccountMembers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserAccountMembers", reflect.TypeOf((*MockStore)(nil).DeleteUserAccountMembers), arg0, arg1)
+}
+
+// DeleteUserDataTx mocks base method.
+func (m *MockStore) DeleteUserDataTx(arg0 context.Context, arg1 string) (db.DeleteUserDataTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserDataTx", arg0, arg1)
+	ret0, _ := ret[0].(db.DeleteUserDataTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteUserDataTx indicates an expected call of DeleteUserDataTx.
+func (mr *MockStoreMockRecorder) DeleteUserDataTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserDataTx", reflect.TypeOf((*MockStore)(nil).DeleteUserDataTx), arg0, arg1)
+}
+
+// DeleteUserIdentities mocks base method.
+func (m *MockStore) DeleteUserIdentities(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserIdentities", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserIdentities indicates an expected call of DeleteUserIdentities.
+func (mr *MockStoreMockRecorder) DeleteUserIdentities(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserIdentities", reflect.TypeOf((*MockStore)(nil).DeleteUserIdentities), arg0, arg1)
+}
+
+// DeleteUserSessions mocks base method.
+func (m *MockStore) DeleteUserSessions(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserSessions", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserSessions indicates an expected call of DeleteUserSessions.
+func (mr *MockStoreMockRecorder) DeleteUserSessions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserSessions", reflect.TypeOf((*MockStore)(nil).DeleteUserSessions), arg0, arg1)
+}
+
+// DeleteUserSigningKeys mocks base method.
+func (m *MockStore) DeleteUserSigningKeys(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserSigningKeys", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserSigningKeys indicates an expected call of DeleteUserSigningKeys.
+func (mr *MockStoreMockRecorder) DeleteUserSigningKeys(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserSigningKeys", reflect.TypeOf((*MockStore)(nil).DeleteUserSigningKeys), arg0, arg1)
+}
+
+// DepositChequeTx mocks base method.
+func (m *MockStore) DepositChequeTx(arg0 context.Context, arg1 db.DepositChequeTxParams) (db.DepositChequeTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DepositChequeTx", arg0, arg1)
+	ret0, _ := ret[0].(db.DepositChequeTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DepositChequeTx indicates an expected call of DepositChequeTx.
+func (mr *MockStoreMockRecorder) DepositChequeTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DepositChequeTx", reflect.TypeOf((*MockStore)(nil).DepositChequeTx), arg0, arg1)
+}
+
+// EscalateTransferReview mocks base method.
+func (m *MockStore) EscalateTransferReview(arg0 context.Context, arg1 db.EscalateTransferReviewParams) (db.TransferReview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EscalateTransferReview", arg0, arg1)
+	ret0, _ := ret[0].(db.TransferReview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EscalateTransferReview indicates an expected call of EscalateTransferReview.
+func (mr *MockStoreMockRecorder) EscalateTransferReview(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EscalateTransferReview", reflect.TypeOf((*MockStore)(nil).EscalateTransferReview), arg0, arg1)
+}
+
+// ExchangeTx mocks base method.
+func (m *MockStore) ExchangeTx(arg0 context.Context, arg1 db.ExchangeTxParams) (db.ExchangeTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExchangeTx", arg0, arg1)
+	ret0, _ := ret[0].(db.ExchangeTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExchangeTx indicates an expected call of ExchangeTx.
+func (mr *MockStoreMockRecorder) ExchangeTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExchangeTx", reflect.TypeOf((*MockStore)(nil).ExchangeTx), arg0, arg1)
+}
+
+// ExpireHolds mocks base method.
+func (m *MockStore) ExpireHolds(arg0 context.Context, arg1 time.Time) ([]db.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireHolds", arg0, arg1)
+	ret0, _ := ret[0].([]db.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireHolds indicates an expected call of ExpireHolds.
+func (mr *MockStoreMockRecorder) ExpireHolds(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireHolds", reflect.TypeOf((*MockStore)(nil).ExpireHolds), arg0, arg1)
+}
+
+// ExpireHoldsTx mocks base method.
+func (m *MockStore) ExpireHoldsTx(arg0 context.Context, arg1 time.Time) ([]db.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireHoldsTx", arg0, arg1)
+	ret0, _ := ret[0].([]db.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireHoldsTx indicates an expected call of ExpireHoldsTx.
+func (mr *MockStoreMockRecorder) ExpireHoldsTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireHoldsTx", reflect.TypeOf((*MockStore)(nil).ExpireHoldsTx), arg0, arg1)
+}
+
+// ExpirePaymentLinks mocks base method.
+func (m *MockStore) ExpirePaymentLinks(arg0 context.Context, arg1 time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpirePaymentLinks", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpirePaymentLinks indicates an expected call of ExpirePaymentLinks.
+func (mr *MockStoreMockRecorder) ExpirePaymentLinks(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpirePaymentLinks", reflect.TypeOf((*MockStore)(nil).ExpirePaymentLinks), arg0, arg1)
+}
+
+// ExpirePaymentRequests mocks base method.
+func (m *MockStore) ExpirePaymentRequests(arg0 context.Context, arg1 time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpirePaymentRequests", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpirePaymentRequests indicates an expected call of ExpirePaymentRequests.
+func (mr *MockStoreMockRecorder) ExpirePaymentRequests(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpirePaymentRequests", reflect.TypeOf((*MockStore)(nil).ExpirePaymentRequests), arg0, arg1)
+}
+
+// ExpirePendingTransfers mocks base method.
+func (m *MockStore) ExpirePendingTransfers(arg0 context.Context, arg1 time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpirePendingTransfers", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpirePendingTransfers indicates an expected call of ExpirePendingTransfers.
+func (mr *MockStoreMockRecorder) ExpirePendingTransfers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpirePendingTransfers", reflect.TypeOf((*MockStore)(nil).ExpirePendingTransfers), arg0, arg1)
+}
+
+// FailJob mocks base method.
+func (m *MockStore) FailJob(arg0 context.Context, arg1 db.FailJobParams) (db.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailJob", arg0, arg1)
+	ret0, _ := ret[0].(db.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FailJob indicates an expected call of FailJob.
+func (mr *MockStoreMockRecorder) FailJob(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailJob", reflect.TypeOf((*MockStore)(nil).FailJob), arg0, arg1)
+}
+
+// FailUserDeletion mocks base method.
+func (m *MockStore) FailUserDeletion(arg0 context.Context, arg1 db.FailUserDeletionParams) (db.UserDeletion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailUserDeletion", arg0, arg1)
+	ret0, _ := ret[0].(db.UserDeletion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FailUserDeletion indicates an expected call of FailUserDeletion.
+func (mr *MockStoreMockRecorder) FailUserDeletion(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailUserDeletion", reflect.TypeOf((*MockStore)(nil).FailUserDeletion), arg0, arg1)
+}
+
+// FreezeUserCards mocks base method.
+func (m *MockStore) FreezeUserCards(arg0 context.Context, arg1 string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FreezeUserCards", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FreezeUserCards indicates an expected call of FreezeUserCards.
+func (mr *MockStoreMockRecorder) FreezeUserCards(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FreezeUserCards", reflect.TypeOf((*MockStore)(nil).FreezeUserCards), arg0, arg1)
+}
+
+// GetAccount mocks base method.
+func (m *MockStore) GetAccount(arg0 context.Context, arg1 int64) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccount", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccount indicates an expected call of GetAccount.
+func (mr *MockStoreMockRecorder) GetAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccount", reflect.TypeOf((*MockStore)(nil).GetAccount), arg0, arg1)
+}
+
+// GetAccountAlert mocks base method.
+func (m *MockStore) GetAccountAlert(arg0 context.Context, arg1 int64) (db.AccountAlert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountAlert", arg0, arg1)
+	ret0, _ := ret[0].(db.AccountAlert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountAlert indicates an expected call of GetAccountAlert.
+func (mr *MockStoreMockRecorder) GetAccountAlert(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountAlert", reflect.TypeOf((*MockStore)(nil).GetAccountAlert), arg0, arg1)
+}
+
+// GetAccountByNumber mocks base method.
+func (m *MockStore) GetAccountByNumber(arg0 context.Context, arg1 string) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountByNumber", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountByNumber indicates an expected call of GetAccountByNumber.
+func (mr *MockStoreMockRecorder) GetAccountByNumber(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountByNumber", reflect.TypeOf((*MockStore)(nil).GetAccountByNumber), arg0, arg1)
+}
+
+// GetAccountForUpdate mocks base method.
+func (m *MockStore) GetAccountForUpdate(arg0 context.Context, arg1 int64) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountForUpdate indicates an expected call of GetAccountForUpdate.
+func (mr *MockStoreMockRecorder) GetAccountForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountForUpdate", reflect.TypeOf((*MockStore)(nil).GetAccountForUpdate), arg0, arg1)
+}
+
+// GetAccountMember mocks base method.
+func (m *MockStore) GetAccountMember(arg0 context.Context, arg1 db.GetAccountMemberParams) (db.AccountMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountMember", arg0, arg1)
+	ret0, _ := ret[0].(db.AccountMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountMember indicates an expected call of GetAccountMember.
+func (mr *MockStoreMockRecorder) GetAccountMember(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountMember", reflect.TypeOf((*MockStore)(nil).GetAccountMember), arg0, arg1)
+}
+
+// GetAccountsByIDs mocks base method.
+func (m *MockStore) GetAccountsByIDs(arg0 context.Context, arg1 db.GetAccountsByIDsParams) ([]db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountsByIDs", arg0, arg1)
+	ret0, _ := ret[0].([]db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountsByIDs indicates an expected call of GetAccountsByIDs.
+func (mr *MockStoreMockRecorder) GetAccountsByIDs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountsByIDs", reflect.TypeOf((*MockStore)(nil).GetAccountsByIDs), arg0, arg1)
+}
+
+// GetActiveBankParameter mocks base method.
+func (m *MockStore) GetActiveBankParameter(arg0 context.Context, arg1 db.GetActiveBankParameterParams) (db.BankParameter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveBankParameter", arg0, arg1)
+	ret0, _ := ret[0].(db.BankParameter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveBankParameter indicates an expected call of GetActiveBankParameter.
+func (mr *MockStoreMockRecorder) GetActiveBankParameter(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveBankParameter", reflect.TypeOf((*MockStore)(nil).GetActiveBankParameter), arg0, arg1)
+}
+
+// GetBalanceSnapshot mocks base method.
+func (m *MockStore) GetBalanceSnapshot(arg0 context.Context, arg1 db.GetBalanceSnapshotParams) (db.BalanceSnapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBalanceSnapshot", arg0, arg1)
+	ret0, _ := ret[0].(db.BalanceSnapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBalanceSnapshot indicates an expected call of GetBalanceSnapshot.
+func (mr *MockStoreMockRecorder) GetBalanceSnapshot(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBalanceSnapshot", reflect.TypeOf((*MockStore)(nil).GetBalanceSnapshot), arg0, arg1)
+}
+
+// GetBeneficiary mocks base method.
+func (m *MockStore) GetBeneficiary(arg0 context.Context, arg1 int64) (db.Beneficiary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBeneficiary", arg0, arg1)
+	ret0, _ := ret[0].(db.Beneficiary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBeneficiary indicates an expected call of GetBeneficiary.
+func (mr *MockStoreMockRecorder) GetBeneficiary(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBeneficiary", reflect.TypeOf((*MockStore)(nil).GetBeneficiary), arg0, arg1)
+}
+
+// GetBeneficiaryByAccount mocks base method.
+func (m *MockStore) GetBeneficiaryByAccount(arg0 context.Context, arg1 db.GetBeneficiaryByAccountParams) (db.Beneficiary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBeneficiaryByAccount", arg0, arg1)
+	ret0, _ := ret[0].(db.Beneficiary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBeneficiaryByAccount indicates an expected call of GetBeneficiaryByAccount.
+func (mr *MockStoreMockRecorder) GetBeneficiaryByAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBeneficiaryByAccount", reflect.TypeOf((*MockStore)(nil).GetBeneficiaryByAccount), arg0, arg1)
+}
+
+// GetBudget mocks base method.
+func (m *MockStore) GetBudget(arg0 context.Context, arg1 db.GetBudgetParams) (db.Budget, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBudget", arg0, arg1)
+	ret0, _ := ret[0].(db.Budget)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBudget indicates an expected call of GetBudget.
+func (mr *MockStoreMockRecorder) GetBudget(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBudget", reflect.TypeOf((*MockStore)(nil).GetBudget), arg0, arg1)
+}
+
+// GetCard mocks base method.
+func (m *MockStore) GetCard(arg0 context.Context, arg1 int64) (db.Card, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCard", arg0, arg1)
+	ret0, _ := ret[0].(db.Card)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCard indicates an expected call of GetCard.
+func (mr *MockStoreMockRecorder) GetCard(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCard", reflect.TypeOf((*MockStore)(nil).GetCard), arg0, arg1)
+}
+
+// GetCardForUpdate mocks base method.
+func (m *MockStore) GetCardForUpdate(arg0 context.Context, arg1 int64) (db.Card, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCardForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.Card)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCardForUpdate indicates an expected call of GetCardForUpdate.
+func (mr *MockStoreMockRecorder) GetCardForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCardForUpdate", reflect.TypeOf((*MockStore)(nil).GetCardForUpdate), arg0, arg1)
+}
+
+// GetChequeDeposit mocks base method.
+func (m *MockStore) GetChequeDeposit(arg0 context.Context, arg1 int64) (db.ChequeDeposit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChequeDeposit", arg0, arg1)
+	ret0, _ := ret[0].(db.ChequeDeposit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChequeDeposit indicates an expected call of GetChequeDeposit.
+func (mr *MockStoreMockRecorder) GetChequeDeposit(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChequeDeposit", reflect.TypeOf((*MockStore)(nil).GetChequeDeposit), arg0, arg1)
+}
+
+// GetChequeDepositForUpdate mocks base method.
+func (m *MockStore) GetChequeDepositForUpdate(arg0 context.Context, arg1 int64) (db.ChequeDeposit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChequeDepositForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.ChequeDeposit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChequeDepositForUpdate indicates an expected call of GetChequeDepositForUpdate.
+func (mr *MockStoreMockRecorder) GetChequeDepositForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChequeDepositForUpdate", reflect.TypeOf((*MockStore)(nil).GetChequeDepositForUpdate), arg0, arg1)
+}
+
+// GetEntry mocks base method.
+func (m *MockStore) GetEntry(arg0 context.Context, arg1 int64) (db.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEntry", arg0, arg1)
+	ret0, _ := ret[0].(db.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEntry indicates an expected call of GetEntry.
+func (mr *MockStoreMockRecorder) GetEntry(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEntry", reflect.TypeOf((*MockStore)(nil).GetEntry), arg0, arg1)
+}
+
+// GetExternalTransfer mocks base method.
+func (m *MockStore) GetExternalTransfer(arg0 context.Context, arg1 int64) (db.ExternalTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExternalTransfer", arg0, arg1)
+	ret0, _ := ret[0].(db.ExternalTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExternalTransfer indicates an expected call of GetExternalTransfer.
+func (mr *MockStoreMockRecorder) GetExternalTransfer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExternalTransfer", reflect.TypeOf((*MockStore)(nil).GetExternalTransfer), arg0, arg1)
+}
+
+// GetExternalTransferForUpdate mocks base method.
+func (m *MockStore) GetExternalTransferForUpdate(arg0 context.Context, arg1 int64) (db.ExternalTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExternalTransferForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.ExternalTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExternalTransferForUpdate indicates an expected call of GetExternalTransferForUpdate.
+func (mr *MockStoreMockRecorder) GetExternalTransferForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExternalTransferForUpdate", reflect.TypeOf((*MockStore)(nil).GetExternalTransferForUpdate), arg0, arg1)
+}
+
+// GetHold mocks base method.
+func (m *MockStore) GetHold(arg0 context.Context, arg1 int64) (db.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHold", arg0, arg1)
+	ret0, _ := ret[0].(db.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHold indicates an expected call of GetHold.
+func (mr *MockStoreMockRecorder) GetHold(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHold", reflect.TypeOf((*MockStore)(nil).GetHold), arg0, arg1)
+}
+
+// GetHoldForUpdate mocks base method.
+func (m *MockStore) GetHoldForUpdate(arg0 context.Context, arg1 int64) (db.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHoldForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHoldForUpdate indicates an expected call of GetHoldForUpdate.
+func (mr *MockStoreMockRecorder) GetHoldForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHoldForUpdate", reflect.TypeOf((*MockStore)(nil).GetHoldForUpdate), arg0, arg1)
+}
+
+// GetInterestAccrual mocks base method.
+func (m *MockStore) GetInterestAccrual(arg0 context.Context, arg1 int64) (db.InterestAccrual, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInterestAccrual", arg0, arg1)
+	ret0, _ := ret[0].(db.InterestAccrual)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInterestAccrual indicates an expected call of GetInterestAccrual.
+func (mr *MockStoreMockRecorder) GetInterestAccrual(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInterestAccrual", reflect.TypeOf((*MockStore)(nil).GetInterestAccrual), arg0, arg1)
+}
+
+// GetJob mocks base method.
+func (m *MockStore) GetJob(arg0 context.Context, arg1 uuid.UUID) (db.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetJob", arg0, arg1)
+	ret0, _ := ret[0].(db.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetJob indicates an expected call of GetJob.
+func (mr *MockStoreMockRecorder) GetJob(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJob", reflect.TypeOf((*MockStore)(nil).GetJob), arg0, arg1)
+}
+
+// GetLastCompletedBatchRun mocks base method.
+func (m *MockStore) GetLastCompletedBatchRun(arg0 context.Context, arg1 string) (db.BatchRun, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLastCompletedBatchRun", arg0, arg1)
+	ret0, _ := ret[0].(db.BatchRun)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLastCompletedBatchRun indicates an expected call of GetLastCompletedBatchRun.
+func (mr *MockStoreMockRecorder) GetLastCompletedBatchRun(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastCompletedBatchRun", reflect.TypeOf((*MockStore)(nil).GetLastCompletedBatchRun), arg0, arg1)
+}
+
+// GetLeaderLease mocks base method.
+func (m *MockStore) GetLeaderLease(arg0 context.Context, arg1 string) (db.LeaderLease, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLeaderLease", arg0, arg1)
+	ret0, _ := ret[0].(db.LeaderLease)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLeaderLease indicates an expected call of GetLeaderLease.
+func (mr *MockStoreMockRecorder) GetLeaderLease(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLeaderLease", reflect.TypeOf((*MockStore)(nil).GetLeaderLease), arg0, arg1)
+}
+
+// GetLoginLockout mocks base method.
+func (m *MockStore) GetLoginLockout(arg0 context.Context, arg1 db.GetLoginLockoutParams) (db.LoginLockout, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLoginLockout", arg0, arg1)
+	ret0, _ := ret[0].(db.LoginLockout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLoginLockout indicates an expected call of GetLoginLockout.
+func (mr *MockStoreMockRecorder) GetLoginLockout(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLoginLockout", reflect.TypeOf((*MockStore)(nil).GetLoginLockout), arg0, arg1)
+}
+
+// GetMandate mocks base method.
+func (m *MockStore) GetMandate(arg0 context.Context, arg1 int64) (db.Mandate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMandate", arg0, arg1)
+	ret0, _ := ret[0].(db.Mandate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMandate indicates an expected call of GetMandate.
+func (mr *MockStoreMockRecorder) GetMandate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMandate", reflect.TypeOf((*MockStore)(nil).GetMandate), arg0, arg1)
+}
+
+// GetMandateForUpdate mocks base method.
+func (m *MockStore) GetMandateForUpdate(arg0 context.Context, arg1 int64) (db.Mandate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMandateForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.Mandate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMandateForUpdate indicates an expected call of GetMandateForUpdate.
+func (mr *MockStoreMockRecorder) GetMandateForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMandateForUpdate", reflect.TypeOf((*MockStore)(nil).GetMandateForUpdate), arg0, arg1)
+}
+
+// GetNotificationPreferences mocks base method.
+func (m *MockStore) GetNotificationPreferences(arg0 context.Context, arg1 string) (db.NotificationPreference, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNotificationPreferences", arg0, arg1)
+	ret0, _ := ret[0].(db.NotificationPreference)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNotificationPreferences indicates an expected call of GetNotificationPreferences.
+func (mr *MockStoreMockRecorder) GetNotificationPreferences(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotificationPreferences", reflect.TypeOf((*MockStore)(nil).GetNotificationPreferences), arg0, arg1)
+}
+
+// GetOrganization mocks base method.
+func (m *MockStore) GetOrganization(arg0 context.Context, arg1 int64) (db.Organization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrganization", arg0, arg1)
+	ret0, _ := ret[0].(db.Organization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrganization indicates an expected call of GetOrganization.
+func (mr *MockStoreMockRecorder) GetOrganization(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrganization", reflect.TypeOf((*MockStore)(nil).GetOrganization), arg0, arg1)
+}
+
+// GetOrganizationBySlug mocks base method.
+func (m *MockStore) GetOrganizationBySlug(arg0 context.Context, arg1 string) (db.Organization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrganizationBySlug", arg0, arg1)
+	ret0, _ := ret[0].(db.Organization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrganizationBySlug indicates an expected call of GetOrganizationBySlug.
+func (mr *MockStoreMockRecorder) GetOrganizationBySlug(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrganizationBySlug", reflect.TypeOf((*MockStore)(nil).GetOrganizationBySlug), arg0, arg1)
+}
+
+// GetPaymentLinkByCode mocks base method.
+func (m *MockStore) GetPaymentLinkByCode(arg0 context.Context, arg1 string) (db.PaymentLink, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPaymentLinkByCode", arg0, arg1)
+	ret0, _ := ret[0].(db.PaymentLink)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPaymentLinkByCode indicates an expected call of GetPaymentLinkByCode.
+func (mr *MockStoreMockRecorder) GetPaymentLinkByCode(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPaymentLinkByCode", reflect.TypeOf((*MockStore)(nil).GetPaymentLinkByCode), arg0, arg1)
+}
+
+// GetPaymentLinkForUpdate mocks base method.
+func (m *MockStore) GetPaymentLinkForUpdate(arg0 context.Context, arg1 int64) (db.PaymentLink, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPaymentLinkForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.PaymentLink)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPaymentLinkForUpdate indicates an expected call of GetPaymentLinkForUpdate.
+func (mr *MockStoreMockRecorder) GetPaymentLinkForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPaymentLinkForUpdate", reflect.TypeOf((*MockStore)(nil).GetPaymentLinkForUpdate), arg0, arg1)
+}
+
+// GetPaymentRequest mocks base method.
+func (m *MockStore) GetPaymentRequest(arg0 context.Context, arg1 int64) (db.PaymentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPaymentRequest", arg0, arg1)
+	ret0, _ := ret[0].(db.PaymentRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPaymentRequest indicates an expected call of GetPaymentRequest.
+func (mr *MockStoreMockRecorder) GetPaymentRequest(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPaymentRequest", reflect.TypeOf((*MockStore)(nil).GetPaymentRequest), arg0, arg1)
+}
+
+// GetPaymentRequestForUpdate mocks base method.
+func (m *MockStore) GetPaymentRequestForUpdate(arg0 context.Context, arg1 int64) (db.PaymentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPaymentRequestForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.PaymentRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPaymentRequestForUpdate indicates an expected call of GetPaymentRequestForUpdate.
+func (mr *MockStoreMockRecorder) GetPaymentRequestForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPaymentRequestForUpdate", reflect.TypeOf((*MockStore)(nil).GetPaymentRequestForUpdate), arg0, arg1)
+}
+
+// GetPendingTransfer mocks base method.
+func (m *MockStore) GetPendingTransfer(arg0 context.Context, arg1 int64) (db.PendingTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingTransfer", arg0, arg1)
+	ret0, _ := ret[0].(db.PendingTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPendingTransfer indicates an expected call of GetPendingTransfer.
+func (mr *MockStoreMockRecorder) GetPendingTransfer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingTransfer", reflect.TypeOf((*MockStore)(nil).GetPendingTransfer), arg0, arg1)
+}
+
+// GetPendingTransferForUpdate mocks base method.
+func (m *MockStore) GetPendingTransferForUpdate(arg0 context.Context, arg1 int64) (db.PendingTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingTransferForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.PendingTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPendingTransferForUpdate indicates an expected call of GetPendingTransferForUpdate.
+func (mr *MockStoreMockRecorder) GetPendingTransferForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingTransferForUpdate", reflect.TypeOf((*MockStore)(nil).GetPendingTransferForUpdate), arg0, arg1)
+}
+
+// GetQueuedTransfer mocks base method.
+func (m *MockStore) GetQueuedTransfer(arg0 context.Context, arg1 uuid.UUID) (db.QueuedTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetQueuedTransfer", arg0, arg1)
+	ret0, _ := ret[0].(db.QueuedTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetQueuedTransfer indicates an expected call of GetQueuedTransfer.
+func (mr *MockStoreMockRecorder) GetQueuedTransfer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQueuedTransfer", reflect.TypeOf((*MockStore)(nil).GetQueuedTransfer), arg0, arg1)
+}
+
+// GetSession mocks base method.
+func (m *MockStore) GetSession(arg0 context.Context, arg1 uuid.UUID) (db.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSession", arg0, arg1)
+	ret0, _ := ret[0].(db.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSession indicates an expected call of GetSession.
+func (mr *MockStoreMockRecorder) GetSession(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSession", reflect.TypeOf((*MockStore)(nil).GetSession), arg0, arg1)
+}
+
+// GetSigningKey mocks base method.
+func (m *MockStore) GetSigningKey(arg0 context.Context, arg1 int64) (db.SigningKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSigningKey", arg0, arg1)
+	ret0, _ := ret[0].(db.SigningKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSigningKey indicates an expected call of GetSigningKey.
+func (mr *MockStoreMockRecorder) GetSigningKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSigningKey", reflect.TypeOf((*MockStore)(nil).GetSigningKey), arg0, arg1)
+}
+
+// GetStandingOrder mocks base method.
+func (m *MockStore) GetStandingOrder(arg0 context.Context, arg1 int64) (db.StandingOrder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStandingOrder", arg0, arg1)
+	ret0, _ := ret[0].(db.StandingOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStandingOrder indicates an expected call of GetStandingOrder.
+func (mr *MockStoreMockRecorder) GetStandingOrder(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStandingOrder", reflect.TypeOf((*MockStore)(nil).GetStandingOrder), arg0, arg1)
+}
+
+// GetStandingOrderForUpdate mocks base method.
+func (m *MockStore) GetStandingOrderForUpdate(arg0 context.Context, arg1 int64) (db.StandingOrder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStandingOrderForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.StandingOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStandingOrderForUpdate indicates an expected call of GetStandingOrderForUpdate.
+func (mr *MockStoreMockRecorder) GetStandingOrderForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStandingOrderForUpdate", reflect.TypeOf((*MockStore)(nil).GetStandingOrderForUpdate), arg0, arg1)
+}
+
+// GetSystemAccount mocks base method.
+func (m *MockStore) GetSystemAccount(arg0 context.Context, arg1 db.GetSystemAccountParams) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSystemAccount", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSystemAccount indicates an expected call of GetSystemAccount.
+func (mr *MockStoreMockRecorder) GetSystemAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSystemAccount", reflect.TypeOf((*MockStore)(nil).GetSystemAccount), arg0, arg1)
+}
+
+// GetTransfer mocks base method.
+func (m *MockStore) GetTransfer(arg0 context.Context, arg1 int64) (db.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransfer", arg0, arg1)
+	ret0, _ := ret[0].(db.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransfer indicates an expected call of GetTransfer.
+func (mr *MockStoreMockRecorder) GetTransfer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransfer", reflect.TypeOf((*MockStore)(nil).GetTransfer), arg0, arg1)
+}
+
+// GetTransferReview mocks base method.
+func (m *MockStore) GetTransferReview(arg0 context.Context, arg1 int64) (db.TransferReview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransferReview", arg0, arg1)
+	ret0, _ := ret[0].(db.TransferReview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransferReview indicates an expected call of GetTransferReview.
+func (mr *MockStoreMockRecorder) GetTransferReview(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransferReview", reflect.TypeOf((*MockStore)(nil).GetTransferReview), arg0, arg1)
+}
+
+// GetTransferReviewForUpdate mocks base method.
+func (m *MockStore) GetTransferReviewForUpdate(arg0 context.Context, arg1 int64) (db.TransferReview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransferReviewForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.TransferReview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransferReviewForUpdate indicates an expected call of GetTransferReviewForUpdate.
+func (mr *MockStoreMockRecorder) GetTransferReviewForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransferReviewForUpdate", reflect.TypeOf((*MockStore)(nil).GetTransferReviewForUpdate), arg0, arg1)
+}
+
+// GetUser mocks base method.
+func (m *MockStore) GetUser(arg0 context.Context, arg1 string) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUser", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUser indicates an expected call of GetUser.
+func (mr *MockStoreMockRecorder) GetUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockStore)(nil).GetUser), arg0, arg1)
+}
+
+// GetUserAPIPlan mocks base method.
+func (m *MockStore) GetUserAPIPlan(arg0 context.Context, arg1 string) (db.UserApiPlan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserAPIPlan", arg0, arg1)
+	ret0, _ := ret[0].(db.UserApiPlan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserAPIPlan indicates an expected call of GetUserAPIPlan.
+func (mr *MockStoreMockRecorder) GetUserAPIPlan(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserAPIPlan", reflect.TypeOf((*MockStore)(nil).GetUserAPIPlan), arg0, arg1)
+}
+
+// GetUserByEmail mocks base method.
+func (m *MockStore) GetUserByEmail(arg0 context.Context, arg1 string) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByEmail", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByEmail indicates an expected call of GetUserByEmail.
+func (mr *MockStoreMockRecorder) GetUserByEmail(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByEmail", reflect.TypeOf((*MockStore)(nil).GetUserByEmail), arg0, arg1)
+}
+
+// GetUserDeletion mocks base method.
+func (m *MockStore) GetUserDeletion(arg0 context.Context, arg1 string) (db.UserDeletion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserDeletion", arg0, arg1)
+	ret0, _ := ret[0].(db.UserDeletion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserDeletion indicates an expected call of GetUserDeletion.
+func (mr *MockStoreMockRecorder) GetUserDeletion(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserDeletion", reflect.TypeOf((*MockStore)(nil).GetUserDeletion), arg0, arg1)
+}
+
+// GetUserForUpdate mocks base method.
+func (m *MockStore) GetUserForUpdate(arg0 context.Context, arg1 string) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserForUpdate indicates an expected call of GetUserForUpdate.
+func (mr *MockStoreMockRecorder) GetUserForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserForUpdate", reflect.TypeOf((*MockStore)(nil).GetUserForUpdate), arg0, arg1)
+}
+
+// GetUserIdentity mocks base method.
+func (m *MockStore) GetUserIdentity(arg0 context.Context, arg1 db.GetUserIdentityParams) (db.UserIdentity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserIdentity", arg0, arg1)
+	ret0, _ := ret[0].(db.UserIdentity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserIdentity indicates an expected call of GetUserIdentity.
+func (mr *MockStoreMockRecorder) GetUserIdentity(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserIdentity", reflect.TypeOf((*MockStore)(nil).GetUserIdentity), arg0, arg1)
+}
+
+// GetUserOverview mocks base method.
+func (m *MockStore) GetUserOverview(arg0 context.Context, arg1 string) (db.UserOverview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserOverview", arg0, arg1)
+	ret0, _ := ret[0].(db.UserOverview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserOverview indicates an expected call of GetUserOverview.
+func (mr *MockStoreMockRecorder) GetUserOverview(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserOverview", reflect.TypeOf((*MockStore)(nil).GetUserOverview), arg0, arg1)
+}
+
+// HandOverJointAccounts mocks base method.
+func (m *MockStore) HandOverJointAccounts(arg0 context.Context, arg1 string) ([]db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HandOverJointAccounts", arg0, arg1)
+	ret0, _ := ret[0].([]db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HandOverJointAccounts indicates an expected call of HandOverJointAccounts.
+func (mr *MockStoreMockRecorder) HandOverJointAccounts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HandOverJointAccounts", reflect.TypeOf((*MockStore)(nil).HandOverJointAccounts), arg0, arg1)
+}
+
+// HoldTransferTx mocks base method.
+func (m *MockStore) HoldTransferTx(arg0 context.Context, arg1 db.HoldTransferTxParams) (db.HoldTransferTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HoldTransferTx", arg0, arg1)
+	ret0, _ := ret[0].(db.HoldTransferTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HoldTransferTx indicates an expected call of HoldTransferTx.
+func (mr *MockStoreMockRecorder) HoldTransferTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HoldTransferTx", reflect.TypeOf((*MockStore)(nil).HoldTransferTx), arg0, arg1)
+}
+
+// ImportEntriesTx mocks base method.
+func (m *MockStore) ImportEntriesTx(arg0 context.Context, arg1 db.ImportEntriesTxParams) (db.ImportEntriesTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportEntriesTx", arg0, arg1)
+	ret0, _ := ret[0].(db.ImportEntriesTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportEntriesTx indicates an expected call of ImportEntriesTx.
+func (mr *MockStoreMockRecorder) ImportEntriesTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportEntriesTx", reflect.TypeOf((*MockStore)(nil).ImportEntriesTx), arg0, arg1)
+}
+
+// InitiateExternalTransferTx mocks base method.
+func (m *MockStore) InitiateExternalTransferTx(arg0 context.Context, arg1 db.InitiateExternalTransferTxParams) (db.InitiateExternalTransferTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InitiateExternalTransferTx", arg0, arg1)
+	ret0, _ := ret[0].(db.InitiateExternalTransferTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InitiateExternalTransferTx indicates an expected call of InitiateExternalTransferTx.
+func (mr *MockStoreMockRecorder) InitiateExternalTransferTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InitiateExternalTransferTx", reflect.TypeOf((*MockStore)(nil).InitiateExternalTransferTx), arg0, arg1)
+}
+
+// IsTaskProcessed mocks base method.
+func (m *MockStore) IsTaskProcessed(arg0 context.Context, arg1 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsTaskProcessed", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsTaskProcessed indicates an expected call of IsTaskProcessed.
+func (mr *MockStoreMockRecorder) IsTaskProcessed(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsTaskProcessed", reflect.TypeOf((*MockStore)(nil).IsTaskProcessed), arg0, arg1)
+}
+
+// ListAccountBalanceDiscrepancies mocks base method.
+func (m *MockStore) ListAccountBalanceDiscrepancies(arg0 context.Context) ([]db.ListAccountBalanceDiscrepanciesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountBalanceDiscrepancies", arg0)
+	ret0, _ := ret[0].([]db.ListAccountBalanceDiscrepanciesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountBalanceDiscrepancies indicates an expected call of ListAccountBalanceDiscrepancies.
+func (mr *MockStoreMockRecorder) ListAccountBalanceDiscrepancies(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountBalanceDiscrepancies", reflect.TypeOf((*MockStore)(nil).ListAccountBalanceDiscrepancies), arg0)
+}
+
+// ListAccountCounterpartyActivity mocks base method.
+func (m *MockStore) ListAccountCounterpartyActivity(arg0 context.Context, arg1 db.ListAccountCounterpartyActivityParams) ([]db.AccountCounterpartyActivity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountCounterpartyActivity", arg0, arg1)
+	ret0, _ := ret[0].([]db.AccountCounterpartyActivity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountCounterpartyActivity indicates an expected call of ListAccountCounterpartyActivity.
+func (mr *MockStoreMockRecorder) ListAccountCounterpartyActivity(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountCounterpartyActivity", reflect.TypeOf((*MockStore)(nil).ListAccountCounterpartyActivity), arg0, arg1)
+}
+
+// ListAccountDailyActivity mocks base method.
+func (m *MockStore) ListAccountDailyActivity(arg0 context.Context, arg1 db.ListAccountDailyActivityParams) ([]db.AccountDailyActivity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountDailyActivity", arg0, arg1)
+	ret0, _ := ret[0].([]db.AccountDailyActivity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountDailyActivity indicates an expected call of ListAccountDailyActivity.
+func (mr *MockStoreMockRecorder) ListAccountDailyActivity(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountDailyActivity", reflect.TypeOf((*MockStore)(nil).ListAccountDailyActivity), arg0, arg1)
+}
+
+// ListAccountHistory mocks base method.
+func (m *MockStore) ListAccountHistory(arg0 context.Context, arg1 db.ListAccountHistoryParams) ([]db.AccountHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountHistory", arg0, arg1)
+	ret0, _ := ret[0].([]db.AccountHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountHistory indicates an expected call of ListAccountHistory.
+func (mr *MockStoreMockRecorder) ListAccountHistory(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountHistory", reflect.TypeOf((*MockStore)(nil).ListAccountHistory), arg0, arg1)
+}
+
+// ListAccountHolds mocks base method.
+func (m *MockStore) ListAccountHolds(arg0 context.Context, arg1 db.ListAccountHoldsParams) ([]db.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountHolds", arg0, arg1)
+	ret0, _ := ret[0].([]db.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountHolds indicates an expected call of ListAccountHolds.
+func (mr *MockStoreMockRecorder) ListAccountHolds(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountHolds", reflect.TypeOf((*MockStore)(nil).ListAccountHolds), arg0, arg1)
+}
+
+// ListAccountInvitations mocks base method.
+func (m *MockStore) ListAccountInvitations(arg0 context.Context, arg1 db.ListAccountInvitationsParams) ([]db.AccountMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountInvitations", arg0, arg1)
+	ret0, _ := ret[0].([]db.AccountMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountInvitations indicates an expected call of ListAccountInvitations.
+func (mr *MockStoreMockRecorder) ListAccountInvitations(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountInvitations", reflect.TypeOf((*MockStore)(nil).ListAccountInvitations), arg0, arg1)
+}
+
+// ListAccountOverviews mocks base method.
+func (m *MockStore) ListAccountOverviews(arg0 context.Context, arg1 db.ListAccountOverviewsParams) ([]db.AccountOverview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountOverviews", arg0, arg1)
+	ret0, _ := ret[0].([]db.AccountOverview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountOverviews indicates an expected call of ListAccountOverviews.
+func (mr *MockStoreMockRecorder) ListAccountOverviews(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountOverviews", reflect.TypeOf((*MockStore)(nil).ListAccountOverviews), arg0, arg1)
+}
+
+// ListAccounts mocks base method.
+func (m *MockStore) ListAccounts(arg0 context.Context, arg1 db.ListAccountsParams) ([]db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccounts", arg0, arg1)
+	ret0, _ := ret[0].([]db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccounts indicates an expected call of ListAccounts.
+func (mr *MockStoreMockRecorder) ListAccounts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccounts", reflect.TypeOf((*MockStore)(nil).ListAccounts), arg0, arg1)
+}
+
+// ListAccountsAfter mocks base method.
+func (m *MockStore) ListAccountsAfter(arg0 context.Context, arg1 db.ListAccountsAfterParams) ([]db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAccountsAfter", arg0, arg1)
+	ret0, _ := ret[0].([]db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAccountsAfter indicates an expected call of ListAccountsAfter.
+func (mr *MockStoreMockRecorder) ListAccountsAfter(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAccountsAfter", reflect.TypeOf((*MockStore)(nil).ListAccountsAfter), arg0, arg1)
+}
+
+// ListActiveBankParameters mocks base method.
+func (m *MockStore) ListActiveBankParameters(arg0 context.Context, arg1 time.Time) ([]db.BankParameter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListActiveBankParameters", arg0, arg1)
+	ret0, _ := ret[0].([]db.BankParameter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListActiveBankParameters indicates an expected call of ListActiveBankParameters.
+func (mr *MockStoreMockRecorder) ListActiveBankParameters(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListActiveBankParameters", reflect.TypeOf((*MockStore)(nil).ListActiveBankParameters), arg0, arg1)
+}
+
+// ListAuditEntries mocks base method.
+func (m *MockStore) ListAuditEntries(arg0 context.Context, arg1 db.ListAuditEntriesParams) ([]db.AuditEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAuditEntries", arg0, arg1)
+	ret0, _ := ret[0].([]db.AuditEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAuditEntries indicates an expected call of ListAuditEntries.
+func (mr *MockStoreMockRecorder) ListAuditEntries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAuditEntries", reflect.TypeOf((*MockStore)(nil).ListAuditEntries), arg0, arg1)
+}
+
+// ListBalanceSnapshots mocks base method.
+func (m *MockStore) ListBalanceSnapshots(arg0 context.Context, arg1 db.ListBalanceSnapshotsParams) ([]db.BalanceSnapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBalanceSnapshots", arg0, arg1)
+	ret0, _ := ret[0].([]db.BalanceSnapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBalanceSnapshots indicates an expected call of ListBalanceSnapshots.
+func (mr *MockStoreMockRecorder) ListBalanceSnapshots(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBalanceSnapshots", reflect.TypeOf((*MockStore)(nil).ListBalanceSnapshots), arg0, arg1)
+}
+
+// ListBankParameterVersions mocks base method.
+func (m *MockStore) ListBankParameterVersions(arg0 context.Context, arg1 db.ListBankParameterVersionsParams) ([]db.BankParameter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBankParameterVersions", arg0, arg1)
+	ret0, _ := ret[0].([]db.BankParameter)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBankParameterVersions indicates an expected call of ListBankParameterVersions.
+func (mr *MockStoreMockRecorder) ListBankParameterVersions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBankParameterVersions", reflect.TypeOf((*MockStore)(nil).ListBankParameterVersions), arg0, arg1)
+}
+
+// ListBeneficiaries mocks base method.
+func (m *MockStore) ListBeneficiaries(arg0 context.Context, arg1 db.ListBeneficiariesParams) ([]db.Beneficiary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBeneficiaries", arg0, arg1)
+	ret0, _ := ret[0].([]db.Beneficiary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBeneficiaries indicates an expected call of ListBeneficiaries.
+func (mr *MockStoreMockRecorder) ListBeneficiaries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBeneficiaries", reflect.TypeOf((*MockStore)(nil).ListBeneficiaries), arg0, arg1)
+}
+
+// ListBudgets mocks base method.
+func (m *MockStore) ListBudgets(arg0 context.Context, arg1 int64) ([]db.Budget, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBudgets", arg0, arg1)
+	ret0, _ := ret[0].([]db.Budget)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBudgets indicates an expected call of ListBudgets.
+func (mr *MockStoreMockRecorder) ListBudgets(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBudgets", reflect.TypeOf((*MockStore)(nil).ListBudgets), arg0, arg1)
+}
+
+// ListCardHolds mocks base method.
+func (m *MockStore) ListCardHolds(arg0 context.Context, arg1 db.ListCardHoldsParams) ([]db.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCardHolds", arg0, arg1)
+	ret0, _ := ret[0].([]db.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCardHolds indicates an expected call of ListCardHolds.
+func (mr *MockStoreMockRecorder) ListCardHolds(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCardHolds", reflect.TypeOf((*MockStore)(nil).ListCardHolds), arg0, arg1)
+}
+
+// ListCards mocks base method.
+func (m *MockStore) ListCards(arg0 context.Context, arg1 db.ListCardsParams) ([]db.Card, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCards", arg0, arg1)
+	ret0, _ := ret[0].([]db.Card)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCards indicates an expected call of ListCards.
+func (mr *MockStoreMockRecorder) ListCards(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCards", reflect.TypeOf((*MockStore)(nil).ListCards), arg0, arg1)
+}
+
+// ListDailyTransferVolumes mocks base method.
+func (m *MockStore) ListDailyTransferVolumes(arg0 context.Context, arg1 time.Time) ([]db.ListDailyTransferVolumesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDailyTransferVolumes", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListDailyTransferVolumesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDailyTransferVolumes indicates an expected call of ListDailyTransferVolumes.
+func (mr *MockStoreMockRecorder) ListDailyTransferVolumes(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDailyTransferVolumes", reflect.TypeOf((*MockStore)(nil).ListDailyTransferVolumes), arg0, arg1)
+}
+
+// ListDueChequeDeposits mocks base method.
+func (m *MockStore) ListDueChequeDeposits(arg0 context.Context, arg1 db.ListDueChequeDepositsParams) ([]db.ChequeDeposit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDueChequeDeposits", arg0, arg1)
+	ret0, _ := ret[0].([]db.ChequeDeposit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDueChequeDeposits indicates an expected call of ListDueChequeDeposits.
+func (mr *MockStoreMockRecorder) ListDueChequeDeposits(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDueChequeDeposits", reflect.TypeOf((*MockStore)(nil).ListDueChequeDeposits), arg0, arg1)
+}
+
+// ListDueStandingOrders mocks base method.
+func (m *MockStore) ListDueStandingOrders(arg0 context.Context, arg1 db.ListDueStandingOrdersParams) ([]db.StandingOrder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDueStandingOrders", arg0, arg1)
+	ret0, _ := ret[0].([]db.StandingOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDueStandingOrders indicates an expected call of ListDueStandingOrders.
+func (mr *MockStoreMockRecorder) ListDueStandingOrders(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDueStandingOrders", reflect.TypeOf((*MockStore)(nil).ListDueStandingOrders), arg0, arg1)
+}
+
+// ListEntries mocks base method.
+func (m *MockStore) ListEntries(arg0 context.Context, arg1 db.ListEntriesParams) ([]db.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEntries", arg0, arg1)
+	ret0, _ := ret[0].([]db.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEntries indicates an expected call of ListEntries.
+func (mr *MockStoreMockRecorder) ListEntries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEntries", reflect.TypeOf((*MockStore)(nil).ListEntries), arg0, arg1)
+}
+
+// ListEntriesForExport mocks base method.
+func (m *MockStore) ListEntriesForExport(arg0 context.Context, arg1 db.ListEntriesForExportParams) ([]db.ListEntriesForExportRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEntriesForExport", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListEntriesForExportRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEntriesForExport indicates an expected call of ListEntriesForExport.
+func (mr *MockStoreMockRecorder) ListEntriesForExport(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEntriesForExport", reflect.TypeOf((*MockStore)(nil).ListEntriesForExport), arg0, arg1)
+}
+
+// ListEntriesInRange mocks base method.
+func (m *MockStore) ListEntriesInRange(arg0 context.Context, arg1 db.ListEntriesInRangeParams) ([]db.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEntriesInRange", arg0, arg1)
+	ret0, _ := ret[0].([]db.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEntriesInRange indicates an expected call of ListEntriesInRange.
+func (mr *MockStoreMockRecorder) ListEntriesInRange(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEntriesInRange", reflect.TypeOf((*MockStore)(nil).ListEntriesInRange), arg0, arg1)
+}
+
+// ListEntriesWithCounterparty mocks base method.
+func (m *MockStore) ListEntriesWithCounterparty(arg0 context.Context, arg1 db.ListEntriesWithCounterpartyParams) ([]db.ListEntriesWithCounterpartyRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEntriesWithCounterparty", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListEntriesWithCounterpartyRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEntriesWithCounterparty indicates an expected call of ListEntriesWithCounterparty.
+func (mr *MockStoreMockRecorder) ListEntriesWithCounterparty(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEntriesWithCounterparty", reflect.TypeOf((*MockStore)(nil).ListEntriesWithCounterparty), arg0, arg1)
+}
+
+// ListEntriesWithCounterpartyBefore mocks base method.
+func (m *MockStore) ListEntriesWithCounterpartyBefore(arg0 context.Context, arg1 db.ListEntriesWithCounterpartyBeforeParams) ([]db.ListEntriesWithCounterpartyBeforeRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEntriesWithCounterpartyBefore", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListEntriesWithCounterpartyBeforeRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEntriesWithCounterpartyBefore indicates an expected call of ListEntriesWithCounterpartyBefore.
+func (mr *MockStoreMockRecorder) ListEntriesWithCounterpartyBefore(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEntriesWithCounterpartyBefore", reflect.TypeOf((*MockStore)(nil).ListEntriesWithCounterpartyBefore), arg0, arg1)
+}
+
+// ListEventsAfter mocks base method.
+func (m *MockStore) ListEventsAfter(arg0 context.Context, arg1 db.ListEventsAfterParams) ([]db.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEventsAfter", arg0, arg1)
+	ret0, _ := ret[0].([]db.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEventsAfter indicates an expected call of ListEventsAfter.
+func (mr *MockStoreMockRecorder) ListEventsAfter(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEventsAfter", reflect.TypeOf((*MockStore)(nil).ListEventsAfter), arg0, arg1)
+}
+
+// ListExternalTransfers mocks base method.
+func (m *MockStore) ListExternalTransfers(arg0 context.Context, arg1 db.ListExternalTransfersParams) ([]db.ExternalTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExternalTransfers", arg0, arg1)
+	ret0, _ := ret[0].([]db.ExternalTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExternalTransfers indicates an expected call of ListExternalTransfers.
+func (mr *MockStoreMockRecorder) ListExternalTransfers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExternalTransfers", reflect.TypeOf((*MockStore)(nil).ListExternalTransfers), arg0, arg1)
+}
+
+// ListLedgerAnomalies mocks base method.
+func (m *MockStore) ListLedgerAnomalies(arg0 context.Context, arg1 db.ListLedgerAnomaliesParams) ([]db.LedgerAnomaly, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLedgerAnomalies", arg0, arg1)
+	ret0, _ := ret[0].([]db.LedgerAnomaly)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLedgerAnomalies indicates an expected call of ListLedgerAnomalies.
+func (mr *MockStoreMockRecorder) ListLedgerAnomalies(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLedgerAnomalies", reflect.TypeOf((*MockStore)(nil).ListLedgerAnomalies), arg0, arg1)
+}
+
+// ListMandates mocks base method.
+func (m *MockStore) ListMandates(arg0 context.Context, arg1 db.ListMandatesParams) ([]db.Mandate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMandates", arg0, arg1)
+	ret0, _ := ret[0].([]db.Mandate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMandates indicates an expected call of ListMandates.
+func (mr *MockStoreMockRecorder) ListMandates(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMandates", reflect.TypeOf((*MockStore)(nil).ListMandates), arg0, arg1)
+}
+
+// ListNotifications mocks base method.
+func (m *MockStore) ListNotifications(arg0 context.Context, arg1 db.ListNotificationsParams) ([]db.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListNotifications", arg0, arg1)
+	ret0, _ := ret[0].([]db.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListNotifications indicates an expected call of ListNotifications.
+func (mr *MockStoreMockRecorder) ListNotifications(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNotifications", reflect.TypeOf((*MockStore)(nil).ListNotifications), arg0, arg1)
+}
+
+// ListOrganizationPendingTransfers mocks base method.
+func (m *MockStore) ListOrganizationPendingTransfers(arg0 context.Context, arg1 db.ListOrganizationPendingTransfersParams) ([]db.PendingTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOrganizationPendingTransfers", arg0, arg1)
+	ret0, _ := ret[0].([]db.PendingTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOrganizationPendingTransfers indicates an expected call of ListOrganizationPendingTransfers.
+func (mr *MockStoreMockRecorder) ListOrganizationPendingTransfers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOrganizationPendingTransfers", reflect.TypeOf((*MockStore)(nil).ListOrganizationPendingTransfers), arg0, arg1)
+}
+
+// ListOrganizationUsers mocks base method.
+func (m *MockStore) ListOrganizationUsers(arg0 context.Context, arg1 db.ListOrganizationUsersParams) ([]db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOrganizationUsers", arg0, arg1)
+	ret0, _ := ret[0].([]db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOrganizationUsers indicates an expected call of ListOrganizationUsers.
+func (mr *MockStoreMockRecorder) ListOrganizationUsers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOrganizationUsers", reflect.TypeOf((*MockStore)(nil).ListOrganizationUsers), arg0, arg1)
+}
+
+// ListOwnerRecentEntries mocks base method.
+func (m *MockStore) ListOwnerRecentEntries(arg0 context.Context, arg1 db.ListOwnerRecentEntriesParams) ([]db.ListOwnerRecentEntriesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOwnerRecentEntries", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListOwnerRecentEntriesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOwnerRecentEntries indicates an expected call of ListOwnerRecentEntries.
+func (mr *MockStoreMockRecorder) ListOwnerRecentEntries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOwnerRecentEntries", reflect.TypeOf((*MockStore)(nil).ListOwnerRecentEntries), arg0, arg1)
+}
+
+// ListPaymentRequests mocks base method.
+func (m *MockStore) ListPaymentRequests(arg0 context.Context, arg1 db.ListPaymentRequestsParams) ([]db.PaymentRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPaymentRequests", arg0, arg1)
+	ret0, _ := ret[0].([]db.PaymentRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPaymentRequests indicates an expected call of ListPaymentRequests.
+func (mr *MockStoreMockRecorder) ListPaymentRequests(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPaymentRequests", reflect.TypeOf((*MockStore)(nil).ListPaymentRequests), arg0, arg1)
+}
+
+// ListPendingTransfers mocks base method.
+func (m *MockStore) ListPendingTransfers(arg0 context.Context, arg1 db.ListPendingTransfersParams) ([]db.PendingTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPendingTransfers", arg0, arg1)
+	ret0, _ := ret[0].([]db.PendingTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPendingTransfers indicates an expected call of ListPendingTransfers.
+func (mr *MockStoreMockRecorder) ListPendingTransfers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPendingTransfers", reflect.TypeOf((*MockStore)(nil).ListPendingTransfers), arg0, arg1)
+}
+
+// ListRequestHeatmap mocks base method.
+func (m *MockStore) ListRequestHeatmap(arg0 context.Context, arg1 time.Time) ([]db.ListRequestHeatmapRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRequestHeatmap", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListRequestHeatmapRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRequestHeatmap indicates an expected call of ListRequestHeatmap.
+func (mr *MockStoreMockRecorder) ListRequestHeatmap(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRequestHeatmap", reflect.TypeOf((*MockStore)(nil).ListRequestHeatmap), arg0, arg1)
+}
+
+// ListRouteRequestVolumes mocks base method.
+func (m *MockStore) ListRouteRequestVolumes(arg0 context.Context, arg1 time.Time) ([]db.ListRouteRequestVolumesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRouteRequestVolumes", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListRouteRequestVolumesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRouteRequestVolumes indicates an expected call of ListRouteRequestVolumes.
+func (mr *MockStoreMockRecorder) ListRouteRequestVolumes(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRouteRequestVolumes", reflect.TypeOf((*MockStore)(nil).ListRouteRequestVolumes), arg0, arg1)
+}
+
+// ListSigningKeys mocks base method.
+func (m *MockStore) ListSigningKeys(arg0 context.Context, arg1 string) ([]db.SigningKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSigningKeys", arg0, arg1)
+	ret0, _ := ret[0].([]db.SigningKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSigningKeys indicates an expected call of ListSigningKeys.
+func (mr *MockStoreMockRecorder) ListSigningKeys(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSigningKeys", reflect.TypeOf((*MockStore)(nil).ListSigningKeys), arg0, arg1)
+}
+
+// ListStaleExternalTransfers mocks base method.
+func (m *MockStore) ListStaleExternalTransfers(arg0 context.Context, arg1 db.ListStaleExternalTransfersParams) ([]db.ExternalTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListStaleExternalTransfers", arg0, arg1)
+	ret0, _ := ret[0].([]db.ExternalTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListStaleExternalTransfers indicates an expected call of ListStaleExternalTransfers.
+func (mr *MockStoreMockRecorder) ListStaleExternalTransfers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStaleExternalTransfers", reflect.TypeOf((*MockStore)(nil).ListStaleExternalTransfers), arg0, arg1)
+}
+
+// ListStandingOrders mocks base method.
+func (m *MockStore) ListStandingOrders(arg0 context.Context, arg1 db.ListStandingOrdersParams) ([]db.StandingOrder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListStandingOrders", arg0, arg1)
+	ret0, _ := ret[0].([]db.StandingOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListStandingOrders indicates an expected call of ListStandingOrders.
+func (mr *MockStoreMockRecorder) ListStandingOrders(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStandingOrders", reflect.TypeOf((*MockStore)(nil).ListStandingOrders), arg0, arg1)
+}
+
+// ListTransferHeatmap mocks base method.
+func (m *MockStore) ListTransferHeatmap(arg0 context.Context, arg1 time.Time) ([]db.ListTransferHeatmapRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTransferHeatmap", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListTransferHeatmapRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTransferHeatmap indicates an expected call of ListTransferHeatmap.
+func (mr *MockStoreMockRecorder) ListTransferHeatmap(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransferHeatmap", reflect.TypeOf((*MockStore)(nil).ListTransferHeatmap), arg0, arg1)
+}
+
+// ListTransferImbalances mocks base method.
+func (m *MockStore) ListTransferImbalances(arg0 context.Context) ([]db.ListTransferImbalancesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTransferImbalances", arg0)
+	ret0, _ := ret[0].([]db.ListTransferImbalancesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTransferImbalances indicates an expected call of ListTransferImbalances.
+func (mr *MockStoreMockRecorder) ListTransferImbalances(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransferImbalances", reflect.TypeOf((*MockStore)(nil).ListTransferImbalances), arg0)
+}
+
+// ListTransferReviews mocks base method.
+func (m *MockStore) ListTransferReviews(arg0 context.Context, arg1 db.ListTransferReviewsParams) ([]db.TransferReview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTransferReviews", arg0, arg1)
+	ret0, _ := ret[0].([]db.TransferReview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTransferReviews indicates an expected call of ListTransferReviews.
+func (mr *MockStoreMockRecorder) ListTransferReviews(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransferReviews", reflect.TypeOf((*MockStore)(nil).ListTransferReviews), arg0, arg1)
+}
+
+// ListTransfers mocks base method.
+func (m *MockStore) ListTransfers(arg0 context.Context, arg1 db.ListTransfersParams) ([]db.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTransfers", arg0, arg1)
+	ret0, _ := ret[0].([]db.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTransfers indicates an expected call of ListTransfers.
+func (mr *MockStoreMockRecorder) ListTransfers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransfers", reflect.TypeOf((*MockStore)(nil).ListTransfers), arg0, arg1)
+}
+
+// ListUserOverviews mocks base method.
+func (m *MockStore) ListUserOverviews(arg0 context.Context, arg1 db.ListUserOverviewsParams) ([]db.UserOverview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserOverviews", arg0, arg1)
+	ret0, _ := ret[0].([]db.UserOverview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserOverviews indicates an expected call of ListUserOverviews.
+func (mr *MockStoreMockRecorder) ListUserOverviews(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserOverviews", reflect.TypeOf((*MockStore)(nil).ListUserOverviews), arg0, arg1)
+}
+
+// LockAccounts mocks base method.
+func (m *MockStore) LockAccounts(arg0 context.Context, arg1 []int64) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockAccounts", arg0, arg1)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockAccounts indicates an expected call of LockAccounts.
+func (mr *MockStoreMockRecorder) LockAccounts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockAccounts", reflect.TypeOf((*MockStore)(nil).LockAccounts), arg0, arg1)
+}
+
+// LockBatchRun mocks base method.
+func (m *MockStore) LockBatchRun(arg0 context.Context, arg1 db.LockBatchRunParams) (db.BatchRun, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockBatchRun", arg0, arg1)
+	ret0, _ := ret[0].(db.BatchRun)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockBatchRun indicates an expected call of LockBatchRun.
+func (mr *MockStoreMockRecorder) LockBatchRun(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockBatchRun", reflect.TypeOf((*MockStore)(nil).LockBatchRun), arg0, arg1)
+}
+
+// LockProjectionCheckpoint mocks base method.
+func (m *MockStore) LockProjectionCheckpoint(arg0 context.Context, arg1 string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockProjectionCheckpoint", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockProjectionCheckpoint indicates an expected call of LockProjectionCheckpoint.
+func (mr *MockStoreMockRecorder) LockProjectionCheckpoint(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockProjectionCheckpoint", reflect.TypeOf((*MockStore)(nil).LockProjectionCheckpoint), arg0, arg1)
+}
+
+// MarkAllNotificationsRead mocks base method.
+func (m *MockStore) MarkAllNotificationsRead(arg0 context.Context, arg1 string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkAllNotificationsRead", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkAllNotificationsRead indicates an expected call of MarkAllNotificationsRead.
+func (mr *MockStoreMockRecorder) MarkAllNotificationsRead(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkAllNotificationsRead", reflect.TypeOf((*MockStore)(nil).MarkAllNotificationsRead), arg0, arg1)
+}
+
+// MarkNotificationDelivered mocks base method.
+func (m *MockStore) MarkNotificationDelivered(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkNotificationDelivered", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkNotificationDelivered indicates an expected call of MarkNotificationDelivered.
+func (mr *MockStoreMockRecorder) MarkNotificationDelivered(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkNotificationDelivered", reflect.TypeOf((*MockStore)(nil).MarkNotificationDelivered), arg0, arg1)
+}
+
+// MarkNotificationRead mocks base method.
+func (m *MockStore) MarkNotificationRead(arg0 context.Context, arg1 db.MarkNotificationReadParams) (db.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkNotificationRead", arg0, arg1)
+	ret0, _ := ret[0].(db.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkNotificationRead indicates an expected call of MarkNotificationRead.
+func (mr *MockStoreMockRecorder) MarkNotificationRead(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkNotificationRead", reflect.TypeOf((*MockStore)(nil).MarkNotificationRead), arg0, arg1)
+}
+
+// OwnerHasBalance mocks base method.
+func (m *MockStore) OwnerHasBalance(arg0 context.Context, arg1 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OwnerHasBalance", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OwnerHasBalance indicates an expected call of OwnerHasBalance.
+func (mr *MockStoreMockRecorder) OwnerHasBalance(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OwnerHasBalance", reflect.TypeOf((*MockStore)(nil).OwnerHasBalance), arg0, arg1)
+}
+
+// PayPaymentLink mocks base method.
+func (m *MockStore) PayPaymentLink(arg0 context.Context, arg1 db.PayPaymentLinkParams) (db.PaymentLink, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PayPaymentLink", arg0, arg1)
+	ret0, _ := ret[0].(db.PaymentLink)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PayPaymentLink indicates an expected call of PayPaymentLink.
+func (mr *MockStoreMockRecorder) PayPaymentLink(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PayPaymentLink", reflect.TypeOf((*MockStore)(nil).PayPaymentLink), arg0, arg1)
+}
+
+// PayPaymentLinkTx mocks base method.
+func (m *MockStore) PayPaymentLinkTx(arg0 context.Context, arg1 db.PayPaymentLinkTxParams) (db.PayPaymentLinkTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PayPaymentLinkTx", arg0, arg1)
+	ret0, _ := ret[0].(db.PayPaymentLinkTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PayPaymentLinkTx indicates an expected call of PayPaymentLinkTx.
+func (mr *MockStoreMockRecorder) PayPaymentLinkTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PayPaymentLinkTx", reflect.TypeOf((*MockStore)(nil).PayPaymentLinkTx), arg0, arg1)
+}
+
+// ProjectEventsTx mocks base method.
+func (m *MockStore) ProjectEventsTx(arg0 context.Context, arg1 db.ProjectEventsTxParams) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProjectEventsTx", arg0, arg1)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProjectEventsTx indicates an expected call of ProjectEventsTx.
+func (mr *MockStoreMockRecorder) ProjectEventsTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProjectEventsTx", reflect.TypeOf((*MockStore)(nil).ProjectEventsTx), arg0, arg1)
+}
+
+// PullMandateTx mocks base method.
+func (m *MockStore) PullMandateTx(arg0 context.Context, arg1 db.PullMandateTxParams) (db.PullMandateTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PullMandateTx", arg0, arg1)
+	ret0, _ := ret[0].(db.PullMandateTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PullMandateTx indicates an expected call of PullMandateTx.
+func (mr *MockStoreMockRecorder) PullMandateTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PullMandateTx", reflect.TypeOf((*MockStore)(nil).PullMandateTx), arg0, arg1)
+}
+
+// ReconcileLedgerTx mocks base method.
+func (m *MockStore) ReconcileLedgerTx(arg0 context.Context) (db.ReconcileLedgerTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReconcileLedgerTx", arg0)
+	ret0, _ := ret[0].(db.ReconcileLedgerTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReconcileLedgerTx indicates an expected call of ReconcileLedgerTx.
+func (mr *MockStoreMockRecorder) ReconcileLedgerTx(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReconcileLedgerTx", reflect.TypeOf((*MockStore)(nil).ReconcileLedgerTx), arg0)
+}
+
+// RecordAccountOverviewTransfer mocks base method.
+func (m *MockStore) RecordAccountOverviewTransfer(arg0 context.Context, arg1 db.RecordAccountOverviewTransferParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordAccountOverviewTransfer", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordAccountOverviewTransfer indicates an expected call of RecordAccountOverviewTransfer.
+func (mr *MockStoreMockRecorder) RecordAccountOverviewTransfer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordAccountOverviewTransfer", reflect.TypeOf((*MockStore)(nil).RecordAccountOverviewTransfer), arg0, arg1)
+}
+
+// RecordLoginFailureTx mocks base method.
+func (m *MockStore) RecordLoginFailureTx(arg0 context.Context, arg1 db.RecordLoginFailureTxParams) (db.RecordLoginFailureTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordLoginFailureTx", arg0, arg1)
+	ret0, _ := ret[0].(db.RecordLoginFailureTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordLoginFailureTx indicates an expected call of RecordLoginFailureTx.
+func (mr *MockStoreMockRecorder) RecordLoginFailureTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordLoginFailureTx", reflect.TypeOf((*MockStore)(nil).RecordLoginFailureTx), arg0, arg1)
+}
+
+// RecordMandatePull mocks base method.
+func (m *MockStore) RecordMandatePull(arg0 context.Context, arg1 db.RecordMandatePullParams) (db.Mandate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordMandatePull", arg0, arg1)
+	ret0, _ := ret[0].(db.Mandate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordMandatePull indicates an expected call of RecordMandatePull.
+func (mr *MockStoreMockRecorder) RecordMandatePull(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordMandatePull", reflect.TypeOf((*MockStore)(nil).RecordMandatePull), arg0, arg1)
+}
+
+// RecordNotificationDeliveryFailure mocks base method.
+func (m *MockStore) RecordNotificationDeliveryFailure(arg0 context.Context, arg1 db.RecordNotificationDeliveryFailureParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordNotificationDeliveryFailure", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordNotificationDeliveryFailure indicates an expected call of RecordNotificationDeliveryFailure.
+func (mr *MockStoreMockRecorder) RecordNotificationDeliveryFailure(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordNotificationDeliveryFailure", reflect.TypeOf((*MockStore)(nil).RecordNotificationDeliveryFailure), arg0, arg1)
+}
+
+// RecordStandingOrderRun mocks base method.
+func (m *MockStore) RecordStandingOrderRun(arg0 context.Context, arg1 db.RecordStandingOrderRunParams) (db.StandingOrder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordStandingOrderRun", arg0, arg1)
+	ret0, _ := ret[0].(db.StandingOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordStandingOrderRun indicates an expected call of RecordStandingOrderRun.
+func (mr *MockStoreMockRecorder) RecordStandingOrderRun(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordStandingOrderRun", reflect.TypeOf((*MockStore)(nil).RecordStandingOrderRun), arg0, arg1)
+}
+
+// RefreshAccountOverviewVolume mocks base method.
+func (m *MockStore) RefreshAccountOverviewVolume(arg0 context.Context, arg1 pgtype.Int8) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshAccountOverviewVolume", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RefreshAccountOverviewVolume indicates an expected call of RefreshAccountOverviewVolume.
+func (mr *MockStoreMockRecorder) RefreshAccountOverviewVolume(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshAccountOverviewVolume", reflect.TypeOf((*MockStore)(nil).RefreshAccountOverviewVolume), arg0, arg1)
+}
+
+// RejectPendingTransfer mocks base method.
+func (m *MockStore) RejectPendingTransfer(arg0 context.Context, arg1 db.RejectPendingTransferParams) (db.PendingTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RejectPendingTransfer", arg0, arg1)
+	ret0, _ := ret[0].(db.PendingTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RejectPendingTransfer indicates an expected call of RejectPendingTransfer.
+func (mr *MockStoreMockRecorder) RejectPendingTransfer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RejectPendingTransfer", reflect.TypeOf((*MockStore)(nil).RejectPendingTransfer), arg0, arg1)
+}
+
+// ReleaseHold mocks base method.
+func (m *MockStore) ReleaseHold(arg0 context.Context, arg1 int64) (db.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseHold", arg0, arg1)
+	ret0, _ := ret[0].(db.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReleaseHold indicates an expected call of ReleaseHold.
+func (mr *MockStoreMockRecorder) ReleaseHold(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseHold", reflect.TypeOf((*MockStore)(nil).ReleaseHold), arg0, arg1)
+}
+
+// ReleaseHoldTx mocks base method.
+func (m *MockStore) ReleaseHoldTx(arg0 context.Context, arg1 int64) (db.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseHoldTx", arg0, arg1)
+	ret0, _ := ret[0].(db.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReleaseHoldTx indicates an expected call of ReleaseHoldTx.
+func (mr *MockStoreMockRecorder) ReleaseHoldTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseHoldTx", reflect.TypeOf((*MockStore)(nil).ReleaseHoldTx), arg0, arg1)
+}
+
+// ReleaseLeaderLease mocks base method.
+func (m *MockStore) ReleaseLeaderLease(arg0 context.Context, arg1 db.ReleaseLeaderLeaseParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseLeaderLease", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseLeaderLease indicates an expected call of ReleaseLeaderLease.
+func (mr *MockStoreMockRecorder) ReleaseLeaderLease(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseLeaderLease", reflect.TypeOf((*MockStore)(nil).ReleaseLeaderLease), arg0, arg1)
+}
+
+// ResolveLedgerAnomalies mocks base method.
+func (m *MockStore) ResolveLedgerAnomalies(arg0 context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveLedgerAnomalies", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveLedgerAnomalies indicates an expected call of ResolveLedgerAnomalies.
+func (mr *MockStoreMockRecorder) ResolveLedgerAnomalies(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveLedgerAnomalies", reflect.TypeOf((*MockStore)(nil).ResolveLedgerAnomalies), arg0)
+}
+
+// RevokeMandate mocks base method.
+func (m *MockStore) RevokeMandate(arg0 context.Context, arg1 int64) (db.Mandate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeMandate", arg0, arg1)
+	ret0, _ := ret[0].(db.Mandate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeMandate indicates an expected call of RevokeMandate.
+func (mr *MockStoreMockRecorder) RevokeMandate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeMandate", reflect.TypeOf((*MockStore)(nil).RevokeMandate), arg0, arg1)
+}
+
+// RevokeSigningKey mocks base method.
+func (m *MockStore) RevokeSigningKey(arg0 context.Context, arg1 db.RevokeSigningKeyParams) (db.SigningKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSigningKey", arg0, arg1)
+	ret0, _ := ret[0].(db.SigningKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeSigningKey indicates an expected call of RevokeSigningKey.
+func (mr *MockStoreMockRecorder) RevokeSigningKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSigningKey", reflect.TypeOf((*MockStore)(nil).RevokeSigningKey), arg0, arg1)
+}
+
+// RevokeUserMandates mocks base method.
+func (m *MockStore) RevokeUserMandates(arg0 context.Context, arg1 string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeUserMandates", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeUserMandates indicates an expected call of RevokeUserMandates.
+func (mr *MockStoreMockRecorder) RevokeUserMandates(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeUserMandates", reflect.TypeOf((*MockStore)(nil).RevokeUserMandates), arg0, arg1)
+}
+
+// RunStandingOrderTx mocks base method.
+func (m *MockStore) RunStandingOrderTx(arg0 context.Context, arg1 db.RunStandingOrderTxParams) (db.RunStandingOrderTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RunStandingOrderTx", arg0, arg1)
+	ret0, _ := ret[0].(db.RunStandingOrderTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RunStandingOrderTx indicates an expected call of RunStandingOrderTx.
+func (mr *MockStoreMockRecorder) RunStandingOrderTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunStandingOrderTx", reflect.TypeOf((*MockStore)(nil).RunStandingOrderTx), arg0, arg1)
+}
+
+// ScheduleUserDeletion mocks base method.
+func (m *MockStore) ScheduleUserDeletion(arg0 context.Context, arg1 db.ScheduleUserDeletionParams) (db.UserDeletion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ScheduleUserDeletion", arg0, arg1)
+	ret0, _ := ret[0].(db.UserDeletion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ScheduleUserDeletion indicates an expected call of ScheduleUserDeletion.
+func (mr *MockStoreMockRecorder) ScheduleUserDeletion(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScheduleUserDeletion", reflect.TypeOf((*MockStore)(nil).ScheduleUserDeletion), arg0, arg1)
+}
+
+// ScrubUserEvents mocks base method.
+func (m *MockStore) ScrubUserEvents(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ScrubUserEvents", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ScrubUserEvents indicates an expected call of ScrubUserEvents.
+func (mr *MockStoreMockRecorder) ScrubUserEvents(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScrubUserEvents", reflect.TypeOf((*MockStore)(nil).ScrubUserEvents), arg0, arg1)
+}
+
+// SearchAccountEntries mocks base method.
+func (m *MockStore) SearchAccountEntries(arg0 context.Context, arg1 db.SearchAccountEntriesParams) ([]db.SearchAccountEntriesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchAccountEntries", arg0, arg1)
+	ret0, _ := ret[0].([]db.SearchAccountEntriesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchAccountEntries indicates an expected call of SearchAccountEntries.
+func (mr *MockStoreMockRecorder) SearchAccountEntries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchAccountEntries", reflect.TypeOf((*MockStore)(nil).SearchAccountEntries), arg0, arg1)
+}
+
+// SearchAccounts mocks base method.
+func (m *MockStore) SearchAccounts(arg0 context.Context, arg1 db.SearchAccountsParams) ([]db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchAccounts", arg0, arg1)
+	ret0, _ := ret[0].([]db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchAccounts indicates an expected call of SearchAccounts.
+func (mr *MockStoreMockRecorder) SearchAccounts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchAccounts", reflect.TypeOf((*MockStore)(nil).SearchAccounts), arg0, arg1)
+}
+
+// SearchTransfers mocks base method.
+func (m *MockStore) SearchTransfers(arg0 context.Context, arg1 db.SearchTransfersParams) ([]db.SearchTransfersRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchTransfers", arg0, arg1)
+	ret0, _ := ret[0].([]db.SearchTransfersRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchTransfers indicates an expected call of SearchTransfers.
+func (mr *MockStoreMockRecorder) SearchTransfers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchTransfers", reflect.TypeOf((*MockStore)(nil).SearchTransfers), arg0, arg1)
+}
+
+// SearchTransfersBefore mocks base method.
+func (m *MockStore) SearchTransfersBefore(arg0 context.Context, arg1 db.SearchTransfersBeforeParams) ([]db.SearchTransfersBeforeRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchTransfersBefore", arg0, arg1)
+	ret0, _ := ret[0].([]db.SearchTransfersBeforeRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchTransfersBefore indicates an expected call of SearchTransfersBefore.
+func (mr *MockStoreMockRecorder) SearchTransfersBefore(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchTransfersBefore", reflect.TypeOf((*MockStore)(nil).SearchTransfersBefore), arg0, arg1)
+}
+
+// SetAccountOverviewBalance mocks base method.
+func (m *MockStore) SetAccountOverviewBalance(arg0 context.Context, arg1 db.SetAccountOverviewBalanceParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAccountOverviewBalance", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetAccountOverviewBalance indicates an expected call of SetAccountOverviewBalance.
+func (mr *MockStoreMockRecorder) SetAccountOverviewBalance(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAccountOverviewBalance", reflect.TypeOf((*MockStore)(nil).SetAccountOverviewBalance), arg0, arg1)
+}
+
+// SetInterestAccrual mocks base method.
+func (m *MockStore) SetInterestAccrual(arg0 context.Context, arg1 db.SetInterestAccrualParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetInterestAccrual", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetInterestAccrual indicates an expected call of SetInterestAccrual.
+func (mr *MockStoreMockRecorder) SetInterestAccrual(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetInterestAccrual", reflect.TypeOf((*MockStore)(nil).SetInterestAccrual), arg0, arg1)
+}
+
+// SnapshotBalancesTx mocks base method.
+func (m *MockStore) SnapshotBalancesTx(arg0 context.Context, arg1 db.SnapshotBalancesTxParams) (db.BatchTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SnapshotBalancesTx", arg0, arg1)
+	ret0, _ := ret[0].(db.BatchTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SnapshotBalancesTx indicates an expected call of SnapshotBalancesTx.
+func (mr *MockStoreMockRecorder) SnapshotBalancesTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SnapshotBalancesTx", reflect.TypeOf((*MockStore)(nil).SnapshotBalancesTx), arg0, arg1)
+}
+
+// SumCategorySpending mocks base method.
+func (m *MockStore) SumCategorySpending(arg0 context.Context, arg1 db.SumCategorySpendingParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumCategorySpending", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SumCategorySpending indicates an expected call of SumCategorySpending.
+func (mr *MockStoreMockRecorder) SumCategorySpending(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumCategorySpending", reflect.TypeOf((*MockStore)(nil).SumCategorySpending), arg0, arg1)
+}
+
+// SumEntriesSince mocks base method.
+func (m *MockStore) SumEntriesSince(arg0 context.Context, arg1 db.SumEntriesSinceParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumEntriesSince", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SumEntriesSince indicates an expected call of SumEntriesSince.
+func (mr *MockStoreMockRecorder) SumEntriesSince(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumEntriesSince", reflect.TypeOf((*MockStore)(nil).SumEntriesSince), arg0, arg1)
+}
+
+// SumOwnerBalances mocks base method.
+func (m *MockStore) SumOwnerBalances(arg0 context.Context, arg1 string) ([]db.SumOwnerBalancesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumOwnerBalances", arg0, arg1)
+	ret0, _ := ret[0].([]db.SumOwnerBalancesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SumOwnerBalances indicates an expected call of SumOwnerBalances.
+func (mr *MockStoreMockRecorder) SumOwnerBalances(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumOwnerBalances", reflect.TypeOf((*MockStore)(nil).SumOwnerBalances), arg0, arg1)
+}
+
+// SumOwnerSpending mocks base method.
+func (m *MockStore) SumOwnerSpending(arg0 context.Context, arg1 db.SumOwnerSpendingParams) ([]db.SumOwnerSpendingRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumOwnerSpending", arg0, arg1)
+	ret0, _ := ret[0].([]db.SumOwnerSpendingRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SumOwnerSpending indicates an expected call of SumOwnerSpending.
+func (mr *MockStoreMockRecorder) SumOwnerSpending(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumOwnerSpending", reflect.TypeOf((*MockStore)(nil).SumOwnerSpending), arg0, arg1)
+}
+
+// TouchSession mocks base method.
+func (m *MockStore) TouchSession(arg0 context.Context, arg1 db.TouchSessionParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TouchSession", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TouchSession indicates an expected call of TouchSession.
+func (mr *MockStoreMockRecorder) TouchSession(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TouchSession", reflect.TypeOf((*MockStore)(nil).TouchSession), arg0, arg1)
+}
+
+// TouchUserOverview mocks base method.
+func (m *MockStore) TouchUserOverview(arg0 context.Context, arg1 db.TouchUserOverviewParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TouchUserOverview", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TouchUserOverview indicates an expected call of TouchUserOverview.
+func (mr *MockStoreMockRecorder) TouchUserOverview(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TouchUserOverview", reflect.TypeOf((*MockStore)(nil).TouchUserOverview), arg0, arg1)
+}
+
+// TransferTx mocks base method.
+func (m *MockStore) TransferTx(arg0 context.Context, arg1 db.TransferTxParams) (db.TransferTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransferTx", arg0, arg1)
+	ret0, _ := ret[0].(db.TransferTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TransferTx indicates an expected call of TransferTx.
+func (mr *MockStoreMockRecorder) TransferTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransferTx", reflect.TypeOf((*MockStore)(nil).TransferTx), arg0, arg1)
+}
+
+// UnlockUserTx mocks base method.
+func (m *MockStore) UnlockUserTx(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnlockUserTx", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnlockUserTx indicates an expected call of UnlockUserTx.
+func (mr *MockStoreMockRecorder) UnlockUserTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnlockUserTx", reflect.TypeOf((*MockStore)(nil).UnlockUserTx), arg0, arg1)
+}
+
+// UpdateAccountCurrency mocks base method.
+func (m *MockStore) UpdateAccountCurrency(arg0 context.Context, arg1 db.UpdateAccountCurrencyParams) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAccountCurrency", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAccountCurrency indicates an expected call of UpdateAccountCurrency.
+func (mr *MockStoreMockRecorder) UpdateAccountCurrency(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccountCurrency", reflect.TypeOf((*MockStore)(nil).UpdateAccountCurrency), arg0, arg1)
+}
+
+// UpdateAccountProfile mocks base method.
+func (m *MockStore) UpdateAccountProfile(arg0 context.Context, arg1 db.UpdateAccountProfileParams) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAccountProfile", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAccountProfile indicates an expected call of UpdateAccountProfile.
+func (mr *MockStoreMockRecorder) UpdateAccountProfile(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccountProfile", reflect.TypeOf((*MockStore)(nil).UpdateAccountProfile), arg0, arg1)
+}
+
+// UpdateBatchRunCheckpoint mocks base method.
+func (m *MockStore) UpdateBatchRunCheckpoint(arg0 context.Context, arg1 db.UpdateBatchRunCheckpointParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBatchRunCheckpoint", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateBatchRunCheckpoint indicates an expected call of UpdateBatchRunCheckpoint.
+func (mr *MockStoreMockRecorder) UpdateBatchRunCheckpoint(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBatchRunCheckpoint", reflect.TypeOf((*MockStore)(nil).UpdateBatchRunCheckpoint), arg0, arg1)
+}
+
+// UpdateBeneficiary mocks base method.
+func (m *MockStore) UpdateBeneficiary(arg0 context.Context, arg1 db.UpdateBeneficiaryParams) (db.Beneficiary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBeneficiary", arg0, arg1)
+	ret0, _ := ret[0].(db.Beneficiary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateBeneficiary indicates an expected call of UpdateBeneficiary.
+func (mr *MockStoreMockRecorder) UpdateBeneficiary(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBeneficiary", reflect.TypeOf((*MockStore)(nil).UpdateBeneficiary), arg0, arg1)
+}
+
+// UpdateCardStatus mocks base method.
+func (m *MockStore) UpdateCardStatus(arg0 context.Context, arg1 db.UpdateCardStatusParams) (db.Card, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCardStatus", arg0, arg1)
+	ret0, _ := ret[0].(db.Card)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCardStatus indicates an expected call of UpdateCardStatus.
+func (mr *MockStoreMockRecorder) UpdateCardStatus(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCardStatus", reflect.TypeOf((*MockStore)(nil).UpdateCardStatus), arg0, arg1)
+}
+
+// UpdateExternalTransferStatus mocks base method.
+func (m *MockStore) UpdateExternalTransferStatus(arg0 context.Context, arg1 db.UpdateExternalTransferStatusParams) (db.ExternalTransfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateExternalTransferStatus", arg0, arg1)
+	ret0, _ := ret[0].(db.ExternalTransfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateExternalTransferStatus indicates an expected call of UpdateExternalTransferStatus.
+func (mr *MockStoreMockRecorder) UpdateExternalTransferStatus(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateExternalTransferStatus", reflect.TypeOf((*MockStore)(nil).UpdateExternalTransferStatus), arg0, arg1)
+}
+
+// UpdateJobProgress mocks base method.
+func (m *MockStore) UpdateJobProgress(arg0 context.Context, arg1 db.UpdateJobProgressParams) (db.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateJobProgress", arg0, arg1)
+	ret0, _ := ret[0].(db.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateJobProgress indicates an expected call of UpdateJobProgress.
+func (mr *MockStoreMockRecorder) UpdateJobProgress(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateJobProgress", reflect.TypeOf((*MockStore)(nil).UpdateJobProgress), arg0, arg1)
+}
+
+// UpdateOrganizationApprovalThreshold mocks base method.
+func (m *MockStore) UpdateOrganizationApprovalThreshold(arg0 context.Context, arg1 db.UpdateOrganizationApprovalThresholdParams) (db.Organization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOrganizationApprovalThreshold", arg0, arg1)
+	ret0, _ := ret[0].(db.Organization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateOrganizationApprovalThreshold indicates an expected call of UpdateOrganizationApprovalThreshold.
+func (mr *MockStoreMockRecorder) UpdateOrganizationApprovalThreshold(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOrganizationApprovalThreshold", reflect.TypeOf((*MockStore)(nil).UpdateOrganizationApprovalThreshold), arg0, arg1)
+}
+
+// UpdateProjectionCheckpoint mocks base method.
+func (m *MockStore) UpdateProjectionCheckpoint(arg0 context.Context, arg1 db.UpdateProjectionCheckpointParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProjectionCheckpoint", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateProjectionCheckpoint indicates an expected call of UpdateProjectionCheckpoint.
+func (mr *MockStoreMockRecorder) UpdateProjectionCheckpoint(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProjectionCheckpoint", reflect.TypeOf((*MockStore)(nil).UpdateProjectionCheckpoint), arg0, arg1)
+}
+
+// UpdateUserRole mocks base method.
+func (m *MockStore) UpdateUserRole(arg0 context.Context, arg1 db.UpdateUserRoleParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserRole", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserRole indicates an expected call of UpdateUserRole.
+func (mr *MockStoreMockRecorder) UpdateUserRole(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserRole", reflect.TypeOf((*MockStore)(nil).UpdateUserRole), arg0, arg1)
+}
+
+// UpsertAccountAlert mocks base method.
+func (m *MockStore) UpsertAccountAlert(arg0 context.Context, arg1 db.UpsertAccountAlertParams) (db.AccountAlert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertAccountAlert", arg0, arg1)
+	ret0, _ := ret[0].(db.AccountAlert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertAccountAlert indicates an expected call of UpsertAccountAlert.
+func (mr *MockStoreMockRecorder) UpsertAccountAlert(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertAccountAlert", reflect.TypeOf((*MockStore)(nil).UpsertAccountAlert), arg0, arg1)
+}
+
+// UpsertAccountOverview mocks base method.
+func (m *MockStore) UpsertAccountOverview(arg0 context.Context, arg1 db.UpsertAccountOverviewParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertAccountOverview", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertAccountOverview indicates an expected call of UpsertAccountOverview.
+func (mr *MockStoreMockRecorder) UpsertAccountOverview(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertAccountOverview", reflect.TypeOf((*MockStore)(nil).UpsertAccountOverview), arg0, arg1)
+}
+
+// UpsertBudget mocks base method.
+func (m *MockStore) UpsertBudget(arg0 context.Context, arg1 db.UpsertBudgetParams) (db.Budget, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertBudget", arg0, arg1)
+	ret0, _ := ret[0].(db.Budget)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertBudget indicates an expected call of UpsertBudget.
+func (mr *MockStoreMockRecorder) UpsertBudget(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertBudget", reflect.TypeOf((*MockStore)(nil).UpsertBudget), arg0, arg1)
+}
+
+// UpsertLedgerAnomaly mocks base method.
+func (m *MockStore) UpsertLedgerAnomaly(arg0 context.Context, arg1 db.UpsertLedgerAnomalyParams) (db.LedgerAnomaly, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertLedgerAnomaly", arg0, arg1)
+	ret0, _ := ret[0].(db.LedgerAnomaly)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertLedgerAnomaly indicates an expected call of UpsertLedgerAnomaly.
+func (mr *MockStoreMockRecorder) UpsertLedgerAnomaly(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertLedgerAnomaly", reflect.TypeOf((*MockStore)(nil).UpsertLedgerAnomaly), arg0, arg1)
+}
+
+// UpsertLoginLockout mocks base method.
+func (m *MockStore) UpsertLoginLockout(arg0 context.Context, arg1 db.UpsertLoginLockoutParams) (db.LoginLockout, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertLoginLockout", arg0, arg1)
+	ret0, _ := ret[0].(db.LoginLockout)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertLoginLockout indicates an expected call of UpsertLoginLockout.
+func (mr *MockStoreMockRecorder) UpsertLoginLockout(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertLoginLockout", reflect.TypeOf((*MockStore)(nil).UpsertLoginLockout), arg0, arg1)
+}
+
+// UpsertNotificationPreferences mocks base method.
+func (m *MockStore) UpsertNotificationPreferences(arg0 context.Context, arg1 db.UpsertNotificationPreferencesParams) (db.NotificationPreference, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertNotificationPreferences", arg0, arg1)
+	ret0, _ := ret[0].(db.NotificationPreference)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertNotificationPreferences indicates an expected call of UpsertNotificationPreferences.
+func (mr *MockStoreMockRecorder) UpsertNotificationPreferences(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertNotificationPreferences", reflect.TypeOf((*MockStore)(nil).UpsertNotificationPreferences), arg0, arg1)
+}
+
+// UpsertUserAPIPlan mocks base method.
+func (m *MockStore) UpsertUserAPIPlan(arg0 context.Context, arg1 db.UpsertUserAPIPlanParams) (db.UserApiPlan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertUserAPIPlan", arg0, arg1)
+	ret0, _ := ret[0].(db.UserApiPlan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertUserAPIPlan indicates an expected call of UpsertUserAPIPlan.
+func (mr *MockStoreMockRecorder) UpsertUserAPIPlan(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertUserAPIPlan", reflect.TypeOf((*MockStore)(nil).UpsertUserAPIPlan), arg0, arg1)
+}
+
+// UpsertUserOverview mocks base method.
+func (m *MockStore) UpsertUserOverview(arg0 context.Context, arg1 db.UpsertUserOverviewParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertUserOverview", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertUserOverview indicates an expected call of UpsertUserOverview.
+func (mr *MockStoreMockRecorder) UpsertUserOverview(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccount", reflect.TypeOf((*MockStore)(nil).UpdateAccount), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertUserOverview", reflect.TypeOf((*MockStore)(nil).UpsertUserOverview), arg0, arg1)
 }
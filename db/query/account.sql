@@ -1,21 +1,38 @@
 -- name: CreateAccount :one
+-- Creates the account in the organization of its owner.
 INSERT INTO accounts (
     owner,
     balance,
-    currency
+    currency,
+    org_id
 ) VALUES (
-    $1, $2, $3
+    $1, $2, $3, (SELECT org_id FROM users WHERE username = $1)
 ) RETURNING *;
 
 -- name: GetAccount :one
 SELECT * FROM accounts
 WHERE id = $1 LIMIT 1;
 
+-- name: GetAccountByNumber :one
+SELECT * FROM accounts
+WHERE account_number = $1 LIMIT 1;
+
 -- name: GetAccountForUpdate :one
 SELECT * FROM accounts
 WHERE id = $1 LIMIT 1
 FOR NO KEY UPDATE;
 
+-- name: GetAccountsByIDs :many
+-- Gets the accounts of an owner among the given ids, including the accounts shared with them, the other
+-- ids are skipped.
+SELECT * FROM accounts
+WHERE
+    (owner = sqlc.arg(owner) OR id IN (
+        SELECT account_id FROM account_members WHERE username = sqlc.arg(owner) AND accepted_at IS NOT NULL
+    )) AND
+    id = ANY(sqlc.arg(ids)::bigint[])
+ORDER BY id;
+
 -- name: ListAccounts :many
 SELECT * FROM accounts
 WHERE owner = $1
@@ -23,17 +40,121 @@ ORDER BY id
 LIMIT $2
 OFFSET $3;
 
--- name: UpdateAccount :one
-UPDATE accounts 
-SET balance = $2
-WHERE id = $1
-RETURNING *;
-
 -- name: AddAccountBalance :one
+-- Adds the amount to the balance of the account, which must be posted as entries of the account in the
+-- same transaction.
 UPDATE accounts 
-SET balance = balance + sqlc.arg(amount)
+SET balance = balance + sqlc.arg(amount), version = version + 1
 WHERE id = sqlc.arg(id)
 RETURNING *;
 
+-- name: AccountHasHistory :one
+-- Reports whether the account has entries or transfers, which prevent it from being deleted.
+SELECT (
+    EXISTS (SELECT 1 FROM entries WHERE account_id = sqlc.arg(account_id)) OR
+    EXISTS (SELECT 1 FROM transfers WHERE from_account_id = sqlc.arg(account_id) OR to_account_id = sqlc.arg(account_id))
+)::bool AS has_history;
+
 -- name: DeleteAccount :exec
-DELETE FROM accounts WHERE id = $1;
\ No newline at end of file
+DELETE FROM accounts WHERE id = $1;
+
+-- name: SearchAccounts :many
+-- Lists the accounts of an owner, including the accounts shared with them, optionally of a single currency
+-- and with at least a given balance, sorted by sort_by (balance, created_at or currency, defaulting to id)
+-- with ties broken by id.
+SELECT * FROM accounts
+WHERE
+    (owner = sqlc.arg(owner) OR id IN (
+        SELECT account_id FROM account_members WHERE username = sqlc.arg(owner) AND accepted_at IS NOT NULL
+    )) AND
+    (sqlc.narg(currency)::varchar IS NULL OR currency = sqlc.narg(currency)) AND
+    (sqlc.narg(min_balance)::bigint IS NULL OR balance >= sqlc.narg(min_balance))
+ORDER BY
+    CASE WHEN sqlc.arg(sort_by)::varchar = 'balance' AND NOT sqlc.arg(sort_desc)::bool THEN balance END,
+    CASE WHEN sqlc.arg(sort_by)::varchar = 'balance' AND sqlc.arg(sort_desc)::bool THEN balance END DESC,
+    CASE WHEN sqlc.arg(sort_by)::varchar = 'created_at' AND NOT sqlc.arg(sort_desc)::bool THEN created_at END,
+    CASE WHEN sqlc.arg(sort_by)::varchar = 'created_at' AND sqlc.arg(sort_desc)::bool THEN created_at END DESC,
+    CASE WHEN sqlc.arg(sort_by)::varchar = 'currency' AND NOT sqlc.arg(sort_desc)::bool THEN currency END,
+    CASE WHEN sqlc.arg(sort_by)::varchar = 'currency' AND sqlc.arg(sort_desc)::bool THEN currency END DESC,
+    id
+LIMIT sqlc.arg(row_limit)
+OFFSET sqlc.arg(row_offset);
+
+-- name: ListAccountsAfter :many
+-- Lists the accounts following an id, locked for the batch updating them.
+SELECT * FROM accounts
+WHERE id > $1
+ORDER BY id
+LIMIT $2
+FOR NO KEY UPDATE;
+
+-- name: CloseOwnerAccounts :many
+-- Closes the open accounts of the owner, for the deletion of their data. The accounts are kept with their
+-- history for the ledger.
+UPDATE accounts
+SET
+    closed_at = now(),
+    version = version + 1
+WHERE owner = $1 AND closed_at IS NULL
+RETURNING *;
+
+-- name: OwnerHasBalance :one
+-- Reports whether an open account of the owner holds money, which prevents the deletion of their data.
+-- The accounts shared with co-owners don't, since they are handed over to a co-owner rather than closed.
+SELECT EXISTS (
+    SELECT 1 FROM accounts
+    WHERE owner = $1 AND closed_at IS NULL AND balance <> 0 AND NOT EXISTS (
+        SELECT 1 FROM account_members
+        WHERE account_id = accounts.id AND role = 'owner' AND accepted_at IS NOT NULL
+    )
+)::bool AS has_balance;
+
+-- name: UpdateAccountCurrency :one
+-- Changes the currency of the account, whose balance must be converted by entries of the account in the
+-- same transaction.
+UPDATE accounts
+SET currency = sqlc.arg(currency), version = version + 1
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: AddAccountHeldBalance :one
+-- Adds the amount to the held balance of the account, which must be the amount of a hold placed on
+-- the account or, negated, released from it in the same transaction.
+UPDATE accounts
+SET held_balance = held_balance + sqlc.arg(amount), version = version + 1
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: HandOverJointAccounts :many
+-- Hands the open accounts of the owner shared with co-owners over to the co-owner who accepted first, for
+-- the deletion of the data of the owner, so that the accounts aren't closed under their co-owners.
+UPDATE accounts
+SET
+    owner = co_owners.username,
+    version = accounts.version + 1
+FROM (
+    SELECT DISTINCT ON (account_id) account_id, username FROM account_members
+    WHERE role = 'owner' AND accepted_at IS NOT NULL
+    ORDER BY account_id, accepted_at, username
+) AS co_owners
+WHERE accounts.id = co_owners.account_id AND accounts.owner = $1 AND accounts.closed_at IS NULL
+RETURNING accounts.*;
+
+-- name: LockAccounts :many
+-- Locks the accounts among the given ids, in order of id so that the transactions locking several
+-- accounts at once don't deadlock.
+SELECT id FROM accounts
+WHERE id = ANY(sqlc.arg(ids)::bigint[])
+ORDER BY id
+FOR NO KEY UPDATE;
+
+-- name: UpdateAccountProfile :one
+-- Changes the nickname or the tier of the account, the ones left null being kept, provided the account
+-- is still at the version, whatever its version when 0.
+UPDATE accounts
+SET
+    nickname = COALESCE(sqlc.narg(nickname), nickname),
+    tier = COALESCE(sqlc.narg(tier), tier),
+    version = version + 1
+WHERE id = sqlc.arg(id) AND (sqlc.arg(version)::bigint = 0 OR version = sqlc.arg(version))
+RETURNING *;
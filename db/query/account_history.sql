@@ -0,0 +1,27 @@
+-- name: CreateAccountVersion :one
+-- Opens a version of the account with its current values, the previous one having to be closed first.
+INSERT INTO account_history (
+    account_id,
+    owner,
+    currency,
+    status,
+    nickname,
+    tier
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: CloseAccountVersion :exec
+-- Closes the current version of the account as of the start of the transaction, when its values change
+-- or it is deleted.
+UPDATE account_history
+SET valid_to = now()
+WHERE account_id = $1 AND valid_to IS NULL;
+
+-- name: ListAccountHistory :many
+-- Lists the versions of the account, oldest first.
+SELECT * FROM account_history
+WHERE account_id = $1
+ORDER BY valid_from, id
+LIMIT $2
+OFFSET $3;
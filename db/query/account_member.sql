@@ -0,0 +1,39 @@
+-- name: CreateAccountMember :one
+-- Invites a user to share an account, the membership being pending until they accept it.
+INSERT INTO account_members (
+    account_id,
+    username,
+    role,
+    invited_by
+) VALUES (
+    $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetAccountMember :one
+SELECT * FROM account_members
+WHERE account_id = $1 AND username = $2 LIMIT 1;
+
+-- name: AcceptAccountMember :one
+-- Accepts the pending invitation of the user to the account.
+UPDATE account_members
+SET accepted_at = now()
+WHERE account_id = $1 AND username = $2 AND accepted_at IS NULL
+RETURNING *;
+
+-- name: ListAccountInvitations :many
+-- Lists the pending invitations of a user, oldest first.
+SELECT * FROM account_members
+WHERE username = $1 AND accepted_at IS NULL
+ORDER BY created_at, account_id
+LIMIT $2
+OFFSET $3;
+
+-- name: DeleteAccountMember :exec
+DELETE FROM account_members
+WHERE account_id = $1 AND username = $2;
+
+-- name: DeleteUserAccountMembers :exec
+-- Deletes the memberships of the user, and those of the closed accounts of the user, for the deletion of
+-- their data.
+DELETE FROM account_members
+WHERE username = $1 OR account_id IN (SELECT id FROM accounts WHERE owner = $1 AND closed_at IS NOT NULL);
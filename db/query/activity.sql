@@ -0,0 +1,54 @@
+-- name: AddAccountDailyActivity :exec
+INSERT INTO account_daily_activity (
+    account_id,
+    day,
+    credits,
+    debits,
+    transfer_count
+) VALUES (
+    $1, $2, $3, $4, 1
+) ON CONFLICT (account_id, day) DO UPDATE
+SET
+    credits = account_daily_activity.credits + EXCLUDED.credits,
+    debits = account_daily_activity.debits + EXCLUDED.debits,
+    transfer_count = account_daily_activity.transfer_count + 1;
+
+-- name: ListAccountDailyActivity :many
+SELECT * FROM account_daily_activity
+WHERE
+    account_id = sqlc.arg(account_id) AND
+    day >= sqlc.arg(from_day) AND
+    day <= sqlc.arg(to_day)
+ORDER BY day;
+
+-- name: AddAccountCounterpartyActivity :exec
+INSERT INTO account_counterparty_activity (
+    account_id,
+    counterparty_account_id,
+    counterparty_owner,
+    sent,
+    received,
+    transfer_count,
+    last_transfer_at
+) VALUES (
+    $1, $2, $3, $4, $5, 1, $6
+) ON CONFLICT (account_id, counterparty_account_id) DO UPDATE
+SET
+    sent = account_counterparty_activity.sent + EXCLUDED.sent,
+    received = account_counterparty_activity.received + EXCLUDED.received,
+    transfer_count = account_counterparty_activity.transfer_count + 1,
+    last_transfer_at = GREATEST(account_counterparty_activity.last_transfer_at, EXCLUDED.last_transfer_at);
+
+-- name: ListAccountCounterpartyActivity :many
+SELECT * FROM account_counterparty_activity
+WHERE account_id = sqlc.arg(account_id)
+ORDER BY transfer_count DESC, counterparty_account_id
+LIMIT sqlc.arg(row_limit);
+
+-- name: DeleteAccountDailyActivity :exec
+DELETE FROM account_daily_activity
+WHERE account_id = $1;
+
+-- name: DeleteAccountCounterpartyActivity :exec
+DELETE FROM account_counterparty_activity
+WHERE account_id = $1;
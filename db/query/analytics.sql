@@ -0,0 +1,53 @@
+-- name: AddRouteRequestVolume :exec
+INSERT INTO route_request_volumes (
+    route,
+    hour,
+    requests,
+    errors
+) VALUES (
+    $1, $2, $3, $4
+) ON CONFLICT (route, hour) DO UPDATE
+SET requests = route_request_volumes.requests + EXCLUDED.requests,
+    errors = route_request_volumes.errors + EXCLUDED.errors;
+
+-- name: ListRouteRequestVolumes :many
+SELECT
+    route,
+    sum(requests)::bigint AS requests,
+    sum(errors)::bigint AS errors,
+    max(requests)::bigint AS peak_hour_requests
+FROM route_request_volumes
+WHERE hour >= sqlc.arg(since)
+GROUP BY route
+ORDER BY requests DESC;
+
+-- name: ListRequestHeatmap :many
+SELECT
+    extract(isodow FROM hour AT TIME ZONE 'UTC')::int AS day_of_week,
+    extract(hour FROM hour AT TIME ZONE 'UTC')::int AS hour_of_day,
+    sum(requests)::bigint AS requests
+FROM route_request_volumes
+WHERE hour >= sqlc.arg(since)
+GROUP BY day_of_week, hour_of_day
+ORDER BY day_of_week, hour_of_day;
+
+-- name: ListTransferHeatmap :many
+SELECT
+    extract(isodow FROM created_at AT TIME ZONE 'UTC')::int AS day_of_week,
+    extract(hour FROM created_at AT TIME ZONE 'UTC')::int AS hour_of_day,
+    count(*) AS transfers,
+    sum(amount)::bigint AS amount
+FROM transfers
+WHERE created_at >= sqlc.arg(since)
+GROUP BY day_of_week, hour_of_day
+ORDER BY day_of_week, hour_of_day;
+
+-- name: ListDailyTransferVolumes :many
+SELECT
+    (created_at AT TIME ZONE 'UTC')::date AS day,
+    count(*) AS transfers,
+    sum(amount)::bigint AS amount
+FROM transfers
+WHERE created_at >= sqlc.arg(since)
+GROUP BY day
+ORDER BY day;
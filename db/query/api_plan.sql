@@ -0,0 +1,18 @@
+-- name: DeleteUserAPIPlan :exec
+DELETE FROM user_api_plans
+WHERE username = $1;
+
+-- name: GetUserAPIPlan :one
+SELECT * FROM user_api_plans
+WHERE username = $1 LIMIT 1;
+
+-- name: UpsertUserAPIPlan :one
+INSERT INTO user_api_plans (
+    username,
+    plan
+) VALUES (
+    $1, $2
+) ON CONFLICT (username) DO UPDATE
+SET plan = EXCLUDED.plan,
+    updated_at = now()
+RETURNING *;
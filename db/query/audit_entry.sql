@@ -0,0 +1,20 @@
+-- name: CreateAuditEntry :one
+INSERT INTO audit_entries (
+    username,
+    impersonator,
+    action,
+    detail,
+    status
+) VALUES (
+    $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: ListAuditEntries :many
+-- Lists the audit entries of the actions taken as a user or by an impersonator, newest first.
+SELECT * FROM audit_entries
+WHERE
+    (sqlc.narg(username)::varchar IS NULL OR username = sqlc.narg(username)) AND
+    (sqlc.narg(impersonator)::varchar IS NULL OR impersonator = sqlc.narg(impersonator))
+ORDER BY id DESC
+LIMIT sqlc.arg(row_limit)
+OFFSET sqlc.arg(row_offset);
@@ -0,0 +1,20 @@
+-- name: CreateBalanceSnapshot :exec
+INSERT INTO balance_snapshots (
+    account_id,
+    business_date,
+    balance
+) VALUES (
+    $1, $2, $3
+) ON CONFLICT (account_id, business_date) DO NOTHING;
+
+-- name: ListBalanceSnapshots :many
+SELECT * FROM balance_snapshots
+WHERE
+    account_id = sqlc.arg(account_id) AND
+    business_date >= sqlc.arg(from_date) AND
+    business_date <= sqlc.arg(to_date)
+ORDER BY business_date;
+
+-- name: GetBalanceSnapshot :one
+SELECT * FROM balance_snapshots
+WHERE account_id = $1 AND business_date = $2;
@@ -0,0 +1,34 @@
+-- name: LockBatchRun :one
+-- Creates the run of the batch for the business date if needed, and locks it until the end of the
+-- transaction so that concurrent runners process each account once.
+INSERT INTO batch_runs (
+    name,
+    business_date
+) VALUES (
+    $1, $2
+) ON CONFLICT (name, business_date) DO UPDATE
+SET name = EXCLUDED.name
+RETURNING *;
+
+-- name: UpdateBatchRunCheckpoint :exec
+UPDATE batch_runs
+SET
+    last_account_id = sqlc.arg(last_account_id),
+    processed_accounts = processed_accounts + sqlc.arg(processed_accounts),
+    updated_at = now()
+WHERE name = sqlc.arg(name) AND business_date = sqlc.arg(business_date);
+
+-- name: CompleteBatchRun :exec
+UPDATE batch_runs
+SET
+    completed_at = now(),
+    updated_at = now()
+WHERE name = $1 AND business_date = $2;
+
+-- name: GetLastCompletedBatchRun :one
+-- Returns the completed run of the batch with the latest business date, which the runs of the
+-- business dates missed since resume from.
+SELECT * FROM batch_runs
+WHERE name = $1 AND completed_at IS NOT NULL
+ORDER BY business_date DESC
+LIMIT 1;
@@ -0,0 +1,39 @@
+-- name: CreateBeneficiary :one
+INSERT INTO beneficiaries (
+    owner,
+    account_id,
+    nickname,
+    category
+) VALUES (
+    $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetBeneficiary :one
+SELECT * FROM beneficiaries
+WHERE id = $1 LIMIT 1;
+
+-- name: GetBeneficiaryByAccount :one
+-- Gets the beneficiary an owner saved an account as.
+SELECT * FROM beneficiaries
+WHERE owner = $1 AND account_id = $2 LIMIT 1;
+
+-- name: ListBeneficiaries :many
+-- Lists the beneficiaries saved by an owner by nickname.
+SELECT * FROM beneficiaries
+WHERE owner = $1
+ORDER BY nickname
+LIMIT $2
+OFFSET $3;
+
+-- name: UpdateBeneficiary :one
+UPDATE beneficiaries
+SET nickname = $2, category = $3
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteBeneficiary :exec
+DELETE FROM beneficiaries WHERE id = $1;
+
+-- name: DeleteOwnerBeneficiaries :exec
+-- Deletes the beneficiaries saved by the owner, for the deletion of their data.
+DELETE FROM beneficiaries WHERE owner = $1;
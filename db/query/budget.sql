@@ -0,0 +1,37 @@
+-- name: DeleteBudget :exec
+DELETE FROM budgets
+WHERE account_id = $1 AND category = $2;
+
+-- name: GetBudget :one
+SELECT * FROM budgets
+WHERE account_id = $1 AND category = $2 LIMIT 1;
+
+-- name: ListBudgets :many
+SELECT * FROM budgets
+WHERE account_id = $1
+ORDER BY category;
+
+-- name: SumCategorySpending :one
+-- Sums the transfers sent from an account to the beneficiaries an owner saved in a category, created
+-- between since and until included.
+SELECT coalesce(sum(transfers.amount), 0)::bigint AS spent
+FROM transfers
+JOIN beneficiaries ON beneficiaries.account_id = transfers.to_account_id
+WHERE
+    transfers.from_account_id = sqlc.arg(account_id) AND
+    beneficiaries.owner = sqlc.arg(owner) AND
+    beneficiaries.category = sqlc.arg(category) AND
+    transfers.created_at >= sqlc.arg(since) AND
+    transfers.created_at <= sqlc.arg(until);
+
+-- name: UpsertBudget :one
+INSERT INTO budgets (
+    account_id,
+    category,
+    monthly_limit
+) VALUES (
+    $1, $2, $3
+) ON CONFLICT (account_id, category) DO UPDATE
+SET monthly_limit = EXCLUDED.monthly_limit,
+    updated_at = now()
+RETURNING *;
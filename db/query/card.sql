@@ -0,0 +1,52 @@
+-- name: CreateCard :one
+INSERT INTO cards (
+    account_id,
+    owner,
+    last4,
+    expiry_month,
+    expiry_year
+) VALUES (
+    $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: GetCard :one
+SELECT * FROM cards
+WHERE id = $1 LIMIT 1;
+
+-- name: GetCardForUpdate :one
+SELECT * FROM cards
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: ListCards :many
+-- Lists the cards issued to a user, oldest first.
+SELECT * FROM cards
+WHERE owner = $1
+ORDER BY id
+LIMIT $2
+OFFSET $3;
+
+-- name: UpdateCardStatus :one
+UPDATE cards
+SET
+    status = $2,
+    updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: ListCardHolds :many
+-- Lists the holds placed by the authorizations of a card, newest first.
+SELECT * FROM holds
+WHERE card_id = $1
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: FreezeUserCards :execrows
+-- Freezes the active cards of the user and those of the closed accounts of the user, for the deletion of
+-- their data, returning how many were.
+UPDATE cards
+SET
+    status = 'frozen',
+    updated_at = now()
+WHERE status = 'active' AND (owner = $1 OR account_id IN (SELECT id FROM accounts WHERE owner = $1 AND closed_at IS NOT NULL));
@@ -0,0 +1,46 @@
+-- name: CreateChequeDeposit :one
+INSERT INTO cheque_deposits (
+    account_id,
+    owner,
+    amount,
+    currency,
+    cheque_number,
+    transfer_id,
+    hold_id,
+    clears_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8
+) RETURNING *;
+
+-- name: GetChequeDeposit :one
+SELECT * FROM cheque_deposits
+WHERE id = $1 LIMIT 1;
+
+-- name: GetChequeDepositForUpdate :one
+SELECT * FROM cheque_deposits
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: ListDueChequeDeposits :many
+-- Lists the pending cheque deposits whose clearing period is over, the oldest first.
+SELECT * FROM cheque_deposits
+WHERE status = 'pending' AND clears_at <= $1
+ORDER BY clears_at
+LIMIT $2;
+
+-- name: ClearChequeDeposit :one
+UPDATE cheque_deposits
+SET
+    status = 'cleared',
+    settled_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: BounceChequeDeposit :one
+UPDATE cheque_deposits
+SET
+    status = 'bounced',
+    reversal_transfer_id = $2,
+    settled_at = now()
+WHERE id = $1
+RETURNING *;
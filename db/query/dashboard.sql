@@ -0,0 +1,79 @@
+-- name: SumOwnerBalances :many
+-- Sums the balances of the open accounts of an owner by currency, the accounts shared with them being
+-- left out.
+SELECT
+    currency,
+    count(*) AS account_count,
+    sum(balance)::bigint AS balance,
+    sum(balance - held_balance)::bigint AS available_balance
+FROM accounts
+WHERE owner = $1 AND closed_at IS NULL
+GROUP BY currency
+ORDER BY currency;
+
+-- name: ListOwnerRecentEntries :many
+-- Lists the latest entries of the accounts of an owner, newest first, with their counterparty like
+-- ListEntriesWithCounterparty.
+SELECT
+    sqlc.embed(entries),
+    counterparty.id AS counterparty_account_id,
+    counterparty.owner AS counterparty_username,
+    users.full_name AS counterparty_full_name,
+    counterparty.nickname AS counterparty_nickname,
+    accounts.currency
+FROM entries
+JOIN accounts ON accounts.id = entries.account_id
+LEFT JOIN transfers ON transfers.id = entries.transfer_id
+LEFT JOIN accounts AS counterparty ON counterparty.id = CASE
+    WHEN transfers.from_account_id = entries.account_id THEN transfers.to_account_id
+    ELSE transfers.from_account_id
+END
+LEFT JOIN users ON users.username = counterparty.owner
+WHERE accounts.owner = $1
+ORDER BY entries.id DESC
+LIMIT $2;
+
+-- name: SumOwnerSpending :many
+-- Sums the amounts that left the accounts of an owner since a time by currency, positive. The transfers
+-- between the accounts of the owner aren't spending and are left out.
+SELECT
+    accounts.currency,
+    (-sum(entries.amount))::bigint AS spent
+FROM entries
+JOIN accounts ON accounts.id = entries.account_id
+LEFT JOIN transfers ON transfers.id = entries.transfer_id
+LEFT JOIN accounts AS counterparty ON counterparty.id = transfers.to_account_id
+WHERE
+    accounts.owner = sqlc.arg(owner) AND
+    entries.amount < 0 AND
+    entries.created_at >= sqlc.arg(since) AND
+    (counterparty.owner IS NULL OR counterparty.owner <> accounts.owner)
+GROUP BY accounts.currency
+ORDER BY accounts.currency;
+
+-- name: CountOwnerPendingItems :one
+-- Counts what awaits a user: their transfers awaiting approval, the payment requests they were sent, the
+-- invitations to accounts shared with them, their cheques still clearing and the holds reserving part of
+-- the balance of their accounts.
+SELECT
+    (
+        SELECT count(*) FROM pending_transfers
+        WHERE pending_transfers.owner = sqlc.arg(owner) AND pending_transfers.status = 'pending_approval'
+    ) AS pending_transfers,
+    (
+        SELECT count(*) FROM payment_requests
+        WHERE payment_requests.payer = sqlc.arg(owner) AND payment_requests.status = 'pending'
+    ) AS payment_requests,
+    (
+        SELECT count(*) FROM account_members
+        WHERE account_members.username = sqlc.arg(owner) AND account_members.accepted_at IS NULL
+    ) AS account_invitations,
+    (
+        SELECT count(*) FROM cheque_deposits
+        WHERE cheque_deposits.owner = sqlc.arg(owner) AND cheque_deposits.status = 'pending'
+    ) AS cheque_deposits,
+    (
+        SELECT count(*) FROM holds
+        JOIN accounts ON accounts.id = holds.account_id
+        WHERE accounts.owner = sqlc.arg(owner) AND holds.status = 'authorized'
+    ) AS holds;
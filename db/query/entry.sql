@@ -1,9 +1,20 @@
 -- name: CreateEntry :one
 INSERT INTO entries (
   account_id,
-  amount
+  amount,
+  transfer_id
 ) VALUES (
-  $1, $2
+  $1, $2, $3
+) RETURNING *;
+
+-- name: CreateHistoricalEntry :one
+-- Creates an entry made before it was recorded, e.g. imported from another system.
+INSERT INTO entries (
+  account_id,
+  amount,
+  created_at
+) VALUES (
+  $1, $2, $3
 ) RETURNING *;
 
 -- name: GetEntry :one
@@ -15,4 +26,144 @@ SELECT * FROM entries
 WHERE account_id = $1
 ORDER BY id
 LIMIT $2
-OFFSET $3;
\ No newline at end of file
+OFFSET $3;
+
+-- name: ListEntriesWithCounterparty :many
+-- Lists the entries of an account with the other account of the transfer each was made for, its
+-- nickname and its owner. The counterparty is null for the entries made without a transfer. Each entry comes with the
+-- currency of its account.
+SELECT
+    sqlc.embed(entries),
+    counterparty.id AS counterparty_account_id,
+    counterparty.owner AS counterparty_username,
+    users.full_name AS counterparty_full_name,
+    counterparty.nickname AS counterparty_nickname,
+    accounts.currency
+FROM entries
+JOIN accounts ON accounts.id = entries.account_id
+LEFT JOIN transfers ON transfers.id = entries.transfer_id
+LEFT JOIN accounts AS counterparty ON counterparty.id = CASE
+    WHEN transfers.from_account_id = entries.account_id THEN transfers.to_account_id
+    ELSE transfers.from_account_id
+END
+LEFT JOIN users ON users.username = counterparty.owner
+WHERE entries.account_id = $1
+ORDER BY entries.id
+LIMIT $2
+OFFSET $3;
+
+-- name: ListEntriesWithCounterpartyBefore :many
+-- Lists the entries of an account like ListEntriesWithCounterparty, newest first and a page at a time by
+-- their id: the page after a cursor, the id of the last entry of the previous page, holds the entries
+-- made before it. The first page, without a cursor, starts with the newest entry.
+SELECT
+    sqlc.embed(entries),
+    counterparty.id AS counterparty_account_id,
+    counterparty.owner AS counterparty_username,
+    users.full_name AS counterparty_full_name,
+    counterparty.nickname AS counterparty_nickname,
+    accounts.currency
+FROM entries
+JOIN accounts ON accounts.id = entries.account_id
+LEFT JOIN transfers ON transfers.id = entries.transfer_id
+LEFT JOIN accounts AS counterparty ON counterparty.id = CASE
+    WHEN transfers.from_account_id = entries.account_id THEN transfers.to_account_id
+    ELSE transfers.from_account_id
+END
+LEFT JOIN users ON users.username = counterparty.owner
+WHERE
+    entries.account_id = sqlc.arg(account_id) AND
+    (sqlc.narg(cursor)::bigint IS NULL OR entries.id < sqlc.narg(cursor))
+ORDER BY entries.id DESC
+LIMIT sqlc.arg(row_limit);
+
+-- name: ListEntriesForExport :many
+-- Lists the entries of an account made in a period, with the transfer each was made for and the other
+-- account of the transfer, for the personal finance files. The transfer is null for the entries made
+-- without one.
+SELECT
+    sqlc.embed(entries),
+    counterparty.id AS counterparty_account_id,
+    counterparty.owner AS counterparty_username,
+    users.full_name AS counterparty_full_name,
+    transfers.memo AS transfer_memo,
+    transfers.external_reference AS transfer_external_reference
+FROM entries
+LEFT JOIN transfers ON transfers.id = entries.transfer_id
+LEFT JOIN accounts AS counterparty ON counterparty.id = CASE
+    WHEN transfers.from_account_id = entries.account_id THEN transfers.to_account_id
+    ELSE transfers.from_account_id
+END
+LEFT JOIN users ON users.username = counterparty.owner
+WHERE
+    entries.account_id = sqlc.arg(account_id) AND
+    entries.created_at >= sqlc.arg(from_time) AND
+    entries.created_at < sqlc.arg(to_time)
+ORDER BY entries.id
+LIMIT sqlc.arg(row_limit);
+
+-- name: CountEntries :one
+SELECT count(*) FROM entries
+WHERE account_id = $1;
+
+-- name: ListEntriesInRange :many
+SELECT * FROM entries
+WHERE
+    account_id = sqlc.arg(account_id) AND
+    created_at >= sqlc.arg(from_time) AND
+    created_at < sqlc.arg(to_time)
+ORDER BY id
+LIMIT sqlc.arg(row_limit)
+OFFSET sqlc.arg(row_offset);
+
+-- name: CountEntriesInRange :one
+SELECT count(*) FROM entries
+WHERE
+    account_id = sqlc.arg(account_id) AND
+    created_at >= sqlc.arg(from_time) AND
+    created_at < sqlc.arg(to_time);
+
+-- name: SumEntriesSince :one
+SELECT COALESCE(SUM(amount), 0)::bigint FROM entries
+WHERE account_id = $1 AND created_at >= $2;
+
+-- name: SearchAccountEntries :many
+-- Searches the entries of an account made for transfers whose memo, counterparty or category match the
+-- web search query, best matches first. The counterparty is the other account of the transfer, matched
+-- by the name and username of its owner, and the category the one the owner of the account gave it as a
+-- beneficiary, for the transfers sent to it. The highlights wrap the matched words of each field in
+-- <mark> tags, the text of the field being HTML escaped, and are empty for the fields without a match.
+SELECT
+    sqlc.embed(entries),
+    sqlc.embed(transfers),
+    counterparty.owner AS counterparty_username,
+    users.full_name AS counterparty_full_name,
+    counterparty.nickname AS counterparty_nickname,
+    COALESCE(beneficiaries.category, '') AS category,
+    accounts.currency,
+    CASE WHEN document.memo @@ search THEN ts_headline('simple', html_escape(transfers.memo), search, 'StartSel=<mark>, StopSel=</mark>') ELSE '' END::text AS memo_highlight,
+    CASE WHEN document.counterparty @@ search THEN ts_headline('simple', html_escape(users.full_name || ' (' || counterparty.owner || ')'), search, 'StartSel=<mark>, StopSel=</mark>, HighlightAll=true') ELSE '' END::text AS counterparty_highlight,
+    CASE WHEN document.category @@ search THEN ts_headline('simple', html_escape(beneficiaries.category), search, 'StartSel=<mark>, StopSel=</mark>, HighlightAll=true') ELSE '' END::text AS category_highlight
+FROM entries
+CROSS JOIN websearch_to_tsquery('simple', sqlc.arg(query)) AS search
+JOIN accounts ON accounts.id = entries.account_id
+JOIN transfers ON transfers.id = entries.transfer_id
+JOIN accounts AS counterparty ON counterparty.id = CASE
+    WHEN transfers.from_account_id = entries.account_id THEN transfers.to_account_id
+    ELSE transfers.from_account_id
+END
+JOIN users ON users.username = counterparty.owner
+LEFT JOIN beneficiaries ON beneficiaries.owner = accounts.owner
+    AND beneficiaries.account_id = counterparty.id
+    AND transfers.from_account_id = entries.account_id
+CROSS JOIN LATERAL (
+    SELECT
+        to_tsvector('simple', transfers.memo) AS memo,
+        to_tsvector('simple', users.full_name || ' ' || counterparty.owner) AS counterparty,
+        to_tsvector('simple', COALESCE(beneficiaries.category, '')) AS category
+) AS document
+WHERE entries.account_id = sqlc.arg(account_id)
+    AND (document.memo @@ search OR document.counterparty @@ search OR document.category @@ search)
+ORDER BY ts_rank(document.memo || document.counterparty || document.category, search) DESC, entries.id DESC
+LIMIT sqlc.arg(row_limit)
+OFFSET sqlc.arg(row_offset);
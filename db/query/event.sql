@@ -0,0 +1,43 @@
+-- name: CreateEvent :one
+INSERT INTO events (
+    type,
+    payload
+) VALUES (
+    $1, $2
+) RETURNING *;
+
+-- name: ListEventsAfter :many
+-- Events are only listed once they have settled, recorded before settled_before: ids are allocated when
+-- the event is inserted but become visible at commit, so a recent event may still be followed by a
+-- smaller id.
+SELECT * FROM events
+WHERE id > sqlc.arg(id)
+AND created_at < sqlc.arg(settled_before)
+ORDER BY id
+LIMIT sqlc.arg(row_limit);
+
+-- name: LockProjectionCheckpoint :one
+INSERT INTO projection_checkpoints (
+    name
+) VALUES (
+    $1
+) ON CONFLICT (name) DO UPDATE
+SET name = EXCLUDED.name
+RETURNING last_event_id;
+
+-- name: UpdateProjectionCheckpoint :exec
+UPDATE projection_checkpoints
+SET
+    last_event_id = $2,
+    updated_at = now()
+WHERE name = $1;
+
+-- name: ScrubUserEvents :exec
+-- Removes the personal data of the user from their events, for the projections replaying them to not
+-- bring it back.
+UPDATE events
+SET payload = CASE type
+    WHEN 'user.created' THEN payload || jsonb_build_object('full_name', 'Deleted user', 'email', $1::varchar || '@deleted.invalid')
+    ELSE payload || jsonb_build_object('user_agent', '', 'client_ip', '')
+END
+WHERE type IN ('user.created', 'session.new_device') AND payload->>'username' = $1;
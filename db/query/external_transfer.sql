@@ -0,0 +1,49 @@
+-- name: CreateExternalTransfer :one
+INSERT INTO external_transfers (
+    owner,
+    account_id,
+    rail,
+    amount,
+    currency,
+    routing_number,
+    account_number,
+    beneficiary_name,
+    transfer_id
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
+) RETURNING *;
+
+-- name: GetExternalTransfer :one
+SELECT * FROM external_transfers
+WHERE id = $1 LIMIT 1;
+
+-- name: GetExternalTransferForUpdate :one
+SELECT * FROM external_transfers
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: ListExternalTransfers :many
+-- Lists the external transfers sent by a user, oldest first.
+SELECT * FROM external_transfers
+WHERE owner = $1
+ORDER BY id
+LIMIT $2
+OFFSET $3;
+
+-- name: UpdateExternalTransferStatus :one
+UPDATE external_transfers
+SET
+    status = $2,
+    failure_reason = $3,
+    return_transfer_id = $4,
+    updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: ListStaleExternalTransfers :many
+-- Lists the external transfers still initiated or processing that didn't move since before updated_at,
+-- after the id, in order of id.
+SELECT * FROM external_transfers
+WHERE status IN ('initiated', 'processing') AND updated_at < $1 AND id > $2
+ORDER BY id
+LIMIT $3;
@@ -0,0 +1,57 @@
+-- name: CreateHold :one
+INSERT INTO holds (
+    card_id,
+    account_id,
+    amount,
+    currency,
+    memo,
+    expires_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: GetHold :one
+SELECT * FROM holds
+WHERE id = $1 LIMIT 1;
+
+-- name: GetHoldForUpdate :one
+SELECT * FROM holds
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: ListAccountHolds :many
+-- Lists the holds placed on an account, newest first.
+SELECT * FROM holds
+WHERE account_id = $1
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: CaptureHold :one
+UPDATE holds
+SET
+    status = 'captured',
+    captured_amount = $2,
+    transfer_id = $3,
+    settled_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: ReleaseHold :one
+-- Releases an authorized hold, returning no row once it was captured, released or expired.
+UPDATE holds
+SET
+    status = 'released',
+    settled_at = now()
+WHERE id = $1 AND status = 'authorized'
+RETURNING *;
+
+-- name: ExpireHolds :many
+-- Expires the authorized holds past their expiry, returning them so that the held balance of their
+-- accounts can be released.
+UPDATE holds
+SET
+    status = 'expired',
+    settled_at = now()
+WHERE status = 'authorized' AND expires_at <= $1
+RETURNING *;
@@ -0,0 +1,14 @@
+-- name: GetInterestAccrual :one
+SELECT * FROM interest_accruals
+WHERE account_id = $1 LIMIT 1;
+
+-- name: SetInterestAccrual :exec
+INSERT INTO interest_accruals (
+    account_id,
+    remainder
+) VALUES (
+    $1, $2
+) ON CONFLICT (account_id) DO UPDATE
+SET
+    remainder = EXCLUDED.remainder,
+    updated_at = now();
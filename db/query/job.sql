@@ -0,0 +1,53 @@
+-- name: CreateJob :one
+INSERT INTO jobs (
+    id,
+    username,
+    kind,
+    params
+) VALUES (
+    $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetJob :one
+SELECT * FROM jobs
+WHERE id = $1 LIMIT 1;
+
+-- name: UpdateJobProgress :one
+UPDATE jobs
+SET
+    status = 'running',
+    progress = sqlc.arg(progress),
+    updated_at = now()
+WHERE id = sqlc.arg(id) AND status IN ('pending', 'running')
+RETURNING *;
+
+-- name: CompleteJob :one
+UPDATE jobs
+SET
+    status = 'succeeded',
+    progress = 100,
+    result = sqlc.arg(result),
+    result_content_type = sqlc.arg(result_content_type),
+    updated_at = now(),
+    completed_at = now()
+WHERE id = sqlc.arg(id) AND status = 'running'
+RETURNING *;
+
+-- name: FailJob :one
+UPDATE jobs
+SET
+    status = 'failed',
+    error = sqlc.arg(error),
+    updated_at = now(),
+    completed_at = now()
+WHERE id = sqlc.arg(id) AND status IN ('pending', 'running')
+RETURNING *;
+
+-- name: CancelJob :one
+UPDATE jobs
+SET
+    status = 'cancelled',
+    updated_at = now(),
+    completed_at = now()
+WHERE id = $1 AND status IN ('pending', 'running')
+RETURNING *;
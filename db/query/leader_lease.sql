@@ -0,0 +1,28 @@
+-- name: AcquireLeaderLease :one
+-- Takes the lease when it is free or expired, or renews it for its holder. No row is returned while
+-- another instance holds it.
+INSERT INTO leader_leases (
+    name,
+    holder,
+    address,
+    expires_at
+) VALUES (
+    $1, $2, $3, now() + sqlc.arg(duration_ms)::bigint * interval '1 millisecond'
+) ON CONFLICT (name) DO UPDATE SET
+    holder = EXCLUDED.holder,
+    address = EXCLUDED.address,
+    expires_at = EXCLUDED.expires_at,
+    acquired_at = CASE
+        WHEN leader_leases.holder = EXCLUDED.holder THEN leader_leases.acquired_at
+        ELSE now()
+    END
+WHERE leader_leases.holder = EXCLUDED.holder OR leader_leases.expires_at < now()
+RETURNING *;
+
+-- name: GetLeaderLease :one
+SELECT * FROM leader_leases
+WHERE name = $1 LIMIT 1;
+
+-- name: ReleaseLeaderLease :exec
+DELETE FROM leader_leases
+WHERE name = $1 AND holder = $2;
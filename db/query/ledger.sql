@@ -0,0 +1,53 @@
+-- name: ListAccountBalanceDiscrepancies :many
+-- Lists the accounts whose balance differs from the sum of their entries.
+SELECT
+    accounts.id AS account_id,
+    accounts.balance,
+    COALESCE(SUM(entries.amount), 0)::bigint AS entries_total
+FROM accounts
+LEFT JOIN entries ON entries.account_id = accounts.id
+GROUP BY accounts.id
+HAVING accounts.balance <> COALESCE(SUM(entries.amount), 0)
+ORDER BY accounts.id;
+
+-- name: ListTransferImbalances :many
+-- Lists the transfers whose entries don't net to zero.
+SELECT
+    transfer_id::bigint AS transfer_id,
+    SUM(amount)::bigint AS entries_total
+FROM entries
+WHERE transfer_id IS NOT NULL
+GROUP BY transfer_id
+HAVING SUM(amount) <> 0
+ORDER BY transfer_id;
+
+-- name: UpsertLedgerAnomaly :one
+-- Records an anomaly, or updates it when it was already found, reopening it if it was resolved.
+INSERT INTO ledger_anomalies (
+    kind,
+    subject_id,
+    expected,
+    actual
+) VALUES (
+    $1, $2, $3, $4
+) ON CONFLICT (kind, subject_id) DO UPDATE SET
+    expected = EXCLUDED.expected,
+    actual = EXCLUDED.actual,
+    last_detected_at = now(),
+    resolved_at = NULL
+RETURNING *;
+
+-- name: ResolveLedgerAnomalies :execrows
+-- Resolves the open anomalies that weren't found again by the reconciliation of the current
+-- transaction, in which now() doesn't change.
+UPDATE ledger_anomalies
+SET resolved_at = now()
+WHERE resolved_at IS NULL AND last_detected_at < now();
+
+-- name: ListLedgerAnomalies :many
+-- Lists the anomalies, optionally only the open ones, the last detected first.
+SELECT * FROM ledger_anomalies
+WHERE NOT sqlc.arg(only_open)::bool OR resolved_at IS NULL
+ORDER BY last_detected_at DESC, id DESC
+LIMIT sqlc.arg(row_limit)
+OFFSET sqlc.arg(row_offset);
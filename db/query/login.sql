@@ -0,0 +1,47 @@
+-- name: CreateLoginFailure :exec
+INSERT INTO login_failures (
+    username,
+    client_ip
+) VALUES (
+    $1, $2
+);
+
+-- name: CountLoginFailures :one
+-- Counts the failed logins of a user from a client since a time.
+SELECT count(*) FROM login_failures
+WHERE username = sqlc.arg(username) AND client_ip = sqlc.arg(client_ip) AND created_at > sqlc.arg(since);
+
+-- name: CountClientLoginFailures :one
+-- Counts the failed logins from a client since a time, whatever the user.
+SELECT count(*) FROM login_failures
+WHERE client_ip = sqlc.arg(client_ip) AND created_at > sqlc.arg(since);
+
+-- name: DeleteLoginFailures :execrows
+DELETE FROM login_failures
+WHERE username = $1;
+
+-- name: DeleteClientLoginFailures :execrows
+DELETE FROM login_failures
+WHERE username = $1 AND client_ip = $2;
+
+-- name: GetLoginLockout :one
+SELECT * FROM login_lockouts
+WHERE username = $1 AND client_ip = $2 LIMIT 1;
+
+-- name: UpsertLoginLockout :one
+INSERT INTO login_lockouts (
+    username,
+    failures,
+    client_ip,
+    locked_until
+) VALUES (
+    $1, $2, $3, $4
+) ON CONFLICT (username, client_ip) DO UPDATE
+SET failures = EXCLUDED.failures,
+    locked_until = EXCLUDED.locked_until,
+    locked_at = now()
+RETURNING *;
+
+-- name: DeleteLoginLockouts :execrows
+DELETE FROM login_lockouts
+WHERE username = $1;
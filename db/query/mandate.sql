@@ -0,0 +1,57 @@
+-- name: CreateMandate :one
+INSERT INTO mandates (
+    payer,
+    from_account_id,
+    merchant_account_id,
+    max_amount,
+    cap_amount
+) VALUES (
+    $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: GetMandate :one
+SELECT * FROM mandates
+WHERE id = $1 LIMIT 1;
+
+-- name: GetMandateForUpdate :one
+SELECT * FROM mandates
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: ListMandates :many
+-- Lists the mandates a payer granted, oldest first.
+SELECT * FROM mandates
+WHERE payer = $1
+ORDER BY id
+LIMIT $2
+OFFSET $3;
+
+-- name: RecordMandatePull :one
+UPDATE mandates
+SET
+    pulled_amount = pulled_amount + sqlc.arg(amount),
+    last_pulled_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: RevokeMandate :one
+-- Revokes the mandate provided it is still active, no row being returned otherwise.
+UPDATE mandates
+SET
+    status = 'revoked',
+    revoked_at = now()
+WHERE id = $1 AND status = 'active'
+RETURNING *;
+
+-- name: RevokeUserMandates :execrows
+-- Revokes the active mandates given by the user and those from or to the closed accounts of the user,
+-- for the deletion of their data, returning how many were.
+UPDATE mandates
+SET
+    status = 'revoked',
+    revoked_at = now()
+WHERE status = 'active' AND (
+    payer = $1
+    OR from_account_id IN (SELECT id FROM accounts WHERE owner = $1 AND closed_at IS NOT NULL)
+    OR merchant_account_id IN (SELECT id FROM accounts WHERE owner = $1 AND closed_at IS NOT NULL)
+);
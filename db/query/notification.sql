@@ -0,0 +1,30 @@
+-- name: CreateNotification :exec
+-- Notifications are projected from events, a replayed event doesn't notify the user twice.
+INSERT INTO notifications (
+    username,
+    event_id,
+    type,
+    payload,
+    created_at
+) VALUES (
+    $1, $2, $3, $4, $5
+) ON CONFLICT (event_id, type) DO NOTHING;
+
+-- name: ListNotifications :many
+SELECT * FROM notifications
+WHERE username = $1
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3;
+
+-- name: MarkNotificationRead :one
+-- A notification read earlier keeps the time it was first read.
+UPDATE notifications
+SET read_at = COALESCE(read_at, now())
+WHERE id = $1 AND username = $2
+RETURNING *;
+
+-- name: MarkAllNotificationsRead :execrows
+UPDATE notifications
+SET read_at = now()
+WHERE username = $1 AND read_at IS NULL;
@@ -0,0 +1,43 @@
+-- name: CreateNotificationDelivery :exec
+-- A replayed event doesn't send the notification twice.
+INSERT INTO notification_deliveries (
+    username,
+    event_id,
+    type,
+    channel,
+    destination,
+    payload,
+    created_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+) ON CONFLICT (event_id, type, channel) DO NOTHING;
+
+-- name: ClaimNotificationDeliveries :many
+-- The claimed deliveries are pushed back until the lease expires, so that concurrent dispatchers don't
+-- send them too and a crashed dispatcher's are sent again afterwards.
+UPDATE notification_deliveries
+SET next_attempt_at = sqlc.arg(lease_until)
+WHERE id IN (
+    SELECT id FROM notification_deliveries
+    WHERE status = 'pending' AND next_attempt_at <= now()
+    ORDER BY next_attempt_at
+    LIMIT sqlc.arg(row_limit)
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: MarkNotificationDelivered :exec
+UPDATE notification_deliveries
+SET status = 'delivered',
+    attempts = attempts + 1,
+    last_error = '',
+    delivered_at = now()
+WHERE id = $1;
+
+-- name: RecordNotificationDeliveryFailure :exec
+UPDATE notification_deliveries
+SET status = sqlc.arg(status),
+    attempts = attempts + 1,
+    last_error = sqlc.arg(last_error),
+    next_attempt_at = sqlc.arg(next_attempt_at)
+WHERE id = sqlc.arg(id);
@@ -0,0 +1,24 @@
+-- name: GetNotificationPreferences :one
+SELECT * FROM notification_preferences
+WHERE username = $1 LIMIT 1;
+
+-- name: UpsertNotificationPreferences :one
+INSERT INTO notification_preferences (
+    username,
+    in_app,
+    email,
+    webhook_url,
+    low_balance_threshold
+) VALUES (
+    $1, $2, $3, $4, $5
+) ON CONFLICT (username) DO UPDATE
+SET in_app = EXCLUDED.in_app,
+    email = EXCLUDED.email,
+    webhook_url = EXCLUDED.webhook_url,
+    low_balance_threshold = EXCLUDED.low_balance_threshold,
+    updated_at = now()
+RETURNING *;
+
+-- name: DeleteNotificationPreferences :exec
+-- Deletes the preferences of the user, their webhook included.
+DELETE FROM notification_preferences WHERE username = $1;
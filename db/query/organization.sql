@@ -0,0 +1,28 @@
+-- name: CreateOrganization :one
+INSERT INTO organizations (
+    slug,
+    name
+) VALUES (
+    $1, $2
+) RETURNING *;
+
+-- name: GetOrganization :one
+SELECT * FROM organizations
+WHERE id = $1 LIMIT 1;
+
+-- name: GetOrganizationBySlug :one
+SELECT * FROM organizations
+WHERE slug = $1 LIMIT 1;
+
+-- name: ListOrganizationUsers :many
+SELECT * FROM users
+WHERE org_id = $1
+ORDER BY username
+LIMIT $2
+OFFSET $3;
+
+-- name: UpdateOrganizationApprovalThreshold :one
+UPDATE organizations
+SET approval_threshold = $2
+WHERE id = $1
+RETURNING *;
@@ -0,0 +1,103 @@
+-- name: UpsertUserOverview :exec
+INSERT INTO user_overview (
+    username,
+    full_name,
+    email,
+    created_at
+) VALUES (
+    $1, $2, $3, $4
+) ON CONFLICT (username) DO UPDATE
+SET
+    full_name = EXCLUDED.full_name,
+    email = EXCLUDED.email;
+
+-- name: AddUserOverviewAccountCount :exec
+UPDATE user_overview
+SET account_count = account_count + sqlc.arg(delta)
+WHERE username = sqlc.arg(username);
+
+-- name: TouchUserOverview :exec
+UPDATE user_overview
+SET last_activity_at = sqlc.arg(activity_at)
+WHERE username = (
+    SELECT owner FROM account_overview
+    WHERE account_id = sqlc.arg(account_id)
+);
+
+-- name: GetUserOverview :one
+SELECT * FROM user_overview
+WHERE username = $1 LIMIT 1;
+
+-- name: ListUserOverviews :many
+SELECT * FROM user_overview
+ORDER BY username
+LIMIT $1
+OFFSET $2;
+
+-- name: UpsertAccountOverview :exec
+INSERT INTO account_overview (
+    account_id,
+    owner,
+    currency,
+    balance,
+    created_at
+) VALUES (
+    $1, $2, $3, $4, $5
+) ON CONFLICT (account_id) DO UPDATE
+SET balance = EXCLUDED.balance;
+
+-- name: SetAccountOverviewBalance :exec
+UPDATE account_overview
+SET balance = $2
+WHERE account_id = $1;
+
+-- name: RecordAccountOverviewTransfer :exec
+UPDATE account_overview
+SET
+    balance = sqlc.arg(balance),
+    transfer_count = transfer_count + 1,
+    last_activity_at = sqlc.arg(activity_at)
+WHERE account_id = sqlc.arg(account_id);
+
+-- name: DeleteAccountOverview :one
+DELETE FROM account_overview
+WHERE account_id = $1
+RETURNING owner;
+
+-- name: ListAccountOverviews :many
+SELECT * FROM account_overview
+WHERE sqlc.narg(owner)::varchar IS NULL OR owner = sqlc.narg(owner)
+ORDER BY volume_30d DESC, account_id
+LIMIT sqlc.arg(row_limit)
+OFFSET sqlc.arg(row_offset);
+
+-- name: AddAccountDailyVolume :exec
+INSERT INTO account_daily_volume (
+    account_id,
+    day,
+    volume
+) VALUES (
+    $1, $2, $3
+) ON CONFLICT (account_id, day) DO UPDATE
+SET volume = account_daily_volume.volume + EXCLUDED.volume;
+
+-- name: RefreshAccountOverviewVolume :exec
+UPDATE account_overview
+SET volume_30d = COALESCE((
+    SELECT SUM(volume) FROM account_daily_volume
+    WHERE account_daily_volume.account_id = account_overview.account_id
+    AND day > CURRENT_DATE - 30
+), 0)::bigint
+WHERE sqlc.narg(account_id)::bigint IS NULL OR account_id = sqlc.narg(account_id);
+
+-- name: DeleteStaleAccountDailyVolume :exec
+DELETE FROM account_daily_volume
+WHERE day <= CURRENT_DATE - 30;
+
+-- name: AnonymizeUserOverview :exec
+-- Replaces the personal data of the user in their overview, like `AnonymizeUser`.
+UPDATE user_overview
+SET
+    full_name = 'Deleted user',
+    email = username || '@deleted.invalid'
+WHERE username = $1;
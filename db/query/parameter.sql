@@ -0,0 +1,36 @@
+-- name: CreateBankParameter :one
+-- Publishes the next version of the parameter.
+INSERT INTO bank_parameters (
+    name,
+    version,
+    value,
+    effective_at,
+    published_by
+) VALUES (
+    sqlc.arg(name),
+    (SELECT COALESCE(MAX(version), 0) + 1 FROM bank_parameters WHERE name = sqlc.arg(name)),
+    sqlc.arg(value),
+    sqlc.arg(effective_at),
+    sqlc.arg(published_by)
+) RETURNING *;
+
+-- name: GetActiveBankParameter :one
+-- Returns the version of the parameter in effect at the given time, the latest published one when
+-- several versions take effect at the same time.
+SELECT * FROM bank_parameters
+WHERE name = sqlc.arg(name) AND effective_at <= sqlc.arg(at)
+ORDER BY effective_at DESC, version DESC
+LIMIT 1;
+
+-- name: ListActiveBankParameters :many
+SELECT DISTINCT ON (name) * FROM bank_parameters
+WHERE effective_at <= sqlc.arg(at)
+ORDER BY name, effective_at DESC, version DESC;
+
+-- name: ListBankParameterVersions :many
+-- Lists every version of the parameters, or of a single one, the latest first.
+SELECT * FROM bank_parameters
+WHERE sqlc.narg(name)::varchar IS NULL OR name = sqlc.narg(name)
+ORDER BY name, version DESC
+LIMIT sqlc.arg(row_limit)
+OFFSET sqlc.arg(row_offset);
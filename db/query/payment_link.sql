@@ -0,0 +1,58 @@
+-- name: CreatePaymentLink :one
+INSERT INTO payment_links (
+    code,
+    merchant,
+    to_account_id,
+    amount,
+    currency,
+    memo,
+    expires_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+) RETURNING *;
+
+-- name: GetPaymentLinkByCode :one
+SELECT * FROM payment_links
+WHERE code = $1 LIMIT 1;
+
+-- name: GetPaymentLinkForUpdate :one
+SELECT * FROM payment_links
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: PayPaymentLink :one
+UPDATE payment_links
+SET
+    status = 'paid',
+    payer = sqlc.narg(payer),
+    from_account_id = sqlc.narg(from_account_id),
+    transfer_id = sqlc.narg(transfer_id),
+    closed_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: CancelPaymentLink :one
+-- Cancels the link provided it is still active, no row being returned otherwise.
+UPDATE payment_links
+SET
+    status = 'cancelled',
+    closed_at = now()
+WHERE id = $1 AND status = 'active'
+RETURNING *;
+
+-- name: ExpirePaymentLinks :execrows
+-- Expires the active links past their expiry, returning how many were.
+UPDATE payment_links
+SET
+    status = 'expired',
+    closed_at = now()
+WHERE status = 'active' AND expires_at <= $1;
+
+-- name: CancelUserPaymentLinks :execrows
+-- Cancels the active links of the merchant and those to the closed accounts of the merchant, for the
+-- deletion of their data, returning how many were.
+UPDATE payment_links
+SET
+    status = 'cancelled',
+    closed_at = now()
+WHERE status = 'active' AND (merchant = $1 OR to_account_id IN (SELECT id FROM accounts WHERE owner = $1 AND closed_at IS NOT NULL));
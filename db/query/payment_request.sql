@@ -0,0 +1,57 @@
+-- name: CreatePaymentRequest :one
+INSERT INTO payment_requests (
+    requester,
+    payer,
+    to_account_id,
+    amount,
+    memo,
+    expires_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: GetPaymentRequest :one
+SELECT * FROM payment_requests
+WHERE id = $1 LIMIT 1;
+
+-- name: GetPaymentRequestForUpdate :one
+SELECT * FROM payment_requests
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: ListPaymentRequests :many
+-- Lists the payment requests sent by a requester or received by a payer, oldest first, optionally only
+-- those with a status.
+SELECT * FROM payment_requests
+WHERE
+    (sqlc.narg(requester)::varchar IS NULL OR requester = sqlc.narg(requester)) AND
+    (sqlc.narg(payer)::varchar IS NULL OR payer = sqlc.narg(payer)) AND
+    (sqlc.narg(status)::varchar IS NULL OR status = sqlc.narg(status))
+ORDER BY id
+LIMIT sqlc.arg(row_limit)
+OFFSET sqlc.arg(row_offset);
+
+-- name: AcceptPaymentRequest :one
+UPDATE payment_requests
+SET
+    status = 'accepted',
+    from_account_id = sqlc.narg(from_account_id),
+    transfer_id = sqlc.narg(transfer_id),
+    decided_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: DeclinePaymentRequest :one
+-- Declines the request provided it is still pending, no row being returned otherwise.
+UPDATE payment_requests
+SET
+    status = 'declined',
+    decided_at = now()
+WHERE id = $1 AND status = 'pending'
+RETURNING *;
+
+-- name: ExpirePaymentRequests :execrows
+-- Expires the pending requests past their expiry, returning how many were.
+UPDATE payment_requests
+SET status = 'expired'
+WHERE status = 'pending' AND expires_at <= $1;
@@ -0,0 +1,66 @@
+-- name: CreatePendingTransfer :one
+INSERT INTO pending_transfers (
+    owner,
+    from_account_id,
+    to_account_id,
+    amount,
+    memo,
+    external_reference,
+    expires_at,
+    org_id
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8
+) RETURNING *;
+
+-- name: GetPendingTransfer :one
+SELECT * FROM pending_transfers
+WHERE id = $1 LIMIT 1;
+
+-- name: GetPendingTransferForUpdate :one
+SELECT * FROM pending_transfers
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: ListPendingTransfers :many
+-- Lists the pending transfers, oldest first, optionally only those of an owner or with a status.
+SELECT * FROM pending_transfers
+WHERE
+    (sqlc.narg(owner)::varchar IS NULL OR owner = sqlc.narg(owner)) AND
+    (sqlc.narg(status)::varchar IS NULL OR status = sqlc.narg(status))
+ORDER BY id
+LIMIT sqlc.arg(row_limit)
+OFFSET sqlc.arg(row_offset);
+
+-- name: ApprovePendingTransfer :one
+UPDATE pending_transfers
+SET
+    status = 'approved',
+    decided_by = sqlc.arg(decided_by)::varchar,
+    transfer_id = sqlc.narg(transfer_id),
+    decided_at = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: RejectPendingTransfer :one
+-- Rejects the transfer provided it still awaits approval, no row being returned otherwise.
+UPDATE pending_transfers
+SET
+    status = 'rejected',
+    decided_by = sqlc.arg(decided_by)::varchar,
+    decided_at = now()
+WHERE id = sqlc.arg(id) AND status = 'pending_approval'
+RETURNING *;
+
+-- name: ExpirePendingTransfers :execrows
+-- Expires the transfers awaiting approval past their expiry, returning how many were.
+UPDATE pending_transfers
+SET status = 'expired'
+WHERE status = 'pending_approval' AND expires_at <= $1;
+
+-- name: ListOrganizationPendingTransfers :many
+-- Lists the transfers awaiting the approval of the admins of an organization, oldest first.
+SELECT * FROM pending_transfers
+WHERE org_id = $1 AND status = 'pending_approval' AND expires_at > now()
+ORDER BY id
+LIMIT $2
+OFFSET $3;
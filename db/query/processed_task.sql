@@ -0,0 +1,12 @@
+-- name: CreateProcessedTask :exec
+INSERT INTO processed_tasks (
+    id,
+    type
+) VALUES (
+    $1, $2
+) ON CONFLICT (id) DO NOTHING;
+
+-- name: IsTaskProcessed :one
+SELECT EXISTS (
+    SELECT 1 FROM processed_tasks WHERE id = $1
+) AS processed;
@@ -0,0 +1,17 @@
+-- name: CreateQueuedTransfer :one
+INSERT INTO queued_transfers (
+    id,
+    owner,
+    status,
+    transfer_id,
+    review_id,
+    pending_transfer_id,
+    error,
+    queued_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8
+) RETURNING *;
+
+-- name: GetQueuedTransfer :one
+SELECT * FROM queued_transfers
+WHERE id = $1 LIMIT 1;
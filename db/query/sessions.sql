@@ -13,4 +13,21 @@ INSERT INTO sessions (
 
 -- name: GetSession :one
 SELECT * FROM sessions
-WHERE id = $1 LIMIT 1;
\ No newline at end of file
+WHERE id = $1 LIMIT 1;
+
+-- name: TouchSession :exec
+UPDATE sessions
+SET last_used_at = GREATEST(last_used_at, sqlc.arg(last_used_at))
+WHERE id = sqlc.arg(id);
+
+-- name: CountUserSessions :one
+-- Counts the sessions of the user, along with those opened from the client with the user agent.
+SELECT
+    count(*) AS sessions,
+    count(*) FILTER (WHERE user_agent = sqlc.arg(user_agent)) AS device_sessions
+FROM sessions
+WHERE username = sqlc.arg(username);
+
+-- name: DeleteUserSessions :exec
+-- Deletes the sessions of the user, their refresh tokens no longer renewing access tokens.
+DELETE FROM sessions WHERE username = $1;
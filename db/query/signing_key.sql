@@ -0,0 +1,32 @@
+-- name: CountActiveSigningKeys :one
+SELECT count(*) FROM signing_keys
+WHERE username = $1 AND revoked_at IS NULL;
+
+-- name: CreateSigningKey :one
+INSERT INTO signing_keys (
+    username,
+    secret
+) VALUES (
+    $1, $2
+) RETURNING *;
+
+-- name: GetSigningKey :one
+SELECT * FROM signing_keys
+WHERE id = $1 LIMIT 1;
+
+-- name: ListSigningKeys :many
+-- Lists the signing keys of a user, revoked ones included, oldest first.
+SELECT * FROM signing_keys
+WHERE username = $1
+ORDER BY id;
+
+-- name: RevokeSigningKey :one
+-- Revokes the signing key of the user provided it is still active, no row being returned otherwise.
+UPDATE signing_keys
+SET revoked_at = now()
+WHERE id = $1 AND username = $2 AND revoked_at IS NULL
+RETURNING *;
+
+-- name: DeleteUserSigningKeys :exec
+-- Deletes the signing keys of the user, revoked ones included.
+DELETE FROM signing_keys WHERE username = $1;
@@ -0,0 +1,73 @@
+-- name: CreateStandingOrder :one
+INSERT INTO standing_orders (
+    owner,
+    from_account_id,
+    to_account_id,
+    amount,
+    currency,
+    memo,
+    frequency,
+    starts_at,
+    next_run_at
+) VALUES (
+    sqlc.arg(owner), sqlc.arg(from_account_id), sqlc.arg(to_account_id), sqlc.arg(amount), sqlc.arg(currency),
+    sqlc.arg(memo), sqlc.arg(frequency), sqlc.arg(starts_at), sqlc.arg(starts_at)
+) RETURNING *;
+
+-- name: GetStandingOrder :one
+SELECT * FROM standing_orders
+WHERE id = $1 LIMIT 1;
+
+-- name: GetStandingOrderForUpdate :one
+SELECT * FROM standing_orders
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: ListStandingOrders :many
+-- Lists the standing orders the owner set up, oldest first.
+SELECT * FROM standing_orders
+WHERE owner = $1
+ORDER BY id
+LIMIT $2
+OFFSET $3;
+
+-- name: ListDueStandingOrders :many
+-- Lists the active standing orders whose next run is due, the most overdue first.
+SELECT * FROM standing_orders
+WHERE status = 'active' AND next_run_at <= $1
+ORDER BY next_run_at
+LIMIT $2;
+
+-- name: RecordStandingOrderRun :one
+-- Records the run of the standing order due at `due_at`, provided it is still active and wasn't
+-- recorded meanwhile, no row being returned otherwise.
+UPDATE standing_orders
+SET
+    run_count = sqlc.arg(run_count),
+    next_run_at = sqlc.arg(next_run_at),
+    last_run_at = now(),
+    last_failure = sqlc.arg(last_failure)
+WHERE id = sqlc.arg(id) AND status = 'active' AND next_run_at = sqlc.arg(due_at)
+RETURNING *;
+
+-- name: CancelStandingOrder :one
+-- Cancels the standing order provided it is still active, no row being returned otherwise.
+UPDATE standing_orders
+SET
+    status = 'cancelled',
+    cancelled_at = now()
+WHERE id = $1 AND status = 'active'
+RETURNING *;
+
+-- name: CancelUserStandingOrders :execrows
+-- Cancels the active standing orders of the owner and those from or to the closed accounts of the owner,
+-- for the deletion of their data, returning how many were.
+UPDATE standing_orders
+SET
+    status = 'cancelled',
+    cancelled_at = now()
+WHERE status = 'active' AND (
+    owner = $1
+    OR from_account_id IN (SELECT id FROM accounts WHERE owner = $1 AND closed_at IS NOT NULL)
+    OR to_account_id IN (SELECT id FROM accounts WHERE owner = $1 AND closed_at IS NOT NULL)
+);
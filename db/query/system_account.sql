@@ -0,0 +1,6 @@
+-- name: GetSystemAccount :one
+-- Gets the system account of a purpose in a currency.
+SELECT accounts.* FROM accounts
+JOIN system_accounts ON system_accounts.account_id = accounts.id
+WHERE system_accounts.purpose = $1 AND system_accounts.currency = $2
+LIMIT 1;
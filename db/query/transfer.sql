@@ -2,9 +2,11 @@
 INSERT INTO transfers (
   from_account_id,
   to_account_id,
-  amount
+  amount,
+  memo,
+  external_reference
 ) VALUES (
-  $1, $2, $3
+  $1, $2, $3, $4, $5
 ) RETURNING *;
 
 -- name: GetTransfer :one
@@ -18,4 +20,54 @@ WHERE
     to_account_id = $2
 ORDER BY id
 LIMIT $3
-OFFSET $4;
\ No newline at end of file
+OFFSET $4;
+
+-- name: SearchTransfers :many
+-- Lists the transfers sent or received by an account, optionally only those with an external reference
+-- or whose memo contains a text, regardless of its case. Each transfer comes with its other account,
+-- the counterparty, and its owner, and with its currency, the one of both its accounts.
+SELECT
+    sqlc.embed(transfers),
+    counterparty.id AS counterparty_account_id,
+    counterparty.owner AS counterparty_username,
+    users.full_name AS counterparty_full_name,
+    counterparty.nickname AS counterparty_nickname,
+    counterparty.currency
+FROM transfers
+JOIN accounts AS counterparty ON counterparty.id = CASE
+    WHEN transfers.from_account_id = sqlc.arg(account_id) THEN transfers.to_account_id
+    ELSE transfers.from_account_id
+END
+JOIN users ON users.username = counterparty.owner
+WHERE
+    (transfers.from_account_id = sqlc.arg(account_id) OR transfers.to_account_id = sqlc.arg(account_id)) AND
+    (sqlc.narg(external_reference)::varchar IS NULL OR transfers.external_reference = sqlc.narg(external_reference)) AND
+    (sqlc.narg(memo)::varchar IS NULL OR transfers.memo ILIKE '%' || sqlc.narg(memo) || '%')
+ORDER BY transfers.id
+LIMIT sqlc.arg(row_limit)
+OFFSET sqlc.arg(row_offset);
+
+-- name: SearchTransfersBefore :many
+-- Lists the transfers of an account like SearchTransfers, newest first and a page at a time by their id:
+-- the page after a cursor, the id of the last transfer of the previous page, holds the transfers made
+-- before it. The first page, without a cursor, starts with the newest transfer.
+SELECT
+    sqlc.embed(transfers),
+    counterparty.id AS counterparty_account_id,
+    counterparty.owner AS counterparty_username,
+    users.full_name AS counterparty_full_name,
+    counterparty.nickname AS counterparty_nickname,
+    counterparty.currency
+FROM transfers
+JOIN accounts AS counterparty ON counterparty.id = CASE
+    WHEN transfers.from_account_id = sqlc.arg(account_id) THEN transfers.to_account_id
+    ELSE transfers.from_account_id
+END
+JOIN users ON users.username = counterparty.owner
+WHERE
+    (transfers.from_account_id = sqlc.arg(account_id) OR transfers.to_account_id = sqlc.arg(account_id)) AND
+    (sqlc.narg(external_reference)::varchar IS NULL OR transfers.external_reference = sqlc.narg(external_reference)) AND
+    (sqlc.narg(memo)::varchar IS NULL OR transfers.memo ILIKE '%' || sqlc.narg(memo) || '%') AND
+    (sqlc.narg(cursor)::bigint IS NULL OR transfers.id < sqlc.narg(cursor))
+ORDER BY transfers.id DESC
+LIMIT sqlc.arg(row_limit);
@@ -0,0 +1,62 @@
+-- name: CreateTransferReview :one
+INSERT INTO transfer_reviews (
+    from_account_id,
+    to_account_id,
+    amount,
+    memo,
+    external_reference,
+    hold_reason,
+    due_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+) RETURNING *;
+
+-- name: GetTransferReview :one
+SELECT * FROM transfer_reviews
+WHERE id = $1 LIMIT 1;
+
+-- name: GetTransferReviewForUpdate :one
+SELECT * FROM transfer_reviews
+WHERE id = $1 LIMIT 1
+FOR NO KEY UPDATE;
+
+-- name: ListTransferReviews :many
+-- Lists the reviews, the first due first, optionally only those with a status, assigned to a reviewer or
+-- still open past a time.
+SELECT * FROM transfer_reviews
+WHERE
+    (sqlc.narg(status)::varchar IS NULL OR status = sqlc.narg(status)) AND
+    (sqlc.narg(assignee)::varchar IS NULL OR assignee = sqlc.narg(assignee)) AND
+    (sqlc.narg(due_before)::timestamptz IS NULL OR (status IN ('pending', 'escalated') AND due_at < sqlc.narg(due_before)))
+ORDER BY due_at, id
+LIMIT sqlc.arg(row_limit)
+OFFSET sqlc.arg(row_offset);
+
+-- name: AssignTransferReview :one
+-- Assigns the review while it is open, no row being returned once it was decided.
+UPDATE transfer_reviews
+SET assignee = sqlc.narg(assignee)
+WHERE id = sqlc.arg(id) AND status IN ('pending', 'escalated')
+RETURNING *;
+
+-- name: EscalateTransferReview :one
+-- Escalates the review while it is open, no row being returned once it was decided.
+UPDATE transfer_reviews
+SET
+    status = 'escalated',
+    assignee = sqlc.narg(assignee),
+    due_at = sqlc.arg(due_at),
+    note = sqlc.arg(note)
+WHERE id = sqlc.arg(id) AND status IN ('pending', 'escalated')
+RETURNING *;
+
+-- name: DecideTransferReview :one
+UPDATE transfer_reviews
+SET
+    status = sqlc.arg(status),
+    transfer_id = sqlc.narg(transfer_id),
+    decided_by = sqlc.arg(decided_by)::varchar,
+    decided_at = now(),
+    note = sqlc.arg(note)
+WHERE id = sqlc.arg(id)
+RETURNING *;
@@ -3,11 +3,38 @@ INSERT INTO users (
     username,
     hashed_password,
     full_name,
-    email
+    email,
+    org_id
 ) VALUES (
-    $1, $2, $3, $4
+    $1, $2, $3, $4, $5
 ) RETURNING *;
 
 -- name: GetUser :one
 SELECT * FROM users
-WHERE username = $1 LIMIT 1;
\ No newline at end of file
+WHERE username = $1 LIMIT 1;
+
+-- name: GetUserByEmail :one
+SELECT * FROM users
+WHERE email = $1 LIMIT 1;
+
+-- name: GetUserForUpdate :one
+SELECT * FROM users
+WHERE username = $1 LIMIT 1
+FOR NO KEY UPDATE;
+-- name: UpdateUserRole :one
+UPDATE users
+SET role = $2
+WHERE username = $1
+RETURNING *;
+
+-- name: AnonymizeUser :one
+-- Replaces the personal data of the user, whose username is kept since the ledger refers to it. The email
+-- stays unique without being deliverable, and no password matches the emptied hash.
+UPDATE users
+SET
+    full_name = 'Deleted user',
+    email = username || '@deleted.invalid',
+    hashed_password = '',
+    password_changed_at = now()
+WHERE username = $1
+RETURNING *;
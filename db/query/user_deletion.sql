@@ -0,0 +1,47 @@
+-- name: ScheduleUserDeletion :one
+-- Schedules the deletion of the data of the user, replacing a cancelled or failed one. No row is returned
+-- when a deletion of the user is already scheduled or completed.
+INSERT INTO user_deletions (
+    username,
+    scheduled_for
+) VALUES (
+    $1, $2
+) ON CONFLICT (username) DO UPDATE
+SET
+    status = 'scheduled',
+    scheduled_for = EXCLUDED.scheduled_for,
+    error = '',
+    created_at = now(),
+    completed_at = NULL
+WHERE user_deletions.status IN ('cancelled', 'failed')
+RETURNING *;
+
+-- name: GetUserDeletion :one
+SELECT * FROM user_deletions
+WHERE username = $1 LIMIT 1;
+
+-- name: CancelUserDeletion :one
+-- Cancels the deletion of the user provided it is still scheduled, no row being returned otherwise.
+UPDATE user_deletions
+SET status = 'cancelled'
+WHERE username = $1 AND status = 'scheduled'
+RETURNING *;
+
+-- name: CompleteUserDeletion :one
+-- Completes the deletion of the user provided it is still scheduled and its cooling-off period is over,
+-- no row being returned otherwise.
+UPDATE user_deletions
+SET
+    status = 'completed',
+    completed_at = now()
+WHERE username = $1 AND status = 'scheduled' AND scheduled_for <= now()
+RETURNING *;
+
+-- name: FailUserDeletion :one
+-- Fails the deletion of the user provided it is still scheduled, no row being returned otherwise.
+UPDATE user_deletions
+SET
+    status = 'failed',
+    error = $2
+WHERE username = $1 AND status = 'scheduled'
+RETURNING *;
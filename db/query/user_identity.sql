@@ -0,0 +1,17 @@
+-- name: CreateUserIdentity :one
+INSERT INTO user_identities (
+    provider,
+    subject,
+    username,
+    email
+) VALUES (
+    $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetUserIdentity :one
+SELECT * FROM user_identities
+WHERE provider = $1 AND subject = $2 LIMIT 1;
+
+-- name: DeleteUserIdentities :exec
+-- Unlinks the identities of the user, who can no longer log in with their providers.
+DELETE FROM user_identities WHERE username = $1;
@@ -7,13 +7,30 @@ package db
 
 import (
 	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const accountHasHistory = `-- name: AccountHasHistory :one
+SELECT (
+    EXISTS (SELECT 1 FROM entries WHERE account_id = $1) OR
+    EXISTS (SELECT 1 FROM transfers WHERE from_account_id = $1 OR to_account_id = $1)
+)::bool AS has_history
+`
+
+// Reports whether the account has entries or transfers, which prevent it from being deleted.
+func (q *Queries) AccountHasHistory(ctx context.Context, accountID int64) (bool, error) {
+	row := q.db.QueryRow(ctx, accountHasHistory, accountID)
+	var has_history bool
+	err := row.Scan(&has_history)
+	return has_history, err
+}
+
 const addAccountBalance = `-- name: AddAccountBalance :one
 UPDATE accounts 
-SET balance = balance + $1
+SET balance = balance + $1, version = version + 1
 WHERE id = $2
-RETURNING id, owner, balance, currency, created_at
+RETURNING id, owner, balance, currency, created_at, account_number, version, closed_at, org_id, held_balance, nickname, tier
 `
 
 type AddAccountBalanceParams struct {
@@ -21,8 +38,10 @@ type AddAccountBalanceParams struct {
 	ID     int64 `json:"id"`
 }
 
+// Adds the amount to the balance of the account, which must be posted as entries of the account in the
+// same transaction.
 func (q *Queries) AddAccountBalance(ctx context.Context, arg AddAccountBalanceParams) (Account, error) {
-	row := q.db.QueryRowContext(ctx, addAccountBalance, arg.Amount, arg.ID)
+	row := q.db.QueryRow(ctx, addAccountBalance, arg.Amount, arg.ID)
 	var i Account
 	err := row.Scan(
 		&i.ID,
@@ -30,18 +49,104 @@ func (q *Queries) AddAccountBalance(ctx context.Context, arg AddAccountBalancePa
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.AccountNumber,
+		&i.Version,
+		&i.ClosedAt,
+		&i.OrgID,
+		&i.HeldBalance,
+		&i.Nickname,
+		&i.Tier,
 	)
 	return i, err
 }
 
+const addAccountHeldBalance = `-- name: AddAccountHeldBalance :one
+UPDATE accounts
+SET held_balance = held_balance + $1, version = version + 1
+WHERE id = $2
+RETURNING id, owner, balance, currency, created_at, account_number, version, closed_at, org_id, held_balance, nickname, tier
+`
+
+type AddAccountHeldBalanceParams struct {
+	Amount int64 `json:"amount"`
+	ID     int64 `json:"id"`
+}
+
+// Adds the amount to the held balance of the account, which must be the amount of a hold placed on
+// the account or, negated, released from it in the same transaction.
+func (q *Queries) AddAccountHeldBalance(ctx context.Context, arg AddAccountHeldBalanceParams) (Account, error) {
+	row := q.db.QueryRow(ctx, addAccountHeldBalance, arg.Amount, arg.ID)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.AccountNumber,
+		&i.Version,
+		&i.ClosedAt,
+		&i.OrgID,
+		&i.HeldBalance,
+		&i.Nickname,
+		&i.Tier,
+	)
+	return i, err
+}
+
+const closeOwnerAccounts = `-- name: CloseOwnerAccounts :many
+UPDATE accounts
+SET
+    closed_at = now(),
+    version = version + 1
+WHERE owner = $1 AND closed_at IS NULL
+RETURNING id, owner, balance, currency, created_at, account_number, version, closed_at, org_id, held_balance, nickname, tier
+`
+
+// Closes the open accounts of the owner, for the deletion of their data. The accounts are kept with their
+// history for the ledger.
+func (q *Queries) CloseOwnerAccounts(ctx context.Context, owner string) ([]Account, error) {
+	rows, err := q.db.Query(ctx, closeOwnerAccounts, owner)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Account{}
+	for rows.Next() {
+		var i Account
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.Balance,
+			&i.Currency,
+			&i.CreatedAt,
+			&i.AccountNumber,
+			&i.Version,
+			&i.ClosedAt,
+			&i.OrgID,
+			&i.HeldBalance,
+			&i.Nickname,
+			&i.Tier,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const createAccount = `-- name: CreateAccount :one
 INSERT INTO accounts (
     owner,
     balance,
-    currency
+    currency,
+    org_id
 ) VALUES (
-    $1, $2, $3
-) RETURNING id, owner, balance, currency, created_at
+    $1, $2, $3, (SELECT org_id FROM users WHERE username = $1)
+) RETURNING id, owner, balance, currency, created_at, account_number, version, closed_at, org_id, held_balance, nickname, tier
 `
 
 type CreateAccountParams struct {
@@ -50,8 +155,9 @@ type CreateAccountParams struct {
 	Currency string `json:"currency"`
 }
 
+// Creates the account in the organization of its owner.
 func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error) {
-	row := q.db.QueryRowContext(ctx, createAccount, arg.Owner, arg.Balance, arg.Currency)
+	row := q.db.QueryRow(ctx, createAccount, arg.Owner, arg.Balance, arg.Currency)
 	var i Account
 	err := row.Scan(
 		&i.ID,
@@ -59,6 +165,13 @@ func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (A
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.AccountNumber,
+		&i.Version,
+		&i.ClosedAt,
+		&i.OrgID,
+		&i.HeldBalance,
+		&i.Nickname,
+		&i.Tier,
 	)
 	return i, err
 }
@@ -68,17 +181,42 @@ DELETE FROM accounts WHERE id = $1
 `
 
 func (q *Queries) DeleteAccount(ctx context.Context, id int64) error {
-	_, err := q.db.ExecContext(ctx, deleteAccount, id)
+	_, err := q.db.Exec(ctx, deleteAccount, id)
 	return err
 }
 
 const getAccount = `-- name: GetAccount :one
-SELECT id, owner, balance, currency, created_at FROM accounts
+SELECT id, owner, balance, currency, created_at, account_number, version, closed_at, org_id, held_balance, nickname, tier FROM accounts
 WHERE id = $1 LIMIT 1
 `
 
 func (q *Queries) GetAccount(ctx context.Context, id int64) (Account, error) {
-	row := q.db.QueryRowContext(ctx, getAccount, id)
+	row := q.db.QueryRow(ctx, getAccount, id)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.AccountNumber,
+		&i.Version,
+		&i.ClosedAt,
+		&i.OrgID,
+		&i.HeldBalance,
+		&i.Nickname,
+		&i.Tier,
+	)
+	return i, err
+}
+
+const getAccountByNumber = `-- name: GetAccountByNumber :one
+SELECT id, owner, balance, currency, created_at, account_number, version, closed_at, org_id, held_balance, nickname, tier FROM accounts
+WHERE account_number = $1 LIMIT 1
+`
+
+func (q *Queries) GetAccountByNumber(ctx context.Context, accountNumber string) (Account, error) {
+	row := q.db.QueryRow(ctx, getAccountByNumber, accountNumber)
 	var i Account
 	err := row.Scan(
 		&i.ID,
@@ -86,18 +224,25 @@ func (q *Queries) GetAccount(ctx context.Context, id int64) (Account, error) {
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.AccountNumber,
+		&i.Version,
+		&i.ClosedAt,
+		&i.OrgID,
+		&i.HeldBalance,
+		&i.Nickname,
+		&i.Tier,
 	)
 	return i, err
 }
 
 const getAccountForUpdate = `-- name: GetAccountForUpdate :one
-SELECT id, owner, balance, currency, created_at FROM accounts
+SELECT id, owner, balance, currency, created_at, account_number, version, closed_at, org_id, held_balance, nickname, tier FROM accounts
 WHERE id = $1 LIMIT 1
 FOR NO KEY UPDATE
 `
 
 func (q *Queries) GetAccountForUpdate(ctx context.Context, id int64) (Account, error) {
-	row := q.db.QueryRowContext(ctx, getAccountForUpdate, id)
+	row := q.db.QueryRow(ctx, getAccountForUpdate, id)
 	var i Account
 	err := row.Scan(
 		&i.ID,
@@ -105,12 +250,118 @@ func (q *Queries) GetAccountForUpdate(ctx context.Context, id int64) (Account, e
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.AccountNumber,
+		&i.Version,
+		&i.ClosedAt,
+		&i.OrgID,
+		&i.HeldBalance,
+		&i.Nickname,
+		&i.Tier,
 	)
 	return i, err
 }
 
+const getAccountsByIDs = `-- name: GetAccountsByIDs :many
+SELECT id, owner, balance, currency, created_at, account_number, version, closed_at, org_id, held_balance, nickname, tier FROM accounts
+WHERE
+    (owner = $1 OR id IN (
+        SELECT account_id FROM account_members WHERE username = $1 AND accepted_at IS NOT NULL
+    )) AND
+    id = ANY($2::bigint[])
+ORDER BY id
+`
+
+type GetAccountsByIDsParams struct {
+	Owner string  `json:"owner"`
+	Ids   []int64 `json:"ids"`
+}
+
+// Gets the accounts of an owner among the given ids, including the accounts shared with them, the other
+// ids are skipped.
+func (q *Queries) GetAccountsByIDs(ctx context.Context, arg GetAccountsByIDsParams) ([]Account, error) {
+	rows, err := q.db.Query(ctx, getAccountsByIDs, arg.Owner, arg.Ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Account{}
+	for rows.Next() {
+		var i Account
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.Balance,
+			&i.Currency,
+			&i.CreatedAt,
+			&i.AccountNumber,
+			&i.Version,
+			&i.ClosedAt,
+			&i.OrgID,
+			&i.HeldBalance,
+			&i.Nickname,
+			&i.Tier,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const handOverJointAccounts = `-- name: HandOverJointAccounts :many
+UPDATE accounts
+SET
+    owner = co_owners.username,
+    version = accounts.version + 1
+FROM (
+    SELECT DISTINCT ON (account_id) account_id, username FROM account_members
+    WHERE role = 'owner' AND accepted_at IS NOT NULL
+    ORDER BY account_id, accepted_at, username
+) AS co_owners
+WHERE accounts.id = co_owners.account_id AND accounts.owner = $1 AND accounts.closed_at IS NULL
+RETURNING accounts.id, accounts.owner, accounts.balance, accounts.currency, accounts.created_at, accounts.account_number, accounts.version, accounts.closed_at, accounts.org_id, accounts.held_balance, accounts.nickname, accounts.tier
+`
+
+// Hands the open accounts of the owner shared with co-owners over to the co-owner who accepted first, for
+// the deletion of the data of the owner, so that the accounts aren't closed under their co-owners.
+func (q *Queries) HandOverJointAccounts(ctx context.Context, owner string) ([]Account, error) {
+	rows, err := q.db.Query(ctx, handOverJointAccounts, owner)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Account{}
+	for rows.Next() {
+		var i Account
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.Balance,
+			&i.Currency,
+			&i.CreatedAt,
+			&i.AccountNumber,
+			&i.Version,
+			&i.ClosedAt,
+			&i.OrgID,
+			&i.HeldBalance,
+			&i.Nickname,
+			&i.Tier,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listAccounts = `-- name: ListAccounts :many
-SELECT id, owner, balance, currency, created_at FROM accounts
+SELECT id, owner, balance, currency, created_at, account_number, version, closed_at, org_id, held_balance, nickname, tier FROM accounts
 WHERE owner = $1
 ORDER BY id
 LIMIT $2
@@ -124,7 +375,7 @@ type ListAccountsParams struct {
 }
 
 func (q *Queries) ListAccounts(ctx context.Context, arg ListAccountsParams) ([]Account, error) {
-	rows, err := q.db.QueryContext(ctx, listAccounts, arg.Owner, arg.Limit, arg.Offset)
+	rows, err := q.db.Query(ctx, listAccounts, arg.Owner, arg.Limit, arg.Offset)
 	if err != nil {
 		return nil, err
 	}
@@ -138,34 +389,253 @@ func (q *Queries) ListAccounts(ctx context.Context, arg ListAccountsParams) ([]A
 			&i.Balance,
 			&i.Currency,
 			&i.CreatedAt,
+			&i.AccountNumber,
+			&i.Version,
+			&i.ClosedAt,
+			&i.OrgID,
+			&i.HeldBalance,
+			&i.Nickname,
+			&i.Tier,
 		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
 	}
-	if err := rows.Close(); err != nil {
+	if err := rows.Err(); err != nil {
 		return nil, err
 	}
+	return items, nil
+}
+
+const listAccountsAfter = `-- name: ListAccountsAfter :many
+SELECT id, owner, balance, currency, created_at, account_number, version, closed_at, org_id, held_balance, nickname, tier FROM accounts
+WHERE id > $1
+ORDER BY id
+LIMIT $2
+FOR NO KEY UPDATE
+`
+
+type ListAccountsAfterParams struct {
+	ID    int64 `json:"id"`
+	Limit int32 `json:"limit"`
+}
+
+// Lists the accounts following an id, locked for the batch updating them.
+func (q *Queries) ListAccountsAfter(ctx context.Context, arg ListAccountsAfterParams) ([]Account, error) {
+	rows, err := q.db.Query(ctx, listAccountsAfter, arg.ID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Account{}
+	for rows.Next() {
+		var i Account
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.Balance,
+			&i.Currency,
+			&i.CreatedAt,
+			&i.AccountNumber,
+			&i.Version,
+			&i.ClosedAt,
+			&i.OrgID,
+			&i.HeldBalance,
+			&i.Nickname,
+			&i.Tier,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
 	return items, nil
 }
 
-const updateAccount = `-- name: UpdateAccount :one
-UPDATE accounts 
-SET balance = $2
-WHERE id = $1
-RETURNING id, owner, balance, currency, created_at
+const lockAccounts = `-- name: LockAccounts :many
+SELECT id FROM accounts
+WHERE id = ANY($1::bigint[])
+ORDER BY id
+FOR NO KEY UPDATE
+`
+
+// Locks the accounts among the given ids, in order of id so that the transactions locking several
+// accounts at once don't deadlock.
+func (q *Queries) LockAccounts(ctx context.Context, ids []int64) ([]int64, error) {
+	rows, err := q.db.Query(ctx, lockAccounts, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []int64{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const ownerHasBalance = `-- name: OwnerHasBalance :one
+SELECT EXISTS (
+    SELECT 1 FROM accounts
+    WHERE owner = $1 AND closed_at IS NULL AND balance <> 0 AND NOT EXISTS (
+        SELECT 1 FROM account_members
+        WHERE account_id = accounts.id AND role = 'owner' AND accepted_at IS NOT NULL
+    )
+)::bool AS has_balance
+`
+
+// Reports whether an open account of the owner holds money, which prevents the deletion of their data.
+// The accounts shared with co-owners don't, since they are handed over to a co-owner rather than closed.
+func (q *Queries) OwnerHasBalance(ctx context.Context, owner string) (bool, error) {
+	row := q.db.QueryRow(ctx, ownerHasBalance, owner)
+	var has_balance bool
+	err := row.Scan(&has_balance)
+	return has_balance, err
+}
+
+const searchAccounts = `-- name: SearchAccounts :many
+SELECT id, owner, balance, currency, created_at, account_number, version, closed_at, org_id, held_balance, nickname, tier FROM accounts
+WHERE
+    (owner = $1 OR id IN (
+        SELECT account_id FROM account_members WHERE username = $1 AND accepted_at IS NOT NULL
+    )) AND
+    ($2::varchar IS NULL OR currency = $2) AND
+    ($3::bigint IS NULL OR balance >= $3)
+ORDER BY
+    CASE WHEN $4::varchar = 'balance' AND NOT $5::bool THEN balance END,
+    CASE WHEN $4::varchar = 'balance' AND $5::bool THEN balance END DESC,
+    CASE WHEN $4::varchar = 'created_at' AND NOT $5::bool THEN created_at END,
+    CASE WHEN $4::varchar = 'created_at' AND $5::bool THEN created_at END DESC,
+    CASE WHEN $4::varchar = 'currency' AND NOT $5::bool THEN currency END,
+    CASE WHEN $4::varchar = 'currency' AND $5::bool THEN currency END DESC,
+    id
+LIMIT $6
+OFFSET $7
+`
+
+type SearchAccountsParams struct {
+	Owner      string      `json:"owner"`
+	Currency   pgtype.Text `json:"currency"`
+	MinBalance pgtype.Int8 `json:"min_balance"`
+	SortBy     string      `json:"sort_by"`
+	SortDesc   bool        `json:"sort_desc"`
+	RowLimit   int32       `json:"row_limit"`
+	RowOffset  int32       `json:"row_offset"`
+}
+
+// Lists the accounts of an owner, including the accounts shared with them, optionally of a single currency
+// and with at least a given balance, sorted by sort_by (balance, created_at or currency, defaulting to id)
+// with ties broken by id.
+func (q *Queries) SearchAccounts(ctx context.Context, arg SearchAccountsParams) ([]Account, error) {
+	rows, err := q.db.Query(ctx, searchAccounts,
+		arg.Owner,
+		arg.Currency,
+		arg.MinBalance,
+		arg.SortBy,
+		arg.SortDesc,
+		arg.RowLimit,
+		arg.RowOffset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Account{}
+	for rows.Next() {
+		var i Account
+		if err := rows.Scan(
+			&i.ID,
+			&i.Owner,
+			&i.Balance,
+			&i.Currency,
+			&i.CreatedAt,
+			&i.AccountNumber,
+			&i.Version,
+			&i.ClosedAt,
+			&i.OrgID,
+			&i.HeldBalance,
+			&i.Nickname,
+			&i.Tier,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateAccountCurrency = `-- name: UpdateAccountCurrency :one
+UPDATE accounts
+SET currency = $1, version = version + 1
+WHERE id = $2
+RETURNING id, owner, balance, currency, created_at, account_number, version, closed_at, org_id, held_balance, nickname, tier
 `
 
-type UpdateAccountParams struct {
-	ID      int64 `json:"id"`
-	Balance int64 `json:"balance"`
+type UpdateAccountCurrencyParams struct {
+	Currency string `json:"currency"`
+	ID       int64  `json:"id"`
 }
 
-func (q *Queries) UpdateAccount(ctx context.Context, arg UpdateAccountParams) (Account, error) {
-	row := q.db.QueryRowContext(ctx, updateAccount, arg.ID, arg.Balance)
+// Changes the currency of the account, whose balance must be converted by entries of the account in the
+// same transaction.
+func (q *Queries) UpdateAccountCurrency(ctx context.Context, arg UpdateAccountCurrencyParams) (Account, error) {
+	row := q.db.QueryRow(ctx, updateAccountCurrency, arg.Currency, arg.ID)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Owner,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.AccountNumber,
+		&i.Version,
+		&i.ClosedAt,
+		&i.OrgID,
+		&i.HeldBalance,
+		&i.Nickname,
+		&i.Tier,
+	)
+	return i, err
+}
+
+const updateAccountProfile = `-- name: UpdateAccountProfile :one
+UPDATE accounts
+SET
+    nickname = COALESCE($1, nickname),
+    tier = COALESCE($2, tier),
+    version = version + 1
+WHERE id = $3 AND ($4::bigint = 0 OR version = $4)
+RETURNING id, owner, balance, currency, created_at, account_number, version, closed_at, org_id, held_balance, nickname, tier
+`
+
+type UpdateAccountProfileParams struct {
+	Nickname pgtype.Text `json:"nickname"`
+	Tier     pgtype.Text `json:"tier"`
+	ID       int64       `json:"id"`
+	Version  int64       `json:"version"`
+}
+
+// Changes the nickname or the tier of the account, the ones left null being kept, provided the account
+// is still at the version, whatever its version when 0.
+func (q *Queries) UpdateAccountProfile(ctx context.Context, arg UpdateAccountProfileParams) (Account, error) {
+	row := q.db.QueryRow(ctx, updateAccountProfile,
+		arg.Nickname,
+		arg.Tier,
+		arg.ID,
+		arg.Version,
+	)
 	var i Account
 	err := row.Scan(
 		&i.ID,
@@ -173,6 +643,13 @@ func (q *Queries) UpdateAccount(ctx context.Context, arg UpdateAccountParams) (A
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.AccountNumber,
+		&i.Version,
+		&i.ClosedAt,
+		&i.OrgID,
+		&i.HeldBalance,
+		&i.Nickname,
+		&i.Tier,
 	)
 	return i, err
 }
@@ -0,0 +1,71 @@
+package db
+
+import (
+	"context"
+	"errors"
+)
+
+// Statuses of the accounts in their history. An account is closed by the deletion of its owner's data.
+const (
+	AccountStatusOpen   = "open"
+	AccountStatusClosed = "closed"
+)
+
+// Tiers of the accounts, which only admins set. The accounts are opened in the standard tier.
+const (
+	AccountTierStandard = "standard"
+	AccountTierPremium  = "premium"
+)
+
+// AccountStatus returns the status of the account, closed once its closing time is set.
+func AccountStatus(account Account) string {
+	if account.ClosedAt.Valid {
+		return AccountStatusClosed
+	}
+	return AccountStatusOpen
+}
+
+// recordAccountVersion records the current values of the account in its history, closing the version
+// they replace. It must run in the transaction changing the account, so that the history can't miss a
+// change nor record one that was rolled back. The balance isn't versioned, the entries being its history.
+func recordAccountVersion(ctx context.Context, q *Queries, account Account) error {
+	err := q.CloseAccountVersion(ctx, account.ID)
+	if err != nil {
+		return err
+	}
+
+	_, err = q.CreateAccountVersion(ctx, CreateAccountVersionParams{
+		AccountID: account.ID,
+		Owner:     account.Owner,
+		Currency:  account.Currency,
+		Status:    AccountStatus(account),
+		Nickname:  account.Nickname,
+		Tier:      account.Tier,
+	})
+	return err
+}
+
+// UpdateAccountProfile changes the nickname or the tier of the account and records its new values in its
+// history. An account no longer at the expected version is left unchanged with ErrAccountVersionMismatch.
+func (store *SQLStore) UpdateAccountProfile(ctx context.Context, arg UpdateAccountProfileParams) (Account, error) {
+	var account Account
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		account, err = q.UpdateAccountProfile(ctx, arg)
+		if errors.Is(err, ErrRecordNotFound) && arg.Version != 0 {
+			// the account exists but is at another version
+			if _, err := q.GetAccount(ctx, arg.ID); err != nil {
+				return err
+			}
+			return ErrAccountVersionMismatch
+		}
+		if err != nil {
+			return err
+		}
+
+		return recordAccountVersion(ctx, q, account)
+	})
+
+	return account, err
+}
@@ -0,0 +1,77 @@
+package db
+
+import (
+	"context"
+	"go-backend/util"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountHistory(t *testing.T) {
+	store := NewStore(testDB)
+	user := createRandomUser(t)
+
+	account, err := store.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:    user.Username,
+		Balance:  0,
+		Currency: util.RandomCurrency(),
+	})
+	require.NoError(t, err)
+
+	arg := ListAccountHistoryParams{
+		AccountID: account.ID,
+		Limit:     5,
+		Offset:    0,
+	}
+	versions, err := testQueries.ListAccountHistory(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, versions, 1)
+	require.Equal(t, account.Owner, versions[0].Owner)
+	require.Equal(t, account.Currency, versions[0].Currency)
+	require.Equal(t, AccountStatusOpen, versions[0].Status)
+	require.Empty(t, versions[0].Nickname)
+	require.Equal(t, AccountTierStandard, versions[0].Tier)
+	require.False(t, versions[0].ValidTo.Valid)
+
+	// the nickname and the tier are versioned, each change keeping the other values
+	_, err = store.UpdateAccountProfile(context.Background(), UpdateAccountProfileParams{
+		Nickname: pgtype.Text{String: "Savings", Valid: true},
+		ID:       account.ID,
+	})
+	require.NoError(t, err)
+	account, err = store.UpdateAccountProfile(context.Background(), UpdateAccountProfileParams{
+		Tier: pgtype.Text{String: AccountTierPremium, Valid: true},
+		ID:   account.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "Savings", account.Nickname)
+	require.Equal(t, AccountTierPremium, account.Tier)
+
+	// a change of an account updated since the version read is rejected
+	_, err = store.UpdateAccountProfile(context.Background(), UpdateAccountProfileParams{
+		Nickname: pgtype.Text{String: "Checking", Valid: true},
+		ID:       account.ID,
+		Version:  account.Version - 1,
+	})
+	require.ErrorIs(t, err, ErrAccountVersionMismatch)
+
+	versions, err = testQueries.ListAccountHistory(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, versions, 3)
+	require.Equal(t, "Savings", versions[1].Nickname)
+	require.Equal(t, AccountTierStandard, versions[1].Tier)
+	require.Equal(t, "Savings", versions[2].Nickname)
+	require.Equal(t, AccountTierPremium, versions[2].Tier)
+	require.False(t, versions[2].ValidTo.Valid)
+
+	// the history is kept once the account is deleted
+	err = store.DeleteAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+
+	versions, err = testQueries.ListAccountHistory(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, versions, 3)
+	require.True(t, versions[2].ValidTo.Valid)
+}
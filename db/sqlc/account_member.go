@@ -0,0 +1,37 @@
+package db
+
+import (
+	"context"
+	"errors"
+)
+
+// Roles of the users sharing an account. The owners of an account use it like its owner does, while its
+// viewers can only read it.
+const (
+	AccountRoleOwner  = "owner"
+	AccountRoleViewer = "viewer"
+)
+
+// AccountRole returns the role of the user on the account: AccountRoleOwner for the owner of the account,
+// the role of their membership once they accepted it, or an empty role when the account isn't theirs.
+func AccountRole(ctx context.Context, q Querier, account Account, username string) (string, error) {
+	if account.Owner == username {
+		return AccountRoleOwner, nil
+	}
+
+	member, err := q.GetAccountMember(ctx, GetAccountMemberParams{
+		AccountID: account.ID,
+		Username:  username,
+	})
+	if err != nil {
+		if errors.Is(err, ErrRecordNotFound) {
+			return "", nil
+		}
+		return "", err
+	}
+	if !member.AcceptedAt.Valid {
+		return "", nil
+	}
+
+	return member.Role, nil
+}
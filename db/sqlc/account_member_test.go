@@ -0,0 +1,68 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountMembers(t *testing.T) {
+	account := createRandomAccount(t)
+	user := createRandomUser(t)
+
+	member, err := testQueries.CreateAccountMember(context.Background(), CreateAccountMemberParams{
+		AccountID: account.ID,
+		Username:  user.Username,
+		Role:      AccountRoleViewer,
+		InvitedBy: account.Owner,
+	})
+	require.NoError(t, err)
+	require.False(t, member.AcceptedAt.Valid)
+
+	invitations, err := testQueries.ListAccountInvitations(context.Background(), ListAccountInvitationsParams{
+		Username: user.Username,
+		Limit:    5,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []AccountMember{member}, invitations)
+
+	// the account isn't shared until the invitation is accepted
+	role, err := AccountRole(context.Background(), testQueries, account, user.Username)
+	require.NoError(t, err)
+	require.Empty(t, role)
+
+	search := SearchAccountsParams{Owner: user.Username, RowLimit: 5}
+	found, err := testQueries.SearchAccounts(context.Background(), search)
+	require.NoError(t, err)
+	require.Empty(t, found)
+
+	member, err = testQueries.AcceptAccountMember(context.Background(), AcceptAccountMemberParams{
+		AccountID: account.ID,
+		Username:  user.Username,
+	})
+	require.NoError(t, err)
+	require.True(t, member.AcceptedAt.Valid)
+
+	role, err = AccountRole(context.Background(), testQueries, account, user.Username)
+	require.NoError(t, err)
+	require.Equal(t, AccountRoleViewer, role)
+
+	found, err = testQueries.SearchAccounts(context.Background(), search)
+	require.NoError(t, err)
+	require.Equal(t, []Account{account}, found)
+
+	found, err = testQueries.GetAccountsByIDs(context.Background(), GetAccountsByIDsParams{
+		Owner: user.Username,
+		Ids:   []int64{account.ID},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []Account{account}, found)
+
+	// an invitation is accepted once
+	_, err = testQueries.AcceptAccountMember(context.Background(), AcceptAccountMemberParams{
+		AccountID: account.ID,
+		Username:  user.Username,
+	})
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
@@ -2,11 +2,11 @@ package db
 
 import (
 	"context"
-	"database/sql"
 	"go-backend/util"
 	"testing"
 	"time"
 
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/stretchr/testify/require"
 )
 
@@ -14,8 +14,9 @@ func createRandomAccount(t *testing.T) Account {
 	user := createRandomUser(t)
 
 	arg := CreateAccountParams{
-		Owner:    user.Username,
-		Balance:  util.RandomMoney(),
+		Owner: user.Username,
+		// enough for the transfers of the tests, which can't spend more than the available balance
+		Balance:  util.RandomInt(1_000, 10_000),
 		Currency: util.RandomCurrency(),
 	}
 
@@ -29,6 +30,8 @@ func createRandomAccount(t *testing.T) Account {
 
 	require.NotZero(t, account.ID)
 	require.NotZero(t, account.CreatedAt)
+	// the database derives the account number the way util does
+	require.Equal(t, util.AccountNumber(account.ID), account.AccountNumber)
 
 	return account
 }
@@ -52,6 +55,17 @@ func TestGetAccount(t *testing.T) {
 	require.WithinDuration(t, account1.CreatedAt, account2.CreatedAt, time.Second)
 }
 
+func TestGetAccountByNumber(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2, err := testQueries.GetAccountByNumber(context.Background(), account1.AccountNumber)
+	require.NoError(t, err)
+	require.Equal(t, account1.ID, account2.ID)
+	require.Equal(t, account1.AccountNumber, account2.AccountNumber)
+
+	_, err = testQueries.GetAccountByNumber(context.Background(), util.AccountNumber(account1.ID+1000000))
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
+
 func TestGetAccountForUpdate(t *testing.T) {
 	// create account
 	account1 := createRandomAccount(t)
@@ -67,48 +81,202 @@ func TestGetAccountForUpdate(t *testing.T) {
 	require.WithinDuration(t, account1.CreatedAt, account2.CreatedAt, time.Second)
 }
 
-func TestUpdateAccount(t *testing.T) {
-	bal := util.RandomMoney()
-	// create account
+func TestGetAccountsByIDs(t *testing.T) {
 	account1 := createRandomAccount(t)
-	account2, err := testQueries.GetAccountForUpdate(context.Background(), account1.ID)
+	account2, err := testQueries.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:    account1.Owner,
+		Balance:  util.RandomMoney(),
+		Currency: util.RandomCurrency(),
+	})
+	require.NoError(t, err)
+	other := createRandomAccount(t)
 
+	accounts, err := testQueries.GetAccountsByIDs(context.Background(), GetAccountsByIDsParams{
+		Owner: account1.Owner,
+		Ids:   []int64{account2.ID, other.ID, account1.ID, other.ID + 1000000},
+	})
 	require.NoError(t, err)
-	require.NotEmpty(t, account2)
+	require.Len(t, accounts, 2)
+	require.Equal(t, account1.ID, accounts[0].ID)
+	require.Equal(t, account2.ID, accounts[1].ID)
+}
 
-	args := UpdateAccountParams{
-		ID:      account2.ID,
-		Balance: bal,
-	}
+// postEntry adds the amount to the balance of the account with an entry, like the transactions of the
+// store do, so that the balance keeps matching the entries. The opposite amount is posted to the
+// suspense account, so that the entries of the transaction sum to zero.
+func postEntry(t *testing.T, accountID int64, amount int64) Account {
+	var account Account
+	err := NewStore(testDB).(*SQLStore).execTx(context.Background(), func(q *Queries) error {
+		var err error
+		_, account, err = adjustBalance(context.Background(), q, accountID, amount)
+		return err
+	})
+	require.NoError(t, err)
+	return account
+}
 
-	account3, err := testQueries.UpdateAccount(context.Background(), args)
+func TestAdjustAccountBalanceTx(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := createRandomAccount(t)
+	suspense, err := testQueries.GetSystemAccount(context.Background(), GetSystemAccountParams{
+		Purpose:  SystemAccountSuspense,
+		Currency: account1.Currency,
+	})
+	require.NoError(t, err)
+
+	result, err := store.AdjustAccountBalanceTx(context.Background(), AdjustAccountBalanceTxParams{
+		ID:     account1.ID,
+		Amount: 10,
+		Memo:   "correction",
+	})
 	require.NoError(t, err)
-	require.NotEmpty(t, account3)
 
-	require.Equal(t, account1.ID, account3.ID)
-	require.Equal(t, account1.Owner, account3.Owner)
-	require.Equal(t, bal, account3.Balance)
-	require.Equal(t, account1.Currency, account3.Currency)
-	require.WithinDuration(t, account1.CreatedAt, account3.CreatedAt, time.Second)
+	// the credit is a transfer from the suspense account
+	require.Equal(t, suspense.ID, result.Transfer.FromAccountID)
+	require.Equal(t, account1.ID, result.ToAccount.ID)
+	require.Equal(t, account1.Balance+10, result.ToAccount.Balance)
+	require.Equal(t, "correction", result.Transfer.Memo)
+
+	// a debit is a transfer to it, which can't overdraw the account
+	result, err = store.AdjustAccountBalanceTx(context.Background(), AdjustAccountBalanceTxParams{
+		ID:     account1.ID,
+		Amount: -5,
+		Memo:   "correction",
+	})
+	require.NoError(t, err)
+	require.Equal(t, suspense.ID, result.Transfer.ToAccountID)
+	require.Equal(t, account1.Balance+5, result.FromAccount.Balance)
+
+	_, err = store.AdjustAccountBalanceTx(context.Background(), AdjustAccountBalanceTxParams{
+		ID:     account1.ID,
+		Amount: -(account1.Balance + 6),
+		Memo:   "correction",
+	})
+	require.ErrorIs(t, err, ErrInsufficientAvailableBalance)
+
+	_, err = store.AdjustAccountBalanceTx(context.Background(), AdjustAccountBalanceTxParams{
+		ID:     account1.ID + 1000000,
+		Amount: 10,
+		Memo:   "correction",
+	})
+	require.ErrorIs(t, err, ErrRecordNotFound)
 }
 
-func TestAddAccountBalance(t *testing.T) {
+func TestAdjustAccountBalanceTxVersion(t *testing.T) {
+	store := NewStore(testDB)
 	account1 := createRandomAccount(t)
-	arg := AddAccountBalanceParams{
-		ID:     account1.ID,
-		Amount: util.RandomMoney(),
-	}
+	require.Equal(t, int64(1), account1.Version)
 
-	account2, err := testQueries.AddAccountBalance(context.Background(), arg)
+	result, err := store.AdjustAccountBalanceTx(context.Background(), AdjustAccountBalanceTxParams{
+		ID:      account1.ID,
+		Amount:  10,
+		Version: account1.Version,
+		Memo:    "correction",
+	})
 	require.NoError(t, err)
-	require.NotEmpty(t, account2)
+	require.Equal(t, account1.Version+1, result.ToAccount.Version)
+
+	// a client still holding the first version doesn't adjust the balance twice
+	_, err = store.AdjustAccountBalanceTx(context.Background(), AdjustAccountBalanceTxParams{
+		ID:      account1.ID,
+		Amount:  10,
+		Version: account1.Version,
+		Memo:    "correction",
+	})
+	require.ErrorIs(t, err, ErrAccountVersionMismatch)
 
+	account2, err := testQueries.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, result.ToAccount, account2)
+}
+
+func TestAddAccountBalance(t *testing.T) {
+	account1 := createRandomAccount(t)
+	amount := util.RandomMoney()
+
+	account2 := postEntry(t, account1.ID, amount)
 	require.Equal(t, account1.ID, account2.ID)
 	require.Equal(t, account1.Owner, account2.Owner)
-	require.Equal(t, account1.Balance+arg.Amount, account2.Balance)
+	require.Equal(t, account1.Balance+amount, account2.Balance)
 	require.Equal(t, account1.Currency, account2.Currency)
 	require.WithinDuration(t, account1.CreatedAt, account2.CreatedAt, time.Second)
+	require.Equal(t, account1.Version+1, account2.Version)
+}
+
+func TestAddAccountBalanceWithoutEntry(t *testing.T) {
+	account1 := createRandomAccount(t)
+
+	_, err := testQueries.AddAccountBalance(context.Background(), AddAccountBalanceParams{
+		ID:     account1.ID,
+		Amount: 5,
+	})
+	require.Error(t, err)
+
+	// an entry of another amount doesn't post the change either
+	err = NewStore(testDB).(*SQLStore).execTx(context.Background(), func(q *Queries) error {
+		_, err := q.CreateEntry(context.Background(), CreateEntryParams{
+			AccountID: account1.ID,
+			Amount:    3,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = q.AddAccountBalance(context.Background(), AddAccountBalanceParams{
+			ID:     account1.ID,
+			Amount: 5,
+		})
+		return err
+	})
+	require.Error(t, err)
+
+	account2, err := testQueries.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance, account2.Balance)
+}
+
+func TestEntriesMustBalance(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	// an entry alone creates money
+	_, err := testQueries.CreateEntry(context.Background(), CreateEntryParams{
+		AccountID: account1.ID,
+		Amount:    5,
+	})
+	require.Error(t, err)
+
+	// and so do entries of different amounts, even when their balance changes are posted
+	err = NewStore(testDB).(*SQLStore).execTx(context.Background(), func(q *Queries) error {
+		for _, posting := range []struct{ id, amount int64 }{{account1.ID, -3}, {account2.ID, 5}} {
+			_, err := q.CreateEntry(context.Background(), CreateEntryParams{
+				AccountID: posting.id,
+				Amount:    posting.amount,
+			})
+			if err != nil {
+				return err
+			}
+
+			_, err = q.AddAccountBalance(context.Background(), AddAccountBalanceParams{
+				ID:     posting.id,
+				Amount: posting.amount,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	require.Error(t, err)
+
+	entries, err := testQueries.ListEntries(context.Background(), ListEntriesParams{
+		AccountID: account2.ID,
+		Limit:     5,
+	})
+	require.NoError(t, err)
+	require.Empty(t, entries)
 }
+
 func TestDeleteAccount(t *testing.T) {
 	account1 := createRandomAccount(t)
 
@@ -117,10 +285,30 @@ func TestDeleteAccount(t *testing.T) {
 
 	account2, err := testQueries.GetAccount(context.Background(), account1.ID)
 	require.Error(t, err)
-	require.EqualError(t, err, sql.ErrNoRows.Error())
+	require.EqualError(t, err, ErrRecordNotFound.Error())
 	require.Empty(t, account2)
 }
 
+func TestDeleteAccountWithHistory(t *testing.T) {
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	hasHistory, err := testQueries.AccountHasHistory(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.False(t, hasHistory)
+
+	createRandomTransfer(t, account1, account2)
+
+	for _, account := range []Account{account1, account2} {
+		hasHistory, err := testQueries.AccountHasHistory(context.Background(), account.ID)
+		require.NoError(t, err)
+		require.True(t, hasHistory)
+
+		err = testQueries.DeleteAccount(context.Background(), account.ID)
+		require.ErrorIs(t, TranslateError(err), ErrForeignKeyViolation)
+	}
+}
+
 func TestListAccounts(t *testing.T) {
 	var lastAccount Account
 	for i := 0; i < 10; i++ {
@@ -143,3 +331,56 @@ func TestListAccounts(t *testing.T) {
 		require.Equal(t, lastAccount.Owner, account.Owner)
 	}
 }
+
+func TestSearchAccounts(t *testing.T) {
+	user := createRandomUser(t)
+
+	var accounts []Account
+	for i, currency := range []string{util.USD, util.CAD, util.EUR} {
+		account, err := testQueries.CreateAccount(context.Background(), CreateAccountParams{
+			Owner:    user.Username,
+			Balance:  int64(100 * (i + 1)),
+			Currency: currency,
+		})
+		require.NoError(t, err)
+		accounts = append(accounts, account)
+	}
+
+	found, err := testQueries.SearchAccounts(context.Background(), SearchAccountsParams{
+		Owner:     user.Username,
+		SortBy:    "balance",
+		SortDesc:  true,
+		RowLimit:  5,
+		RowOffset: 0,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []Account{accounts[2], accounts[1], accounts[0]}, found)
+
+	found, err = testQueries.SearchAccounts(context.Background(), SearchAccountsParams{
+		Owner:     user.Username,
+		SortBy:    "currency",
+		RowLimit:  5,
+		RowOffset: 0,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []Account{accounts[1], accounts[2], accounts[0]}, found)
+
+	found, err = testQueries.SearchAccounts(context.Background(), SearchAccountsParams{
+		Owner:      user.Username,
+		Currency:   pgtype.Text{String: util.CAD, Valid: true},
+		MinBalance: pgtype.Int8{Int64: 150, Valid: true},
+		RowLimit:   5,
+		RowOffset:  0,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []Account{accounts[1]}, found)
+
+	found, err = testQueries.SearchAccounts(context.Background(), SearchAccountsParams{
+		Owner:      user.Username,
+		MinBalance: pgtype.Int8{Int64: 1000, Valid: true},
+		RowLimit:   5,
+		RowOffset:  0,
+	})
+	require.NoError(t, err)
+	require.Empty(t, found)
+}
@@ -0,0 +1,109 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddAccountDailyActivity(t *testing.T) {
+	account := createRandomAccount(t)
+	day := time.Now().UTC().Truncate(24 * time.Hour)
+
+	for _, arg := range []AddAccountDailyActivityParams{
+		{AccountID: account.ID, Day: day, Credits: 100},
+		{AccountID: account.ID, Day: day, Debits: 40},
+		{AccountID: account.ID, Day: day.AddDate(0, 0, -1), Debits: 10},
+	} {
+		err := testQueries.AddAccountDailyActivity(context.Background(), arg)
+		require.NoError(t, err)
+	}
+
+	days, err := testQueries.ListAccountDailyActivity(context.Background(), ListAccountDailyActivityParams{
+		AccountID: account.ID,
+		FromDay:   day.AddDate(0, 0, -1),
+		ToDay:     day,
+	})
+	require.NoError(t, err)
+	require.Len(t, days, 2)
+
+	require.True(t, days[0].Day.Equal(day.AddDate(0, 0, -1)))
+	require.Equal(t, int64(10), days[0].Debits)
+	require.Equal(t, int64(1), days[0].TransferCount)
+
+	require.True(t, days[1].Day.Equal(day))
+	require.Equal(t, int64(100), days[1].Credits)
+	require.Equal(t, int64(40), days[1].Debits)
+	require.Equal(t, int64(2), days[1].TransferCount)
+
+	// the days out of the period are left out
+	days, err = testQueries.ListAccountDailyActivity(context.Background(), ListAccountDailyActivityParams{
+		AccountID: account.ID,
+		FromDay:   day,
+		ToDay:     day,
+	})
+	require.NoError(t, err)
+	require.Len(t, days, 1)
+
+	err = testQueries.DeleteAccountDailyActivity(context.Background(), account.ID)
+	require.NoError(t, err)
+
+	days, err = testQueries.ListAccountDailyActivity(context.Background(), ListAccountDailyActivityParams{
+		AccountID: account.ID,
+		FromDay:   day.AddDate(0, 0, -1),
+		ToDay:     day,
+	})
+	require.NoError(t, err)
+	require.Empty(t, days)
+}
+
+func TestAddAccountCounterpartyActivity(t *testing.T) {
+	account := createRandomAccount(t)
+	frequent := createRandomAccount(t)
+	occasional := createRandomAccount(t)
+	now := time.Now().UTC().Truncate(time.Second)
+
+	for _, arg := range []AddAccountCounterpartyActivityParams{
+		{AccountID: account.ID, CounterpartyAccountID: frequent.ID, CounterpartyOwner: frequent.Owner, Sent: 30, LastTransferAt: now},
+		{AccountID: account.ID, CounterpartyAccountID: frequent.ID, CounterpartyOwner: frequent.Owner, Received: 50, LastTransferAt: now.Add(-time.Hour)},
+		{AccountID: account.ID, CounterpartyAccountID: occasional.ID, CounterpartyOwner: occasional.Owner, Sent: 5, LastTransferAt: now},
+	} {
+		err := testQueries.AddAccountCounterpartyActivity(context.Background(), arg)
+		require.NoError(t, err)
+	}
+
+	counterparties, err := testQueries.ListAccountCounterpartyActivity(context.Background(), ListAccountCounterpartyActivityParams{
+		AccountID: account.ID,
+		RowLimit:  10,
+	})
+	require.NoError(t, err)
+	require.Len(t, counterparties, 2)
+
+	// the counterparties with the most transfers come first
+	require.Equal(t, frequent.ID, counterparties[0].CounterpartyAccountID)
+	require.Equal(t, frequent.Owner, counterparties[0].CounterpartyOwner)
+	require.Equal(t, int64(30), counterparties[0].Sent)
+	require.Equal(t, int64(50), counterparties[0].Received)
+	require.Equal(t, int64(2), counterparties[0].TransferCount)
+	require.True(t, counterparties[0].LastTransferAt.Equal(now))
+	require.Equal(t, occasional.ID, counterparties[1].CounterpartyAccountID)
+
+	counterparties, err = testQueries.ListAccountCounterpartyActivity(context.Background(), ListAccountCounterpartyActivityParams{
+		AccountID: account.ID,
+		RowLimit:  1,
+	})
+	require.NoError(t, err)
+	require.Len(t, counterparties, 1)
+
+	err = testQueries.DeleteAccountCounterpartyActivity(context.Background(), account.ID)
+	require.NoError(t, err)
+
+	counterparties, err = testQueries.ListAccountCounterpartyActivity(context.Background(), ListAccountCounterpartyActivityParams{
+		AccountID: account.ID,
+		RowLimit:  10,
+	})
+	require.NoError(t, err)
+	require.Empty(t, counterparties)
+}
@@ -0,0 +1,65 @@
+package db
+
+import (
+	"context"
+	"go-backend/util"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddRouteRequestVolume(t *testing.T) {
+	route := "GET /" + util.RandomString(10)
+	hour := time.Now().UTC().Truncate(time.Hour)
+
+	for i := 0; i < 2; i++ {
+		err := testQueries.AddRouteRequestVolume(context.Background(), AddRouteRequestVolumeParams{
+			Route:    route,
+			Hour:     hour,
+			Requests: 5,
+			Errors:   1,
+		})
+		require.NoError(t, err)
+	}
+
+	volumes, err := testQueries.ListRouteRequestVolumes(context.Background(), hour)
+	require.NoError(t, err)
+
+	var found bool
+	for _, volume := range volumes {
+		if volume.Route == route {
+			found = true
+			require.Equal(t, int64(10), volume.Requests)
+			require.Equal(t, int64(2), volume.Errors)
+			require.Equal(t, int64(10), volume.PeakHourRequests)
+		}
+	}
+	require.True(t, found)
+}
+
+func TestListTransferHeatmap(t *testing.T) {
+	since := time.Now().Add(-time.Minute)
+	fromAccount := createRandomAccount(t)
+	toAccount := createRandomAccount(t)
+	transfer := createRandomTransfer(t, fromAccount, toAccount)
+
+	heatmap, err := testQueries.ListTransferHeatmap(context.Background(), since)
+	require.NoError(t, err)
+
+	createdAt := transfer.CreatedAt.UTC()
+	dayOfWeek := int32(createdAt.Weekday())
+	if dayOfWeek == 0 {
+		dayOfWeek = 7
+	}
+
+	var found bool
+	for _, bucket := range heatmap {
+		if bucket.DayOfWeek == dayOfWeek && bucket.HourOfDay == int32(createdAt.Hour()) {
+			found = true
+			require.GreaterOrEqual(t, bucket.Transfers, int64(1))
+			require.GreaterOrEqual(t, bucket.Amount, transfer.Amount)
+		}
+	}
+	require.True(t, found)
+}
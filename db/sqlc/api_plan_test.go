@@ -0,0 +1,40 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserAPIPlan(t *testing.T) {
+	user := createRandomUser(t)
+
+	_, err := testQueries.GetUserAPIPlan(context.Background(), user.Username)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	plan, err := testQueries.UpsertUserAPIPlan(context.Background(), UpsertUserAPIPlanParams{
+		Username: user.Username,
+		Plan:     "basic",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "basic", plan.Plan)
+
+	// a user has a single plan, replaced when they change plans
+	plan2, err := testQueries.UpsertUserAPIPlan(context.Background(), UpsertUserAPIPlanParams{
+		Username: user.Username,
+		Plan:     "pro",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "pro", plan2.Plan)
+	require.False(t, plan2.UpdatedAt.Before(plan.UpdatedAt))
+
+	plan3, err := testQueries.GetUserAPIPlan(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Equal(t, plan2, plan3)
+
+	err = testQueries.DeleteUserAPIPlan(context.Background(), user.Username)
+	require.NoError(t, err)
+	_, err = testQueries.GetUserAPIPlan(context.Background(), user.Username)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
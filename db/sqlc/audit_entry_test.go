@@ -0,0 +1,55 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditEntries(t *testing.T) {
+	user := createRandomUser(t)
+	admin := createRandomUser(t)
+
+	started, err := testQueries.CreateAuditEntry(context.Background(), CreateAuditEntryParams{
+		Username:     user.Username,
+		Impersonator: pgtype.Text{String: admin.Username, Valid: true},
+		Action:       "impersonation.started",
+		Detail:       "ticket 42",
+	})
+	require.NoError(t, err)
+	require.Equal(t, admin.Username, started.Impersonator.String)
+	require.Zero(t, started.Status)
+
+	request, err := testQueries.CreateAuditEntry(context.Background(), CreateAuditEntryParams{
+		Username:     user.Username,
+		Impersonator: pgtype.Text{String: admin.Username, Valid: true},
+		Action:       "POST /api/v1/transfers",
+		Status:       201,
+	})
+	require.NoError(t, err)
+
+	_, err = testQueries.CreateAuditEntry(context.Background(), CreateAuditEntryParams{
+		Username: user.Username,
+		Action:   "POST /api/v1/accounts",
+		Status:   201,
+	})
+	require.NoError(t, err)
+
+	// newest first
+	entries, err := testQueries.ListAuditEntries(context.Background(), ListAuditEntriesParams{
+		Impersonator: pgtype.Text{String: admin.Username, Valid: true},
+		RowLimit:     10,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []AuditEntry{request, started}, entries)
+
+	entries, err = testQueries.ListAuditEntries(context.Background(), ListAuditEntriesParams{
+		Username: pgtype.Text{String: user.Username, Valid: true},
+		RowLimit: 10,
+	})
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	require.False(t, entries[0].Impersonator.Valid)
+}
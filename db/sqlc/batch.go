@@ -0,0 +1,266 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Batches run by the bank once a business day is over. Each batch keeps a run per business date in the
+// batch_runs table, so that it is applied once to every account however many times it is started.
+const (
+	BatchBalanceSnapshot        = "balance_snapshot"
+	BatchInterestCapitalization = "interest_capitalization"
+	BatchLedgerReconciliation   = "ledger_reconciliation"
+	BatchMonthlyFee             = "monthly_fee"
+)
+
+// The CapitalizeInterestTxParams type contains the parameters to capitalize the interest of the next
+// accounts for a business date.
+// @property {time.Time} BusinessDate - the day the interest is earned for.
+// @property {int64} RateBps - the yearly interest rate, in basis points.
+// @property {int32} Limit - the maximum number of accounts processed in the batch.
+type CapitalizeInterestTxParams struct {
+	BusinessDate time.Time
+	RateBps      int64
+	Limit        int32
+}
+
+// The ChargeMonthlyFeeTxParams type contains the parameters to charge the monthly fee to the next
+// accounts for the last business date of a month.
+// @property {time.Time} BusinessDate - the last day of the month the fee is charged for.
+// @property {int64} Fee - the fee charged to every account, in its currency.
+// @property {int32} Limit - the maximum number of accounts processed in the batch.
+type ChargeMonthlyFeeTxParams struct {
+	BusinessDate time.Time
+	Fee          int64
+	Limit        int32
+}
+
+// The BatchTxResult type reports the progress of the run of a batch.
+// @property {int} Accounts - the number of accounts processed by the batch.
+// @property {bool} Done - whether every account was processed for the business date.
+type BatchTxResult struct {
+	Accounts int
+	Done     bool
+}
+
+// The SnapshotBalancesTxParams type contains the parameters to snapshot the balance of the next accounts
+// at the end of a business date.
+// @property {time.Time} BusinessDate - the day whose closing balances are recorded.
+// @property {int32} Limit - the maximum number of accounts processed in the batch.
+type SnapshotBalancesTxParams struct {
+	BusinessDate time.Time
+	Limit        int32
+}
+
+// SnapshotBalancesTx records the balance of the next accounts of the run at the end of the business
+// date, which is their balance less the entries made since. Accounts opened after the business date
+// have no snapshot for it.
+func (store *SQLStore) SnapshotBalancesTx(ctx context.Context, arg SnapshotBalancesTxParams) (BatchTxResult, error) {
+	businessDate := time.Date(arg.BusinessDate.Year(), arg.BusinessDate.Month(), arg.BusinessDate.Day(), 0, 0, 0, 0, time.UTC)
+	endOfDay := businessDate.AddDate(0, 0, 1)
+
+	return store.accountBatchTx(ctx, BatchBalanceSnapshot, businessDate, arg.Limit, func(q *Queries, account Account) error {
+		if !account.CreatedAt.Before(endOfDay) {
+			return nil
+		}
+
+		since, err := q.SumEntriesSince(ctx, SumEntriesSinceParams{
+			AccountID: account.ID,
+			CreatedAt: endOfDay,
+		})
+		if err != nil {
+			return err
+		}
+
+		return q.CreateBalanceSnapshot(ctx, CreateBalanceSnapshotParams{
+			AccountID:    account.ID,
+			BusinessDate: businessDate,
+			Balance:      account.Balance - since,
+		})
+	})
+}
+
+// CapitalizeInterestTx credits the daily interest of the business date to the next accounts of the run,
+// from the interest account of their currency. The interest is earned on the balance snapshot of the
+// business date rather than the live balance, so that a business date closed late earns what it did
+// back then, and only positive balances earn it. The interest is accrued in millionths of the smallest
+// unit and the whole units credited, the fraction being carried over to the next day so that small
+// balances earn their interest too. Closed accounts, accounts without a snapshot for the business date
+// and the system accounts of the bank earn none.
+func (store *SQLStore) CapitalizeInterestTx(ctx context.Context, arg CapitalizeInterestTxParams) (BatchTxResult, error) {
+	businessDate := time.Date(arg.BusinessDate.Year(), arg.BusinessDate.Month(), arg.BusinessDate.Day(), 0, 0, 0, 0, time.UTC)
+
+	return store.accountBatchTx(ctx, BatchInterestCapitalization, businessDate, arg.Limit, func(q *Queries, account Account) error {
+		if IsSystemAccount(account) || account.ClosedAt.Valid {
+			return nil
+		}
+
+		snapshot, err := q.GetBalanceSnapshot(ctx, GetBalanceSnapshotParams{
+			AccountID:    account.ID,
+			BusinessDate: businessDate,
+		})
+		if err != nil {
+			if errors.Is(err, ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+		if snapshot.Balance <= 0 {
+			return nil
+		}
+
+		accrual, err := q.GetInterestAccrual(ctx, account.ID)
+		if err != nil && !errors.Is(err, ErrRecordNotFound) {
+			return err
+		}
+
+		interest, remainder := accrueInterest(snapshot.Balance, arg.RateBps, accrual.Remainder)
+		err = q.SetInterestAccrual(ctx, SetInterestAccrualParams{
+			AccountID: account.ID,
+			Remainder: remainder,
+		})
+		if err != nil || interest == 0 {
+			return err
+		}
+
+		payer, err := q.GetSystemAccount(ctx, GetSystemAccountParams{
+			Purpose:  SystemAccountInterest,
+			Currency: account.Currency,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = transfer(ctx, q, TransferTxParams{
+			FromAccountID: payer.ID,
+			ToAccountID:   account.ID,
+			Amount:        interest,
+			Memo:          fmt.Sprintf("interest for %s", businessDate.Format("2006-01-02")),
+		}, nil)
+		return err
+	})
+}
+
+// ChargeMonthlyFeeTx charges the monthly fee to the next accounts of the run, to the fees account of
+// their currency. An account is charged at most its available balance, so that the fee never overdraws
+// it. Closed accounts, accounts opened after the business date and the system accounts of the bank are
+// not charged.
+func (store *SQLStore) ChargeMonthlyFeeTx(ctx context.Context, arg ChargeMonthlyFeeTxParams) (BatchTxResult, error) {
+	businessDate := time.Date(arg.BusinessDate.Year(), arg.BusinessDate.Month(), arg.BusinessDate.Day(), 0, 0, 0, 0, time.UTC)
+	endOfDay := businessDate.AddDate(0, 0, 1)
+
+	return store.accountBatchTx(ctx, BatchMonthlyFee, businessDate, arg.Limit, func(q *Queries, account Account) error {
+		if IsSystemAccount(account) || account.ClosedAt.Valid || !account.CreatedAt.Before(endOfDay) {
+			return nil
+		}
+
+		// the available balance is read under the lock of the account, as it may have moved since listed
+		account, err := q.GetAccountForUpdate(ctx, account.ID)
+		if err != nil {
+			return err
+		}
+
+		fee := arg.Fee
+		if available := AvailableBalance(account); available < fee {
+			fee = available
+		}
+		if fee <= 0 {
+			return nil
+		}
+
+		fees, err := q.GetSystemAccount(ctx, GetSystemAccountParams{
+			Purpose:  SystemAccountFees,
+			Currency: account.Currency,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = transfer(ctx, q, TransferTxParams{
+			FromAccountID: account.ID,
+			ToAccountID:   fees.ID,
+			Amount:        fee,
+			Memo:          fmt.Sprintf("monthly fee for %s", businessDate.Format("January 2006")),
+		}, nil)
+		return err
+	})
+}
+
+// accrueInterest returns the whole interest credited for a day at a yearly rate in basis points, and the
+// millionths of the smallest unit carried over to the next day, given those carried over so far.
+func accrueInterest(balance int64, rateBps int64, remainder int64) (int64, int64) {
+	if balance <= 0 {
+		return 0, remainder
+	}
+
+	const daily = 10000 * 365
+	whole := balance * rateBps / daily
+	remainder += balance * rateBps % daily * 1_000_000 / daily
+	return whole + remainder/1_000_000, remainder % 1_000_000
+}
+
+// accountBatchTx applies a batch to the accounts following the checkpoint of its run for the business
+// date, and moves the checkpoint past them, all in one transaction: a crashed batch is rolled back and
+// resumed from the last committed checkpoint rather than applied twice. The run is locked until the end
+// of the transaction so that concurrent runners don't process the same accounts. The run is completed
+// once a batch finds fewer accounts than the limit.
+func (store *SQLStore) accountBatchTx(ctx context.Context, name string, businessDate time.Time, limit int32, apply func(q *Queries, account Account) error) (BatchTxResult, error) {
+	var result BatchTxResult
+	businessDate = time.Date(businessDate.Year(), businessDate.Month(), businessDate.Day(), 0, 0, 0, 0, time.UTC)
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		run, err := q.LockBatchRun(ctx, LockBatchRunParams{
+			Name:         name,
+			BusinessDate: businessDate,
+		})
+		if err != nil {
+			return err
+		}
+		if run.CompletedAt.Valid {
+			result.Done = true
+			return nil
+		}
+
+		accounts, err := q.ListAccountsAfter(ctx, ListAccountsAfterParams{
+			ID:    run.LastAccountID,
+			Limit: limit,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, account := range accounts {
+			err = apply(q, account)
+			if err != nil {
+				return err
+			}
+		}
+
+		result.Accounts = len(accounts)
+		if len(accounts) > 0 {
+			err = q.UpdateBatchRunCheckpoint(ctx, UpdateBatchRunCheckpointParams{
+				LastAccountID:     accounts[len(accounts)-1].ID,
+				ProcessedAccounts: int64(len(accounts)),
+				Name:              name,
+				BusinessDate:      businessDate,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(accounts) < int(limit) {
+			result.Done = true
+			return q.CompleteBatchRun(ctx, CompleteBatchRunParams{
+				Name:         name,
+				BusinessDate: businessDate,
+			})
+		}
+		return nil
+	})
+
+	return result, err
+}
@@ -0,0 +1,187 @@
+package db
+
+import (
+	"context"
+	"go-backend/util"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccrueInterest(t *testing.T) {
+	interest, remainder := accrueInterest(365_000_000, 100, 0)
+	require.Equal(t, int64(10000), interest)
+	require.Zero(t, remainder)
+
+	// a small balance earns less than a unit a day, which is carried over until it adds up to one
+	interest, remainder = accrueInterest(1000, 100, 0)
+	require.Zero(t, interest)
+	require.Equal(t, int64(27_397), remainder)
+
+	interest, remainder = accrueInterest(1000, 100, 990_000)
+	require.Equal(t, int64(1), interest)
+	require.Equal(t, int64(17_397), remainder)
+
+	interest, remainder = accrueInterest(-365_000_000, 100, 500)
+	require.Zero(t, interest)
+	require.Equal(t, int64(500), remainder)
+}
+
+func TestCapitalizeInterestTx(t *testing.T) {
+	store := NewStore(testDB)
+	account := createRandomAccount(t)
+	small := createRandomAccount(t)
+	unsnapshotted := createRandomAccount(t)
+
+	// a business date of its own, so that the run starts from the first account and only the snapshots
+	// of the test earn interest
+	businessDate := time.Date(int(util.RandomInt(1000, 2000)), time.January, 1, 0, 0, 0, 0, time.UTC)
+	for id, balance := range map[int64]int64{account.ID: 365_000_000, small.ID: 1000} {
+		err := testQueries.CreateBalanceSnapshot(context.Background(), CreateBalanceSnapshotParams{
+			AccountID:    id,
+			BusinessDate: businessDate,
+			Balance:      balance,
+		})
+		require.NoError(t, err)
+	}
+
+	payer, err := testQueries.GetSystemAccount(context.Background(), GetSystemAccountParams{
+		Purpose:  SystemAccountInterest,
+		Currency: account.Currency,
+	})
+	require.NoError(t, err)
+
+	arg := CapitalizeInterestTxParams{
+		BusinessDate: businessDate,
+		RateBps:      100,
+		Limit:        1000,
+	}
+
+	for {
+		result, err := store.CapitalizeInterestTx(context.Background(), arg)
+		require.NoError(t, err)
+		if result.Done {
+			break
+		}
+		require.Equal(t, 1000, result.Accounts)
+	}
+
+	// the interest is earned on the snapshot rather than the live balance, and paid by the bank
+	updated, err := testQueries.GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, account.Balance+10000, updated.Balance)
+
+	updatedPayer, err := testQueries.GetAccount(context.Background(), payer.ID)
+	require.NoError(t, err)
+	require.LessOrEqual(t, updatedPayer.Balance, payer.Balance-10000)
+
+	// the interest of the small balance is accrued rather than rounded away
+	updated, err = testQueries.GetAccount(context.Background(), small.ID)
+	require.NoError(t, err)
+	require.Equal(t, small.Balance, updated.Balance)
+
+	accrual, err := testQueries.GetInterestAccrual(context.Background(), small.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(27_397), accrual.Remainder)
+
+	updated, err = testQueries.GetAccount(context.Background(), unsnapshotted.ID)
+	require.NoError(t, err)
+	require.Equal(t, unsnapshotted.Balance, updated.Balance)
+
+	// the run is complete, so running it again doesn't credit the interest twice
+	result, err := store.CapitalizeInterestTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.True(t, result.Done)
+	require.Zero(t, result.Accounts)
+
+	updated, err = testQueries.GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, account.Balance+10000, updated.Balance)
+}
+
+func TestChargeMonthlyFeeTx(t *testing.T) {
+	store := NewStore(testDB)
+	account := createRandomAccount(t)
+
+	// the last day of a month of its own, so that the run starts from the first account
+	businessDate := time.Date(int(util.RandomInt(5000, 6000)), time.January, 31, 0, 0, 0, 0, time.UTC)
+	arg := ChargeMonthlyFeeTxParams{
+		BusinessDate: businessDate,
+		Fee:          1,
+		Limit:        1000,
+	}
+
+	for {
+		result, err := store.ChargeMonthlyFeeTx(context.Background(), arg)
+		require.NoError(t, err)
+		if result.Done {
+			break
+		}
+	}
+
+	updated, err := testQueries.GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, account.Balance-1, updated.Balance)
+
+	// the run is complete, so running it again doesn't charge the fee twice
+	result, err := store.ChargeMonthlyFeeTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.True(t, result.Done)
+
+	updated, err = testQueries.GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, account.Balance-1, updated.Balance)
+}
+
+func TestSnapshotBalancesTx(t *testing.T) {
+	store := NewStore(testDB)
+	account := createRandomAccount(t)
+
+	// a business date of its own, after the account was opened, so that the run starts from the first
+	// account and no entry was made since
+	businessDate := time.Date(int(util.RandomInt(3000, 4000)), time.January, 1, 0, 0, 0, 0, time.UTC)
+	arg := SnapshotBalancesTxParams{
+		BusinessDate: businessDate,
+		Limit:        1000,
+	}
+
+	for {
+		result, err := store.SnapshotBalancesTx(context.Background(), arg)
+		require.NoError(t, err)
+		if result.Done {
+			break
+		}
+	}
+
+	snapshots, err := testQueries.ListBalanceSnapshots(context.Background(), ListBalanceSnapshotsParams{
+		AccountID: account.ID,
+		FromDate:  businessDate,
+		ToDate:    businessDate,
+	})
+	require.NoError(t, err)
+	require.Len(t, snapshots, 1)
+	require.Equal(t, account.Balance, snapshots[0].Balance)
+	require.True(t, businessDate.Equal(snapshots[0].BusinessDate))
+
+	// the account didn't exist at the end of a day before it was opened
+	pastDate := time.Date(int(util.RandomInt(1000, 2000)), time.January, 1, 0, 0, 0, 0, time.UTC)
+	for {
+		result, err := store.SnapshotBalancesTx(context.Background(), SnapshotBalancesTxParams{
+			BusinessDate: pastDate,
+			Limit:        1000,
+		})
+		require.NoError(t, err)
+		if result.Done {
+			break
+		}
+	}
+
+	snapshots, err = testQueries.ListBalanceSnapshots(context.Background(), ListBalanceSnapshotsParams{
+		AccountID: account.ID,
+		FromDate:  pastDate,
+		ToDate:    pastDate,
+	})
+	require.NoError(t, err)
+	require.Empty(t, snapshots)
+}
@@ -0,0 +1,88 @@
+package db
+
+import (
+	"context"
+	"go-backend/util"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomBeneficiary(t *testing.T, owner User, account Account) Beneficiary {
+	arg := CreateBeneficiaryParams{
+		Owner:     owner.Username,
+		AccountID: account.ID,
+		Nickname:  util.RandomString(8),
+	}
+
+	beneficiary, err := testQueries.CreateBeneficiary(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotZero(t, beneficiary.ID)
+	require.Equal(t, arg.Owner, beneficiary.Owner)
+	require.Equal(t, arg.AccountID, beneficiary.AccountID)
+	require.Equal(t, arg.Nickname, beneficiary.Nickname)
+	require.NotZero(t, beneficiary.CreatedAt)
+
+	return beneficiary
+}
+
+func TestBeneficiaries(t *testing.T) {
+	owner := createRandomUser(t)
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	beneficiary1 := createRandomBeneficiary(t, owner, account1)
+	beneficiary2 := createRandomBeneficiary(t, owner, account2)
+
+	got, err := testQueries.GetBeneficiary(context.Background(), beneficiary1.ID)
+	require.NoError(t, err)
+	require.Equal(t, beneficiary1, got)
+
+	// an account or a nickname is saved once per owner
+	_, err = testQueries.CreateBeneficiary(context.Background(), CreateBeneficiaryParams{
+		Owner:     owner.Username,
+		AccountID: account1.ID,
+		Nickname:  util.RandomString(8),
+	})
+	require.ErrorIs(t, TranslateError(err), ErrUniqueViolation)
+	_, err = testQueries.UpdateBeneficiary(context.Background(), UpdateBeneficiaryParams{
+		ID:       beneficiary2.ID,
+		Nickname: beneficiary1.Nickname,
+	})
+	require.ErrorIs(t, TranslateError(err), ErrUniqueViolation)
+
+	beneficiary1, err = testQueries.UpdateBeneficiary(context.Background(), UpdateBeneficiaryParams{
+		ID:       beneficiary1.ID,
+		Nickname: util.RandomString(8),
+		Category: "groceries",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "groceries", beneficiary1.Category)
+
+	got, err = testQueries.GetBeneficiaryByAccount(context.Background(), GetBeneficiaryByAccountParams{
+		Owner:     owner.Username,
+		AccountID: account1.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, beneficiary1, got)
+
+	beneficiaries, err := testQueries.ListBeneficiaries(context.Background(), ListBeneficiariesParams{
+		Owner:  owner.Username,
+		Limit:  5,
+		Offset: 0,
+	})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []Beneficiary{beneficiary1, beneficiary2}, beneficiaries)
+	require.Less(t, beneficiaries[0].Nickname, beneficiaries[1].Nickname)
+
+	err = testQueries.DeleteBeneficiary(context.Background(), beneficiary1.ID)
+	require.NoError(t, err)
+	_, err = testQueries.GetBeneficiary(context.Background(), beneficiary1.ID)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	// deleting the account deletes the beneficiaries saving it
+	err = testQueries.DeleteAccount(context.Background(), account2.ID)
+	require.NoError(t, err)
+	_, err = testQueries.GetBeneficiary(context.Background(), beneficiary2.ID)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
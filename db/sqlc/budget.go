@@ -0,0 +1,10 @@
+package db
+
+import "time"
+
+// BudgetMonthStart returns the start of the calendar month of a time, in UTC, since which the budgets
+// count the spending of a category.
+func BudgetMonthStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
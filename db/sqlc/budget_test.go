@@ -0,0 +1,92 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBudgets(t *testing.T) {
+	account := createRandomAccount(t)
+	owner, err := testQueries.GetUser(context.Background(), account.Owner)
+	require.NoError(t, err)
+
+	budget, err := testQueries.UpsertBudget(context.Background(), UpsertBudgetParams{
+		AccountID:    account.ID,
+		Category:     "groceries",
+		MonthlyLimit: 100,
+	})
+	require.NoError(t, err)
+
+	// setting a budget again replaces it
+	budget, err = testQueries.UpsertBudget(context.Background(), UpsertBudgetParams{
+		AccountID:    account.ID,
+		Category:     "groceries",
+		MonthlyLimit: 200,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(200), budget.MonthlyLimit)
+
+	budgets, err := testQueries.ListBudgets(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, []Budget{budget}, budgets)
+
+	// only the transfers to the beneficiaries of the owner in the category are spent in it
+	grocer := createRandomAccount(t)
+	beneficiary := createRandomBeneficiary(t, owner, grocer)
+	_, err = testQueries.UpdateBeneficiary(context.Background(), UpdateBeneficiaryParams{
+		ID:       beneficiary.ID,
+		Nickname: beneficiary.Nickname,
+		Category: "groceries",
+	})
+	require.NoError(t, err)
+	transfer := createRandomTransfer(t, account, grocer)
+	createRandomTransfer(t, account, createRandomAccount(t))
+
+	spent, err := testQueries.SumCategorySpending(context.Background(), SumCategorySpendingParams{
+		AccountID: account.ID,
+		Owner:     owner.Username,
+		Category:  "groceries",
+		Since:     BudgetMonthStart(transfer.CreatedAt),
+		Until:     transfer.CreatedAt.Add(time.Second),
+	})
+	require.NoError(t, err)
+	require.Equal(t, transfer.Amount, spent)
+
+	err = testQueries.DeleteBudget(context.Background(), DeleteBudgetParams{AccountID: account.ID, Category: "groceries"})
+	require.NoError(t, err)
+	_, err = testQueries.GetBudget(context.Background(), GetBudgetParams{AccountID: account.ID, Category: "groceries"})
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
+
+func TestAccountAlerts(t *testing.T) {
+	account := createRandomAccount(t)
+	preferences := DefaultNotificationPreferences(account.Owner)
+	preferences.LowBalanceThreshold = 50
+
+	// the threshold of the owner applies until one is set on the account
+	threshold, err := LowBalanceThresholdOf(context.Background(), testQueries, account.ID, preferences)
+	require.NoError(t, err)
+	require.Equal(t, int64(50), threshold)
+
+	_, err = testQueries.UpsertAccountAlert(context.Background(), UpsertAccountAlertParams{
+		AccountID:           account.ID,
+		LowBalanceThreshold: 0,
+	})
+	require.NoError(t, err)
+	threshold, err = LowBalanceThresholdOf(context.Background(), testQueries, account.ID, preferences)
+	require.NoError(t, err)
+	require.Zero(t, threshold)
+
+	err = testQueries.DeleteAccountAlert(context.Background(), account.ID)
+	require.NoError(t, err)
+	_, err = testQueries.GetAccountAlert(context.Background(), account.ID)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
+
+func TestBudgetMonthStart(t *testing.T) {
+	at := time.Date(2026, time.October, 16, 1, 30, 0, 0, time.FixedZone("EDT", -4*60*60))
+	require.Equal(t, time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC), BudgetMonthStart(at))
+}
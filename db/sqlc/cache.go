@@ -0,0 +1,380 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// accountCacheGenerationKey holds the generation of the cached accounts. Accounts cached under a previous
+// generation are stale, so incrementing it invalidates every account at once.
+const accountCacheGenerationKey = "account:generation"
+
+// The cachedAccount type is an account as stored in the cache, with the generation it was cached under.
+type cachedAccount struct {
+	Generation int64   `json:"generation"`
+	Account    Account `json:"account"`
+}
+
+// The CachedStore type caches the accounts read by GetAccount in Redis, which is read twice per transfer.
+// The cached account is invalidated once the transactions changing the account commit, rather than
+// within them, so that a concurrent read can't cache the balance from before the commit. The batches
+// updating every account invalidate them all. A cached account may still be stale for up to the TTL,
+// e.g. when the invalidation failed. Transfers only check the owner and currency of the cached accounts,
+// which rarely change, while their balances are updated from the locked rows within the transaction.
+type CachedStore struct {
+	Store
+	client *redis.Client
+	ttl    time.Duration
+}
+
+// The function creates a store caching the accounts read from `store` in Redis for `ttl`. The cache is
+// best effort: the store is read when Redis fails.
+func NewCachedStore(store Store, client *redis.Client, ttl time.Duration) Store {
+	return &CachedStore{
+		Store:  store,
+		client: client,
+		ttl:    ttl,
+	}
+}
+
+// GetAccount reads the account from the cache, or from the store when it isn't cached, and caches it.
+func (store *CachedStore) GetAccount(ctx context.Context, id int64) (Account, error) {
+	generation, cached, err := store.readAccount(ctx, id)
+	if err != nil {
+		log.Printf("cannot read account %d from the cache: %v", id, err)
+	}
+	if cached != nil {
+		return *cached, nil
+	}
+
+	account, err := store.Store.GetAccount(ctx, id)
+	if err != nil {
+		return account, err
+	}
+
+	// the generation is unknown when the cache couldn't be read
+	if generation >= 0 {
+		store.writeAccount(ctx, generation, account)
+	}
+	return account, nil
+}
+
+func (store *CachedStore) AdjustAccountBalanceTx(ctx context.Context, arg AdjustAccountBalanceTxParams) (TransferTxResult, error) {
+	result, err := store.Store.AdjustAccountBalanceTx(ctx, arg)
+	if err == nil {
+		store.invalidate(ctx, result.FromAccount.ID, result.ToAccount.ID)
+	}
+	return result, err
+}
+
+func (store *CachedStore) UpdateAccountProfile(ctx context.Context, arg UpdateAccountProfileParams) (Account, error) {
+	account, err := store.Store.UpdateAccountProfile(ctx, arg)
+	if err == nil {
+		store.invalidate(ctx, arg.ID)
+	}
+	return account, err
+}
+
+func (store *CachedStore) ImportEntriesTx(ctx context.Context, arg ImportEntriesTxParams) (ImportEntriesTxResult, error) {
+	result, err := store.Store.ImportEntriesTx(ctx, arg)
+	if err == nil {
+		store.invalidate(ctx, arg.AccountID)
+	}
+	return result, err
+}
+
+func (store *CachedStore) DeleteAccount(ctx context.Context, id int64) error {
+	err := store.Store.DeleteAccount(ctx, id)
+	if err == nil {
+		store.invalidate(ctx, id)
+	}
+	return err
+}
+
+func (store *CachedStore) TransferTx(ctx context.Context, arg TransferTxParams) (TransferTxResult, error) {
+	result, err := store.Store.TransferTx(ctx, arg)
+	if err == nil {
+		store.invalidate(ctx, append(feeAccount(result), arg.FromAccountID, arg.ToAccountID)...)
+	}
+	return result, err
+}
+
+func (store *CachedStore) BatchTransferTx(ctx context.Context, arg BatchTransferTxParams) ([]BatchTransferTxItem, error) {
+	items, err := store.Store.BatchTransferTx(ctx, arg)
+	if err != nil {
+		return items, err
+	}
+
+	ids := make([]int64, 0, 2*len(items))
+	for i, item := range items {
+		if item.Err == nil {
+			ids = append(ids, arg.Transfers[i].FromAccountID, arg.Transfers[i].ToAccountID)
+			ids = append(ids, feeAccount(item.Result)...)
+		}
+	}
+	store.invalidate(ctx, ids...)
+	return items, nil
+}
+
+func (store *CachedStore) ExchangeTx(ctx context.Context, arg ExchangeTxParams) (ExchangeTxResult, error) {
+	result, err := store.Store.ExchangeTx(ctx, arg)
+	if err == nil {
+		store.invalidate(ctx, arg.FromAccountID, arg.ToAccountID, result.Sold.ToAccount.ID, result.Bought.FromAccount.ID)
+	}
+	return result, err
+}
+
+func (store *CachedStore) ConvertAccountTx(ctx context.Context, arg ConvertAccountTxParams) (ConvertAccountTxResult, error) {
+	result, err := store.Store.ConvertAccountTx(ctx, arg)
+	if err == nil {
+		store.invalidate(ctx, arg.AccountID)
+	}
+	return result, err
+}
+
+func (store *CachedStore) HoldTransferTx(ctx context.Context, arg HoldTransferTxParams) (HoldTransferTxResult, error) {
+	result, err := store.Store.HoldTransferTx(ctx, arg)
+	if err == nil {
+		store.invalidate(ctx, arg.Transfer.FromAccountID)
+	}
+	return result, err
+}
+
+func (store *CachedStore) DecideTransferReviewTx(ctx context.Context, arg DecideTransferReviewTxParams) (DecideTransferReviewTxResult, error) {
+	result, err := store.Store.DecideTransferReviewTx(ctx, arg)
+	if err == nil {
+		ids := []int64{result.Review.FromAccountID, result.Review.ToAccountID}
+		if result.Transfer != nil {
+			ids = append(ids, feeAccount(*result.Transfer)...)
+		}
+		store.invalidate(ctx, ids...)
+	}
+	return result, err
+}
+
+func (store *CachedStore) AcceptPaymentRequestTx(ctx context.Context, arg AcceptPaymentRequestTxParams) (AcceptPaymentRequestTxResult, error) {
+	result, err := store.Store.AcceptPaymentRequestTx(ctx, arg)
+	if err == nil {
+		store.invalidate(ctx, arg.FromAccountID, result.PaymentRequest.ToAccountID)
+	}
+	return result, err
+}
+
+func (store *CachedStore) PayPaymentLinkTx(ctx context.Context, arg PayPaymentLinkTxParams) (PayPaymentLinkTxResult, error) {
+	result, err := store.Store.PayPaymentLinkTx(ctx, arg)
+	if err == nil {
+		store.invalidate(ctx, arg.FromAccountID, result.PaymentLink.ToAccountID)
+	}
+	return result, err
+}
+
+func (store *CachedStore) ApprovePendingTransferTx(ctx context.Context, arg ApprovePendingTransferTxParams) (ApprovePendingTransferTxResult, error) {
+	result, err := store.Store.ApprovePendingTransferTx(ctx, arg)
+	if err == nil {
+		store.invalidate(ctx, append(feeAccount(result.Transfer), result.PendingTransfer.FromAccountID, result.PendingTransfer.ToAccountID)...)
+	}
+	return result, err
+}
+
+func (store *CachedStore) PullMandateTx(ctx context.Context, arg PullMandateTxParams) (PullMandateTxResult, error) {
+	result, err := store.Store.PullMandateTx(ctx, arg)
+	if err == nil {
+		store.invalidate(ctx, result.Mandate.FromAccountID, result.Mandate.MerchantAccountID)
+	}
+	return result, err
+}
+
+func (store *CachedStore) RunStandingOrderTx(ctx context.Context, arg RunStandingOrderTxParams) (RunStandingOrderTxResult, error) {
+	result, err := store.Store.RunStandingOrderTx(ctx, arg)
+	if err == nil {
+		store.invalidate(ctx, result.StandingOrder.FromAccountID, result.StandingOrder.ToAccountID)
+	}
+	return result, err
+}
+
+func (store *CachedStore) InitiateExternalTransferTx(ctx context.Context, arg InitiateExternalTransferTxParams) (InitiateExternalTransferTxResult, error) {
+	result, err := store.Store.InitiateExternalTransferTx(ctx, arg)
+	if err == nil {
+		store.invalidate(ctx, result.Transfer.FromAccount.ID, result.Transfer.ToAccount.ID)
+	}
+	return result, err
+}
+
+func (store *CachedStore) AdvanceExternalTransferTx(ctx context.Context, arg AdvanceExternalTransferTxParams) (AdvanceExternalTransferTxResult, error) {
+	result, err := store.Store.AdvanceExternalTransferTx(ctx, arg)
+	if err == nil && result.ExternalTransfer.Status == ExternalTransferFailed {
+		store.invalidate(ctx, result.Return.FromAccount.ID, result.Return.ToAccount.ID)
+	}
+	return result, err
+}
+
+func (store *CachedStore) ReleaseHoldTx(ctx context.Context, id int64) (Hold, error) {
+	hold, err := store.Store.ReleaseHoldTx(ctx, id)
+	if err == nil {
+		store.invalidate(ctx, hold.AccountID)
+	}
+	return hold, err
+}
+
+func (store *CachedStore) ExpireHoldsTx(ctx context.Context, expiresAt time.Time) ([]Hold, error) {
+	holds, err := store.Store.ExpireHoldsTx(ctx, expiresAt)
+	if err == nil {
+		ids := make([]int64, len(holds))
+		for i, hold := range holds {
+			ids[i] = hold.AccountID
+		}
+		store.invalidate(ctx, ids...)
+	}
+	return holds, err
+}
+
+func (store *CachedStore) AuthorizeCardTx(ctx context.Context, arg AuthorizeCardTxParams) (AuthorizeCardTxResult, error) {
+	result, err := store.Store.AuthorizeCardTx(ctx, arg)
+	if err == nil {
+		store.invalidate(ctx, result.CardHold.AccountID)
+	}
+	return result, err
+}
+
+func (store *CachedStore) CaptureCardHoldTx(ctx context.Context, arg CaptureCardHoldTxParams) (CaptureCardHoldTxResult, error) {
+	result, err := store.Store.CaptureCardHoldTx(ctx, arg)
+	if err == nil {
+		store.invalidate(ctx, result.Transfer.FromAccount.ID, result.Transfer.ToAccount.ID)
+	}
+	return result, err
+}
+
+func (store *CachedStore) DepositChequeTx(ctx context.Context, arg DepositChequeTxParams) (DepositChequeTxResult, error) {
+	result, err := store.Store.DepositChequeTx(ctx, arg)
+	if err == nil {
+		store.invalidate(ctx, result.Transfer.FromAccount.ID, result.Transfer.ToAccount.ID)
+	}
+	return result, err
+}
+
+func (store *CachedStore) ClearChequeDepositTx(ctx context.Context, id int64) (ChequeDeposit, error) {
+	deposit, err := store.Store.ClearChequeDepositTx(ctx, id)
+	if err == nil {
+		store.invalidate(ctx, deposit.AccountID)
+	}
+	return deposit, err
+}
+
+func (store *CachedStore) BounceChequeDepositTx(ctx context.Context, id int64) (BounceChequeDepositTxResult, error) {
+	result, err := store.Store.BounceChequeDepositTx(ctx, id)
+	if err == nil {
+		store.invalidate(ctx, result.Reversal.FromAccount.ID, result.Reversal.ToAccount.ID)
+	}
+	return result, err
+}
+
+func (store *CachedStore) DeleteUserDataTx(ctx context.Context, username string) (DeleteUserDataTxResult, error) {
+	result, err := store.Store.DeleteUserDataTx(ctx, username)
+	if err == nil {
+		ids := make([]int64, 0, len(result.ClosedAccounts)+len(result.HandedOverAccounts))
+		for _, account := range append(result.ClosedAccounts, result.HandedOverAccounts...) {
+			ids = append(ids, account.ID)
+		}
+		store.invalidate(ctx, ids...)
+	}
+	return result, err
+}
+
+func (store *CachedStore) CapitalizeInterestTx(ctx context.Context, arg CapitalizeInterestTxParams) (BatchTxResult, error) {
+	result, err := store.Store.CapitalizeInterestTx(ctx, arg)
+	if err == nil && result.Accounts > 0 {
+		store.invalidateAll(ctx)
+	}
+	return result, err
+}
+
+func (store *CachedStore) ChargeMonthlyFeeTx(ctx context.Context, arg ChargeMonthlyFeeTxParams) (BatchTxResult, error) {
+	result, err := store.Store.ChargeMonthlyFeeTx(ctx, arg)
+	if err == nil && result.Accounts > 0 {
+		store.invalidateAll(ctx)
+	}
+	return result, err
+}
+
+// readAccount returns the current generation and the account cached under it, nil when it isn't cached.
+// The generation is -1 when the cache couldn't be read.
+func (store *CachedStore) readAccount(ctx context.Context, id int64) (int64, *Account, error) {
+	values, err := store.client.MGet(ctx, accountCacheGenerationKey, accountCacheKey(id)).Result()
+	if err != nil {
+		return -1, nil, err
+	}
+
+	var generation int64
+	if value, ok := values[0].(string); ok {
+		generation, err = strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return -1, nil, fmt.Errorf("invalid generation %q: %w", value, err)
+		}
+	}
+
+	value, ok := values[1].(string)
+	if !ok {
+		return generation, nil, nil
+	}
+
+	var cached cachedAccount
+	err = json.Unmarshal([]byte(value), &cached)
+	if err != nil {
+		return generation, nil, err
+	}
+	if cached.Generation != generation {
+		return generation, nil, nil
+	}
+	return generation, &cached.Account, nil
+}
+
+func (store *CachedStore) writeAccount(ctx context.Context, generation int64, account Account) {
+	value, err := json.Marshal(cachedAccount{Generation: generation, Account: account})
+	if err == nil {
+		err = store.client.Set(ctx, accountCacheKey(account.ID), value, store.ttl).Err()
+	}
+	if err != nil {
+		log.Printf("cannot cache account %d: %v", account.ID, err)
+	}
+}
+
+func (store *CachedStore) invalidate(ctx context.Context, ids ...int64) {
+	if len(ids) == 0 {
+		return
+	}
+
+	keys := make([]string, 0, len(ids))
+	for _, id := range ids {
+		keys = append(keys, accountCacheKey(id))
+	}
+
+	err := store.client.Del(ctx, keys...).Err()
+	if err != nil {
+		log.Printf("cannot invalidate cached accounts %v: %v", ids, err)
+	}
+}
+
+func (store *CachedStore) invalidateAll(ctx context.Context) {
+	err := store.client.Incr(ctx, accountCacheGenerationKey).Err()
+	if err != nil {
+		log.Printf("cannot invalidate cached accounts: %v", err)
+	}
+}
+
+func accountCacheKey(id int64) string {
+	return fmt.Sprintf("account:%d", id)
+}
+
+// feeAccount returns the fees account a transfer charged its fee to, if it did.
+func feeAccount(result TransferTxResult) []int64 {
+	if result.Fee == nil {
+		return nil
+	}
+	return []int64{result.Fee.ToAccountID}
+}
@@ -0,0 +1,73 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCachedStoreGetAccount(t *testing.T) {
+	store := NewCachedStore(NewStore(testDB), testRedis, time.Minute)
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	cached, err := store.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance, cached.Balance)
+
+	// a write bypassing the store isn't seen until the account is invalidated
+	postEntry(t, account1.ID, 5)
+
+	cached, err = store.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance, cached.Balance)
+
+	// a transfer invalidates both accounts
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	})
+	require.NoError(t, err)
+
+	cached, err = store.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance+5-10, cached.Balance)
+
+	// so does a change of its nickname or tier, the cached version being the one of its ETag
+	renamed, err := store.UpdateAccountProfile(context.Background(), UpdateAccountProfileParams{
+		Nickname: pgtype.Text{String: "Savings", Valid: true},
+		ID:       account1.ID,
+	})
+	require.NoError(t, err)
+
+	cached, err = store.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, "Savings", cached.Nickname)
+	require.Equal(t, renamed.Version, cached.Version)
+
+	// the batches invalidate every account
+	postEntry(t, account1.ID, 5)
+	store.(*CachedStore).invalidateAll(context.Background())
+
+	cached, err = store.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance, cached.Balance)
+}
+
+func TestCachedStoreWithoutRedis(t *testing.T) {
+	// reads fall back to the store while the cache is down
+	client := redis.NewClient(&redis.Options{Addr: "localhost:1"})
+	defer client.Close()
+
+	store := NewCachedStore(NewStore(testDB), client, time.Minute)
+	account := createRandomAccount(t)
+
+	got, err := store.GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, account.ID, got.ID)
+}
@@ -0,0 +1,134 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Statuses of a card. A frozen card declines every authorization until it is unfrozen.
+const (
+	CardActive = "active"
+	CardFrozen = "frozen"
+)
+
+// ErrCardFrozen is returned when authorizing a frozen card.
+var ErrCardFrozen = errors.New("card is frozen")
+
+// The AuthorizeCardTxParams type contains the authorization of a card by a merchant.
+// @property {int64} CardID - the card authorized, which must be active.
+// @property {int64} Amount - the positive amount reserved on the account of the card.
+// @property {string} Currency - the currency of the account of the card, the authorization failing with
+// ErrAccountVersionMismatch once it holds another.
+// @property {time.Time} ExpiresAt - the hold is released past this time unless captured.
+type AuthorizeCardTxParams struct {
+	CardID    int64
+	Amount    int64
+	Currency  string
+	Merchant  string
+	ExpiresAt time.Time
+}
+
+// The AuthorizeCardTxResult type is the hold placed by an authorization.
+// @property {int64} AvailableBalance - the balance of the account less its held balance, this hold
+// included.
+type AuthorizeCardTxResult struct {
+	CardHold         Hold  `json:"card_hold"`
+	AvailableBalance int64 `json:"available_balance"`
+}
+
+// AuthorizeCardTx places a hold reserving the amount on the account of the card, see placeHold, once it
+// checked the card is active. The card and its account are locked, ErrCardFrozen being returned for a
+// frozen card and ErrInsufficientAvailableBalance for a declined amount.
+func (store *SQLStore) AuthorizeCardTx(ctx context.Context, arg AuthorizeCardTxParams) (AuthorizeCardTxResult, error) {
+	var result AuthorizeCardTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		card, err := q.GetCardForUpdate(ctx, arg.CardID)
+		if err != nil {
+			return err
+		}
+		if card.Status != CardActive {
+			return ErrCardFrozen
+		}
+
+		account, err := q.GetAccountForUpdate(ctx, card.AccountID)
+		if err != nil {
+			return err
+		}
+		if account.Currency != arg.Currency {
+			return ErrAccountVersionMismatch
+		}
+
+		placed, err := placeHold(ctx, q, account, pgtype.Int8{Int64: card.ID, Valid: true}, placeHoldParams{
+			Amount:    arg.Amount,
+			Memo:      arg.Merchant,
+			ExpiresAt: arg.ExpiresAt,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.CardHold = placed.Hold
+		result.AvailableBalance = AvailableBalance(placed.Account)
+		return nil
+	})
+
+	return result, err
+}
+
+// The CaptureCardHoldTxParams type contains the capture of a card hold.
+// @property {int64} Amount - the positive amount settled, at most the amount of the hold. The rest of the
+// hold is released.
+type CaptureCardHoldTxParams struct {
+	ID     int64
+	Amount int64
+}
+
+// The CaptureCardHoldTxResult type is the captured hold, along with the transfer settling it.
+type CaptureCardHoldTxResult struct {
+	CardHold Hold             `json:"card_hold"`
+	Transfer TransferTxResult `json:"transfer"`
+}
+
+// CaptureCardHoldTx settles a hold placed by a card authorization, see settleHold, moving the captured
+// amount from the account of the card to the card_settlement account of its currency.
+func (store *SQLStore) CaptureCardHoldTx(ctx context.Context, arg CaptureCardHoldTxParams) (CaptureCardHoldTxResult, error) {
+	var result CaptureCardHoldTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		hold, err := q.GetHold(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+		// the other holds aren't card holds
+		if !hold.CardID.Valid {
+			return ErrRecordNotFound
+		}
+
+		settlement, err := q.GetSystemAccount(ctx, GetSystemAccountParams{
+			Purpose:  SystemAccountCardSettlement,
+			Currency: hold.Currency,
+		})
+		if err != nil {
+			return err
+		}
+
+		captured, err := settleHold(ctx, q, settleHoldParams{
+			ID:          hold.ID,
+			Amount:      arg.Amount,
+			ToAccountID: settlement.ID,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.CardHold = captured.Hold
+		result.Transfer = captured.Transfer
+		return nil
+	})
+
+	return result, err
+}
@@ -0,0 +1,117 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomCard(t *testing.T, account Account) Card {
+	card, err := testQueries.CreateCard(context.Background(), CreateCardParams{
+		AccountID:   account.ID,
+		Owner:       account.Owner,
+		Last4:       "4242",
+		ExpiryMonth: 12,
+		ExpiryYear:  int32(time.Now().Year() + 3),
+	})
+	require.NoError(t, err)
+	require.Equal(t, CardActive, card.Status)
+	return card
+}
+
+func authorizeCard(store Store, card Card, currency string, amount int64) (AuthorizeCardTxResult, error) {
+	return store.AuthorizeCardTx(context.Background(), AuthorizeCardTxParams{
+		CardID:    card.ID,
+		Amount:    amount,
+		Currency:  currency,
+		Merchant:  "Coffee Shop",
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+}
+
+func TestAuthorizeCardTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	account := createRandomAccount(t)
+	card := createRandomCard(t, account)
+
+	// the hold reserves the amount without changing the balance
+	result, err := authorizeCard(store, card, account.Currency, account.Balance-1)
+	require.NoError(t, err)
+	require.Equal(t, HoldAuthorized, result.CardHold.Status)
+	require.Equal(t, card.ID, result.CardHold.CardID.Int64)
+	require.Equal(t, int64(1), result.AvailableBalance)
+
+	got, err := testQueries.GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, account.Balance, got.Balance)
+	require.Equal(t, account.Balance-1, got.HeldBalance)
+
+	// the next authorization is declined above the available balance
+	_, err = authorizeCard(store, card, account.Currency, 2)
+	require.ErrorIs(t, err, ErrInsufficientAvailableBalance)
+
+	// a released hold no longer reserves its amount
+	_, err = store.ReleaseHoldTx(context.Background(), result.CardHold.ID)
+	require.NoError(t, err)
+	_, err = authorizeCard(store, card, account.Currency, 2)
+	require.NoError(t, err)
+
+	_, err = testQueries.UpdateCardStatus(context.Background(), UpdateCardStatusParams{ID: card.ID, Status: CardFrozen})
+	require.NoError(t, err)
+	_, err = authorizeCard(store, card, account.Currency, 1)
+	require.ErrorIs(t, err, ErrCardFrozen)
+}
+
+func TestCaptureCardHoldTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	account := createRandomAccount(t)
+	card := createRandomCard(t, account)
+	authorized, err := authorizeCard(store, card, account.Currency, 10)
+	require.NoError(t, err)
+
+	_, err = store.CaptureCardHoldTx(context.Background(), CaptureCardHoldTxParams{ID: authorized.CardHold.ID, Amount: 11})
+	require.ErrorIs(t, err, ErrCaptureExceedsHold)
+
+	// a partial capture settles the amount captured only
+	result, err := store.CaptureCardHoldTx(context.Background(), CaptureCardHoldTxParams{ID: authorized.CardHold.ID, Amount: 8})
+	require.NoError(t, err)
+	require.Equal(t, HoldCaptured, result.CardHold.Status)
+	require.Equal(t, int64(8), result.CardHold.CapturedAmount)
+	require.Equal(t, result.Transfer.Transfer.ID, result.CardHold.TransferID.Int64)
+	require.Equal(t, account.Balance-8, result.Transfer.FromAccount.Balance)
+	require.True(t, IsSystemAccount(result.Transfer.ToAccount))
+
+	// the rest of the hold is released
+	require.Zero(t, result.Transfer.FromAccount.HeldBalance)
+
+	// a hold is captured once
+	_, err = store.CaptureCardHoldTx(context.Background(), CaptureCardHoldTxParams{ID: authorized.CardHold.ID, Amount: 2})
+	require.ErrorIs(t, err, ErrHoldClosed)
+}
+
+func TestExpireHoldsTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	account := createRandomAccount(t)
+	card := createRandomCard(t, account)
+	authorized, err := authorizeCard(store, card, account.Currency, 10)
+	require.NoError(t, err)
+
+	holds, err := store.ExpireHoldsTx(context.Background(), authorized.CardHold.ExpiresAt)
+	require.NoError(t, err)
+	require.NotEmpty(t, holds)
+
+	hold, err := testQueries.GetHold(context.Background(), authorized.CardHold.ID)
+	require.NoError(t, err)
+	require.Equal(t, HoldExpired, hold.Status)
+	require.True(t, hold.SettledAt.Valid)
+
+	// the expired hold no longer reserves its amount
+	got, err := testQueries.GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Zero(t, got.HeldBalance)
+}
@@ -0,0 +1,185 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Statuses of a cheque deposit. The amount of a pending cheque is credited to the account but held until
+// the cheque clears, unless it bounces first, which reverses the credit. The other statuses are final.
+const (
+	ChequeDepositPending = "pending"
+	ChequeDepositCleared = "cleared"
+	ChequeDepositBounced = "bounced"
+)
+
+// ErrChequeDepositClosed is returned when bouncing or clearing a cheque that is no longer pending, or
+// bouncing one whose clearing period is over.
+var ErrChequeDepositClosed = errors.New("cheque deposit is no longer pending")
+
+// The DepositChequeTxParams type contains a cheque deposited on an account.
+// @property {int64} Amount - the positive amount of the cheque, in the currency of the account.
+// @property {string} ChequeNumber - the number printed on the cheque.
+// @property {time.Time} ClearsAt - the amount is held on the account until this time, when the cheque
+// clears unless it bounced.
+type DepositChequeTxParams struct {
+	AccountID    int64
+	Owner        string
+	Amount       int64
+	ChequeNumber string
+	ClearsAt     time.Time
+}
+
+// The DepositChequeTxResult type is the cheque deposited, along with the transfer crediting its amount
+// and the hold reserving it.
+type DepositChequeTxResult struct {
+	ChequeDeposit ChequeDeposit    `json:"cheque_deposit"`
+	Transfer      TransferTxResult `json:"transfer"`
+	Hold          Hold             `json:"hold"`
+}
+
+// DepositChequeTx credits the amount of the cheque to the account from the cheque_clearing account of its
+// currency and places a hold on it until the cheque clears, so that the balance of the account shows it
+// but its available balance doesn't.
+func (store *SQLStore) DepositChequeTx(ctx context.Context, arg DepositChequeTxParams) (DepositChequeTxResult, error) {
+	var result DepositChequeTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		account, err := q.GetAccountForUpdate(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+
+		clearing, err := q.GetSystemAccount(ctx, GetSystemAccountParams{
+			Purpose:  SystemAccountChequeClearing,
+			Currency: account.Currency,
+		})
+		if err != nil {
+			return err
+		}
+
+		memo := fmt.Sprintf("cheque %s", arg.ChequeNumber)
+		result.Transfer, err = transfer(ctx, q, TransferTxParams{
+			FromAccountID: clearing.ID,
+			ToAccountID:   account.ID,
+			Amount:        arg.Amount,
+			Memo:          memo,
+		}, nil)
+		if err != nil {
+			return err
+		}
+
+		placed, err := placeHold(ctx, q, result.Transfer.ToAccount, pgtype.Int8{}, placeHoldParams{
+			Amount:    arg.Amount,
+			Memo:      memo,
+			ExpiresAt: arg.ClearsAt,
+		})
+		if err != nil {
+			return err
+		}
+		result.Hold = placed.Hold
+		result.Transfer.ToAccount = placed.Account
+
+		result.ChequeDeposit, err = q.CreateChequeDeposit(ctx, CreateChequeDepositParams{
+			AccountID:    account.ID,
+			Owner:        arg.Owner,
+			Amount:       arg.Amount,
+			Currency:     account.Currency,
+			ChequeNumber: arg.ChequeNumber,
+			TransferID:   result.Transfer.Transfer.ID,
+			HoldID:       placed.Hold.ID,
+			ClearsAt:     arg.ClearsAt,
+		})
+		return err
+	})
+
+	return result, err
+}
+
+// ClearChequeDepositTx clears a pending cheque, releasing the hold on its amount, which the account can
+// then spend. The hold was released already when it expired before the cheque cleared. The cheque deposit
+// is locked so that it isn't bounced at the same time, ErrChequeDepositClosed being returned when it is
+// no longer pending.
+func (store *SQLStore) ClearChequeDepositTx(ctx context.Context, id int64) (ChequeDeposit, error) {
+	var deposit ChequeDeposit
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		deposit, err = q.GetChequeDepositForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if deposit.Status != ChequeDepositPending {
+			return ErrChequeDepositClosed
+		}
+
+		_, err = freeHold(ctx, q, deposit.HoldID)
+		if err != nil && !errors.Is(err, ErrHoldClosed) {
+			return err
+		}
+
+		deposit, err = q.ClearChequeDeposit(ctx, id)
+		return err
+	})
+
+	return deposit, err
+}
+
+// The BounceChequeDepositTxResult type is the bounced cheque, along with the transfer reversing its
+// credit.
+type BounceChequeDepositTxResult struct {
+	ChequeDeposit ChequeDeposit    `json:"cheque_deposit"`
+	Reversal      TransferTxResult `json:"reversal"`
+}
+
+// BounceChequeDepositTx reverses the credit of a pending cheque the bank of the drawer refused to pay,
+// capturing the hold on its amount to the cheque_clearing account it was credited from. The cheque
+// deposit is locked so that it isn't cleared at the same time, ErrChequeDepositClosed being returned
+// when it is no longer pending or its clearing period is over.
+func (store *SQLStore) BounceChequeDepositTx(ctx context.Context, id int64) (BounceChequeDepositTxResult, error) {
+	var result BounceChequeDepositTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		deposit, err := q.GetChequeDepositForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if deposit.Status != ChequeDepositPending {
+			return ErrChequeDepositClosed
+		}
+
+		clearing, err := q.GetSystemAccount(ctx, GetSystemAccountParams{
+			Purpose:  SystemAccountChequeClearing,
+			Currency: deposit.Currency,
+		})
+		if err != nil {
+			return err
+		}
+
+		captured, err := settleHold(ctx, q, settleHoldParams{
+			ID:          deposit.HoldID,
+			Amount:      deposit.Amount,
+			ToAccountID: clearing.ID,
+		})
+		if err != nil {
+			// the hold expired at the end of the clearing period
+			if errors.Is(err, ErrHoldClosed) || errors.Is(err, ErrHoldExpired) {
+				return ErrChequeDepositClosed
+			}
+			return err
+		}
+		result.Reversal = captured.Transfer
+
+		result.ChequeDeposit, err = q.BounceChequeDeposit(ctx, BounceChequeDepositParams{
+			ID:                 id,
+			ReversalTransferID: pgtype.Int8{Int64: captured.Transfer.Transfer.ID, Valid: true},
+		})
+		return err
+	})
+
+	return result, err
+}
@@ -0,0 +1,79 @@
+package db
+
+import (
+	"context"
+	"go-backend/util"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func depositTestCheque(t *testing.T, store Store, account Account, amount int64) DepositChequeTxResult {
+	result, err := store.DepositChequeTx(context.Background(), DepositChequeTxParams{
+		AccountID:    account.ID,
+		Owner:        account.Owner,
+		Amount:       amount,
+		ChequeNumber: util.RandomString(8),
+		ClearsAt:     time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+	return result
+}
+
+func TestDepositChequeTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	account := createRandomAccount(t)
+	result := depositTestCheque(t, store, account, 100)
+	require.Equal(t, ChequeDepositPending, result.ChequeDeposit.Status)
+	require.Equal(t, result.Transfer.Transfer.ID, result.ChequeDeposit.TransferID)
+	require.Equal(t, result.Hold.ID, result.ChequeDeposit.HoldID)
+	require.Equal(t, HoldAuthorized, result.Hold.Status)
+
+	// the amount is credited but can't be spent until the cheque clears
+	require.Equal(t, account.Balance+100, result.Transfer.ToAccount.Balance)
+	require.Equal(t, int64(100), result.Transfer.ToAccount.HeldBalance)
+	require.Equal(t, account.Balance, AvailableBalance(result.Transfer.ToAccount))
+}
+
+func TestClearChequeDepositTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	account := createRandomAccount(t)
+	deposited := depositTestCheque(t, store, account, 100)
+
+	deposit, err := store.ClearChequeDepositTx(context.Background(), deposited.ChequeDeposit.ID)
+	require.NoError(t, err)
+	require.Equal(t, ChequeDepositCleared, deposit.Status)
+	require.True(t, deposit.SettledAt.Valid)
+
+	got, err := testQueries.GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Zero(t, got.HeldBalance)
+	require.Equal(t, account.Balance+100, AvailableBalance(got))
+
+	// a cleared cheque can't bounce
+	_, err = store.BounceChequeDepositTx(context.Background(), deposited.ChequeDeposit.ID)
+	require.ErrorIs(t, err, ErrChequeDepositClosed)
+}
+
+func TestBounceChequeDepositTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	account := createRandomAccount(t)
+	deposited := depositTestCheque(t, store, account, 100)
+
+	result, err := store.BounceChequeDepositTx(context.Background(), deposited.ChequeDeposit.ID)
+	require.NoError(t, err)
+	require.Equal(t, ChequeDepositBounced, result.ChequeDeposit.Status)
+	require.Equal(t, result.Reversal.Transfer.ID, result.ChequeDeposit.ReversalTransferID.Int64)
+	require.Equal(t, deposited.Transfer.Transfer.FromAccountID, result.Reversal.Transfer.ToAccountID)
+
+	// the credit is reversed
+	require.Equal(t, account.Balance, result.Reversal.FromAccount.Balance)
+	require.Zero(t, result.Reversal.FromAccount.HeldBalance)
+
+	_, err = store.ClearChequeDepositTx(context.Background(), deposited.ChequeDeposit.ID)
+	require.ErrorIs(t, err, ErrChequeDepositClosed)
+}
@@ -0,0 +1,74 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDashboardQueries(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := createRandomAccount(t)
+	account2, err := testQueries.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:    account1.Owner,
+		Balance:  100,
+		Currency: account1.Currency,
+	})
+	require.NoError(t, err)
+	recipient := createRandomUser(t)
+	account3, err := testQueries.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:    recipient.Username,
+		Currency: account1.Currency,
+	})
+	require.NoError(t, err)
+
+	since := time.Now().Add(-time.Minute)
+	// moving money between the accounts of the owner isn't spending
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	})
+	require.NoError(t, err)
+	sent, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account3.ID,
+		Amount:        20,
+	})
+	require.NoError(t, err)
+	placeTestHold(t, store, account1, 5)
+
+	balances, err := testQueries.SumOwnerBalances(context.Background(), account1.Owner)
+	require.NoError(t, err)
+	require.Len(t, balances, 1)
+	require.Equal(t, account1.Currency, balances[0].Currency)
+	require.Equal(t, int64(2), balances[0].AccountCount)
+	require.Equal(t, account1.Balance+100-20, balances[0].Balance)
+	require.Equal(t, account1.Balance+100-20-5, balances[0].AvailableBalance)
+
+	entries, err := testQueries.ListOwnerRecentEntries(context.Background(), ListOwnerRecentEntriesParams{
+		Owner: account1.Owner,
+		Limit: 2,
+	})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	require.Equal(t, sent.FromEntry.ID, entries[0].Entry.ID)
+	require.Equal(t, account3.ID, entries[0].CounterpartyAccountID.Int64)
+
+	spending, err := testQueries.SumOwnerSpending(context.Background(), SumOwnerSpendingParams{
+		Owner: account1.Owner,
+		Since: since,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []SumOwnerSpendingRow{{Currency: account1.Currency, Spent: 20}}, spending)
+
+	pending, err := testQueries.CountOwnerPendingItems(context.Background(), account1.Owner)
+	require.NoError(t, err)
+	require.Equal(t, CountOwnerPendingItemsRow{Holds: 1}, pending)
+
+	pending, err = testQueries.CountOwnerPendingItems(context.Background(), recipient.Username)
+	require.NoError(t, err)
+	require.Zero(t, pending)
+}
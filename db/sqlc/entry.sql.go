@@ -7,53 +7,123 @@ package db
 
 import (
 	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const countEntries = `-- name: CountEntries :one
+SELECT count(*) FROM entries
+WHERE account_id = $1
+`
+
+func (q *Queries) CountEntries(ctx context.Context, accountID int64) (int64, error) {
+	row := q.db.QueryRow(ctx, countEntries, accountID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countEntriesInRange = `-- name: CountEntriesInRange :one
+SELECT count(*) FROM entries
+WHERE
+    account_id = $1 AND
+    created_at >= $2 AND
+    created_at < $3
+`
+
+type CountEntriesInRangeParams struct {
+	AccountID int64     `json:"account_id"`
+	FromTime  time.Time `json:"from_time"`
+	ToTime    time.Time `json:"to_time"`
+}
+
+func (q *Queries) CountEntriesInRange(ctx context.Context, arg CountEntriesInRangeParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countEntriesInRange, arg.AccountID, arg.FromTime, arg.ToTime)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createEntry = `-- name: CreateEntry :one
 INSERT INTO entries (
   account_id,
-  amount
+  amount,
+  transfer_id
 ) VALUES (
-  $1, $2
-) RETURNING id, account_id, amount, created_at
+  $1, $2, $3
+) RETURNING id, account_id, amount, created_at, transfer_id
 `
 
 type CreateEntryParams struct {
-	AccountID int64 `json:"account_id"`
-	Amount    int64 `json:"amount"`
+	AccountID  int64       `json:"account_id"`
+	Amount     int64       `json:"amount"`
+	TransferID pgtype.Int8 `json:"transfer_id"`
 }
 
 func (q *Queries) CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry, error) {
-	row := q.db.QueryRowContext(ctx, createEntry, arg.AccountID, arg.Amount)
+	row := q.db.QueryRow(ctx, createEntry, arg.AccountID, arg.Amount, arg.TransferID)
 	var i Entry
 	err := row.Scan(
 		&i.ID,
 		&i.AccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.TransferID,
+	)
+	return i, err
+}
+
+const createHistoricalEntry = `-- name: CreateHistoricalEntry :one
+INSERT INTO entries (
+  account_id,
+  amount,
+  created_at
+) VALUES (
+  $1, $2, $3
+) RETURNING id, account_id, amount, created_at, transfer_id
+`
+
+type CreateHistoricalEntryParams struct {
+	AccountID int64     `json:"account_id"`
+	Amount    int64     `json:"amount"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Creates an entry made before it was recorded, e.g. imported from another system.
+func (q *Queries) CreateHistoricalEntry(ctx context.Context, arg CreateHistoricalEntryParams) (Entry, error) {
+	row := q.db.QueryRow(ctx, createHistoricalEntry, arg.AccountID, arg.Amount, arg.CreatedAt)
+	var i Entry
+	err := row.Scan(
+		&i.ID,
+		&i.AccountID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.TransferID,
 	)
 	return i, err
 }
 
 const getEntry = `-- name: GetEntry :one
-SELECT id, account_id, amount, created_at FROM entries
+SELECT id, account_id, amount, created_at, transfer_id FROM entries
 WHERE id = $1 LIMIT 1
 `
 
 func (q *Queries) GetEntry(ctx context.Context, id int64) (Entry, error) {
-	row := q.db.QueryRowContext(ctx, getEntry, id)
+	row := q.db.QueryRow(ctx, getEntry, id)
 	var i Entry
 	err := row.Scan(
 		&i.ID,
 		&i.AccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.TransferID,
 	)
 	return i, err
 }
 
 const listEntries = `-- name: ListEntries :many
-SELECT id, account_id, amount, created_at FROM entries
+SELECT id, account_id, amount, created_at, transfer_id FROM entries
 WHERE account_id = $1
 ORDER BY id
 LIMIT $2
@@ -67,7 +137,136 @@ type ListEntriesParams struct {
 }
 
 func (q *Queries) ListEntries(ctx context.Context, arg ListEntriesParams) ([]Entry, error) {
-	rows, err := q.db.QueryContext(ctx, listEntries, arg.AccountID, arg.Limit, arg.Offset)
+	rows, err := q.db.Query(ctx, listEntries, arg.AccountID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Entry{}
+	for rows.Next() {
+		var i Entry
+		if err := rows.Scan(
+			&i.ID,
+			&i.AccountID,
+			&i.Amount,
+			&i.CreatedAt,
+			&i.TransferID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listEntriesForExport = `-- name: ListEntriesForExport :many
+SELECT
+    entries.id, entries.account_id, entries.amount, entries.created_at, entries.transfer_id,
+    counterparty.id AS counterparty_account_id,
+    counterparty.owner AS counterparty_username,
+    users.full_name AS counterparty_full_name,
+    transfers.memo AS transfer_memo,
+    transfers.external_reference AS transfer_external_reference
+FROM entries
+LEFT JOIN transfers ON transfers.id = entries.transfer_id
+LEFT JOIN accounts AS counterparty ON counterparty.id = CASE
+    WHEN transfers.from_account_id = entries.account_id THEN transfers.to_account_id
+    ELSE transfers.from_account_id
+END
+LEFT JOIN users ON users.username = counterparty.owner
+WHERE
+    entries.account_id = $1 AND
+    entries.created_at >= $2 AND
+    entries.created_at < $3
+ORDER BY entries.id
+LIMIT $4
+`
+
+type ListEntriesForExportParams struct {
+	AccountID int64     `json:"account_id"`
+	FromTime  time.Time `json:"from_time"`
+	ToTime    time.Time `json:"to_time"`
+	RowLimit  int32     `json:"row_limit"`
+}
+
+type ListEntriesForExportRow struct {
+	Entry                     Entry       `json:"entry"`
+	CounterpartyAccountID     pgtype.Int8 `json:"counterparty_account_id"`
+	CounterpartyUsername      pgtype.Text `json:"counterparty_username"`
+	CounterpartyFullName      pgtype.Text `json:"counterparty_full_name"`
+	TransferMemo              pgtype.Text `json:"transfer_memo"`
+	TransferExternalReference pgtype.Text `json:"transfer_external_reference"`
+}
+
+// Lists the entries of an account made in a period, with the transfer each was made for and the other
+// account of the transfer, for the personal finance files. The transfer is null for the entries made
+// without one.
+func (q *Queries) ListEntriesForExport(ctx context.Context, arg ListEntriesForExportParams) ([]ListEntriesForExportRow, error) {
+	rows, err := q.db.Query(ctx, listEntriesForExport,
+		arg.AccountID,
+		arg.FromTime,
+		arg.ToTime,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListEntriesForExportRow{}
+	for rows.Next() {
+		var i ListEntriesForExportRow
+		if err := rows.Scan(
+			&i.Entry.ID,
+			&i.Entry.AccountID,
+			&i.Entry.Amount,
+			&i.Entry.CreatedAt,
+			&i.Entry.TransferID,
+			&i.CounterpartyAccountID,
+			&i.CounterpartyUsername,
+			&i.CounterpartyFullName,
+			&i.TransferMemo,
+			&i.TransferExternalReference,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listEntriesInRange = `-- name: ListEntriesInRange :many
+SELECT id, account_id, amount, created_at, transfer_id FROM entries
+WHERE
+    account_id = $1 AND
+    created_at >= $2 AND
+    created_at < $3
+ORDER BY id
+LIMIT $4
+OFFSET $5
+`
+
+type ListEntriesInRangeParams struct {
+	AccountID int64     `json:"account_id"`
+	FromTime  time.Time `json:"from_time"`
+	ToTime    time.Time `json:"to_time"`
+	RowLimit  int32     `json:"row_limit"`
+	RowOffset int32     `json:"row_offset"`
+}
+
+func (q *Queries) ListEntriesInRange(ctx context.Context, arg ListEntriesInRangeParams) ([]Entry, error) {
+	rows, err := q.db.Query(ctx, listEntriesInRange,
+		arg.AccountID,
+		arg.FromTime,
+		arg.ToTime,
+		arg.RowLimit,
+		arg.RowOffset,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -80,16 +279,282 @@ func (q *Queries) ListEntries(ctx context.Context, arg ListEntriesParams) ([]Ent
 			&i.AccountID,
 			&i.Amount,
 			&i.CreatedAt,
+			&i.TransferID,
 		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
 	}
-	if err := rows.Close(); err != nil {
+	if err := rows.Err(); err != nil {
 		return nil, err
 	}
+	return items, nil
+}
+
+const listEntriesWithCounterparty = `-- name: ListEntriesWithCounterparty :many
+SELECT
+    entries.id, entries.account_id, entries.amount, entries.created_at, entries.transfer_id,
+    counterparty.id AS counterparty_account_id,
+    counterparty.owner AS counterparty_username,
+    users.full_name AS counterparty_full_name,
+    counterparty.nickname AS counterparty_nickname,
+    accounts.currency
+FROM entries
+JOIN accounts ON accounts.id = entries.account_id
+LEFT JOIN transfers ON transfers.id = entries.transfer_id
+LEFT JOIN accounts AS counterparty ON counterparty.id = CASE
+    WHEN transfers.from_account_id = entries.account_id THEN transfers.to_account_id
+    ELSE transfers.from_account_id
+END
+LEFT JOIN users ON users.username = counterparty.owner
+WHERE entries.account_id = $1
+ORDER BY entries.id
+LIMIT $2
+OFFSET $3
+`
+
+type ListEntriesWithCounterpartyParams struct {
+	AccountID int64 `json:"account_id"`
+	Limit     int32 `json:"limit"`
+	Offset    int32 `json:"offset"`
+}
+
+type ListEntriesWithCounterpartyRow struct {
+	Entry                 Entry       `json:"entry"`
+	CounterpartyAccountID pgtype.Int8 `json:"counterparty_account_id"`
+	CounterpartyUsername  pgtype.Text `json:"counterparty_username"`
+	CounterpartyFullName  pgtype.Text `json:"counterparty_full_name"`
+	CounterpartyNickname  pgtype.Text `json:"counterparty_nickname"`
+	Currency              string      `json:"currency"`
+}
+
+// Lists the entries of an account with the other account of the transfer each was made for, its
+// nickname and its owner. The counterparty is null for the entries made without a transfer. Each entry comes with the
+// currency of its account.
+func (q *Queries) ListEntriesWithCounterparty(ctx context.Context, arg ListEntriesWithCounterpartyParams) ([]ListEntriesWithCounterpartyRow, error) {
+	rows, err := q.db.Query(ctx, listEntriesWithCounterparty, arg.AccountID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListEntriesWithCounterpartyRow{}
+	for rows.Next() {
+		var i ListEntriesWithCounterpartyRow
+		if err := rows.Scan(
+			&i.Entry.ID,
+			&i.Entry.AccountID,
+			&i.Entry.Amount,
+			&i.Entry.CreatedAt,
+			&i.Entry.TransferID,
+			&i.CounterpartyAccountID,
+			&i.CounterpartyUsername,
+			&i.CounterpartyFullName,
+			&i.CounterpartyNickname,
+			&i.Currency,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
 	return items, nil
 }
+
+const listEntriesWithCounterpartyBefore = `-- name: ListEntriesWithCounterpartyBefore :many
+SELECT
+    entries.id, entries.account_id, entries.amount, entries.created_at, entries.transfer_id,
+    counterparty.id AS counterparty_account_id,
+    counterparty.owner AS counterparty_username,
+    users.full_name AS counterparty_full_name,
+    counterparty.nickname AS counterparty_nickname,
+    accounts.currency
+FROM entries
+JOIN accounts ON accounts.id = entries.account_id
+LEFT JOIN transfers ON transfers.id = entries.transfer_id
+LEFT JOIN accounts AS counterparty ON counterparty.id = CASE
+    WHEN transfers.from_account_id = entries.account_id THEN transfers.to_account_id
+    ELSE transfers.from_account_id
+END
+LEFT JOIN users ON users.username = counterparty.owner
+WHERE
+    entries.account_id = $1 AND
+    ($2::bigint IS NULL OR entries.id < $2)
+ORDER BY entries.id DESC
+LIMIT $3
+`
+
+type ListEntriesWithCounterpartyBeforeParams struct {
+	AccountID int64       `json:"account_id"`
+	Cursor    pgtype.Int8 `json:"cursor"`
+	RowLimit  int32       `json:"row_limit"`
+}
+
+type ListEntriesWithCounterpartyBeforeRow struct {
+	Entry                 Entry       `json:"entry"`
+	CounterpartyAccountID pgtype.Int8 `json:"counterparty_account_id"`
+	CounterpartyUsername  pgtype.Text `json:"counterparty_username"`
+	CounterpartyFullName  pgtype.Text `json:"counterparty_full_name"`
+	CounterpartyNickname  pgtype.Text `json:"counterparty_nickname"`
+	Currency              string      `json:"currency"`
+}
+
+// Lists the entries of an account like ListEntriesWithCounterparty, newest first and a page at a time by
+// their id: the page after a cursor, the id of the last entry of the previous page, holds the entries
+// made before it. The first page, without a cursor, starts with the newest entry.
+func (q *Queries) ListEntriesWithCounterpartyBefore(ctx context.Context, arg ListEntriesWithCounterpartyBeforeParams) ([]ListEntriesWithCounterpartyBeforeRow, error) {
+	rows, err := q.db.Query(ctx, listEntriesWithCounterpartyBefore, arg.AccountID, arg.Cursor, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListEntriesWithCounterpartyBeforeRow{}
+	for rows.Next() {
+		var i ListEntriesWithCounterpartyBeforeRow
+		if err := rows.Scan(
+			&i.Entry.ID,
+			&i.Entry.AccountID,
+			&i.Entry.Amount,
+			&i.Entry.CreatedAt,
+			&i.Entry.TransferID,
+			&i.CounterpartyAccountID,
+			&i.CounterpartyUsername,
+			&i.CounterpartyFullName,
+			&i.CounterpartyNickname,
+			&i.Currency,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchAccountEntries = `-- name: SearchAccountEntries :many
+SELECT
+    entries.id, entries.account_id, entries.amount, entries.created_at, entries.transfer_id,
+    transfers.id, transfers.from_account_id, transfers.to_account_id, transfers.amount, transfers.created_at, transfers.memo, transfers.external_reference,
+    counterparty.owner AS counterparty_username,
+    users.full_name AS counterparty_full_name,
+    counterparty.nickname AS counterparty_nickname,
+    COALESCE(beneficiaries.category, '') AS category,
+    accounts.currency,
+    CASE WHEN document.memo @@ search THEN ts_headline('simple', html_escape(transfers.memo), search, 'StartSel=<mark>, StopSel=</mark>') ELSE '' END::text AS memo_highlight,
+    CASE WHEN document.counterparty @@ search THEN ts_headline('simple', html_escape(users.full_name || ' (' || counterparty.owner || ')'), search, 'StartSel=<mark>, StopSel=</mark>, HighlightAll=true') ELSE '' END::text AS counterparty_highlight,
+    CASE WHEN document.category @@ search THEN ts_headline('simple', html_escape(beneficiaries.category), search, 'StartSel=<mark>, StopSel=</mark>, HighlightAll=true') ELSE '' END::text AS category_highlight
+FROM entries
+CROSS JOIN websearch_to_tsquery('simple', $1) AS search
+JOIN accounts ON accounts.id = entries.account_id
+JOIN transfers ON transfers.id = entries.transfer_id
+JOIN accounts AS counterparty ON counterparty.id = CASE
+    WHEN transfers.from_account_id = entries.account_id THEN transfers.to_account_id
+    ELSE transfers.from_account_id
+END
+JOIN users ON users.username = counterparty.owner
+LEFT JOIN beneficiaries ON beneficiaries.owner = accounts.owner
+    AND beneficiaries.account_id = counterparty.id
+    AND transfers.from_account_id = entries.account_id
+CROSS JOIN LATERAL (
+    SELECT
+        to_tsvector('simple', transfers.memo) AS memo,
+        to_tsvector('simple', users.full_name || ' ' || counterparty.owner) AS counterparty,
+        to_tsvector('simple', COALESCE(beneficiaries.category, '')) AS category
+) AS document
+WHERE entries.account_id = $2
+    AND (document.memo @@ search OR document.counterparty @@ search OR document.category @@ search)
+ORDER BY ts_rank(document.memo || document.counterparty || document.category, search) DESC, entries.id DESC
+LIMIT $3
+OFFSET $4
+`
+
+type SearchAccountEntriesParams struct {
+	Query     string `json:"query"`
+	AccountID int64  `json:"account_id"`
+	RowLimit  int32  `json:"row_limit"`
+	RowOffset int32  `json:"row_offset"`
+}
+
+type SearchAccountEntriesRow struct {
+	Entry                 Entry    `json:"entry"`
+	Transfer              Transfer `json:"transfer"`
+	CounterpartyUsername  string   `json:"counterparty_username"`
+	CounterpartyFullName  string   `json:"counterparty_full_name"`
+	CounterpartyNickname  string   `json:"counterparty_nickname"`
+	Category              string   `json:"category"`
+	Currency              string   `json:"currency"`
+	MemoHighlight         string   `json:"memo_highlight"`
+	CounterpartyHighlight string   `json:"counterparty_highlight"`
+	CategoryHighlight     string   `json:"category_highlight"`
+}
+
+// Searches the entries of an account made for transfers whose memo, counterparty or category match the
+// web search query, best matches first. The counterparty is the other account of the transfer, matched
+// by the name and username of its owner, and the category the one the owner of the account gave it as a
+// beneficiary, for the transfers sent to it. The highlights wrap the matched words of each field in
+// <mark> tags, the text of the field being HTML escaped, and are empty for the fields without a match.
+func (q *Queries) SearchAccountEntries(ctx context.Context, arg SearchAccountEntriesParams) ([]SearchAccountEntriesRow, error) {
+	rows, err := q.db.Query(ctx, searchAccountEntries,
+		arg.Query,
+		arg.AccountID,
+		arg.RowLimit,
+		arg.RowOffset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SearchAccountEntriesRow{}
+	for rows.Next() {
+		var i SearchAccountEntriesRow
+		if err := rows.Scan(
+			&i.Entry.ID,
+			&i.Entry.AccountID,
+			&i.Entry.Amount,
+			&i.Entry.CreatedAt,
+			&i.Entry.TransferID,
+			&i.Transfer.ID,
+			&i.Transfer.FromAccountID,
+			&i.Transfer.ToAccountID,
+			&i.Transfer.Amount,
+			&i.Transfer.CreatedAt,
+			&i.Transfer.Memo,
+			&i.Transfer.ExternalReference,
+			&i.CounterpartyUsername,
+			&i.CounterpartyFullName,
+			&i.CounterpartyNickname,
+			&i.Category,
+			&i.Currency,
+			&i.MemoHighlight,
+			&i.CounterpartyHighlight,
+			&i.CategoryHighlight,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const sumEntriesSince = `-- name: SumEntriesSince :one
+SELECT COALESCE(SUM(amount), 0)::bigint FROM entries
+WHERE account_id = $1 AND created_at >= $2
+`
+
+type SumEntriesSinceParams struct {
+	AccountID int64     `json:"account_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (q *Queries) SumEntriesSince(ctx context.Context, arg SumEntriesSinceParams) (int64, error) {
+	row := q.db.QueryRow(ctx, sumEntriesSince, arg.AccountID, arg.CreatedAt)
+	var column_1 int64
+	err := row.Scan(&column_1)
+	return column_1, err
+}
@@ -0,0 +1,102 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrImportNegativeBalance is returned when the imported entries would leave the account with a negative
+// balance.
+var ErrImportNegativeBalance = errors.New("the imported entries would make the balance negative")
+
+// The ImportedEntry type is an entry of the history of an account kept by another system.
+// @property {int64} Amount - negative when money left the account.
+// @property {time.Time} CreatedAt - when the entry was made in the other system.
+type ImportedEntry struct {
+	Amount    int64     `json:"amount"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// The ImportEntriesTxParams type contains the entries imported into an account.
+type ImportEntriesTxParams struct {
+	AccountID int64
+	Entries   []ImportedEntry
+}
+
+// The ImportEntriesTxResult type is the account once the entries were imported, along with the entries.
+type ImportEntriesTxResult struct {
+	Account Account `json:"account"`
+	Entries []Entry `json:"entries"`
+}
+
+// ImportEntriesTx records the entries with the time they were made at and adds their total to the
+// balance of the account, recording an account.updated event, so that the balance keeps matching the
+// entries. The opposite of the total is posted to the suspense account of its currency, the money
+// imported coming from outside of the ledger, so that the entries of the transaction sum to zero. Either
+// all the entries are imported or none is: the import fails with ErrImportNegativeBalance when the
+// balance would end up negative, and system accounts are left unchanged with ErrSystemAccount.
+func (store *SQLStore) ImportEntriesTx(ctx context.Context, arg ImportEntriesTxParams) (ImportEntriesTxResult, error) {
+	result := ImportEntriesTxResult{Entries: make([]Entry, 0, len(arg.Entries))}
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		account, err := q.GetAccount(ctx, arg.AccountID)
+		if err != nil {
+			return err
+		}
+		if IsSystemAccount(account) {
+			return ErrSystemAccount
+		}
+
+		suspense, err := q.GetSystemAccount(ctx, GetSystemAccountParams{
+			Purpose:  SystemAccountSuspense,
+			Currency: account.Currency,
+		})
+		if err != nil {
+			return err
+		}
+
+		var total int64
+		for _, imported := range arg.Entries {
+			entry, err := q.CreateHistoricalEntry(ctx, CreateHistoricalEntryParams{
+				AccountID: account.ID,
+				Amount:    imported.Amount,
+				CreatedAt: imported.CreatedAt,
+			})
+			if err != nil {
+				return err
+			}
+			result.Entries = append(result.Entries, entry)
+			total += imported.Amount
+		}
+
+		_, err = q.CreateEntry(ctx, CreateEntryParams{
+			AccountID: suspense.ID,
+			Amount:    -total,
+		})
+		if err != nil {
+			return err
+		}
+
+		// the accounts are updated in the order of their ids, like the transfers do, so as not to
+		// deadlock with them
+		if account.ID < suspense.ID {
+			result.Account, _, err = addMoney(ctx, q, account.ID, total, suspense.ID, -total)
+		} else {
+			_, result.Account, err = addMoney(ctx, q, suspense.ID, -total, account.ID, total)
+		}
+		if err != nil {
+			return err
+		}
+		if result.Account.ClosedAt.Valid {
+			return ErrAccountClosed
+		}
+		if result.Account.Balance < 0 {
+			return ErrImportNegativeBalance
+		}
+
+		return recordEvent(ctx, q, EventAccountUpdated, newAccountEvent(result.Account))
+	})
+
+	return result, err
+}
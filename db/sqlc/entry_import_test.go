@@ -0,0 +1,54 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportEntriesTx(t *testing.T) {
+	store := NewStore(testDB)
+	account := createRandomAccount(t)
+
+	createdAt := time.Date(2020, time.January, 2, 10, 0, 0, 0, time.UTC)
+	result, err := store.ImportEntriesTx(context.Background(), ImportEntriesTxParams{
+		AccountID: account.ID,
+		Entries: []ImportedEntry{
+			{Amount: 100, CreatedAt: createdAt},
+			{Amount: -40, CreatedAt: createdAt.AddDate(0, 0, 1)},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, account.Balance+60, result.Account.Balance)
+	require.Len(t, result.Entries, 2)
+	require.Equal(t, int64(100), result.Entries[0].Amount)
+	require.True(t, createdAt.Equal(result.Entries[0].CreatedAt))
+	require.False(t, result.Entries[0].TransferID.Valid)
+
+	// the balance keeps matching the entries
+	entries, err := testQueries.ListEntries(context.Background(), ListEntriesParams{
+		AccountID: account.ID,
+		Limit:     5,
+	})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	// nothing is imported when the balance would end up negative
+	_, err = store.ImportEntriesTx(context.Background(), ImportEntriesTxParams{
+		AccountID: account.ID,
+		Entries: []ImportedEntry{
+			{Amount: 10, CreatedAt: createdAt},
+			{Amount: -(result.Account.Balance + 11), CreatedAt: createdAt},
+		},
+	})
+	require.ErrorIs(t, err, ErrImportNegativeBalance)
+
+	got, err := testQueries.GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, result.Account.Balance, got.Balance)
+
+	_, err = store.ImportEntriesTx(context.Background(), ImportEntriesTxParams{AccountID: account.ID + 1000000})
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
@@ -6,16 +6,41 @@ import (
 	"testing"
 	"time"
 
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/stretchr/testify/require"
 )
 
+// createRandomEntry records an entry of a random amount on the account without changing its balance, the
+// opposite amount being recorded on the suspense account so that the entries of the transaction sum to
+// zero.
 func createRandomEntry(t *testing.T, account Account) Entry {
 	arg := CreateEntryParams{
 		AccountID: account.ID,
 		Amount:    util.RandomMoney(),
 	}
 
-	entry, err := testQueries.CreateEntry(context.Background(), arg)
+	var entry Entry
+	err := NewStore(testDB).(*SQLStore).execTx(context.Background(), func(q *Queries) error {
+		var err error
+		entry, err = q.CreateEntry(context.Background(), arg)
+		if err != nil {
+			return err
+		}
+
+		suspense, err := q.GetSystemAccount(context.Background(), GetSystemAccountParams{
+			Purpose:  SystemAccountSuspense,
+			Currency: account.Currency,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = q.CreateEntry(context.Background(), CreateEntryParams{
+			AccountID: suspense.ID,
+			Amount:    -arg.Amount,
+		})
+		return err
+	})
 	require.NoError(t, err)
 	require.NotEmpty(t, entry)
 
@@ -68,3 +93,190 @@ func TestListEntry(t *testing.T) {
 		require.NotEmpty(t, account)
 	}
 }
+
+func TestListEntriesWithCounterparty(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+	entry := createRandomEntry(t, account1)
+
+	result, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	})
+	require.NoError(t, err)
+	require.Equal(t, result.Transfer.ID, result.FromEntry.TransferID.Int64)
+	require.Equal(t, result.Transfer.ID, result.ToEntry.TransferID.Int64)
+
+	entries, err := testQueries.ListEntriesWithCounterparty(context.Background(), ListEntriesWithCounterpartyParams{
+		AccountID: account1.ID,
+		Limit:     5,
+	})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	// an entry made without a transfer has no counterparty
+	require.Equal(t, entry.ID, entries[0].Entry.ID)
+	require.False(t, entries[0].CounterpartyAccountID.Valid)
+	require.Equal(t, account1.Currency, entries[0].Currency)
+
+	require.Equal(t, result.FromEntry.ID, entries[1].Entry.ID)
+	require.Equal(t, account2.ID, entries[1].CounterpartyAccountID.Int64)
+	require.Equal(t, account2.Owner, entries[1].CounterpartyUsername.String)
+	require.True(t, entries[1].CounterpartyFullName.Valid)
+	require.True(t, entries[1].CounterpartyNickname.Valid)
+
+	// the recipient sees the sender as the counterparty
+	entries, err = testQueries.ListEntriesWithCounterparty(context.Background(), ListEntriesWithCounterpartyParams{
+		AccountID: account2.ID,
+		Limit:     5,
+	})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, account1.ID, entries[0].CounterpartyAccountID.Int64)
+}
+
+func TestListEntriesWithCounterpartyBefore(t *testing.T) {
+	account := createRandomAccount(t)
+	var entries []Entry
+	for i := 0; i < 3; i++ {
+		entries = append(entries, createRandomEntry(t, account))
+	}
+
+	// the first page starts with the newest entry
+	found, err := testQueries.ListEntriesWithCounterpartyBefore(context.Background(), ListEntriesWithCounterpartyBeforeParams{
+		AccountID: account.ID,
+		RowLimit:  2,
+	})
+	require.NoError(t, err)
+	require.Len(t, found, 2)
+	require.Equal(t, entries[2].ID, found[0].Entry.ID)
+	require.Equal(t, entries[1].ID, found[1].Entry.ID)
+	require.Equal(t, account.Currency, found[0].Currency)
+
+	// the next page holds the entries made before the last one of the page
+	found, err = testQueries.ListEntriesWithCounterpartyBefore(context.Background(), ListEntriesWithCounterpartyBeforeParams{
+		AccountID: account.ID,
+		Cursor:    pgtype.Int8{Int64: found[1].Entry.ID, Valid: true},
+		RowLimit:  2,
+	})
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	require.Equal(t, entries[0].ID, found[0].Entry.ID)
+}
+
+func TestListEntriesForExport(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+	entry := createRandomEntry(t, account1)
+
+	result, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID:     account1.ID,
+		ToAccountID:       account2.ID,
+		Amount:            10,
+		Memo:              "rent",
+		ExternalReference: "INV-42",
+	})
+	require.NoError(t, err)
+
+	arg := ListEntriesForExportParams{
+		AccountID: account1.ID,
+		FromTime:  entry.CreatedAt,
+		ToTime:    time.Now().Add(time.Minute),
+		RowLimit:  5,
+	}
+	entries, err := testQueries.ListEntriesForExport(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	// an entry made without a transfer has no transfer nor counterparty
+	require.Equal(t, entry.ID, entries[0].Entry.ID)
+	require.False(t, entries[0].CounterpartyAccountID.Valid)
+	require.False(t, entries[0].TransferMemo.Valid)
+
+	require.Equal(t, result.FromEntry.ID, entries[1].Entry.ID)
+	require.Equal(t, account2.ID, entries[1].CounterpartyAccountID.Int64)
+	require.Equal(t, "rent", entries[1].TransferMemo.String)
+	require.Equal(t, "INV-42", entries[1].TransferExternalReference.String)
+
+	// the period is exclusive of its end
+	arg.ToTime = entry.CreatedAt
+	entries, err = testQueries.ListEntriesForExport(context.Background(), arg)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
+
+func TestSearchAccountEntries(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+	createRandomEntry(t, account1)
+
+	_, err := testQueries.CreateBeneficiary(context.Background(), CreateBeneficiaryParams{
+		Owner:     account1.Owner,
+		AccountID: account2.ID,
+		Nickname:  util.RandomString(8),
+		Category:  "housing",
+	})
+	require.NoError(t, err)
+
+	result, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+		Memo:          "Rent for March",
+	})
+	require.NoError(t, err)
+
+	search := func(accountID int64, query string) []SearchAccountEntriesRow {
+		rows, err := testQueries.SearchAccountEntries(context.Background(), SearchAccountEntriesParams{
+			Query:     query,
+			AccountID: accountID,
+			RowLimit:  5,
+		})
+		require.NoError(t, err)
+		return rows
+	}
+
+	rows := search(account1.ID, "rent")
+	require.Len(t, rows, 1)
+	require.Equal(t, result.FromEntry.ID, rows[0].Entry.ID)
+	require.Equal(t, result.Transfer.ID, rows[0].Transfer.ID)
+	require.Equal(t, account2.Owner, rows[0].CounterpartyUsername)
+	require.Equal(t, "housing", rows[0].Category)
+	require.Equal(t, "<mark>Rent</mark> for March", rows[0].MemoHighlight)
+	require.Empty(t, rows[0].CategoryHighlight)
+
+	rows = search(account1.ID, "housing")
+	require.Len(t, rows, 1)
+	require.Equal(t, "<mark>housing</mark>", rows[0].CategoryHighlight)
+	require.Empty(t, rows[0].MemoHighlight)
+
+	rows = search(account1.ID, account2.Owner)
+	require.Len(t, rows, 1)
+	require.Contains(t, rows[0].CounterpartyHighlight, "<mark>")
+
+	require.Empty(t, search(account1.ID, "rent -march"))
+
+	// the category is the one the sender gave the recipient
+	rows = search(account2.ID, "rent")
+	require.Len(t, rows, 1)
+	require.Equal(t, result.ToEntry.ID, rows[0].Entry.ID)
+	require.Empty(t, rows[0].Category)
+	require.Empty(t, search(account2.ID, "housing"))
+
+	// the highlights are HTML, so the memo is escaped around the marks
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+		Memo:          "<img src=x onerror=alert(1)> deposit",
+	})
+	require.NoError(t, err)
+
+	rows = search(account1.ID, "deposit")
+	require.Len(t, rows, 1)
+	require.Equal(t, "&lt;img src=x onerror=alert(1)&gt; <mark>deposit</mark>", rows[0].MemoHighlight)
+}
@@ -0,0 +1,74 @@
+package db
+
+import (
+	"errors"
+	"go-backend/util"
+	"net"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Codes of the postgres errors handled by the application.
+const (
+	ForeignKeyViolation = "23503"
+	UniqueViolation     = "23505"
+)
+
+var (
+	// ErrRecordNotFound is returned by the queries fetching a single row when no row matches.
+	ErrRecordNotFound = util.ErrRecordNotFound
+	// ErrUniqueViolation matches the errors of writes conflicting with an existing row once translated.
+	ErrUniqueViolation = util.ErrUniqueViolation
+	// ErrForeignKeyViolation matches the errors of writes referencing a missing row, or deleting a
+	// referenced one, once translated.
+	ErrForeignKeyViolation = util.ErrForeignKeyViolation
+)
+
+// The `ErrorCode` function returns the postgres code of the error, or an empty string when the error
+// doesn't come from postgres, so that callers can compare it with `UniqueViolation` and the like
+// instead of depending on the driver.
+func ErrorCode(err error) string {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code
+	}
+	return ""
+}
+
+// The `TranslateError` function translates an error returned by the store so that it matches
+// `ErrUniqueViolation` or `ErrForeignKeyViolation` with `errors.Is`. The postgres error is kept in the
+// chain, and other errors are returned as they are.
+func TranslateError(err error) error {
+	switch ErrorCode(err) {
+	case UniqueViolation:
+		return &violationError{kind: ErrUniqueViolation, err: err}
+	case ForeignKeyViolation:
+		return &violationError{kind: ErrForeignKeyViolation, err: err}
+	}
+	return err
+}
+
+// The `Unavailable` function reports whether the error comes from a database that couldn't be reached
+// before anything was sent to it, e.g. while it is down, so that the write failing with it certainly
+// wasn't applied.
+func Unavailable(err error) bool {
+	var opErr *net.OpError
+	return pgconn.SafeToRetry(err) || (errors.As(err, &opErr) && opErr.Op == "dial")
+}
+
+type violationError struct {
+	kind error
+	err  error
+}
+
+func (e *violationError) Error() string {
+	return e.err.Error()
+}
+
+func (e *violationError) Unwrap() error {
+	return e.err
+}
+
+func (e *violationError) Is(target error) bool {
+	return target == e.kind
+}
@@ -0,0 +1,384 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Domain events appended to the events table by the store, in the same transaction as the change they
+// describe, so that projections never miss or see uncommitted changes.
+const (
+	EventUserCreated             = "user.created"
+	EventAccountCreated          = "account.created"
+	EventAccountUpdated          = "account.updated"
+	EventAccountDeleted          = "account.deleted"
+	EventTransferCompleted       = "transfer.completed"
+	EventTransferSent            = "transfer.sent"
+	EventTransferReceived        = "transfer.received"
+	EventPaymentRequestCreated   = "payment_request.created"
+	EventQueuedTransferProcessed = "queued_transfer.processed"
+	EventSessionNewDevice        = "session.new_device"
+	EventUserLocked              = "user.locked"
+	EventExternalTransferUpdated = "external_transfer.updated"
+)
+
+type UserCreatedEvent struct {
+	Username string `json:"username"`
+	FullName string `json:"full_name"`
+	Email    string `json:"email"`
+}
+
+// The NewDeviceEvent type describes a session opened from a user agent none of the earlier sessions of
+// the user was opened from, for the session.new_device event.
+type NewDeviceEvent struct {
+	SessionID uuid.UUID `json:"session_id"`
+	Username  string    `json:"username"`
+	UserAgent string    `json:"user_agent"`
+	ClientIP  string    `json:"client_ip"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type AccountEvent struct {
+	AccountID int64  `json:"account_id"`
+	Owner     string `json:"owner"`
+	Currency  string `json:"currency"`
+	Balance   int64  `json:"balance"`
+}
+
+// The TransferCompletedEvent type carries the balances of both accounts after the transfer so that
+// projections can apply it without reading the ledger.
+type TransferCompletedEvent struct {
+	TransferID         int64     `json:"transfer_id"`
+	FromAccountID      int64     `json:"from_account_id"`
+	ToAccountID        int64     `json:"to_account_id"`
+	Amount             int64     `json:"amount"`
+	FromAccountBalance int64     `json:"from_account_balance"`
+	ToAccountBalance   int64     `json:"to_account_balance"`
+	CreatedAt          time.Time `json:"created_at"`
+}
+
+// The TransferPartyEvent type describes a transfer from the point of view of one of its accounts, for the
+// transfer.sent event of the sender and the transfer.received event of the recipient. The amount is
+// negative when sent and positive when received, and the counterparty is the other account.
+type TransferPartyEvent struct {
+	TransferID            int64     `json:"transfer_id"`
+	AccountID             int64     `json:"account_id"`
+	Owner                 string    `json:"owner"`
+	Currency              string    `json:"currency"`
+	Amount                int64     `json:"amount"`
+	Balance               int64     `json:"balance"`
+	CounterpartyAccountID int64     `json:"counterparty_account_id"`
+	CounterpartyOwner     string    `json:"counterparty_owner"`
+	Memo                  string    `json:"memo"`
+	ExternalReference     string    `json:"external_reference"`
+	CreatedAt             time.Time `json:"created_at"`
+}
+
+func newTransferPartyEvent(transfer Transfer, account Account, amount int64, counterparty Account) TransferPartyEvent {
+	return TransferPartyEvent{
+		TransferID:            transfer.ID,
+		AccountID:             account.ID,
+		Owner:                 account.Owner,
+		Currency:              account.Currency,
+		Amount:                amount,
+		Balance:               account.Balance,
+		CounterpartyAccountID: counterparty.ID,
+		CounterpartyOwner:     counterparty.Owner,
+		Memo:                  transfer.Memo,
+		ExternalReference:     transfer.ExternalReference,
+		CreatedAt:             transfer.CreatedAt,
+	}
+}
+
+func recordEvent(ctx context.Context, q *Queries, eventType string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	_, err = q.CreateEvent(ctx, CreateEventParams{
+		Type:    eventType,
+		Payload: data,
+	})
+	return err
+}
+
+// CreateUser creates the user and records a user.created event.
+func (store *SQLStore) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
+	var user User
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		user, err = q.CreateUser(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		return recordEvent(ctx, q, EventUserCreated, UserCreatedEvent{
+			Username: user.Username,
+			FullName: user.FullName,
+			Email:    user.Email,
+		})
+	})
+
+	return user, err
+}
+
+// CreateUserWithRoleTx creates the user with the role, e.g. the admins created from the command line,
+// and records a user.created event.
+func (store *SQLStore) CreateUserWithRoleTx(ctx context.Context, arg CreateUserParams, role string) (User, error) {
+	var user User
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		_, err := q.CreateUser(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		user, err = q.UpdateUserRole(ctx, UpdateUserRoleParams{
+			Username: arg.Username,
+			Role:     role,
+		})
+		if err != nil {
+			return err
+		}
+
+		return recordEvent(ctx, q, EventUserCreated, UserCreatedEvent{
+			Username: user.Username,
+			FullName: user.FullName,
+			Email:    user.Email,
+		})
+	})
+
+	return user, err
+}
+
+// CreateSession creates the session and records a session.new_device event when none of the earlier
+// sessions of the user was opened from its user agent. The first session of a user isn't from a new
+// device.
+func (store *SQLStore) CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error) {
+	var session Session
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		count, err := q.CountUserSessions(ctx, CountUserSessionsParams{
+			UserAgent: arg.UserAgent,
+			Username:  arg.Username,
+		})
+		if err != nil {
+			return err
+		}
+
+		session, err = q.CreateSession(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		if count.Sessions == 0 || count.DeviceSessions > 0 {
+			return nil
+		}
+
+		return recordEvent(ctx, q, EventSessionNewDevice, NewDeviceEvent{
+			SessionID: session.ID,
+			Username:  session.Username,
+			UserAgent: session.UserAgent,
+			ClientIP:  session.ClientIp,
+			CreatedAt: session.CreatedAt,
+		})
+	})
+
+	return session, err
+}
+
+// CreateAccount creates the account, opening its history, and records an account.created event.
+func (store *SQLStore) CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error) {
+	var account Account
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		account, err = q.CreateAccount(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		err = recordAccountVersion(ctx, q, account)
+		if err != nil {
+			return err
+		}
+
+		return recordEvent(ctx, q, EventAccountCreated, newAccountEvent(account))
+	})
+
+	return account, err
+}
+
+// ErrAccountVersionMismatch is returned when changing an account that was updated since the version the
+// caller read, e.g. a transfer to or from an account whose currency changed since it was checked.
+var ErrAccountVersionMismatch = errors.New("account was updated since the expected version")
+
+// ErrAccountClosed is returned when moving money to or from a closed account, which is checked once the
+// row of the account is locked so that an account closed meanwhile fails the transaction.
+var ErrAccountClosed = errors.New("account is closed")
+
+// The AdjustAccountBalanceTxParams type contains the parameters to adjust the balance of an account.
+// @property {int64} ID - the account whose balance is adjusted.
+// @property {int64} Amount - the amount credited to the account, debited from it when negative.
+// @property {int64} Version - the version the account must still be at, 0 to adjust the balance
+// whatever its version.
+// @property {string} Memo - why the balance is adjusted.
+type AdjustAccountBalanceTxParams struct {
+	ID      int64  `json:"id"`
+	Amount  int64  `json:"amount"`
+	Version int64  `json:"version"`
+	Memo    string `json:"memo"`
+}
+
+// AdjustAccountBalanceTx corrects the balance of the account with a transfer from the suspense account of
+// its currency, to it when the amount is negative, so that the money adjusted is booked against the bank
+// rather than created or destroyed. System accounts are left unchanged with ErrSystemAccount, and
+// accounts no longer at the expected version with ErrAccountVersionMismatch, the version being checked
+// once the transfer holds the lock of the account.
+func (store *SQLStore) AdjustAccountBalanceTx(ctx context.Context, arg AdjustAccountBalanceTxParams) (TransferTxResult, error) {
+	var result TransferTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		account, err := q.GetAccount(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+		if IsSystemAccount(account) {
+			return ErrSystemAccount
+		}
+
+		suspense, err := q.GetSystemAccount(ctx, GetSystemAccountParams{
+			Purpose:  SystemAccountSuspense,
+			Currency: account.Currency,
+		})
+		if err != nil {
+			return err
+		}
+
+		params := TransferTxParams{
+			FromAccountID: suspense.ID,
+			ToAccountID:   account.ID,
+			Amount:        arg.Amount,
+			Memo:          arg.Memo,
+		}
+		if arg.Amount < 0 {
+			params.FromAccountID, params.ToAccountID, params.Amount = account.ID, suspense.ID, -arg.Amount
+		}
+
+		result, err = transfer(ctx, q, params, nil)
+		if err != nil {
+			return err
+		}
+
+		// the transfer was the only update of the account since the version read
+		adjusted := result.ToAccount
+		if arg.Amount < 0 {
+			adjusted = result.FromAccount
+		}
+		if arg.Version != 0 && adjusted.Version != arg.Version+1 {
+			return ErrAccountVersionMismatch
+		}
+		return recordEvent(ctx, q, EventAccountUpdated, newAccountEvent(adjusted))
+	})
+
+	return result, err
+}
+
+// DeleteAccount deletes the account, closing its history, and records an account.deleted event. System
+// accounts are kept with ErrSystemAccount.
+func (store *SQLStore) DeleteAccount(ctx context.Context, id int64) error {
+	return store.execTx(ctx, func(q *Queries) error {
+		account, err := q.GetAccountForUpdate(ctx, id)
+		if err != nil && !errors.Is(err, ErrRecordNotFound) {
+			return err
+		}
+		if IsSystemAccount(account) {
+			return ErrSystemAccount
+		}
+
+		err = q.DeleteAccount(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		// the history outlives the account
+		err = q.CloseAccountVersion(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		return recordEvent(ctx, q, EventAccountDeleted, AccountEvent{AccountID: id})
+	})
+}
+
+func newAccountEvent(account Account) AccountEvent {
+	return AccountEvent{
+		AccountID: account.ID,
+		Owner:     account.Owner,
+		Currency:  account.Currency,
+		Balance:   account.Balance,
+	}
+}
+
+// EventSettleDelay is how old an event must be before it is projected, for the events committed after
+// it with smaller ids to be visible by then.
+const EventSettleDelay = 2 * time.Second
+
+// The ProjectEventsTxParams type contains the parameters to apply the next batch of events to a
+// projection.
+// @property {string} Projection - the name of the projection, under which its checkpoint is stored.
+// @property {int32} Limit - the maximum number of events applied in the batch.
+// @property {time.Time} SettledBefore - only the events recorded before it are applied, usually
+// `EventSettleDelay` ago.
+// @property Apply - the function applying an event to the read tables, within the transaction.
+type ProjectEventsTxParams struct {
+	Projection    string
+	Limit         int32
+	SettledBefore time.Time
+	Apply         func(ctx context.Context, q *Queries, event Event) error
+}
+
+// ProjectEventsTx applies the events recorded since the checkpoint of the projection and moves the
+// checkpoint forward, all in one transaction: a crashed batch is simply applied again. The checkpoint
+// row stays locked until the end of the transaction so that concurrent workers don't apply the same
+// events twice. It returns the number of events applied.
+func (store *SQLStore) ProjectEventsTx(ctx context.Context, arg ProjectEventsTxParams) (int, error) {
+	var applied int
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		lastEventID, err := q.LockProjectionCheckpoint(ctx, arg.Projection)
+		if err != nil {
+			return err
+		}
+
+		events, err := q.ListEventsAfter(ctx, ListEventsAfterParams{
+			ID:            lastEventID,
+			SettledBefore: arg.SettledBefore,
+			RowLimit:      arg.Limit,
+		})
+		if err != nil || len(events) == 0 {
+			return err
+		}
+
+		for _, event := range events {
+			err = arg.Apply(ctx, q, event)
+			if err != nil {
+				return err
+			}
+		}
+
+		applied = len(events)
+		return q.UpdateProjectionCheckpoint(ctx, UpdateProjectionCheckpointParams{
+			Name:        arg.Projection,
+			LastEventID: events[len(events)-1].ID,
+		})
+	})
+
+	return applied, err
+}
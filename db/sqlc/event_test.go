@@ -0,0 +1,156 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"go-backend/util"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectEventsTx(t *testing.T) {
+	store := NewStore(testDB)
+	user := createRandomUser(t)
+
+	account, err := store.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:    user.Username,
+		Balance:  util.RandomMoney(),
+		Currency: util.RandomCurrency(),
+	})
+	require.NoError(t, err)
+
+	projection := "test_" + util.RandomString(8)
+	var events []Event
+	apply := func(ctx context.Context, q *Queries, event Event) error {
+		events = append(events, event)
+		return nil
+	}
+
+	projectTestEvents(t, store, projection, apply)
+
+	var found int
+	for _, event := range events {
+		if event.Type != EventAccountCreated {
+			continue
+		}
+
+		var payload AccountEvent
+		err := json.Unmarshal(event.Payload, &payload)
+		require.NoError(t, err)
+
+		if payload.AccountID == account.ID {
+			found++
+			require.Equal(t, account.Owner, payload.Owner)
+			require.Equal(t, account.Balance, payload.Balance)
+		}
+	}
+	require.Equal(t, 1, found)
+
+	// the checkpoint moved past the event, so it is not applied again
+	seen := len(events)
+	projectTestEvents(t, store, projection, apply)
+	for _, event := range events[seen:] {
+		require.Greater(t, event.ID, events[seen-1].ID)
+	}
+}
+
+func TestProjectEventsTxSettling(t *testing.T) {
+	store := NewStore(testDB)
+	user := createRandomUser(t)
+
+	account, err := store.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:    user.Username,
+		Balance:  util.RandomMoney(),
+		Currency: util.RandomCurrency(),
+	})
+	require.NoError(t, err)
+
+	projection := "test_" + util.RandomString(8)
+	var found int
+	apply := func(ctx context.Context, q *Queries, event Event) error {
+		var payload AccountEvent
+		if event.Type == EventAccountCreated && json.Unmarshal(event.Payload, &payload) == nil && payload.AccountID == account.ID {
+			found++
+		}
+		return nil
+	}
+
+	// the events recorded since the settling time are left for the next batches
+	_, err = store.ProjectEventsTx(context.Background(), ProjectEventsTxParams{
+		Projection:    projection,
+		Limit:         1000,
+		SettledBefore: time.Now().Add(-time.Hour),
+		Apply:         apply,
+	})
+	require.NoError(t, err)
+	require.Zero(t, found)
+
+	projectTestEvents(t, store, projection, apply)
+	require.Equal(t, 1, found)
+}
+
+func TestCreateSessionNewDevice(t *testing.T) {
+	store := NewStore(testDB)
+	user := createRandomUser(t)
+
+	createSession := func(userAgent string) Session {
+		session, err := store.CreateSession(context.Background(), CreateSessionParams{
+			ID:           uuid.New(),
+			Username:     user.Username,
+			RefreshToken: util.RandomString(32),
+			UserAgent:    userAgent,
+			ClientIp:     "127.0.0.1",
+			ExpiresAt:    time.Now().Add(time.Hour),
+		})
+		require.NoError(t, err)
+		return session
+	}
+
+	// the first session and another one from the same device aren't from a new device
+	createSession("laptop")
+	createSession("laptop")
+	phone := createSession("phone")
+
+	var newDevices []NewDeviceEvent
+	apply := func(ctx context.Context, q *Queries, event Event) error {
+		if event.Type != EventSessionNewDevice {
+			return nil
+		}
+
+		var payload NewDeviceEvent
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return err
+		}
+		if payload.Username == user.Username {
+			newDevices = append(newDevices, payload)
+		}
+		return nil
+	}
+
+	projection := "test_" + util.RandomString(8)
+	projectTestEvents(t, store, projection, apply)
+
+	require.Len(t, newDevices, 1)
+	require.Equal(t, phone.ID, newDevices[0].SessionID)
+	require.Equal(t, "phone", newDevices[0].UserAgent)
+}
+
+// The `projectTestEvents` function applies every event to the projection. The events of the test are
+// committed already, so they are applied without waiting for them to settle.
+func projectTestEvents(t *testing.T, store Store, projection string, apply func(ctx context.Context, q *Queries, event Event) error) {
+	for {
+		applied, err := store.ProjectEventsTx(context.Background(), ProjectEventsTxParams{
+			Projection:    projection,
+			Limit:         1000,
+			SettledBefore: time.Now().Add(time.Minute),
+			Apply:         apply,
+		})
+		require.NoError(t, err)
+		if applied < 1000 {
+			return
+		}
+	}
+}
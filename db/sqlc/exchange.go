@@ -0,0 +1,210 @@
+package db
+
+import (
+	"context"
+	"sort"
+)
+
+// The ExchangeTxParams type is money moved between two accounts of different currencies.
+// @property {int64} Amount - the amount taken from the from account, in its currency.
+// @property {int64} ConvertedAmount - the amount given to the to account, in its currency.
+type ExchangeTxParams struct {
+	FromAccountID   int64  `json:"from_account_id"`
+	FromCurrency    string `json:"from_currency"`
+	ToAccountID     int64  `json:"to_account_id"`
+	ToCurrency      string `json:"to_currency"`
+	Amount          int64  `json:"amount"`
+	ConvertedAmount int64  `json:"converted_amount"`
+	Memo            string `json:"memo"`
+}
+
+// The ExchangeTxResult type is the outcome of an exchange, made of two transfers through the fx_spread
+// system accounts of the currencies.
+// @property {TransferTxResult} Sold - the transfer of the amount from the from account to the fx_spread
+// account of its currency.
+// @property {TransferTxResult} Bought - the transfer of the converted amount from the fx_spread account
+// of the other currency to the to account.
+type ExchangeTxResult struct {
+	Sold   TransferTxResult `json:"sold"`
+	Bought TransferTxResult `json:"bought"`
+}
+
+// ExchangeTx moves money between accounts of different currencies in one transaction. The money goes
+// through the fx_spread system accounts, which hold the position of the bank in each currency, so that
+// the entries of every transfer net to zero in a single currency.
+func (store *SQLStore) ExchangeTx(ctx context.Context, arg ExchangeTxParams) (ExchangeTxResult, error) {
+	var result ExchangeTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		soldTo, err := q.GetSystemAccount(ctx, GetSystemAccountParams{
+			Purpose:  SystemAccountFXSpread,
+			Currency: arg.FromCurrency,
+		})
+		if err != nil {
+			return err
+		}
+
+		boughtFrom, err := q.GetSystemAccount(ctx, GetSystemAccountParams{
+			Purpose:  SystemAccountFXSpread,
+			Currency: arg.ToCurrency,
+		})
+		if err != nil {
+			return err
+		}
+
+		// the four accounts are locked in the order of their ids, so that exchanges in opposite
+		// directions don't deadlock on the fx_spread accounts
+		ids := []int64{arg.FromAccountID, arg.ToAccountID, soldTo.ID, boughtFrom.ID}
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+		for _, id := range ids {
+			_, err = q.GetAccountForUpdate(ctx, id)
+			if err != nil {
+				return err
+			}
+		}
+
+		result.Sold, err = transfer(ctx, q, TransferTxParams{
+			FromAccountID: arg.FromAccountID,
+			ToAccountID:   soldTo.ID,
+			Amount:        arg.Amount,
+			Memo:          arg.Memo,
+		}, nil)
+		if err != nil {
+			return err
+		}
+
+		result.Bought, err = transfer(ctx, q, TransferTxParams{
+			FromAccountID: boughtFrom.ID,
+			ToAccountID:   arg.ToAccountID,
+			Amount:        arg.ConvertedAmount,
+			Memo:          arg.Memo,
+		}, nil)
+		return err
+	})
+
+	return result, err
+}
+
+// The ConvertAccountTxParams type is the change of the currency of an account, its balance being
+// converted to the new currency.
+// @property {string} FromCurrency - the currency the account must still hold, the change failing with
+// ErrAccountVersionMismatch once it holds another.
+// @property {func} Convert - returns the balance, in the currency of the account, converted to the new
+// currency. It is called under the lock of the account, with its positive balance.
+type ConvertAccountTxParams struct {
+	AccountID    int64                              `json:"account_id"`
+	FromCurrency string                             `json:"from_currency"`
+	ToCurrency   string                             `json:"to_currency"`
+	Convert      func(balance int64) (int64, error) `json:"-"`
+	Memo         string                             `json:"memo"`
+}
+
+// The ConvertAccountTxResult type is the outcome of the change of the currency of an account.
+// @property {Account} Account - the account holding the new currency and the converted balance.
+// @property {int64} Amount - the balance converted, in the previous currency.
+// @property {int64} ConvertedAmount - the balance after the conversion, in the new currency.
+// @property {[]Transfer} Transfers - the transfer of the balance to the fx_spread account of the previous
+// currency and the one of the converted balance from the fx_spread account of the new currency, none when
+// the account was empty.
+type ConvertAccountTxResult struct {
+	Account         Account    `json:"account"`
+	Amount          int64      `json:"amount"`
+	ConvertedAmount int64      `json:"converted_amount"`
+	Transfers       []Transfer `json:"transfers"`
+}
+
+// ConvertAccountTx changes the currency of an account in one transaction, selling its balance to the
+// fx_spread account of its currency before the change and buying the converted balance from the one of
+// the new currency after it, so that the entries of every transfer net to zero in a single currency.
+// The change is recorded in the history of the account. System accounts are left unchanged with
+// ErrSystemAccount.
+func (store *SQLStore) ConvertAccountTx(ctx context.Context, arg ConvertAccountTxParams) (ConvertAccountTxResult, error) {
+	result := ConvertAccountTxResult{Transfers: []Transfer{}}
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		soldTo, err := q.GetSystemAccount(ctx, GetSystemAccountParams{
+			Purpose:  SystemAccountFXSpread,
+			Currency: arg.FromCurrency,
+		})
+		if err != nil {
+			return err
+		}
+
+		boughtFrom, err := q.GetSystemAccount(ctx, GetSystemAccountParams{
+			Purpose:  SystemAccountFXSpread,
+			Currency: arg.ToCurrency,
+		})
+		if err != nil {
+			return err
+		}
+
+		// the accounts are locked in the order of their ids, like the exchanges
+		ids := []int64{arg.AccountID, soldTo.ID, boughtFrom.ID}
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+		for _, id := range ids {
+			account, err := q.GetAccountForUpdate(ctx, id)
+			if err != nil {
+				return err
+			}
+			if id == arg.AccountID {
+				result.Account = account
+			}
+		}
+		if IsSystemAccount(result.Account) {
+			return ErrSystemAccount
+		}
+		if result.Account.Currency != arg.FromCurrency {
+			return ErrAccountVersionMismatch
+		}
+
+		result.Amount = result.Account.Balance
+		if result.Amount > 0 {
+			result.ConvertedAmount, err = arg.Convert(result.Amount)
+			if err != nil {
+				return err
+			}
+
+			sold, err := transfer(ctx, q, TransferTxParams{
+				FromAccountID: arg.AccountID,
+				ToAccountID:   soldTo.ID,
+				Amount:        result.Amount,
+				Memo:          arg.Memo,
+			}, nil)
+			if err != nil {
+				return err
+			}
+			result.Transfers = append(result.Transfers, sold.Transfer)
+		}
+
+		result.Account, err = q.UpdateAccountCurrency(ctx, UpdateAccountCurrencyParams{
+			ID:       arg.AccountID,
+			Currency: arg.ToCurrency,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = recordAccountVersion(ctx, q, result.Account)
+		if err != nil {
+			return err
+		}
+
+		if result.ConvertedAmount > 0 {
+			bought, err := transfer(ctx, q, TransferTxParams{
+				FromAccountID: boughtFrom.ID,
+				ToAccountID:   arg.AccountID,
+				Amount:        result.ConvertedAmount,
+				Memo:          arg.Memo,
+			}, nil)
+			if err != nil {
+				return err
+			}
+			result.Account = bought.ToAccount
+			result.Transfers = append(result.Transfers, bought.Transfer)
+		}
+
+		return recordEvent(ctx, q, EventAccountUpdated, newAccountEvent(result.Account))
+	})
+
+	return result, err
+}
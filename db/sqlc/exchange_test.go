@@ -0,0 +1,124 @@
+package db
+
+import (
+	"context"
+	"go-backend/util"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createAccountInCurrency(t *testing.T, owner string, currency string) Account {
+	account, err := testQueries.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:    owner,
+		Balance:  1000,
+		Currency: currency,
+	})
+	require.NoError(t, err)
+	return account
+}
+
+func TestExchangeTx(t *testing.T) {
+	store := NewStore(testDB)
+	user := createRandomUser(t)
+	from := createAccountInCurrency(t, user.Username, util.USD)
+	to := createAccountInCurrency(t, user.Username, util.CAD)
+
+	fxUSD, err := store.GetSystemAccount(context.Background(), GetSystemAccountParams{Purpose: SystemAccountFXSpread, Currency: util.USD})
+	require.NoError(t, err)
+	fxCAD, err := store.GetSystemAccount(context.Background(), GetSystemAccountParams{Purpose: SystemAccountFXSpread, Currency: util.CAD})
+	require.NoError(t, err)
+
+	result, err := store.ExchangeTx(context.Background(), ExchangeTxParams{
+		FromAccountID:   from.ID,
+		FromCurrency:    from.Currency,
+		ToAccountID:     to.ID,
+		ToCurrency:      to.Currency,
+		Amount:          100,
+		ConvertedAmount: 135,
+		Memo:            "savings",
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, from.Balance-100, result.Sold.FromAccount.Balance)
+	require.Equal(t, fxUSD.ID, result.Sold.ToAccount.ID)
+	require.Equal(t, int64(100), result.Sold.Transfer.Amount)
+	require.Equal(t, "savings", result.Sold.Transfer.Memo)
+
+	require.Equal(t, fxCAD.ID, result.Bought.FromAccount.ID)
+	require.Equal(t, to.Balance+135, result.Bought.ToAccount.Balance)
+	require.Equal(t, int64(135), result.Bought.Transfer.Amount)
+
+	// each transfer nets to zero in its currency
+	imbalances, err := store.ListTransferImbalances(context.Background())
+	require.NoError(t, err)
+	for _, imbalance := range imbalances {
+		require.NotEqual(t, result.Sold.Transfer.ID, imbalance.TransferID)
+		require.NotEqual(t, result.Bought.Transfer.ID, imbalance.TransferID)
+	}
+}
+
+func TestConvertAccountTx(t *testing.T) {
+	store := NewStore(testDB)
+	user := createRandomUser(t)
+
+	// the account is created by the store, opening its history
+	account, err := store.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:    user.Username,
+		Balance:  0,
+		Currency: util.USD,
+	})
+	require.NoError(t, err)
+	funding, err := store.AdjustAccountBalanceTx(context.Background(), AdjustAccountBalanceTxParams{ID: account.ID, Amount: 1000, Memo: "funding"})
+	require.NoError(t, err)
+	account = funding.ToAccount
+
+	result, err := store.ConvertAccountTx(context.Background(), ConvertAccountTxParams{
+		AccountID:    account.ID,
+		FromCurrency: util.USD,
+		ToCurrency:   util.CAD,
+		Convert: func(balance int64) (int64, error) {
+			require.Equal(t, account.Balance, balance)
+			return 1350, nil
+		},
+		Memo: "moving",
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, util.CAD, result.Account.Currency)
+	require.Equal(t, int64(1350), result.Account.Balance)
+	require.Greater(t, result.Account.Version, account.Version)
+	require.Equal(t, int64(1000), result.Amount)
+	require.Equal(t, int64(1350), result.ConvertedAmount)
+	require.Len(t, result.Transfers, 2)
+	require.Equal(t, account.ID, result.Transfers[0].FromAccountID)
+	require.Equal(t, account.ID, result.Transfers[1].ToAccountID)
+
+	// the previous currency is closed in the history of the account
+	versions, err := testQueries.ListAccountHistory(context.Background(), ListAccountHistoryParams{AccountID: account.ID, Limit: 5})
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+	require.Equal(t, util.USD, versions[0].Currency)
+	require.True(t, versions[0].ValidTo.Valid)
+	require.Equal(t, util.CAD, versions[1].Currency)
+	require.False(t, versions[1].ValidTo.Valid)
+
+	// each transfer nets to zero in its currency
+	imbalances, err := store.ListTransferImbalances(context.Background())
+	require.NoError(t, err)
+	for _, imbalance := range imbalances {
+		require.NotEqual(t, result.Transfers[0].ID, imbalance.TransferID)
+		require.NotEqual(t, result.Transfers[1].ID, imbalance.TransferID)
+	}
+
+	// the account doesn't hold the currency anymore
+	_, err = store.ConvertAccountTx(context.Background(), ConvertAccountTxParams{
+		AccountID:    account.ID,
+		FromCurrency: util.USD,
+		ToCurrency:   util.EUR,
+		Convert: func(balance int64) (int64, error) {
+			return balance, nil
+		},
+	})
+	require.ErrorIs(t, err, ErrAccountVersionMismatch)
+}
@@ -0,0 +1,211 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Rails an external transfer is sent through. A wire settles within the day, an ACH transfer takes
+// longer.
+const (
+	ExternalTransferACH  = "ach"
+	ExternalTransferWire = "wire"
+)
+
+// Statuses of an external transfer. An initiated transfer is submitted to its rail, processing until the
+// receiving bank settles it or returns the money, which are final.
+const (
+	ExternalTransferInitiated  = "initiated"
+	ExternalTransferProcessing = "processing"
+	ExternalTransferSettled    = "settled"
+	ExternalTransferFailed     = "failed"
+)
+
+// ErrExternalTransferStatus is returned when moving an external transfer to a status it can't reach
+// from its current one, e.g. when it was already settled.
+var ErrExternalTransferStatus = errors.New("external transfer can't move to this status")
+
+// The ExternalTransferEvent type describes an external transfer for the external_transfer.updated event,
+// recorded when it is initiated and on every change of its status, which notifies its owner.
+type ExternalTransferEvent struct {
+	ExternalTransferID int64     `json:"external_transfer_id"`
+	Owner              string    `json:"owner"`
+	AccountID          int64     `json:"account_id"`
+	Rail               string    `json:"rail"`
+	Amount             int64     `json:"amount"`
+	Currency           string    `json:"currency"`
+	BeneficiaryName    string    `json:"beneficiary_name"`
+	Status             string    `json:"status"`
+	FailureReason      string    `json:"failure_reason"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+func recordExternalTransferEvent(ctx context.Context, q *Queries, transfer ExternalTransfer) error {
+	return recordEvent(ctx, q, EventExternalTransferUpdated, ExternalTransferEvent{
+		ExternalTransferID: transfer.ID,
+		Owner:              transfer.Owner,
+		AccountID:          transfer.AccountID,
+		Rail:               transfer.Rail,
+		Amount:             transfer.Amount,
+		Currency:           transfer.Currency,
+		BeneficiaryName:    transfer.BeneficiaryName,
+		Status:             transfer.Status,
+		FailureReason:      transfer.FailureReason,
+		UpdatedAt:          transfer.UpdatedAt,
+	})
+}
+
+// The InitiateExternalTransferTxParams type contains money sent out of the bank.
+// @property {string} Rail - ach or wire.
+// @property {int64} Amount - the positive amount sent, in the currency of the account.
+// @property {string} RoutingNumber - the routing number of the receiving bank.
+// @property {string} AccountNumber - the account at the receiving bank.
+// @property {string} Memo - optional free text kept on the transfer to the clearing account.
+type InitiateExternalTransferTxParams struct {
+	Owner           string
+	AccountID       int64
+	Rail            string
+	Amount          int64
+	Currency        string
+	RoutingNumber   string
+	AccountNumber   string
+	BeneficiaryName string
+	Memo            string
+}
+
+// The InitiateExternalTransferTxResult type is the initiated external transfer, along with the transfer
+// of its amount to the clearing account.
+type InitiateExternalTransferTxResult struct {
+	ExternalTransfer ExternalTransfer `json:"external_transfer"`
+	Transfer         TransferTxResult `json:"transfer"`
+}
+
+// InitiateExternalTransferTx moves the amount from the account to the external_clearing account of its
+// currency, which holds it until the transfer settles, and records the initiated external transfer along
+// with an external_transfer.updated event.
+func (store *SQLStore) InitiateExternalTransferTx(ctx context.Context, arg InitiateExternalTransferTxParams) (InitiateExternalTransferTxResult, error) {
+	var result InitiateExternalTransferTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		clearing, err := q.GetSystemAccount(ctx, GetSystemAccountParams{
+			Purpose:  SystemAccountClearing,
+			Currency: arg.Currency,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Transfer, err = transfer(ctx, q, TransferTxParams{
+			FromAccountID: arg.AccountID,
+			ToAccountID:   clearing.ID,
+			Amount:        arg.Amount,
+			Memo:          arg.Memo,
+		}, nil)
+		if err != nil {
+			return err
+		}
+
+		result.ExternalTransfer, err = q.CreateExternalTransfer(ctx, CreateExternalTransferParams{
+			Owner:           arg.Owner,
+			AccountID:       arg.AccountID,
+			Rail:            arg.Rail,
+			Amount:          arg.Amount,
+			Currency:        arg.Currency,
+			RoutingNumber:   arg.RoutingNumber,
+			AccountNumber:   arg.AccountNumber,
+			BeneficiaryName: arg.BeneficiaryName,
+			TransferID:      result.Transfer.Transfer.ID,
+		})
+		if err != nil {
+			return err
+		}
+
+		return recordExternalTransferEvent(ctx, q, result.ExternalTransfer)
+	})
+
+	return result, err
+}
+
+// The AdvanceExternalTransferTxParams type contains the next status of an external transfer.
+// @property {string} Status - processing for an initiated transfer, settled or failed for a processing one.
+// @property {string} FailureReason - why the receiving bank returned the money, when failed.
+type AdvanceExternalTransferTxParams struct {
+	ID            int64
+	Status        string
+	FailureReason string
+}
+
+// The AdvanceExternalTransferTxResult type is the external transfer in its new status.
+// @property {TransferTxResult} Return - the transfer of the amount back to the account, only made when
+// the transfer failed.
+type AdvanceExternalTransferTxResult struct {
+	ExternalTransfer ExternalTransfer `json:"external_transfer"`
+	Return           TransferTxResult `json:"return"`
+}
+
+// AdvanceExternalTransferTx moves an external transfer to its next status and records an
+// external_transfer.updated event. A failed transfer returns the amount from the external_clearing
+// account to the account it was sent from. The transfer is locked so that it advances once however
+// many times the worker runs, ErrExternalTransferStatus being returned when it can't reach the status.
+func (store *SQLStore) AdvanceExternalTransferTx(ctx context.Context, arg AdvanceExternalTransferTxParams) (AdvanceExternalTransferTxResult, error) {
+	var result AdvanceExternalTransferTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		external, err := q.GetExternalTransferForUpdate(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+		if !canAdvanceExternalTransfer(external.Status, arg.Status) {
+			return ErrExternalTransferStatus
+		}
+
+		update := UpdateExternalTransferStatusParams{
+			ID:     external.ID,
+			Status: arg.Status,
+		}
+		if arg.Status == ExternalTransferFailed {
+			original, err := q.GetTransfer(ctx, external.TransferID)
+			if err != nil {
+				return err
+			}
+
+			result.Return, err = transfer(ctx, q, TransferTxParams{
+				FromAccountID: original.ToAccountID,
+				ToAccountID:   external.AccountID,
+				Amount:        external.Amount,
+				Memo:          arg.FailureReason,
+				Refund:        true,
+			}, nil)
+			if err != nil {
+				return err
+			}
+
+			update.FailureReason = arg.FailureReason
+			update.ReturnTransferID = pgtype.Int8{Int64: result.Return.Transfer.ID, Valid: true}
+		}
+
+		result.ExternalTransfer, err = q.UpdateExternalTransferStatus(ctx, update)
+		if err != nil {
+			return err
+		}
+
+		return recordExternalTransferEvent(ctx, q, result.ExternalTransfer)
+	})
+
+	return result, err
+}
+
+// canAdvanceExternalTransfer reports whether an external transfer moves from one status to the other. An
+// initiated transfer may be rejected before it is processed.
+func canAdvanceExternalTransfer(from string, to string) bool {
+	switch from {
+	case ExternalTransferInitiated:
+		return to == ExternalTransferProcessing || to == ExternalTransferFailed
+	case ExternalTransferProcessing:
+		return to == ExternalTransferSettled || to == ExternalTransferFailed
+	}
+	return false
+}
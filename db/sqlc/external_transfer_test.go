@@ -0,0 +1,78 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func initiateExternalTransfer(t *testing.T, store Store, account Account, amount int64) InitiateExternalTransferTxResult {
+	result, err := store.InitiateExternalTransferTx(context.Background(), InitiateExternalTransferTxParams{
+		Owner:           account.Owner,
+		AccountID:       account.ID,
+		Rail:            ExternalTransferACH,
+		Amount:          amount,
+		Currency:        account.Currency,
+		RoutingNumber:   "021000021",
+		AccountNumber:   "123456789",
+		BeneficiaryName: "Bob",
+	})
+	require.NoError(t, err)
+	require.Equal(t, ExternalTransferInitiated, result.ExternalTransfer.Status)
+	require.Equal(t, result.Transfer.Transfer.ID, result.ExternalTransfer.TransferID)
+	require.True(t, IsSystemAccount(result.Transfer.ToAccount))
+	return result
+}
+
+func TestExternalTransferSettled(t *testing.T) {
+	store := NewStore(testDB)
+
+	account := createRandomAccount(t)
+	initiated := initiateExternalTransfer(t, store, account, 10)
+	require.Equal(t, account.Balance-10, initiated.Transfer.FromAccount.Balance)
+
+	// a transfer is processed before it settles
+	_, err := store.AdvanceExternalTransferTx(context.Background(), AdvanceExternalTransferTxParams{ID: initiated.ExternalTransfer.ID, Status: ExternalTransferSettled})
+	require.ErrorIs(t, err, ErrExternalTransferStatus)
+
+	for _, status := range []string{ExternalTransferProcessing, ExternalTransferSettled} {
+		result, err := store.AdvanceExternalTransferTx(context.Background(), AdvanceExternalTransferTxParams{ID: initiated.ExternalTransfer.ID, Status: status})
+		require.NoError(t, err)
+		require.Equal(t, status, result.ExternalTransfer.Status)
+		require.False(t, result.ExternalTransfer.ReturnTransferID.Valid)
+	}
+
+	// a settled transfer is final
+	_, err = store.AdvanceExternalTransferTx(context.Background(), AdvanceExternalTransferTxParams{ID: initiated.ExternalTransfer.ID, Status: ExternalTransferFailed})
+	require.ErrorIs(t, err, ErrExternalTransferStatus)
+
+	got, err := testQueries.GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, account.Balance-10, got.Balance)
+}
+
+func TestExternalTransferFailed(t *testing.T) {
+	store := NewStore(testDB)
+
+	account := createRandomAccount(t)
+	initiated := initiateExternalTransfer(t, store, account, 10)
+
+	_, err := store.AdvanceExternalTransferTx(context.Background(), AdvanceExternalTransferTxParams{ID: initiated.ExternalTransfer.ID, Status: ExternalTransferProcessing})
+	require.NoError(t, err)
+
+	result, err := store.AdvanceExternalTransferTx(context.Background(), AdvanceExternalTransferTxParams{
+		ID:            initiated.ExternalTransfer.ID,
+		Status:        ExternalTransferFailed,
+		FailureReason: "account closed",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "account closed", result.ExternalTransfer.FailureReason)
+	require.Equal(t, result.Return.Transfer.ID, result.ExternalTransfer.ReturnTransferID.Int64)
+	require.Equal(t, account.Balance, result.Return.ToAccount.Balance)
+
+	transfers, err := testQueries.ListExternalTransfers(context.Background(), ListExternalTransfersParams{Owner: account.Owner, Limit: 5})
+	require.NoError(t, err)
+	require.Len(t, transfers, 1)
+	require.Equal(t, ExternalTransferFailed, transfers[0].Status)
+}
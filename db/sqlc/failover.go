@@ -0,0 +1,281 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	// failoverThreshold is the number of consecutive queries that must fail to reach the database before
+	// the pool fails over to the next source.
+	failoverThreshold = 3
+	// minFailoverBackoff is the least time between two failovers, doubled after each failover until
+	// maxFailoverBackoff, and reset once the database is reached again.
+	minFailoverBackoff = time.Second
+	maxFailoverBackoff = time.Minute
+	// failoverPingTimeout is how long a source is given to answer the ping checking it before it is
+	// connected to.
+	failoverPingTimeout = 5 * time.Second
+)
+
+// The FailoverPool type is a connection pool to the primary database that fails over to the next of its
+// sources once several consecutive queries failed to reach the current one, e.g. when its node went
+// down. The sources are tried in order, wrapping around to the first one, with a growing backoff between
+// failovers so that the pool doesn't spin while every source is down. A query that failed is not retried
+// on the next source, since a write may have been applied before the connection was lost.
+type FailoverPool struct {
+	sources []string
+	connect func(ctx context.Context, source string) (*pgxpool.Pool, error)
+
+	mutex        sync.Mutex
+	current      int
+	pool         *pgxpool.Pool
+	failures     int
+	backoff      time.Duration
+	nextFailover time.Time
+}
+
+// The function creates a pool connected to the first of the `sources` that `connect` opens a pool to and
+// that answers a ping. When none answers, e.g. as the database is still starting, it connects to the
+// first source a pool was opened to, failing over once the queries fail. The sources are connection
+// strings, which are never logged as they hold credentials.
+func NewFailoverPool(ctx context.Context, sources []string, connect func(ctx context.Context, source string) (*pgxpool.Pool, error)) (*FailoverPool, error) {
+	if len(sources) == 0 {
+		return nil, errors.New("no database source")
+	}
+
+	failoverPool := &FailoverPool{
+		sources: sources,
+		connect: connect,
+		backoff: minFailoverBackoff,
+	}
+
+	var fallback *pgxpool.Pool
+	var fallbackIndex int
+	var err error
+	for i := range sources {
+		var pool *pgxpool.Pool
+		pool, err = failoverPool.open(ctx, i)
+		switch {
+		case err == nil:
+			if fallback != nil {
+				fallback.Close()
+			}
+			failoverPool.use(i, pool)
+			return failoverPool, nil
+		case pool != nil && fallback == nil:
+			fallback, fallbackIndex = pool, i
+		case pool != nil:
+			pool.Close()
+		}
+	}
+	if fallback == nil {
+		return nil, err
+	}
+
+	log.Printf("no db source answers, starting with source %d", fallbackIndex)
+	failoverPool.use(fallbackIndex, fallback)
+	return failoverPool, nil
+}
+
+// use makes the pool of the source with the given index the current one.
+func (pool *FailoverPool) use(index int, connPool *pgxpool.Pool) {
+	pool.current = index
+	pool.pool = connPool
+	DBActiveSource.Set(float64(index))
+}
+
+// open opens a pool to the source with the given index and pings it, since the pools connect lazily:
+// a pool is opened even to a source that is down. The pool is returned with the error of the ping, and
+// none is returned when it can't be opened.
+func (pool *FailoverPool) open(ctx context.Context, index int) (*pgxpool.Pool, error) {
+	connPool, err := pool.connect(ctx, pool.sources[index])
+	if err != nil {
+		log.Printf("cannot connect to db source %d: %v", index, err)
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, failoverPingTimeout)
+	defer cancel()
+
+	return connPool, connPool.Ping(ctx)
+}
+
+func (pool *FailoverPool) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	current := pool.currentPool()
+	tag, err := current.Exec(ctx, sql, args...)
+	pool.report(ctx, current, err)
+	return tag, err
+}
+
+func (pool *FailoverPool) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	current := pool.currentPool()
+	rows, err := current.Query(ctx, sql, args...)
+	pool.report(ctx, current, err)
+	return rows, err
+}
+
+func (pool *FailoverPool) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	current := pool.currentPool()
+	return &reportedRow{
+		Row:     current.QueryRow(ctx, sql, args...),
+		ctx:     ctx,
+		current: current,
+		pool:    pool,
+	}
+}
+
+func (pool *FailoverPool) Begin(ctx context.Context) (pgx.Tx, error) {
+	return pool.BeginTx(ctx, pgx.TxOptions{})
+}
+
+func (pool *FailoverPool) BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error) {
+	current := pool.currentPool()
+	tx, err := current.BeginTx(ctx, txOptions)
+	pool.report(ctx, current, err)
+	return tx, err
+}
+
+// The `Ping` function checks that the current source can be reached.
+func (pool *FailoverPool) Ping(ctx context.Context) error {
+	current := pool.currentPool()
+	err := current.Ping(ctx)
+	pool.report(ctx, current, err)
+	return err
+}
+
+// The `Run` function pings the current source every `interval` until the context is cancelled, so that
+// the pool fails over even while no query is made.
+func (pool *FailoverPool) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := pool.Ping(ctx)
+			if err != nil && ctx.Err() == nil {
+				log.Printf("db health check failed: %v", err)
+			}
+		}
+	}
+}
+
+// The `Close` function closes the pool to the current source.
+func (pool *FailoverPool) Close() {
+	pool.currentPool().Close()
+}
+
+// The `Stat` function returns the statistics of the pool to the current source.
+func (pool *FailoverPool) Stat() *pgxpool.Stat {
+	return pool.currentPool().Stat()
+}
+
+func (pool *FailoverPool) currentPool() *pgxpool.Pool {
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+
+	return pool.pool
+}
+
+// report counts the queries of the current source that failed to reach it, and fails over once there
+// are enough in a row. The errors of the queries themselves say nothing about the health of the source,
+// nor do the failures of a previous source reported after the failover.
+func (pool *FailoverPool) report(ctx context.Context, current *pgxpool.Pool, err error) {
+	failed := err != nil && unreachable(ctx, err)
+	if err != nil && !failed {
+		return
+	}
+
+	pool.mutex.Lock()
+	if current != pool.pool {
+		pool.mutex.Unlock()
+		return
+	}
+	if !failed {
+		pool.failures = 0
+		pool.backoff = minFailoverBackoff
+		pool.mutex.Unlock()
+		return
+	}
+
+	pool.failures++
+	if pool.failures < failoverThreshold || len(pool.sources) < 2 || time.Now().Before(pool.nextFailover) {
+		pool.mutex.Unlock()
+		return
+	}
+
+	// the backoff keeps the other queries failing meanwhile from failing over too
+	from, failures := pool.current, pool.failures
+	pool.failures = 0
+	pool.nextFailover = time.Now().Add(pool.backoff)
+	pool.backoff *= 2
+	if pool.backoff > maxFailoverBackoff {
+		pool.backoff = maxFailoverBackoff
+	}
+	pool.mutex.Unlock()
+
+	pool.failover(from, current, failures)
+}
+
+// failover switches from the failed pool of the source with index `from` to the next source that a pool
+// can be opened to and that answers a ping, staying on the current one when none does. The sources are
+// opened and pinged without the mutex, so that the queries meanwhile aren't held up by the network, and
+// the new pool is closed if the current one was replaced in the meantime. The pool of the previous source
+// is closed once its queries in flight are done.
+func (pool *FailoverPool) failover(from int, failed *pgxpool.Pool, failures int) {
+	for i := 1; i < len(pool.sources); i++ {
+		next := (from + i) % len(pool.sources)
+		connPool, err := pool.open(context.Background(), next)
+		if err != nil {
+			if connPool != nil {
+				log.Printf("db source %d doesn't answer: %v", next, err)
+				connPool.Close()
+			}
+			continue
+		}
+
+		pool.mutex.Lock()
+		if pool.pool != failed {
+			pool.mutex.Unlock()
+			connPool.Close()
+			return
+		}
+		pool.use(next, connPool)
+		pool.mutex.Unlock()
+		go failed.Close()
+
+		DBFailovers.Inc()
+		log.Printf("db failed over from source %d to source %d after %d failed queries", from, next, failures)
+		return
+	}
+}
+
+// The reportedRow type is a row whose error is reported to the pool, as it is only known once the row is
+// scanned.
+type reportedRow struct {
+	pgx.Row
+	ctx     context.Context
+	current *pgxpool.Pool
+	pool    *FailoverPool
+}
+
+func (row *reportedRow) Scan(dest ...interface{}) error {
+	err := row.Row.Scan(dest...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// the source was reached
+		row.pool.report(row.ctx, row.current, nil)
+		return err
+	}
+	row.pool.report(row.ctx, row.current, err)
+	return err
+}
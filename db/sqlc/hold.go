@@ -0,0 +1,244 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Statuses of a hold. An authorized hold reserves its amount on the account, counted in its held
+// balance, until it is captured, which settles it, released, or expires. The other statuses are final.
+const (
+	HoldAuthorized = "authorized"
+	HoldCaptured   = "captured"
+	HoldReleased   = "released"
+	HoldExpired    = "expired"
+)
+
+var (
+	// ErrInsufficientAvailableBalance is returned when placing a hold or debiting more than the available
+	// balance of an account, its ledger balance less its held balance.
+	ErrInsufficientAvailableBalance = errors.New("available balance is insufficient")
+	// ErrHoldClosed is returned when capturing or releasing a hold that is no longer authorized.
+	ErrHoldClosed = errors.New("hold is no longer authorized")
+	// ErrHoldExpired is returned when capturing a hold past its expiry.
+	ErrHoldExpired = errors.New("hold has expired")
+	// ErrCaptureExceedsHold is returned when capturing more than the amount of a hold.
+	ErrCaptureExceedsHold = errors.New("captured amount exceeds the hold")
+	// ErrCurrencyMismatch is returned when capturing a hold from or to an account that doesn't hold its
+	// currency, e.g. once the account was converted to another.
+	ErrCurrencyMismatch = errors.New("account doesn't hold the currency of the hold")
+)
+
+// AvailableBalance returns what the account can spend: its ledger balance less the amounts its
+// authorized holds reserve.
+func AvailableBalance(account Account) int64 {
+	return account.Balance - account.HeldBalance
+}
+
+// The placeHoldParams type contains a hold to place on an account.
+// @property {int64} Amount - the positive amount reserved, at most the available balance of the account.
+// @property {string} Memo - what the amount is reserved for.
+// @property {time.Time} ExpiresAt - the hold is released past this time unless captured.
+type placeHoldParams struct {
+	Amount    int64
+	Memo      string
+	ExpiresAt time.Time
+}
+
+// The placeHoldResult type is the hold placed, along with the account whose held balance it was added
+// to.
+type placeHoldResult struct {
+	Hold    Hold
+	Account Account
+}
+
+// placeHold places a hold reserving the amount on the account, which adds it to the held balance of the
+// account and leaves its ledger balance unchanged until the hold is captured. The account must be locked
+// by the transaction, the transaction of AuthorizeCardTx or DepositChequeTx, so that the holds placed at
+// the same time can't reserve more than its available balance, ErrInsufficientAvailableBalance being
+// returned for a declined amount. The card is the one that was authorized if any.
+func placeHold(ctx context.Context, q *Queries, account Account, cardID pgtype.Int8, arg placeHoldParams) (placeHoldResult, error) {
+	var result placeHoldResult
+
+	if account.ClosedAt.Valid {
+		return result, ErrAccountClosed
+	}
+	if AvailableBalance(account) < arg.Amount {
+		return result, ErrInsufficientAvailableBalance
+	}
+
+	var err error
+	result.Hold, err = q.CreateHold(ctx, CreateHoldParams{
+		CardID:    cardID,
+		AccountID: account.ID,
+		Amount:    arg.Amount,
+		Currency:  account.Currency,
+		Memo:      arg.Memo,
+		ExpiresAt: arg.ExpiresAt,
+	})
+	if err != nil {
+		return result, err
+	}
+
+	result.Account, err = q.AddAccountHeldBalance(ctx, AddAccountHeldBalanceParams{
+		ID:     account.ID,
+		Amount: arg.Amount,
+	})
+	return result, err
+}
+
+// The settleHoldParams type contains the capture of a hold.
+// @property {int64} Amount - the positive amount settled, at most the amount of the hold. The rest of the
+// hold is released.
+// @property {int64} ToAccountID - the account the captured amount is moved to.
+type settleHoldParams struct {
+	ID          int64
+	Amount      int64
+	ToAccountID int64
+}
+
+// The settleHoldResult type is the captured hold, along with the transfer settling it.
+type settleHoldResult struct {
+	Hold     Hold
+	Transfer TransferTxResult
+}
+
+// settleHold captures a hold in the transaction of CaptureCardHoldTx or BounceChequeDepositTx, releasing
+// its whole amount from the held balance of its account and moving the captured amount out of the account
+// in the same transaction, so that the amount reserved is never spent twice. The hold is locked so that it
+// is captured once, ErrHoldClosed being returned when it is no longer authorized, ErrHoldExpired once it
+// expired and ErrCurrencyMismatch when either account no longer holds its currency.
+func settleHold(ctx context.Context, q *Queries, arg settleHoldParams) (settleHoldResult, error) {
+	var result settleHoldResult
+
+	hold, err := q.GetHoldForUpdate(ctx, arg.ID)
+	if err != nil {
+		return result, err
+	}
+	if hold.Status != HoldAuthorized {
+		return result, ErrHoldClosed
+	}
+	if !hold.ExpiresAt.After(time.Now()) {
+		return result, ErrHoldExpired
+	}
+	if arg.Amount > hold.Amount {
+		return result, ErrCaptureExceedsHold
+	}
+
+	// the accounts are locked in order of id like the transfers lock them, before the held balance is
+	// released, so as not to deadlock with a transfer between them the other way
+	_, err = q.LockAccounts(ctx, []int64{hold.AccountID, arg.ToAccountID})
+	if err != nil {
+		return result, err
+	}
+
+	// the amount is no longer reserved once it is spent by the transfer
+	_, err = q.AddAccountHeldBalance(ctx, AddAccountHeldBalanceParams{
+		ID:     hold.AccountID,
+		Amount: -hold.Amount,
+	})
+	if err != nil {
+		return result, err
+	}
+
+	result.Transfer, err = transfer(ctx, q, TransferTxParams{
+		FromAccountID: hold.AccountID,
+		ToAccountID:   arg.ToAccountID,
+		Amount:        arg.Amount,
+		Memo:          hold.Memo,
+	}, nil)
+	if err != nil {
+		return result, err
+	}
+	// the money is moved without conversion, which the accounts locked by the transfer can't need
+	if result.Transfer.FromAccount.Currency != hold.Currency || result.Transfer.ToAccount.Currency != hold.Currency {
+		return result, ErrCurrencyMismatch
+	}
+
+	result.Hold, err = q.CaptureHold(ctx, CaptureHoldParams{
+		ID:             hold.ID,
+		CapturedAmount: arg.Amount,
+		TransferID:     pgtype.Int8{Int64: result.Transfer.Transfer.ID, Valid: true},
+	})
+	return result, err
+}
+
+// ReleaseHoldTx releases an authorized hold, removing its amount from the held balance of its account.
+// The hold is locked so that it isn't captured at the same time, ErrHoldClosed being returned when it is
+// no longer authorized.
+func (store *SQLStore) ReleaseHoldTx(ctx context.Context, id int64) (Hold, error) {
+	var hold Hold
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		hold, err = freeHold(ctx, q, id)
+		return err
+	})
+
+	return hold, err
+}
+
+// freeHold releases the hold in the transaction of ReleaseHoldTx or of the transactions settling what it
+// was placed for.
+func freeHold(ctx context.Context, q *Queries, id int64) (Hold, error) {
+	hold, err := q.GetHoldForUpdate(ctx, id)
+	if err != nil {
+		return hold, err
+	}
+	if hold.Status != HoldAuthorized {
+		return hold, ErrHoldClosed
+	}
+
+	hold, err = q.ReleaseHold(ctx, id)
+	if err != nil {
+		return hold, err
+	}
+
+	_, err = q.AddAccountHeldBalance(ctx, AddAccountHeldBalanceParams{
+		ID:     hold.AccountID,
+		Amount: -hold.Amount,
+	})
+	return hold, err
+}
+
+// ExpireHoldsTx expires the authorized holds past `expiresAt`, removing their amounts from the held
+// balance of their accounts, and returns them. The accounts are updated in the order of their ids like
+// the transfers do, so as not to deadlock with them.
+func (store *SQLStore) ExpireHoldsTx(ctx context.Context, expiresAt time.Time) ([]Hold, error) {
+	var holds []Hold
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		holds, err = q.ExpireHolds(ctx, expiresAt)
+		if err != nil {
+			return err
+		}
+
+		released := map[int64]int64{}
+		for _, hold := range holds {
+			released[hold.AccountID] += hold.Amount
+		}
+		accountIDs := make([]int64, 0, len(released))
+		for accountID := range released {
+			accountIDs = append(accountIDs, accountID)
+		}
+		sort.Slice(accountIDs, func(i, j int) bool { return accountIDs[i] < accountIDs[j] })
+
+		for _, accountID := range accountIDs {
+			_, err = q.AddAccountHeldBalance(ctx, AddAccountHeldBalanceParams{
+				ID:     accountID,
+				Amount: -released[accountID],
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	return holds, err
+}
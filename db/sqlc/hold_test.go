@@ -0,0 +1,220 @@
+package db
+
+import (
+	"context"
+	"go-backend/util"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+// placeTestHoldTx places a hold on the account in a transaction of the store, which locks the account like
+// AuthorizeCardTx and DepositChequeTx do.
+func placeTestHoldTx(store Store, account Account, amount int64) (placeHoldResult, error) {
+	var result placeHoldResult
+
+	err := store.(*SQLStore).execTx(context.Background(), func(q *Queries) error {
+		locked, err := q.GetAccountForUpdate(context.Background(), account.ID)
+		if err != nil {
+			return err
+		}
+
+		result, err = placeHold(context.Background(), q, locked, pgtype.Int8{}, placeHoldParams{
+			Amount:    amount,
+			Memo:      "cheque",
+			ExpiresAt: time.Now().Add(time.Hour),
+		})
+		return err
+	})
+
+	return result, err
+}
+
+func placeTestHold(t *testing.T, store Store, account Account, amount int64) placeHoldResult {
+	result, err := placeTestHoldTx(store, account, amount)
+	require.NoError(t, err)
+	return result
+}
+
+// settleTestHold captures a hold in a transaction of the store, like CaptureCardHoldTx does.
+func settleTestHold(store Store, arg settleHoldParams) (settleHoldResult, error) {
+	var result settleHoldResult
+
+	err := store.(*SQLStore).execTx(context.Background(), func(q *Queries) error {
+		var err error
+		result, err = settleHold(context.Background(), q, arg)
+		return err
+	})
+
+	return result, err
+}
+
+func TestPlaceHold(t *testing.T) {
+	store := NewStore(testDB)
+
+	account := createRandomAccount(t)
+	result := placeTestHold(t, store, account, 100)
+	require.Equal(t, HoldAuthorized, result.Hold.Status)
+	require.False(t, result.Hold.CardID.Valid)
+	require.Equal(t, account.Balance, result.Account.Balance)
+	require.Equal(t, int64(100), result.Account.HeldBalance)
+	require.Equal(t, account.Balance-100, AvailableBalance(result.Account))
+
+	// a hold can't reserve more than the available balance
+	_, err := placeTestHoldTx(store, account, account.Balance-99)
+	require.ErrorIs(t, err, ErrInsufficientAvailableBalance)
+}
+
+func TestTransferTxAvailableBalance(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+	placeTestHold(t, store, account1, account1.Balance-10)
+
+	// the held amount can't be transferred
+	_, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        11,
+	})
+	require.ErrorIs(t, err, ErrInsufficientAvailableBalance)
+
+	got, err := testQueries.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance, got.Balance)
+
+	result, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	})
+	require.NoError(t, err)
+	require.Zero(t, AvailableBalance(result.FromAccount))
+}
+
+func TestSettleHold(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccount(t)
+	account2 := createAccountInCurrency(t, createRandomUser(t).Username, account1.Currency)
+	placed := placeTestHold(t, store, account1, account1.Balance)
+
+	// the held amount is spent by the capture only
+	result, err := settleTestHold(store, settleHoldParams{
+		ID:          placed.Hold.ID,
+		Amount:      account1.Balance,
+		ToAccountID: account2.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, HoldCaptured, result.Hold.Status)
+	require.Equal(t, result.Transfer.Transfer.ID, result.Hold.TransferID.Int64)
+	require.Zero(t, result.Transfer.FromAccount.Balance)
+	require.Zero(t, result.Transfer.FromAccount.HeldBalance)
+	require.Equal(t, account2.Balance+account1.Balance, result.Transfer.ToAccount.Balance)
+
+	_, err = settleTestHold(store, settleHoldParams{
+		ID:          placed.Hold.ID,
+		Amount:      1,
+		ToAccountID: account2.ID,
+	})
+	require.ErrorIs(t, err, ErrHoldClosed)
+}
+
+func TestSettleHoldCurrencyMismatch(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccount(t)
+	placed := placeTestHold(t, store, account1, 10)
+	account2 := createAccountInCurrency(t, createRandomUser(t).Username, util.EUR)
+	if account1.Currency == util.EUR {
+		account2 = createAccountInCurrency(t, createRandomUser(t).Username, util.USD)
+	}
+
+	// the money isn't moved between currencies without conversion
+	_, err := settleTestHold(store, settleHoldParams{
+		ID:          placed.Hold.ID,
+		Amount:      10,
+		ToAccountID: account2.ID,
+	})
+	require.ErrorIs(t, err, ErrCurrencyMismatch)
+
+	got, err := testQueries.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance, got.Balance)
+	require.Equal(t, int64(10), got.HeldBalance)
+}
+
+func TestSettleHoldDeadlock(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccount(t)
+	account2 := createAccountInCurrency(t, createRandomUser(t).Username, account1.Currency)
+
+	n := 10
+	amount := int64(10)
+	holds := make([]Hold, n/2)
+	for i := range holds {
+		holds[i] = placeTestHold(t, store, account1, amount).Hold
+	}
+
+	errs := make(chan error)
+
+	// half capture the holds of the first account, the other half move money the opposite direction
+	for i := 0; i < n; i++ {
+		i := i
+		go func() {
+			var err error
+			if i%2 == 0 {
+				_, err = settleTestHold(store, settleHoldParams{
+					ID:          holds[i/2].ID,
+					Amount:      amount,
+					ToAccountID: account2.ID,
+				})
+			} else {
+				_, err = store.TransferTx(context.Background(), TransferTxParams{
+					FromAccountID: account2.ID,
+					ToAccountID:   account1.ID,
+					Amount:        amount,
+				})
+			}
+
+			errs <- err
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		err := <-errs
+		require.NoError(t, err)
+	}
+
+	updatedAccount1, err := testQueries.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance, updatedAccount1.Balance)
+	require.Zero(t, updatedAccount1.HeldBalance)
+
+	updatedAccount2, err := testQueries.GetAccount(context.Background(), account2.ID)
+	require.NoError(t, err)
+	require.Equal(t, account2.Balance, updatedAccount2.Balance)
+}
+
+func TestReleaseHoldTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	account := createRandomAccount(t)
+	placed := placeTestHold(t, store, account, 10)
+
+	hold, err := store.ReleaseHoldTx(context.Background(), placed.Hold.ID)
+	require.NoError(t, err)
+	require.Equal(t, HoldReleased, hold.Status)
+
+	got, err := testQueries.GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Zero(t, got.HeldBalance)
+
+	// a hold is released once
+	_, err = store.ReleaseHoldTx(context.Background(), placed.Hold.ID)
+	require.ErrorIs(t, err, ErrHoldClosed)
+}
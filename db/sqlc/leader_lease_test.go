@@ -0,0 +1,85 @@
+package db
+
+import (
+	"context"
+	"go-backend/util"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcquireLeaderLease(t *testing.T) {
+	name := util.RandomString(12)
+	leader := util.RandomOwner()
+	standby := util.RandomOwner()
+
+	lease, err := testQueries.AcquireLeaderLease(context.Background(), AcquireLeaderLeaseParams{
+		Name:       name,
+		Holder:     leader,
+		Address:    "https://leader.example.com",
+		DurationMs: time.Minute.Milliseconds(),
+	})
+	require.NoError(t, err)
+	require.Equal(t, leader, lease.Holder)
+	require.WithinDuration(t, time.Now().Add(time.Minute), lease.ExpiresAt, 5*time.Second)
+
+	// the lease can't be taken while it is held
+	_, err = testQueries.AcquireLeaderLease(context.Background(), AcquireLeaderLeaseParams{
+		Name:       name,
+		Holder:     standby,
+		Address:    "https://standby.example.com",
+		DurationMs: time.Minute.Milliseconds(),
+	})
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	// the holder renews it
+	renewed, err := testQueries.AcquireLeaderLease(context.Background(), AcquireLeaderLeaseParams{
+		Name:       name,
+		Holder:     leader,
+		Address:    "https://leader.example.com",
+		DurationMs: time.Minute.Milliseconds(),
+	})
+	require.NoError(t, err)
+	require.Equal(t, lease.AcquiredAt, renewed.AcquiredAt)
+	require.False(t, renewed.ExpiresAt.Before(lease.ExpiresAt))
+
+	// the standby takes it over once released
+	err = testQueries.ReleaseLeaderLease(context.Background(), ReleaseLeaderLeaseParams{Name: name, Holder: leader})
+	require.NoError(t, err)
+
+	taken, err := testQueries.AcquireLeaderLease(context.Background(), AcquireLeaderLeaseParams{
+		Name:       name,
+		Holder:     standby,
+		Address:    "https://standby.example.com",
+		DurationMs: time.Minute.Milliseconds(),
+	})
+	require.NoError(t, err)
+	require.Equal(t, standby, taken.Holder)
+
+	got, err := testQueries.GetLeaderLease(context.Background(), name)
+	require.NoError(t, err)
+	require.Equal(t, "https://standby.example.com", got.Address)
+}
+
+func TestAcquireExpiredLeaderLease(t *testing.T) {
+	name := util.RandomString(12)
+
+	_, err := testQueries.AcquireLeaderLease(context.Background(), AcquireLeaderLeaseParams{
+		Name:       name,
+		Holder:     util.RandomOwner(),
+		Address:    "https://leader.example.com",
+		DurationMs: -time.Second.Milliseconds(),
+	})
+	require.NoError(t, err)
+
+	standby := util.RandomOwner()
+	lease, err := testQueries.AcquireLeaderLease(context.Background(), AcquireLeaderLeaseParams{
+		Name:       name,
+		Holder:     standby,
+		Address:    "https://standby.example.com",
+		DurationMs: time.Minute.Milliseconds(),
+	})
+	require.NoError(t, err)
+	require.Equal(t, standby, lease.Holder)
+}
@@ -0,0 +1,72 @@
+package db
+
+import "context"
+
+// Kinds of the anomalies found by the reconciliation of the ledger.
+const (
+	// AnomalyAccountBalance is an account whose balance differs from the sum of its entries.
+	AnomalyAccountBalance = "account_balance"
+	// AnomalyTransferImbalance is a transfer whose entries don't net to zero.
+	AnomalyTransferImbalance = "transfer_imbalance"
+)
+
+// The ReconcileLedgerTxResult type is the outcome of a reconciliation of the ledger.
+// @property {[]LedgerAnomaly} Anomalies - the anomalies found, recorded in the ledger_anomalies table.
+// @property {int64} Resolved - the number of anomalies recorded before that weren't found again.
+type ReconcileLedgerTxResult struct {
+	Anomalies []LedgerAnomaly `json:"anomalies"`
+	Resolved  int64           `json:"resolved"`
+}
+
+// ReconcileLedgerTx checks that the balance of every account equals the sum of its entries and that the
+// entries of every transfer net to zero, and records the discrepancies found as anomalies. The anomalies
+// recorded by a previous reconciliation that aren't found anymore, e.g. once the ledger was corrected,
+// are resolved. Transfers made before the entries recorded their transfer can't be checked.
+func (store *SQLStore) ReconcileLedgerTx(ctx context.Context) (ReconcileLedgerTxResult, error) {
+	var result ReconcileLedgerTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		result = ReconcileLedgerTxResult{Anomalies: []LedgerAnomaly{}}
+
+		accounts, err := q.ListAccountBalanceDiscrepancies(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, account := range accounts {
+			anomaly, err := q.UpsertLedgerAnomaly(ctx, UpsertLedgerAnomalyParams{
+				Kind:      AnomalyAccountBalance,
+				SubjectID: account.AccountID,
+				Expected:  account.EntriesTotal,
+				Actual:    account.Balance,
+			})
+			if err != nil {
+				return err
+			}
+			result.Anomalies = append(result.Anomalies, anomaly)
+		}
+
+		transfers, err := q.ListTransferImbalances(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, transfer := range transfers {
+			anomaly, err := q.UpsertLedgerAnomaly(ctx, UpsertLedgerAnomalyParams{
+				Kind:      AnomalyTransferImbalance,
+				SubjectID: transfer.TransferID,
+				Expected:  0,
+				Actual:    transfer.EntriesTotal,
+			})
+			if err != nil {
+				return err
+			}
+			result.Anomalies = append(result.Anomalies, anomaly)
+		}
+
+		result.Resolved, err = q.ResolveLedgerAnomalies(ctx)
+		return err
+	})
+
+	return result, err
+}
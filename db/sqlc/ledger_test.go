@@ -0,0 +1,107 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func findAnomaly(anomalies []LedgerAnomaly, kind string, subjectID int64) *LedgerAnomaly {
+	for i := range anomalies {
+		if anomalies[i].Kind == kind && anomalies[i].SubjectID == subjectID {
+			return &anomalies[i]
+		}
+	}
+	return nil
+}
+
+// createUnbalancedEntry records an entry that no other entry balances, like those made before the entries
+// of a transaction had to sum to zero, for the reconciliation to find.
+func createUnbalancedEntry(t *testing.T, arg CreateEntryParams) {
+	err := NewStore(testDB).(*SQLStore).execTx(context.Background(), func(q *Queries) error {
+		_, err := q.CreateEntry(context.Background(), arg)
+		if err != nil {
+			return err
+		}
+
+		_, err = q.db.Exec(context.Background(), "SELECT set_config('bank.unbalanced', '0', true)")
+		return err
+	})
+	require.NoError(t, err)
+}
+
+func TestReconcileLedgerTx(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	// start from balances matching the entries
+	for _, account := range []Account{account1, account2} {
+		createUnbalancedEntry(t, CreateEntryParams{
+			AccountID: account.ID,
+			Amount:    account.Balance,
+		})
+	}
+
+	result, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	})
+	require.NoError(t, err)
+
+	reconciled, err := store.ReconcileLedgerTx(context.Background())
+	require.NoError(t, err)
+	require.Nil(t, findAnomaly(reconciled.Anomalies, AnomalyAccountBalance, account1.ID))
+	require.Nil(t, findAnomaly(reconciled.Anomalies, AnomalyTransferImbalance, result.Transfer.ID))
+
+	// an entry without its balance change and an entry unbalancing the transfer
+	createUnbalancedEntry(t, CreateEntryParams{
+		AccountID: account1.ID,
+		Amount:    5,
+	})
+	createUnbalancedEntry(t, CreateEntryParams{
+		AccountID:  account2.ID,
+		Amount:     3,
+		TransferID: result.ToEntry.TransferID,
+	})
+
+	reconciled, err = store.ReconcileLedgerTx(context.Background())
+	require.NoError(t, err)
+
+	anomaly := findAnomaly(reconciled.Anomalies, AnomalyAccountBalance, account1.ID)
+	require.NotNil(t, anomaly)
+	require.Equal(t, account1.Balance-5, anomaly.Expected)
+	require.Equal(t, account1.Balance-10, anomaly.Actual)
+	require.False(t, anomaly.ResolvedAt.Valid)
+
+	anomaly = findAnomaly(reconciled.Anomalies, AnomalyTransferImbalance, result.Transfer.ID)
+	require.NotNil(t, anomaly)
+	require.Equal(t, int64(3), anomaly.Actual)
+
+	// account2 got the entry without its balance
+	require.NotNil(t, findAnomaly(reconciled.Anomalies, AnomalyAccountBalance, account2.ID))
+
+	// reversing the entry resolves its anomaly
+	createUnbalancedEntry(t, CreateEntryParams{
+		AccountID: account1.ID,
+		Amount:    -5,
+	})
+
+	reconciled, err = store.ReconcileLedgerTx(context.Background())
+	require.NoError(t, err)
+	require.Nil(t, findAnomaly(reconciled.Anomalies, AnomalyAccountBalance, account1.ID))
+	require.Positive(t, reconciled.Resolved)
+
+	anomalies, err := testQueries.ListLedgerAnomalies(context.Background(), ListLedgerAnomaliesParams{
+		OnlyOpen:  true,
+		RowLimit:  5,
+		RowOffset: 0,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, anomalies)
+	for _, anomaly := range anomalies {
+		require.False(t, anomaly.ResolvedAt.Valid)
+	}
+}
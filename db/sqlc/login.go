@@ -0,0 +1,117 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// The UserLockedEvent type describes a user locked out after too many failed logins, for the
+// user.locked event which notifies them.
+type UserLockedEvent struct {
+	Username    string    `json:"username"`
+	ClientIP    string    `json:"client_ip"`
+	Failures    int64     `json:"failures"`
+	LockedUntil time.Time `json:"locked_until"`
+}
+
+// The RecordLoginFailureTxParams type contains the parameters to record a failed login.
+// @property {string} ClientIP - the client the wrong password was sent from.
+// @property {time.Duration} Window - how far back the failed logins are counted.
+// @property {int64} MaxFailures - the failed logins from the client within the window that lock the user out
+// of it.
+// @property {time.Duration} LockoutDuration - how long the user is locked.
+type RecordLoginFailureTxParams struct {
+	Username        string
+	ClientIP        string
+	Window          time.Duration
+	MaxFailures     int64
+	LockoutDuration time.Duration
+}
+
+// The RecordLoginFailureTxResult type holds the failed logins from the client counted within the window, and
+// the lockout when this failure locked the user out of it.
+type RecordLoginFailureTxResult struct {
+	Failures int64
+	Lockout  *LoginLockout
+}
+
+// RecordLoginFailureTx records a failed login of the user and locks them out of the client once they reach
+// the maximum of failed logins from it within the window, recording a user.locked event. The other clients
+// can still log in as the user, so that someone guessing their password can't keep them out. The failures
+// that locked the user are only counted until the lockout is over, they are kept so that the failures of
+// the client count whatever the user. The user row stays locked until the end of the transaction so that
+// concurrent failures are all counted.
+func (store *SQLStore) RecordLoginFailureTx(ctx context.Context, arg RecordLoginFailureTxParams) (RecordLoginFailureTxResult, error) {
+	var result RecordLoginFailureTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		_, err := q.GetUserForUpdate(ctx, arg.Username)
+		if err != nil {
+			return err
+		}
+
+		err = q.CreateLoginFailure(ctx, CreateLoginFailureParams{
+			Username: arg.Username,
+			ClientIp: arg.ClientIP,
+		})
+		if err != nil {
+			return err
+		}
+
+		since := time.Now().Add(-arg.Window)
+		previous, err := q.GetLoginLockout(ctx, GetLoginLockoutParams{
+			Username: arg.Username,
+			ClientIp: arg.ClientIP,
+		})
+		if err != nil && !errors.Is(err, ErrRecordNotFound) {
+			return err
+		}
+		if err == nil && previous.LockedUntil.After(since) {
+			since = previous.LockedUntil
+		}
+
+		result.Failures, err = q.CountLoginFailures(ctx, CountLoginFailuresParams{
+			Username: arg.Username,
+			ClientIp: arg.ClientIP,
+			Since:    since,
+		})
+		if err != nil || result.Failures < arg.MaxFailures {
+			return err
+		}
+
+		lockout, err := q.UpsertLoginLockout(ctx, UpsertLoginLockoutParams{
+			Username:    arg.Username,
+			Failures:    result.Failures,
+			ClientIp:    arg.ClientIP,
+			LockedUntil: time.Now().Add(arg.LockoutDuration),
+		})
+		if err != nil {
+			return err
+		}
+		result.Lockout = &lockout
+
+		return recordEvent(ctx, q, EventUserLocked, UserLockedEvent{
+			Username:    lockout.Username,
+			ClientIP:    lockout.ClientIp,
+			Failures:    lockout.Failures,
+			LockedUntil: lockout.LockedUntil,
+		})
+	})
+
+	return result, err
+}
+
+// UnlockUserTx lifts the lockouts of the user from all clients and clears their failed logins, so that they can log in
+// again right away.
+func (store *SQLStore) UnlockUserTx(ctx context.Context, username string) error {
+	return store.execTx(ctx, func(q *Queries) error {
+		_, err := q.DeleteLoginLockouts(ctx, username)
+		if err != nil {
+			return err
+		}
+
+		_, err = q.DeleteLoginFailures(ctx, username)
+		return err
+	})
+}
@@ -0,0 +1,99 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordLoginFailureTx(t *testing.T) {
+	store := NewStore(testDB)
+	user := createRandomUser(t)
+
+	arg := RecordLoginFailureTxParams{
+		Username:        user.Username,
+		ClientIP:        "10.0.0.1",
+		Window:          time.Minute,
+		MaxFailures:     3,
+		LockoutDuration: time.Hour,
+	}
+
+	for i := int64(1); i < arg.MaxFailures; i++ {
+		result, err := store.RecordLoginFailureTx(context.Background(), arg)
+		require.NoError(t, err)
+		require.Equal(t, i, result.Failures)
+		require.Nil(t, result.Lockout)
+	}
+
+	result, err := store.RecordLoginFailureTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.MaxFailures, result.Failures)
+	require.NotNil(t, result.Lockout)
+	require.Equal(t, arg.ClientIP, result.Lockout.ClientIp)
+	require.WithinDuration(t, time.Now().Add(arg.LockoutDuration), result.Lockout.LockedUntil, time.Minute)
+
+	key := GetLoginLockoutParams{Username: user.Username, ClientIp: arg.ClientIP}
+	lockout, err := store.GetLoginLockout(context.Background(), key)
+	require.NoError(t, err)
+	require.Equal(t, arg.MaxFailures, lockout.Failures)
+
+	// the user isn't locked out of the other clients, whose failures count on their own
+	_, err = store.GetLoginLockout(context.Background(), GetLoginLockoutParams{Username: user.Username, ClientIp: "10.0.0.2"})
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	other := arg
+	other.ClientIP = "10.0.0.2"
+	result, err = store.RecordLoginFailureTx(context.Background(), other)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), result.Failures)
+	require.Nil(t, result.Lockout)
+
+	// the failures that locked the user are kept for the client, whatever the user
+	failures, err := store.CountClientLoginFailures(context.Background(), CountClientLoginFailuresParams{
+		ClientIp: arg.ClientIP,
+		Since:    time.Now().Add(-arg.Window),
+	})
+	require.NoError(t, err)
+	require.Equal(t, arg.MaxFailures, failures)
+
+	err = store.UnlockUserTx(context.Background(), user.Username)
+	require.NoError(t, err)
+
+	_, err = store.GetLoginLockout(context.Background(), key)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	failures, err = store.CountLoginFailures(context.Background(), CountLoginFailuresParams{
+		Username: user.Username,
+		ClientIp: arg.ClientIP,
+		Since:    time.Now().Add(-arg.Window),
+	})
+	require.NoError(t, err)
+	require.Zero(t, failures)
+}
+
+// Once a lockout is over, the failures that locked the user no longer count towards the next one.
+func TestRecordLoginFailureTxAfterLockout(t *testing.T) {
+	store := NewStore(testDB)
+	user := createRandomUser(t)
+
+	arg := RecordLoginFailureTxParams{
+		Username:        user.Username,
+		ClientIP:        "10.0.0.3",
+		Window:          time.Hour,
+		MaxFailures:     2,
+		LockoutDuration: time.Millisecond,
+	}
+
+	for i := int64(0); i < arg.MaxFailures; i++ {
+		_, err := store.RecordLoginFailureTx(context.Background(), arg)
+		require.NoError(t, err)
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	result, err := store.RecordLoginFailureTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), result.Failures)
+	require.Nil(t, result.Lockout)
+}
@@ -1,17 +1,19 @@
 package db
 
 import (
-	"database/sql"
+	"context"
 	"go-backend/util"
 	"log"
 	"os"
 	"testing"
 
-	_ "github.com/lib/pq"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
 )
 
 var testQueries *Queries
-var testDB *sql.DB
+var testDB *pgxpool.Pool
+var testRedis *redis.Client
 
 func TestMain(m *testing.M) {
 	config, err := util.LoadConfig("../../app.env")
@@ -19,11 +21,12 @@ func TestMain(m *testing.M) {
 		log.Fatal("cannot load config: ", err)
 	}
 
-	testDB, err = sql.Open(config.DBDriver, config.DBSource)
+	testDB, err = pgxpool.New(context.Background(), config.DBSource)
 	if err != nil {
 		log.Fatal("cannot connect to db: ", err)
 	}
 
 	testQueries = New(testDB)
+	testRedis = redis.NewClient(&redis.Options{Addr: config.RedisAddress})
 	os.Exit(m.Run())
 }
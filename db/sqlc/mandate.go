@@ -0,0 +1,88 @@
+package db
+
+import (
+	"context"
+	"errors"
+)
+
+// Statuses of a mandate. Only active mandates are pulled from, a revoked mandate staying revoked.
+const (
+	MandateActive  = "active"
+	MandateRevoked = "revoked"
+)
+
+var (
+	// ErrMandateRevoked is returned when pulling funds with a mandate that was revoked.
+	ErrMandateRevoked = errors.New("mandate has been revoked")
+	// ErrMandateLimitExceeded is returned when pulling more than the most a mandate allows at once.
+	ErrMandateLimitExceeded = errors.New("amount exceeds the maximum of the mandate")
+	// ErrMandateCapExceeded is returned when a pull would take the total pulled with a mandate over its cap.
+	ErrMandateCapExceeded = errors.New("amount exceeds what remains under the cap of the mandate")
+)
+
+// The PullMandateTxParams type contains a pull of funds by a merchant with a mandate.
+// @property {int64} ID - the mandate pulled with.
+// @property {int64} Amount - the positive amount pulled, at most the maximum of the mandate and what remains
+// under its cap.
+// @property {string} Memo - optional free text kept on the transfer.
+// @property {string} ExternalReference - optional reference of the payment outside the bank, e.g. an
+// invoice number of the merchant.
+// @property {string} Currency - the currency the accounts were checked to hold, checked again within
+// the transaction of the transfer.
+type PullMandateTxParams struct {
+	ID                int64
+	Amount            int64
+	Memo              string
+	ExternalReference string
+	Currency          string
+}
+
+// The PullMandateTxResult type is the mandate pulled with, along with the transfer pulling the funds.
+type PullMandateTxResult struct {
+	Mandate  Mandate          `json:"mandate"`
+	Transfer TransferTxResult `json:"transfer"`
+}
+
+// PullMandateTx makes the transfer from the account of the payer to the account of the merchant and
+// records it on the mandate. The mandate is locked so that a pull can't race with its revocation nor with
+// another pull, ErrMandateRevoked being returned once it is revoked, ErrMandateLimitExceeded when the
+// amount exceeds its maximum and ErrMandateCapExceeded when it would take the total pulled over its cap.
+func (store *SQLStore) PullMandateTx(ctx context.Context, arg PullMandateTxParams) (PullMandateTxResult, error) {
+	var result PullMandateTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		mandate, err := q.GetMandateForUpdate(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+		if mandate.Status != MandateActive {
+			return ErrMandateRevoked
+		}
+		if arg.Amount > mandate.MaxAmount {
+			return ErrMandateLimitExceeded
+		}
+		if mandate.CapAmount.Valid && mandate.PulledAmount+arg.Amount > mandate.CapAmount.Int64 {
+			return ErrMandateCapExceeded
+		}
+
+		result.Transfer, err = transfer(ctx, q, TransferTxParams{
+			FromAccountID:     mandate.FromAccountID,
+			ToAccountID:       mandate.MerchantAccountID,
+			Amount:            arg.Amount,
+			Memo:              arg.Memo,
+			ExternalReference: arg.ExternalReference,
+			Currency:          arg.Currency,
+		}, nil)
+		if err != nil {
+			return err
+		}
+
+		result.Mandate, err = q.RecordMandatePull(ctx, RecordMandatePullParams{
+			Amount: arg.Amount,
+			ID:     arg.ID,
+		})
+		return err
+	})
+
+	return result, err
+}
@@ -0,0 +1,58 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPullMandateTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	fromAccount := createRandomAccount(t)
+	merchantAccount := createRandomAccount(t)
+	mandate, err := testQueries.CreateMandate(context.Background(), CreateMandateParams{
+		Payer:             fromAccount.Owner,
+		FromAccountID:     fromAccount.ID,
+		MerchantAccountID: merchantAccount.ID,
+		MaxAmount:         10,
+		CapAmount:         pgtype.Int8{Int64: 15, Valid: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, MandateActive, mandate.Status)
+
+	result, err := store.PullMandateTx(context.Background(), PullMandateTxParams{ID: mandate.ID, Amount: 10, ExternalReference: "INV-1"})
+	require.NoError(t, err)
+	require.Equal(t, fromAccount.Balance-10, result.Transfer.FromAccount.Balance)
+	require.Equal(t, merchantAccount.Balance+10, result.Transfer.ToAccount.Balance)
+	require.Equal(t, "INV-1", result.Transfer.Transfer.ExternalReference)
+	require.Equal(t, int64(10), result.Mandate.PulledAmount)
+	require.True(t, result.Mandate.LastPulledAt.Valid)
+
+	// a pull can't exceed the maximum of the mandate, nor be made once it is revoked
+	_, err = store.PullMandateTx(context.Background(), PullMandateTxParams{ID: mandate.ID, Amount: 11})
+	require.ErrorIs(t, err, ErrMandateLimitExceeded)
+
+	// nor take the total pulled over the cap of the mandate
+	_, err = store.PullMandateTx(context.Background(), PullMandateTxParams{ID: mandate.ID, Amount: 6})
+	require.ErrorIs(t, err, ErrMandateCapExceeded)
+	result, err = store.PullMandateTx(context.Background(), PullMandateTxParams{ID: mandate.ID, Amount: 5})
+	require.NoError(t, err)
+	require.Equal(t, int64(15), result.Mandate.PulledAmount)
+
+	revoked, err := testQueries.RevokeMandate(context.Background(), mandate.ID)
+	require.NoError(t, err)
+	require.Equal(t, MandateRevoked, revoked.Status)
+	_, err = testQueries.RevokeMandate(context.Background(), mandate.ID)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	_, err = store.PullMandateTx(context.Background(), PullMandateTxParams{ID: mandate.ID, Amount: 1})
+	require.ErrorIs(t, err, ErrMandateRevoked)
+
+	mandates, err := testQueries.ListMandates(context.Background(), ListMandatesParams{Payer: fromAccount.Owner, Limit: 5})
+	require.NoError(t, err)
+	require.Len(t, mandates, 1)
+	require.Equal(t, int64(15), mandates[0].PulledAmount)
+}
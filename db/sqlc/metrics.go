@@ -0,0 +1,82 @@
+package db
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Steps of `TransferTx` timed by TransferTxStepDuration. Locking shows up in update_balances, where the
+// rows of both accounts are locked, while begin includes waiting for a connection of the pool.
+const (
+	transferStepBegin          = "begin"
+	transferStepCreateTransfer = "create_transfer"
+	transferStepCreateEntries  = "create_entries"
+	transferStepUpdateBalances = "update_balances"
+	transferStepRecordEvents   = "record_events"
+	transferStepCommit         = "commit"
+)
+
+// TransferTxStepDuration is the duration of each step of the successful transfer transactions. It is
+// shared by every store of the process, and registered by the servers exposing metrics.
+var TransferTxStepDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "transfer_tx_step_duration_seconds",
+	Help:    "Duration of each step of the transfer transactions.",
+	Buckets: []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+}, []string{"step"})
+
+// DBFailovers is the number of failovers of a `FailoverPool` to the next source of the primary database.
+var DBFailovers = prometheus.NewCounter(prometheus.CounterOpts{
+	Name: "db_failovers_total",
+	Help: "Number of failovers of the primary database to the next configured source.",
+})
+
+// DBActiveSource is the index of the source of the primary database a `FailoverPool` currently uses, in
+// the configured order.
+var DBActiveSource = prometheus.NewGauge(prometheus.GaugeOpts{
+	Name: "db_active_source",
+	Help: "Index of the configured source of the primary database currently used.",
+})
+
+// DBRetries is the number of calls to the store retried by a `RetryStore` after a transient error, by
+// method of the store.
+var DBRetries = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "db_retries_total",
+	Help: "Number of queries and transactions retried after a transient error.",
+}, []string{"method"})
+
+// The stepTimer type times consecutive steps of a transaction. The durations are only observed once the
+// transaction succeeded, so that failed attempts don't skew the breakdown.
+type stepTimer struct {
+	histogram *prometheus.HistogramVec
+	last      time.Time
+	steps     []string
+	durations []time.Duration
+}
+
+func newStepTimer(histogram *prometheus.HistogramVec) *stepTimer {
+	return &stepTimer{
+		histogram: histogram,
+		last:      time.Now(),
+	}
+}
+
+// The `step` function ends the current step, which started when the previous one ended. Steps of a nil
+// timer are not timed.
+func (timer *stepTimer) step(name string) {
+	if timer == nil {
+		return
+	}
+
+	now := time.Now()
+	timer.steps = append(timer.steps, name)
+	timer.durations = append(timer.durations, now.Sub(timer.last))
+	timer.last = now
+}
+
+// The `observe` function records the duration of every step in the histogram.
+func (timer *stepTimer) observe() {
+	for i, name := range timer.steps {
+		timer.histogram.WithLabelValues(name).Observe(timer.durations[i].Seconds())
+	}
+}
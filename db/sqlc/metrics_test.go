@@ -0,0 +1,23 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStepTimer(t *testing.T) {
+	histogram := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "test_step_duration_seconds",
+	}, []string{"step"})
+
+	timer := newStepTimer(histogram)
+	timer.step("first")
+	timer.step("second")
+	require.Zero(t, testutil.CollectAndCount(histogram))
+
+	timer.observe()
+	require.Equal(t, 2, testutil.CollectAndCount(histogram))
+}
@@ -5,17 +5,212 @@
 package db
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type Account struct {
-	ID        int64     `json:"id"`
-	Owner     string    `json:"owner"`
+	ID    int64  `json:"id"`
+	Owner string `json:"owner"`
+	// the ledger balance, the sum of the entries of the account
 	Balance   int64     `json:"balance"`
 	Currency  string    `json:"currency"`
 	CreatedAt time.Time `json:"created_at"`
+	// IBAN-like number of the account derived from its id, GO + 2 ISO 7064 MOD 97-10 check digits + BANK + the id on 10 digits, see util.AccountNumber
+	AccountNumber string `json:"account_number"`
+	// incremented by every update of the account, sent as its ETag
+	Version int64 `json:"version"`
+	// when the account was closed by the deletion of its owner's data, null while it is open
+	ClosedAt pgtype.Timestamptz `json:"closed_at"`
+	// the organization of the owner of the account
+	OrgID int64 `json:"org_id"`
+	// the sum of the authorized holds, which the available balance is the ledger balance less of
+	HeldBalance int64 `json:"held_balance"`
+	// the name the owner gave the account, empty when they gave none
+	Nickname string `json:"nickname"`
+	// the service tier of the account, set by admins, e.g. standard or premium
+	Tier string `json:"tier"`
+}
+
+type AccountAlert struct {
+	AccountID int64 `json:"account_id"`
+	// a transfer leaving the account below it notifies its owner, overriding their own threshold, 0 to never notify
+	LowBalanceThreshold int64     `json:"low_balance_threshold"`
+	UpdatedAt           time.Time `json:"updated_at"`
+}
+
+type AccountCounterpartyActivity struct {
+	AccountID             int64     `json:"account_id"`
+	CounterpartyAccountID int64     `json:"counterparty_account_id"`
+	CounterpartyOwner     string    `json:"counterparty_owner"`
+	Sent                  int64     `json:"sent"`
+	Received              int64     `json:"received"`
+	TransferCount         int64     `json:"transfer_count"`
+	LastTransferAt        time.Time `json:"last_transfer_at"`
+}
+
+type AccountDailyActivity struct {
+	AccountID int64     `json:"account_id"`
+	Day       time.Time `json:"day"`
+	// sum of the amounts received by transfer on the day
+	Credits int64 `json:"credits"`
+	// sum of the amounts sent by transfer on the day, positive
+	Debits        int64 `json:"debits"`
+	TransferCount int64 `json:"transfer_count"`
+}
+
+type AccountDailyVolume struct {
+	AccountID int64     `json:"account_id"`
+	Day       time.Time `json:"day"`
+	Volume    int64     `json:"volume"`
+}
+
+type AccountHistory struct {
+	ID int64 `json:"id"`
+	// the account, kept once deleted
+	AccountID int64  `json:"account_id"`
+	Owner     string `json:"owner"`
+	Currency  string `json:"currency"`
+	// the account had these values from this time
+	ValidFrom time.Time `json:"valid_from"`
+	// the account had these values until this time, null for its current values
+	ValidTo pgtype.Timestamptz `json:"valid_to"`
+	// open, or closed once the data of its owner was deleted
+	Status   string `json:"status"`
+	Nickname string `json:"nickname"`
+	Tier     string `json:"tier"`
+}
+
+type AccountMember struct {
+	AccountID int64 `json:"account_id"`
+	// a user sharing the account with its owner
+	Username string `json:"username"`
+	// owner or viewer, viewers can only read the account
+	Role      string    `json:"role"`
+	InvitedBy string    `json:"invited_by"`
+	CreatedAt time.Time `json:"created_at"`
+	// when the user accepted the invitation, null while it is pending
+	AcceptedAt pgtype.Timestamptz `json:"accepted_at"`
+}
+
+type AccountOverview struct {
+	AccountID     int64  `json:"account_id"`
+	Owner         string `json:"owner"`
+	Currency      string `json:"currency"`
+	Balance       int64  `json:"balance"`
+	TransferCount int64  `json:"transfer_count"`
+	// sum of the absolute amounts transferred over the last 30 days
+	Volume30d      int64              `json:"volume_30d"`
+	LastActivityAt pgtype.Timestamptz `json:"last_activity_at"`
+	CreatedAt      time.Time          `json:"created_at"`
+}
+
+type AuditEntry struct {
+	ID int64 `json:"id"`
+	// the user the action was taken as
+	Username string `json:"username"`
+	// the admin who took the action while impersonating the user, null when the user took it
+	Impersonator pgtype.Text `json:"impersonator"`
+	// e.g. impersonation.started, or the method and route of a request such as POST /api/v1/transfers
+	Action string `json:"action"`
+	Detail string `json:"detail"`
+	// the HTTP status of the request, 0 for the actions that are not requests
+	Status    int32     `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type BalanceSnapshot struct {
+	AccountID    int64     `json:"account_id"`
+	BusinessDate time.Time `json:"business_date"`
+	// balance of the account at the end of the business date, in UTC
+	Balance   int64     `json:"balance"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type BankParameter struct {
+	// transfer_limit, transfer_fee or interest_rate_bps
+	Name string `json:"name"`
+	// starts at 1 and increases with every publication of the parameter
+	Version int32 `json:"version"`
+	Value   int64 `json:"value"`
+	// the version applies from then until the next effective version
+	EffectiveAt time.Time `json:"effective_at"`
+	PublishedBy string    `json:"published_by"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type BatchRun struct {
+	Name         string    `json:"name"`
+	BusinessDate time.Time `json:"business_date"`
+	// the batch resumes after this account when restarted
+	LastAccountID     int64     `json:"last_account_id"`
+	ProcessedAccounts int64     `json:"processed_accounts"`
+	StartedAt         time.Time `json:"started_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+	// null until every account was processed
+	CompletedAt pgtype.Timestamptz `json:"completed_at"`
+}
+
+type Beneficiary struct {
+	ID int64 `json:"id"`
+	// the user who saved the account
+	Owner string `json:"owner"`
+	// the account the transfers to the beneficiary are sent to
+	AccountID int64     `json:"account_id"`
+	Nickname  string    `json:"nickname"`
+	CreatedAt time.Time `json:"created_at"`
+	// the spending category of the transfers to the beneficiary, e.g. groceries, empty when uncategorized
+	Category string `json:"category"`
+}
+
+type Budget struct {
+	AccountID int64  `json:"account_id"`
+	Category  string `json:"category"`
+	// must be positive, the transfers to the beneficiaries of the category sent from the account in a calendar month exceeding it notify its owner
+	MonthlyLimit int64     `json:"monthly_limit"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+type Card struct {
+	ID        int64 `json:"id"`
+	AccountID int64 `json:"account_id"`
+	// the user the card was issued to
+	Owner string `json:"owner"`
+	// the last 4 digits of the card number, the only ones kept
+	Last4       string `json:"last4"`
+	ExpiryMonth int32  `json:"expiry_month"`
+	ExpiryYear  int32  `json:"expiry_year"`
+	// active or frozen, a frozen card declining every authorization
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type ChequeDeposit struct {
+	ID        int64 `json:"id"`
+	AccountID int64 `json:"account_id"`
+	// the user who deposited the cheque
+	Owner string `json:"owner"`
+	// the amount of the cheque credited to the account, must be positive
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+	// the number printed on the cheque
+	ChequeNumber string `json:"cheque_number"`
+	// pending, cleared or bounced, the amount of a pending cheque being held on the account
+	Status string `json:"status"`
+	// the transfer crediting the amount from the cheque_clearing account
+	TransferID int64 `json:"transfer_id"`
+	// the hold reserving the amount credited until the cheque clears
+	HoldID int64 `json:"hold_id"`
+	// the transfer reversing the credit of a bounced cheque
+	ReversalTransferID pgtype.Int8 `json:"reversal_transfer_id"`
+	// the hold is released at this time unless the cheque bounced
+	ClearsAt  time.Time          `json:"clears_at"`
+	SettledAt pgtype.Timestamptz `json:"settled_at"`
+	CreatedAt time.Time          `json:"created_at"`
 }
 
 type Entry struct {
@@ -24,6 +219,324 @@ type Entry struct {
 	// can be negative or positive
 	Amount    int64     `json:"amount"`
 	CreatedAt time.Time `json:"created_at"`
+	// transfer the entry was made for, null for the other entries such as interest and holds, and for the entries made before it was recorded
+	TransferID pgtype.Int8 `json:"transfer_id"`
+}
+
+type Event struct {
+	ID int64 `json:"id"`
+	// e.g. user.created, account.created, transfer.completed
+	Type      string          `json:"type"`
+	Payload   json.RawMessage `json:"payload"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+type ExternalTransfer struct {
+	ID int64 `json:"id"`
+	// the user sending the money out of the bank
+	Owner     string `json:"owner"`
+	AccountID int64  `json:"account_id"`
+	// ach or wire, the network the money is sent through
+	Rail string `json:"rail"`
+	// must be positive
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+	// the routing number of the receiving bank
+	RoutingNumber string `json:"routing_number"`
+	// the account at the receiving bank
+	AccountNumber   string `json:"account_number"`
+	BeneficiaryName string `json:"beneficiary_name"`
+	// initiated, processing, settled or failed, settled and failed being final
+	Status string `json:"status"`
+	// why the receiving bank returned the money, when failed
+	FailureReason string `json:"failure_reason"`
+	// the transfer of the amount to the external_clearing account, made when initiated
+	TransferID int64 `json:"transfer_id"`
+	// the transfer of the amount back to the account, made when failed
+	ReturnTransferID pgtype.Int8 `json:"return_transfer_id"`
+	CreatedAt        time.Time   `json:"created_at"`
+	UpdatedAt        time.Time   `json:"updated_at"`
+}
+
+type Hold struct {
+	ID int64 `json:"id"`
+	// the card whose authorization placed the hold, if any
+	CardID    pgtype.Int8 `json:"card_id"`
+	AccountID int64       `json:"account_id"`
+	// the amount reserved on the account, must be positive
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+	// what the amount is reserved for, e.g. the merchant a card was authorized for
+	Memo string `json:"memo"`
+	// authorized, captured, released or expired, only authorized holds reserving their amount
+	Status string `json:"status"`
+	// the amount settled when captured, at most the amount of the hold
+	CapturedAmount int64 `json:"captured_amount"`
+	// the transfer of the captured amount out of the account
+	TransferID pgtype.Int8 `json:"transfer_id"`
+	// the hold is released past this time unless captured
+	ExpiresAt time.Time          `json:"expires_at"`
+	SettledAt pgtype.Timestamptz `json:"settled_at"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+type InterestAccrual struct {
+	AccountID int64 `json:"account_id"`
+	// the interest accrued but not yet credited to the account, in millionths of its smallest unit
+	Remainder int64     `json:"remainder"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type Job struct {
+	ID       uuid.UUID `json:"id"`
+	Username string    `json:"username"`
+	// statement, gdpr or entries
+	Kind   string          `json:"kind"`
+	Params json.RawMessage `json:"params"`
+	// pending, running, succeeded, failed or cancelled
+	Status string `json:"status"`
+	// percentage between 0 and 100
+	Progress          int32              `json:"progress"`
+	Result            []byte             `json:"result"`
+	ResultContentType string             `json:"result_content_type"`
+	Error             string             `json:"error"`
+	CreatedAt         time.Time          `json:"created_at"`
+	UpdatedAt         time.Time          `json:"updated_at"`
+	CompletedAt       pgtype.Timestamptz `json:"completed_at"`
+}
+
+type LeaderLease struct {
+	Name string `json:"name"`
+	// id of the instance holding the lease
+	Holder string `json:"holder"`
+	// address the holder serves writes at, sent to clients of the standby instances
+	Address    string    `json:"address"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	AcquiredAt time.Time `json:"acquired_at"`
+}
+
+type LedgerAnomaly struct {
+	ID int64 `json:"id"`
+	// account_balance or transfer_imbalance
+	Kind string `json:"kind"`
+	// id of the account for account_balance, of the transfer for transfer_imbalance
+	SubjectID int64 `json:"subject_id"`
+	// sum of the entries of the account, or 0 for a transfer
+	Expected int64 `json:"expected"`
+	// balance of the account, or sum of the entries of the transfer
+	Actual          int64     `json:"actual"`
+	FirstDetectedAt time.Time `json:"first_detected_at"`
+	LastDetectedAt  time.Time `json:"last_detected_at"`
+	// set once a reconciliation no longer finds the anomaly
+	ResolvedAt pgtype.Timestamptz `json:"resolved_at"`
+}
+
+type LoginFailure struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+	// the client the wrong password was sent from
+	ClientIp  string    `json:"client_ip"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type LoginLockout struct {
+	Username string `json:"username"`
+	// the failed logins within the window that locked the user
+	Failures int64 `json:"failures"`
+	// the client of the failed login that locked the user
+	ClientIp string `json:"client_ip"`
+	// the user can't log in until then, unless an admin unlocks them
+	LockedUntil time.Time `json:"locked_until"`
+	LockedAt    time.Time `json:"locked_at"`
+}
+
+type Mandate struct {
+	ID int64 `json:"id"`
+	// the user authorizing the merchant to pull funds from their account
+	Payer string `json:"payer"`
+	// the account of the payer the funds are pulled from
+	FromAccountID int64 `json:"from_account_id"`
+	// the account the funds are pulled to, whose owners pull them
+	MerchantAccountID int64 `json:"merchant_account_id"`
+	// must be positive, the most a single pull takes from the account
+	MaxAmount int64 `json:"max_amount"`
+	// active or revoked, only active mandates are pulled from
+	Status string `json:"status"`
+	// the total pulled with the mandate
+	PulledAmount int64              `json:"pulled_amount"`
+	LastPulledAt pgtype.Timestamptz `json:"last_pulled_at"`
+	RevokedAt    pgtype.Timestamptz `json:"revoked_at"`
+	CreatedAt    time.Time          `json:"created_at"`
+	// the most pulled with the mandate in total, at least the maximum of a pull, none for the mandates granted before the caps
+	CapAmount pgtype.Int8 `json:"cap_amount"`
+}
+
+type Notification struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+	// event the notification was projected from
+	EventID int64 `json:"event_id"`
+	// e.g. transfer.sent, transfer.received, account.low_balance
+	Type      string          `json:"type"`
+	Payload   json.RawMessage `json:"payload"`
+	CreatedAt time.Time       `json:"created_at"`
+	// when the user marked the notification as read, null while unread
+	ReadAt pgtype.Timestamptz `json:"read_at"`
+}
+
+type NotificationDelivery struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+	EventID  int64  `json:"event_id"`
+	Type     string `json:"type"`
+	// email or webhook
+	Channel string `json:"channel"`
+	// the email address or webhook URL, as set when the event was projected
+	Destination string          `json:"destination"`
+	Payload     json.RawMessage `json:"payload"`
+	// pending, delivered or failed
+	Status    string `json:"status"`
+	Attempts  int32  `json:"attempts"`
+	LastError string `json:"last_error"`
+	// when the dispatcher sends the notification next, pushed back while it is being sent
+	NextAttemptAt time.Time          `json:"next_attempt_at"`
+	DeliveredAt   pgtype.Timestamptz `json:"delivered_at"`
+	CreatedAt     time.Time          `json:"created_at"`
+}
+
+type NotificationPreference struct {
+	Username string `json:"username"`
+	// whether the notifications are listed in the app
+	InApp bool `json:"in_app"`
+	// whether the notifications are sent to the email of the user
+	Email bool `json:"email"`
+	// where the notifications are posted, empty to not post them
+	WebhookURL string `json:"webhook_url"`
+	// a transfer leaving an account below it notifies its owner, 0 to never notify
+	LowBalanceThreshold int64     `json:"low_balance_threshold"`
+	UpdatedAt           time.Time `json:"updated_at"`
+}
+
+type Organization struct {
+	ID int64 `json:"id"`
+	// the name of the organization in urls and at the sign up of its users, e.g. acme
+	Slug      string    `json:"slug"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	// the transfers of at least this amount from the accounts of the organization await a second approver, none when 0
+	ApprovalThreshold int64 `json:"approval_threshold"`
+}
+
+type PaymentLink struct {
+	ID int64 `json:"id"`
+	// the random code the link is shared and looked up with
+	Code string `json:"code"`
+	// the user the link collects money for
+	Merchant string `json:"merchant"`
+	// the account of the merchant the money is sent to
+	ToAccountID int64 `json:"to_account_id"`
+	// the amount to pay, must be positive
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+	Memo     string `json:"memo"`
+	// active, paid, cancelled or expired, only an active link can be paid
+	Status string `json:"status"`
+	// an active link can no longer be paid past this time
+	ExpiresAt time.Time `json:"expires_at"`
+	// the user who paid the link
+	Payer pgtype.Text `json:"payer"`
+	// the account of the payer the money was taken from once paid
+	FromAccountID pgtype.Int8 `json:"from_account_id"`
+	// the transfer made once the link was paid
+	TransferID pgtype.Int8 `json:"transfer_id"`
+	// when the link was paid, cancelled or expired
+	ClosedAt  pgtype.Timestamptz `json:"closed_at"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+type PaymentRequest struct {
+	ID int64 `json:"id"`
+	// the user asking for the money
+	Requester string `json:"requester"`
+	// the user asked to pay
+	Payer string `json:"payer"`
+	// the account of the requester the money is sent to
+	ToAccountID int64  `json:"to_account_id"`
+	Amount      int64  `json:"amount"`
+	Memo        string `json:"memo"`
+	// pending, accepted, declined or expired
+	Status string `json:"status"`
+	// a pending request can no longer be accepted past this time
+	ExpiresAt time.Time `json:"expires_at"`
+	// the account of the payer the money was taken from once accepted
+	FromAccountID pgtype.Int8 `json:"from_account_id"`
+	// the transfer made once the request was accepted
+	TransferID pgtype.Int8        `json:"transfer_id"`
+	DecidedAt  pgtype.Timestamptz `json:"decided_at"`
+	CreatedAt  time.Time          `json:"created_at"`
+}
+
+type PendingTransfer struct {
+	ID int64 `json:"id"`
+	// the user who requested the transfer
+	Owner             string `json:"owner"`
+	FromAccountID     int64  `json:"from_account_id"`
+	ToAccountID       int64  `json:"to_account_id"`
+	Amount            int64  `json:"amount"`
+	Memo              string `json:"memo"`
+	ExternalReference string `json:"external_reference"`
+	// pending_approval, approved, rejected or expired
+	Status string `json:"status"`
+	// the transfer can no longer be approved past this time
+	ExpiresAt time.Time `json:"expires_at"`
+	// the owner, the banker or the admin of the organization who approved or rejected the transfer
+	DecidedBy pgtype.Text `json:"decided_by"`
+	// the transfer made once approved
+	TransferID pgtype.Int8        `json:"transfer_id"`
+	DecidedAt  pgtype.Timestamptz `json:"decided_at"`
+	CreatedAt  time.Time          `json:"created_at"`
+	// the organization whose admins approve the transfer, a second approver other than its owner; null for the transfers the owner confirms
+	OrgID pgtype.Int8 `json:"org_id"`
+}
+
+type ProcessedTask struct {
+	// derived from the business keys of the task, e.g. task:run_export:<job id>
+	ID          string    `json:"id"`
+	Type        string    `json:"type"`
+	ProcessedAt time.Time `json:"processed_at"`
+}
+
+type ProjectionCheckpoint struct {
+	Name string `json:"name"`
+	// id of the last event applied by the projection
+	LastEventID int64     `json:"last_event_id"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type QueuedTransfer struct {
+	// the tracking ID returned when the transfer was queued
+	ID    uuid.UUID `json:"id"`
+	Owner string    `json:"owner"`
+	// succeeded, held, pending_approval or failed
+	Status            string      `json:"status"`
+	TransferID        pgtype.Int8 `json:"transfer_id"`
+	ReviewID          pgtype.Int8 `json:"review_id"`
+	PendingTransferID pgtype.Int8 `json:"pending_transfer_id"`
+	// why the transfer failed
+	Error string `json:"error"`
+	// when the transfer was accepted while the database was unavailable
+	QueuedAt    time.Time `json:"queued_at"`
+	ProcessedAt time.Time `json:"processed_at"`
+}
+
+type RouteRequestVolume struct {
+	// method and path template of the route
+	Route string `json:"route"`
+	// start of the hour the requests were received in
+	Hour     time.Time `json:"hour"`
+	Requests int64     `json:"requests"`
+	// requests answered with a 5xx status
+	Errors int64 `json:"errors"`
 }
 
 type Session struct {
@@ -35,6 +548,51 @@ type Session struct {
 	IsBlocked    bool      `json:"is_blocked"`
 	ExpiresAt    time.Time `json:"expires_at"`
 	CreatedAt    time.Time `json:"created_at"`
+	// last time a token of the session was used, written behind so it may lag by the flush interval
+	LastUsedAt time.Time `json:"last_used_at"`
+}
+
+type SigningKey struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+	// the HMAC key the requests of the user are signed with, kept as is since the server signs with it too
+	Secret    string    `json:"secret"`
+	CreatedAt time.Time `json:"created_at"`
+	// the requests signed with the key are rejected once set
+	RevokedAt pgtype.Timestamptz `json:"revoked_at"`
+}
+
+type StandingOrder struct {
+	ID int64 `json:"id"`
+	// the user who set up the standing order, who must be able to use the from account as an owner
+	Owner         string `json:"owner"`
+	FromAccountID int64  `json:"from_account_id"`
+	ToAccountID   int64  `json:"to_account_id"`
+	// must be positive, the amount transferred on every run
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+	Memo     string `json:"memo"`
+	// weekly or monthly
+	Frequency string `json:"frequency"`
+	// the first run, the later ones falling on the same weekday or day of the month, or the last day of a shorter month
+	StartsAt time.Time `json:"starts_at"`
+	// the runs due so far, whether their transfer was made or failed
+	RunCount  int32     `json:"run_count"`
+	NextRunAt time.Time `json:"next_run_at"`
+	// active or cancelled, only active standing orders run
+	Status    string             `json:"status"`
+	LastRunAt pgtype.Timestamptz `json:"last_run_at"`
+	// why the transfer of the last run failed, empty when it was made
+	LastFailure string             `json:"last_failure"`
+	CancelledAt pgtype.Timestamptz `json:"cancelled_at"`
+	CreatedAt   time.Time          `json:"created_at"`
+}
+
+type SystemAccount struct {
+	// fees, fx_spread, suspense, external_clearing, card_settlement, cheque_clearing or interest
+	Purpose   string `json:"purpose"`
+	Currency  string `json:"currency"`
+	AccountID int64  `json:"account_id"`
 }
 
 type Transfer struct {
@@ -44,6 +602,33 @@ type Transfer struct {
 	// must be positive
 	Amount    int64     `json:"amount"`
 	CreatedAt time.Time `json:"created_at"`
+	// free text written by the sender, empty when omitted
+	Memo string `json:"memo"`
+	// reference of the payment outside the bank, e.g. an invoice number
+	ExternalReference string `json:"external_reference"`
+}
+
+type TransferReview struct {
+	ID            int64 `json:"id"`
+	FromAccountID int64 `json:"from_account_id"`
+	ToAccountID   int64 `json:"to_account_id"`
+	// taken from the from account while the review is open
+	Amount            int64  `json:"amount"`
+	Memo              string `json:"memo"`
+	ExternalReference string `json:"external_reference"`
+	// why the screening held the transfer
+	HoldReason string `json:"hold_reason"`
+	// pending, escalated, approved or denied
+	Status   string      `json:"status"`
+	Assignee pgtype.Text `json:"assignee"`
+	// the review is overdue once open past this time
+	DueAt time.Time `json:"due_at"`
+	Note  string    `json:"note"`
+	// the transfer made once the review was approved
+	TransferID pgtype.Int8        `json:"transfer_id"`
+	DecidedBy  pgtype.Text        `json:"decided_by"`
+	DecidedAt  pgtype.Timestamptz `json:"decided_at"`
+	CreatedAt  time.Time          `json:"created_at"`
 }
 
 type User struct {
@@ -53,4 +638,47 @@ type User struct {
 	Email             string    `json:"email"`
 	PasswordChangedAt time.Time `json:"password_changed_at"`
 	CreatedAt         time.Time `json:"created_at"`
+	// depositor, org_admin, admin or system
+	Role string `json:"role"`
+	// the organization of the user, whose data the user can't see outside of it
+	OrgID int64 `json:"org_id"`
+}
+
+type UserApiPlan struct {
+	Username string `json:"username"`
+	// a plan of the API_PLANS config, whose monthly quota of calls the user is held to
+	Plan      string    `json:"plan"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type UserDeletion struct {
+	Username string `json:"username"`
+	// scheduled, cancelled, completed or failed
+	Status string `json:"status"`
+	// the end of the cooling-off period, when the data of the user is deleted unless they cancel
+	ScheduledFor time.Time `json:"scheduled_for"`
+	// why the deletion failed, e.g. an account of the user holding money
+	Error       string             `json:"error"`
+	CreatedAt   time.Time          `json:"created_at"`
+	CompletedAt pgtype.Timestamptz `json:"completed_at"`
+}
+
+type UserIdentity struct {
+	// the identity provider the user logs in with, e.g. google or github
+	Provider string `json:"provider"`
+	// the id of the user at the provider, which never changes unlike their email
+	Subject  string `json:"subject"`
+	Username string `json:"username"`
+	// the email the provider gave when the identity was linked
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type UserOverview struct {
+	Username       string             `json:"username"`
+	FullName       string             `json:"full_name"`
+	Email          string             `json:"email"`
+	AccountCount   int32              `json:"account_count"`
+	LastActivityAt pgtype.Timestamptz `json:"last_activity_at"`
+	CreatedAt      time.Time          `json:"created_at"`
 }
@@ -0,0 +1,84 @@
+package db
+
+import (
+	"context"
+	"errors"
+)
+
+// Channels a notification is sent through besides the app, and the statuses of its delivery. A failed
+// delivery is no longer retried.
+const (
+	NotificationChannelEmail   = "email"
+	NotificationChannelWebhook = "webhook"
+
+	NotificationDeliveryPending   = "pending"
+	NotificationDeliveryDelivered = "delivered"
+	NotificationDeliveryFailed    = "failed"
+)
+
+// NotificationAccountLowBalance is the type of the notification telling the owner of an account that a
+// transfer left it below their low balance threshold. It is projected from the transfer.sent event, no
+// event of its own being recorded.
+const NotificationAccountLowBalance = "account.low_balance"
+
+// NotificationBudgetExceeded is the type of the notification telling the owner of an account that a
+// transfer took the spending of a category beyond its monthly budget. Like account.low_balance, it is
+// projected from the transfer.sent event.
+const NotificationBudgetExceeded = "budget.exceeded"
+
+// DefaultNotificationPreferences returns the preferences of a user who never set theirs: they are only
+// notified in the app.
+func DefaultNotificationPreferences(username string) NotificationPreference {
+	return NotificationPreference{
+		Username: username,
+		InApp:    true,
+	}
+}
+
+// NotificationPreferencesOf returns the notification preferences of the user, or the default ones when
+// they never set them.
+func NotificationPreferencesOf(ctx context.Context, q Querier, username string) (NotificationPreference, error) {
+	preferences, err := q.GetNotificationPreferences(ctx, username)
+	if errors.Is(err, ErrRecordNotFound) {
+		return DefaultNotificationPreferences(username), nil
+	}
+
+	return preferences, err
+}
+
+// LowBalanceThresholdOf returns the low balance threshold of an account: the one set on the account when
+// there is one, the one of the preferences of its owner otherwise.
+func LowBalanceThresholdOf(ctx context.Context, q Querier, accountID int64, preferences NotificationPreference) (int64, error) {
+	alert, err := q.GetAccountAlert(ctx, accountID)
+	if errors.Is(err, ErrRecordNotFound) {
+		return preferences.LowBalanceThreshold, nil
+	}
+
+	return alert.LowBalanceThreshold, err
+}
+
+// The LowBalanceNotification type is the payload of an account.low_balance notification.
+// @property {int64} Balance - the balance of the account after the transfer, below the threshold.
+// @property {int64} Threshold - the low balance threshold of the owner when the transfer was made.
+type LowBalanceNotification struct {
+	AccountID  int64  `json:"account_id"`
+	Owner      string `json:"owner"`
+	Currency   string `json:"currency"`
+	Balance    int64  `json:"balance"`
+	Threshold  int64  `json:"threshold"`
+	TransferID int64  `json:"transfer_id"`
+}
+
+// The BudgetExceededNotification type is the payload of a budget.exceeded notification.
+// @property {int64} Spent - the transfers sent to the beneficiaries of the category since the start of
+// the month, the one exceeding the budget included.
+// @property {int64} MonthlyLimit - the budget of the category when the transfer was made.
+type BudgetExceededNotification struct {
+	AccountID    int64  `json:"account_id"`
+	Owner        string `json:"owner"`
+	Currency     string `json:"currency"`
+	Category     string `json:"category"`
+	Spent        int64  `json:"spent"`
+	MonthlyLimit int64  `json:"monthly_limit"`
+	TransferID   int64  `json:"transfer_id"`
+}
@@ -0,0 +1,246 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotifications(t *testing.T) {
+	store := NewStore(testDB)
+	user1 := createRandomUser(t)
+	user2 := createRandomUser(t)
+
+	account1, err := store.CreateAccount(context.Background(), CreateAccountParams{Owner: user1.Username, Balance: 100, Currency: "USD"})
+	require.NoError(t, err)
+	account2, err := store.CreateAccount(context.Background(), CreateAccountParams{Owner: user2.Username, Balance: 0, Currency: "USD"})
+	require.NoError(t, err)
+
+	result, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	})
+	require.NoError(t, err)
+
+	var sent, received []TransferPartyEvent
+	var receivedEvent Event
+	apply := func(ctx context.Context, q *Queries, event Event) error {
+		if event.Type != EventTransferSent && event.Type != EventTransferReceived {
+			return nil
+		}
+
+		var payload TransferPartyEvent
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return err
+		}
+		if payload.TransferID != result.Transfer.ID {
+			return nil
+		}
+
+		if event.Type == EventTransferSent {
+			sent = append(sent, payload)
+		} else {
+			received = append(received, payload)
+			receivedEvent = event
+		}
+		return nil
+	}
+
+	projectTestEvents(t, store, "test_notifications_"+user1.Username, apply)
+
+	require.Equal(t, []TransferPartyEvent{{
+		TransferID:            result.Transfer.ID,
+		AccountID:             account1.ID,
+		Owner:                 user1.Username,
+		Currency:              "USD",
+		Amount:                -10,
+		Balance:               90,
+		CounterpartyAccountID: account2.ID,
+		CounterpartyOwner:     user2.Username,
+		CreatedAt:             sent[0].CreatedAt,
+	}}, sent)
+	require.Len(t, received, 1)
+	require.Equal(t, int64(10), received[0].Amount)
+	require.Equal(t, int64(10), received[0].Balance)
+	require.Equal(t, user2.Username, received[0].Owner)
+	require.Equal(t, user1.Username, received[0].CounterpartyOwner)
+	require.Equal(t, account1.ID, received[0].CounterpartyAccountID)
+
+	// a replayed event notifies the user once
+	for i := 0; i < 2; i++ {
+		err = testQueries.CreateNotification(context.Background(), CreateNotificationParams{
+			Username:  user2.Username,
+			EventID:   receivedEvent.ID,
+			Type:      receivedEvent.Type,
+			Payload:   receivedEvent.Payload,
+			CreatedAt: receivedEvent.CreatedAt,
+		})
+		require.NoError(t, err)
+	}
+
+	notifications, err := testQueries.ListNotifications(context.Background(), ListNotificationsParams{
+		Username: user2.Username,
+		Limit:    10,
+	})
+	require.NoError(t, err)
+	require.Len(t, notifications, 1)
+	require.Equal(t, EventTransferReceived, notifications[0].Type)
+}
+
+func TestNotificationPreferences(t *testing.T) {
+	user := createRandomUser(t)
+
+	preferences, err := NotificationPreferencesOf(context.Background(), testQueries, user.Username)
+	require.NoError(t, err)
+	require.Equal(t, DefaultNotificationPreferences(user.Username), preferences)
+
+	arg := UpsertNotificationPreferencesParams{
+		Username:            user.Username,
+		InApp:               false,
+		Email:               true,
+		WebhookURL:          "https://example.com/hook",
+		LowBalanceThreshold: 50,
+	}
+	preferences, err = testQueries.UpsertNotificationPreferences(context.Background(), arg)
+	require.NoError(t, err)
+	require.False(t, preferences.InApp)
+	require.True(t, preferences.Email)
+
+	// the preferences are replaced as a whole
+	arg.InApp = true
+	arg.WebhookURL = ""
+	_, err = testQueries.UpsertNotificationPreferences(context.Background(), arg)
+	require.NoError(t, err)
+
+	preferences, err = NotificationPreferencesOf(context.Background(), testQueries, user.Username)
+	require.NoError(t, err)
+	require.True(t, preferences.InApp)
+	require.True(t, preferences.Email)
+	require.Empty(t, preferences.WebhookURL)
+	require.Equal(t, int64(50), preferences.LowBalanceThreshold)
+}
+
+func TestMarkNotificationRead(t *testing.T) {
+	user := createRandomUser(t)
+	other := createRandomUser(t)
+
+	event, err := testQueries.CreateEvent(context.Background(), CreateEventParams{
+		Type:    EventSessionNewDevice,
+		Payload: json.RawMessage(`{}`),
+	})
+	require.NoError(t, err)
+
+	for _, notificationType := range []string{EventSessionNewDevice, NotificationAccountLowBalance} {
+		err = testQueries.CreateNotification(context.Background(), CreateNotificationParams{
+			Username:  user.Username,
+			EventID:   event.ID,
+			Type:      notificationType,
+			Payload:   event.Payload,
+			CreatedAt: event.CreatedAt,
+		})
+		require.NoError(t, err)
+	}
+
+	notifications, err := testQueries.ListNotifications(context.Background(), ListNotificationsParams{
+		Username: user.Username,
+		Limit:    10,
+	})
+	require.NoError(t, err)
+	require.Len(t, notifications, 2)
+	require.False(t, notifications[0].ReadAt.Valid)
+
+	// the notifications of another user can't be read
+	_, err = testQueries.MarkNotificationRead(context.Background(), MarkNotificationReadParams{
+		ID:       notifications[0].ID,
+		Username: other.Username,
+	})
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	read, err := testQueries.MarkNotificationRead(context.Background(), MarkNotificationReadParams{
+		ID:       notifications[0].ID,
+		Username: user.Username,
+	})
+	require.NoError(t, err)
+	require.True(t, read.ReadAt.Valid)
+
+	marked, err := testQueries.MarkAllNotificationsRead(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), marked)
+
+	// reading it again keeps the time it was first read
+	again, err := testQueries.MarkNotificationRead(context.Background(), MarkNotificationReadParams{
+		ID:       read.ID,
+		Username: user.Username,
+	})
+	require.NoError(t, err)
+	require.Equal(t, read.ReadAt, again.ReadAt)
+}
+
+func TestClaimNotificationDeliveries(t *testing.T) {
+	user := createRandomUser(t)
+
+	event, err := testQueries.CreateEvent(context.Background(), CreateEventParams{
+		Type:    EventSessionNewDevice,
+		Payload: json.RawMessage(`{}`),
+	})
+	require.NoError(t, err)
+
+	arg := CreateNotificationDeliveryParams{
+		Username:    user.Username,
+		EventID:     event.ID,
+		Type:        event.Type,
+		Channel:     NotificationChannelWebhook,
+		Destination: "https://example.com/hook",
+		Payload:     event.Payload,
+		CreatedAt:   event.CreatedAt,
+	}
+	// a replayed event is delivered once
+	for i := 0; i < 2; i++ {
+		err = testQueries.CreateNotificationDelivery(context.Background(), arg)
+		require.NoError(t, err)
+	}
+
+	claim := func() []NotificationDelivery {
+		deliveries, err := testQueries.ClaimNotificationDeliveries(context.Background(), ClaimNotificationDeliveriesParams{
+			LeaseUntil: time.Now().Add(time.Minute),
+			RowLimit:   1000,
+		})
+		require.NoError(t, err)
+
+		var claimed []NotificationDelivery
+		for _, delivery := range deliveries {
+			if delivery.EventID == event.ID {
+				claimed = append(claimed, delivery)
+			}
+		}
+		return claimed
+	}
+
+	claimed := claim()
+	require.Len(t, claimed, 1)
+	require.Equal(t, NotificationDeliveryPending, claimed[0].Status)
+
+	// a claimed delivery is left to its dispatcher until the lease expires
+	require.Empty(t, claim())
+
+	err = testQueries.RecordNotificationDeliveryFailure(context.Background(), RecordNotificationDeliveryFailureParams{
+		Status:        NotificationDeliveryPending,
+		LastError:     "webhook responded with status 500",
+		NextAttemptAt: time.Now().Add(-time.Second),
+		ID:            claimed[0].ID,
+	})
+	require.NoError(t, err)
+
+	retried := claim()
+	require.Len(t, retried, 1)
+	require.Equal(t, int32(1), retried[0].Attempts)
+	require.Equal(t, "webhook responded with status 500", retried[0].LastError)
+
+	err = testQueries.MarkNotificationDelivered(context.Background(), retried[0].ID)
+	require.NoError(t, err)
+	require.Empty(t, claim())
+}
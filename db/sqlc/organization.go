@@ -0,0 +1,180 @@
+package db
+
+import "context"
+
+// DefaultOrganizationID is the organization of the users and accounts from before the organizations, and
+// of the users signing up without one.
+const DefaultOrganizationID int64 = 1
+
+type organizationKey struct{}
+
+// WithOrganization returns a copy of the context scoping the queries of an OrganizationStore to the
+// organization.
+func WithOrganization(ctx context.Context, orgID int64) context.Context {
+	return context.WithValue(ctx, organizationKey{}, orgID)
+}
+
+// OrganizationFromContext returns the organization the queries run with the context are scoped to, and
+// whether they are scoped at all.
+func OrganizationFromContext(ctx context.Context) (int64, bool) {
+	orgID, ok := ctx.Value(organizationKey{}).(int64)
+	return orgID, ok
+}
+
+// The OrganizationStore type scopes the users and accounts read from the store to the organization of the
+// context, those of the other organizations being not found, so that the users of an organization can't
+// see nor reach, e.g. with a transfer, the data of another. The cards, holds, cheque deposits, mandates,
+// payment links, payment requests and standing orders read by id or code are scoped by the organization
+// of their account. The queries run with a context without an organization, e.g. those of the workers,
+// aren't scoped.
+type OrganizationStore struct {
+	Store
+}
+
+// The function creates a store scoping the users, accounts and the data of the accounts read from `store`
+// to the organization of the context of each query.
+func NewOrganizationStore(store Store) Store {
+	return &OrganizationStore{Store: store}
+}
+
+// inOrganization reports whether a user or account of the organization can be read with the context.
+func inOrganization(ctx context.Context, orgID int64) bool {
+	scope, ok := OrganizationFromContext(ctx)
+	return !ok || scope == orgID
+}
+
+func (store *OrganizationStore) GetUser(ctx context.Context, username string) (User, error) {
+	user, err := store.Store.GetUser(ctx, username)
+	if err == nil && !inOrganization(ctx, user.OrgID) {
+		return User{}, ErrRecordNotFound
+	}
+	return user, err
+}
+
+func (store *OrganizationStore) GetUserByEmail(ctx context.Context, email string) (User, error) {
+	user, err := store.Store.GetUserByEmail(ctx, email)
+	if err == nil && !inOrganization(ctx, user.OrgID) {
+		return User{}, ErrRecordNotFound
+	}
+	return user, err
+}
+
+func (store *OrganizationStore) GetAccount(ctx context.Context, id int64) (Account, error) {
+	account, err := store.Store.GetAccount(ctx, id)
+	if err == nil && !inOrganization(ctx, account.OrgID) {
+		return Account{}, ErrRecordNotFound
+	}
+	return account, err
+}
+
+func (store *OrganizationStore) GetAccountByNumber(ctx context.Context, accountNumber string) (Account, error) {
+	account, err := store.Store.GetAccountByNumber(ctx, accountNumber)
+	if err == nil && !inOrganization(ctx, account.OrgID) {
+		return Account{}, ErrRecordNotFound
+	}
+	return account, err
+}
+
+func (store *OrganizationStore) GetAccountsByIDs(ctx context.Context, arg GetAccountsByIDsParams) ([]Account, error) {
+	accounts, err := store.Store.GetAccountsByIDs(ctx, arg)
+	if err != nil {
+		return accounts, err
+	}
+
+	scoped := accounts[:0]
+	for _, account := range accounts {
+		if inOrganization(ctx, account.OrgID) {
+			scoped = append(scoped, account)
+		}
+	}
+	return scoped, nil
+}
+
+// scopeByAccount returns the row read along with `err`, or ErrRecordNotFound when the account it belongs
+// to isn't in the organization of the context.
+func scopeByAccount[T any](ctx context.Context, store Store, row T, err error, accountID int64) (T, error) {
+	if _, scoped := OrganizationFromContext(ctx); err != nil || !scoped {
+		return row, err
+	}
+
+	account, err := store.GetAccount(ctx, accountID)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	if !inOrganization(ctx, account.OrgID) {
+		var zero T
+		return zero, ErrRecordNotFound
+	}
+	return row, nil
+}
+
+func (store *OrganizationStore) GetCard(ctx context.Context, id int64) (Card, error) {
+	card, err := store.Store.GetCard(ctx, id)
+	return scopeByAccount(ctx, store.Store, card, err, card.AccountID)
+}
+
+func (store *OrganizationStore) GetCardForUpdate(ctx context.Context, id int64) (Card, error) {
+	card, err := store.Store.GetCardForUpdate(ctx, id)
+	return scopeByAccount(ctx, store.Store, card, err, card.AccountID)
+}
+
+func (store *OrganizationStore) GetHold(ctx context.Context, id int64) (Hold, error) {
+	hold, err := store.Store.GetHold(ctx, id)
+	return scopeByAccount(ctx, store.Store, hold, err, hold.AccountID)
+}
+
+func (store *OrganizationStore) GetHoldForUpdate(ctx context.Context, id int64) (Hold, error) {
+	hold, err := store.Store.GetHoldForUpdate(ctx, id)
+	return scopeByAccount(ctx, store.Store, hold, err, hold.AccountID)
+}
+
+func (store *OrganizationStore) GetChequeDeposit(ctx context.Context, id int64) (ChequeDeposit, error) {
+	deposit, err := store.Store.GetChequeDeposit(ctx, id)
+	return scopeByAccount(ctx, store.Store, deposit, err, deposit.AccountID)
+}
+
+func (store *OrganizationStore) GetChequeDepositForUpdate(ctx context.Context, id int64) (ChequeDeposit, error) {
+	deposit, err := store.Store.GetChequeDepositForUpdate(ctx, id)
+	return scopeByAccount(ctx, store.Store, deposit, err, deposit.AccountID)
+}
+
+func (store *OrganizationStore) GetMandate(ctx context.Context, id int64) (Mandate, error) {
+	mandate, err := store.Store.GetMandate(ctx, id)
+	return scopeByAccount(ctx, store.Store, mandate, err, mandate.FromAccountID)
+}
+
+func (store *OrganizationStore) GetMandateForUpdate(ctx context.Context, id int64) (Mandate, error) {
+	mandate, err := store.Store.GetMandateForUpdate(ctx, id)
+	return scopeByAccount(ctx, store.Store, mandate, err, mandate.FromAccountID)
+}
+
+func (store *OrganizationStore) GetPaymentLinkByCode(ctx context.Context, code string) (PaymentLink, error) {
+	link, err := store.Store.GetPaymentLinkByCode(ctx, code)
+	return scopeByAccount(ctx, store.Store, link, err, link.ToAccountID)
+}
+
+func (store *OrganizationStore) GetPaymentLinkForUpdate(ctx context.Context, id int64) (PaymentLink, error) {
+	link, err := store.Store.GetPaymentLinkForUpdate(ctx, id)
+	return scopeByAccount(ctx, store.Store, link, err, link.ToAccountID)
+}
+
+func (store *OrganizationStore) GetPaymentRequest(ctx context.Context, id int64) (PaymentRequest, error) {
+	request, err := store.Store.GetPaymentRequest(ctx, id)
+	return scopeByAccount(ctx, store.Store, request, err, request.ToAccountID)
+}
+
+func (store *OrganizationStore) GetPaymentRequestForUpdate(ctx context.Context, id int64) (PaymentRequest, error) {
+	request, err := store.Store.GetPaymentRequestForUpdate(ctx, id)
+	return scopeByAccount(ctx, store.Store, request, err, request.ToAccountID)
+}
+
+func (store *OrganizationStore) GetStandingOrder(ctx context.Context, id int64) (StandingOrder, error) {
+	order, err := store.Store.GetStandingOrder(ctx, id)
+	return scopeByAccount(ctx, store.Store, order, err, order.FromAccountID)
+}
+
+func (store *OrganizationStore) GetStandingOrderForUpdate(ctx context.Context, id int64) (StandingOrder, error) {
+	order, err := store.Store.GetStandingOrderForUpdate(ctx, id)
+	return scopeByAccount(ctx, store.Store, order, err, order.FromAccountID)
+}
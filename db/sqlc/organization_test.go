@@ -0,0 +1,124 @@
+package db
+
+import (
+	"context"
+	"go-backend/util"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomOrganization(t *testing.T) Organization {
+	arg := CreateOrganizationParams{
+		Slug: util.RandomString(12),
+		Name: util.RandomOwner(),
+	}
+
+	organization, err := testQueries.CreateOrganization(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotZero(t, organization.ID)
+	require.Equal(t, arg.Slug, organization.Slug)
+	require.Equal(t, arg.Name, organization.Name)
+	require.NotZero(t, organization.CreatedAt)
+
+	return organization
+}
+
+func TestGetOrganization(t *testing.T) {
+	organization1 := createRandomOrganization(t)
+
+	organization2, err := testQueries.GetOrganization(context.Background(), organization1.ID)
+	require.NoError(t, err)
+	require.Equal(t, organization1.Slug, organization2.Slug)
+
+	organization2, err = testQueries.GetOrganizationBySlug(context.Background(), organization1.Slug)
+	require.NoError(t, err)
+	require.Equal(t, organization1.ID, organization2.ID)
+
+	// the users and accounts from before the organizations belong to the default one
+	organization2, err = testQueries.GetOrganization(context.Background(), DefaultOrganizationID)
+	require.NoError(t, err)
+	require.Equal(t, "default", organization2.Slug)
+}
+
+func TestListOrganizationUsers(t *testing.T) {
+	organization := createRandomOrganization(t)
+
+	user, err := testQueries.CreateUser(context.Background(), CreateUserParams{
+		Username:       util.RandomOwner(),
+		HashedPassword: util.RandomString(16),
+		FullName:       util.RandomOwner(),
+		Email:          util.RandomEmail(),
+		OrgID:          organization.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, organization.ID, user.OrgID)
+
+	// the accounts are created in the organization of their owner
+	account, err := testQueries.CreateAccount(context.Background(), CreateAccountParams{
+		Owner:    user.Username,
+		Currency: util.USD,
+	})
+	require.NoError(t, err)
+	require.Equal(t, organization.ID, account.OrgID)
+
+	users, err := testQueries.ListOrganizationUsers(context.Background(), ListOrganizationUsersParams{
+		OrgID:  organization.ID,
+		Limit:  5,
+		Offset: 0,
+	})
+	require.NoError(t, err)
+	require.Len(t, users, 1)
+	require.Equal(t, user.Username, users[0].Username)
+}
+
+func TestOrganizationStore(t *testing.T) {
+	store := NewOrganizationStore(NewStore(testDB))
+	account := createRandomAccount(t)
+	organization := createRandomOrganization(t)
+
+	// unscoped, e.g. the workers
+	got, err := store.GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, account.ID, got.ID)
+
+	ctx := WithOrganization(context.Background(), DefaultOrganizationID)
+	got, err = store.GetAccount(ctx, account.ID)
+	require.NoError(t, err)
+	require.Equal(t, account.ID, got.ID)
+
+	// the users and accounts of another organization aren't found
+	ctx = WithOrganization(context.Background(), organization.ID)
+	_, err = store.GetAccount(ctx, account.ID)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+	_, err = store.GetAccountByNumber(ctx, account.AccountNumber)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+	_, err = store.GetUser(ctx, account.Owner)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	accounts, err := store.GetAccountsByIDs(ctx, GetAccountsByIDsParams{Owner: account.Owner, Ids: []int64{account.ID}})
+	require.NoError(t, err)
+	require.Empty(t, accounts)
+}
+
+func TestOrganizationStoreAccountData(t *testing.T) {
+	store := NewOrganizationStore(NewStore(testDB))
+	account := createRandomAccount(t)
+	organization := createRandomOrganization(t)
+	card := createRandomCard(t, account)
+	link := createRandomPaymentLink(t, account, time.Now().Add(time.Hour))
+
+	// the data of the accounts of the organization are found
+	ctx := WithOrganization(context.Background(), DefaultOrganizationID)
+	got, err := store.GetCard(ctx, card.ID)
+	require.NoError(t, err)
+	require.Equal(t, card.ID, got.ID)
+
+	// those of the accounts of another organization aren't, whether by id or by code
+	ctx = WithOrganization(context.Background(), organization.ID)
+	_, err = store.GetCard(ctx, card.ID)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+	_, err = store.GetPaymentLinkByCode(ctx, link.Code)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
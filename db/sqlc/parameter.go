@@ -0,0 +1,25 @@
+package db
+
+import "strings"
+
+// Parameters of the bank published by the admins. Every publication is a new version which applies from
+// its effective date, so that a new fee schedule or limit can be announced in advance and the history
+// of the values remains queryable.
+const (
+	// ParameterTransferLimit is the largest amount of a single transfer.
+	ParameterTransferLimit = "transfer_limit"
+	// ParameterTransferFee is the flat fee charged for a transfer.
+	ParameterTransferFee = "transfer_fee"
+	// ParameterInterestRate is the yearly interest rate paid on balances, in basis points.
+	ParameterInterestRate = "interest_rate_bps"
+	// ParameterMonthlyFee is the flat maintenance fee charged to every account at the end of a month.
+	ParameterMonthlyFee = "monthly_fee"
+	// ParameterFXRatePrefix prefixes the exchange rates between two currencies, e.g. fx_rate_usd_cad,
+	// in millionths of the second currency per unit of the first.
+	ParameterFXRatePrefix = "fx_rate_"
+)
+
+// FXRateParameter returns the name of the parameter of the exchange rate from a currency to another.
+func FXRateParameter(from string, to string) string {
+	return ParameterFXRatePrefix + strings.ToLower(from) + "_" + strings.ToLower(to)
+}
@@ -0,0 +1,51 @@
+package db
+
+import (
+	"context"
+	"go-backend/util"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBankParameterVersions(t *testing.T) {
+	admin := createRandomUser(t)
+	name := "test_" + util.RandomString(8)
+	now := time.Now()
+
+	var versions []BankParameter
+	for i, effectiveAt := range []time.Time{now.Add(-time.Hour), now.Add(-time.Minute), now.Add(time.Hour)} {
+		parameter, err := testQueries.CreateBankParameter(context.Background(), CreateBankParameterParams{
+			Name:        name,
+			Value:       int64(100 * (i + 1)),
+			EffectiveAt: effectiveAt,
+			PublishedBy: admin.Username,
+		})
+		require.NoError(t, err)
+		require.Equal(t, int32(i+1), parameter.Version)
+		versions = append(versions, parameter)
+	}
+
+	// the version in effect is the latest that already took effect
+	active, err := testQueries.GetActiveBankParameter(context.Background(), GetActiveBankParameterParams{Name: name, At: now})
+	require.NoError(t, err)
+	require.Equal(t, versions[1].Version, active.Version)
+
+	active, err = testQueries.GetActiveBankParameter(context.Background(), GetActiveBankParameterParams{Name: name, At: now.Add(2 * time.Hour)})
+	require.NoError(t, err)
+	require.Equal(t, versions[2].Version, active.Version)
+
+	_, err = testQueries.GetActiveBankParameter(context.Background(), GetActiveBankParameterParams{Name: name, At: now.Add(-2 * time.Hour)})
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	history, err := testQueries.ListBankParameterVersions(context.Background(), ListBankParameterVersionsParams{
+		Name:     pgtype.Text{String: name, Valid: true},
+		RowLimit: 10,
+	})
+	require.NoError(t, err)
+	require.Len(t, history, 3)
+	require.Equal(t, int32(3), history[0].Version)
+	require.Equal(t, int32(1), history[2].Version)
+}
@@ -0,0 +1,85 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Statuses of a payment link. Only active links can be paid or cancelled, until they expire. The other
+// statuses are final.
+const (
+	PaymentLinkActive    = "active"
+	PaymentLinkPaid      = "paid"
+	PaymentLinkCancelled = "cancelled"
+	PaymentLinkExpired   = "expired"
+)
+
+var (
+	// ErrPaymentLinkClosed is returned when paying or cancelling a link that is no longer active.
+	ErrPaymentLinkClosed = errors.New("payment link is no longer active")
+	// ErrPaymentLinkExpired is returned when paying an active link past its expiry.
+	ErrPaymentLinkExpired = errors.New("payment link has expired")
+)
+
+// The PayPaymentLinkTxParams type contains the payment of a link.
+// @property {int64} ID - the link paid.
+// @property {string} Payer - the user paying the link.
+// @property {int64} FromAccountID - the account of the payer the money is taken from.
+// @property {string} Currency - the currency the accounts were checked to hold, checked again within
+// the transaction of the transfer.
+type PayPaymentLinkTxParams struct {
+	ID            int64
+	Payer         string
+	FromAccountID int64
+	Currency      string
+}
+
+// The PayPaymentLinkTxResult type is the paid link, along with the transfer paying it.
+type PayPaymentLinkTxResult struct {
+	PaymentLink PaymentLink      `json:"payment_link"`
+	Transfer    TransferTxResult `json:"transfer"`
+}
+
+// PayPaymentLinkTx makes the transfer paying the link to the account of its merchant, and marks it paid.
+// The link is locked so that it is paid once, ErrPaymentLinkClosed being returned when it is no longer
+// active and ErrPaymentLinkExpired once it expired.
+func (store *SQLStore) PayPaymentLinkTx(ctx context.Context, arg PayPaymentLinkTxParams) (PayPaymentLinkTxResult, error) {
+	var result PayPaymentLinkTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		link, err := q.GetPaymentLinkForUpdate(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+		if link.Status != PaymentLinkActive {
+			return ErrPaymentLinkClosed
+		}
+		if !link.ExpiresAt.After(time.Now()) {
+			return ErrPaymentLinkExpired
+		}
+
+		result.Transfer, err = transfer(ctx, q, TransferTxParams{
+			FromAccountID: arg.FromAccountID,
+			ToAccountID:   link.ToAccountID,
+			Amount:        link.Amount,
+			Memo:          link.Memo,
+			Currency:      arg.Currency,
+		}, nil)
+		if err != nil {
+			return err
+		}
+
+		result.PaymentLink, err = q.PayPaymentLink(ctx, PayPaymentLinkParams{
+			Payer:         pgtype.Text{String: arg.Payer, Valid: true},
+			FromAccountID: pgtype.Int8{Int64: arg.FromAccountID, Valid: true},
+			TransferID:    pgtype.Int8{Int64: result.Transfer.Transfer.ID, Valid: true},
+			ID:            arg.ID,
+		})
+		return err
+	})
+
+	return result, err
+}
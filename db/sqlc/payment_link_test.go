@@ -0,0 +1,126 @@
+package db
+
+import (
+	"context"
+	"go-backend/util"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomPaymentLink(t *testing.T, toAccount Account, expiresAt time.Time) PaymentLink {
+	arg := CreatePaymentLinkParams{
+		Code:        util.RandomString(22),
+		Merchant:    toAccount.Owner,
+		ToAccountID: toAccount.ID,
+		Amount:      10,
+		Currency:    toAccount.Currency,
+		Memo:        "order 42",
+		ExpiresAt:   expiresAt,
+	}
+
+	link, err := testQueries.CreatePaymentLink(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotZero(t, link.ID)
+	require.Equal(t, arg.Code, link.Code)
+	require.Equal(t, arg.Merchant, link.Merchant)
+	require.Equal(t, arg.Amount, link.Amount)
+	require.Equal(t, PaymentLinkActive, link.Status)
+	require.WithinDuration(t, expiresAt, link.ExpiresAt, time.Second)
+	require.False(t, link.Payer.Valid)
+
+	return link
+}
+
+func TestGetPaymentLinkByCode(t *testing.T) {
+	link := createRandomPaymentLink(t, createRandomAccount(t), time.Now().Add(time.Hour))
+
+	got, err := testQueries.GetPaymentLinkByCode(context.Background(), link.Code)
+	require.NoError(t, err)
+	require.Equal(t, link.ID, got.ID)
+
+	_, err = testQueries.GetPaymentLinkByCode(context.Background(), util.RandomString(22))
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
+
+func TestPayPaymentLinkTx(t *testing.T) {
+	store := NewStore(testDB)
+	toAccount := createRandomAccount(t)
+	fromAccount := createRandomAccount(t)
+
+	link := createRandomPaymentLink(t, toAccount, time.Now().Add(time.Hour))
+
+	result, err := store.PayPaymentLinkTx(context.Background(), PayPaymentLinkTxParams{
+		ID:            link.ID,
+		Payer:         fromAccount.Owner,
+		FromAccountID: fromAccount.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, PaymentLinkPaid, result.PaymentLink.Status)
+	require.Equal(t, fromAccount.Owner, result.PaymentLink.Payer.String)
+	require.Equal(t, result.Transfer.Transfer.ID, result.PaymentLink.TransferID.Int64)
+	require.True(t, result.PaymentLink.ClosedAt.Valid)
+	require.Equal(t, "order 42", result.Transfer.Transfer.Memo)
+	require.Equal(t, fromAccount.Balance-10, result.Transfer.FromAccount.Balance)
+	require.Equal(t, toAccount.Balance+10, result.Transfer.ToAccount.Balance)
+
+	// a link is paid once
+	_, err = store.PayPaymentLinkTx(context.Background(), PayPaymentLinkTxParams{
+		ID:            link.ID,
+		Payer:         fromAccount.Owner,
+		FromAccountID: fromAccount.ID,
+	})
+	require.ErrorIs(t, err, ErrPaymentLinkClosed)
+
+	_, err = testQueries.CancelPaymentLink(context.Background(), link.ID)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
+
+func TestCancelPaymentLink(t *testing.T) {
+	store := NewStore(testDB)
+	fromAccount := createRandomAccount(t)
+
+	link := createRandomPaymentLink(t, createRandomAccount(t), time.Now().Add(time.Hour))
+
+	cancelled, err := testQueries.CancelPaymentLink(context.Background(), link.ID)
+	require.NoError(t, err)
+	require.Equal(t, PaymentLinkCancelled, cancelled.Status)
+	require.True(t, cancelled.ClosedAt.Valid)
+
+	_, err = store.PayPaymentLinkTx(context.Background(), PayPaymentLinkTxParams{
+		ID:            link.ID,
+		Payer:         fromAccount.Owner,
+		FromAccountID: fromAccount.ID,
+	})
+	require.ErrorIs(t, err, ErrPaymentLinkClosed)
+}
+
+func TestExpirePaymentLinks(t *testing.T) {
+	store := NewStore(testDB)
+	toAccount := createRandomAccount(t)
+	fromAccount := createRandomAccount(t)
+
+	expired := createRandomPaymentLink(t, toAccount, time.Now().Add(-time.Minute))
+	active := createRandomPaymentLink(t, toAccount, time.Now().Add(time.Hour))
+
+	// a link past its expiry can't be paid even before being expired
+	_, err := store.PayPaymentLinkTx(context.Background(), PayPaymentLinkTxParams{
+		ID:            expired.ID,
+		Payer:         fromAccount.Owner,
+		FromAccountID: fromAccount.ID,
+	})
+	require.ErrorIs(t, err, ErrPaymentLinkExpired)
+
+	count, err := testQueries.ExpirePaymentLinks(context.Background(), time.Now())
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, count, int64(1))
+
+	got, err := testQueries.GetPaymentLinkByCode(context.Background(), expired.Code)
+	require.NoError(t, err)
+	require.Equal(t, PaymentLinkExpired, got.Status)
+
+	got, err = testQueries.GetPaymentLinkByCode(context.Background(), active.Code)
+	require.NoError(t, err)
+	require.Equal(t, PaymentLinkActive, got.Status)
+}
@@ -0,0 +1,120 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Statuses of a payment request. Only pending requests can be accepted or declined, until they expire.
+const (
+	PaymentRequestPending  = "pending"
+	PaymentRequestAccepted = "accepted"
+	PaymentRequestDeclined = "declined"
+	PaymentRequestExpired  = "expired"
+)
+
+var (
+	// ErrPaymentRequestClosed is returned when accepting a request that is no longer pending.
+	ErrPaymentRequestClosed = errors.New("payment request is no longer pending")
+	// ErrPaymentRequestExpired is returned when accepting a pending request past its expiry.
+	ErrPaymentRequestExpired = errors.New("payment request has expired")
+)
+
+// The PaymentRequestEvent type describes a payment request for the payment_request.created event, which
+// notifies the payer.
+type PaymentRequestEvent struct {
+	PaymentRequestID int64     `json:"payment_request_id"`
+	Requester        string    `json:"requester"`
+	Payer            string    `json:"payer"`
+	ToAccountID      int64     `json:"to_account_id"`
+	Amount           int64     `json:"amount"`
+	Memo             string    `json:"memo"`
+	ExpiresAt        time.Time `json:"expires_at"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// CreatePaymentRequest creates the payment request and records a payment_request.created event.
+func (store *SQLStore) CreatePaymentRequest(ctx context.Context, arg CreatePaymentRequestParams) (PaymentRequest, error) {
+	var request PaymentRequest
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		request, err = q.CreatePaymentRequest(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		return recordEvent(ctx, q, EventPaymentRequestCreated, PaymentRequestEvent{
+			PaymentRequestID: request.ID,
+			Requester:        request.Requester,
+			Payer:            request.Payer,
+			ToAccountID:      request.ToAccountID,
+			Amount:           request.Amount,
+			Memo:             request.Memo,
+			ExpiresAt:        request.ExpiresAt,
+			CreatedAt:        request.CreatedAt,
+		})
+	})
+
+	return request, err
+}
+
+// The AcceptPaymentRequestTxParams type contains the acceptance of a payment request by its payer.
+// @property {int64} ID - the request accepted.
+// @property {int64} FromAccountID - the account of the payer the money is taken from.
+// @property {string} Currency - the currency the accounts were checked to hold, checked again within
+// the transaction of the transfer.
+type AcceptPaymentRequestTxParams struct {
+	ID            int64
+	FromAccountID int64
+	Currency      string
+}
+
+// The AcceptPaymentRequestTxResult type is the accepted request, along with the transfer paying it.
+type AcceptPaymentRequestTxResult struct {
+	PaymentRequest PaymentRequest   `json:"payment_request"`
+	Transfer       TransferTxResult `json:"transfer"`
+}
+
+// AcceptPaymentRequestTx makes the transfer paying the request to the account of the requester, and
+// marks it accepted. The request is locked so that it is paid once, ErrPaymentRequestClosed being
+// returned when it is no longer pending and ErrPaymentRequestExpired once it expired.
+func (store *SQLStore) AcceptPaymentRequestTx(ctx context.Context, arg AcceptPaymentRequestTxParams) (AcceptPaymentRequestTxResult, error) {
+	var result AcceptPaymentRequestTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		request, err := q.GetPaymentRequestForUpdate(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+		if request.Status != PaymentRequestPending {
+			return ErrPaymentRequestClosed
+		}
+		if !request.ExpiresAt.After(time.Now()) {
+			return ErrPaymentRequestExpired
+		}
+
+		result.Transfer, err = transfer(ctx, q, TransferTxParams{
+			FromAccountID: arg.FromAccountID,
+			ToAccountID:   request.ToAccountID,
+			Amount:        request.Amount,
+			Memo:          request.Memo,
+			Currency:      arg.Currency,
+		}, nil)
+		if err != nil {
+			return err
+		}
+
+		result.PaymentRequest, err = q.AcceptPaymentRequest(ctx, AcceptPaymentRequestParams{
+			FromAccountID: pgtype.Int8{Int64: arg.FromAccountID, Valid: true},
+			TransferID:    pgtype.Int8{Int64: result.Transfer.Transfer.ID, Valid: true},
+			ID:            arg.ID,
+		})
+		return err
+	})
+
+	return result, err
+}
@@ -0,0 +1,130 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomPaymentRequest(t *testing.T, store Store, toAccount Account, payer Account, expiresAt time.Time) PaymentRequest {
+	arg := CreatePaymentRequestParams{
+		Requester:   toAccount.Owner,
+		Payer:       payer.Owner,
+		ToAccountID: toAccount.ID,
+		Amount:      10,
+		Memo:        "dinner",
+		ExpiresAt:   expiresAt,
+	}
+
+	request, err := store.CreatePaymentRequest(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotZero(t, request.ID)
+	require.Equal(t, arg.Requester, request.Requester)
+	require.Equal(t, arg.Payer, request.Payer)
+	require.Equal(t, arg.ToAccountID, request.ToAccountID)
+	require.Equal(t, arg.Amount, request.Amount)
+	require.Equal(t, PaymentRequestPending, request.Status)
+	require.WithinDuration(t, expiresAt, request.ExpiresAt, time.Second)
+	require.False(t, request.TransferID.Valid)
+
+	return request
+}
+
+func TestCreatePaymentRequest(t *testing.T) {
+	store := NewStore(testDB)
+	toAccount := createRandomAccount(t)
+	payer := createRandomAccount(t)
+
+	request := createRandomPaymentRequest(t, store, toAccount, payer, time.Now().Add(time.Hour))
+
+	received, err := testQueries.ListPaymentRequests(context.Background(), ListPaymentRequestsParams{
+		Payer:     pgtype.Text{String: payer.Owner, Valid: true},
+		Status:    pgtype.Text{String: PaymentRequestPending, Valid: true},
+		RowLimit:  5,
+		RowOffset: 0,
+	})
+	require.NoError(t, err)
+	require.Len(t, received, 1)
+	require.Equal(t, request.ID, received[0].ID)
+}
+
+func TestAcceptPaymentRequestTx(t *testing.T) {
+	store := NewStore(testDB)
+	toAccount := createRandomAccount(t)
+	fromAccount := createRandomAccount(t)
+
+	request := createRandomPaymentRequest(t, store, toAccount, fromAccount, time.Now().Add(time.Hour))
+
+	result, err := store.AcceptPaymentRequestTx(context.Background(), AcceptPaymentRequestTxParams{
+		ID:            request.ID,
+		FromAccountID: fromAccount.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, PaymentRequestAccepted, result.PaymentRequest.Status)
+	require.Equal(t, fromAccount.ID, result.PaymentRequest.FromAccountID.Int64)
+	require.Equal(t, result.Transfer.Transfer.ID, result.PaymentRequest.TransferID.Int64)
+	require.True(t, result.PaymentRequest.DecidedAt.Valid)
+	require.Equal(t, "dinner", result.Transfer.Transfer.Memo)
+	require.Equal(t, fromAccount.Balance-10, result.Transfer.FromAccount.Balance)
+	require.Equal(t, toAccount.Balance+10, result.Transfer.ToAccount.Balance)
+
+	// a request is paid once
+	_, err = store.AcceptPaymentRequestTx(context.Background(), AcceptPaymentRequestTxParams{
+		ID:            request.ID,
+		FromAccountID: fromAccount.ID,
+	})
+	require.ErrorIs(t, err, ErrPaymentRequestClosed)
+
+	_, err = testQueries.DeclinePaymentRequest(context.Background(), request.ID)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
+
+func TestDeclinePaymentRequest(t *testing.T) {
+	store := NewStore(testDB)
+	toAccount := createRandomAccount(t)
+	fromAccount := createRandomAccount(t)
+
+	request := createRandomPaymentRequest(t, store, toAccount, fromAccount, time.Now().Add(time.Hour))
+
+	declined, err := testQueries.DeclinePaymentRequest(context.Background(), request.ID)
+	require.NoError(t, err)
+	require.Equal(t, PaymentRequestDeclined, declined.Status)
+	require.True(t, declined.DecidedAt.Valid)
+
+	_, err = store.AcceptPaymentRequestTx(context.Background(), AcceptPaymentRequestTxParams{
+		ID:            request.ID,
+		FromAccountID: fromAccount.ID,
+	})
+	require.ErrorIs(t, err, ErrPaymentRequestClosed)
+}
+
+func TestExpirePaymentRequests(t *testing.T) {
+	store := NewStore(testDB)
+	toAccount := createRandomAccount(t)
+	fromAccount := createRandomAccount(t)
+
+	expired := createRandomPaymentRequest(t, store, toAccount, fromAccount, time.Now().Add(-time.Minute))
+	pending := createRandomPaymentRequest(t, store, toAccount, fromAccount, time.Now().Add(time.Hour))
+
+	// a request past its expiry can't be accepted even before being expired
+	_, err := store.AcceptPaymentRequestTx(context.Background(), AcceptPaymentRequestTxParams{
+		ID:            expired.ID,
+		FromAccountID: fromAccount.ID,
+	})
+	require.ErrorIs(t, err, ErrPaymentRequestExpired)
+
+	count, err := testQueries.ExpirePaymentRequests(context.Background(), time.Now())
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, count, int64(1))
+
+	got, err := testQueries.GetPaymentRequest(context.Background(), expired.ID)
+	require.NoError(t, err)
+	require.Equal(t, PaymentRequestExpired, got.Status)
+
+	got, err = testQueries.GetPaymentRequest(context.Background(), pending.ID)
+	require.NoError(t, err)
+	require.Equal(t, PaymentRequestPending, got.Status)
+}
@@ -0,0 +1,120 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Statuses of a pending transfer. A transfer awaiting approval is either approved, which makes it, or
+// rejected by its owner or a banker, or expires. The other statuses are final.
+const (
+	PendingTransferAwaitingApproval = "pending_approval"
+	PendingTransferApproved         = "approved"
+	PendingTransferRejected         = "rejected"
+	PendingTransferExpired          = "expired"
+)
+
+var (
+	// ErrPendingTransferClosed is returned when approving a transfer that no longer awaits approval.
+	ErrPendingTransferClosed = errors.New("pending transfer no longer awaits approval")
+	// ErrPendingTransferExpired is returned when approving a transfer past its expiry.
+	ErrPendingTransferExpired = errors.New("pending transfer has expired")
+)
+
+// The CreatePendingTransferTxParams type contains a transfer to record awaiting approval.
+// @property {CreatePendingTransferParams} PendingTransfer - the transfer awaiting approval.
+// @property {CreateQueuedTransferParams} Queued - the queued transfer this transfer processes, whose
+// outcome is recorded in the same transaction, when the transfer was queued.
+type CreatePendingTransferTxParams struct {
+	PendingTransfer CreatePendingTransferParams
+	Queued          *CreateQueuedTransferParams
+}
+
+// CreatePendingTransferTx records a transfer awaiting approval. The outcome of the queued transfer it
+// processes is recorded in the same transaction, so that a queued transfer processed again awaits
+// approval once, ErrQueuedTransferProcessed being returned the second time.
+func (store *SQLStore) CreatePendingTransferTx(ctx context.Context, arg CreatePendingTransferTxParams) (PendingTransfer, error) {
+	var pending PendingTransfer
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		pending, err = q.CreatePendingTransfer(ctx, arg.PendingTransfer)
+		if err != nil || arg.Queued == nil {
+			return err
+		}
+
+		queued := *arg.Queued
+		queued.Status = QueuedTransferPendingApproval
+		queued.PendingTransferID = pgtype.Int8{Int64: pending.ID, Valid: true}
+		_, err = recordQueuedTransfer(ctx, q, queued)
+		return err
+	})
+
+	return pending, err
+}
+
+// The ApprovePendingTransferTxParams type contains the approval of a pending transfer.
+// @property {int64} ID - the pending transfer approved.
+// @property {string} DecidedBy - the owner or the banker approving the transfer.
+// @property {string} Currency - the currency the accounts were checked to hold, checked again within
+// the transaction of the transfer.
+// @property {time.Time} Now - the time of the approval, by which the transfer must not have expired
+// and its fee is charged.
+type ApprovePendingTransferTxParams struct {
+	ID        int64
+	DecidedBy string
+	Currency  string
+	Now       time.Time
+}
+
+// The ApprovePendingTransferTxResult type is the approved pending transfer, along with the transfer made.
+type ApprovePendingTransferTxResult struct {
+	PendingTransfer PendingTransfer  `json:"pending_transfer"`
+	Transfer        TransferTxResult `json:"transfer"`
+}
+
+// ApprovePendingTransferTx makes the transfer awaiting approval and marks it approved. The pending
+// transfer is locked so that it is made once, ErrPendingTransferClosed being returned when it no longer
+// awaits approval and ErrPendingTransferExpired once it expired.
+func (store *SQLStore) ApprovePendingTransferTx(ctx context.Context, arg ApprovePendingTransferTxParams) (ApprovePendingTransferTxResult, error) {
+	var result ApprovePendingTransferTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		pending, err := q.GetPendingTransferForUpdate(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+		if pending.Status != PendingTransferAwaitingApproval {
+			return ErrPendingTransferClosed
+		}
+		if !pending.ExpiresAt.After(arg.Now) {
+			return ErrPendingTransferExpired
+		}
+
+		result.Transfer, err = transfer(ctx, q, TransferTxParams{
+			FromAccountID:     pending.FromAccountID,
+			ToAccountID:       pending.ToAccountID,
+			Amount:            pending.Amount,
+			Memo:              pending.Memo,
+			ExternalReference: pending.ExternalReference,
+			Currency:          arg.Currency,
+			ChargeFee:         true,
+			Now:               arg.Now,
+		}, nil)
+		if err != nil {
+			return err
+		}
+
+		result.PendingTransfer, err = q.ApprovePendingTransfer(ctx, ApprovePendingTransferParams{
+			DecidedBy:  arg.DecidedBy,
+			TransferID: pgtype.Int8{Int64: result.Transfer.Transfer.ID, Valid: true},
+			ID:         arg.ID,
+		})
+		return err
+	})
+
+	return result, err
+}
@@ -0,0 +1,140 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomPendingTransfer(t *testing.T, fromAccount Account, toAccount Account, expiresAt time.Time) PendingTransfer {
+	arg := CreatePendingTransferParams{
+		Owner:         fromAccount.Owner,
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   toAccount.ID,
+		Amount:        10,
+		Memo:          "rent",
+		ExpiresAt:     expiresAt,
+	}
+
+	pending, err := testQueries.CreatePendingTransfer(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotZero(t, pending.ID)
+	require.Equal(t, arg.Owner, pending.Owner)
+	require.Equal(t, arg.FromAccountID, pending.FromAccountID)
+	require.Equal(t, arg.ToAccountID, pending.ToAccountID)
+	require.Equal(t, arg.Amount, pending.Amount)
+	require.Equal(t, PendingTransferAwaitingApproval, pending.Status)
+	require.WithinDuration(t, expiresAt, pending.ExpiresAt, time.Second)
+	require.False(t, pending.TransferID.Valid)
+
+	return pending
+}
+
+func TestCreatePendingTransfer(t *testing.T) {
+	fromAccount := createRandomAccount(t)
+	toAccount := createRandomAccount(t)
+
+	pending := createRandomPendingTransfer(t, fromAccount, toAccount, time.Now().Add(time.Hour))
+
+	listed, err := testQueries.ListPendingTransfers(context.Background(), ListPendingTransfersParams{
+		Owner:     pgtype.Text{String: fromAccount.Owner, Valid: true},
+		Status:    pgtype.Text{String: PendingTransferAwaitingApproval, Valid: true},
+		RowLimit:  5,
+		RowOffset: 0,
+	})
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	require.Equal(t, pending.ID, listed[0].ID)
+}
+
+func TestApprovePendingTransferTx(t *testing.T) {
+	store := NewStore(testDB)
+	fromAccount := createRandomAccount(t)
+	toAccount := createRandomAccount(t)
+
+	pending := createRandomPendingTransfer(t, fromAccount, toAccount, time.Now().Add(time.Hour))
+
+	result, err := store.ApprovePendingTransferTx(context.Background(), ApprovePendingTransferTxParams{
+		ID:        pending.ID,
+		DecidedBy: fromAccount.Owner,
+		Now:       time.Now(),
+	})
+	require.NoError(t, err)
+	require.Equal(t, PendingTransferApproved, result.PendingTransfer.Status)
+	require.Equal(t, fromAccount.Owner, result.PendingTransfer.DecidedBy.String)
+	require.Equal(t, result.Transfer.Transfer.ID, result.PendingTransfer.TransferID.Int64)
+	require.True(t, result.PendingTransfer.DecidedAt.Valid)
+	require.Equal(t, "rent", result.Transfer.Transfer.Memo)
+	require.Equal(t, fromAccount.Balance-10, result.Transfer.FromAccount.Balance)
+	require.Equal(t, toAccount.Balance+10, result.Transfer.ToAccount.Balance)
+
+	// a transfer is made once
+	_, err = store.ApprovePendingTransferTx(context.Background(), ApprovePendingTransferTxParams{
+		ID:        pending.ID,
+		DecidedBy: fromAccount.Owner,
+		Now:       time.Now(),
+	})
+	require.ErrorIs(t, err, ErrPendingTransferClosed)
+
+	_, err = testQueries.RejectPendingTransfer(context.Background(), RejectPendingTransferParams{
+		DecidedBy: fromAccount.Owner,
+		ID:        pending.ID,
+	})
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
+
+func TestRejectPendingTransfer(t *testing.T) {
+	store := NewStore(testDB)
+	fromAccount := createRandomAccount(t)
+	toAccount := createRandomAccount(t)
+
+	pending := createRandomPendingTransfer(t, fromAccount, toAccount, time.Now().Add(time.Hour))
+
+	rejected, err := testQueries.RejectPendingTransfer(context.Background(), RejectPendingTransferParams{
+		DecidedBy: "banker",
+		ID:        pending.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, PendingTransferRejected, rejected.Status)
+	require.Equal(t, "banker", rejected.DecidedBy.String)
+	require.True(t, rejected.DecidedAt.Valid)
+
+	_, err = store.ApprovePendingTransferTx(context.Background(), ApprovePendingTransferTxParams{
+		ID:        pending.ID,
+		DecidedBy: fromAccount.Owner,
+		Now:       time.Now(),
+	})
+	require.ErrorIs(t, err, ErrPendingTransferClosed)
+}
+
+func TestExpirePendingTransfers(t *testing.T) {
+	store := NewStore(testDB)
+	fromAccount := createRandomAccount(t)
+	toAccount := createRandomAccount(t)
+
+	expired := createRandomPendingTransfer(t, fromAccount, toAccount, time.Now().Add(-time.Minute))
+	pending := createRandomPendingTransfer(t, fromAccount, toAccount, time.Now().Add(time.Hour))
+
+	// a transfer past its expiry can't be approved even before being expired
+	_, err := store.ApprovePendingTransferTx(context.Background(), ApprovePendingTransferTxParams{
+		ID:        expired.ID,
+		DecidedBy: fromAccount.Owner,
+		Now:       time.Now(),
+	})
+	require.ErrorIs(t, err, ErrPendingTransferExpired)
+
+	count, err := testQueries.ExpirePendingTransfers(context.Background(), time.Now())
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, count, int64(1))
+
+	got, err := testQueries.GetPendingTransfer(context.Background(), expired.ID)
+	require.NoError(t, err)
+	require.Equal(t, PendingTransferExpired, got.Status)
+
+	got, err = testQueries.GetPendingTransfer(context.Background(), pending.ID)
+	require.NoError(t, err)
+	require.Equal(t, PendingTransferAwaitingApproval, got.Status)
+}
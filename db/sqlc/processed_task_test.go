@@ -0,0 +1,32 @@
+package db
+
+import (
+	"context"
+	"go-backend/util"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessedTask(t *testing.T) {
+	id := "task:test:" + util.RandomString(12)
+
+	processed, err := testQueries.IsTaskProcessed(context.Background(), id)
+	require.NoError(t, err)
+	require.False(t, processed)
+
+	arg := CreateProcessedTaskParams{
+		ID:   id,
+		Type: "task:test",
+	}
+	err = testQueries.CreateProcessedTask(context.Background(), arg)
+	require.NoError(t, err)
+
+	processed, err = testQueries.IsTaskProcessed(context.Background(), id)
+	require.NoError(t, err)
+	require.True(t, processed)
+
+	// recording the task again is a no-op
+	err = testQueries.CreateProcessedTask(context.Background(), arg)
+	require.NoError(t, err)
+}
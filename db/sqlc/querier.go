@@ -6,28 +6,415 @@ package db
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type Querier interface {
+	// Accepts the pending invitation of the user to the account.
+	AcceptAccountMember(ctx context.Context, arg AcceptAccountMemberParams) (AccountMember, error)
+	AcceptPaymentRequest(ctx context.Context, arg AcceptPaymentRequestParams) (PaymentRequest, error)
+	// Reports whether the account has entries or transfers, which prevent it from being deleted.
+	AccountHasHistory(ctx context.Context, accountID int64) (bool, error)
+	// Takes the lease when it is free or expired, or renews it for its holder. No row is returned while
+	// another instance holds it.
+	AcquireLeaderLease(ctx context.Context, arg AcquireLeaderLeaseParams) (LeaderLease, error)
+	// Adds the amount to the balance of the account, which must be posted as entries of the account in the
+	// same transaction.
 	AddAccountBalance(ctx context.Context, arg AddAccountBalanceParams) (Account, error)
+	AddAccountCounterpartyActivity(ctx context.Context, arg AddAccountCounterpartyActivityParams) error
+	AddAccountDailyActivity(ctx context.Context, arg AddAccountDailyActivityParams) error
+	AddAccountDailyVolume(ctx context.Context, arg AddAccountDailyVolumeParams) error
+	// Adds the amount to the held balance of the account, which must be the amount of a hold placed on
+	// the account or, negated, released from it in the same transaction.
+	AddAccountHeldBalance(ctx context.Context, arg AddAccountHeldBalanceParams) (Account, error)
+	AddRouteRequestVolume(ctx context.Context, arg AddRouteRequestVolumeParams) error
+	AddUserOverviewAccountCount(ctx context.Context, arg AddUserOverviewAccountCountParams) error
+	// Replaces the personal data of the user, whose username is kept since the ledger refers to it. The email
+	// stays unique without being deliverable, and no password matches the emptied hash.
+	AnonymizeUser(ctx context.Context, username string) (User, error)
+	// Replaces the personal data of the user in their overview, like `AnonymizeUser`.
+	AnonymizeUserOverview(ctx context.Context, username string) error
+	ApprovePendingTransfer(ctx context.Context, arg ApprovePendingTransferParams) (PendingTransfer, error)
+	// Assigns the review while it is open, no row being returned once it was decided.
+	AssignTransferReview(ctx context.Context, arg AssignTransferReviewParams) (TransferReview, error)
+	BounceChequeDeposit(ctx context.Context, arg BounceChequeDepositParams) (ChequeDeposit, error)
+	CancelJob(ctx context.Context, id uuid.UUID) (Job, error)
+	// Cancels the link provided it is still active, no row being returned otherwise.
+	CancelPaymentLink(ctx context.Context, id int64) (PaymentLink, error)
+	// Cancels the standing order provided it is still active, no row being returned otherwise.
+	CancelStandingOrder(ctx context.Context, id int64) (StandingOrder, error)
+	// Cancels the deletion of the user provided it is still scheduled, no row being returned otherwise.
+	CancelUserDeletion(ctx context.Context, username string) (UserDeletion, error)
+	// Cancels the active links of the merchant and those to the closed accounts of the merchant, for the
+	// deletion of their data, returning how many were.
+	CancelUserPaymentLinks(ctx context.Context, merchant string) (int64, error)
+	// Cancels the active standing orders of the owner and those from or to the closed accounts of the owner,
+	// for the deletion of their data, returning how many were.
+	CancelUserStandingOrders(ctx context.Context, owner string) (int64, error)
+	CaptureHold(ctx context.Context, arg CaptureHoldParams) (Hold, error)
+	// The claimed deliveries are pushed back until the lease expires, so that concurrent dispatchers don't
+	// send them too and a crashed dispatcher's are sent again afterwards.
+	ClaimNotificationDeliveries(ctx context.Context, arg ClaimNotificationDeliveriesParams) ([]NotificationDelivery, error)
+	ClearChequeDeposit(ctx context.Context, id int64) (ChequeDeposit, error)
+	// Closes the current version of the account as of the start of the transaction, when its values change
+	// or it is deleted.
+	CloseAccountVersion(ctx context.Context, accountID int64) error
+	// Closes the open accounts of the owner, for the deletion of their data. The accounts are kept with their
+	// history for the ledger.
+	CloseOwnerAccounts(ctx context.Context, owner string) ([]Account, error)
+	CompleteBatchRun(ctx context.Context, arg CompleteBatchRunParams) error
+	CompleteJob(ctx context.Context, arg CompleteJobParams) (Job, error)
+	// Completes the deletion of the user provided it is still scheduled and its cooling-off period is over,
+	// no row being returned otherwise.
+	CompleteUserDeletion(ctx context.Context, username string) (UserDeletion, error)
+	CountActiveSigningKeys(ctx context.Context, username string) (int64, error)
+	// Counts the failed logins from a client since a time, whatever the user.
+	CountClientLoginFailures(ctx context.Context, arg CountClientLoginFailuresParams) (int64, error)
+	CountEntries(ctx context.Context, accountID int64) (int64, error)
+	CountEntriesInRange(ctx context.Context, arg CountEntriesInRangeParams) (int64, error)
+	// Counts the failed logins of a user from a client since a time.
+	CountLoginFailures(ctx context.Context, arg CountLoginFailuresParams) (int64, error)
+	// Counts what awaits a user: their transfers awaiting approval, the payment requests they were sent, the
+	// invitations to accounts shared with them, their cheques still clearing and the holds reserving part of
+	// the balance of their accounts.
+	CountOwnerPendingItems(ctx context.Context, owner string) (CountOwnerPendingItemsRow, error)
+	// Counts the sessions of the user, along with those opened from the client with the user agent.
+	CountUserSessions(ctx context.Context, arg CountUserSessionsParams) (CountUserSessionsRow, error)
+	// Creates the account in the organization of its owner.
 	CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error)
+	// Invites a user to share an account, the membership being pending until they accept it.
+	CreateAccountMember(ctx context.Context, arg CreateAccountMemberParams) (AccountMember, error)
+	// Opens a version of the account with its current values, the previous one having to be closed first.
+	CreateAccountVersion(ctx context.Context, arg CreateAccountVersionParams) (AccountHistory, error)
+	CreateAuditEntry(ctx context.Context, arg CreateAuditEntryParams) (AuditEntry, error)
+	CreateBalanceSnapshot(ctx context.Context, arg CreateBalanceSnapshotParams) error
+	// Publishes the next version of the parameter.
+	CreateBankParameter(ctx context.Context, arg CreateBankParameterParams) (BankParameter, error)
+	CreateBeneficiary(ctx context.Context, arg CreateBeneficiaryParams) (Beneficiary, error)
+	CreateCard(ctx context.Context, arg CreateCardParams) (Card, error)
+	CreateChequeDeposit(ctx context.Context, arg CreateChequeDepositParams) (ChequeDeposit, error)
 	CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry, error)
+	CreateEvent(ctx context.Context, arg CreateEventParams) (Event, error)
+	CreateExternalTransfer(ctx context.Context, arg CreateExternalTransferParams) (ExternalTransfer, error)
+	// Creates an entry made before it was recorded, e.g. imported from another system.
+	CreateHistoricalEntry(ctx context.Context, arg CreateHistoricalEntryParams) (Entry, error)
+	CreateHold(ctx context.Context, arg CreateHoldParams) (Hold, error)
+	CreateJob(ctx context.Context, arg CreateJobParams) (Job, error)
+	CreateLoginFailure(ctx context.Context, arg CreateLoginFailureParams) error
+	CreateMandate(ctx context.Context, arg CreateMandateParams) (Mandate, error)
+	// Notifications are projected from events, a replayed event doesn't notify the user twice.
+	CreateNotification(ctx context.Context, arg CreateNotificationParams) error
+	// A replayed event doesn't send the notification twice.
+	CreateNotificationDelivery(ctx context.Context, arg CreateNotificationDeliveryParams) error
+	CreateOrganization(ctx context.Context, arg CreateOrganizationParams) (Organization, error)
+	CreatePaymentLink(ctx context.Context, arg CreatePaymentLinkParams) (PaymentLink, error)
+	CreatePaymentRequest(ctx context.Context, arg CreatePaymentRequestParams) (PaymentRequest, error)
+	CreatePendingTransfer(ctx context.Context, arg CreatePendingTransferParams) (PendingTransfer, error)
+	CreateProcessedTask(ctx context.Context, arg CreateProcessedTaskParams) error
+	CreateQueuedTransfer(ctx context.Context, arg CreateQueuedTransferParams) (QueuedTransfer, error)
 	CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error)
+	CreateSigningKey(ctx context.Context, arg CreateSigningKeyParams) (SigningKey, error)
+	CreateStandingOrder(ctx context.Context, arg CreateStandingOrderParams) (StandingOrder, error)
 	CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transfer, error)
+	CreateTransferReview(ctx context.Context, arg CreateTransferReviewParams) (TransferReview, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	CreateUserIdentity(ctx context.Context, arg CreateUserIdentityParams) (UserIdentity, error)
+	DecideTransferReview(ctx context.Context, arg DecideTransferReviewParams) (TransferReview, error)
+	// Declines the request provided it is still pending, no row being returned otherwise.
+	DeclinePaymentRequest(ctx context.Context, id int64) (PaymentRequest, error)
 	DeleteAccount(ctx context.Context, id int64) error
+	DeleteAccountAlert(ctx context.Context, accountID int64) error
+	DeleteAccountCounterpartyActivity(ctx context.Context, accountID int64) error
+	DeleteAccountDailyActivity(ctx context.Context, accountID int64) error
+	DeleteAccountMember(ctx context.Context, arg DeleteAccountMemberParams) error
+	DeleteAccountOverview(ctx context.Context, accountID int64) (string, error)
+	DeleteBeneficiary(ctx context.Context, id int64) error
+	DeleteBudget(ctx context.Context, arg DeleteBudgetParams) error
+	DeleteClientLoginFailures(ctx context.Context, arg DeleteClientLoginFailuresParams) (int64, error)
+	DeleteLoginFailures(ctx context.Context, username string) (int64, error)
+	DeleteLoginLockouts(ctx context.Context, username string) (int64, error)
+	// Deletes the preferences of the user, their webhook included.
+	DeleteNotificationPreferences(ctx context.Context, username string) error
+	// Deletes the beneficiaries saved by the owner, for the deletion of their data.
+	DeleteOwnerBeneficiaries(ctx context.Context, owner string) error
+	DeleteStaleAccountDailyVolume(ctx context.Context) error
+	DeleteUserAPIPlan(ctx context.Context, username string) error
+	// Deletes the memberships of the user, and those of the closed accounts of the user, for the deletion of
+	// their data.
+	DeleteUserAccountMembers(ctx context.Context, username string) error
+	// Unlinks the identities of the user, who can no longer log in with their providers.
+	DeleteUserIdentities(ctx context.Context, username string) error
+	// Deletes the sessions of the user, their refresh tokens no longer renewing access tokens.
+	DeleteUserSessions(ctx context.Context, username string) error
+	// Deletes the signing keys of the user, revoked ones included.
+	DeleteUserSigningKeys(ctx context.Context, username string) error
+	// Escalates the review while it is open, no row being returned once it was decided.
+	EscalateTransferReview(ctx context.Context, arg EscalateTransferReviewParams) (TransferReview, error)
+	// Expires the authorized holds past their expiry, returning them so that the held balance of their
+	// accounts can be released.
+	ExpireHolds(ctx context.Context, expiresAt time.Time) ([]Hold, error)
+	// Expires the active links past their expiry, returning how many were.
+	ExpirePaymentLinks(ctx context.Context, expiresAt time.Time) (int64, error)
+	// Expires the pending requests past their expiry, returning how many were.
+	ExpirePaymentRequests(ctx context.Context, expiresAt time.Time) (int64, error)
+	// Expires the transfers awaiting approval past their expiry, returning how many were.
+	ExpirePendingTransfers(ctx context.Context, expiresAt time.Time) (int64, error)
+	FailJob(ctx context.Context, arg FailJobParams) (Job, error)
+	// Fails the deletion of the user provided it is still scheduled, no row being returned otherwise.
+	FailUserDeletion(ctx context.Context, arg FailUserDeletionParams) (UserDeletion, error)
+	// Freezes the active cards of the user and those of the closed accounts of the user, for the deletion of
+	// their data, returning how many were.
+	FreezeUserCards(ctx context.Context, owner string) (int64, error)
 	GetAccount(ctx context.Context, id int64) (Account, error)
+	GetAccountAlert(ctx context.Context, accountID int64) (AccountAlert, error)
+	GetAccountByNumber(ctx context.Context, accountNumber string) (Account, error)
 	GetAccountForUpdate(ctx context.Context, id int64) (Account, error)
+	GetAccountMember(ctx context.Context, arg GetAccountMemberParams) (AccountMember, error)
+	// Gets the accounts of an owner among the given ids, including the accounts shared with them, the other
+	// ids are skipped.
+	GetAccountsByIDs(ctx context.Context, arg GetAccountsByIDsParams) ([]Account, error)
+	// Returns the version of the parameter in effect at the given time, the latest published one when
+	// several versions take effect at the same time.
+	GetActiveBankParameter(ctx context.Context, arg GetActiveBankParameterParams) (BankParameter, error)
+	GetBalanceSnapshot(ctx context.Context, arg GetBalanceSnapshotParams) (BalanceSnapshot, error)
+	GetBeneficiary(ctx context.Context, id int64) (Beneficiary, error)
+	// Gets the beneficiary an owner saved an account as.
+	GetBeneficiaryByAccount(ctx context.Context, arg GetBeneficiaryByAccountParams) (Beneficiary, error)
+	GetBudget(ctx context.Context, arg GetBudgetParams) (Budget, error)
+	GetCard(ctx context.Context, id int64) (Card, error)
+	GetCardForUpdate(ctx context.Context, id int64) (Card, error)
+	GetChequeDeposit(ctx context.Context, id int64) (ChequeDeposit, error)
+	GetChequeDepositForUpdate(ctx context.Context, id int64) (ChequeDeposit, error)
 	GetEntry(ctx context.Context, id int64) (Entry, error)
+	GetExternalTransfer(ctx context.Context, id int64) (ExternalTransfer, error)
+	GetExternalTransferForUpdate(ctx context.Context, id int64) (ExternalTransfer, error)
+	GetHold(ctx context.Context, id int64) (Hold, error)
+	GetHoldForUpdate(ctx context.Context, id int64) (Hold, error)
+	GetInterestAccrual(ctx context.Context, accountID int64) (InterestAccrual, error)
+	GetJob(ctx context.Context, id uuid.UUID) (Job, error)
+	// Returns the completed run of the batch with the latest business date, which the runs of the
+	// business dates missed since resume from.
+	GetLastCompletedBatchRun(ctx context.Context, name string) (BatchRun, error)
+	GetLeaderLease(ctx context.Context, name string) (LeaderLease, error)
+	GetLoginLockout(ctx context.Context, arg GetLoginLockoutParams) (LoginLockout, error)
+	GetMandate(ctx context.Context, id int64) (Mandate, error)
+	GetMandateForUpdate(ctx context.Context, id int64) (Mandate, error)
+	GetNotificationPreferences(ctx context.Context, username string) (NotificationPreference, error)
+	GetOrganization(ctx context.Context, id int64) (Organization, error)
+	GetOrganizationBySlug(ctx context.Context, slug string) (Organization, error)
+	GetPaymentLinkByCode(ctx context.Context, code string) (PaymentLink, error)
+	GetPaymentLinkForUpdate(ctx context.Context, id int64) (PaymentLink, error)
+	GetPaymentRequest(ctx context.Context, id int64) (PaymentRequest, error)
+	GetPaymentRequestForUpdate(ctx context.Context, id int64) (PaymentRequest, error)
+	GetPendingTransfer(ctx context.Context, id int64) (PendingTransfer, error)
+	GetPendingTransferForUpdate(ctx context.Context, id int64) (PendingTransfer, error)
+	GetQueuedTransfer(ctx context.Context, id uuid.UUID) (QueuedTransfer, error)
 	GetSession(ctx context.Context, id uuid.UUID) (Session, error)
+	GetSigningKey(ctx context.Context, id int64) (SigningKey, error)
+	GetStandingOrder(ctx context.Context, id int64) (StandingOrder, error)
+	GetStandingOrderForUpdate(ctx context.Context, id int64) (StandingOrder, error)
+	// Gets the system account of a purpose in a currency.
+	GetSystemAccount(ctx context.Context, arg GetSystemAccountParams) (Account, error)
 	GetTransfer(ctx context.Context, id int64) (Transfer, error)
+	GetTransferReview(ctx context.Context, id int64) (TransferReview, error)
+	GetTransferReviewForUpdate(ctx context.Context, id int64) (TransferReview, error)
 	GetUser(ctx context.Context, username string) (User, error)
+	GetUserAPIPlan(ctx context.Context, username string) (UserApiPlan, error)
+	GetUserByEmail(ctx context.Context, email string) (User, error)
+	GetUserDeletion(ctx context.Context, username string) (UserDeletion, error)
+	GetUserForUpdate(ctx context.Context, username string) (User, error)
+	GetUserIdentity(ctx context.Context, arg GetUserIdentityParams) (UserIdentity, error)
+	GetUserOverview(ctx context.Context, username string) (UserOverview, error)
+	// Hands the open accounts of the owner shared with co-owners over to the co-owner who accepted first, for
+	// the deletion of the data of the owner, so that the accounts aren't closed under their co-owners.
+	HandOverJointAccounts(ctx context.Context, owner string) ([]Account, error)
+	IsTaskProcessed(ctx context.Context, id string) (bool, error)
+	// Lists the accounts whose balance differs from the sum of their entries.
+	ListAccountBalanceDiscrepancies(ctx context.Context) ([]ListAccountBalanceDiscrepanciesRow, error)
+	ListAccountCounterpartyActivity(ctx context.Context, arg ListAccountCounterpartyActivityParams) ([]AccountCounterpartyActivity, error)
+	ListAccountDailyActivity(ctx context.Context, arg ListAccountDailyActivityParams) ([]AccountDailyActivity, error)
+	// Lists the versions of the account, oldest first.
+	ListAccountHistory(ctx context.Context, arg ListAccountHistoryParams) ([]AccountHistory, error)
+	// Lists the holds placed on an account, newest first.
+	ListAccountHolds(ctx context.Context, arg ListAccountHoldsParams) ([]Hold, error)
+	// Lists the pending invitations of a user, oldest first.
+	ListAccountInvitations(ctx context.Context, arg ListAccountInvitationsParams) ([]AccountMember, error)
+	ListAccountOverviews(ctx context.Context, arg ListAccountOverviewsParams) ([]AccountOverview, error)
 	ListAccounts(ctx context.Context, arg ListAccountsParams) ([]Account, error)
+	// Lists the accounts following an id, locked for the batch updating them.
+	ListAccountsAfter(ctx context.Context, arg ListAccountsAfterParams) ([]Account, error)
+	ListActiveBankParameters(ctx context.Context, at time.Time) ([]BankParameter, error)
+	// Lists the audit entries of the actions taken as a user or by an impersonator, newest first.
+	ListAuditEntries(ctx context.Context, arg ListAuditEntriesParams) ([]AuditEntry, error)
+	ListBalanceSnapshots(ctx context.Context, arg ListBalanceSnapshotsParams) ([]BalanceSnapshot, error)
+	// Lists every version of the parameters, or of a single one, the latest first.
+	ListBankParameterVersions(ctx context.Context, arg ListBankParameterVersionsParams) ([]BankParameter, error)
+	// Lists the beneficiaries saved by an owner by nickname.
+	ListBeneficiaries(ctx context.Context, arg ListBeneficiariesParams) ([]Beneficiary, error)
+	ListBudgets(ctx context.Context, accountID int64) ([]Budget, error)
+	// Lists the holds placed by the authorizations of a card, newest first.
+	ListCardHolds(ctx context.Context, arg ListCardHoldsParams) ([]Hold, error)
+	// Lists the cards issued to a user, oldest first.
+	ListCards(ctx context.Context, arg ListCardsParams) ([]Card, error)
+	ListDailyTransferVolumes(ctx context.Context, since time.Time) ([]ListDailyTransferVolumesRow, error)
+	// Lists the pending cheque deposits whose clearing period is over, the oldest first.
+	ListDueChequeDeposits(ctx context.Context, arg ListDueChequeDepositsParams) ([]ChequeDeposit, error)
+	// Lists the active standing orders whose next run is due, the most overdue first.
+	ListDueStandingOrders(ctx context.Context, arg ListDueStandingOrdersParams) ([]StandingOrder, error)
 	ListEntries(ctx context.Context, arg ListEntriesParams) ([]Entry, error)
+	// Lists the entries of an account made in a period, with the transfer each was made for and the other
+	// account of the transfer, for the personal finance files. The transfer is null for the entries made
+	// without one.
+	ListEntriesForExport(ctx context.Context, arg ListEntriesForExportParams) ([]ListEntriesForExportRow, error)
+	ListEntriesInRange(ctx context.Context, arg ListEntriesInRangeParams) ([]Entry, error)
+	// Lists the entries of an account with the other account of the transfer each was made for, its
+	// nickname and its owner. The counterparty is null for the entries made without a transfer. Each entry comes with the
+	// currency of its account.
+	ListEntriesWithCounterparty(ctx context.Context, arg ListEntriesWithCounterpartyParams) ([]ListEntriesWithCounterpartyRow, error)
+	// Lists the entries of an account like ListEntriesWithCounterparty, newest first and a page at a time by
+	// their id: the page after a cursor, the id of the last entry of the previous page, holds the entries
+	// made before it. The first page, without a cursor, starts with the newest entry.
+	ListEntriesWithCounterpartyBefore(ctx context.Context, arg ListEntriesWithCounterpartyBeforeParams) ([]ListEntriesWithCounterpartyBeforeRow, error)
+	// Events are only listed once they have settled, recorded before settled_before: ids are allocated when
+	// the event is inserted but become visible at commit, so a recent event may still be followed by a
+	// smaller id.
+	ListEventsAfter(ctx context.Context, arg ListEventsAfterParams) ([]Event, error)
+	// Lists the external transfers sent by a user, oldest first.
+	ListExternalTransfers(ctx context.Context, arg ListExternalTransfersParams) ([]ExternalTransfer, error)
+	// Lists the anomalies, optionally only the open ones, the last detected first.
+	ListLedgerAnomalies(ctx context.Context, arg ListLedgerAnomaliesParams) ([]LedgerAnomaly, error)
+	// Lists the mandates a payer granted, oldest first.
+	ListMandates(ctx context.Context, arg ListMandatesParams) ([]Mandate, error)
+	ListNotifications(ctx context.Context, arg ListNotificationsParams) ([]Notification, error)
+	// Lists the transfers awaiting the approval of the admins of an organization, oldest first.
+	ListOrganizationPendingTransfers(ctx context.Context, arg ListOrganizationPendingTransfersParams) ([]PendingTransfer, error)
+	ListOrganizationUsers(ctx context.Context, arg ListOrganizationUsersParams) ([]User, error)
+	// Lists the latest entries of the accounts of an owner, newest first, with their counterparty like
+	// ListEntriesWithCounterparty.
+	ListOwnerRecentEntries(ctx context.Context, arg ListOwnerRecentEntriesParams) ([]ListOwnerRecentEntriesRow, error)
+	// Lists the payment requests sent by a requester or received by a payer, oldest first, optionally only
+	// those with a status.
+	ListPaymentRequests(ctx context.Context, arg ListPaymentRequestsParams) ([]PaymentRequest, error)
+	// Lists the pending transfers, oldest first, optionally only those of an owner or with a status.
+	ListPendingTransfers(ctx context.Context, arg ListPendingTransfersParams) ([]PendingTransfer, error)
+	ListRequestHeatmap(ctx context.Context, since time.Time) ([]ListRequestHeatmapRow, error)
+	ListRouteRequestVolumes(ctx context.Context, since time.Time) ([]ListRouteRequestVolumesRow, error)
+	// Lists the signing keys of a user, revoked ones included, oldest first.
+	ListSigningKeys(ctx context.Context, username string) ([]SigningKey, error)
+	// Lists the external transfers still initiated or processing that didn't move since before updated_at,
+	// after the id, in order of id.
+	ListStaleExternalTransfers(ctx context.Context, arg ListStaleExternalTransfersParams) ([]ExternalTransfer, error)
+	// Lists the standing orders the owner set up, oldest first.
+	ListStandingOrders(ctx context.Context, arg ListStandingOrdersParams) ([]StandingOrder, error)
+	ListTransferHeatmap(ctx context.Context, since time.Time) ([]ListTransferHeatmapRow, error)
+	// Lists the transfers whose entries don't net to zero.
+	ListTransferImbalances(ctx context.Context) ([]ListTransferImbalancesRow, error)
+	// Lists the reviews, the first due first, optionally only those with a status, assigned to a reviewer or
+	// still open past a time.
+	ListTransferReviews(ctx context.Context, arg ListTransferReviewsParams) ([]TransferReview, error)
 	ListTransfers(ctx context.Context, arg ListTransfersParams) ([]Transfer, error)
-	UpdateAccount(ctx context.Context, arg UpdateAccountParams) (Account, error)
+	ListUserOverviews(ctx context.Context, arg ListUserOverviewsParams) ([]UserOverview, error)
+	// Locks the accounts among the given ids, in order of id so that the transactions locking several
+	// accounts at once don't deadlock.
+	LockAccounts(ctx context.Context, ids []int64) ([]int64, error)
+	// Creates the run of the batch for the business date if needed, and locks it until the end of the
+	// transaction so that concurrent runners process each account once.
+	LockBatchRun(ctx context.Context, arg LockBatchRunParams) (BatchRun, error)
+	LockProjectionCheckpoint(ctx context.Context, name string) (int64, error)
+	MarkAllNotificationsRead(ctx context.Context, username string) (int64, error)
+	MarkNotificationDelivered(ctx context.Context, id int64) error
+	// A notification read earlier keeps the time it was first read.
+	MarkNotificationRead(ctx context.Context, arg MarkNotificationReadParams) (Notification, error)
+	// Reports whether an open account of the owner holds money, which prevents the deletion of their data.
+	// The accounts shared with co-owners don't, since they are handed over to a co-owner rather than closed.
+	OwnerHasBalance(ctx context.Context, owner string) (bool, error)
+	PayPaymentLink(ctx context.Context, arg PayPaymentLinkParams) (PaymentLink, error)
+	RecordAccountOverviewTransfer(ctx context.Context, arg RecordAccountOverviewTransferParams) error
+	RecordMandatePull(ctx context.Context, arg RecordMandatePullParams) (Mandate, error)
+	RecordNotificationDeliveryFailure(ctx context.Context, arg RecordNotificationDeliveryFailureParams) error
+	// Records the run of the standing order due at `due_at`, provided it is still active and wasn't
+	// recorded meanwhile, no row being returned otherwise.
+	RecordStandingOrderRun(ctx context.Context, arg RecordStandingOrderRunParams) (StandingOrder, error)
+	RefreshAccountOverviewVolume(ctx context.Context, accountID pgtype.Int8) error
+	// Rejects the transfer provided it still awaits approval, no row being returned otherwise.
+	RejectPendingTransfer(ctx context.Context, arg RejectPendingTransferParams) (PendingTransfer, error)
+	// Releases an authorized hold, returning no row once it was captured, released or expired.
+	ReleaseHold(ctx context.Context, id int64) (Hold, error)
+	ReleaseLeaderLease(ctx context.Context, arg ReleaseLeaderLeaseParams) error
+	// Resolves the open anomalies that weren't found again by the reconciliation of the current
+	// transaction, in which now() doesn't change.
+	ResolveLedgerAnomalies(ctx context.Context) (int64, error)
+	// Revokes the mandate provided it is still active, no row being returned otherwise.
+	RevokeMandate(ctx context.Context, id int64) (Mandate, error)
+	// Revokes the signing key of the user provided it is still active, no row being returned otherwise.
+	RevokeSigningKey(ctx context.Context, arg RevokeSigningKeyParams) (SigningKey, error)
+	// Revokes the active mandates given by the user and those from or to the closed accounts of the user,
+	// for the deletion of their data, returning how many were.
+	RevokeUserMandates(ctx context.Context, payer string) (int64, error)
+	// Schedules the deletion of the data of the user, replacing a cancelled or failed one. No row is returned
+	// when a deletion of the user is already scheduled or completed.
+	ScheduleUserDeletion(ctx context.Context, arg ScheduleUserDeletionParams) (UserDeletion, error)
+	// Removes the personal data of the user from their events, for the projections replaying them to not
+	// bring it back.
+	ScrubUserEvents(ctx context.Context, username string) error
+	// Searches the entries of an account made for transfers whose memo, counterparty or category match the
+	// web search query, best matches first. The counterparty is the other account of the transfer, matched
+	// by the name and username of its owner, and the category the one the owner of the account gave it as a
+	// beneficiary, for the transfers sent to it. The highlights wrap the matched words of each field in
+	// <mark> tags, the text of the field being HTML escaped, and are empty for the fields without a match.
+	SearchAccountEntries(ctx context.Context, arg SearchAccountEntriesParams) ([]SearchAccountEntriesRow, error)
+	// Lists the accounts of an owner, including the accounts shared with them, optionally of a single currency
+	// and with at least a given balance, sorted by sort_by (balance, created_at or currency, defaulting to id)
+	// with ties broken by id.
+	SearchAccounts(ctx context.Context, arg SearchAccountsParams) ([]Account, error)
+	// Lists the transfers sent or received by an account, optionally only those with an external reference
+	// or whose memo contains a text, regardless of its case. Each transfer comes with its other account,
+	// the counterparty, and its owner, and with its currency, the one of both its accounts.
+	SearchTransfers(ctx context.Context, arg SearchTransfersParams) ([]SearchTransfersRow, error)
+	// Lists the transfers of an account like SearchTransfers, newest first and a page at a time by their id:
+	// the page after a cursor, the id of the last transfer of the previous page, holds the transfers made
+	// before it. The first page, without a cursor, starts with the newest transfer.
+	SearchTransfersBefore(ctx context.Context, arg SearchTransfersBeforeParams) ([]SearchTransfersBeforeRow, error)
+	SetAccountOverviewBalance(ctx context.Context, arg SetAccountOverviewBalanceParams) error
+	SetInterestAccrual(ctx context.Context, arg SetInterestAccrualParams) error
+	// Sums the transfers sent from an account to the beneficiaries an owner saved in a category, created
+	// between since and until included.
+	SumCategorySpending(ctx context.Context, arg SumCategorySpendingParams) (int64, error)
+	SumEntriesSince(ctx context.Context, arg SumEntriesSinceParams) (int64, error)
+	// Sums the balances of the open accounts of an owner by currency, the accounts shared with them being
+	// left out.
+	SumOwnerBalances(ctx context.Context, owner string) ([]SumOwnerBalancesRow, error)
+	// Sums the amounts that left the accounts of an owner since a time by currency, positive. The transfers
+	// between the accounts of the owner aren't spending and are left out.
+	SumOwnerSpending(ctx context.Context, arg SumOwnerSpendingParams) ([]SumOwnerSpendingRow, error)
+	TouchSession(ctx context.Context, arg TouchSessionParams) error
+	TouchUserOverview(ctx context.Context, arg TouchUserOverviewParams) error
+	// Changes the currency of the account, whose balance must be converted by entries of the account in the
+	// same transaction.
+	UpdateAccountCurrency(ctx context.Context, arg UpdateAccountCurrencyParams) (Account, error)
+	// Changes the nickname or the tier of the account, the ones left null being kept, provided the account
+	// is still at the version, whatever its version when 0.
+	UpdateAccountProfile(ctx context.Context, arg UpdateAccountProfileParams) (Account, error)
+	UpdateBatchRunCheckpoint(ctx context.Context, arg UpdateBatchRunCheckpointParams) error
+	UpdateBeneficiary(ctx context.Context, arg UpdateBeneficiaryParams) (Beneficiary, error)
+	UpdateCardStatus(ctx context.Context, arg UpdateCardStatusParams) (Card, error)
+	UpdateExternalTransferStatus(ctx context.Context, arg UpdateExternalTransferStatusParams) (ExternalTransfer, error)
+	UpdateJobProgress(ctx context.Context, arg UpdateJobProgressParams) (Job, error)
+	UpdateOrganizationApprovalThreshold(ctx context.Context, arg UpdateOrganizationApprovalThresholdParams) (Organization, error)
+	UpdateProjectionCheckpoint(ctx context.Context, arg UpdateProjectionCheckpointParams) error
+	UpdateUserRole(ctx context.Context, arg UpdateUserRoleParams) (User, error)
+	UpsertAccountAlert(ctx context.Context, arg UpsertAccountAlertParams) (AccountAlert, error)
+	UpsertAccountOverview(ctx context.Context, arg UpsertAccountOverviewParams) error
+	UpsertBudget(ctx context.Context, arg UpsertBudgetParams) (Budget, error)
+	// Records an anomaly, or updates it when it was already found, reopening it if it was resolved.
+	UpsertLedgerAnomaly(ctx context.Context, arg UpsertLedgerAnomalyParams) (LedgerAnomaly, error)
+	UpsertLoginLockout(ctx context.Context, arg UpsertLoginLockoutParams) (LoginLockout, error)
+	UpsertNotificationPreferences(ctx context.Context, arg UpsertNotificationPreferencesParams) (NotificationPreference, error)
+	UpsertUserAPIPlan(ctx context.Context, arg UpsertUserAPIPlanParams) (UserApiPlan, error)
+	UpsertUserOverview(ctx context.Context, arg UpsertUserOverviewParams) error
 }
 
 var _ Querier = (*Queries)(nil)
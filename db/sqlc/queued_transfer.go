@@ -0,0 +1,85 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Statuses of a queued transfer. A transfer is queued while the database is unavailable, and only
+// recorded once processed with the outcome of the transfer, which is final.
+const (
+	QueuedTransferQueued          = "queued"
+	QueuedTransferSucceeded       = "succeeded"
+	QueuedTransferHeld            = "held"
+	QueuedTransferPendingApproval = "pending_approval"
+	QueuedTransferFailed          = "failed"
+)
+
+// ErrQueuedTransferProcessed is returned when recording the outcome of a queued transfer that was already
+// processed, the transaction making it again being rolled back.
+var ErrQueuedTransferProcessed = errors.New("queued transfer was already processed")
+
+// The QueuedTransferEvent type describes the outcome of a queued transfer for the
+// queued_transfer.processed event, which notifies its owner.
+type QueuedTransferEvent struct {
+	QueuedTransferID  uuid.UUID `json:"queued_transfer_id"`
+	Owner             string    `json:"owner"`
+	Status            string    `json:"status"`
+	TransferID        *int64    `json:"transfer_id"`
+	ReviewID          *int64    `json:"review_id"`
+	PendingTransferID *int64    `json:"pending_transfer_id"`
+	Error             string    `json:"error"`
+	QueuedAt          time.Time `json:"queued_at"`
+	ProcessedAt       time.Time `json:"processed_at"`
+}
+
+// CreateQueuedTransfer records the outcome of a queued transfer and a queued_transfer.processed event.
+// ErrQueuedTransferProcessed is returned when it was already recorded.
+func (store *SQLStore) CreateQueuedTransfer(ctx context.Context, arg CreateQueuedTransferParams) (QueuedTransfer, error) {
+	var queued QueuedTransfer
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		queued, err = recordQueuedTransfer(ctx, q, arg)
+		return err
+	})
+
+	return queued, err
+}
+
+// recordQueuedTransfer records the outcome of a queued transfer within the transaction of `q`, so that
+// a transfer made from the queue is recorded along with the money it moves, and made once however many
+// times the queue delivers it.
+func recordQueuedTransfer(ctx context.Context, q *Queries, arg CreateQueuedTransferParams) (QueuedTransfer, error) {
+	queued, err := q.CreateQueuedTransfer(ctx, arg)
+	if err != nil {
+		if ErrorCode(err) == UniqueViolation {
+			return queued, ErrQueuedTransferProcessed
+		}
+		return queued, err
+	}
+
+	err = recordEvent(ctx, q, EventQueuedTransferProcessed, QueuedTransferEvent{
+		QueuedTransferID:  queued.ID,
+		Owner:             queued.Owner,
+		Status:            queued.Status,
+		TransferID:        int8Ptr(queued.TransferID),
+		ReviewID:          int8Ptr(queued.ReviewID),
+		PendingTransferID: int8Ptr(queued.PendingTransferID),
+		Error:             queued.Error,
+		QueuedAt:          queued.QueuedAt,
+		ProcessedAt:       queued.ProcessedAt,
+	})
+	return queued, err
+}
+
+func int8Ptr(value pgtype.Int8) *int64 {
+	if !value.Valid {
+		return nil
+	}
+	return &value.Int64
+}
@@ -0,0 +1,108 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransferTxQueued(t *testing.T) {
+	store := NewStore(testDB)
+	fromAccount := createRandomAccount(t)
+	toAccount := createRandomAccount(t)
+
+	arg := TransferTxParams{
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   toAccount.ID,
+		Amount:        10,
+		Queued: &CreateQueuedTransferParams{
+			ID:       uuid.New(),
+			Owner:    fromAccount.Owner,
+			QueuedAt: time.Now().Add(-time.Minute),
+		},
+	}
+
+	result, err := store.TransferTx(context.Background(), arg)
+	require.NoError(t, err)
+
+	queued, err := testQueries.GetQueuedTransfer(context.Background(), arg.Queued.ID)
+	require.NoError(t, err)
+	require.Equal(t, QueuedTransferSucceeded, queued.Status)
+	require.Equal(t, result.Transfer.ID, queued.TransferID.Int64)
+	require.Equal(t, fromAccount.Owner, queued.Owner)
+
+	// a queued transfer delivered again isn't made twice
+	_, err = store.TransferTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrQueuedTransferProcessed)
+
+	account, err := testQueries.GetAccount(context.Background(), fromAccount.ID)
+	require.NoError(t, err)
+	require.Equal(t, result.FromAccount.Balance, account.Balance)
+}
+
+func TestCreateQueuedTransferFailed(t *testing.T) {
+	store := NewStore(testDB)
+	account := createRandomAccount(t)
+
+	arg := CreateQueuedTransferParams{
+		ID:       uuid.New(),
+		Owner:    account.Owner,
+		Status:   QueuedTransferFailed,
+		Error:    "account currency mismatch",
+		QueuedAt: time.Now().Add(-time.Minute),
+	}
+
+	queued, err := store.CreateQueuedTransfer(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, QueuedTransferFailed, queued.Status)
+	require.Equal(t, arg.Error, queued.Error)
+	require.False(t, queued.TransferID.Valid)
+
+	_, err = store.CreateQueuedTransfer(context.Background(), arg)
+	require.ErrorIs(t, err, ErrQueuedTransferProcessed)
+}
+
+func TestCreatePendingTransferTxQueued(t *testing.T) {
+	store := NewStore(testDB)
+	fromAccount := createRandomAccount(t)
+	toAccount := createRandomAccount(t)
+
+	arg := CreatePendingTransferTxParams{
+		PendingTransfer: CreatePendingTransferParams{
+			Owner:         fromAccount.Owner,
+			FromAccountID: fromAccount.ID,
+			ToAccountID:   toAccount.ID,
+			Amount:        10,
+			ExpiresAt:     time.Now().Add(time.Hour),
+		},
+		Queued: &CreateQueuedTransferParams{
+			ID:       uuid.New(),
+			Owner:    fromAccount.Owner,
+			QueuedAt: time.Now().Add(-time.Minute),
+		},
+	}
+
+	pending, err := store.CreatePendingTransferTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, PendingTransferAwaitingApproval, pending.Status)
+
+	queued, err := testQueries.GetQueuedTransfer(context.Background(), arg.Queued.ID)
+	require.NoError(t, err)
+	require.Equal(t, QueuedTransferPendingApproval, queued.Status)
+	require.Equal(t, pending.ID, queued.PendingTransferID.Int64)
+
+	// a queued transfer delivered again doesn't await approval twice
+	_, err = store.CreatePendingTransferTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrQueuedTransferProcessed)
+
+	pendings, err := testQueries.ListPendingTransfers(context.Background(), ListPendingTransfersParams{
+		Owner:    pgtype.Text{String: fromAccount.Owner, Valid: true},
+		RowLimit: 10,
+	})
+	require.NoError(t, err)
+	require.Len(t, pendings, 1)
+}
@@ -0,0 +1,198 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// replicaRetryInterval is how long reads go to the primary once the replica failed to serve one, before
+// the replica is tried again.
+const replicaRetryInterval = 30 * time.Second
+
+// readOnlyQueryPrefixes are the prefixes of the names of the queries that only read, which the replica
+// can serve.
+var readOnlyQueryPrefixes = []string{"Get", "List", "Search", "Count"}
+
+// primaryQueries are the read-only queries the requests are authenticated with, which are served by the
+// primary so that a session blocked, a password changed or a key revoked a moment ago can't be used
+// while the replica lags behind.
+var primaryQueries = map[string]bool{
+	"GetSession":               true,
+	"GetUser":                  true,
+	"GetUserByEmail":           true,
+	"GetUserIdentity":          true,
+	"GetSigningKey":            true,
+	"GetLoginLockout":          true,
+	"CountLoginFailures":       true,
+	"CountClientLoginFailures": true,
+}
+
+// NewStoreWithReplica creates a store whose read-only queries made outside of a transaction are served
+// by the replica, while writes, locking reads, transactions and the reads authenticating the requests go
+// to the primary. Reads fall back to the
+// primary while the replica is unreachable. The replica lags behind the primary, so reads that must see
+// a write made just before should be made within a transaction.
+func NewStoreWithReplica(primary ConnPool, replica *pgxpool.Pool, options ...StoreOption) Store {
+	return newSQLStore(primary, New(&replicaRouter{primary: primary, replica: replica}), options)
+}
+
+// The replicaRouter type sends the read-only queries to the replica and every other query to the
+// primary. The time until which the replica is considered down is stored in unix nanoseconds.
+type replicaRouter struct {
+	primary   DBTX
+	replica   *pgxpool.Pool
+	downUntil atomic.Int64
+}
+
+func (router *replicaRouter) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	return router.primary.Exec(ctx, sql, args...)
+}
+
+func (router *replicaRouter) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	if !router.useReplica(sql) {
+		return router.primary.Query(ctx, sql, args...)
+	}
+
+	rows, err := router.replica.Query(ctx, sql, args...)
+	if err != nil && unreachable(ctx, err) {
+		router.markDown()
+		return router.primary.Query(ctx, sql, args...)
+	}
+	if err != nil {
+		return rows, err
+	}
+
+	return &fallbackRows{
+		Rows:   rows,
+		ctx:    ctx,
+		sql:    sql,
+		args:   args,
+		router: router,
+	}, nil
+}
+
+func (router *replicaRouter) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	if !router.useReplica(sql) {
+		return router.primary.QueryRow(ctx, sql, args...)
+	}
+
+	return &fallbackRow{
+		Row:    router.replica.QueryRow(ctx, sql, args...),
+		ctx:    ctx,
+		sql:    sql,
+		args:   args,
+		router: router,
+	}
+}
+
+func (router *replicaRouter) useReplica(sql string) bool {
+	return isReadOnlyQuery(sql) && time.Now().UnixNano() >= router.downUntil.Load()
+}
+
+func (router *replicaRouter) markDown() {
+	router.downUntil.Store(time.Now().Add(replicaRetryInterval).UnixNano())
+}
+
+// The fallbackRow type is a row read from the replica, which is read again from the primary when the
+// replica couldn't serve it. The error of a row is only known once it is scanned.
+type fallbackRow struct {
+	pgx.Row
+	ctx    context.Context
+	sql    string
+	args   []interface{}
+	router *replicaRouter
+}
+
+func (row *fallbackRow) Scan(dest ...interface{}) error {
+	err := row.Row.Scan(dest...)
+	if err != nil && unreachable(row.ctx, err) {
+		row.router.markDown()
+		return row.router.primary.QueryRow(row.ctx, row.sql, row.args...).Scan(dest...)
+	}
+	return err
+}
+
+// The fallbackRows type are rows read from the replica, which are read again from the primary when the
+// replica couldn't serve them. The error of the rows is only known once they are iterated, and they can
+// only be read again from the primary until the first row is returned.
+type fallbackRows struct {
+	pgx.Rows
+	ctx    context.Context
+	sql    string
+	args   []interface{}
+	router *replicaRouter
+	read   bool
+	err    error
+}
+
+func (rows *fallbackRows) Next() bool {
+	if rows.Rows.Next() {
+		rows.read = true
+		return true
+	}
+	if rows.read || rows.Rows.Err() == nil || !unreachable(rows.ctx, rows.Rows.Err()) {
+		return false
+	}
+
+	rows.router.markDown()
+	rows.Rows.Close()
+	rows.read = true
+	primary, err := rows.router.primary.Query(rows.ctx, rows.sql, rows.args...)
+	if err != nil {
+		rows.err = err
+		return false
+	}
+	rows.Rows = primary
+	return rows.Rows.Next()
+}
+
+func (rows *fallbackRows) Err() error {
+	if rows.err != nil {
+		return rows.err
+	}
+	return rows.Rows.Err()
+}
+
+// isReadOnlyQuery reports whether the query, as generated by sqlc, only reads without locking rows, and
+// doesn't authenticate the requests.
+func isReadOnlyQuery(sql string) bool {
+	if !strings.HasPrefix(sql, "-- name: ") {
+		return false
+	}
+	if strings.Contains(sql, "FOR UPDATE") || strings.Contains(sql, "FOR NO KEY UPDATE") {
+		return false
+	}
+
+	name, _, _ := strings.Cut(strings.TrimPrefix(sql, "-- name: "), " ")
+	return isReadOnlyMethod(name) && !primaryQueries[name]
+}
+
+// isReadOnlyMethod reports whether the query of the name, e.g. the method of the store running it, only
+// reads.
+func isReadOnlyMethod(name string) bool {
+	for _, prefix := range readOnlyQueryPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// unreachable reports whether a query failed because the database couldn't be reached, rather than
+// because of the query or the cancellation of the request.
+func unreachable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) || pgconn.SafeToRetry(err)
+}
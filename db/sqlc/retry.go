@@ -0,0 +1,150 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"time"
+)
+
+// Codes of the postgres errors of the statements rolled back because of concurrent transactions, which
+// succeed once retried.
+const (
+	SerializationFailure = "40001"
+	DeadlockDetected     = "40P01"
+)
+
+// The RetryPolicy type sets how the queries and transactions of a `RetryStore` are timed out and
+// retried.
+// @property {time.Duration} QueryTimeout - how long each attempt of a query may take, there is no
+// timeout when 0.
+// @property {time.Duration} TxTimeout - how long each attempt of a transaction may take as a whole,
+// there is no timeout when 0.
+// @property {int} MaxRetries - how many times a call failing with a transient error is retried.
+// @property {time.Duration} Backoff - the longest wait before the first retry, doubled for each of the
+// next ones until MaxBackoff. The wait is drawn at random below it, so that the transactions conflicting
+// with each other don't retry in lockstep.
+// @property {time.Duration} MaxBackoff - the longest wait before a retry, the backoff isn't capped when 0.
+type RetryPolicy struct {
+	QueryTimeout time.Duration
+	TxTimeout    time.Duration
+	MaxRetries   int
+	Backoff      time.Duration
+	MaxBackoff   time.Duration
+}
+
+// The RetryStore type times out and retries the calls to the store according to its policy. Each call
+// is a query, or a transaction that is retried as a whole. A call is retried when it failed with a
+// transient error that left nothing applied: a serialization failure or a deadlock, which roll the
+// statement back, or a database that couldn't be reached before the call was sent. The read-only
+// queries are also retried when the connection was lost or the attempt timed out, which may not be the
+// case of writes that could have been applied. The retries are counted in DBRetries.
+type RetryStore struct {
+	Store
+	policy RetryPolicy
+	sleep  func(ctx context.Context, d time.Duration) error
+}
+
+// The function creates a store timing out and retrying the calls to `store` according to `policy`.
+func NewRetryStore(store Store, policy RetryPolicy) Store {
+	return &RetryStore{
+		Store:  store,
+		policy: policy,
+		sleep:  sleep,
+	}
+}
+
+// retry calls `fn` with a context timed out after `timeout`, again while it fails with a transient
+// error and the policy allows it. The context of the caller being done stops the retries.
+func retry[T any](ctx context.Context, store *RetryStore, method string, timeout time.Duration, fn func(ctx context.Context) (T, error)) (T, error) {
+	for retries := 0; ; retries++ {
+		result, timedOut, err := attempt(ctx, timeout, fn)
+		if err == nil || retries >= store.policy.MaxRetries || ctx.Err() != nil || !retryable(method, err, timedOut) {
+			return result, err
+		}
+
+		DBRetries.WithLabelValues(method).Inc()
+		if err := store.sleep(ctx, store.backoff(retries)); err != nil {
+			return result, err
+		}
+	}
+}
+
+// retryQuery retries a query, timing out each attempt after the query timeout.
+func retryQuery[T any](ctx context.Context, store *RetryStore, method string, fn func(ctx context.Context) (T, error)) (T, error) {
+	return retry(ctx, store, method, store.policy.QueryTimeout, fn)
+}
+
+// retryExec retries a query that only returns an error.
+func retryExec(ctx context.Context, store *RetryStore, method string, fn func(ctx context.Context) error) error {
+	_, err := retryQuery(ctx, store, method, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, fn(ctx)
+	})
+	return err
+}
+
+// retryTx retries a transaction as a whole, timing out each attempt after the transaction
+// timeout.
+func retryTx[T any](ctx context.Context, store *RetryStore, method string, fn func(ctx context.Context) (T, error)) (T, error) {
+	return retry(ctx, store, method, store.policy.TxTimeout, fn)
+}
+
+// attempt calls `fn` with a context timed out after `timeout`, reporting whether it failed because the
+// attempt timed out rather than the context of the caller.
+func attempt[T any](ctx context.Context, timeout time.Duration, fn func(ctx context.Context) (T, error)) (T, bool, error) {
+	if timeout <= 0 {
+		result, err := fn(ctx)
+		return result, false, err
+	}
+
+	attemptCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	result, err := fn(attemptCtx)
+	return result, err != nil && ctx.Err() == nil && errors.Is(attemptCtx.Err(), context.DeadlineExceeded), err
+}
+
+// backoff returns how long to wait before the retry following `retries` retries, drawn at random below
+// the backoff doubled as many times.
+func (store *RetryStore) backoff(retries int) time.Duration {
+	limit := store.policy.Backoff
+	for i := 0; i < retries && (store.policy.MaxBackoff <= 0 || limit < store.policy.MaxBackoff); i++ {
+		limit *= 2
+	}
+	if store.policy.MaxBackoff > 0 && limit > store.policy.MaxBackoff {
+		limit = store.policy.MaxBackoff
+	}
+	if limit <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(limit)))
+}
+
+// retryable reports whether a call to the method that failed with the error can be retried without
+// applying it twice.
+func retryable(method string, err error, timedOut bool) bool {
+	switch ErrorCode(err) {
+	case SerializationFailure, DeadlockDetected:
+		return true
+	}
+	if Unavailable(err) {
+		return true
+	}
+
+	var netErr net.Error
+	return isReadOnlyMethod(method) && (timedOut || errors.As(err, &netErr))
+}
+
+// sleep waits for the duration, or until the context is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,1707 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// The methods of the RetryStore wrap every method of the store, so that each query and transaction is
+// timed out and retried. The queries the SQL store runs in a transaction of their own, e.g. CreateUser,
+// are timed out like the transactions.
+
+func (store *RetryStore) TransferTx(ctx context.Context, arg TransferTxParams) (TransferTxResult, error) {
+	return retryTx(ctx, store, "TransferTx", func(ctx context.Context) (TransferTxResult, error) {
+		return store.Store.TransferTx(ctx, arg)
+	})
+}
+
+func (store *RetryStore) BatchTransferTx(ctx context.Context, arg BatchTransferTxParams) ([]BatchTransferTxItem, error) {
+	return retryTx(ctx, store, "BatchTransferTx", func(ctx context.Context) ([]BatchTransferTxItem, error) {
+		return store.Store.BatchTransferTx(ctx, arg)
+	})
+}
+
+func (store *RetryStore) ExchangeTx(ctx context.Context, arg ExchangeTxParams) (ExchangeTxResult, error) {
+	return retryTx(ctx, store, "ExchangeTx", func(ctx context.Context) (ExchangeTxResult, error) {
+		return store.Store.ExchangeTx(ctx, arg)
+	})
+}
+
+func (store *RetryStore) ConvertAccountTx(ctx context.Context, arg ConvertAccountTxParams) (ConvertAccountTxResult, error) {
+	return retryTx(ctx, store, "ConvertAccountTx", func(ctx context.Context) (ConvertAccountTxResult, error) {
+		return store.Store.ConvertAccountTx(ctx, arg)
+	})
+}
+
+func (store *RetryStore) ProjectEventsTx(ctx context.Context, arg ProjectEventsTxParams) (int, error) {
+	return retryTx(ctx, store, "ProjectEventsTx", func(ctx context.Context) (int, error) {
+		return store.Store.ProjectEventsTx(ctx, arg)
+	})
+}
+
+func (store *RetryStore) CapitalizeInterestTx(ctx context.Context, arg CapitalizeInterestTxParams) (BatchTxResult, error) {
+	return retryTx(ctx, store, "CapitalizeInterestTx", func(ctx context.Context) (BatchTxResult, error) {
+		return store.Store.CapitalizeInterestTx(ctx, arg)
+	})
+}
+
+func (store *RetryStore) ChargeMonthlyFeeTx(ctx context.Context, arg ChargeMonthlyFeeTxParams) (BatchTxResult, error) {
+	return retryTx(ctx, store, "ChargeMonthlyFeeTx", func(ctx context.Context) (BatchTxResult, error) {
+		return store.Store.ChargeMonthlyFeeTx(ctx, arg)
+	})
+}
+
+func (store *RetryStore) SnapshotBalancesTx(ctx context.Context, arg SnapshotBalancesTxParams) (BatchTxResult, error) {
+	return retryTx(ctx, store, "SnapshotBalancesTx", func(ctx context.Context) (BatchTxResult, error) {
+		return store.Store.SnapshotBalancesTx(ctx, arg)
+	})
+}
+
+func (store *RetryStore) HoldTransferTx(ctx context.Context, arg HoldTransferTxParams) (HoldTransferTxResult, error) {
+	return retryTx(ctx, store, "HoldTransferTx", func(ctx context.Context) (HoldTransferTxResult, error) {
+		return store.Store.HoldTransferTx(ctx, arg)
+	})
+}
+
+func (store *RetryStore) DecideTransferReviewTx(ctx context.Context, arg DecideTransferReviewTxParams) (DecideTransferReviewTxResult, error) {
+	return retryTx(ctx, store, "DecideTransferReviewTx", func(ctx context.Context) (DecideTransferReviewTxResult, error) {
+		return store.Store.DecideTransferReviewTx(ctx, arg)
+	})
+}
+
+func (store *RetryStore) ReconcileLedgerTx(ctx context.Context) (ReconcileLedgerTxResult, error) {
+	return retryTx(ctx, store, "ReconcileLedgerTx", func(ctx context.Context) (ReconcileLedgerTxResult, error) {
+		return store.Store.ReconcileLedgerTx(ctx)
+	})
+}
+
+func (store *RetryStore) AdjustAccountBalanceTx(ctx context.Context, arg AdjustAccountBalanceTxParams) (TransferTxResult, error) {
+	return retryTx(ctx, store, "AdjustAccountBalanceTx", func(ctx context.Context) (TransferTxResult, error) {
+		return store.Store.AdjustAccountBalanceTx(ctx, arg)
+	})
+}
+
+func (store *RetryStore) ImportEntriesTx(ctx context.Context, arg ImportEntriesTxParams) (ImportEntriesTxResult, error) {
+	return retryTx(ctx, store, "ImportEntriesTx", func(ctx context.Context) (ImportEntriesTxResult, error) {
+		return store.Store.ImportEntriesTx(ctx, arg)
+	})
+}
+
+func (store *RetryStore) AcceptPaymentRequestTx(ctx context.Context, arg AcceptPaymentRequestTxParams) (AcceptPaymentRequestTxResult, error) {
+	return retryTx(ctx, store, "AcceptPaymentRequestTx", func(ctx context.Context) (AcceptPaymentRequestTxResult, error) {
+		return store.Store.AcceptPaymentRequestTx(ctx, arg)
+	})
+}
+
+func (store *RetryStore) PayPaymentLinkTx(ctx context.Context, arg PayPaymentLinkTxParams) (PayPaymentLinkTxResult, error) {
+	return retryTx(ctx, store, "PayPaymentLinkTx", func(ctx context.Context) (PayPaymentLinkTxResult, error) {
+		return store.Store.PayPaymentLinkTx(ctx, arg)
+	})
+}
+
+func (store *RetryStore) CreatePendingTransferTx(ctx context.Context, arg CreatePendingTransferTxParams) (PendingTransfer, error) {
+	return retryTx(ctx, store, "CreatePendingTransferTx", func(ctx context.Context) (PendingTransfer, error) {
+		return store.Store.CreatePendingTransferTx(ctx, arg)
+	})
+}
+
+func (store *RetryStore) ApprovePendingTransferTx(ctx context.Context, arg ApprovePendingTransferTxParams) (ApprovePendingTransferTxResult, error) {
+	return retryTx(ctx, store, "ApprovePendingTransferTx", func(ctx context.Context) (ApprovePendingTransferTxResult, error) {
+		return store.Store.ApprovePendingTransferTx(ctx, arg)
+	})
+}
+
+func (store *RetryStore) PullMandateTx(ctx context.Context, arg PullMandateTxParams) (PullMandateTxResult, error) {
+	return retryTx(ctx, store, "PullMandateTx", func(ctx context.Context) (PullMandateTxResult, error) {
+		return store.Store.PullMandateTx(ctx, arg)
+	})
+}
+
+func (store *RetryStore) RunStandingOrderTx(ctx context.Context, arg RunStandingOrderTxParams) (RunStandingOrderTxResult, error) {
+	return retryTx(ctx, store, "RunStandingOrderTx", func(ctx context.Context) (RunStandingOrderTxResult, error) {
+		return store.Store.RunStandingOrderTx(ctx, arg)
+	})
+}
+
+func (store *RetryStore) InitiateExternalTransferTx(ctx context.Context, arg InitiateExternalTransferTxParams) (InitiateExternalTransferTxResult, error) {
+	return retryTx(ctx, store, "InitiateExternalTransferTx", func(ctx context.Context) (InitiateExternalTransferTxResult, error) {
+		return store.Store.InitiateExternalTransferTx(ctx, arg)
+	})
+}
+
+func (store *RetryStore) AdvanceExternalTransferTx(ctx context.Context, arg AdvanceExternalTransferTxParams) (AdvanceExternalTransferTxResult, error) {
+	return retryTx(ctx, store, "AdvanceExternalTransferTx", func(ctx context.Context) (AdvanceExternalTransferTxResult, error) {
+		return store.Store.AdvanceExternalTransferTx(ctx, arg)
+	})
+}
+
+func (store *RetryStore) ReleaseHoldTx(ctx context.Context, id int64) (Hold, error) {
+	return retryTx(ctx, store, "ReleaseHoldTx", func(ctx context.Context) (Hold, error) {
+		return store.Store.ReleaseHoldTx(ctx, id)
+	})
+}
+
+func (store *RetryStore) ExpireHoldsTx(ctx context.Context, expiresAt time.Time) ([]Hold, error) {
+	return retryTx(ctx, store, "ExpireHoldsTx", func(ctx context.Context) ([]Hold, error) {
+		return store.Store.ExpireHoldsTx(ctx, expiresAt)
+	})
+}
+
+func (store *RetryStore) AuthorizeCardTx(ctx context.Context, arg AuthorizeCardTxParams) (AuthorizeCardTxResult, error) {
+	return retryTx(ctx, store, "AuthorizeCardTx", func(ctx context.Context) (AuthorizeCardTxResult, error) {
+		return store.Store.AuthorizeCardTx(ctx, arg)
+	})
+}
+
+func (store *RetryStore) CaptureCardHoldTx(ctx context.Context, arg CaptureCardHoldTxParams) (CaptureCardHoldTxResult, error) {
+	return retryTx(ctx, store, "CaptureCardHoldTx", func(ctx context.Context) (CaptureCardHoldTxResult, error) {
+		return store.Store.CaptureCardHoldTx(ctx, arg)
+	})
+}
+
+func (store *RetryStore) DepositChequeTx(ctx context.Context, arg DepositChequeTxParams) (DepositChequeTxResult, error) {
+	return retryTx(ctx, store, "DepositChequeTx", func(ctx context.Context) (DepositChequeTxResult, error) {
+		return store.Store.DepositChequeTx(ctx, arg)
+	})
+}
+
+func (store *RetryStore) ClearChequeDepositTx(ctx context.Context, id int64) (ChequeDeposit, error) {
+	return retryTx(ctx, store, "ClearChequeDepositTx", func(ctx context.Context) (ChequeDeposit, error) {
+		return store.Store.ClearChequeDepositTx(ctx, id)
+	})
+}
+
+func (store *RetryStore) BounceChequeDepositTx(ctx context.Context, id int64) (BounceChequeDepositTxResult, error) {
+	return retryTx(ctx, store, "BounceChequeDepositTx", func(ctx context.Context) (BounceChequeDepositTxResult, error) {
+		return store.Store.BounceChequeDepositTx(ctx, id)
+	})
+}
+
+func (store *RetryStore) RecordLoginFailureTx(ctx context.Context, arg RecordLoginFailureTxParams) (RecordLoginFailureTxResult, error) {
+	return retryTx(ctx, store, "RecordLoginFailureTx", func(ctx context.Context) (RecordLoginFailureTxResult, error) {
+		return store.Store.RecordLoginFailureTx(ctx, arg)
+	})
+}
+
+func (store *RetryStore) UnlockUserTx(ctx context.Context, username string) error {
+	_, err := retryTx(ctx, store, "UnlockUserTx", func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, store.Store.UnlockUserTx(ctx, username)
+	})
+	return err
+}
+
+func (store *RetryStore) CreateUserWithRoleTx(ctx context.Context, arg CreateUserParams, role string) (User, error) {
+	return retryTx(ctx, store, "CreateUserWithRoleTx", func(ctx context.Context) (User, error) {
+		return store.Store.CreateUserWithRoleTx(ctx, arg, role)
+	})
+}
+
+func (store *RetryStore) CreateUserWithIdentityTx(ctx context.Context, arg CreateUserWithIdentityTxParams) (User, error) {
+	return retryTx(ctx, store, "CreateUserWithIdentityTx", func(ctx context.Context) (User, error) {
+		return store.Store.CreateUserWithIdentityTx(ctx, arg)
+	})
+}
+
+func (store *RetryStore) DeleteUserDataTx(ctx context.Context, username string) (DeleteUserDataTxResult, error) {
+	return retryTx(ctx, store, "DeleteUserDataTx", func(ctx context.Context) (DeleteUserDataTxResult, error) {
+		return store.Store.DeleteUserDataTx(ctx, username)
+	})
+}
+
+func (store *RetryStore) AcceptAccountMember(ctx context.Context, arg AcceptAccountMemberParams) (AccountMember, error) {
+	return retryQuery(ctx, store, "AcceptAccountMember", func(ctx context.Context) (AccountMember, error) {
+		return store.Store.AcceptAccountMember(ctx, arg)
+	})
+}
+
+func (store *RetryStore) AcceptPaymentRequest(ctx context.Context, arg AcceptPaymentRequestParams) (PaymentRequest, error) {
+	return retryQuery(ctx, store, "AcceptPaymentRequest", func(ctx context.Context) (PaymentRequest, error) {
+		return store.Store.AcceptPaymentRequest(ctx, arg)
+	})
+}
+
+func (store *RetryStore) AccountHasHistory(ctx context.Context, accountID int64) (bool, error) {
+	return retryQuery(ctx, store, "AccountHasHistory", func(ctx context.Context) (bool, error) {
+		return store.Store.AccountHasHistory(ctx, accountID)
+	})
+}
+
+func (store *RetryStore) AcquireLeaderLease(ctx context.Context, arg AcquireLeaderLeaseParams) (LeaderLease, error) {
+	return retryQuery(ctx, store, "AcquireLeaderLease", func(ctx context.Context) (LeaderLease, error) {
+		return store.Store.AcquireLeaderLease(ctx, arg)
+	})
+}
+
+func (store *RetryStore) AddAccountBalance(ctx context.Context, arg AddAccountBalanceParams) (Account, error) {
+	return retryQuery(ctx, store, "AddAccountBalance", func(ctx context.Context) (Account, error) {
+		return store.Store.AddAccountBalance(ctx, arg)
+	})
+}
+
+func (store *RetryStore) AddAccountCounterpartyActivity(ctx context.Context, arg AddAccountCounterpartyActivityParams) error {
+	return retryExec(ctx, store, "AddAccountCounterpartyActivity", func(ctx context.Context) error {
+		return store.Store.AddAccountCounterpartyActivity(ctx, arg)
+	})
+}
+
+func (store *RetryStore) AddAccountDailyActivity(ctx context.Context, arg AddAccountDailyActivityParams) error {
+	return retryExec(ctx, store, "AddAccountDailyActivity", func(ctx context.Context) error {
+		return store.Store.AddAccountDailyActivity(ctx, arg)
+	})
+}
+
+func (store *RetryStore) AddAccountDailyVolume(ctx context.Context, arg AddAccountDailyVolumeParams) error {
+	return retryExec(ctx, store, "AddAccountDailyVolume", func(ctx context.Context) error {
+		return store.Store.AddAccountDailyVolume(ctx, arg)
+	})
+}
+
+func (store *RetryStore) AddAccountHeldBalance(ctx context.Context, arg AddAccountHeldBalanceParams) (Account, error) {
+	return retryQuery(ctx, store, "AddAccountHeldBalance", func(ctx context.Context) (Account, error) {
+		return store.Store.AddAccountHeldBalance(ctx, arg)
+	})
+}
+
+func (store *RetryStore) AddRouteRequestVolume(ctx context.Context, arg AddRouteRequestVolumeParams) error {
+	return retryExec(ctx, store, "AddRouteRequestVolume", func(ctx context.Context) error {
+		return store.Store.AddRouteRequestVolume(ctx, arg)
+	})
+}
+
+func (store *RetryStore) AddUserOverviewAccountCount(ctx context.Context, arg AddUserOverviewAccountCountParams) error {
+	return retryExec(ctx, store, "AddUserOverviewAccountCount", func(ctx context.Context) error {
+		return store.Store.AddUserOverviewAccountCount(ctx, arg)
+	})
+}
+
+func (store *RetryStore) AnonymizeUser(ctx context.Context, username string) (User, error) {
+	return retryQuery(ctx, store, "AnonymizeUser", func(ctx context.Context) (User, error) {
+		return store.Store.AnonymizeUser(ctx, username)
+	})
+}
+
+func (store *RetryStore) AnonymizeUserOverview(ctx context.Context, username string) error {
+	return retryExec(ctx, store, "AnonymizeUserOverview", func(ctx context.Context) error {
+		return store.Store.AnonymizeUserOverview(ctx, username)
+	})
+}
+
+func (store *RetryStore) ApprovePendingTransfer(ctx context.Context, arg ApprovePendingTransferParams) (PendingTransfer, error) {
+	return retryQuery(ctx, store, "ApprovePendingTransfer", func(ctx context.Context) (PendingTransfer, error) {
+		return store.Store.ApprovePendingTransfer(ctx, arg)
+	})
+}
+
+func (store *RetryStore) AssignTransferReview(ctx context.Context, arg AssignTransferReviewParams) (TransferReview, error) {
+	return retryQuery(ctx, store, "AssignTransferReview", func(ctx context.Context) (TransferReview, error) {
+		return store.Store.AssignTransferReview(ctx, arg)
+	})
+}
+
+func (store *RetryStore) BounceChequeDeposit(ctx context.Context, arg BounceChequeDepositParams) (ChequeDeposit, error) {
+	return retryQuery(ctx, store, "BounceChequeDeposit", func(ctx context.Context) (ChequeDeposit, error) {
+		return store.Store.BounceChequeDeposit(ctx, arg)
+	})
+}
+
+func (store *RetryStore) CancelJob(ctx context.Context, id uuid.UUID) (Job, error) {
+	return retryQuery(ctx, store, "CancelJob", func(ctx context.Context) (Job, error) {
+		return store.Store.CancelJob(ctx, id)
+	})
+}
+
+func (store *RetryStore) CancelPaymentLink(ctx context.Context, id int64) (PaymentLink, error) {
+	return retryQuery(ctx, store, "CancelPaymentLink", func(ctx context.Context) (PaymentLink, error) {
+		return store.Store.CancelPaymentLink(ctx, id)
+	})
+}
+
+func (store *RetryStore) CancelStandingOrder(ctx context.Context, id int64) (StandingOrder, error) {
+	return retryQuery(ctx, store, "CancelStandingOrder", func(ctx context.Context) (StandingOrder, error) {
+		return store.Store.CancelStandingOrder(ctx, id)
+	})
+}
+
+func (store *RetryStore) CancelUserDeletion(ctx context.Context, username string) (UserDeletion, error) {
+	return retryQuery(ctx, store, "CancelUserDeletion", func(ctx context.Context) (UserDeletion, error) {
+		return store.Store.CancelUserDeletion(ctx, username)
+	})
+}
+
+func (store *RetryStore) CancelUserPaymentLinks(ctx context.Context, merchant string) (int64, error) {
+	return retryQuery(ctx, store, "CancelUserPaymentLinks", func(ctx context.Context) (int64, error) {
+		return store.Store.CancelUserPaymentLinks(ctx, merchant)
+	})
+}
+
+func (store *RetryStore) CancelUserStandingOrders(ctx context.Context, owner string) (int64, error) {
+	return retryQuery(ctx, store, "CancelUserStandingOrders", func(ctx context.Context) (int64, error) {
+		return store.Store.CancelUserStandingOrders(ctx, owner)
+	})
+}
+
+func (store *RetryStore) CaptureHold(ctx context.Context, arg CaptureHoldParams) (Hold, error) {
+	return retryQuery(ctx, store, "CaptureHold", func(ctx context.Context) (Hold, error) {
+		return store.Store.CaptureHold(ctx, arg)
+	})
+}
+
+func (store *RetryStore) ClaimNotificationDeliveries(ctx context.Context, arg ClaimNotificationDeliveriesParams) ([]NotificationDelivery, error) {
+	return retryQuery(ctx, store, "ClaimNotificationDeliveries", func(ctx context.Context) ([]NotificationDelivery, error) {
+		return store.Store.ClaimNotificationDeliveries(ctx, arg)
+	})
+}
+
+func (store *RetryStore) ClearChequeDeposit(ctx context.Context, id int64) (ChequeDeposit, error) {
+	return retryQuery(ctx, store, "ClearChequeDeposit", func(ctx context.Context) (ChequeDeposit, error) {
+		return store.Store.ClearChequeDeposit(ctx, id)
+	})
+}
+
+func (store *RetryStore) CloseAccountVersion(ctx context.Context, accountID int64) error {
+	return retryExec(ctx, store, "CloseAccountVersion", func(ctx context.Context) error {
+		return store.Store.CloseAccountVersion(ctx, accountID)
+	})
+}
+
+func (store *RetryStore) CloseOwnerAccounts(ctx context.Context, owner string) ([]Account, error) {
+	return retryQuery(ctx, store, "CloseOwnerAccounts", func(ctx context.Context) ([]Account, error) {
+		return store.Store.CloseOwnerAccounts(ctx, owner)
+	})
+}
+
+func (store *RetryStore) CompleteBatchRun(ctx context.Context, arg CompleteBatchRunParams) error {
+	return retryExec(ctx, store, "CompleteBatchRun", func(ctx context.Context) error {
+		return store.Store.CompleteBatchRun(ctx, arg)
+	})
+}
+
+func (store *RetryStore) CompleteJob(ctx context.Context, arg CompleteJobParams) (Job, error) {
+	return retryQuery(ctx, store, "CompleteJob", func(ctx context.Context) (Job, error) {
+		return store.Store.CompleteJob(ctx, arg)
+	})
+}
+
+func (store *RetryStore) CompleteUserDeletion(ctx context.Context, username string) (UserDeletion, error) {
+	return retryQuery(ctx, store, "CompleteUserDeletion", func(ctx context.Context) (UserDeletion, error) {
+		return store.Store.CompleteUserDeletion(ctx, username)
+	})
+}
+
+func (store *RetryStore) CountActiveSigningKeys(ctx context.Context, username string) (int64, error) {
+	return retryQuery(ctx, store, "CountActiveSigningKeys", func(ctx context.Context) (int64, error) {
+		return store.Store.CountActiveSigningKeys(ctx, username)
+	})
+}
+
+func (store *RetryStore) CountClientLoginFailures(ctx context.Context, arg CountClientLoginFailuresParams) (int64, error) {
+	return retryQuery(ctx, store, "CountClientLoginFailures", func(ctx context.Context) (int64, error) {
+		return store.Store.CountClientLoginFailures(ctx, arg)
+	})
+}
+
+func (store *RetryStore) CountEntries(ctx context.Context, accountID int64) (int64, error) {
+	return retryQuery(ctx, store, "CountEntries", func(ctx context.Context) (int64, error) {
+		return store.Store.CountEntries(ctx, accountID)
+	})
+}
+
+func (store *RetryStore) CountEntriesInRange(ctx context.Context, arg CountEntriesInRangeParams) (int64, error) {
+	return retryQuery(ctx, store, "CountEntriesInRange", func(ctx context.Context) (int64, error) {
+		return store.Store.CountEntriesInRange(ctx, arg)
+	})
+}
+
+func (store *RetryStore) CountLoginFailures(ctx context.Context, arg CountLoginFailuresParams) (int64, error) {
+	return retryQuery(ctx, store, "CountLoginFailures", func(ctx context.Context) (int64, error) {
+		return store.Store.CountLoginFailures(ctx, arg)
+	})
+}
+
+func (store *RetryStore) CountOwnerPendingItems(ctx context.Context, owner string) (CountOwnerPendingItemsRow, error) {
+	return retryQuery(ctx, store, "CountOwnerPendingItems", func(ctx context.Context) (CountOwnerPendingItemsRow, error) {
+		return store.Store.CountOwnerPendingItems(ctx, owner)
+	})
+}
+
+func (store *RetryStore) CountUserSessions(ctx context.Context, arg CountUserSessionsParams) (CountUserSessionsRow, error) {
+	return retryQuery(ctx, store, "CountUserSessions", func(ctx context.Context) (CountUserSessionsRow, error) {
+		return store.Store.CountUserSessions(ctx, arg)
+	})
+}
+
+func (store *RetryStore) CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error) {
+	return retryTx(ctx, store, "CreateAccount", func(ctx context.Context) (Account, error) {
+		return store.Store.CreateAccount(ctx, arg)
+	})
+}
+
+func (store *RetryStore) CreateAccountMember(ctx context.Context, arg CreateAccountMemberParams) (AccountMember, error) {
+	return retryQuery(ctx, store, "CreateAccountMember", func(ctx context.Context) (AccountMember, error) {
+		return store.Store.CreateAccountMember(ctx, arg)
+	})
+}
+
+func (store *RetryStore) CreateAccountVersion(ctx context.Context, arg CreateAccountVersionParams) (AccountHistory, error) {
+	return retryQuery(ctx, store, "CreateAccountVersion", func(ctx context.Context) (AccountHistory, error) {
+		return store.Store.CreateAccountVersion(ctx, arg)
+	})
+}
+
+func (store *RetryStore) CreateAuditEntry(ctx context.Context, arg CreateAuditEntryParams) (AuditEntry, error) {
+	return retryQuery(ctx, store, "CreateAuditEntry", func(ctx context.Context) (AuditEntry, error) {
+		return store.Store.CreateAuditEntry(ctx, arg)
+	})
+}
+
+func (store *RetryStore) CreateBalanceSnapshot(ctx context.Context, arg CreateBalanceSnapshotParams) error {
+	return retryExec(ctx, store, "CreateBalanceSnapshot", func(ctx context.Context) error {
+		return store.Store.CreateBalanceSnapshot(ctx, arg)
+	})
+}
+
+func (store *RetryStore) CreateBankParameter(ctx context.Context, arg CreateBankParameterParams) (BankParameter, error) {
+	return retryQuery(ctx, store, "CreateBankParameter", func(ctx context.Context) (BankParameter, error) {
+		return store.Store.CreateBankParameter(ctx, arg)
+	})
+}
+
+func (store *RetryStore) CreateBeneficiary(ctx context.Context, arg CreateBeneficiaryParams) (Beneficiary, error) {
+	return retryQuery(ctx, store, "CreateBeneficiary", func(ctx context.Context) (Beneficiary, error) {
+		return store.Store.CreateBeneficiary(ctx, arg)
+	})
+}
+
+func (store *RetryStore) CreateCard(ctx context.Context, arg CreateCardParams) (Card, error) {
+	return retryQuery(ctx, store, "CreateCard", func(ctx context.Context) (Card, error) {
+		return store.Store.CreateCard(ctx, arg)
+	})
+}
+
+func (store *RetryStore) CreateChequeDeposit(ctx context.Context, arg CreateChequeDepositParams) (ChequeDeposit, error) {
+	return retryQuery(ctx, store, "CreateChequeDeposit", func(ctx context.Context) (ChequeDeposit, error) {
+		return store.Store.CreateChequeDeposit(ctx, arg)
+	})
+}
+
+func (store *RetryStore) CreateEntry(ctx context.Context, arg CreateEntryParams) (Entry, error) {
+	return retryQuery(ctx, store, "CreateEntry", func(ctx context.Context) (Entry, error) {
+		return store.Store.CreateEntry(ctx, arg)
+	})
+}
+
+func (store *RetryStore) CreateEvent(ctx context.Context, arg CreateEventParams) (Event, error) {
+	return retryQuery(ctx, store, "CreateEvent", func(ctx context.Context) (Event, error) {
+		return store.Store.CreateEvent(ctx, arg)
+	})
+}
+
+func (store *RetryStore) CreateExternalTransfer(ctx context.Context, arg CreateExternalTransferParams) (ExternalTransfer, error) {
+	return retryQuery(ctx, store, "CreateExternalTransfer", func(ctx context.Context) (ExternalTransfer, error) {
+		return store.Store.CreateExternalTransfer(ctx, arg)
+	})
+}
+
+func (store *RetryStore) CreateHistoricalEntry(ctx context.Context, arg CreateHistoricalEntryParams) (Entry, error) {
+	return retryQuery(ctx, store, "CreateHistoricalEntry", func(ctx context.Context) (Entry, error) {
+		return store.Store.CreateHistoricalEntry(ctx, arg)
+	})
+}
+
+func (store *RetryStore) CreateHold(ctx context.Context, arg CreateHoldParams) (Hold, error) {
+	return retryQuery(ctx, store, "CreateHold", func(ctx context.Context) (Hold, error) {
+		return store.Store.CreateHold(ctx, arg)
+	})
+}
+
+func (store *RetryStore) CreateJob(ctx context.Context, arg CreateJobParams) (Job, error) {
+	return retryQuery(ctx, store, "CreateJob", func(ctx context.Context) (Job, error) {
+		return store.Store.CreateJob(ctx, arg)
+	})
+}
+
+func (store *RetryStore) CreateLoginFailure(ctx context.Context, arg CreateLoginFailureParams) error {
+	return retryExec(ctx, store, "CreateLoginFailure", func(ctx context.Context) error {
+		return store.Store.CreateLoginFailure(ctx, arg)
+	})
+}
+
+func (store *RetryStore) CreateMandate(ctx context.Context, arg CreateMandateParams) (Mandate, error) {
+	return retryQuery(ctx, store, "CreateMandate", func(ctx context.Context) (Mandate, error) {
+		return store.Store.CreateMandate(ctx, arg)
+	})
+}
+
+func (store *RetryStore) CreateNotification(ctx context.Context, arg CreateNotificationParams) error {
+	return retryExec(ctx, store, "CreateNotification", func(ctx context.Context) error {
+		return store.Store.CreateNotification(ctx, arg)
+	})
+}
+
+func (store *RetryStore) CreateNotificationDelivery(ctx context.Context, arg CreateNotificationDeliveryParams) error {
+	return retryExec(ctx, store, "CreateNotificationDelivery", func(ctx context.Context) error {
+		return store.Store.CreateNotificationDelivery(ctx, arg)
+	})
+}
+
+func (store *RetryStore) CreateOrganization(ctx context.Context, arg CreateOrganizationParams) (Organization, error) {
+	return retryQuery(ctx, store, "CreateOrganization", func(ctx context.Context) (Organization, error) {
+		return store.Store.CreateOrganization(ctx, arg)
+	})
+}
+
+func (store *RetryStore) CreatePaymentLink(ctx context.Context, arg CreatePaymentLinkParams) (PaymentLink, error) {
+	return retryQuery(ctx, store, "CreatePaymentLink", func(ctx context.Context) (PaymentLink, error) {
+		return store.Store.CreatePaymentLink(ctx, arg)
+	})
+}
+
+func (store *RetryStore) CreatePaymentRequest(ctx context.Context, arg CreatePaymentRequestParams) (PaymentRequest, error) {
+	return retryTx(ctx, store, "CreatePaymentRequest", func(ctx context.Context) (PaymentRequest, error) {
+		return store.Store.CreatePaymentRequest(ctx, arg)
+	})
+}
+
+func (store *RetryStore) CreatePendingTransfer(ctx context.Context, arg CreatePendingTransferParams) (PendingTransfer, error) {
+	return retryQuery(ctx, store, "CreatePendingTransfer", func(ctx context.Context) (PendingTransfer, error) {
+		return store.Store.CreatePendingTransfer(ctx, arg)
+	})
+}
+
+func (store *RetryStore) CreateProcessedTask(ctx context.Context, arg CreateProcessedTaskParams) error {
+	return retryExec(ctx, store, "CreateProcessedTask", func(ctx context.Context) error {
+		return store.Store.CreateProcessedTask(ctx, arg)
+	})
+}
+
+func (store *RetryStore) CreateQueuedTransfer(ctx context.Context, arg CreateQueuedTransferParams) (QueuedTransfer, error) {
+	return retryTx(ctx, store, "CreateQueuedTransfer", func(ctx context.Context) (QueuedTransfer, error) {
+		return store.Store.CreateQueuedTransfer(ctx, arg)
+	})
+}
+
+func (store *RetryStore) CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error) {
+	return retryTx(ctx, store, "CreateSession", func(ctx context.Context) (Session, error) {
+		return store.Store.CreateSession(ctx, arg)
+	})
+}
+
+func (store *RetryStore) CreateSigningKey(ctx context.Context, arg CreateSigningKeyParams) (SigningKey, error) {
+	return retryQuery(ctx, store, "CreateSigningKey", func(ctx context.Context) (SigningKey, error) {
+		return store.Store.CreateSigningKey(ctx, arg)
+	})
+}
+
+func (store *RetryStore) CreateStandingOrder(ctx context.Context, arg CreateStandingOrderParams) (StandingOrder, error) {
+	return retryQuery(ctx, store, "CreateStandingOrder", func(ctx context.Context) (StandingOrder, error) {
+		return store.Store.CreateStandingOrder(ctx, arg)
+	})
+}
+
+func (store *RetryStore) CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transfer, error) {
+	return retryQuery(ctx, store, "CreateTransfer", func(ctx context.Context) (Transfer, error) {
+		return store.Store.CreateTransfer(ctx, arg)
+	})
+}
+
+func (store *RetryStore) CreateTransferReview(ctx context.Context, arg CreateTransferReviewParams) (TransferReview, error) {
+	return retryQuery(ctx, store, "CreateTransferReview", func(ctx context.Context) (TransferReview, error) {
+		return store.Store.CreateTransferReview(ctx, arg)
+	})
+}
+
+func (store *RetryStore) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
+	return retryTx(ctx, store, "CreateUser", func(ctx context.Context) (User, error) {
+		return store.Store.CreateUser(ctx, arg)
+	})
+}
+
+func (store *RetryStore) CreateUserIdentity(ctx context.Context, arg CreateUserIdentityParams) (UserIdentity, error) {
+	return retryQuery(ctx, store, "CreateUserIdentity", func(ctx context.Context) (UserIdentity, error) {
+		return store.Store.CreateUserIdentity(ctx, arg)
+	})
+}
+
+func (store *RetryStore) DecideTransferReview(ctx context.Context, arg DecideTransferReviewParams) (TransferReview, error) {
+	return retryQuery(ctx, store, "DecideTransferReview", func(ctx context.Context) (TransferReview, error) {
+		return store.Store.DecideTransferReview(ctx, arg)
+	})
+}
+
+func (store *RetryStore) DeclinePaymentRequest(ctx context.Context, id int64) (PaymentRequest, error) {
+	return retryQuery(ctx, store, "DeclinePaymentRequest", func(ctx context.Context) (PaymentRequest, error) {
+		return store.Store.DeclinePaymentRequest(ctx, id)
+	})
+}
+
+func (store *RetryStore) DeleteAccount(ctx context.Context, id int64) error {
+	_, err := retryTx(ctx, store, "DeleteAccount", func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, store.Store.DeleteAccount(ctx, id)
+	})
+	return err
+}
+
+func (store *RetryStore) DeleteAccountAlert(ctx context.Context, accountID int64) error {
+	return retryExec(ctx, store, "DeleteAccountAlert", func(ctx context.Context) error {
+		return store.Store.DeleteAccountAlert(ctx, accountID)
+	})
+}
+
+func (store *RetryStore) DeleteAccountCounterpartyActivity(ctx context.Context, accountID int64) error {
+	return retryExec(ctx, store, "DeleteAccountCounterpartyActivity", func(ctx context.Context) error {
+		return store.Store.DeleteAccountCounterpartyActivity(ctx, accountID)
+	})
+}
+
+func (store *RetryStore) DeleteAccountDailyActivity(ctx context.Context, accountID int64) error {
+	return retryExec(ctx, store, "DeleteAccountDailyActivity", func(ctx context.Context) error {
+		return store.Store.DeleteAccountDailyActivity(ctx, accountID)
+	})
+}
+
+func (store *RetryStore) DeleteAccountMember(ctx context.Context, arg DeleteAccountMemberParams) error {
+	return retryExec(ctx, store, "DeleteAccountMember", func(ctx context.Context) error {
+		return store.Store.DeleteAccountMember(ctx, arg)
+	})
+}
+
+func (store *RetryStore) DeleteAccountOverview(ctx context.Context, accountID int64) (string, error) {
+	return retryQuery(ctx, store, "DeleteAccountOverview", func(ctx context.Context) (string, error) {
+		return store.Store.DeleteAccountOverview(ctx, accountID)
+	})
+}
+
+func (store *RetryStore) DeleteBeneficiary(ctx context.Context, id int64) error {
+	return retryExec(ctx, store, "DeleteBeneficiary", func(ctx context.Context) error {
+		return store.Store.DeleteBeneficiary(ctx, id)
+	})
+}
+
+func (store *RetryStore) DeleteBudget(ctx context.Context, arg DeleteBudgetParams) error {
+	return retryExec(ctx, store, "DeleteBudget", func(ctx context.Context) error {
+		return store.Store.DeleteBudget(ctx, arg)
+	})
+}
+
+func (store *RetryStore) DeleteClientLoginFailures(ctx context.Context, arg DeleteClientLoginFailuresParams) (int64, error) {
+	return retryQuery(ctx, store, "DeleteClientLoginFailures", func(ctx context.Context) (int64, error) {
+		return store.Store.DeleteClientLoginFailures(ctx, arg)
+	})
+}
+
+func (store *RetryStore) DeleteLoginFailures(ctx context.Context, username string) (int64, error) {
+	return retryQuery(ctx, store, "DeleteLoginFailures", func(ctx context.Context) (int64, error) {
+		return store.Store.DeleteLoginFailures(ctx, username)
+	})
+}
+
+func (store *RetryStore) DeleteLoginLockouts(ctx context.Context, username string) (int64, error) {
+	return retryQuery(ctx, store, "DeleteLoginLockouts", func(ctx context.Context) (int64, error) {
+		return store.Store.DeleteLoginLockouts(ctx, username)
+	})
+}
+
+func (store *RetryStore) DeleteNotificationPreferences(ctx context.Context, username string) error {
+	return retryExec(ctx, store, "DeleteNotificationPreferences", func(ctx context.Context) error {
+		return store.Store.DeleteNotificationPreferences(ctx, username)
+	})
+}
+
+func (store *RetryStore) DeleteOwnerBeneficiaries(ctx context.Context, owner string) error {
+	return retryExec(ctx, store, "DeleteOwnerBeneficiaries", func(ctx context.Context) error {
+		return store.Store.DeleteOwnerBeneficiaries(ctx, owner)
+	})
+}
+
+func (store *RetryStore) DeleteStaleAccountDailyVolume(ctx context.Context) error {
+	return retryExec(ctx, store, "DeleteStaleAccountDailyVolume", func(ctx context.Context) error {
+		return store.Store.DeleteStaleAccountDailyVolume(ctx)
+	})
+}
+
+func (store *RetryStore) DeleteUserAPIPlan(ctx context.Context, username string) error {
+	return retryExec(ctx, store, "DeleteUserAPIPlan", func(ctx context.Context) error {
+		return store.Store.DeleteUserAPIPlan(ctx, username)
+	})
+}
+
+func (store *RetryStore) DeleteUserAccountMembers(ctx context.Context, username string) error {
+	return retryExec(ctx, store, "DeleteUserAccountMembers", func(ctx context.Context) error {
+		return store.Store.DeleteUserAccountMembers(ctx, username)
+	})
+}
+
+func (store *RetryStore) DeleteUserIdentities(ctx context.Context, username string) error {
+	return retryExec(ctx, store, "DeleteUserIdentities", func(ctx context.Context) error {
+		return store.Store.DeleteUserIdentities(ctx, username)
+	})
+}
+
+func (store *RetryStore) DeleteUserSessions(ctx context.Context, username string) error {
+	return retryExec(ctx, store, "DeleteUserSessions", func(ctx context.Context) error {
+		return store.Store.DeleteUserSessions(ctx, username)
+	})
+}
+
+func (store *RetryStore) DeleteUserSigningKeys(ctx context.Context, username string) error {
+	return retryExec(ctx, store, "DeleteUserSigningKeys", func(ctx context.Context) error {
+		return store.Store.DeleteUserSigningKeys(ctx, username)
+	})
+}
+
+func (store *RetryStore) EscalateTransferReview(ctx context.Context, arg EscalateTransferReviewParams) (TransferReview, error) {
+	return retryQuery(ctx, store, "EscalateTransferReview", func(ctx context.Context) (TransferReview, error) {
+		return store.Store.EscalateTransferReview(ctx, arg)
+	})
+}
+
+func (store *RetryStore) ExpireHolds(ctx context.Context, expiresAt time.Time) ([]Hold, error) {
+	return retryQuery(ctx, store, "ExpireHolds", func(ctx context.Context) ([]Hold, error) {
+		return store.Store.ExpireHolds(ctx, expiresAt)
+	})
+}
+
+func (store *RetryStore) ExpirePaymentLinks(ctx context.Context, expiresAt time.Time) (int64, error) {
+	return retryQuery(ctx, store, "ExpirePaymentLinks", func(ctx context.Context) (int64, error) {
+		return store.Store.ExpirePaymentLinks(ctx, expiresAt)
+	})
+}
+
+func (store *RetryStore) ExpirePaymentRequests(ctx context.Context, expiresAt time.Time) (int64, error) {
+	return retryQuery(ctx, store, "ExpirePaymentRequests", func(ctx context.Context) (int64, error) {
+		return store.Store.ExpirePaymentRequests(ctx, expiresAt)
+	})
+}
+
+func (store *RetryStore) ExpirePendingTransfers(ctx context.Context, expiresAt time.Time) (int64, error) {
+	return retryQuery(ctx, store, "ExpirePendingTransfers", func(ctx context.Context) (int64, error) {
+		return store.Store.ExpirePendingTransfers(ctx, expiresAt)
+	})
+}
+
+func (store *RetryStore) FailJob(ctx context.Context, arg FailJobParams) (Job, error) {
+	return retryQuery(ctx, store, "FailJob", func(ctx context.Context) (Job, error) {
+		return store.Store.FailJob(ctx, arg)
+	})
+}
+
+func (store *RetryStore) FailUserDeletion(ctx context.Context, arg FailUserDeletionParams) (UserDeletion, error) {
+	return retryQuery(ctx, store, "FailUserDeletion", func(ctx context.Context) (UserDeletion, error) {
+		return store.Store.FailUserDeletion(ctx, arg)
+	})
+}
+
+func (store *RetryStore) FreezeUserCards(ctx context.Context, owner string) (int64, error) {
+	return retryQuery(ctx, store, "FreezeUserCards", func(ctx context.Context) (int64, error) {
+		return store.Store.FreezeUserCards(ctx, owner)
+	})
+}
+
+func (store *RetryStore) GetAccount(ctx context.Context, id int64) (Account, error) {
+	return retryQuery(ctx, store, "GetAccount", func(ctx context.Context) (Account, error) {
+		return store.Store.GetAccount(ctx, id)
+	})
+}
+
+func (store *RetryStore) GetAccountAlert(ctx context.Context, accountID int64) (AccountAlert, error) {
+	return retryQuery(ctx, store, "GetAccountAlert", func(ctx context.Context) (AccountAlert, error) {
+		return store.Store.GetAccountAlert(ctx, accountID)
+	})
+}
+
+func (store *RetryStore) GetAccountByNumber(ctx context.Context, accountNumber string) (Account, error) {
+	return retryQuery(ctx, store, "GetAccountByNumber", func(ctx context.Context) (Account, error) {
+		return store.Store.GetAccountByNumber(ctx, accountNumber)
+	})
+}
+
+func (store *RetryStore) GetAccountForUpdate(ctx context.Context, id int64) (Account, error) {
+	return retryQuery(ctx, store, "GetAccountForUpdate", func(ctx context.Context) (Account, error) {
+		return store.Store.GetAccountForUpdate(ctx, id)
+	})
+}
+
+func (store *RetryStore) GetAccountMember(ctx context.Context, arg GetAccountMemberParams) (AccountMember, error) {
+	return retryQuery(ctx, store, "GetAccountMember", func(ctx context.Context) (AccountMember, error) {
+		return store.Store.GetAccountMember(ctx, arg)
+	})
+}
+
+func (store *RetryStore) GetAccountsByIDs(ctx context.Context, arg GetAccountsByIDsParams) ([]Account, error) {
+	return retryQuery(ctx, store, "GetAccountsByIDs", func(ctx context.Context) ([]Account, error) {
+		return store.Store.GetAccountsByIDs(ctx, arg)
+	})
+}
+
+func (store *RetryStore) GetActiveBankParameter(ctx context.Context, arg GetActiveBankParameterParams) (BankParameter, error) {
+	return retryQuery(ctx, store, "GetActiveBankParameter", func(ctx context.Context) (BankParameter, error) {
+		return store.Store.GetActiveBankParameter(ctx, arg)
+	})
+}
+
+func (store *RetryStore) GetBalanceSnapshot(ctx context.Context, arg GetBalanceSnapshotParams) (BalanceSnapshot, error) {
+	return retryQuery(ctx, store, "GetBalanceSnapshot", func(ctx context.Context) (BalanceSnapshot, error) {
+		return store.Store.GetBalanceSnapshot(ctx, arg)
+	})
+}
+
+func (store *RetryStore) GetBeneficiary(ctx context.Context, id int64) (Beneficiary, error) {
+	return retryQuery(ctx, store, "GetBeneficiary", func(ctx context.Context) (Beneficiary, error) {
+		return store.Store.GetBeneficiary(ctx, id)
+	})
+}
+
+func (store *RetryStore) GetBeneficiaryByAccount(ctx context.Context, arg GetBeneficiaryByAccountParams) (Beneficiary, error) {
+	return retryQuery(ctx, store, "GetBeneficiaryByAccount", func(ctx context.Context) (Beneficiary, error) {
+		return store.Store.GetBeneficiaryByAccount(ctx, arg)
+	})
+}
+
+func (store *RetryStore) GetBudget(ctx context.Context, arg GetBudgetParams) (Budget, error) {
+	return retryQuery(ctx, store, "GetBudget", func(ctx context.Context) (Budget, error) {
+		return store.Store.GetBudget(ctx, arg)
+	})
+}
+
+func (store *RetryStore) GetCard(ctx context.Context, id int64) (Card, error) {
+	return retryQuery(ctx, store, "GetCard", func(ctx context.Context) (Card, error) {
+		return store.Store.GetCard(ctx, id)
+	})
+}
+
+func (store *RetryStore) GetCardForUpdate(ctx context.Context, id int64) (Card, error) {
+	return retryQuery(ctx, store, "GetCardForUpdate", func(ctx context.Context) (Card, error) {
+		return store.Store.GetCardForUpdate(ctx, id)
+	})
+}
+
+func (store *RetryStore) GetChequeDeposit(ctx context.Context, id int64) (ChequeDeposit, error) {
+	return retryQuery(ctx, store, "GetChequeDeposit", func(ctx context.Context) (ChequeDeposit, error) {
+		return store.Store.GetChequeDeposit(ctx, id)
+	})
+}
+
+func (store *RetryStore) GetChequeDepositForUpdate(ctx context.Context, id int64) (ChequeDeposit, error) {
+	return retryQuery(ctx, store, "GetChequeDepositForUpdate", func(ctx context.Context) (ChequeDeposit, error) {
+		return store.Store.GetChequeDepositForUpdate(ctx, id)
+	})
+}
+
+func (store *RetryStore) GetEntry(ctx context.Context, id int64) (Entry, error) {
+	return retryQuery(ctx, store, "GetEntry", func(ctx context.Context) (Entry, error) {
+		return store.Store.GetEntry(ctx, id)
+	})
+}
+
+func (store *RetryStore) GetExternalTransfer(ctx context.Context, id int64) (ExternalTransfer, error) {
+	return retryQuery(ctx, store, "GetExternalTransfer", func(ctx context.Context) (ExternalTransfer, error) {
+		return store.Store.GetExternalTransfer(ctx, id)
+	})
+}
+
+func (store *RetryStore) GetExternalTransferForUpdate(ctx context.Context, id int64) (ExternalTransfer, error) {
+	return retryQuery(ctx, store, "GetExternalTransferForUpdate", func(ctx context.Context) (ExternalTransfer, error) {
+		return store.Store.GetExternalTransferForUpdate(ctx, id)
+	})
+}
+
+func (store *RetryStore) GetHold(ctx context.Context, id int64) (Hold, error) {
+	return retryQuery(ctx, store, "GetHold", func(ctx context.Context) (Hold, error) {
+		return store.Store.GetHold(ctx, id)
+	})
+}
+
+func (store *RetryStore) GetHoldForUpdate(ctx context.Context, id int64) (Hold, error) {
+	return retryQuery(ctx, store, "GetHoldForUpdate", func(ctx context.Context) (Hold, error) {
+		return store.Store.GetHoldForUpdate(ctx, id)
+	})
+}
+
+func (store *RetryStore) GetInterestAccrual(ctx context.Context, accountID int64) (InterestAccrual, error) {
+	return retryQuery(ctx, store, "GetInterestAccrual", func(ctx context.Context) (InterestAccrual, error) {
+		return store.Store.GetInterestAccrual(ctx, accountID)
+	})
+}
+
+func (store *RetryStore) GetJob(ctx context.Context, id uuid.UUID) (Job, error) {
+	return retryQuery(ctx, store, "GetJob", func(ctx context.Context) (Job, error) {
+		return store.Store.GetJob(ctx, id)
+	})
+}
+
+func (store *RetryStore) GetLastCompletedBatchRun(ctx context.Context, name string) (BatchRun, error) {
+	return retryQuery(ctx, store, "GetLastCompletedBatchRun", func(ctx context.Context) (BatchRun, error) {
+		return store.Store.GetLastCompletedBatchRun(ctx, name)
+	})
+}
+
+func (store *RetryStore) GetLeaderLease(ctx context.Context, name string) (LeaderLease, error) {
+	return retryQuery(ctx, store, "GetLeaderLease", func(ctx context.Context) (LeaderLease, error) {
+		return store.Store.GetLeaderLease(ctx, name)
+	})
+}
+
+func (store *RetryStore) GetLoginLockout(ctx context.Context, arg GetLoginLockoutParams) (LoginLockout, error) {
+	return retryQuery(ctx, store, "GetLoginLockout", func(ctx context.Context) (LoginLockout, error) {
+		return store.Store.GetLoginLockout(ctx, arg)
+	})
+}
+
+func (store *RetryStore) GetMandate(ctx context.Context, id int64) (Mandate, error) {
+	return retryQuery(ctx, store, "GetMandate", func(ctx context.Context) (Mandate, error) {
+		return store.Store.GetMandate(ctx, id)
+	})
+}
+
+func (store *RetryStore) GetMandateForUpdate(ctx context.Context, id int64) (Mandate, error) {
+	return retryQuery(ctx, store, "GetMandateForUpdate", func(ctx context.Context) (Mandate, error) {
+		return store.Store.GetMandateForUpdate(ctx, id)
+	})
+}
+
+func (store *RetryStore) GetNotificationPreferences(ctx context.Context, username string) (NotificationPreference, error) {
+	return retryQuery(ctx, store, "GetNotificationPreferences", func(ctx context.Context) (NotificationPreference, error) {
+		return store.Store.GetNotificationPreferences(ctx, username)
+	})
+}
+
+func (store *RetryStore) GetOrganization(ctx context.Context, id int64) (Organization, error) {
+	return retryQuery(ctx, store, "GetOrganization", func(ctx context.Context) (Organization, error) {
+		return store.Store.GetOrganization(ctx, id)
+	})
+}
+
+func (store *RetryStore) GetOrganizationBySlug(ctx context.Context, slug string) (Organization, error) {
+	return retryQuery(ctx, store, "GetOrganizationBySlug", func(ctx context.Context) (Organization, error) {
+		return store.Store.GetOrganizationBySlug(ctx, slug)
+	})
+}
+
+func (store *RetryStore) GetPaymentLinkByCode(ctx context.Context, code string) (PaymentLink, error) {
+	return retryQuery(ctx, store, "GetPaymentLinkByCode", func(ctx context.Context) (PaymentLink, error) {
+		return store.Store.GetPaymentLinkByCode(ctx, code)
+	})
+}
+
+func (store *RetryStore) GetPaymentLinkForUpdate(ctx context.Context, id int64) (PaymentLink, error) {
+	return retryQuery(ctx, store, "GetPaymentLinkForUpdate", func(ctx context.Context) (PaymentLink, error) {
+		return store.Store.GetPaymentLinkForUpdate(ctx, id)
+	})
+}
+
+func (store *RetryStore) GetPaymentRequest(ctx context.Context, id int64) (PaymentRequest, error) {
+	return retryQuery(ctx, store, "GetPaymentRequest", func(ctx context.Context) (PaymentRequest, error) {
+		return store.Store.GetPaymentRequest(ctx, id)
+	})
+}
+
+func (store *RetryStore) GetPaymentRequestForUpdate(ctx context.Context, id int64) (PaymentRequest, error) {
+	return retryQuery(ctx, store, "GetPaymentRequestForUpdate", func(ctx context.Context) (PaymentRequest, error) {
+		return store.Store.GetPaymentRequestForUpdate(ctx, id)
+	})
+}
+
+func (store *RetryStore) GetPendingTransfer(ctx context.Context, id int64) (PendingTransfer, error) {
+	return retryQuery(ctx, store, "GetPendingTransfer", func(ctx context.Context) (PendingTransfer, error) {
+		return store.Store.GetPendingTransfer(ctx, id)
+	})
+}
+
+func (store *RetryStore) GetPendingTransferForUpdate(ctx context.Context, id int64) (PendingTransfer, error) {
+	return retryQuery(ctx, store, "GetPendingTransferForUpdate", func(ctx context.Context) (PendingTransfer, error) {
+		return store.Store.GetPendingTransferForUpdate(ctx, id)
+	})
+}
+
+func (store *RetryStore) GetQueuedTransfer(ctx context.Context, id uuid.UUID) (QueuedTransfer, error) {
+	return retryQuery(ctx, store, "GetQueuedTransfer", func(ctx context.Context) (QueuedTransfer, error) {
+		return store.Store.GetQueuedTransfer(ctx, id)
+	})
+}
+
+func (store *RetryStore) GetSession(ctx context.Context, id uuid.UUID) (Session, error) {
+	return retryQuery(ctx, store, "GetSession", func(ctx context.Context) (Session, error) {
+		return store.Store.GetSession(ctx, id)
+	})
+}
+
+func (store *RetryStore) GetSigningKey(ctx context.Context, id int64) (SigningKey, error) {
+	return retryQuery(ctx, store, "GetSigningKey", func(ctx context.Context) (SigningKey, error) {
+		return store.Store.GetSigningKey(ctx, id)
+	})
+}
+
+func (store *RetryStore) GetStandingOrder(ctx context.Context, id int64) (StandingOrder, error) {
+	return retryQuery(ctx, store, "GetStandingOrder", func(ctx context.Context) (StandingOrder, error) {
+		return store.Store.GetStandingOrder(ctx, id)
+	})
+}
+
+func (store *RetryStore) GetStandingOrderForUpdate(ctx context.Context, id int64) (StandingOrder, error) {
+	return retryQuery(ctx, store, "GetStandingOrderForUpdate", func(ctx context.Context) (StandingOrder, error) {
+		return store.Store.GetStandingOrderForUpdate(ctx, id)
+	})
+}
+
+func (store *RetryStore) GetSystemAccount(ctx context.Context, arg GetSystemAccountParams) (Account, error) {
+	return retryQuery(ctx, store, "GetSystemAccount", func(ctx context.Context) (Account, error) {
+		return store.Store.GetSystemAccount(ctx, arg)
+	})
+}
+
+func (store *RetryStore) GetTransfer(ctx context.Context, id int64) (Transfer, error) {
+	return retryQuery(ctx, store, "GetTransfer", func(ctx context.Context) (Transfer, error) {
+		return store.Store.GetTransfer(ctx, id)
+	})
+}
+
+func (store *RetryStore) GetTransferReview(ctx context.Context, id int64) (TransferReview, error) {
+	return retryQuery(ctx, store, "GetTransferReview", func(ctx context.Context) (TransferReview, error) {
+		return store.Store.GetTransferReview(ctx, id)
+	})
+}
+
+func (store *RetryStore) GetTransferReviewForUpdate(ctx context.Context, id int64) (TransferReview, error) {
+	return retryQuery(ctx, store, "GetTransferReviewForUpdate", func(ctx context.Context) (TransferReview, error) {
+		return store.Store.GetTransferReviewForUpdate(ctx, id)
+	})
+}
+
+func (store *RetryStore) GetUser(ctx context.Context, username string) (User, error) {
+	return retryQuery(ctx, store, "GetUser", func(ctx context.Context) (User, error) {
+		return store.Store.GetUser(ctx, username)
+	})
+}
+
+func (store *RetryStore) GetUserAPIPlan(ctx context.Context, username string) (UserApiPlan, error) {
+	return retryQuery(ctx, store, "GetUserAPIPlan", func(ctx context.Context) (UserApiPlan, error) {
+		return store.Store.GetUserAPIPlan(ctx, username)
+	})
+}
+
+func (store *RetryStore) GetUserByEmail(ctx context.Context, email string) (User, error) {
+	return retryQuery(ctx, store, "GetUserByEmail", func(ctx context.Context) (User, error) {
+		return store.Store.GetUserByEmail(ctx, email)
+	})
+}
+
+func (store *RetryStore) GetUserDeletion(ctx context.Context, username string) (UserDeletion, error) {
+	return retryQuery(ctx, store, "GetUserDeletion", func(ctx context.Context) (UserDeletion, error) {
+		return store.Store.GetUserDeletion(ctx, username)
+	})
+}
+
+func (store *RetryStore) GetUserForUpdate(ctx context.Context, username string) (User, error) {
+	return retryQuery(ctx, store, "GetUserForUpdate", func(ctx context.Context) (User, error) {
+		return store.Store.GetUserForUpdate(ctx, username)
+	})
+}
+
+func (store *RetryStore) GetUserIdentity(ctx context.Context, arg GetUserIdentityParams) (UserIdentity, error) {
+	return retryQuery(ctx, store, "GetUserIdentity", func(ctx context.Context) (UserIdentity, error) {
+		return store.Store.GetUserIdentity(ctx, arg)
+	})
+}
+
+func (store *RetryStore) GetUserOverview(ctx context.Context, username string) (UserOverview, error) {
+	return retryQuery(ctx, store, "GetUserOverview", func(ctx context.Context) (UserOverview, error) {
+		return store.Store.GetUserOverview(ctx, username)
+	})
+}
+
+func (store *RetryStore) HandOverJointAccounts(ctx context.Context, owner string) ([]Account, error) {
+	return retryQuery(ctx, store, "HandOverJointAccounts", func(ctx context.Context) ([]Account, error) {
+		return store.Store.HandOverJointAccounts(ctx, owner)
+	})
+}
+
+func (store *RetryStore) IsTaskProcessed(ctx context.Context, id string) (bool, error) {
+	return retryQuery(ctx, store, "IsTaskProcessed", func(ctx context.Context) (bool, error) {
+		return store.Store.IsTaskProcessed(ctx, id)
+	})
+}
+
+func (store *RetryStore) ListAccountBalanceDiscrepancies(ctx context.Context) ([]ListAccountBalanceDiscrepanciesRow, error) {
+	return retryQuery(ctx, store, "ListAccountBalanceDiscrepancies", func(ctx context.Context) ([]ListAccountBalanceDiscrepanciesRow, error) {
+		return store.Store.ListAccountBalanceDiscrepancies(ctx)
+	})
+}
+
+func (store *RetryStore) ListAccountCounterpartyActivity(ctx context.Context, arg ListAccountCounterpartyActivityParams) ([]AccountCounterpartyActivity, error) {
+	return retryQuery(ctx, store, "ListAccountCounterpartyActivity", func(ctx context.Context) ([]AccountCounterpartyActivity, error) {
+		return store.Store.ListAccountCounterpartyActivity(ctx, arg)
+	})
+}
+
+func (store *RetryStore) ListAccountDailyActivity(ctx context.Context, arg ListAccountDailyActivityParams) ([]AccountDailyActivity, error) {
+	return retryQuery(ctx, store, "ListAccountDailyActivity", func(ctx context.Context) ([]AccountDailyActivity, error) {
+		return store.Store.ListAccountDailyActivity(ctx, arg)
+	})
+}
+
+func (store *RetryStore) ListAccountHistory(ctx context.Context, arg ListAccountHistoryParams) ([]AccountHistory, error) {
+	return retryQuery(ctx, store, "ListAccountHistory", func(ctx context.Context) ([]AccountHistory, error) {
+		return store.Store.ListAccountHistory(ctx, arg)
+	})
+}
+
+func (store *RetryStore) ListAccountHolds(ctx context.Context, arg ListAccountHoldsParams) ([]Hold, error) {
+	return retryQuery(ctx, store, "ListAccountHolds", func(ctx context.Context) ([]Hold, error) {
+		return store.Store.ListAccountHolds(ctx, arg)
+	})
+}
+
+func (store *RetryStore) ListAccountInvitations(ctx context.Context, arg ListAccountInvitationsParams) ([]AccountMember, error) {
+	return retryQuery(ctx, store, "ListAccountInvitations", func(ctx context.Context) ([]AccountMember, error) {
+		return store.Store.ListAccountInvitations(ctx, arg)
+	})
+}
+
+func (store *RetryStore) ListAccountOverviews(ctx context.Context, arg ListAccountOverviewsParams) ([]AccountOverview, error) {
+	return retryQuery(ctx, store, "ListAccountOverviews", func(ctx context.Context) ([]AccountOverview, error) {
+		return store.Store.ListAccountOverviews(ctx, arg)
+	})
+}
+
+func (store *RetryStore) ListAccounts(ctx context.Context, arg ListAccountsParams) ([]Account, error) {
+	return retryQuery(ctx, store, "ListAccounts", func(ctx context.Context) ([]Account, error) {
+		return store.Store.ListAccounts(ctx, arg)
+	})
+}
+
+func (store *RetryStore) ListAccountsAfter(ctx context.Context, arg ListAccountsAfterParams) ([]Account, error) {
+	return retryQuery(ctx, store, "ListAccountsAfter", func(ctx context.Context) ([]Account, error) {
+		return store.Store.ListAccountsAfter(ctx, arg)
+	})
+}
+
+func (store *RetryStore) ListActiveBankParameters(ctx context.Context, at time.Time) ([]BankParameter, error) {
+	return retryQuery(ctx, store, "ListActiveBankParameters", func(ctx context.Context) ([]BankParameter, error) {
+		return store.Store.ListActiveBankParameters(ctx, at)
+	})
+}
+
+func (store *RetryStore) ListAuditEntries(ctx context.Context, arg ListAuditEntriesParams) ([]AuditEntry, error) {
+	return retryQuery(ctx, store, "ListAuditEntries", func(ctx context.Context) ([]AuditEntry, error) {
+		return store.Store.ListAuditEntries(ctx, arg)
+	})
+}
+
+func (store *RetryStore) ListBalanceSnapshots(ctx context.Context, arg ListBalanceSnapshotsParams) ([]BalanceSnapshot, error) {
+	return retryQuery(ctx, store, "ListBalanceSnapshots", func(ctx context.Context) ([]BalanceSnapshot, error) {
+		return store.Store.ListBalanceSnapshots(ctx, arg)
+	})
+}
+
+func (store *RetryStore) ListBankParameterVersions(ctx context.Context, arg ListBankParameterVersionsParams) ([]BankParameter, error) {
+	return retryQuery(ctx, store, "ListBankParameterVersions", func(ctx context.Context) ([]BankParameter, error) {
+		return store.Store.ListBankParameterVersions(ctx, arg)
+	})
+}
+
+func (store *RetryStore) ListBeneficiaries(ctx context.Context, arg ListBeneficiariesParams) ([]Beneficiary, error) {
+	return retryQuery(ctx, store, "ListBeneficiaries", func(ctx context.Context) ([]Beneficiary, error) {
+		return store.Store.ListBeneficiaries(ctx, arg)
+	})
+}
+
+func (store *RetryStore) ListBudgets(ctx context.Context, accountID int64) ([]Budget, error) {
+	return retryQuery(ctx, store, "ListBudgets", func(ctx context.Context) ([]Budget, error) {
+		return store.Store.ListBudgets(ctx, accountID)
+	})
+}
+
+func (store *RetryStore) ListCardHolds(ctx context.Context, arg ListCardHoldsParams) ([]Hold, error) {
+	return retryQuery(ctx, store, "ListCardHolds", func(ctx context.Context) ([]Hold, error) {
+		return store.Store.ListCardHolds(ctx, arg)
+	})
+}
+
+func (store *RetryStore) ListCards(ctx context.Context, arg ListCardsParams) ([]Card, error) {
+	return retryQuery(ctx, store, "ListCards", func(ctx context.Context) ([]Card, error) {
+		return store.Store.ListCards(ctx, arg)
+	})
+}
+
+func (store *RetryStore) ListDailyTransferVolumes(ctx context.Context, since time.Time) ([]ListDailyTransferVolumesRow, error) {
+	return retryQuery(ctx, store, "ListDailyTransferVolumes", func(ctx context.Context) ([]ListDailyTransferVolumesRow, error) {
+		return store.Store.ListDailyTransferVolumes(ctx, since)
+	})
+}
+
+func (store *RetryStore) ListDueChequeDeposits(ctx context.Context, arg ListDueChequeDepositsParams) ([]ChequeDeposit, error) {
+	return retryQuery(ctx, store, "ListDueChequeDeposits", func(ctx context.Context) ([]ChequeDeposit, error) {
+		return store.Store.ListDueChequeDeposits(ctx, arg)
+	})
+}
+
+func (store *RetryStore) ListDueStandingOrders(ctx context.Context, arg ListDueStandingOrdersParams) ([]StandingOrder, error) {
+	return retryQuery(ctx, store, "ListDueStandingOrders", func(ctx context.Context) ([]StandingOrder, error) {
+		return store.Store.ListDueStandingOrders(ctx, arg)
+	})
+}
+
+func (store *RetryStore) ListEntries(ctx context.Context, arg ListEntriesParams) ([]Entry, error) {
+	return retryQuery(ctx, store, "ListEntries", func(ctx context.Context) ([]Entry, error) {
+		return store.Store.ListEntries(ctx, arg)
+	})
+}
+
+func (store *RetryStore) ListEntriesForExport(ctx context.Context, arg ListEntriesForExportParams) ([]ListEntriesForExportRow, error) {
+	return retryQuery(ctx, store, "ListEntriesForExport", func(ctx context.Context) ([]ListEntriesForExportRow, error) {
+		return store.Store.ListEntriesForExport(ctx, arg)
+	})
+}
+
+func (store *RetryStore) ListEntriesInRange(ctx context.Context, arg ListEntriesInRangeParams) ([]Entry, error) {
+	return retryQuery(ctx, store, "ListEntriesInRange", func(ctx context.Context) ([]Entry, error) {
+		return store.Store.ListEntriesInRange(ctx, arg)
+	})
+}
+
+func (store *RetryStore) ListEntriesWithCounterparty(ctx context.Context, arg ListEntriesWithCounterpartyParams) ([]ListEntriesWithCounterpartyRow, error) {
+	return retryQuery(ctx, store, "ListEntriesWithCounterparty", func(ctx context.Context) ([]ListEntriesWithCounterpartyRow, error) {
+		return store.Store.ListEntriesWithCounterparty(ctx, arg)
+	})
+}
+
+func (store *RetryStore) ListEntriesWithCounterpartyBefore(ctx context.Context, arg ListEntriesWithCounterpartyBeforeParams) ([]ListEntriesWithCounterpartyBeforeRow, error) {
+	return retryQuery(ctx, store, "ListEntriesWithCounterpartyBefore", func(ctx context.Context) ([]ListEntriesWithCounterpartyBeforeRow, error) {
+		return store.Store.ListEntriesWithCounterpartyBefore(ctx, arg)
+	})
+}
+
+func (store *RetryStore) ListEventsAfter(ctx context.Context, arg ListEventsAfterParams) ([]Event, error) {
+	return retryQuery(ctx, store, "ListEventsAfter", func(ctx context.Context) ([]Event, error) {
+		return store.Store.ListEventsAfter(ctx, arg)
+	})
+}
+
+func (store *RetryStore) ListExternalTransfers(ctx context.Context, arg ListExternalTransfersParams) ([]ExternalTransfer, error) {
+	return retryQuery(ctx, store, "ListExternalTransfers", func(ctx context.Context) ([]ExternalTransfer, error) {
+		return store.Store.ListExternalTransfers(ctx, arg)
+	})
+}
+
+func (store *RetryStore) ListLedgerAnomalies(ctx context.Context, arg ListLedgerAnomaliesParams) ([]LedgerAnomaly, error) {
+	return retryQuery(ctx, store, "ListLedgerAnomalies", func(ctx context.Context) ([]LedgerAnomaly, error) {
+		return store.Store.ListLedgerAnomalies(ctx, arg)
+	})
+}
+
+func (store *RetryStore) ListMandates(ctx context.Context, arg ListMandatesParams) ([]Mandate, error) {
+	return retryQuery(ctx, store, "ListMandates", func(ctx context.Context) ([]Mandate, error) {
+		return store.Store.ListMandates(ctx, arg)
+	})
+}
+
+func (store *RetryStore) ListNotifications(ctx context.Context, arg ListNotificationsParams) ([]Notification, error) {
+	return retryQuery(ctx, store, "ListNotifications", func(ctx context.Context) ([]Notification, error) {
+		return store.Store.ListNotifications(ctx, arg)
+	})
+}
+
+func (store *RetryStore) ListOrganizationPendingTransfers(ctx context.Context, arg ListOrganizationPendingTransfersParams) ([]PendingTransfer, error) {
+	return retryQuery(ctx, store, "ListOrganizationPendingTransfers", func(ctx context.Context) ([]PendingTransfer, error) {
+		return store.Store.ListOrganizationPendingTransfers(ctx, arg)
+	})
+}
+
+func (store *RetryStore) ListOrganizationUsers(ctx context.Context, arg ListOrganizationUsersParams) ([]User, error) {
+	return retryQuery(ctx, store, "ListOrganizationUsers", func(ctx context.Context) ([]User, error) {
+		return store.Store.ListOrganizationUsers(ctx, arg)
+	})
+}
+
+func (store *RetryStore) ListOwnerRecentEntries(ctx context.Context, arg ListOwnerRecentEntriesParams) ([]ListOwnerRecentEntriesRow, error) {
+	return retryQuery(ctx, store, "ListOwnerRecentEntries", func(ctx context.Context) ([]ListOwnerRecentEntriesRow, error) {
+		return store.Store.ListOwnerRecentEntries(ctx, arg)
+	})
+}
+
+func (store *RetryStore) ListPaymentRequests(ctx context.Context, arg ListPaymentRequestsParams) ([]PaymentRequest, error) {
+	return retryQuery(ctx, store, "ListPaymentRequests", func(ctx context.Context) ([]PaymentRequest, error) {
+		return store.Store.ListPaymentRequests(ctx, arg)
+	})
+}
+
+func (store *RetryStore) ListPendingTransfers(ctx context.Context, arg ListPendingTransfersParams) ([]PendingTransfer, error) {
+	return retryQuery(ctx, store, "ListPendingTransfers", func(ctx context.Context) ([]PendingTransfer, error) {
+		return store.Store.ListPendingTransfers(ctx, arg)
+	})
+}
+
+func (store *RetryStore) ListRequestHeatmap(ctx context.Context, since time.Time) ([]ListRequestHeatmapRow, error) {
+	return retryQuery(ctx, store, "ListRequestHeatmap", func(ctx context.Context) ([]ListRequestHeatmapRow, error) {
+		return store.Store.ListRequestHeatmap(ctx, since)
+	})
+}
+
+func (store *RetryStore) ListRouteRequestVolumes(ctx context.Context, since time.Time) ([]ListRouteRequestVolumesRow, error) {
+	return retryQuery(ctx, store, "ListRouteRequestVolumes", func(ctx context.Context) ([]ListRouteRequestVolumesRow, error) {
+		return store.Store.ListRouteRequestVolumes(ctx, since)
+	})
+}
+
+func (store *RetryStore) ListSigningKeys(ctx context.Context, username string) ([]SigningKey, error) {
+	return retryQuery(ctx, store, "ListSigningKeys", func(ctx context.Context) ([]SigningKey, error) {
+		return store.Store.ListSigningKeys(ctx, username)
+	})
+}
+
+func (store *RetryStore) ListStaleExternalTransfers(ctx context.Context, arg ListStaleExternalTransfersParams) ([]ExternalTransfer, error) {
+	return retryQuery(ctx, store, "ListStaleExternalTransfers", func(ctx context.Context) ([]ExternalTransfer, error) {
+		return store.Store.ListStaleExternalTransfers(ctx, arg)
+	})
+}
+
+func (store *RetryStore) ListStandingOrders(ctx context.Context, arg ListStandingOrdersParams) ([]StandingOrder, error) {
+	return retryQuery(ctx, store, "ListStandingOrders", func(ctx context.Context) ([]StandingOrder, error) {
+		return store.Store.ListStandingOrders(ctx, arg)
+	})
+}
+
+func (store *RetryStore) ListTransferHeatmap(ctx context.Context, since time.Time) ([]ListTransferHeatmapRow, error) {
+	return retryQuery(ctx, store, "ListTransferHeatmap", func(ctx context.Context) ([]ListTransferHeatmapRow, error) {
+		return store.Store.ListTransferHeatmap(ctx, since)
+	})
+}
+
+func (store *RetryStore) ListTransferImbalances(ctx context.Context) ([]ListTransferImbalancesRow, error) {
+	return retryQuery(ctx, store, "ListTransferImbalances", func(ctx context.Context) ([]ListTransferImbalancesRow, error) {
+		return store.Store.ListTransferImbalances(ctx)
+	})
+}
+
+func (store *RetryStore) ListTransferReviews(ctx context.Context, arg ListTransferReviewsParams) ([]TransferReview, error) {
+	return retryQuery(ctx, store, "ListTransferReviews", func(ctx context.Context) ([]TransferReview, error) {
+		return store.Store.ListTransferReviews(ctx, arg)
+	})
+}
+
+func (store *RetryStore) ListTransfers(ctx context.Context, arg ListTransfersParams) ([]Transfer, error) {
+	return retryQuery(ctx, store, "ListTransfers", func(ctx context.Context) ([]Transfer, error) {
+		return store.Store.ListTransfers(ctx, arg)
+	})
+}
+
+func (store *RetryStore) ListUserOverviews(ctx context.Context, arg ListUserOverviewsParams) ([]UserOverview, error) {
+	return retryQuery(ctx, store, "ListUserOverviews", func(ctx context.Context) ([]UserOverview, error) {
+		return store.Store.ListUserOverviews(ctx, arg)
+	})
+}
+
+func (store *RetryStore) LockAccounts(ctx context.Context, ids []int64) ([]int64, error) {
+	return retryQuery(ctx, store, "LockAccounts", func(ctx context.Context) ([]int64, error) {
+		return store.Store.LockAccounts(ctx, ids)
+	})
+}
+
+func (store *RetryStore) LockBatchRun(ctx context.Context, arg LockBatchRunParams) (BatchRun, error) {
+	return retryQuery(ctx, store, "LockBatchRun", func(ctx context.Context) (BatchRun, error) {
+		return store.Store.LockBatchRun(ctx, arg)
+	})
+}
+
+func (store *RetryStore) LockProjectionCheckpoint(ctx context.Context, name string) (int64, error) {
+	return retryQuery(ctx, store, "LockProjectionCheckpoint", func(ctx context.Context) (int64, error) {
+		return store.Store.LockProjectionCheckpoint(ctx, name)
+	})
+}
+
+func (store *RetryStore) MarkAllNotificationsRead(ctx context.Context, username string) (int64, error) {
+	return retryQuery(ctx, store, "MarkAllNotificationsRead", func(ctx context.Context) (int64, error) {
+		return store.Store.MarkAllNotificationsRead(ctx, username)
+	})
+}
+
+func (store *RetryStore) MarkNotificationDelivered(ctx context.Context, id int64) error {
+	return retryExec(ctx, store, "MarkNotificationDelivered", func(ctx context.Context) error {
+		return store.Store.MarkNotificationDelivered(ctx, id)
+	})
+}
+
+func (store *RetryStore) MarkNotificationRead(ctx context.Context, arg MarkNotificationReadParams) (Notification, error) {
+	return retryQuery(ctx, store, "MarkNotificationRead", func(ctx context.Context) (Notification, error) {
+		return store.Store.MarkNotificationRead(ctx, arg)
+	})
+}
+
+func (store *RetryStore) OwnerHasBalance(ctx context.Context, owner string) (bool, error) {
+	return retryQuery(ctx, store, "OwnerHasBalance", func(ctx context.Context) (bool, error) {
+		return store.Store.OwnerHasBalance(ctx, owner)
+	})
+}
+
+func (store *RetryStore) PayPaymentLink(ctx context.Context, arg PayPaymentLinkParams) (PaymentLink, error) {
+	return retryQuery(ctx, store, "PayPaymentLink", func(ctx context.Context) (PaymentLink, error) {
+		return store.Store.PayPaymentLink(ctx, arg)
+	})
+}
+
+func (store *RetryStore) RecordAccountOverviewTransfer(ctx context.Context, arg RecordAccountOverviewTransferParams) error {
+	return retryExec(ctx, store, "RecordAccountOverviewTransfer", func(ctx context.Context) error {
+		return store.Store.RecordAccountOverviewTransfer(ctx, arg)
+	})
+}
+
+func (store *RetryStore) RecordMandatePull(ctx context.Context, arg RecordMandatePullParams) (Mandate, error) {
+	return retryQuery(ctx, store, "RecordMandatePull", func(ctx context.Context) (Mandate, error) {
+		return store.Store.RecordMandatePull(ctx, arg)
+	})
+}
+
+func (store *RetryStore) RecordNotificationDeliveryFailure(ctx context.Context, arg RecordNotificationDeliveryFailureParams) error {
+	return retryExec(ctx, store, "RecordNotificationDeliveryFailure", func(ctx context.Context) error {
+		return store.Store.RecordNotificationDeliveryFailure(ctx, arg)
+	})
+}
+
+func (store *RetryStore) RecordStandingOrderRun(ctx context.Context, arg RecordStandingOrderRunParams) (StandingOrder, error) {
+	return retryQuery(ctx, store, "RecordStandingOrderRun", func(ctx context.Context) (StandingOrder, error) {
+		return store.Store.RecordStandingOrderRun(ctx, arg)
+	})
+}
+
+func (store *RetryStore) RefreshAccountOverviewVolume(ctx context.Context, accountID pgtype.Int8) error {
+	return retryExec(ctx, store, "RefreshAccountOverviewVolume", func(ctx context.Context) error {
+		return store.Store.RefreshAccountOverviewVolume(ctx, accountID)
+	})
+}
+
+func (store *RetryStore) RejectPendingTransfer(ctx context.Context, arg RejectPendingTransferParams) (PendingTransfer, error) {
+	return retryQuery(ctx, store, "RejectPendingTransfer", func(ctx context.Context) (PendingTransfer, error) {
+		return store.Store.RejectPendingTransfer(ctx, arg)
+	})
+}
+
+func (store *RetryStore) ReleaseHold(ctx context.Context, id int64) (Hold, error) {
+	return retryQuery(ctx, store, "ReleaseHold", func(ctx context.Context) (Hold, error) {
+		return store.Store.ReleaseHold(ctx, id)
+	})
+}
+
+func (store *RetryStore) ReleaseLeaderLease(ctx context.Context, arg ReleaseLeaderLeaseParams) error {
+	return retryExec(ctx, store, "ReleaseLeaderLease", func(ctx context.Context) error {
+		return store.Store.ReleaseLeaderLease(ctx, arg)
+	})
+}
+
+func (store *RetryStore) ResolveLedgerAnomalies(ctx context.Context) (int64, error) {
+	return retryQuery(ctx, store, "ResolveLedgerAnomalies", func(ctx context.Context) (int64, error) {
+		return store.Store.ResolveLedgerAnomalies(ctx)
+	})
+}
+
+func (store *RetryStore) RevokeMandate(ctx context.Context, id int64) (Mandate, error) {
+	return retryQuery(ctx, store, "RevokeMandate", func(ctx context.Context) (Mandate, error) {
+		return store.Store.RevokeMandate(ctx, id)
+	})
+}
+
+func (store *RetryStore) RevokeSigningKey(ctx context.Context, arg RevokeSigningKeyParams) (SigningKey, error) {
+	return retryQuery(ctx, store, "RevokeSigningKey", func(ctx context.Context) (SigningKey, error) {
+		return store.Store.RevokeSigningKey(ctx, arg)
+	})
+}
+
+func (store *RetryStore) RevokeUserMandates(ctx context.Context, payer string) (int64, error) {
+	return retryQuery(ctx, store, "RevokeUserMandates", func(ctx context.Context) (int64, error) {
+		return store.Store.RevokeUserMandates(ctx, payer)
+	})
+}
+
+func (store *RetryStore) ScheduleUserDeletion(ctx context.Context, arg ScheduleUserDeletionParams) (UserDeletion, error) {
+	return retryQuery(ctx, store, "ScheduleUserDeletion", func(ctx context.Context) (UserDeletion, error) {
+		return store.Store.ScheduleUserDeletion(ctx, arg)
+	})
+}
+
+func (store *RetryStore) ScrubUserEvents(ctx context.Context, username string) error {
+	return retryExec(ctx, store, "ScrubUserEvents", func(ctx context.Context) error {
+		return store.Store.ScrubUserEvents(ctx, username)
+	})
+}
+
+func (store *RetryStore) SearchAccountEntries(ctx context.Context, arg SearchAccountEntriesParams) ([]SearchAccountEntriesRow, error) {
+	return retryQuery(ctx, store, "SearchAccountEntries", func(ctx context.Context) ([]SearchAccountEntriesRow, error) {
+		return store.Store.SearchAccountEntries(ctx, arg)
+	})
+}
+
+func (store *RetryStore) SearchAccounts(ctx context.Context, arg SearchAccountsParams) ([]Account, error) {
+	return retryQuery(ctx, store, "SearchAccounts", func(ctx context.Context) ([]Account, error) {
+		return store.Store.SearchAccounts(ctx, arg)
+	})
+}
+
+func (store *RetryStore) SearchTransfers(ctx context.Context, arg SearchTransfersParams) ([]SearchTransfersRow, error) {
+	return retryQuery(ctx, store, "SearchTransfers", func(ctx context.Context) ([]SearchTransfersRow, error) {
+		return store.Store.SearchTransfers(ctx, arg)
+	})
+}
+
+func (store *RetryStore) SearchTransfersBefore(ctx context.Context, arg SearchTransfersBeforeParams) ([]SearchTransfersBeforeRow, error) {
+	return retryQuery(ctx, store, "SearchTransfersBefore", func(ctx context.Context) ([]SearchTransfersBeforeRow, error) {
+		return store.Store.SearchTransfersBefore(ctx, arg)
+	})
+}
+
+func (store *RetryStore) SetAccountOverviewBalance(ctx context.Context, arg SetAccountOverviewBalanceParams) error {
+	return retryExec(ctx, store, "SetAccountOverviewBalance", func(ctx context.Context) error {
+		return store.Store.SetAccountOverviewBalance(ctx, arg)
+	})
+}
+
+func (store *RetryStore) SetInterestAccrual(ctx context.Context, arg SetInterestAccrualParams) error {
+	return retryExec(ctx, store, "SetInterestAccrual", func(ctx context.Context) error {
+		return store.Store.SetInterestAccrual(ctx, arg)
+	})
+}
+
+func (store *RetryStore) SumCategorySpending(ctx context.Context, arg SumCategorySpendingParams) (int64, error) {
+	return retryQuery(ctx, store, "SumCategorySpending", func(ctx context.Context) (int64, error) {
+		return store.Store.SumCategorySpending(ctx, arg)
+	})
+}
+
+func (store *RetryStore) SumEntriesSince(ctx context.Context, arg SumEntriesSinceParams) (int64, error) {
+	return retryQuery(ctx, store, "SumEntriesSince", func(ctx context.Context) (int64, error) {
+		return store.Store.SumEntriesSince(ctx, arg)
+	})
+}
+
+func (store *RetryStore) SumOwnerBalances(ctx context.Context, owner string) ([]SumOwnerBalancesRow, error) {
+	return retryQuery(ctx, store, "SumOwnerBalances", func(ctx context.Context) ([]SumOwnerBalancesRow, error) {
+		return store.Store.SumOwnerBalances(ctx, owner)
+	})
+}
+
+func (store *RetryStore) SumOwnerSpending(ctx context.Context, arg SumOwnerSpendingParams) ([]SumOwnerSpendingRow, error) {
+	return retryQuery(ctx, store, "SumOwnerSpending", func(ctx context.Context) ([]SumOwnerSpendingRow, error) {
+		return store.Store.SumOwnerSpending(ctx, arg)
+	})
+}
+
+func (store *RetryStore) TouchSession(ctx context.Context, arg TouchSessionParams) error {
+	return retryExec(ctx, store, "TouchSession", func(ctx context.Context) error {
+		return store.Store.TouchSession(ctx, arg)
+	})
+}
+
+func (store *RetryStore) TouchUserOverview(ctx context.Context, arg TouchUserOverviewParams) error {
+	return retryExec(ctx, store, "TouchUserOverview", func(ctx context.Context) error {
+		return store.Store.TouchUserOverview(ctx, arg)
+	})
+}
+
+func (store *RetryStore) UpdateAccountCurrency(ctx context.Context, arg UpdateAccountCurrencyParams) (Account, error) {
+	return retryQuery(ctx, store, "UpdateAccountCurrency", func(ctx context.Context) (Account, error) {
+		return store.Store.UpdateAccountCurrency(ctx, arg)
+	})
+}
+
+func (store *RetryStore) UpdateAccountProfile(ctx context.Context, arg UpdateAccountProfileParams) (Account, error) {
+	return retryTx(ctx, store, "UpdateAccountProfile", func(ctx context.Context) (Account, error) {
+		return store.Store.UpdateAccountProfile(ctx, arg)
+	})
+}
+
+func (store *RetryStore) UpdateBatchRunCheckpoint(ctx context.Context, arg UpdateBatchRunCheckpointParams) error {
+	return retryExec(ctx, store, "UpdateBatchRunCheckpoint", func(ctx context.Context) error {
+		return store.Store.UpdateBatchRunCheckpoint(ctx, arg)
+	})
+}
+
+func (store *RetryStore) UpdateBeneficiary(ctx context.Context, arg UpdateBeneficiaryParams) (Beneficiary, error) {
+	return retryQuery(ctx, store, "UpdateBeneficiary", func(ctx context.Context) (Beneficiary, error) {
+		return store.Store.UpdateBeneficiary(ctx, arg)
+	})
+}
+
+func (store *RetryStore) UpdateCardStatus(ctx context.Context, arg UpdateCardStatusParams) (Card, error) {
+	return retryQuery(ctx, store, "UpdateCardStatus", func(ctx context.Context) (Card, error) {
+		return store.Store.UpdateCardStatus(ctx, arg)
+	})
+}
+
+func (store *RetryStore) UpdateExternalTransferStatus(ctx context.Context, arg UpdateExternalTransferStatusParams) (ExternalTransfer, error) {
+	return retryQuery(ctx, store, "UpdateExternalTransferStatus", func(ctx context.Context) (ExternalTransfer, error) {
+		return store.Store.UpdateExternalTransferStatus(ctx, arg)
+	})
+}
+
+func (store *RetryStore) UpdateJobProgress(ctx context.Context, arg UpdateJobProgressParams) (Job, error) {
+	return retryQuery(ctx, store, "UpdateJobProgress", func(ctx context.Context) (Job, error) {
+		return store.Store.UpdateJobProgress(ctx, arg)
+	})
+}
+
+func (store *RetryStore) UpdateOrganizationApprovalThreshold(ctx context.Context, arg UpdateOrganizationApprovalThresholdParams) (Organization, error) {
+	return retryQuery(ctx, store, "UpdateOrganizationApprovalThreshold", func(ctx context.Context) (Organization, error) {
+		return store.Store.UpdateOrganizationApprovalThreshold(ctx, arg)
+	})
+}
+
+func (store *RetryStore) UpdateProjectionCheckpoint(ctx context.Context, arg UpdateProjectionCheckpointParams) error {
+	return retryExec(ctx, store, "UpdateProjectionCheckpoint", func(ctx context.Context) error {
+		return store.Store.UpdateProjectionCheckpoint(ctx, arg)
+	})
+}
+
+func (store *RetryStore) UpdateUserRole(ctx context.Context, arg UpdateUserRoleParams) (User, error) {
+	return retryQuery(ctx, store, "UpdateUserRole", func(ctx context.Context) (User, error) {
+		return store.Store.UpdateUserRole(ctx, arg)
+	})
+}
+
+func (store *RetryStore) UpsertAccountAlert(ctx context.Context, arg UpsertAccountAlertParams) (AccountAlert, error) {
+	return retryQuery(ctx, store, "UpsertAccountAlert", func(ctx context.Context) (AccountAlert, error) {
+		return store.Store.UpsertAccountAlert(ctx, arg)
+	})
+}
+
+func (store *RetryStore) UpsertAccountOverview(ctx context.Context, arg UpsertAccountOverviewParams) error {
+	return retryExec(ctx, store, "UpsertAccountOverview", func(ctx context.Context) error {
+		return store.Store.UpsertAccountOverview(ctx, arg)
+	})
+}
+
+func (store *RetryStore) UpsertBudget(ctx context.Context, arg UpsertBudgetParams) (Budget, error) {
+	return retryQuery(ctx, store, "UpsertBudget", func(ctx context.Context) (Budget, error) {
+		return store.Store.UpsertBudget(ctx, arg)
+	})
+}
+
+func (store *RetryStore) UpsertLedgerAnomaly(ctx context.Context, arg UpsertLedgerAnomalyParams) (LedgerAnomaly, error) {
+	return retryQuery(ctx, store, "UpsertLedgerAnomaly", func(ctx context.Context) (LedgerAnomaly, error) {
+		return store.Store.UpsertLedgerAnomaly(ctx, arg)
+	})
+}
+
+func (store *RetryStore) UpsertLoginLockout(ctx context.Context, arg UpsertLoginLockoutParams) (LoginLockout, error) {
+	return retryQuery(ctx, store, "UpsertLoginLockout", func(ctx context.Context) (LoginLockout, error) {
+		return store.Store.UpsertLoginLockout(ctx, arg)
+	})
+}
+
+func (store *RetryStore) UpsertNotificationPreferences(ctx context.Context, arg UpsertNotificationPreferencesParams) (NotificationPreference, error) {
+	return retryQuery(ctx, store, "UpsertNotificationPreferences", func(ctx context.Context) (NotificationPreference, error) {
+		return store.Store.UpsertNotificationPreferences(ctx, arg)
+	})
+}
+
+func (store *RetryStore) UpsertUserAPIPlan(ctx context.Context, arg UpsertUserAPIPlanParams) (UserApiPlan, error) {
+	return retryQuery(ctx, store, "UpsertUserAPIPlan", func(ctx context.Context) (UserApiPlan, error) {
+		return store.Store.UpsertUserAPIPlan(ctx, arg)
+	})
+}
+
+func (store *RetryStore) UpsertUserOverview(ctx context.Context, arg UpsertUserOverviewParams) error {
+	return retryExec(ctx, store, "UpsertUserOverview", func(ctx context.Context) error {
+		return store.Store.UpsertUserOverview(ctx, arg)
+	})
+}
@@ -0,0 +1,182 @@
+package db
+
+import (
+	"context"
+	"net"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/require"
+)
+
+// The flakyStore type fails its calls with the errors in turn, then succeeds. The calls given a slow
+// error wait for their context to be done instead.
+type flakyStore struct {
+	Store
+	errs  []error
+	calls int
+}
+
+var errSlow = &pgconn.PgError{Code: "slow"}
+
+func (store *flakyStore) fail(ctx context.Context) error {
+	store.calls++
+	if store.calls > len(store.errs) {
+		return nil
+	}
+
+	err := store.errs[store.calls-1]
+	if err == errSlow {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	return err
+}
+
+func (store *flakyStore) TransferTx(ctx context.Context, arg TransferTxParams) (TransferTxResult, error) {
+	return TransferTxResult{}, store.fail(ctx)
+}
+
+func (store *flakyStore) GetAccount(ctx context.Context, id int64) (Account, error) {
+	return Account{ID: id}, store.fail(ctx)
+}
+
+func (store *flakyStore) CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transfer, error) {
+	return Transfer{}, store.fail(ctx)
+}
+
+func newTestRetryStore(store Store, policy RetryPolicy) *RetryStore {
+	retryStore := NewRetryStore(store, policy).(*RetryStore)
+	retryStore.sleep = func(ctx context.Context, d time.Duration) error { return nil }
+	return retryStore
+}
+
+func TestRetryStore(t *testing.T) {
+	serializationFailure := &pgconn.PgError{Code: SerializationFailure}
+	deadlock := &pgconn.PgError{Code: DeadlockDetected}
+	connectionReset := &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}
+
+	testCases := []struct {
+		name   string
+		errs   []error
+		call   func(store Store) error
+		calls  int
+		failed bool
+	}{
+		{
+			name: "SerializationFailures",
+			errs: []error{serializationFailure, deadlock},
+			call: func(store Store) error {
+				_, err := store.TransferTx(context.Background(), TransferTxParams{})
+				return err
+			},
+			calls: 3,
+		},
+		{
+			name: "TooManyRetries",
+			errs: []error{serializationFailure, serializationFailure, serializationFailure, serializationFailure},
+			call: func(store Store) error {
+				_, err := store.TransferTx(context.Background(), TransferTxParams{})
+				return err
+			},
+			calls:  4,
+			failed: true,
+		},
+		{
+			name: "PermanentError",
+			errs: []error{&pgconn.PgError{Code: UniqueViolation}},
+			call: func(store Store) error {
+				_, err := store.TransferTx(context.Background(), TransferTxParams{})
+				return err
+			},
+			calls:  1,
+			failed: true,
+		},
+		{
+			name: "ReadConnectionReset",
+			errs: []error{connectionReset},
+			call: func(store Store) error {
+				_, err := store.GetAccount(context.Background(), 1)
+				return err
+			},
+			calls: 2,
+		},
+		{
+			// the transfer may have been made before the connection was lost
+			name: "WriteConnectionReset",
+			errs: []error{connectionReset},
+			call: func(store Store) error {
+				_, err := store.CreateTransfer(context.Background(), CreateTransferParams{})
+				return err
+			},
+			calls:  1,
+			failed: true,
+		},
+		{
+			name: "ReadTimeout",
+			errs: []error{errSlow},
+			call: func(store Store) error {
+				_, err := store.GetAccount(context.Background(), 1)
+				return err
+			},
+			calls: 2,
+		},
+		{
+			name: "WriteTimeout",
+			errs: []error{errSlow},
+			call: func(store Store) error {
+				_, err := store.CreateTransfer(context.Background(), CreateTransferParams{})
+				return err
+			},
+			calls:  1,
+			failed: true,
+		},
+		{
+			name: "CallerCancelled",
+			errs: []error{serializationFailure},
+			call: func(store Store) error {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				_, err := store.TransferTx(ctx, TransferTxParams{})
+				return err
+			},
+			calls:  1,
+			failed: true,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			flaky := &flakyStore{errs: tc.errs}
+			store := newTestRetryStore(flaky, RetryPolicy{
+				QueryTimeout: 10 * time.Millisecond,
+				MaxRetries:   3,
+				Backoff:      time.Millisecond,
+			})
+
+			err := tc.call(store)
+			require.Equal(t, tc.failed, err != nil, err)
+			require.Equal(t, tc.calls, flaky.calls)
+		})
+	}
+}
+
+func TestRetryStoreBackoff(t *testing.T) {
+	store := newTestRetryStore(nil, RetryPolicy{Backoff: 10 * time.Millisecond, MaxBackoff: 50 * time.Millisecond})
+
+	// the backoff doubles with each retry until the max backoff
+	for retries, limit := range []time.Duration{10, 20, 40, 50, 50, 50} {
+		for i := 0; i < 20; i++ {
+			backoff := store.backoff(retries)
+			require.GreaterOrEqual(t, backoff, time.Duration(0))
+			require.Less(t, backoff, limit*time.Millisecond)
+		}
+	}
+
+	store.policy.Backoff = 0
+	require.Zero(t, store.backoff(2))
+}
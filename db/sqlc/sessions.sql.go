@@ -12,6 +12,32 @@ import (
 	"github.com/google/uuid"
 )
 
+const countUserSessions = `-- name: CountUserSessions :one
+SELECT
+    count(*) AS sessions,
+    count(*) FILTER (WHERE user_agent = $1) AS device_sessions
+FROM sessions
+WHERE username = $2
+`
+
+type CountUserSessionsParams struct {
+	UserAgent string `json:"user_agent"`
+	Username  string `json:"username"`
+}
+
+type CountUserSessionsRow struct {
+	Sessions       int64 `json:"sessions"`
+	DeviceSessions int64 `json:"device_sessions"`
+}
+
+// Counts the sessions of the user, along with those opened from the client with the user agent.
+func (q *Queries) CountUserSessions(ctx context.Context, arg CountUserSessionsParams) (CountUserSessionsRow, error) {
+	row := q.db.QueryRow(ctx, countUserSessions, arg.UserAgent, arg.Username)
+	var i CountUserSessionsRow
+	err := row.Scan(&i.Sessions, &i.DeviceSessions)
+	return i, err
+}
+
 const createSession = `-- name: CreateSession :one
 INSERT INTO sessions (
     id,
@@ -23,7 +49,7 @@ INSERT INTO sessions (
     expires_at
 ) VALUES (
     $1, $2, $3, $4, $5, $6, $7
-) RETURNING id, username, refresh_token, user_agent, client_ip, is_blocked, expires_at, created_at
+) RETURNING id, username, refresh_token, user_agent, client_ip, is_blocked, expires_at, created_at, last_used_at
 `
 
 type CreateSessionParams struct {
@@ -37,7 +63,7 @@ type CreateSessionParams struct {
 }
 
 func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error) {
-	row := q.db.QueryRowContext(ctx, createSession,
+	row := q.db.QueryRow(ctx, createSession,
 		arg.ID,
 		arg.Username,
 		arg.RefreshToken,
@@ -56,17 +82,28 @@ func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (S
 		&i.IsBlocked,
 		&i.ExpiresAt,
 		&i.CreatedAt,
+		&i.LastUsedAt,
 	)
 	return i, err
 }
 
+const deleteUserSessions = `-- name: DeleteUserSessions :exec
+DELETE FROM sessions WHERE username = $1
+`
+
+// Deletes the sessions of the user, their refresh tokens no longer renewing access tokens.
+func (q *Queries) DeleteUserSessions(ctx context.Context, username string) error {
+	_, err := q.db.Exec(ctx, deleteUserSessions, username)
+	return err
+}
+
 const getSession = `-- name: GetSession :one
-SELECT id, username, refresh_token, user_agent, client_ip, is_blocked, expires_at, created_at FROM sessions
+SELECT id, username, refresh_token, user_agent, client_ip, is_blocked, expires_at, created_at, last_used_at FROM sessions
 WHERE id = $1 LIMIT 1
 `
 
 func (q *Queries) GetSession(ctx context.Context, id uuid.UUID) (Session, error) {
-	row := q.db.QueryRowContext(ctx, getSession, id)
+	row := q.db.QueryRow(ctx, getSession, id)
 	var i Session
 	err := row.Scan(
 		&i.ID,
@@ -77,6 +114,23 @@ func (q *Queries) GetSession(ctx context.Context, id uuid.UUID) (Session, error)
 		&i.IsBlocked,
 		&i.ExpiresAt,
 		&i.CreatedAt,
+		&i.LastUsedAt,
 	)
 	return i, err
 }
+
+const touchSession = `-- name: TouchSession :exec
+UPDATE sessions
+SET last_used_at = GREATEST(last_used_at, $1)
+WHERE id = $2
+`
+
+type TouchSessionParams struct {
+	LastUsedAt time.Time `json:"last_used_at"`
+	ID         uuid.UUID `json:"id"`
+}
+
+func (q *Queries) TouchSession(ctx context.Context, arg TouchSessionParams) error {
+	_, err := q.db.Exec(ctx, touchSession, arg.LastUsedAt, arg.ID)
+	return err
+}
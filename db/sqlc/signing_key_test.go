@@ -0,0 +1,50 @@
+package db
+
+import (
+	"context"
+	"go-backend/util"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSigningKey(t *testing.T) {
+	user := createRandomUser(t)
+
+	key, err := testQueries.CreateSigningKey(context.Background(), CreateSigningKeyParams{
+		Username: user.Username,
+		Secret:   util.RandomString(32),
+	})
+	require.NoError(t, err)
+	require.Equal(t, user.Username, key.Username)
+	require.False(t, key.RevokedAt.Valid)
+
+	key2, err := testQueries.GetSigningKey(context.Background(), key.ID)
+	require.NoError(t, err)
+	require.Equal(t, key, key2)
+
+	count, err := testQueries.CountActiveSigningKeys(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+
+	// only the owner of the key can revoke it
+	_, err = testQueries.RevokeSigningKey(context.Background(), RevokeSigningKeyParams{ID: key.ID, Username: util.RandomOwner()})
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	revoked, err := testQueries.RevokeSigningKey(context.Background(), RevokeSigningKeyParams{ID: key.ID, Username: user.Username})
+	require.NoError(t, err)
+	require.True(t, revoked.RevokedAt.Valid)
+
+	// a key is revoked once
+	_, err = testQueries.RevokeSigningKey(context.Background(), RevokeSigningKeyParams{ID: key.ID, Username: user.Username})
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	count, err = testQueries.CountActiveSigningKeys(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Zero(t, count)
+
+	keys, err := testQueries.ListSigningKeys(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	require.Equal(t, revoked, keys[0])
+}
@@ -0,0 +1,126 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Statuses of a standing order. Only active standing orders run, a cancelled one staying cancelled.
+const (
+	StandingOrderActive    = "active"
+	StandingOrderCancelled = "cancelled"
+)
+
+// Frequencies of a standing order.
+const (
+	StandingOrderWeekly  = "weekly"
+	StandingOrderMonthly = "monthly"
+)
+
+var (
+	// ErrStandingOrderCancelled is returned when running a standing order that was cancelled.
+	ErrStandingOrderCancelled = errors.New("standing order has been cancelled")
+	// ErrStandingOrderNotDue is returned when running a standing order before its next run.
+	ErrStandingOrderNotDue = errors.New("standing order is not due")
+	// ErrStandingOrderNotOwner is returned when the owner of a standing order can no longer make
+	// transactions with its from account, e.g. once they were removed from a joint account.
+	ErrStandingOrderNotOwner = errors.New("owner of the standing order can no longer use the account")
+)
+
+// StandingOrderRunAt returns the run of a standing order at index `n`, 0 being its first run at
+// `startsAt`. The monthly runs fall on the day of the month of the first run, or on the last day of the
+// months too short for it, rather than drifting after such a month.
+func StandingOrderRunAt(frequency string, startsAt time.Time, n int) time.Time {
+	if frequency == StandingOrderWeekly {
+		return startsAt.AddDate(0, 0, 7*n)
+	}
+
+	firstOfMonth := time.Date(startsAt.Year(), startsAt.Month()+time.Month(n), 1,
+		startsAt.Hour(), startsAt.Minute(), startsAt.Second(), startsAt.Nanosecond(), startsAt.Location())
+	day := startsAt.Day()
+	if lastDay := firstOfMonth.AddDate(0, 1, -1).Day(); day > lastDay {
+		day = lastDay
+	}
+	return firstOfMonth.AddDate(0, 0, day-1)
+}
+
+// NextStandingOrderRun returns the count of the runs of the standing order due at `now`, along with
+// its first run after `now`. The runs missed while the bank was down are skipped rather than all made at
+// once, so that the payer isn't charged several times in a row.
+func NextStandingOrderRun(order StandingOrder, now time.Time) (int32, time.Time) {
+	n := int(order.RunCount) + 1
+	next := StandingOrderRunAt(order.Frequency, order.StartsAt, n)
+	for !next.After(now) {
+		n++
+		next = StandingOrderRunAt(order.Frequency, order.StartsAt, n)
+	}
+	return int32(n), next
+}
+
+// The RunStandingOrderTxParams type contains the run of a standing order.
+// @property {int64} ID - the standing order run.
+// @property {time.Time} Now - the time of the run, the standing order must be due by then.
+type RunStandingOrderTxParams struct {
+	ID  int64
+	Now time.Time
+}
+
+// The RunStandingOrderTxResult type is the standing order run, along with the transfer of the run.
+type RunStandingOrderTxResult struct {
+	StandingOrder StandingOrder    `json:"standing_order"`
+	Transfer      TransferTxResult `json:"transfer"`
+}
+
+// RunStandingOrderTx makes the transfer of a due standing order and schedules its next run. The
+// standing order is locked so that a run can't race with its cancellation nor with another run,
+// ErrStandingOrderCancelled being returned once it is cancelled and ErrStandingOrderNotDue when it was run
+// meanwhile. The transfer fails with ErrStandingOrderNotOwner when the owner can no longer make
+// transactions with the from account, and with the errors of `TransferTx` otherwise, the run then being
+// recorded as failed with `RecordStandingOrderRun` by the caller.
+func (store *SQLStore) RunStandingOrderTx(ctx context.Context, arg RunStandingOrderTxParams) (RunStandingOrderTxResult, error) {
+	var result RunStandingOrderTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		order, err := q.GetStandingOrderForUpdate(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+		if order.Status != StandingOrderActive {
+			return ErrStandingOrderCancelled
+		}
+		if order.NextRunAt.After(arg.Now) {
+			return ErrStandingOrderNotDue
+		}
+
+		result.Transfer, err = transfer(ctx, q, TransferTxParams{
+			FromAccountID: order.FromAccountID,
+			ToAccountID:   order.ToAccountID,
+			Amount:        order.Amount,
+			Memo:          order.Memo,
+			Currency:      order.Currency,
+		}, nil)
+		if err != nil {
+			return err
+		}
+
+		role, err := AccountRole(ctx, q, result.Transfer.FromAccount, order.Owner)
+		if err != nil {
+			return err
+		}
+		if role != AccountRoleOwner {
+			return ErrStandingOrderNotOwner
+		}
+
+		runCount, nextRunAt := NextStandingOrderRun(order, arg.Now)
+		result.StandingOrder, err = q.RecordStandingOrderRun(ctx, RecordStandingOrderRunParams{
+			RunCount:  runCount,
+			NextRunAt: nextRunAt,
+			ID:        order.ID,
+			DueAt:     order.NextRunAt,
+		})
+		return err
+	})
+
+	return result, err
+}
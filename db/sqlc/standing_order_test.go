@@ -0,0 +1,104 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStandingOrderRunAt(t *testing.T) {
+	startsAt := time.Date(2024, time.January, 31, 9, 0, 0, 0, time.UTC)
+
+	// the monthly runs fall on the last day of the shorter months, then back on the 31st
+	require.Equal(t, time.Date(2024, time.February, 29, 9, 0, 0, 0, time.UTC), StandingOrderRunAt(StandingOrderMonthly, startsAt, 1))
+	require.Equal(t, time.Date(2024, time.March, 31, 9, 0, 0, 0, time.UTC), StandingOrderRunAt(StandingOrderMonthly, startsAt, 2))
+	require.Equal(t, time.Date(2025, time.January, 31, 9, 0, 0, 0, time.UTC), StandingOrderRunAt(StandingOrderMonthly, startsAt, 12))
+	require.Equal(t, time.Date(2024, time.February, 14, 9, 0, 0, 0, time.UTC), StandingOrderRunAt(StandingOrderWeekly, startsAt, 2))
+
+	// the runs missed are skipped
+	order := StandingOrder{Frequency: StandingOrderWeekly, StartsAt: startsAt}
+	runCount, next := NextStandingOrderRun(order, startsAt.AddDate(0, 0, 15))
+	require.Equal(t, int32(3), runCount)
+	require.Equal(t, startsAt.AddDate(0, 0, 21), next)
+}
+
+func TestRunStandingOrderTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	fromAccount := createRandomAccount(t)
+	toAccount := createRandomAccount(t)
+	startsAt := time.Now().Add(-time.Minute).UTC().Truncate(time.Microsecond)
+	order, err := testQueries.CreateStandingOrder(context.Background(), CreateStandingOrderParams{
+		Owner:         fromAccount.Owner,
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   toAccount.ID,
+		Amount:        10,
+		Currency:      fromAccount.Currency,
+		Memo:          "rent",
+		Frequency:     StandingOrderMonthly,
+		StartsAt:      startsAt,
+	})
+	require.NoError(t, err)
+	require.Equal(t, StandingOrderActive, order.Status)
+	require.WithinDuration(t, startsAt, order.NextRunAt, time.Microsecond)
+
+	due, err := testQueries.ListDueStandingOrders(context.Background(), ListDueStandingOrdersParams{NextRunAt: time.Now(), Limit: 1000})
+	require.NoError(t, err)
+	require.Contains(t, standingOrderIDs(due), order.ID)
+
+	result, err := store.RunStandingOrderTx(context.Background(), RunStandingOrderTxParams{ID: order.ID, Now: time.Now()})
+	require.NoError(t, err)
+	require.Equal(t, fromAccount.Balance-10, result.Transfer.FromAccount.Balance)
+	require.Equal(t, toAccount.Balance+10, result.Transfer.ToAccount.Balance)
+	require.Equal(t, "rent", result.Transfer.Transfer.Memo)
+	require.Equal(t, int32(1), result.StandingOrder.RunCount)
+	require.True(t, result.StandingOrder.NextRunAt.After(time.Now()))
+	require.True(t, result.StandingOrder.LastRunAt.Valid)
+
+	// the next run isn't due yet
+	_, err = store.RunStandingOrderTx(context.Background(), RunStandingOrderTxParams{ID: order.ID, Now: time.Now()})
+	require.ErrorIs(t, err, ErrStandingOrderNotDue)
+
+	// a failed run is recorded once, and a run recorded meanwhile isn't recorded again
+	next := result.StandingOrder.NextRunAt
+	failed, err := testQueries.RecordStandingOrderRun(context.Background(), RecordStandingOrderRunParams{
+		RunCount:    2,
+		NextRunAt:   next.AddDate(0, 1, 0),
+		LastFailure: ErrInsufficientAvailableBalance.Error(),
+		ID:          order.ID,
+		DueAt:       next,
+	})
+	require.NoError(t, err)
+	require.Equal(t, ErrInsufficientAvailableBalance.Error(), failed.LastFailure)
+	_, err = testQueries.RecordStandingOrderRun(context.Background(), RecordStandingOrderRunParams{
+		RunCount:  2,
+		NextRunAt: next.AddDate(0, 1, 0),
+		ID:        order.ID,
+		DueAt:     next,
+	})
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	cancelled, err := testQueries.CancelStandingOrder(context.Background(), order.ID)
+	require.NoError(t, err)
+	require.Equal(t, StandingOrderCancelled, cancelled.Status)
+	_, err = testQueries.CancelStandingOrder(context.Background(), order.ID)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	_, err = store.RunStandingOrderTx(context.Background(), RunStandingOrderTxParams{ID: order.ID, Now: next.AddDate(1, 0, 0)})
+	require.ErrorIs(t, err, ErrStandingOrderCancelled)
+
+	orders, err := testQueries.ListStandingOrders(context.Background(), ListStandingOrdersParams{Owner: fromAccount.Owner, Limit: 5})
+	require.NoError(t, err)
+	require.Len(t, orders, 1)
+	require.Equal(t, int32(2), orders[0].RunCount)
+}
+
+func standingOrderIDs(orders []StandingOrder) []int64 {
+	ids := make([]int64, len(orders))
+	for i, order := range orders {
+		ids[i] = order.ID
+	}
+	return ids
+}
@@ -2,31 +2,96 @@ package db
 
 import (
 	"context"
-	"database/sql"
+	"errors"
 	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type Store interface {
 	Querier
 	TransferTx(ctx context.Context, arg TransferTxParams) (TransferTxResult, error)
+	BatchTransferTx(ctx context.Context, arg BatchTransferTxParams) ([]BatchTransferTxItem, error)
+	ExchangeTx(ctx context.Context, arg ExchangeTxParams) (ExchangeTxResult, error)
+	ConvertAccountTx(ctx context.Context, arg ConvertAccountTxParams) (ConvertAccountTxResult, error)
+	ProjectEventsTx(ctx context.Context, arg ProjectEventsTxParams) (int, error)
+	CapitalizeInterestTx(ctx context.Context, arg CapitalizeInterestTxParams) (BatchTxResult, error)
+	ChargeMonthlyFeeTx(ctx context.Context, arg ChargeMonthlyFeeTxParams) (BatchTxResult, error)
+	SnapshotBalancesTx(ctx context.Context, arg SnapshotBalancesTxParams) (BatchTxResult, error)
+	HoldTransferTx(ctx context.Context, arg HoldTransferTxParams) (HoldTransferTxResult, error)
+	DecideTransferReviewTx(ctx context.Context, arg DecideTransferReviewTxParams) (DecideTransferReviewTxResult, error)
+	ReconcileLedgerTx(ctx context.Context) (ReconcileLedgerTxResult, error)
+	AdjustAccountBalanceTx(ctx context.Context, arg AdjustAccountBalanceTxParams) (TransferTxResult, error)
+	ImportEntriesTx(ctx context.Context, arg ImportEntriesTxParams) (ImportEntriesTxResult, error)
+	AcceptPaymentRequestTx(ctx context.Context, arg AcceptPaymentRequestTxParams) (AcceptPaymentRequestTxResult, error)
+	PayPaymentLinkTx(ctx context.Context, arg PayPaymentLinkTxParams) (PayPaymentLinkTxResult, error)
+	CreatePendingTransferTx(ctx context.Context, arg CreatePendingTransferTxParams) (PendingTransfer, error)
+	ApprovePendingTransferTx(ctx context.Context, arg ApprovePendingTransferTxParams) (ApprovePendingTransferTxResult, error)
+	PullMandateTx(ctx context.Context, arg PullMandateTxParams) (PullMandateTxResult, error)
+	RunStandingOrderTx(ctx context.Context, arg RunStandingOrderTxParams) (RunStandingOrderTxResult, error)
+	InitiateExternalTransferTx(ctx context.Context, arg InitiateExternalTransferTxParams) (InitiateExternalTransferTxResult, error)
+	AdvanceExternalTransferTx(ctx context.Context, arg AdvanceExternalTransferTxParams) (AdvanceExternalTransferTxResult, error)
+	ReleaseHoldTx(ctx context.Context, id int64) (Hold, error)
+	ExpireHoldsTx(ctx context.Context, expiresAt time.Time) ([]Hold, error)
+	AuthorizeCardTx(ctx context.Context, arg AuthorizeCardTxParams) (AuthorizeCardTxResult, error)
+	CaptureCardHoldTx(ctx context.Context, arg CaptureCardHoldTxParams) (CaptureCardHoldTxResult, error)
+	DepositChequeTx(ctx context.Context, arg DepositChequeTxParams) (DepositChequeTxResult, error)
+	ClearChequeDepositTx(ctx context.Context, id int64) (ChequeDeposit, error)
+	BounceChequeDepositTx(ctx context.Context, id int64) (BounceChequeDepositTxResult, error)
+	RecordLoginFailureTx(ctx context.Context, arg RecordLoginFailureTxParams) (RecordLoginFailureTxResult, error)
+	UnlockUserTx(ctx context.Context, username string) error
+	CreateUserWithRoleTx(ctx context.Context, arg CreateUserParams, role string) (User, error)
+	CreateUserWithIdentityTx(ctx context.Context, arg CreateUserWithIdentityTxParams) (User, error)
+	DeleteUserDataTx(ctx context.Context, username string) (DeleteUserDataTxResult, error)
+}
+
+// The ConnPool interface is the pool of connections to the primary database the store runs its queries
+// and transactions on, e.g. a `pgxpool.Pool` or a `FailoverPool`.
+type ConnPool interface {
+	DBTX
+	BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error)
 }
 
-// The Store type contains a pointer to a Queries struct and a pointer to a sql.DB struct.
+// The Store type contains a pointer to a Queries struct and a connection pool.
 // @property {Queries}  - The `Store` struct has two properties:
-// @property db - The `db` property is a pointer to a `sql.DB` object, which represents a database
-// connection pool. It is used to execute SQL queries and interact with the database.
+// @property connPool - The `connPool` property is the pool of connections to the primary database,
+// such as a `pgxpool.Pool`. It is used to execute SQL queries and interact with the database.
+// @property {pgx.TxOptions} transferTxOptions - the options of the transactions of `TransferTx`.
 type SQLStore struct {
 	*Queries
-	db *sql.DB
+	connPool          ConnPool
+	transferTxOptions pgx.TxOptions
+}
+
+// The StoreOption type changes how a `SQLStore` runs its transactions.
+type StoreOption func(store *SQLStore)
+
+// The `WithTransferIsolation` function runs the transactions of `TransferTx` at the isolation level,
+// e.g. `pgx.Serializable` for the deployments that prefer the correctness of every transfer over their
+// throughput. The transfers failing with a serialization failure are retried by a `RetryStore`.
+func WithTransferIsolation(level pgx.TxIsoLevel) StoreOption {
+	return func(store *SQLStore) {
+		store.transferTxOptions.IsoLevel = level
+	}
 }
 
-// The function creates a new instance of a Store struct with a given database connection and
+// The function creates a new instance of a Store struct with a given database connection pool and
 // associated queries.
-func NewStore(db *sql.DB) Store {
-	return &SQLStore{
-		db:      db,
-		Queries: New(db),
+func NewStore(connPool ConnPool, options ...StoreOption) Store {
+	return newSQLStore(connPool, New(connPool), options)
+}
+
+func newSQLStore(connPool ConnPool, queries *Queries, options []StoreOption) *SQLStore {
+	store := &SQLStore{
+		connPool: connPool,
+		Queries:  queries,
+	}
+	for _, option := range options {
+		option(store)
 	}
+	return store
 }
 
 // This function `execTx` is used to execute a function within a database transaction. It takes a
@@ -34,7 +99,13 @@ func NewStore(db *sql.DB) Store {
 // `*Queries` object as input and returns an error. The `*Queries` object is used to execute database
 // queries within the transaction.
 func (store *SQLStore) execTx(ctx context.Context, fn func(*Queries) error) error {
-	tx, err := store.db.BeginTx(ctx, nil)
+	return store.execTxWithOptions(ctx, pgx.TxOptions{}, fn)
+}
+
+// The `execTxWithOptions` function executes the function within a database transaction like `execTx`,
+// the transaction being started with the options, e.g. its isolation level or access mode.
+func (store *SQLStore) execTxWithOptions(ctx context.Context, txOptions pgx.TxOptions, fn func(*Queries) error) error {
+	tx, err := store.connPool.BeginTx(ctx, txOptions)
 
 	if err != nil {
 		return err
@@ -46,13 +117,13 @@ func (store *SQLStore) execTx(ctx context.Context, fn func(*Queries) error) erro
 
 	// rollback transaction
 	if err != nil {
-		if rbErr := tx.Rollback(); rbErr != nil {
+		if rbErr := tx.Rollback(ctx); rbErr != nil {
 			return fmt.Errorf("tx err: %v\nrb err: %v", err, rbErr)
 		}
 		return err
 	}
 
-	return tx.Commit()
+	return tx.Commit(ctx)
 }
 
 // The TransferTxParams type defines parameters for a transfer transaction, including the IDs of the
@@ -64,10 +135,31 @@ func (store *SQLStore) execTx(ctx context.Context, fn func(*Queries) error) erro
 // @property {int64} Amount - The `Amount` property is an integer that represents the amount of a
 // currency being transferred from one account to another. It could be a positive or negative value
 // depending on whether the transfer is a deposit or a withdrawal.
+// @property {string} Memo - optional free text written by the sender.
+// @property {string} ExternalReference - optional reference of the payment outside the bank.
+// @property {string} Currency - the currency both accounts were checked to hold, checked again once
+// their rows are locked so that a currency changed meanwhile fails the transfer with
+// ErrAccountVersionMismatch. It isn't checked when empty.
+// @property {CreateQueuedTransferParams} Queued - the queued transfer this transfer processes, whose
+// outcome is recorded in the same transaction, when the transfer was queued.
+// @property {bool} Refund - whether the transfer gives back money the to account sent, e.g. that of a
+// failed external transfer, which a closed account still receives so that the money isn't lost. Any
+// other transfer to or from a closed account fails with ErrAccountClosed.
+// @property {bool} ChargeFee - whether the transfer fee active when the transfer is made is charged to
+// the from account, as it is for the transfers the customers ask for but not for those the bank makes.
+// @property {time.Time} Now - the time the transfer is made at by the clock of the caller, which the fee
+// charged with ChargeFee is the one active at.
 type TransferTxParams struct {
-	FromAccountID int64 `json:"from_account_id"`
-	ToAccountID   int64 `json:"to_account_id"`
-	Amount        int64 `json:"amount"`
+	FromAccountID     int64                       `json:"from_account_id"`
+	ToAccountID       int64                       `json:"to_account_id"`
+	Amount            int64                       `json:"amount"`
+	Memo              string                      `json:"memo"`
+	ExternalReference string                      `json:"external_reference"`
+	Currency          string                      `json:"currency"`
+	Queued            *CreateQueuedTransferParams `json:"-"`
+	Refund            bool                        `json:"-"`
+	ChargeFee         bool                        `json:"-"`
+	Now               time.Time                   `json:"-"`
 }
 
 // The TransferTxResult type represents the result of a transfer transaction, including information
@@ -81,6 +173,8 @@ type TransferTxParams struct {
 // @property {Account} ToAccount - ToAccount is a property of the TransferTxResult struct and
 // represents the account that received the transfer in a transaction. It is of type Account, which
 // likely contains information such as the account holder's name, account number, and balance.
+// @property {Transfer} Fee - the transfer of the fee from the from account to the fees account of its
+// currency, when a fee was charged.
 // @property {Entry} FromEntry - FromEntry is a property of the TransferTxResult struct that represents
 // the entry (transaction) from which the transfer was made. It contains information such as the entry
 // ID, the account ID from which the transfer was made, the amount transferred, and the time at which
@@ -90,60 +184,180 @@ type TransferTxParams struct {
 // record of a financial transaction that includes information such as the amount transferred, the date
 // and time of the transaction, and the accounts involved.
 type TransferTxResult struct {
-	Transfer    Transfer `json:"transfer"`
-	FromAccount Account  `json:"from_account"`
-	ToAccount   Account  `json:"to_account"`
-	FromEntry   Entry    `json:"from_entry"`
-	ToEntry     Entry    `json:"to_entry"`
+	Transfer    Transfer  `json:"transfer"`
+	FromAccount Account   `json:"from_account"`
+	ToAccount   Account   `json:"to_account"`
+	FromEntry   Entry     `json:"from_entry"`
+	ToEntry     Entry     `json:"to_entry"`
+	Fee         *Transfer `json:"fee,omitempty"`
 }
 
-// TransferTx
+// TransferTx moves the amount between the accounts, recording the transfer, its entries and events in
+// one transaction, along with the outcome of the queued transfer it processes. The transaction runs at
+// the isolation level set with `WithTransferIsolation`. The duration of each step is observed in
+// TransferTxStepDuration.
 func (store *SQLStore) TransferTx(ctx context.Context, arg TransferTxParams) (TransferTxResult, error) {
 	var result TransferTxResult
+	timer := newStepTimer(TransferTxStepDuration)
+
+	err := store.execTxWithOptions(ctx, store.transferTxOptions, func(q *Queries) error {
+		timer.step(transferStepBegin)
 
-	err := store.execTx(ctx, func(q *Queries) error {
 		var err error
-		result.Transfer, err = q.CreateTransfer(ctx, CreateTransferParams{
-			FromAccountID: arg.FromAccountID,
-			ToAccountID:   arg.ToAccountID,
-			Amount:        arg.Amount,
-		})
-		if err != nil {
+		result, err = transfer(ctx, q, arg, timer)
+		if err != nil || arg.Queued == nil {
 			return err
 		}
 
-		// create from entry
-		result.FromEntry, err = q.CreateEntry(ctx, CreateEntryParams{
-			AccountID: arg.FromAccountID,
-			Amount:    -arg.Amount,
-		})
-		if err != nil {
-			return err
-		}
+		queued := *arg.Queued
+		queued.Status = QueuedTransferSucceeded
+		queued.TransferID = pgtype.Int8{Int64: result.Transfer.ID, Valid: true}
+		_, err = recordQueuedTransfer(ctx, q, queued)
+		return err
+	})
+	if err != nil {
+		return result, err
+	}
+
+	timer.step(transferStepCommit)
+	timer.observe()
+	return result, nil
+}
+
+// transfer moves the amount between the accounts within the transaction of `q`, timing its steps with
+// `timer` when it is set.
+func transfer(ctx context.Context, q *Queries, arg TransferTxParams, timer *stepTimer) (TransferTxResult, error) {
+	var result TransferTxResult
+
+	var err error
+	result.Transfer, err = q.CreateTransfer(ctx, CreateTransferParams{
+		FromAccountID:     arg.FromAccountID,
+		ToAccountID:       arg.ToAccountID,
+		Amount:            arg.Amount,
+		Memo:              arg.Memo,
+		ExternalReference: arg.ExternalReference,
+	})
+	if err != nil {
+		return result, err
+	}
+	timer.step(transferStepCreateTransfer)
+
+	// create from entry
+	result.FromEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+		AccountID:  arg.FromAccountID,
+		Amount:     -arg.Amount,
+		TransferID: pgtype.Int8{Int64: result.Transfer.ID, Valid: true},
+	})
+	if err != nil {
+		return result, err
+	}
+
+	// create to entry
+	result.ToEntry, err = q.CreateEntry(ctx, CreateEntryParams{
+		AccountID:  arg.ToAccountID,
+		Amount:     arg.Amount,
+		TransferID: pgtype.Int8{Int64: result.Transfer.ID, Valid: true},
+	})
+	if err != nil {
+		return result, err
+	}
+	timer.step(transferStepCreateEntries)
 
-		// create to entry
-		result.ToEntry, err = q.CreateEntry(ctx, CreateEntryParams{
-			AccountID: arg.ToAccountID,
-			Amount:    arg.Amount,
-		})
+	// update accounts balances
+	if arg.FromAccountID < arg.ToAccountID {
+		result.FromAccount, result.ToAccount, err = addMoney(ctx, q, arg.FromAccountID, -arg.Amount, arg.ToAccountID, arg.Amount)
+	} else {
+		result.ToAccount, result.FromAccount, err = addMoney(ctx, q, arg.ToAccountID, arg.Amount, arg.FromAccountID, -arg.Amount)
+	}
+
+	if err != nil {
+		return result, err
+	}
+	if arg.Currency != "" && (result.FromAccount.Currency != arg.Currency || result.ToAccount.Currency != arg.Currency) {
+		return result, ErrAccountVersionMismatch
+	}
+	if result.FromAccount.ClosedAt.Valid || (result.ToAccount.ClosedAt.Valid && !arg.Refund) {
+		return result, ErrAccountClosed
+	}
+	if arg.ChargeFee {
+		err = chargeTransferFee(ctx, q, &result, arg.Now)
 		if err != nil {
-			return err
+			return result, err
 		}
+	}
+	// the debited account can only spend its available balance, the system accounts excepted
+	if !IsSystemAccount(result.FromAccount) && AvailableBalance(result.FromAccount) < 0 {
+		return result, ErrInsufficientAvailableBalance
+	}
+	timer.step(transferStepUpdateBalances)
 
-		// update accounts balances
-		if arg.FromAccountID < arg.ToAccountID {
-			result.FromAccount, result.ToAccount, err = addMoney(ctx, q, arg.FromAccountID, -arg.Amount, arg.ToAccountID, arg.Amount)
-		} else {
-			result.ToAccount, result.FromAccount, err = addMoney(ctx, q, arg.ToAccountID, arg.Amount, arg.FromAccountID, -arg.Amount)
-		}
+	err = recordEvent(ctx, q, EventTransferCompleted, TransferCompletedEvent{
+		TransferID:         result.Transfer.ID,
+		FromAccountID:      arg.FromAccountID,
+		ToAccountID:        arg.ToAccountID,
+		Amount:             arg.Amount,
+		FromAccountBalance: result.FromAccount.Balance,
+		ToAccountBalance:   result.ToAccount.Balance,
+		CreatedAt:          result.Transfer.CreatedAt,
+	})
+	if err != nil {
+		return result, err
+	}
 
-		if err != nil {
-			return err
+	// each side is told about the transfer from its own point of view
+	err = recordEvent(ctx, q, EventTransferSent, newTransferPartyEvent(result.Transfer, result.FromAccount, -arg.Amount, result.ToAccount))
+	if err != nil {
+		return result, err
+	}
+
+	err = recordEvent(ctx, q, EventTransferReceived, newTransferPartyEvent(result.Transfer, result.ToAccount, arg.Amount, result.FromAccount))
+	if err != nil {
+		return result, err
+	}
+	timer.step(transferStepRecordEvents)
+
+	return result, nil
+}
+
+// chargeTransferFee moves the transfer fee active at `now` from the from account of the transfer to the
+// fees account of its currency, before the available balance of the from account is checked so that it
+// covers both. Nothing is charged while no fee is published.
+func chargeTransferFee(ctx context.Context, q *Queries, result *TransferTxResult, now time.Time) error {
+	parameter, err := q.GetActiveBankParameter(ctx, GetActiveBankParameterParams{
+		Name: ParameterTransferFee,
+		At:   now,
+	})
+	if err != nil {
+		if errors.Is(err, ErrRecordNotFound) {
+			return nil
 		}
+		return err
+	}
+	if parameter.Value <= 0 || IsSystemAccount(result.FromAccount) {
 		return nil
+	}
+
+	fees, err := q.GetSystemAccount(ctx, GetSystemAccountParams{
+		Purpose:  SystemAccountFees,
+		Currency: result.FromAccount.Currency,
 	})
+	if err != nil {
+		return err
+	}
+
+	fee, err := transfer(ctx, q, TransferTxParams{
+		FromAccountID: result.FromAccount.ID,
+		ToAccountID:   fees.ID,
+		Amount:        parameter.Value,
+		Memo:          fmt.Sprintf("fee for transfer %d", result.Transfer.ID),
+	}, nil)
+	if err != nil {
+		return err
+	}
 
-	return result, err
+	result.Fee = &fee.Transfer
+	result.FromAccount = fee.FromAccount
+	return nil
 }
 
 func addMoney(ctx context.Context, q *Queries, accountID1 int64, amount1 int64, accountID2 int64, amount2 int64) (account1 Account, account2 Account, err error) {
@@ -2,8 +2,12 @@ package db
 
 import (
 	"context"
+	"go-backend/util"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/stretchr/testify/require"
 )
 
@@ -153,3 +157,215 @@ func TestTransferTxDeadlock(t *testing.T) {
 	require.Equal(t, account1.Balance, updateAccount1.Balance)
 	require.Equal(t, account2.Balance, updateAccount2.Balance)
 }
+
+func TestTransferTxCurrencyChanged(t *testing.T) {
+	store := NewStore(testDB)
+
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	// the accounts were checked to hold a currency neither holds any more
+	_, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+		Currency:      "JPY",
+	})
+	require.ErrorIs(t, err, ErrAccountVersionMismatch)
+
+	updatedAccount1, err := testQueries.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance, updatedAccount1.Balance)
+}
+
+func TestTransferTxChargesFee(t *testing.T) {
+	store := NewStore(testDB)
+	admin := createRandomUser(t)
+
+	publishFee := func(fee int64) {
+		_, err := testQueries.CreateBankParameter(context.Background(), CreateBankParameterParams{
+			Name:        ParameterTransferFee,
+			Value:       fee,
+			EffectiveAt: time.Now(),
+			PublishedBy: admin.Username,
+		})
+		require.NoError(t, err)
+	}
+	publishFee(7)
+	// the fee is withdrawn again for the other tests
+	defer publishFee(0)
+
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+	fees, err := testQueries.GetSystemAccount(context.Background(), GetSystemAccountParams{
+		Purpose:  SystemAccountFees,
+		Currency: account1.Currency,
+	})
+	require.NoError(t, err)
+
+	result, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+		ChargeFee:     true,
+		Now:           time.Now(),
+	})
+	require.NoError(t, err)
+	require.NotNil(t, result.Fee)
+	require.Equal(t, int64(7), result.Fee.Amount)
+	require.Equal(t, fees.ID, result.Fee.ToAccountID)
+	require.Equal(t, account1.Balance-17, result.FromAccount.Balance)
+
+	updatedFees, err := testQueries.GetAccount(context.Background(), fees.ID)
+	require.NoError(t, err)
+	require.Equal(t, fees.Balance+7, updatedFees.Balance)
+
+	// the transfers the bank makes aren't charged
+	result, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+	})
+	require.NoError(t, err)
+	require.Nil(t, result.Fee)
+	require.Equal(t, account1.Balance-27, result.FromAccount.Balance)
+
+	// the fee charged is the one active when the transfer is made, by the clock of the caller
+	result, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        10,
+		ChargeFee:     true,
+		Now:           time.Now().Add(-time.Hour),
+	})
+	require.NoError(t, err)
+	require.Nil(t, result.Fee)
+	require.Equal(t, account1.Balance-37, result.FromAccount.Balance)
+
+	// the fee can't overdraw the account
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        account1.Balance - 37,
+		ChargeFee:     true,
+		Now:           time.Now(),
+	})
+	require.ErrorIs(t, err, ErrInsufficientAvailableBalance)
+}
+
+// createBenchmarkAccounts creates n accounts in the same currency, funded well enough for every
+// transfer of a benchmark to go through.
+func createBenchmarkAccounts(b *testing.B, n int) []Account {
+	currency := util.RandomCurrency()
+
+	accounts := make([]Account, n)
+	for i := range accounts {
+		user := createRandomUser(b)
+
+		account, err := testQueries.CreateAccount(context.Background(), CreateAccountParams{
+			Owner:    user.Username,
+			Balance:  1_000_000_000,
+			Currency: currency,
+		})
+		require.NoError(b, err)
+		accounts[i] = account
+	}
+
+	return accounts
+}
+
+// BenchmarkTransferTx measures the throughput of transfers made one after the other between two
+// accounts, moving the money back and forth.
+func BenchmarkTransferTx(b *testing.B) {
+	store := NewStore(testDB)
+	accounts := createBenchmarkAccounts(b, 2)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		from, to := accounts[i%2], accounts[(i+1)%2]
+
+		_, err := store.TransferTx(context.Background(), TransferTxParams{
+			FromAccountID: from.ID,
+			ToAccountID:   to.ID,
+			Amount:        1,
+		})
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkTransferTxParallel measures the throughput of concurrent transfers, either contending for the
+// locks of the same two accounts or spread over distinct pairs of accounts.
+func BenchmarkTransferTxParallel(b *testing.B) {
+	testCases := []struct {
+		name  string
+		pairs int
+	}{
+		{name: "hot", pairs: 1},
+		{name: "spread", pairs: 32},
+	}
+
+	for _, tc := range testCases {
+		b.Run(tc.name, func(b *testing.B) {
+			store := NewStore(testDB)
+			accounts := createBenchmarkAccounts(b, tc.pairs*2)
+
+			var next int64
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					i := int(atomic.AddInt64(&next, 1))
+					pair := accounts[(i%tc.pairs)*2 : (i%tc.pairs)*2+2]
+					// half should move money the opposite direction
+					from, to := pair[(i/tc.pairs)%2], pair[(i/tc.pairs+1)%2]
+
+					_, err := store.TransferTx(context.Background(), TransferTxParams{
+						FromAccountID: from.ID,
+						ToAccountID:   to.ID,
+						Amount:        1,
+					})
+					if err != nil {
+						b.Error(err)
+						return
+					}
+				}
+			})
+		})
+	}
+}
+
+func TestTransferTxSerializable(t *testing.T) {
+	// the transfers failing with a serialization failure are retried until they succeed
+	store := NewRetryStore(NewStore(testDB, WithTransferIsolation(pgx.Serializable)), RetryPolicy{
+		MaxRetries: 20,
+		Backoff:    10 * time.Millisecond,
+		MaxBackoff: 100 * time.Millisecond,
+	})
+
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	n := 10
+	amount := int64(10)
+	errs := make(chan error)
+
+	for i := 0; i < n; i++ {
+		go func() {
+			_, err := store.TransferTx(context.Background(), TransferTxParams{
+				FromAccountID: account1.ID,
+				ToAccountID:   account2.ID,
+				Amount:        amount,
+			})
+			errs <- err
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		require.NoError(t, <-errs)
+	}
+
+	updatedAccount1, err := testQueries.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance-int64(n)*amount, updatedAccount1.Balance)
+}
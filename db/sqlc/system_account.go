@@ -0,0 +1,30 @@
+package db
+
+import "errors"
+
+// SystemOwner is the user owning the system accounts of the bank, which can't log in.
+const SystemOwner = "system"
+
+// Purposes of the system accounts. The bank holds a system account per purpose in every currency,
+// created by migration, that its transactions post to, e.g. the fees charged for transfers.
+const (
+	SystemAccountFees     = "fees"
+	SystemAccountFXSpread = "fx_spread"
+	SystemAccountSuspense = "suspense"
+	// SystemAccountClearing holds the money sent out of the bank by external transfers until it settles.
+	SystemAccountClearing = "external_clearing"
+	// SystemAccountCardSettlement holds the card payments captured until the card network settles them.
+	SystemAccountCardSettlement = "card_settlement"
+	// SystemAccountChequeClearing credits the cheques deposited until the bank of their drawer pays them.
+	SystemAccountChequeClearing = "cheque_clearing"
+	// SystemAccountInterest pays the interest credited to the accounts.
+	SystemAccountInterest = "interest"
+)
+
+// ErrSystemAccount is returned when changing a system account outside of the transactions of the store.
+var ErrSystemAccount = errors.New("system accounts can only be changed by the transactions of the bank")
+
+// IsSystemAccount reports whether the account is a system account of the bank.
+func IsSystemAccount(account Account) bool {
+	return account.Owner == SystemOwner
+}
@@ -0,0 +1,46 @@
+package db
+
+import (
+	"context"
+	"go-backend/util"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetSystemAccount(t *testing.T) {
+	for _, purpose := range []string{SystemAccountFees, SystemAccountFXSpread, SystemAccountSuspense, SystemAccountClearing, SystemAccountCardSettlement, SystemAccountChequeClearing, SystemAccountInterest} {
+		for _, currency := range util.SupportedCurrencies {
+			account, err := testQueries.GetSystemAccount(context.Background(), GetSystemAccountParams{
+				Purpose:  purpose,
+				Currency: currency,
+			})
+			require.NoError(t, err)
+			require.True(t, IsSystemAccount(account))
+			require.Equal(t, currency, account.Currency)
+		}
+	}
+}
+
+func TestSystemAccountIsNotChanged(t *testing.T) {
+	store := NewStore(testDB)
+	account, err := testQueries.GetSystemAccount(context.Background(), GetSystemAccountParams{
+		Purpose:  SystemAccountSuspense,
+		Currency: util.USD,
+	})
+	require.NoError(t, err)
+
+	_, err = store.AdjustAccountBalanceTx(context.Background(), AdjustAccountBalanceTxParams{
+		ID:     account.ID,
+		Amount: 100,
+		Memo:   "correction",
+	})
+	require.ErrorIs(t, err, ErrSystemAccount)
+
+	err = store.DeleteAccount(context.Background(), account.ID)
+	require.ErrorIs(t, err, ErrSystemAccount)
+
+	got, err := testQueries.GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, account.Balance, got.Balance)
+}
@@ -7,26 +7,38 @@ package db
 
 import (
 	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const createTransfer = `-- name: CreateTransfer :one
 INSERT INTO transfers (
   from_account_id,
   to_account_id,
-  amount
+  amount,
+  memo,
+  external_reference
 ) VALUES (
-  $1, $2, $3
-) RETURNING id, from_account_id, to_account_id, amount, created_at
+  $1, $2, $3, $4, $5
+) RETURNING id, from_account_id, to_account_id, amount, created_at, memo, external_reference
 `
 
 type CreateTransferParams struct {
-	FromAccountID int64 `json:"from_account_id"`
-	ToAccountID   int64 `json:"to_account_id"`
-	Amount        int64 `json:"amount"`
+	FromAccountID     int64  `json:"from_account_id"`
+	ToAccountID       int64  `json:"to_account_id"`
+	Amount            int64  `json:"amount"`
+	Memo              string `json:"memo"`
+	ExternalReference string `json:"external_reference"`
 }
 
 func (q *Queries) CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transfer, error) {
-	row := q.db.QueryRowContext(ctx, createTransfer, arg.FromAccountID, arg.ToAccountID, arg.Amount)
+	row := q.db.QueryRow(ctx, createTransfer,
+		arg.FromAccountID,
+		arg.ToAccountID,
+		arg.Amount,
+		arg.Memo,
+		arg.ExternalReference,
+	)
 	var i Transfer
 	err := row.Scan(
 		&i.ID,
@@ -34,17 +46,19 @@ func (q *Queries) CreateTransfer(ctx context.Context, arg CreateTransferParams)
 		&i.ToAccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.Memo,
+		&i.ExternalReference,
 	)
 	return i, err
 }
 
 const getTransfer = `-- name: GetTransfer :one
-SELECT id, from_account_id, to_account_id, amount, created_at FROM transfers
+SELECT id, from_account_id, to_account_id, amount, created_at, memo, external_reference FROM transfers
 WHERE id = $1 LIMIT 1
 `
 
 func (q *Queries) GetTransfer(ctx context.Context, id int64) (Transfer, error) {
-	row := q.db.QueryRowContext(ctx, getTransfer, id)
+	row := q.db.QueryRow(ctx, getTransfer, id)
 	var i Transfer
 	err := row.Scan(
 		&i.ID,
@@ -52,12 +66,14 @@ func (q *Queries) GetTransfer(ctx context.Context, id int64) (Transfer, error) {
 		&i.ToAccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.Memo,
+		&i.ExternalReference,
 	)
 	return i, err
 }
 
 const listTransfers = `-- name: ListTransfers :many
-SELECT id, from_account_id, to_account_id, amount, created_at FROM transfers
+SELECT id, from_account_id, to_account_id, amount, created_at, memo, external_reference FROM transfers
 WHERE 
     from_account_id = $1 OR
     to_account_id = $2
@@ -74,7 +90,7 @@ type ListTransfersParams struct {
 }
 
 func (q *Queries) ListTransfers(ctx context.Context, arg ListTransfersParams) ([]Transfer, error) {
-	rows, err := q.db.QueryContext(ctx, listTransfers,
+	rows, err := q.db.Query(ctx, listTransfers,
 		arg.FromAccountID,
 		arg.ToAccountID,
 		arg.Limit,
@@ -93,14 +109,177 @@ func (q *Queries) ListTransfers(ctx context.Context, arg ListTransfersParams) ([
 			&i.ToAccountID,
 			&i.Amount,
 			&i.CreatedAt,
+			&i.Memo,
+			&i.ExternalReference,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchTransfers = `-- name: SearchTransfers :many
+SELECT
+    transfers.id, transfers.from_account_id, transfers.to_account_id, transfers.amount, transfers.created_at, transfers.memo, transfers.external_reference,
+    counterparty.id AS counterparty_account_id,
+    counterparty.owner AS counterparty_username,
+    users.full_name AS counterparty_full_name,
+    counterparty.nickname AS counterparty_nickname,
+    counterparty.currency
+FROM transfers
+JOIN accounts AS counterparty ON counterparty.id = CASE
+    WHEN transfers.from_account_id = $1 THEN transfers.to_account_id
+    ELSE transfers.from_account_id
+END
+JOIN users ON users.username = counterparty.owner
+WHERE
+    (transfers.from_account_id = $1 OR transfers.to_account_id = $1) AND
+    ($2::varchar IS NULL OR transfers.external_reference = $2) AND
+    ($3::varchar IS NULL OR transfers.memo ILIKE '%' || $3 || '%')
+ORDER BY transfers.id
+LIMIT $4
+OFFSET $5
+`
+
+type SearchTransfersParams struct {
+	AccountID         int64       `json:"account_id"`
+	ExternalReference pgtype.Text `json:"external_reference"`
+	Memo              pgtype.Text `json:"memo"`
+	RowLimit          int32       `json:"row_limit"`
+	RowOffset         int32       `json:"row_offset"`
+}
+
+type SearchTransfersRow struct {
+	Transfer              Transfer `json:"transfer"`
+	CounterpartyAccountID int64    `json:"counterparty_account_id"`
+	CounterpartyUsername  string   `json:"counterparty_username"`
+	CounterpartyFullName  string   `json:"counterparty_full_name"`
+	CounterpartyNickname  string   `json:"counterparty_nickname"`
+	Currency              string   `json:"currency"`
+}
+
+// Lists the transfers sent or received by an account, optionally only those with an external reference
+// or whose memo contains a text, regardless of its case. Each transfer comes with its other account,
+// the counterparty, and its owner, and with its currency, the one of both its accounts.
+func (q *Queries) SearchTransfers(ctx context.Context, arg SearchTransfersParams) ([]SearchTransfersRow, error) {
+	rows, err := q.db.Query(ctx, searchTransfers,
+		arg.AccountID,
+		arg.ExternalReference,
+		arg.Memo,
+		arg.RowLimit,
+		arg.RowOffset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SearchTransfersRow{}
+	for rows.Next() {
+		var i SearchTransfersRow
+		if err := rows.Scan(
+			&i.Transfer.ID,
+			&i.Transfer.FromAccountID,
+			&i.Transfer.ToAccountID,
+			&i.Transfer.Amount,
+			&i.Transfer.CreatedAt,
+			&i.Transfer.Memo,
+			&i.Transfer.ExternalReference,
+			&i.CounterpartyAccountID,
+			&i.CounterpartyUsername,
+			&i.CounterpartyFullName,
+			&i.CounterpartyNickname,
+			&i.Currency,
 		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
 	}
-	if err := rows.Close(); err != nil {
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchTransfersBefore = `-- name: SearchTransfersBefore :many
+SELECT
+    transfers.id, transfers.from_account_id, transfers.to_account_id, transfers.amount, transfers.created_at, transfers.memo, transfers.external_reference,
+    counterparty.id AS counterparty_account_id,
+    counterparty.owner AS counterparty_username,
+    users.full_name AS counterparty_full_name,
+    counterparty.nickname AS counterparty_nickname,
+    counterparty.currency
+FROM transfers
+JOIN accounts AS counterparty ON counterparty.id = CASE
+    WHEN transfers.from_account_id = $1 THEN transfers.to_account_id
+    ELSE transfers.from_account_id
+END
+JOIN users ON users.username = counterparty.owner
+WHERE
+    (transfers.from_account_id = $1 OR transfers.to_account_id = $1) AND
+    ($2::varchar IS NULL OR transfers.external_reference = $2) AND
+    ($3::varchar IS NULL OR transfers.memo ILIKE '%' || $3 || '%') AND
+    ($4::bigint IS NULL OR transfers.id < $4)
+ORDER BY transfers.id DESC
+LIMIT $5
+`
+
+type SearchTransfersBeforeParams struct {
+	AccountID         int64       `json:"account_id"`
+	ExternalReference pgtype.Text `json:"external_reference"`
+	Memo              pgtype.Text `json:"memo"`
+	Cursor            pgtype.Int8 `json:"cursor"`
+	RowLimit          int32       `json:"row_limit"`
+}
+
+type SearchTransfersBeforeRow struct {
+	Transfer              Transfer `json:"transfer"`
+	CounterpartyAccountID int64    `json:"counterparty_account_id"`
+	CounterpartyUsername  string   `json:"counterparty_username"`
+	CounterpartyFullName  string   `json:"counterparty_full_name"`
+	CounterpartyNickname  string   `json:"counterparty_nickname"`
+	Currency              string   `json:"currency"`
+}
+
+// Lists the transfers of an account like SearchTransfers, newest first and a page at a time by their id:
+// the page after a cursor, the id of the last transfer of the previous page, holds the transfers made
+// before it. The first page, without a cursor, starts with the newest transfer.
+func (q *Queries) SearchTransfersBefore(ctx context.Context, arg SearchTransfersBeforeParams) ([]SearchTransfersBeforeRow, error) {
+	rows, err := q.db.Query(ctx, searchTransfersBefore,
+		arg.AccountID,
+		arg.ExternalReference,
+		arg.Memo,
+		arg.Cursor,
+		arg.RowLimit,
+	)
+	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
+	items := []SearchTransfersBeforeRow{}
+	for rows.Next() {
+		var i SearchTransfersBeforeRow
+		if err := rows.Scan(
+			&i.Transfer.ID,
+			&i.Transfer.FromAccountID,
+			&i.Transfer.ToAccountID,
+			&i.Transfer.Amount,
+			&i.Transfer.CreatedAt,
+			&i.Transfer.Memo,
+			&i.Transfer.ExternalReference,
+			&i.CounterpartyAccountID,
+			&i.CounterpartyUsername,
+			&i.CounterpartyFullName,
+			&i.CounterpartyNickname,
+			&i.Currency,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
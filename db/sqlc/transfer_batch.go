@@ -0,0 +1,108 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// The BatchTransferTxParams type contains the transfers of a batch, e.g. the salaries of a payroll.
+// @property {[]TransferTxParams} Transfers - the transfers, made in order.
+type BatchTransferTxParams struct {
+	Transfers []TransferTxParams
+}
+
+// The BatchTransferTxItem type is the outcome of a transfer of a batch: its result when it was made, or
+// the error that prevented it.
+type BatchTransferTxItem struct {
+	Result TransferTxResult
+	Err    error
+}
+
+// BatchTransferTx makes the transfers of the batch in one transaction, each within a savepoint: a
+// transfer that fails is rolled back on its own and reported in its item, while the others are still
+// made. The items are in the order of the transfers. An error is only returned when the batch as a
+// whole failed, in which case none of the transfers were made. The accounts of the batch are all locked
+// up front in order of id, outside the savepoints which would release them, so that batches sharing
+// accounts in a different order wait for each other rather than deadlock.
+func (store *SQLStore) BatchTransferTx(ctx context.Context, arg BatchTransferTxParams) ([]BatchTransferTxItem, error) {
+	items := make([]BatchTransferTxItem, len(arg.Transfers))
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		_, err := q.LockAccounts(ctx, batchAccountIDs(arg.Transfers))
+		if err != nil {
+			return err
+		}
+
+		for i, params := range arg.Transfers {
+			err := withSavepoint(ctx, q, func() error {
+				var err error
+				items[i].Result, err = transfer(ctx, q, params, nil)
+				return err
+			})
+			if err != nil {
+				var rollbackErr *savepointError
+				if errors.As(err, &rollbackErr) {
+					return err
+				}
+				items[i] = BatchTransferTxItem{Err: err}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// batchAccountIDs returns the distinct ids of the accounts the transfers move money between, ascending.
+func batchAccountIDs(transfers []TransferTxParams) []int64 {
+	seen := make(map[int64]bool, 2*len(transfers))
+	ids := make([]int64, 0, 2*len(transfers))
+	for _, params := range transfers {
+		for _, id := range []int64{params.FromAccountID, params.ToAccountID} {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// The savepointError type is a failure to manage a savepoint, which aborts the whole transaction.
+type savepointError struct {
+	err error
+}
+
+func (e *savepointError) Error() string {
+	return fmt.Sprintf("savepoint: %v", e.err)
+}
+
+func (e *savepointError) Unwrap() error {
+	return e.err
+}
+
+// withSavepoint runs `fn` within a savepoint of the transaction of `q`, rolling back to the savepoint
+// when it fails so that the transaction can go on.
+func withSavepoint(ctx context.Context, q *Queries, fn func() error) error {
+	if _, err := q.db.Exec(ctx, "SAVEPOINT batch_item"); err != nil {
+		return &savepointError{err}
+	}
+
+	if err := fn(); err != nil {
+		if _, rbErr := q.db.Exec(ctx, "ROLLBACK TO SAVEPOINT batch_item"); rbErr != nil {
+			return &savepointError{rbErr}
+		}
+		return err
+	}
+
+	if _, err := q.db.Exec(ctx, "RELEASE SAVEPOINT batch_item"); err != nil {
+		return &savepointError{err}
+	}
+	return nil
+}
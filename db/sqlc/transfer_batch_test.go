@@ -0,0 +1,90 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchTransferTx(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	items, err := store.BatchTransferTx(context.Background(), BatchTransferTxParams{
+		Transfers: []TransferTxParams{
+			{FromAccountID: account1.ID, ToAccountID: account2.ID, Amount: 10},
+			// the account doesn't exist, so the transfer is rolled back on its own
+			{FromAccountID: account1.ID, ToAccountID: -1, Amount: 10},
+			{FromAccountID: account1.ID, ToAccountID: account2.ID, Amount: 5, Memo: "second"},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, items, 3)
+
+	require.NoError(t, items[0].Err)
+	require.Equal(t, int64(10), items[0].Result.Transfer.Amount)
+	require.Equal(t, account1.Balance-10, items[0].Result.FromAccount.Balance)
+
+	require.Error(t, items[1].Err)
+	require.True(t, errors.Is(TranslateError(items[1].Err), ErrForeignKeyViolation))
+
+	require.NoError(t, items[2].Err)
+	require.Equal(t, "second", items[2].Result.Transfer.Memo)
+	require.Equal(t, account1.Balance-15, items[2].Result.FromAccount.Balance)
+	require.Equal(t, account2.Balance+15, items[2].Result.ToAccount.Balance)
+
+	updated1, err := testQueries.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance-15, updated1.Balance)
+
+	transfers, err := testQueries.SearchTransfers(context.Background(), SearchTransfersParams{
+		AccountID: account1.ID,
+		RowLimit:  10,
+	})
+	require.NoError(t, err)
+	require.Len(t, transfers, 2)
+}
+
+func TestBatchTransferTxDeadlock(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+	account3 := createRandomAccount(t)
+
+	// the batches move money around the same accounts, half of them in the opposite order
+	n := 10
+	errs := make(chan error)
+	for i := 0; i < n; i++ {
+		transfers := []TransferTxParams{
+			{FromAccountID: account1.ID, ToAccountID: account2.ID, Amount: 10},
+			{FromAccountID: account2.ID, ToAccountID: account3.ID, Amount: 10},
+			{FromAccountID: account3.ID, ToAccountID: account1.ID, Amount: 10},
+		}
+		if i%2 == 1 {
+			transfers[0], transfers[2] = transfers[2], transfers[0]
+		}
+
+		go func() {
+			items, err := store.BatchTransferTx(context.Background(), BatchTransferTxParams{Transfers: transfers})
+			for _, item := range items {
+				if err == nil {
+					err = item.Err
+				}
+			}
+			errs <- err
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		require.NoError(t, <-errs)
+	}
+
+	for _, account := range []Account{account1, account2, account3} {
+		updated, err := testQueries.GetAccount(context.Background(), account.ID)
+		require.NoError(t, err)
+		require.Equal(t, account.Balance, updated.Balance)
+	}
+}
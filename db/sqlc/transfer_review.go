@@ -0,0 +1,205 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Statuses of a transfer review. Pending and escalated reviews are open, the amount of their transfer
+// being held until they are approved or denied.
+const (
+	TransferReviewPending   = "pending"
+	TransferReviewEscalated = "escalated"
+	TransferReviewApproved  = "approved"
+	TransferReviewDenied    = "denied"
+)
+
+// ErrTransferReviewClosed is returned when deciding a review that was already approved or denied.
+var ErrTransferReviewClosed = errors.New("transfer review is already decided")
+
+// IsTransferReviewOpen reports whether a review with the status still awaits a decision.
+func IsTransferReviewOpen(status string) bool {
+	return status == TransferReviewPending || status == TransferReviewEscalated
+}
+
+// The HoldTransferTxParams type contains a transfer held for review by the screening.
+// @property {TransferTxParams} Transfer - the transfer held.
+// @property {string} HoldReason - why the screening held the transfer.
+// @property {time.Time} DueAt - when the review must be decided by.
+type HoldTransferTxParams struct {
+	Transfer   TransferTxParams
+	HoldReason string
+	DueAt      time.Time
+}
+
+// The HoldTransferTxResult type is the review opened for a held transfer, along with the from account
+// and the entry taking the amount from it.
+type HoldTransferTxResult struct {
+	Review      TransferReview `json:"review"`
+	FromAccount Account        `json:"from_account"`
+	FromEntry   Entry          `json:"from_entry"`
+}
+
+// HoldTransferTx opens a review for the transfer and takes its amount from the from account, so that the
+// money can't be spent elsewhere while the review is open. The to account isn't credited until the
+// review is approved. The outcome of the queued transfer it processes is recorded in the same
+// transaction.
+func (store *SQLStore) HoldTransferTx(ctx context.Context, arg HoldTransferTxParams) (HoldTransferTxResult, error) {
+	var result HoldTransferTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		result.Review, err = q.CreateTransferReview(ctx, CreateTransferReviewParams{
+			FromAccountID:     arg.Transfer.FromAccountID,
+			ToAccountID:       arg.Transfer.ToAccountID,
+			Amount:            arg.Transfer.Amount,
+			Memo:              arg.Transfer.Memo,
+			ExternalReference: arg.Transfer.ExternalReference,
+			HoldReason:        arg.HoldReason,
+			DueAt:             arg.DueAt,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.FromEntry, result.FromAccount, err = adjustBalance(ctx, q, arg.Transfer.FromAccountID, -arg.Transfer.Amount)
+		if err != nil || arg.Transfer.Queued == nil {
+			return err
+		}
+
+		queued := *arg.Transfer.Queued
+		queued.Status = QueuedTransferHeld
+		queued.ReviewID = pgtype.Int8{Int64: result.Review.ID, Valid: true}
+		_, err = recordQueuedTransfer(ctx, q, queued)
+		return err
+	})
+
+	return result, err
+}
+
+// The DecideTransferReviewTxParams type contains the decision of a reviewer on an open review.
+// @property {int64} ID - the review decided.
+// @property {string} Status - TransferReviewApproved or TransferReviewDenied.
+// @property {string} DecidedBy - the reviewer making the decision.
+// @property {string} Note - why the reviewer made the decision.
+// @property {time.Time} Now - the time of the decision, by which the fee of an approved transfer is
+// charged.
+type DecideTransferReviewTxParams struct {
+	ID        int64
+	Status    string
+	DecidedBy string
+	Note      string
+	Now       time.Time
+}
+
+// The DecideTransferReviewTxResult type is the decided review, along with the transfer made when it was
+// approved.
+type DecideTransferReviewTxResult struct {
+	Review   TransferReview    `json:"review"`
+	Transfer *TransferTxResult `json:"transfer"`
+}
+
+// DecideTransferReviewTx releases the amount held by the review back to the from account, then makes
+// the transfer when the review is approved. A denied review is thus refunded. The review is locked so
+// that it is decided once, ErrTransferReviewClosed being returned when it already was.
+func (store *SQLStore) DecideTransferReviewTx(ctx context.Context, arg DecideTransferReviewTxParams) (DecideTransferReviewTxResult, error) {
+	var result DecideTransferReviewTxResult
+	if arg.Status != TransferReviewApproved && arg.Status != TransferReviewDenied {
+		return result, fmt.Errorf("invalid transfer review decision %s", arg.Status)
+	}
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		review, err := q.GetTransferReviewForUpdate(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+		if !IsTransferReviewOpen(review.Status) {
+			return ErrTransferReviewClosed
+		}
+
+		_, _, err = adjustBalance(ctx, q, review.FromAccountID, review.Amount)
+		if err != nil {
+			return err
+		}
+
+		var transferID pgtype.Int8
+		if arg.Status == TransferReviewApproved {
+			transferResult, err := transfer(ctx, q, TransferTxParams{
+				FromAccountID:     review.FromAccountID,
+				ToAccountID:       review.ToAccountID,
+				Amount:            review.Amount,
+				Memo:              review.Memo,
+				ExternalReference: review.ExternalReference,
+				ChargeFee:         true,
+				Now:               arg.Now,
+			}, nil)
+			if err != nil {
+				return err
+			}
+			result.Transfer = &transferResult
+			transferID = pgtype.Int8{Int64: transferResult.Transfer.ID, Valid: true}
+		}
+
+		result.Review, err = q.DecideTransferReview(ctx, DecideTransferReviewParams{
+			Status:     arg.Status,
+			TransferID: transferID,
+			DecidedBy:  arg.DecidedBy,
+			Note:       arg.Note,
+			ID:         arg.ID,
+		})
+		return err
+	})
+
+	return result, err
+}
+
+// adjustBalance adds the amount to the balance of the account with an entry and an account.updated
+// event, e.g. for the money held by a review and released once it is decided. The opposite amount is
+// posted to the suspense account of its currency, where the money waits meanwhile, so that the entries
+// of the transaction still sum to zero. The accounts are updated in the order of their ids, like the
+// transfers do, so as not to deadlock with them.
+func adjustBalance(ctx context.Context, q *Queries, accountID int64, amount int64) (Entry, Account, error) {
+	account, err := q.GetAccount(ctx, accountID)
+	if err != nil {
+		return Entry{}, account, err
+	}
+
+	suspense, err := q.GetSystemAccount(ctx, GetSystemAccountParams{
+		Purpose:  SystemAccountSuspense,
+		Currency: account.Currency,
+	})
+	if err != nil {
+		return Entry{}, account, err
+	}
+
+	entry, err := q.CreateEntry(ctx, CreateEntryParams{
+		AccountID: accountID,
+		Amount:    amount,
+	})
+	if err != nil {
+		return entry, account, err
+	}
+
+	_, err = q.CreateEntry(ctx, CreateEntryParams{
+		AccountID: suspense.ID,
+		Amount:    -amount,
+	})
+	if err != nil {
+		return entry, account, err
+	}
+
+	if accountID < suspense.ID {
+		account, _, err = addMoney(ctx, q, accountID, amount, suspense.ID, -amount)
+	} else {
+		_, account, err = addMoney(ctx, q, suspense.ID, -amount, accountID, amount)
+	}
+	if err != nil {
+		return entry, account, err
+	}
+
+	return entry, account, recordEvent(ctx, q, EventAccountUpdated, newAccountEvent(account))
+}
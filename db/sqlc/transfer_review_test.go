@@ -0,0 +1,97 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func holdRandomTransfer(t *testing.T, store Store, from Account, to Account, amount int64) TransferReview {
+	result, err := store.HoldTransferTx(context.Background(), HoldTransferTxParams{
+		Transfer: TransferTxParams{
+			FromAccountID: from.ID,
+			ToAccountID:   to.ID,
+			Amount:        amount,
+			Memo:          "held",
+		},
+		HoldReason: "large amount",
+		DueAt:      time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+	require.Equal(t, TransferReviewPending, result.Review.Status)
+	require.Equal(t, from.Balance-amount, result.FromAccount.Balance)
+	require.Equal(t, -amount, result.FromEntry.Amount)
+
+	return result.Review
+}
+
+func TestApproveTransferReviewTx(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	review := holdRandomTransfer(t, store, account1, account2, 10)
+
+	// the recipient isn't credited while the review is open
+	held, err := testQueries.GetAccount(context.Background(), account2.ID)
+	require.NoError(t, err)
+	require.Equal(t, account2.Balance, held.Balance)
+
+	result, err := store.DecideTransferReviewTx(context.Background(), DecideTransferReviewTxParams{
+		ID:        review.ID,
+		Status:    TransferReviewApproved,
+		DecidedBy: account1.Owner,
+		Now:       time.Now(),
+	})
+	require.NoError(t, err)
+	require.Equal(t, TransferReviewApproved, result.Review.Status)
+	require.NotNil(t, result.Transfer)
+	require.Equal(t, result.Transfer.Transfer.ID, result.Review.TransferID.Int64)
+	require.Equal(t, "held", result.Transfer.Transfer.Memo)
+	require.Equal(t, account1.Balance-10, result.Transfer.FromAccount.Balance)
+	require.Equal(t, account2.Balance+10, result.Transfer.ToAccount.Balance)
+
+	_, err = store.DecideTransferReviewTx(context.Background(), DecideTransferReviewTxParams{
+		ID:        review.ID,
+		Status:    TransferReviewDenied,
+		DecidedBy: account1.Owner,
+	})
+	require.ErrorIs(t, err, ErrTransferReviewClosed)
+
+	// a decided review can't be reopened by escalating or assigning it
+	_, err = testQueries.EscalateTransferReview(context.Background(), EscalateTransferReviewParams{
+		DueAt: time.Now().Add(time.Hour),
+		Note:  "second look",
+		ID:    review.ID,
+	})
+	require.ErrorIs(t, err, ErrRecordNotFound)
+	_, err = testQueries.AssignTransferReview(context.Background(), AssignTransferReviewParams{ID: review.ID})
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
+
+func TestDenyTransferReviewTx(t *testing.T) {
+	store := NewStore(testDB)
+	account1 := createRandomAccount(t)
+	account2 := createRandomAccount(t)
+
+	review := holdRandomTransfer(t, store, account1, account2, 10)
+
+	result, err := store.DecideTransferReviewTx(context.Background(), DecideTransferReviewTxParams{
+		ID:        review.ID,
+		Status:    TransferReviewDenied,
+		DecidedBy: account1.Owner,
+		Note:      "sanctioned recipient",
+	})
+	require.NoError(t, err)
+	require.Equal(t, TransferReviewDenied, result.Review.Status)
+	require.Equal(t, "sanctioned recipient", result.Review.Note)
+	require.Nil(t, result.Transfer)
+	require.False(t, result.Review.TransferID.Valid)
+
+	// the held amount is refunded
+	refunded, err := testQueries.GetAccount(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, account1.Balance, refunded.Balance)
+}
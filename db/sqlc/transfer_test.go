@@ -3,17 +3,21 @@ package db
 import (
 	"context"
 	"go-backend/util"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/stretchr/testify/require"
 )
 
 func createRandomTransfer(t *testing.T, fromAccount Account, toAccount Account) Transfer {
 	arg := CreateTransferParams{
-		FromAccountID: fromAccount.ID,
-		ToAccountID:   toAccount.ID,
-		Amount:        util.RandomMoney(),
+		FromAccountID:     fromAccount.ID,
+		ToAccountID:       toAccount.ID,
+		Amount:            util.RandomMoney(),
+		Memo:              util.RandomString(12),
+		ExternalReference: util.RandomString(8),
 	}
 
 	transfer, err := testQueries.CreateTransfer(context.Background(), arg)
@@ -23,6 +27,8 @@ func createRandomTransfer(t *testing.T, fromAccount Account, toAccount Account)
 	require.Equal(t, arg.FromAccountID, transfer.FromAccountID)
 	require.Equal(t, arg.ToAccountID, transfer.ToAccountID)
 	require.Equal(t, arg.Amount, transfer.Amount)
+	require.Equal(t, arg.Memo, transfer.Memo)
+	require.Equal(t, arg.ExternalReference, transfer.ExternalReference)
 
 	require.NotZero(t, transfer.ID)
 	require.NotZero(t, transfer.CreatedAt)
@@ -50,6 +56,8 @@ func TestGetTransfer(t *testing.T) {
 	require.Equal(t, transfer1.FromAccountID, transfer2.FromAccountID)
 	require.Equal(t, transfer1.ToAccountID, transfer2.ToAccountID)
 	require.Equal(t, transfer1.Amount, transfer2.Amount)
+	require.Equal(t, transfer1.Memo, transfer2.Memo)
+	require.Equal(t, transfer1.ExternalReference, transfer2.ExternalReference)
 	require.WithinDuration(t, transfer1.CreatedAt, transfer2.CreatedAt, time.Second)
 }
 
@@ -76,3 +84,75 @@ func TestListTransfers(t *testing.T) {
 		require.NotEmpty(t, account)
 	}
 }
+
+func TestSearchTransfers(t *testing.T) {
+	fromAccount := createRandomAccount(t)
+	toAccount := createRandomAccount(t)
+	var transfers []Transfer
+	for i := 0; i < 3; i++ {
+		transfers = append(transfers, createRandomTransfer(t, fromAccount, toAccount))
+	}
+
+	found, err := testQueries.SearchTransfers(context.Background(), SearchTransfersParams{
+		AccountID: toAccount.ID,
+		RowLimit:  10,
+	})
+	require.NoError(t, err)
+	require.Len(t, found, 3)
+
+	// the counterparty is the other account of the transfer
+	require.Equal(t, fromAccount.ID, found[0].CounterpartyAccountID)
+	require.Equal(t, fromAccount.Owner, found[0].CounterpartyUsername)
+	require.NotEmpty(t, found[0].CounterpartyFullName)
+	require.Equal(t, fromAccount.Currency, found[0].Currency)
+
+	found, err = testQueries.SearchTransfers(context.Background(), SearchTransfersParams{
+		AccountID:         fromAccount.ID,
+		ExternalReference: pgtype.Text{String: transfers[1].ExternalReference, Valid: true},
+		RowLimit:          10,
+	})
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	require.Equal(t, transfers[1].ID, found[0].Transfer.ID)
+	require.Equal(t, toAccount.ID, found[0].CounterpartyAccountID)
+
+	// the memo matches on a part of it, regardless of its case
+	found, err = testQueries.SearchTransfers(context.Background(), SearchTransfersParams{
+		AccountID: fromAccount.ID,
+		Memo:      pgtype.Text{String: strings.ToUpper(transfers[2].Memo[2:8]), Valid: true},
+		RowLimit:  10,
+	})
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	require.Equal(t, transfers[2].ID, found[0].Transfer.ID)
+}
+
+func TestSearchTransfersBefore(t *testing.T) {
+	fromAccount := createRandomAccount(t)
+	toAccount := createRandomAccount(t)
+	var transfers []Transfer
+	for i := 0; i < 3; i++ {
+		transfers = append(transfers, createRandomTransfer(t, fromAccount, toAccount))
+	}
+
+	// the first page starts with the newest transfer
+	found, err := testQueries.SearchTransfersBefore(context.Background(), SearchTransfersBeforeParams{
+		AccountID: toAccount.ID,
+		RowLimit:  2,
+	})
+	require.NoError(t, err)
+	require.Len(t, found, 2)
+	require.Equal(t, transfers[2].ID, found[0].Transfer.ID)
+	require.Equal(t, transfers[1].ID, found[1].Transfer.ID)
+	require.Equal(t, fromAccount.ID, found[0].CounterpartyAccountID)
+
+	// the next page holds the transfers made before the last one of the page
+	found, err = testQueries.SearchTransfersBefore(context.Background(), SearchTransfersBeforeParams{
+		AccountID: toAccount.ID,
+		Cursor:    pgtype.Int8{Int64: found[1].Transfer.ID, Valid: true},
+		RowLimit:  2,
+	})
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	require.Equal(t, transfers[0].ID, found[0].Transfer.ID)
+}
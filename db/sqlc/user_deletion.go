@@ -0,0 +1,125 @@
+package db
+
+import (
+	"context"
+	"errors"
+)
+
+// Statuses of the deletion of the data of a user. A scheduled deletion is either completed once its
+// cooling-off period is over, cancelled by the user before, or failed. The user can schedule another one
+// after a cancelled or failed deletion.
+const (
+	UserDeletionScheduled = "scheduled"
+	UserDeletionCancelled = "cancelled"
+	UserDeletionCompleted = "completed"
+	UserDeletionFailed    = "failed"
+)
+
+// ErrAccountNotEmpty is returned when deleting the data of a user one of whose accounts holds money,
+// which must be withdrawn before the account is closed.
+var ErrAccountNotEmpty = errors.New("account still holds money")
+
+// The DeleteUserDataTxResult type is the completed deletion, along with the accounts it closed.
+// @property {[]Account} HandedOverAccounts - the accounts shared with co-owners, which were handed over
+// to one of them rather than closed.
+type DeleteUserDataTxResult struct {
+	UserDeletion       UserDeletion `json:"user_deletion"`
+	ClosedAccounts     []Account    `json:"closed_accounts"`
+	HandedOverAccounts []Account    `json:"handed_over_accounts"`
+}
+
+// DeleteUserDataTx completes the scheduled deletion of the data of the user once its cooling-off period
+// is over: their accounts shared with co-owners are handed over to the co-owner who accepted first, and
+// their other accounts are closed. The mandates, cards, payment links, standing orders and memberships of
+// the user and of their closed accounts are revoked, frozen, cancelled or deleted, so that nothing moves
+// money for them afterwards. Their personal data is anonymized, in their events too, and their sessions,
+// identities, beneficiaries, preferences and signing keys are deleted. The username is kept, as the
+// pseudonymous key the ledger refers to. ErrRecordNotFound is returned when the deletion isn't scheduled
+// or not due yet, and ErrAccountNotEmpty when an account closed holds money, in which case nothing is
+// changed.
+func (store *SQLStore) DeleteUserDataTx(ctx context.Context, username string) (DeleteUserDataTxResult, error) {
+	var result DeleteUserDataTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		result.UserDeletion, err = q.CompleteUserDeletion(ctx, username)
+		if err != nil {
+			return err
+		}
+
+		result.HandedOverAccounts, err = q.HandOverJointAccounts(ctx, username)
+		if err != nil {
+			return err
+		}
+		for _, account := range result.HandedOverAccounts {
+			// the new owner is no longer a member
+			err = q.DeleteAccountMember(ctx, DeleteAccountMemberParams{
+				AccountID: account.ID,
+				Username:  account.Owner,
+			})
+			if err != nil {
+				return err
+			}
+			err = recordAccountVersion(ctx, q, account)
+			if err != nil {
+				return err
+			}
+		}
+
+		result.ClosedAccounts, err = q.CloseOwnerAccounts(ctx, username)
+		if err != nil {
+			return err
+		}
+		for _, account := range result.ClosedAccounts {
+			if account.Balance != 0 {
+				return ErrAccountNotEmpty
+			}
+			err = recordAccountVersion(ctx, q, account)
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, closeUserRows := range []func(context.Context, string) (int64, error){
+			q.RevokeUserMandates,
+			q.FreezeUserCards,
+			q.CancelUserPaymentLinks,
+			q.CancelUserStandingOrders,
+		} {
+			_, err = closeUserRows(ctx, username)
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err = q.AnonymizeUser(ctx, username)
+		if err != nil {
+			return err
+		}
+		err = q.AnonymizeUserOverview(ctx, username)
+		if err != nil {
+			return err
+		}
+		err = q.ScrubUserEvents(ctx, username)
+		if err != nil {
+			return err
+		}
+
+		for _, deleteUserRows := range []func(context.Context, string) error{
+			q.DeleteUserSessions,
+			q.DeleteUserIdentities,
+			q.DeleteOwnerBeneficiaries,
+			q.DeleteNotificationPreferences,
+			q.DeleteUserSigningKeys,
+			q.DeleteUserAccountMembers,
+		} {
+			err = deleteUserRows(ctx, username)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	return result, err
+}
@@ -0,0 +1,150 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduleUserDeletion(t *testing.T) {
+	user := createRandomUser(t)
+	arg := ScheduleUserDeletionParams{
+		Username:     user.Username,
+		ScheduledFor: time.Now().Add(time.Hour),
+	}
+
+	deletion, err := testQueries.ScheduleUserDeletion(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, UserDeletionScheduled, deletion.Status)
+	require.WithinDuration(t, arg.ScheduledFor, deletion.ScheduledFor, time.Second)
+
+	// a scheduled deletion isn't replaced
+	_, err = testQueries.ScheduleUserDeletion(context.Background(), arg)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	// nor completed before the end of its cooling-off period
+	_, err = testQueries.CompleteUserDeletion(context.Background(), user.Username)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	deletion, err = testQueries.CancelUserDeletion(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Equal(t, UserDeletionCancelled, deletion.Status)
+
+	_, err = testQueries.CancelUserDeletion(context.Background(), user.Username)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	// a cancelled deletion can be scheduled again
+	deletion, err = testQueries.ScheduleUserDeletion(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, UserDeletionScheduled, deletion.Status)
+}
+
+func TestDeleteUserDataTx(t *testing.T) {
+	store := NewStore(testDB)
+	account := postEntry(t, createRandomAccount(t).ID, 10)
+	card := createRandomCard(t, account)
+
+	_, err := testQueries.ScheduleUserDeletion(context.Background(), ScheduleUserDeletionParams{
+		Username:     account.Owner,
+		ScheduledFor: time.Now(),
+	})
+	require.NoError(t, err)
+
+	// the money must be withdrawn first
+	_, err = store.DeleteUserDataTx(context.Background(), account.Owner)
+	require.ErrorIs(t, err, ErrAccountNotEmpty)
+
+	account = postEntry(t, account.ID, -account.Balance)
+
+	result, err := store.DeleteUserDataTx(context.Background(), account.Owner)
+	require.NoError(t, err)
+	require.Equal(t, UserDeletionCompleted, result.UserDeletion.Status)
+	require.True(t, result.UserDeletion.CompletedAt.Valid)
+	require.Len(t, result.ClosedAccounts, 1)
+	require.Equal(t, account.ID, result.ClosedAccounts[0].ID)
+	require.True(t, result.ClosedAccounts[0].ClosedAt.Valid)
+	require.Equal(t, account.Version+1, result.ClosedAccounts[0].Version)
+
+	// the closure is versioned in the history of the account
+	versions, err := testQueries.ListAccountHistory(context.Background(), ListAccountHistoryParams{
+		AccountID: account.ID,
+		Limit:     10,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, versions)
+	require.Equal(t, AccountStatusClosed, versions[len(versions)-1].Status)
+	require.False(t, versions[len(versions)-1].ValidTo.Valid)
+
+	user, err := testQueries.GetUser(context.Background(), account.Owner)
+	require.NoError(t, err)
+	require.Equal(t, "Deleted user", user.FullName)
+	require.Equal(t, account.Owner+"@deleted.invalid", user.Email)
+	require.Empty(t, user.HashedPassword)
+
+	// nothing moves money for the user afterwards
+	card, err = testQueries.GetCard(context.Background(), card.ID)
+	require.NoError(t, err)
+	require.Equal(t, CardFrozen, card.Status)
+
+	other := createRandomAccount(t)
+	_, err = store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: other.ID,
+		ToAccountID:   account.ID,
+		Amount:        1,
+	})
+	require.ErrorIs(t, err, ErrAccountClosed)
+
+	// the deletion is only completed once
+	_, err = store.DeleteUserDataTx(context.Background(), account.Owner)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
+
+func TestDeleteUserDataTxJointAccount(t *testing.T) {
+	store := NewStore(testDB)
+	account := postEntry(t, createRandomAccount(t).ID, 10)
+	coOwner := createRandomUser(t)
+
+	_, err := testQueries.CreateAccountMember(context.Background(), CreateAccountMemberParams{
+		AccountID: account.ID,
+		Username:  coOwner.Username,
+		Role:      AccountRoleOwner,
+		InvitedBy: account.Owner,
+	})
+	require.NoError(t, err)
+	_, err = testQueries.AcceptAccountMember(context.Background(), AcceptAccountMemberParams{
+		AccountID: account.ID,
+		Username:  coOwner.Username,
+	})
+	require.NoError(t, err)
+
+	// the money of a joint account doesn't prevent the deletion
+	hasBalance, err := testQueries.OwnerHasBalance(context.Background(), account.Owner)
+	require.NoError(t, err)
+	require.False(t, hasBalance)
+
+	_, err = testQueries.ScheduleUserDeletion(context.Background(), ScheduleUserDeletionParams{
+		Username:     account.Owner,
+		ScheduledFor: time.Now(),
+	})
+	require.NoError(t, err)
+
+	result, err := store.DeleteUserDataTx(context.Background(), account.Owner)
+	require.NoError(t, err)
+	require.Empty(t, result.ClosedAccounts)
+	require.Len(t, result.HandedOverAccounts, 1)
+
+	// the co-owner owns the account, which stays open
+	handedOver, err := testQueries.GetAccount(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, coOwner.Username, handedOver.Owner)
+	require.False(t, handedOver.ClosedAt.Valid)
+	require.Equal(t, account.Balance, handedOver.Balance)
+
+	_, err = testQueries.GetAccountMember(context.Background(), GetAccountMemberParams{
+		AccountID: account.ID,
+		Username:  coOwner.Username,
+	})
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
@@ -0,0 +1,44 @@
+package db
+
+import "context"
+
+// The CreateUserWithIdentityTxParams type is a user registering through an identity provider, along with
+// their identity at the provider.
+// @property {string} Subject - the id of the user at the provider.
+type CreateUserWithIdentityTxParams struct {
+	User     CreateUserParams
+	Provider string
+	Subject  string
+}
+
+// CreateUserWithIdentityTx creates the user along with the identity they log in with, and records a
+// user.created event.
+func (store *SQLStore) CreateUserWithIdentityTx(ctx context.Context, arg CreateUserWithIdentityTxParams) (User, error) {
+	var user User
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		user, err = q.CreateUser(ctx, arg.User)
+		if err != nil {
+			return err
+		}
+
+		_, err = q.CreateUserIdentity(ctx, CreateUserIdentityParams{
+			Provider: arg.Provider,
+			Subject:  arg.Subject,
+			Username: user.Username,
+			Email:    user.Email,
+		})
+		if err != nil {
+			return err
+		}
+
+		return recordEvent(ctx, q, EventUserCreated, UserCreatedEvent{
+			Username: user.Username,
+			FullName: user.FullName,
+			Email:    user.Email,
+		})
+	})
+
+	return user, err
+}
@@ -0,0 +1,80 @@
+package db
+
+import (
+	"context"
+	"go-backend/util"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserIdentity(t *testing.T) {
+	user := createRandomUser(t)
+
+	arg := CreateUserIdentityParams{
+		Provider: "google",
+		Subject:  util.RandomString(21),
+		Username: user.Username,
+		Email:    user.Email,
+	}
+	identity, err := testQueries.CreateUserIdentity(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Username, identity.Username)
+	require.NotZero(t, identity.CreatedAt)
+
+	identity2, err := testQueries.GetUserIdentity(context.Background(), GetUserIdentityParams{
+		Provider: arg.Provider,
+		Subject:  arg.Subject,
+	})
+	require.NoError(t, err)
+	require.Equal(t, identity, identity2)
+
+	// the same subject at another provider is another identity
+	_, err = testQueries.GetUserIdentity(context.Background(), GetUserIdentityParams{
+		Provider: "github",
+		Subject:  arg.Subject,
+	})
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	// a user has a single identity per provider
+	arg.Subject = util.RandomString(21)
+	_, err = testQueries.CreateUserIdentity(context.Background(), arg)
+	require.ErrorIs(t, TranslateError(err), ErrUniqueViolation)
+}
+
+func TestCreateUserWithIdentityTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	arg := CreateUserWithIdentityTxParams{
+		User: CreateUserParams{
+			Username:       util.RandomOwner(),
+			HashedPassword: util.RandomString(16),
+			FullName:       util.RandomOwner(),
+			Email:          util.RandomEmail(),
+			OrgID:          DefaultOrganizationID,
+		},
+		Provider: "github",
+		Subject:  util.RandomString(8),
+	}
+
+	user, err := store.CreateUserWithIdentityTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.User.Username, user.Username)
+
+	identity, err := store.GetUserIdentity(context.Background(), GetUserIdentityParams{
+		Provider: arg.Provider,
+		Subject:  arg.Subject,
+	})
+	require.NoError(t, err)
+	require.Equal(t, user.Username, identity.Username)
+	require.Equal(t, user.Email, identity.Email)
+
+	// a taken identity fails without creating the user
+	arg.User.Username = util.RandomOwner()
+	arg.User.Email = util.RandomEmail()
+	_, err = store.CreateUserWithIdentityTx(context.Background(), arg)
+	require.ErrorIs(t, TranslateError(err), ErrUniqueViolation)
+
+	_, err = store.GetUser(context.Background(), arg.User.Username)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
@@ -9,7 +9,7 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-func createRandomUser(t *testing.T) User {
+func createRandomUser(t testing.TB) User {
 	hashedPassword, err := util.HashPassword(util.RandomString(8))
 	require.NoError(t, err)
 
@@ -18,6 +18,7 @@ func createRandomUser(t *testing.T) User {
 		HashedPassword: hashedPassword,
 		FullName:       util.RandomOwner(),
 		Email:          util.RandomEmail(),
+		OrgID:          DefaultOrganizationID,
 	}
 
 	user, err := testQueries.CreateUser(context.Background(), arg)
@@ -55,3 +56,39 @@ func TestGetUser(t *testing.T) {
 	require.WithinDuration(t, user1.CreatedAt, user2.CreatedAt, time.Second)
 	require.WithinDuration(t, user1.PasswordChangedAt, user2.PasswordChangedAt, time.Second)
 }
+
+func TestGetUserByEmail(t *testing.T) {
+	user1 := createRandomUser(t)
+	user2, err := testQueries.GetUserByEmail(context.Background(), user1.Email)
+	require.NoError(t, err)
+	require.Equal(t, user1.Username, user2.Username)
+	require.Equal(t, user1.Email, user2.Email)
+
+	_, err = testQueries.GetUserByEmail(context.Background(), util.RandomEmail())
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
+
+func TestCreateUserWithRoleTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	arg := CreateUserParams{
+		Username:       util.RandomOwner(),
+		HashedPassword: util.RandomString(16),
+		FullName:       util.RandomOwner(),
+		Email:          util.RandomEmail(),
+		OrgID:          DefaultOrganizationID,
+	}
+
+	user, err := store.CreateUserWithRoleTx(context.Background(), arg, util.AdminRole)
+	require.NoError(t, err)
+	require.Equal(t, arg.Username, user.Username)
+	require.Equal(t, util.AdminRole, user.Role)
+
+	// a taken username fails without changing the role of the existing user
+	_, err = store.CreateUserWithRoleTx(context.Background(), arg, util.DepositorRole)
+	require.ErrorIs(t, TranslateError(err), ErrUniqueViolation)
+
+	user, err = store.GetUser(context.Background(), arg.Username)
+	require.NoError(t, err)
+	require.Equal(t, util.AdminRole, user.Role)
+}
@@ -0,0 +1,206 @@
+package diagnostics
+
+import (
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// The PoolStater interface is a pool of database connections reporting its statistics, such as a
+// `pgxpool.Pool`.
+type PoolStater interface {
+	Stat() *pgxpool.Stat
+}
+
+// The QueueInspector interface reports the sizes of the task queues, such as an `asynq.Inspector`.
+type QueueInspector interface {
+	Queues() ([]string, error)
+	GetQueueInfo(queue string) (*asynq.QueueInfo, error)
+}
+
+// The RuntimeStats type holds the statistics of the Go runtime of the process.
+// @property {int64} HeapAllocBytes - the bytes of the heap objects still in use or not yet collected.
+// @property {int64} SysBytes - the bytes obtained from the operating system.
+// @property {time.Duration} GCPauseTotal - the time the program was paused by the garbage collector.
+type RuntimeStats struct {
+	GoVersion      string        `json:"go_version"`
+	Uptime         time.Duration `json:"uptime"`
+	Goroutines     int           `json:"goroutines"`
+	CPUs           int           `json:"cpus"`
+	HeapAllocBytes uint64        `json:"heap_alloc_bytes"`
+	HeapObjects    uint64        `json:"heap_objects"`
+	SysBytes       uint64        `json:"sys_bytes"`
+	NumGC          uint32        `json:"num_gc"`
+	GCPauseTotal   time.Duration `json:"gc_pause_total"`
+}
+
+// The PoolStats type holds the statistics of a pool of database connections.
+// @property {int64} EmptyAcquireCount - the acquires that waited for a connection as none was idle,
+// which grows when the pool is too small for the load.
+// @property {time.Duration} AcquireDuration - the total time spent acquiring connections.
+type PoolStats struct {
+	MaxConns             int32         `json:"max_conns"`
+	TotalConns           int32         `json:"total_conns"`
+	AcquiredConns        int32         `json:"acquired_conns"`
+	IdleConns            int32         `json:"idle_conns"`
+	ConstructingConns    int32         `json:"constructing_conns"`
+	AcquireCount         int64         `json:"acquire_count"`
+	EmptyAcquireCount    int64         `json:"empty_acquire_count"`
+	CanceledAcquireCount int64         `json:"canceled_acquire_count"`
+	AcquireDuration      time.Duration `json:"acquire_duration"`
+}
+
+// The QueueStats type holds the sizes of a task queue.
+// @property {int} Size - the tasks in the queue, in any state apart from completed.
+// @property {time.Duration} Latency - how long the oldest pending task has waited.
+// @property {int} Processed - the tasks processed today, including the failed ones.
+type QueueStats struct {
+	Size      int           `json:"size"`
+	Pending   int           `json:"pending"`
+	Active    int           `json:"active"`
+	Scheduled int           `json:"scheduled"`
+	Retry     int           `json:"retry"`
+	Archived  int           `json:"archived"`
+	Latency   time.Duration `json:"latency"`
+	Processed int           `json:"processed"`
+	Failed    int           `json:"failed"`
+	Paused    bool          `json:"paused"`
+}
+
+// The Snapshot type holds the diagnostics of the process at a point in time.
+// @property {map[string]PoolStats} Pools - the statistics of the database pools by name.
+// @property {map[string]QueueStats} Queues - the sizes of the task queues by name.
+// @property {string} QueuesError - why the task queues couldn't be inspected, e.g. redis being down,
+// which doesn't prevent the rest of the diagnostics from being reported.
+type Snapshot struct {
+	Runtime     RuntimeStats          `json:"runtime"`
+	Pools       map[string]PoolStats  `json:"pools"`
+	Queues      map[string]QueueStats `json:"queues"`
+	QueuesError string                `json:"queues_error,omitempty"`
+}
+
+// The Collector type gathers the diagnostics of the process from the runtime, the database pools and
+// the task queues it is given.
+type Collector struct {
+	started time.Time
+	queues  QueueInspector
+
+	mutex sync.Mutex
+	pools map[string]PoolStater
+}
+
+// The `NewCollector` function creates a collector of the runtime statistics and of the sizes of the
+// queues of `queues`, which may be nil when the process doesn't use the task queues.
+func NewCollector(queues QueueInspector) *Collector {
+	return &Collector{
+		started: time.Now(),
+		queues:  queues,
+		pools:   map[string]PoolStater{},
+	}
+}
+
+// The `AddPool` method adds the statistics of the database pool to the diagnostics under `name`.
+func (collector *Collector) AddPool(name string, pool PoolStater) {
+	collector.mutex.Lock()
+	defer collector.mutex.Unlock()
+
+	collector.pools[name] = pool
+}
+
+// The `Collect` method gathers the current diagnostics.
+func (collector *Collector) Collect() Snapshot {
+	snapshot := Snapshot{
+		Runtime: collector.runtimeStats(),
+		Pools:   collector.poolStats(),
+		Queues:  map[string]QueueStats{},
+	}
+
+	if collector.queues != nil {
+		queues, err := collector.queueStats()
+		if err != nil {
+			snapshot.QueuesError = err.Error()
+		}
+		snapshot.Queues = queues
+	}
+
+	return snapshot
+}
+
+func (collector *Collector) runtimeStats() RuntimeStats {
+	var memStats runtime.MemStats
+	runtime.ReadMemStats(&memStats)
+
+	return RuntimeStats{
+		GoVersion:      runtime.Version(),
+		Uptime:         time.Since(collector.started),
+		Goroutines:     runtime.NumGoroutine(),
+		CPUs:           runtime.NumCPU(),
+		HeapAllocBytes: memStats.HeapAlloc,
+		HeapObjects:    memStats.HeapObjects,
+		SysBytes:       memStats.Sys,
+		NumGC:          memStats.NumGC,
+		GCPauseTotal:   time.Duration(memStats.PauseTotalNs),
+	}
+}
+
+func (collector *Collector) poolStats() map[string]PoolStats {
+	collector.mutex.Lock()
+	defer collector.mutex.Unlock()
+
+	stats := make(map[string]PoolStats, len(collector.pools))
+	for name, pool := range collector.pools {
+		stat := pool.Stat()
+		stats[name] = PoolStats{
+			MaxConns:             stat.MaxConns(),
+			TotalConns:           stat.TotalConns(),
+			AcquiredConns:        stat.AcquiredConns(),
+			IdleConns:            stat.IdleConns(),
+			ConstructingConns:    stat.ConstructingConns(),
+			AcquireCount:         stat.AcquireCount(),
+			EmptyAcquireCount:    stat.EmptyAcquireCount(),
+			CanceledAcquireCount: stat.CanceledAcquireCount(),
+			AcquireDuration:      stat.AcquireDuration(),
+		}
+	}
+	return stats
+}
+
+// queueStats returns the sizes of the queues that could be inspected, along with the first error.
+func (collector *Collector) queueStats() (map[string]QueueStats, error) {
+	stats := map[string]QueueStats{}
+
+	queues, err := collector.queues.Queues()
+	if err != nil {
+		return stats, err
+	}
+	sort.Strings(queues)
+
+	var firstErr error
+	for _, queue := range queues {
+		info, err := collector.queues.GetQueueInfo(queue)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+
+		stats[queue] = QueueStats{
+			Size:      info.Size,
+			Pending:   info.Pending,
+			Active:    info.Active,
+			Scheduled: info.Scheduled,
+			Retry:     info.Retry,
+			Archived:  info.Archived,
+			Latency:   info.Latency,
+			Processed: info.Processed,
+			Failed:    info.Failed,
+			Paused:    info.Paused,
+		}
+	}
+	return stats, firstErr
+}
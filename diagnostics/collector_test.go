@@ -0,0 +1,69 @@
+package diagnostics
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeQueueInspector struct {
+	queues map[string]*asynq.QueueInfo
+	err    error
+}
+
+func (inspector fakeQueueInspector) Queues() ([]string, error) {
+	if inspector.err != nil {
+		return nil, inspector.err
+	}
+
+	queues := make([]string, 0, len(inspector.queues))
+	for queue := range inspector.queues {
+		queues = append(queues, queue)
+	}
+	return queues, nil
+}
+
+func (inspector fakeQueueInspector) GetQueueInfo(queue string) (*asynq.QueueInfo, error) {
+	info, ok := inspector.queues[queue]
+	if !ok {
+		return nil, errors.New("queue not found")
+	}
+	return info, nil
+}
+
+func TestCollect(t *testing.T) {
+	collector := NewCollector(fakeQueueInspector{queues: map[string]*asynq.QueueInfo{
+		"critical": {Queue: "critical", Size: 3, Pending: 2, Active: 1, Latency: time.Second},
+		"default":  {Queue: "default", Retry: 4, Size: 4, Paused: true},
+	}})
+
+	snapshot := collector.Collect()
+	require.NotEmpty(t, snapshot.Runtime.GoVersion)
+	require.Positive(t, snapshot.Runtime.Goroutines)
+	require.Positive(t, snapshot.Runtime.HeapAllocBytes)
+	require.Empty(t, snapshot.Pools)
+
+	require.Empty(t, snapshot.QueuesError)
+	require.Equal(t, map[string]QueueStats{
+		"critical": {Size: 3, Pending: 2, Active: 1, Latency: time.Second},
+		"default":  {Size: 4, Retry: 4, Paused: true},
+	}, snapshot.Queues)
+}
+
+func TestCollectQueuesUnavailable(t *testing.T) {
+	collector := NewCollector(fakeQueueInspector{err: errors.New("redis is down")})
+
+	snapshot := collector.Collect()
+	require.Positive(t, snapshot.Runtime.Goroutines)
+	require.Empty(t, snapshot.Queues)
+	require.Equal(t, "redis is down", snapshot.QueuesError)
+}
+
+func TestCollectWithoutQueues(t *testing.T) {
+	snapshot := NewCollector(nil).Collect()
+	require.Empty(t, snapshot.Queues)
+	require.Empty(t, snapshot.QueuesError)
+}
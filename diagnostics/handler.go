@@ -0,0 +1,90 @@
+package diagnostics
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	db "go-backend/db/sqlc"
+	"go-backend/token"
+	"go-backend/util"
+	"net/http"
+	"net/http/pprof"
+	"strings"
+)
+
+// The UserGetter interface reads the users, whose role tells whether they may read the diagnostics.
+type UserGetter interface {
+	GetUser(ctx context.Context, username string) (db.User, error)
+}
+
+// The `NewHandler` function creates the handler serving the diagnostics of `collector` at /debug/vars
+// and the profiles of the process at /debug/pprof/. It doesn't authenticate the requests, see
+// `AdminOnly`.
+func NewHandler(collector *Collector) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/vars", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, collector.Collect())
+	})
+	RegisterPprof(mux)
+	return mux
+}
+
+// The `RegisterPprof` function registers the handlers of the profiles of the process at /debug/pprof/
+// on `mux`, e.g. for `go tool pprof http://host/debug/pprof/profile?seconds=30`.
+func RegisterPprof(mux *http.ServeMux) {
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+}
+
+// The `AdminOnly` function only lets through the requests bearing the access token, without scopes, of a
+// user with the admin role, as the diagnostics expose the internals of the process. The role is read
+// from `users` so that revoking it takes effect without waiting for the access token to expire.
+func AdminOnly(tokenMaker token.Maker, users UserGetter, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fields := strings.Fields(r.Header.Get("Authorization"))
+		if len(fields) < 2 || strings.ToLower(fields[0]) != "bearer" {
+			err := errors.New("a bearer access token is required")
+			writeJSON(w, http.StatusUnauthorized, util.ErrorResponse(http.StatusUnauthorized, err))
+			return
+		}
+
+		payload, err := tokenMaker.VerifyToken(fields[1])
+		if err != nil {
+			writeJSON(w, http.StatusUnauthorized, util.ErrorResponse(http.StatusUnauthorized, err))
+			return
+		}
+		if payload.Scoped() {
+			err := errors.New("the diagnostics can't be used with a scoped access token")
+			writeJSON(w, http.StatusForbidden, util.ErrorResponse(http.StatusForbidden, util.WithErrorCode(util.ErrorCodeInsufficientScope, err)))
+			return
+		}
+
+		user, err := users.GetUser(r.Context(), payload.Username)
+		if err != nil {
+			if errors.Is(err, db.ErrRecordNotFound) {
+				writeJSON(w, http.StatusUnauthorized, util.ErrorResponse(http.StatusUnauthorized, err))
+				return
+			}
+			writeJSON(w, http.StatusInternalServerError, util.ErrorResponse(http.StatusInternalServerError, err))
+			return
+		}
+
+		if user.Role != util.AdminRole {
+			err := fmt.Errorf("role %s is not allowed to access this resource", user.Role)
+			writeJSON(w, http.StatusForbidden, util.ErrorResponse(http.StatusForbidden, err))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, obj interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(obj)
+}
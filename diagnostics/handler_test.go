@@ -0,0 +1,135 @@
+package diagnostics
+
+import (
+	"encoding/json"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/testutil/factory"
+	"go-backend/token"
+	"go-backend/util"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminOnly(t *testing.T) {
+	admin := factory.User(factory.WithRole(util.AdminRole))
+	depositor := factory.User()
+
+	testCases := []struct {
+		name          string
+		path          string
+		user          *db.User
+		scopes        []string
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Vars",
+			path: "/debug/vars",
+			user: &admin,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).Times(1).Return(admin, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var snapshot Snapshot
+				err := json.Unmarshal(recorder.Body.Bytes(), &snapshot)
+				require.NoError(t, err)
+				require.Positive(t, snapshot.Runtime.Goroutines)
+			},
+		},
+		{
+			name: "Pprof",
+			path: "/debug/pprof/goroutine?debug=1",
+			user: &admin,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).Times(1).Return(admin, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Body.String(), "goroutine profile")
+			},
+		},
+		{
+			name: "NoAuthorization",
+			path: "/debug/vars",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "Forbidden",
+			path: "/debug/vars",
+			user: &depositor,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(depositor.Username)).Times(1).Return(depositor, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:   "Scoped",
+			path:   "/debug/vars",
+			user:   &admin,
+			scopes: token.Scopes,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				require.Contains(t, recorder.Body.String(), util.ErrorCodeInsufficientScope)
+			},
+		},
+		{
+			name: "UserNotFound",
+			path: "/debug/vars",
+			user: &admin,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).Times(1).Return(db.User{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			tokenMaker, err := token.NewPasetoMaker(util.RandomString(32))
+			require.NoError(t, err)
+
+			handler := AdminOnly(tokenMaker, store, NewHandler(NewCollector(nil)))
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, tc.path, nil)
+			require.NoError(t, err)
+
+			if tc.user != nil {
+				accessToken, _, err := tokenMaker.CreateToken(token.Claims{Username: tc.user.Username, Scopes: tc.scopes}, time.Minute)
+				require.NoError(t, err)
+				request.Header.Set("Authorization", "Bearer "+accessToken)
+			}
+
+			handler.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package changelog
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Types of the changes of the changelog.
+const (
+	TypeAdded      = "added"
+	TypeChanged    = "changed"
+	TypeDeprecated = "deprecated"
+	TypeRemoved    = "removed"
+)
+
+// dateLayout is the layout of the dates of the changelog.
+const dateLayout = "2006-01-02"
+
+// data holds the changelog of the HTTP API, newest change first, so that it always ships with the binary
+// whose changes it lists.
+//
+//go:embed changelog.json
+var data []byte
+
+// The Changelog type is the list of the changes of the HTTP API.
+type Changelog struct {
+	Changes []Change `json:"changes"`
+}
+
+// The Change type is a change of the HTTP API.
+// @property {string} Date - the day the change was released, as YYYY-MM-DD.
+// @property {string} Type - added, changed, deprecated or removed.
+// @property {string} Method - the method of the route changed, empty for a change of the whole API.
+// @property {string} Path - the path of the route changed, with its parameters written as `{name}`.
+// @property {string} Description - what changed, for the clients of the API.
+// @property {string} Sunset - the day a deprecated route is removed, as YYYY-MM-DD.
+// @property {string} Replacement - the route to use instead of a deprecated one.
+type Change struct {
+	Date        string `json:"date"`
+	Type        string `json:"type"`
+	Method      string `json:"method,omitempty"`
+	Path        string `json:"path,omitempty"`
+	Description string `json:"description"`
+	Sunset      string `json:"sunset,omitempty"`
+	Replacement string `json:"replacement,omitempty"`
+}
+
+// The function parses the embedded changelog, checking that every change has a known type and valid
+// dates, and that the deprecations name their route and sunset.
+func Load() (Changelog, error) {
+	var changelog Changelog
+	err := json.Unmarshal(data, &changelog)
+	if err != nil {
+		return changelog, fmt.Errorf("cannot parse changelog: %w", err)
+	}
+
+	for i, change := range changelog.Changes {
+		err = change.validate()
+		if err != nil {
+			return changelog, fmt.Errorf("invalid change %d of the changelog: %w", i, err)
+		}
+	}
+	return changelog, nil
+}
+
+func (change Change) validate() error {
+	switch change.Type {
+	case TypeAdded, TypeChanged, TypeDeprecated, TypeRemoved:
+	default:
+		return fmt.Errorf("unknown type %q", change.Type)
+	}
+	if change.Description == "" {
+		return errors.New("missing description")
+	}
+	if (change.Method == "") != (change.Path == "") {
+		return errors.New("method and path must be set together")
+	}
+
+	date, err := change.DateTime()
+	if err != nil {
+		return fmt.Errorf("invalid date: %w", err)
+	}
+	if change.Type != TypeDeprecated {
+		return nil
+	}
+
+	if change.Path == "" {
+		return errors.New("deprecation without a route")
+	}
+	sunset, err := change.SunsetTime()
+	if err != nil {
+		return fmt.Errorf("invalid sunset: %w", err)
+	}
+	if !sunset.After(date) {
+		return fmt.Errorf("sunset %s is not after the deprecation", change.Sunset)
+	}
+	return nil
+}
+
+// The `DateTime` function returns the day the change was released, at midnight UTC.
+func (change Change) DateTime() (time.Time, error) {
+	return time.Parse(dateLayout, change.Date)
+}
+
+// The `SunsetTime` function returns the day a deprecated route is removed, at midnight UTC.
+func (change Change) SunsetTime() (time.Time, error) {
+	return time.Parse(dateLayout, change.Sunset)
+}
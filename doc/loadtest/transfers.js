@@ -0,0 +1,90 @@
+// Load profile of the transfers for k6 (https://k6.io), measuring the throughput of TransferTx through the
+// gin HTTP API.
+//
+// Serve the API with the profiles exposed, then run the profile against it:
+//
+//   go run main.go serve --http-api --pprof-address localhost:6060
+//   k6 run -e BASE_URL=http://localhost:8080 -e RATE=200 -e DURATION=1m doc/loadtest/transfers.js
+//
+// and take a CPU profile while it runs with
+//
+//   go tool pprof "http://localhost:6060/debug/pprof/profile?seconds=30"
+//
+// Two users are created with a funded account each, and every iteration transfers between them in one
+// direction or the other, so that the balances hold however long the test runs.
+import http from "k6/http";
+import { check, fail } from "k6";
+
+const baseURL = `${__ENV.BASE_URL || "http://localhost:8080"}/api/v1`;
+const currency = __ENV.CURRENCY || "USD";
+const password = "loadtest";
+
+export const options = {
+  scenarios: {
+    transfers: {
+      executor: "constant-arrival-rate",
+      rate: Number(__ENV.RATE || 100),
+      timeUnit: "1s",
+      duration: __ENV.DURATION || "1m",
+      preAllocatedVUs: Number(__ENV.VUS || 50),
+    },
+  },
+  thresholds: {
+    http_req_failed: ["rate<0.01"],
+    "http_req_duration{name:transfer}": ["p(95)<250"],
+  },
+};
+
+function post(path, body, token) {
+  const headers = { "Content-Type": "application/json" };
+  if (token) {
+    headers.Authorization = `Bearer ${token}`;
+  }
+  return http.post(`${baseURL}${path}`, JSON.stringify(body), { headers, tags: { name: "setup" } });
+}
+
+function createFundedAccount(suffix) {
+  const username = `loadtest${Date.now()}${suffix}`;
+  let res = post("/users", {
+    username,
+    password,
+    full_name: `Load Test ${suffix}`,
+    email: `${username}@example.com`,
+  });
+  if (res.status !== 200) fail(`cannot create user: ${res.status} ${res.body}`);
+
+  res = post("/users/login", { username, password });
+  if (res.status !== 200) fail(`cannot login: ${res.status} ${res.body}`);
+  const token = res.json("access_token");
+
+  res = post("/accounts", { currency }, token);
+  if (res.status !== 200) fail(`cannot create account: ${res.status} ${res.body}`);
+  const id = res.json("id");
+
+  res = http.put(`${baseURL}/accounts/${id}`, JSON.stringify({ balance: 1000000000 }), {
+    headers: { "Content-Type": "application/json", Authorization: `Bearer ${token}` },
+    tags: { name: "setup" },
+  });
+  if (res.status !== 200) fail(`cannot fund account: ${res.status} ${res.body}`);
+
+  return { token, id };
+}
+
+export function setup() {
+  return [createFundedAccount("a"), createFundedAccount("b")];
+}
+
+export default function (accounts) {
+  const from = accounts[__ITER % 2];
+  const to = accounts[(__ITER + 1) % 2];
+
+  const res = http.post(
+    `${baseURL}/transfers`,
+    JSON.stringify({ from_account_id: from.id, to_account_id: to.id, amount: 1, currency }),
+    {
+      headers: { "Content-Type": "application/json", Authorization: `Bearer ${from.token}` },
+      tags: { name: "transfer" },
+    },
+  );
+  check(res, { "transfer succeeded": (r) => r.status === 200 });
+}
@@ -0,0 +1,49 @@
+#!/bin/sh
+# Load profile of the transfers for vegeta (https://github.com/tsenart/vegeta), the counterpart of
+# transfers.js for k6. It creates two users with a funded account each, then attacks the transfer route
+# with transfers between them in both directions:
+#
+#   go run main.go serve --http-api --pprof-address localhost:6060
+#   BASE_URL=http://localhost:8080 RATE=200 DURATION=1m doc/loadtest/vegeta.sh
+#
+# Requires curl, jq and vegeta.
+set -eu
+
+BASE_URL="${BASE_URL:-http://localhost:8080}/api/v1"
+CURRENCY="${CURRENCY:-USD}"
+RATE="${RATE:-100}"
+DURATION="${DURATION:-1m}"
+PASSWORD=loadtest
+
+request() {
+  curl -sSf -X "$1" "$BASE_URL$2" -H "Content-Type: application/json" ${3:+-H "Authorization: Bearer $3"} -d "$4"
+}
+
+# create_funded_account prints the access token of a new user and the ID of their funded account.
+create_funded_account() {
+  username="loadtest$(date +%s)$1"
+  request POST /users "" "{\"username\":\"$username\",\"password\":\"$PASSWORD\",\"full_name\":\"Load Test $1\",\"email\":\"$username@example.com\"}" >/dev/null
+  token=$(request POST /users/login "" "{\"username\":\"$username\",\"password\":\"$PASSWORD\"}" | jq -r .access_token)
+  id=$(request POST /accounts "$token" "{\"currency\":\"$CURRENCY\"}" | jq -r .id)
+  request PUT "/accounts/$id" "$token" '{"balance":1000000000}' >/dev/null
+  echo "$token $id"
+}
+
+read -r token_a id_a <<EOF_A
+$(create_funded_account a)
+EOF_A
+read -r token_b id_b <<EOF_B
+$(create_funded_account b)
+EOF_B
+
+target() {
+  body=$(printf '{"from_account_id":%s,"to_account_id":%s,"amount":1,"currency":"%s"}' "$2" "$3" "$CURRENCY" | base64 | tr -d '\n')
+  printf '{"method":"POST","url":"%s/transfers","header":{"Content-Type":["application/json"],"Authorization":["Bearer %s"]},"body":"%s"}\n' "$BASE_URL" "$1" "$body"
+}
+
+targets=$(mktemp)
+trap 'rm -f "$targets"' EXIT
+target "$token_a" "$id_a" "$id_b" >"$targets"
+target "$token_b" "$id_b" "$id_a" >>"$targets"
+
+vegeta attack -format=json -targets="$targets" -rate="$RATE" -duration="$DURATION" | vegeta report
@@ -0,0 +1,17 @@
+package openapi
+
+import (
+	_ "embed"
+)
+
+// Spec holds the OpenAPI 3 document of the HTTP API, served alongside the Swagger UI so that the
+// documentation always ships with the binary it describes.
+//
+//go:embed openapi.json
+var Spec []byte
+
+// UI holds the Swagger UI page rendering Spec. The page loads the Swagger UI assets from a CDN and the
+// spec from the `openapi.json` route next to it.
+//
+//go:embed index.html
+var UI []byte
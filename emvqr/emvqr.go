@@ -0,0 +1,141 @@
+// Package emvqr writes the payloads of the QR codes paying an account, in the format of the EMVCo
+// merchant-presented QR codes that mobile banking apps scan.
+package emvqr
+
+import (
+	"fmt"
+	"strconv"
+	"unicode/utf8"
+
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+// GloballyUniqueID identifies the bank in the merchant account information of the payloads, telling the
+// apps scanning them which account numbers they carry.
+const GloballyUniqueID = "com.gobank.account"
+
+// The ids of the data objects of a payload, in the order they are written.
+const (
+	idPayloadFormat       = "00"
+	idPointOfInitiation   = "01"
+	idMerchantAccount     = "26"
+	idMerchantCategory    = "52"
+	idTransactionCurrency = "53"
+	idTransactionAmount   = "54"
+	idMerchantName        = "59"
+	idCRC                 = "63"
+
+	// the ids of the data objects nested in the merchant account information
+	idGloballyUniqueID = "00"
+	idAccountNumber    = "01"
+)
+
+const (
+	// payloadFormat is the only version of the payload format.
+	payloadFormat = "01"
+	// staticInitiation marks the payloads without an amount, which the payer enters, and
+	// dynamicInitiation those with one.
+	staticInitiation  = "11"
+	dynamicInitiation = "12"
+	// unknownMerchantCategory is the category code of the accounts, which aren't merchants.
+	unknownMerchantCategory = "0000"
+	// maxMerchantName is the longest merchant name, the longer ones being truncated.
+	maxMerchantName = 25
+	// maxAmount is the longest amount written.
+	maxAmount = 13
+)
+
+// numericCurrencies are the ISO 4217 numeric codes of the currencies, which the payloads are written
+// with.
+var numericCurrencies = map[string]string{
+	"USD": "840",
+	"EUR": "978",
+	"CAD": "124",
+}
+
+// The Payload type is what a QR code paying an account encodes.
+// @property {string} AccountNumber - the number of the account paid.
+// @property {string} Currency - the ISO 4217 code of the currency of the account.
+// @property {string} Amount - the amount to pay as a decimal number, e.g. "12.00", optional. The payer
+// enters the amount when it is empty.
+// @property {string} MerchantName - the name of the owner of the account shown to the payer, truncated to
+// 25 characters.
+type Payload struct {
+	AccountNumber string
+	Currency      string
+	Amount        string
+	MerchantName  string
+}
+
+// The `Encode` function writes the payload as EMVCo data objects, each an id, the length of its value
+// and the value, ending with the CRC of the whole payload.
+func Encode(payload Payload) (string, error) {
+	currency, ok := numericCurrencies[payload.Currency]
+	if !ok {
+		return "", fmt.Errorf("unknown currency %s", payload.Currency)
+	}
+	if len(payload.Amount) > maxAmount {
+		return "", fmt.Errorf("amount %s is longer than %d characters", payload.Amount, maxAmount)
+	}
+
+	initiation := staticInitiation
+	if payload.Amount != "" {
+		initiation = dynamicInitiation
+	}
+
+	data := dataObject(idPayloadFormat, payloadFormat) +
+		dataObject(idPointOfInitiation, initiation) +
+		dataObject(idMerchantAccount, dataObject(idGloballyUniqueID, GloballyUniqueID)+dataObject(idAccountNumber, payload.AccountNumber)) +
+		dataObject(idMerchantCategory, unknownMerchantCategory) +
+		dataObject(idTransactionCurrency, currency)
+	if payload.Amount != "" {
+		data += dataObject(idTransactionAmount, payload.Amount)
+	}
+	if payload.MerchantName != "" {
+		data += dataObject(idMerchantName, truncate(payload.MerchantName, maxMerchantName))
+	}
+
+	// the CRC covers its own id and length
+	data += idCRC + "04"
+	return data + fmt.Sprintf("%04X", crc16(data)), nil
+}
+
+// The `PNG` function draws the QR code of an encoded payload as a PNG image of `size` pixels square.
+func PNG(encoded string, size int) ([]byte, error) {
+	return qrcode.Encode(encoded, qrcode.Medium, size)
+}
+
+// dataObject writes a data object, whose value is at most 99 characters.
+func dataObject(id string, value string) string {
+	length := utf8.RuneCountInString(value)
+	if length < 10 {
+		return id + "0" + strconv.Itoa(length) + value
+	}
+	return id + strconv.Itoa(length) + value
+}
+
+// truncate returns the first `n` characters of `s`.
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) > n {
+		return string(runes[:n])
+	}
+	return s
+}
+
+// crc16 returns the CRC-16/CCITT-FALSE checksum of `data`, polynomial 0x1021 starting from 0xFFFF,
+// which the payloads end with.
+func crc16(data string) uint16 {
+	crc := uint16(0xFFFF)
+	for i := 0; i < len(data); i++ {
+		crc ^= uint16(data[i]) << 8
+		for bit := 0; bit < 8; bit++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
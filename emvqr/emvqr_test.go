@@ -0,0 +1,66 @@
+package emvqr
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCRC16(t *testing.T) {
+	// the check value of CRC-16/CCITT-FALSE
+	require.Equal(t, uint16(0x29B1), crc16("123456789"))
+}
+
+func TestEncode(t *testing.T) {
+	payload := Payload{
+		AccountNumber: "GO12BANK0000000042",
+		Currency:      "CAD",
+		Amount:        "12.00",
+		MerchantName:  "Jane Doe",
+	}
+
+	encoded, err := Encode(payload)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(encoded, "000201"+"010212"))
+	require.Contains(t, encoded, "2644"+"0018"+GloballyUniqueID+"0118GO12BANK0000000042")
+	require.Contains(t, encoded, "5303124")
+	require.Contains(t, encoded, "540512.00")
+	require.Contains(t, encoded, "5908Jane Doe")
+
+	// the payload ends with the CRC of the rest of it
+	require.Equal(t, "6304", encoded[len(encoded)-8:len(encoded)-4])
+	crc, err := strconv.ParseUint(encoded[len(encoded)-4:], 16, 16)
+	require.NoError(t, err)
+	require.Equal(t, crc16(encoded[:len(encoded)-4]), uint16(crc))
+}
+
+func TestEncodeWithoutAmount(t *testing.T) {
+	encoded, err := Encode(Payload{AccountNumber: "GO12BANK0000000042", Currency: "USD"})
+	require.NoError(t, err)
+	// the payer enters the amount of the static codes
+	require.True(t, strings.HasPrefix(encoded, "000201"+"010211"))
+	require.Contains(t, encoded, "5303840"+"6304")
+}
+
+func TestEncodeTruncatesMerchantName(t *testing.T) {
+	encoded, err := Encode(Payload{AccountNumber: "GO12BANK0000000042", Currency: "EUR", MerchantName: strings.Repeat("a", 30)})
+	require.NoError(t, err)
+	require.Contains(t, encoded, "5925"+strings.Repeat("a", 25)+"6304")
+}
+
+func TestEncodeUnknownCurrency(t *testing.T) {
+	_, err := Encode(Payload{AccountNumber: "GO12BANK0000000042", Currency: "XYZ"})
+	require.Error(t, err)
+}
+
+func TestPNG(t *testing.T) {
+	encoded, err := Encode(Payload{AccountNumber: "GO12BANK0000000042", Currency: "USD"})
+	require.NoError(t, err)
+
+	image, err := PNG(encoded, 256)
+	require.NoError(t, err)
+	require.True(t, bytes.HasPrefix(image, []byte("\x89PNG\r\n\x1a\n")))
+}
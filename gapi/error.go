@@ -0,0 +1,70 @@
+package gapi
+
+import (
+	"context"
+	"go-backend/service"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// leaderAddressMetadataKey is the header sent with the writes rejected by a standby, the gateway
+// forwards it as Grpc-Metadata-X-Leader-Address.
+const leaderAddressMetadataKey = "x-leader-address"
+
+var errorCodes = map[service.Code]codes.Code{
+	service.CodeInternal:           codes.Internal,
+	service.CodeInvalidArgument:    codes.InvalidArgument,
+	service.CodeNotFound:           codes.NotFound,
+	service.CodeUnauthenticated:    codes.Unauthenticated,
+	service.CodePermissionDenied:   codes.PermissionDenied,
+	service.CodeAlreadyExists:      codes.AlreadyExists,
+	service.CodeFailedPrecondition: codes.FailedPrecondition,
+	service.CodeUnavailable:        codes.Unavailable,
+	service.CodeLocked:             codes.ResourceExhausted,
+	service.CodeDeadlineExceeded:   codes.DeadlineExceeded,
+	service.CodeResourceExhausted:  codes.ResourceExhausted,
+}
+
+// serviceError converts an error returned by the service to a gRPC status. The details of internal
+// errors are not sent to the client, they are only kept for the error tracking.
+func serviceError(err error, internalMessage string) error {
+	code := errorCodes[service.ErrorCode(err)]
+	if code == codes.Internal {
+		return &internalError{status: status.New(code, internalMessage), err: err}
+	}
+	return status.Error(code, err.Error())
+}
+
+// internalError is the status of an internal error, which unwraps to the error it was made from.
+type internalError struct {
+	status *status.Status
+	err    error
+}
+
+func (e *internalError) Error() string {
+	return e.status.Message()
+}
+
+func (e *internalError) GRPCStatus() *status.Status {
+	return e.status
+}
+
+func (e *internalError) Unwrap() error {
+	return e.err
+}
+
+// checkLeader fails with Unavailable, sending the address of the leader when it is known, while the
+// instance is a standby. It is called by the RPCs that write.
+func (server *Server) checkLeader(ctx context.Context) error {
+	if server.leadership == nil || server.leadership.IsLeader() {
+		return nil
+	}
+
+	if address := server.leadership.LeaderAddress(); address != "" {
+		grpc.SetHeader(ctx, metadata.Pairs(leaderAddressMetadataKey, address))
+	}
+	return status.Error(codes.Unavailable, "this instance is a standby and only serves reads, send writes to the leader")
+}
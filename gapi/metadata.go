@@ -0,0 +1,37 @@
+package gapi
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// forwardedForMetadataKey is the header the gateway forwards the address of the HTTP client with.
+const forwardedForMetadataKey = "x-forwarded-for"
+
+// The `clientIP` function returns the address of the client of the call: the peer of a gRPC connection,
+// or the HTTP client the gateway serving the call in-process forwarded. The last forwarded address is the
+// one the gateway saw, the others were sent by the client and aren't trusted.
+func clientIP(ctx context.Context) string {
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		host, _, err := net.SplitHostPort(p.Addr.String())
+		if err != nil {
+			return p.Addr.String()
+		}
+		return host
+	}
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	forwarded := md.Get(forwardedForMetadataKey)
+	if len(forwarded) == 0 {
+		return ""
+	}
+	addresses := strings.Split(forwarded[len(forwarded)-1], ",")
+	return strings.TrimSpace(addresses[len(addresses)-1])
+}
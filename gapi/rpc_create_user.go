@@ -2,38 +2,40 @@ package gapi
 
 import (
 	"context"
-	db "go-backend/db/sqlc"
+	"errors"
+	"go-backend/captcha"
 	"go-backend/pb"
-	"go-backend/util"
+	"go-backend/service"
 
-	"github.com/lib/pq"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
+// The `CreateUser` function registers a user who solved the CAPTCHA, when one is configured, like the
+// POST /users endpoint: an invalid token fails with InvalidArgument, and the registrations fail with
+// Unavailable while the provider can't be reached.
 func (server *Server) CreateUser(ctx context.Context, req *pb.CreateUserRequest) (*pb.CreateUserResponse, error) {
-	hashedPassword, err := util.HashPassword(req.GetPassword())
-	if err != nil {
-		return nil, status.Errorf(codes.Internal, "could not hash password")
+	if err := server.checkLeader(ctx); err != nil {
+		return nil, err
 	}
 
-	arg := db.CreateUserParams{
-		Username:       req.GetUsername(),
-		HashedPassword: hashedPassword,
-		FullName:       req.GetFullName(),
-		Email:          req.GetEmail(),
+	ip := clientIP(ctx)
+	if err := server.captcha.Verify(ctx, req.GetCaptchaToken(), ip); err != nil {
+		if errors.Is(err, captcha.ErrFailed) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return nil, &internalError{status: status.New(codes.Unavailable, "failed to verify CAPTCHA"), err: err}
 	}
 
-	user, err := server.store.CreateUser(ctx, arg)
-
+	user, err := server.service.CreateUser(ctx, service.CreateUserParams{
+		Username: req.GetUsername(),
+		Password: req.GetPassword(),
+		FullName: req.GetFullName(),
+		Email:    req.GetEmail(),
+		ClientIP: ip,
+	})
 	if err != nil {
-		if pqError, ok := err.(*pq.Error); ok {
-			switch pqError.Code.Name() {
-			case "unique_violation":
-				return nil, status.Errorf(codes.AlreadyExists, "username already exists")
-			}
-		}
-		return nil, status.Errorf(codes.Internal, "failed to create user")
+		return nil, serviceError(err, "failed to create user")
 	}
 
 	res := &pb.CreateUserResponse{
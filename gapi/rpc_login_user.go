@@ -2,60 +2,33 @@ package gapi
 
 import (
 	"context"
-	"database/sql"
-	db "go-backend/db/sqlc"
 	"go-backend/pb"
-	"go-backend/util"
+	"go-backend/service"
 
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 func (server *Server) LoginUser(ctx context.Context, req *pb.LoginUserRequest) (*pb.LoginUserResponse, error) {
-	user, err := server.store.GetUser(ctx, req.GetUsername())
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, status.Errorf(codes.NotFound, "no rows")
-		}
-		return nil, status.Errorf(codes.Internal, "an error occured getting the user")
-	}
-
-	err = util.Checkpassword(req.Password, user.HashedPassword)
-	if err != nil {
-		return nil, status.Errorf(codes.NotFound, "invalid password")
-	}
-
-	accessToken, accessPayload, err := server.tokenMaker.CreateToken(user.Username, server.config.AccessTokenDuration)
-	if err != nil {
-		return nil, status.Errorf(codes.Internal, "could not create access token")
-	}
-
-	refreshToken, refreshPayload, err := server.tokenMaker.CreateToken(user.Username, server.config.RefreshTokenDuration)
-	if err != nil {
-		return nil, status.Errorf(codes.Internal, "could not create refresh token")
+	if err := server.checkLeader(ctx); err != nil {
+		return nil, err
 	}
 
-	session, err := server.store.CreateSession(ctx, db.CreateSessionParams{
-		ID:           refreshPayload.ID,
-		Username:     user.Username,
-		RefreshToken: refreshToken,
-		UserAgent:    "",
-		ClientIp:     "",
-		IsBlocked:    false,
-		ExpiresAt:    refreshPayload.ExpiredAt,
+	result, err := server.service.LoginUser(ctx, service.LoginUserParams{
+		Identifier: req.GetUsername(),
+		Password:   req.GetPassword(),
+		ClientIP:   clientIP(ctx),
 	})
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "could not create session")
+		return nil, serviceError(err, "failed to login user")
 	}
 
 	res := &pb.LoginUserResponse{
-		User:                  convertUser(user),
-		SessionId:             session.ID.String(),
-		AccessToken:           accessToken,
-		RefreshToken:          refreshToken,
-		AccessTokenExpiresAt:  timestamppb.New(accessPayload.ExpiredAt),
-		RefreshTokenExpiresAt: timestamppb.New(refreshPayload.ExpiredAt),
+		User:                  convertUser(result.User),
+		SessionId:             result.Session.ID.String(),
+		AccessToken:           result.AccessToken,
+		RefreshToken:          result.RefreshToken,
+		AccessTokenExpiresAt:  timestamppb.New(result.AccessPayload.ExpiredAt),
+		RefreshTokenExpiresAt: timestamppb.New(result.RefreshPayload.ExpiredAt),
 	}
 	return res, nil
 }
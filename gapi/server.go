@@ -2,10 +2,13 @@ package gapi
 
 import (
 	"fmt"
+	"go-backend/captcha"
 	db "go-backend/db/sqlc"
 	"go-backend/pb"
+	"go-backend/service"
 	"go-backend/token"
 	"go-backend/util"
+	"go-backend/worker"
 )
 
 type Server struct {
@@ -13,19 +16,42 @@ type Server struct {
 	config     util.Config
 	store      db.Store
 	tokenMaker token.Maker
+	service    *service.Service
+	leadership worker.Leadership
+	captcha    captcha.Verifier
 }
 
 func NewServer(config util.Config, store db.Store) (*Server, error) {
-	tokenMaker, err := token.NewPasetoMaker(config.TokenSymmetricKey)
+	tokenMaker, err := token.NewMaker(token.MakerConfig{
+		Kind:             config.TokenKind,
+		SymmetricKey:     config.TokenSymmetricKey,
+		PrivateKey:       config.TokenPrivateKey,
+		PublicKey:        config.TokenPublicKey,
+		KeyID:            config.TokenKeyID,
+		VerificationKeys: config.TokenVerificationKeys,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("cannot create token maker: %w", err)
 	}
 
+	captchaVerifier, err := captcha.NewVerifier(config)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create CAPTCHA verifier: %w", err)
+	}
+
 	server := &Server{
 		config:     config,
 		store:      store,
 		tokenMaker: tokenMaker,
+		service:    service.New(config, store, tokenMaker, nil),
+		captcha:    captchaVerifier,
 	}
 
 	return server, nil
 }
+
+// The `SetLeadership` function makes the server a standby, rejecting the writes, whenever the instance
+// isn't the leader. Every instance serves writes when it is never called.
+func (server *Server) SetLeadership(leadership worker.Leadership) {
+	server.leadership = leadership
+}
@@ -0,0 +1,60 @@
+package graph
+
+import "errors"
+
+// The Location type is a position in the text of a request, from 1.
+type Location struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// The Error type is an error of the response: the request couldn't be parsed or validated, or a field
+// failed, which is then null. The cause of the error of a field is kept for the error tracking, its
+// message being the only part sent to the client.
+// @property {[]any} Path - the response keys and list indices leading to the field that failed.
+// @property {map[string]any} Extensions - the details of the error, e.g. its code.
+type Error struct {
+	Message    string         `json:"message"`
+	Locations  []Location     `json:"locations,omitempty"`
+	Path       []any          `json:"path,omitempty"`
+	Extensions map[string]any `json:"extensions,omitempty"`
+
+	err error
+}
+
+// The function creates the error of a field with its code, sent in the code extension, and its cause,
+// whose message is sent unless `message` is set.
+func NewError(code string, message string, err error) *Error {
+	if message == "" {
+		message = err.Error()
+	}
+	return &Error{Message: message, Extensions: map[string]any{"code": code}, err: err}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+// Code returns the code of the error, or an empty string when it has none.
+func (e *Error) Code() string {
+	code, _ := e.Extensions["code"].(string)
+	return code
+}
+
+// fieldError returns the error of a field at a path, failed with the error returned by its resolver or
+// its authorization.
+func fieldError(err error, location Location, path []any) *Error {
+	var graphErr *Error
+	if !errors.As(err, &graphErr) {
+		graphErr = &Error{Message: err.Error(), err: err}
+	}
+
+	fieldErr := *graphErr
+	fieldErr.Locations = []Location{location}
+	fieldErr.Path = append([]any(nil), path...)
+	return &fieldErr
+}
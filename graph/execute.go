@@ -0,0 +1,818 @@
+package graph
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// timeFormat is the format of the Time scalar: RFC 3339 in UTC with milliseconds, like the times of the
+// REST responses.
+const timeFormat = "2006-01-02T15:04:05.000Z07:00"
+
+// The Request type is a GraphQL request, as sent in the body of a POST request.
+// @property {string} OperationName - the operation to execute, which can be omitted when the query
+// holds a single one.
+// @property {map[string]any} Variables - the values of the variables of the operation, as decoded from
+// JSON. The numbers can be float64 or json.Number, the latter keeping the precision of the Int64 values.
+type Request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+// The Response type is the result of a GraphQL request. The data is missing when the request couldn't
+// be executed, and null when a non-null root field failed.
+type Response struct {
+	Data   json.RawMessage `json:"data,omitempty"`
+	Errors []*Error        `json:"errors,omitempty"`
+}
+
+// The `Execute` function parses, validates and executes a query against the schema, once it is within
+// the limits of the schema. The errors of the request leave the response without data, whereas the
+// errors of the fields only leave them null, or the nearest nullable field holding them when they can't
+// be null, the others still being resolved.
+func (schema *Schema) Execute(ctx context.Context, request Request) Response {
+	doc, err := parse(request.Query)
+	if err != nil {
+		return Response{Errors: []*Error{err.(*Error)}}
+	}
+
+	operation, errs := schema.validate(doc, request.OperationName)
+	if len(errs) > 0 {
+		return Response{Errors: errs}
+	}
+
+	variables, errs := schema.coerceVariables(operation, request.Variables)
+	if len(errs) > 0 {
+		return Response{Errors: errs}
+	}
+
+	e := &executor{schema: schema, doc: doc, variables: variables}
+	if err := e.checkLimits(operation); err != nil {
+		return Response{Errors: []*Error{err}}
+	}
+	data, ok := e.executeSelectionSet(ctx, schema.query, nil, operation.selections, nil)
+
+	var result any
+	if ok {
+		result = data
+	}
+	raw, err := json.Marshal(result)
+	if err != nil {
+		return Response{Errors: []*Error{{Message: fmt.Sprintf("cannot encode the response: %v", err), err: err}}}
+	}
+	return Response{Data: raw, Errors: e.errors}
+}
+
+// The validator type checks a request against the schema before it is executed, collecting every error
+// it finds.
+type validator struct {
+	schema    *Schema
+	doc       *document
+	operation *operation
+	used      map[string]bool
+	// validated holds the fragments whose selections were validated, which are the same wherever they are
+	// spread, so that the fragments spread many times, possibly nested, are validated once.
+	validated map[string]bool
+	errors    []*Error
+}
+
+func (v *validator) fail(location Location, format string, a ...any) {
+	v.errors = append(v.errors, errorAt(location, format, a...))
+}
+
+// validate returns the operation of the document to execute, with the errors that prevent executing it:
+// the unknown fields, arguments, fragments and variables, the missing required arguments and the
+// selections that don't match the type of their field.
+func (schema *Schema) validate(doc *document, operationName string) (*operation, []*Error) {
+	var operation *operation
+	switch {
+	case operationName != "":
+		for _, candidate := range doc.operations {
+			if candidate.name == operationName {
+				operation = candidate
+			}
+		}
+		if operation == nil {
+			return nil, []*Error{{Message: fmt.Sprintf("unknown operation %q", operationName)}}
+		}
+	case len(doc.operations) > 1:
+		return nil, []*Error{{Message: "operationName is required as the document holds several operations"}}
+	default:
+		operation = doc.operations[0]
+	}
+
+	if operation.kind != "query" {
+		return nil, []*Error{errorAt(operation.location, "only queries are supported, not %ss", operation.kind)}
+	}
+
+	v := &validator{schema: schema, doc: doc, operation: operation, used: map[string]bool{}, validated: map[string]bool{}}
+	defined := map[string]bool{}
+	for _, variable := range operation.variables {
+		if defined[variable.name] {
+			v.fail(operation.location, "there can be only one variable named $%s", variable.name)
+		}
+		defined[variable.name] = true
+
+		if !schema.isInputType(variable.typ.namedType()) {
+			v.fail(operation.location, "variable $%s can't be of type %s", variable.name, variable.typ)
+		}
+	}
+
+	v.validateSelectionSet(schema.query, operation.selections, map[string]bool{})
+
+	for _, fragment := range doc.fragments {
+		if !v.used[fragment.name] {
+			v.fail(fragment.location, "fragment %q is never used", fragment.name)
+		}
+	}
+	sort.SliceStable(v.errors, func(i, j int) bool {
+		a, b := v.errors[i].Locations[0], v.errors[j].Locations[0]
+		return a.Line < b.Line || (a.Line == b.Line && a.Column < b.Column)
+	})
+	return operation, v.errors
+}
+
+func (v *validator) validateSelectionSet(object *Object, selections []selection, spreading map[string]bool) {
+	for _, selection := range selections {
+		switch {
+		case selection.field != nil:
+			v.validateField(object, selection.field, spreading)
+
+		case selection.spread != nil:
+			spread := selection.spread
+			v.validateDirectives(spread.directives)
+
+			fragment, ok := v.doc.fragments[spread.name]
+			if !ok {
+				v.fail(spread.location, "unknown fragment %q", spread.name)
+				continue
+			}
+			v.used[spread.name] = true
+
+			if spreading[spread.name] {
+				v.fail(spread.location, "cannot spread fragment %q within itself", spread.name)
+				continue
+			}
+			if fragment.typeCondition != object.Name {
+				v.fail(spread.location, "fragment %q cannot be spread here as objects of type %q can never be of type %q", spread.name, object.Name, fragment.typeCondition)
+				continue
+			}
+
+			if v.validated[spread.name] {
+				continue
+			}
+			v.validated[spread.name] = true
+
+			v.validateDirectives(fragment.directives)
+			spreading[spread.name] = true
+			v.validateSelectionSet(object, fragment.selections, spreading)
+			delete(spreading, spread.name)
+
+		case selection.inline != nil:
+			inline := selection.inline
+			v.validateDirectives(inline.directives)
+			if inline.typeCondition != "" && inline.typeCondition != object.Name {
+				v.fail(inline.location, "an inline fragment on %q cannot be spread in type %q", inline.typeCondition, object.Name)
+				continue
+			}
+			v.validateSelectionSet(object, inline.selections, spreading)
+		}
+	}
+}
+
+func (v *validator) validateField(object *Object, f *field, spreading map[string]bool) {
+	v.validateDirectives(f.directives)
+
+	if f.name == "__typename" {
+		if len(f.arguments) > 0 {
+			v.fail(f.location, "unknown argument %q on field %q", f.arguments[0].name, f.name)
+		}
+		if len(f.selections) > 0 {
+			v.fail(f.location, "field %q must not have a selection since type %q has no subfields", f.name, "String")
+		}
+		return
+	}
+
+	definition, ok := object.fields[f.name]
+	if !ok {
+		v.fail(f.location, "cannot query field %q on type %q", f.name, object.Name)
+		return
+	}
+
+	for _, argument := range f.arguments {
+		if findArg(definition.Args, argument.name) == nil {
+			v.fail(argument.location, "unknown argument %q on field %q", argument.name, f.name)
+		}
+		v.validateValue(argument.value)
+	}
+	for _, arg := range definition.Args {
+		if arg.typ.nonNull && arg.Default == nil && findArgument(f.arguments, arg.Name) == nil {
+			v.fail(f.location, "field %q argument %q of type %q is required", f.name, arg.Name, arg.Type)
+		}
+	}
+
+	name := definition.typ.namedType()
+	if fieldObject, ok := v.schema.objects[name]; ok {
+		if len(f.selections) == 0 {
+			v.fail(f.location, "field %q of type %q must have a selection of subfields", f.name, definition.Type)
+			return
+		}
+		v.validateSelectionSet(fieldObject, f.selections, spreading)
+	} else if len(f.selections) > 0 {
+		v.fail(f.location, "field %q must not have a selection since type %q has no subfields", f.name, definition.Type)
+	}
+}
+
+// validateDirectives checks that the directives are the @skip and @include ones, with their condition.
+func (v *validator) validateDirectives(directives []directive) {
+	for _, directive := range directives {
+		if directive.name != "skip" && directive.name != "include" {
+			v.fail(directive.location, "unknown directive @%s", directive.name)
+			continue
+		}
+
+		condition := findArgument(directive.arguments, "if")
+		if condition == nil || len(directive.arguments) != 1 {
+			v.fail(directive.location, "directive @%s takes a single argument \"if\" of type \"Boolean!\"", directive.name)
+			continue
+		}
+		if condition.value.kind != "boolean" && condition.value.kind != "variable" {
+			v.fail(condition.location, "argument \"if\" of directive @%s must be a Boolean", directive.name)
+		}
+		v.validateValue(condition.value)
+	}
+}
+
+// validateValue checks that the variables used by a value are defined by the operation.
+func (v *validator) validateValue(value value) {
+	switch value.kind {
+	case "variable":
+		for _, variable := range v.operation.variables {
+			if variable.name == value.raw {
+				return
+			}
+		}
+		v.fail(value.location, "variable $%s is not defined", value.raw)
+	case "list":
+		for _, item := range value.list {
+			v.validateValue(item)
+		}
+	case "object":
+		for _, item := range value.object {
+			v.validateValue(item.value)
+		}
+	}
+}
+
+func findArg(args []*Arg, name string) *Arg {
+	for _, arg := range args {
+		if arg.Name == name {
+			return arg
+		}
+	}
+	return nil
+}
+
+func findArgument(arguments []argument, name string) *argument {
+	for i := range arguments {
+		if arguments[i].name == name {
+			return &arguments[i]
+		}
+	}
+	return nil
+}
+
+// coerceVariables returns the values of the variables of the operation sent with the request, or their
+// default value, coerced to their type. The variables without a value are missing.
+func (schema *Schema) coerceVariables(operation *operation, input map[string]any) (map[string]any, []*Error) {
+	variables := map[string]any{}
+	var errs []*Error
+
+	for _, definition := range operation.variables {
+		raw, ok := input[definition.name]
+		if !ok {
+			switch {
+			case definition.defaultValue.kind != "":
+				value, _, err := schema.coerceLiteral(definition.typ, definition.defaultValue, nil)
+				if err != nil {
+					errs = append(errs, errorAt(definition.defaultValue.location, "variable $%s: %v", definition.name, err))
+				}
+				variables[definition.name] = value
+			case definition.typ.nonNull:
+				errs = append(errs, errorAt(operation.location, "variable $%s of type %s is required", definition.name, definition.typ))
+			}
+			continue
+		}
+
+		value, err := schema.coerceInput(definition.typ, raw)
+		if err != nil {
+			errs = append(errs, errorAt(operation.location, "variable $%s: %v", definition.name, err))
+			continue
+		}
+		variables[definition.name] = value
+	}
+
+	return variables, errs
+}
+
+// coerceInput coerces the value of a variable, as decoded from JSON, to its type.
+func (schema *Schema) coerceInput(typ *typeRef, raw any) (any, error) {
+	if raw == nil {
+		if typ.nonNull {
+			return nil, fmt.Errorf("expected a non-null %s", typ)
+		}
+		return nil, nil
+	}
+
+	if typ.elem != nil {
+		items, ok := raw.([]any)
+		if !ok {
+			// a single value is coerced to a list holding it
+			items = []any{raw}
+		}
+		list := make([]any, len(items))
+		for i, item := range items {
+			value, err := schema.coerceInput(typ.elem, item)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = value
+		}
+		return list, nil
+	}
+
+	invalid := fmt.Errorf("expected a value of type %s, got %v", typ, raw)
+	switch typ.name {
+	case "Int":
+		if n, ok := toInt64(raw); ok && n >= math.MinInt32 && n <= math.MaxInt32 {
+			return n, nil
+		}
+	case "Int64":
+		if n, ok := toInt64(raw); ok {
+			return n, nil
+		}
+		if s, ok := raw.(string); ok {
+			if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+				return n, nil
+			}
+		}
+	case "Float":
+		switch raw := raw.(type) {
+		case float64:
+			return raw, nil
+		case json.Number:
+			if f, err := raw.Float64(); err == nil {
+				return f, nil
+			}
+		}
+	case "String":
+		if s, ok := raw.(string); ok {
+			return s, nil
+		}
+	case "Boolean":
+		if b, ok := raw.(bool); ok {
+			return b, nil
+		}
+	case "ID":
+		if s, ok := raw.(string); ok {
+			return s, nil
+		}
+		if n, ok := toInt64(raw); ok {
+			return strconv.FormatInt(n, 10), nil
+		}
+	case "Time":
+		if s, ok := raw.(string); ok {
+			if t, err := time.Parse(time.RFC3339, s); err == nil {
+				return t, nil
+			}
+		}
+	default:
+		if enum, ok := schema.enums[typ.name]; ok {
+			if s, ok := raw.(string); ok && contains(enum.Values, s) {
+				return s, nil
+			}
+		}
+	}
+	return nil, invalid
+}
+
+// coerceLiteral coerces a literal of the request to its type, reporting whether it has a value: a
+// variable that wasn't sent has none.
+func (schema *Schema) coerceLiteral(typ *typeRef, literal value, variables map[string]any) (any, bool, error) {
+	if literal.kind == "variable" {
+		value, ok := variables[literal.raw]
+		if !ok {
+			return nil, false, nil
+		}
+		if value == nil && typ.nonNull {
+			return nil, true, fmt.Errorf("expected a non-null %s, got the null variable $%s", typ, literal.raw)
+		}
+		return value, true, nil
+	}
+
+	if literal.kind == "null" {
+		if typ.nonNull {
+			return nil, true, fmt.Errorf("expected a non-null %s, got null", typ)
+		}
+		return nil, true, nil
+	}
+
+	if typ.elem != nil {
+		items := literal.list
+		if literal.kind != "list" {
+			// a single value is coerced to a list holding it
+			items = []value{literal}
+		}
+		list := make([]any, len(items))
+		for i, item := range items {
+			value, _, err := schema.coerceLiteral(typ.elem, item, variables)
+			if err != nil {
+				return nil, true, err
+			}
+			list[i] = value
+		}
+		return list, true, nil
+	}
+
+	invalid := fmt.Errorf("expected a value of type %s, got %s", typ, literal.raw)
+	switch typ.name {
+	case "Int":
+		if literal.kind == "int" {
+			if n, err := strconv.ParseInt(literal.raw, 10, 32); err == nil {
+				return n, true, nil
+			}
+		}
+	case "Int64":
+		if literal.kind == "int" || literal.kind == "string" {
+			if n, err := strconv.ParseInt(literal.raw, 10, 64); err == nil {
+				return n, true, nil
+			}
+		}
+	case "Float":
+		if literal.kind == "int" || literal.kind == "float" {
+			if f, err := strconv.ParseFloat(literal.raw, 64); err == nil {
+				return f, true, nil
+			}
+		}
+	case "String":
+		if literal.kind == "string" {
+			return literal.raw, true, nil
+		}
+	case "Boolean":
+		if literal.kind == "boolean" {
+			return literal.raw == "true", true, nil
+		}
+	case "ID":
+		if literal.kind == "string" || literal.kind == "int" {
+			return literal.raw, true, nil
+		}
+	case "Time":
+		if literal.kind == "string" {
+			if t, err := time.Parse(time.RFC3339, literal.raw); err == nil {
+				return t, true, nil
+			}
+		}
+	default:
+		if enum, ok := schema.enums[typ.name]; ok && literal.kind == "enum" && contains(enum.Values, literal.raw) {
+			return literal.raw, true, nil
+		}
+	}
+	return nil, true, invalid
+}
+
+func toInt64(raw any) (int64, bool) {
+	switch raw := raw.(type) {
+	case int:
+		return int64(raw), true
+	case int32:
+		return int64(raw), true
+	case int64:
+		return raw, true
+	case float64:
+		if raw == math.Trunc(raw) && raw >= math.MinInt64 && raw <= math.MaxInt64 {
+			return int64(raw), true
+		}
+	case json.Number:
+		if n, err := raw.Int64(); err == nil {
+			return n, true
+		}
+	}
+	return 0, false
+}
+
+// The executor type resolves the fields of an operation, collecting the errors of the fields.
+type executor struct {
+	schema    *Schema
+	doc       *document
+	variables map[string]any
+	errors    []*Error
+}
+
+// The orderedObject type is an object of the response, whose fields are written in the order they were
+// selected.
+type orderedObject []objectField
+
+type objectField struct {
+	key   string
+	value any
+}
+
+func (object orderedObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, field := range object {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(field.key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(field.value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// The fieldGroup type is the fields selected under the same response key, which are merged.
+type fieldGroup struct {
+	key    string
+	fields []*field
+}
+
+// executeSelectionSet resolves the fields selected on an object, reporting false when a non-null field
+// failed, making the object null.
+func (e *executor) executeSelectionSet(ctx context.Context, object *Object, source any, selections []selection, path []any) (orderedObject, bool) {
+	groups := e.collectFields(selections, nil, map[string]bool{})
+
+	result := make(orderedObject, 0, len(groups))
+	for _, group := range groups {
+		fieldPath := append(append([]any(nil), path...), group.key)
+
+		name := group.fields[0].name
+		if name == "__typename" {
+			result = append(result, objectField{key: group.key, value: object.Name})
+			continue
+		}
+
+		value, ok := e.executeField(ctx, object.fields[name], source, group.fields, fieldPath)
+		if !ok {
+			return nil, false
+		}
+		result = append(result, objectField{key: group.key, value: value})
+	}
+	return result, true
+}
+
+// collectFields groups the fields of a selection set by response key, following the fragments and
+// leaving out the selections skipped by their directives.
+func (e *executor) collectFields(selections []selection, groups []fieldGroup, spread map[string]bool) []fieldGroup {
+	for _, selection := range selections {
+		switch {
+		case selection.field != nil:
+			if !e.included(selection.field.directives) {
+				continue
+			}
+
+			key := selection.field.responseKey()
+			merged := false
+			for i := range groups {
+				if groups[i].key == key {
+					groups[i].fields = append(groups[i].fields, selection.field)
+					merged = true
+				}
+			}
+			if !merged {
+				groups = append(groups, fieldGroup{key: key, fields: []*field{selection.field}})
+			}
+
+		case selection.spread != nil:
+			name := selection.spread.name
+			if spread[name] || !e.included(selection.spread.directives) {
+				continue
+			}
+			spread[name] = true
+
+			fragment := e.doc.fragments[name]
+			if e.included(fragment.directives) {
+				groups = e.collectFields(fragment.selections, groups, spread)
+			}
+
+		case selection.inline != nil:
+			if e.included(selection.inline.directives) {
+				groups = e.collectFields(selection.inline.selections, groups, spread)
+			}
+		}
+	}
+	return groups
+}
+
+// included reports whether a selection is kept given its @skip and @include directives.
+func (e *executor) included(directives []directive) bool {
+	for _, directive := range directives {
+		condition, _, _ := e.schema.coerceLiteral(&typeRef{name: "Boolean"}, findArgument(directive.arguments, "if").value, e.variables)
+		value, _ := condition.(bool)
+		if (directive.name == "skip" && value) || (directive.name == "include" && !value) {
+			return false
+		}
+	}
+	return true
+}
+
+// executeField resolves a field and completes its value, reporting false when it is null but can't be.
+func (e *executor) executeField(ctx context.Context, definition *Field, source any, fields []*field, path []any) (any, bool) {
+	args, err := e.coerceArguments(definition, fields[0].arguments)
+	if err == nil && definition.Role != "" {
+		err = e.schema.authorize(ctx, definition.Role)
+	}
+
+	var value any
+	if err == nil {
+		value, err = definition.Resolve(ctx, source, args)
+	}
+	if err != nil {
+		e.errors = append(e.errors, fieldError(err, fields[0].location, path))
+		return nil, !definition.typ.nonNull
+	}
+
+	return e.completeValue(ctx, definition.typ, fields, value, path)
+}
+
+// coerceArguments returns the arguments of a field coerced to their type, with the default value of
+// those omitted.
+func (e *executor) coerceArguments(definition *Field, arguments []argument) (map[string]any, error) {
+	args := make(map[string]any, len(definition.Args))
+	for _, arg := range definition.Args {
+		if argument := findArgument(arguments, arg.Name); argument != nil {
+			value, ok, err := e.schema.coerceLiteral(arg.typ, argument.value, e.variables)
+			if err != nil {
+				return nil, fmt.Errorf("argument %q: %w", arg.Name, err)
+			}
+			if ok {
+				args[arg.Name] = value
+				continue
+			}
+		}
+
+		if arg.Default != nil {
+			args[arg.Name] = arg.Default
+		} else if arg.typ.nonNull {
+			return nil, fmt.Errorf("argument %q of type %q is required", arg.Name, arg.Type)
+		}
+	}
+	return args, nil
+}
+
+// completeValue turns the value returned by a resolver into the value of the response, reporting false
+// when it is null but can't be, which makes the nearest nullable field holding it null.
+func (e *executor) completeValue(ctx context.Context, typ *typeRef, fields []*field, value any, path []any) (any, bool) {
+	if !typ.nonNull {
+		completed, ok := e.completeNullable(ctx, typ, fields, value, path)
+		if !ok {
+			return nil, true
+		}
+		return completed, true
+	}
+
+	nullable := *typ
+	nullable.nonNull = false
+	completed, ok := e.completeNullable(ctx, &nullable, fields, value, path)
+	if !ok {
+		return nil, false
+	}
+	if completed == nil {
+		e.fail(fields, path, "cannot return null for non-nullable field of type %s", typ)
+		return nil, false
+	}
+	return completed, true
+}
+
+func (e *executor) completeNullable(ctx context.Context, typ *typeRef, fields []*field, value any, path []any) (any, bool) {
+	if isNull(value) {
+		return nil, true
+	}
+
+	if typ.elem != nil {
+		list := reflect.ValueOf(value)
+		if list.Kind() != reflect.Slice && list.Kind() != reflect.Array {
+			e.fail(fields, path, "expected a list of type %s, got %T", typ, value)
+			return nil, false
+		}
+
+		items := make([]any, list.Len())
+		for i := range items {
+			item, ok := e.completeValue(ctx, typ.elem, fields, list.Index(i).Interface(), append(append([]any(nil), path...), i))
+			if !ok {
+				return nil, false
+			}
+			items[i] = item
+		}
+		return items, true
+	}
+
+	if object, ok := e.schema.objects[typ.name]; ok {
+		var selections []selection
+		for _, field := range fields {
+			selections = append(selections, field.selections...)
+		}
+
+		result, ok := e.executeSelectionSet(ctx, object, value, selections, path)
+		if !ok {
+			return nil, false
+		}
+		return result, true
+	}
+
+	serialized, err := e.schema.serialize(typ.name, value)
+	if err != nil {
+		e.fail(fields, path, "%v", err)
+		return nil, false
+	}
+	return serialized, true
+}
+
+func (e *executor) fail(fields []*field, path []any, format string, a ...any) {
+	err := errorAt(fields[0].location, format, a...)
+	err.Path = append([]any(nil), path...)
+	e.errors = append(e.errors, err)
+}
+
+// serialize returns the value of a scalar or an enum in the response.
+func (schema *Schema) serialize(name string, value any) (any, error) {
+	invalid := fmt.Errorf("%s cannot represent the value %v", name, value)
+	switch name {
+	case "Int":
+		if n, ok := toInt64(value); ok && n >= math.MinInt32 && n <= math.MaxInt32 {
+			return n, nil
+		}
+	case "Int64":
+		if _, ok := value.(float64); !ok {
+			if n, ok := toInt64(value); ok {
+				return n, nil
+			}
+		}
+	case "Float":
+		if f, ok := value.(float64); ok {
+			return f, nil
+		}
+		if n, ok := toInt64(value); ok {
+			return float64(n), nil
+		}
+	case "String":
+		if s, ok := value.(string); ok {
+			return s, nil
+		}
+	case "Boolean":
+		if b, ok := value.(bool); ok {
+			return b, nil
+		}
+	case "ID":
+		if s, ok := value.(string); ok {
+			return s, nil
+		}
+		if _, ok := value.(float64); !ok {
+			if n, ok := toInt64(value); ok {
+				return strconv.FormatInt(n, 10), nil
+			}
+		}
+	case "Time":
+		if t, ok := value.(time.Time); ok {
+			return t.UTC().Format(timeFormat), nil
+		}
+	default:
+		if enum, ok := schema.enums[name]; ok {
+			if s, ok := value.(string); ok && contains(enum.Values, s) {
+				return s, nil
+			}
+		}
+	}
+	return nil, invalid
+}
+
+// isNull reports whether a value returned by a resolver is null: nil, or a nil pointer, map or slice.
+func isNull(value any) bool {
+	if value == nil {
+		return true
+	}
+
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Interface:
+		return v.IsNil()
+	}
+	return false
+}
@@ -0,0 +1,350 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testBook struct {
+	id        int64
+	title     string
+	author    *testAuthor
+	published time.Time
+}
+
+type testAuthor struct {
+	name string
+}
+
+// newTestSchema creates a schema of books, one of which fails to resolve its author, to test the engine
+// without the service.
+func newTestSchema(t *testing.T) *Schema {
+	books := []testBook{
+		{id: 1, title: "Dune", author: &testAuthor{name: "Frank Herbert"}, published: time.Date(1965, time.August, 1, 0, 0, 0, 0, time.UTC)},
+		{id: 9007199254740993, title: "Anonymous"},
+	}
+
+	query := &Object{
+		Name: "Query",
+		Fields: []*Field{
+			{
+				Name: "books",
+				Type: "[Book!]!",
+				Args: []*Arg{{Name: "first", Type: "Int", Default: int64(10)}},
+				ListSize: func(args map[string]any) (int64, error) {
+					first := args["first"].(int64)
+					if first > 100 {
+						return 0, NewError("INVALID_ARGUMENT", "", errors.New("first must be at most 100"))
+					}
+					return first, nil
+				},
+				Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+					first := args["first"].(int64)
+					if first < int64(len(books)) {
+						return books[:first], nil
+					}
+					return books, nil
+				},
+			},
+			{
+				Name: "book",
+				Type: "Book",
+				Args: []*Arg{{Name: "id", Type: "ID!"}},
+				Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+					for _, book := range books {
+						if args["id"] == strconv.FormatInt(book.id, 10) {
+							return book, nil
+						}
+					}
+					return nil, nil
+				},
+			},
+			{
+				Name: "secret",
+				Type: "String",
+				Role: "ADMIN",
+				Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+					return "42", nil
+				},
+			},
+		},
+	}
+	book := &Object{
+		Name: "Book",
+		Fields: []*Field{
+			{Name: "id", Type: "ID!", Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+				return source.(testBook).id, nil
+			}},
+			{Name: "title", Type: "String!", Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+				return source.(testBook).title, nil
+			}},
+			{Name: "published", Type: "Time", Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+				if source.(testBook).published.IsZero() {
+					return nil, nil
+				}
+				return source.(testBook).published, nil
+			}},
+			{Name: "author", Type: "Author!", Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+				if source.(testBook).author == nil {
+					return nil, NewError("NOT_FOUND", "", errors.New("the author is unknown"))
+				}
+				return source.(testBook).author, nil
+			}},
+		},
+	}
+	author := &Object{
+		Name: "Author",
+		Fields: []*Field{
+			{Name: "name", Type: "String!", Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+				return source.(*testAuthor).name, nil
+			}},
+		},
+	}
+	role := &Enum{Name: "Role", Values: []string{"USER", "ADMIN"}}
+
+	authorize := func(ctx context.Context, role string) error {
+		if ctx.Value(testRoleKey{}) != role {
+			return NewError("PERMISSION_DENIED", "", errors.New("the role is not allowed"))
+		}
+		return nil
+	}
+
+	schema, err := NewSchema(query, []*Object{book, author}, []*Enum{role}, authorize)
+	require.NoError(t, err)
+	return schema
+}
+
+type testRoleKey struct{}
+
+func TestExecute(t *testing.T) {
+	schema := newTestSchema(t)
+
+	testCases := []struct {
+		name      string
+		ctx       context.Context
+		request   Request
+		data      string
+		errors    []string
+		errorPath []any
+	}{
+		{
+			name:    "Shorthand",
+			request: Request{Query: `{ books(first: 1) { id title published } }`},
+			data:    `{"books":[{"id":"1","title":"Dune","published":"1965-08-01T00:00:00.000Z"}]}`,
+		},
+		{
+			name: "AliasesAndFragments",
+			request: Request{Query: `
+				query Books {
+					first: book(id: 1) { ...title author { name } }
+					all: books { id }
+				}
+				fragment title on Book { title }
+			`},
+			data: `{"first":{"title":"Dune","author":{"name":"Frank Herbert"}},"all":[{"id":"1"},{"id":"9007199254740993"}]}`,
+		},
+		{
+			name: "Variables",
+			request: Request{
+				Query:     `query ($id: ID!, $withTitle: Boolean = false) { book(id: $id) { id title @include(if: $withTitle) } }`,
+				Variables: map[string]any{"id": json.Number("9007199254740993")},
+			},
+			data: `{"book":{"id":"9007199254740993"}}`,
+		},
+		{
+			name: "OperationName",
+			request: Request{
+				Query:         `query A { books { id } } query B { book(id: "1") { title } }`,
+				OperationName: "B",
+			},
+			data: `{"book":{"title":"Dune"}}`,
+		},
+		{
+			name:      "NonNullPropagation",
+			request:   Request{Query: `{ book(id: "9007199254740993") { title author { name } } }`},
+			data:      `{"book":null}`,
+			errors:    []string{"the author is unknown"},
+			errorPath: []any{"book", "author"},
+		},
+		{
+			name:      "Unauthorized",
+			request:   Request{Query: `{ secret }`},
+			data:      `{"secret":null}`,
+			errors:    []string{"the role is not allowed"},
+			errorPath: []any{"secret"},
+		},
+		{
+			name:    "Authorized",
+			ctx:     context.WithValue(context.Background(), testRoleKey{}, "ADMIN"),
+			request: Request{Query: `{ secret }`},
+			data:    `{"secret":"42"}`,
+		},
+		{
+			name:    "SyntaxError",
+			request: Request{Query: `{ books { id }`},
+			errors:  []string{"syntax error: unexpected end of document"},
+		},
+		{
+			name:    "UnknownField",
+			request: Request{Query: `{ books { isbn } }`},
+			errors:  []string{`cannot query field "isbn" on type "Book"`},
+		},
+		{
+			name:    "MissingSelection",
+			request: Request{Query: `{ books }`},
+			errors:  []string{`field "books" of type "[Book!]!" must have a selection of subfields`},
+		},
+		{
+			name:    "MissingArgument",
+			request: Request{Query: `{ book { id } }`},
+			errors:  []string{`field "book" argument "id" of type "ID!" is required`},
+		},
+		{
+			name:    "Mutation",
+			request: Request{Query: `mutation { books { id } }`},
+			errors:  []string{"only queries are supported, not mutations"},
+		},
+		{
+			name: "MissingVariable",
+			request: Request{
+				Query: `query ($id: ID!) { book(id: $id) { id } }`,
+			},
+			errors: []string{"variable $id of type ID! is required"},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := tc.ctx
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			res := schema.Execute(ctx, tc.request)
+
+			if tc.data == "" {
+				require.Nil(t, res.Data)
+			} else {
+				require.JSONEq(t, tc.data, string(res.Data))
+			}
+
+			messages := make([]string, len(res.Errors))
+			for i, err := range res.Errors {
+				messages[i] = err.Message
+			}
+			require.Equal(t, len(tc.errors), len(messages), messages)
+			for i, message := range tc.errors {
+				require.Equal(t, message, messages[i])
+			}
+			if tc.errorPath != nil {
+				require.Equal(t, tc.errorPath, res.Errors[0].Path)
+			}
+		})
+	}
+}
+
+func TestResponseKeepsFieldOrder(t *testing.T) {
+	schema := newTestSchema(t)
+
+	res := schema.Execute(context.Background(), Request{Query: `{ book(id: "1") { title id } }`})
+	require.Empty(t, res.Errors)
+	require.Equal(t, `{"book":{"title":"Dune","id":"1"}}`, string(res.Data))
+}
+
+func TestExecuteLimits(t *testing.T) {
+	schema := newTestSchema(t)
+	schema.SetLimits(Limits{MaxAliases: 2, MaxComplexity: 50})
+
+	testCases := []struct {
+		name    string
+		query   string
+		message string
+	}{
+		{
+			name:  "WithinLimits",
+			query: `{ books(first: 1) { id title author { name } } }`,
+		},
+		{
+			name:    "TooManyAliases",
+			query:   `{ a: book(id: "1") { id } b: book(id: "1") { id } c: book(id: "1") { id } }`,
+			message: "the query has more than the 2 aliases allowed",
+		},
+		{
+			name:    "AliasesInFragments",
+			query:   `{ book(id: "1") { ...ids } } fragment ids on Book { a: id b: id c: id }`,
+			message: "the query has more than the 2 aliases allowed",
+		},
+		{
+			name:    "TooComplex",
+			query:   `{ books(first: 20) { id title author { name } } }`,
+			message: "the query is more complex than the 50 fields allowed",
+		},
+		{
+			name:    "FirstOutOfBounds",
+			query:   `{ books(first: 1000) { id } }`,
+			message: "first must be at most 100",
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			res := schema.Execute(context.Background(), Request{Query: tc.query})
+			if tc.message == "" {
+				require.Empty(t, res.Errors)
+				require.NotNil(t, res.Data)
+				return
+			}
+
+			require.Nil(t, res.Data)
+			require.Len(t, res.Errors, 1)
+			require.Equal(t, tc.message, res.Errors[0].Message)
+		})
+	}
+}
+
+func TestExecuteDepthLimit(t *testing.T) {
+	schema := newTestSchema(t)
+	schema.SetLimits(Limits{MaxDepth: 2})
+
+	res := schema.Execute(context.Background(), Request{Query: `{ book(id: "1") { author { name } } }`})
+	require.Nil(t, res.Data)
+	require.Len(t, res.Errors, 1)
+	require.Equal(t, "the query is nested deeper than the 2 levels allowed", res.Errors[0].Message)
+}
+
+func TestValidateNestedFragmentsOnce(t *testing.T) {
+	schema := newTestSchema(t)
+
+	// every fragment spreads the next one twice, which would validate the last one 2^40 times
+	var query strings.Builder
+	query.WriteString(`{ book(id: "1") { ...f0 } }`)
+	for i := 0; i < 40; i++ {
+		fmt.Fprintf(&query, " fragment f%d on Book { id ...f%d ...f%d }", i, i+1, i+1)
+	}
+	query.WriteString(" fragment f40 on Book { title }")
+
+	done := make(chan Response)
+	go func() {
+		done <- schema.Execute(context.Background(), Request{Query: query.String()})
+	}()
+
+	select {
+	case res := <-done:
+		require.Empty(t, res.Errors)
+		require.JSONEq(t, `{"book":{"id":"1","title":"Dune"}}`, string(res.Data))
+	case <-time.After(5 * time.Second):
+		t.Fatal("the fragments were not validated in time")
+	}
+}
@@ -0,0 +1,115 @@
+package graph
+
+import "math"
+
+// The Limits type bounds the cost of the queries, which are rejected before any of their fields is
+// resolved when they exceed one of them. A zero limit isn't enforced.
+// @property {int} MaxDepth - the deepest nesting of the fields, the fields of the query type being at
+// depth 1.
+// @property {int} MaxAliases - the number of aliased fields, counted every time they are spread.
+// @property {int64} MaxComplexity - the number of fields a query resolves at most, the selection of a
+// field with a list size counting as many times as the field holds items.
+type Limits struct {
+	MaxDepth      int
+	MaxAliases    int
+	MaxComplexity int64
+}
+
+// DefaultLimits are the limits of the schemas, which let through the queries of the graph of a user
+// with a page of entries and transfers for each of their accounts.
+var DefaultLimits = Limits{
+	MaxDepth:      10,
+	MaxAliases:    20,
+	MaxComplexity: 20_000,
+}
+
+// The `SetLimits` function sets the limits the queries must be within to be executed.
+func (schema *Schema) SetLimits(limits Limits) {
+	schema.limits = limits
+}
+
+// The limiter type measures an operation against the limits of the schema, stopping at the first limit
+// it exceeds so that measuring a query is never costlier than executing one within the limits.
+type limiter struct {
+	e          *executor
+	limits     Limits
+	aliases    int
+	complexity int64
+}
+
+// checkLimits returns the error of an operation exceeding the limits of the schema, or of a field whose
+// arguments are out of the bounds of its list size. The selections are measured as they are executed,
+// with their fragments and without those skipped by their directives.
+func (e *executor) checkLimits(operation *operation) *Error {
+	l := &limiter{e: e, limits: e.schema.limits}
+	return l.measure(e.schema.query, operation.selections, 1, 1)
+}
+
+// measure adds the fields selected on an object at `depth` to the measures of the operation, each one
+// being resolved `multiplier` times.
+func (l *limiter) measure(object *Object, selections []selection, depth int, multiplier int64) *Error {
+	for _, group := range l.e.collectFields(selections, nil, map[string]bool{}) {
+		first := group.fields[0]
+		if l.limits.MaxDepth > 0 && depth > l.limits.MaxDepth {
+			return errorAt(first.location, "the query is nested deeper than the %d levels allowed", l.limits.MaxDepth)
+		}
+		for _, f := range group.fields {
+			if f.alias != "" {
+				l.aliases++
+			}
+		}
+		if l.limits.MaxAliases > 0 && l.aliases > l.limits.MaxAliases {
+			return errorAt(first.location, "the query has more than the %d aliases allowed", l.limits.MaxAliases)
+		}
+		if first.name == "__typename" {
+			continue
+		}
+
+		l.complexity = saturatingAdd(l.complexity, multiplier)
+		if l.limits.MaxComplexity > 0 && l.complexity > l.limits.MaxComplexity {
+			return errorAt(first.location, "the query is more complex than the %d fields allowed", l.limits.MaxComplexity)
+		}
+
+		definition := object.fields[first.name]
+		fieldObject, ok := l.e.schema.objects[definition.typ.namedType()]
+		if !ok {
+			continue
+		}
+
+		fieldMultiplier := multiplier
+		if definition.ListSize != nil {
+			args, err := l.e.coerceArguments(definition, first.arguments)
+			var size int64
+			if err == nil {
+				size, err = definition.ListSize(args)
+			}
+			if err != nil {
+				return fieldError(err, first.location, nil)
+			}
+			fieldMultiplier = saturatingMul(multiplier, size)
+		}
+
+		var fieldSelections []selection
+		for _, f := range group.fields {
+			fieldSelections = append(fieldSelections, f.selections...)
+		}
+		if err := l.measure(fieldObject, fieldSelections, depth+1, fieldMultiplier); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func saturatingAdd(a int64, b int64) int64 {
+	if a > math.MaxInt64-b {
+		return math.MaxInt64
+	}
+	return a + b
+}
+
+func saturatingMul(a int64, b int64) int64 {
+	if b > 0 && a > math.MaxInt64/b {
+		return math.MaxInt64
+	}
+	return a * b
+}
@@ -0,0 +1,764 @@
+package graph
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// The document type is a parsed GraphQL request: its operations and the fragments they spread.
+type document struct {
+	operations []*operation
+	fragments  map[string]*fragment
+}
+
+type operation struct {
+	kind       string
+	name       string
+	variables  []variableDefinition
+	selections []selection
+	location   Location
+}
+
+type variableDefinition struct {
+	name         string
+	typ          *typeRef
+	defaultValue value
+}
+
+type fragment struct {
+	name          string
+	typeCondition string
+	directives    []directive
+	selections    []selection
+	location      Location
+}
+
+// The selection type is a field, a fragment spread or an inline fragment, told apart by which of
+// `field`, `spread` and `inline` is set.
+type selection struct {
+	field  *field
+	spread *fragmentSpread
+	inline *inlineFragment
+}
+
+type field struct {
+	alias      string
+	name       string
+	arguments  []argument
+	directives []directive
+	selections []selection
+	location   Location
+}
+
+// responseKey is the name of the field in the response: its alias, or else its name.
+func (f *field) responseKey() string {
+	if f.alias != "" {
+		return f.alias
+	}
+	return f.name
+}
+
+type fragmentSpread struct {
+	name       string
+	directives []directive
+	location   Location
+}
+
+type inlineFragment struct {
+	typeCondition string
+	directives    []directive
+	selections    []selection
+	location      Location
+}
+
+type argument struct {
+	name     string
+	value    value
+	location Location
+}
+
+type directive struct {
+	name      string
+	arguments []argument
+	location  Location
+}
+
+// The value type is a literal of the request, or a variable.
+// @property {string} kind - one of int, float, string, boolean, null, enum, list, object and variable,
+// whose name is then `raw`.
+type value struct {
+	kind     string
+	raw      string
+	list     []value
+	object   []argument
+	location Location
+}
+
+// The parser type turns the text of a request into a document, failing on the first syntax error.
+type parser struct {
+	lexer *lexer
+	token token
+}
+
+func parse(source string) (*document, error) {
+	p := &parser{lexer: &lexer{source: source, line: 1, column: 1}}
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+
+	doc := &document{fragments: map[string]*fragment{}}
+	for p.token.kind != tokenEOF {
+		if p.peekName("fragment") {
+			fragment, err := p.parseFragment()
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := doc.fragments[fragment.name]; ok {
+				return nil, errorAt(fragment.location, "there can be only one fragment named %q", fragment.name)
+			}
+			doc.fragments[fragment.name] = fragment
+			continue
+		}
+
+		operation, err := p.parseOperation()
+		if err != nil {
+			return nil, err
+		}
+		doc.operations = append(doc.operations, operation)
+	}
+
+	if len(doc.operations) == 0 {
+		return nil, errorAt(Location{Line: 1, Column: 1}, "the document has no operation")
+	}
+	return doc, nil
+}
+
+func (p *parser) next() error {
+	token, err := p.lexer.next()
+	if err != nil {
+		return err
+	}
+	p.token = token
+	return nil
+}
+
+func (p *parser) peek(kind tokenKind) bool {
+	return p.token.kind == kind
+}
+
+func (p *parser) peekName(name string) bool {
+	return p.token.kind == tokenName && p.token.value == name
+}
+
+// skip moves past the current token when it is of the kind, reporting whether it was.
+func (p *parser) skip(kind tokenKind) (bool, error) {
+	if p.token.kind != kind {
+		return false, nil
+	}
+	return true, p.next()
+}
+
+func (p *parser) expect(kind tokenKind) (token, error) {
+	token := p.token
+	if token.kind != kind {
+		return token, p.unexpected()
+	}
+	return token, p.next()
+}
+
+func (p *parser) expectName(name string) error {
+	if !p.peekName(name) {
+		return p.unexpected()
+	}
+	return p.next()
+}
+
+func (p *parser) unexpected() error {
+	if p.token.kind == tokenEOF {
+		return errorAt(p.token.location, "syntax error: unexpected end of document")
+	}
+	return errorAt(p.token.location, "syntax error: unexpected %q", p.token.value)
+}
+
+func (p *parser) parseOperation() (*operation, error) {
+	operation := &operation{kind: "query", location: p.token.location}
+
+	// a selection set alone is a query without a name
+	if p.peek(tokenBraceL) {
+		selections, err := p.parseSelectionSet()
+		if err != nil {
+			return nil, err
+		}
+		operation.selections = selections
+		return operation, nil
+	}
+
+	if !p.peekName("query") && !p.peekName("mutation") && !p.peekName("subscription") {
+		return nil, p.unexpected()
+	}
+	operation.kind = p.token.value
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+
+	if p.peek(tokenName) {
+		operation.name = p.token.value
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+	}
+
+	if ok, err := p.skip(tokenParenL); err != nil {
+		return nil, err
+	} else if ok {
+		for !p.peek(tokenParenR) {
+			definition, err := p.parseVariableDefinition()
+			if err != nil {
+				return nil, err
+			}
+			operation.variables = append(operation.variables, definition)
+		}
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+	}
+
+	// the directives of operations are accepted but none applies to them
+	if _, err := p.parseDirectives(); err != nil {
+		return nil, err
+	}
+
+	selections, err := p.parseSelectionSet()
+	if err != nil {
+		return nil, err
+	}
+	operation.selections = selections
+	return operation, nil
+}
+
+func (p *parser) parseVariableDefinition() (variableDefinition, error) {
+	if _, err := p.expect(tokenDollar); err != nil {
+		return variableDefinition{}, err
+	}
+	name, err := p.expect(tokenName)
+	if err != nil {
+		return variableDefinition{}, err
+	}
+	if _, err := p.expect(tokenColon); err != nil {
+		return variableDefinition{}, err
+	}
+
+	typ, err := p.parseType()
+	if err != nil {
+		return variableDefinition{}, err
+	}
+
+	definition := variableDefinition{name: name.value, typ: typ}
+	if ok, err := p.skip(tokenEquals); err != nil {
+		return definition, err
+	} else if ok {
+		definition.defaultValue, err = p.parseValue(true)
+		if err != nil {
+			return definition, err
+		}
+	}
+	return definition, nil
+}
+
+func (p *parser) parseType() (*typeRef, error) {
+	var typ *typeRef
+	if ok, err := p.skip(tokenBracketL); err != nil {
+		return nil, err
+	} else if ok {
+		elem, err := p.parseType()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(tokenBracketR); err != nil {
+			return nil, err
+		}
+		typ = &typeRef{elem: elem}
+	} else {
+		name, err := p.expect(tokenName)
+		if err != nil {
+			return nil, err
+		}
+		typ = &typeRef{name: name.value}
+	}
+
+	nonNull, err := p.skip(tokenBang)
+	typ.nonNull = nonNull
+	return typ, err
+}
+
+func (p *parser) parseFragment() (*fragment, error) {
+	fragment := &fragment{location: p.token.location}
+	if err := p.expectName("fragment"); err != nil {
+		return nil, err
+	}
+
+	name, err := p.expect(tokenName)
+	if err != nil {
+		return nil, err
+	}
+	if name.value == "on" {
+		return nil, errorAt(name.location, "syntax error: unexpected %q", name.value)
+	}
+	fragment.name = name.value
+
+	if err := p.expectName("on"); err != nil {
+		return nil, err
+	}
+	typeCondition, err := p.expect(tokenName)
+	if err != nil {
+		return nil, err
+	}
+	fragment.typeCondition = typeCondition.value
+
+	if fragment.directives, err = p.parseDirectives(); err != nil {
+		return nil, err
+	}
+	if fragment.selections, err = p.parseSelectionSet(); err != nil {
+		return nil, err
+	}
+	return fragment, nil
+}
+
+func (p *parser) parseSelectionSet() ([]selection, error) {
+	if _, err := p.expect(tokenBraceL); err != nil {
+		return nil, err
+	}
+
+	var selections []selection
+	for !p.peek(tokenBraceR) {
+		selection, err := p.parseSelection()
+		if err != nil {
+			return nil, err
+		}
+		selections = append(selections, selection)
+	}
+	if len(selections) == 0 {
+		return nil, p.unexpected()
+	}
+	return selections, p.next()
+}
+
+func (p *parser) parseSelection() (selection, error) {
+	if !p.peek(tokenSpread) {
+		field, err := p.parseField()
+		return selection{field: field}, err
+	}
+
+	location := p.token.location
+	if err := p.next(); err != nil {
+		return selection{}, err
+	}
+
+	if p.peek(tokenName) && !p.peekName("on") {
+		spread := &fragmentSpread{name: p.token.value, location: location}
+		if err := p.next(); err != nil {
+			return selection{}, err
+		}
+		var err error
+		spread.directives, err = p.parseDirectives()
+		return selection{spread: spread}, err
+	}
+
+	inline := &inlineFragment{location: location}
+	if p.peekName("on") {
+		if err := p.next(); err != nil {
+			return selection{}, err
+		}
+		typeCondition, err := p.expect(tokenName)
+		if err != nil {
+			return selection{}, err
+		}
+		inline.typeCondition = typeCondition.value
+	}
+
+	var err error
+	if inline.directives, err = p.parseDirectives(); err != nil {
+		return selection{}, err
+	}
+	inline.selections, err = p.parseSelectionSet()
+	return selection{inline: inline}, err
+}
+
+func (p *parser) parseField() (*field, error) {
+	name, err := p.expect(tokenName)
+	if err != nil {
+		return nil, err
+	}
+	field := &field{name: name.value, location: name.location}
+
+	if ok, err := p.skip(tokenColon); err != nil {
+		return nil, err
+	} else if ok {
+		name, err := p.expect(tokenName)
+		if err != nil {
+			return nil, err
+		}
+		field.alias = field.name
+		field.name = name.value
+	}
+
+	if field.arguments, err = p.parseArguments(false); err != nil {
+		return nil, err
+	}
+	if field.directives, err = p.parseDirectives(); err != nil {
+		return nil, err
+	}
+	if p.peek(tokenBraceL) {
+		if field.selections, err = p.parseSelectionSet(); err != nil {
+			return nil, err
+		}
+	}
+	return field, nil
+}
+
+func (p *parser) parseArguments(constant bool) ([]argument, error) {
+	if ok, err := p.skip(tokenParenL); err != nil || !ok {
+		return nil, err
+	}
+
+	var arguments []argument
+	for !p.peek(tokenParenR) {
+		name, err := p.expect(tokenName)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(tokenColon); err != nil {
+			return nil, err
+		}
+		value, err := p.parseValue(constant)
+		if err != nil {
+			return nil, err
+		}
+		arguments = append(arguments, argument{name: name.value, value: value, location: name.location})
+	}
+	if len(arguments) == 0 {
+		return nil, p.unexpected()
+	}
+	return arguments, p.next()
+}
+
+func (p *parser) parseDirectives() ([]directive, error) {
+	var directives []directive
+	for p.peek(tokenAt) {
+		location := p.token.location
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		name, err := p.expect(tokenName)
+		if err != nil {
+			return nil, err
+		}
+		arguments, err := p.parseArguments(false)
+		if err != nil {
+			return nil, err
+		}
+		directives = append(directives, directive{name: name.value, arguments: arguments, location: location})
+	}
+	return directives, nil
+}
+
+// parseValue parses a literal, which can't hold variables when it is `constant`, e.g. the default value
+// of a variable.
+func (p *parser) parseValue(constant bool) (value, error) {
+	token := p.token
+	v := value{raw: token.value, location: token.location}
+
+	switch token.kind {
+	case tokenDollar:
+		if constant {
+			return v, p.unexpected()
+		}
+		if err := p.next(); err != nil {
+			return v, err
+		}
+		name, err := p.expect(tokenName)
+		v.kind, v.raw = "variable", name.value
+		return v, err
+	case tokenInt:
+		v.kind = "int"
+	case tokenFloat:
+		v.kind = "float"
+	case tokenString:
+		v.kind = "string"
+	case tokenName:
+		switch token.value {
+		case "true", "false":
+			v.kind = "boolean"
+		case "null":
+			v.kind = "null"
+		default:
+			v.kind = "enum"
+		}
+	case tokenBracketL:
+		v.kind = "list"
+		if err := p.next(); err != nil {
+			return v, err
+		}
+		for !p.peek(tokenBracketR) {
+			item, err := p.parseValue(constant)
+			if err != nil {
+				return v, err
+			}
+			v.list = append(v.list, item)
+		}
+	case tokenBraceL:
+		v.kind = "object"
+		if err := p.next(); err != nil {
+			return v, err
+		}
+		for !p.peek(tokenBraceR) {
+			name, err := p.expect(tokenName)
+			if err != nil {
+				return v, err
+			}
+			if _, err := p.expect(tokenColon); err != nil {
+				return v, err
+			}
+			item, err := p.parseValue(constant)
+			if err != nil {
+				return v, err
+			}
+			v.object = append(v.object, argument{name: name.value, value: item, location: name.location})
+		}
+	default:
+		return v, p.unexpected()
+	}
+
+	return v, p.next()
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenBang
+	tokenDollar
+	tokenParenL
+	tokenParenR
+	tokenSpread
+	tokenColon
+	tokenEquals
+	tokenAt
+	tokenBracketL
+	tokenBracketR
+	tokenBraceL
+	tokenBraceR
+	tokenPipe
+	tokenName
+	tokenInt
+	tokenFloat
+	tokenString
+)
+
+var punctuators = map[byte]tokenKind{
+	'!': tokenBang,
+	'$': tokenDollar,
+	'(': tokenParenL,
+	')': tokenParenR,
+	':': tokenColon,
+	'=': tokenEquals,
+	'@': tokenAt,
+	'[': tokenBracketL,
+	']': tokenBracketR,
+	'{': tokenBraceL,
+	'}': tokenBraceR,
+	'|': tokenPipe,
+}
+
+type token struct {
+	kind     tokenKind
+	value    string
+	location Location
+}
+
+// The lexer type splits the source of a request into tokens, skipping the white space, the commas and
+// the comments, which GraphQL ignores.
+type lexer struct {
+	source string
+	offset int
+	line   int
+	column int
+}
+
+func (l *lexer) next() (token, error) {
+	l.skipIgnored()
+
+	location := Location{Line: l.line, Column: l.column}
+	if l.offset >= len(l.source) {
+		return token{kind: tokenEOF, location: location}, nil
+	}
+
+	c := l.source[l.offset]
+	if kind, ok := punctuators[c]; ok {
+		l.advance(1)
+		return token{kind: kind, value: string(c), location: location}, nil
+	}
+
+	switch {
+	case c == '.':
+		if !strings.HasPrefix(l.source[l.offset:], "...") {
+			return token{}, errorAt(location, "syntax error: unexpected %q", ".")
+		}
+		l.advance(3)
+		return token{kind: tokenSpread, value: "...", location: location}, nil
+	case c == '_' || isLetter(c):
+		start := l.offset
+		for l.offset < len(l.source) && (l.source[l.offset] == '_' || isLetter(l.source[l.offset]) || isDigit(l.source[l.offset])) {
+			l.advance(1)
+		}
+		return token{kind: tokenName, value: l.source[start:l.offset], location: location}, nil
+	case c == '-' || isDigit(c):
+		return l.readNumber(location)
+	case c == '"':
+		value, err := l.readString(location)
+		return token{kind: tokenString, value: value, location: location}, err
+	}
+
+	r, _ := utf8.DecodeRuneInString(l.source[l.offset:])
+	return token{}, errorAt(location, "syntax error: unexpected character %q", r)
+}
+
+func (l *lexer) skipIgnored() {
+	for l.offset < len(l.source) {
+		switch c := l.source[l.offset]; c {
+		case ' ', '\t', ',', '\r':
+			l.advance(1)
+		case '\n':
+			l.offset++
+			l.line++
+			l.column = 1
+		case '#':
+			for l.offset < len(l.source) && l.source[l.offset] != '\n' {
+				l.advance(1)
+			}
+		default:
+			if strings.HasPrefix(l.source[l.offset:], "\uFEFF") {
+				l.offset += len("\uFEFF")
+				continue
+			}
+			return
+		}
+	}
+}
+
+func (l *lexer) advance(n int) {
+	l.offset += n
+	l.column += n
+}
+
+func (l *lexer) readNumber(location Location) (token, error) {
+	start := l.offset
+	kind := tokenInt
+
+	if l.source[l.offset] == '-' {
+		l.advance(1)
+	}
+	if !l.readDigits() {
+		return token{}, errorAt(location, "syntax error: invalid number %q", l.source[start:l.offset])
+	}
+	if l.offset < len(l.source) && l.source[l.offset] == '.' {
+		kind = tokenFloat
+		l.advance(1)
+		if !l.readDigits() {
+			return token{}, errorAt(location, "syntax error: invalid number %q", l.source[start:l.offset])
+		}
+	}
+	if l.offset < len(l.source) && (l.source[l.offset] == 'e' || l.source[l.offset] == 'E') {
+		kind = tokenFloat
+		l.advance(1)
+		if l.offset < len(l.source) && (l.source[l.offset] == '+' || l.source[l.offset] == '-') {
+			l.advance(1)
+		}
+		if !l.readDigits() {
+			return token{}, errorAt(location, "syntax error: invalid number %q", l.source[start:l.offset])
+		}
+	}
+
+	return token{kind: kind, value: l.source[start:l.offset], location: location}, nil
+}
+
+func (l *lexer) readDigits() bool {
+	start := l.offset
+	for l.offset < len(l.source) && isDigit(l.source[l.offset]) {
+		l.advance(1)
+	}
+	return l.offset > start
+}
+
+// readString reads a string between double quotes with its escape sequences. Block strings, between
+// triple quotes, aren't supported.
+func (l *lexer) readString(location Location) (string, error) {
+	if strings.HasPrefix(l.source[l.offset:], `"""`) {
+		return "", errorAt(location, "syntax error: block strings are not supported")
+	}
+	l.advance(1)
+
+	var value strings.Builder
+	for l.offset < len(l.source) {
+		c := l.source[l.offset]
+		switch {
+		case c == '"':
+			l.advance(1)
+			return value.String(), nil
+		case c == '\n':
+			return "", errorAt(location, "syntax error: unterminated string")
+		case c == '\\':
+			if l.offset+1 >= len(l.source) {
+				return "", errorAt(location, "syntax error: unterminated string")
+			}
+			escape := l.source[l.offset+1]
+			switch escape {
+			case '"', '\\', '/':
+				value.WriteByte(escape)
+			case 'b':
+				value.WriteByte('\b')
+			case 'f':
+				value.WriteByte('\f')
+			case 'n':
+				value.WriteByte('\n')
+			case 'r':
+				value.WriteByte('\r')
+			case 't':
+				value.WriteByte('\t')
+			case 'u':
+				if l.offset+6 > len(l.source) {
+					return "", errorAt(location, "syntax error: invalid unicode escape")
+				}
+				code, err := strconv.ParseUint(l.source[l.offset+2:l.offset+6], 16, 32)
+				if err != nil {
+					return "", errorAt(location, "syntax error: invalid unicode escape")
+				}
+				value.WriteRune(rune(code))
+				l.advance(4)
+			default:
+				return "", errorAt(location, "syntax error: invalid escape \\%c", escape)
+			}
+			l.advance(2)
+		default:
+			r, size := utf8.DecodeRuneInString(l.source[l.offset:])
+			value.WriteRune(r)
+			l.offset += size
+			l.column++
+		}
+	}
+	return "", errorAt(location, "syntax error: unterminated string")
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func errorAt(location Location, format string, a ...any) *Error {
+	return &Error{Message: fmt.Sprintf(format, a...), Locations: []Location{location}}
+}
@@ -0,0 +1,537 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	db "go-backend/db/sqlc"
+	"go-backend/service"
+	"go-backend/util"
+	"strconv"
+)
+
+// The Pagination type bounds the page sizes of the lists of the schema, like those of the REST listings.
+type Pagination struct {
+	Accounts  util.PaginationPolicy
+	Entries   util.PaginationPolicy
+	Transfers util.PaginationPolicy
+}
+
+// errorCodes maps the codes of the service errors to the codes of the GraphQL errors, the same as the
+// codes of the REST error responses.
+var errorCodes = map[service.Code]string{
+	service.CodeInternal:           util.ErrorCodeInternal,
+	service.CodeInvalidArgument:    util.ErrorCodeInvalidArgument,
+	service.CodeNotFound:           util.ErrorCodeNotFound,
+	service.CodeUnauthenticated:    util.ErrorCodeUnauthenticated,
+	service.CodePermissionDenied:   util.ErrorCodePermissionDenied,
+	service.CodeAlreadyExists:      util.ErrorCodeAlreadyExists,
+	service.CodeFailedPrecondition: util.ErrorCodeFailedPrecondition,
+	service.CodeUnavailable:        util.ErrorCodeUnavailable,
+	service.CodeLocked:             util.ErrorCodeLocked,
+	service.CodeDeadlineExceeded:   util.ErrorCodeDeadlineExceeded,
+	service.CodeResourceExhausted:  util.ErrorCodeResourceExhausted,
+}
+
+// roles are the values of the Role enum by role of the users.
+var roles = map[string]string{
+	util.DepositorRole: "DEPOSITOR",
+	util.AdminRole:     "ADMIN",
+	util.SystemRole:    "SYSTEM",
+}
+
+type userKey struct{}
+
+// The `WithUser` function returns a copy of the context carrying the username of the authenticated user
+// of the request, whom the fields are resolved for.
+func WithUser(ctx context.Context, username string) context.Context {
+	return context.WithValue(ctx, userKey{}, username)
+}
+
+func userFrom(ctx context.Context) (string, error) {
+	username, ok := ctx.Value(userKey{}).(string)
+	if !ok || username == "" {
+		return "", NewError(util.ErrorCodeUnauthenticated, "", errors.New("the request has no authenticated user"))
+	}
+	return username, nil
+}
+
+// The accountNode type is an account along with the user reading it, as whom its entries and transfers
+// are listed: the one it was queried by, or the user whose accounts were listed by an admin.
+type accountNode struct {
+	account db.Account
+	reader  string
+}
+
+type entryPage struct {
+	entries    []db.ListEntriesWithCounterpartyRow
+	nextCursor string
+}
+
+type transferPage struct {
+	transfers  []db.SearchTransfersRow
+	nextCursor string
+}
+
+type counterparty struct {
+	accountID int64
+	username  string
+	fullName  string
+}
+
+type resolver struct {
+	service    *service.Service
+	pagination Pagination
+}
+
+// The function creates the schema of the GraphQL API, the graph of the authenticated user, their
+// accounts and the entries and transfers of each, resolved with the service so that the same ownership
+// checks apply as in the REST API. The admins can also read the graph of any user. The queries must be
+// within DefaultLimits, the lists counting as many items as their page size.
+func New(service *service.Service, pagination Pagination) (*Schema, error) {
+	r := &resolver{service: service, pagination: pagination}
+
+	query := &Object{
+		Name: "Query",
+		Fields: []*Field{
+			{
+				Name:        "me",
+				Description: "The authenticated user.",
+				Type:        "User!",
+				Resolve:     r.me,
+			},
+			{
+				Name:        "account",
+				Description: "An account owned by the authenticated user or shared with them.",
+				Type:        "Account",
+				Args:        []*Arg{{Name: "id", Type: "ID!"}},
+				Resolve:     r.account,
+			},
+			{
+				Name:        "user",
+				Description: "A user by username, with their accounts.",
+				Type:        "User",
+				Args:        []*Arg{{Name: "username", Type: "String!"}},
+				Role:        "ADMIN",
+				Resolve:     r.user,
+			},
+		},
+	}
+
+	user := &Object{
+		Name: "User",
+		Fields: []*Field{
+			{Name: "username", Type: "String!", Resolve: userField(func(user db.User) any { return user.Username })},
+			{Name: "fullName", Type: "String!", Resolve: userField(func(user db.User) any { return user.FullName })},
+			{Name: "email", Type: "String!", Resolve: userField(func(user db.User) any { return user.Email })},
+			{Name: "role", Type: "Role!", Resolve: userField(func(user db.User) any { return roles[user.Role] })},
+			{Name: "createdAt", Type: "Time!", Resolve: userField(func(user db.User) any { return user.CreatedAt })},
+			{
+				Name:        "accounts",
+				Description: "The accounts of the user, including those shared with them, by id. `first` defaults to the page size of the account listing.",
+				Type:        "[Account!]!",
+				Args:        []*Arg{{Name: "first", Type: "Int"}, {Name: "offset", Type: "Int", Default: int64(0)}},
+				Resolve:     r.accounts,
+				ListSize:    pageSizeOf(r.pagination.Accounts),
+			},
+		},
+	}
+
+	account := &Object{
+		Name: "Account",
+		Fields: []*Field{
+			{Name: "id", Type: "ID!", Resolve: accountField(func(account db.Account) any { return account.ID })},
+			{Name: "number", Type: "String!", Resolve: accountField(func(account db.Account) any { return account.AccountNumber })},
+			{Name: "owner", Type: "String!", Resolve: accountField(func(account db.Account) any { return account.Owner })},
+			{Name: "balance", Type: "Int64!", Resolve: accountField(func(account db.Account) any { return account.Balance })},
+			{Name: "currency", Type: "String!", Resolve: accountField(func(account db.Account) any { return account.Currency })},
+			{Name: "createdAt", Type: "Time!", Resolve: accountField(func(account db.Account) any { return account.CreatedAt })},
+			{
+				Name:        "entries",
+				Description: "The entries of the account, newest first, a page after the cursor at a time. `first` defaults to the page size of the entry listing.",
+				Type:        "EntryConnection!",
+				Args:        []*Arg{{Name: "first", Type: "Int"}, {Name: "after", Type: "String"}},
+				Resolve:     r.entries,
+				ListSize:    pageSizeOf(r.pagination.Entries),
+			},
+			{
+				Name:        "transfers",
+				Description: "The transfers sent or received by the account, newest first, a page after the cursor at a time. `first` defaults to the page size of the transfer listing.",
+				Type:        "TransferConnection!",
+				Args:        []*Arg{{Name: "first", Type: "Int"}, {Name: "after", Type: "String"}},
+				Resolve:     r.transfers,
+				ListSize:    pageSizeOf(r.pagination.Transfers),
+			},
+		},
+	}
+
+	entryConnection := &Object{
+		Name: "EntryConnection",
+		Fields: []*Field{
+			{Name: "nodes", Type: "[Entry!]!", Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+				return source.(entryPage).entries, nil
+			}},
+			{
+				Name:        "nextCursor",
+				Description: "The cursor of the next page, to pass as `after`, null on the last page.",
+				Type:        "String",
+				Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+					return nullable(source.(entryPage).nextCursor), nil
+				},
+			},
+		},
+	}
+
+	entry := &Object{
+		Name: "Entry",
+		Fields: []*Field{
+			{Name: "id", Type: "ID!", Resolve: entryField(func(row db.ListEntriesWithCounterpartyRow) any { return row.Entry.ID })},
+			{
+				Name:        "amount",
+				Description: "Negative when the money left the account.",
+				Type:        "Int64!",
+				Resolve:     entryField(func(row db.ListEntriesWithCounterpartyRow) any { return row.Entry.Amount }),
+			},
+			{Name: "currency", Type: "String!", Resolve: entryField(func(row db.ListEntriesWithCounterpartyRow) any { return row.Currency })},
+			{Name: "createdAt", Type: "Time!", Resolve: entryField(func(row db.ListEntriesWithCounterpartyRow) any { return row.Entry.CreatedAt })},
+			{
+				Name:        "transferId",
+				Description: "The transfer the entry was made for, null for the other entries.",
+				Type:        "ID",
+				Resolve: entryField(func(row db.ListEntriesWithCounterpartyRow) any {
+					if !row.Entry.TransferID.Valid {
+						return nil
+					}
+					return row.Entry.TransferID.Int64
+				}),
+			},
+			{
+				Name:        "counterparty",
+				Description: "The other account of the transfer the entry was made for, null for the other entries.",
+				Type:        "Counterparty",
+				Resolve: entryField(func(row db.ListEntriesWithCounterpartyRow) any {
+					if !row.CounterpartyAccountID.Valid {
+						return nil
+					}
+					return counterparty{
+						accountID: row.CounterpartyAccountID.Int64,
+						username:  row.CounterpartyUsername.String,
+						fullName:  row.CounterpartyFullName.String,
+					}
+				}),
+			},
+		},
+	}
+
+	transferConnection := &Object{
+		Name: "TransferConnection",
+		Fields: []*Field{
+			{Name: "nodes", Type: "[Transfer!]!", Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+				return source.(transferPage).transfers, nil
+			}},
+			{
+				Name:        "nextCursor",
+				Description: "The cursor of the next page, to pass as `after`, null on the last page.",
+				Type:        "String",
+				Resolve: func(ctx context.Context, source any, args map[string]any) (any, error) {
+					return nullable(source.(transferPage).nextCursor), nil
+				},
+			},
+		},
+	}
+
+	transfer := &Object{
+		Name: "Transfer",
+		Fields: []*Field{
+			{Name: "id", Type: "ID!", Resolve: transferField(func(row db.SearchTransfersRow) any { return row.Transfer.ID })},
+			{Name: "fromAccountId", Type: "ID!", Resolve: transferField(func(row db.SearchTransfersRow) any { return row.Transfer.FromAccountID })},
+			{Name: "toAccountId", Type: "ID!", Resolve: transferField(func(row db.SearchTransfersRow) any { return row.Transfer.ToAccountID })},
+			{Name: "amount", Type: "Int64!", Resolve: transferField(func(row db.SearchTransfersRow) any { return row.Transfer.Amount })},
+			{Name: "currency", Type: "String!", Resolve: transferField(func(row db.SearchTransfersRow) any { return row.Currency })},
+			{Name: "memo", Type: "String!", Resolve: transferField(func(row db.SearchTransfersRow) any { return row.Transfer.Memo })},
+			{Name: "externalReference", Type: "String!", Resolve: transferField(func(row db.SearchTransfersRow) any { return row.Transfer.ExternalReference })},
+			{Name: "createdAt", Type: "Time!", Resolve: transferField(func(row db.SearchTransfersRow) any { return row.Transfer.CreatedAt })},
+			{
+				Name:        "counterparty",
+				Description: "The other account of the transfer.",
+				Type:        "Counterparty!",
+				Resolve: transferField(func(row db.SearchTransfersRow) any {
+					return counterparty{
+						accountID: row.CounterpartyAccountID,
+						username:  row.CounterpartyUsername,
+						fullName:  row.CounterpartyFullName,
+					}
+				}),
+			},
+		},
+	}
+
+	counterpartyObject := &Object{
+		Name:        "Counterparty",
+		Description: "The other account of a transfer, and its owner.",
+		Fields: []*Field{
+			{Name: "accountId", Type: "ID!", Resolve: counterpartyField(func(c counterparty) any { return c.accountID })},
+			{Name: "username", Type: "String!", Resolve: counterpartyField(func(c counterparty) any { return c.username })},
+			{Name: "fullName", Type: "String!", Resolve: counterpartyField(func(c counterparty) any { return c.fullName })},
+		},
+	}
+
+	role := &Enum{
+		Name:        "Role",
+		Description: "The role of a user.",
+		Values:      []string{"DEPOSITOR", "ADMIN", "SYSTEM"},
+	}
+
+	schema, err := NewSchema(
+		query,
+		[]*Object{user, account, entryConnection, entry, transferConnection, transfer, counterpartyObject},
+		[]*Enum{role},
+		r.authorize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	schema.SetLimits(DefaultLimits)
+	return schema, nil
+}
+
+// The `authorize` function lets through the authenticated users holding the role. The role is read from
+// the database so that revoking it takes effect without waiting for the access token to expire.
+func (r *resolver) authorize(ctx context.Context, role string) error {
+	username, err := userFrom(ctx)
+	if err != nil {
+		return err
+	}
+
+	user, err := r.service.GetUser(ctx, username)
+	if err != nil {
+		if service.ErrorCode(err) == service.CodeNotFound {
+			return NewError(util.ErrorCodeUnauthenticated, "", err)
+		}
+		return serviceError(err)
+	}
+
+	if roles[user.Role] != role {
+		return NewError(util.ErrorCodePermissionDenied, "", fmt.Errorf("role %s is not allowed to access this field", user.Role))
+	}
+	return nil
+}
+
+func (r *resolver) me(ctx context.Context, source any, args map[string]any) (any, error) {
+	username, err := userFrom(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := r.service.GetUser(ctx, username)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	return user, nil
+}
+
+func (r *resolver) user(ctx context.Context, source any, args map[string]any) (any, error) {
+	user, err := r.service.GetUser(ctx, args["username"].(string))
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	return user, nil
+}
+
+func (r *resolver) account(ctx context.Context, source any, args map[string]any) (any, error) {
+	username, err := userFrom(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := parseID(args["id"].(string))
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := r.service.GetAccount(ctx, username, id)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+	return accountNode{account: account, reader: username}, nil
+}
+
+func (r *resolver) accounts(ctx context.Context, source any, args map[string]any) (any, error) {
+	user := source.(db.User)
+
+	limit, err := pageSize("first", args, r.pagination.Accounts)
+	if err != nil {
+		return nil, err
+	}
+	offset := args["offset"].(int64)
+	if offset < 0 {
+		return nil, NewError(util.ErrorCodeInvalidArgument, "", errors.New("offset must not be negative"))
+	}
+
+	accounts, err := r.service.ListAccounts(ctx, service.ListAccountsParams{
+		Owner:  user.Username,
+		Limit:  limit,
+		Offset: int32(offset),
+	})
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	nodes := make([]accountNode, len(accounts))
+	for i, account := range accounts {
+		nodes[i] = accountNode{account: account, reader: user.Username}
+	}
+	return nodes, nil
+}
+
+func (r *resolver) entries(ctx context.Context, source any, args map[string]any) (any, error) {
+	node := source.(accountNode)
+
+	limit, err := pageSize("first", args, r.pagination.Entries)
+	if err != nil {
+		return nil, err
+	}
+	cursor, err := afterCursor(args)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := r.service.ListEntriesBefore(ctx, node.reader, node.account.ID, cursor, limit)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	// a nil slice would be a null list
+	page := entryPage{entries: append([]db.ListEntriesWithCounterpartyRow{}, entries...)}
+	if len(entries) == int(limit) {
+		page.nextCursor = util.EncodeCursor(entries[len(entries)-1].Entry.ID)
+	}
+	return page, nil
+}
+
+func (r *resolver) transfers(ctx context.Context, source any, args map[string]any) (any, error) {
+	node := source.(accountNode)
+
+	limit, err := pageSize("first", args, r.pagination.Transfers)
+	if err != nil {
+		return nil, err
+	}
+	cursor, err := afterCursor(args)
+	if err != nil {
+		return nil, err
+	}
+
+	transfers, err := r.service.ListTransfersBefore(ctx, service.ListTransfersParams{
+		Owner:     node.reader,
+		AccountID: node.account.ID,
+		Limit:     limit,
+	}, cursor)
+	if err != nil {
+		return nil, serviceError(err)
+	}
+
+	page := transferPage{transfers: append([]db.SearchTransfersRow{}, transfers...)}
+	if len(transfers) == int(limit) {
+		page.nextCursor = util.EncodeCursor(transfers[len(transfers)-1].Transfer.ID)
+	}
+	return page, nil
+}
+
+// The `pageSize` function returns the page size sent in the argument, or the default one of the policy,
+// failing when it is out of its bounds rather than clamping it.
+func pageSize(name string, args map[string]any, policy util.PaginationPolicy) (int32, error) {
+	size, ok := args[name].(int64)
+	if !ok {
+		return policy.DefaultPageSize, nil
+	}
+	if size < int64(policy.MinPageSize) || size > int64(policy.MaxPageSize) {
+		return 0, NewError(util.ErrorCodeInvalidArgument, "", fmt.Errorf("%s must be between %d and %d", name, policy.MinPageSize, policy.MaxPageSize))
+	}
+	return int32(size), nil
+}
+
+// The `pageSizeOf` function returns the list size of the paginated fields, their page size, so that a
+// `first` out of the bounds of the policy rejects the query before it is executed.
+func pageSizeOf(policy util.PaginationPolicy) ListSizeFunc {
+	return func(args map[string]any) (int64, error) {
+		size, err := pageSize("first", args, policy)
+		return int64(size), err
+	}
+}
+
+// The `afterCursor` function returns the id held by the cursor sent in the after argument, or 0 for the
+// first page.
+func afterCursor(args map[string]any) (int64, error) {
+	after, _ := args["after"].(string)
+	if after == "" {
+		return 0, nil
+	}
+
+	cursor, err := util.DecodeCursor(after)
+	if err != nil {
+		return 0, NewError(util.ErrorCodeInvalidArgument, "", err)
+	}
+	return cursor, nil
+}
+
+// The `nullable` function returns null for an empty string, e.g. the cursor of the page after the last.
+func nullable(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+func parseID(id string) (int64, error) {
+	n, err := strconv.ParseInt(id, 10, 64)
+	if err != nil || n < 1 {
+		return 0, NewError(util.ErrorCodeInvalidArgument, "", fmt.Errorf("invalid id %q", id))
+	}
+	return n, nil
+}
+
+// The `serviceError` function converts an error returned by the service to the error of a field, with
+// its reason as its code when it has one. The details of internal errors are not sent to the client,
+// they are only kept for the error tracking.
+func serviceError(err error) *Error {
+	code := service.ErrorReason(err)
+	if code == "" {
+		code = errorCodes[service.ErrorCode(err)]
+	}
+
+	message := ""
+	if service.ErrorCode(err) == service.CodeInternal {
+		message = "internal error"
+	}
+	return NewError(code, message, err)
+}
+
+func userField(get func(user db.User) any) ResolveFunc {
+	return func(ctx context.Context, source any, args map[string]any) (any, error) {
+		return get(source.(db.User)), nil
+	}
+}
+
+func accountField(get func(account db.Account) any) ResolveFunc {
+	return func(ctx context.Context, source any, args map[string]any) (any, error) {
+		return get(source.(accountNode).account), nil
+	}
+}
+
+func entryField(get func(row db.ListEntriesWithCounterpartyRow) any) ResolveFunc {
+	return func(ctx context.Context, source any, args map[string]any) (any, error) {
+		return get(source.(db.ListEntriesWithCounterpartyRow)), nil
+	}
+}
+
+func transferField(get func(row db.SearchTransfersRow) any) ResolveFunc {
+	return func(ctx context.Context, source any, args map[string]any) (any, error) {
+		return get(source.(db.SearchTransfersRow)), nil
+	}
+}
+
+func counterpartyField(get func(c counterparty) any) ResolveFunc {
+	return func(ctx context.Context, source any, args map[string]any) (any, error) {
+		return get(source.(counterparty)), nil
+	}
+}
@@ -0,0 +1,29 @@
+package graph
+
+import (
+	"flag"
+	"go-backend/util"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "update schema.graphqls")
+
+// TestSchemaSDL makes sure schema.graphqls, which the clients generate their types from, matches the
+// schema. Run the tests with -update to rewrite it after changing the schema.
+func TestSchemaSDL(t *testing.T) {
+	policy := util.PaginationPolicy{MinPageSize: 1, DefaultPageSize: 20, MaxPageSize: 100}
+	schema, err := New(nil, Pagination{Accounts: policy, Entries: policy, Transfers: policy})
+	require.NoError(t, err)
+
+	if *update {
+		err := os.WriteFile("schema.graphqls", []byte(schema.SDL()), 0o644)
+		require.NoError(t, err)
+	}
+
+	sdl, err := os.ReadFile("schema.graphqls")
+	require.NoError(t, err)
+	require.Equal(t, string(sdl), schema.SDL())
+}
@@ -0,0 +1,359 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// builtinScalars are the scalars every GraphQL schema has, which the SDL doesn't declare.
+var builtinScalars = map[string]bool{
+	"String":  true,
+	"Int":     true,
+	"Float":   true,
+	"Boolean": true,
+	"ID":      true,
+}
+
+// customScalars are the scalars the schemas may use besides the built-in ones, with their description.
+var customScalars = map[string]string{
+	"Int64": "A signed 64-bit integer, for the amounts of money and the ids which Int can't hold.",
+	"Time":  "A time in RFC 3339 format, in UTC with milliseconds.",
+}
+
+// The ResolveFunc type returns the value of a field of `source`, the value of the object it belongs to,
+// given its arguments. The arguments are coerced to their type: int64 for Int and Int64, float64 for
+// Float, string for String, ID and the enums, bool for Boolean and time.Time for Time, and []any for the
+// lists. The omitted arguments without a default value are missing from `args`.
+type ResolveFunc func(ctx context.Context, source any, args map[string]any) (any, error)
+
+// The ListSizeFunc type returns the number of items a field holds at most given its arguments, coerced
+// like those passed to the resolver. It fails when the arguments are out of their bounds, e.g. a page
+// size above the maximum, the query being rejected before it is executed.
+type ListSizeFunc func(args map[string]any) (int64, error)
+
+// The AuthorizeFunc type fails unless the user of the request holds the role, a value of the Role enum,
+// for the fields marked with the @auth directive.
+type AuthorizeFunc func(ctx context.Context, role string) error
+
+// The Object type is an object type of the schema.
+// @property {[]*Field} Fields - the fields of the object, in the order of the SDL.
+type Object struct {
+	Name        string
+	Description string
+	Fields      []*Field
+
+	fields map[string]*Field
+}
+
+// The Field type is a field of an object type.
+// @property {string} Type - the type of the field in the SDL notation, e.g. [Account!]!.
+// @property {string} Role - the role the user must hold to query the field, set with @auth(role:).
+// @property {ResolveFunc} Resolve - returns the value of the field. A list is returned as a slice, and a
+// null as nil.
+// @property {ListSizeFunc} ListSize - returns the number of items the field holds at most, e.g. the
+// page size of a paginated list, by which its selection is multiplied in the complexity of the queries.
+// The selection is counted once when it is nil.
+type Field struct {
+	Name        string
+	Description string
+	Type        string
+	Args        []*Arg
+	Role        string
+	Resolve     ResolveFunc
+	ListSize    ListSizeFunc
+
+	typ *typeRef
+}
+
+// The Arg type is an argument of a field.
+// @property {any} Default - the value of the argument when it is omitted, coerced like the arguments
+// passed to the resolvers, none when nil.
+type Arg struct {
+	Name        string
+	Description string
+	Type        string
+	Default     any
+
+	typ *typeRef
+}
+
+// The Enum type is an enum type of the schema, whose values are passed to and returned by the resolvers
+// as strings.
+type Enum struct {
+	Name        string
+	Description string
+	Values      []string
+}
+
+// The typeRef type is the type of a field, an argument or a variable: a named type, or a list of
+// `elem` when it is set, which can't be null when `nonNull` is set.
+type typeRef struct {
+	name    string
+	elem    *typeRef
+	nonNull bool
+}
+
+func (typ *typeRef) String() string {
+	s := typ.name
+	if typ.elem != nil {
+		s = "[" + typ.elem.String() + "]"
+	}
+	if typ.nonNull {
+		s += "!"
+	}
+	return s
+}
+
+// namedType returns the name of the type, or of the type of the items of the list.
+func (typ *typeRef) namedType() string {
+	for typ.elem != nil {
+		typ = typ.elem
+	}
+	return typ.name
+}
+
+// The Schema type is a GraphQL schema whose queries can be executed with `Execute`. It only serves
+// queries, the schema having no mutation or subscription type.
+type Schema struct {
+	query     *Object
+	objects   map[string]*Object
+	enums     map[string]*Enum
+	authorize AuthorizeFunc
+	limits    Limits
+}
+
+// The function creates a schema with `query` as the root of the queries, and the object and enum types
+// its fields lead to. The fields marked with @auth are authorized by `authorize`. It fails when a type
+// is unknown or defined twice, or when a field has no resolver.
+func NewSchema(query *Object, objects []*Object, enums []*Enum, authorize AuthorizeFunc) (*Schema, error) {
+	schema := &Schema{
+		query:     query,
+		objects:   map[string]*Object{},
+		enums:     map[string]*Enum{},
+		authorize: authorize,
+	}
+
+	for _, object := range append([]*Object{query}, objects...) {
+		if schema.isType(object.Name) {
+			return nil, fmt.Errorf("type %s is defined twice", object.Name)
+		}
+		schema.objects[object.Name] = object
+	}
+	for _, enum := range enums {
+		if schema.isType(enum.Name) {
+			return nil, fmt.Errorf("type %s is defined twice", enum.Name)
+		}
+		schema.enums[enum.Name] = enum
+	}
+	if _, ok := schema.enums["Role"]; !ok && hasAuth(schema.objects) {
+		return nil, fmt.Errorf("the Role enum of the @auth directive is not defined")
+	}
+
+	for _, object := range schema.objects {
+		object.fields = make(map[string]*Field, len(object.Fields))
+		for _, field := range object.Fields {
+			if _, ok := object.fields[field.Name]; ok {
+				return nil, fmt.Errorf("field %s.%s is defined twice", object.Name, field.Name)
+			}
+			object.fields[field.Name] = field
+
+			typ, err := schema.parseType(field.Type, false)
+			if err != nil {
+				return nil, fmt.Errorf("field %s.%s: %w", object.Name, field.Name, err)
+			}
+			field.typ = typ
+
+			for _, arg := range field.Args {
+				typ, err := schema.parseType(arg.Type, true)
+				if err != nil {
+					return nil, fmt.Errorf("argument %s of field %s.%s: %w", arg.Name, object.Name, field.Name, err)
+				}
+				arg.typ = typ
+			}
+
+			if field.Role != "" && !contains(schema.enums["Role"].Values, field.Role) {
+				return nil, fmt.Errorf("field %s.%s requires the unknown role %s", object.Name, field.Name, field.Role)
+			}
+			if field.Resolve == nil {
+				return nil, fmt.Errorf("field %s.%s has no resolver", object.Name, field.Name)
+			}
+		}
+	}
+
+	return schema, nil
+}
+
+func (schema *Schema) isType(name string) bool {
+	_, isObject := schema.objects[name]
+	_, isEnum := schema.enums[name]
+	return isObject || isEnum || builtinScalars[name] || customScalars[name] != ""
+}
+
+func (schema *Schema) isInputType(name string) bool {
+	_, isObject := schema.objects[name]
+	return schema.isType(name) && !isObject
+}
+
+// parseType parses a type written in the SDL notation, which must be an input type, i.e. a scalar or an
+// enum, for the arguments.
+func (schema *Schema) parseType(notation string, input bool) (*typeRef, error) {
+	p := &parser{lexer: &lexer{source: notation, line: 1, column: 1}}
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	typ, err := p.parseType()
+	if err != nil || !p.peek(tokenEOF) {
+		return nil, fmt.Errorf("invalid type %q", notation)
+	}
+
+	name := typ.namedType()
+	if !schema.isType(name) {
+		return nil, fmt.Errorf("unknown type %s", name)
+	}
+	if input && !schema.isInputType(name) {
+		return nil, fmt.Errorf("type %s is not an input type", name)
+	}
+	return typ, nil
+}
+
+func hasAuth(objects map[string]*Object) bool {
+	for _, object := range objects {
+		for _, field := range object.Fields {
+			if field.Role != "" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// The `SDL` function returns the schema in the GraphQL schema definition language, for the clients
+// generating their types from it: the @auth directive, the custom scalars, the enums and the object
+// types, the query type first and the others by name.
+func (schema *Schema) SDL() string {
+	var sdl strings.Builder
+
+	if hasAuth(schema.objects) {
+		writeDescription(&sdl, "", "Restricts the field to the users holding the role.")
+		sdl.WriteString("directive @auth(role: Role!) on FIELD_DEFINITION\n\n")
+	}
+
+	scalars := make([]string, 0, len(customScalars))
+	for name := range customScalars {
+		if schema.uses(name) {
+			scalars = append(scalars, name)
+		}
+	}
+	sort.Strings(scalars)
+	for _, name := range scalars {
+		writeDescription(&sdl, "", customScalars[name])
+		fmt.Fprintf(&sdl, "scalar %s\n\n", name)
+	}
+
+	enums := make([]string, 0, len(schema.enums))
+	for name := range schema.enums {
+		enums = append(enums, name)
+	}
+	sort.Strings(enums)
+	for _, name := range enums {
+		enum := schema.enums[name]
+		writeDescription(&sdl, "", enum.Description)
+		fmt.Fprintf(&sdl, "enum %s {\n", enum.Name)
+		for _, value := range enum.Values {
+			fmt.Fprintf(&sdl, "  %s\n", value)
+		}
+		sdl.WriteString("}\n\n")
+	}
+
+	objects := make([]string, 0, len(schema.objects))
+	for name := range schema.objects {
+		if name != schema.query.Name {
+			objects = append(objects, name)
+		}
+	}
+	sort.Strings(objects)
+	for _, name := range append([]string{schema.query.Name}, objects...) {
+		object := schema.objects[name]
+		writeDescription(&sdl, "", object.Description)
+		fmt.Fprintf(&sdl, "type %s {\n", object.Name)
+		for i, field := range object.Fields {
+			if i > 0 && field.Description != "" {
+				sdl.WriteString("\n")
+			}
+			writeDescription(&sdl, "  ", field.Description)
+			fmt.Fprintf(&sdl, "  %s%s: %s", field.Name, sdlArgs(field.Args), field.Type)
+			if field.Role != "" {
+				fmt.Fprintf(&sdl, " @auth(role: %s)", field.Role)
+			}
+			sdl.WriteString("\n")
+		}
+		sdl.WriteString("}\n\n")
+	}
+
+	return strings.TrimSuffix(sdl.String(), "\n")
+}
+
+// uses reports whether a field or an argument of the schema is of the named type.
+func (schema *Schema) uses(name string) bool {
+	for _, object := range schema.objects {
+		for _, field := range object.Fields {
+			if field.typ.namedType() == name {
+				return true
+			}
+			for _, arg := range field.Args {
+				if arg.typ.namedType() == name {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func sdlArgs(args []*Arg) string {
+	if len(args) == 0 {
+		return ""
+	}
+
+	written := make([]string, len(args))
+	for i, arg := range args {
+		written[i] = arg.Name + ": " + arg.Type
+		if arg.Default != nil {
+			written[i] += " = " + sdlValue(arg.Default)
+		}
+	}
+	return "(" + strings.Join(written, ", ") + ")"
+}
+
+func sdlValue(value any) string {
+	switch value := value.(type) {
+	case string:
+		return strconv.Quote(value)
+	case int64:
+		return strconv.FormatInt(value, 10)
+	case float64:
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	case bool:
+		return strconv.FormatBool(value)
+	}
+	return fmt.Sprint(value)
+}
+
+func writeDescription(sdl *strings.Builder, indent string, description string) {
+	if description == "" {
+		return
+	}
+	fmt.Fprintf(sdl, "%s\"\"\"\n%s%s\n%s\"\"\"\n", indent, indent, description, indent)
+}
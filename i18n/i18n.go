@@ -0,0 +1,112 @@
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Languages the messages are written in, as the primary subtags of their language tags.
+const (
+	English = "en"
+	French  = "fr"
+)
+
+// DefaultLanguage is the language of the responses to the requests that don't ask for a supported one,
+// and the one the messages missing from a catalog are written in.
+const DefaultLanguage = English
+
+//go:embed catalogs
+var catalogFiles embed.FS
+
+// catalogs are the messages of each language by key, read from catalogs/<language>.json. A message
+// names its arguments between braces, e.g. "{field} is required".
+var catalogs = map[string]map[string]string{}
+
+func init() {
+	for _, language := range []string{English, French} {
+		data, err := catalogFiles.ReadFile(path.Join("catalogs", language+".json"))
+		if err != nil {
+			panic(err)
+		}
+
+		catalog := map[string]string{}
+		if err := json.Unmarshal(data, &catalog); err != nil {
+			panic(err)
+		}
+		catalogs[language] = catalog
+	}
+}
+
+// The `Languages` function returns the supported languages, sorted.
+func Languages() []string {
+	languages := make([]string, 0, len(catalogs))
+	for language := range catalogs {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+// The `Lookup` function returns the message of the key in the language, its arguments given as pairs of
+// names and values, and whether the catalog of the language has it.
+func Lookup(language string, key string, args ...string) (string, bool) {
+	message, ok := catalogs[language][key]
+	if !ok {
+		return "", false
+	}
+
+	replacements := make([]string, 0, len(args))
+	for i := 0; i+1 < len(args); i += 2 {
+		replacements = append(replacements, "{"+args[i]+"}", args[i+1])
+	}
+	return strings.NewReplacer(replacements...).Replace(message), true
+}
+
+// The `Message` function returns the message of the key in the language like `Lookup`, falling back to
+// the default language when the catalog of the language misses it, and to the key itself when no
+// catalog has it.
+func Message(language string, key string, args ...string) string {
+	if message, ok := Lookup(language, key, args...); ok {
+		return message
+	}
+	if message, ok := Lookup(DefaultLanguage, key, args...); ok {
+		return message
+	}
+	return key
+}
+
+// The `MatchLanguage` function returns the supported language a client prefers according to the value
+// of its Accept-Language header, e.g. "fr-CA,fr;q=0.9,en;q=0.8", or the default language when it accepts
+// none of them. Language tags are matched by their primary subtag, and the ranges of equal quality in
+// the order they are listed.
+func MatchLanguage(acceptLanguage string) string {
+	best, bestQuality := DefaultLanguage, 0.0
+	for _, element := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(element), ";")
+		primary, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+
+		quality := 1.0
+		for _, param := range strings.Split(params, ";") {
+			name, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if ok && strings.TrimSpace(name) == "q" {
+				parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+				if err != nil {
+					parsed = 0
+				}
+				quality = parsed
+			}
+		}
+
+		if primary == "*" {
+			primary = DefaultLanguage
+		}
+		if _, ok := catalogs[primary]; ok && quality > bestQuality {
+			best, bestQuality = primary, quality
+		}
+	}
+	return best
+}
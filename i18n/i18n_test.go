@@ -0,0 +1,56 @@
+package i18n
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCatalogs(t *testing.T) {
+	require.Equal(t, []string{English, French}, Languages())
+
+	// every catalog translates every message of the default language, and only those
+	for _, language := range Languages() {
+		require.Len(t, catalogs[language], len(catalogs[DefaultLanguage]), language)
+		for key := range catalogs[DefaultLanguage] {
+			require.Contains(t, catalogs[language], key, language)
+		}
+	}
+}
+
+func TestMessage(t *testing.T) {
+	require.Equal(t, "amount is required", Message(English, "validation.required", "field", "amount"))
+	require.Equal(t, "amount est obligatoire", Message(French, "validation.required", "field", "amount"))
+	require.Equal(t, "amount must be at least 1", Message(English, "validation.min", "field", "amount", "param", "1"))
+
+	// unsupported languages and unknown keys fall back
+	require.Equal(t, "amount is required", Message("de", "validation.required", "field", "amount"))
+	require.Equal(t, "validation.unknown", Message(French, "validation.unknown"))
+
+	_, ok := Lookup("de", "validation.required")
+	require.False(t, ok)
+}
+
+func TestMatchLanguage(t *testing.T) {
+	testCases := []struct {
+		header   string
+		language string
+	}{
+		{header: "", language: English},
+		{header: "fr", language: French},
+		{header: "fr-CA", language: French},
+		{header: "FR-ca", language: French},
+		{header: "de-DE,fr;q=0.5,en;q=0.4", language: French},
+		{header: "en;q=0.5, fr;q=0.8", language: French},
+		{header: "fr;q=0.8, en", language: English},
+		{header: "fr, en", language: French},
+		{header: "fr;q=0, en;q=0.1", language: English},
+		{header: "de, ja", language: English},
+		{header: "*", language: English},
+		{header: "fr;q=oops", language: English},
+	}
+
+	for _, tc := range testCases {
+		require.Equal(t, tc.language, MatchLanguage(tc.header), tc.header)
+	}
+}
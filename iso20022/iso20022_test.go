@@ -0,0 +1,195 @@
+package iso20022
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testInitiation is a pain.001 payroll of two salaries, with elements the bank doesn't read.
+const testInitiation = `<?xml version="1.0" encoding="UTF-8"?>
+<Document xmlns="urn:iso:std:iso:20022:tech:xsd:pain.001.001.03">
+  <CstmrCdtTrfInitn>
+    <GrpHdr>
+      <MsgId>PAYROLL-2024-01</MsgId>
+      <CreDtTm>2024-01-31T09:00:00</CreDtTm>
+      <NbOfTxs>2</NbOfTxs>
+      <CtrlSum>350.00</CtrlSum>
+      <InitgPty>
+        <Nm>Acme Corp</Nm>
+      </InitgPty>
+    </GrpHdr>
+    <PmtInf>
+      <PmtInfId>SALARIES</PmtInfId>
+      <PmtMtd>TRF</PmtMtd>
+      <ReqdExctnDt>2024-01-31</ReqdExctnDt>
+      <Dbtr>
+        <Nm>Acme Corp</Nm>
+      </Dbtr>
+      <DbtrAcct>
+        <Id>
+          <Othr>
+            <Id>1</Id>
+          </Othr>
+        </Id>
+        <Ccy>USD</Ccy>
+      </DbtrAcct>
+      <CdtTrfTxInf>
+        <PmtId>
+          <InstrId>INSTR-1</InstrId>
+          <EndToEndId>E2E-1</EndToEndId>
+        </PmtId>
+        <Amt>
+          <InstdAmt Ccy="USD">100.00</InstdAmt>
+        </Amt>
+        <Cdtr>
+          <Nm>Alice</Nm>
+        </Cdtr>
+        <CdtrAcct>
+          <Id>
+            <Othr>
+              <Id>2</Id>
+            </Othr>
+          </Id>
+        </CdtrAcct>
+        <RmtInf>
+          <Ustrd>January salary</Ustrd>
+        </RmtInf>
+      </CdtTrfTxInf>
+      <CdtTrfTxInf>
+        <PmtId>
+          <EndToEndId>NOTPROVIDED</EndToEndId>
+        </PmtId>
+        <Amt>
+          <InstdAmt Ccy="USD">250</InstdAmt>
+        </Amt>
+        <Cdtr>
+          <Nm>Bob</Nm>
+        </Cdtr>
+        <CdtrAcct>
+          <Id>
+            <IBAN>3</IBAN>
+          </Id>
+        </CdtrAcct>
+      </CdtTrfTxInf>
+    </PmtInf>
+  </CstmrCdtTrfInitn>
+</Document>
+`
+
+func TestParseInitiation(t *testing.T) {
+	initiation, err := ParseInitiation(strings.NewReader(testInitiation))
+	require.NoError(t, err)
+
+	require.Equal(t, Initiation{
+		MessageID:            "PAYROLL-2024-01",
+		NumberOfTransactions: "2",
+		ControlSum:           "350.00",
+		Payments: []PaymentInformation{
+			{
+				ID:            "SALARIES",
+				DebtorAccount: "1",
+				Currency:      "USD",
+				Transactions: []CreditTransfer{
+					{
+						InstructionID:         "INSTR-1",
+						EndToEndID:            "E2E-1",
+						Amount:                "100.00",
+						Currency:              "USD",
+						CreditorName:          "Alice",
+						CreditorAccount:       "2",
+						RemittanceInformation: "January salary",
+					},
+					{
+						EndToEndID:      "NOTPROVIDED",
+						Amount:          "250",
+						Currency:        "USD",
+						CreditorName:    "Bob",
+						CreditorAccount: "3",
+					},
+				},
+			},
+		},
+	}, initiation)
+	require.Equal(t, 2, initiation.Transactions())
+}
+
+func TestParseInitiationInvalid(t *testing.T) {
+	for name, document := range map[string]string{
+		"NotXML":           "date,amount\n2024-01-01,10\n",
+		"MissingMessageID": `<Document><CstmrCdtTrfInitn><GrpHdr><NbOfTxs>0</NbOfTxs></GrpHdr></CstmrCdtTrfInitn></Document>`,
+		"OtherMessage":     `<Document><CstmrPmtStsRpt><GrpHdr><MsgId>1</MsgId></GrpHdr></CstmrPmtStsRpt></Document>`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseInitiation(strings.NewReader(document))
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestAggregateStatus(t *testing.T) {
+	testCases := []struct {
+		statuses []string
+		want     string
+	}{
+		{nil, StatusRejected},
+		{[]string{StatusSettled, StatusSettled}, StatusSettled},
+		{[]string{StatusRejected, StatusRejected}, StatusRejected},
+		{[]string{StatusSettled, StatusPending}, StatusPending},
+		{[]string{StatusSettled, StatusRejected}, StatusPartiallyAccepted},
+		{[]string{StatusPending, StatusRejected}, StatusPartiallyAccepted},
+	}
+
+	for _, tc := range testCases {
+		require.Equal(t, tc.want, AggregateStatus(tc.statuses), "%v", tc.statuses)
+	}
+}
+
+func TestWriteStatusReport(t *testing.T) {
+	report := StatusReport{
+		MessageID:                    "REPORT-1",
+		CreatedAt:                    time.Date(2024, time.January, 31, 9, 0, 1, 0, time.UTC),
+		OriginalMessageID:            "PAYROLL-2024-01",
+		OriginalNumberOfTransactions: "2",
+		GroupStatus:                  StatusPartiallyAccepted,
+		Payments: []PaymentStatus{
+			{
+				OriginalPaymentInformationID: "SALARIES",
+				Status:                       StatusPartiallyAccepted,
+				Transactions: []TransactionStatus{
+					{OriginalEndToEndID: "E2E-1", Status: StatusSettled, Reference: "transfer:7"},
+					{
+						OriginalEndToEndID: "E2E-2",
+						Status:             StatusRejected,
+						Reason:             &StatusReason{Code: ReasonNarrative, AdditionalInformation: strings.Repeat("x", 200)},
+					},
+				},
+			},
+		},
+	}
+
+	var buffer bytes.Buffer
+	require.NoError(t, WriteStatusReport(&buffer, report))
+	require.True(t, strings.HasPrefix(buffer.String(), xml.Header))
+	require.Contains(t, buffer.String(), `<Document xmlns="urn:iso:std:iso:20022:tech:xsd:pain.002.001.03">`)
+
+	var document pain002Document
+	require.NoError(t, xml.Unmarshal(buffer.Bytes(), &document))
+	require.Equal(t, "REPORT-1", document.Report.GroupHeader.MessageID)
+	require.Equal(t, "2024-01-31T09:00:01", document.Report.GroupHeader.CreatedAt)
+	require.Equal(t, "pain.001.001.03", document.Report.Group.OriginalMessageName)
+	require.Equal(t, StatusPartiallyAccepted, document.Report.Group.Status)
+	require.Nil(t, document.Report.Group.Reason)
+
+	require.Len(t, document.Report.Payments, 1)
+	transactions := document.Report.Payments[0].Transactions
+	require.Len(t, transactions, 2)
+	require.Equal(t, "transfer:7", transactions[0].Reference)
+	require.Nil(t, transactions[0].Reason)
+	require.Equal(t, ReasonNarrative, transactions[1].Reason.Code)
+	require.Len(t, transactions[1].Reason.AdditionalInformation, maxAdditionalInformation)
+}
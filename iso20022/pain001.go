@@ -0,0 +1,144 @@
+package iso20022
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// The Initiation type is a pain.001 customer credit transfer initiation, the file a corporate customer
+// sends its bank to make a batch of payments, e.g. a payroll.
+// @property {string} MessageID - the id the customer gave the file, which the status report refers to.
+// @property {string} NumberOfTransactions - the number of credit transfers the customer declares in the
+// group header, kept as written to be checked against the file.
+// @property {string} ControlSum - the total of the amounts the customer declares, optional.
+type Initiation struct {
+	MessageID            string
+	NumberOfTransactions string
+	ControlSum           string
+	Payments             []PaymentInformation
+}
+
+// The PaymentInformation type is a block of credit transfers sent from the same debtor account.
+// @property {string} DebtorAccount - the account the transfers are sent from, identified by its IBAN or
+// its id at the bank.
+// @property {string} Currency - the currency of the debtor account, optional.
+type PaymentInformation struct {
+	ID            string
+	DebtorAccount string
+	Currency      string
+	Transactions  []CreditTransfer
+}
+
+// The CreditTransfer type is a payment of an initiation.
+// @property {string} Amount - the instructed amount, a decimal number kept as written.
+// @property {string} CreditorAccount - the account the transfer is sent to, identified by its IBAN or its
+// id at the bank.
+// @property {string} RemittanceInformation - the unstructured remittance information, shown to the
+// creditor.
+type CreditTransfer struct {
+	InstructionID         string
+	EndToEndID            string
+	Amount                string
+	Currency              string
+	CreditorName          string
+	CreditorAccount       string
+	RemittanceInformation string
+}
+
+// The `Transactions` method returns the number of credit transfers found in the file.
+func (initiation Initiation) Transactions() int {
+	n := 0
+	for _, payment := range initiation.Payments {
+		n += len(payment.Transactions)
+	}
+	return n
+}
+
+// The pain001Document type maps the elements of a pain.001 document the bank reads, the others being
+// ignored. The elements are matched whatever their namespace, so that the versions of the message which
+// didn't change them are all read.
+type pain001Document struct {
+	XMLName    xml.Name `xml:"Document"`
+	Initiation struct {
+		GroupHeader struct {
+			MessageID            string `xml:"MsgId"`
+			NumberOfTransactions string `xml:"NbOfTxs"`
+			ControlSum           string `xml:"CtrlSum"`
+		} `xml:"GrpHdr"`
+		Payments []struct {
+			ID            string         `xml:"PmtInfId"`
+			DebtorAccount pain001Account `xml:"DbtrAcct"`
+			Transactions  []struct {
+				InstructionID string `xml:"PmtId>InstrId"`
+				EndToEndID    string `xml:"PmtId>EndToEndId"`
+				Amount        struct {
+					Value    string `xml:",chardata"`
+					Currency string `xml:"Ccy,attr"`
+				} `xml:"Amt>InstdAmt"`
+				CreditorName          string         `xml:"Cdtr>Nm"`
+				CreditorAccount       pain001Account `xml:"CdtrAcct"`
+				RemittanceInformation []string       `xml:"RmtInf>Ustrd"`
+			} `xml:"CdtTrfTxInf"`
+		} `xml:"PmtInf"`
+	} `xml:"CstmrCdtTrfInitn"`
+}
+
+// The pain001Account type is an account of a pain.001 document, identified by an IBAN or another id.
+type pain001Account struct {
+	IBAN     string `xml:"Id>IBAN"`
+	Other    string `xml:"Id>Othr>Id"`
+	Currency string `xml:"Ccy"`
+}
+
+func (account pain001Account) id() string {
+	if account.Other != "" {
+		return strings.TrimSpace(account.Other)
+	}
+	return strings.TrimSpace(account.IBAN)
+}
+
+// The `ParseInitiation` function reads a pain.001 customer credit transfer initiation. Only the
+// structure of the document is checked, the payments it holds being checked by the bank when made.
+func ParseInitiation(r io.Reader) (Initiation, error) {
+	var document pain001Document
+	if err := xml.NewDecoder(r).Decode(&document); err != nil {
+		return Initiation{}, fmt.Errorf("invalid pain.001 document: %w", err)
+	}
+
+	header := document.Initiation.GroupHeader
+	if strings.TrimSpace(header.MessageID) == "" {
+		return Initiation{}, errors.New("invalid pain.001 document: missing message id")
+	}
+
+	initiation := Initiation{
+		MessageID:            strings.TrimSpace(header.MessageID),
+		NumberOfTransactions: strings.TrimSpace(header.NumberOfTransactions),
+		ControlSum:           strings.TrimSpace(header.ControlSum),
+		Payments:             make([]PaymentInformation, 0, len(document.Initiation.Payments)),
+	}
+	for _, block := range document.Initiation.Payments {
+		payment := PaymentInformation{
+			ID:            strings.TrimSpace(block.ID),
+			DebtorAccount: block.DebtorAccount.id(),
+			Currency:      strings.TrimSpace(block.DebtorAccount.Currency),
+			Transactions:  make([]CreditTransfer, 0, len(block.Transactions)),
+		}
+		for _, transaction := range block.Transactions {
+			payment.Transactions = append(payment.Transactions, CreditTransfer{
+				InstructionID:         strings.TrimSpace(transaction.InstructionID),
+				EndToEndID:            strings.TrimSpace(transaction.EndToEndID),
+				Amount:                strings.TrimSpace(transaction.Amount.Value),
+				Currency:              strings.TrimSpace(transaction.Amount.Currency),
+				CreditorName:          strings.TrimSpace(transaction.CreditorName),
+				CreditorAccount:       transaction.CreditorAccount.id(),
+				RemittanceInformation: strings.TrimSpace(strings.Join(transaction.RemittanceInformation, " ")),
+			})
+		}
+		initiation.Payments = append(initiation.Payments, payment)
+	}
+
+	return initiation, nil
+}
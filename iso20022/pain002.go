@@ -0,0 +1,193 @@
+package iso20022
+
+import (
+	"encoding/xml"
+	"io"
+	"time"
+)
+
+// pain002Namespace is the namespace of the status reports written by the bank.
+const pain002Namespace = "urn:iso:std:iso:20022:tech:xsd:pain.002.001.03"
+
+// pain001MessageName is the message the status reports refer to, the one whose elements the bank reads.
+const pain001MessageName = "pain.001.001.03"
+
+// maxAdditionalInformation is the longest additional information of a status reason ISO 20022 allows,
+// longer ones being cut.
+const maxAdditionalInformation = 105
+
+// Statuses of the groups, payments and transactions of a status report.
+const (
+	StatusSettled           = "ACSC"
+	StatusPending           = "PDNG"
+	StatusRejected          = "RJCT"
+	StatusPartiallyAccepted = "PART"
+)
+
+// Codes of the reasons a transaction, or the whole initiation, is rejected for, from the external status
+// reason code list of ISO 20022.
+const (
+	ReasonIncorrectAccountNumber      = "AC01"
+	ReasonTransactionForbidden        = "AG01"
+	ReasonNotAllowedAmount            = "AM02"
+	ReasonInvalidControlSum           = "AM10"
+	ReasonInvalidCurrency             = "AM11"
+	ReasonInvalidAmount               = "AM12"
+	ReasonInvalidNumberOfTransactions = "AM18"
+	ReasonNarrative                   = "NARR"
+)
+
+// The StatusReport type is a pain.002 customer payment status report, the answer of the bank to an
+// initiation with the status of each of its transactions.
+// @property {string} MessageID - the id of the report, at most 35 characters.
+// @property {string} GroupStatus - the status of the initiation as a whole.
+// @property {*StatusReason} GroupReason - why the whole initiation was rejected, nil otherwise.
+type StatusReport struct {
+	MessageID                    string
+	CreatedAt                    time.Time
+	OriginalMessageID            string
+	OriginalNumberOfTransactions string
+	OriginalControlSum           string
+	GroupStatus                  string
+	GroupReason                  *StatusReason
+	Payments                     []PaymentStatus
+}
+
+// The PaymentStatus type is the status of a payment information block of an initiation.
+type PaymentStatus struct {
+	OriginalPaymentInformationID string
+	Status                       string
+	Transactions                 []TransactionStatus
+}
+
+// The TransactionStatus type is the status of a credit transfer of an initiation.
+// @property {string} Reference - the reference the bank gave the transaction, e.g. the id of the
+// transfer made.
+// @property {*StatusReason} Reason - why the transaction was rejected, nil otherwise.
+type TransactionStatus struct {
+	OriginalInstructionID string
+	OriginalEndToEndID    string
+	Status                string
+	Reason                *StatusReason
+	Reference             string
+}
+
+// The StatusReason type is a reason code along with a text explaining it.
+type StatusReason struct {
+	Code                  string
+	AdditionalInformation string
+}
+
+// The `AggregateStatus` function returns the status of a group of transactions: settled or rejected when
+// they all are, pending when none was rejected but some are pending, and partially accepted otherwise.
+func AggregateStatus(statuses []string) string {
+	counts := make(map[string]int)
+	for _, status := range statuses {
+		counts[status]++
+	}
+
+	switch {
+	case len(statuses) == 0 || counts[StatusRejected] == len(statuses):
+		return StatusRejected
+	case counts[StatusSettled] == len(statuses):
+		return StatusSettled
+	case counts[StatusRejected] == 0:
+		return StatusPending
+	}
+	return StatusPartiallyAccepted
+}
+
+type pain002Document struct {
+	XMLName xml.Name `xml:"Document"`
+	Xmlns   string   `xml:"xmlns,attr"`
+	Report  struct {
+		GroupHeader struct {
+			MessageID string `xml:"MsgId"`
+			CreatedAt string `xml:"CreDtTm"`
+		} `xml:"GrpHdr"`
+		Group struct {
+			OriginalMessageID            string         `xml:"OrgnlMsgId"`
+			OriginalMessageName          string         `xml:"OrgnlMsgNmId"`
+			OriginalNumberOfTransactions string         `xml:"OrgnlNbOfTxs,omitempty"`
+			OriginalControlSum           string         `xml:"OrgnlCtrlSum,omitempty"`
+			Status                       string         `xml:"GrpSts"`
+			Reason                       *pain002Reason `xml:"StsRsnInf,omitempty"`
+		} `xml:"OrgnlGrpInfAndSts"`
+		Payments []pain002Payment `xml:"OrgnlPmtInfAndSts"`
+	} `xml:"CstmrPmtStsRpt"`
+}
+
+type pain002Payment struct {
+	OriginalPaymentInformationID string               `xml:"OrgnlPmtInfId"`
+	Status                       string               `xml:"PmtInfSts"`
+	Transactions                 []pain002Transaction `xml:"TxInfAndSts"`
+}
+
+type pain002Transaction struct {
+	OriginalInstructionID string         `xml:"OrgnlInstrId,omitempty"`
+	OriginalEndToEndID    string         `xml:"OrgnlEndToEndId,omitempty"`
+	Status                string         `xml:"TxSts"`
+	Reason                *pain002Reason `xml:"StsRsnInf,omitempty"`
+	Reference             string         `xml:"AcctSvcrRef,omitempty"`
+}
+
+type pain002Reason struct {
+	Code                  string `xml:"Rsn>Cd"`
+	AdditionalInformation string `xml:"AddtlInf,omitempty"`
+}
+
+func newPain002Reason(reason *StatusReason) *pain002Reason {
+	if reason == nil {
+		return nil
+	}
+
+	information := reason.AdditionalInformation
+	if len(information) > maxAdditionalInformation {
+		information = information[:maxAdditionalInformation]
+	}
+	return &pain002Reason{Code: reason.Code, AdditionalInformation: information}
+}
+
+// The `WriteStatusReport` function writes the report as a pain.002.001.03 customer payment status report.
+func WriteStatusReport(w io.Writer, report StatusReport) error {
+	var document pain002Document
+	document.Xmlns = pain002Namespace
+	document.Report.GroupHeader.MessageID = report.MessageID
+	document.Report.GroupHeader.CreatedAt = report.CreatedAt.UTC().Format("2006-01-02T15:04:05")
+
+	group := &document.Report.Group
+	group.OriginalMessageID = report.OriginalMessageID
+	group.OriginalMessageName = pain001MessageName
+	group.OriginalNumberOfTransactions = report.OriginalNumberOfTransactions
+	group.OriginalControlSum = report.OriginalControlSum
+	group.Status = report.GroupStatus
+	group.Reason = newPain002Reason(report.GroupReason)
+
+	for _, payment := range report.Payments {
+		status := pain002Payment{
+			OriginalPaymentInformationID: payment.OriginalPaymentInformationID,
+			Status:                       payment.Status,
+		}
+		for _, transaction := range payment.Transactions {
+			status.Transactions = append(status.Transactions, pain002Transaction{
+				OriginalInstructionID: transaction.OriginalInstructionID,
+				OriginalEndToEndID:    transaction.OriginalEndToEndID,
+				Status:                transaction.Status,
+				Reason:                newPain002Reason(transaction.Reason),
+				Reference:             transaction.Reference,
+			})
+		}
+		document.Report.Payments = append(document.Report.Payments, status)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(document); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
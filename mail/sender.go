@@ -0,0 +1,67 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"go-backend/util"
+	"log"
+	netmail "net/mail"
+)
+
+// Drivers sending the emails, selected with the MAIL_DRIVER config.
+const (
+	DriverLog      = "log"
+	DriverSMTP     = "smtp"
+	DriverSendGrid = "sendgrid"
+)
+
+// The Message type is an email ready to be sent.
+// @property {[]string} To - the addresses the email is sent to.
+// @property {string} HTML - the HTML body of the email.
+// @property {string} Text - the plain text body, for the clients not showing HTML.
+type Message struct {
+	To      []string
+	Subject string
+	HTML    string
+	Text    string
+}
+
+// The Sender interface sends emails from the address of the bank.
+type Sender interface {
+	Send(ctx context.Context, message Message) error
+}
+
+// The function creates the sender of the MAIL_DRIVER config, the log driver when it is empty.
+func NewSender(config util.Config) (Sender, error) {
+	from := Address{Name: config.MailFromName, Email: config.MailFromAddress}
+
+	switch config.MailDriver {
+	case "", DriverLog:
+		return LogSender{}, nil
+	case DriverSMTP:
+		return NewSMTPSender(config.SMTPHost, config.SMTPPort, config.SMTPUsername, config.SMTPPassword, from)
+	case DriverSendGrid:
+		return NewSendGridSender(config.SendGridAPIKey, from)
+	}
+
+	return nil, fmt.Errorf("unknown mail driver %s", config.MailDriver)
+}
+
+// The Address type is the sender of the emails, shown as `Name <Email>`.
+type Address struct {
+	Name  string
+	Email string
+}
+
+func (address Address) String() string {
+	// encodes the name when it isn't plain ASCII
+	return (&netmail.Address{Name: address.Name, Address: address.Email}).String()
+}
+
+// The LogSender type writes the emails to the log instead of sending them, for local development.
+type LogSender struct{}
+
+func (LogSender) Send(ctx context.Context, message Message) error {
+	log.Printf("email to %v: %s\n%s", message.To, message.Subject, message.Text)
+	return nil
+}
@@ -0,0 +1,113 @@
+package mail
+
+import (
+	"context"
+	"encoding/json"
+	"go-backend/util"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	netmail "net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSendGridSender(t *testing.T) {
+	var got sendGridRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "Bearer test-key", r.Header.Get("Authorization"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+
+		if got.Subject == "rejected" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"errors":[{"message":"invalid"}]}`))
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	sender, err := NewSendGridSender("test-key", Address{Name: "Go Bank", Email: "no-reply@bank.test"})
+	require.NoError(t, err)
+	sender.(*SendGridSender).url = server.URL
+
+	err = sender.Send(context.Background(), Message{
+		To:      []string{"alice@example.com"},
+		Subject: "Hello",
+		HTML:    "<p>Hi</p>",
+		Text:    "Hi",
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, sendGridAddress{Email: "no-reply@bank.test", Name: "Go Bank"}, got.From)
+	require.Equal(t, []sendGridPersonalization{{To: []sendGridAddress{{Email: "alice@example.com"}}}}, got.Personalizations)
+	require.Equal(t, []sendGridContent{{Type: "text/plain", Value: "Hi"}, {Type: "text/html", Value: "<p>Hi</p>"}}, got.Content)
+
+	err = sender.Send(context.Background(), Message{To: []string{"alice@example.com"}, Subject: "rejected", Text: "Hi"})
+	require.ErrorContains(t, err, "status 400")
+	require.ErrorContains(t, err, "invalid")
+}
+
+func TestSMTPSenderFormat(t *testing.T) {
+	sender, err := NewSMTPSender("smtp.bank.test", 587, "", "", Address{Name: "Banque Gö", Email: "no-reply@bank.test"})
+	require.NoError(t, err)
+
+	data, err := sender.(*SMTPSender).format(Message{
+		To:      []string{"alice@example.com"},
+		Subject: "Virement reçu",
+		HTML:    "<p>Hi</p>",
+		Text:    "Hi",
+	}, time.Now())
+	require.NoError(t, err)
+
+	message, err := netmail.ReadMessage(strings.NewReader(string(data)))
+	require.NoError(t, err)
+
+	from, err := message.Header.AddressList("From")
+	require.NoError(t, err)
+	require.Equal(t, []*netmail.Address{{Name: "Banque Gö", Address: "no-reply@bank.test"}}, from)
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(message.Header.Get("Subject"))
+	require.NoError(t, err)
+	require.Equal(t, "Virement reçu", subject)
+	require.True(t, strings.HasSuffix(message.Header.Get("Message-ID"), "@bank.test>"))
+
+	mediaType, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	require.Equal(t, "multipart/alternative", mediaType)
+
+	// the plain text part comes first, so that the clients showing HTML prefer the HTML one
+	reader := multipart.NewReader(message.Body, params["boundary"])
+	for _, want := range []struct{ contentType, body string }{
+		{"text/plain; charset=utf-8", "Hi"},
+		{"text/html; charset=utf-8", "<p>Hi</p>"},
+	} {
+		part, err := reader.NextPart()
+		require.NoError(t, err)
+		require.Equal(t, want.contentType, part.Header.Get("Content-Type"))
+
+		body, err := io.ReadAll(part)
+		require.NoError(t, err)
+		require.Equal(t, want.body, string(body))
+	}
+
+	_, err = reader.NextPart()
+	require.ErrorIs(t, err, io.EOF)
+}
+
+func TestNewSender(t *testing.T) {
+	sender, err := NewSender(util.Config{})
+	require.NoError(t, err)
+	require.IsType(t, LogSender{}, sender)
+
+	_, err = NewSender(util.Config{MailDriver: DriverSendGrid, MailFromAddress: "no-reply@bank.test"})
+	require.Error(t, err, "the API key is required")
+
+	_, err = NewSender(util.Config{MailDriver: "pigeon"})
+	require.ErrorContains(t, err, "unknown mail driver")
+}
@@ -0,0 +1,110 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const (
+	sendGridURL     = "https://api.sendgrid.com/v3/mail/send"
+	sendGridTimeout = 10 * time.Second
+)
+
+// The SendGridSender type sends the emails with the v3 mail send API of SendGrid.
+type SendGridSender struct {
+	apiKey string
+	from   Address
+	url    string
+	client *http.Client
+}
+
+// The function creates a sender authenticating with the SendGrid `apiKey`.
+func NewSendGridSender(apiKey string, from Address) (Sender, error) {
+	if apiKey == "" {
+		return nil, errors.New("SendGrid API key is required")
+	}
+	if from.Email == "" {
+		return nil, errors.New("from address is required")
+	}
+
+	return &SendGridSender{
+		apiKey: apiKey,
+		from:   from,
+		url:    sendGridURL,
+		client: &http.Client{Timeout: sendGridTimeout},
+	}, nil
+}
+
+type sendGridAddress struct {
+	Email string `json:"email"`
+	Name  string `json:"name,omitempty"`
+}
+
+type sendGridContent struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+type sendGridPersonalization struct {
+	To []sendGridAddress `json:"to"`
+}
+
+type sendGridRequest struct {
+	Personalizations []sendGridPersonalization `json:"personalizations"`
+	From             sendGridAddress           `json:"from"`
+	Subject          string                    `json:"subject"`
+	Content          []sendGridContent         `json:"content"`
+}
+
+func (sender *SendGridSender) Send(ctx context.Context, message Message) error {
+	request := sendGridRequest{
+		From:    sendGridAddress{Email: sender.from.Email, Name: sender.from.Name},
+		Subject: message.Subject,
+	}
+
+	var personalization sendGridPersonalization
+	for _, to := range message.To {
+		personalization.To = append(personalization.To, sendGridAddress{Email: to})
+	}
+	request.Personalizations = []sendGridPersonalization{personalization}
+
+	// SendGrid requires the plain text content first
+	if message.Text != "" {
+		request.Content = append(request.Content, sendGridContent{Type: "text/plain", Value: message.Text})
+	}
+	if message.HTML != "" {
+		request.Content = append(request.Content, sendGridContent{Type: "text/html", Value: message.HTML})
+	}
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, sender.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	httpRequest.Header.Set("Authorization", "Bearer "+sender.apiKey)
+	httpRequest.Header.Set("Content-Type", "application/json")
+
+	response, err := sender.client.Do(httpRequest)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		// the errors of the API are small json documents
+		detail, _ := io.ReadAll(io.LimitReader(response.Body, 1024))
+		return fmt.Errorf("SendGrid responded with status %d: %s", response.StatusCode, detail)
+	}
+
+	return nil
+}
@@ -0,0 +1,194 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The SMTPSender type sends the emails through an SMTP server, upgrading the connection with STARTTLS
+// when the server offers it. The credentials are only sent over TLS.
+type SMTPSender struct {
+	host     string
+	port     int
+	username string
+	password string
+	from     Address
+}
+
+// The function creates a sender authenticating with `username` and `password` on the server at
+// `host`:`port`, anonymous when the username is empty.
+func NewSMTPSender(host string, port int, username string, password string, from Address) (Sender, error) {
+	if host == "" || port == 0 {
+		return nil, errors.New("SMTP host and port are required")
+	}
+	if from.Email == "" {
+		return nil, errors.New("from address is required")
+	}
+
+	return &SMTPSender{
+		host:     host,
+		port:     port,
+		username: username,
+		password: password,
+		from:     from,
+	}, nil
+}
+
+func (sender *SMTPSender) Send(ctx context.Context, message Message) error {
+	data, err := sender.format(message, time.Now())
+	if err != nil {
+		return err
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(sender.host, strconv.Itoa(sender.port)))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	// the SMTP client doesn't take a context, the deadline bounds the whole exchange instead
+	if deadline, ok := ctx.Deadline(); ok {
+		err = conn.SetDeadline(deadline)
+		if err != nil {
+			return err
+		}
+	}
+
+	client, err := smtp.NewClient(conn, sender.host)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		err = client.StartTLS(&tls.Config{ServerName: sender.host})
+		if err != nil {
+			return err
+		}
+	}
+
+	if sender.username != "" {
+		// PlainAuth refuses to send the credentials over an unencrypted connection to a remote server
+		err = client.Auth(smtp.PlainAuth("", sender.username, sender.password, sender.host))
+		if err != nil {
+			return err
+		}
+	}
+
+	err = client.Mail(sender.from.Email)
+	if err != nil {
+		return err
+	}
+	for _, to := range message.To {
+		err = client.Rcpt(to)
+		if err != nil {
+			return err
+		}
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	_, err = writer.Write(data)
+	if err != nil {
+		return err
+	}
+	err = writer.Close()
+	if err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+// The `format` function writes the message as a multipart/alternative MIME email, the plain text part
+// first so that the clients showing HTML prefer the HTML one.
+func (sender *SMTPSender) format(message Message, date time.Time) ([]byte, error) {
+	var body bytes.Buffer
+	parts := multipart.NewWriter(&body)
+
+	for _, part := range []struct {
+		contentType string
+		content     string
+	}{
+		{"text/plain; charset=utf-8", message.Text},
+		{"text/html; charset=utf-8", message.HTML},
+	} {
+		if part.content == "" {
+			continue
+		}
+
+		writer, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		encoder := quotedprintable.NewWriter(writer)
+		_, err = encoder.Write([]byte(part.content))
+		if err != nil {
+			return nil, err
+		}
+		err = encoder.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err := parts.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	messageID, err := randomMessageID(sender.from.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	var data bytes.Buffer
+	fmt.Fprintf(&data, "From: %s\r\n", sender.from)
+	for _, to := range message.To {
+		fmt.Fprintf(&data, "To: %s\r\n", to)
+	}
+	fmt.Fprintf(&data, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", message.Subject))
+	fmt.Fprintf(&data, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	fmt.Fprintf(&data, "Message-ID: %s\r\n", messageID)
+	fmt.Fprintf(&data, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&data, "Content-Type: multipart/alternative; boundary=%q\r\n\r\n", parts.Boundary())
+	data.Write(body.Bytes())
+
+	return data.Bytes(), nil
+}
+
+// The `randomMessageID` function returns a unique Message-ID in the domain of the from address.
+func randomMessageID(from string) (string, error) {
+	id := make([]byte, 16)
+	_, err := rand.Read(id)
+	if err != nil {
+		return "", err
+	}
+
+	domain := "localhost"
+	if at := strings.LastIndexByte(from, '@'); at >= 0 {
+		domain = from[at+1:]
+	}
+
+	return fmt.Sprintf("<%x@%s>", id, domain), nil
+}
@@ -0,0 +1,99 @@
+package mail
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	htmltemplate "html/template"
+	"path"
+	"strings"
+	texttemplate "text/template"
+	"time"
+)
+
+// Templates of the emails, each made of an HTML page in templates/<name>.html, rendered within the
+// layout, and of a templates/<name>.txt file defining the subject and the plain text body.
+const (
+	TemplateNotification = "notification"
+)
+
+//go:embed templates
+var templateFiles embed.FS
+
+var templateFuncs = map[string]interface{}{
+	// drops the sign of an amount, the amounts sent being negative
+	"unsigned": func(amount interface{}) string {
+		return strings.TrimPrefix(fmt.Sprint(amount), "-")
+	},
+}
+
+var (
+	htmlTemplates = map[string]*htmltemplate.Template{}
+	textTemplates = map[string]*texttemplate.Template{}
+)
+
+func init() {
+	layout := htmltemplate.Must(htmltemplate.New("layout.html").Funcs(templateFuncs).ParseFS(templateFiles, "templates/layout.html"))
+
+	for _, name := range []string{TemplateNotification} {
+		text := texttemplate.Must(texttemplate.New(name).Funcs(templateFuncs).ParseFS(templateFiles, path.Join("templates", name+".txt")))
+		textTemplates[name] = text
+
+		html := htmltemplate.Must(htmltemplate.Must(layout.Clone()).ParseFS(templateFiles, path.Join("templates", name+".html")))
+		// the title of the page is the subject, the copy being escaped for HTML
+		htmltemplate.Must(html.AddParseTree("subject", text.Lookup("subject").Tree.Copy()))
+		htmlTemplates[name] = html
+	}
+}
+
+// The NotificationData type is rendered by the notification template.
+// @property {string} Type - the type of the notification, e.g. transfer.received.
+// @property Payload - the payload of the notification, its numbers decoded as json.Number to be shown
+// as they are by NewNotificationData.
+type NotificationData struct {
+	Type      string
+	Payload   map[string]interface{}
+	CreatedAt time.Time
+}
+
+// The function decodes the json payload of a notification, keeping its numbers as they are rather than
+// turning them into floats.
+func NewNotificationData(notificationType string, payload json.RawMessage, createdAt time.Time) (NotificationData, error) {
+	data := NotificationData{
+		Type:      notificationType,
+		CreatedAt: createdAt,
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.UseNumber()
+	err := decoder.Decode(&data.Payload)
+	return data, err
+}
+
+// The Render function renders the email of the template for the data, the recipients being left to the
+// caller.
+func Render(name string, data interface{}) (Message, error) {
+	var subject, text, html bytes.Buffer
+
+	err := textTemplates[name].ExecuteTemplate(&subject, "subject", data)
+	if err != nil {
+		return Message{}, err
+	}
+
+	err = textTemplates[name].ExecuteTemplate(&text, "text", data)
+	if err != nil {
+		return Message{}, err
+	}
+
+	err = htmlTemplates[name].ExecuteTemplate(&html, "layout.html", data)
+	if err != nil {
+		return Message{}, err
+	}
+
+	return Message{
+		Subject: strings.TrimSpace(subject.String()),
+		HTML:    html.String(),
+		Text:    strings.TrimSpace(text.String()),
+	}, nil
+}
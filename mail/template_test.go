@@ -0,0 +1,112 @@
+package mail
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderNotification(t *testing.T) {
+	createdAt := time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name     string
+		kind     string
+		payload  string
+		subject  string
+		contains []string
+	}{
+		{
+			name:     "TransferReceived",
+			kind:     "transfer.received",
+			payload:  `{"account_id":7,"amount":2500000,"balance":2500100,"currency":"USD","counterparty_owner":"alice","memo":""}`,
+			subject:  "You received 2500000 USD",
+			contains: []string{"account #7 from alice", "balance is now 2500100 USD"},
+		},
+		{
+			name:     "TransferSent",
+			kind:     "transfer.sent",
+			payload:  `{"account_id":7,"amount":-10,"balance":90,"currency":"EUR","counterparty_owner":"bob","memo":""}`,
+			subject:  "You sent 10 EUR",
+			contains: []string{"from account #7 to bob"},
+		},
+		{
+			name:     "LowBalance",
+			kind:     "account.low_balance",
+			payload:  `{"account_id":7,"balance":40,"threshold":50,"currency":"USD"}`,
+			subject:  "Low balance on account #7",
+			contains: []string{"below your alert threshold of 50 USD"},
+		},
+		{
+			name:     "BudgetExceeded",
+			kind:     "budget.exceeded",
+			payload:  `{"account_id":7,"category":"groceries","spent":120,"monthly_limit":100,"currency":"USD"}`,
+			subject:  "Budget exceeded for groceries",
+			contains: []string{"on groceries from account #7", "over your budget of 100 USD"},
+		},
+		{
+			name:     "ExternalTransferFailed",
+			kind:     "external_transfer.updated",
+			payload:  `{"account_id":7,"rail":"ach","amount":50,"currency":"USD","beneficiary_name":"Bob","status":"failed","failure_reason":"account closed at the receiving bank"}`,
+			subject:  "Your transfer to Bob: failed",
+			contains: []string{"from account #7 to Bob is now", "returned to your account: account closed at the receiving bank"},
+		},
+		{
+			name:     "NewDevice",
+			kind:     "session.new_device",
+			payload:  `{"user_agent":"curl/8.0","client_ip":"10.0.0.1"}`,
+			subject:  "New sign-in to your account",
+			contains: []string{"curl/8.0 (10.0.0.1)"},
+		},
+		{
+			name:     "UserLocked",
+			kind:     "user.locked",
+			payload:  `{"username":"alice","client_ip":"10.0.0.1","failures":5,"locked_until":"2026-10-16T10:00:00Z"}`,
+			subject:  "Your account has been locked",
+			contains: []string{"locked until 2026-10-16T10:00:00Z after 5 failed sign-ins", "from 10.0.0.1"},
+		},
+		{
+			name:     "UnknownType",
+			kind:     "account.frozen",
+			payload:  `{}`,
+			subject:  "Notification from Go Bank",
+			contains: []string{"account.frozen"},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := NewNotificationData(tc.kind, json.RawMessage(tc.payload), createdAt)
+			require.NoError(t, err)
+
+			message, err := Render(TemplateNotification, data)
+			require.NoError(t, err)
+
+			require.Equal(t, tc.subject, message.Subject)
+			require.Contains(t, message.HTML, "<title>"+tc.subject+"</title>")
+			require.Contains(t, message.HTML, "16 Oct 2026 09:30 UTC")
+			for _, text := range tc.contains {
+				require.Contains(t, message.HTML, text)
+				require.Contains(t, message.Text, text)
+			}
+		})
+	}
+}
+
+func TestRenderNotificationEscapesHTML(t *testing.T) {
+	payload := `{"account_id":7,"amount":1,"balance":1,"currency":"USD","counterparty_owner":"alice","memo":"<script>alert(1)</script>"}`
+	data, err := NewNotificationData("transfer.received", json.RawMessage(payload), time.Now())
+	require.NoError(t, err)
+
+	message, err := Render(TemplateNotification, data)
+	require.NoError(t, err)
+
+	require.NotContains(t, message.HTML, "<script>")
+	require.Contains(t, message.HTML, "&lt;script&gt;")
+	// the plain text body isn't escaped
+	require.NotContains(t, message.Text, "&lt;")
+}
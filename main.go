@@ -1,104 +1,7 @@
 package main
 
-import (
-	"context"
-	"database/sql"
-	"go-backend/api"
-	db "go-backend/db/sqlc"
-	"go-backend/gapi"
-	"go-backend/pb"
-	"go-backend/util"
-	"log"
-	"net"
-	"net/http"
-
-	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
-	_ "github.com/lib/pq"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/reflection"
-)
+import "go-backend/cmd"
 
 func main() {
-	config, err := util.LoadConfig("app.env")
-	if err != nil {
-		log.Fatal("cannot load config: ", err)
-	}
-
-	conn, err := sql.Open(config.DBDriver, config.DBSource)
-
-	if err != nil {
-		log.Fatal("cannot connect to db: ", err)
-	}
-
-	store := db.NewStore(conn)
-
-	// runHTTPServer(config, store)
-	go runGatewayServer(config, store)
-	runGRPCServer(config, store)
-}
-
-func runGRPCServer(config util.Config, store db.Store) {
-	server, err := gapi.NewServer(config, store)
-	if err != nil {
-		log.Fatal("cannot create server: ", err)
-	}
-
-	grpcServer := grpc.NewServer()
-	pb.RegisterSimpleBankServer(grpcServer, server)
-	reflection.Register(grpcServer)
-
-	listener, err := net.Listen("tcp", config.GRPCServerAddress)
-	if err != nil {
-		log.Fatal("cannot create listener: ", err)
-	}
-
-	log.Println("starting gRPC server at ", listener.Addr().String())
-	err = grpcServer.Serve(listener)
-	if err != nil {
-		log.Fatal("cannot start gRPC server", err)
-	}
-}
-
-func runGatewayServer(config util.Config, store db.Store) {
-	server, err := gapi.NewServer(config, store)
-	if err != nil {
-		log.Fatal("cannot create server: ", err)
-	}
-
-	grpcMux := runtime.NewServeMux()
-
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-
-	err = pb.RegisterSimpleBankHandlerServer(ctx, grpcMux, server)
-	if err != nil {
-		log.Fatal("cannot register handler server: ", err)
-	}
-
-	mux := http.NewServeMux()
-	mux.Handle("/", grpcMux)
-
-	listener, err := net.Listen("tcp", config.ServerAddress)
-	if err != nil {
-		log.Fatal("cannot create listener: ", err)
-	}
-
-	log.Println("starting HTTP gateway server at ", listener.Addr().String())
-	err = http.Serve(listener, mux)
-	if err != nil {
-		log.Fatal("cannot start HTTP gateway server", err)
-	}
-}
-
-func runHTTPServer(config util.Config, store db.Store) {
-	server, err := api.NewServer(config, store)
-	if err != nil {
-		log.Fatal("cannot create server: ", err)
-	}
-
-	err = server.Start(config.ServerAddress)
-
-	if err != nil {
-		log.Fatal("cannot run server: ", err)
-	}
+	cmd.Execute()
 }
@@ -0,0 +1,87 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+)
+
+const (
+	gitHubAuthURL  = "https://github.com/login/oauth/authorize"
+	gitHubTokenURL = "https://github.com/login/oauth/access_token"
+	gitHubAPIURL   = "https://api.github.com"
+)
+
+// The GitHubProvider type logs users in with their GitHub account. GitHub isn't an OpenID Connect
+// provider: the identity is read from its REST API, the email being the primary one of the user.
+type GitHubProvider struct {
+	client
+	apiURL string
+}
+
+// The function creates the provider of the GitHub OAuth app `clientID`, which sends the users back to
+// `redirectURL`.
+func NewGitHubProvider(clientID string, clientSecret string, redirectURL string) *GitHubProvider {
+	return &GitHubProvider{
+		client: client{
+			clientID:     clientID,
+			clientSecret: clientSecret,
+			redirectURL:  redirectURL,
+			authURL:      gitHubAuthURL,
+			tokenURL:     gitHubTokenURL,
+			scopes:       []string{"read:user", "user:email"},
+			http:         &http.Client{Timeout: providerTimeout},
+		},
+		apiURL: gitHubAPIURL,
+	}
+}
+
+type gitHubUser struct {
+	ID    int64  `json:"id"`
+	Login string `json:"login"`
+	Name  string `json:"name"`
+}
+
+type gitHubEmail struct {
+	Email    string `json:"email"`
+	Primary  bool   `json:"primary"`
+	Verified bool   `json:"verified"`
+}
+
+func (provider *GitHubProvider) Exchange(ctx context.Context, code string) (Identity, error) {
+	accessToken, err := provider.exchange(ctx, code)
+	if err != nil {
+		return Identity{}, err
+	}
+
+	var user gitHubUser
+	err = provider.get(ctx, provider.apiURL+"/user", accessToken, &user)
+	if err != nil {
+		return Identity{}, err
+	}
+	if user.ID == 0 {
+		return Identity{}, errors.New("the user of GitHub has no id")
+	}
+
+	// the email of the profile is the public one, which may be unset or unverified
+	var emails []gitHubEmail
+	err = provider.get(ctx, provider.apiURL+"/user/emails", accessToken, &emails)
+	if err != nil {
+		return Identity{}, err
+	}
+
+	identity := Identity{
+		Provider: ProviderGitHub,
+		Subject:  strconv.FormatInt(user.ID, 10),
+		Name:     user.Name,
+		Login:    user.Login,
+	}
+	for _, email := range emails {
+		if email.Primary {
+			identity.Email = email.Email
+			identity.EmailVerified = email.Verified
+		}
+	}
+	return identity, nil
+}
@@ -0,0 +1,68 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+const (
+	googleAuthURL     = "https://accounts.google.com/o/oauth2/v2/auth"
+	googleTokenURL    = "https://oauth2.googleapis.com/token"
+	googleUserInfoURL = "https://openidconnect.googleapis.com/v1/userinfo"
+)
+
+// The GoogleProvider type logs users in with their Google account over OpenID Connect, their identity
+// being read from the userinfo endpoint.
+type GoogleProvider struct {
+	client
+	userInfoURL string
+}
+
+// The function creates the provider of the Google OAuth client `clientID`, which sends the users back
+// to `redirectURL`.
+func NewGoogleProvider(clientID string, clientSecret string, redirectURL string) *GoogleProvider {
+	return &GoogleProvider{
+		client: client{
+			clientID:     clientID,
+			clientSecret: clientSecret,
+			redirectURL:  redirectURL,
+			authURL:      googleAuthURL,
+			tokenURL:     googleTokenURL,
+			scopes:       []string{"openid", "email", "profile"},
+			http:         &http.Client{Timeout: providerTimeout},
+		},
+		userInfoURL: googleUserInfoURL,
+	}
+}
+
+type googleUserInfo struct {
+	Subject       string `json:"sub"`
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	Name          string `json:"name"`
+}
+
+func (provider *GoogleProvider) Exchange(ctx context.Context, code string) (Identity, error) {
+	accessToken, err := provider.exchange(ctx, code)
+	if err != nil {
+		return Identity{}, err
+	}
+
+	var info googleUserInfo
+	err = provider.get(ctx, provider.userInfoURL, accessToken, &info)
+	if err != nil {
+		return Identity{}, err
+	}
+	if info.Subject == "" {
+		return Identity{}, errors.New("the userinfo of Google has no subject")
+	}
+
+	return Identity{
+		Provider:      ProviderGoogle,
+		Subject:       info.Subject,
+		Email:         info.Email,
+		EmailVerified: info.EmailVerified,
+		Name:          info.Name,
+	}, nil
+}
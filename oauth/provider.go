@@ -0,0 +1,160 @@
+// Package oauth logs users in with their account at an identity provider such as Google or GitHub,
+// following the authorization code flow of OAuth 2.0: the user is sent to the provider, which sends
+// them back with a code the bank exchanges for a token reading who they are.
+package oauth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-backend/util"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Names of the providers, which are enabled by setting the client id and secret of their config.
+const (
+	ProviderGoogle = "google"
+	ProviderGitHub = "github"
+)
+
+const providerTimeout = 10 * time.Second
+
+// The Identity type is a user as their identity provider knows them.
+// @property {string} Subject - the id of the user at the provider, which never changes unlike their
+// email.
+// @property {bool} EmailVerified - whether the provider checked that the user owns the email.
+// @property {string} Login - the handle of the user at the provider, if any, to suggest a username.
+type Identity struct {
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+	Login         string
+}
+
+// The Provider interface is an identity provider users log in with.
+type Provider interface {
+	// AuthCodeURL returns the page of the provider the user is sent to, carrying the `state` sent back
+	// along with the code.
+	AuthCodeURL(state string) string
+	// Exchange exchanges the code the provider sent the user back with for their identity.
+	Exchange(ctx context.Context, code string) (Identity, error)
+}
+
+// The Providers type holds the enabled providers by name.
+type Providers map[string]Provider
+
+// The function creates the providers with a client id in the config, `callbackURL` returning the URL
+// of the bank each provider sends the users back to.
+func NewProviders(config util.Config, callbackURL func(provider string) string) Providers {
+	providers := Providers{}
+	if config.GoogleClientID != "" {
+		providers[ProviderGoogle] = NewGoogleProvider(config.GoogleClientID, config.GoogleClientSecret, callbackURL(ProviderGoogle))
+	}
+	if config.GitHubClientID != "" {
+		providers[ProviderGitHub] = NewGitHubProvider(config.GitHubClientID, config.GitHubClientSecret, callbackURL(ProviderGitHub))
+	}
+	return providers
+}
+
+// The client type is the OAuth 2.0 client of the bank at a provider.
+type client struct {
+	clientID     string
+	clientSecret string
+	redirectURL  string
+	authURL      string
+	tokenURL     string
+	scopes       []string
+	http         *http.Client
+}
+
+func (client *client) AuthCodeURL(state string) string {
+	query := url.Values{
+		"response_type": {"code"},
+		"client_id":     {client.clientID},
+		"redirect_uri":  {client.redirectURL},
+		"scope":         {strings.Join(client.scopes, " ")},
+		"state":         {state},
+	}
+	return client.authURL + "?" + query.Encode()
+}
+
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// exchange exchanges the code for an access token, authenticating the bank with its client secret.
+func (client *client) exchange(ctx context.Context, code string) (string, error) {
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {client.redirectURL},
+		"client_id":     {client.clientID},
+		"client_secret": {client.clientSecret},
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, client.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	// GitHub responds with a form unless asked for json
+	request.Header.Set("Accept", "application/json")
+
+	var token tokenResponse
+	err = client.do(request, &token)
+	if err != nil {
+		return "", fmt.Errorf("cannot exchange the code: %w", err)
+	}
+	// GitHub reports the invalid codes with a 200 status
+	if token.Error != "" {
+		return "", fmt.Errorf("cannot exchange the code: %s: %s", token.Error, token.ErrorDescription)
+	}
+	if token.AccessToken == "" {
+		return "", errors.New("cannot exchange the code: no access token")
+	}
+
+	return token.AccessToken, nil
+}
+
+// get reads the json resource at `url` on behalf of the user with their access token.
+func (client *client) get(ctx context.Context, url string, accessToken string, v interface{}) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Authorization", "Bearer "+accessToken)
+	request.Header.Set("Accept", "application/json")
+
+	return client.do(request, v)
+}
+
+func (client *client) do(request *http.Request, v interface{}) error {
+	response, err := client.http.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(response.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		if len(body) > 1024 {
+			body = body[:1024]
+		}
+		return fmt.Errorf("%s responded with status %d: %s", request.URL.Host, response.StatusCode, bytes.TrimSpace(body))
+	}
+
+	return json.Unmarshal(body, v)
+}
@@ -0,0 +1,150 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"go-backend/util"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testCode        = "code"
+	testAccessToken = "access-token"
+)
+
+// newTestProviderServer serves the token endpoint, exchanging testCode for testAccessToken, along with
+// the resources of `resources` for the holder of the token.
+func newTestProviderServer(t *testing.T, resources map[string]interface{}) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.NoError(t, r.ParseForm())
+		require.Equal(t, "authorization_code", r.PostForm.Get("grant_type"))
+		require.Equal(t, "client-id", r.PostForm.Get("client_id"))
+		require.Equal(t, "client-secret", r.PostForm.Get("client_secret"))
+		require.Equal(t, "https://bank.example.com/callback", r.PostForm.Get("redirect_uri"))
+
+		w.Header().Set("Content-Type", "application/json")
+		if r.PostForm.Get("code") != testCode {
+			// like GitHub, which reports the errors with a 200 status
+			json.NewEncoder(w).Encode(tokenResponse{Error: "bad_verification_code", ErrorDescription: "The code is incorrect."})
+			return
+		}
+		json.NewEncoder(w).Encode(tokenResponse{AccessToken: testAccessToken})
+	})
+	for path, resource := range resources {
+		resource := resource
+		mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer "+testAccessToken {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(resource)
+		})
+	}
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestNewProviders(t *testing.T) {
+	callbackURL := func(provider string) string {
+		return "https://bank.example.com/api/v1/auth/" + provider + "/callback"
+	}
+
+	providers := NewProviders(util.Config{}, callbackURL)
+	require.Empty(t, providers)
+
+	providers = NewProviders(util.Config{GitHubClientID: "client-id", GitHubClientSecret: "client-secret"}, callbackURL)
+	require.Len(t, providers, 1)
+	require.IsType(t, &GitHubProvider{}, providers[ProviderGitHub])
+}
+
+func TestAuthCodeURL(t *testing.T) {
+	provider := NewGoogleProvider("client-id", "client-secret", "https://bank.example.com/callback")
+
+	authURL, err := url.Parse(provider.AuthCodeURL("state"))
+	require.NoError(t, err)
+	require.Equal(t, "accounts.google.com", authURL.Host)
+
+	query := authURL.Query()
+	require.Equal(t, "code", query.Get("response_type"))
+	require.Equal(t, "client-id", query.Get("client_id"))
+	require.Equal(t, "https://bank.example.com/callback", query.Get("redirect_uri"))
+	require.Equal(t, "openid email profile", query.Get("scope"))
+	require.Equal(t, "state", query.Get("state"))
+}
+
+func TestGoogleExchange(t *testing.T) {
+	server := newTestProviderServer(t, map[string]interface{}{
+		"/userinfo": googleUserInfo{
+			Subject:       "110169484474386276334",
+			Email:         "jane@example.com",
+			EmailVerified: true,
+			Name:          "Jane Doe",
+		},
+	})
+
+	provider := NewGoogleProvider("client-id", "client-secret", "https://bank.example.com/callback")
+	provider.tokenURL = server.URL + "/token"
+	provider.userInfoURL = server.URL + "/userinfo"
+
+	identity, err := provider.Exchange(context.Background(), testCode)
+	require.NoError(t, err)
+	require.Equal(t, Identity{
+		Provider:      ProviderGoogle,
+		Subject:       "110169484474386276334",
+		Email:         "jane@example.com",
+		EmailVerified: true,
+		Name:          "Jane Doe",
+	}, identity)
+
+	_, err = provider.Exchange(context.Background(), "wrong")
+	require.ErrorContains(t, err, "bad_verification_code")
+}
+
+func TestGitHubExchange(t *testing.T) {
+	server := newTestProviderServer(t, map[string]interface{}{
+		"/user": gitHubUser{ID: 583231, Login: "octocat", Name: "The Octocat"},
+		"/user/emails": []gitHubEmail{
+			{Email: "octocat@users.noreply.github.com", Verified: true},
+			{Email: "octocat@github.com", Primary: true, Verified: true},
+		},
+	})
+
+	provider := NewGitHubProvider("client-id", "client-secret", "https://bank.example.com/callback")
+	provider.tokenURL = server.URL + "/token"
+	provider.apiURL = server.URL
+
+	identity, err := provider.Exchange(context.Background(), testCode)
+	require.NoError(t, err)
+	require.Equal(t, Identity{
+		Provider:      ProviderGitHub,
+		Subject:       "583231",
+		Email:         "octocat@github.com",
+		EmailVerified: true,
+		Name:          "The Octocat",
+		Login:         "octocat",
+	}, identity)
+}
+
+func TestExchangeProviderError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte("unavailable"))
+	}))
+	defer server.Close()
+
+	provider := NewGoogleProvider("client-id", "client-secret", "https://bank.example.com/callback")
+	provider.tokenURL = server.URL + "/token"
+
+	_, err := provider.Exchange(context.Background(), testCode)
+	require.ErrorContains(t, err, "responded with status 503: unavailable")
+}
@@ -0,0 +1,413 @@
+package service
+
+import (
+	"context"
+	"errors"
+	db "go-backend/db/sqlc"
+	"go-backend/statement"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// The CreateAccount function opens an empty account in the given currency for the owner.
+func (service *Service) CreateAccount(ctx context.Context, owner string, currency string) (db.Account, error) {
+	account, err := service.store.CreateAccount(ctx, db.CreateAccountParams{
+		Owner:    owner,
+		Currency: currency,
+		Balance:  0,
+	})
+	if err != nil {
+		return account, storeError(err)
+	}
+
+	return account, nil
+}
+
+// The GetAccount function returns an account, provided it belongs to the owner or was shared with them,
+// whatever their role.
+func (service *Service) GetAccount(ctx context.Context, owner string, id int64) (db.Account, error) {
+	account, _, err := service.accountWithRole(ctx, owner, id)
+	return account, err
+}
+
+// The ownedAccount function returns an account the owner can make transactions with, i.e. one belonging
+// to them or shared with them as an owner rather than a viewer.
+func (service *Service) ownedAccount(ctx context.Context, owner string, id int64) (db.Account, error) {
+	account, role, err := service.accountWithRole(ctx, owner, id)
+	if err != nil {
+		return account, err
+	}
+
+	if role != db.AccountRoleOwner {
+		return account, newError(CodePermissionDenied, errors.New("authenticated user can only view the account"))
+	}
+
+	return account, nil
+}
+
+// The accountWithRole function returns an account along with the role of the user on it, the accounts
+// that aren't theirs being denied.
+func (service *Service) accountWithRole(ctx context.Context, username string, id int64) (db.Account, string, error) {
+	account, err := service.store.GetAccount(ctx, id)
+	if err != nil {
+		return account, "", storeError(err)
+	}
+
+	role, err := db.AccountRole(ctx, service.store, account, username)
+	if err != nil {
+		return account, "", storeError(err)
+	}
+	if role == "" {
+		return account, "", newError(CodePermissionDenied, errors.New("account doesn't belong to authenticated user"))
+	}
+
+	return account, role, nil
+}
+
+// MaxBatchGetAccounts is the largest number of accounts got at once.
+const MaxBatchGetAccounts = 100
+
+// The GetAccounts function returns the accounts among the given ids that belong to the owner or were
+// shared with them, by id. The
+// ids of missing accounts and of accounts of other users are skipped rather than failing the request, so
+// that a client can render a list of transfers with a single call.
+func (service *Service) GetAccounts(ctx context.Context, owner string, ids []int64) ([]db.Account, error) {
+	if len(ids) == 0 || len(ids) > MaxBatchGetAccounts {
+		return nil, errorf(CodeInvalidArgument, "between 1 and %d accounts can be got at once, got %d", MaxBatchGetAccounts, len(ids))
+	}
+
+	accounts, err := service.store.GetAccountsByIDs(ctx, db.GetAccountsByIDsParams{
+		Owner: owner,
+		Ids:   ids,
+	})
+	if err != nil {
+		return nil, storeError(err)
+	}
+
+	return accounts, nil
+}
+
+// The ListAccountsParams type holds the filters, sort and page of an account listing.
+// @property {string} Owner - the user whose accounts are listed.
+// @property {string} Currency - only list the accounts in this currency when it is set.
+// @property {*int64} MinBalance - only list the accounts with at least this balance when it is set.
+// @property {string} SortBy - one of balance, created_at or currency, accounts are listed by id otherwise.
+// @property {bool} SortDesc - sort in descending order.
+type ListAccountsParams struct {
+	Owner      string
+	Currency   string
+	MinBalance *int64
+	SortBy     string
+	SortDesc   bool
+	Limit      int32
+	Offset     int32
+}
+
+// The ListAccounts function lists the accounts of an owner, including the accounts shared with them once
+// they accepted the invitation. Filtering and sorting happen in the
+// database so that pages stay consistent.
+func (service *Service) ListAccounts(ctx context.Context, arg ListAccountsParams) ([]db.Account, error) {
+	params := db.SearchAccountsParams{
+		Owner:     arg.Owner,
+		Currency:  pgtype.Text{String: arg.Currency, Valid: arg.Currency != ""},
+		SortBy:    arg.SortBy,
+		SortDesc:  arg.SortDesc,
+		RowLimit:  arg.Limit,
+		RowOffset: arg.Offset,
+	}
+	if arg.MinBalance != nil {
+		params.MinBalance = pgtype.Int8{Int64: *arg.MinBalance, Valid: true}
+	}
+
+	accounts, err := service.store.SearchAccounts(ctx, params)
+	if err != nil {
+		return nil, storeError(err)
+	}
+
+	return accounts, nil
+}
+
+// The AdjustAccountParams type is a correction an admin makes to the balance of an account.
+// @property {int64} AccountID - the account whose balance is adjusted.
+// @property {int64} Version - the version of the account the admin read, 0 whatever its version.
+// @property {int64} Amount - the amount credited to the account, debited from it when negative.
+// @property {string} Memo - why the balance is adjusted, kept on the transfer.
+type AdjustAccountParams struct {
+	AccountID int64
+	Version   int64
+	Amount    int64
+	Memo      string
+}
+
+// The AdjustAccount function corrects the balance of an account with a transfer from the suspense
+// account of its currency, to it for a debit, so that the ledger stays balanced. It is reserved to
+// admins. The account must still be at the version the admin read, so that concurrent updates aren't
+// lost, and a debit can't exceed its available balance.
+func (service *Service) AdjustAccount(ctx context.Context, arg AdjustAccountParams) (db.TransferTxResult, error) {
+	if arg.Amount == 0 {
+		return db.TransferTxResult{}, errorf(CodeInvalidArgument, "amount must not be zero")
+	}
+	if strings.TrimSpace(arg.Memo) == "" {
+		return db.TransferTxResult{}, errorf(CodeInvalidArgument, "memo must explain the adjustment")
+	}
+
+	result, err := service.store.AdjustAccountBalanceTx(ctx, db.AdjustAccountBalanceTxParams{
+		ID:      arg.AccountID,
+		Amount:  arg.Amount,
+		Version: arg.Version,
+		Memo:    arg.Memo,
+	})
+	if errors.Is(err, db.ErrAccountVersionMismatch) {
+		return result, errorf(CodeFailedPrecondition, "account %d was updated since version %d", arg.AccountID, arg.Version).withReason(ReasonVersionMismatch)
+	}
+	if err != nil {
+		return result, storeError(err)
+	}
+
+	return result, nil
+}
+
+// The RenameAccount function sets the nickname of an account the owner can make transactions with, an
+// empty nickname removing it. The account must still be at the version the owner read, 0 whatever its
+// version, and the account history keeps the previous nicknames.
+func (service *Service) RenameAccount(ctx context.Context, owner string, accountID int64, version int64, nickname string) (db.Account, error) {
+	account, err := service.ownedAccount(ctx, owner, accountID)
+	if err != nil {
+		return account, err
+	}
+
+	return service.updateAccountProfile(ctx, db.UpdateAccountProfileParams{
+		Nickname: pgtype.Text{String: strings.TrimSpace(nickname), Valid: true},
+		ID:       account.ID,
+		Version:  version,
+	})
+}
+
+// The SetAccountTier function moves an account to another tier. It is reserved to admins, the account
+// must still be at the version the admin read, 0 whatever its version, and the account history keeps
+// the previous tiers.
+func (service *Service) SetAccountTier(ctx context.Context, accountID int64, version int64, tier string) (db.Account, error) {
+	if tier != db.AccountTierStandard && tier != db.AccountTierPremium {
+		return db.Account{}, errorf(CodeInvalidArgument, "unsupported account tier %q", tier)
+	}
+
+	return service.updateAccountProfile(ctx, db.UpdateAccountProfileParams{
+		Tier:    pgtype.Text{String: tier, Valid: true},
+		ID:      accountID,
+		Version: version,
+	})
+}
+
+// The updateAccountProfile function changes the nickname or the tier of an account, failing the
+// precondition when the account was updated since the version read.
+func (service *Service) updateAccountProfile(ctx context.Context, arg db.UpdateAccountProfileParams) (db.Account, error) {
+	account, err := service.store.UpdateAccountProfile(ctx, arg)
+	if errors.Is(err, db.ErrAccountVersionMismatch) {
+		return account, errorf(CodeFailedPrecondition, "account %d was updated since version %d", arg.ID, arg.Version).withReason(ReasonVersionMismatch)
+	}
+	if err != nil {
+		return account, storeError(err)
+	}
+
+	return account, nil
+}
+
+// The DeleteAccount function deletes an account. Accounts with entries or transfers can't be deleted,
+// as that would erase the history of the other accounts too, so their balance should be swept to
+// another account with a transfer instead.
+func (service *Service) DeleteAccount(ctx context.Context, id int64) error {
+	hasHistory, err := service.store.AccountHasHistory(ctx, id)
+	if err != nil {
+		return storeError(err)
+	}
+	if hasHistory {
+		return accountHistoryError(id)
+	}
+
+	err = service.store.DeleteAccount(ctx, id)
+	if err != nil {
+		// entries or transfers made since the check still prevent the deletion
+		if errors.Is(db.TranslateError(err), db.ErrForeignKeyViolation) {
+			return accountHistoryError(id)
+		}
+		return storeError(err)
+	}
+
+	return nil
+}
+
+func accountHistoryError(id int64) error {
+	return errorf(CodeFailedPrecondition, "account %d has entries or transfers and can't be deleted, transfer its balance to another account instead", id).withReason(ReasonAccountHasHistory)
+}
+
+// The ListAccountHistory function lists the successive values of the fields of an account, oldest first,
+// for support and compliance investigations. The history of a deleted account is kept, so the account
+// isn't read, but an id without any history is not found.
+func (service *Service) ListAccountHistory(ctx context.Context, accountID int64, limit int32, offset int32) ([]db.AccountHistory, error) {
+	versions, err := service.store.ListAccountHistory(ctx, db.ListAccountHistoryParams{
+		AccountID: accountID,
+		Limit:     limit,
+		Offset:    offset,
+	})
+	if err != nil {
+		return nil, storeError(err)
+	}
+	if len(versions) == 0 && offset == 0 {
+		return nil, errorf(CodeNotFound, "account %d has no history", accountID)
+	}
+
+	return versions, nil
+}
+
+// The ListEntries function lists the entries of an account belonging to the owner, oldest first, with
+// the counterparty of the transfer each was made for.
+func (service *Service) ListEntries(ctx context.Context, owner string, accountID int64, limit int32, offset int32) ([]db.ListEntriesWithCounterpartyRow, error) {
+	account, err := service.GetAccount(ctx, owner, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := service.store.ListEntriesWithCounterparty(ctx, db.ListEntriesWithCounterpartyParams{
+		AccountID: account.ID,
+		Limit:     limit,
+		Offset:    offset,
+	})
+	if err != nil {
+		return nil, storeError(err)
+	}
+
+	return entries, nil
+}
+
+// The ListEntriesBefore function lists the entries of an account belonging to the owner like ListEntries,
+// newest first and a page at a time by their id: the entries made before the cursor, the id of the last
+// entry of the previous page, or the newest ones when the cursor is 0.
+func (service *Service) ListEntriesBefore(ctx context.Context, owner string, accountID int64, cursor int64, limit int32) ([]db.ListEntriesWithCounterpartyRow, error) {
+	account, err := service.GetAccount(ctx, owner, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := service.store.ListEntriesWithCounterpartyBefore(ctx, db.ListEntriesWithCounterpartyBeforeParams{
+		AccountID: account.ID,
+		Cursor:    pgtype.Int8{Int64: cursor, Valid: cursor != 0},
+		RowLimit:  limit,
+	})
+	if err != nil {
+		return nil, storeError(err)
+	}
+
+	entries := make([]db.ListEntriesWithCounterpartyRow, 0, len(rows))
+	for _, row := range rows {
+		entries = append(entries, db.ListEntriesWithCounterpartyRow(row))
+	}
+	return entries, nil
+}
+
+// MaxBalanceHistoryDays is the longest period a balance history can cover.
+const MaxBalanceHistoryDays = 366
+
+// The BalanceHistory function lists the closing balances of an account belonging to the owner for the
+// business dates from `from` to `to` included, oldest first. They are read from the snapshots of the
+// end-of-day batch, so the days it didn't close yet are missing.
+func (service *Service) BalanceHistory(ctx context.Context, owner string, accountID int64, from time.Time, to time.Time) ([]db.BalanceSnapshot, error) {
+	if to.Before(from) {
+		return nil, errorf(CodeInvalidArgument, "from %s is after to %s", from.Format("2006-01-02"), to.Format("2006-01-02"))
+	}
+	if to.Sub(from) >= MaxBalanceHistoryDays*24*time.Hour {
+		return nil, errorf(CodeInvalidArgument, "a balance history covers at most %d days", MaxBalanceHistoryDays)
+	}
+
+	account, err := service.GetAccount(ctx, owner, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots, err := service.store.ListBalanceSnapshots(ctx, db.ListBalanceSnapshotsParams{
+		AccountID: account.ID,
+		FromDate:  from,
+		ToDate:    to,
+	})
+	if err != nil {
+		return nil, storeError(err)
+	}
+
+	return snapshots, nil
+}
+
+const (
+	// MaxStatementDays is the longest period a statement can cover.
+	MaxStatementDays = 366
+	// MaxStatementEntries is the most entries a statement can hold, the longer ones being left to the
+	// export jobs.
+	MaxStatementEntries = 5000
+)
+
+// The Statement function returns the transactions of an account belonging to the owner from `from`
+// (inclusive) to `to` (exclusive), oldest first, with the other party and memo of the transfer each was
+// made for, for the personal finance files. The closing balance is the balance of the account less the
+// entries it got since the end of the period.
+func (service *Service) Statement(ctx context.Context, owner string, accountID int64, from time.Time, to time.Time) (statement.Statement, error) {
+	if !from.Before(to) {
+		return statement.Statement{}, errorf(CodeInvalidArgument, "from %s is not before to %s", from.Format(time.RFC3339), to.Format(time.RFC3339))
+	}
+	if to.Sub(from) > MaxStatementDays*24*time.Hour {
+		return statement.Statement{}, errorf(CodeInvalidArgument, "a statement covers at most %d days", MaxStatementDays)
+	}
+
+	account, err := service.GetAccount(ctx, owner, accountID)
+	if err != nil {
+		return statement.Statement{}, err
+	}
+
+	entries, err := service.store.ListEntriesForExport(ctx, db.ListEntriesForExportParams{
+		AccountID: account.ID,
+		FromTime:  from,
+		ToTime:    to,
+		RowLimit:  MaxStatementEntries + 1,
+	})
+	if err != nil {
+		return statement.Statement{}, storeError(err)
+	}
+	if len(entries) > MaxStatementEntries {
+		return statement.Statement{}, errorf(CodeInvalidArgument, "the period has more than %d entries, shorten it or create an entries export job", MaxStatementEntries)
+	}
+
+	since, err := service.store.SumEntriesSince(ctx, db.SumEntriesSinceParams{
+		AccountID: account.ID,
+		CreatedAt: to,
+	})
+	if err != nil {
+		return statement.Statement{}, storeError(err)
+	}
+
+	res := statement.Statement{
+		AccountID:      account.ID,
+		Currency:       account.Currency,
+		From:           from,
+		To:             to,
+		ClosingBalance: account.Balance - since,
+		Transactions:   make([]statement.Transaction, 0, len(entries)),
+	}
+	for _, entry := range entries {
+		payee := entry.CounterpartyFullName.String
+		if payee == "" {
+			payee = entry.CounterpartyUsername.String
+		}
+
+		res.Transactions = append(res.Transactions, statement.Transaction{
+			ID:                    strconv.FormatInt(entry.Entry.ID, 10),
+			PostedAt:              entry.Entry.CreatedAt,
+			Amount:                entry.Entry.Amount,
+			Payee:                 payee,
+			Memo:                  entry.TransferMemo.String,
+			Reference:             entry.TransferExternalReference.String,
+			CounterpartyAccountID: entry.CounterpartyAccountID.Int64,
+		})
+	}
+
+	return res, nil
+}
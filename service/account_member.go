@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"errors"
+	db "go-backend/db/sqlc"
+)
+
+// The InviteAccountMemberParams type is an invitation to share an account with another user.
+// @property {string} Owner - the user inviting, who must be able to use the account as an owner.
+// @property {string} Username - the user invited, who gets access to the account once they accept.
+// @property {string} Role - owner, to let them make transactions with the account, or viewer, to let them
+// read it only.
+type InviteAccountMemberParams struct {
+	Owner     string
+	AccountID int64
+	Username  string
+	Role      string
+}
+
+// The InviteAccountMember function invites a user to share an account of the owner. A user can only be
+// invited once to an account.
+func (service *Service) InviteAccountMember(ctx context.Context, arg InviteAccountMemberParams) (db.AccountMember, error) {
+	if arg.Role != db.AccountRoleOwner && arg.Role != db.AccountRoleViewer {
+		return db.AccountMember{}, errorf(CodeInvalidArgument, "role must be %s or %s, got %q", db.AccountRoleOwner, db.AccountRoleViewer, arg.Role)
+	}
+
+	account, err := service.ownedAccount(ctx, arg.Owner, arg.AccountID)
+	if err != nil {
+		return db.AccountMember{}, err
+	}
+	if account.Owner == arg.Username {
+		return db.AccountMember{}, errorf(CodeAlreadyExists, "%s already owns account [%d]", arg.Username, account.ID)
+	}
+
+	_, err = service.store.GetUser(ctx, arg.Username)
+	if err != nil {
+		return db.AccountMember{}, storeError(err)
+	}
+
+	member, err := service.store.CreateAccountMember(ctx, db.CreateAccountMemberParams{
+		AccountID: account.ID,
+		Username:  arg.Username,
+		Role:      arg.Role,
+		InvitedBy: arg.Owner,
+	})
+	if err != nil {
+		if errors.Is(db.TranslateError(err), db.ErrUniqueViolation) {
+			return member, errorf(CodeAlreadyExists, "%s is already a member of account [%d] or invited to it", arg.Username, account.ID)
+		}
+		return member, storeError(err)
+	}
+
+	return member, nil
+}
+
+// The AcceptAccountInvitation function accepts the pending invitation of the user to an account, which
+// they can use with the role they were invited with from then on.
+func (service *Service) AcceptAccountInvitation(ctx context.Context, username string, accountID int64) (db.AccountMember, error) {
+	member, err := service.store.AcceptAccountMember(ctx, db.AcceptAccountMemberParams{
+		AccountID: accountID,
+		Username:  username,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return member, errorf(CodeNotFound, "no pending invitation to account [%d]", accountID)
+		}
+		return member, storeError(err)
+	}
+
+	return member, nil
+}
+
+// The ListAccountInvitations function lists the pending invitations of the user, oldest first.
+func (service *Service) ListAccountInvitations(ctx context.Context, username string, limit int32, offset int32) ([]db.AccountMember, error) {
+	invitations, err := service.store.ListAccountInvitations(ctx, db.ListAccountInvitationsParams{
+		Username: username,
+		Limit:    limit,
+		Offset:   offset,
+	})
+	if err != nil {
+		return nil, storeError(err)
+	}
+
+	return invitations, nil
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/testutil/factory"
+	"go-backend/util"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInviteAccountMember(t *testing.T) {
+	owner := util.RandomOwner()
+	account := factory.Account(factory.OwnedBy(owner))
+	invitee := factory.User()
+
+	testCases := []struct {
+		name      string
+		arg       InviteAccountMemberParams
+		buildStub func(store *mockdb.MockStore)
+		code      *Code
+	}{
+		{
+			name: "OK",
+			arg:  InviteAccountMemberParams{Owner: owner, AccountID: account.ID, Username: invitee.Username, Role: db.AccountRoleViewer},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(invitee.Username)).Times(1).Return(invitee, nil)
+				store.EXPECT().
+					CreateAccountMember(gomock.Any(), gomock.Eq(db.CreateAccountMemberParams{
+						AccountID: account.ID,
+						Username:  invitee.Username,
+						Role:      db.AccountRoleViewer,
+						InvitedBy: owner,
+					})).
+					Times(1)
+			},
+		},
+		{
+			name: "InvalidRole",
+			arg:  InviteAccountMemberParams{Owner: owner, AccountID: account.ID, Username: invitee.Username, Role: "admin"},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code: codePtr(CodeInvalidArgument),
+		},
+		{
+			name: "Viewer",
+			arg:  InviteAccountMemberParams{Owner: invitee.Username, AccountID: account.ID, Username: util.RandomOwner(), Role: db.AccountRoleOwner},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().
+					GetAccountMember(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.AccountMember{Role: db.AccountRoleViewer, AcceptedAt: acceptedNow()}, nil)
+				store.EXPECT().CreateAccountMember(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code: codePtr(CodePermissionDenied),
+		},
+		{
+			name: "Owner",
+			arg:  InviteAccountMemberParams{Owner: owner, AccountID: account.ID, Username: owner, Role: db.AccountRoleOwner},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().CreateAccountMember(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code: codePtr(CodeAlreadyExists),
+		},
+		{
+			name: "UserNotFound",
+			arg:  InviteAccountMemberParams{Owner: owner, AccountID: account.ID, Username: invitee.Username, Role: db.AccountRoleOwner},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(1).Return(db.User{}, db.ErrRecordNotFound)
+				store.EXPECT().CreateAccountMember(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code: codePtr(CodeNotFound),
+		},
+		{
+			name: "AlreadyInvited",
+			arg:  InviteAccountMemberParams{Owner: owner, AccountID: account.ID, Username: invitee.Username, Role: db.AccountRoleOwner},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(1).Return(invitee, nil)
+				store.EXPECT().
+					CreateAccountMember(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.AccountMember{}, &pgconn.PgError{Code: db.UniqueViolation})
+			},
+			code: codePtr(CodeAlreadyExists),
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			_, err := newTestService(t, store).InviteAccountMember(context.Background(), tc.arg)
+			if tc.code == nil {
+				require.NoError(t, err)
+			} else {
+				require.Equal(t, *tc.code, ErrorCode(err))
+			}
+		})
+	}
+}
+
+func TestAcceptAccountInvitation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	service := newTestService(t, store)
+
+	username := util.RandomOwner()
+	arg := db.AcceptAccountMemberParams{AccountID: 42, Username: username}
+	store.EXPECT().
+		AcceptAccountMember(gomock.Any(), gomock.Eq(arg)).
+		Times(1).
+		Return(db.AccountMember{AccountID: 42, Username: username, AcceptedAt: acceptedNow()}, nil)
+
+	member, err := service.AcceptAccountInvitation(context.Background(), username, 42)
+	require.NoError(t, err)
+	require.True(t, member.AcceptedAt.Valid)
+
+	// an invitation is only accepted once
+	store.EXPECT().AcceptAccountMember(gomock.Any(), gomock.Eq(arg)).Times(1).Return(db.AccountMember{}, db.ErrRecordNotFound)
+	_, err = service.AcceptAccountInvitation(context.Background(), username, 42)
+	require.Equal(t, CodeNotFound, ErrorCode(err))
+}
+
+func acceptedNow() pgtype.Timestamptz {
+	return pgtype.Timestamptz{Time: time.Now(), Valid: true}
+}
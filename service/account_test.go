@@ -0,0 +1,280 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/testutil/factory"
+	"go-backend/util"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	service := newTestService(t, store)
+
+	account := factory.Account(factory.OwnedBy(util.RandomOwner()), factory.InCurrency(util.CAD))
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(2).Return(account, nil)
+
+	got, err := service.GetAccount(context.Background(), account.Owner, account.ID)
+	require.NoError(t, err)
+	require.Equal(t, account, got)
+
+	store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(db.AccountMember{}, db.ErrRecordNotFound)
+	_, err = service.GetAccount(context.Background(), util.RandomOwner(), account.ID)
+	require.Equal(t, CodePermissionDenied, ErrorCode(err))
+
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(1).Return(db.Account{}, db.ErrRecordNotFound)
+	_, err = service.GetAccount(context.Background(), account.Owner, account.ID+1)
+	require.Equal(t, CodeNotFound, ErrorCode(err))
+	require.ErrorIs(t, err, db.ErrRecordNotFound)
+}
+
+func TestGetSharedAccount(t *testing.T) {
+	account := factory.Account(factory.OwnedBy(util.RandomOwner()), factory.InCurrency(util.CAD))
+	member := util.RandomOwner()
+	accepted := acceptedNow()
+
+	testCases := []struct {
+		name      string
+		member    db.AccountMember
+		err       error
+		readCode  *Code
+		ownedCode *Code
+	}{
+		{
+			name:   "Owner",
+			member: db.AccountMember{Role: db.AccountRoleOwner, AcceptedAt: accepted},
+		},
+		{
+			name:      "Viewer",
+			member:    db.AccountMember{Role: db.AccountRoleViewer, AcceptedAt: accepted},
+			ownedCode: codePtr(CodePermissionDenied),
+		},
+		{
+			name:      "Invited",
+			member:    db.AccountMember{Role: db.AccountRoleOwner},
+			readCode:  codePtr(CodePermissionDenied),
+			ownedCode: codePtr(CodePermissionDenied),
+		},
+		{
+			name:      "NotMember",
+			err:       db.ErrRecordNotFound,
+			readCode:  codePtr(CodePermissionDenied),
+			ownedCode: codePtr(CodePermissionDenied),
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(2).Return(account, nil)
+			store.EXPECT().
+				GetAccountMember(gomock.Any(), gomock.Eq(db.GetAccountMemberParams{AccountID: account.ID, Username: member})).
+				Times(2).
+				Return(tc.member, tc.err)
+			service := newTestService(t, store)
+
+			_, err := service.GetAccount(context.Background(), member, account.ID)
+			if tc.readCode == nil {
+				require.NoError(t, err)
+			} else {
+				require.Equal(t, *tc.readCode, ErrorCode(err))
+			}
+
+			_, err = service.ownedAccount(context.Background(), member, account.ID)
+			if tc.ownedCode == nil {
+				require.NoError(t, err)
+			} else {
+				require.Equal(t, *tc.ownedCode, ErrorCode(err))
+			}
+		})
+	}
+}
+
+func TestCreateAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	service := newTestService(t, store)
+
+	owner := util.RandomOwner()
+	arg := db.CreateAccountParams{Owner: owner, Currency: util.EUR, Balance: 0}
+	store.EXPECT().CreateAccount(gomock.Any(), gomock.Eq(arg)).Times(1).Return(db.Account{}, &pgconn.PgError{Code: db.UniqueViolation})
+
+	_, err := service.CreateAccount(context.Background(), owner, util.EUR)
+	require.Equal(t, CodeAlreadyExists, ErrorCode(err))
+}
+
+func TestListAccounts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	service := newTestService(t, store)
+
+	owner := util.RandomOwner()
+	minBalance := int64(100)
+	store.EXPECT().SearchAccounts(gomock.Any(), gomock.Eq(db.SearchAccountsParams{
+		Owner:      owner,
+		Currency:   pgtype.Text{String: util.USD, Valid: true},
+		MinBalance: pgtype.Int8{Int64: 100, Valid: true},
+		SortBy:     "created_at",
+		SortDesc:   true,
+		RowLimit:   20,
+		RowOffset:  40,
+	})).Times(1).Return([]db.Account{}, nil)
+
+	_, err := service.ListAccounts(context.Background(), ListAccountsParams{
+		Owner:      owner,
+		Currency:   util.USD,
+		MinBalance: &minBalance,
+		SortBy:     "created_at",
+		SortDesc:   true,
+		Limit:      20,
+		Offset:     40,
+	})
+	require.NoError(t, err)
+}
+
+func TestAdjustAccountInvalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().AdjustAccountBalanceTx(gomock.Any(), gomock.Any()).Times(0)
+	service := newTestService(t, store)
+
+	_, err := service.AdjustAccount(context.Background(), AdjustAccountParams{AccountID: 1, Memo: "correction"})
+	require.Equal(t, CodeInvalidArgument, ErrorCode(err))
+
+	_, err = service.AdjustAccount(context.Background(), AdjustAccountParams{AccountID: 1, Amount: 10, Memo: " "})
+	require.Equal(t, CodeInvalidArgument, ErrorCode(err))
+}
+
+func TestAdjustAccountVersionMismatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		AdjustAccountBalanceTx(gomock.Any(), db.AdjustAccountBalanceTxParams{ID: 1, Amount: -10, Version: 3, Memo: "correction"}).
+		Times(1).Return(db.TransferTxResult{}, db.ErrAccountVersionMismatch)
+
+	_, err := newTestService(t, store).AdjustAccount(context.Background(), AdjustAccountParams{
+		AccountID: 1,
+		Version:   3,
+		Amount:    -10,
+		Memo:      "correction",
+	})
+	require.Equal(t, CodeFailedPrecondition, ErrorCode(err))
+	require.Equal(t, ReasonVersionMismatch, ErrorReason(err))
+}
+
+func TestErrorCode(t *testing.T) {
+	require.Equal(t, CodeInternal, ErrorCode(errors.New("unclassified")))
+	require.Equal(t, CodeNotFound, ErrorCode(storeError(db.ErrRecordNotFound)))
+	require.Equal(t, CodeInternal, ErrorCode(storeError(sql.ErrConnDone)))
+	require.Equal(t, ReasonAccountClosed, ErrorReason(storeError(db.ErrAccountClosed)))
+
+	err := storeError(&pgconn.PgError{Code: db.ForeignKeyViolation})
+	require.Equal(t, CodeAlreadyExists, ErrorCode(err))
+	require.ErrorIs(t, err, db.ErrForeignKeyViolation)
+	require.NotErrorIs(t, err, db.ErrUniqueViolation)
+
+	var pgErr *pgconn.PgError
+	require.ErrorAs(t, err, &pgErr)
+}
+
+func TestStatement(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	service := newTestService(t, store)
+
+	account := factory.Account(factory.OwnedBy(util.RandomOwner()), factory.InCurrency(util.CAD))
+	account.Balance = 500
+	from := time.Date(2026, time.September, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+
+	deposit := factory.Entry(factory.OfAccount(account))
+	deposit.Amount = 200
+	adjustment := factory.Entry(factory.OfAccount(account))
+	adjustment.Amount = -60
+
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+	store.EXPECT().ListEntriesForExport(gomock.Any(), gomock.Eq(db.ListEntriesForExportParams{
+		AccountID: account.ID,
+		FromTime:  from,
+		ToTime:    to,
+		RowLimit:  MaxStatementEntries + 1,
+	})).Times(1).Return([]db.ListEntriesForExportRow{
+		{
+			Entry:                     deposit,
+			CounterpartyAccountID:     pgtype.Int8{Int64: 12, Valid: true},
+			CounterpartyUsername:      pgtype.Text{String: "jdoe", Valid: true},
+			CounterpartyFullName:      pgtype.Text{String: "", Valid: true},
+			TransferMemo:              pgtype.Text{String: "rent", Valid: true},
+			TransferExternalReference: pgtype.Text{String: "INV-42", Valid: true},
+		},
+		{Entry: adjustment},
+	}, nil)
+	store.EXPECT().SumEntriesSince(gomock.Any(), gomock.Eq(db.SumEntriesSinceParams{AccountID: account.ID, CreatedAt: to})).Times(1).Return(int64(350), nil)
+
+	got, err := service.Statement(context.Background(), account.Owner, account.ID, from, to)
+	require.NoError(t, err)
+	require.Equal(t, account.ID, got.AccountID)
+	require.Equal(t, util.CAD, got.Currency)
+	require.Equal(t, int64(150), got.ClosingBalance)
+	require.Equal(t, int64(10), got.OpeningBalance())
+	require.Len(t, got.Transactions, 2)
+
+	// the payee falls back to the username of the counterparty without a full name
+	require.Equal(t, "jdoe", got.Transactions[0].Payee)
+	require.Equal(t, "rent", got.Transactions[0].Memo)
+	require.Equal(t, "INV-42", got.Transactions[0].Reference)
+	require.Equal(t, int64(12), got.Transactions[0].CounterpartyAccountID)
+	require.Empty(t, got.Transactions[1].Payee)
+	require.Zero(t, got.Transactions[1].CounterpartyAccountID)
+
+	_, err = service.Statement(context.Background(), account.Owner, account.ID, to, from)
+	require.Equal(t, CodeInvalidArgument, ErrorCode(err))
+
+	_, err = service.Statement(context.Background(), account.Owner, account.ID, from, from.AddDate(0, 0, MaxStatementDays+1))
+	require.Equal(t, CodeInvalidArgument, ErrorCode(err))
+}
+
+func TestStatementTooManyEntries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	service := newTestService(t, store)
+
+	account := factory.Account(factory.OwnedBy(util.RandomOwner()))
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+	store.EXPECT().ListEntriesForExport(gomock.Any(), gomock.Any()).Times(1).Return(make([]db.ListEntriesForExportRow, MaxStatementEntries+1), nil)
+	store.EXPECT().SumEntriesSince(gomock.Any(), gomock.Any()).Times(0)
+
+	now := time.Now()
+	_, err := service.Statement(context.Background(), account.Owner, account.ID, now.AddDate(0, -1, 0), now)
+	require.Equal(t, CodeInvalidArgument, ErrorCode(err))
+}
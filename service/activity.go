@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	db "go-backend/db/sqlc"
+	"time"
+)
+
+const (
+	// MaxActivityDays is the longest period the activity of an account can cover.
+	MaxActivityDays = 366
+	// ActivityCounterparties is the number of counterparties listed in the activity of an account.
+	ActivityCounterparties = 10
+)
+
+// The AccountActivity type is the activity of an account over a period, read from the tables of the
+// activity projection.
+// @property {[]db.AccountDailyActivity} Days - the days of the period the account made transfers on,
+// oldest first.
+// @property {[]db.AccountCounterpartyActivity} Counterparties - the accounts it made the most transfers
+// with, of all time.
+// @property {int64} Credits - the sum of the amounts received over the period.
+// @property {int64} Debits - the sum of the amounts sent over the period, positive.
+// @property {int64} TransferCount - the number of transfers made over the period.
+type AccountActivity struct {
+	Days           []db.AccountDailyActivity
+	Counterparties []db.AccountCounterpartyActivity
+	Credits        int64
+	Debits         int64
+	TransferCount  int64
+}
+
+// The GetAccountActivity function returns the activity of an account belonging to the owner for the
+// days from `from` to `to` included, in UTC, with its top counterparties. It is maintained by the
+// activity projection of the worker, so the latest transfers may be missing for a moment.
+func (service *Service) GetAccountActivity(ctx context.Context, owner string, accountID int64, from time.Time, to time.Time) (AccountActivity, error) {
+	if to.Before(from) {
+		return AccountActivity{}, errorf(CodeInvalidArgument, "from %s is after to %s", from.Format("2006-01-02"), to.Format("2006-01-02"))
+	}
+	if to.Sub(from) >= MaxActivityDays*24*time.Hour {
+		return AccountActivity{}, errorf(CodeInvalidArgument, "an activity covers at most %d days", MaxActivityDays)
+	}
+
+	account, err := service.GetAccount(ctx, owner, accountID)
+	if err != nil {
+		return AccountActivity{}, err
+	}
+
+	days, err := service.store.ListAccountDailyActivity(ctx, db.ListAccountDailyActivityParams{
+		AccountID: account.ID,
+		FromDay:   from,
+		ToDay:     to,
+	})
+	if err != nil {
+		return AccountActivity{}, storeError(err)
+	}
+
+	counterparties, err := service.store.ListAccountCounterpartyActivity(ctx, db.ListAccountCounterpartyActivityParams{
+		AccountID: account.ID,
+		RowLimit:  ActivityCounterparties,
+	})
+	if err != nil {
+		return AccountActivity{}, storeError(err)
+	}
+
+	activity := AccountActivity{Days: days, Counterparties: counterparties}
+	for _, day := range days {
+		activity.Credits += day.Credits
+		activity.Debits += day.Debits
+		activity.TransferCount += day.TransferCount
+	}
+	return activity, nil
+}
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	db "go-backend/db/sqlc"
+	"log"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// The actions of the maker-checker approvals recorded in the audit log, whose detail names the pending
+// transfer.
+const (
+	ActionTransferApprovalRequested = "transfer.approval_requested"
+	ActionTransferApproved          = "transfer.approved"
+	ActionTransferRejected          = "transfer.rejected"
+)
+
+// The approvalOrganization function returns the organization whose admins must approve a transfer from
+// the account as its second approver, when the amount reaches the approval threshold of the organization
+// of the account. The accounts of the default organization, of the users signing up on their own, have
+// no second approver.
+func (service *Service) approvalOrganization(ctx context.Context, fromAccount db.Account, amount int64) (pgtype.Int8, error) {
+	if fromAccount.OrgID == db.DefaultOrganizationID {
+		return pgtype.Int8{}, nil
+	}
+
+	organization, err := service.store.GetOrganization(ctx, fromAccount.OrgID)
+	if err != nil {
+		return pgtype.Int8{}, storeError(err)
+	}
+	if organization.ApprovalThreshold <= 0 || amount < organization.ApprovalThreshold {
+		return pgtype.Int8{}, nil
+	}
+	return pgtype.Int8{Int64: organization.ID, Valid: true}, nil
+}
+
+// The requireNoApproval function fails a payment from the account that can't await a second approver,
+// e.g. the pull of a mandate or an external transfer, when the amount reaches the approval threshold of
+// the organization of the account, so that no flow moves such an amount on the word of its maker alone.
+func (service *Service) requireNoApproval(ctx context.Context, fromAccount db.Account, amount int64, what string) error {
+	approvers, err := service.approvalOrganization(ctx, fromAccount, amount)
+	if err != nil {
+		return err
+	}
+	if approvers.Valid {
+		return errorf(CodeFailedPrecondition, "%s reaches the approval threshold of organization [%d] and must be sent as a transfer awaiting a second approver", what, approvers.Int64).withReason(ReasonApprovalRequired)
+	}
+	return nil
+}
+
+// The SetApprovalThreshold function sets the amount from which the transfers from the accounts of an
+// organization await a second approver, 0 turning the approvals off. The default organization has no
+// approvals.
+func (service *Service) SetApprovalThreshold(ctx context.Context, orgID int64, threshold int64) (db.Organization, error) {
+	if threshold < 0 {
+		return db.Organization{}, errorf(CodeInvalidArgument, "approval threshold can't be negative, got %d", threshold).withField("approval_threshold")
+	}
+	if orgID == db.DefaultOrganizationID {
+		return db.Organization{}, errorf(CodePermissionDenied, "the default organization has no approvals")
+	}
+
+	organization, err := service.store.UpdateOrganizationApprovalThreshold(ctx, db.UpdateOrganizationApprovalThresholdParams{
+		ID:                orgID,
+		ApprovalThreshold: threshold,
+	})
+	if err != nil {
+		return organization, storeError(err)
+	}
+	return organization, nil
+}
+
+// The ListApprovals function lists the transfers awaiting the approval of the admins of an organization,
+// oldest first.
+func (service *Service) ListApprovals(ctx context.Context, orgID int64, limit int32, offset int32) ([]db.PendingTransfer, error) {
+	pending, err := service.store.ListOrganizationPendingTransfers(ctx, db.ListOrganizationPendingTransfersParams{
+		OrgID:  pgtype.Int8{Int64: orgID, Valid: true},
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return nil, storeError(err)
+	}
+	return pending, nil
+}
+
+// The DecideApprovalParams type is the decision of an admin of an organization on a transfer awaiting
+// their approval.
+// @property {int64} OrgID - the organization of the admin, whose transfers only they decide.
+// @property {string} Approver - the admin, who can't be the owner of the transfer.
+// @property {string} Reason - why the transfer is rejected, recorded in the audit log.
+type DecideApprovalParams struct {
+	OrgID    int64
+	Approver string
+	ID       int64
+	Reason   string
+}
+
+// The ApproveTransfer function makes a transfer awaiting the approval of an organization as its second
+// approver, an admin of the organization other than the owner of the transfer, and records it in the
+// audit log.
+func (service *Service) ApproveTransfer(ctx context.Context, arg DecideApprovalParams) (db.ApprovePendingTransferTxResult, error) {
+	pending, err := service.awaitingApproval(ctx, arg)
+	if err != nil {
+		return db.ApprovePendingTransferTxResult{}, err
+	}
+
+	result, err := service.approvePendingTransfer(ctx, pending, arg.Approver)
+	if err != nil {
+		return result, err
+	}
+
+	service.auditApproval(ctx, arg.Approver, ActionTransferApproved, pending, "")
+	return result, nil
+}
+
+// The RejectTransfer function rejects a transfer awaiting the approval of an organization on behalf of
+// an admin of the organization other than the owner of the transfer, and records it in the audit log.
+func (service *Service) RejectTransfer(ctx context.Context, arg DecideApprovalParams) (db.PendingTransfer, error) {
+	pending, err := service.awaitingApproval(ctx, arg)
+	if err != nil {
+		return pending, err
+	}
+
+	rejected, err := service.rejectPendingTransfer(ctx, arg.ID, arg.Approver)
+	if err != nil {
+		return rejected, err
+	}
+
+	service.auditApproval(ctx, arg.Approver, ActionTransferRejected, pending, arg.Reason)
+	return rejected, nil
+}
+
+// The awaitingApproval function returns a transfer awaiting the approval of the organization of the
+// decision, those of the other organizations not being found, provided the approver isn't its owner.
+func (service *Service) awaitingApproval(ctx context.Context, arg DecideApprovalParams) (db.PendingTransfer, error) {
+	pending, err := service.awaitingPendingTransfer(ctx, arg.ID)
+	if err != nil {
+		return pending, err
+	}
+	if !pending.OrgID.Valid || pending.OrgID.Int64 != arg.OrgID {
+		return pending, storeError(db.ErrRecordNotFound)
+	}
+	if pending.Owner == arg.Approver {
+		return pending, newError(CodePermissionDenied, errors.New("the owner of a transfer can't be its second approver"))
+	}
+	return pending, nil
+}
+
+// The auditApproval function records a step of the approval of a transfer in the audit log, as taken by
+// `username`. The step was taken already, so a failure to record it is only logged.
+func (service *Service) auditApproval(ctx context.Context, username string, action string, pending db.PendingTransfer, reason string) {
+	detail := fmt.Sprintf("pending transfer [%d] of %d from account [%d] to account [%d] requested by %s",
+		pending.ID, pending.Amount, pending.FromAccountID, pending.ToAccountID, pending.Owner)
+	if reason != "" {
+		detail += ": " + reason
+	}
+
+	_, err := service.RecordAuditEntry(ctx, AuditEntryParams{
+		Username: username,
+		Action:   action,
+		Detail:   detail,
+	})
+	if err != nil {
+		log.Printf("cannot record %s of pending transfer [%d]: %v", action, pending.ID, err)
+	}
+}
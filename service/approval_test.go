@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"go-backend/clock"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/testutil/factory"
+	"go-backend/util"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateTransferAwaitingSecondApprover(t *testing.T) {
+	organization := db.Organization{ID: 2, Slug: "acme", ApprovalThreshold: 100}
+	owner := util.RandomOwner()
+	fromAccount := factory.Account(factory.OwnedBy(owner), factory.InCurrency(util.USD), func(account *db.Account) {
+		account.OrgID = organization.ID
+	})
+	toAccount := factory.Account(factory.InCurrency(util.USD))
+	toAccount.ID = fromAccount.ID + 1
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(2).Return(fromAccount, nil)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(2).Return(toAccount, nil)
+	store.EXPECT().GetActiveBankParameter(gomock.Any(), gomock.Any()).Times(2).Return(db.BankParameter{}, db.ErrRecordNotFound)
+	store.EXPECT().GetOrganization(gomock.Any(), gomock.Eq(organization.ID)).Times(2).Return(organization, nil)
+
+	// the transfer at the threshold of the organization awaits its admins, which the audit log records
+	store.EXPECT().CreatePendingTransferTx(gomock.Any(), gomock.Any()).Times(1).
+		DoAndReturn(func(_ context.Context, arg db.CreatePendingTransferTxParams) (db.PendingTransfer, error) {
+			require.Equal(t, pgtype.Int8{Int64: organization.ID, Valid: true}, arg.PendingTransfer.OrgID)
+			return db.PendingTransfer{ID: 1, Owner: owner, Status: db.PendingTransferAwaitingApproval, OrgID: arg.PendingTransfer.OrgID}, nil
+		})
+	store.EXPECT().CreateAuditEntry(gomock.Any(), gomock.Any()).Times(1).
+		DoAndReturn(func(_ context.Context, arg db.CreateAuditEntryParams) (db.AuditEntry, error) {
+			require.Equal(t, owner, arg.Username)
+			require.Equal(t, ActionTransferApprovalRequested, arg.Action)
+			return db.AuditEntry{}, nil
+		})
+	store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(1).Return(db.TransferTxResult{}, nil)
+
+	arg := CreateTransferParams{
+		Owner:         owner,
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   toAccount.ID,
+		Amount:        100,
+		Currency:      util.USD,
+	}
+	service := newTestService(t, store)
+	result, err := service.CreateTransfer(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotNil(t, result.Pending)
+
+	arg.Amount = 99
+	result, err = service.CreateTransfer(context.Background(), arg)
+	require.NoError(t, err)
+	require.Nil(t, result.Pending)
+}
+
+func TestDecideApproval(t *testing.T) {
+	const orgID = 2
+	fromAccount := factory.Account(factory.InCurrency(util.USD), func(account *db.Account) {
+		account.OrgID = orgID
+	})
+	toAccount := factory.Account(factory.InCurrency(util.USD))
+	toAccount.ID = fromAccount.ID + 1
+	pending := factory.PendingTransfer(factory.PendingBetween(fromAccount, toAccount), func(pending *db.PendingTransfer) {
+		pending.OrgID = pgtype.Int8{Int64: orgID, Valid: true}
+	})
+	approver := util.RandomOwner()
+	now := time.Now()
+
+	testCases := []struct {
+		name      string
+		arg       DecideApprovalParams
+		buildStub func(store *mockdb.MockStore)
+		code      *Code
+	}{
+		{
+			name: "OK",
+			arg:  DecideApprovalParams{OrgID: orgID, Approver: approver, ID: pending.ID},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPendingTransfer(gomock.Any(), gomock.Eq(pending.ID)).Times(1).Return(pending, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(2).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().GetActiveBankParameter(gomock.Any(), gomock.Any()).Times(1).Return(db.BankParameter{}, db.ErrRecordNotFound)
+				store.EXPECT().
+					ApprovePendingTransferTx(gomock.Any(), gomock.Eq(db.ApprovePendingTransferTxParams{ID: pending.ID, DecidedBy: approver, Currency: fromAccount.Currency, Now: now})).
+					Times(1).
+					Return(db.ApprovePendingTransferTxResult{PendingTransfer: pending}, nil)
+				store.EXPECT().CreateAuditEntry(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateAuditEntryParams) (db.AuditEntry, error) {
+						require.Equal(t, approver, arg.Username)
+						require.Equal(t, ActionTransferApproved, arg.Action)
+						return db.AuditEntry{}, nil
+					})
+			},
+		},
+		{
+			name: "Owner",
+			arg:  DecideApprovalParams{OrgID: orgID, Approver: pending.Owner, ID: pending.ID},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPendingTransfer(gomock.Any(), gomock.Eq(pending.ID)).Times(1).Return(pending, nil)
+				store.EXPECT().ApprovePendingTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code: codePtr(CodePermissionDenied),
+		},
+		{
+			name: "OtherOrganization",
+			arg:  DecideApprovalParams{OrgID: orgID + 1, Approver: approver, ID: pending.ID},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetPendingTransfer(gomock.Any(), gomock.Eq(pending.ID)).Times(1).Return(pending, nil)
+				store.EXPECT().ApprovePendingTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code: codePtr(CodeNotFound),
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			service := newTestService(t, store)
+			service.SetClock(clock.NewFake(now))
+
+			_, err := service.ApproveTransfer(context.Background(), tc.arg)
+			if tc.code != nil {
+				require.Equal(t, *tc.code, ErrorCode(err))
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestConfirmPendingTransferAwaitingSecondApprover(t *testing.T) {
+	pending := factory.PendingTransfer(func(pending *db.PendingTransfer) {
+		pending.OrgID = pgtype.Int8{Int64: 2, Valid: true}
+		pending.ExpiresAt = time.Now().Add(time.Hour)
+	})
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// the owner of the transfer, its maker, can't be its checker
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetPendingTransfer(gomock.Any(), gomock.Eq(pending.ID)).Times(1).Return(pending, nil)
+	store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+	store.EXPECT().ApprovePendingTransferTx(gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := newTestService(t, store).ConfirmPendingTransfer(context.Background(), pending.Owner, pending.ID, "secret")
+	require.Equal(t, CodePermissionDenied, ErrorCode(err))
+}
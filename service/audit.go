@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	db "go-backend/db/sqlc"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// The AuditEntryParams type is an action recorded in the audit log.
+// @property {string} Username - the user the action was taken as.
+// @property {string} Impersonator - the admin who took the action while impersonating the user, empty
+// when the user took it.
+// @property {string} Action - e.g. ActionImpersonationStarted, or the method and route of a request.
+// @property {int32} Status - the HTTP status of the request, 0 for the actions that are not requests.
+type AuditEntryParams struct {
+	Username     string
+	Impersonator string
+	Action       string
+	Detail       string
+	Status       int32
+}
+
+// The RecordAuditEntry function records an action in the audit log.
+func (service *Service) RecordAuditEntry(ctx context.Context, arg AuditEntryParams) (db.AuditEntry, error) {
+	entry, err := service.store.CreateAuditEntry(ctx, db.CreateAuditEntryParams{
+		Username:     arg.Username,
+		Impersonator: pgtype.Text{String: arg.Impersonator, Valid: arg.Impersonator != ""},
+		Action:       arg.Action,
+		Detail:       arg.Detail,
+		Status:       arg.Status,
+	})
+	if err != nil {
+		return db.AuditEntry{}, storeError(err)
+	}
+	return entry, nil
+}
+
+// The ListAuditEntriesParams type holds the filters and page of an audit log listing.
+// @property {string} Username - only list the actions taken as this user when it is set.
+// @property {string} Impersonator - only list the actions taken by this admin impersonating users when it
+// is set.
+type ListAuditEntriesParams struct {
+	Username     string
+	Impersonator string
+	Limit        int32
+	Offset       int32
+}
+
+// The ListAuditEntries function lists the actions of the audit log, newest first.
+func (service *Service) ListAuditEntries(ctx context.Context, arg ListAuditEntriesParams) ([]db.AuditEntry, error) {
+	entries, err := service.store.ListAuditEntries(ctx, db.ListAuditEntriesParams{
+		Username:     pgtype.Text{String: arg.Username, Valid: arg.Username != ""},
+		Impersonator: pgtype.Text{String: arg.Impersonator, Valid: arg.Impersonator != ""},
+		RowLimit:     arg.Limit,
+		RowOffset:    arg.Offset,
+	})
+	if err != nil {
+		return nil, storeError(err)
+	}
+	return entries, nil
+}
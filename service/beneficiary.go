@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"errors"
+	db "go-backend/db/sqlc"
+)
+
+// The CreateBeneficiaryParams type is an account saved by a user to send transfers to.
+// @property {string} Owner - the user saving the account.
+// @property {int64} AccountID - the account saved, which can belong to any user but the bank.
+// @property {string} Nickname - the name the owner gives to the account, unique among their
+// beneficiaries.
+// @property {string} Category - the spending category of the transfers to the account, which the budgets
+// of the owner's accounts track, empty when uncategorized.
+type CreateBeneficiaryParams struct {
+	Owner     string
+	AccountID int64
+	Nickname  string
+	Category  string
+}
+
+// The CreateBeneficiary function saves an account as a beneficiary of the owner. An account or a
+// nickname can only be saved once by the same owner.
+func (service *Service) CreateBeneficiary(ctx context.Context, arg CreateBeneficiaryParams) (db.Beneficiary, error) {
+	account, err := service.store.GetAccount(ctx, arg.AccountID)
+	if err != nil {
+		return db.Beneficiary{}, storeError(err)
+	}
+
+	// only the transactions of the bank post to its system accounts
+	if db.IsSystemAccount(account) {
+		return db.Beneficiary{}, newError(CodePermissionDenied, db.ErrSystemAccount)
+	}
+
+	beneficiary, err := service.store.CreateBeneficiary(ctx, db.CreateBeneficiaryParams{
+		Owner:     arg.Owner,
+		AccountID: arg.AccountID,
+		Nickname:  arg.Nickname,
+		Category:  arg.Category,
+	})
+	if err != nil {
+		return beneficiary, storeError(err)
+	}
+
+	return beneficiary, nil
+}
+
+// The GetBeneficiary function returns a beneficiary, provided it was saved by the owner.
+func (service *Service) GetBeneficiary(ctx context.Context, owner string, id int64) (db.Beneficiary, error) {
+	beneficiary, err := service.store.GetBeneficiary(ctx, id)
+	if err != nil {
+		return beneficiary, storeError(err)
+	}
+
+	if beneficiary.Owner != owner {
+		return beneficiary, newError(CodePermissionDenied, errors.New("beneficiary doesn't belong to authenticated user"))
+	}
+
+	return beneficiary, nil
+}
+
+// The ListBeneficiaries function lists the beneficiaries of the owner by nickname.
+func (service *Service) ListBeneficiaries(ctx context.Context, owner string, limit int32, offset int32) ([]db.Beneficiary, error) {
+	beneficiaries, err := service.store.ListBeneficiaries(ctx, db.ListBeneficiariesParams{
+		Owner:  owner,
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return nil, storeError(err)
+	}
+
+	return beneficiaries, nil
+}
+
+// The UpdateBeneficiary function changes the nickname and the category of a beneficiary of the owner.
+func (service *Service) UpdateBeneficiary(ctx context.Context, owner string, id int64, nickname string, category string) (db.Beneficiary, error) {
+	_, err := service.GetBeneficiary(ctx, owner, id)
+	if err != nil {
+		return db.Beneficiary{}, err
+	}
+
+	beneficiary, err := service.store.UpdateBeneficiary(ctx, db.UpdateBeneficiaryParams{
+		ID:       id,
+		Nickname: nickname,
+		Category: category,
+	})
+	if err != nil {
+		return beneficiary, storeError(err)
+	}
+
+	return beneficiary, nil
+}
+
+// The DeleteBeneficiary function deletes a beneficiary of the owner. The transfers already sent to it are
+// kept.
+func (service *Service) DeleteBeneficiary(ctx context.Context, owner string, id int64) error {
+	_, err := service.GetBeneficiary(ctx, owner, id)
+	if err != nil {
+		return err
+	}
+
+	err = service.store.DeleteBeneficiary(ctx, id)
+	if err != nil {
+		return storeError(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"errors"
+	db "go-backend/db/sqlc"
+	"time"
+)
+
+// The SetBudgetParams type is the monthly budget of a spending category of an account.
+// @property {string} Owner - the user setting the budget, who must be able to use the account as an owner.
+// @property {string} Category - the category of the beneficiaries of the account owner the budget tracks
+// the transfers to.
+// @property {int64} MonthlyLimit - the most the account should send to the category in a calendar month,
+// in its currency.
+type SetBudgetParams struct {
+	Owner        string
+	AccountID    int64
+	Category     string
+	MonthlyLimit int64
+}
+
+// The SetBudget function sets the monthly budget of a category of an account, replacing the previous one.
+// The notification worker notifies the account owner when a transfer takes the spending of the category
+// beyond it.
+func (service *Service) SetBudget(ctx context.Context, arg SetBudgetParams) (db.Budget, error) {
+	if arg.MonthlyLimit <= 0 {
+		return db.Budget{}, errorf(CodeInvalidArgument, "monthly limit must be positive, got %d", arg.MonthlyLimit).withReason(ReasonInvalidAmount)
+	}
+
+	account, err := service.ownedAccount(ctx, arg.Owner, arg.AccountID)
+	if err != nil {
+		return db.Budget{}, err
+	}
+
+	budget, err := service.store.UpsertBudget(ctx, db.UpsertBudgetParams{
+		AccountID:    account.ID,
+		Category:     arg.Category,
+		MonthlyLimit: arg.MonthlyLimit,
+	})
+	if err != nil {
+		return budget, storeError(err)
+	}
+
+	return budget, nil
+}
+
+// The DeleteBudget function deletes the budget of a category of an account, whose spending is no longer
+// tracked.
+func (service *Service) DeleteBudget(ctx context.Context, owner string, accountID int64, category string) error {
+	account, err := service.ownedAccount(ctx, owner, accountID)
+	if err != nil {
+		return err
+	}
+
+	arg := db.GetBudgetParams{AccountID: account.ID, Category: category}
+	_, err = service.store.GetBudget(ctx, arg)
+	if err != nil {
+		return storeError(err)
+	}
+
+	err = service.store.DeleteBudget(ctx, db.DeleteBudgetParams(arg))
+	if err != nil {
+		return storeError(err)
+	}
+
+	return nil
+}
+
+// The BudgetSpending type is a budget along with the spending of its category this month.
+// @property {int64} Spent - the transfers sent to the category since the start of the month, in UTC.
+type BudgetSpending struct {
+	Budget db.Budget
+	Spent  int64
+}
+
+// The ListBudgets function lists the budgets of an account by category, with what was spent in each
+// this month. Every user the account was shared with can read them.
+func (service *Service) ListBudgets(ctx context.Context, owner string, accountID int64) ([]BudgetSpending, error) {
+	account, err := service.GetAccount(ctx, owner, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	budgets, err := service.store.ListBudgets(ctx, account.ID)
+	if err != nil {
+		return nil, storeError(err)
+	}
+
+	now := time.Now()
+	spending := make([]BudgetSpending, len(budgets))
+	for i, budget := range budgets {
+		// the categories are those of the beneficiaries of the account owner, whoever reads the budgets
+		spent, err := service.store.SumCategorySpending(ctx, db.SumCategorySpendingParams{
+			AccountID: account.ID,
+			Owner:     account.Owner,
+			Category:  budget.Category,
+			Since:     db.BudgetMonthStart(now),
+			Until:     now,
+		})
+		if err != nil {
+			return nil, storeError(err)
+		}
+
+		spending[i] = BudgetSpending{Budget: budget, Spent: spent}
+	}
+
+	return spending, nil
+}
+
+// The GetAccountAlert function returns the low balance alert set on an account. An account without one
+// is not found, the threshold of the preferences of its owner applying to it.
+func (service *Service) GetAccountAlert(ctx context.Context, owner string, accountID int64) (db.AccountAlert, error) {
+	account, err := service.GetAccount(ctx, owner, accountID)
+	if err != nil {
+		return db.AccountAlert{}, err
+	}
+
+	alert, err := service.store.GetAccountAlert(ctx, account.ID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return alert, errorf(CodeNotFound, "no low balance alert set on account [%d]", account.ID)
+		}
+		return alert, storeError(err)
+	}
+
+	return alert, nil
+}
+
+// The SetAccountAlert function sets the low balance threshold of an account, overriding the one of the
+// preferences of its owner. A threshold of 0 never notifies.
+func (service *Service) SetAccountAlert(ctx context.Context, owner string, accountID int64, threshold int64) (db.AccountAlert, error) {
+	if threshold < 0 {
+		return db.AccountAlert{}, errorf(CodeInvalidArgument, "low balance threshold can't be negative, got %d", threshold)
+	}
+
+	account, err := service.ownedAccount(ctx, owner, accountID)
+	if err != nil {
+		return db.AccountAlert{}, err
+	}
+
+	alert, err := service.store.UpsertAccountAlert(ctx, db.UpsertAccountAlertParams{
+		AccountID:           account.ID,
+		LowBalanceThreshold: threshold,
+	})
+	if err != nil {
+		return alert, storeError(err)
+	}
+
+	return alert, nil
+}
+
+// The DeleteAccountAlert function deletes the low balance alert of an account, the threshold of the
+// preferences of its owner applying to it again.
+func (service *Service) DeleteAccountAlert(ctx context.Context, owner string, accountID int64) error {
+	account, err := service.ownedAccount(ctx, owner, accountID)
+	if err != nil {
+		return err
+	}
+
+	err = service.store.DeleteAccountAlert(ctx, account.ID)
+	if err != nil {
+		return storeError(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/testutil/factory"
+	"go-backend/util"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetBudget(t *testing.T) {
+	owner := util.RandomOwner()
+	account := factory.Account(factory.OwnedBy(owner))
+
+	testCases := []struct {
+		name      string
+		arg       SetBudgetParams
+		buildStub func(store *mockdb.MockStore)
+		code      *Code
+	}{
+		{
+			name: "OK",
+			arg:  SetBudgetParams{Owner: owner, AccountID: account.ID, Category: "groceries", MonthlyLimit: 400},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().
+					UpsertBudget(gomock.Any(), gomock.Eq(db.UpsertBudgetParams{
+						AccountID:    account.ID,
+						Category:     "groceries",
+						MonthlyLimit: 400,
+					})).
+					Times(1)
+			},
+		},
+		{
+			name: "InvalidLimit",
+			arg:  SetBudgetParams{Owner: owner, AccountID: account.ID, Category: "groceries"},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code: codePtr(CodeInvalidArgument),
+		},
+		{
+			name: "Viewer",
+			arg:  SetBudgetParams{Owner: util.RandomOwner(), AccountID: account.ID, Category: "groceries", MonthlyLimit: 400},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().
+					GetAccountMember(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.AccountMember{Role: db.AccountRoleViewer, AcceptedAt: acceptedNow()}, nil)
+				store.EXPECT().UpsertBudget(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code: codePtr(CodePermissionDenied),
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			_, err := newTestService(t, store).SetBudget(context.Background(), tc.arg)
+			if tc.code == nil {
+				require.NoError(t, err)
+			} else {
+				require.Equal(t, *tc.code, ErrorCode(err))
+			}
+		})
+	}
+}
+
+func TestListBudgets(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	owner := util.RandomOwner()
+	viewer := util.RandomOwner()
+	account := factory.Account(factory.OwnedBy(owner))
+	budget := db.Budget{AccountID: account.ID, Category: "groceries", MonthlyLimit: 400}
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+	store.EXPECT().
+		GetAccountMember(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(db.AccountMember{Role: db.AccountRoleViewer, AcceptedAt: acceptedNow()}, nil)
+	store.EXPECT().ListBudgets(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return([]db.Budget{budget}, nil)
+	store.EXPECT().
+		SumCategorySpending(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(ctx context.Context, arg db.SumCategorySpendingParams) (int64, error) {
+			// the spending is the one of the beneficiaries of the owner, whoever reads it
+			require.Equal(t, owner, arg.Owner)
+			require.Equal(t, "groceries", arg.Category)
+			require.Equal(t, db.BudgetMonthStart(arg.Until), arg.Since)
+			return 150, nil
+		})
+
+	budgets, err := newTestService(t, store).ListBudgets(context.Background(), viewer, account.ID)
+	require.NoError(t, err)
+	require.Equal(t, []BudgetSpending{{Budget: budget, Spent: 150}}, budgets)
+}
+
+func TestAccountAlert(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	owner := util.RandomOwner()
+	account := factory.Account(factory.OwnedBy(owner))
+
+	store := mockdb.NewMockStore(ctrl)
+	service := newTestService(t, store)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).AnyTimes().Return(account, nil)
+
+	_, err := service.SetAccountAlert(context.Background(), owner, account.ID, -1)
+	require.Equal(t, CodeInvalidArgument, ErrorCode(err))
+
+	arg := db.UpsertAccountAlertParams{AccountID: account.ID, LowBalanceThreshold: 50}
+	store.EXPECT().
+		UpsertAccountAlert(gomock.Any(), gomock.Eq(arg)).
+		Times(1).
+		Return(db.AccountAlert{AccountID: account.ID, LowBalanceThreshold: 50}, nil)
+	alert, err := service.SetAccountAlert(context.Background(), owner, account.ID, 50)
+	require.NoError(t, err)
+	require.Equal(t, int64(50), alert.LowBalanceThreshold)
+
+	// an account without an alert follows the threshold of its owner
+	store.EXPECT().GetAccountAlert(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(db.AccountAlert{}, db.ErrRecordNotFound)
+	_, err = service.GetAccountAlert(context.Background(), owner, account.ID)
+	require.Equal(t, CodeNotFound, ErrorCode(err))
+}
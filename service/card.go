@@ -0,0 +1,312 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	db "go-backend/db/sqlc"
+	"math/big"
+	"time"
+)
+
+const (
+	// cardIIN is the issuer identification number the numbers of the cards issued by the bank start with.
+	cardIIN = "400000"
+	// cardNumberLength is the length of the numbers of the cards, their check digit included.
+	cardNumberLength = 16
+	// cardValidityYears is how long a card can be used after it is issued.
+	cardValidityYears = 3
+)
+
+// The IssuedCard type is a card just issued, along with its number.
+// @property {string} Number - the full number of the card, only returned here as the bank keeps its last
+// 4 digits only.
+type IssuedCard struct {
+	db.Card
+	Number string
+}
+
+// The IssueCard function issues a virtual card on an account the user owns, valid for 3 years. The
+// authorizations of the card place holds reserving their amount on the account.
+func (service *Service) IssueCard(ctx context.Context, owner string, accountID int64) (IssuedCard, error) {
+	account, err := service.ownedAccount(ctx, owner, accountID)
+	if err != nil {
+		return IssuedCard{}, err
+	}
+
+	number, err := newCardNumber()
+	if err != nil {
+		return IssuedCard{}, newError(CodeInternal, err)
+	}
+
+	expiry := time.Now().UTC().AddDate(cardValidityYears, 0, 0)
+	card, err := service.store.CreateCard(ctx, db.CreateCardParams{
+		AccountID:   account.ID,
+		Owner:       owner,
+		Last4:       number[len(number)-4:],
+		ExpiryMonth: int32(expiry.Month()),
+		ExpiryYear:  int32(expiry.Year()),
+	})
+	if err != nil {
+		return IssuedCard{}, storeError(err)
+	}
+
+	return IssuedCard{Card: card, Number: number}, nil
+}
+
+// The GetCard function returns a card, provided it was issued to the user.
+func (service *Service) GetCard(ctx context.Context, owner string, id int64) (db.Card, error) {
+	card, err := service.store.GetCard(ctx, id)
+	if err != nil {
+		return card, storeError(err)
+	}
+
+	if card.Owner != owner {
+		return card, newError(CodePermissionDenied, errors.New("card doesn't belong to authenticated user"))
+	}
+
+	return card, nil
+}
+
+// The ListCards function lists the cards issued to the user, oldest first.
+func (service *Service) ListCards(ctx context.Context, owner string, limit int32, offset int32) ([]db.Card, error) {
+	cards, err := service.store.ListCards(ctx, db.ListCardsParams{
+		Owner:  owner,
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return nil, storeError(err)
+	}
+
+	return cards, nil
+}
+
+// The FreezeCard function freezes a card of the user, which declines every authorization until it is
+// unfrozen. The holds already placed are left as they are.
+func (service *Service) FreezeCard(ctx context.Context, owner string, id int64) (db.Card, error) {
+	return service.setCardStatus(ctx, owner, id, db.CardFrozen)
+}
+
+// The UnfreezeCard function unfreezes a card of the user, which can be authorized again.
+func (service *Service) UnfreezeCard(ctx context.Context, owner string, id int64) (db.Card, error) {
+	return service.setCardStatus(ctx, owner, id, db.CardActive)
+}
+
+func (service *Service) setCardStatus(ctx context.Context, owner string, id int64, status string) (db.Card, error) {
+	card, err := service.GetCard(ctx, owner, id)
+	if err != nil {
+		return card, err
+	}
+
+	card, err = service.store.UpdateCardStatus(ctx, db.UpdateCardStatusParams{
+		ID:     card.ID,
+		Status: status,
+	})
+	if err != nil {
+		return card, storeError(err)
+	}
+
+	return card, nil
+}
+
+// The ListCardHolds function lists the holds placed by the authorizations of a card of the user, newest
+// first.
+func (service *Service) ListCardHolds(ctx context.Context, owner string, cardID int64, limit int32, offset int32) ([]db.Hold, error) {
+	card, err := service.GetCard(ctx, owner, cardID)
+	if err != nil {
+		return nil, err
+	}
+
+	holds, err := service.store.ListCardHolds(ctx, db.ListCardHoldsParams{
+		CardID: card.ID,
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return nil, storeError(err)
+	}
+
+	return holds, nil
+}
+
+// The AuthorizeCardParams type is the authorization of a card requested by a merchant through the card
+// network.
+// @property {int64} Amount - the positive amount to reserve, in the currency of the account of the card.
+// @property {string} Merchant - the merchant requesting the authorization.
+type AuthorizeCardParams struct {
+	CardID   int64
+	Amount   int64
+	Currency string
+	Merchant string
+}
+
+// The AuthorizeCard function authorizes a card, placing a hold that reserves the amount on its account
+// until it is captured, released or expires after the CARD_HOLD_TTL config. The balance of the account
+// is left unchanged until then, but its available balance, less its held balance, must cover the amount.
+// Frozen and expired cards are declined.
+func (service *Service) AuthorizeCard(ctx context.Context, arg AuthorizeCardParams) (db.AuthorizeCardTxResult, error) {
+	if arg.Amount <= 0 {
+		return db.AuthorizeCardTxResult{}, errorf(CodeInvalidArgument, "amount must be positive, got %d", arg.Amount).withReason(ReasonInvalidAmount)
+	}
+
+	card, err := service.store.GetCard(ctx, arg.CardID)
+	if err != nil {
+		return db.AuthorizeCardTxResult{}, storeError(err)
+	}
+	if cardExpired(card, time.Now()) {
+		return db.AuthorizeCardTxResult{}, errorf(CodeFailedPrecondition, "card [%d] has expired", card.ID).withReason(ReasonCardExpired)
+	}
+
+	account, err := service.store.GetAccount(ctx, card.AccountID)
+	if err != nil {
+		return db.AuthorizeCardTxResult{}, storeError(err)
+	}
+	if account.Currency != arg.Currency {
+		return db.AuthorizeCardTxResult{}, cardCurrencyError(card, arg.Currency)
+	}
+
+	result, err := service.store.AuthorizeCardTx(ctx, db.AuthorizeCardTxParams{
+		CardID:    card.ID,
+		Amount:    arg.Amount,
+		Currency:  arg.Currency,
+		Merchant:  arg.Merchant,
+		ExpiresAt: time.Now().Add(service.config.CardHoldTTL),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, db.ErrCardFrozen):
+			return result, errorf(CodeFailedPrecondition, "card [%d] is frozen", card.ID).withReason(ReasonCardFrozen)
+		case errors.Is(err, db.ErrInsufficientAvailableBalance):
+			return result, errorf(CodeFailedPrecondition, "available balance of account [%d] is insufficient for %d", account.ID, arg.Amount).withReason(ReasonInsufficientFunds)
+		case errors.Is(err, db.ErrAccountVersionMismatch):
+			// the currency of the account was changed meanwhile
+			return result, cardCurrencyError(card, arg.Currency)
+		}
+		return result, storeError(err)
+	}
+
+	return result, nil
+}
+
+// The CaptureCardHold function settles an authorized hold, moving the amount from the account of the card
+// to the card_settlement account of its currency. An amount of 0 captures the whole hold, a smaller one
+// releasing the rest.
+func (service *Service) CaptureCardHold(ctx context.Context, id int64, amount int64) (db.CaptureCardHoldTxResult, error) {
+	if amount < 0 {
+		return db.CaptureCardHoldTxResult{}, errorf(CodeInvalidArgument, "amount can't be negative, got %d", amount).withReason(ReasonInvalidAmount)
+	}
+
+	hold, err := service.authorizedCardHold(ctx, id)
+	if err != nil {
+		return db.CaptureCardHoldTxResult{}, err
+	}
+	if amount == 0 {
+		amount = hold.Amount
+	}
+	if amount > hold.Amount {
+		return db.CaptureCardHoldTxResult{}, errorf(CodeInvalidArgument, "amount %d exceeds the hold of %d", amount, hold.Amount).withReason(ReasonInvalidAmount)
+	}
+
+	result, err := service.store.CaptureCardHoldTx(ctx, db.CaptureCardHoldTxParams{
+		ID:     id,
+		Amount: amount,
+	})
+	if err != nil {
+		return result, cardHoldError(id, err)
+	}
+
+	return result, nil
+}
+
+// The ReleaseCardHold function releases an authorized hold, e.g. for a cancelled purchase, which then no
+// longer reserves its amount.
+func (service *Service) ReleaseCardHold(ctx context.Context, id int64) (db.Hold, error) {
+	_, err := service.authorizedCardHold(ctx, id)
+	if err != nil {
+		return db.Hold{}, err
+	}
+
+	hold, err := service.store.ReleaseHoldTx(ctx, id)
+	if err != nil {
+		return hold, cardHoldError(id, err)
+	}
+
+	return hold, nil
+}
+
+// The authorizedCardHold function returns a hold placed by a card authorization, provided it is still
+// authorized and isn't past its expiry.
+func (service *Service) authorizedCardHold(ctx context.Context, id int64) (db.Hold, error) {
+	hold, err := service.store.GetHold(ctx, id)
+	if err != nil {
+		return hold, storeError(err)
+	}
+
+	// the other holds aren't processed by the card network
+	if !hold.CardID.Valid {
+		return hold, errorf(CodeNotFound, "card hold [%d] doesn't exist", id)
+	}
+	if hold.Status != db.HoldAuthorized {
+		return hold, cardHoldError(id, db.ErrHoldClosed)
+	}
+	if !hold.ExpiresAt.After(time.Now()) {
+		return hold, cardHoldError(id, db.ErrHoldExpired)
+	}
+
+	return hold, nil
+}
+
+// The cardHoldError function classifies the errors of the store on a card hold.
+func cardHoldError(id int64, err error) error {
+	switch {
+	case errors.Is(err, db.ErrHoldClosed):
+		return errorf(CodeFailedPrecondition, "card hold [%d] is no longer authorized", id).withReason(ReasonCardHoldClosed)
+	case errors.Is(err, db.ErrHoldExpired):
+		return errorf(CodeFailedPrecondition, "card hold [%d] has expired", id).withReason(ReasonCardHoldExpired)
+	case errors.Is(err, db.ErrCaptureExceedsHold):
+		return errorf(CodeInvalidArgument, "captured amount exceeds card hold [%d]", id).withReason(ReasonInvalidAmount)
+	}
+	return storeError(err)
+}
+
+func cardCurrencyError(card db.Card, currency string) error {
+	return errorf(CodeInvalidArgument, "account [%d] of card [%d] doesn't hold %s", card.AccountID, card.ID, currency).withReason(ReasonCurrencyMismatch)
+}
+
+// cardExpired reports whether the card can no longer be used at `now`, once its expiry month is over.
+func cardExpired(card db.Card, now time.Time) bool {
+	end := time.Date(int(card.ExpiryYear), time.Month(card.ExpiryMonth)+1, 1, 0, 0, 0, 0, time.UTC)
+	return !now.Before(end)
+}
+
+// newCardNumber returns a random card number starting with the issuer identification number of the bank,
+// whose last digit is its Luhn check digit.
+func newCardNumber() (string, error) {
+	digits := []byte(cardIIN)
+	for len(digits) < cardNumberLength-1 {
+		digit, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", err
+		}
+		digits = append(digits, byte('0'+digit.Int64()))
+	}
+	return string(append(digits, luhnCheckDigit(digits))), nil
+}
+
+// luhnCheckDigit returns the digit completing the number so that it passes the Luhn check, every second
+// digit from the right of the completed number being doubled.
+func luhnCheckDigit(digits []byte) byte {
+	sum := 0
+	for i := len(digits) - 1; i >= 0; i-- {
+		digit := int(digits[i] - '0')
+		if (len(digits)-1-i)%2 == 0 {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+	}
+	return byte('0' + (10-sum%10)%10)
+}
@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/testutil/factory"
+	"go-backend/util"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+// luhnValid reports whether the number passes the Luhn check.
+func luhnValid(number string) bool {
+	return luhnCheckDigit([]byte(number[:len(number)-1])) == number[len(number)-1]
+}
+
+func TestLuhnCheckDigit(t *testing.T) {
+	require.True(t, luhnValid("4242424242424242"))
+	require.True(t, luhnValid("79927398713"))
+	require.False(t, luhnValid("4242424242424241"))
+}
+
+func TestIssueCard(t *testing.T) {
+	owner := util.RandomOwner()
+	account := factory.Account(factory.OwnedBy(owner))
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+	store.EXPECT().CreateCard(gomock.Any(), gomock.Any()).Times(1).
+		DoAndReturn(func(_ context.Context, arg db.CreateCardParams) (db.Card, error) {
+			require.Equal(t, account.ID, arg.AccountID)
+			require.Equal(t, int32(time.Now().UTC().Year()+cardValidityYears), arg.ExpiryYear)
+			return db.Card{ID: 1, AccountID: arg.AccountID, Owner: arg.Owner, Last4: arg.Last4, Status: db.CardActive}, nil
+		})
+
+	issued, err := newTestService(t, store).IssueCard(context.Background(), owner, account.ID)
+	require.NoError(t, err)
+	require.Len(t, issued.Number, cardNumberLength)
+	require.True(t, strings.HasPrefix(issued.Number, cardIIN))
+	require.True(t, luhnValid(issued.Number))
+	// only the last 4 digits of the number are kept
+	require.Equal(t, issued.Number[cardNumberLength-4:], issued.Last4)
+}
+
+func TestAuthorizeCard(t *testing.T) {
+	account := factory.Account(factory.InCurrency(util.USD))
+	card := db.Card{
+		ID:          1,
+		AccountID:   account.ID,
+		Owner:       account.Owner,
+		ExpiryMonth: 12,
+		ExpiryYear:  int32(time.Now().Year() + 1),
+		Status:      db.CardActive,
+	}
+	expired := card
+	expired.ExpiryYear = int32(time.Now().Year() - 1)
+
+	testCases := []struct {
+		name      string
+		arg       AuthorizeCardParams
+		buildStub func(store *mockdb.MockStore)
+		code      *Code
+		reason    string
+	}{
+		{
+			name: "OK",
+			arg:  AuthorizeCardParams{CardID: card.ID, Amount: 100, Currency: util.USD, Merchant: "Coffee Shop"},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetCard(gomock.Any(), gomock.Eq(card.ID)).Times(1).Return(card, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().AuthorizeCardTx(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ context.Context, arg db.AuthorizeCardTxParams) (db.AuthorizeCardTxResult, error) {
+						require.Equal(t, int64(100), arg.Amount)
+						require.True(t, arg.ExpiresAt.After(time.Now()))
+						return db.AuthorizeCardTxResult{CardHold: db.Hold{ID: 1, Status: db.HoldAuthorized}}, nil
+					})
+			},
+		},
+		{
+			name: "Frozen",
+			arg:  AuthorizeCardParams{CardID: card.ID, Amount: 100, Currency: util.USD},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetCard(gomock.Any(), gomock.Eq(card.ID)).Times(1).Return(card, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().AuthorizeCardTx(gomock.Any(), gomock.Any()).Times(1).Return(db.AuthorizeCardTxResult{}, db.ErrCardFrozen)
+			},
+			code:   codePtr(CodeFailedPrecondition),
+			reason: ReasonCardFrozen,
+		},
+		{
+			name: "InsufficientFunds",
+			arg:  AuthorizeCardParams{CardID: card.ID, Amount: 100, Currency: util.USD},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetCard(gomock.Any(), gomock.Eq(card.ID)).Times(1).Return(card, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().AuthorizeCardTx(gomock.Any(), gomock.Any()).Times(1).Return(db.AuthorizeCardTxResult{}, db.ErrInsufficientAvailableBalance)
+			},
+			code:   codePtr(CodeFailedPrecondition),
+			reason: ReasonInsufficientFunds,
+		},
+		{
+			name: "Expired",
+			arg:  AuthorizeCardParams{CardID: card.ID, Amount: 100, Currency: util.USD},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetCard(gomock.Any(), gomock.Eq(card.ID)).Times(1).Return(expired, nil)
+				store.EXPECT().AuthorizeCardTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code:   codePtr(CodeFailedPrecondition),
+			reason: ReasonCardExpired,
+		},
+		{
+			name: "CurrencyMismatch",
+			arg:  AuthorizeCardParams{CardID: card.ID, Amount: 100, Currency: util.EUR},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetCard(gomock.Any(), gomock.Eq(card.ID)).Times(1).Return(card, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().AuthorizeCardTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code:   codePtr(CodeInvalidArgument),
+			reason: ReasonCurrencyMismatch,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			service := newTestService(t, store)
+			service.config.CardHoldTTL = time.Hour
+			_, err := service.AuthorizeCard(context.Background(), tc.arg)
+			if tc.code != nil {
+				require.Equal(t, *tc.code, ErrorCode(err))
+				require.Equal(t, tc.reason, ErrorReason(err))
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestCaptureCardHold(t *testing.T) {
+	hold := db.Hold{
+		ID:        1,
+		CardID:    pgtype.Int8{Int64: 1, Valid: true},
+		Amount:    100,
+		Status:    db.HoldAuthorized,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetHold(gomock.Any(), gomock.Eq(hold.ID)).Times(2).Return(hold, nil)
+	// without an amount, the whole hold is captured
+	store.EXPECT().
+		CaptureCardHoldTx(gomock.Any(), gomock.Eq(db.CaptureCardHoldTxParams{ID: hold.ID, Amount: hold.Amount})).
+		Times(1).
+		Return(db.CaptureCardHoldTxResult{}, nil)
+
+	service := newTestService(t, store)
+	_, err := service.CaptureCardHold(context.Background(), hold.ID, 0)
+	require.NoError(t, err)
+
+	_, err = service.CaptureCardHold(context.Background(), hold.ID, hold.Amount+1)
+	require.Equal(t, CodeInvalidArgument, ErrorCode(err))
+
+	// a captured hold can't be captured again
+	captured := hold
+	captured.Status = db.HoldCaptured
+	store.EXPECT().GetHold(gomock.Any(), gomock.Eq(hold.ID)).Times(1).Return(captured, nil)
+	_, err = service.CaptureCardHold(context.Background(), hold.ID, 0)
+	require.Equal(t, ReasonCardHoldClosed, ErrorReason(err))
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"errors"
+	db "go-backend/db/sqlc"
+	"time"
+)
+
+// The DepositChequeParams type is a cheque the user deposits on an account.
+// @property {int64} Amount - the positive amount of the cheque, in the currency of the account.
+// @property {string} ChequeNumber - the number printed on the cheque.
+type DepositChequeParams struct {
+	Owner        string
+	AccountID    int64
+	Amount       int64
+	ChequeNumber string
+}
+
+// The DepositCheque function deposits a cheque on an open account the user owns. Its amount is credited
+// to the account at once but held for the CHEQUE_CLEARING_PERIOD config, during which the cheque can
+// bounce, so that it can't be spent until the cheque clears.
+func (service *Service) DepositCheque(ctx context.Context, arg DepositChequeParams) (db.DepositChequeTxResult, error) {
+	if arg.Amount <= 0 {
+		return db.DepositChequeTxResult{}, errorf(CodeInvalidArgument, "amount must be positive, got %d", arg.Amount).withReason(ReasonInvalidAmount)
+	}
+
+	account, err := service.ownedAccount(ctx, arg.Owner, arg.AccountID)
+	if err != nil {
+		return db.DepositChequeTxResult{}, err
+	}
+	if account.ClosedAt.Valid {
+		return db.DepositChequeTxResult{}, errorf(CodeFailedPrecondition, "account [%d] is closed", account.ID).withReason(ReasonAccountClosed)
+	}
+
+	result, err := service.store.DepositChequeTx(ctx, db.DepositChequeTxParams{
+		AccountID:    account.ID,
+		Owner:        arg.Owner,
+		Amount:       arg.Amount,
+		ChequeNumber: arg.ChequeNumber,
+		ClearsAt:     time.Now().Add(service.config.ChequeClearingPeriod),
+	})
+	if err != nil {
+		return result, storeError(err)
+	}
+
+	return result, nil
+}
+
+// The GetChequeDeposit function returns a cheque deposit, provided the user deposited it.
+func (service *Service) GetChequeDeposit(ctx context.Context, owner string, id int64) (db.ChequeDeposit, error) {
+	deposit, err := service.store.GetChequeDeposit(ctx, id)
+	if err != nil {
+		return deposit, storeError(err)
+	}
+
+	if deposit.Owner != owner {
+		return deposit, newError(CodePermissionDenied, errors.New("cheque deposit doesn't belong to authenticated user"))
+	}
+
+	return deposit, nil
+}
+
+// The BounceChequeDeposit function reverses the credit of a pending cheque the bank of its drawer refused
+// to pay, debiting its amount from the account it was deposited on. A cheque can only bounce during its
+// clearing period.
+func (service *Service) BounceChequeDeposit(ctx context.Context, id int64) (db.BounceChequeDepositTxResult, error) {
+	result, err := service.store.BounceChequeDepositTx(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrChequeDepositClosed) {
+			return result, errorf(CodeFailedPrecondition, "cheque deposit [%d] is no longer pending", id).withReason(ReasonChequeDepositClosed)
+		}
+		return result, storeError(err)
+	}
+
+	return result, nil
+}
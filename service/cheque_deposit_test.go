@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/testutil/factory"
+	"go-backend/util"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDepositCheque(t *testing.T) {
+	owner := util.RandomOwner()
+	account := factory.Account(factory.OwnedBy(owner))
+	closed := factory.Account(factory.OwnedBy(owner), func(account *db.Account) {
+		account.ClosedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+	})
+
+	testCases := []struct {
+		name      string
+		arg       DepositChequeParams
+		buildStub func(store *mockdb.MockStore)
+		code      *Code
+		reason    string
+	}{
+		{
+			name: "OK",
+			arg:  DepositChequeParams{Owner: owner, AccountID: account.ID, Amount: 100, ChequeNumber: "0001"},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().DepositChequeTx(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ context.Context, arg db.DepositChequeTxParams) (db.DepositChequeTxResult, error) {
+						require.Equal(t, int64(100), arg.Amount)
+						// the amount is held for the clearing period
+						require.WithinDuration(t, time.Now().Add(72*time.Hour), arg.ClearsAt, time.Minute)
+						return db.DepositChequeTxResult{ChequeDeposit: db.ChequeDeposit{ID: 1, Status: db.ChequeDepositPending}}, nil
+					})
+			},
+		},
+		{
+			name: "InvalidAmount",
+			arg:  DepositChequeParams{Owner: owner, AccountID: account.ID, Amount: 0, ChequeNumber: "0001"},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().DepositChequeTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code:   codePtr(CodeInvalidArgument),
+			reason: ReasonInvalidAmount,
+		},
+		{
+			name: "AccountClosed",
+			arg:  DepositChequeParams{Owner: owner, AccountID: closed.ID, Amount: 100, ChequeNumber: "0001"},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(closed.ID)).Times(1).Return(closed, nil)
+				store.EXPECT().DepositChequeTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code:   codePtr(CodeFailedPrecondition),
+			reason: ReasonAccountClosed,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			service := newTestService(t, store)
+			service.config.ChequeClearingPeriod = 72 * time.Hour
+			_, err := service.DepositCheque(context.Background(), tc.arg)
+			if tc.code != nil {
+				require.Equal(t, *tc.code, ErrorCode(err))
+				require.Equal(t, tc.reason, ErrorReason(err))
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestBounceChequeDeposit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().BounceChequeDepositTx(gomock.Any(), gomock.Eq(int64(1))).Times(1).
+		Return(db.BounceChequeDepositTxResult{ChequeDeposit: db.ChequeDeposit{ID: 1, Status: db.ChequeDepositBounced}}, nil)
+	store.EXPECT().BounceChequeDepositTx(gomock.Any(), gomock.Eq(int64(2))).Times(1).
+		Return(db.BounceChequeDepositTxResult{}, db.ErrChequeDepositClosed)
+
+	service := newTestService(t, store)
+	result, err := service.BounceChequeDeposit(context.Background(), 1)
+	require.NoError(t, err)
+	require.Equal(t, db.ChequeDepositBounced, result.ChequeDeposit.Status)
+
+	// a cheque that cleared can't bounce
+	_, err = service.BounceChequeDeposit(context.Background(), 2)
+	require.Equal(t, CodeFailedPrecondition, ErrorCode(err))
+	require.Equal(t, ReasonChequeDepositClosed, ErrorReason(err))
+}
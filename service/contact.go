@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"errors"
+	db "go-backend/db/sqlc"
+)
+
+// MaxContactAccounts is the most accounts of a contact returned by a lookup.
+const MaxContactAccounts = 100
+
+// The Contact type is a user found by another one to send them money.
+// @property {db.User} User - the user, of whom only the public profile is to be shown.
+// @property {[]db.Account} Accounts - the accounts owned by the user, which transfers can be sent to.
+type Contact struct {
+	User     db.User
+	Accounts []db.Account
+}
+
+// The LookupContactParams type identifies the user looked up, by exactly one of their email and username.
+type LookupContactParams struct {
+	Email    string
+	Username string
+}
+
+// The LookupContact function finds a user by their email or username, along with the accounts they own,
+// for a user sending them money without knowing their account. The accounts shared with the user aren't
+// theirs to receive money, and the system user of the bank is never found.
+func (service *Service) LookupContact(ctx context.Context, arg LookupContactParams) (Contact, error) {
+	if (arg.Email == "") == (arg.Username == "") {
+		return Contact{}, newError(CodeInvalidArgument, errors.New("exactly one of email and username must be given"))
+	}
+
+	var user db.User
+	var err error
+	if arg.Email != "" {
+		user, err = service.store.GetUserByEmail(ctx, arg.Email)
+	} else {
+		user, err = service.store.GetUser(ctx, arg.Username)
+	}
+	if err != nil {
+		return Contact{}, storeError(err)
+	}
+	if user.Username == db.SystemOwner {
+		return Contact{}, storeError(db.ErrRecordNotFound)
+	}
+
+	accounts, err := service.store.ListAccounts(ctx, db.ListAccountsParams{
+		Owner: user.Username,
+		Limit: MaxContactAccounts,
+	})
+	if err != nil {
+		return Contact{}, storeError(err)
+	}
+
+	// the closed accounts can't receive transfers
+	open := accounts[:0]
+	for _, account := range accounts {
+		if !account.ClosedAt.Valid {
+			open = append(open, account)
+		}
+	}
+
+	return Contact{User: user, Accounts: open}, nil
+}
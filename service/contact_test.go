@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/testutil/factory"
+	"go-backend/util"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookupContact(t *testing.T) {
+	user := factory.User()
+	accounts := []db.Account{
+		factory.Account(factory.OwnedBy(user.Username), factory.InCurrency(util.USD)),
+		factory.Account(factory.OwnedBy(user.Username), factory.InCurrency(util.EUR)),
+	}
+
+	testCases := []struct {
+		name      string
+		arg       LookupContactParams
+		buildStub func(store *mockdb.MockStore)
+		code      *Code
+	}{
+		{
+			name: "ByEmail",
+			arg:  LookupContactParams{Email: user.Email},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserByEmail(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().
+					ListAccounts(gomock.Any(), gomock.Eq(db.ListAccountsParams{Owner: user.Username, Limit: MaxContactAccounts})).
+					Times(1).
+					Return(accounts, nil)
+			},
+		},
+		{
+			name: "ByUsername",
+			arg:  LookupContactParams{Username: user.Username},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().ListAccounts(gomock.Any(), gomock.Any()).Times(1).Return(accounts, nil)
+			},
+		},
+		{
+			name: "NotFound",
+			arg:  LookupContactParams{Email: "nobody@test.com"},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserByEmail(gomock.Any(), gomock.Any()).Times(1).Return(db.User{}, db.ErrRecordNotFound)
+				store.EXPECT().ListAccounts(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code: codePtr(CodeNotFound),
+		},
+		{
+			name: "SystemUser",
+			arg:  LookupContactParams{Username: db.SystemOwner},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(1).Return(db.User{Username: db.SystemOwner}, nil)
+				store.EXPECT().ListAccounts(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code: codePtr(CodeNotFound),
+		},
+		{
+			name: "EmailAndUsername",
+			arg:  LookupContactParams{Email: user.Email, Username: user.Username},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().GetUserByEmail(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code: codePtr(CodeInvalidArgument),
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			contact, err := newTestService(t, store).LookupContact(context.Background(), tc.arg)
+			if tc.code != nil {
+				require.Equal(t, *tc.code, ErrorCode(err))
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, user, contact.User)
+			require.Equal(t, accounts, contact.Accounts)
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	db "go-backend/db/sqlc"
+	"time"
+)
+
+// DashboardRecentEntries is the number of latest entries listed on the dashboard of a user.
+const DashboardRecentEntries = 10
+
+// The Dashboard type is what the home screen of the apps shows a user, read at once.
+// @property {[]db.SumOwnerBalancesRow} Balances - the total balance of the open accounts of the user by
+// currency, sorted by currency.
+// @property {[]db.ListEntriesWithCounterpartyRow} RecentEntries - the latest entries of the accounts of
+// the user, newest first.
+// @property {[]db.SumOwnerSpendingRow} Spending - what left the accounts of the user since the start of
+// the month, in UTC, by currency. The moves between the accounts of the user aren't counted.
+// @property {time.Time} SpendingSince - the start of the month the spending is counted from.
+// @property {db.CountOwnerPendingItemsRow} Pending - the number of items awaiting the user or still
+// being settled.
+type Dashboard struct {
+	Balances      []db.SumOwnerBalancesRow
+	RecentEntries []db.ListEntriesWithCounterpartyRow
+	Spending      []db.SumOwnerSpendingRow
+	SpendingSince time.Time
+	Pending       db.CountOwnerPendingItemsRow
+}
+
+// The GetDashboard function returns the dashboard of a user, each of its parts being computed by a
+// single aggregate query over the accounts the user owns, so that the apps get it in one call. The
+// accounts shared with the user aren't included.
+func (service *Service) GetDashboard(ctx context.Context, username string) (Dashboard, error) {
+	balances, err := service.store.SumOwnerBalances(ctx, username)
+	if err != nil {
+		return Dashboard{}, storeError(err)
+	}
+
+	rows, err := service.store.ListOwnerRecentEntries(ctx, db.ListOwnerRecentEntriesParams{
+		Owner: username,
+		Limit: DashboardRecentEntries,
+	})
+	if err != nil {
+		return Dashboard{}, storeError(err)
+	}
+	entries := make([]db.ListEntriesWithCounterpartyRow, 0, len(rows))
+	for _, row := range rows {
+		entries = append(entries, db.ListEntriesWithCounterpartyRow(row))
+	}
+
+	since := db.BudgetMonthStart(time.Now())
+	spending, err := service.store.SumOwnerSpending(ctx, db.SumOwnerSpendingParams{
+		Owner: username,
+		Since: since,
+	})
+	if err != nil {
+		return Dashboard{}, storeError(err)
+	}
+
+	pending, err := service.store.CountOwnerPendingItems(ctx, username)
+	if err != nil {
+		return Dashboard{}, storeError(err)
+	}
+
+	return Dashboard{
+		Balances:      balances,
+		RecentEntries: entries,
+		Spending:      spending,
+		SpendingSince: since,
+		Pending:       pending,
+	}, nil
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/util"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetDashboard(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	username := util.RandomOwner()
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().SumOwnerBalances(gomock.Any(), gomock.Eq(username)).Times(1).
+		Return([]db.SumOwnerBalancesRow{{Currency: util.USD, AccountCount: 2, Balance: 100, AvailableBalance: 90}}, nil)
+	store.EXPECT().ListOwnerRecentEntries(gomock.Any(), gomock.Eq(db.ListOwnerRecentEntriesParams{
+		Owner: username,
+		Limit: DashboardRecentEntries,
+	})).Times(1).Return([]db.ListOwnerRecentEntriesRow{{Entry: db.Entry{ID: 1}, Currency: util.USD}}, nil)
+	store.EXPECT().SumOwnerSpending(gomock.Any(), gomock.Any()).Times(1).
+		DoAndReturn(func(_ context.Context, arg db.SumOwnerSpendingParams) ([]db.SumOwnerSpendingRow, error) {
+			require.Equal(t, username, arg.Owner)
+			// the spending is counted since the start of the month
+			require.Equal(t, 1, arg.Since.Day())
+			require.Equal(t, time.UTC, arg.Since.Location())
+			return []db.SumOwnerSpendingRow{{Currency: util.USD, Spent: 20}}, nil
+		})
+	store.EXPECT().CountOwnerPendingItems(gomock.Any(), gomock.Eq(username)).Times(1).
+		Return(db.CountOwnerPendingItemsRow{PaymentRequests: 1}, nil)
+
+	dashboard, err := newTestService(t, store).GetDashboard(context.Background(), username)
+	require.NoError(t, err)
+	require.Len(t, dashboard.Balances, 1)
+	require.Equal(t, []db.ListEntriesWithCounterpartyRow{{Entry: db.Entry{ID: 1}, Currency: util.USD}}, dashboard.RecentEntries)
+	require.Equal(t, int64(20), dashboard.Spending[0].Spent)
+	require.Equal(t, int64(1), dashboard.Pending.PaymentRequests)
+}
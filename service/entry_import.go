@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	db "go-backend/db/sqlc"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxImportEntries is the most entries a file can import at once.
+const MaxImportEntries = 10000
+
+// The ImportEntriesParams type is a CSV file of the history of an account kept by another system.
+// @property {io.Reader} CSV - the file, whose header names a `date` and an `amount` column, the other
+// columns being ignored.
+// @property {bool} DryRun - validates the file and previews the import without applying it.
+type ImportEntriesParams struct {
+	AccountID int64
+	CSV       io.Reader
+	DryRun    bool
+}
+
+// The ImportError type is a problem found while validating a file, which prevents importing it.
+// @property {int} Line - the line of the file with the problem, 0 for the problems of the whole file.
+type ImportError struct {
+	Line    int    `json:"line,omitempty"`
+	Message string `json:"message"`
+}
+
+// The EntryImport type is the outcome of an import, or its preview for a dry run.
+// @property {int64} Balance - the balance of the account before the import.
+// @property {int64} NewBalance - the balance of the account once the entries are imported.
+// @property {[]ImportError} Errors - the problems found, always empty once imported.
+type EntryImport struct {
+	AccountID  int64              `json:"account_id"`
+	DryRun     bool               `json:"dry_run"`
+	Entries    []db.ImportedEntry `json:"entries"`
+	Total      int64              `json:"total"`
+	Balance    int64              `json:"balance"`
+	NewBalance int64              `json:"new_balance"`
+	Errors     []ImportError      `json:"errors"`
+}
+
+// The ImportEntries function imports the entries of a CSV file into an account, e.g. when migrating it
+// from another system, keeping the dates they were made at. The file is validated as a whole first and
+// the entries are imported in a single transaction, oldest first, so that none is imported when any is
+// invalid or the balance would end up negative. A dry run returns the preview of the import with the
+// problems found instead of failing.
+func (service *Service) ImportEntries(ctx context.Context, arg ImportEntriesParams) (EntryImport, error) {
+	entries, errs, err := parseImportedEntries(arg.CSV, time.Now())
+	if err != nil {
+		return EntryImport{}, newError(CodeInvalidArgument, err)
+	}
+
+	account, err := service.store.GetAccount(ctx, arg.AccountID)
+	if err != nil {
+		return EntryImport{}, storeError(err)
+	}
+	if db.IsSystemAccount(account) {
+		return EntryImport{}, newError(CodePermissionDenied, db.ErrSystemAccount)
+	}
+
+	res := EntryImport{
+		AccountID: account.ID,
+		DryRun:    arg.DryRun,
+		Entries:   entries,
+		Balance:   account.Balance,
+		Errors:    errs,
+	}
+	for _, entry := range entries {
+		res.Total += entry.Amount
+	}
+	res.NewBalance = res.Balance + res.Total
+
+	if arg.DryRun {
+		if res.NewBalance < 0 {
+			res.Errors = append(res.Errors, ImportError{Message: db.ErrImportNegativeBalance.Error()})
+		}
+		return res, nil
+	}
+	if len(res.Errors) > 0 {
+		return EntryImport{}, importError(res.Errors)
+	}
+	if res.NewBalance < 0 {
+		return EntryImport{}, newError(CodeInvalidArgument, db.ErrImportNegativeBalance).withReason(ReasonNegativeBalance)
+	}
+
+	result, err := service.store.ImportEntriesTx(ctx, db.ImportEntriesTxParams{
+		AccountID: account.ID,
+		Entries:   entries,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrImportNegativeBalance) {
+			return EntryImport{}, newError(CodeInvalidArgument, err).withReason(ReasonNegativeBalance)
+		}
+		return EntryImport{}, storeError(err)
+	}
+
+	// the balance may have changed since it was read
+	res.NewBalance = result.Account.Balance
+	res.Balance = res.NewBalance - res.Total
+	return res, nil
+}
+
+// importError returns the error an import fails with for the problems found in the rows of its file,
+// which names the first ones.
+func importError(errs []ImportError) error {
+	const shown = 3
+
+	messages := make([]string, 0, shown)
+	for _, e := range errs {
+		if len(messages) == shown {
+			break
+		}
+		messages = append(messages, fmt.Sprintf("line %d: %s", e.Line, e.Message))
+	}
+
+	message := strings.Join(messages, "; ")
+	if len(errs) > shown {
+		message += fmt.Sprintf(" and %d more errors", len(errs)-shown)
+	}
+
+	return errorf(CodeInvalidArgument, "invalid import: %s", message)
+}
+
+// parseImportedEntries reads the entries of a CSV file, sorted oldest first, along with the problems of
+// its rows. It fails when the file itself can't be read, e.g. without a header naming the columns of
+// the entries. Dates are either RFC 3339 times or days, taken at midnight UTC, and can't be after `now`.
+func parseImportedEntries(r io.Reader, now time.Time) ([]db.ImportedEntry, []ImportError, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil, errors.New("the file is empty")
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot read the file: %w", err)
+	}
+
+	dateColumn, amountColumn := -1, -1
+	for i, name := range header {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "date":
+			dateColumn = i
+		case "amount":
+			amountColumn = i
+		}
+	}
+	if dateColumn < 0 || amountColumn < 0 {
+		return nil, nil, errors.New("the header of the file must name a date and an amount column")
+	}
+
+	entries := []db.ImportedEntry{}
+	errs := []ImportError{}
+	for rows := 0; ; rows++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("cannot read the file: %w", err)
+		}
+
+		if rows == MaxImportEntries {
+			return nil, nil, fmt.Errorf("a file imports at most %d entries", MaxImportEntries)
+		}
+
+		line, _ := reader.FieldPos(0)
+		entry, err := parseImportedEntry(record[dateColumn], record[amountColumn], now)
+		if err != nil {
+			errs = append(errs, ImportError{Line: line, Message: err.Error()})
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	if len(entries)+len(errs) == 0 {
+		return nil, nil, errors.New("the file has no entries")
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].CreatedAt.Before(entries[j].CreatedAt)
+	})
+	return entries, errs, nil
+}
+
+func parseImportedEntry(date string, amount string, now time.Time) (db.ImportedEntry, error) {
+	date = strings.TrimSpace(date)
+	createdAt, err := time.Parse(time.RFC3339, date)
+	if err != nil {
+		createdAt, err = time.Parse("2006-01-02", date)
+		if err != nil {
+			return db.ImportedEntry{}, fmt.Errorf("invalid date %q, must be an RFC 3339 time or a YYYY-MM-DD day", date)
+		}
+	}
+	if createdAt.After(now) {
+		return db.ImportedEntry{}, fmt.Errorf("date %s is in the future", date)
+	}
+
+	value, err := strconv.ParseInt(strings.TrimSpace(amount), 10, 64)
+	if err != nil {
+		return db.ImportedEntry{}, fmt.Errorf("invalid amount %q, must be a whole number", amount)
+	}
+	if value == 0 {
+		return db.ImportedEntry{}, errors.New("amount must not be 0")
+	}
+
+	return db.ImportedEntry{Amount: value, CreatedAt: createdAt.UTC()}, nil
+}
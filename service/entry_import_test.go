@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/testutil/factory"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseImportedEntries(t *testing.T) {
+	now := time.Date(2026, time.October, 16, 12, 0, 0, 0, time.UTC)
+
+	entries, errs, err := parseImportedEntries(strings.NewReader(
+		"Date,Description,Amount\n"+
+			"2026-03-02,salary,1500\n"+
+			"2026-03-01T10:30:00-05:00,opening balance,200\n"+
+			"yesterday,rent,-900\n"+
+			"2026-03-04,rent,-9.50\n"+
+			"2026-03-05,nothing,0\n"+
+			"2026-11-01,later,10\n",
+	), now)
+	require.NoError(t, err)
+
+	// the entries are sorted oldest first
+	require.Equal(t, []db.ImportedEntry{
+		{Amount: 200, CreatedAt: time.Date(2026, time.March, 1, 15, 30, 0, 0, time.UTC)},
+		{Amount: 1500, CreatedAt: time.Date(2026, time.March, 2, 0, 0, 0, 0, time.UTC)},
+	}, entries)
+
+	require.Len(t, errs, 4)
+	require.Equal(t, 4, errs[0].Line)
+	require.Contains(t, errs[0].Message, "invalid date")
+	require.Equal(t, 5, errs[1].Line)
+	require.Contains(t, errs[1].Message, "invalid amount")
+	require.Equal(t, 6, errs[2].Line)
+	require.Equal(t, 7, errs[3].Line)
+	require.Contains(t, errs[3].Message, "in the future")
+
+	testCases := []struct {
+		name string
+		csv  string
+	}{
+		{name: "Empty", csv: ""},
+		{name: "HeaderOnly", csv: "date,amount\n"},
+		{name: "MissingAmountColumn", csv: "date,value\n2026-03-01,10\n"},
+		{name: "WrongNumberOfFields", csv: "date,amount\n2026-03-01,10,extra\n"},
+		{name: "TooManyEntries", csv: "date,amount\n" + strings.Repeat("2026-03-01,10\n", MaxImportEntries+1)},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, err := parseImportedEntries(strings.NewReader(tc.csv), now)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestImportEntries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	service := newTestService(t, store)
+
+	account := factory.Account()
+	account.Balance = 100
+	csv := "date,amount\n2024-01-01,50\n2024-01-02,-20\n"
+	entries := []db.ImportedEntry{
+		{Amount: 50, CreatedAt: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{Amount: -20, CreatedAt: time.Date(2024, time.January, 2, 0, 0, 0, 0, time.UTC)},
+	}
+
+	// a dry run only reads the account
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(2).Return(account, nil)
+	store.EXPECT().ImportEntriesTx(gomock.Any(), gomock.Any()).Times(0)
+
+	preview, err := service.ImportEntries(context.Background(), ImportEntriesParams{
+		AccountID: account.ID,
+		CSV:       strings.NewReader(csv),
+		DryRun:    true,
+	})
+	require.NoError(t, err)
+	require.True(t, preview.DryRun)
+	require.Equal(t, entries, preview.Entries)
+	require.Equal(t, int64(30), preview.Total)
+	require.Equal(t, int64(100), preview.Balance)
+	require.Equal(t, int64(130), preview.NewBalance)
+	require.Empty(t, preview.Errors)
+
+	// the preview reports the balance the import would leave negative
+	preview, err = service.ImportEntries(context.Background(), ImportEntriesParams{
+		AccountID: account.ID,
+		CSV:       strings.NewReader("date,amount\n2024-01-01,-150\n"),
+		DryRun:    true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []ImportError{{Message: db.ErrImportNegativeBalance.Error()}}, preview.Errors)
+
+	updated := account
+	updated.Balance = 130
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+	store.EXPECT().
+		ImportEntriesTx(gomock.Any(), gomock.Eq(db.ImportEntriesTxParams{AccountID: account.ID, Entries: entries})).
+		Times(1).
+		Return(db.ImportEntriesTxResult{Account: updated}, nil)
+
+	res, err := service.ImportEntries(context.Background(), ImportEntriesParams{
+		AccountID: account.ID,
+		CSV:       strings.NewReader(csv),
+	})
+	require.NoError(t, err)
+	require.False(t, res.DryRun)
+	require.Equal(t, int64(130), res.NewBalance)
+}
+
+func TestImportEntriesInvalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	service := newTestService(t, store)
+
+	account := factory.Account()
+	account.Balance = 100
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).AnyTimes().Return(account, nil)
+	store.EXPECT().ImportEntriesTx(gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := service.ImportEntries(context.Background(), ImportEntriesParams{
+		AccountID: account.ID,
+		CSV:       strings.NewReader("date,amount\n2024-01-01,x\n2024-01-02,0\n2024-01-03,5\n2024-01-04,y\n2024-01-05,z\n"),
+	})
+	require.Equal(t, CodeInvalidArgument, ErrorCode(err))
+	require.EqualError(t, err, `invalid import: line 2: invalid amount "x", must be a whole number; line 3: amount must not be 0; line 5: invalid amount "y", must be a whole number and 1 more errors`)
+
+	_, err = service.ImportEntries(context.Background(), ImportEntriesParams{
+		AccountID: account.ID,
+		CSV:       strings.NewReader("date,amount\n2024-01-01,-101\n"),
+	})
+	require.Equal(t, CodeInvalidArgument, ErrorCode(err))
+	require.Equal(t, ReasonNegativeBalance, ErrorReason(err))
+
+	systemAccount := factory.Account(factory.OwnedBy(db.SystemOwner))
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(systemAccount.ID)).Times(1).Return(systemAccount, nil)
+	_, err = service.ImportEntries(context.Background(), ImportEntriesParams{
+		AccountID: systemAccount.ID,
+		CSV:       strings.NewReader("date,amount\n2024-01-01,10\n"),
+	})
+	require.Equal(t, CodePermissionDenied, ErrorCode(err))
+}
@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	db "go-backend/db/sqlc"
+)
+
+// The Code type classifies the errors returned by the service, so that every transport can map them to
+// its own status codes.
+type Code int
+
+const (
+	// CodeInternal is an unexpected failure, e.g. of the database.
+	CodeInternal Code = iota
+	// CodeInvalidArgument is a request that can't be served as it is.
+	CodeInvalidArgument
+	// CodeNotFound is a request for a resource that doesn't exist.
+	CodeNotFound
+	// CodeUnauthenticated is a request with invalid credentials.
+	CodeUnauthenticated
+	// CodePermissionDenied is a request for a resource of another user.
+	CodePermissionDenied
+	// CodeAlreadyExists is a request creating a resource that already exists.
+	CodeAlreadyExists
+	// CodeFailedPrecondition is a request that doesn't apply to the current state of the resource.
+	CodeFailedPrecondition
+	// CodeUnavailable is a request that failed as the database couldn't be reached, and can be retried.
+	CodeUnavailable
+	// CodeLocked is a request of a user locked out for a while, e.g. after too many failed logins.
+	CodeLocked
+	// CodeDeadlineExceeded is a request whose queries were cancelled as it took longer than its timeout.
+	CodeDeadlineExceeded
+	// CodeResourceExhausted is a request of a user who used up their quota, e.g. of API calls, or of a
+	// client that failed to log in or signed up too many times.
+	CodeResourceExhausted
+)
+
+// Reasons refining the code of some errors, so that clients can tell them apart from other errors with
+// the same code.
+const (
+	ReasonInvalidAmount          = "INVALID_AMOUNT"
+	ReasonCurrencyMismatch       = "CURRENCY_MISMATCH"
+	ReasonTransferLimitExceeded  = "TRANSFER_LIMIT_EXCEEDED"
+	ReasonNegativeBalance        = "NEGATIVE_BALANCE"
+	ReasonAccountHasHistory      = "ACCOUNT_HAS_HISTORY"
+	ReasonInvalidCredentials     = "INVALID_CREDENTIALS"
+	ReasonIdentityLinkRequired   = "IDENTITY_LINK_REQUIRED"
+	ReasonAccountLocked          = "ACCOUNT_LOCKED"
+	ReasonTooManyLoginFailures   = "TOO_MANY_LOGIN_FAILURES"
+	ReasonTooManySignups         = "TOO_MANY_SIGNUPS"
+	ReasonIdempotencyKeyRequired = "IDEMPOTENCY_KEY_REQUIRED"
+	ReasonSessionBlocked         = "SESSION_BLOCKED"
+	ReasonSessionExpired         = "SESSION_EXPIRED"
+	ReasonSessionIdle            = "SESSION_IDLE"
+	ReasonJobFinished            = "JOB_FINISHED"
+	ReasonJobNotSucceeded        = "JOB_NOT_SUCCEEDED"
+	ReasonReviewClosed           = "REVIEW_CLOSED"
+	ReasonReviewRequired         = "REVIEW_REQUIRED"
+	ReasonApprovalRequired       = "APPROVAL_REQUIRED"
+	ReasonPaymentRequestClosed   = "PAYMENT_REQUEST_CLOSED"
+	ReasonPaymentRequestExpired  = "PAYMENT_REQUEST_EXPIRED"
+	ReasonPendingTransferClosed  = "PENDING_TRANSFER_CLOSED"
+	ReasonPendingTransferExpired = "PENDING_TRANSFER_EXPIRED"
+	ReasonUsernameTaken          = "USERNAME_TAKEN"
+	ReasonEmailTaken             = "EMAIL_TAKEN"
+	ReasonNoExchangeRate         = "NO_EXCHANGE_RATE"
+	ReasonMandateRevoked         = "MANDATE_REVOKED"
+	ReasonMandateCapExceeded     = "MANDATE_CAP_EXCEEDED"
+	ReasonMandateLimitExceeded   = "MANDATE_LIMIT_EXCEEDED"
+	ReasonVersionMismatch        = "VERSION_MISMATCH"
+	ReasonQuotaExceeded          = "QUOTA_EXCEEDED"
+	ReasonAccountClosed          = "ACCOUNT_CLOSED"
+	ReasonAccountNotEmpty        = "ACCOUNT_NOT_EMPTY"
+	ReasonDeletionScheduled      = "DELETION_SCHEDULED"
+	ReasonDeletionNotScheduled   = "DELETION_NOT_SCHEDULED"
+	ReasonCardFrozen             = "CARD_FROZEN"
+	ReasonCardExpired            = "CARD_EXPIRED"
+	ReasonInsufficientFunds      = "INSUFFICIENT_FUNDS"
+	ReasonCardHoldClosed         = "CARD_HOLD_CLOSED"
+	ReasonCardHoldExpired        = "CARD_HOLD_EXPIRED"
+	ReasonChequeDepositClosed    = "CHEQUE_DEPOSIT_CLOSED"
+	ReasonStandingOrderCancelled = "STANDING_ORDER_CANCELLED"
+	ReasonPaymentLinkClosed      = "PAYMENT_LINK_CLOSED"
+	ReasonPaymentLinkExpired     = "PAYMENT_LINK_EXPIRED"
+)
+
+// The Error type is an error returned by the service along with its code and, for some errors, the
+// reason refining the code and the field of the request it is about.
+type Error struct {
+	Code   Code
+	Reason string
+	Field  string
+	Err    error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// ErrorCode returns the code of an error returned by the service, errors that weren't classified by the
+// service are internal.
+func ErrorCode(err error) Code {
+	var serviceErr *Error
+	if errors.As(err, &serviceErr) {
+		return serviceErr.Code
+	}
+	return CodeInternal
+}
+
+// ErrorReason returns the reason of an error returned by the service, or an empty string when the code
+// is all there is to know.
+func ErrorReason(err error) string {
+	var serviceErr *Error
+	if errors.As(err, &serviceErr) {
+		return serviceErr.Reason
+	}
+	return ""
+}
+
+// ErrorField returns the field of the request an error returned by the service is about, e.g. the one
+// colliding with an existing resource, or an empty string when it isn't about a single field.
+func ErrorField(err error) string {
+	var serviceErr *Error
+	if errors.As(err, &serviceErr) {
+		return serviceErr.Field
+	}
+	return ""
+}
+
+func (e *Error) withField(field string) *Error {
+	e.Field = field
+	return e
+}
+
+func (e *Error) withReason(reason string) *Error {
+	e.Reason = reason
+	return e
+}
+
+func newError(code Code, err error) *Error {
+	return &Error{Code: code, Err: err}
+}
+
+func errorf(code Code, format string, a ...any) *Error {
+	return &Error{Code: code, Err: fmt.Errorf(format, a...)}
+}
+
+// storeError classifies an error returned by the store: missing rows are not found and unique or
+// foreign key violations mean the resource already exists (or can't be created for a missing user). A
+// debit above the available balance of an account fails its precondition, like a change of an account
+// updated since it was read. A database that couldn't be
+// reached makes the request unavailable, and queries cancelled past the deadline of the request make it
+// exceed its deadline.
+func storeError(err error) error {
+	err = db.TranslateError(err)
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return newError(CodeDeadlineExceeded, err)
+	case errors.Is(err, db.ErrRecordNotFound):
+		return newError(CodeNotFound, err)
+	case errors.Is(err, db.ErrUniqueViolation), errors.Is(err, db.ErrForeignKeyViolation):
+		return newError(CodeAlreadyExists, err)
+	case errors.Is(err, db.ErrSystemAccount):
+		return newError(CodePermissionDenied, err)
+	case errors.Is(err, db.ErrInsufficientAvailableBalance):
+		return newError(CodeFailedPrecondition, err).withReason(ReasonInsufficientFunds)
+	case errors.Is(err, db.ErrAccountVersionMismatch):
+		return newError(CodeFailedPrecondition, err).withReason(ReasonVersionMismatch)
+	case errors.Is(err, db.ErrAccountClosed):
+		return newError(CodeFailedPrecondition, err).withReason(ReasonAccountClosed)
+	case errors.Is(err, db.ErrCurrencyMismatch):
+		return newError(CodeFailedPrecondition, err).withReason(ReasonCurrencyMismatch)
+	case db.Unavailable(err):
+		return newError(CodeUnavailable, err)
+	}
+
+	return newError(CodeInternal, err)
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"errors"
+	db "go-backend/db/sqlc"
+	"go-backend/worker"
+	"log"
+
+	"github.com/hibiken/asynq"
+)
+
+// The InitiateExternalTransferParams type is money sent out of the bank to an account at another bank.
+// @property {string} Owner - the user sending the money, who must be able to use the account as an owner.
+// @property {int64} AccountID - the account the money is sent from.
+// @property {string} Rail - ach or wire, the network the money is sent through.
+// @property {int64} Amount - the positive amount sent, in the currency of the account.
+// @property {string} RoutingNumber - the routing number of the receiving bank.
+// @property {string} AccountNumber - the account at the receiving bank.
+// @property {string} BeneficiaryName - the holder of the account at the receiving bank.
+// @property {string} Memo - optional free text kept on the transfer.
+type InitiateExternalTransferParams struct {
+	Owner           string
+	AccountID       int64
+	Rail            string
+	Amount          int64
+	Currency        string
+	RoutingNumber   string
+	AccountNumber   string
+	BeneficiaryName string
+	Memo            string
+}
+
+// The InitiateExternalTransfer function sends money out of the bank. The amount leaves the account right
+// away for the clearing account of its currency, and the transfer is settled in the background after
+// the delay of its rail, the owner being notified of every change of its status. A transfer returned by
+// the receiving bank gives the amount back to the account. The transfer is screened like the transfers
+// between accounts, one flagged by the screening being refused.
+func (service *Service) InitiateExternalTransfer(ctx context.Context, arg InitiateExternalTransferParams) (db.ExternalTransfer, error) {
+	if arg.Amount <= 0 {
+		return db.ExternalTransfer{}, errorf(CodeInvalidArgument, "amount must be positive, got %d", arg.Amount).withReason(ReasonInvalidAmount)
+	}
+	if arg.Rail != db.ExternalTransferACH && arg.Rail != db.ExternalTransferWire {
+		return db.ExternalTransfer{}, errorf(CodeInvalidArgument, "unsupported rail %s", arg.Rail)
+	}
+	if service.taskDistributor == nil {
+		return db.ExternalTransfer{}, newError(CodeInternal, errors.New("external transfers can't be settled without a task distributor"))
+	}
+
+	account, err := service.ownedAccount(ctx, arg.Owner, arg.AccountID)
+	if err != nil {
+		return db.ExternalTransfer{}, err
+	}
+	if account.Currency != arg.Currency {
+		return db.ExternalTransfer{}, errorf(CodeInvalidArgument, "account [%d] currency mismatch: %s vs %s", account.ID, account.Currency, arg.Currency).withReason(ReasonCurrencyMismatch)
+	}
+
+	limit, ok, err := service.activeParameter(ctx, db.ParameterTransferLimit)
+	if err != nil {
+		return db.ExternalTransfer{}, err
+	}
+	if ok && arg.Amount > limit {
+		return db.ExternalTransfer{}, transferLimitError(arg.Amount, limit)
+	}
+
+	holdReason, err := service.screen(ctx, CreateTransferParams{
+		Owner:         arg.Owner,
+		FromAccountID: account.ID,
+		Amount:        arg.Amount,
+		Currency:      account.Currency,
+		Memo:          arg.Memo,
+	})
+	if err != nil {
+		return db.ExternalTransfer{}, err
+	}
+	if holdReason != "" {
+		return db.ExternalTransfer{}, errorf(CodeFailedPrecondition, "external transfer must be reviewed: %s", holdReason).withReason(ReasonReviewRequired)
+	}
+
+	err = service.requireNoApproval(ctx, account, arg.Amount, "external transfer")
+	if err != nil {
+		return db.ExternalTransfer{}, err
+	}
+
+	result, err := service.store.InitiateExternalTransferTx(ctx, db.InitiateExternalTransferTxParams{
+		Owner:           arg.Owner,
+		AccountID:       account.ID,
+		Rail:            arg.Rail,
+		Amount:          arg.Amount,
+		Currency:        account.Currency,
+		RoutingNumber:   arg.RoutingNumber,
+		AccountNumber:   arg.AccountNumber,
+		BeneficiaryName: arg.BeneficiaryName,
+		Memo:            arg.Memo,
+	})
+	if err != nil {
+		return result.ExternalTransfer, storeError(err)
+	}
+
+	opts := []asynq.Option{
+		asynq.ProcessIn(worker.ExternalTransferSubmissionDelay),
+		asynq.Queue(worker.QueueCritical),
+	}
+	err = service.taskDistributor.DistributeTaskExternalTransfer(ctx, &worker.PayloadExternalTransfer{
+		ID:     result.ExternalTransfer.ID,
+		Status: db.ExternalTransferInitiated,
+	}, opts...)
+	// the amount already left the account, so the transfer is made anyway and its submission is
+	// enqueued again by the end-of-day runner
+	if err != nil {
+		log.Printf("cannot enqueue external transfer [%d]: %v", result.ExternalTransfer.ID, err)
+	}
+
+	return result.ExternalTransfer, nil
+}
+
+// The GetExternalTransfer function returns an external transfer, provided the user sent it or shares
+// the account it was sent from.
+func (service *Service) GetExternalTransfer(ctx context.Context, username string, id int64) (db.ExternalTransfer, error) {
+	external, err := service.store.GetExternalTransfer(ctx, id)
+	if err != nil {
+		return external, storeError(err)
+	}
+
+	if external.Owner == username {
+		return external, nil
+	}
+
+	_, err = service.GetAccount(ctx, username, external.AccountID)
+	if err != nil {
+		if ErrorCode(err) == CodePermissionDenied {
+			return external, newError(CodePermissionDenied, errors.New("external transfer doesn't concern authenticated user"))
+		}
+		return external, err
+	}
+
+	return external, nil
+}
+
+// The ListExternalTransfers function lists the external transfers sent by the user, oldest first.
+func (service *Service) ListExternalTransfers(ctx context.Context, owner string, limit int32, offset int32) ([]db.ExternalTransfer, error) {
+	transfers, err := service.store.ListExternalTransfers(ctx, db.ListExternalTransfersParams{
+		Owner:  owner,
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return nil, storeError(err)
+	}
+
+	return transfers, nil
+}
@@ -0,0 +1,235 @@
+package service
+
+import (
+	"context"
+	"errors"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/testutil/factory"
+	"go-backend/util"
+	"go-backend/worker"
+	mockwk "go-backend/worker/mock"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInitiateExternalTransfer(t *testing.T) {
+	owner := util.RandomOwner()
+	account := factory.Account(factory.OwnedBy(owner), factory.InCurrency(util.USD))
+	external := db.ExternalTransfer{ID: 7, Owner: owner, AccountID: account.ID, Status: db.ExternalTransferInitiated}
+
+	arg := InitiateExternalTransferParams{
+		Owner:           owner,
+		AccountID:       account.ID,
+		Rail:            db.ExternalTransferACH,
+		Amount:          100,
+		Currency:        util.USD,
+		RoutingNumber:   "021000021",
+		AccountNumber:   "123456789",
+		BeneficiaryName: "Bob",
+	}
+
+	testCases := []struct {
+		name      string
+		arg       func() InitiateExternalTransferParams
+		buildStub func(store *mockdb.MockStore, distributor *mockwk.MockTaskDistributor)
+		code      *Code
+		reason    string
+	}{
+		{
+			name: "OK",
+			arg:  func() InitiateExternalTransferParams { return arg },
+			buildStub: func(store *mockdb.MockStore, distributor *mockwk.MockTaskDistributor) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetActiveBankParameter(gomock.Any(), gomock.Any()).Times(1).Return(db.BankParameter{}, db.ErrRecordNotFound)
+				store.EXPECT().
+					InitiateExternalTransferTx(gomock.Any(), gomock.Eq(db.InitiateExternalTransferTxParams{
+						Owner:           owner,
+						AccountID:       account.ID,
+						Rail:            db.ExternalTransferACH,
+						Amount:          100,
+						Currency:        util.USD,
+						RoutingNumber:   "021000021",
+						AccountNumber:   "123456789",
+						BeneficiaryName: "Bob",
+					})).
+					Times(1).
+					Return(db.InitiateExternalTransferTxResult{ExternalTransfer: external}, nil)
+				distributor.EXPECT().
+					DistributeTaskExternalTransfer(gomock.Any(), gomock.Eq(&worker.PayloadExternalTransfer{ID: external.ID, Status: db.ExternalTransferInitiated}), gomock.Any()).
+					Times(1).
+					Return(nil)
+			},
+		},
+		{
+			name: "ApprovalRequired",
+			arg:  func() InitiateExternalTransferParams { return arg },
+			buildStub: func(store *mockdb.MockStore, distributor *mockwk.MockTaskDistributor) {
+				orgAccount := account
+				orgAccount.OrgID = 7
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(orgAccount, nil)
+				store.EXPECT().GetActiveBankParameter(gomock.Any(), gomock.Any()).Times(1).Return(db.BankParameter{}, db.ErrRecordNotFound)
+				store.EXPECT().GetOrganization(gomock.Any(), gomock.Eq(int64(7))).Times(1).Return(db.Organization{ID: 7, ApprovalThreshold: 100}, nil)
+				store.EXPECT().InitiateExternalTransferTx(gomock.Any(), gomock.Any()).Times(0)
+				distributor.EXPECT().DistributeTaskExternalTransfer(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			code:   codePtr(CodeFailedPrecondition),
+			reason: ReasonApprovalRequired,
+		},
+		{
+			name: "InvalidAmount",
+			arg: func() InitiateExternalTransferParams {
+				invalid := arg
+				invalid.Amount = 0
+				return invalid
+			},
+			buildStub: func(store *mockdb.MockStore, distributor *mockwk.MockTaskDistributor) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code:   codePtr(CodeInvalidArgument),
+			reason: ReasonInvalidAmount,
+		},
+		{
+			name: "UnsupportedRail",
+			arg: func() InitiateExternalTransferParams {
+				invalid := arg
+				invalid.Rail = "swift"
+				return invalid
+			},
+			buildStub: func(store *mockdb.MockStore, distributor *mockwk.MockTaskDistributor) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code: codePtr(CodeInvalidArgument),
+		},
+		{
+			name: "CurrencyMismatch",
+			arg: func() InitiateExternalTransferParams {
+				invalid := arg
+				invalid.Currency = util.EUR
+				return invalid
+			},
+			buildStub: func(store *mockdb.MockStore, distributor *mockwk.MockTaskDistributor) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().InitiateExternalTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code:   codePtr(CodeInvalidArgument),
+			reason: ReasonCurrencyMismatch,
+		},
+		{
+			name: "TransferLimitExceeded",
+			arg:  func() InitiateExternalTransferParams { return arg },
+			buildStub: func(store *mockdb.MockStore, distributor *mockwk.MockTaskDistributor) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetActiveBankParameter(gomock.Any(), gomock.Any()).Times(1).Return(db.BankParameter{Value: 50}, nil)
+				store.EXPECT().InitiateExternalTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code:   codePtr(CodeInvalidArgument),
+			reason: ReasonTransferLimitExceeded,
+		},
+		{
+			name: "NotOwner",
+			arg: func() InitiateExternalTransferParams {
+				invalid := arg
+				invalid.Owner = util.RandomOwner()
+				return invalid
+			},
+			buildStub: func(store *mockdb.MockStore, distributor *mockwk.MockTaskDistributor) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(db.AccountMember{}, db.ErrRecordNotFound)
+				store.EXPECT().InitiateExternalTransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code: codePtr(CodePermissionDenied),
+		},
+		{
+			name: "EnqueueFailed",
+			arg:  func() InitiateExternalTransferParams { return arg },
+			buildStub: func(store *mockdb.MockStore, distributor *mockwk.MockTaskDistributor) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetActiveBankParameter(gomock.Any(), gomock.Any()).Times(1).Return(db.BankParameter{}, db.ErrRecordNotFound)
+				store.EXPECT().InitiateExternalTransferTx(gomock.Any(), gomock.Any()).Times(1).Return(db.InitiateExternalTransferTxResult{ExternalTransfer: external}, nil)
+				distributor.EXPECT().DistributeTaskExternalTransfer(gomock.Any(), gomock.Any(), gomock.Any()).Times(1).Return(errors.New("redis down"))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			distributor := mockwk.NewMockTaskDistributor(ctrl)
+			tc.buildStub(store, distributor)
+
+			service := newTestService(t, store)
+			service.taskDistributor = distributor
+
+			_, err := service.InitiateExternalTransfer(context.Background(), tc.arg())
+			if tc.code == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.Equal(t, *tc.code, ErrorCode(err))
+			if tc.reason != "" {
+				require.Equal(t, tc.reason, ErrorReason(err))
+			}
+		})
+	}
+}
+
+func TestInitiateExternalTransferScreened(t *testing.T) {
+	owner := util.RandomOwner()
+	account := factory.Account(factory.OwnedBy(owner), factory.InCurrency(util.USD))
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+	store.EXPECT().GetActiveBankParameter(gomock.Any(), gomock.Any()).Times(1).Return(db.BankParameter{}, db.ErrRecordNotFound)
+	store.EXPECT().InitiateExternalTransferTx(gomock.Any(), gomock.Any()).Times(0)
+
+	service := newTestService(t, store)
+	service.taskDistributor = mockwk.NewMockTaskDistributor(ctrl)
+	service.RegisterScreener(AmountScreener{Threshold: 100})
+
+	_, err := service.InitiateExternalTransfer(context.Background(), InitiateExternalTransferParams{
+		Owner:           owner,
+		AccountID:       account.ID,
+		Rail:            db.ExternalTransferWire,
+		Amount:          100,
+		Currency:        util.USD,
+		RoutingNumber:   "021000021",
+		AccountNumber:   "123456789",
+		BeneficiaryName: "Bob",
+	})
+	require.Equal(t, CodeFailedPrecondition, ErrorCode(err))
+	require.Equal(t, ReasonReviewRequired, ErrorReason(err))
+}
+
+func TestGetExternalTransfer(t *testing.T) {
+	owner := util.RandomOwner()
+	account := factory.Account(factory.OwnedBy(owner))
+	external := db.ExternalTransfer{ID: 7, Owner: owner, AccountID: account.ID}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetExternalTransfer(gomock.Any(), gomock.Eq(external.ID)).Times(2).Return(external, nil)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+	store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(db.AccountMember{}, db.ErrRecordNotFound)
+
+	service := newTestService(t, store)
+
+	got, err := service.GetExternalTransfer(context.Background(), owner, external.ID)
+	require.NoError(t, err)
+	require.Equal(t, external, got)
+
+	_, err = service.GetExternalTransfer(context.Background(), util.RandomOwner(), external.ID)
+	require.Equal(t, CodePermissionDenied, ErrorCode(err))
+}
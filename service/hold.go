@@ -0,0 +1,27 @@
+package service
+
+import (
+	"context"
+	db "go-backend/db/sqlc"
+)
+
+// The ListAccountHolds function lists the holds placed on an account, newest first, e.g. by the
+// authorizations of its cards. Only the authorized holds reserve their amount, which the available
+// balance of the account is its balance less of. Every user the account was shared with can read them.
+func (service *Service) ListAccountHolds(ctx context.Context, owner string, accountID int64, limit int32, offset int32) ([]db.Hold, error) {
+	account, err := service.GetAccount(ctx, owner, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	holds, err := service.store.ListAccountHolds(ctx, db.ListAccountHoldsParams{
+		AccountID: account.ID,
+		Limit:     limit,
+		Offset:    offset,
+	})
+	if err != nil {
+		return nil, storeError(err)
+	}
+
+	return holds, nil
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	db "go-backend/db/sqlc"
+	"go-backend/oauth"
+	"go-backend/util"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// identityUsernameAttempts is how many usernames are tried for a user registering with an identity
+// provider, the ones after the first getting a random suffix.
+const identityUsernameAttempts = 3
+
+// The LoginWithIdentityParams type is a user logging in with the identity an identity provider vouched
+// for, along with the client they use which is recorded on the session.
+// @property {string} Password - the password of the user owning the email of the identity, which links
+// the identity to them. Empty unless the identity is to be linked.
+type LoginWithIdentityParams struct {
+	Identity  oauth.Identity
+	Password  string
+	UserAgent string
+	ClientIP  string
+}
+
+// The LoginWithIdentity function logs in the user linked to an identity at a provider, and issues
+// their tokens as LoginUser does. The first time an identity logs in, as long as the provider verified
+// its email, a new user is registered with it. When a user already owns the email the identity is only
+// linked to them given their password, since the provider vouching for the email doesn't prove that
+// the user owns it: the emails of the users are never verified. They can then log in with either their
+// password or the provider.
+func (service *Service) LoginWithIdentity(ctx context.Context, arg LoginWithIdentityParams) (LoginUserResult, error) {
+	identity := arg.Identity
+
+	linked, err := service.store.GetUserIdentity(ctx, db.GetUserIdentityParams{
+		Provider: identity.Provider,
+		Subject:  identity.Subject,
+	})
+	if err == nil {
+		user, err := service.store.GetUser(ctx, linked.Username)
+		if err != nil {
+			return LoginUserResult{}, storeError(err)
+		}
+		return service.startSession(ctx, user, arg.UserAgent, arg.ClientIP, nil, identity.Provider)
+	}
+	if !errors.Is(err, db.ErrRecordNotFound) {
+		return LoginUserResult{}, storeError(err)
+	}
+
+	// anyone can add an email they don't own to their account at some providers
+	if identity.Email == "" || !identity.EmailVerified {
+		return LoginUserResult{}, errorf(CodePermissionDenied, "the email of the %s account must be verified", identity.Provider)
+	}
+
+	user, err := service.store.GetUserByEmail(ctx, identity.Email)
+	switch {
+	case err == nil:
+		if arg.Password == "" {
+			return LoginUserResult{}, errorf(CodeFailedPrecondition, "the password of the user with the email of the %s account is required to link it", identity.Provider).
+				withReason(ReasonIdentityLinkRequired)
+		}
+		err = service.checkPassword(ctx, user, arg.Password, arg.ClientIP)
+		if err != nil {
+			return LoginUserResult{}, err
+		}
+
+		_, err = service.store.CreateUserIdentity(ctx, db.CreateUserIdentityParams{
+			Provider: identity.Provider,
+			Subject:  identity.Subject,
+			Username: user.Username,
+			Email:    identity.Email,
+		})
+		if err != nil {
+			return LoginUserResult{}, storeError(err)
+		}
+	case errors.Is(err, db.ErrRecordNotFound):
+		user, err = service.registerIdentity(ctx, identity)
+		if err != nil {
+			return LoginUserResult{}, err
+		}
+	default:
+		return LoginUserResult{}, storeError(err)
+	}
+
+	return service.startSession(ctx, user, arg.UserAgent, arg.ClientIP, nil, identity.Provider)
+}
+
+// The registerIdentity function registers a new user of the default organization for an identity, named
+// after its handle or email.
+// The user gets a random password, which they never learn, so that they log in with the provider only.
+func (service *Service) registerIdentity(ctx context.Context, identity oauth.Identity) (db.User, error) {
+	password, err := randomPassword()
+	if err != nil {
+		return db.User{}, newError(CodeInternal, err)
+	}
+	hashedPassword, err := util.HashPassword(password)
+	if err != nil {
+		return db.User{}, newError(CodeInternal, err)
+	}
+
+	fullName := identity.Name
+	if fullName == "" {
+		fullName = identityUsername(identity)
+	}
+
+	for attempt := 0; ; attempt++ {
+		username := identityUsername(identity)
+		if attempt > 0 {
+			username += strconv.FormatInt(util.RandomInt(1000, 9999), 10)
+		}
+
+		user, err := service.store.CreateUserWithIdentityTx(ctx, db.CreateUserWithIdentityTxParams{
+			User: db.CreateUserParams{
+				Username:       username,
+				HashedPassword: hashedPassword,
+				FullName:       fullName,
+				Email:          identity.Email,
+				OrgID:          db.DefaultOrganizationID,
+			},
+			Provider: identity.Provider,
+			Subject:  identity.Subject,
+		})
+		if err == nil {
+			return user, nil
+		}
+		if !errors.Is(db.TranslateError(err), db.ErrUniqueViolation) || attempt+1 == identityUsernameAttempts {
+			return db.User{}, storeError(err)
+		}
+	}
+}
+
+// The identityUsername function returns the username suggested by an identity: its handle at the
+// provider or the local part of its email, keeping the letters and digits only.
+func identityUsername(identity oauth.Identity) string {
+	name := identity.Login
+	if name == "" {
+		name, _, _ = strings.Cut(identity.Email, "@")
+	}
+
+	var username strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			username.WriteRune(r)
+		}
+		if username.Len() == 20 {
+			break
+		}
+	}
+	if username.Len() == 0 {
+		return "user"
+	}
+	return username.String()
+}
+
+func randomPassword() (string, error) {
+	b := make([]byte, 24)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
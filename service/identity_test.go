@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/oauth"
+	"go-backend/testutil/factory"
+	"go-backend/token"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoginWithIdentity(t *testing.T) {
+	user, password := factory.UserWithPassword(t)
+	identity := oauth.Identity{
+		Provider:      oauth.ProviderGitHub,
+		Subject:       "583231",
+		Email:         user.Email,
+		EmailVerified: true,
+		Name:          "The Octocat",
+		Login:         "the-octocat",
+	}
+	identityParams := db.GetUserIdentityParams{Provider: identity.Provider, Subject: identity.Subject}
+
+	testCases := []struct {
+		name       string
+		identity   func() oauth.Identity
+		password   string
+		buildStubs func(store *mockdb.MockStore)
+		check      func(t *testing.T, result LoginUserResult, err error)
+	}{
+		{
+			name: "Linked",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserIdentity(gomock.Any(), gomock.Eq(identityParams)).
+					Times(1).
+					Return(db.UserIdentity{Provider: identity.Provider, Subject: identity.Subject, Username: user.Username}, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().GetUserByEmail(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(1)
+			},
+			check: func(t *testing.T, result LoginUserResult, err error) {
+				require.NoError(t, err)
+				require.Equal(t, user.Username, result.AccessPayload.Username)
+				require.Equal(t, identity.Provider, result.AccessPayload.Metadata[token.MetadataAuthMethod])
+			},
+		},
+		{
+			name:     "LinkedByEmail",
+			password: password,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserIdentity(gomock.Any(), gomock.Eq(identityParams)).Times(1).Return(db.UserIdentity{}, db.ErrRecordNotFound)
+				store.EXPECT().GetUserByEmail(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().
+					CreateUserIdentity(gomock.Any(), gomock.Eq(db.CreateUserIdentityParams{
+						Provider: identity.Provider,
+						Subject:  identity.Subject,
+						Username: user.Username,
+						Email:    user.Email,
+					})).
+					Times(1)
+				store.EXPECT().CreateUserWithIdentityTx(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(1)
+			},
+			check: func(t *testing.T, result LoginUserResult, err error) {
+				require.NoError(t, err)
+				require.Equal(t, user.Username, result.User.Username)
+			},
+		},
+		{
+			name: "LinkRequired",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserIdentity(gomock.Any(), gomock.Eq(identityParams)).Times(1).Return(db.UserIdentity{}, db.ErrRecordNotFound)
+				store.EXPECT().GetUserByEmail(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().CreateUserIdentity(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(0)
+			},
+			check: func(t *testing.T, result LoginUserResult, err error) {
+				require.Equal(t, CodeFailedPrecondition, ErrorCode(err))
+				require.Equal(t, ReasonIdentityLinkRequired, ErrorReason(err))
+			},
+		},
+		{
+			name:     "LinkWrongPassword",
+			password: "wrong-password",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserIdentity(gomock.Any(), gomock.Eq(identityParams)).Times(1).Return(db.UserIdentity{}, db.ErrRecordNotFound)
+				store.EXPECT().GetUserByEmail(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+				store.EXPECT().CreateUserIdentity(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(0)
+			},
+			check: func(t *testing.T, result LoginUserResult, err error) {
+				require.Equal(t, CodeUnauthenticated, ErrorCode(err))
+				require.Equal(t, ReasonInvalidCredentials, ErrorReason(err))
+			},
+		},
+		{
+			name: "Registered",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserIdentity(gomock.Any(), gomock.Any()).Times(1).Return(db.UserIdentity{}, db.ErrRecordNotFound)
+				store.EXPECT().GetUserByEmail(gomock.Any(), gomock.Any()).Times(1).Return(db.User{}, db.ErrRecordNotFound)
+				store.EXPECT().
+					CreateUserWithIdentityTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(ctx context.Context, arg db.CreateUserWithIdentityTxParams) (db.User, error) {
+						require.Equal(t, "theoctocat", arg.User.Username)
+						require.Equal(t, "The Octocat", arg.User.FullName)
+						require.Equal(t, user.Email, arg.User.Email)
+						require.NotEmpty(t, arg.User.HashedPassword)
+						require.Equal(t, identity.Provider, arg.Provider)
+						require.Equal(t, identity.Subject, arg.Subject)
+						return db.User{Username: arg.User.Username, Email: arg.User.Email}, nil
+					})
+				store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(1)
+			},
+			check: func(t *testing.T, result LoginUserResult, err error) {
+				require.NoError(t, err)
+				require.Equal(t, "theoctocat", result.AccessPayload.Username)
+			},
+		},
+		{
+			name: "UsernameTaken",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserIdentity(gomock.Any(), gomock.Any()).Times(1).Return(db.UserIdentity{}, db.ErrRecordNotFound)
+				store.EXPECT().GetUserByEmail(gomock.Any(), gomock.Any()).Times(1).Return(db.User{}, db.ErrRecordNotFound)
+				gomock.InOrder(
+					store.EXPECT().
+						CreateUserWithIdentityTx(gomock.Any(), gomock.Any()).
+						Times(1).
+						Return(db.User{}, &pgconn.PgError{Code: db.UniqueViolation}),
+					store.EXPECT().
+						CreateUserWithIdentityTx(gomock.Any(), gomock.Any()).
+						Times(1).
+						DoAndReturn(func(ctx context.Context, arg db.CreateUserWithIdentityTxParams) (db.User, error) {
+							require.Regexp(t, "^theoctocat[0-9]{4}$", arg.User.Username)
+							return db.User{Username: arg.User.Username}, nil
+						}),
+				)
+				store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(1)
+			},
+			check: func(t *testing.T, result LoginUserResult, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "EmailNotVerified",
+			identity: func() oauth.Identity {
+				unverified := identity
+				unverified.EmailVerified = false
+				return unverified
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserIdentity(gomock.Any(), gomock.Any()).Times(1).Return(db.UserIdentity{}, db.ErrRecordNotFound)
+				store.EXPECT().GetUserByEmail(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateUserIdentity(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateUserWithIdentityTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			check: func(t *testing.T, result LoginUserResult, err error) {
+				require.Equal(t, CodePermissionDenied, ErrorCode(err))
+			},
+		},
+		{
+			name: "InternalError",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserIdentity(gomock.Any(), gomock.Any()).Times(1).Return(db.UserIdentity{}, sql.ErrConnDone)
+				store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(0)
+			},
+			check: func(t *testing.T, result LoginUserResult, err error) {
+				require.Equal(t, CodeInternal, ErrorCode(err))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			arg := LoginWithIdentityParams{Identity: identity, Password: tc.password}
+			if tc.identity != nil {
+				arg.Identity = tc.identity()
+			}
+
+			result, err := newTestService(t, store).LoginWithIdentity(context.Background(), arg)
+			tc.check(t, result, err)
+		})
+	}
+}
+
+func TestIdentityUsername(t *testing.T) {
+	require.Equal(t, "octocat", identityUsername(oauth.Identity{Login: "Octo-Cat", Email: "jane@example.com"}))
+	require.Equal(t, "janedoe", identityUsername(oauth.Identity{Email: "jane.doe@example.com"}))
+	require.Equal(t, "user", identityUsername(oauth.Identity{Email: "_@example.com"}))
+	require.Len(t, identityUsername(oauth.Identity{Email: "abcdefghijklmnopqrstuvwxyz@example.com"}), 20)
+}
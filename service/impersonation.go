@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"go-backend/token"
+	"go-backend/util"
+	"strings"
+	"time"
+)
+
+// ActionImpersonationStarted is the action of the audit entries of the impersonation tokens issued to
+// admins.
+const ActionImpersonationStarted = "impersonation.started"
+
+// The ImpersonateParams type is the request of an admin to act as a user, e.g. to reproduce an issue
+// they reported.
+// @property {string} Impersonator - the admin impersonating the user.
+// @property {string} Reason - why the user is impersonated, e.g. the support ticket, kept in the audit
+// log.
+// @property {time.Duration} Duration - how long the token is valid, at most and by default the
+// IMPERSONATION_TOKEN_DURATION config.
+type ImpersonateParams struct {
+	Impersonator string
+	Username     string
+	Reason       string
+	Duration     time.Duration
+}
+
+// The ImpersonationResult type is the token an admin acts as a user with.
+type ImpersonationResult struct {
+	AccessToken   string
+	AccessPayload *token.Payload
+}
+
+// The Impersonate function issues an admin a time-boxed access token of a user, carrying the admin as
+// its impersonator so that the actions taken with it are flagged in the audit log. The start of the
+// impersonation is recorded first, no token being issued when it can't be. Admins can't be
+// impersonated, for the token not to grant more than the support needs.
+func (service *Service) Impersonate(ctx context.Context, arg ImpersonateParams) (ImpersonationResult, error) {
+	if strings.TrimSpace(arg.Reason) == "" {
+		return ImpersonationResult{}, errorf(CodeInvalidArgument, "the reason of the impersonation is required").withField("reason")
+	}
+	if arg.Username == arg.Impersonator {
+		return ImpersonationResult{}, errorf(CodeInvalidArgument, "users can't impersonate themselves").withField("username")
+	}
+
+	user, err := service.store.GetUser(ctx, arg.Username)
+	if err != nil {
+		return ImpersonationResult{}, storeError(err)
+	}
+	if user.Role == util.AdminRole {
+		return ImpersonationResult{}, errorf(CodePermissionDenied, "admins can't be impersonated")
+	}
+
+	duration := service.config.ImpersonationTokenDuration
+	if arg.Duration > 0 && arg.Duration < duration {
+		duration = arg.Duration
+	}
+
+	_, err = service.RecordAuditEntry(ctx, AuditEntryParams{
+		Username:     arg.Username,
+		Impersonator: arg.Impersonator,
+		Action:       ActionImpersonationStarted,
+		Detail:       arg.Reason,
+	})
+	if err != nil {
+		return ImpersonationResult{}, err
+	}
+
+	accessToken, accessPayload, err := service.tokenMaker.CreateToken(token.Claims{
+		Username:     user.Username,
+		Role:         user.Role,
+		OrgID:        user.OrgID,
+		Impersonator: arg.Impersonator,
+	}, duration)
+	if err != nil {
+		return ImpersonationResult{}, newError(CodeInternal, err)
+	}
+
+	return ImpersonationResult{
+		AccessToken:   accessToken,
+		AccessPayload: accessPayload,
+	}, nil
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"errors"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/testutil/factory"
+	"go-backend/util"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImpersonate(t *testing.T) {
+	admin := factory.User(factory.WithRole(util.AdminRole))
+	user := factory.User()
+	otherAdmin := factory.User(factory.WithRole(util.AdminRole))
+
+	testCases := []struct {
+		name       string
+		arg        ImpersonateParams
+		buildStubs func(store *mockdb.MockStore)
+		duration   time.Duration
+		code       *Code
+	}{
+		{
+			name: "OK",
+			arg:  ImpersonateParams{Impersonator: admin.Username, Username: user.Username, Reason: "ticket 42"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().
+					CreateAuditEntry(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateAuditEntryParams) (db.AuditEntry, error) {
+						require.Equal(t, user.Username, arg.Username)
+						require.Equal(t, admin.Username, arg.Impersonator.String)
+						require.Equal(t, ActionImpersonationStarted, arg.Action)
+						require.Equal(t, "ticket 42", arg.Detail)
+						return db.AuditEntry{ID: 1}, nil
+					})
+			},
+			duration: 15 * time.Minute,
+		},
+		{
+			name: "ShorterDuration",
+			arg:  ImpersonateParams{Impersonator: admin.Username, Username: user.Username, Reason: "ticket 42", Duration: 5 * time.Minute},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().CreateAuditEntry(gomock.Any(), gomock.Any()).Times(1)
+			},
+			duration: 5 * time.Minute,
+		},
+		{
+			name: "LongerDurationCapped",
+			arg:  ImpersonateParams{Impersonator: admin.Username, Username: user.Username, Reason: "ticket 42", Duration: 24 * time.Hour},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().CreateAuditEntry(gomock.Any(), gomock.Any()).Times(1)
+			},
+			duration: 15 * time.Minute,
+		},
+		{
+			name: "MissingReason",
+			arg:  ImpersonateParams{Impersonator: admin.Username, Username: user.Username, Reason: " "},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code: codePtr(CodeInvalidArgument),
+		},
+		{
+			name: "Themselves",
+			arg:  ImpersonateParams{Impersonator: admin.Username, Username: admin.Username, Reason: "ticket 42"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code: codePtr(CodeInvalidArgument),
+		},
+		{
+			name: "Admin",
+			arg:  ImpersonateParams{Impersonator: admin.Username, Username: otherAdmin.Username, Reason: "ticket 42"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(otherAdmin.Username)).Times(1).Return(otherAdmin, nil)
+				store.EXPECT().CreateAuditEntry(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code: codePtr(CodePermissionDenied),
+		},
+		{
+			name: "UserNotFound",
+			arg:  ImpersonateParams{Impersonator: admin.Username, Username: user.Username, Reason: "ticket 42"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(db.User{}, db.ErrRecordNotFound)
+				store.EXPECT().CreateAuditEntry(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code: codePtr(CodeNotFound),
+		},
+		{
+			// no token is issued without its audit entry
+			name: "AuditFailed",
+			arg:  ImpersonateParams{Impersonator: admin.Username, Username: user.Username, Reason: "ticket 42"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().CreateAuditEntry(gomock.Any(), gomock.Any()).Times(1).Return(db.AuditEntry{}, errors.New("connection refused"))
+			},
+			code: codePtr(CodeInternal),
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			service := newTestService(t, store)
+			service.config.ImpersonationTokenDuration = 15 * time.Minute
+
+			result, err := service.Impersonate(context.Background(), tc.arg)
+			if tc.code != nil {
+				require.Equal(t, *tc.code, ErrorCode(err))
+				require.Empty(t, result.AccessToken)
+				return
+			}
+			require.NoError(t, err)
+
+			payload, err := service.tokenMaker.VerifyToken(result.AccessToken)
+			require.NoError(t, err)
+			require.Equal(t, tc.arg.Username, payload.Username)
+			require.Equal(t, tc.arg.Impersonator, payload.Impersonator)
+			require.WithinDuration(t, payload.IssuedAt.Add(tc.duration), payload.ExpiredAt, time.Second)
+		})
+	}
+}
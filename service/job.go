@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	db "go-backend/db/sqlc"
+	"go-backend/worker"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+)
+
+// The CreateJobParams type is a request to export data in the background. The account is required for
+// statements and entries, and statements also need the period to cover.
+type CreateJobParams struct {
+	Username  string
+	Kind      string
+	AccountID int64
+	From      time.Time
+	To        time.Time
+}
+
+// The CreateJob function creates an export job for the user and enqueues the task generating its file.
+func (service *Service) CreateJob(ctx context.Context, arg CreateJobParams) (db.Job, error) {
+	if arg.Kind != worker.ExportKindGDPR {
+		_, err := service.GetAccount(ctx, arg.Username, arg.AccountID)
+		if err != nil {
+			return db.Job{}, err
+		}
+	}
+
+	params, err := json.Marshal(worker.ExportParams{
+		AccountID: arg.AccountID,
+		From:      arg.From,
+		To:        arg.To,
+	})
+	if err != nil {
+		return db.Job{}, newError(CodeInternal, err)
+	}
+
+	job, err := service.store.CreateJob(ctx, db.CreateJobParams{
+		ID:       uuid.New(),
+		Username: arg.Username,
+		Kind:     arg.Kind,
+		Params:   params,
+	})
+	if err != nil {
+		return job, storeError(err)
+	}
+
+	opts := []asynq.Option{
+		asynq.MaxRetry(3),
+		asynq.Queue(worker.QueueDefault),
+	}
+	err = service.taskDistributor.DistributeTaskRunExport(ctx, &worker.PayloadRunExport{JobID: job.ID}, opts...)
+	if err != nil {
+		return job, newError(CodeInternal, err)
+	}
+
+	return job, nil
+}
+
+// The GetJob function returns an export job, provided it belongs to the user.
+func (service *Service) GetJob(ctx context.Context, username string, id uuid.UUID) (db.Job, error) {
+	job, err := service.store.GetJob(ctx, id)
+	if err != nil {
+		return job, storeError(err)
+	}
+
+	if job.Username != username {
+		return job, newError(CodePermissionDenied, errors.New("job doesn't belong to authenticated user"))
+	}
+
+	return job, nil
+}
+
+// The CancelJob function cancels a pending or running export job of the user. A job that already
+// finished can't be cancelled anymore.
+func (service *Service) CancelJob(ctx context.Context, username string, id uuid.UUID) (db.Job, error) {
+	job, err := service.GetJob(ctx, username, id)
+	if err != nil {
+		return job, err
+	}
+
+	job, err = service.store.CancelJob(ctx, job.ID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return job, newError(CodeFailedPrecondition, errors.New("job has already finished")).withReason(ReasonJobFinished)
+		}
+		return job, storeError(err)
+	}
+
+	return job, nil
+}
+
+// The GetJobResult function returns a succeeded export job along with its file. The caller is
+// responsible for authorizing the download.
+func (service *Service) GetJobResult(ctx context.Context, id uuid.UUID) (db.Job, error) {
+	job, err := service.store.GetJob(ctx, id)
+	if err != nil {
+		return job, storeError(err)
+	}
+
+	if job.Status != worker.JobStatusSucceeded {
+		return job, errorf(CodeFailedPrecondition, "job is %s", job.Status).withReason(ReasonJobNotSucceeded)
+	}
+
+	return job, nil
+}
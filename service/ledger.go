@@ -0,0 +1,33 @@
+package service
+
+import (
+	"context"
+	db "go-backend/db/sqlc"
+)
+
+// The ReconcileLedger function checks the balances of the accounts against their entries and the
+// entries of the transfers against each other, recording the anomalies found. It runs every night with
+// the end-of-day batches and can be started by an admin, e.g. after correcting the ledger.
+func (service *Service) ReconcileLedger(ctx context.Context) (db.ReconcileLedgerTxResult, error) {
+	result, err := service.store.ReconcileLedgerTx(ctx)
+	if err != nil {
+		return result, storeError(err)
+	}
+
+	return result, nil
+}
+
+// The ListLedgerAnomalies function lists the anomalies found by the reconciliations, the last detected
+// first, optionally only those that are still open.
+func (service *Service) ListLedgerAnomalies(ctx context.Context, onlyOpen bool, limit int32, offset int32) ([]db.LedgerAnomaly, error) {
+	anomalies, err := service.store.ListLedgerAnomalies(ctx, db.ListLedgerAnomaliesParams{
+		OnlyOpen:  onlyOpen,
+		RowLimit:  limit,
+		RowOffset: offset,
+	})
+	if err != nil {
+		return nil, storeError(err)
+	}
+
+	return anomalies, nil
+}
@@ -0,0 +1,24 @@
+package service
+
+import (
+	db "go-backend/db/sqlc"
+	"go-backend/token"
+	"go-backend/util"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestService(t *testing.T, store db.Store) *Service {
+	config := util.Config{
+		TokenSymmetricKey:    util.RandomString(32),
+		AccessTokenDuration:  time.Minute,
+		RefreshTokenDuration: time.Hour,
+	}
+
+	tokenMaker, err := token.NewPasetoMaker(config.TokenSymmetricKey)
+	require.NoError(t, err)
+
+	return New(config, store, tokenMaker, nil)
+}
@@ -0,0 +1,213 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	db "go-backend/db/sqlc"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// The CreateMandateParams type is a direct-debit mandate granted by a user to a merchant.
+// @property {string} Payer - the user granting the mandate, who must be able to use the from account as
+// an owner.
+// @property {int64} FromAccountID - the account of the payer the merchant pulls funds from.
+// @property {int64} MerchantAccountID - the account the funds are pulled to, whose owners pull them.
+// @property {int64} MaxAmount - the most a single pull takes, in the currency of both accounts.
+// @property {int64} CapAmount - the most pulled with the mandate in total, at least the maximum of a pull.
+type CreateMandateParams struct {
+	Payer             string
+	FromAccountID     int64
+	MerchantAccountID int64
+	MaxAmount         int64
+	CapAmount         int64
+}
+
+// The CreateMandate function authorizes the owners of the merchant account to pull funds from an account
+// of the payer, up to the maximum of the mandate per pull and its cap in total, until the mandate is
+// revoked. Both accounts must hold the same currency.
+func (service *Service) CreateMandate(ctx context.Context, arg CreateMandateParams) (db.Mandate, error) {
+	if arg.MaxAmount <= 0 {
+		return db.Mandate{}, errorf(CodeInvalidArgument, "max amount must be positive, got %d", arg.MaxAmount).withReason(ReasonInvalidAmount)
+	}
+	if arg.CapAmount < arg.MaxAmount {
+		return db.Mandate{}, errorf(CodeInvalidArgument, "cap amount must be at least the max amount %d, got %d", arg.MaxAmount, arg.CapAmount).withReason(ReasonInvalidAmount)
+	}
+	if arg.FromAccountID == arg.MerchantAccountID {
+		return db.Mandate{}, newError(CodeInvalidArgument, errors.New("an account can't pull funds from itself"))
+	}
+
+	fromAccount, err := service.ownedAccount(ctx, arg.Payer, arg.FromAccountID)
+	if err != nil {
+		return db.Mandate{}, err
+	}
+
+	merchantAccount, err := service.validAccount(ctx, arg.MerchantAccountID, fromAccount.Currency)
+	if err != nil {
+		return db.Mandate{}, err
+	}
+
+	// only the transactions of the bank post to its system accounts
+	if db.IsSystemAccount(merchantAccount) {
+		return db.Mandate{}, newError(CodePermissionDenied, db.ErrSystemAccount)
+	}
+
+	mandate, err := service.store.CreateMandate(ctx, db.CreateMandateParams{
+		Payer:             arg.Payer,
+		FromAccountID:     fromAccount.ID,
+		MerchantAccountID: merchantAccount.ID,
+		MaxAmount:         arg.MaxAmount,
+		CapAmount:         pgtype.Int8{Int64: arg.CapAmount, Valid: true},
+	})
+	if err != nil {
+		return mandate, storeError(err)
+	}
+
+	return mandate, nil
+}
+
+// The GetMandate function returns a mandate, provided the user is its payer or shares the merchant
+// account.
+func (service *Service) GetMandate(ctx context.Context, username string, id int64) (db.Mandate, error) {
+	mandate, err := service.store.GetMandate(ctx, id)
+	if err != nil {
+		return mandate, storeError(err)
+	}
+
+	if mandate.Payer == username {
+		return mandate, nil
+	}
+
+	_, err = service.GetAccount(ctx, username, mandate.MerchantAccountID)
+	if err != nil {
+		if ErrorCode(err) == CodePermissionDenied {
+			return mandate, newError(CodePermissionDenied, errors.New("mandate doesn't concern authenticated user"))
+		}
+		return mandate, err
+	}
+
+	return mandate, nil
+}
+
+// The ListMandates function lists the mandates granted by the payer, oldest first.
+func (service *Service) ListMandates(ctx context.Context, payer string, limit int32, offset int32) ([]db.Mandate, error) {
+	mandates, err := service.store.ListMandates(ctx, db.ListMandatesParams{
+		Payer:  payer,
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return nil, storeError(err)
+	}
+
+	return mandates, nil
+}
+
+// The RevokeMandate function revokes an active mandate, after which no funds are pulled with it. Its payer
+// and the owners of the merchant account can revoke it.
+func (service *Service) RevokeMandate(ctx context.Context, username string, id int64) (db.Mandate, error) {
+	mandate, err := service.store.GetMandate(ctx, id)
+	if err != nil {
+		return mandate, storeError(err)
+	}
+
+	if mandate.Payer != username {
+		_, err = service.ownedAccount(ctx, username, mandate.MerchantAccountID)
+		if err != nil {
+			return mandate, err
+		}
+	}
+
+	mandate, err = service.store.RevokeMandate(ctx, id)
+	if err != nil {
+		// it was revoked before
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return mandate, mandateError(id, db.ErrMandateRevoked)
+		}
+		return mandate, storeError(err)
+	}
+
+	return mandate, nil
+}
+
+// The PullMandateParams type is a pull of funds by a merchant with a mandate.
+// @property {string} Merchant - the user pulling the funds, who must be able to use the merchant account
+// as an owner.
+// @property {int64} ID - the mandate pulled with.
+// @property {int64} Amount - the positive amount pulled, at most the maximum of the mandate.
+// @property {string} Memo - optional free text kept on the transfer.
+// @property {string} ExternalReference - optional reference of the payment outside the bank.
+type PullMandateParams struct {
+	Merchant          string
+	ID                int64
+	Amount            int64
+	Memo              string
+	ExternalReference string
+}
+
+// The PullMandate function pulls funds from the account of the payer of an active mandate to the merchant
+// account, with a transfer checked like any transfer of the payer. A pull flagged by the screening isn't
+// made, the payer having to send it as a transfer to be held for review instead. The mandate is checked
+// again within the transaction of the transfer, so that a pull can't race with its revocation.
+func (service *Service) PullMandate(ctx context.Context, arg PullMandateParams) (db.PullMandateTxResult, error) {
+	mandate, err := service.store.GetMandate(ctx, arg.ID)
+	if err != nil {
+		return db.PullMandateTxResult{}, storeError(err)
+	}
+
+	merchantAccount, err := service.ownedAccount(ctx, arg.Merchant, mandate.MerchantAccountID)
+	if err != nil {
+		return db.PullMandateTxResult{}, err
+	}
+
+	if mandate.Status != db.MandateActive {
+		return db.PullMandateTxResult{}, mandateError(arg.ID, db.ErrMandateRevoked)
+	}
+	if arg.Amount > mandate.MaxAmount {
+		return db.PullMandateTxResult{}, mandateError(arg.ID, db.ErrMandateLimitExceeded)
+	}
+	if mandate.CapAmount.Valid && mandate.PulledAmount+arg.Amount > mandate.CapAmount.Int64 {
+		return db.PullMandateTxResult{}, mandateError(arg.ID, db.ErrMandateCapExceeded)
+	}
+
+	transfer := CreateTransferParams{
+		Owner:             mandate.Payer,
+		FromAccountID:     mandate.FromAccountID,
+		ToAccountID:       merchantAccount.ID,
+		Amount:            arg.Amount,
+		Currency:          merchantAccount.Currency,
+		Memo:              arg.Memo,
+		ExternalReference: arg.ExternalReference,
+	}
+	err = service.checkDirectTransfer(ctx, transfer, fmt.Sprintf("pull with mandate [%d]", arg.ID))
+	if err != nil {
+		return db.PullMandateTxResult{}, err
+	}
+
+	result, err := service.store.PullMandateTx(ctx, db.PullMandateTxParams{
+		ID:                arg.ID,
+		Amount:            arg.Amount,
+		Memo:              arg.Memo,
+		ExternalReference: arg.ExternalReference,
+		Currency:          merchantAccount.Currency,
+	})
+	if err != nil {
+		return result, mandateError(arg.ID, err)
+	}
+
+	return result, nil
+}
+
+// The mandateError function classifies the errors of the store on a mandate.
+func mandateError(id int64, err error) error {
+	switch {
+	case errors.Is(err, db.ErrMandateRevoked):
+		return errorf(CodeFailedPrecondition, "mandate [%d] has been revoked", id).withReason(ReasonMandateRevoked)
+	case errors.Is(err, db.ErrMandateLimitExceeded):
+		return errorf(CodeInvalidArgument, "amount exceeds the maximum of mandate [%d]", id).withReason(ReasonMandateLimitExceeded)
+	case errors.Is(err, db.ErrMandateCapExceeded):
+		return errorf(CodeFailedPrecondition, "amount exceeds what remains under the cap of mandate [%d]", id).withReason(ReasonMandateCapExceeded)
+	}
+	return storeError(err)
+}
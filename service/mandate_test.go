@@ -0,0 +1,290 @@
+package service
+
+import (
+	"context"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/testutil/factory"
+	"go-backend/util"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateMandate(t *testing.T) {
+	payer := util.RandomOwner()
+	fromAccount := factory.Account(factory.OwnedBy(payer), factory.InCurrency(util.USD))
+	merchantAccount := factory.Account(factory.InCurrency(util.USD))
+	merchantAccount.ID = fromAccount.ID + 1
+	euroAccount := factory.Account(factory.InCurrency(util.EUR))
+	euroAccount.ID = fromAccount.ID + 2
+
+	testCases := []struct {
+		name      string
+		arg       CreateMandateParams
+		buildStub func(store *mockdb.MockStore)
+		code      *Code
+		reason    string
+	}{
+		{
+			name: "OK",
+			arg:  CreateMandateParams{Payer: payer, FromAccountID: fromAccount.ID, MerchantAccountID: merchantAccount.ID, MaxAmount: 500, CapAmount: 2000},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(merchantAccount.ID)).Times(1).Return(merchantAccount, nil)
+				store.EXPECT().
+					CreateMandate(gomock.Any(), gomock.Eq(db.CreateMandateParams{
+						Payer:             payer,
+						FromAccountID:     fromAccount.ID,
+						MerchantAccountID: merchantAccount.ID,
+						MaxAmount:         500,
+						CapAmount:         pgtype.Int8{Int64: 2000, Valid: true},
+					})).
+					Times(1)
+			},
+		},
+		{
+			name: "InvalidMaxAmount",
+			arg:  CreateMandateParams{Payer: payer, FromAccountID: fromAccount.ID, MerchantAccountID: merchantAccount.ID},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code:   codePtr(CodeInvalidArgument),
+			reason: ReasonInvalidAmount,
+		},
+		{
+			name: "CapBelowMaxAmount",
+			arg:  CreateMandateParams{Payer: payer, FromAccountID: fromAccount.ID, MerchantAccountID: merchantAccount.ID, MaxAmount: 500, CapAmount: 499},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code:   codePtr(CodeInvalidArgument),
+			reason: ReasonInvalidAmount,
+		},
+		{
+			name: "SameAccount",
+			arg:  CreateMandateParams{Payer: payer, FromAccountID: fromAccount.ID, MerchantAccountID: fromAccount.ID, MaxAmount: 500, CapAmount: 2000},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code: codePtr(CodeInvalidArgument),
+		},
+		{
+			name: "CurrencyMismatch",
+			arg:  CreateMandateParams{Payer: payer, FromAccountID: fromAccount.ID, MerchantAccountID: euroAccount.ID, MaxAmount: 500, CapAmount: 2000},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(euroAccount.ID)).Times(1).Return(euroAccount, nil)
+				store.EXPECT().CreateMandate(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code:   codePtr(CodeInvalidArgument),
+			reason: ReasonCurrencyMismatch,
+		},
+		{
+			name: "NotOwner",
+			arg:  CreateMandateParams{Payer: util.RandomOwner(), FromAccountID: fromAccount.ID, MerchantAccountID: merchantAccount.ID, MaxAmount: 500, CapAmount: 2000},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(db.AccountMember{}, db.ErrRecordNotFound)
+				store.EXPECT().CreateMandate(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code: codePtr(CodePermissionDenied),
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			_, err := newTestService(t, store).CreateMandate(context.Background(), tc.arg)
+			if tc.code == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.Equal(t, *tc.code, ErrorCode(err))
+			if tc.reason != "" {
+				require.Equal(t, tc.reason, ErrorReason(err))
+			}
+		})
+	}
+}
+
+func TestPullMandate(t *testing.T) {
+	payer := util.RandomOwner()
+	merchant := util.RandomOwner()
+	fromAccount := factory.Account(factory.OwnedBy(payer), factory.InCurrency(util.USD))
+	merchantAccount := factory.Account(factory.OwnedBy(merchant), factory.InCurrency(util.USD))
+	merchantAccount.ID = fromAccount.ID + 1
+	mandate := factory.Mandate(fromAccount, merchantAccount)
+
+	testCases := []struct {
+		name      string
+		arg       PullMandateParams
+		buildStub func(store *mockdb.MockStore)
+		code      *Code
+		reason    string
+	}{
+		{
+			name: "OK",
+			arg:  PullMandateParams{Merchant: merchant, ID: mandate.ID, Amount: 1000, ExternalReference: "INV-42"},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetMandate(gomock.Any(), gomock.Eq(mandate.ID)).Times(1).Return(mandate, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(merchantAccount.ID)).Times(2).Return(merchantAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetActiveBankParameter(gomock.Any(), gomock.Any()).Times(1).Return(db.BankParameter{}, db.ErrRecordNotFound)
+				store.EXPECT().
+					PullMandateTx(gomock.Any(), gomock.Eq(db.PullMandateTxParams{
+						ID:                mandate.ID,
+						Amount:            1000,
+						ExternalReference: "INV-42",
+						Currency:          util.USD,
+					})).
+					Times(1)
+			},
+		},
+		{
+			name: "ExceedsMaximum",
+			arg:  PullMandateParams{Merchant: merchant, ID: mandate.ID, Amount: 1001},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetMandate(gomock.Any(), gomock.Eq(mandate.ID)).Times(1).Return(mandate, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(merchantAccount.ID)).Times(1).Return(merchantAccount, nil)
+				store.EXPECT().PullMandateTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code:   codePtr(CodeInvalidArgument),
+			reason: ReasonMandateLimitExceeded,
+		},
+		{
+			name: "CapExceeded",
+			arg:  PullMandateParams{Merchant: merchant, ID: mandate.ID, Amount: 1000},
+			buildStub: func(store *mockdb.MockStore) {
+				pulled := mandate
+				pulled.PulledAmount = mandate.CapAmount.Int64 - 999
+				store.EXPECT().GetMandate(gomock.Any(), gomock.Eq(mandate.ID)).Times(1).Return(pulled, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(merchantAccount.ID)).Times(1).Return(merchantAccount, nil)
+				store.EXPECT().PullMandateTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code:   codePtr(CodeFailedPrecondition),
+			reason: ReasonMandateCapExceeded,
+		},
+		{
+			name: "CapExceededMeanwhile",
+			arg:  PullMandateParams{Merchant: merchant, ID: mandate.ID, Amount: 100},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetMandate(gomock.Any(), gomock.Eq(mandate.ID)).Times(1).Return(mandate, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(merchantAccount.ID)).Times(2).Return(merchantAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetActiveBankParameter(gomock.Any(), gomock.Any()).Times(1).Return(db.BankParameter{}, db.ErrRecordNotFound)
+				store.EXPECT().PullMandateTx(gomock.Any(), gomock.Any()).Times(1).Return(db.PullMandateTxResult{}, db.ErrMandateCapExceeded)
+			},
+			code:   codePtr(CodeFailedPrecondition),
+			reason: ReasonMandateCapExceeded,
+		},
+		{
+			name: "Revoked",
+			arg:  PullMandateParams{Merchant: merchant, ID: mandate.ID, Amount: 100},
+			buildStub: func(store *mockdb.MockStore) {
+				revoked := mandate
+				revoked.Status = db.MandateRevoked
+				store.EXPECT().GetMandate(gomock.Any(), gomock.Eq(mandate.ID)).Times(1).Return(revoked, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(merchantAccount.ID)).Times(1).Return(merchantAccount, nil)
+				store.EXPECT().PullMandateTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code:   codePtr(CodeFailedPrecondition),
+			reason: ReasonMandateRevoked,
+		},
+		{
+			name: "RevokedMeanwhile",
+			arg:  PullMandateParams{Merchant: merchant, ID: mandate.ID, Amount: 100},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetMandate(gomock.Any(), gomock.Eq(mandate.ID)).Times(1).Return(mandate, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(merchantAccount.ID)).Times(2).Return(merchantAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetActiveBankParameter(gomock.Any(), gomock.Any()).Times(1).Return(db.BankParameter{}, db.ErrRecordNotFound)
+				store.EXPECT().PullMandateTx(gomock.Any(), gomock.Any()).Times(1).Return(db.PullMandateTxResult{}, db.ErrMandateRevoked)
+			},
+			code:   codePtr(CodeFailedPrecondition),
+			reason: ReasonMandateRevoked,
+		},
+		{
+			name: "ApprovalRequired",
+			arg:  PullMandateParams{Merchant: merchant, ID: mandate.ID, Amount: 1000},
+			buildStub: func(store *mockdb.MockStore) {
+				orgAccount := fromAccount
+				orgAccount.OrgID = 7
+				store.EXPECT().GetMandate(gomock.Any(), gomock.Eq(mandate.ID)).Times(1).Return(mandate, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(merchantAccount.ID)).Times(2).Return(merchantAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(orgAccount, nil)
+				store.EXPECT().GetActiveBankParameter(gomock.Any(), gomock.Any()).Times(1).Return(db.BankParameter{}, db.ErrRecordNotFound)
+				store.EXPECT().GetOrganization(gomock.Any(), gomock.Eq(int64(7))).Times(1).Return(db.Organization{ID: 7, ApprovalThreshold: 500}, nil)
+				store.EXPECT().PullMandateTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code:   codePtr(CodeFailedPrecondition),
+			reason: ReasonApprovalRequired,
+		},
+		{
+			name: "NotMerchant",
+			arg:  PullMandateParams{Merchant: payer, ID: mandate.ID, Amount: 100},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetMandate(gomock.Any(), gomock.Eq(mandate.ID)).Times(1).Return(mandate, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(merchantAccount.ID)).Times(1).Return(merchantAccount, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(db.AccountMember{}, db.ErrRecordNotFound)
+				store.EXPECT().PullMandateTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code: codePtr(CodePermissionDenied),
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			_, err := newTestService(t, store).PullMandate(context.Background(), tc.arg)
+			if tc.code == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.Equal(t, *tc.code, ErrorCode(err))
+			if tc.reason != "" {
+				require.Equal(t, tc.reason, ErrorReason(err))
+			}
+		})
+	}
+}
+
+func TestRevokeMandate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fromAccount := factory.Account()
+	merchantAccount := factory.Account()
+	mandate := factory.Mandate(fromAccount, merchantAccount)
+
+	store := mockdb.NewMockStore(ctrl)
+	service := newTestService(t, store)
+	store.EXPECT().GetMandate(gomock.Any(), gomock.Eq(mandate.ID)).Times(2).Return(mandate, nil)
+
+	// the merchant revokes it like the payer
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(merchantAccount.ID)).Times(1).Return(merchantAccount, nil)
+	store.EXPECT().RevokeMandate(gomock.Any(), gomock.Eq(mandate.ID)).Times(1).Return(db.Mandate{Status: db.MandateRevoked}, nil)
+	revoked, err := service.RevokeMandate(context.Background(), merchantAccount.Owner, mandate.ID)
+	require.NoError(t, err)
+	require.Equal(t, db.MandateRevoked, revoked.Status)
+
+	store.EXPECT().RevokeMandate(gomock.Any(), gomock.Eq(mandate.ID)).Times(1).Return(db.Mandate{}, db.ErrRecordNotFound)
+	_, err = service.RevokeMandate(context.Background(), mandate.Payer, mandate.ID)
+	require.Equal(t, ReasonMandateRevoked, ErrorReason(err))
+}
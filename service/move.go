@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"errors"
+	db "go-backend/db/sqlc"
+	"go-backend/util"
+	"math/big"
+)
+
+// FXRateScale is the unit of the exchange rates, which are in millionths of the currency bought per unit
+// of the currency sold.
+const FXRateScale = 1_000_000
+
+// The MoveMoneyParams type is money moved by a user between two of their own accounts.
+// @property {int64} Amount - the positive amount taken from the from account, in its currency.
+type MoveMoneyParams struct {
+	Owner         string
+	FromAccountID int64
+	ToAccountID   int64
+	Amount        int64
+	Memo          string
+}
+
+// The MoveMoneyResult type is the outcome of a move between the accounts of a user.
+// @property {int64} ConvertedAmount - the amount given to the to account, in its currency.
+// @property {int64} ExchangeRate - the rate the amount was converted at, in millionths, FXRateScale
+// when both accounts hold the same currency.
+// @property {[]db.Transfer} Transfers - the transfer between the accounts, or the two transfers through
+// the fx_spread system accounts when the amount was converted.
+type MoveMoneyResult struct {
+	FromAccount     db.Account
+	ToAccount       db.Account
+	Amount          int64
+	ConvertedAmount int64
+	ExchangeRate    int64
+	Transfers       []db.Transfer
+}
+
+// The MoveMoney function moves money between two accounts the owner can use, including the accounts shared
+// with them as an owner, converting it at the exchange rate in effect when the accounts hold different
+// currencies, the rates being published as fx_rate parameters. Moving money between one's own accounts
+// isn't subject to the transfer limit, the screening or the approval of large transfers.
+func (service *Service) MoveMoney(ctx context.Context, arg MoveMoneyParams) (MoveMoneyResult, error) {
+	if arg.Amount <= 0 {
+		return MoveMoneyResult{}, errorf(CodeInvalidArgument, "amount must be positive, got %d", arg.Amount).withReason(ReasonInvalidAmount)
+	}
+	if arg.FromAccountID == arg.ToAccountID {
+		return MoveMoneyResult{}, errorf(CodeInvalidArgument, "cannot move money from account [%d] to itself", arg.FromAccountID)
+	}
+
+	fromAccount, err := service.ownedAccount(ctx, arg.Owner, arg.FromAccountID)
+	if err != nil {
+		return MoveMoneyResult{}, err
+	}
+	toAccount, err := service.ownedAccount(ctx, arg.Owner, arg.ToAccountID)
+	if err != nil {
+		return MoveMoneyResult{}, err
+	}
+
+	if fromAccount.Currency == toAccount.Currency {
+		result, err := service.store.TransferTx(ctx, db.TransferTxParams{
+			FromAccountID: fromAccount.ID,
+			ToAccountID:   toAccount.ID,
+			Amount:        arg.Amount,
+			Memo:          arg.Memo,
+			Currency:      fromAccount.Currency,
+		})
+		if err != nil {
+			return MoveMoneyResult{}, storeError(err)
+		}
+
+		return MoveMoneyResult{
+			FromAccount:     result.FromAccount,
+			ToAccount:       result.ToAccount,
+			Amount:          arg.Amount,
+			ConvertedAmount: arg.Amount,
+			ExchangeRate:    FXRateScale,
+			Transfers:       []db.Transfer{result.Transfer},
+		}, nil
+	}
+
+	rate, ok, err := service.activeParameter(ctx, db.FXRateParameter(fromAccount.Currency, toAccount.Currency))
+	if err != nil {
+		return MoveMoneyResult{}, err
+	}
+	if !ok {
+		return MoveMoneyResult{}, errorf(CodeFailedPrecondition, "no exchange rate from %s to %s was published", fromAccount.Currency, toAccount.Currency).withReason(ReasonNoExchangeRate)
+	}
+
+	converted, err := convertAmount(arg.Amount, rate)
+	if err != nil {
+		return MoveMoneyResult{}, newError(CodeInvalidArgument, err).withReason(ReasonInvalidAmount)
+	}
+
+	result, err := service.store.ExchangeTx(ctx, db.ExchangeTxParams{
+		FromAccountID:   fromAccount.ID,
+		FromCurrency:    fromAccount.Currency,
+		ToAccountID:     toAccount.ID,
+		ToCurrency:      toAccount.Currency,
+		Amount:          arg.Amount,
+		ConvertedAmount: converted,
+		Memo:            arg.Memo,
+	})
+	if err != nil {
+		return MoveMoneyResult{}, storeError(err)
+	}
+
+	return MoveMoneyResult{
+		FromAccount:     result.Sold.FromAccount,
+		ToAccount:       result.Bought.ToAccount,
+		Amount:          arg.Amount,
+		ConvertedAmount: converted,
+		ExchangeRate:    rate,
+		Transfers:       []db.Transfer{result.Sold.Transfer, result.Bought.Transfer},
+	}, nil
+}
+
+// The convertAmount function converts an amount at a rate in millionths, rounding down so that the bank
+// never gives more than the amount is worth.
+func convertAmount(amount int64, rate int64) (int64, error) {
+	converted := new(big.Int).Mul(big.NewInt(amount), big.NewInt(rate))
+	converted.Quo(converted, big.NewInt(FXRateScale))
+
+	if !converted.IsInt64() {
+		return 0, errors.New("amount is too large to convert")
+	}
+	if converted.Sign() <= 0 {
+		return 0, errors.New("amount is too small to convert")
+	}
+	return converted.Int64(), nil
+}
+
+// The ConvertAccountParams type is the change by its owner of the currency of an account.
+type ConvertAccountParams struct {
+	Owner     string
+	AccountID int64
+	Currency  string
+	Memo      string
+}
+
+// The ConvertAccountResult type is the outcome of the change of the currency of an account.
+// @property {int64} Amount - the balance converted, in the previous currency.
+// @property {int64} ConvertedAmount - the balance after the conversion, in the new currency.
+// @property {int64} ExchangeRate - the rate the balance was converted at, in millionths.
+// @property {[]db.Transfer} Transfers - the two transfers through the fx_spread system accounts, none
+// when the account was empty.
+type ConvertAccountResult struct {
+	Account          db.Account
+	PreviousCurrency string
+	Amount           int64
+	ConvertedAmount  int64
+	ExchangeRate     int64
+	Transfers        []db.Transfer
+}
+
+// The ConvertAccount function changes the currency of an account the owner can use, converting its
+// balance at the exchange rate in effect in the same transaction, instead of the owner opening an account
+// in the other currency and moving the money to it. Like moves, conversions aren't subject to the
+// transfer limit, the screening or the approval of large transfers.
+func (service *Service) ConvertAccount(ctx context.Context, arg ConvertAccountParams) (ConvertAccountResult, error) {
+	if !util.IsSupportedCurrency(arg.Currency) {
+		return ConvertAccountResult{}, errorf(CodeInvalidArgument, "currency %s is not supported", arg.Currency).withField("currency")
+	}
+
+	account, err := service.ownedAccount(ctx, arg.Owner, arg.AccountID)
+	if err != nil {
+		return ConvertAccountResult{}, err
+	}
+	if account.ClosedAt.Valid {
+		return ConvertAccountResult{}, errorf(CodeFailedPrecondition, "account [%d] is closed", account.ID).withReason(ReasonAccountClosed)
+	}
+	if account.Currency == arg.Currency {
+		return ConvertAccountResult{}, errorf(CodeInvalidArgument, "account [%d] already holds %s", account.ID, arg.Currency).withReason(ReasonCurrencyMismatch).withField("currency")
+	}
+
+	rate, ok, err := service.activeParameter(ctx, db.FXRateParameter(account.Currency, arg.Currency))
+	if err != nil {
+		return ConvertAccountResult{}, err
+	}
+	if !ok {
+		return ConvertAccountResult{}, errorf(CodeFailedPrecondition, "no exchange rate from %s to %s was published", account.Currency, arg.Currency).withReason(ReasonNoExchangeRate)
+	}
+
+	result, err := service.store.ConvertAccountTx(ctx, db.ConvertAccountTxParams{
+		AccountID:    account.ID,
+		FromCurrency: account.Currency,
+		ToCurrency:   arg.Currency,
+		Convert: func(balance int64) (int64, error) {
+			converted, err := convertAmount(balance, rate)
+			if err != nil {
+				return 0, newError(CodeFailedPrecondition, err).withReason(ReasonInvalidAmount)
+			}
+			return converted, nil
+		},
+		Memo: arg.Memo,
+	})
+	if err != nil {
+		var serviceErr *Error
+		if errors.As(err, &serviceErr) {
+			return ConvertAccountResult{}, serviceErr
+		}
+		// the currency changed since the account was read
+		if errors.Is(err, db.ErrAccountVersionMismatch) {
+			return ConvertAccountResult{}, errorf(CodeFailedPrecondition, "account %d was updated while converting it", account.ID).withReason(ReasonVersionMismatch)
+		}
+		return ConvertAccountResult{}, storeError(err)
+	}
+
+	return ConvertAccountResult{
+		Account:          result.Account,
+		PreviousCurrency: account.Currency,
+		Amount:           result.Amount,
+		ConvertedAmount:  result.ConvertedAmount,
+		ExchangeRate:     rate,
+		Transfers:        result.Transfers,
+	}, nil
+}
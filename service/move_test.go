@@ -0,0 +1,320 @@
+package service
+
+import (
+	"context"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/testutil/factory"
+	"go-backend/util"
+	"math"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMoveMoney(t *testing.T) {
+	owner := util.RandomOwner()
+	usdAccount := factory.Account(factory.OwnedBy(owner), factory.InCurrency(util.USD))
+	savingsAccount := factory.Account(factory.OwnedBy(owner), factory.InCurrency(util.USD))
+	savingsAccount.ID = usdAccount.ID + 1
+	eurAccount := factory.Account(factory.OwnedBy(owner), factory.InCurrency(util.EUR))
+	eurAccount.ID = usdAccount.ID + 2
+	otherAccount := factory.Account(factory.OwnedBy(util.RandomOwner()), factory.InCurrency(util.USD))
+	otherAccount.ID = usdAccount.ID + 3
+
+	usdToEUR := db.BankParameter{Name: db.FXRateParameter(util.USD, util.EUR), Value: 920_000}
+
+	testCases := []struct {
+		name      string
+		arg       MoveMoneyParams
+		buildStub func(store *mockdb.MockStore)
+		check     func(t *testing.T, result MoveMoneyResult, err error)
+	}{
+		{
+			name: "SameCurrency",
+			arg:  MoveMoneyParams{Owner: owner, FromAccountID: usdAccount.ID, ToAccountID: savingsAccount.ID, Amount: 100},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(usdAccount.ID)).Times(1).Return(usdAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(savingsAccount.ID)).Times(1).Return(savingsAccount, nil)
+				// the transfer limit doesn't apply
+				store.EXPECT().GetActiveBankParameter(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Eq(db.TransferTxParams{
+					FromAccountID: usdAccount.ID,
+					ToAccountID:   savingsAccount.ID,
+					Amount:        100,
+					Currency:      usdAccount.Currency,
+				})).Times(1).Return(db.TransferTxResult{FromAccount: usdAccount, ToAccount: savingsAccount}, nil)
+			},
+			check: func(t *testing.T, result MoveMoneyResult, err error) {
+				require.NoError(t, err)
+				require.Equal(t, int64(100), result.ConvertedAmount)
+				require.Equal(t, int64(FXRateScale), result.ExchangeRate)
+				require.Len(t, result.Transfers, 1)
+			},
+		},
+		{
+			name: "Converted",
+			arg:  MoveMoneyParams{Owner: owner, FromAccountID: usdAccount.ID, ToAccountID: eurAccount.ID, Amount: 1_000, Memo: "holidays"},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(usdAccount.ID)).Times(1).Return(usdAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(eurAccount.ID)).Times(1).Return(eurAccount, nil)
+				store.EXPECT().
+					GetActiveBankParameter(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(ctx context.Context, arg db.GetActiveBankParameterParams) (db.BankParameter, error) {
+						require.Equal(t, "fx_rate_usd_eur", arg.Name)
+						return usdToEUR, nil
+					})
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().ExchangeTx(gomock.Any(), gomock.Eq(db.ExchangeTxParams{
+					FromAccountID:   usdAccount.ID,
+					FromCurrency:    util.USD,
+					ToAccountID:     eurAccount.ID,
+					ToCurrency:      util.EUR,
+					Amount:          1_000,
+					ConvertedAmount: 920,
+					Memo:            "holidays",
+				})).Times(1).Return(db.ExchangeTxResult{
+					Sold:   db.TransferTxResult{FromAccount: usdAccount},
+					Bought: db.TransferTxResult{ToAccount: eurAccount},
+				}, nil)
+			},
+			check: func(t *testing.T, result MoveMoneyResult, err error) {
+				require.NoError(t, err)
+				require.Equal(t, usdAccount.ID, result.FromAccount.ID)
+				require.Equal(t, eurAccount.ID, result.ToAccount.ID)
+				require.Equal(t, int64(920), result.ConvertedAmount)
+				require.Equal(t, usdToEUR.Value, result.ExchangeRate)
+				require.Len(t, result.Transfers, 2)
+			},
+		},
+		{
+			name: "NoExchangeRate",
+			arg:  MoveMoneyParams{Owner: owner, FromAccountID: usdAccount.ID, ToAccountID: eurAccount.ID, Amount: 1_000},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(usdAccount.ID)).Times(1).Return(usdAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(eurAccount.ID)).Times(1).Return(eurAccount, nil)
+				store.EXPECT().GetActiveBankParameter(gomock.Any(), gomock.Any()).Times(1).Return(db.BankParameter{}, db.ErrRecordNotFound)
+				store.EXPECT().ExchangeTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			check: func(t *testing.T, result MoveMoneyResult, err error) {
+				require.Equal(t, CodeFailedPrecondition, ErrorCode(err))
+				require.Equal(t, ReasonNoExchangeRate, ErrorReason(err))
+			},
+		},
+		{
+			name: "TooSmallToConvert",
+			arg:  MoveMoneyParams{Owner: owner, FromAccountID: usdAccount.ID, ToAccountID: eurAccount.ID, Amount: 1},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(usdAccount.ID)).Times(1).Return(usdAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(eurAccount.ID)).Times(1).Return(eurAccount, nil)
+				store.EXPECT().GetActiveBankParameter(gomock.Any(), gomock.Any()).Times(1).Return(usdToEUR, nil)
+				store.EXPECT().ExchangeTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			check: func(t *testing.T, result MoveMoneyResult, err error) {
+				require.Equal(t, CodeInvalidArgument, ErrorCode(err))
+			},
+		},
+		{
+			name: "NotOwner",
+			arg:  MoveMoneyParams{Owner: owner, FromAccountID: usdAccount.ID, ToAccountID: otherAccount.ID, Amount: 100},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(usdAccount.ID)).Times(1).Return(usdAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(otherAccount.ID)).Times(1).Return(otherAccount, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(db.AccountMember{}, db.ErrRecordNotFound)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			check: func(t *testing.T, result MoveMoneyResult, err error) {
+				require.Equal(t, CodePermissionDenied, ErrorCode(err))
+			},
+		},
+		{
+			name: "SameAccount",
+			arg:  MoveMoneyParams{Owner: owner, FromAccountID: usdAccount.ID, ToAccountID: usdAccount.ID, Amount: 100},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			check: func(t *testing.T, result MoveMoneyResult, err error) {
+				require.Equal(t, CodeInvalidArgument, ErrorCode(err))
+			},
+		},
+		{
+			name: "NegativeAmount",
+			arg:  MoveMoneyParams{Owner: owner, FromAccountID: usdAccount.ID, ToAccountID: savingsAccount.ID, Amount: -100},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			check: func(t *testing.T, result MoveMoneyResult, err error) {
+				require.Equal(t, CodeInvalidArgument, ErrorCode(err))
+				require.Equal(t, ReasonInvalidAmount, ErrorReason(err))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			result, err := newTestService(t, store).MoveMoney(context.Background(), tc.arg)
+			tc.check(t, result, err)
+		})
+	}
+}
+
+func TestConvertAmount(t *testing.T) {
+	converted, err := convertAmount(1_999, 500_000)
+	require.NoError(t, err)
+	require.Equal(t, int64(999), converted)
+
+	_, err = convertAmount(math.MaxInt64, 2*FXRateScale)
+	require.Error(t, err)
+}
+
+func TestConvertAccount(t *testing.T) {
+	owner := util.RandomOwner()
+	account := factory.Account(factory.OwnedBy(owner), factory.InCurrency(util.USD))
+	otherAccount := factory.Account(factory.OwnedBy(util.RandomOwner()), factory.InCurrency(util.USD))
+	otherAccount.ID = account.ID + 1
+
+	usdToEUR := db.BankParameter{Name: db.FXRateParameter(util.USD, util.EUR), Value: 920_000}
+
+	testCases := []struct {
+		name      string
+		arg       ConvertAccountParams
+		buildStub func(store *mockdb.MockStore)
+		check     func(t *testing.T, result ConvertAccountResult, err error)
+	}{
+		{
+			name: "OK",
+			arg:  ConvertAccountParams{Owner: owner, AccountID: account.ID, Currency: util.EUR, Memo: "moving"},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().
+					GetActiveBankParameter(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(ctx context.Context, arg db.GetActiveBankParameterParams) (db.BankParameter, error) {
+						require.Equal(t, "fx_rate_usd_eur", arg.Name)
+						return usdToEUR, nil
+					})
+				store.EXPECT().
+					ConvertAccountTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(ctx context.Context, arg db.ConvertAccountTxParams) (db.ConvertAccountTxResult, error) {
+						require.Equal(t, account.ID, arg.AccountID)
+						require.Equal(t, util.USD, arg.FromCurrency)
+						require.Equal(t, util.EUR, arg.ToCurrency)
+						require.Equal(t, "moving", arg.Memo)
+
+						// the balance read under the lock is converted, rounding down
+						converted, err := arg.Convert(1_001)
+						require.NoError(t, err)
+						require.Equal(t, int64(920), converted)
+
+						convertedAccount := account
+						convertedAccount.Currency = util.EUR
+						convertedAccount.Balance = 920
+						return db.ConvertAccountTxResult{Account: convertedAccount, Amount: 1_001, ConvertedAmount: 920, Transfers: make([]db.Transfer, 2)}, nil
+					})
+			},
+			check: func(t *testing.T, result ConvertAccountResult, err error) {
+				require.NoError(t, err)
+				require.Equal(t, util.EUR, result.Account.Currency)
+				require.Equal(t, util.USD, result.PreviousCurrency)
+				require.Equal(t, int64(1_001), result.Amount)
+				require.Equal(t, int64(920), result.ConvertedAmount)
+				require.Equal(t, usdToEUR.Value, result.ExchangeRate)
+				require.Len(t, result.Transfers, 2)
+			},
+		},
+		{
+			name: "TooSmallToConvert",
+			arg:  ConvertAccountParams{Owner: owner, AccountID: account.ID, Currency: util.EUR},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetActiveBankParameter(gomock.Any(), gomock.Any()).Times(1).Return(usdToEUR, nil)
+				store.EXPECT().
+					ConvertAccountTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(ctx context.Context, arg db.ConvertAccountTxParams) (db.ConvertAccountTxResult, error) {
+						_, err := arg.Convert(1)
+						return db.ConvertAccountTxResult{}, err
+					})
+			},
+			check: func(t *testing.T, result ConvertAccountResult, err error) {
+				require.Equal(t, CodeFailedPrecondition, ErrorCode(err))
+				require.Equal(t, ReasonInvalidAmount, ErrorReason(err))
+			},
+		},
+		{
+			name: "ChangedMeanwhile",
+			arg:  ConvertAccountParams{Owner: owner, AccountID: account.ID, Currency: util.EUR},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetActiveBankParameter(gomock.Any(), gomock.Any()).Times(1).Return(usdToEUR, nil)
+				store.EXPECT().ConvertAccountTx(gomock.Any(), gomock.Any()).Times(1).Return(db.ConvertAccountTxResult{}, db.ErrAccountVersionMismatch)
+			},
+			check: func(t *testing.T, result ConvertAccountResult, err error) {
+				require.Equal(t, CodeFailedPrecondition, ErrorCode(err))
+				require.Equal(t, ReasonVersionMismatch, ErrorReason(err))
+			},
+		},
+		{
+			name: "NoExchangeRate",
+			arg:  ConvertAccountParams{Owner: owner, AccountID: account.ID, Currency: util.EUR},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetActiveBankParameter(gomock.Any(), gomock.Any()).Times(1).Return(db.BankParameter{}, db.ErrRecordNotFound)
+				store.EXPECT().ConvertAccountTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			check: func(t *testing.T, result ConvertAccountResult, err error) {
+				require.Equal(t, CodeFailedPrecondition, ErrorCode(err))
+				require.Equal(t, ReasonNoExchangeRate, ErrorReason(err))
+			},
+		},
+		{
+			name: "SameCurrency",
+			arg:  ConvertAccountParams{Owner: owner, AccountID: account.ID, Currency: util.USD},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().ConvertAccountTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			check: func(t *testing.T, result ConvertAccountResult, err error) {
+				require.Equal(t, CodeInvalidArgument, ErrorCode(err))
+			},
+		},
+		{
+			name: "NotOwner",
+			arg:  ConvertAccountParams{Owner: owner, AccountID: otherAccount.ID, Currency: util.EUR},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(otherAccount.ID)).Times(1).Return(otherAccount, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(db.AccountMember{}, db.ErrRecordNotFound)
+				store.EXPECT().ConvertAccountTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			check: func(t *testing.T, result ConvertAccountResult, err error) {
+				require.Equal(t, CodePermissionDenied, ErrorCode(err))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			result, err := newTestService(t, store).ConvertAccount(context.Background(), tc.arg)
+			tc.check(t, result, err)
+		})
+	}
+}
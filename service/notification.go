@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	db "go-backend/db/sqlc"
+	"go-backend/util"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// The ListNotifications function lists the notifications of a user, newest first.
+func (service *Service) ListNotifications(ctx context.Context, username string, limit int32, offset int32) ([]db.Notification, error) {
+	notifications, err := service.store.ListNotifications(ctx, db.ListNotificationsParams{
+		Username: username,
+		Limit:    limit,
+		Offset:   offset,
+	})
+	if err != nil {
+		return nil, storeError(err)
+	}
+
+	return notifications, nil
+}
+
+// The MarkNotificationRead function marks a notification of the user as read. A notification of another
+// user isn't found.
+func (service *Service) MarkNotificationRead(ctx context.Context, username string, id int64) (db.Notification, error) {
+	notification, err := service.store.MarkNotificationRead(ctx, db.MarkNotificationReadParams{
+		ID:       id,
+		Username: username,
+	})
+	if err != nil {
+		return notification, storeError(err)
+	}
+
+	return notification, nil
+}
+
+// The MarkAllNotificationsRead function marks every unread notification of the user as read, returning
+// how many were.
+func (service *Service) MarkAllNotificationsRead(ctx context.Context, username string) (int64, error) {
+	marked, err := service.store.MarkAllNotificationsRead(ctx, username)
+	if err != nil {
+		return 0, storeError(err)
+	}
+
+	return marked, nil
+}
+
+// The GetNotificationPreferences function returns the notification preferences of the user, the default
+// ones when they never set them.
+func (service *Service) GetNotificationPreferences(ctx context.Context, username string) (db.NotificationPreference, error) {
+	preferences, err := db.NotificationPreferencesOf(ctx, service.store, username)
+	if err != nil {
+		return preferences, storeError(err)
+	}
+
+	return preferences, nil
+}
+
+// The UpdateNotificationPreferencesParams type holds the channels a user is notified through.
+// @property {bool} InApp - whether the notifications are listed in the app.
+// @property {bool} Email - whether the notifications are sent to the email of the user.
+// @property {string} WebhookURL - the http or https URL the notifications are posted to, empty to not
+// post them.
+// @property {int64} LowBalanceThreshold - a transfer leaving an account below it notifies the user, 0 to
+// never notify.
+type UpdateNotificationPreferencesParams struct {
+	Username            string
+	InApp               bool
+	Email               bool
+	WebhookURL          string
+	LowBalanceThreshold int64
+}
+
+// The UpdateNotificationPreferences function replaces the notification preferences of the user. They
+// apply to the events projected from then on.
+func (service *Service) UpdateNotificationPreferences(ctx context.Context, arg UpdateNotificationPreferencesParams) (db.NotificationPreference, error) {
+	if arg.LowBalanceThreshold < 0 {
+		return db.NotificationPreference{}, errorf(CodeInvalidArgument, "low balance threshold can't be negative, got %d", arg.LowBalanceThreshold)
+	}
+	if arg.WebhookURL != "" {
+		webhook, err := url.Parse(arg.WebhookURL)
+		if err != nil || (webhook.Scheme != "http" && webhook.Scheme != "https") || webhook.Host == "" {
+			return db.NotificationPreference{}, errorf(CodeInvalidArgument, "webhook URL %s must be an http or https URL", arg.WebhookURL)
+		}
+		// the hosts resolving to other addresses than public ones are refused when the webhook is posted to
+		host := webhook.Hostname()
+		ip := net.ParseIP(host)
+		if strings.EqualFold(host, "localhost") || strings.HasSuffix(strings.ToLower(host), ".localhost") || (ip != nil && !util.IsPublicIP(ip)) {
+			return db.NotificationPreference{}, errorf(CodeInvalidArgument, "webhook URL %s must be a public address", arg.WebhookURL)
+		}
+	}
+
+	preferences, err := service.store.UpsertNotificationPreferences(ctx, db.UpsertNotificationPreferencesParams{
+		Username:            arg.Username,
+		InApp:               arg.InApp,
+		Email:               arg.Email,
+		WebhookURL:          arg.WebhookURL,
+		LowBalanceThreshold: arg.LowBalanceThreshold,
+	})
+	if err != nil {
+		return preferences, storeError(err)
+	}
+
+	return preferences, nil
+}
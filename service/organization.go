@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"errors"
+	db "go-backend/db/sqlc"
+	"go-backend/util"
+	"regexp"
+)
+
+// organizationSlugPattern is the form of the slugs of the organizations, which the users give when signing
+// up: lowercase letters, digits and dashes, not starting with a dash.
+var organizationSlugPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{1,62}$`)
+
+// The CreateOrganizationParams type is a new organization of the platform.
+// @property {string} Slug - the unique name the users give to sign up to the organization.
+// @property {string} Name - the name of the organization shown to its users.
+type CreateOrganizationParams struct {
+	Slug string
+	Name string
+}
+
+// The CreateOrganization function creates an organization, the users then signing up to it having their
+// own users and accounts apart from those of the other organizations.
+func (service *Service) CreateOrganization(ctx context.Context, arg CreateOrganizationParams) (db.Organization, error) {
+	if !organizationSlugPattern.MatchString(arg.Slug) {
+		return db.Organization{}, errorf(CodeInvalidArgument, "invalid organization slug %q", arg.Slug).withField("slug")
+	}
+	if arg.Name == "" {
+		return db.Organization{}, errorf(CodeInvalidArgument, "the name of the organization is required").withField("name")
+	}
+
+	organization, err := service.store.CreateOrganization(ctx, db.CreateOrganizationParams{
+		Slug: arg.Slug,
+		Name: arg.Name,
+	})
+	if err != nil {
+		if errors.Is(db.TranslateError(err), db.ErrUniqueViolation) {
+			return organization, errorf(CodeAlreadyExists, "organization %s already exists", arg.Slug).withField("slug")
+		}
+		return organization, storeError(err)
+	}
+	return organization, nil
+}
+
+// The GetOrganization function returns an organization.
+func (service *Service) GetOrganization(ctx context.Context, orgID int64) (db.Organization, error) {
+	organization, err := service.store.GetOrganization(ctx, orgID)
+	if err != nil {
+		return organization, storeError(err)
+	}
+	return organization, nil
+}
+
+// The ListOrganizationUsers function lists the users of an organization by username.
+func (service *Service) ListOrganizationUsers(ctx context.Context, orgID int64, limit int32, offset int32) ([]db.User, error) {
+	users, err := service.store.ListOrganizationUsers(ctx, db.ListOrganizationUsersParams{
+		OrgID:  orgID,
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return nil, storeError(err)
+	}
+	return users, nil
+}
+
+// The SetOrganizationRoleParams type is a role given to a user by an admin of their organization.
+// @property {int64} OrgID - the organization of the admin, which the user must belong to.
+type SetOrganizationRoleParams struct {
+	OrgID    int64
+	Username string
+	Role     string
+}
+
+// The SetOrganizationRole function makes a user of an organization an admin of it or a depositor again.
+// Only these roles are given within an organization, the admins of the platform and the system user
+// being out of reach, and the users of the other organizations aren't found.
+func (service *Service) SetOrganizationRole(ctx context.Context, arg SetOrganizationRoleParams) (db.User, error) {
+	if arg.Role != util.DepositorRole && arg.Role != util.OrgAdminRole {
+		return db.User{}, errorf(CodeInvalidArgument, "role %s can't be given within an organization", arg.Role).withField("role")
+	}
+
+	user, err := service.store.GetUser(ctx, arg.Username)
+	if err != nil {
+		return user, storeError(err)
+	}
+	if user.OrgID != arg.OrgID {
+		return db.User{}, storeError(db.ErrRecordNotFound)
+	}
+	if user.Role != util.DepositorRole && user.Role != util.OrgAdminRole {
+		return db.User{}, errorf(CodePermissionDenied, "the role of user %s can't be changed within an organization", arg.Username)
+	}
+
+	user, err = service.store.UpdateUserRole(ctx, db.UpdateUserRoleParams{
+		Username: arg.Username,
+		Role:     arg.Role,
+	})
+	if err != nil {
+		return user, storeError(err)
+	}
+	return user, nil
+}
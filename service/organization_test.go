@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/testutil/factory"
+	"go-backend/util"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateOrganization(t *testing.T) {
+	organization := db.Organization{ID: 2, Slug: "acme", Name: "Acme"}
+
+	testCases := []struct {
+		name      string
+		arg       CreateOrganizationParams
+		buildStub func(store *mockdb.MockStore)
+		code      *Code
+	}{
+		{
+			name: "OK",
+			arg:  CreateOrganizationParams{Slug: organization.Slug, Name: organization.Name},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateOrganization(gomock.Any(), gomock.Eq(db.CreateOrganizationParams{Slug: organization.Slug, Name: organization.Name})).
+					Times(1).
+					Return(organization, nil)
+			},
+		},
+		{
+			name: "InvalidSlug",
+			arg:  CreateOrganizationParams{Slug: "Acme Bank", Name: organization.Name},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateOrganization(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code: codePtr(CodeInvalidArgument),
+		},
+		{
+			name: "Taken",
+			arg:  CreateOrganizationParams{Slug: organization.Slug, Name: organization.Name},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateOrganization(gomock.Any(), gomock.Any()).Times(1).Return(db.Organization{}, db.ErrUniqueViolation)
+			},
+			code: codePtr(CodeAlreadyExists),
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			created, err := newTestService(t, store).CreateOrganization(context.Background(), tc.arg)
+			if tc.code != nil {
+				require.Equal(t, *tc.code, ErrorCode(err))
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, organization, created)
+		})
+	}
+}
+
+func TestSetOrganizationRole(t *testing.T) {
+	user := factory.User()
+	other := factory.User(factory.InOrganization(2))
+	admin := factory.User(factory.WithRole(util.AdminRole))
+
+	testCases := []struct {
+		name      string
+		arg       SetOrganizationRoleParams
+		buildStub func(store *mockdb.MockStore)
+		code      *Code
+	}{
+		{
+			name: "OK",
+			arg:  SetOrganizationRoleParams{OrgID: db.DefaultOrganizationID, Username: user.Username, Role: util.OrgAdminRole},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+				store.EXPECT().
+					UpdateUserRole(gomock.Any(), gomock.Eq(db.UpdateUserRoleParams{Username: user.Username, Role: util.OrgAdminRole})).
+					Times(1).
+					Return(user, nil)
+			},
+		},
+		{
+			name: "PlatformRole",
+			arg:  SetOrganizationRoleParams{OrgID: db.DefaultOrganizationID, Username: user.Username, Role: util.AdminRole},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code: codePtr(CodeInvalidArgument),
+		},
+		{
+			name: "OtherOrganization",
+			arg:  SetOrganizationRoleParams{OrgID: db.DefaultOrganizationID, Username: other.Username, Role: util.OrgAdminRole},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(other.Username)).Times(1).Return(other, nil)
+				store.EXPECT().UpdateUserRole(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code: codePtr(CodeNotFound),
+		},
+		{
+			name: "PlatformAdmin",
+			arg:  SetOrganizationRoleParams{OrgID: db.DefaultOrganizationID, Username: admin.Username, Role: util.DepositorRole},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).Times(1).Return(admin, nil)
+				store.EXPECT().UpdateUserRole(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code: codePtr(CodePermissionDenied),
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			_, err := newTestService(t, store).SetOrganizationRole(context.Background(), tc.arg)
+			if tc.code != nil {
+				require.Equal(t, *tc.code, ErrorCode(err))
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
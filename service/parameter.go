@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"errors"
+	db "go-backend/db/sqlc"
+	"go-backend/util"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// IsSupportedParameter reports whether the name is one of the parameters of the bank, the exchange
+// rates being named after two different supported currencies.
+func IsSupportedParameter(name string) bool {
+	switch name {
+	case db.ParameterTransferLimit, db.ParameterTransferFee, db.ParameterInterestRate, db.ParameterMonthlyFee:
+		return true
+	}
+	return isFXRateParameter(name)
+}
+
+func isFXRateParameter(name string) bool {
+	if !strings.HasPrefix(name, db.ParameterFXRatePrefix) {
+		return false
+	}
+	from, to, ok := strings.Cut(strings.ToUpper(strings.TrimPrefix(name, db.ParameterFXRatePrefix)), "_")
+	return ok && from != to && util.IsSupportedCurrency(from) && util.IsSupportedCurrency(to) && name == db.FXRateParameter(from, to)
+}
+
+// The PublishParameterParams type is a new version of a parameter published by an admin.
+// @property {string} Username - the admin publishing the version.
+// @property {string} Name - the name of the parameter.
+// @property {int64} Value - the value of the parameter, which can't be negative.
+// @property {time.Time} EffectiveAt - when the version takes effect, now when it is zero.
+type PublishParameterParams struct {
+	Username    string
+	Name        string
+	Value       int64
+	EffectiveAt time.Time
+}
+
+// The PublishParameter function publishes the next version of a parameter. Versions can take effect
+// in the future but not in the past, as transactions already resolved the version active back then.
+func (service *Service) PublishParameter(ctx context.Context, arg PublishParameterParams) (db.BankParameter, error) {
+	if !IsSupportedParameter(arg.Name) {
+		return db.BankParameter{}, errorf(CodeInvalidArgument, "unknown parameter %s", arg.Name)
+	}
+	if arg.Value < 0 {
+		return db.BankParameter{}, errorf(CodeInvalidArgument, "value must not be negative, got %d", arg.Value)
+	}
+	if arg.Value == 0 && isFXRateParameter(arg.Name) {
+		return db.BankParameter{}, errorf(CodeInvalidArgument, "exchange rate %s must be positive", arg.Name)
+	}
+
+	now := time.Now()
+	effectiveAt := arg.EffectiveAt
+	if effectiveAt.IsZero() {
+		effectiveAt = now
+	}
+	if effectiveAt.Before(now.Add(-time.Minute)) {
+		return db.BankParameter{}, errorf(CodeInvalidArgument, "effective date %s is in the past", effectiveAt.Format(time.RFC3339))
+	}
+
+	parameter, err := service.store.CreateBankParameter(ctx, db.CreateBankParameterParams{
+		Name:        arg.Name,
+		Value:       arg.Value,
+		EffectiveAt: effectiveAt,
+		PublishedBy: arg.Username,
+	})
+	if err != nil {
+		return parameter, storeError(err)
+	}
+
+	return parameter, nil
+}
+
+// The ListActiveParameters function lists the version of every parameter in effect at the given time.
+func (service *Service) ListActiveParameters(ctx context.Context, at time.Time) ([]db.BankParameter, error) {
+	parameters, err := service.store.ListActiveBankParameters(ctx, at)
+	if err != nil {
+		return nil, storeError(err)
+	}
+
+	return parameters, nil
+}
+
+// The ListParameterVersions function lists the published versions of the parameters, or of a single one
+// when the name isn't empty, the latest first.
+func (service *Service) ListParameterVersions(ctx context.Context, name string, limit int32, offset int32) ([]db.BankParameter, error) {
+	parameters, err := service.store.ListBankParameterVersions(ctx, db.ListBankParameterVersionsParams{
+		Name:      pgtype.Text{String: name, Valid: name != ""},
+		RowLimit:  limit,
+		RowOffset: offset,
+	})
+	if err != nil {
+		return nil, storeError(err)
+	}
+
+	return parameters, nil
+}
+
+// The activeParameter function resolves the value of the parameter in effect now, and whether the
+// parameter was ever published.
+func (service *Service) activeParameter(ctx context.Context, name string) (int64, bool, error) {
+	parameter, err := service.store.GetActiveBankParameter(ctx, db.GetActiveBankParameterParams{
+		Name: name,
+		At:   time.Now(),
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return 0, false, nil
+		}
+		return 0, false, storeError(err)
+	}
+
+	return parameter.Value, true, nil
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/util"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublishParameter(t *testing.T) {
+	admin := util.RandomOwner()
+	effectiveAt := time.Now().Add(24 * time.Hour)
+
+	testCases := []struct {
+		name      string
+		arg       PublishParameterParams
+		buildStub func(store *mockdb.MockStore)
+		code      *Code
+	}{
+		{
+			name: "OK",
+			arg:  PublishParameterParams{Username: admin, Name: db.ParameterTransferLimit, Value: 5000, EffectiveAt: effectiveAt},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateBankParameter(gomock.Any(), gomock.Eq(db.CreateBankParameterParams{
+					Name:        db.ParameterTransferLimit,
+					Value:       5000,
+					EffectiveAt: effectiveAt,
+					PublishedBy: admin,
+				})).Times(1).Return(db.BankParameter{Name: db.ParameterTransferLimit, Version: 2}, nil)
+			},
+		},
+		{
+			name: "EffectiveNow",
+			arg:  PublishParameterParams{Username: admin, Name: db.ParameterTransferFee, Value: 0},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateBankParameter(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(ctx context.Context, arg db.CreateBankParameterParams) (db.BankParameter, error) {
+						require.WithinDuration(t, time.Now(), arg.EffectiveAt, time.Second)
+						return db.BankParameter{}, nil
+					})
+			},
+		},
+		{
+			name: "UnknownParameter",
+			arg:  PublishParameterParams{Username: admin, Name: "overdraft", Value: 10},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateBankParameter(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code: codePtr(CodeInvalidArgument),
+		},
+		{
+			name: "NegativeValue",
+			arg:  PublishParameterParams{Username: admin, Name: db.ParameterInterestRate, Value: -1},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateBankParameter(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code: codePtr(CodeInvalidArgument),
+		},
+		{
+			name: "FXRate",
+			arg:  PublishParameterParams{Username: admin, Name: "fx_rate_usd_cad", Value: 1350000},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateBankParameter(gomock.Any(), gomock.Any()).Times(1)
+			},
+		},
+		{
+			name: "ZeroFXRate",
+			arg:  PublishParameterParams{Username: admin, Name: "fx_rate_usd_cad", Value: 0},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateBankParameter(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code: codePtr(CodeInvalidArgument),
+		},
+		{
+			name: "FXRateOfUnsupportedCurrency",
+			arg:  PublishParameterParams{Username: admin, Name: "fx_rate_usd_gbp", Value: 790000},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateBankParameter(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code: codePtr(CodeInvalidArgument),
+		},
+		{
+			name: "EffectiveInThePast",
+			arg:  PublishParameterParams{Username: admin, Name: db.ParameterTransferLimit, Value: 10, EffectiveAt: time.Now().Add(-time.Hour)},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateBankParameter(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code: codePtr(CodeInvalidArgument),
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			_, err := newTestService(t, store).PublishParameter(context.Background(), tc.arg)
+			if tc.code == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.Equal(t, *tc.code, ErrorCode(err))
+		})
+	}
+}
+
+func TestIsSupportedParameter(t *testing.T) {
+	require.True(t, IsSupportedParameter(db.ParameterTransferLimit))
+	require.True(t, IsSupportedParameter(db.FXRateParameter(util.EUR, util.USD)))
+	require.False(t, IsSupportedParameter("fx_rate_usd_usd"))
+	require.False(t, IsSupportedParameter("fx_rate_USD_CAD"))
+	require.False(t, IsSupportedParameter("fx_rate_usd"))
+	require.False(t, IsSupportedParameter("overdraft"))
+}
@@ -0,0 +1,245 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"go-backend/iso20022"
+	"go-backend/util"
+	"io"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// notProvided is the end-to-end id of the transactions the customer gave none, by ISO 20022 convention.
+const notProvided = "NOTPROVIDED"
+
+// maxMemoLength is the longest memo of a transfer, longer remittance information being cut.
+const maxMemoLength = 140
+
+// The ImportPaymentInitiation function makes the credit transfers of a pain.001 file sent by the owner, a
+// batch of transfers from accounts of theirs identified by their account number or id, as a batch
+// transfer. The file is rejected as a whole when its group header doesn't match its transactions, and
+// otherwise each transaction is checked and made on its own like in a batch. It returns the pain.002
+// status report of the file, where a transaction is settled when made, pending when held for review or
+// awaiting approval, and rejected with the ISO 20022 reason code of the error that prevented it. An error
+// is only returned when the file can't be read or the batch as a whole failed.
+func (service *Service) ImportPaymentInitiation(ctx context.Context, owner string, file io.Reader) (iso20022.StatusReport, error) {
+	initiation, err := iso20022.ParseInitiation(file)
+	if err != nil {
+		return iso20022.StatusReport{}, newError(CodeInvalidArgument, err)
+	}
+
+	report := iso20022.StatusReport{
+		MessageID:                    strings.ReplaceAll(uuid.NewString(), "-", ""),
+		CreatedAt:                    time.Now(),
+		OriginalMessageID:            initiation.MessageID,
+		OriginalNumberOfTransactions: initiation.NumberOfTransactions,
+		OriginalControlSum:           initiation.ControlSum,
+	}
+	if reason := checkInitiationHeader(initiation); reason != nil {
+		report.GroupStatus = iso20022.StatusRejected
+		report.GroupReason = reason
+		return report, nil
+	}
+
+	// the transactions which can't be made into transfers are rejected right away, the others are made
+	// as a batch and their status is set from their outcome
+	statuses := make([][]iso20022.TransactionStatus, len(initiation.Payments))
+	var transfers []CreateTransferParams
+	var positions [][2]int
+	for i, payment := range initiation.Payments {
+		statuses[i] = make([]iso20022.TransactionStatus, len(payment.Transactions))
+		for j, transaction := range payment.Transactions {
+			statuses[i][j] = iso20022.TransactionStatus{
+				OriginalInstructionID: transaction.InstructionID,
+				OriginalEndToEndID:    transaction.EndToEndID,
+			}
+
+			transfer, reason := newInitiatedTransfer(payment, transaction)
+			if reason != nil {
+				statuses[i][j].Status = iso20022.StatusRejected
+				statuses[i][j].Reason = reason
+				continue
+			}
+			transfers = append(transfers, transfer)
+			positions = append(positions, [2]int{i, j})
+		}
+	}
+
+	if len(transfers) > 0 {
+		outcomes, err := service.CreateBatchTransfer(ctx, owner, transfers)
+		if err != nil {
+			return iso20022.StatusReport{}, err
+		}
+
+		for k, outcome := range outcomes {
+			status := &statuses[positions[k][0]][positions[k][1]]
+			switch {
+			case outcome.Err != nil:
+				status.Status = iso20022.StatusRejected
+				status.Reason = initiationStatusReason(outcome.Err)
+			case outcome.Review != nil:
+				status.Status = iso20022.StatusPending
+				status.Reference = fmt.Sprintf("review:%d", outcome.Review.ID)
+			case outcome.Pending != nil:
+				status.Status = iso20022.StatusPending
+				status.Reference = fmt.Sprintf("pending_transfer:%d", outcome.Pending.ID)
+			default:
+				status.Status = iso20022.StatusSettled
+				status.Reference = fmt.Sprintf("transfer:%d", outcome.Result.Transfer.ID)
+			}
+		}
+	}
+
+	var all []string
+	for i, payment := range initiation.Payments {
+		var paymentStatuses []string
+		for _, status := range statuses[i] {
+			paymentStatuses = append(paymentStatuses, status.Status)
+		}
+		all = append(all, paymentStatuses...)
+
+		report.Payments = append(report.Payments, iso20022.PaymentStatus{
+			OriginalPaymentInformationID: payment.ID,
+			Status:                       iso20022.AggregateStatus(paymentStatuses),
+			Transactions:                 statuses[i],
+		})
+	}
+	report.GroupStatus = iso20022.AggregateStatus(all)
+
+	return report, nil
+}
+
+// The checkInitiationHeader function checks the group header of an initiation against its transactions,
+// returning why the whole initiation is rejected when they don't match, nil otherwise.
+func checkInitiationHeader(initiation iso20022.Initiation) *iso20022.StatusReason {
+	count := initiation.Transactions()
+	if initiation.NumberOfTransactions != strconv.Itoa(count) {
+		return &iso20022.StatusReason{
+			Code:                  iso20022.ReasonInvalidNumberOfTransactions,
+			AdditionalInformation: fmt.Sprintf("the file declares %s transactions but holds %d", initiation.NumberOfTransactions, count),
+		}
+	}
+	if count == 0 || count > MaxBatchTransfers {
+		return &iso20022.StatusReason{
+			Code:                  iso20022.ReasonInvalidNumberOfTransactions,
+			AdditionalInformation: fmt.Sprintf("a file must hold between 1 and %d transactions, got %d", MaxBatchTransfers, count),
+		}
+	}
+
+	if initiation.ControlSum == "" {
+		return nil
+	}
+	declared, ok := new(big.Rat).SetString(initiation.ControlSum)
+	sum := new(big.Rat)
+	for _, payment := range initiation.Payments {
+		for _, transaction := range payment.Transactions {
+			amount, valid := new(big.Rat).SetString(transaction.Amount)
+			ok = ok && valid
+			if valid {
+				sum.Add(sum, amount)
+			}
+		}
+	}
+	if !ok || declared.Cmp(sum) != 0 {
+		return &iso20022.StatusReason{
+			Code:                  iso20022.ReasonInvalidControlSum,
+			AdditionalInformation: fmt.Sprintf("the control sum %s doesn't match the amounts of the transactions", initiation.ControlSum),
+		}
+	}
+
+	return nil
+}
+
+// The newInitiatedTransfer function makes a transaction of an initiation into a transfer, returning why it
+// is rejected when it can't be. The accounts are identified by their account number or their id, and the
+// amounts must be whole units of the currency like those of every transfer.
+func newInitiatedTransfer(payment iso20022.PaymentInformation, transaction iso20022.CreditTransfer) (CreateTransferParams, *iso20022.StatusReason) {
+	fromAccountID, fromAccountNumber, ok := initiatedAccount(payment.DebtorAccount)
+	if !ok {
+		return CreateTransferParams{}, &iso20022.StatusReason{
+			Code:                  iso20022.ReasonIncorrectAccountNumber,
+			AdditionalInformation: fmt.Sprintf("unknown debtor account %q", payment.DebtorAccount),
+		}
+	}
+
+	toAccountID, toAccountNumber, ok := initiatedAccount(transaction.CreditorAccount)
+	if !ok {
+		return CreateTransferParams{}, &iso20022.StatusReason{
+			Code:                  iso20022.ReasonIncorrectAccountNumber,
+			AdditionalInformation: fmt.Sprintf("unknown creditor account %q", transaction.CreditorAccount),
+		}
+	}
+
+	if !util.IsSupportedCurrency(transaction.Currency) || (payment.Currency != "" && payment.Currency != transaction.Currency) {
+		return CreateTransferParams{}, &iso20022.StatusReason{
+			Code:                  iso20022.ReasonInvalidCurrency,
+			AdditionalInformation: fmt.Sprintf("unsupported currency %q", transaction.Currency),
+		}
+	}
+
+	amount, ok := new(big.Rat).SetString(transaction.Amount)
+	if !ok || !amount.IsInt() || amount.Sign() <= 0 || !amount.Num().IsInt64() {
+		return CreateTransferParams{}, &iso20022.StatusReason{
+			Code:                  iso20022.ReasonInvalidAmount,
+			AdditionalInformation: fmt.Sprintf("invalid amount %q, must be a positive whole number", transaction.Amount),
+		}
+	}
+
+	reference := transaction.EndToEndID
+	if reference == notProvided {
+		reference = ""
+	}
+	memo := transaction.RemittanceInformation
+	if len(memo) > maxMemoLength {
+		memo = memo[:maxMemoLength]
+	}
+
+	return CreateTransferParams{
+		FromAccountID:     fromAccountID,
+		FromAccountNumber: fromAccountNumber,
+		ToAccountID:       toAccountID,
+		ToAccountNumber:   toAccountNumber,
+		Amount:            amount.Num().Int64(),
+		Currency:          transaction.Currency,
+		Memo:              memo,
+		ExternalReference: reference,
+	}, nil
+}
+
+// The initiatedAccount function identifies an account of an initiation, by its account number, e.g. the
+// IBAN of the account, or its id.
+func initiatedAccount(account string) (int64, string, bool) {
+	if util.ValidAccountNumber(account) {
+		return 0, account, true
+	}
+
+	id, err := strconv.ParseInt(account, 10, 64)
+	return id, "", err == nil && id > 0
+}
+
+// The initiationStatusReason function returns the ISO 20022 reason a transaction of an initiation was
+// rejected for by the service.
+func initiationStatusReason(err error) *iso20022.StatusReason {
+	reason := &iso20022.StatusReason{Code: iso20022.ReasonNarrative, AdditionalInformation: err.Error()}
+	switch ErrorReason(err) {
+	case ReasonInvalidAmount:
+		reason.Code = iso20022.ReasonInvalidAmount
+	case ReasonCurrencyMismatch:
+		reason.Code = iso20022.ReasonInvalidCurrency
+	case ReasonTransferLimitExceeded:
+		reason.Code = iso20022.ReasonNotAllowedAmount
+	default:
+		switch ErrorCode(err) {
+		case CodeNotFound:
+			reason.Code = iso20022.ReasonIncorrectAccountNumber
+		case CodePermissionDenied:
+			reason.Code = iso20022.ReasonTransactionForbidden
+		}
+	}
+	return reason
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"go-backend/clock"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/iso20022"
+	"go-backend/testutil/factory"
+	"go-backend/util"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestInitiation returns a pain.001 document of a payment from the account, the transactions being
+// given as their creditor account, amount and currency.
+func newTestInitiation(numberOfTransactions, controlSum string, debtorAccountID int64, transactions ...[3]string) string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, `<Document xmlns="urn:iso:std:iso:20022:tech:xsd:pain.001.001.03"><CstmrCdtTrfInitn>`)
+	fmt.Fprintf(&builder, `<GrpHdr><MsgId>MSG-1</MsgId><NbOfTxs>%s</NbOfTxs><CtrlSum>%s</CtrlSum></GrpHdr>`, numberOfTransactions, controlSum)
+	fmt.Fprintf(&builder, `<PmtInf><PmtInfId>PMT-1</PmtInfId><DbtrAcct><Id><Othr><Id>%d</Id></Othr></Id></DbtrAcct>`, debtorAccountID)
+	for i, transaction := range transactions {
+		fmt.Fprintf(&builder, `<CdtTrfTxInf><PmtId><EndToEndId>E2E-%d</EndToEndId></PmtId>`, i+1)
+		fmt.Fprintf(&builder, `<Amt><InstdAmt Ccy="%s">%s</InstdAmt></Amt>`, transaction[2], transaction[1])
+		fmt.Fprintf(&builder, `<CdtrAcct><Id><Othr><Id>%s</Id></Othr></Id></CdtrAcct><RmtInf><Ustrd>salary</Ustrd></RmtInf></CdtTrfTxInf>`, transaction[0])
+	}
+	builder.WriteString(`</PmtInf></CstmrCdtTrfInitn></Document>`)
+	return builder.String()
+}
+
+func TestImportPaymentInitiation(t *testing.T) {
+	owner := util.RandomOwner()
+	fromAccount := factory.Account(factory.OwnedBy(owner), factory.InCurrency(util.USD))
+	toAccount := factory.Account(factory.OwnedBy(util.RandomOwner()), factory.InCurrency(util.USD))
+	toAccount.ID = fromAccount.ID + 1
+	toAccount.AccountNumber = util.AccountNumber(toAccount.ID)
+	to := fmt.Sprint(toAccount.ID)
+	now := time.Now()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetActiveBankParameter(gomock.Any(), gomock.Any()).Times(1).
+		Return(db.BankParameter{Name: db.ParameterTransferLimit, Value: 500}, nil)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(2).Return(fromAccount, nil)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(2).Return(toAccount, nil)
+	store.EXPECT().GetAccountByNumber(gomock.Any(), gomock.Eq(toAccount.AccountNumber)).Times(1).Return(toAccount, nil)
+
+	// the accounts are identified by their id or account number, and only the transactions which are
+	// valid transfers reach the batch
+	store.EXPECT().BatchTransferTx(gomock.Any(), gomock.Eq(db.BatchTransferTxParams{
+		Transfers: []db.TransferTxParams{
+			{FromAccountID: fromAccount.ID, ToAccountID: toAccount.ID, Amount: 100, Memo: "salary", ExternalReference: "E2E-1", Currency: util.USD, ChargeFee: true, Now: now},
+		},
+	})).Times(1).Return([]db.BatchTransferTxItem{{Result: db.TransferTxResult{Transfer: db.Transfer{ID: 7}}}}, nil)
+
+	file := newTestInitiation("5", "1960.50", fromAccount.ID,
+		[3]string{toAccount.AccountNumber, "100.00", util.USD},
+		[3]string{to, "1000", util.USD},
+		[3]string{to, "10.50", util.USD},
+		[3]string{"ACME-42", "100", util.USD},
+		[3]string{to, "750", "XYZ"},
+	)
+	service := newTestService(t, store)
+	service.SetClock(clock.NewFake(now))
+	report, err := service.ImportPaymentInitiation(context.Background(), owner, strings.NewReader(file))
+	require.NoError(t, err)
+
+	require.Len(t, report.MessageID, 32)
+	require.Equal(t, "MSG-1", report.OriginalMessageID)
+	require.Equal(t, iso20022.StatusPartiallyAccepted, report.GroupStatus)
+	require.Nil(t, report.GroupReason)
+	require.Len(t, report.Payments, 1)
+	require.Equal(t, "PMT-1", report.Payments[0].OriginalPaymentInformationID)
+	require.Equal(t, iso20022.StatusPartiallyAccepted, report.Payments[0].Status)
+
+	transactions := report.Payments[0].Transactions
+	require.Len(t, transactions, 5)
+	require.Equal(t, "E2E-1", transactions[0].OriginalEndToEndID)
+	require.Equal(t, iso20022.StatusSettled, transactions[0].Status)
+	require.Equal(t, "transfer:7", transactions[0].Reference)
+	for i, code := range []string{
+		iso20022.ReasonNotAllowedAmount,
+		iso20022.ReasonInvalidAmount,
+		iso20022.ReasonIncorrectAccountNumber,
+		iso20022.ReasonInvalidCurrency,
+	} {
+		require.Equal(t, iso20022.StatusRejected, transactions[i+1].Status)
+		require.Equal(t, code, transactions[i+1].Reason.Code)
+	}
+}
+
+func TestImportPaymentInitiationRejected(t *testing.T) {
+	testCases := []struct {
+		name   string
+		file   string
+		code   *Code
+		reason string
+	}{
+		{
+			name: "InvalidFile",
+			file: "date,amount\n2024-01-01,10\n",
+			code: codePtr(CodeInvalidArgument),
+		},
+		{
+			name:   "NumberOfTransactionsMismatch",
+			file:   newTestInitiation("2", "", 1, [3]string{"2", "100", util.USD}),
+			reason: iso20022.ReasonInvalidNumberOfTransactions,
+		},
+		{
+			name:   "NoTransactions",
+			file:   newTestInitiation("0", "", 1),
+			reason: iso20022.ReasonInvalidNumberOfTransactions,
+		},
+		{
+			name:   "ControlSumMismatch",
+			file:   newTestInitiation("1", "99.99", 1, [3]string{"2", "100", util.USD}),
+			reason: iso20022.ReasonInvalidControlSum,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// a file rejected as a whole makes no transfer
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().BatchTransferTx(gomock.Any(), gomock.Any()).Times(0)
+
+			report, err := newTestService(t, store).ImportPaymentInitiation(context.Background(), util.RandomOwner(), strings.NewReader(tc.file))
+			if tc.code != nil {
+				require.Equal(t, *tc.code, ErrorCode(err))
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, iso20022.StatusRejected, report.GroupStatus)
+			require.Equal(t, tc.reason, report.GroupReason.Code)
+			require.Empty(t, report.Payments)
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	db "go-backend/db/sqlc"
+	"time"
+)
+
+// paymentLinkCodeBytes is the number of random bytes of the codes of the payment links, which can't be
+// guessed by the users the link wasn't shared with.
+const paymentLinkCodeBytes = 16
+
+// The CreatePaymentLinkParams type is a payment link a merchant shares for any user to pay.
+// @property {string} Merchant - the user the link collects money for, who must own the to account.
+// @property {int64} ToAccountID - the account of the merchant the money is sent to.
+// @property {int64} Amount - the positive amount to pay.
+// @property {string} Currency - the currency of the amount, which the to account must hold.
+// @property {string} Memo - optional free text shown to the payer and kept on the transfer.
+// @property {time.Time} ExpiresAt - optional expiry of the link, at most the PAYMENT_LINK_TTL config
+// from now, which is its expiry when unset.
+type CreatePaymentLinkParams struct {
+	Merchant    string
+	ToAccountID int64
+	Amount      int64
+	Currency    string
+	Memo        string
+	ExpiresAt   time.Time
+}
+
+// The CreatePaymentLink function creates a payment link for an open account of the merchant, whose code
+// the merchant shares with the users who should pay it. The first payment made closes the link.
+func (service *Service) CreatePaymentLink(ctx context.Context, arg CreatePaymentLinkParams) (db.PaymentLink, error) {
+	if arg.Amount <= 0 {
+		return db.PaymentLink{}, errorf(CodeInvalidArgument, "amount must be positive, got %d", arg.Amount).withReason(ReasonInvalidAmount)
+	}
+
+	now := time.Now()
+	latest := now.Add(service.config.PaymentLinkTTL)
+	expiresAt := arg.ExpiresAt
+	if expiresAt.IsZero() {
+		expiresAt = latest
+	}
+	if !expiresAt.After(now) || expiresAt.After(latest) {
+		return db.PaymentLink{}, errorf(CodeInvalidArgument, "expiry must be in the next %s", service.config.PaymentLinkTTL)
+	}
+
+	account, err := service.ownedAccount(ctx, arg.Merchant, arg.ToAccountID)
+	if err != nil {
+		return db.PaymentLink{}, err
+	}
+	if account.ClosedAt.Valid {
+		return db.PaymentLink{}, errorf(CodeFailedPrecondition, "account [%d] is closed", account.ID).withReason(ReasonAccountClosed)
+	}
+	if account.Currency != arg.Currency {
+		return db.PaymentLink{}, errorf(CodeInvalidArgument, "account [%d] currency mismatch: %s vs %s", account.ID, account.Currency, arg.Currency).withReason(ReasonCurrencyMismatch)
+	}
+
+	code, err := newPaymentLinkCode()
+	if err != nil {
+		return db.PaymentLink{}, newError(CodeInternal, err)
+	}
+
+	link, err := service.store.CreatePaymentLink(ctx, db.CreatePaymentLinkParams{
+		Code:        code,
+		Merchant:    arg.Merchant,
+		ToAccountID: account.ID,
+		Amount:      arg.Amount,
+		Currency:    account.Currency,
+		Memo:        arg.Memo,
+		ExpiresAt:   expiresAt,
+	})
+	if err != nil {
+		return link, storeError(err)
+	}
+
+	return link, nil
+}
+
+// The GetPaymentLink function returns the payment link with the code, which any user it was shared with
+// can look up before paying it.
+func (service *Service) GetPaymentLink(ctx context.Context, code string) (db.PaymentLink, error) {
+	link, err := service.store.GetPaymentLinkByCode(ctx, code)
+	if err != nil {
+		return link, storeError(err)
+	}
+
+	return link, nil
+}
+
+// The PayPaymentLinkParams type is the payment of a link by a user.
+// @property {string} Payer - the user paying the link, who can't be its merchant.
+// @property {string} Code - the code of the link paid.
+// @property {int64} FromAccountID - the account of the payer the money is taken from.
+type PayPaymentLinkParams struct {
+	Payer         string
+	Code          string
+	FromAccountID int64
+}
+
+// The PayPaymentLink function pays an active link with a transfer from an account of the payer to the
+// account of its merchant, checked like any transfer. A payment flagged by the screening isn't made, the
+// payer having to send it as a transfer to be held for review instead.
+func (service *Service) PayPaymentLink(ctx context.Context, arg PayPaymentLinkParams) (db.PayPaymentLinkTxResult, error) {
+	link, err := service.activePaymentLink(ctx, arg.Code)
+	if err != nil {
+		return db.PayPaymentLinkTxResult{}, err
+	}
+	if link.Merchant == arg.Payer {
+		return db.PayPaymentLinkTxResult{}, newError(CodeInvalidArgument, errors.New("payment link can't be paid by its merchant"))
+	}
+
+	transfer := CreateTransferParams{
+		Owner:         arg.Payer,
+		FromAccountID: arg.FromAccountID,
+		ToAccountID:   link.ToAccountID,
+		Amount:        link.Amount,
+		Currency:      link.Currency,
+		Memo:          link.Memo,
+	}
+	err = service.checkDirectTransfer(ctx, transfer, fmt.Sprintf("payment of link [%d]", link.ID))
+	if err != nil {
+		return db.PayPaymentLinkTxResult{}, err
+	}
+
+	result, err := service.store.PayPaymentLinkTx(ctx, db.PayPaymentLinkTxParams{
+		ID:            link.ID,
+		Payer:         arg.Payer,
+		FromAccountID: arg.FromAccountID,
+		Currency:      link.Currency,
+	})
+	if err != nil {
+		return result, paymentLinkError(link.ID, err)
+	}
+
+	return result, nil
+}
+
+// The CancelPaymentLink function cancels an active link of the merchant, which can no longer be paid.
+func (service *Service) CancelPaymentLink(ctx context.Context, merchant string, code string) (db.PaymentLink, error) {
+	link, err := service.store.GetPaymentLinkByCode(ctx, code)
+	if err != nil {
+		return link, storeError(err)
+	}
+	if link.Merchant != merchant {
+		return link, newError(CodePermissionDenied, errors.New("payment link doesn't belong to authenticated user"))
+	}
+
+	cancelled, err := service.store.CancelPaymentLink(ctx, link.ID)
+	if err != nil {
+		// it was paid, cancelled or expired meanwhile
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return cancelled, paymentLinkError(link.ID, db.ErrPaymentLinkClosed)
+		}
+		return cancelled, storeError(err)
+	}
+
+	return cancelled, nil
+}
+
+// The activePaymentLink function returns the link with the code, provided it is still active and not
+// past its expiry.
+func (service *Service) activePaymentLink(ctx context.Context, code string) (db.PaymentLink, error) {
+	link, err := service.store.GetPaymentLinkByCode(ctx, code)
+	if err != nil {
+		return link, storeError(err)
+	}
+
+	if link.Status != db.PaymentLinkActive {
+		return link, paymentLinkError(link.ID, db.ErrPaymentLinkClosed)
+	}
+	if !link.ExpiresAt.After(time.Now()) {
+		return link, paymentLinkError(link.ID, db.ErrPaymentLinkExpired)
+	}
+
+	return link, nil
+}
+
+// The paymentLinkError function classifies the errors of the store on a payment link.
+func paymentLinkError(id int64, err error) error {
+	switch {
+	case errors.Is(err, db.ErrPaymentLinkClosed):
+		return errorf(CodeFailedPrecondition, "payment link [%d] is no longer active", id).withReason(ReasonPaymentLinkClosed)
+	case errors.Is(err, db.ErrPaymentLinkExpired):
+		return errorf(CodeFailedPrecondition, "payment link [%d] has expired", id).withReason(ReasonPaymentLinkExpired)
+	}
+	return storeError(err)
+}
+
+// newPaymentLinkCode returns a random code for a payment link, safe to use in a URL.
+func newPaymentLinkCode() (string, error) {
+	b := make([]byte, paymentLinkCodeBytes)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
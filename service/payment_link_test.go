@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/testutil/factory"
+	"go-backend/util"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreatePaymentLink(t *testing.T) {
+	account := factory.Account(factory.InCurrency(util.USD))
+
+	testCases := []struct {
+		name      string
+		arg       CreatePaymentLinkParams
+		buildStub func(store *mockdb.MockStore)
+		code      *Code
+		reason    string
+	}{
+		{
+			// the link expires after the PAYMENT_LINK_TTL config by default
+			name: "DefaultExpiry",
+			arg:  CreatePaymentLinkParams{Merchant: account.Owner, ToAccountID: account.ID, Amount: 10, Currency: util.USD},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().CreatePaymentLink(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreatePaymentLinkParams) (db.PaymentLink, error) {
+						require.WithinDuration(t, time.Now().Add(time.Hour), arg.ExpiresAt, time.Second)
+						require.Len(t, arg.Code, 22)
+						return db.PaymentLink{Code: arg.Code, Status: db.PaymentLinkActive}, nil
+					})
+			},
+		},
+		{
+			name: "ExpiryTooLate",
+			arg: CreatePaymentLinkParams{
+				Merchant:    account.Owner,
+				ToAccountID: account.ID,
+				Amount:      10,
+				Currency:    util.USD,
+				ExpiresAt:   time.Now().Add(2 * time.Hour),
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().CreatePaymentLink(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code: codePtr(CodeInvalidArgument),
+		},
+		{
+			name: "CurrencyMismatch",
+			arg:  CreatePaymentLinkParams{Merchant: account.Owner, ToAccountID: account.ID, Amount: 10, Currency: util.EUR},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().CreatePaymentLink(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code:   codePtr(CodeInvalidArgument),
+			reason: ReasonCurrencyMismatch,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			service := newTestService(t, store)
+			service.config.PaymentLinkTTL = time.Hour
+			_, err := service.CreatePaymentLink(context.Background(), tc.arg)
+			if tc.code != nil {
+				require.Equal(t, *tc.code, ErrorCode(err))
+				require.Equal(t, tc.reason, ErrorReason(err))
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestPayPaymentLink(t *testing.T) {
+	merchant := factory.Account(factory.InCurrency(util.USD))
+	link := db.PaymentLink{
+		ID:          1,
+		Code:        "code",
+		Merchant:    merchant.Owner,
+		ToAccountID: merchant.ID,
+		Amount:      10,
+		Currency:    util.USD,
+		Status:      db.PaymentLinkActive,
+		ExpiresAt:   time.Now().Add(time.Hour),
+	}
+	paid := link
+	paid.Status = db.PaymentLinkPaid
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetPaymentLinkByCode(gomock.Any(), gomock.Eq(link.Code)).Times(1).Return(link, nil)
+	store.EXPECT().GetPaymentLinkByCode(gomock.Any(), gomock.Eq("paid")).Times(1).Return(paid, nil)
+	store.EXPECT().PayPaymentLinkTx(gomock.Any(), gomock.Any()).Times(0)
+
+	service := newTestService(t, store)
+
+	// the merchant can't pay their own link
+	_, err := service.PayPaymentLink(context.Background(), PayPaymentLinkParams{
+		Payer:         merchant.Owner,
+		Code:          link.Code,
+		FromAccountID: merchant.ID,
+	})
+	require.Equal(t, CodeInvalidArgument, ErrorCode(err))
+
+	_, err = service.PayPaymentLink(context.Background(), PayPaymentLinkParams{
+		Payer:         util.RandomOwner(),
+		Code:          "paid",
+		FromAccountID: merchant.ID + 1,
+	})
+	require.Equal(t, CodeFailedPrecondition, ErrorCode(err))
+	require.Equal(t, ReasonPaymentLinkClosed, ErrorReason(err))
+}
@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	db "go-backend/db/sqlc"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// The CreatePaymentRequestParams type is money requested by a user from another user.
+// @property {string} Requester - the user asking for the money, who must own the to account.
+// @property {string} Payer - the user asked to pay.
+// @property {int64} ToAccountID - the account of the requester the money is sent to.
+// @property {int64} Amount - the positive amount requested, in the currency of the to account.
+// @property {string} Memo - optional free text shown to the payer and kept on the transfer.
+type CreatePaymentRequestParams struct {
+	Requester   string
+	Payer       string
+	ToAccountID int64
+	Amount      int64
+	Memo        string
+}
+
+// The CreatePaymentRequest function asks the payer for money, sent to an account of the requester once
+// the payer accepts. The request expires after the PAYMENT_REQUEST_TTL config.
+func (service *Service) CreatePaymentRequest(ctx context.Context, arg CreatePaymentRequestParams) (db.PaymentRequest, error) {
+	if arg.Amount <= 0 {
+		return db.PaymentRequest{}, errorf(CodeInvalidArgument, "amount must be positive, got %d", arg.Amount).withReason(ReasonInvalidAmount)
+	}
+	if arg.Payer == arg.Requester {
+		return db.PaymentRequest{}, newError(CodeInvalidArgument, errors.New("payment can't be requested from oneself"))
+	}
+
+	_, err := service.ownedAccount(ctx, arg.Requester, arg.ToAccountID)
+	if err != nil {
+		return db.PaymentRequest{}, err
+	}
+
+	_, err = service.store.GetUser(ctx, arg.Payer)
+	if err != nil {
+		return db.PaymentRequest{}, storeError(err)
+	}
+
+	request, err := service.store.CreatePaymentRequest(ctx, db.CreatePaymentRequestParams{
+		Requester:   arg.Requester,
+		Payer:       arg.Payer,
+		ToAccountID: arg.ToAccountID,
+		Amount:      arg.Amount,
+		Memo:        arg.Memo,
+		ExpiresAt:   time.Now().Add(service.config.PaymentRequestTTL),
+	})
+	if err != nil {
+		return request, storeError(err)
+	}
+
+	return request, nil
+}
+
+// The GetPaymentRequest function returns a payment request, provided the user is its requester or its
+// payer.
+func (service *Service) GetPaymentRequest(ctx context.Context, username string, id int64) (db.PaymentRequest, error) {
+	request, err := service.store.GetPaymentRequest(ctx, id)
+	if err != nil {
+		return request, storeError(err)
+	}
+
+	if request.Requester != username && request.Payer != username {
+		return request, newError(CodePermissionDenied, errors.New("payment request doesn't concern authenticated user"))
+	}
+
+	return request, nil
+}
+
+// Sides of the payment requests listed for a user.
+const (
+	PaymentRequestsSent     = "sent"
+	PaymentRequestsReceived = "received"
+)
+
+// The ListPaymentRequestsParams type holds the filters and page of a payment request listing.
+// @property {string} Username - the user whose requests are listed.
+// @property {string} Side - PaymentRequestsSent for the requests of the user, PaymentRequestsReceived
+// for the requests asking the user to pay.
+// @property {string} Status - only list the requests with this status when it is set.
+type ListPaymentRequestsParams struct {
+	Username string
+	Side     string
+	Status   string
+	Limit    int32
+	Offset   int32
+}
+
+// The ListPaymentRequests function lists the payment requests sent or received by the user, oldest
+// first.
+func (service *Service) ListPaymentRequests(ctx context.Context, arg ListPaymentRequestsParams) ([]db.PaymentRequest, error) {
+	switch arg.Status {
+	case "", db.PaymentRequestPending, db.PaymentRequestAccepted, db.PaymentRequestDeclined, db.PaymentRequestExpired:
+	default:
+		return nil, errorf(CodeInvalidArgument, "unknown payment request status %s", arg.Status)
+	}
+
+	params := db.ListPaymentRequestsParams{
+		Status:    pgtype.Text{String: arg.Status, Valid: arg.Status != ""},
+		RowLimit:  arg.Limit,
+		RowOffset: arg.Offset,
+	}
+	switch arg.Side {
+	case PaymentRequestsSent:
+		params.Requester = pgtype.Text{String: arg.Username, Valid: true}
+	case PaymentRequestsReceived:
+		params.Payer = pgtype.Text{String: arg.Username, Valid: true}
+	default:
+		return nil, errorf(CodeInvalidArgument, "unknown payment request side %s", arg.Side)
+	}
+
+	requests, err := service.store.ListPaymentRequests(ctx, params)
+	if err != nil {
+		return nil, storeError(err)
+	}
+
+	return requests, nil
+}
+
+// The AcceptPaymentRequestParams type is the acceptance of a payment request by its payer.
+// @property {string} Payer - the user accepting the request, who must be its payer.
+// @property {int64} ID - the request accepted.
+// @property {int64} FromAccountID - the account of the payer the money is taken from.
+type AcceptPaymentRequestParams struct {
+	Payer         string
+	ID            int64
+	FromAccountID int64
+}
+
+// The AcceptPaymentRequest function pays a pending request with a transfer from an account of the
+// payer, checked like any transfer. A payment flagged by the screening isn't made, the payer having to
+// send it as a transfer to be held for review instead.
+func (service *Service) AcceptPaymentRequest(ctx context.Context, arg AcceptPaymentRequestParams) (db.AcceptPaymentRequestTxResult, error) {
+	request, err := service.pendingPaymentRequest(ctx, arg.Payer, arg.ID)
+	if err != nil {
+		return db.AcceptPaymentRequestTxResult{}, err
+	}
+
+	toAccount, err := service.store.GetAccount(ctx, request.ToAccountID)
+	if err != nil {
+		return db.AcceptPaymentRequestTxResult{}, storeError(err)
+	}
+
+	transfer := CreateTransferParams{
+		Owner:         arg.Payer,
+		FromAccountID: arg.FromAccountID,
+		ToAccountID:   request.ToAccountID,
+		Amount:        request.Amount,
+		Currency:      toAccount.Currency,
+		Memo:          request.Memo,
+	}
+	err = service.checkDirectTransfer(ctx, transfer, fmt.Sprintf("payment of request [%d]", arg.ID))
+	if err != nil {
+		return db.AcceptPaymentRequestTxResult{}, err
+	}
+
+	result, err := service.store.AcceptPaymentRequestTx(ctx, db.AcceptPaymentRequestTxParams{
+		ID:            arg.ID,
+		FromAccountID: arg.FromAccountID,
+		Currency:      toAccount.Currency,
+	})
+	if err != nil {
+		return result, paymentRequestError(arg.ID, err)
+	}
+
+	return result, nil
+}
+
+// The DeclinePaymentRequest function declines a pending request, which can no longer be accepted.
+func (service *Service) DeclinePaymentRequest(ctx context.Context, payer string, id int64) (db.PaymentRequest, error) {
+	_, err := service.pendingPaymentRequest(ctx, payer, id)
+	if err != nil {
+		return db.PaymentRequest{}, err
+	}
+
+	request, err := service.store.DeclinePaymentRequest(ctx, id)
+	if err != nil {
+		// it was accepted or declined meanwhile
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return request, paymentRequestError(id, db.ErrPaymentRequestClosed)
+		}
+		return request, storeError(err)
+	}
+
+	return request, nil
+}
+
+// The pendingPaymentRequest function returns a request of the payer, provided it is still pending and
+// not past its expiry.
+func (service *Service) pendingPaymentRequest(ctx context.Context, payer string, id int64) (db.PaymentRequest, error) {
+	request, err := service.store.GetPaymentRequest(ctx, id)
+	if err != nil {
+		return request, storeError(err)
+	}
+
+	if request.Payer != payer {
+		return request, newError(CodePermissionDenied, errors.New("payment request isn't addressed to authenticated user"))
+	}
+	if request.Status != db.PaymentRequestPending {
+		return request, paymentRequestError(id, db.ErrPaymentRequestClosed)
+	}
+	if !request.ExpiresAt.After(time.Now()) {
+		return request, paymentRequestError(id, db.ErrPaymentRequestExpired)
+	}
+
+	return request, nil
+}
+
+// The paymentRequestError function classifies the errors of the store on a payment request.
+func paymentRequestError(id int64, err error) error {
+	switch {
+	case errors.Is(err, db.ErrPaymentRequestClosed):
+		return errorf(CodeFailedPrecondition, "payment request [%d] is no longer pending", id).withReason(ReasonPaymentRequestClosed)
+	case errors.Is(err, db.ErrPaymentRequestExpired):
+		return errorf(CodeFailedPrecondition, "payment request [%d] has expired", id).withReason(ReasonPaymentRequestExpired)
+	}
+	return storeError(err)
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/testutil/factory"
+	"go-backend/util"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreatePaymentRequestExpiry(t *testing.T) {
+	payer := factory.User()
+	account := factory.Account()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(payer.Username)).Times(1).Return(payer, nil)
+	store.EXPECT().CreatePaymentRequest(gomock.Any(), gomock.Any()).Times(1).
+		DoAndReturn(func(_ context.Context, arg db.CreatePaymentRequestParams) (db.PaymentRequest, error) {
+			require.WithinDuration(t, time.Now().Add(time.Hour), arg.ExpiresAt, time.Second)
+			return db.PaymentRequest{}, nil
+		})
+
+	service := newTestService(t, store)
+	service.config.PaymentRequestTTL = time.Hour
+	_, err := service.CreatePaymentRequest(context.Background(), CreatePaymentRequestParams{
+		Requester:   account.Owner,
+		Payer:       payer.Username,
+		ToAccountID: account.ID,
+		Amount:      10,
+	})
+	require.NoError(t, err)
+}
+
+func TestAcceptPaymentRequestScreened(t *testing.T) {
+	payer := factory.User()
+	fromAccount := factory.Account(factory.OwnedBy(payer.Username), factory.InCurrency(util.USD))
+	toAccount := factory.Account(factory.InCurrency(util.USD))
+	toAccount.ID = fromAccount.ID + 1
+	request := factory.PaymentRequest(factory.PaidInto(toAccount), factory.PaidBy(payer.Username), func(request *db.PaymentRequest) {
+		request.Amount = 500
+	})
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetPaymentRequest(gomock.Any(), gomock.Eq(request.ID)).Times(1).Return(request, nil)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(2).Return(toAccount, nil)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+	store.EXPECT().GetActiveBankParameter(gomock.Any(), gomock.Any()).Times(1).Return(db.BankParameter{}, db.ErrRecordNotFound)
+
+	// the payment isn't held for review in place of the request
+	store.EXPECT().HoldTransferTx(gomock.Any(), gomock.Any()).Times(0)
+	store.EXPECT().AcceptPaymentRequestTx(gomock.Any(), gomock.Any()).Times(0)
+
+	service := newTestService(t, store)
+	service.RegisterScreener(AmountScreener{Threshold: 100})
+	_, err := service.AcceptPaymentRequest(context.Background(), AcceptPaymentRequestParams{
+		Payer:         payer.Username,
+		ID:            request.ID,
+		FromAccountID: fromAccount.ID,
+	})
+	require.Error(t, err)
+	require.Equal(t, CodeFailedPrecondition, ErrorCode(err))
+	require.Equal(t, ReasonReviewRequired, ErrorReason(err))
+}
+
+func TestListPaymentRequestsSide(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().ListPaymentRequests(gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := newTestService(t, store).ListPaymentRequests(context.Background(), ListPaymentRequestsParams{
+		Username: util.RandomOwner(),
+		Side:     "both",
+		Limit:    5,
+	})
+	require.Equal(t, CodeInvalidArgument, ErrorCode(err))
+}
@@ -0,0 +1,239 @@
+package service
+
+import (
+	"context"
+	"errors"
+	db "go-backend/db/sqlc"
+	"go-backend/util"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// The needsApproval function reports whether the transfer is large enough to await approval before being
+// made, according to the TRANSFER_APPROVAL_THRESHOLD config.
+func (service *Service) needsApproval(arg CreateTransferParams) bool {
+	threshold := service.config.TransferApprovalThreshold
+	return threshold > 0 && arg.Amount >= threshold
+}
+
+// The pendTransfer function records a transfer awaiting approval, which expires after the
+// TRANSFER_APPROVAL_TTL config. The transfer awaits a second approver among the admins of the `approvers`
+// organization when it is set, which the audit log records. The outcome of the queued transfer it
+// processes is recorded along with it.
+func (service *Service) pendTransfer(ctx context.Context, arg CreateTransferParams, approvers pgtype.Int8) (db.PendingTransfer, error) {
+	pending, err := service.store.CreatePendingTransferTx(ctx, db.CreatePendingTransferTxParams{
+		PendingTransfer: db.CreatePendingTransferParams{
+			Owner:             arg.Owner,
+			FromAccountID:     arg.FromAccountID,
+			ToAccountID:       arg.ToAccountID,
+			Amount:            arg.Amount,
+			Memo:              arg.Memo,
+			ExternalReference: arg.ExternalReference,
+			ExpiresAt:         time.Now().Add(service.config.TransferApprovalTTL),
+			OrgID:             approvers,
+		},
+		Queued: arg.queued,
+	})
+	if err != nil {
+		return pending, storeError(err)
+	}
+
+	if approvers.Valid {
+		service.auditApproval(ctx, arg.Owner, ActionTransferApprovalRequested, pending, "")
+	}
+	return pending, nil
+}
+
+// The GetPendingTransfer function returns a pending transfer, provided it belongs to the owner.
+func (service *Service) GetPendingTransfer(ctx context.Context, owner string, id int64) (db.PendingTransfer, error) {
+	pending, err := service.store.GetPendingTransfer(ctx, id)
+	if err != nil {
+		return pending, storeError(err)
+	}
+
+	if pending.Owner != owner {
+		return pending, newError(CodePermissionDenied, errors.New("pending transfer doesn't belong to authenticated user"))
+	}
+
+	return pending, nil
+}
+
+// The ListPendingTransfersParams type holds the filters and page of a pending transfer listing.
+// @property {string} Owner - only list the transfers of this user when it is set.
+// @property {string} Status - only list the transfers with this status when it is set.
+type ListPendingTransfersParams struct {
+	Owner  string
+	Status string
+	Limit  int32
+	Offset int32
+}
+
+// The ListPendingTransfers function lists the pending transfers, oldest first.
+func (service *Service) ListPendingTransfers(ctx context.Context, arg ListPendingTransfersParams) ([]db.PendingTransfer, error) {
+	switch arg.Status {
+	case "", db.PendingTransferAwaitingApproval, db.PendingTransferApproved, db.PendingTransferRejected, db.PendingTransferExpired:
+	default:
+		return nil, errorf(CodeInvalidArgument, "unknown pending transfer status %s", arg.Status)
+	}
+
+	pending, err := service.store.ListPendingTransfers(ctx, db.ListPendingTransfersParams{
+		Owner:     pgtype.Text{String: arg.Owner, Valid: arg.Owner != ""},
+		Status:    pgtype.Text{String: arg.Status, Valid: arg.Status != ""},
+		RowLimit:  arg.Limit,
+		RowOffset: arg.Offset,
+	})
+	if err != nil {
+		return nil, storeError(err)
+	}
+
+	return pending, nil
+}
+
+// The ConfirmPendingTransfer function approves a transfer of the owner, who confirms it with their
+// password: a stolen access token alone can't move a large amount. The transfers awaiting a second
+// approver of an organization can't be confirmed by their owner.
+func (service *Service) ConfirmPendingTransfer(ctx context.Context, owner string, id int64, password string) (db.ApprovePendingTransferTxResult, error) {
+	pending, err := service.awaitingPendingTransfer(ctx, id)
+	if err != nil {
+		return db.ApprovePendingTransferTxResult{}, err
+	}
+	if pending.Owner != owner {
+		return db.ApprovePendingTransferTxResult{}, newError(CodePermissionDenied, errors.New("pending transfer doesn't belong to authenticated user"))
+	}
+	if pending.OrgID.Valid {
+		return db.ApprovePendingTransferTxResult{}, errorf(CodePermissionDenied, "pending transfer [%d] awaits the approval of another admin of the organization", id)
+	}
+
+	user, err := service.store.GetUser(ctx, owner)
+	if err != nil {
+		return db.ApprovePendingTransferTxResult{}, storeError(err)
+	}
+
+	err = util.Checkpassword(password, user.HashedPassword)
+	if err != nil {
+		return db.ApprovePendingTransferTxResult{}, newError(CodeUnauthenticated, err).withReason(ReasonInvalidCredentials)
+	}
+
+	return service.approvePendingTransfer(ctx, pending, owner)
+}
+
+// The ApprovePendingTransfer function approves a transfer on behalf of its owner, for a banker who
+// checked it with them.
+func (service *Service) ApprovePendingTransfer(ctx context.Context, banker string, id int64) (db.ApprovePendingTransferTxResult, error) {
+	pending, err := service.awaitingPendingTransfer(ctx, id)
+	if err != nil {
+		return db.ApprovePendingTransferTxResult{}, err
+	}
+
+	return service.approvePendingTransfer(ctx, pending, banker)
+}
+
+// The approvePendingTransfer function makes a pending transfer, checked again like a new transfer as the
+// accounts or the transfer limit may have changed since it was requested.
+func (service *Service) approvePendingTransfer(ctx context.Context, pending db.PendingTransfer, decidedBy string) (db.ApprovePendingTransferTxResult, error) {
+	fromAccount, err := service.store.GetAccount(ctx, pending.FromAccountID)
+	if err != nil {
+		return db.ApprovePendingTransferTxResult{}, storeError(err)
+	}
+
+	_, err = service.checkTransferAccounts(ctx, CreateTransferParams{
+		Owner:         pending.Owner,
+		FromAccountID: pending.FromAccountID,
+		ToAccountID:   pending.ToAccountID,
+		Amount:        pending.Amount,
+		Currency:      fromAccount.Currency,
+	})
+	if err != nil {
+		return db.ApprovePendingTransferTxResult{}, err
+	}
+
+	limit, ok, err := service.activeParameter(ctx, db.ParameterTransferLimit)
+	if err != nil {
+		return db.ApprovePendingTransferTxResult{}, err
+	}
+	if ok && pending.Amount > limit {
+		return db.ApprovePendingTransferTxResult{}, transferLimitError(pending.Amount, limit)
+	}
+
+	result, err := service.store.ApprovePendingTransferTx(ctx, db.ApprovePendingTransferTxParams{
+		ID:        pending.ID,
+		DecidedBy: decidedBy,
+		Currency:  fromAccount.Currency,
+		Now:       service.clock.Now(),
+	})
+	if err != nil {
+		return result, pendingTransferError(pending.ID, err)
+	}
+
+	return result, nil
+}
+
+// The CancelPendingTransfer function rejects a transfer of the owner awaiting approval, which is then
+// never made.
+func (service *Service) CancelPendingTransfer(ctx context.Context, owner string, id int64) (db.PendingTransfer, error) {
+	pending, err := service.awaitingPendingTransfer(ctx, id)
+	if err != nil {
+		return pending, err
+	}
+	if pending.Owner != owner {
+		return pending, newError(CodePermissionDenied, errors.New("pending transfer doesn't belong to authenticated user"))
+	}
+
+	return service.rejectPendingTransfer(ctx, id, owner)
+}
+
+// The RejectPendingTransfer function rejects a transfer awaiting approval on behalf of a banker.
+func (service *Service) RejectPendingTransfer(ctx context.Context, banker string, id int64) (db.PendingTransfer, error) {
+	_, err := service.awaitingPendingTransfer(ctx, id)
+	if err != nil {
+		return db.PendingTransfer{}, err
+	}
+
+	return service.rejectPendingTransfer(ctx, id, banker)
+}
+
+func (service *Service) rejectPendingTransfer(ctx context.Context, id int64, decidedBy string) (db.PendingTransfer, error) {
+	pending, err := service.store.RejectPendingTransfer(ctx, db.RejectPendingTransferParams{
+		DecidedBy: decidedBy,
+		ID:        id,
+	})
+	if err != nil {
+		// it was approved or rejected meanwhile
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return pending, pendingTransferError(id, db.ErrPendingTransferClosed)
+		}
+		return pending, storeError(err)
+	}
+
+	return pending, nil
+}
+
+// The awaitingPendingTransfer function returns a pending transfer, provided it still awaits approval and
+// isn't past its expiry.
+func (service *Service) awaitingPendingTransfer(ctx context.Context, id int64) (db.PendingTransfer, error) {
+	pending, err := service.store.GetPendingTransfer(ctx, id)
+	if err != nil {
+		return pending, storeError(err)
+	}
+
+	if pending.Status != db.PendingTransferAwaitingApproval {
+		return pending, pendingTransferError(id, db.ErrPendingTransferClosed)
+	}
+	if !pending.ExpiresAt.After(time.Now()) {
+		return pending, pendingTransferError(id, db.ErrPendingTransferExpired)
+	}
+
+	return pending, nil
+}
+
+// The pendingTransferError function classifies the errors of the store on a pending transfer.
+func pendingTransferError(id int64, err error) error {
+	switch {
+	case errors.Is(err, db.ErrPendingTransferClosed):
+		return errorf(CodeFailedPrecondition, "pending transfer [%d] no longer awaits approval", id).withReason(ReasonPendingTransferClosed)
+	case errors.Is(err, db.ErrPendingTransferExpired):
+		return errorf(CodeFailedPrecondition, "pending transfer [%d] has expired", id).withReason(ReasonPendingTransferExpired)
+	}
+	return storeError(err)
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"go-backend/clock"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/testutil/factory"
+	"go-backend/util"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateTransferAwaitingApproval(t *testing.T) {
+	owner := util.RandomOwner()
+	fromAccount := factory.Account(factory.OwnedBy(owner), factory.InCurrency(util.USD))
+	toAccount := factory.Account(factory.InCurrency(util.USD))
+	toAccount.ID = fromAccount.ID + 1
+	now := time.Now()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(2).Return(fromAccount, nil)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(2).Return(toAccount, nil)
+	store.EXPECT().GetActiveBankParameter(gomock.Any(), gomock.Any()).Times(2).Return(db.BankParameter{}, db.ErrRecordNotFound)
+
+	// the transfer at the threshold isn't made but awaits approval
+	store.EXPECT().CreatePendingTransferTx(gomock.Any(), gomock.Any()).Times(1).
+		DoAndReturn(func(_ context.Context, arg db.CreatePendingTransferTxParams) (db.PendingTransfer, error) {
+			require.Equal(t, owner, arg.PendingTransfer.Owner)
+			require.Equal(t, int64(100), arg.PendingTransfer.Amount)
+			require.WithinDuration(t, time.Now().Add(time.Hour), arg.PendingTransfer.ExpiresAt, time.Second)
+			require.Nil(t, arg.Queued)
+			return db.PendingTransfer{ID: 1, Status: db.PendingTransferAwaitingApproval}, nil
+		})
+	store.EXPECT().TransferTx(gomock.Any(), gomock.Eq(db.TransferTxParams{
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   toAccount.ID,
+		Amount:        99,
+		Currency:      fromAccount.Currency,
+		ChargeFee:     true,
+		Now:           now,
+	})).Times(1).Return(db.TransferTxResult{}, nil)
+
+	service := newTestService(t, store)
+	service.SetClock(clock.NewFake(now))
+	service.config.TransferApprovalThreshold = 100
+	service.config.TransferApprovalTTL = time.Hour
+
+	arg := CreateTransferParams{
+		Owner:         owner,
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   toAccount.ID,
+		Amount:        100,
+		Currency:      util.USD,
+	}
+	result, err := service.CreateTransfer(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotNil(t, result.Pending)
+	require.Equal(t, int64(1), result.Pending.ID)
+
+	arg.Amount = 99
+	result, err = service.CreateTransfer(context.Background(), arg)
+	require.NoError(t, err)
+	require.Nil(t, result.Pending)
+}
+
+func TestApprovePendingTransferLimit(t *testing.T) {
+	fromAccount := factory.Account(factory.InCurrency(util.USD))
+	toAccount := factory.Account(factory.InCurrency(util.USD))
+	toAccount.ID = fromAccount.ID + 1
+	pending := factory.PendingTransfer(factory.PendingBetween(fromAccount, toAccount))
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// the transfer limit lowered since the transfer was requested applies to its approval
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetPendingTransfer(gomock.Any(), gomock.Eq(pending.ID)).Times(1).Return(pending, nil)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(2).Return(fromAccount, nil)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+	store.EXPECT().GetActiveBankParameter(gomock.Any(), gomock.Any()).Times(1).
+		Return(db.BankParameter{Name: db.ParameterTransferLimit, Value: pending.Amount - 1}, nil)
+	store.EXPECT().ApprovePendingTransferTx(gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := newTestService(t, store).ApprovePendingTransfer(context.Background(), "banker", pending.ID)
+	require.Error(t, err)
+	require.Equal(t, ReasonTransferLimitExceeded, ErrorReason(err))
+}
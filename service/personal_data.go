@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"errors"
+	db "go-backend/db/sqlc"
+	"go-backend/util"
+	"go-backend/worker"
+	"log"
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+// The ExportPersonalData function creates a job exporting all the personal data kept about the user, as a
+// JSON file of their profile, accounts with their entries, beneficiaries and preferences.
+func (service *Service) ExportPersonalData(ctx context.Context, username string) (db.Job, error) {
+	return service.CreateJob(ctx, CreateJobParams{
+		Username: username,
+		Kind:     worker.ExportKindGDPR,
+	})
+}
+
+// The RequestUserDeletionParams type is the request of a user for the deletion of their data.
+// @property {string} Password - the password of the user, asked again for the request not to be made
+// with a stolen access token.
+type RequestUserDeletionParams struct {
+	Username string
+	Password string
+}
+
+// The RequestUserDeletion function schedules the deletion of the data of the user at the end of the
+// USER_DELETION_COOLING_OFF config, during which they can cancel it, and enqueues the task deleting it.
+// The accounts of the user must not hold money, as they are closed by the deletion.
+func (service *Service) RequestUserDeletion(ctx context.Context, arg RequestUserDeletionParams) (db.UserDeletion, error) {
+	user, err := service.store.GetUser(ctx, arg.Username)
+	if err != nil {
+		return db.UserDeletion{}, storeError(err)
+	}
+
+	err = util.Checkpassword(arg.Password, user.HashedPassword)
+	if err != nil {
+		return db.UserDeletion{}, newError(CodeUnauthenticated, err).withReason(ReasonInvalidCredentials)
+	}
+
+	hasBalance, err := service.store.OwnerHasBalance(ctx, arg.Username)
+	if err != nil {
+		return db.UserDeletion{}, storeError(err)
+	}
+	if hasBalance {
+		return db.UserDeletion{}, errorf(CodeFailedPrecondition, "an account of user %s still holds money, withdraw it before deleting the data", arg.Username).withReason(ReasonAccountNotEmpty)
+	}
+
+	deletion, err := service.store.ScheduleUserDeletion(ctx, db.ScheduleUserDeletionParams{
+		Username:     arg.Username,
+		ScheduledFor: time.Now().Add(service.config.UserDeletionCoolingOff),
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return deletion, errorf(CodeAlreadyExists, "the deletion of the data of user %s is already scheduled", arg.Username).withReason(ReasonDeletionScheduled)
+		}
+		return deletion, storeError(err)
+	}
+
+	opts := []asynq.Option{
+		asynq.MaxRetry(10),
+		asynq.ProcessAt(deletion.ScheduledFor),
+		asynq.Queue(worker.QueueDefault),
+	}
+	err = service.taskDistributor.DistributeTaskDeleteUserData(ctx, &worker.PayloadDeleteUserData{
+		Username:     deletion.Username,
+		ScheduledFor: deletion.ScheduledFor.Unix(),
+	}, opts...)
+	if err != nil {
+		// the deletion is cancelled for the user to ask again, no task deleting the data being enqueued
+		if _, cancelErr := service.store.CancelUserDeletion(ctx, deletion.Username); cancelErr != nil {
+			log.Printf("cannot cancel the deletion of user %s: %v", deletion.Username, cancelErr)
+		}
+		return deletion, newError(CodeInternal, err)
+	}
+
+	return deletion, nil
+}
+
+// The GetUserDeletion function returns the latest deletion of the data of the user.
+func (service *Service) GetUserDeletion(ctx context.Context, username string) (db.UserDeletion, error) {
+	deletion, err := service.store.GetUserDeletion(ctx, username)
+	if err != nil {
+		return deletion, storeError(err)
+	}
+	return deletion, nil
+}
+
+// The CancelUserDeletion function cancels the deletion of the data of the user during its cooling-off
+// period. A deletion that isn't scheduled anymore, e.g. completed, can't be cancelled.
+func (service *Service) CancelUserDeletion(ctx context.Context, username string) (db.UserDeletion, error) {
+	deletion, err := service.store.CancelUserDeletion(ctx, username)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return deletion, errorf(CodeFailedPrecondition, "no deletion of the data of user %s is scheduled", username).withReason(ReasonDeletionNotScheduled)
+		}
+		return deletion, storeError(err)
+	}
+	return deletion, nil
+}
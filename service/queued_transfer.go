@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"errors"
+	db "go-backend/db/sqlc"
+	"go-backend/worker"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+)
+
+// queuedTransferNamespace derives the tracking ID of a transfer queued with an idempotency key from the
+// key, so that the same request queued twice gets the same ID.
+var queuedTransferNamespace = uuid.MustParse("3bff3023-2144-4ca4-9b50-f4e137b2dd77")
+
+// The queuesTransfers function reports whether the transfers failing as the database can't be reached
+// are queued, according to the TRANSFER_QUEUE_ENABLED config.
+func (service *Service) queuesTransfers() bool {
+	return service.config.TransferQueueEnabled && service.taskDistributor != nil
+}
+
+// The queueTransfer function queues a transfer to be made once the database is back. Nothing is checked
+// before, the transfer failing when it is made if it is invalid. Only a transfer with an idempotency key
+// is queued, its tracking ID being derived from the key: without one, a client retrying a transfer whose
+// response was lost would queue it twice, and the money would move twice.
+func (service *Service) queueTransfer(ctx context.Context, arg CreateTransferParams) (CreateTransferResult, error) {
+	if arg.IdempotencyKey == "" {
+		return CreateTransferResult{}, errorf(CodeUnavailable, "database is unavailable, a transfer can only be queued with an idempotency key").withReason(ReasonIdempotencyKeyRequired)
+	}
+	id := uuid.NewSHA1(queuedTransferNamespace, []byte(arg.Owner+":"+arg.IdempotencyKey))
+
+	payload := &worker.PayloadQueuedTransfer{
+		ID:                id,
+		Owner:             arg.Owner,
+		FromAccountID:     arg.FromAccountID,
+		ToAccountID:       arg.ToAccountID,
+		BeneficiaryID:     arg.BeneficiaryID,
+		Amount:            arg.Amount,
+		Currency:          arg.Currency,
+		Memo:              arg.Memo,
+		ExternalReference: arg.ExternalReference,
+		QueuedAt:          time.Now(),
+	}
+	opts := []asynq.Option{
+		asynq.MaxRetry(worker.QueuedTransferMaxRetry),
+		asynq.Queue(worker.QueueCritical),
+	}
+	err := service.taskDistributor.DistributeTaskQueuedTransfer(ctx, payload, opts...)
+	if err != nil {
+		return CreateTransferResult{}, newError(CodeUnavailable, err)
+	}
+
+	return CreateTransferResult{Queued: newQueuedTransfer(payload, db.QueuedTransferQueued)}, nil
+}
+
+// The ProcessQueuedTransfer function makes a queued transfer, checked like any transfer, and records its
+// outcome, which notifies its owner. A transfer already processed is skipped: its outcome is recorded
+// in the same transaction as the money it moves, so it is made once however many times it is processed.
+// An error is returned while the database is unavailable, for the transfer to be processed again.
+func (service *Service) ProcessQueuedTransfer(ctx context.Context, payload *worker.PayloadQueuedTransfer) error {
+	_, err := service.store.GetQueuedTransfer(ctx, payload.ID)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, db.ErrRecordNotFound) {
+		return storeError(err)
+	}
+
+	queued := db.CreateQueuedTransferParams{
+		ID:       payload.ID,
+		Owner:    payload.Owner,
+		QueuedAt: payload.QueuedAt,
+	}
+	_, err = service.createTransfer(ctx, CreateTransferParams{
+		Owner:             payload.Owner,
+		FromAccountID:     payload.FromAccountID,
+		ToAccountID:       payload.ToAccountID,
+		BeneficiaryID:     payload.BeneficiaryID,
+		Amount:            payload.Amount,
+		Currency:          payload.Currency,
+		Memo:              payload.Memo,
+		ExternalReference: payload.ExternalReference,
+		queued:            &queued,
+	})
+	switch {
+	case errors.Is(err, db.ErrQueuedTransferProcessed):
+		return nil
+	case ErrorCode(err) == CodeUnavailable, ErrorCode(err) == CodeInternal:
+		return err
+	case err != nil:
+		queued.Status = db.QueuedTransferFailed
+		queued.Error = err.Error()
+	default:
+		// the outcome was recorded along with the transfer, its hold or the transfer awaiting approval
+		return nil
+	}
+
+	_, err = service.store.CreateQueuedTransfer(ctx, queued)
+	if err != nil && !errors.Is(err, db.ErrQueuedTransferProcessed) {
+		return storeError(err)
+	}
+	return nil
+}
+
+// The GetQueuedTransfer function returns a queued transfer of the owner, with its outcome once it was
+// processed. It is still queued until then, or failed when it couldn't be processed in time.
+func (service *Service) GetQueuedTransfer(ctx context.Context, owner string, id uuid.UUID) (db.QueuedTransfer, error) {
+	queued, err := service.store.GetQueuedTransfer(ctx, id)
+	if err == nil {
+		if queued.Owner != owner {
+			return queued, newError(CodePermissionDenied, errors.New("queued transfer doesn't belong to authenticated user"))
+		}
+		return queued, nil
+	}
+
+	// the transfer isn't processed yet, or the database is still unavailable
+	storeErr := storeError(err)
+	code := ErrorCode(storeErr)
+	if (code != CodeNotFound && code != CodeUnavailable) || service.taskDistributor == nil {
+		return queued, storeErr
+	}
+
+	task, err := service.taskDistributor.QueuedTransfer(ctx, id)
+	if err != nil {
+		if errors.Is(err, worker.ErrTaskNotFound) {
+			return queued, storeErr
+		}
+		return queued, newError(CodeInternal, err)
+	}
+	if task.Payload.Owner != owner {
+		return queued, newError(CodePermissionDenied, errors.New("queued transfer doesn't belong to authenticated user"))
+	}
+
+	if task.Archived {
+		queued = *newQueuedTransfer(&task.Payload, db.QueuedTransferFailed)
+		queued.Error = "transfer couldn't be processed before the queue gave up on it"
+		return queued, nil
+	}
+	return *newQueuedTransfer(&task.Payload, db.QueuedTransferQueued), nil
+}
+
+func newQueuedTransfer(payload *worker.PayloadQueuedTransfer, status string) *db.QueuedTransfer {
+	return &db.QueuedTransfer{
+		ID:       payload.ID,
+		Owner:    payload.Owner,
+		Status:   status,
+		QueuedAt: payload.QueuedAt,
+	}
+}
@@ -0,0 +1,285 @@
+package service
+
+import (
+	"context"
+	"errors"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/testutil/factory"
+	"go-backend/util"
+	"go-backend/worker"
+	mockwk "go-backend/worker/mock"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// errDatabaseDown is the error of a query made while the database can't be reached.
+var errDatabaseDown = &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+
+func newQueueingTestService(t *testing.T, store db.Store, distributor worker.TaskDistributor) *Service {
+	service := newTestService(t, store)
+	service.taskDistributor = distributor
+	service.config.TransferQueueEnabled = true
+	return service
+}
+
+func TestCreateTransferQueued(t *testing.T) {
+	arg := CreateTransferParams{
+		Owner:          util.RandomOwner(),
+		FromAccountID:  1,
+		ToAccountID:    2,
+		Amount:         10,
+		Currency:       util.USD,
+		IdempotencyKey: "key",
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(2).Return(db.Account{}, errDatabaseDown)
+	store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+
+	// the same request is queued under the same tracking ID
+	var ids []uuid.UUID
+	distributor := mockwk.NewMockTaskDistributor(ctrl)
+	distributor.EXPECT().DistributeTaskQueuedTransfer(gomock.Any(), gomock.Any(), gomock.Any()).Times(2).
+		DoAndReturn(func(_ context.Context, payload *worker.PayloadQueuedTransfer, _ ...interface{}) error {
+			require.Equal(t, arg.Owner, payload.Owner)
+			require.Equal(t, arg.Amount, payload.Amount)
+			ids = append(ids, payload.ID)
+			return nil
+		})
+
+	service := newQueueingTestService(t, store, distributor)
+	for i := 0; i < 2; i++ {
+		result, err := service.CreateTransfer(context.Background(), arg)
+		require.NoError(t, err)
+		require.NotNil(t, result.Queued)
+		require.Equal(t, db.QueuedTransferQueued, result.Queued.Status)
+		require.Equal(t, ids[i], result.Queued.ID)
+	}
+	require.Equal(t, ids[0], ids[1])
+}
+
+func TestCreateTransferNotQueued(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// the transfers aren't queued unless the bank enabled it
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(1).Return(db.Account{}, errDatabaseDown)
+	distributor := mockwk.NewMockTaskDistributor(ctrl)
+	distributor.EXPECT().DistributeTaskQueuedTransfer(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	service := newQueueingTestService(t, store, distributor)
+	service.config.TransferQueueEnabled = false
+	_, err := service.CreateTransfer(context.Background(), CreateTransferParams{
+		Owner:         util.RandomOwner(),
+		FromAccountID: 1,
+		ToAccountID:   2,
+		Amount:        10,
+		Currency:      util.USD,
+	})
+	require.Error(t, err)
+	require.Equal(t, CodeUnavailable, ErrorCode(err))
+
+	// nor without an idempotency key, which a retry could not be told apart without
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(1).Return(db.Account{}, errDatabaseDown)
+	service.config.TransferQueueEnabled = true
+	_, err = service.CreateTransfer(context.Background(), CreateTransferParams{
+		Owner:         util.RandomOwner(),
+		FromAccountID: 1,
+		ToAccountID:   2,
+		Amount:        10,
+		Currency:      util.USD,
+	})
+	require.Equal(t, CodeUnavailable, ErrorCode(err))
+	require.Equal(t, ReasonIdempotencyKeyRequired, ErrorReason(err))
+}
+
+func TestProcessQueuedTransfer(t *testing.T) {
+	owner := util.RandomOwner()
+	fromAccount := factory.Account(factory.OwnedBy(owner), factory.InCurrency(util.USD))
+	toAccount := factory.Account(factory.InCurrency(util.USD))
+	toAccount.ID = fromAccount.ID + 1
+	payload := &worker.PayloadQueuedTransfer{
+		ID:            uuid.New(),
+		Owner:         owner,
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   toAccount.ID,
+		Amount:        10,
+		Currency:      util.USD,
+		QueuedAt:      time.Now(),
+	}
+
+	testCases := []struct {
+		name      string
+		buildStub func(store *mockdb.MockStore)
+		check     func(err error)
+	}{
+		{
+			name: "Succeeded",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetQueuedTransfer(gomock.Any(), gomock.Eq(payload.ID)).Times(1).Return(db.QueuedTransfer{}, db.ErrRecordNotFound)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().GetActiveBankParameter(gomock.Any(), gomock.Any()).Times(1).Return(db.BankParameter{}, db.ErrRecordNotFound)
+
+				// the outcome is recorded along with the transfer
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ context.Context, arg db.TransferTxParams) (db.TransferTxResult, error) {
+						require.NotNil(t, arg.Queued)
+						require.Equal(t, payload.ID, arg.Queued.ID)
+						require.Equal(t, owner, arg.Queued.Owner)
+						return db.TransferTxResult{}, nil
+					})
+				store.EXPECT().CreateQueuedTransfer(gomock.Any(), gomock.Any()).Times(0)
+			},
+			check: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "AlreadyProcessed",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetQueuedTransfer(gomock.Any(), gomock.Eq(payload.ID)).Times(1).Return(db.QueuedTransfer{ID: payload.ID}, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			check: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "ProcessedMeanwhile",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetQueuedTransfer(gomock.Any(), gomock.Eq(payload.ID)).Times(1).Return(db.QueuedTransfer{}, db.ErrRecordNotFound)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().GetActiveBankParameter(gomock.Any(), gomock.Any()).Times(1).Return(db.BankParameter{}, db.ErrRecordNotFound)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(1).Return(db.TransferTxResult{}, db.ErrQueuedTransferProcessed)
+				store.EXPECT().CreateQueuedTransfer(gomock.Any(), gomock.Any()).Times(0)
+			},
+			check: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "Failed",
+			buildStub: func(store *mockdb.MockStore) {
+				cadAccount := toAccount
+				cadAccount.Currency = util.CAD
+				store.EXPECT().GetQueuedTransfer(gomock.Any(), gomock.Eq(payload.ID)).Times(1).Return(db.QueuedTransfer{}, db.ErrRecordNotFound)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(cadAccount, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+
+				// a rejected transfer isn't retried but recorded as failed
+				store.EXPECT().CreateQueuedTransfer(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateQueuedTransferParams) (db.QueuedTransfer, error) {
+						require.Equal(t, payload.ID, arg.ID)
+						require.Equal(t, db.QueuedTransferFailed, arg.Status)
+						require.NotEmpty(t, arg.Error)
+						return db.QueuedTransfer{}, nil
+					})
+			},
+			check: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "DatabaseStillDown",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetQueuedTransfer(gomock.Any(), gomock.Eq(payload.ID)).Times(1).Return(db.QueuedTransfer{}, errDatabaseDown)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			check: func(err error) {
+				require.Error(t, err)
+				require.Equal(t, CodeUnavailable, ErrorCode(err))
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			tc.check(newTestService(t, store).ProcessQueuedTransfer(context.Background(), payload))
+		})
+	}
+}
+
+func TestProcessQueuedTransferPendingApproval(t *testing.T) {
+	owner := util.RandomOwner()
+	fromAccount := factory.Account(factory.OwnedBy(owner), factory.InCurrency(util.USD))
+	toAccount := factory.Account(factory.InCurrency(util.USD))
+	toAccount.ID = fromAccount.ID + 1
+	payload := &worker.PayloadQueuedTransfer{
+		ID:            uuid.New(),
+		Owner:         owner,
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   toAccount.ID,
+		Amount:        100,
+		Currency:      util.USD,
+		QueuedAt:      time.Now(),
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetQueuedTransfer(gomock.Any(), gomock.Eq(payload.ID)).Times(1).Return(db.QueuedTransfer{}, db.ErrRecordNotFound)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+	store.EXPECT().GetActiveBankParameter(gomock.Any(), gomock.Any()).Times(1).Return(db.BankParameter{}, db.ErrRecordNotFound)
+
+	// the outcome is recorded along with the transfer awaiting approval
+	store.EXPECT().CreatePendingTransferTx(gomock.Any(), gomock.Any()).Times(1).
+		DoAndReturn(func(_ context.Context, arg db.CreatePendingTransferTxParams) (db.PendingTransfer, error) {
+			require.NotNil(t, arg.Queued)
+			require.Equal(t, payload.ID, arg.Queued.ID)
+			require.Equal(t, owner, arg.Queued.Owner)
+			return db.PendingTransfer{ID: 1, Status: db.PendingTransferAwaitingApproval}, nil
+		})
+	store.EXPECT().CreateQueuedTransfer(gomock.Any(), gomock.Any()).Times(0)
+
+	service := newTestService(t, store)
+	service.config.TransferApprovalThreshold = 100
+	service.config.TransferApprovalTTL = time.Hour
+
+	require.NoError(t, service.ProcessQueuedTransfer(context.Background(), payload))
+}
+
+func TestGetQueuedTransferStillQueued(t *testing.T) {
+	owner := util.RandomOwner()
+	id := uuid.New()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetQueuedTransfer(gomock.Any(), gomock.Eq(id)).Times(2).Return(db.QueuedTransfer{}, errDatabaseDown)
+	distributor := mockwk.NewMockTaskDistributor(ctrl)
+	distributor.EXPECT().QueuedTransfer(gomock.Any(), gomock.Eq(id)).Times(2).
+		Return(worker.QueuedTransferTask{Payload: worker.PayloadQueuedTransfer{ID: id, Owner: owner}}, nil)
+
+	service := newQueueingTestService(t, store, distributor)
+	queued, err := service.GetQueuedTransfer(context.Background(), owner, id)
+	require.NoError(t, err)
+	require.Equal(t, db.QueuedTransferQueued, queued.Status)
+
+	_, err = service.GetQueuedTransfer(context.Background(), util.RandomOwner(), id)
+	require.Error(t, err)
+	require.Equal(t, CodePermissionDenied, ErrorCode(err))
+}
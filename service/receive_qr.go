@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"go-backend/emvqr"
+	"go-backend/util"
+	"strconv"
+	"strings"
+)
+
+// The ReceiveQR type is the payload of a QR code paying an account, which mobile apps scan to pay it.
+// @property {string} Payload - the EMVCo payload the QR code encodes.
+// @property {int64} Amount - the amount to pay, 0 when the payer enters it.
+type ReceiveQR struct {
+	Payload       string
+	AccountNumber string
+	Currency      string
+	Amount        int64
+}
+
+// The ReceiveQR function returns the payload of a QR code paying an open account the user can see, in
+// the currency of the account. The amount is optional, the payer entering it when it is 0. The name of
+// the owner of the account is shown to the payer.
+func (service *Service) ReceiveQR(ctx context.Context, username string, accountID int64, amount int64) (ReceiveQR, error) {
+	if amount < 0 {
+		return ReceiveQR{}, errorf(CodeInvalidArgument, "amount can't be negative, got %d", amount).withReason(ReasonInvalidAmount)
+	}
+
+	account, err := service.GetAccount(ctx, username, accountID)
+	if err != nil {
+		return ReceiveQR{}, err
+	}
+	if account.ClosedAt.Valid {
+		return ReceiveQR{}, errorf(CodeFailedPrecondition, "account [%d] is closed", account.ID).withReason(ReasonAccountClosed)
+	}
+
+	owner, err := service.store.GetUser(ctx, account.Owner)
+	if err != nil {
+		return ReceiveQR{}, storeError(err)
+	}
+
+	payload := emvqr.Payload{
+		AccountNumber: account.AccountNumber,
+		Currency:      account.Currency,
+		MerchantName:  owner.FullName,
+	}
+	if amount > 0 {
+		payload.Amount = decimalAmount(amount, account.Currency)
+	}
+	encoded, err := emvqr.Encode(payload)
+	if err != nil {
+		return ReceiveQR{}, errorf(CodeInvalidArgument, "cannot encode QR code of account [%d]: %v", account.ID, err)
+	}
+
+	return ReceiveQR{
+		Payload:       encoded,
+		AccountNumber: account.AccountNumber,
+		Currency:      account.Currency,
+		Amount:        amount,
+	}, nil
+}
+
+// decimalAmount writes an amount of the currency as a decimal number, e.g. "12.00". The amounts of the
+// bank being whole units of their currency, their decimals are zeros.
+func decimalAmount(amount int64, currency string) string {
+	decimal := strconv.FormatInt(amount, 10)
+	if exponent := util.CurrencyExponent(currency); exponent > 0 {
+		decimal += "." + strings.Repeat("0", exponent)
+	}
+	return decimal
+}
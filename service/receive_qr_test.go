@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/testutil/factory"
+	"go-backend/util"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReceiveQR(t *testing.T) {
+	owner := factory.User()
+	account := factory.Account(factory.OwnedBy(owner.Username), factory.InCurrency(util.EUR))
+	account.AccountNumber = util.AccountNumber(account.ID)
+	closed := factory.Account(factory.OwnedBy(owner.Username), func(account *db.Account) {
+		account.ClosedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+	})
+
+	testCases := []struct {
+		name      string
+		accountID int64
+		amount    int64
+		buildStub func(store *mockdb.MockStore)
+		check     func(qr ReceiveQR)
+		code      *Code
+		reason    string
+	}{
+		{
+			name:      "OK",
+			accountID: account.ID,
+			amount:    25,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(owner.Username)).Times(1).Return(owner, nil)
+			},
+			check: func(qr ReceiveQR) {
+				require.Equal(t, account.AccountNumber, qr.AccountNumber)
+				require.Equal(t, int64(25), qr.Amount)
+				// a dynamic code in euros for 25.00
+				require.Contains(t, qr.Payload, "010212")
+				require.Contains(t, qr.Payload, "5303978540525.00")
+			},
+		},
+		{
+			// the payer enters the amount of a static code
+			name:      "Static",
+			accountID: account.ID,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(owner.Username)).Times(1).Return(owner, nil)
+			},
+			check: func(qr ReceiveQR) {
+				require.Contains(t, qr.Payload, "010211")
+				require.NotContains(t, qr.Payload, "5303978"+"54")
+			},
+		},
+		{
+			name:      "NegativeAmount",
+			accountID: account.ID,
+			amount:    -1,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code:   codePtr(CodeInvalidArgument),
+			reason: ReasonInvalidAmount,
+		},
+		{
+			name:      "AccountClosed",
+			accountID: closed.ID,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(closed.ID)).Times(1).Return(closed, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code:   codePtr(CodeFailedPrecondition),
+			reason: ReasonAccountClosed,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			service := newTestService(t, store)
+			qr, err := service.ReceiveQR(context.Background(), owner.Username, tc.accountID, tc.amount)
+			if tc.code != nil {
+				require.Equal(t, *tc.code, ErrorCode(err))
+				require.Equal(t, tc.reason, ErrorReason(err))
+				return
+			}
+			require.NoError(t, err)
+			tc.check(qr)
+		})
+	}
+}
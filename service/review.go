@@ -0,0 +1,245 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	db "go-backend/db/sqlc"
+	"go-backend/util"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// The Screener interface is a check run on every transfer before it is made, e.g. against a sanctions
+// list or a risk model. A transfer flagged by any screener is held for review rather than made. An
+// external transfer is screened with no ToAccountID, the money leaving the bank.
+type Screener interface {
+	// Screen returns why the transfer must be held for review, or an empty string to let it through.
+	Screen(ctx context.Context, arg CreateTransferParams) (string, error)
+}
+
+// The AmountScreener type holds the transfers of at least Threshold for review.
+type AmountScreener struct {
+	Threshold int64
+}
+
+func (screener AmountScreener) Screen(ctx context.Context, arg CreateTransferParams) (string, error) {
+	if arg.Amount >= screener.Threshold {
+		return fmt.Sprintf("amount %d is at least the review threshold of %d", arg.Amount, screener.Threshold), nil
+	}
+	return "", nil
+}
+
+// The RegisterScreener function adds a screener run on every transfer, after those already registered.
+func (service *Service) RegisterScreener(screener Screener) {
+	service.screeners = append(service.screeners, screener)
+}
+
+// The screen function runs the screeners on the transfer until one flags it, and returns why. A screener
+// that fails rejects the transfer rather than letting it through unchecked.
+func (service *Service) screen(ctx context.Context, arg CreateTransferParams) (string, error) {
+	for _, screener := range service.screeners {
+		reason, err := screener.Screen(ctx, arg)
+		if err != nil {
+			return "", newError(CodeInternal, fmt.Errorf("cannot screen transfer: %w", err))
+		}
+		if reason != "" {
+			return reason, nil
+		}
+	}
+	return "", nil
+}
+
+// The holdTransfer function opens a review for a transfer flagged by the screening, due within the
+// REVIEW_SLA config.
+func (service *Service) holdTransfer(ctx context.Context, arg CreateTransferParams, reason string) (db.TransferReview, error) {
+	result, err := service.store.HoldTransferTx(ctx, db.HoldTransferTxParams{
+		Transfer:   service.newTransferTxParams(arg),
+		HoldReason: reason,
+		DueAt:      time.Now().Add(service.config.ReviewSLA),
+	})
+	if err != nil {
+		return result.Review, storeError(err)
+	}
+
+	return result.Review, nil
+}
+
+// The ListTransferReviewsParams type holds the filters and page of the review queue.
+// @property {string} Status - only list the reviews with this status when it is set.
+// @property {string} Assignee - only list the reviews assigned to this reviewer when it is set.
+// @property {bool} Overdue - only list the open reviews past their due date.
+type ListTransferReviewsParams struct {
+	Status   string
+	Assignee string
+	Overdue  bool
+	Limit    int32
+	Offset   int32
+}
+
+// The ListTransferReviews function lists the reviews of held transfers, the first due first.
+func (service *Service) ListTransferReviews(ctx context.Context, arg ListTransferReviewsParams) ([]db.TransferReview, error) {
+	switch arg.Status {
+	case "", db.TransferReviewPending, db.TransferReviewEscalated, db.TransferReviewApproved, db.TransferReviewDenied:
+	default:
+		return nil, errorf(CodeInvalidArgument, "unknown review status %s", arg.Status)
+	}
+
+	reviews, err := service.store.ListTransferReviews(ctx, db.ListTransferReviewsParams{
+		Status:    pgtype.Text{String: arg.Status, Valid: arg.Status != ""},
+		Assignee:  pgtype.Text{String: arg.Assignee, Valid: arg.Assignee != ""},
+		DueBefore: pgtype.Timestamptz{Time: time.Now(), Valid: arg.Overdue},
+		RowLimit:  arg.Limit,
+		RowOffset: arg.Offset,
+	})
+	if err != nil {
+		return nil, storeError(err)
+	}
+
+	return reviews, nil
+}
+
+// The AssignTransferReview function assigns an open review to a reviewer, who must be an admin, or
+// unassigns it when the reviewer is empty.
+func (service *Service) AssignTransferReview(ctx context.Context, id int64, assignee string) (db.TransferReview, error) {
+	_, err := service.openTransferReview(ctx, id)
+	if err != nil {
+		return db.TransferReview{}, err
+	}
+
+	err = service.checkReviewer(ctx, assignee)
+	if err != nil {
+		return db.TransferReview{}, err
+	}
+
+	review, err := service.store.AssignTransferReview(ctx, db.AssignTransferReviewParams{
+		Assignee: pgtype.Text{String: assignee, Valid: assignee != ""},
+		ID:       id,
+	})
+	if err != nil {
+		// the review was decided since it was read
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return review, reviewClosedError(id)
+		}
+		return review, storeError(err)
+	}
+
+	return review, nil
+}
+
+// The EscalateTransferReviewParams type is the escalation of an open review.
+// @property {int64} ID - the review escalated.
+// @property {string} Assignee - the reviewer the review is escalated to, it is unassigned when empty.
+// @property {string} Note - why the review is escalated.
+type EscalateTransferReviewParams struct {
+	ID       int64
+	Assignee string
+	Note     string
+}
+
+// The EscalateTransferReview function escalates an open review to another reviewer, restarting its SLA.
+func (service *Service) EscalateTransferReview(ctx context.Context, arg EscalateTransferReviewParams) (db.TransferReview, error) {
+	_, err := service.openTransferReview(ctx, arg.ID)
+	if err != nil {
+		return db.TransferReview{}, err
+	}
+
+	err = service.checkReviewer(ctx, arg.Assignee)
+	if err != nil {
+		return db.TransferReview{}, err
+	}
+
+	review, err := service.store.EscalateTransferReview(ctx, db.EscalateTransferReviewParams{
+		Assignee: pgtype.Text{String: arg.Assignee, Valid: arg.Assignee != ""},
+		DueAt:    time.Now().Add(service.config.ReviewSLA),
+		Note:     arg.Note,
+		ID:       arg.ID,
+	})
+	if err != nil {
+		// the review was decided since it was read
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return review, reviewClosedError(arg.ID)
+		}
+		return review, storeError(err)
+	}
+
+	return review, nil
+}
+
+// The DecideTransferReviewParams type is the decision of a reviewer on an open review.
+// @property {int64} ID - the review decided.
+// @property {string} Reviewer - the admin making the decision.
+// @property {bool} Approve - whether the transfer is made, rather than refunded.
+// @property {string} Note - why the reviewer made the decision.
+type DecideTransferReviewParams struct {
+	ID       int64
+	Reviewer string
+	Approve  bool
+	Note     string
+}
+
+// The DecideTransferReview function approves an open review, making its transfer, or denies it,
+// refunding the held amount to the from account.
+func (service *Service) DecideTransferReview(ctx context.Context, arg DecideTransferReviewParams) (db.DecideTransferReviewTxResult, error) {
+	status := db.TransferReviewDenied
+	if arg.Approve {
+		status = db.TransferReviewApproved
+	}
+
+	result, err := service.store.DecideTransferReviewTx(ctx, db.DecideTransferReviewTxParams{
+		ID:        arg.ID,
+		Status:    status,
+		DecidedBy: arg.Reviewer,
+		Note:      arg.Note,
+		Now:       service.clock.Now(),
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrTransferReviewClosed) {
+			return result, reviewClosedError(arg.ID)
+		}
+		return result, storeError(err)
+	}
+
+	return result, nil
+}
+
+// The openTransferReview function returns the review, provided it wasn't decided yet.
+func (service *Service) openTransferReview(ctx context.Context, id int64) (db.TransferReview, error) {
+	review, err := service.store.GetTransferReview(ctx, id)
+	if err != nil {
+		return review, storeError(err)
+	}
+
+	if !db.IsTransferReviewOpen(review.Status) {
+		return review, reviewClosedError(id)
+	}
+
+	return review, nil
+}
+
+// The checkReviewer function checks that a review can be assigned to the user, who must be an admin. An
+// empty username leaves the review unassigned.
+func (service *Service) checkReviewer(ctx context.Context, username string) error {
+	if username == "" {
+		return nil
+	}
+
+	user, err := service.store.GetUser(ctx, username)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return errorf(CodeInvalidArgument, "unknown reviewer %s", username)
+		}
+		return storeError(err)
+	}
+
+	if user.Role != util.AdminRole {
+		return errorf(CodeInvalidArgument, "reviewer %s is not an admin", username)
+	}
+
+	return nil
+}
+
+func reviewClosedError(id int64) error {
+	return errorf(CodeFailedPrecondition, "review [%d] is already decided", id).withReason(ReasonReviewClosed)
+}
@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"go-backend/clock"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/testutil/factory"
+	"go-backend/util"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateTransferHeld(t *testing.T) {
+	owner := util.RandomOwner()
+	fromAccount := factory.Account(factory.OwnedBy(owner), factory.InCurrency(util.USD))
+	toAccount := factory.Account(factory.OwnedBy(util.RandomOwner()), factory.InCurrency(util.USD))
+	toAccount.ID = fromAccount.ID + 1
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	service := newTestService(t, store)
+	service.config.ReviewSLA = time.Hour
+	service.RegisterScreener(AmountScreener{Threshold: 100})
+
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(2).Return(fromAccount, nil)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(2).Return(toAccount, nil)
+	store.EXPECT().GetActiveBankParameter(gomock.Any(), gomock.Any()).Times(2).Return(db.BankParameter{}, db.ErrRecordNotFound)
+
+	// below the threshold the transfer is made
+	store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(1).Return(db.TransferTxResult{}, nil)
+	result, err := service.CreateTransfer(context.Background(), CreateTransferParams{
+		Owner:         owner,
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   toAccount.ID,
+		Amount:        99,
+		Currency:      util.USD,
+	})
+	require.NoError(t, err)
+	require.Nil(t, result.Review)
+
+	store.EXPECT().
+		HoldTransferTx(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(ctx context.Context, arg db.HoldTransferTxParams) (db.HoldTransferTxResult, error) {
+			require.Equal(t, int64(100), arg.Transfer.Amount)
+			require.NotEmpty(t, arg.HoldReason)
+			require.WithinDuration(t, time.Now().Add(time.Hour), arg.DueAt, time.Second)
+			return db.HoldTransferTxResult{Review: db.TransferReview{ID: 1, Status: db.TransferReviewPending}}, nil
+		})
+	result, err = service.CreateTransfer(context.Background(), CreateTransferParams{
+		Owner:         owner,
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   toAccount.ID,
+		Amount:        100,
+		Currency:      util.USD,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, result.Review)
+	require.Equal(t, int64(1), result.Review.ID)
+}
+
+func TestAssignTransferReview(t *testing.T) {
+	admin := db.User{Username: util.RandomOwner(), Role: util.AdminRole}
+	depositor := db.User{Username: util.RandomOwner(), Role: util.DepositorRole}
+	pending := db.TransferReview{ID: 1, Status: db.TransferReviewPending}
+	denied := db.TransferReview{ID: 2, Status: db.TransferReviewDenied}
+
+	testCases := []struct {
+		name      string
+		review    db.TransferReview
+		assignee  string
+		buildStub func(store *mockdb.MockStore)
+		code      *Code
+		reason    string
+	}{
+		{
+			name:     "OK",
+			review:   pending,
+			assignee: admin.Username,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransferReview(gomock.Any(), gomock.Eq(pending.ID)).Times(1).Return(pending, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).Times(1).Return(admin, nil)
+				store.EXPECT().AssignTransferReview(gomock.Any(), gomock.Any()).Times(1).Return(pending, nil)
+			},
+		},
+		{
+			name:     "NotAnAdmin",
+			review:   pending,
+			assignee: depositor.Username,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransferReview(gomock.Any(), gomock.Eq(pending.ID)).Times(1).Return(pending, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(depositor.Username)).Times(1).Return(depositor, nil)
+				store.EXPECT().AssignTransferReview(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code: codePtr(CodeInvalidArgument),
+		},
+		{
+			name:     "Decided",
+			review:   denied,
+			assignee: admin.Username,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransferReview(gomock.Any(), gomock.Eq(denied.ID)).Times(1).Return(denied, nil)
+				store.EXPECT().AssignTransferReview(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code:   codePtr(CodeFailedPrecondition),
+			reason: ReasonReviewClosed,
+		},
+		{
+			// the review is decided between the check and the update
+			name:     "DecidedMeanwhile",
+			review:   pending,
+			assignee: admin.Username,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTransferReview(gomock.Any(), gomock.Eq(pending.ID)).Times(1).Return(pending, nil)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).Times(1).Return(admin, nil)
+				store.EXPECT().AssignTransferReview(gomock.Any(), gomock.Any()).Times(1).Return(db.TransferReview{}, db.ErrRecordNotFound)
+			},
+			code:   codePtr(CodeFailedPrecondition),
+			reason: ReasonReviewClosed,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			_, err := newTestService(t, store).AssignTransferReview(context.Background(), tc.review.ID, tc.assignee)
+			if tc.code == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.Equal(t, *tc.code, ErrorCode(err))
+			require.Equal(t, tc.reason, ErrorReason(err))
+		})
+	}
+}
+
+func TestDecideTransferReview(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	service := newTestService(t, store)
+	fake := clock.NewFake(time.Now())
+	service.SetClock(fake)
+
+	// the fee of the approved transfer is charged by the clock of the service
+	reviewer := util.RandomOwner()
+	store.EXPECT().
+		DecideTransferReviewTx(gomock.Any(), gomock.Eq(db.DecideTransferReviewTxParams{
+			ID:        1,
+			Status:    db.TransferReviewApproved,
+			DecidedBy: reviewer,
+			Note:      "known customer",
+			Now:       fake.Now(),
+		})).
+		Times(1).
+		Return(db.DecideTransferReviewTxResult{}, nil)
+	_, err := service.DecideTransferReview(context.Background(), DecideTransferReviewParams{
+		ID:       1,
+		Reviewer: reviewer,
+		Approve:  true,
+		Note:     "known customer",
+	})
+	require.NoError(t, err)
+
+	store.EXPECT().
+		DecideTransferReviewTx(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(ctx context.Context, arg db.DecideTransferReviewTxParams) (db.DecideTransferReviewTxResult, error) {
+			require.Equal(t, db.TransferReviewDenied, arg.Status)
+			return db.DecideTransferReviewTxResult{}, db.ErrTransferReviewClosed
+		})
+	_, err = service.DecideTransferReview(context.Background(), DecideTransferReviewParams{ID: 1, Reviewer: reviewer})
+	require.Equal(t, CodeFailedPrecondition, ErrorCode(err))
+	require.Equal(t, ReasonReviewClosed, ErrorReason(err))
+}
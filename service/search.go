@@ -0,0 +1,41 @@
+package service
+
+import (
+	"context"
+	db "go-backend/db/sqlc"
+	"strings"
+)
+
+// MaxSearchQueryLength is the longest query the entries of an account can be searched with.
+const MaxSearchQueryLength = 200
+
+// The SearchEntries function searches the entries of an account belonging to the owner made for transfers
+// whose memo, counterparty or category match the query, best matches first. The query is a web search
+// query: words all match by default, "quoted phrases" match as a whole, `or` matches either side and a
+// leading `-` excludes a word. The entries made without a transfer, such as interest, never match.
+func (service *Service) SearchEntries(ctx context.Context, owner string, accountID int64, query string, limit int32, offset int32) ([]db.SearchAccountEntriesRow, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, errorf(CodeInvalidArgument, "search query is empty")
+	}
+	if len(query) > MaxSearchQueryLength {
+		return nil, errorf(CodeInvalidArgument, "search query is longer than %d characters", MaxSearchQueryLength)
+	}
+
+	account, err := service.GetAccount(ctx, owner, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := service.store.SearchAccountEntries(ctx, db.SearchAccountEntriesParams{
+		Query:     query,
+		AccountID: account.ID,
+		RowLimit:  limit,
+		RowOffset: offset,
+	})
+	if err != nil {
+		return nil, storeError(err)
+	}
+
+	return results, nil
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/testutil/factory"
+	"go-backend/util"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchEntries(t *testing.T) {
+	owner := util.RandomOwner()
+	account := factory.Account(factory.OwnedBy(owner))
+
+	testCases := []struct {
+		name      string
+		query     string
+		buildStub func(store *mockdb.MockStore)
+		code      *Code
+	}{
+		{
+			name:  "OK",
+			query: "  rent ",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().SearchAccountEntries(gomock.Any(), gomock.Eq(db.SearchAccountEntriesParams{
+					Query:     "rent",
+					AccountID: account.ID,
+					RowLimit:  10,
+				})).Times(1).Return([]db.SearchAccountEntriesRow{{MemoHighlight: "<mark>rent</mark>"}}, nil)
+			},
+		},
+		{
+			name:  "EmptyQuery",
+			query: " ",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().SearchAccountEntries(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code: codePtr(CodeInvalidArgument),
+		},
+		{
+			name:  "QueryTooLong",
+			query: strings.Repeat("a", MaxSearchQueryLength+1),
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().SearchAccountEntries(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code: codePtr(CodeInvalidArgument),
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			service := newTestService(t, store)
+			results, err := service.SearchEntries(context.Background(), owner, account.ID, tc.query, 10, 0)
+			if tc.code != nil {
+				require.Equal(t, *tc.code, ErrorCode(err))
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, results, 1)
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	db "go-backend/db/sqlc"
+	"go-backend/util"
+	"math/rand"
+)
+
+// Names and memos the seeded users and transfers are drawn from, so that the data looks like real
+// customers rather than random strings.
+var (
+	seedFirstNames = []string{"Ada", "Alan", "Grace", "Linus", "Margaret", "Ken", "Barbara", "Dennis", "Frances", "John"}
+	seedLastNames  = []string{"Lovelace", "Turing", "Hopper", "Torvalds", "Hamilton", "Thompson", "Liskov", "Ritchie", "Allen", "Backus"}
+	seedMemos      = []string{"Rent", "Dinner", "Groceries", "Concert tickets", "Birthday gift", "Utilities", "Car pool", "Coffee", ""}
+)
+
+// The SeedParams type holds how much data is seeded.
+// @property {int} Users - the number of users created.
+// @property {int} MaxAccountsPerUser - each user opens between one and this many accounts, in distinct
+// currencies.
+// @property {int} Transfers - the number of transfers made between the accounts of different users.
+// @property {string} Password - the password of every seeded user, so that they can log in.
+type SeedParams struct {
+	Users              int
+	MaxAccountsPerUser int
+	Transfers          int
+	Password           string
+}
+
+// The SeedResult type holds the data seeded.
+type SeedResult struct {
+	Users     []db.User
+	Accounts  []db.Account
+	Transfers []db.Transfer
+}
+
+// The Seed function creates random users with funded accounts across the currencies and a history of
+// transfers between them, for developing against meaningful data. The accounts are funded from the
+// suspense account of their currency and the transfers post their entries, so that the balances match
+// the ledger.
+func (service *Service) Seed(ctx context.Context, arg SeedParams) (SeedResult, error) {
+	var result SeedResult
+
+	if arg.Users < 0 || arg.Transfers < 0 || arg.MaxAccountsPerUser < 1 || arg.MaxAccountsPerUser > len(util.SupportedCurrencies) {
+		return result, errorf(CodeInvalidArgument, "cannot seed %d users with up to %d accounts and %d transfers", arg.Users, arg.MaxAccountsPerUser, arg.Transfers)
+	}
+
+	for i := 0; i < arg.Users; i++ {
+		firstName := seedFirstNames[rand.Intn(len(seedFirstNames))]
+		lastName := seedLastNames[rand.Intn(len(seedLastNames))]
+
+		user, err := service.CreateUser(ctx, CreateUserParams{
+			Username: fmt.Sprintf("%s%d", util.RandomOwner(), i),
+			Password: arg.Password,
+			FullName: firstName + " " + lastName,
+			Email:    util.RandomEmail(),
+		})
+		if err != nil {
+			return result, err
+		}
+		result.Users = append(result.Users, user)
+
+		currencies := rand.Perm(len(util.SupportedCurrencies))[:util.RandomInt(1, int64(arg.MaxAccountsPerUser))]
+		for _, c := range currencies {
+			account, err := service.CreateAccount(ctx, user.Username, util.SupportedCurrencies[c])
+			if err != nil {
+				return result, err
+			}
+
+			funding, err := service.AdjustAccount(ctx, AdjustAccountParams{
+				AccountID: account.ID,
+				Version:   account.Version,
+				Amount:    util.RandomInt(1000, 100000),
+				Memo:      "seed funding",
+			})
+			if err != nil {
+				return result, err
+			}
+			result.Accounts = append(result.Accounts, funding.ToAccount)
+		}
+	}
+
+	for i := 0; i < arg.Transfers; i++ {
+		from, to, ok := seedTransferAccounts(result.Accounts)
+		if !ok {
+			break
+		}
+
+		transfer, err := service.store.TransferTx(ctx, db.TransferTxParams{
+			FromAccountID: result.Accounts[from].ID,
+			ToAccountID:   result.Accounts[to].ID,
+			Amount:        util.RandomInt(1, result.Accounts[from].Balance/4+1),
+			Memo:          seedMemos[rand.Intn(len(seedMemos))],
+		})
+		if err != nil {
+			return result, storeError(err)
+		}
+
+		result.Accounts[from] = transfer.FromAccount
+		result.Accounts[to] = transfer.ToAccount
+		result.Transfers = append(result.Transfers, transfer.Transfer)
+	}
+
+	return result, nil
+}
+
+// The seedTransferAccounts function picks a funded account and another account in its currency owned by
+// another user, `ok` being false when no such pair exists.
+func seedTransferAccounts(accounts []db.Account) (from int, to int, ok bool) {
+	for _, from := range rand.Perm(len(accounts)) {
+		if accounts[from].Balance <= 0 {
+			continue
+		}
+
+		for _, to := range rand.Perm(len(accounts)) {
+			if accounts[to].Currency == accounts[from].Currency && accounts[to].Owner != accounts[from].Owner {
+				return from, to, true
+			}
+		}
+	}
+
+	return 0, 0, false
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/util"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	service := newTestService(t, store)
+
+	accounts := map[int64]db.Account{}
+
+	store.EXPECT().
+		CreateUser(gomock.Any(), gomock.Any()).
+		Times(4).
+		DoAndReturn(func(ctx context.Context, arg db.CreateUserParams) (db.User, error) {
+			require.NoError(t, util.Checkpassword("secret", arg.HashedPassword))
+			return db.User{Username: arg.Username, FullName: arg.FullName, Email: arg.Email}, nil
+		})
+	store.EXPECT().
+		CreateAccount(gomock.Any(), gomock.Any()).
+		MinTimes(4).
+		DoAndReturn(func(ctx context.Context, arg db.CreateAccountParams) (db.Account, error) {
+			account := db.Account{ID: int64(len(accounts) + 1), Owner: arg.Owner, Currency: arg.Currency}
+			accounts[account.ID] = account
+			return account, nil
+		})
+	store.EXPECT().
+		AdjustAccountBalanceTx(gomock.Any(), gomock.Any()).
+		MinTimes(4).
+		DoAndReturn(func(ctx context.Context, arg db.AdjustAccountBalanceTxParams) (db.TransferTxResult, error) {
+			require.Positive(t, arg.Amount)
+			account := accounts[arg.ID]
+			account.Balance += arg.Amount
+			accounts[arg.ID] = account
+			return db.TransferTxResult{ToAccount: account}, nil
+		})
+	store.EXPECT().
+		TransferTx(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		DoAndReturn(func(ctx context.Context, arg db.TransferTxParams) (db.TransferTxResult, error) {
+			from, to := accounts[arg.FromAccountID], accounts[arg.ToAccountID]
+			require.Equal(t, from.Currency, to.Currency)
+			require.NotEqual(t, from.Owner, to.Owner)
+			require.Positive(t, arg.Amount)
+			require.LessOrEqual(t, arg.Amount, from.Balance)
+
+			from.Balance -= arg.Amount
+			to.Balance += arg.Amount
+			accounts[from.ID], accounts[to.ID] = from, to
+			return db.TransferTxResult{
+				Transfer:    db.Transfer{FromAccountID: from.ID, ToAccountID: to.ID, Amount: arg.Amount},
+				FromAccount: from,
+				ToAccount:   to,
+			}, nil
+		})
+
+	result, err := service.Seed(context.Background(), SeedParams{
+		Users:              4,
+		MaxAccountsPerUser: 3,
+		Transfers:          20,
+		Password:           "secret",
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Users, 4)
+	require.Len(t, result.Accounts, len(accounts))
+	require.LessOrEqual(t, len(result.Transfers), 20)
+
+	for _, account := range result.Accounts {
+		require.Equal(t, accounts[account.ID], account)
+	}
+
+	_, err = service.Seed(context.Background(), SeedParams{Users: 1, MaxAccountsPerUser: 4})
+	require.Equal(t, CodeInvalidArgument, ErrorCode(err))
+}
@@ -0,0 +1,65 @@
+// Package service holds the business logic of the bank independently of the transport it is served
+// over. The gin handlers of the api package and the gRPC methods of the gapi package are thin adapters
+// that decode a request, call the service and encode its result or error.
+package service
+
+import (
+	"go-backend/clock"
+	db "go-backend/db/sqlc"
+	"go-backend/token"
+	"go-backend/usage"
+	"go-backend/util"
+	"go-backend/worker"
+)
+
+// The Service type implements the use cases of the bank on top of the store.
+// @property config - the configuration of the server, for token durations.
+// @property store - the store used to read and write the database.
+// @property tokenMaker - creates and verifies the access and refresh tokens.
+// @property taskDistributor - enqueues the background tasks, it may be nil when no task is needed.
+// @property sessions - the last use of the sessions not yet written to the database.
+// @property signups - the signups of every client in the current window of the SIGNUP_WINDOW config.
+// @property screeners - the checks a transfer must pass to be made rather than held for review.
+// @property meter - counts the calls of the users to the API, in memory unless `SetUsageMeter` is called.
+// @property plans - the monthly quotas of calls of the API plans, by plan.
+// @property clock - the clock the sessions expire by, the real one unless `SetClock` is called.
+type Service struct {
+	config          util.Config
+	store           db.Store
+	tokenMaker      token.Maker
+	taskDistributor worker.TaskDistributor
+	sessions        *sessionActivity
+	signups         *signupLimiter
+	screeners       []Screener
+	meter           usage.Meter
+	plans           map[string]int64
+	clock           clock.Clock
+}
+
+// The function creates a new service with its dependencies.
+// Transfers of at least the REVIEW_AMOUNT_THRESHOLD config are held for review when it is set, other
+// screeners can be added with `RegisterScreener`.
+func New(config util.Config, store db.Store, tokenMaker token.Maker, taskDistributor worker.TaskDistributor) *Service {
+	service := &Service{
+		config:          config,
+		store:           store,
+		tokenMaker:      tokenMaker,
+		taskDistributor: taskDistributor,
+		sessions:        newSessionActivity(),
+		signups:         newSignupLimiter(),
+		meter:           usage.NewMemoryMeter(),
+		clock:           clock.Real,
+	}
+
+	if config.ReviewAmountThreshold > 0 {
+		service.RegisterScreener(AmountScreener{Threshold: config.ReviewAmountThreshold})
+	}
+
+	return service
+}
+
+// The SetClock function sets the clock the sessions expire by, for tests to fast-forward time. The token
+// maker has its clock of its own, see `token.MakerConfig`.
+func (service *Service) SetClock(clock clock.Clock) {
+	service.clock = clock
+}
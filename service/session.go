@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"errors"
+	db "go-backend/db/sqlc"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// The sessionActivity type holds the last use of every session until it is flushed to the last_used_at
+// column of the sessions table, so that authenticated requests don't each write to the database.
+type sessionActivity struct {
+	mu       sync.Mutex
+	lastUsed map[uuid.UUID]time.Time
+}
+
+func newSessionActivity() *sessionActivity {
+	return &sessionActivity{lastUsed: make(map[uuid.UUID]time.Time)}
+}
+
+func (activity *sessionActivity) touch(sessionID uuid.UUID, at time.Time) {
+	activity.mu.Lock()
+	defer activity.mu.Unlock()
+
+	if at.After(activity.lastUsed[sessionID]) {
+		activity.lastUsed[sessionID] = at
+	}
+}
+
+func (activity *sessionActivity) pending(sessionID uuid.UUID) (time.Time, bool) {
+	activity.mu.Lock()
+	defer activity.mu.Unlock()
+
+	at, ok := activity.lastUsed[sessionID]
+	return at, ok
+}
+
+// The `flush` function writes the pending last uses to the database. Those that could not be written
+// are kept for the next flush, unless the session was used again in the meantime.
+func (activity *sessionActivity) flush(ctx context.Context, store db.Store) error {
+	activity.mu.Lock()
+	pending := activity.lastUsed
+	activity.lastUsed = make(map[uuid.UUID]time.Time)
+	activity.mu.Unlock()
+
+	var flushErr error
+	for sessionID, at := range pending {
+		err := store.TouchSession(ctx, db.TouchSessionParams{
+			LastUsedAt: at,
+			ID:         sessionID,
+		})
+		if err != nil {
+			flushErr = err
+			continue
+		}
+		delete(pending, sessionID)
+	}
+
+	for sessionID, at := range pending {
+		activity.touch(sessionID, at)
+	}
+
+	return flushErr
+}
+
+// The CheckSessionIdle function fails with SESSION_IDLE when the session of an access token wasn't used
+// for longer than the SESSION_IDLE_TIMEOUT config, so that the access tokens of an idle session stop
+// working along with it rather than once they expire. The last use recorded since the last flush is
+// used when there is one, the session being only read from the database otherwise. The tokens that
+// aren't tied to a session aren't checked.
+func (service *Service) CheckSessionIdle(ctx context.Context, sessionID uuid.UUID) error {
+	if sessionID == uuid.Nil || service.config.SessionIdleTimeout <= 0 {
+		return nil
+	}
+
+	lastUse, ok := service.sessions.pending(sessionID)
+	if !ok {
+		session, err := service.store.GetSession(ctx, sessionID)
+		if err != nil {
+			if errors.Is(err, db.ErrRecordNotFound) {
+				return newError(CodeUnauthenticated, errors.New("unknown session"))
+			}
+			return storeError(err)
+		}
+		lastUse = session.LastUsedAt
+	}
+
+	if service.clock.Now().Sub(lastUse) > service.config.SessionIdleTimeout {
+		return newError(CodeUnauthenticated, errors.New("idle session")).withReason(ReasonSessionIdle)
+	}
+	return nil
+}
+
+// The TouchSession function records that the session was used now, by the clock of the service. The use
+// is only written to the database by the next flush; tokens that aren't tied to a session are ignored.
+func (service *Service) TouchSession(sessionID uuid.UUID) {
+	if sessionID == uuid.Nil {
+		return
+	}
+	service.sessions.touch(sessionID, service.clock.Now())
+}
+
+// The FlushSessionActivity function writes the session uses recorded since the last flush to the
+// database.
+func (service *Service) FlushSessionActivity(ctx context.Context) error {
+	return service.sessions.flush(ctx, service.store)
+}
+
+// The RunSessionActivityFlusher function flushes the session uses every `interval` until the context is
+// cancelled, then flushes one last time.
+func (service *Service) RunSessionActivityFlusher(ctx context.Context, interval time.Duration) {
+	ticker := service.clock.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			err := service.FlushSessionActivity(context.Background())
+			if err != nil {
+				log.Printf("failed to flush session activity: %v", err)
+			}
+			return
+		case <-ticker.C():
+			err := service.FlushSessionActivity(ctx)
+			if err != nil && ctx.Err() == nil {
+				log.Printf("failed to flush session activity: %v", err)
+			}
+		}
+	}
+}
+
+// lastSessionUse returns the last use of the session, which is the pending one when the session was
+// used since the last flush.
+func (service *Service) lastSessionUse(session db.Session) time.Time {
+	if at, ok := service.sessions.pending(session.ID); ok && at.After(session.LastUsedAt) {
+		return at
+	}
+	return session.LastUsedAt
+}
@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"go-backend/clock"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/token"
+	"go-backend/util"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenewAccessTokenIdleSession(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	service := newTestService(t, store)
+	service.config.SessionIdleTimeout = 10 * time.Minute
+
+	username := util.RandomOwner()
+	refreshToken, refreshPayload, err := service.tokenMaker.CreateToken(token.Claims{Username: username}, time.Hour)
+	require.NoError(t, err)
+
+	session := db.Session{
+		ID:           refreshPayload.ID,
+		Username:     username,
+		RefreshToken: refreshToken,
+		ExpiresAt:    refreshPayload.ExpiredAt,
+		LastUsedAt:   time.Now().Add(-time.Hour),
+	}
+	store.EXPECT().GetSession(gomock.Any(), gomock.Eq(session.ID)).AnyTimes().Return(session, nil)
+
+	_, _, err = service.RenewAccessToken(context.Background(), refreshToken)
+	require.Equal(t, CodeUnauthenticated, ErrorCode(err))
+	require.Equal(t, ReasonSessionIdle, ErrorReason(err))
+
+	// a use that wasn't flushed yet keeps the session active
+	service.TouchSession(session.ID)
+	_, accessPayload, err := service.RenewAccessToken(context.Background(), refreshToken)
+	require.NoError(t, err)
+	require.Equal(t, session.ID, accessPayload.SessionID)
+}
+
+func TestRenewAccessTokenKeepsScopes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	service := newTestService(t, store)
+
+	username := util.RandomOwner()
+	scopes := []string{token.ScopeAccountsRead}
+	metadata := map[string]string{token.MetadataAuthMethod: AuthMethodPassword}
+	refreshToken, refreshPayload, err := service.tokenMaker.CreateToken(token.Claims{
+		Username: username,
+		Role:     util.DepositorRole,
+		Scopes:   scopes,
+		Metadata: metadata,
+	}, time.Hour)
+	require.NoError(t, err)
+
+	session := db.Session{
+		ID:           refreshPayload.ID,
+		Username:     username,
+		RefreshToken: refreshToken,
+		ExpiresAt:    refreshPayload.ExpiredAt,
+	}
+	store.EXPECT().GetSession(gomock.Any(), gomock.Eq(session.ID)).Times(1).Return(session, nil)
+
+	// a read-only session can't renew its way to more scopes
+	_, accessPayload, err := service.RenewAccessToken(context.Background(), refreshToken)
+	require.NoError(t, err)
+	require.Equal(t, scopes, accessPayload.Scopes)
+	require.Equal(t, util.DepositorRole, accessPayload.Role)
+	require.Equal(t, metadata, accessPayload.Metadata)
+	require.Equal(t, session.ID, accessPayload.SessionID)
+}
+
+func TestFlushSessionActivity(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	service := newTestService(t, store)
+	fake := clock.NewFake(time.Now())
+	service.SetClock(fake)
+
+	// the last use is written, at the time of the clock
+	session := db.Session{ID: uuid.New()}
+	service.TouchSession(session.ID)
+	fake.Advance(time.Minute)
+	service.TouchSession(session.ID)
+	usedAt := fake.Now()
+
+	// a failed flush keeps the use for the next one
+	store.EXPECT().
+		TouchSession(gomock.Any(), gomock.Eq(db.TouchSessionParams{LastUsedAt: usedAt, ID: session.ID})).
+		Times(1).
+		Return(errors.New("connection refused"))
+	require.Error(t, service.FlushSessionActivity(context.Background()))
+	require.Equal(t, usedAt, service.lastSessionUse(session))
+
+	store.EXPECT().
+		TouchSession(gomock.Any(), gomock.Eq(db.TouchSessionParams{LastUsedAt: usedAt, ID: session.ID})).
+		Times(1).
+		Return(nil)
+	require.NoError(t, service.FlushSessionActivity(context.Background()))
+
+	// flushed uses are not written again
+	require.NoError(t, service.FlushSessionActivity(context.Background()))
+}
+
+func TestRenewAccessTokenSessionExpiresByClock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	service := newTestService(t, store)
+	service.config.SessionIdleTimeout = 10 * time.Minute
+	fake := clock.NewFake(time.Now())
+	service.SetClock(fake)
+
+	username := util.RandomOwner()
+	refreshToken, refreshPayload, err := service.tokenMaker.CreateToken(token.Claims{Username: username}, time.Hour)
+	require.NoError(t, err)
+
+	session := db.Session{
+		ID:           refreshPayload.ID,
+		Username:     username,
+		RefreshToken: refreshToken,
+		ExpiresAt:    fake.Now().Add(30 * time.Minute),
+		LastUsedAt:   fake.Now(),
+	}
+	store.EXPECT().GetSession(gomock.Any(), gomock.Eq(session.ID)).AnyTimes().Return(session, nil)
+
+	// every renewal is a use of the session, keeping it active
+	for i := 0; i < 2; i++ {
+		fake.Advance(9 * time.Minute)
+		_, _, err = service.RenewAccessToken(context.Background(), refreshToken)
+		require.NoError(t, err)
+	}
+
+	fake.Advance(11 * time.Minute)
+	_, _, err = service.RenewAccessToken(context.Background(), refreshToken)
+	require.Equal(t, ReasonSessionIdle, ErrorReason(err))
+
+	service.TouchSession(session.ID)
+	fake.Advance(2 * time.Minute)
+	_, _, err = service.RenewAccessToken(context.Background(), refreshToken)
+	require.Equal(t, ReasonSessionExpired, ErrorReason(err))
+}
+
+func TestCheckSessionIdle(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	service := newTestService(t, store)
+	service.config.SessionIdleTimeout = 10 * time.Minute
+	fake := clock.NewFake(time.Now())
+	service.SetClock(fake)
+
+	session := db.Session{ID: uuid.New(), LastUsedAt: fake.Now().Add(-time.Hour)}
+
+	// the last use is read from the database while none is pending
+	store.EXPECT().GetSession(gomock.Any(), gomock.Eq(session.ID)).Times(1).Return(session, nil)
+	err := service.CheckSessionIdle(context.Background(), session.ID)
+	require.Equal(t, CodeUnauthenticated, ErrorCode(err))
+	require.Equal(t, ReasonSessionIdle, ErrorReason(err))
+
+	// a pending use keeps the session active without reading it
+	service.TouchSession(session.ID)
+	fake.Advance(9 * time.Minute)
+	require.NoError(t, service.CheckSessionIdle(context.Background(), session.ID))
+
+	fake.Advance(2 * time.Minute)
+	err = service.CheckSessionIdle(context.Background(), session.ID)
+	require.Equal(t, ReasonSessionIdle, ErrorReason(err))
+
+	// the tokens that aren't tied to a session aren't checked
+	require.NoError(t, service.CheckSessionIdle(context.Background(), uuid.Nil))
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	db "go-backend/db/sqlc"
+	"go-backend/util"
+)
+
+// errUnknownSigningKey is the error of the requests signed with a key that isn't an active key of their
+// user, whether it doesn't exist, belongs to another user or was revoked.
+var errUnknownSigningKey = errors.New("unknown signing key")
+
+// The SigningKeyProof type proves that a change of the signing keys of a user is made by the user, not
+// by someone holding a stolen access token, who could otherwise sign their own requests.
+// @property {bool} Signed - whether the request was signed with an active signing key of the user.
+// @property {string} Password - the password of the user, asked again when the request isn't signed.
+type SigningKeyProof struct {
+	Signed   bool
+	Password string
+}
+
+// The verifySigningKeyProof function checks the proof that a change of the signing keys of the user is
+// made by the user, failing as unauthenticated when it doesn't hold.
+func (service *Service) verifySigningKeyProof(ctx context.Context, username string, proof SigningKeyProof) error {
+	if proof.Signed {
+		return nil
+	}
+
+	user, err := service.store.GetUser(ctx, username)
+	if err != nil {
+		return storeError(err)
+	}
+	err = util.Checkpassword(proof.Password, user.HashedPassword)
+	if err != nil {
+		return newError(CodeUnauthenticated, err).withReason(ReasonInvalidCredentials)
+	}
+	return nil
+}
+
+// The CreateSigningKey function creates a signing key for the user, whose secret is only returned here.
+// Once a machine client, a user with an API plan, has an active key, the transfers they create must be
+// signed. The request must be signed with another active key of the user or carry their password.
+func (service *Service) CreateSigningKey(ctx context.Context, username string, proof SigningKeyProof) (db.SigningKey, error) {
+	err := service.verifySigningKeyProof(ctx, username, proof)
+	if err != nil {
+		return db.SigningKey{}, err
+	}
+
+	secret := make([]byte, 32)
+	_, err = rand.Read(secret)
+	if err != nil {
+		return db.SigningKey{}, newError(CodeInternal, err)
+	}
+
+	key, err := service.store.CreateSigningKey(ctx, db.CreateSigningKeyParams{
+		Username: username,
+		Secret:   hex.EncodeToString(secret),
+	})
+	if err != nil {
+		return db.SigningKey{}, storeError(err)
+	}
+	return key, nil
+}
+
+// The ListSigningKeys function lists the signing keys of the user, revoked ones included.
+func (service *Service) ListSigningKeys(ctx context.Context, username string) ([]db.SigningKey, error) {
+	keys, err := service.store.ListSigningKeys(ctx, username)
+	if err != nil {
+		return nil, storeError(err)
+	}
+	return keys, nil
+}
+
+// The RevokeSigningKey function revokes a signing key of the user, the requests signed with it being
+// rejected from then on. The request must be signed with an active key of the user or carry their
+// password.
+func (service *Service) RevokeSigningKey(ctx context.Context, username string, id int64, proof SigningKeyProof) (db.SigningKey, error) {
+	err := service.verifySigningKeyProof(ctx, username, proof)
+	if err != nil {
+		return db.SigningKey{}, err
+	}
+
+	key, err := service.store.RevokeSigningKey(ctx, db.RevokeSigningKeyParams{
+		ID:       id,
+		Username: username,
+	})
+	if err != nil {
+		return db.SigningKey{}, storeError(err)
+	}
+	return key, nil
+}
+
+// The SigningKey function returns the active signing key `id` of the user, failing as unauthenticated
+// when they have no such key.
+func (service *Service) SigningKey(ctx context.Context, username string, id int64) (db.SigningKey, error) {
+	key, err := service.store.GetSigningKey(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return db.SigningKey{}, newError(CodeUnauthenticated, errUnknownSigningKey)
+		}
+		return db.SigningKey{}, storeError(err)
+	}
+	if key.Username != username || key.RevokedAt.Valid {
+		return db.SigningKey{}, newError(CodeUnauthenticated, errUnknownSigningKey)
+	}
+	return key, nil
+}
+
+// The SignatureRequired function reports whether the high-risk requests of the user must be signed,
+// which they must once the user is a machine client, with an API plan, and has an active signing key.
+// The signatures are optional for the other users.
+func (service *Service) SignatureRequired(ctx context.Context, username string) (bool, error) {
+	if len(service.plans) == 0 {
+		return false, nil
+	}
+
+	_, err := service.store.GetUserAPIPlan(ctx, username)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, storeError(err)
+	}
+
+	count, err := service.store.CountActiveSigningKeys(ctx, username)
+	if err != nil {
+		return false, storeError(err)
+	}
+	return count > 0, nil
+}
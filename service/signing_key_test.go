@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/testutil/factory"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignatureRequired(t *testing.T) {
+	user := factory.User()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	service := newTestService(t, store)
+
+	// there are no machine clients without API plans
+	required, err := service.SignatureRequired(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.False(t, required)
+
+	service.SetAPIPlans(map[string]int64{"basic": 10000})
+	store.EXPECT().GetUserAPIPlan(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(db.UserApiPlan{}, db.ErrRecordNotFound)
+	required, err = service.SignatureRequired(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.False(t, required)
+
+	store.EXPECT().GetUserAPIPlan(gomock.Any(), gomock.Eq(user.Username)).Times(2).Return(db.UserApiPlan{Plan: "basic"}, nil)
+	store.EXPECT().CountActiveSigningKeys(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(int64(0), nil)
+	required, err = service.SignatureRequired(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.False(t, required)
+
+	store.EXPECT().CountActiveSigningKeys(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(int64(2), nil)
+	required, err = service.SignatureRequired(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.True(t, required)
+}
+
+func TestSigningKey(t *testing.T) {
+	user := factory.User()
+	key := db.SigningKey{ID: 1, Username: user.Username, Secret: "secret"}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetSigningKey(gomock.Any(), gomock.Eq(key.ID)).Times(2).Return(key, nil)
+	store.EXPECT().GetSigningKey(gomock.Any(), gomock.Eq(int64(2))).Times(1).Return(db.SigningKey{}, db.ErrRecordNotFound)
+	service := newTestService(t, store)
+
+	got, err := service.SigningKey(context.Background(), user.Username, key.ID)
+	require.NoError(t, err)
+	require.Equal(t, key, got)
+
+	// the keys of other users are as unknown as missing ones
+	_, err = service.SigningKey(context.Background(), "mallory", key.ID)
+	require.Equal(t, CodeUnauthenticated, ErrorCode(err))
+
+	_, err = service.SigningKey(context.Background(), user.Username, 2)
+	require.Equal(t, CodeUnauthenticated, ErrorCode(err))
+}
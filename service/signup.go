@@ -0,0 +1,36 @@
+package service
+
+import (
+	"sync"
+	"time"
+)
+
+// The signupLimiter type counts the signups of every client within fixed windows, in memory, so that a
+// client can't register users in bulk. It is a soft limit: each server counts its own signups, and the
+// counts start from 0 again when the server restarts. The CAPTCHA is what stops the bots for good.
+type signupLimiter struct {
+	mu      sync.Mutex
+	start   time.Time
+	signups map[string]int64
+}
+
+func newSignupLimiter() *signupLimiter {
+	return &signupLimiter{signups: make(map[string]int64)}
+}
+
+// The `allow` function counts a signup of the client at `at` unless it already signed up `max` times in
+// the current window, the counts of a window being dropped once it is over.
+func (limiter *signupLimiter) allow(clientIP string, at time.Time, window time.Duration, max int64) bool {
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+
+	if at.Sub(limiter.start) >= window {
+		limiter.start = at
+		limiter.signups = make(map[string]int64)
+	}
+	if limiter.signups[clientIP] >= max {
+		return false
+	}
+	limiter.signups[clientIP]++
+	return true
+}
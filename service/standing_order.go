@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"errors"
+	db "go-backend/db/sqlc"
+	"time"
+)
+
+// The CreateStandingOrderParams type is a standing order set up by a user.
+// @property {string} Owner - the user setting up the standing order, who must be able to use the from
+// account as an owner.
+// @property {int64} FromAccountID - the account the money is sent from on every run.
+// @property {int64} ToAccountID - the account the money is sent to on every run.
+// @property {int64} Amount - the positive amount sent on every run.
+// @property {string} Currency - the currency of the amount, which both accounts must hold.
+// @property {string} Memo - optional free text kept on the transfers.
+// @property {string} Frequency - weekly or monthly.
+// @property {time.Time} StartsAt - optional first run, now when unset.
+type CreateStandingOrderParams struct {
+	Owner         string
+	FromAccountID int64
+	ToAccountID   int64
+	Amount        int64
+	Currency      string
+	Memo          string
+	Frequency     string
+	StartsAt      time.Time
+}
+
+// The CreateStandingOrder function sets up a standing order, which transfers the amount between the
+// accounts every week or month from its first run until it is cancelled. The standing order is checked
+// like a transfer of its owner, and can't await a review or a second approver since its runs are made in
+// the background. A run whose transfer fails, e.g. for a lack of funds, is skipped until the next one.
+func (service *Service) CreateStandingOrder(ctx context.Context, arg CreateStandingOrderParams) (db.StandingOrder, error) {
+	if arg.Amount <= 0 {
+		return db.StandingOrder{}, errorf(CodeInvalidArgument, "amount must be positive, got %d", arg.Amount).withReason(ReasonInvalidAmount)
+	}
+	if arg.Frequency != db.StandingOrderWeekly && arg.Frequency != db.StandingOrderMonthly {
+		return db.StandingOrder{}, errorf(CodeInvalidArgument, "frequency must be %s or %s, got %q", db.StandingOrderWeekly, db.StandingOrderMonthly, arg.Frequency)
+	}
+	if arg.FromAccountID == arg.ToAccountID {
+		return db.StandingOrder{}, errorf(CodeInvalidArgument, "cannot send money from account [%d] to itself", arg.FromAccountID)
+	}
+
+	now := time.Now()
+	startsAt := arg.StartsAt
+	if startsAt.IsZero() {
+		startsAt = now
+	}
+	if startsAt.Before(now.Add(-time.Minute)) {
+		return db.StandingOrder{}, newError(CodeInvalidArgument, errors.New("first run can't be in the past"))
+	}
+
+	err := service.checkDirectTransfer(ctx, CreateTransferParams{
+		Owner:         arg.Owner,
+		FromAccountID: arg.FromAccountID,
+		ToAccountID:   arg.ToAccountID,
+		Amount:        arg.Amount,
+		Currency:      arg.Currency,
+		Memo:          arg.Memo,
+	}, "standing order")
+	if err != nil {
+		return db.StandingOrder{}, err
+	}
+
+	order, err := service.store.CreateStandingOrder(ctx, db.CreateStandingOrderParams{
+		Owner:         arg.Owner,
+		FromAccountID: arg.FromAccountID,
+		ToAccountID:   arg.ToAccountID,
+		Amount:        arg.Amount,
+		Currency:      arg.Currency,
+		Memo:          arg.Memo,
+		Frequency:     arg.Frequency,
+		StartsAt:      startsAt,
+	})
+	if err != nil {
+		return order, storeError(err)
+	}
+
+	return order, nil
+}
+
+// The GetStandingOrder function returns a standing order, provided the user set it up or owns its from
+// account.
+func (service *Service) GetStandingOrder(ctx context.Context, username string, id int64) (db.StandingOrder, error) {
+	order, err := service.store.GetStandingOrder(ctx, id)
+	if err != nil {
+		return order, storeError(err)
+	}
+
+	if order.Owner != username {
+		_, err = service.ownedAccount(ctx, username, order.FromAccountID)
+		if err != nil {
+			return order, err
+		}
+	}
+
+	return order, nil
+}
+
+// The ListStandingOrders function lists the standing orders set up by the owner, oldest first.
+func (service *Service) ListStandingOrders(ctx context.Context, owner string, limit int32, offset int32) ([]db.StandingOrder, error) {
+	orders, err := service.store.ListStandingOrders(ctx, db.ListStandingOrdersParams{
+		Owner:  owner,
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return nil, storeError(err)
+	}
+
+	return orders, nil
+}
+
+// The CancelStandingOrder function cancels an active standing order, which then no longer runs. The user
+// who set it up and the owners of its from account can cancel it.
+func (service *Service) CancelStandingOrder(ctx context.Context, username string, id int64) (db.StandingOrder, error) {
+	_, err := service.GetStandingOrder(ctx, username, id)
+	if err != nil {
+		return db.StandingOrder{}, err
+	}
+
+	order, err := service.store.CancelStandingOrder(ctx, id)
+	if err != nil {
+		// it was cancelled before
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return order, errorf(CodeFailedPrecondition, "standing order [%d] has been cancelled", id).withReason(ReasonStandingOrderCancelled)
+		}
+		return order, storeError(err)
+	}
+
+	return order, nil
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/testutil/factory"
+	"go-backend/util"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateStandingOrder(t *testing.T) {
+	owner := util.RandomOwner()
+	fromAccount := factory.Account(factory.OwnedBy(owner), factory.InCurrency(util.USD))
+	toAccount := factory.Account(factory.InCurrency(util.USD))
+	toAccount.ID = fromAccount.ID + 1
+	startsAt := time.Now().Add(time.Hour)
+
+	arg := CreateStandingOrderParams{
+		Owner:         owner,
+		FromAccountID: fromAccount.ID,
+		ToAccountID:   toAccount.ID,
+		Amount:        100,
+		Currency:      util.USD,
+		Memo:          "rent",
+		Frequency:     db.StandingOrderMonthly,
+		StartsAt:      startsAt,
+	}
+
+	testCases := []struct {
+		name      string
+		arg       func() CreateStandingOrderParams
+		buildStub func(store *mockdb.MockStore)
+		code      *Code
+		reason    string
+	}{
+		{
+			name: "OK",
+			arg:  func() CreateStandingOrderParams { return arg },
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().GetActiveBankParameter(gomock.Any(), gomock.Any()).Times(1).Return(db.BankParameter{}, db.ErrRecordNotFound)
+				store.EXPECT().
+					CreateStandingOrder(gomock.Any(), gomock.Eq(db.CreateStandingOrderParams{
+						Owner:         owner,
+						FromAccountID: fromAccount.ID,
+						ToAccountID:   toAccount.ID,
+						Amount:        100,
+						Currency:      util.USD,
+						Memo:          "rent",
+						Frequency:     db.StandingOrderMonthly,
+						StartsAt:      startsAt,
+					})).
+					Times(1)
+			},
+		},
+		{
+			name: "InvalidFrequency",
+			arg: func() CreateStandingOrderParams {
+				invalid := arg
+				invalid.Frequency = "daily"
+				return invalid
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code: codePtr(CodeInvalidArgument),
+		},
+		{
+			name: "StartsInThePast",
+			arg: func() CreateStandingOrderParams {
+				past := arg
+				past.StartsAt = time.Now().Add(-time.Hour)
+				return past
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code: codePtr(CodeInvalidArgument),
+		},
+		{
+			name: "NotOwner",
+			arg: func() CreateStandingOrderParams {
+				other := arg
+				other.Owner = util.RandomOwner()
+				return other
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(db.AccountMember{}, db.ErrRecordNotFound)
+				store.EXPECT().CreateStandingOrder(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code: codePtr(CodePermissionDenied),
+		},
+		{
+			name: "ApprovalRequired",
+			arg:  func() CreateStandingOrderParams { return arg },
+			buildStub: func(store *mockdb.MockStore) {
+				orgAccount := fromAccount
+				orgAccount.OrgID = 7
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(orgAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().GetActiveBankParameter(gomock.Any(), gomock.Any()).Times(1).Return(db.BankParameter{}, db.ErrRecordNotFound)
+				store.EXPECT().GetOrganization(gomock.Any(), gomock.Eq(int64(7))).Times(1).Return(db.Organization{ID: 7, ApprovalThreshold: 100}, nil)
+				store.EXPECT().CreateStandingOrder(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code:   codePtr(CodeFailedPrecondition),
+			reason: ReasonApprovalRequired,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			_, err := newTestService(t, store).CreateStandingOrder(context.Background(), tc.arg())
+			if tc.code == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.Equal(t, *tc.code, ErrorCode(err))
+			if tc.reason != "" {
+				require.Equal(t, tc.reason, ErrorReason(err))
+			}
+		})
+	}
+}
+
+func TestCancelStandingOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fromAccount := factory.Account()
+	toAccount := factory.Account()
+	order := factory.StandingOrder(fromAccount, toAccount)
+
+	store := mockdb.NewMockStore(ctrl)
+	service := newTestService(t, store)
+	store.EXPECT().GetStandingOrder(gomock.Any(), gomock.Eq(order.ID)).Times(3).Return(order, nil)
+
+	// the owner of the to account can't cancel it
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+	store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(db.AccountMember{}, db.ErrRecordNotFound)
+	_, err := service.CancelStandingOrder(context.Background(), toAccount.Owner, order.ID)
+	require.Equal(t, CodePermissionDenied, ErrorCode(err))
+
+	store.EXPECT().CancelStandingOrder(gomock.Any(), gomock.Eq(order.ID)).Times(1).Return(db.StandingOrder{Status: db.StandingOrderCancelled}, nil)
+	cancelled, err := service.CancelStandingOrder(context.Background(), order.Owner, order.ID)
+	require.NoError(t, err)
+	require.Equal(t, db.StandingOrderCancelled, cancelled.Status)
+
+	store.EXPECT().CancelStandingOrder(gomock.Any(), gomock.Eq(order.ID)).Times(1).Return(db.StandingOrder{}, db.ErrRecordNotFound)
+	_, err = service.CancelStandingOrder(context.Background(), order.Owner, order.ID)
+	require.Equal(t, ReasonStandingOrderCancelled, ErrorReason(err))
+}
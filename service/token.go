@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"go-backend/token"
+	"strings"
+	"time"
+)
+
+// The RenewAccessToken function issues a new access token from a refresh token, provided its session
+// is neither blocked, expired nor idle for longer than the SESSION_IDLE_TIMEOUT config.
+func (service *Service) RenewAccessToken(ctx context.Context, refreshToken string) (string, *token.Payload, error) {
+	refreshPayload, err := service.tokenMaker.VerifyToken(refreshToken)
+	if err != nil {
+		return "", nil, newError(CodeUnauthenticated, err)
+	}
+
+	session, err := service.store.GetSession(ctx, refreshPayload.ID)
+	if err != nil {
+		return "", nil, storeError(err)
+	}
+
+	if session.IsBlocked {
+		return "", nil, newError(CodeUnauthenticated, errors.New("blocked session")).withReason(ReasonSessionBlocked)
+	}
+
+	if session.Username != refreshPayload.Username {
+		return "", nil, newError(CodeUnauthenticated, errors.New("incorrect session user"))
+	}
+
+	if session.RefreshToken != refreshToken {
+		return "", nil, newError(CodeUnauthenticated, errors.New("mismatched session token"))
+	}
+
+	now := service.clock.Now()
+	if now.After(session.ExpiresAt) {
+		return "", nil, newError(CodeUnauthenticated, errors.New("expired session")).withReason(ReasonSessionExpired)
+	}
+
+	if service.config.SessionIdleTimeout > 0 && now.Sub(service.lastSessionUse(session)) > service.config.SessionIdleTimeout {
+		return "", nil, newError(CodeUnauthenticated, errors.New("idle session")).withReason(ReasonSessionIdle)
+	}
+
+	// the access token asserts the claims of the refresh token, so that a read-only session can't renew its
+	// way to more scopes
+	claims := refreshPayload.Claims()
+	claims.SessionID = session.ID
+	accessToken, accessPayload, err := service.tokenMaker.CreateToken(claims, service.config.AccessTokenDuration)
+	if err != nil {
+		return "", nil, newError(CodeInternal, err)
+	}
+
+	service.TouchSession(session.ID)
+	return accessToken, accessPayload, nil
+}
+
+// The IssueAccessToken function issues an access token of a user valid for the duration, which isn't
+// tied to a session and so can't be renewed, e.g. for an operator calling the API as that user or for an
+// integration limited to `scopes`.
+func (service *Service) IssueAccessToken(ctx context.Context, username string, scopes []string, duration time.Duration) (string, *token.Payload, error) {
+	if duration <= 0 {
+		return "", nil, errorf(CodeInvalidArgument, "token duration must be positive, got %s", duration)
+	}
+	err := validateScopes(scopes)
+	if err != nil {
+		return "", nil, err
+	}
+
+	user, err := service.store.GetUser(ctx, username)
+	if err != nil {
+		return "", nil, storeError(err)
+	}
+
+	accessToken, accessPayload, err := service.tokenMaker.CreateToken(token.Claims{
+		Username: user.Username,
+		Role:     user.Role,
+		OrgID:    user.OrgID,
+		Scopes:   scopes,
+	}, duration)
+	if err != nil {
+		return "", nil, newError(CodeInternal, err)
+	}
+
+	return accessToken, accessPayload, nil
+}
+
+// The validateScopes function checks that tokens can be issued with every scope of `scopes`.
+func validateScopes(scopes []string) error {
+	for _, scope := range scopes {
+		if !token.ValidScope(scope) {
+			return errorf(CodeInvalidArgument, "unknown scope %s, expected one of %s", scope, strings.Join(token.Scopes, ", ")).withField("scopes")
+		}
+	}
+	return nil
+}
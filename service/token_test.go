@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/token"
+	"go-backend/util"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIssueAccessToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	service := newTestService(t, store)
+
+	user := db.User{Username: util.RandomOwner()}
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+
+	accessToken, payload, err := service.IssueAccessToken(context.Background(), user.Username, nil, time.Hour)
+	require.NoError(t, err)
+	require.NotEmpty(t, accessToken)
+	require.Equal(t, user.Username, payload.Username)
+	require.Equal(t, uuid.Nil, payload.SessionID)
+	require.WithinDuration(t, time.Now().Add(time.Hour), payload.ExpiredAt, time.Second)
+
+	store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(1).Return(db.User{}, db.ErrRecordNotFound)
+	_, _, err = service.IssueAccessToken(context.Background(), "unknown", nil, time.Hour)
+	require.Equal(t, CodeNotFound, ErrorCode(err))
+
+	_, _, err = service.IssueAccessToken(context.Background(), user.Username, nil, 0)
+	require.Equal(t, CodeInvalidArgument, ErrorCode(err))
+
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+	_, payload, err = service.IssueAccessToken(context.Background(), user.Username, []string{token.ScopeAccountsRead}, time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, []string{token.ScopeAccountsRead}, payload.Scopes)
+
+	_, _, err = service.IssueAccessToken(context.Background(), user.Username, []string{"admin:write"}, time.Hour)
+	require.Equal(t, CodeInvalidArgument, ErrorCode(err))
+	require.Equal(t, "scopes", ErrorField(err))
+}
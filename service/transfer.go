@@ -0,0 +1,447 @@
+package service
+
+import (
+	"context"
+	"errors"
+	db "go-backend/db/sqlc"
+	"go-backend/util"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// The CreateTransferParams type is a transfer requested by a user.
+// @property {string} Owner - the user requesting the transfer, who must own the from account.
+// @property {int64} FromAccountID - the account the money is taken from.
+// @property {string} FromAccountNumber - the number of the account the money is taken from, instead of
+// FromAccountID when it is set.
+// @property {int64} ToAccountID - the account the money is sent to.
+// @property {string} ToAccountNumber - the number of the account the money is sent to, instead of
+// ToAccountID when it is set.
+// @property {int64} BeneficiaryID - the beneficiary of the owner whose account the money is sent to,
+// instead of ToAccountID when it is set.
+// @property {int64} Amount - the positive amount of money transferred.
+// @property {string} Currency - the currency of the amount, which both accounts must hold.
+// @property {string} Memo - optional free text shown to both parties.
+// @property {string} ExternalReference - optional reference of the payment outside the bank, e.g. an
+// invoice number.
+// @property {string} IdempotencyKey - optional key of the request, a transfer queued again with the same
+// key getting the same tracking ID and being made once. A transfer without one isn't queued.
+// @property queued - the queued transfer being made, whose outcome is recorded along with the transfer.
+type CreateTransferParams struct {
+	Owner             string
+	FromAccountID     int64
+	FromAccountNumber string
+	ToAccountID       int64
+	ToAccountNumber   string
+	BeneficiaryID     int64
+	Amount            int64
+	Currency          string
+	Memo              string
+	ExternalReference string
+	IdempotencyKey    string
+	queued            *db.CreateQueuedTransferParams
+}
+
+// MaxBatchTransfers is the largest number of transfers in a batch.
+const MaxBatchTransfers = 100
+
+// The CreateTransferResult type is the outcome of a transfer: its result when it was made, the review it
+// is held for when the screening flagged it, the pending transfer awaiting approval when it is large, or
+// the queued transfer when the database was unavailable.
+type CreateTransferResult struct {
+	Result  db.TransferTxResult
+	Review  *db.TransferReview
+	Pending *db.PendingTransfer
+	Queued  *db.QueuedTransfer
+}
+
+// The CreateTransfer function moves money between two accounts of the same currency, the from account
+// belonging to the owner and the to account being given directly or as a beneficiary, the accounts given
+// directly being identified by their id or their account number. The amount can't exceed the transfer
+// limit in effect, if one was published. A transfer flagged by the screening is held for review instead,
+// its amount being taken from the from account until the review is decided. A transfer of at least the
+// TRANSFER_APPROVAL_THRESHOLD config awaits the approval of its owner or of a banker instead, nothing
+// being taken until it is approved. A transfer from an account of an organization reaching the approval
+// threshold of the organization awaits the approval of a second admin of the organization instead, its
+// owner being unable to confirm it. When the TRANSFER_QUEUE_ENABLED config is set, a transfer with an
+// idempotency key failing as the database can't be reached is queued instead, and made once it is back.
+func (service *Service) CreateTransfer(ctx context.Context, arg CreateTransferParams) (CreateTransferResult, error) {
+	// a transfer is queued with the ids of its accounts, so their numbers can't wait for the database
+	arg, err := service.resolveAccountNumbers(ctx, arg)
+	if err != nil {
+		return CreateTransferResult{}, err
+	}
+
+	result, err := service.createTransfer(ctx, arg)
+	if err != nil && ErrorCode(err) == CodeUnavailable && service.queuesTransfers() {
+		return service.queueTransfer(ctx, arg)
+	}
+
+	return result, err
+}
+
+func (service *Service) createTransfer(ctx context.Context, arg CreateTransferParams) (CreateTransferResult, error) {
+	arg, err := service.resolveBeneficiary(ctx, arg)
+	if err != nil {
+		return CreateTransferResult{}, err
+	}
+
+	fromAccount, err := service.checkTransferAccounts(ctx, arg)
+	if err != nil {
+		return CreateTransferResult{}, err
+	}
+
+	limit, ok, err := service.activeParameter(ctx, db.ParameterTransferLimit)
+	if err != nil {
+		return CreateTransferResult{}, err
+	}
+	if ok && arg.Amount > limit {
+		return CreateTransferResult{}, transferLimitError(arg.Amount, limit)
+	}
+
+	holdReason, err := service.screen(ctx, arg)
+	if err != nil {
+		return CreateTransferResult{}, err
+	}
+	if holdReason != "" {
+		review, err := service.holdTransfer(ctx, arg, holdReason)
+		if err != nil {
+			return CreateTransferResult{}, err
+		}
+		return CreateTransferResult{Review: &review}, nil
+	}
+	approvers, err := service.approvalOrganization(ctx, fromAccount, arg.Amount)
+	if err != nil {
+		return CreateTransferResult{}, err
+	}
+	if approvers.Valid || service.needsApproval(arg) {
+		pending, err := service.pendTransfer(ctx, arg, approvers)
+		if err != nil {
+			return CreateTransferResult{}, err
+		}
+		return CreateTransferResult{Pending: &pending}, nil
+	}
+
+	result, err := service.store.TransferTx(ctx, service.newTransferTxParams(arg))
+	if err != nil {
+		return CreateTransferResult{Result: result}, storeError(err)
+	}
+
+	return CreateTransferResult{Result: result}, nil
+}
+
+// The BatchTransferOutcome type is the outcome of a transfer of a batch: its result when it was made, the
+// review it is held for when the screening flagged it, the pending transfer awaiting approval when it is
+// large, or the error that prevented it.
+type BatchTransferOutcome struct {
+	Result  db.TransferTxResult
+	Review  *db.TransferReview
+	Pending *db.PendingTransfer
+	Err     error
+}
+
+// The CreateBatchTransfer function makes a batch of transfers from accounts of the owner, e.g. the
+// salaries of a payroll, in one transaction. Each transfer is checked like a single one, and a transfer
+// that is rejected or fails doesn't prevent the others from being made. The outcomes are in the order
+// of the transfers, and an error is only returned when the batch as a whole failed. Transfers flagged by
+// the screening are held for review and large transfers await approval, each outside of the transaction
+// of the batch.
+func (service *Service) CreateBatchTransfer(ctx context.Context, owner string, transfers []CreateTransferParams) ([]BatchTransferOutcome, error) {
+	if len(transfers) == 0 || len(transfers) > MaxBatchTransfers {
+		return nil, errorf(CodeInvalidArgument, "a batch must have between 1 and %d transfers, got %d", MaxBatchTransfers, len(transfers))
+	}
+
+	limit, hasLimit, err := service.activeParameter(ctx, db.ParameterTransferLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	outcomes := make([]BatchTransferOutcome, len(transfers))
+	var params []db.TransferTxParams
+	var indexes []int
+	for i, arg := range transfers {
+		arg.Owner = owner
+		var fromAccount db.Account
+		arg, err := service.resolveAccountNumbers(ctx, arg)
+		if err == nil {
+			arg, err = service.resolveBeneficiary(ctx, arg)
+		}
+		if err == nil {
+			fromAccount, err = service.checkTransferAccounts(ctx, arg)
+		}
+		if err == nil && hasLimit && arg.Amount > limit {
+			err = transferLimitError(arg.Amount, limit)
+		}
+		if err != nil {
+			outcomes[i].Err = err
+			continue
+		}
+
+		holdReason, err := service.screen(ctx, arg)
+		if err != nil {
+			outcomes[i].Err = err
+			continue
+		}
+		if holdReason != "" {
+			review, err := service.holdTransfer(ctx, arg, holdReason)
+			if err != nil {
+				outcomes[i].Err = err
+				continue
+			}
+			outcomes[i].Review = &review
+			continue
+		}
+		approvers, err := service.approvalOrganization(ctx, fromAccount, arg.Amount)
+		if err != nil {
+			outcomes[i].Err = err
+			continue
+		}
+		if approvers.Valid || service.needsApproval(arg) {
+			pending, err := service.pendTransfer(ctx, arg, approvers)
+			if err != nil {
+				outcomes[i].Err = err
+				continue
+			}
+			outcomes[i].Pending = &pending
+			continue
+		}
+
+		params = append(params, service.newTransferTxParams(arg))
+		indexes = append(indexes, i)
+	}
+
+	if len(params) == 0 {
+		return outcomes, nil
+	}
+
+	items, err := service.store.BatchTransferTx(ctx, db.BatchTransferTxParams{Transfers: params})
+	if err != nil {
+		return nil, storeError(err)
+	}
+
+	for j, item := range items {
+		outcome := &outcomes[indexes[j]]
+		outcome.Result = item.Result
+		if item.Err != nil {
+			outcome.Err = storeError(item.Err)
+		}
+	}
+
+	return outcomes, nil
+}
+
+// The resolveAccountNumbers function sends a transfer between accounts given by their number between the
+// accounts with these numbers.
+func (service *Service) resolveAccountNumbers(ctx context.Context, arg CreateTransferParams) (CreateTransferParams, error) {
+	if arg.FromAccountNumber != "" {
+		account, err := service.accountByNumber(ctx, arg.FromAccountNumber)
+		if err != nil {
+			return arg, err
+		}
+		arg.FromAccountID = account.ID
+	}
+
+	if arg.ToAccountNumber != "" {
+		account, err := service.accountByNumber(ctx, arg.ToAccountNumber)
+		if err != nil {
+			return arg, err
+		}
+		arg.ToAccountID = account.ID
+	}
+
+	return arg, nil
+}
+
+// The accountByNumber function returns the account with the number, whose check digits must be right.
+func (service *Service) accountByNumber(ctx context.Context, number string) (db.Account, error) {
+	if !util.ValidAccountNumber(number) {
+		return db.Account{}, errorf(CodeInvalidArgument, "invalid account number %s", number)
+	}
+
+	account, err := service.store.GetAccountByNumber(ctx, number)
+	if err != nil {
+		return db.Account{}, storeError(err)
+	}
+	return account, nil
+}
+
+// The resolveBeneficiary function sends a transfer to a beneficiary to the account of the beneficiary,
+// which must have been saved by the owner.
+func (service *Service) resolveBeneficiary(ctx context.Context, arg CreateTransferParams) (CreateTransferParams, error) {
+	if arg.BeneficiaryID == 0 {
+		return arg, nil
+	}
+
+	beneficiary, err := service.GetBeneficiary(ctx, arg.Owner, arg.BeneficiaryID)
+	if err != nil {
+		return arg, err
+	}
+
+	arg.ToAccountID = beneficiary.AccountID
+	return arg, nil
+}
+
+// The checkTransferAccounts function checks that the amount is positive and that both accounts exist and
+// hold the currency, the from account belonging to the owner, or being shared with them as an owner, and
+// the to account not being a system account. It returns the from account.
+func (service *Service) checkTransferAccounts(ctx context.Context, arg CreateTransferParams) (db.Account, error) {
+	if arg.Amount <= 0 {
+		return db.Account{}, errorf(CodeInvalidArgument, "amount must be positive, got %d", arg.Amount).withReason(ReasonInvalidAmount)
+	}
+
+	fromAccount, err := service.validAccount(ctx, arg.FromAccountID, arg.Currency)
+	if err != nil {
+		return fromAccount, err
+	}
+
+	role, err := db.AccountRole(ctx, service.store, fromAccount, arg.Owner)
+	if err != nil {
+		return fromAccount, storeError(err)
+	}
+	if role != db.AccountRoleOwner {
+		return fromAccount, newError(CodePermissionDenied, errors.New("from account doesn't belong to authenticated user"))
+	}
+
+	toAccount, err := service.validAccount(ctx, arg.ToAccountID, arg.Currency)
+	if err != nil {
+		return fromAccount, err
+	}
+
+	// only the transactions of the bank post to its system accounts
+	if db.IsSystemAccount(toAccount) {
+		return fromAccount, newError(CodePermissionDenied, db.ErrSystemAccount)
+	}
+	return fromAccount, nil
+}
+
+// The checkDirectTransfer function checks a transfer made for a flow other than a transfer, e.g. the
+// pull of a mandate or the payment of a link, like a transfer: its accounts, the transfer limit, the
+// screening and the approval of a second admin of the organization of the from account. These flows
+// can't hold a transfer for review nor pend it, so a transfer that would be held or pended fails, the
+// payer having to send it as a transfer instead. `what` names the payment in the errors.
+func (service *Service) checkDirectTransfer(ctx context.Context, arg CreateTransferParams, what string) error {
+	fromAccount, err := service.checkTransferAccounts(ctx, arg)
+	if err != nil {
+		return err
+	}
+
+	limit, ok, err := service.activeParameter(ctx, db.ParameterTransferLimit)
+	if err != nil {
+		return err
+	}
+	if ok && arg.Amount > limit {
+		return transferLimitError(arg.Amount, limit)
+	}
+
+	holdReason, err := service.screen(ctx, arg)
+	if err != nil {
+		return err
+	}
+	if holdReason != "" {
+		return errorf(CodeFailedPrecondition, "%s must be reviewed: %s", what, holdReason).withReason(ReasonReviewRequired)
+	}
+
+	return service.requireNoApproval(ctx, fromAccount, arg.Amount, what)
+}
+
+func transferLimitError(amount int64, limit int64) error {
+	return errorf(CodeInvalidArgument, "amount %d exceeds the transfer limit of %d", amount, limit).withReason(ReasonTransferLimitExceeded)
+}
+
+// The newTransferTxParams function returns the params of the transfer made for `arg`, charged the fee
+// active now by the clock of the service.
+func (service *Service) newTransferTxParams(arg CreateTransferParams) db.TransferTxParams {
+	return db.TransferTxParams{
+		FromAccountID:     arg.FromAccountID,
+		ToAccountID:       arg.ToAccountID,
+		Amount:            arg.Amount,
+		Memo:              arg.Memo,
+		ExternalReference: arg.ExternalReference,
+		Currency:          arg.Currency,
+		Queued:            arg.queued,
+		ChargeFee:         true,
+		Now:               service.clock.Now(),
+	}
+}
+
+// The validAccount function checks that the account exists, is open and holds the currency.
+func (service *Service) validAccount(ctx context.Context, accountID int64, currency string) (db.Account, error) {
+	account, err := service.store.GetAccount(ctx, accountID)
+	if err != nil {
+		return account, storeError(err)
+	}
+
+	if account.ClosedAt.Valid {
+		return account, errorf(CodeFailedPrecondition, "account [%d] is closed", accountID).withReason(ReasonAccountClosed)
+	}
+
+	if account.Currency != currency {
+		return account, errorf(CodeInvalidArgument, "account [%d] currency mismatch: %s vs %s", accountID, account.Currency, currency).withReason(ReasonCurrencyMismatch)
+	}
+
+	return account, nil
+}
+
+// The ListTransfersParams type holds the filters and page of a transfer listing.
+// @property {string} Owner - the user requesting the listing, who must own the account.
+// @property {int64} AccountID - the account whose sent and received transfers are listed.
+// @property {string} ExternalReference - only list the transfers with this reference when it is set.
+// @property {string} Memo - only list the transfers whose memo contains this text when it is set.
+type ListTransfersParams struct {
+	Owner             string
+	AccountID         int64
+	ExternalReference string
+	Memo              string
+	Limit             int32
+	Offset            int32
+}
+
+// The ListTransfers function lists the transfers sent or received by an account belonging to the
+// owner, oldest first, with their counterparty.
+func (service *Service) ListTransfers(ctx context.Context, arg ListTransfersParams) ([]db.SearchTransfersRow, error) {
+	account, err := service.GetAccount(ctx, arg.Owner, arg.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	transfers, err := service.store.SearchTransfers(ctx, db.SearchTransfersParams{
+		AccountID:         account.ID,
+		ExternalReference: pgtype.Text{String: arg.ExternalReference, Valid: arg.ExternalReference != ""},
+		Memo:              pgtype.Text{String: arg.Memo, Valid: arg.Memo != ""},
+		RowLimit:          arg.Limit,
+		RowOffset:         arg.Offset,
+	})
+	if err != nil {
+		return nil, storeError(err)
+	}
+
+	return transfers, nil
+}
+
+// The ListTransfersBefore function lists the transfers of an account belonging to the owner like
+// ListTransfers, newest first and a page at a time by their id: the transfers made before the cursor, the
+// id of the last transfer of the previous page, or the newest ones when the cursor is 0. The offset of
+// the params is ignored.
+func (service *Service) ListTransfersBefore(ctx context.Context, arg ListTransfersParams, cursor int64) ([]db.SearchTransfersRow, error) {
+	account, err := service.GetAccount(ctx, arg.Owner, arg.AccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := service.store.SearchTransfersBefore(ctx, db.SearchTransfersBeforeParams{
+		AccountID:         account.ID,
+		ExternalReference: pgtype.Text{String: arg.ExternalReference, Valid: arg.ExternalReference != ""},
+		Memo:              pgtype.Text{String: arg.Memo, Valid: arg.Memo != ""},
+		Cursor:            pgtype.Int8{Int64: cursor, Valid: cursor != 0},
+		RowLimit:          arg.Limit,
+	})
+	if err != nil {
+		return nil, storeError(err)
+	}
+
+	transfers := make([]db.SearchTransfersRow, 0, len(rows))
+	for _, row := range rows {
+		transfers = append(transfers, db.SearchTransfersRow(row))
+	}
+	return transfers, nil
+}
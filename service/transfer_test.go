@@ -0,0 +1,355 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"go-backend/clock"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/testutil/factory"
+	"go-backend/util"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateTransfer(t *testing.T) {
+	owner := util.RandomOwner()
+	fromAccount := factory.Account(factory.OwnedBy(owner), factory.InCurrency(util.USD))
+	toAccount := factory.Account(factory.OwnedBy(util.RandomOwner()), factory.InCurrency(util.USD))
+	toAccount.ID = fromAccount.ID + 1
+	otherAccount := factory.Account(factory.OwnedBy(util.RandomOwner()), factory.InCurrency(util.EUR))
+	otherAccount.ID = fromAccount.ID + 2
+	systemAccount := factory.Account(factory.OwnedBy(db.SystemOwner), factory.InCurrency(util.USD))
+	systemAccount.ID = fromAccount.ID + 3
+	closedAccount := factory.Account(factory.OwnedBy(util.RandomOwner()), factory.InCurrency(util.USD))
+	closedAccount.ID = fromAccount.ID + 4
+	closedAccount.ClosedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+	now := time.Now()
+
+	testCases := []struct {
+		name      string
+		arg       CreateTransferParams
+		buildStub func(store *mockdb.MockStore)
+		code      *Code
+	}{
+		{
+			name: "OK",
+			arg: CreateTransferParams{
+				Owner:         owner,
+				FromAccountID: fromAccount.ID,
+				ToAccountID:   toAccount.ID,
+				Amount:        10,
+				Currency:      util.USD,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().GetActiveBankParameter(gomock.Any(), gomock.Any()).Times(1).Return(db.BankParameter{}, db.ErrRecordNotFound)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Eq(db.TransferTxParams{
+					FromAccountID: fromAccount.ID,
+					ToAccountID:   toAccount.ID,
+					Amount:        10,
+					Currency:      util.USD,
+					ChargeFee:     true,
+					Now:           now,
+				})).Times(1).Return(db.TransferTxResult{}, nil)
+			},
+		},
+		{
+			name: "NegativeAmount",
+			arg: CreateTransferParams{
+				Owner:         owner,
+				FromAccountID: fromAccount.ID,
+				ToAccountID:   toAccount.ID,
+				Amount:        -10,
+				Currency:      util.USD,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code: codePtr(CodeInvalidArgument),
+		},
+		{
+			name: "NotOwner",
+			arg: CreateTransferParams{
+				Owner:         util.RandomOwner(),
+				FromAccountID: fromAccount.ID,
+				ToAccountID:   toAccount.ID,
+				Amount:        10,
+				Currency:      util.USD,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccountMember(gomock.Any(), gomock.Any()).Times(1).Return(db.AccountMember{}, db.ErrRecordNotFound)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code: codePtr(CodePermissionDenied),
+		},
+		{
+			name: "ToSystemAccount",
+			arg: CreateTransferParams{
+				Owner:         owner,
+				FromAccountID: fromAccount.ID,
+				ToAccountID:   systemAccount.ID,
+				Amount:        10,
+				Currency:      util.USD,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(systemAccount.ID)).Times(1).Return(systemAccount, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code: codePtr(CodePermissionDenied),
+		},
+		{
+			name: "ToClosedAccount",
+			arg: CreateTransferParams{
+				Owner:         owner,
+				FromAccountID: fromAccount.ID,
+				ToAccountID:   closedAccount.ID,
+				Amount:        10,
+				Currency:      util.USD,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(closedAccount.ID)).Times(1).Return(closedAccount, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code: codePtr(CodeFailedPrecondition),
+		},
+		{
+			name: "CurrencyMismatch",
+			arg: CreateTransferParams{
+				Owner:         owner,
+				FromAccountID: fromAccount.ID,
+				ToAccountID:   otherAccount.ID,
+				Amount:        10,
+				Currency:      util.USD,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(otherAccount.ID)).Times(1).Return(otherAccount, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code: codePtr(CodeInvalidArgument),
+		},
+		{
+			name: "AccountNotFound",
+			arg: CreateTransferParams{
+				Owner:         owner,
+				FromAccountID: fromAccount.ID,
+				ToAccountID:   toAccount.ID,
+				Amount:        10,
+				Currency:      util.USD,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(db.Account{}, db.ErrRecordNotFound)
+			},
+			code: codePtr(CodeNotFound),
+		},
+		{
+			name: "TransferTxError",
+			arg: CreateTransferParams{
+				Owner:         owner,
+				FromAccountID: fromAccount.ID,
+				ToAccountID:   toAccount.ID,
+				Amount:        10,
+				Currency:      util.USD,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().GetActiveBankParameter(gomock.Any(), gomock.Any()).Times(1).Return(db.BankParameter{}, db.ErrRecordNotFound)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(1).Return(db.TransferTxResult{}, sql.ErrTxDone)
+			},
+			code: codePtr(CodeInternal),
+		},
+		{
+			name: "TransferLimitExceeded",
+			arg: CreateTransferParams{
+				Owner:         owner,
+				FromAccountID: fromAccount.ID,
+				ToAccountID:   toAccount.ID,
+				Amount:        10,
+				Currency:      util.USD,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+				store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+				store.EXPECT().GetActiveBankParameter(gomock.Any(), gomock.Any()).Times(1).
+					Return(db.BankParameter{Name: db.ParameterTransferLimit, Value: 5}, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			code: codePtr(CodeInvalidArgument),
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			service := newTestService(t, store)
+			service.SetClock(clock.NewFake(now))
+
+			_, err := service.CreateTransfer(context.Background(), tc.arg)
+			if tc.code == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			require.Equal(t, *tc.code, ErrorCode(err))
+		})
+	}
+}
+
+func TestCreateTransferByAccountNumber(t *testing.T) {
+	owner := util.RandomOwner()
+	fromAccount := factory.Account(factory.OwnedBy(owner), factory.InCurrency(util.USD))
+	toAccount := factory.Account(factory.OwnedBy(util.RandomOwner()), factory.InCurrency(util.USD))
+	toAccount.ID = fromAccount.ID + 1
+	toAccount.AccountNumber = util.AccountNumber(toAccount.ID)
+	now := time.Now()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetAccountByNumber(gomock.Any(), gomock.Eq(toAccount.AccountNumber)).Times(1).Return(toAccount, nil)
+	store.EXPECT().GetAccountByNumber(gomock.Any(), gomock.Eq(util.AccountNumber(toAccount.ID+1))).Times(1).Return(db.Account{}, db.ErrRecordNotFound)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+	store.EXPECT().GetActiveBankParameter(gomock.Any(), gomock.Any()).Times(1).Return(db.BankParameter{}, db.ErrRecordNotFound)
+	store.EXPECT().
+		TransferTx(gomock.Any(), gomock.Eq(db.TransferTxParams{FromAccountID: fromAccount.ID, ToAccountID: toAccount.ID, Amount: 10, Currency: util.USD, ChargeFee: true, Now: now})).
+		Times(1)
+
+	service := newTestService(t, store)
+	service.SetClock(clock.NewFake(now))
+	arg := CreateTransferParams{Owner: owner, FromAccountID: fromAccount.ID, ToAccountNumber: toAccount.AccountNumber, Amount: 10, Currency: util.USD}
+	_, err := service.CreateTransfer(context.Background(), arg)
+	require.NoError(t, err)
+
+	// an account number nobody has
+	arg.ToAccountNumber = util.AccountNumber(toAccount.ID + 1)
+	_, err = service.CreateTransfer(context.Background(), arg)
+	require.Equal(t, CodeNotFound, ErrorCode(err))
+
+	// a mistyped account number, caught by its check digits
+	arg.ToAccountNumber = toAccount.AccountNumber[:4] + "BANK" + "9" + toAccount.AccountNumber[9:]
+	_, err = service.CreateTransfer(context.Background(), arg)
+	require.Equal(t, CodeInvalidArgument, ErrorCode(err))
+}
+
+func TestCreateBatchTransfer(t *testing.T) {
+	owner := util.RandomOwner()
+	fromAccount := factory.Account(factory.OwnedBy(owner), factory.InCurrency(util.USD))
+	toAccount := factory.Account(factory.OwnedBy(util.RandomOwner()), factory.InCurrency(util.USD))
+	toAccount.ID = fromAccount.ID + 1
+	otherAccount := factory.Account(factory.OwnedBy(util.RandomOwner()), factory.InCurrency(util.EUR))
+	otherAccount.ID = fromAccount.ID + 2
+
+	valid := CreateTransferParams{FromAccountID: fromAccount.ID, ToAccountID: toAccount.ID, Amount: 10, Currency: util.USD}
+	mismatch := CreateTransferParams{FromAccountID: fromAccount.ID, ToAccountID: otherAccount.ID, Amount: 10, Currency: util.USD}
+	overLimit := CreateTransferParams{FromAccountID: fromAccount.ID, ToAccountID: toAccount.ID, Amount: 100, Currency: util.USD}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetActiveBankParameter(gomock.Any(), gomock.Any()).Times(1).
+		Return(db.BankParameter{Name: db.ParameterTransferLimit, Value: 50}, nil)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(4).Return(fromAccount, nil)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(3).Return(toAccount, nil)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(otherAccount.ID)).Times(1).Return(otherAccount, nil)
+
+	service := newTestService(t, store)
+	service.SetClock(clock.NewFake(time.Now()))
+
+	// only the transfers passing the checks reach the store, which fails the second of them
+	params := service.newTransferTxParams(valid)
+	store.EXPECT().BatchTransferTx(gomock.Any(), gomock.Eq(db.BatchTransferTxParams{
+		Transfers: []db.TransferTxParams{params, params},
+	})).Times(1).Return([]db.BatchTransferTxItem{
+		{Result: db.TransferTxResult{Transfer: db.Transfer{ID: 1}}},
+		{Err: sql.ErrTxDone},
+	}, nil)
+
+	outcomes, err := service.CreateBatchTransfer(context.Background(), owner, []CreateTransferParams{valid, mismatch, overLimit, valid})
+	require.NoError(t, err)
+	require.Len(t, outcomes, 4)
+
+	require.NoError(t, outcomes[0].Err)
+	require.Equal(t, int64(1), outcomes[0].Result.Transfer.ID)
+	require.Equal(t, ReasonCurrencyMismatch, ErrorReason(outcomes[1].Err))
+	require.Equal(t, ReasonTransferLimitExceeded, ErrorReason(outcomes[2].Err))
+	require.Error(t, outcomes[3].Err)
+	require.Equal(t, CodeInternal, ErrorCode(outcomes[3].Err))
+}
+
+func TestCreateBatchTransferToBeneficiary(t *testing.T) {
+	owner := util.RandomOwner()
+	fromAccount := factory.Account(factory.OwnedBy(owner), factory.InCurrency(util.USD))
+	toAccount := factory.Account(factory.InCurrency(util.USD))
+	toAccount.ID = fromAccount.ID + 1
+	beneficiary := factory.Beneficiary(factory.SavedBy(owner), func(beneficiary *db.Beneficiary) {
+		beneficiary.AccountID = toAccount.ID
+	})
+	otherBeneficiary := factory.Beneficiary()
+	otherBeneficiary.ID = beneficiary.ID + 1
+	now := time.Now()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetActiveBankParameter(gomock.Any(), gomock.Any()).Times(1).Return(db.BankParameter{}, db.ErrRecordNotFound)
+	store.EXPECT().GetBeneficiary(gomock.Any(), gomock.Eq(beneficiary.ID)).Times(1).Return(beneficiary, nil)
+	store.EXPECT().GetBeneficiary(gomock.Any(), gomock.Eq(otherBeneficiary.ID)).Times(1).Return(otherBeneficiary, nil)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(fromAccount.ID)).Times(1).Return(fromAccount, nil)
+	store.EXPECT().GetAccount(gomock.Any(), gomock.Eq(toAccount.ID)).Times(1).Return(toAccount, nil)
+
+	// the transfer to the beneficiary is sent to its account
+	store.EXPECT().BatchTransferTx(gomock.Any(), gomock.Eq(db.BatchTransferTxParams{
+		Transfers: []db.TransferTxParams{{FromAccountID: fromAccount.ID, ToAccountID: toAccount.ID, Amount: 10, Currency: util.USD, ChargeFee: true, Now: now}},
+	})).Times(1).Return([]db.BatchTransferTxItem{{}}, nil)
+
+	service := newTestService(t, store)
+	service.SetClock(clock.NewFake(now))
+	outcomes, err := service.CreateBatchTransfer(context.Background(), owner, []CreateTransferParams{
+		{FromAccountID: fromAccount.ID, BeneficiaryID: beneficiary.ID, Amount: 10, Currency: util.USD},
+		{FromAccountID: fromAccount.ID, BeneficiaryID: otherBeneficiary.ID, Amount: 10, Currency: util.USD},
+	})
+	require.NoError(t, err)
+	require.Len(t, outcomes, 2)
+	require.NoError(t, outcomes[0].Err)
+	require.Equal(t, CodePermissionDenied, ErrorCode(outcomes[1].Err))
+}
+
+func TestCreateBatchTransferSize(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().BatchTransferTx(gomock.Any(), gomock.Any()).Times(0)
+	service := newTestService(t, store)
+
+	_, err := service.CreateBatchTransfer(context.Background(), util.RandomOwner(), nil)
+	require.Equal(t, CodeInvalidArgument, ErrorCode(err))
+
+	_, err = service.CreateBatchTransfer(context.Background(), util.RandomOwner(), make([]CreateTransferParams, MaxBatchTransfers+1))
+	require.Equal(t, CodeInvalidArgument, ErrorCode(err))
+}
+
+func codePtr(code Code) *Code {
+	return &code
+}
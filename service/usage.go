@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"errors"
+	db "go-backend/db/sqlc"
+	"go-backend/usage"
+	"time"
+)
+
+// The APIUsage type is the consumption of the API by a user in the current period, a calendar month.
+// @property Plan - the API plan of the user, empty for the users without one, whose calls are counted
+// but not limited.
+// @property Quota - the calls the plan allows per period, 0 when the calls aren't limited.
+// @property ResetsAt - when the period is over and the calls are counted from 0 again.
+type APIUsage struct {
+	Period   string
+	Calls    int64
+	Plan     string
+	Quota    int64
+	ResetsAt time.Time
+}
+
+// The `Remaining` function returns the calls the user can still make in the period, 0 once the quota is
+// used up.
+func (apiUsage APIUsage) Remaining() int64 {
+	if apiUsage.Calls >= apiUsage.Quota {
+		return 0
+	}
+	return apiUsage.Quota - apiUsage.Calls
+}
+
+// The SetUsageMeter function counts the calls of the users with `meter`, e.g. in Redis so that the
+// counts add up across the replicas of the server.
+func (service *Service) SetUsageMeter(meter usage.Meter) {
+	service.meter = meter
+}
+
+// The SetAPIPlans function sets the monthly quotas of calls of the API plans users can be given, by plan.
+func (service *Service) SetAPIPlans(plans map[string]int64) {
+	service.plans = plans
+}
+
+// The RecordAPICall function counts a call of the user to the API and returns their usage including it.
+// The call is rejected once the user made more calls than the quota of their plan allows in the period.
+func (service *Service) RecordAPICall(ctx context.Context, username string) (APIUsage, error) {
+	now := time.Now()
+	calls, err := service.meter.Increment(ctx, username, now)
+	if err != nil {
+		return APIUsage{}, newError(CodeUnavailable, err)
+	}
+
+	apiUsage, err := service.apiUsage(ctx, username, now, calls)
+	if err != nil {
+		return APIUsage{}, err
+	}
+	if apiUsage.Quota > 0 && apiUsage.Calls > apiUsage.Quota {
+		return apiUsage, errorf(CodeResourceExhausted, "the quota of the %s plan, %d calls a month, is used up until %s", apiUsage.Plan, apiUsage.Quota, apiUsage.ResetsAt.Format(time.RFC3339)).withReason(ReasonQuotaExceeded)
+	}
+	return apiUsage, nil
+}
+
+// The GetAPIUsage function returns the usage of the API by the user in the current period.
+func (service *Service) GetAPIUsage(ctx context.Context, username string) (APIUsage, error) {
+	now := time.Now()
+	calls, err := service.meter.Count(ctx, username, now)
+	if err != nil {
+		return APIUsage{}, newError(CodeUnavailable, err)
+	}
+	return service.apiUsage(ctx, username, now, calls)
+}
+
+// The apiUsage function returns the usage of the user given their calls in the period of `at`, along
+// with their plan. The plans are only looked up when some are configured.
+func (service *Service) apiUsage(ctx context.Context, username string, at time.Time, calls int64) (APIUsage, error) {
+	apiUsage := APIUsage{
+		Period:   usage.Period(at),
+		Calls:    calls,
+		ResetsAt: usage.PeriodEnd(at),
+	}
+	if len(service.plans) == 0 {
+		return apiUsage, nil
+	}
+
+	plan, err := service.store.GetUserAPIPlan(ctx, username)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return apiUsage, nil
+		}
+		return APIUsage{}, storeError(err)
+	}
+
+	// the users of a plan no longer configured aren't limited until they are given another
+	apiUsage.Plan = plan.Plan
+	apiUsage.Quota = service.plans[plan.Plan]
+	return apiUsage, nil
+}
+
+// The SetAPIPlan function gives the user an API plan, holding their calls to its quota from then on.
+func (service *Service) SetAPIPlan(ctx context.Context, username string, plan string) (db.UserApiPlan, error) {
+	if _, ok := service.plans[plan]; !ok {
+		return db.UserApiPlan{}, errorf(CodeInvalidArgument, "unknown API plan %s", plan).withField("plan")
+	}
+
+	_, err := service.store.GetUser(ctx, username)
+	if err != nil {
+		return db.UserApiPlan{}, storeError(err)
+	}
+
+	userPlan, err := service.store.UpsertUserAPIPlan(ctx, db.UpsertUserAPIPlanParams{
+		Username: username,
+		Plan:     plan,
+	})
+	if err != nil {
+		return db.UserApiPlan{}, storeError(err)
+	}
+	return userPlan, nil
+}
+
+// The RemoveAPIPlan function takes the API plan of the user away, their calls no longer being limited.
+func (service *Service) RemoveAPIPlan(ctx context.Context, username string) error {
+	err := service.store.DeleteUserAPIPlan(ctx, username)
+	if err != nil {
+		return storeError(err)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/testutil/factory"
+	"go-backend/usage"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordAPICall(t *testing.T) {
+	user := factory.User()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		GetUserAPIPlan(gomock.Any(), gomock.Eq(user.Username)).
+		Times(3).
+		Return(db.UserApiPlan{Username: user.Username, Plan: "basic"}, nil)
+
+	service := newTestService(t, store)
+	service.SetAPIPlans(map[string]int64{"basic": 2})
+
+	for i := int64(1); i <= 2; i++ {
+		apiUsage, err := service.RecordAPICall(context.Background(), user.Username)
+		require.NoError(t, err)
+		require.Equal(t, i, apiUsage.Calls)
+		require.Equal(t, "basic", apiUsage.Plan)
+		require.Equal(t, 2-i, apiUsage.Remaining())
+		require.Equal(t, usage.Period(time.Now()), apiUsage.Period)
+	}
+
+	apiUsage, err := service.RecordAPICall(context.Background(), user.Username)
+	require.Equal(t, CodeResourceExhausted, ErrorCode(err))
+	require.Equal(t, ReasonQuotaExceeded, ErrorReason(err))
+	require.Equal(t, int64(3), apiUsage.Calls)
+	require.Zero(t, apiUsage.Remaining())
+}
+
+func TestRecordAPICallWithoutPlan(t *testing.T) {
+	user := factory.User()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// the plans aren't looked up when none is configured
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetUserAPIPlan(gomock.Any(), gomock.Any()).Times(0)
+	service := newTestService(t, store)
+
+	for i := 0; i < 3; i++ {
+		_, err := service.RecordAPICall(context.Background(), user.Username)
+		require.NoError(t, err)
+	}
+
+	apiUsage, err := service.GetAPIUsage(context.Background(), user.Username)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), apiUsage.Calls)
+	require.Empty(t, apiUsage.Plan)
+	require.Zero(t, apiUsage.Quota)
+
+	// the users without a plan aren't limited when plans are configured
+	store.EXPECT().GetUserAPIPlan(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(db.UserApiPlan{}, db.ErrRecordNotFound)
+	service.SetAPIPlans(map[string]int64{"basic": 1})
+	_, err = service.RecordAPICall(context.Background(), user.Username)
+	require.NoError(t, err)
+}
+
+func TestSetAPIPlan(t *testing.T) {
+	user := factory.User()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+	store.EXPECT().
+		UpsertUserAPIPlan(gomock.Any(), gomock.Eq(db.UpsertUserAPIPlanParams{Username: user.Username, Plan: "pro"})).
+		Times(1).
+		Return(db.UserApiPlan{Username: user.Username, Plan: "pro"}, nil)
+
+	service := newTestService(t, store)
+	service.SetAPIPlans(map[string]int64{"basic": 10000, "pro": 1000000})
+
+	plan, err := service.SetAPIPlan(context.Background(), user.Username, "pro")
+	require.NoError(t, err)
+	require.Equal(t, "pro", plan.Plan)
+
+	_, err = service.SetAPIPlan(context.Background(), user.Username, "enterprise")
+	require.Equal(t, CodeInvalidArgument, ErrorCode(err))
+	require.Equal(t, "plan", ErrorField(err))
+}
@@ -0,0 +1,330 @@
+package service
+
+import (
+	"context"
+	"errors"
+	db "go-backend/db/sqlc"
+	"go-backend/token"
+	"go-backend/util"
+	"strings"
+	"time"
+)
+
+// AuthMethodPassword is the token.MetadataAuthMethod of the tokens of the users logging in with their
+// password, those logging in with an identity provider having the name of the provider.
+const AuthMethodPassword = "password"
+
+// The CreateUserParams type is the registration of a new user.
+// @property {string} Organization - the slug of the organization the user signs up to, the default one
+// when empty.
+type CreateUserParams struct {
+	Username     string
+	Password     string
+	FullName     string
+	Email        string
+	Organization string
+	ClientIP     string
+}
+
+// The CreateUser function registers a user, storing a hash of the password. Registering the same user
+// again returns it, so that clients can retry a registration whose response was lost, while a username
+// or email taken by another user fails with the reason naming the field. A client can't register more
+// than SIGNUP_MAX_PER_CLIENT users within the SIGNUP_WINDOW config, a soft limit counted by each server.
+func (service *Service) CreateUser(ctx context.Context, arg CreateUserParams) (db.User, error) {
+	if arg.ClientIP != "" && service.config.SignupMaxPerClient > 0 {
+		if !service.signups.allow(arg.ClientIP, service.clock.Now(), service.config.SignupWindow, service.config.SignupMaxPerClient) {
+			return db.User{}, errorf(CodeResourceExhausted, "too many signups from this client, try again later").withReason(ReasonTooManySignups)
+		}
+	}
+
+	orgID := db.DefaultOrganizationID
+	if arg.Organization != "" {
+		organization, err := service.store.GetOrganizationBySlug(ctx, arg.Organization)
+		if err != nil {
+			if errors.Is(err, db.ErrRecordNotFound) {
+				return db.User{}, errorf(CodeInvalidArgument, "organization %s doesn't exist", arg.Organization).withField("organization")
+			}
+			return db.User{}, storeError(err)
+		}
+		orgID = organization.ID
+	}
+
+	hashedPassword, err := util.HashPassword(arg.Password)
+	if err != nil {
+		return db.User{}, newError(CodeInternal, err)
+	}
+
+	user, err := service.store.CreateUser(ctx, db.CreateUserParams{
+		Username:       arg.Username,
+		HashedPassword: hashedPassword,
+		FullName:       arg.FullName,
+		Email:          arg.Email,
+		OrgID:          orgID,
+	})
+	if err != nil {
+		if errors.Is(db.TranslateError(err), db.ErrUniqueViolation) {
+			return service.userConflict(ctx, arg, err)
+		}
+		return user, storeError(err)
+	}
+
+	return user, nil
+}
+
+// The userConflict function tells the user colliding with a registration apart: the same user, when
+// all its fields match, is returned as if it was just registered, otherwise the error names the field
+// taken by another user.
+func (service *Service) userConflict(ctx context.Context, arg CreateUserParams, violation error) (db.User, error) {
+	existing, err := service.store.GetUser(ctx, arg.Username)
+	switch {
+	case err == nil:
+		if existing.Email == arg.Email && existing.FullName == arg.FullName && util.Checkpassword(arg.Password, existing.HashedPassword) == nil {
+			return existing, nil
+		}
+		return db.User{}, errorf(CodeAlreadyExists, "username %s is already taken", arg.Username).withReason(ReasonUsernameTaken).withField("username")
+	case !errors.Is(err, db.ErrRecordNotFound):
+		return db.User{}, storeError(err)
+	}
+
+	_, err = service.store.GetUserByEmail(ctx, arg.Email)
+	switch {
+	case err == nil:
+		return db.User{}, errorf(CodeAlreadyExists, "email %s is already registered", arg.Email).withReason(ReasonEmailTaken).withField("email")
+	case !errors.Is(err, db.ErrRecordNotFound):
+		return db.User{}, storeError(err)
+	}
+
+	// the user the registration collided with was deleted since
+	return db.User{}, storeError(violation)
+}
+
+// The CreateSuperuser function registers a user with the admin role, for the operators creating the
+// first admins of the bank. The admins are users of the default organization, who aren't scoped to it.
+func (service *Service) CreateSuperuser(ctx context.Context, arg CreateUserParams) (db.User, error) {
+	hashedPassword, err := util.HashPassword(arg.Password)
+	if err != nil {
+		return db.User{}, newError(CodeInternal, err)
+	}
+
+	user, err := service.store.CreateUserWithRoleTx(ctx, db.CreateUserParams{
+		Username:       arg.Username,
+		HashedPassword: hashedPassword,
+		FullName:       arg.FullName,
+		Email:          arg.Email,
+		OrgID:          db.DefaultOrganizationID,
+	}, util.AdminRole)
+	if err != nil {
+		return user, storeError(err)
+	}
+
+	return user, nil
+}
+
+// The GetUser function returns a user by username.
+func (service *Service) GetUser(ctx context.Context, username string) (db.User, error) {
+	user, err := service.store.GetUser(ctx, username)
+	if err != nil {
+		return user, storeError(err)
+	}
+
+	return user, nil
+}
+
+// The LoginUserParams type holds the credentials of a user logging in, along with the client they use
+// which is recorded on the session.
+// @property {string} Identifier - the username or the email of the user, told apart by the `@` only
+// emails have.
+// @property {[]string} Scopes - the scopes the tokens of the session are limited to, e.g. for a read-only
+// integration, the tokens being allowed every request when empty.
+type LoginUserParams struct {
+	Identifier string
+	Password   string
+	UserAgent  string
+	ClientIP   string
+	Scopes     []string
+}
+
+// The LoginUserResult type holds the tokens issued to a user who logged in.
+type LoginUserResult struct {
+	User           db.User
+	Session        db.Session
+	AccessToken    string
+	AccessPayload  *token.Payload
+	RefreshToken   string
+	RefreshPayload *token.Payload
+}
+
+// The LoginUser function checks the credentials of a user, who gives either their username or their
+// email, and issues an access token along with a refresh token, whose session is stored so that it can
+// be blocked. Too many wrong passwords lock the user out, see checkPassword.
+func (service *Service) LoginUser(ctx context.Context, arg LoginUserParams) (LoginUserResult, error) {
+	var result LoginUserResult
+
+	err := validateScopes(arg.Scopes)
+	if err != nil {
+		return result, err
+	}
+
+	user, err := service.getUserByIdentifier(ctx, arg.Identifier)
+	if err != nil {
+		return result, storeError(err)
+	}
+
+	err = service.checkPassword(ctx, user, arg.Password, arg.ClientIP)
+	if err != nil {
+		return result, err
+	}
+
+	return service.startSession(ctx, user, arg.UserAgent, arg.ClientIP, arg.Scopes, AuthMethodPassword)
+}
+
+// The checkPassword function checks the password of a user logging in from `clientIP`. The user is locked
+// out of the client for the LOGIN_LOCKOUT_DURATION config after LOGIN_MAX_FAILURES wrong passwords from it
+// within the LOGIN_FAILURE_WINDOW config, a right password clearing the failures. The other clients can
+// still log in, and a client can't log in at all after LOGIN_CLIENT_MAX_FAILURES wrong passwords within the
+// window whatever the user, so that it can't guess the passwords of many users instead.
+func (service *Service) checkPassword(ctx context.Context, user db.User, password string, clientIP string) error {
+	throttled := service.config.LoginMaxFailures > 0
+	if throttled {
+		if service.config.LoginClientMaxFailures > 0 {
+			failures, err := service.store.CountClientLoginFailures(ctx, db.CountClientLoginFailuresParams{
+				ClientIp: clientIP,
+				Since:    time.Now().Add(-service.config.LoginFailureWindow),
+			})
+			if err != nil {
+				return storeError(err)
+			}
+			if failures >= service.config.LoginClientMaxFailures {
+				return errorf(CodeResourceExhausted, "too many failed logins from this client, try again later").withReason(ReasonTooManyLoginFailures)
+			}
+		}
+
+		// a locked user can't log in even with the right password, so that the lockout can't be used to
+		// find it out
+		lockout, err := service.store.GetLoginLockout(ctx, db.GetLoginLockoutParams{
+			Username: user.Username,
+			ClientIp: clientIP,
+		})
+		if err != nil && !errors.Is(err, db.ErrRecordNotFound) {
+			return storeError(err)
+		}
+		if err == nil && lockout.LockedUntil.After(time.Now()) {
+			return lockedError(lockout)
+		}
+	}
+
+	err := util.Checkpassword(password, user.HashedPassword)
+	if err != nil {
+		if !throttled {
+			return newError(CodeUnauthenticated, err).withReason(ReasonInvalidCredentials)
+		}
+
+		failure, failureErr := service.store.RecordLoginFailureTx(ctx, db.RecordLoginFailureTxParams{
+			Username:        user.Username,
+			ClientIP:        clientIP,
+			Window:          service.config.LoginFailureWindow,
+			MaxFailures:     service.config.LoginMaxFailures,
+			LockoutDuration: service.config.LoginLockoutDuration,
+		})
+		if failureErr != nil {
+			return storeError(failureErr)
+		}
+		if failure.Lockout != nil {
+			return lockedError(*failure.Lockout)
+		}
+		return newError(CodeUnauthenticated, err).withReason(ReasonInvalidCredentials)
+	}
+
+	if throttled {
+		_, err = service.store.DeleteClientLoginFailures(ctx, db.DeleteClientLoginFailuresParams{
+			Username: user.Username,
+			ClientIp: clientIP,
+		})
+		if err != nil {
+			return storeError(err)
+		}
+	}
+
+	return nil
+}
+
+// The startSession function issues an access token along with a refresh token to a user who logged in
+// from the client of `userAgent` and `clientIP` with `authMethod`, AuthMethodPassword or the identity
+// provider, storing the session of the refresh token. Both tokens carry the role of the user and are
+// limited to `scopes`, so that the access tokens renewed from the refresh token are too.
+func (service *Service) startSession(ctx context.Context, user db.User, userAgent string, clientIP string, scopes []string, authMethod string) (LoginUserResult, error) {
+	var result LoginUserResult
+
+	claims := token.Claims{
+		Username: user.Username,
+		Role:     user.Role,
+		OrgID:    user.OrgID,
+		Scopes:   scopes,
+		Metadata: map[string]string{token.MetadataAuthMethod: authMethod},
+	}
+	refreshToken, refreshPayload, err := service.tokenMaker.CreateToken(claims, service.config.RefreshTokenDuration)
+	if err != nil {
+		return result, newError(CodeInternal, err)
+	}
+
+	// the session is identified by the refresh token, the access token carries its id so that using it
+	// keeps the session active
+	claims.SessionID = refreshPayload.ID
+	accessToken, accessPayload, err := service.tokenMaker.CreateToken(claims, service.config.AccessTokenDuration)
+	if err != nil {
+		return result, newError(CodeInternal, err)
+	}
+
+	session, err := service.store.CreateSession(ctx, db.CreateSessionParams{
+		ID:           refreshPayload.ID,
+		Username:     user.Username,
+		RefreshToken: refreshToken,
+		UserAgent:    userAgent,
+		ClientIp:     clientIP,
+		IsBlocked:    false,
+		ExpiresAt:    refreshPayload.ExpiredAt,
+	})
+	if err != nil {
+		return result, storeError(err)
+	}
+
+	return LoginUserResult{
+		User:           user,
+		Session:        session,
+		AccessToken:    accessToken,
+		AccessPayload:  accessPayload,
+		RefreshToken:   refreshToken,
+		RefreshPayload: refreshPayload,
+	}, nil
+}
+
+// The getUserByIdentifier function gets a user by their email when `identifier` is one, and by their
+// username otherwise, usernames being alphanumeric.
+func (service *Service) getUserByIdentifier(ctx context.Context, identifier string) (db.User, error) {
+	if strings.Contains(identifier, "@") {
+		return service.store.GetUserByEmail(ctx, identifier)
+	}
+	return service.store.GetUser(ctx, identifier)
+}
+
+// The lockedError function reports a user locked out of a client after too many failed logins, with when
+// they can log in from it again.
+func lockedError(lockout db.LoginLockout) error {
+	return errorf(CodeLocked, "account is locked until %s after too many failed logins", lockout.LockedUntil.UTC().Format(time.RFC3339)).withReason(ReasonAccountLocked)
+}
+
+// The UnlockUser function lifts the lockouts of a user before they are over, clearing their failed logins,
+// for a banker who checked it was them.
+func (service *Service) UnlockUser(ctx context.Context, username string) error {
+	_, err := service.store.GetUser(ctx, username)
+	if err != nil {
+		return storeError(err)
+	}
+
+	err = service.store.UnlockUserTx(ctx, username)
+	if err != nil {
+		return storeError(err)
+	}
+
+	return nil
+}
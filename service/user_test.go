@@ -0,0 +1,334 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"go-backend/clock"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"go-backend/testutil/factory"
+	"go-backend/token"
+	"go-backend/util"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoginUser(t *testing.T) {
+	password := util.RandomString(8)
+	hashedPassword, err := util.HashPassword(password)
+	require.NoError(t, err)
+
+	user := db.User{
+		Username:       util.RandomOwner(),
+		HashedPassword: hashedPassword,
+		FullName:       util.RandomOwner(),
+		Email:          util.RandomEmail(),
+		Role:           util.DepositorRole,
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	service := newTestService(t, store)
+
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(2).Return(user, nil)
+	store.EXPECT().
+		CreateSession(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(ctx context.Context, arg db.CreateSessionParams) (db.Session, error) {
+			require.Equal(t, user.Username, arg.Username)
+			require.Equal(t, "test-agent", arg.UserAgent)
+			require.Equal(t, "127.0.0.1", arg.ClientIp)
+			return db.Session{ID: arg.ID, Username: arg.Username, RefreshToken: arg.RefreshToken}, nil
+		})
+
+	result, err := service.LoginUser(context.Background(), LoginUserParams{
+		Identifier: user.Username,
+		Password:   password,
+		UserAgent:  "test-agent",
+		ClientIP:   "127.0.0.1",
+	})
+	require.NoError(t, err)
+	require.Equal(t, user.Username, result.AccessPayload.Username)
+	require.Equal(t, result.RefreshPayload.ID, result.Session.ID)
+	require.Equal(t, result.Session.ID, result.AccessPayload.SessionID)
+	require.Equal(t, user.Role, result.AccessPayload.Role)
+	require.Equal(t, AuthMethodPassword, result.AccessPayload.Metadata[token.MetadataAuthMethod])
+	require.Equal(t, user.Role, result.RefreshPayload.Role)
+
+	_, err = service.LoginUser(context.Background(), LoginUserParams{
+		Identifier: user.Username,
+		Password:   "wrong-password",
+	})
+	require.Equal(t, CodeUnauthenticated, ErrorCode(err))
+
+	store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(1).Return(db.User{}, db.ErrRecordNotFound)
+	_, err = service.LoginUser(context.Background(), LoginUserParams{Identifier: "unknown", Password: password})
+	require.Equal(t, CodeNotFound, ErrorCode(err))
+}
+
+func TestLoginUserByEmail(t *testing.T) {
+	user, password := factory.UserWithPassword(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	service := newTestService(t, store)
+
+	store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+	store.EXPECT().GetUserByEmail(gomock.Any(), gomock.Eq(user.Email)).Times(1).Return(user, nil)
+	store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(1)
+
+	result, err := service.LoginUser(context.Background(), LoginUserParams{Identifier: user.Email, Password: password})
+	require.NoError(t, err)
+	require.Equal(t, user.Username, result.AccessPayload.Username)
+
+	store.EXPECT().GetUserByEmail(gomock.Any(), gomock.Any()).Times(1).Return(db.User{}, db.ErrRecordNotFound)
+	_, err = service.LoginUser(context.Background(), LoginUserParams{Identifier: "unknown@email.com", Password: password})
+	require.Equal(t, CodeNotFound, ErrorCode(err))
+}
+
+func TestLoginUserWithScopes(t *testing.T) {
+	user, password := factory.UserWithPassword(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	service := newTestService(t, store)
+
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+	store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(1)
+
+	scopes := []string{token.ScopeAccountsRead, token.ScopeTransfersRead}
+	result, err := service.LoginUser(context.Background(), LoginUserParams{Identifier: user.Username, Password: password, Scopes: scopes})
+	require.NoError(t, err)
+	require.Equal(t, scopes, result.AccessPayload.Scopes)
+	require.Equal(t, scopes, result.RefreshPayload.Scopes)
+
+	// the scopes are checked before the credentials
+	_, err = service.LoginUser(context.Background(), LoginUserParams{Identifier: user.Username, Password: password, Scopes: []string{"transfers"}})
+	require.Equal(t, CodeInvalidArgument, ErrorCode(err))
+	require.Equal(t, "scopes", ErrorField(err))
+}
+
+func TestLoginUserLockout(t *testing.T) {
+	password := util.RandomString(8)
+	hashedPassword, err := util.HashPassword(password)
+	require.NoError(t, err)
+
+	user := db.User{Username: util.RandomOwner(), HashedPassword: hashedPassword}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	service := newTestService(t, store)
+	service.config.LoginMaxFailures = 3
+	service.config.LoginFailureWindow = time.Minute
+	service.config.LoginLockoutDuration = time.Hour
+	service.config.LoginClientMaxFailures = 10
+
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).AnyTimes().Return(user, nil)
+	store.EXPECT().CountClientLoginFailures(gomock.Any(), gomock.Any()).Times(3).Return(int64(3), nil)
+
+	// the user is locked out of the client only
+	key := db.GetLoginLockoutParams{Username: user.Username, ClientIp: "10.0.0.1"}
+
+	// the failure is recorded from the client that sent it
+	store.EXPECT().GetLoginLockout(gomock.Any(), gomock.Eq(key)).Times(1).Return(db.LoginLockout{}, db.ErrRecordNotFound)
+	store.EXPECT().
+		RecordLoginFailureTx(gomock.Any(), gomock.Eq(db.RecordLoginFailureTxParams{
+			Username:        user.Username,
+			ClientIP:        "10.0.0.1",
+			Window:          time.Minute,
+			MaxFailures:     3,
+			LockoutDuration: time.Hour,
+		})).
+		Times(1).
+		Return(db.RecordLoginFailureTxResult{
+			Failures: 3,
+			Lockout:  &db.LoginLockout{Username: user.Username, LockedUntil: time.Now().Add(time.Hour)},
+		}, nil)
+
+	_, err = service.LoginUser(context.Background(), LoginUserParams{Identifier: user.Username, Password: "wrong-password", ClientIP: "10.0.0.1"})
+	require.Equal(t, CodeLocked, ErrorCode(err))
+	require.Equal(t, ReasonAccountLocked, ErrorReason(err))
+
+	// the right password doesn't get a locked user in
+	store.EXPECT().
+		GetLoginLockout(gomock.Any(), gomock.Eq(key)).
+		Times(1).
+		Return(db.LoginLockout{Username: user.Username, LockedUntil: time.Now().Add(time.Hour)}, nil)
+
+	_, err = service.LoginUser(context.Background(), LoginUserParams{Identifier: user.Username, Password: password, ClientIP: "10.0.0.1"})
+	require.Equal(t, CodeLocked, ErrorCode(err))
+
+	// a successful login clears the failures
+	store.EXPECT().
+		GetLoginLockout(gomock.Any(), gomock.Eq(key)).
+		Times(1).
+		Return(db.LoginLockout{Username: user.Username, LockedUntil: time.Now().Add(-time.Minute)}, nil)
+	store.EXPECT().
+		DeleteClientLoginFailures(gomock.Any(), gomock.Eq(db.DeleteClientLoginFailuresParams{Username: user.Username, ClientIp: "10.0.0.1"})).
+		Times(1).
+		Return(int64(2), nil)
+	store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(1).Return(db.Session{}, nil)
+
+	_, err = service.LoginUser(context.Background(), LoginUserParams{Identifier: user.Username, Password: password, ClientIP: "10.0.0.1"})
+	require.NoError(t, err)
+}
+
+func TestLoginUserClientFailures(t *testing.T) {
+	password := util.RandomString(8)
+	hashedPassword, err := util.HashPassword(password)
+	require.NoError(t, err)
+
+	user := db.User{Username: util.RandomOwner(), HashedPassword: hashedPassword}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	service := newTestService(t, store)
+	service.config.LoginMaxFailures = 3
+	service.config.LoginFailureWindow = time.Minute
+	service.config.LoginClientMaxFailures = 10
+
+	// a client that failed too many times can't log in, even as a user it didn't fail for
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(user.Username)).Times(1).Return(user, nil)
+	store.EXPECT().
+		CountClientLoginFailures(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, arg db.CountClientLoginFailuresParams) (int64, error) {
+			require.Equal(t, "10.0.0.1", arg.ClientIp)
+			require.WithinDuration(t, time.Now().Add(-time.Minute), arg.Since, time.Second)
+			return 10, nil
+		})
+	store.EXPECT().GetLoginLockout(gomock.Any(), gomock.Any()).Times(0)
+	store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(0)
+
+	_, err = service.LoginUser(context.Background(), LoginUserParams{Identifier: user.Username, Password: password, ClientIP: "10.0.0.1"})
+	require.Equal(t, CodeResourceExhausted, ErrorCode(err))
+	require.Equal(t, ReasonTooManyLoginFailures, ErrorReason(err))
+}
+
+func TestCreateSuperuser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	service := newTestService(t, store)
+
+	arg := CreateUserParams{
+		Username: util.RandomOwner(),
+		Password: util.RandomString(8),
+		FullName: util.RandomOwner(),
+		Email:    util.RandomEmail(),
+	}
+
+	store.EXPECT().
+		CreateUserWithRoleTx(gomock.Any(), gomock.Any(), gomock.Eq(util.AdminRole)).
+		Times(1).
+		DoAndReturn(func(ctx context.Context, params db.CreateUserParams, role string) (db.User, error) {
+			require.Equal(t, arg.Username, params.Username)
+			require.NoError(t, util.Checkpassword(arg.Password, params.HashedPassword))
+			return db.User{Username: params.Username, Role: role}, nil
+		})
+
+	user, err := service.CreateSuperuser(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, util.AdminRole, user.Role)
+}
+
+func TestCreateUserConflict(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	service := newTestService(t, store)
+
+	existing, password := factory.UserWithPassword(t)
+	arg := CreateUserParams{
+		Username: existing.Username,
+		Password: password,
+		FullName: existing.FullName,
+		Email:    existing.Email,
+	}
+	violation := &pgconn.PgError{Code: db.UniqueViolation}
+
+	store.EXPECT().CreateUser(gomock.Any(), gomock.Any()).AnyTimes().Return(db.User{}, violation)
+
+	// registering the same user again returns it
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(existing.Username)).Times(2).Return(existing, nil)
+	user, err := service.CreateUser(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, existing, user)
+
+	// any other field makes it another user
+	other := arg
+	other.FullName = util.RandomOwner()
+	_, err = service.CreateUser(context.Background(), other)
+	require.Equal(t, CodeAlreadyExists, ErrorCode(err))
+	require.Equal(t, ReasonUsernameTaken, ErrorReason(err))
+	require.Equal(t, "username", ErrorField(err))
+
+	other = arg
+	other.Username = util.RandomOwner()
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(other.Username)).Times(1).Return(db.User{}, db.ErrRecordNotFound)
+	store.EXPECT().GetUserByEmail(gomock.Any(), gomock.Eq(existing.Email)).Times(1).Return(existing, nil)
+	_, err = service.CreateUser(context.Background(), other)
+	require.Equal(t, ReasonEmailTaken, ErrorReason(err))
+	require.Equal(t, "email", ErrorField(err))
+
+	// the lookups failing leave the violation unexplained
+	other.Username = util.RandomOwner()
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(other.Username)).Times(1).Return(db.User{}, sql.ErrConnDone)
+	_, err = service.CreateUser(context.Background(), other)
+	require.Equal(t, CodeInternal, ErrorCode(err))
+}
+
+func TestCreateUserSignupLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	service := newTestService(t, store)
+	service.config.SignupMaxPerClient = 2
+	service.config.SignupWindow = time.Hour
+	fake := clock.NewFake(time.Now())
+	service.SetClock(fake)
+
+	store.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Times(4).Return(db.User{}, nil)
+
+	signup := func(clientIP string) error {
+		_, err := service.CreateUser(context.Background(), CreateUserParams{
+			Username: util.RandomOwner(),
+			Password: util.RandomString(8),
+			FullName: util.RandomOwner(),
+			Email:    util.RandomEmail(),
+			ClientIP: clientIP,
+		})
+		return err
+	}
+
+	require.NoError(t, signup("10.0.0.1"))
+	require.NoError(t, signup("10.0.0.1"))
+
+	err := signup("10.0.0.1")
+	require.Equal(t, CodeResourceExhausted, ErrorCode(err))
+	require.Equal(t, ReasonTooManySignups, ErrorReason(err))
+
+	// the other clients have their own count
+	require.NoError(t, signup("10.0.0.2"))
+
+	// the count starts again with the next window
+	fake.Advance(time.Hour)
+	require.NoError(t, signup("10.0.0.1"))
+}
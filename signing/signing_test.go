@@ -0,0 +1,51 @@
+package signing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessage(t *testing.T) {
+	message := Message(1792152000, "POST", "/api/v1/transfers", []byte(`{"amount":10}`))
+	require.Equal(t, "1792152000\nPOST\n/api/v1/transfers\n", message[:len(message)-64])
+
+	// the body is part of the message
+	require.NotEqual(t, message, Message(1792152000, "POST", "/api/v1/transfers", []byte(`{"amount":1000}`)))
+	require.NotEqual(t, message, Message(1792152001, "POST", "/api/v1/transfers", []byte(`{"amount":10}`)))
+}
+
+func TestFresh(t *testing.T) {
+	now := time.Now()
+	require.True(t, Fresh(now.Unix(), now))
+	require.True(t, Fresh(now.Add(-4*time.Minute).Unix(), now))
+	require.True(t, Fresh(now.Add(4*time.Minute).Unix(), now))
+	require.False(t, Fresh(now.Add(-6*time.Minute).Unix(), now))
+	require.False(t, Fresh(now.Add(6*time.Minute).Unix(), now))
+}
+
+func TestMemoryNonceStore(t *testing.T) {
+	store := NewMemoryNonceStore()
+
+	first, err := store.Claim(context.Background(), "abc", time.Minute)
+	require.NoError(t, err)
+	require.True(t, first)
+
+	first, err = store.Claim(context.Background(), "abc", time.Minute)
+	require.NoError(t, err)
+	require.False(t, first)
+
+	first, err = store.Claim(context.Background(), "def", time.Minute)
+	require.NoError(t, err)
+	require.True(t, first)
+
+	// the signatures are forgotten once they expire
+	first, err = store.Claim(context.Background(), "ghi", -time.Second)
+	require.NoError(t, err)
+	require.True(t, first)
+	first, err = store.Claim(context.Background(), "ghi", time.Minute)
+	require.NoError(t, err)
+	require.True(t, first)
+}
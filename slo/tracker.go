@@ -0,0 +1,263 @@
+package slo
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// The Objective type describes the service level a route is expected to meet.
+// @property {float64} Availability - the fraction of requests that must not fail with a server error,
+// e.g. 0.999.
+// @property {time.Duration} Latency - the duration under which a request is considered fast.
+// @property {float64} LatencyTarget - the fraction of requests that must be faster than `Latency`.
+type Objective struct {
+	Availability  float64       `json:"availability"`
+	Latency       time.Duration `json:"latency"`
+	LatencyTarget float64       `json:"latency_target"`
+}
+
+// DefaultObjective applies to every route without an objective of its own.
+var DefaultObjective = Objective{
+	Availability:  0.999,
+	Latency:       300 * time.Millisecond,
+	LatencyTarget: 0.95,
+}
+
+// The AlertPolicy type is a multi-window burn-rate alert: it fires when the error budget burns faster
+// than `Threshold` over both the long and the short window, so that it triggers quickly on a real
+// incident but resets as soon as the incident is over.
+type AlertPolicy struct {
+	Severity  string
+	Long      time.Duration
+	Short     time.Duration
+	Threshold float64
+}
+
+// AlertPolicies are the standard page and ticket policies for a 30 day error budget: paging when 2% of
+// the budget is spent within an hour, and opening a ticket when 5% is spent within six hours.
+var AlertPolicies = []AlertPolicy{
+	{Severity: "page", Long: time.Hour, Short: 5 * time.Minute, Threshold: 14.4},
+	{Severity: "ticket", Long: 6 * time.Hour, Short: 30 * time.Minute, Threshold: 6},
+}
+
+// Windows are the windows over which burn rates are reported.
+var Windows = []time.Duration{5 * time.Minute, 30 * time.Minute, time.Hour, 6 * time.Hour}
+
+const (
+	SLOAvailability = "availability"
+	SLOLatency      = "latency"
+
+	// one bucket per minute, enough to cover the longest window
+	bucketCount = 6 * 60
+)
+
+type bucket struct {
+	minute int64
+	total  uint64
+	errors uint64
+	slow   uint64
+}
+
+type routeStats struct {
+	objective Objective
+	buckets   [bucketCount]bucket
+}
+
+// The Tracker type records the outcome of every request per route and computes how fast each route
+// burns its error budget.
+type Tracker struct {
+	mu               sync.Mutex
+	defaultObjective Objective
+	objectives       map[string]Objective
+	routes           map[string]*routeStats
+	now              func() time.Time
+
+	burnRateDesc *prometheus.Desc
+	alertDesc    *prometheus.Desc
+}
+
+// The `NewTracker` function creates a tracker using `objectives` for the routes listed in it and
+// `defaultObjective` for every other route.
+func NewTracker(defaultObjective Objective, objectives map[string]Objective) *Tracker {
+	return &Tracker{
+		defaultObjective: defaultObjective,
+		objectives:       objectives,
+		routes:           make(map[string]*routeStats),
+		now:              time.Now,
+		burnRateDesc: prometheus.NewDesc(
+			"slo_burn_rate",
+			"Rate at which the route burns its error budget over the window, 1 meaning the budget lasts exactly the SLO period.",
+			[]string{"route", "slo", "window"},
+			nil,
+		),
+		alertDesc: prometheus.NewDesc(
+			"slo_burn_rate_alert",
+			"Whether the multi-window burn-rate alert of the route is firing.",
+			[]string{"route", "slo", "severity"},
+			nil,
+		),
+	}
+}
+
+// The `Observe` function records a request to `route`. Requests failing with a 5xx status spend the
+// availability budget and requests slower than the objective spend the latency budget.
+func (tracker *Tracker) Observe(route string, status int, duration time.Duration) {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	stats, ok := tracker.routes[route]
+	if !ok {
+		objective, ok := tracker.objectives[route]
+		if !ok {
+			objective = tracker.defaultObjective
+		}
+		stats = &routeStats{objective: objective}
+		tracker.routes[route] = stats
+	}
+
+	minute := tracker.now().Unix() / 60
+	b := &stats.buckets[minute%bucketCount]
+	if b.minute != minute {
+		*b = bucket{minute: minute}
+	}
+
+	b.total++
+	if status >= 500 {
+		b.errors++
+	}
+	if duration > stats.objective.Latency {
+		b.slow++
+	}
+}
+
+// The BurnRate type is the burn rate of both objectives of a route over a window.
+type BurnRate struct {
+	Window       string  `json:"window"`
+	Requests     uint64  `json:"requests"`
+	Availability float64 `json:"availability"`
+	Latency      float64 `json:"latency"`
+}
+
+// The Alert type is a firing burn-rate alert.
+type Alert struct {
+	SLO      string `json:"slo"`
+	Severity string `json:"severity"`
+}
+
+// The Summary type reports the objective, burn rates and firing alerts of a route.
+type Summary struct {
+	Route     string     `json:"route"`
+	Objective Objective  `json:"objective"`
+	BurnRates []BurnRate `json:"burn_rates"`
+	Alerts    []Alert    `json:"alerts"`
+}
+
+// The `burnRate` function computes the burn rates of `stats` over the last `window`. It must be called
+// with the lock held.
+func (tracker *Tracker) burnRate(stats *routeStats, window time.Duration) BurnRate {
+	minute := tracker.now().Unix() / 60
+	oldest := minute - int64(window/time.Minute) + 1
+
+	var total, errors, slow uint64
+	for _, b := range stats.buckets {
+		if b.minute >= oldest && b.minute <= minute {
+			total += b.total
+			errors += b.errors
+			slow += b.slow
+		}
+	}
+
+	rate := BurnRate{
+		Window:   formatWindow(window),
+		Requests: total,
+	}
+	if total > 0 {
+		rate.Availability = float64(errors) / float64(total) / (1 - stats.objective.Availability)
+		rate.Latency = float64(slow) / float64(total) / (1 - stats.objective.LatencyTarget)
+	}
+
+	return rate
+}
+
+// The `alerts` function returns the alerts firing for `stats`. It must be called with the lock held.
+func (tracker *Tracker) alerts(stats *routeStats) []Alert {
+	alerts := []Alert{}
+	for _, policy := range AlertPolicies {
+		long := tracker.burnRate(stats, policy.Long)
+		short := tracker.burnRate(stats, policy.Short)
+
+		if long.Availability > policy.Threshold && short.Availability > policy.Threshold {
+			alerts = append(alerts, Alert{SLO: SLOAvailability, Severity: policy.Severity})
+		}
+		if long.Latency > policy.Threshold && short.Latency > policy.Threshold {
+			alerts = append(alerts, Alert{SLO: SLOLatency, Severity: policy.Severity})
+		}
+	}
+
+	return alerts
+}
+
+// The `Summary` function reports every route that received requests, sorted by route.
+func (tracker *Tracker) Summary() []Summary {
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	summaries := make([]Summary, 0, len(tracker.routes))
+	for route, stats := range tracker.routes {
+		summary := Summary{
+			Route:     route,
+			Objective: stats.objective,
+			BurnRates: make([]BurnRate, 0, len(Windows)),
+			Alerts:    tracker.alerts(stats),
+		}
+		for _, window := range Windows {
+			summary.BurnRates = append(summary.BurnRates, tracker.burnRate(stats, window))
+		}
+		summaries = append(summaries, summary)
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].Route < summaries[j].Route
+	})
+
+	return summaries
+}
+
+// Describe implements prometheus.Collector.
+func (tracker *Tracker) Describe(ch chan<- *prometheus.Desc) {
+	ch <- tracker.burnRateDesc
+	ch <- tracker.alertDesc
+}
+
+// Collect implements prometheus.Collector, exporting the burn rates and alerts of every route.
+func (tracker *Tracker) Collect(ch chan<- prometheus.Metric) {
+	for _, summary := range tracker.Summary() {
+		for _, rate := range summary.BurnRates {
+			ch <- prometheus.MustNewConstMetric(tracker.burnRateDesc, prometheus.GaugeValue, rate.Availability, summary.Route, SLOAvailability, rate.Window)
+			ch <- prometheus.MustNewConstMetric(tracker.burnRateDesc, prometheus.GaugeValue, rate.Latency, summary.Route, SLOLatency, rate.Window)
+		}
+
+		for _, slo := range []string{SLOAvailability, SLOLatency} {
+			for _, policy := range AlertPolicies {
+				firing := 0.0
+				for _, alert := range summary.Alerts {
+					if alert.SLO == slo && alert.Severity == policy.Severity {
+						firing = 1
+					}
+				}
+				ch <- prometheus.MustNewConstMetric(tracker.alertDesc, prometheus.GaugeValue, firing, summary.Route, slo, policy.Severity)
+			}
+		}
+	}
+}
+
+func formatWindow(window time.Duration) string {
+	if window%time.Hour == 0 {
+		return fmt.Sprintf("%dh", window/time.Hour)
+	}
+	return fmt.Sprintf("%dm", window/time.Minute)
+}
@@ -0,0 +1,86 @@
+package slo
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTracker(now *time.Time) *Tracker {
+	tracker := NewTracker(DefaultObjective, map[string]Objective{
+		"POST /transfers": {Availability: 0.99, Latency: time.Second, LatencyTarget: 0.9},
+	})
+	tracker.now = func() time.Time { return *now }
+	return tracker
+}
+
+func TestBurnRate(t *testing.T) {
+	now := time.Now()
+	tracker := newTestTracker(&now)
+
+	// 2 errors and 1 slow request out of 10
+	for i := 0; i < 10; i++ {
+		status := http.StatusOK
+		if i < 2 {
+			status = http.StatusInternalServerError
+		}
+		duration := 10 * time.Millisecond
+		if i == 9 {
+			duration = 2 * time.Second
+		}
+		tracker.Observe("POST /transfers", status, duration)
+	}
+
+	summaries := tracker.Summary()
+	require.Len(t, summaries, 1)
+	require.Equal(t, "POST /transfers", summaries[0].Route)
+	require.Len(t, summaries[0].BurnRates, len(Windows))
+
+	rate := summaries[0].BurnRates[0]
+	require.Equal(t, "5m", rate.Window)
+	require.Equal(t, uint64(10), rate.Requests)
+	require.InDelta(t, 20, rate.Availability, 1e-9)
+	require.InDelta(t, 1, rate.Latency, 1e-9)
+
+	// both page windows burn faster than 14.4, the latency budget burns at exactly its objective
+	require.Contains(t, summaries[0].Alerts, Alert{SLO: SLOAvailability, Severity: "page"})
+	require.Contains(t, summaries[0].Alerts, Alert{SLO: SLOAvailability, Severity: "ticket"})
+	require.NotContains(t, summaries[0].Alerts, Alert{SLO: SLOLatency, Severity: "page"})
+}
+
+func TestBurnRateWindows(t *testing.T) {
+	now := time.Now()
+	tracker := newTestTracker(&now)
+
+	tracker.Observe("GET /accounts", http.StatusInternalServerError, time.Millisecond)
+	now = now.Add(10 * time.Minute)
+	tracker.Observe("GET /accounts", http.StatusOK, time.Millisecond)
+
+	rates := tracker.Summary()[0].BurnRates
+
+	// the error has left the 5 minute window but not the longer ones
+	require.Equal(t, uint64(1), rates[0].Requests)
+	require.Zero(t, rates[0].Availability)
+	require.Equal(t, uint64(2), rates[1].Requests)
+	require.InDelta(t, 500, rates[1].Availability, 1e-6)
+
+	// the 5 minute window has recovered so the page stops firing, while the ticket keeps firing
+	require.Equal(t, []Alert{{SLO: SLOAvailability, Severity: "ticket"}}, tracker.Summary()[0].Alerts)
+
+	// buckets older than the longest window are recycled
+	now = now.Add(7 * time.Hour)
+	tracker.Observe("GET /accounts", http.StatusOK, time.Millisecond)
+	require.Equal(t, uint64(1), tracker.Summary()[0].BurnRates[3].Requests)
+}
+
+func TestCollect(t *testing.T) {
+	now := time.Now()
+	tracker := newTestTracker(&now)
+	tracker.Observe("GET /accounts", http.StatusOK, time.Millisecond)
+
+	// one burn rate per window and slo, one alert per policy and slo
+	require.Equal(t, 2*len(Windows)+2*len(AlertPolicies), testutil.CollectAndCount(tracker))
+}
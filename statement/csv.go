@@ -0,0 +1,39 @@
+package statement
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+)
+
+// The `writeCSV` function writes the statement as CSV, a transaction per row with the balance of the
+// account after it, which the applications whose columns can be mapped import.
+func writeCSV(w io.Writer, statement Statement) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"id", "date", "amount", "balance", "payee", "memo", "reference", "counterparty_account_id"})
+
+	balance := statement.OpeningBalance()
+	for _, transaction := range statement.Transactions {
+		balance += transaction.Amount
+
+		counterparty := ""
+		if transaction.CounterpartyAccountID != 0 {
+			counterparty = strconv.FormatInt(transaction.CounterpartyAccountID, 10)
+		}
+
+		writer.Write([]string{
+			transaction.ID,
+			transaction.PostedAt.UTC().Format(time.RFC3339),
+			formatAmount(transaction.Amount),
+			formatAmount(balance),
+			transaction.Payee,
+			transaction.Memo,
+			transaction.Reference,
+			counterparty,
+		})
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,113 @@
+package statement
+
+import (
+	"bufio"
+	"encoding/xml"
+	"io"
+	"strconv"
+	"time"
+)
+
+// ofxNameLength is the longest payee name OFX allows, longer ones being cut.
+const ofxNameLength = 32
+
+// ofxWriter writes the elements of an OFX 2 document, whose syntax is XML, the first error being kept
+// so that it is checked once at the end.
+type ofxWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (ofx *ofxWriter) raw(s string) {
+	if ofx.err == nil {
+		_, ofx.err = ofx.w.WriteString(s)
+	}
+}
+
+// element writes an element with a value, which is left out when empty.
+func (ofx *ofxWriter) element(tag string, value string) {
+	if value == "" {
+		return
+	}
+	ofx.raw("<" + tag + ">")
+	if ofx.err == nil {
+		ofx.err = xml.EscapeText(ofx.w, []byte(value))
+	}
+	ofx.raw("</" + tag + ">\n")
+}
+
+func (ofx *ofxWriter) status() {
+	ofx.raw("<STATUS>\n")
+	ofx.element("CODE", "0")
+	ofx.element("SEVERITY", "INFO")
+	ofx.raw("</STATUS>\n")
+}
+
+// ofxTime formats a time in the datetime format of OFX.
+func ofxTime(t time.Time) string {
+	return t.UTC().Format("20060102150405.000") + "[0:GMT]"
+}
+
+// The `writeOFX` function writes the statement as an OFX 2.1.1 bank statement response, as downloaded
+// from a bank by Quicken or YNAB.
+func writeOFX(w io.Writer, statement Statement) error {
+	ofx := &ofxWriter{w: bufio.NewWriter(w)}
+	ofx.raw(`<?xml version="1.0" encoding="UTF-8" standalone="no"?>` + "\n")
+	ofx.raw(`<?OFX OFXHEADER="200" VERSION="211" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>` + "\n")
+	ofx.raw("<OFX>\n")
+
+	ofx.raw("<SIGNONMSGSRSV1>\n<SONRS>\n")
+	ofx.status()
+	ofx.element("DTSERVER", ofxTime(time.Now()))
+	ofx.element("LANGUAGE", "ENG")
+	ofx.raw("</SONRS>\n</SIGNONMSGSRSV1>\n")
+
+	ofx.raw("<BANKMSGSRSV1>\n<STMTTRNRS>\n")
+	ofx.element("TRNUID", "0")
+	ofx.status()
+	ofx.raw("<STMTRS>\n")
+	ofx.element("CURDEF", statement.Currency)
+	ofx.raw("<BANKACCTFROM>\n")
+	ofx.element("BANKID", bankID)
+	ofx.element("ACCTID", strconv.FormatInt(statement.AccountID, 10))
+	ofx.element("ACCTTYPE", "CHECKING")
+	ofx.raw("</BANKACCTFROM>\n")
+
+	ofx.raw("<BANKTRANLIST>\n")
+	ofx.element("DTSTART", ofxTime(statement.From))
+	ofx.element("DTEND", ofxTime(statement.To))
+	for _, transaction := range statement.Transactions {
+		trnType := "CREDIT"
+		if transaction.Amount < 0 {
+			trnType = "DEBIT"
+		}
+
+		name := []rune(transaction.Payee)
+		if len(name) > ofxNameLength {
+			name = name[:ofxNameLength]
+		}
+
+		ofx.raw("<STMTTRN>\n")
+		ofx.element("TRNTYPE", trnType)
+		ofx.element("DTPOSTED", ofxTime(transaction.PostedAt))
+		ofx.element("TRNAMT", formatAmount(transaction.Amount))
+		ofx.element("FITID", transaction.ID)
+		ofx.element("REFNUM", transaction.Reference)
+		ofx.element("NAME", string(name))
+		ofx.element("MEMO", transaction.Memo)
+		ofx.raw("</STMTTRN>\n")
+	}
+	ofx.raw("</BANKTRANLIST>\n")
+
+	ofx.raw("<LEDGERBAL>\n")
+	ofx.element("BALAMT", formatAmount(statement.ClosingBalance))
+	ofx.element("DTASOF", ofxTime(statement.To))
+	ofx.raw("</LEDGERBAL>\n")
+
+	ofx.raw("</STMTRS>\n</STMTTRNRS>\n</BANKMSGSRSV1>\n</OFX>\n")
+
+	if ofx.err != nil {
+		return ofx.err
+	}
+	return ofx.w.Flush()
+}
@@ -0,0 +1,39 @@
+package statement
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// qifDateFormat is the format of the dates of QIF, the one of Quicken in the US.
+const qifDateFormat = "01/02/2006"
+
+// The `writeQIF` function writes the statement as a QIF bank account, a transaction per record ended by
+// `^`. QIF has no ids, so the applications tell the transactions imported already by their date and
+// amount.
+func writeQIF(w io.Writer, statement Statement) error {
+	writer := bufio.NewWriter(w)
+	writer.WriteString("!Type:Bank\n")
+
+	// the fields are lines, so a memo can't span several of them
+	line := func(code byte, value string) {
+		if value == "" {
+			return
+		}
+		writer.WriteByte(code)
+		writer.WriteString(strings.Join(strings.Fields(value), " "))
+		writer.WriteByte('\n')
+	}
+
+	for _, transaction := range statement.Transactions {
+		line('D', transaction.PostedAt.UTC().Format(qifDateFormat))
+		line('T', formatAmount(transaction.Amount))
+		line('N', transaction.Reference)
+		line('P', transaction.Payee)
+		line('M', transaction.Memo)
+		writer.WriteString("^\n")
+	}
+
+	return writer.Flush()
+}
@@ -0,0 +1,88 @@
+package statement
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// The Format type is the format of the files personal finance applications such as Quicken or YNAB
+// import transactions from.
+type Format string
+
+const (
+	FormatOFX Format = "ofx"
+	FormatQIF Format = "qif"
+	FormatCSV Format = "csv"
+)
+
+// bankID identifies the bank in the files that ask for one, the accounts being numbered by their id.
+const bankID = "GOBANK"
+
+// The Transaction type is a line of a statement, the entry an account got for a transfer or otherwise.
+// @property {string} ID - the id of the entry, which the applications use to skip the transactions
+// imported already.
+// @property {int64} Amount - negative when money left the account.
+// @property {string} Payee - the other party of the transfer, empty for the entries made without one.
+// @property {string} Reference - the reference of the payment outside the bank, e.g. an invoice number.
+type Transaction struct {
+	ID                    string
+	PostedAt              time.Time
+	Amount                int64
+	Payee                 string
+	Memo                  string
+	Reference             string
+	CounterpartyAccountID int64
+}
+
+// The Statement type holds the transactions of an account over a period, oldest first.
+// @property {time.Time} From - start (inclusive) of the period.
+// @property {time.Time} To - end (exclusive) of the period.
+// @property {int64} ClosingBalance - the balance of the account at the end of the period.
+type Statement struct {
+	AccountID      int64
+	Currency       string
+	From           time.Time
+	To             time.Time
+	ClosingBalance int64
+	Transactions   []Transaction
+}
+
+// The `OpeningBalance` method returns the balance of the account at the start of the period.
+func (statement Statement) OpeningBalance() int64 {
+	balance := statement.ClosingBalance
+	for _, transaction := range statement.Transactions {
+		balance -= transaction.Amount
+	}
+	return balance
+}
+
+// The `ContentType` method returns the media type of the files of the format.
+func (format Format) ContentType() string {
+	switch format {
+	case FormatOFX:
+		return "application/x-ofx"
+	case FormatQIF:
+		return "application/qif"
+	}
+	return "text/csv"
+}
+
+// The `Write` function writes `statement` to `w` in `format`.
+func Write(w io.Writer, format Format, statement Statement) error {
+	switch format {
+	case FormatOFX:
+		return writeOFX(w, statement)
+	case FormatQIF:
+		return writeQIF(w, statement)
+	case FormatCSV:
+		return writeCSV(w, statement)
+	}
+	return fmt.Errorf("unsupported format %q", format)
+}
+
+// formatAmount formats an amount with the two decimals the applications expect, the amounts being
+// whole units of the currency.
+func formatAmount(amount int64) string {
+	return fmt.Sprintf("%d.00", amount)
+}
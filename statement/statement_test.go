@@ -0,0 +1,101 @@
+package statement
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestStatement() Statement {
+	from := time.Date(2026, time.September, 1, 0, 0, 0, 0, time.UTC)
+	return Statement{
+		AccountID:      42,
+		Currency:       "CAD",
+		From:           from,
+		To:             from.AddDate(0, 1, 0),
+		ClosingBalance: 150,
+		Transactions: []Transaction{
+			{
+				ID:                    "7",
+				PostedAt:              from.Add(36 * time.Hour),
+				Amount:                200,
+				Payee:                 "Jane & John Doe",
+				Memo:                  "rent\nfor september",
+				Reference:             "INV-42",
+				CounterpartyAccountID: 12,
+			},
+			{
+				ID:       "9",
+				PostedAt: from.AddDate(0, 0, 3),
+				Amount:   -60,
+			},
+		},
+	}
+}
+
+func TestOpeningBalance(t *testing.T) {
+	require.Equal(t, int64(10), newTestStatement().OpeningBalance())
+	require.Equal(t, int64(5), Statement{ClosingBalance: 5}.OpeningBalance())
+}
+
+func TestWriteOFX(t *testing.T) {
+	var buffer bytes.Buffer
+	require.NoError(t, Write(&buffer, FormatOFX, newTestStatement()))
+
+	ofx := buffer.String()
+	require.True(t, strings.HasPrefix(ofx, `<?xml version="1.0"`))
+	require.Contains(t, ofx, "<CURDEF>CAD</CURDEF>")
+	require.Contains(t, ofx, "<ACCTID>42</ACCTID>")
+	require.Contains(t, ofx, "<DTSTART>20260901000000.000[0:GMT]</DTSTART>")
+	require.Contains(t, ofx, "<DTEND>20261001000000.000[0:GMT]</DTEND>")
+	require.Contains(t, ofx, "<TRNTYPE>CREDIT</TRNTYPE>\n<DTPOSTED>20260902120000.000[0:GMT]</DTPOSTED>\n<TRNAMT>200.00</TRNAMT>\n<FITID>7</FITID>\n<REFNUM>INV-42</REFNUM>\n<NAME>Jane &amp; John Doe</NAME>\n")
+	require.Contains(t, ofx, "<TRNTYPE>DEBIT</TRNTYPE>\n<DTPOSTED>20260904000000.000[0:GMT]</DTPOSTED>\n<TRNAMT>-60.00</TRNAMT>\n<FITID>9</FITID>\n</STMTTRN>")
+	require.Contains(t, ofx, "<BALAMT>150.00</BALAMT>")
+
+	// the document is well formed
+	decoder := xml.NewDecoder(&buffer)
+	for {
+		_, err := decoder.Token()
+		if err != nil {
+			require.ErrorContains(t, err, "EOF")
+			break
+		}
+	}
+}
+
+func TestWriteOFXLongPayee(t *testing.T) {
+	statement := newTestStatement()
+	statement.Transactions[0].Payee = strings.Repeat("é", 40)
+
+	var buffer bytes.Buffer
+	require.NoError(t, Write(&buffer, FormatOFX, statement))
+	require.Contains(t, buffer.String(), "<NAME>"+strings.Repeat("é", ofxNameLength)+"</NAME>")
+}
+
+func TestWriteQIF(t *testing.T) {
+	var buffer bytes.Buffer
+	require.NoError(t, Write(&buffer, FormatQIF, newTestStatement()))
+
+	require.Equal(t, "!Type:Bank\n"+
+		"D09/02/2026\nT200.00\nNINV-42\nPJane & John Doe\nMrent for september\n^\n"+
+		"D09/04/2026\nT-60.00\n^\n", buffer.String())
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buffer bytes.Buffer
+	require.NoError(t, Write(&buffer, FormatCSV, newTestStatement()))
+
+	require.Equal(t, "id,date,amount,balance,payee,memo,reference,counterparty_account_id\n"+
+		"7,2026-09-02T12:00:00Z,200.00,210.00,Jane & John Doe,\"rent\nfor september\",INV-42,12\n"+
+		"9,2026-09-04T00:00:00Z,-60.00,150.00,,,,\n", buffer.String())
+}
+
+func TestWriteUnsupportedFormat(t *testing.T) {
+	var buffer bytes.Buffer
+	require.Error(t, Write(&buffer, Format("xlsx"), newTestStatement()))
+	require.Zero(t, buffer.Len())
+}
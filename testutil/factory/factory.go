@@ -0,0 +1,260 @@
+// Package factory builds the records of the bank with random defaults for the tests, each builder taking
+// overrides for the fields a test cares about. The records are only built, the tests store them or
+// return them from the mocked store as they need.
+package factory
+
+import (
+	db "go-backend/db/sqlc"
+	"go-backend/util"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+// User builds a depositor of the default organization with a random username, full name and email, and
+// no password.
+func User(overrides ...func(*db.User)) db.User {
+	user := db.User{
+		Username: util.RandomOwner(),
+		FullName: util.RandomOwner(),
+		Email:    util.RandomEmail(),
+		Role:     util.DepositorRole,
+		OrgID:    db.DefaultOrganizationID,
+	}
+	for _, override := range overrides {
+		override(&user)
+	}
+	return user
+}
+
+// UserWithPassword builds a user like User, with the hash of a random password that it returns too.
+func UserWithPassword(t testing.TB, overrides ...func(*db.User)) (db.User, string) {
+	password := util.RandomString(6)
+	hashedPassword, err := util.HashPassword(password)
+	require.NoError(t, err)
+
+	user := User(overrides...)
+	user.HashedPassword = hashedPassword
+	return user, password
+}
+
+// InOrganization overrides the organization of a user.
+func InOrganization(orgID int64) func(*db.User) {
+	return func(user *db.User) {
+		user.OrgID = orgID
+	}
+}
+
+// WithRole overrides the role of a user.
+func WithRole(role string) func(*db.User) {
+	return func(user *db.User) {
+		user.Role = role
+	}
+}
+
+// Account builds an account of a random owner of the default organization, with a random id, balance and
+// currency.
+func Account(overrides ...func(*db.Account)) db.Account {
+	account := db.Account{
+		ID:       util.RandomInt(1, 1000),
+		Owner:    util.RandomOwner(),
+		Balance:  util.RandomMoney(),
+		Currency: util.RandomCurrency(),
+		Version:  1,
+		OrgID:    db.DefaultOrganizationID,
+		Tier:     db.AccountTierStandard,
+	}
+	for _, override := range overrides {
+		override(&account)
+	}
+	if account.AccountNumber == "" {
+		account.AccountNumber = util.AccountNumber(account.ID)
+	}
+	return account
+}
+
+// OwnedBy overrides the owner of an account.
+func OwnedBy(owner string) func(*db.Account) {
+	return func(account *db.Account) {
+		account.Owner = owner
+	}
+}
+
+// InCurrency overrides the currency of an account.
+func InCurrency(currency string) func(*db.Account) {
+	return func(account *db.Account) {
+		account.Currency = currency
+	}
+}
+
+// Entry builds an entry of a random amount on a random account.
+func Entry(overrides ...func(*db.Entry)) db.Entry {
+	entry := db.Entry{
+		ID:        util.RandomInt(1, 1000),
+		AccountID: util.RandomInt(1, 1000),
+		Amount:    util.RandomMoney(),
+	}
+	for _, override := range overrides {
+		override(&entry)
+	}
+	return entry
+}
+
+// OfAccount overrides the account of an entry.
+func OfAccount(account db.Account) func(*db.Entry) {
+	return func(entry *db.Entry) {
+		entry.AccountID = account.ID
+	}
+}
+
+// Transfer builds a transfer of a random amount between random accounts, with a random memo and
+// external reference.
+func Transfer(overrides ...func(*db.Transfer)) db.Transfer {
+	transfer := db.Transfer{
+		ID:                util.RandomInt(1, 1000),
+		FromAccountID:     util.RandomInt(1, 1000),
+		ToAccountID:       util.RandomInt(1, 1000),
+		Amount:            util.RandomMoney(),
+		Memo:              util.RandomString(12),
+		ExternalReference: util.RandomString(8),
+	}
+	for _, override := range overrides {
+		override(&transfer)
+	}
+	return transfer
+}
+
+// Between overrides the accounts of a transfer.
+func Between(fromAccount db.Account, toAccount db.Account) func(*db.Transfer) {
+	return func(transfer *db.Transfer) {
+		transfer.FromAccountID = fromAccount.ID
+		transfer.ToAccountID = toAccount.ID
+	}
+}
+
+// Beneficiary builds a beneficiary of a random owner, saving a random account under a random nickname.
+func Beneficiary(overrides ...func(*db.Beneficiary)) db.Beneficiary {
+	beneficiary := db.Beneficiary{
+		ID:        util.RandomInt(1, 1000),
+		Owner:     util.RandomOwner(),
+		AccountID: util.RandomInt(1, 1000),
+		Nickname:  util.RandomString(8),
+	}
+	for _, override := range overrides {
+		override(&beneficiary)
+	}
+	return beneficiary
+}
+
+// SavedBy overrides the owner of a beneficiary.
+func SavedBy(owner string) func(*db.Beneficiary) {
+	return func(beneficiary *db.Beneficiary) {
+		beneficiary.Owner = owner
+	}
+}
+
+// PaymentRequest builds a pending payment request of a random amount between random users, expiring in
+// a day.
+func PaymentRequest(overrides ...func(*db.PaymentRequest)) db.PaymentRequest {
+	request := db.PaymentRequest{
+		ID:          util.RandomInt(1, 1000),
+		Requester:   util.RandomOwner(),
+		Payer:       util.RandomOwner(),
+		ToAccountID: util.RandomInt(1, 1000),
+		Amount:      util.RandomInt(1, 1000),
+		Memo:        util.RandomString(12),
+		Status:      db.PaymentRequestPending,
+		ExpiresAt:   time.Now().Add(24 * time.Hour),
+	}
+	for _, override := range overrides {
+		override(&request)
+	}
+	return request
+}
+
+// PaidInto overrides the account a payment request is paid into, and its requester with the owner of
+// the account.
+func PaidInto(account db.Account) func(*db.PaymentRequest) {
+	return func(request *db.PaymentRequest) {
+		request.Requester = account.Owner
+		request.ToAccountID = account.ID
+	}
+}
+
+// PaidBy overrides the payer of a payment request.
+func PaidBy(payer string) func(*db.PaymentRequest) {
+	return func(request *db.PaymentRequest) {
+		request.Payer = payer
+	}
+}
+
+// Mandate builds an active mandate of the owner of the from account, letting the merchant account pull
+// up to 1000 at once and 5000 in total from it.
+func Mandate(from db.Account, merchant db.Account, overrides ...func(*db.Mandate)) db.Mandate {
+	mandate := db.Mandate{
+		ID:                util.RandomInt(1, 1000),
+		Payer:             from.Owner,
+		FromAccountID:     from.ID,
+		MerchantAccountID: merchant.ID,
+		MaxAmount:         1000,
+		CapAmount:         pgtype.Int8{Int64: 5000, Valid: true},
+		Status:            db.MandateActive,
+	}
+	for _, override := range overrides {
+		override(&mandate)
+	}
+	return mandate
+}
+
+// StandingOrder builds an active monthly standing order of the owner of the from account, sending 100 to
+// the to account, due now.
+func StandingOrder(from db.Account, to db.Account, overrides ...func(*db.StandingOrder)) db.StandingOrder {
+	now := time.Now()
+	order := db.StandingOrder{
+		ID:            util.RandomInt(1, 1000),
+		Owner:         from.Owner,
+		FromAccountID: from.ID,
+		ToAccountID:   to.ID,
+		Amount:        100,
+		Currency:      from.Currency,
+		Frequency:     db.StandingOrderMonthly,
+		StartsAt:      now,
+		NextRunAt:     now,
+		Status:        db.StandingOrderActive,
+	}
+	for _, override := range overrides {
+		override(&order)
+	}
+	return order
+}
+
+// PendingTransfer builds a transfer of a random amount between random accounts awaiting approval,
+// expiring in a day.
+func PendingTransfer(overrides ...func(*db.PendingTransfer)) db.PendingTransfer {
+	pending := db.PendingTransfer{
+		ID:            util.RandomInt(1, 1000),
+		Owner:         util.RandomOwner(),
+		FromAccountID: util.RandomInt(1, 1000),
+		ToAccountID:   util.RandomInt(1, 1000),
+		Amount:        util.RandomInt(1000, 10000),
+		Memo:          util.RandomString(12),
+		Status:        db.PendingTransferAwaitingApproval,
+		ExpiresAt:     time.Now().Add(24 * time.Hour),
+	}
+	for _, override := range overrides {
+		override(&pending)
+	}
+	return pending
+}
+
+// PendingBetween overrides the accounts of a pending transfer, and its owner with the owner of the from
+// account.
+func PendingBetween(fromAccount db.Account, toAccount db.Account) func(*db.PendingTransfer) {
+	return func(pending *db.PendingTransfer) {
+		pending.Owner = fromAccount.Owner
+		pending.FromAccountID = fromAccount.ID
+		pending.ToAccountID = toAccount.ID
+	}
+}
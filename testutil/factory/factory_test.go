@@ -0,0 +1,69 @@
+package factory
+
+import (
+	db "go-backend/db/sqlc"
+	"go-backend/util"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUser(t *testing.T) {
+	user := User()
+	require.NotEmpty(t, user.Username)
+	require.Equal(t, util.DepositorRole, user.Role)
+	require.Empty(t, user.HashedPassword)
+
+	admin, password := UserWithPassword(t, WithRole(util.AdminRole))
+	require.Equal(t, util.AdminRole, admin.Role)
+	require.NoError(t, util.Checkpassword(password, admin.HashedPassword))
+}
+
+func TestAccount(t *testing.T) {
+	account := Account(OwnedBy("owner"), InCurrency(util.CAD), func(account *db.Account) {
+		account.Balance = 0
+	})
+	require.NotZero(t, account.ID)
+	require.Equal(t, "owner", account.Owner)
+	require.Equal(t, util.CAD, account.Currency)
+	require.Zero(t, account.Balance)
+}
+
+func TestEntryAndTransfer(t *testing.T) {
+	fromAccount := Account()
+	toAccount := Account()
+
+	entry := Entry(OfAccount(fromAccount))
+	require.Equal(t, fromAccount.ID, entry.AccountID)
+
+	transfer := Transfer(Between(fromAccount, toAccount))
+	require.Equal(t, fromAccount.ID, transfer.FromAccountID)
+	require.Equal(t, toAccount.ID, transfer.ToAccountID)
+}
+
+func TestPaymentRequest(t *testing.T) {
+	account := Account()
+	payer := User()
+
+	request := PaymentRequest(PaidInto(account), PaidBy(payer.Username))
+	require.Equal(t, account.Owner, request.Requester)
+	require.Equal(t, account.ID, request.ToAccountID)
+	require.Equal(t, payer.Username, request.Payer)
+	require.Equal(t, db.PaymentRequestPending, request.Status)
+	require.Positive(t, request.Amount)
+	require.True(t, request.ExpiresAt.After(time.Now()))
+}
+
+func TestPendingTransfer(t *testing.T) {
+	fromAccount := Account()
+	toAccount := Account()
+
+	pending := PendingTransfer(PendingBetween(fromAccount, toAccount))
+	require.Equal(t, fromAccount.Owner, pending.Owner)
+	require.Equal(t, fromAccount.ID, pending.FromAccountID)
+	require.Equal(t, toAccount.ID, pending.ToAccountID)
+	require.Equal(t, db.PendingTransferAwaitingApproval, pending.Status)
+	require.Positive(t, pending.Amount)
+	require.True(t, pending.ExpiresAt.After(time.Now()))
+}
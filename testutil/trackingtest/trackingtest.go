@@ -0,0 +1,57 @@
+// Package trackingtest records the events sent to the error tracking in the tests, instead of sending
+// them to Sentry.
+package trackingtest
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/stretchr/testify/require"
+)
+
+// The Transport type records the events the error tracking sends.
+type Transport struct {
+	mutex  sync.Mutex
+	events []*sentry.Event
+}
+
+func (transport *Transport) Configure(options sentry.ClientOptions) {}
+
+func (transport *Transport) Flush(timeout time.Duration) bool {
+	return true
+}
+
+func (transport *Transport) SendEvent(event *sentry.Event) {
+	transport.mutex.Lock()
+	defer transport.mutex.Unlock()
+
+	transport.events = append(transport.events, event)
+}
+
+// The `Events` method returns the events sent so far.
+func (transport *Transport) Events() []*sentry.Event {
+	transport.mutex.Lock()
+	defer transport.mutex.Unlock()
+
+	return append([]*sentry.Event(nil), transport.events...)
+}
+
+// Record binds a client recording its events to the current hub until the end of the test, so that the
+// errors captured by the code under test can be checked. The tests using it must not run in parallel.
+func Record(t *testing.T) *Transport {
+	transport := &Transport{}
+	client, err := sentry.NewClient(sentry.ClientOptions{
+		Dsn:              "https://public@sentry.example.com/1",
+		Transport:        transport,
+		AttachStacktrace: true,
+	})
+	require.NoError(t, err)
+
+	sentry.CurrentHub().BindClient(client)
+	t.Cleanup(func() {
+		sentry.CurrentHub().BindClient(nil)
+	})
+	return transport
+}
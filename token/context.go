@@ -0,0 +1,17 @@
+package token
+
+import "context"
+
+type payloadKey struct{}
+
+// The `NewContext` function returns a copy of the context carrying the payload of the token the request
+// was authenticated with, for the code only given the context to read its claims.
+func NewContext(ctx context.Context, payload *Payload) context.Context {
+	return context.WithValue(ctx, payloadKey{}, payload)
+}
+
+// The `FromContext` function returns the payload of the token carried by the context, if any.
+func FromContext(ctx context.Context) (*Payload, bool) {
+	payload, ok := ctx.Value(payloadKey{}).(*Payload)
+	return payload, ok
+}
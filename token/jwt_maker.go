@@ -3,6 +3,7 @@ package token
 import (
 	"errors"
 	"fmt"
+	"go-backend/clock"
 	"time"
 
 	"github.com/golang-jwt/jwt"
@@ -12,24 +13,43 @@ const minSecretKeySize = 32
 
 type JWTMaker struct {
 	secretKey string
+	clock     clock.Clock
 }
 
 func NewJWTMaker(secretKey string) (Maker, error) {
+	return newJWTMaker(secretKey, clock.Real)
+}
+
+// The `newJWTMaker` function creates a maker of JWTs signed with the secret key, which are issued and
+// expire by the time of the clock.
+func newJWTMaker(secretKey string, clock clock.Clock) (Maker, error) {
 	if len(secretKey) < minSecretKeySize {
 		return nil, fmt.Errorf("invalid key size. must be at least length %d", minSecretKeySize)
 	}
 
-	return &JWTMaker{secretKey}, nil
+	return &JWTMaker{secretKey: secretKey, clock: clock}, nil
 }
 
-func (maker JWTMaker) CreateToken(username string, duration time.Duration) (string, *Payload, error) {
-	payload, err := NewPayload(username, duration)
+// The jwtClaims type is the payload of a JWT, validated by jwt at the time of the clock of the maker.
+// @property {*Payload} Payload - the payload the token carries.
+// @property {time.Time} now - the time the token is verified at.
+type jwtClaims struct {
+	*Payload
+	now time.Time
+}
+
+func (claims jwtClaims) Valid() error {
+	return claims.validAt(claims.now)
+}
+
+func (maker JWTMaker) CreateToken(claims Claims, duration time.Duration) (string, *Payload, error) {
+	payload, err := newPayload(claims, duration, maker.clock.Now())
 
 	if err != nil {
 		return "", payload, err
 	}
 
-	jwtToken := jwt.NewWithClaims(jwt.SigningMethodHS256, payload)
+	jwtToken := jwt.NewWithClaims(jwt.SigningMethodHS256, jwtClaims{Payload: payload})
 
 	token, err := jwtToken.SignedString([]byte(maker.secretKey))
 	return token, payload, err
@@ -46,7 +66,8 @@ func (maker JWTMaker) VerifyToken(token string) (*Payload, error) {
 		return []byte(maker.secretKey), nil
 	}
 
-	jwtToken, err := jwt.ParseWithClaims(token, &Payload{}, keyFunc)
+	claims := &jwtClaims{Payload: &Payload{}, now: maker.clock.Now()}
+	jwtToken, err := jwt.ParseWithClaims(token, claims, keyFunc)
 
 	if err != nil {
 		verr, ok := err.(*jwt.ValidationError)
@@ -56,11 +77,9 @@ func (maker JWTMaker) VerifyToken(token string) (*Payload, error) {
 		return nil, ErrInvalidToken
 	}
 
-	payload, ok := jwtToken.Claims.(*Payload)
-
-	if !ok {
+	if !jwtToken.Valid {
 		return nil, ErrInvalidToken
 	}
 
-	return payload, nil
+	return claims.Payload, nil
 }
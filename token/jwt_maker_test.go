@@ -1,11 +1,13 @@
 package token
 
 import (
+	"go-backend/clock"
 	"go-backend/util"
 	"testing"
 	"time"
 
 	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 )
 
@@ -19,7 +21,7 @@ func TestJWTMaker(t *testing.T) {
 	issuedAt := time.Now()
 	expiredAt := issuedAt.Add(duration)
 
-	token, payload, err := maker.CreateToken(username, duration)
+	token, payload, err := maker.CreateToken(Claims{Username: username}, duration)
 	require.NoError(t, err)
 	require.NotEmpty(t, token)
 
@@ -43,7 +45,7 @@ func TestExpiredJWTToken(t *testing.T) {
 	maker, err := NewJWTMaker(util.RandomString(32))
 	require.NoError(t, err)
 
-	token, payload, err := maker.CreateToken(util.RandomOwner(), -time.Minute)
+	token, payload, err := maker.CreateToken(Claims{Username: util.RandomOwner()}, -time.Minute)
 	require.NoError(t, err)
 	require.NotEmpty(t, token)
 	require.NotEmpty(t, payload)
@@ -54,10 +56,10 @@ func TestExpiredJWTToken(t *testing.T) {
 }
 
 func TestInvalidJWTTokenAlgNone(t *testing.T) {
-	payload, err := NewPayload(util.RandomOwner(), time.Minute)
+	payload, err := newPayload(Claims{Username: util.RandomOwner()}, time.Minute, time.Now())
 	require.NoError(t, err)
 
-	jwtToken := jwt.NewWithClaims(jwt.SigningMethodNone, payload)
+	jwtToken := jwt.NewWithClaims(jwt.SigningMethodNone, jwtClaims{Payload: payload})
 	token, err := jwtToken.SignedString(jwt.UnsafeAllowNoneSignatureType)
 	require.NoError(t, err)
 
@@ -69,3 +71,76 @@ func TestInvalidJWTTokenAlgNone(t *testing.T) {
 	require.EqualError(t, err, ErrInvalidToken.Error())
 	require.Nil(t, payload)
 }
+
+func TestJWTSessionToken(t *testing.T) {
+	maker, err := NewJWTMaker(util.RandomString(32))
+	require.NoError(t, err)
+
+	sessionID := uuid.New()
+	token, _, err := maker.CreateToken(Claims{Username: util.RandomOwner(), SessionID: sessionID}, time.Minute)
+	require.NoError(t, err)
+
+	payload, err := maker.VerifyToken(token)
+	require.NoError(t, err)
+	require.Equal(t, sessionID, payload.SessionID)
+}
+
+func TestJWTScopedToken(t *testing.T) {
+	maker, err := NewJWTMaker(util.RandomString(32))
+	require.NoError(t, err)
+
+	scopes := []string{ScopeAccountsRead}
+	token, _, err := maker.CreateToken(Claims{Username: util.RandomOwner(), SessionID: uuid.New(), Scopes: scopes}, time.Minute)
+	require.NoError(t, err)
+
+	payload, err := maker.VerifyToken(token)
+	require.NoError(t, err)
+	require.Equal(t, scopes, payload.Scopes)
+}
+
+func TestJWTImpersonationToken(t *testing.T) {
+	maker, err := NewJWTMaker(util.RandomString(32))
+	require.NoError(t, err)
+
+	impersonator := util.RandomOwner()
+	token, _, err := maker.CreateToken(Claims{Username: util.RandomOwner(), Impersonator: impersonator}, time.Minute)
+	require.NoError(t, err)
+
+	payload, err := maker.VerifyToken(token)
+	require.NoError(t, err)
+	require.Equal(t, impersonator, payload.Impersonator)
+}
+
+func TestJWTOrganizationToken(t *testing.T) {
+	maker, err := NewJWTMaker(util.RandomString(32))
+	require.NoError(t, err)
+
+	orgID := util.RandomInt(2, 100)
+	token, _, err := maker.CreateToken(Claims{Username: util.RandomOwner(), OrgID: orgID}, time.Minute)
+	require.NoError(t, err)
+
+	payload, err := maker.VerifyToken(token)
+	require.NoError(t, err)
+	require.Equal(t, orgID, payload.OrgID)
+	require.Equal(t, orgID, payload.Claims().OrgID)
+}
+
+func TestJWTTokenExpiresByClock(t *testing.T) {
+	issuedAt := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	fake := clock.NewFake(issuedAt)
+	maker, err := newJWTMaker(util.RandomString(32), fake)
+	require.NoError(t, err)
+
+	token, payload, err := maker.CreateToken(Claims{Username: util.RandomOwner()}, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, issuedAt, payload.IssuedAt)
+	require.Equal(t, issuedAt.Add(time.Minute), payload.ExpiredAt)
+
+	fake.Advance(time.Minute)
+	_, err = maker.VerifyToken(token)
+	require.NoError(t, err)
+
+	fake.Advance(time.Second)
+	_, err = maker.VerifyToken(token)
+	require.ErrorIs(t, err, ErrExpiredToken)
+}
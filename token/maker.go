@@ -1,8 +1,98 @@
 package token
 
-import "time"
+import (
+	"fmt"
+	"go-backend/clock"
+	"time"
+)
 
 type Maker interface {
-	CreateToken(username string, duration time.Duration) (string, *Payload, error)
+	// CreateToken creates a token asserting the claims about its user, valid for the duration.
+	CreateToken(claims Claims, duration time.Duration) (string, *Payload, error)
 	VerifyToken(token string) (*Payload, error)
 }
+
+// Kinds of the tokens a maker creates, selected with the TOKEN_KIND config. Switching kinds invalidates the
+// tokens already issued, their users having to log in again.
+const (
+	// KindPasetoV2Local is the kind of the PASETO v2.local tokens encrypted with the symmetric key.
+	KindPasetoV2Local = "paseto_v2_local"
+	// KindPasetoV4Local is the kind of the PASETO v4.local tokens encrypted with the symmetric key.
+	KindPasetoV4Local = "paseto_v4_local"
+	// KindPasetoV4Public is the kind of the PASETO v4.public tokens signed with the private key, which other
+	// services verify with the public key without sharing any secret.
+	KindPasetoV4Public = "paseto_v4_public"
+)
+
+// The MakerConfig type configures the maker returned by `NewMaker`.
+// @property {string} Kind - the kind of the tokens, KindPasetoV2Local when empty.
+// @property {string} SymmetricKey - the key of the local kinds.
+// @property {string} PrivateKey - the Ed25519 private key in hex signing the public tokens.
+// @property {string} PublicKey - the Ed25519 public key in hex verifying the public tokens, only used
+// without PrivateKey, the maker then only verifying tokens.
+// @property {string} KeyID - the id of the current key, written in the footer of the tokens.
+// @property {string} VerificationKeys - the previous keys still verifying tokens, as `id=key` pairs
+// separated by commas, symmetric keys for the local kinds and public keys in hex for the public one.
+// @property {clock.Clock} Clock - the clock the tokens are issued and expire by, the real one when nil.
+type MakerConfig struct {
+	Kind             string
+	SymmetricKey     string
+	PrivateKey       string
+	PublicKey        string
+	KeyID            string
+	VerificationKeys string
+	Clock            clock.Clock
+}
+
+// The function creates the maker of the kind of tokens of the config. Given a key id or verification
+// keys, it is a `RotatingMaker` verifying the tokens of the previous keys as well.
+func NewMaker(config MakerConfig) (Maker, error) {
+	current, err := newKindMaker(config)
+	if err != nil {
+		return nil, err
+	}
+	if config.KeyID == "" && config.VerificationKeys == "" {
+		return current, nil
+	}
+
+	keys, err := parseVerificationKeys(config.VerificationKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	verifiers := make(map[string]Maker, len(keys))
+	for keyID, key := range keys {
+		if keyID == config.KeyID {
+			return nil, fmt.Errorf("verification key %q is the current key", keyID)
+		}
+
+		verifier := MakerConfig{Kind: config.Kind, SymmetricKey: key, PublicKey: key, Clock: config.Clock}
+		verifiers[keyID], err = newKindMaker(verifier)
+		if err != nil {
+			return nil, fmt.Errorf("invalid verification key %q: %w", keyID, err)
+		}
+	}
+
+	return NewRotatingMaker(current, config.KeyID, verifiers), nil
+}
+
+// The `newKindMaker` function creates the maker of the kind of the config with its current key.
+func newKindMaker(config MakerConfig) (Maker, error) {
+	makerClock := config.Clock
+	if makerClock == nil {
+		makerClock = clock.Real
+	}
+
+	switch config.Kind {
+	case "", KindPasetoV2Local:
+		return newPasetoMaker(config.SymmetricKey, config.KeyID, makerClock)
+	case KindPasetoV4Local:
+		return newPasetoV4LocalMaker(config.SymmetricKey, config.KeyID, makerClock)
+	case KindPasetoV4Public:
+		if config.PrivateKey == "" {
+			return newPasetoV4Verifier(config.PublicKey, config.KeyID, makerClock)
+		}
+		return newPasetoV4PublicMaker(config.PrivateKey, config.KeyID, makerClock)
+	}
+	return nil, fmt.Errorf("unknown token kind %q", config.Kind)
+}
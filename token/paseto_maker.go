@@ -2,6 +2,7 @@ package token
 
 import (
 	"fmt"
+	"go-backend/clock"
 	"time"
 
 	"github.com/aead/chacha20poly1305"
@@ -11,9 +12,17 @@ import (
 type PasetoMaker struct {
 	paseto       *paseto.V2
 	symmetricKey []byte
+	keyID        string
+	clock        clock.Clock
 }
 
 func NewPasetoMaker(symmetricKey string) (Maker, error) {
+	return newPasetoMaker(symmetricKey, "", clock.Real)
+}
+
+// The function creates a maker whose tokens are marked with the id of the key, see `RotatingMaker`, and
+// expire by the time of the clock.
+func newPasetoMaker(symmetricKey string, keyID string, clock clock.Clock) (*PasetoMaker, error) {
 	if len(symmetricKey) != chacha20poly1305.KeySize {
 		return nil, fmt.Errorf("invalid key size. must be at least length %d", chacha20poly1305.KeySize)
 	}
@@ -21,22 +30,32 @@ func NewPasetoMaker(symmetricKey string) (Maker, error) {
 	maker := &PasetoMaker{
 		paseto:       paseto.NewV2(),
 		symmetricKey: []byte(symmetricKey),
+		keyID:        keyID,
+		clock:        clock,
 	}
 
 	return maker, nil
 }
 
-func (maker PasetoMaker) CreateToken(username string, duration time.Duration) (string, *Payload, error) {
-	payload, err := NewPayload(username, duration)
+func (maker PasetoMaker) CreateToken(claims Claims, duration time.Duration) (string, *Payload, error) {
+	payload, err := newPayload(claims, duration, maker.clock.Now())
 
 	if err != nil {
 		return "", payload, err
 	}
 
-	token, err := maker.paseto.Encrypt(maker.symmetricKey, payload, nil)
+	token, err := maker.encrypt(payload)
 	return token, payload, err
 }
 
+func (maker PasetoMaker) encrypt(payload *Payload) (string, error) {
+	payload.KeyID = maker.keyID
+	if maker.keyID == "" {
+		return maker.paseto.Encrypt(maker.symmetricKey, payload, nil)
+	}
+	return maker.paseto.Encrypt(maker.symmetricKey, payload, keyFooter{KeyID: maker.keyID})
+}
+
 func (maker PasetoMaker) VerifyToken(token string) (*Payload, error) {
 	payload := &Payload{}
 
@@ -46,7 +65,7 @@ func (maker PasetoMaker) VerifyToken(token string) (*Payload, error) {
 		return nil, ErrInvalidToken
 	}
 
-	err = payload.Valid()
+	err = payload.validAt(maker.clock.Now())
 	if err != nil {
 		return nil, err
 	}
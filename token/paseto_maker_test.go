@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 )
 
@@ -18,7 +19,7 @@ func TestPasetoMaker(t *testing.T) {
 	issuedAt := time.Now()
 	expiredAt := issuedAt.Add(duration)
 
-	token, payload, err := maker.CreateToken(username, duration)
+	token, payload, err := maker.CreateToken(Claims{Username: username}, duration)
 	require.NoError(t, err)
 	require.NotEmpty(t, token)
 	require.NotEmpty(t, payload)
@@ -37,7 +38,7 @@ func TestExpiredPasetoToken(t *testing.T) {
 	maker, err := NewPasetoMaker(util.RandomString(32))
 	require.NoError(t, err)
 
-	token, payload, err := maker.CreateToken(util.RandomOwner(), -time.Minute)
+	token, payload, err := maker.CreateToken(Claims{Username: util.RandomOwner()}, -time.Minute)
 	require.NoError(t, err)
 	require.NotEmpty(t, token)
 	require.NotEmpty(t, payload)
@@ -46,3 +47,65 @@ func TestExpiredPasetoToken(t *testing.T) {
 	require.Error(t, err)
 	require.Nil(t, payload)
 }
+
+func TestPasetoSessionToken(t *testing.T) {
+	maker, err := NewPasetoMaker(util.RandomString(32))
+	require.NoError(t, err)
+
+	sessionID := uuid.New()
+	token, _, err := maker.CreateToken(Claims{Username: util.RandomOwner(), SessionID: sessionID}, time.Minute)
+	require.NoError(t, err)
+
+	payload, err := maker.VerifyToken(token)
+	require.NoError(t, err)
+	require.Equal(t, sessionID, payload.SessionID)
+}
+
+func TestPasetoScopedToken(t *testing.T) {
+	maker, err := NewPasetoMaker(util.RandomString(32))
+	require.NoError(t, err)
+
+	scopes := []string{ScopeAccountsRead, ScopeTransfersRead}
+	token, _, err := maker.CreateToken(Claims{Username: util.RandomOwner(), SessionID: uuid.New(), Scopes: scopes}, time.Minute)
+	require.NoError(t, err)
+
+	payload, err := maker.VerifyToken(token)
+	require.NoError(t, err)
+	require.Equal(t, scopes, payload.Scopes)
+	require.True(t, payload.Scoped())
+	require.True(t, payload.HasScope(ScopeAccountsRead))
+	require.False(t, payload.HasScope(ScopeTransfersWrite))
+
+	// the tokens without scopes allow every request
+	token, _, err = maker.CreateToken(Claims{Username: util.RandomOwner()}, time.Minute)
+	require.NoError(t, err)
+	payload, err = maker.VerifyToken(token)
+	require.NoError(t, err)
+	require.False(t, payload.Scoped())
+	require.True(t, payload.HasScope(ScopeTransfersWrite))
+}
+
+func TestValidScope(t *testing.T) {
+	for _, scope := range Scopes {
+		require.True(t, ValidScope(scope))
+	}
+	require.False(t, ValidScope("accounts"))
+	require.False(t, ValidScope("admin:write"))
+}
+
+func TestPasetoImpersonationToken(t *testing.T) {
+	maker, err := NewPasetoMaker(util.RandomString(32))
+	require.NoError(t, err)
+
+	username := util.RandomOwner()
+	impersonator := util.RandomOwner()
+	token, _, err := maker.CreateToken(Claims{Username: username, Impersonator: impersonator}, time.Minute)
+	require.NoError(t, err)
+
+	payload, err := maker.VerifyToken(token)
+	require.NoError(t, err)
+	require.Equal(t, username, payload.Username)
+	require.Equal(t, impersonator, payload.Impersonator)
+	require.Equal(t, uuid.Nil, payload.SessionID)
+	require.False(t, payload.Scoped())
+}
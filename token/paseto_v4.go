@@ -0,0 +1,208 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"strings"
+
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/chacha20"
+)
+
+// Headers of the PASETO v4 tokens, v4.local ones being encrypted with a symmetric key and v4.public ones
+// signed with an Ed25519 private key, see https://github.com/paseto-standard/paseto-spec.
+const (
+	pasetoV4LocalHeader  = "v4.local."
+	pasetoV4PublicHeader = "v4.public."
+)
+
+const (
+	pasetoV4NonceSize = 32
+	pasetoV4MACSize   = 32
+)
+
+var errPasetoV4Token = errors.New("malformed PASETO v4 token")
+
+// The `pae` function is the Pre-Authentication Encoding of PASETO, encoding the pieces unambiguously for
+// them to be authenticated together: their count then each of them prefixed with its length, as 64 bits
+// little-endian integers whose most significant bit is cleared.
+func pae(pieces ...[]byte) []byte {
+	size := 8
+	for _, piece := range pieces {
+		size += 8 + len(piece)
+	}
+
+	out := make([]byte, 0, size)
+	out = binary.LittleEndian.AppendUint64(out, uint64(len(pieces))&^(1<<63))
+	for _, piece := range pieces {
+		out = binary.LittleEndian.AppendUint64(out, uint64(len(piece))&^(1<<63))
+		out = append(out, piece...)
+	}
+	return out
+}
+
+// The `splitPasetoToken` function returns the decoded body and footer of a token with `header`.
+func splitPasetoToken(token string, header string) ([]byte, []byte, error) {
+	if !strings.HasPrefix(token, header) {
+		return nil, nil, errPasetoV4Token
+	}
+
+	parts := strings.Split(token[len(header):], ".")
+	if len(parts) > 2 {
+		return nil, nil, errPasetoV4Token
+	}
+
+	body, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, nil, errPasetoV4Token
+	}
+	var footer []byte
+	if len(parts) == 2 {
+		footer, err = base64.RawURLEncoding.DecodeString(parts[1])
+		if err != nil {
+			return nil, nil, errPasetoV4Token
+		}
+	}
+	return body, footer, nil
+}
+
+// The `joinPasetoToken` function encodes the token of `header` made of the body and the footer.
+func joinPasetoToken(header string, body []byte, footer []byte) string {
+	token := header + base64.RawURLEncoding.EncodeToString(body)
+	if len(footer) > 0 {
+		token += "." + base64.RawURLEncoding.EncodeToString(footer)
+	}
+	return token
+}
+
+// The `pasetoV4Keys` function derives the encryption key, the nonce of XChaCha20 and the authentication
+// key of a v4.local token from the symmetric key and the random nonce of the token.
+func pasetoV4Keys(key []byte, nonce []byte) ([]byte, []byte, []byte, error) {
+	encryption, err := blake2b.New(56, key)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	encryption.Write([]byte("paseto-encryption-key"))
+	encryption.Write(nonce)
+	tmp := encryption.Sum(nil)
+
+	auth, err := blake2b.New(32, key)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	auth.Write([]byte("paseto-auth-key-for-aead"))
+	auth.Write(nonce)
+
+	return tmp[:32], tmp[32:], auth.Sum(nil), nil
+}
+
+// The `pasetoV4MAC` function authenticates the header, nonce, ciphertext, footer and implicit assertion
+// of a v4.local token.
+func pasetoV4MAC(authKey []byte, nonce []byte, ciphertext []byte, footer []byte, implicit []byte) ([]byte, error) {
+	mac, err := blake2b.New(pasetoV4MACSize, authKey)
+	if err != nil {
+		return nil, err
+	}
+	mac.Write(pae([]byte(pasetoV4LocalHeader), nonce, ciphertext, footer, implicit))
+	return mac.Sum(nil), nil
+}
+
+// The `pasetoV4Encrypt` function encrypts the message into a v4.local token with the 32 bytes key. The
+// nonce is random when nil, tests passing one to reproduce the vectors of the specification.
+func pasetoV4Encrypt(key []byte, message []byte, footer []byte, implicit []byte, nonce []byte) (string, error) {
+	if nonce == nil {
+		nonce = make([]byte, pasetoV4NonceSize)
+		if _, err := rand.Read(nonce); err != nil {
+			return "", err
+		}
+	}
+
+	encryptionKey, cipherNonce, authKey, err := pasetoV4Keys(key, nonce)
+	if err != nil {
+		return "", err
+	}
+	cipher, err := chacha20.NewUnauthenticatedCipher(encryptionKey, cipherNonce)
+	if err != nil {
+		return "", err
+	}
+	ciphertext := make([]byte, len(message))
+	cipher.XORKeyStream(ciphertext, message)
+
+	mac, err := pasetoV4MAC(authKey, nonce, ciphertext, footer, implicit)
+	if err != nil {
+		return "", err
+	}
+
+	body := make([]byte, 0, len(nonce)+len(ciphertext)+len(mac))
+	body = append(append(append(body, nonce...), ciphertext...), mac...)
+	return joinPasetoToken(pasetoV4LocalHeader, body, footer), nil
+}
+
+// The `pasetoV4Decrypt` function decrypts a v4.local token with the 32 bytes key once its MAC is checked,
+// returning its message and footer.
+func pasetoV4Decrypt(key []byte, token string, implicit []byte) ([]byte, []byte, error) {
+	body, footer, err := splitPasetoToken(token, pasetoV4LocalHeader)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(body) < pasetoV4NonceSize+pasetoV4MACSize {
+		return nil, nil, errPasetoV4Token
+	}
+
+	nonce := body[:pasetoV4NonceSize]
+	ciphertext := body[pasetoV4NonceSize : len(body)-pasetoV4MACSize]
+	mac := body[len(body)-pasetoV4MACSize:]
+
+	encryptionKey, cipherNonce, authKey, err := pasetoV4Keys(key, nonce)
+	if err != nil {
+		return nil, nil, err
+	}
+	expected, err := pasetoV4MAC(authKey, nonce, ciphertext, footer, implicit)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !hmac.Equal(mac, expected) {
+		return nil, nil, errPasetoV4Token
+	}
+
+	cipher, err := chacha20.NewUnauthenticatedCipher(encryptionKey, cipherNonce)
+	if err != nil {
+		return nil, nil, err
+	}
+	message := make([]byte, len(ciphertext))
+	cipher.XORKeyStream(message, ciphertext)
+	return message, footer, nil
+}
+
+// The `pasetoV4Sign` function signs the message into a v4.public token with the Ed25519 private key. The
+// message is readable by anyone, only its integrity being protected.
+func pasetoV4Sign(privateKey ed25519.PrivateKey, message []byte, footer []byte, implicit []byte) string {
+	signature := ed25519.Sign(privateKey, pae([]byte(pasetoV4PublicHeader), message, footer, implicit))
+
+	body := make([]byte, 0, len(message)+ed25519.SignatureSize)
+	body = append(append(body, message...), signature...)
+	return joinPasetoToken(pasetoV4PublicHeader, body, footer)
+}
+
+// The `pasetoV4Verify` function checks the signature of a v4.public token with the Ed25519 public key,
+// returning its message and footer.
+func pasetoV4Verify(publicKey ed25519.PublicKey, token string, implicit []byte) ([]byte, []byte, error) {
+	body, footer, err := splitPasetoToken(token, pasetoV4PublicHeader)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(body) < ed25519.SignatureSize {
+		return nil, nil, errPasetoV4Token
+	}
+
+	message := body[:len(body)-ed25519.SignatureSize]
+	signature := body[len(body)-ed25519.SignatureSize:]
+	if !ed25519.Verify(publicKey, pae([]byte(pasetoV4PublicHeader), message, footer, implicit), signature) {
+		return nil, nil, errPasetoV4Token
+	}
+	return message, footer, nil
+}
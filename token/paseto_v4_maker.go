@@ -0,0 +1,193 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-backend/clock"
+	"time"
+)
+
+// ErrVerifyOnly is returned when creating a token with a maker that only holds a public key, e.g. the one
+// of another service validating the tokens of the bank.
+var ErrVerifyOnly = errors.New("the token maker can only verify tokens, it has no private key")
+
+const pasetoV4KeySize = 32
+
+// The PasetoV4LocalMaker type creates PASETO v4.local tokens, encrypted with a symmetric key like those of
+// `PasetoMaker` but with the current version of the protocol.
+type PasetoV4LocalMaker struct {
+	symmetricKey []byte
+	keyID        string
+	clock        clock.Clock
+}
+
+// The function creates a maker of v4.local tokens encrypted with the 32 bytes key.
+func NewPasetoV4LocalMaker(symmetricKey string) (Maker, error) {
+	return newPasetoV4LocalMaker(symmetricKey, "", clock.Real)
+}
+
+func newPasetoV4LocalMaker(symmetricKey string, keyID string, clock clock.Clock) (*PasetoV4LocalMaker, error) {
+	if len(symmetricKey) != pasetoV4KeySize {
+		return nil, fmt.Errorf("invalid key size. must be at least length %d", pasetoV4KeySize)
+	}
+
+	return &PasetoV4LocalMaker{symmetricKey: []byte(symmetricKey), keyID: keyID, clock: clock}, nil
+}
+
+func (maker PasetoV4LocalMaker) CreateToken(claims Claims, duration time.Duration) (string, *Payload, error) {
+	payload, err := newPayload(claims, duration, maker.clock.Now())
+
+	if err != nil {
+		return "", payload, err
+	}
+
+	token, err := maker.encrypt(payload)
+	return token, payload, err
+}
+
+func (maker PasetoV4LocalMaker) encrypt(payload *Payload) (string, error) {
+	payload.KeyID = maker.keyID
+	message, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	footer, err := newKeyFooter(maker.keyID)
+	if err != nil {
+		return "", err
+	}
+	return pasetoV4Encrypt(maker.symmetricKey, message, footer, nil, nil)
+}
+
+func (maker PasetoV4LocalMaker) VerifyToken(token string) (*Payload, error) {
+	message, _, err := pasetoV4Decrypt(maker.symmetricKey, token, nil)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	return verifiedPayload(message, maker.clock.Now())
+}
+
+// The PasetoV4PublicMaker type creates PASETO v4.public tokens, signed with an Ed25519 private key, which
+// the other services verify with the public key alone, without holding any secret of the bank. The
+// payload of the tokens is signed, not encrypted: anyone holding a token can read it.
+// @property {ed25519.PrivateKey} privateKey - nil for the makers that only verify tokens.
+type PasetoV4PublicMaker struct {
+	privateKey ed25519.PrivateKey
+	publicKey  ed25519.PublicKey
+	keyID      string
+	clock      clock.Clock
+}
+
+// The function creates a maker of v4.public tokens signed with the Ed25519 private key, given in hex as its
+// 32 bytes seed or its 64 bytes key, and verified with its public key.
+func NewPasetoV4PublicMaker(privateKey string) (Maker, error) {
+	return newPasetoV4PublicMaker(privateKey, "", clock.Real)
+}
+
+func newPasetoV4PublicMaker(privateKey string, keyID string, clock clock.Clock) (*PasetoV4PublicMaker, error) {
+	key, err := ParseEd25519PrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PasetoV4PublicMaker{
+		privateKey: key,
+		publicKey:  key.Public().(ed25519.PublicKey),
+		keyID:      keyID,
+		clock:      clock,
+	}, nil
+}
+
+// The function creates a maker only verifying v4.public tokens with the Ed25519 public key, given in hex,
+// for the services validating the tokens the bank issues. Creating a token with it fails with
+// ErrVerifyOnly.
+func NewPasetoV4Verifier(publicKey string) (Maker, error) {
+	return newPasetoV4Verifier(publicKey, "", clock.Real)
+}
+
+func newPasetoV4Verifier(publicKey string, keyID string, clock clock.Clock) (*PasetoV4PublicMaker, error) {
+	key, err := hex.DecodeString(publicKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid public key. must be %d bytes in hex", ed25519.PublicKeySize)
+	}
+
+	return &PasetoV4PublicMaker{publicKey: key, keyID: keyID, clock: clock}, nil
+}
+
+// The `ParseEd25519PrivateKey` function parses an Ed25519 private key given in hex, as its 32 bytes seed
+// or its 64 bytes key.
+func ParseEd25519PrivateKey(privateKey string) (ed25519.PrivateKey, error) {
+	key, err := hex.DecodeString(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %w", err)
+	}
+
+	switch len(key) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(key), nil
+	case ed25519.PrivateKeySize:
+		return ed25519.PrivateKey(key), nil
+	}
+	return nil, fmt.Errorf("invalid private key. must be %d or %d bytes in hex", ed25519.SeedSize, ed25519.PrivateKeySize)
+}
+
+// The `PublicKey` function returns the public key the tokens of the maker are verified with, in hex, to be
+// given to the services validating them.
+func (maker PasetoV4PublicMaker) PublicKey() string {
+	return hex.EncodeToString(maker.publicKey)
+}
+
+func (maker PasetoV4PublicMaker) CreateToken(claims Claims, duration time.Duration) (string, *Payload, error) {
+	payload, err := newPayload(claims, duration, maker.clock.Now())
+
+	if err != nil {
+		return "", payload, err
+	}
+
+	token, err := maker.sign(payload)
+	return token, payload, err
+}
+
+func (maker PasetoV4PublicMaker) sign(payload *Payload) (string, error) {
+	if maker.privateKey == nil {
+		return "", ErrVerifyOnly
+	}
+
+	payload.KeyID = maker.keyID
+	message, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	footer, err := newKeyFooter(maker.keyID)
+	if err != nil {
+		return "", err
+	}
+	return pasetoV4Sign(maker.privateKey, message, footer, nil), nil
+}
+
+func (maker PasetoV4PublicMaker) VerifyToken(token string) (*Payload, error) {
+	message, _, err := pasetoV4Verify(maker.publicKey, token, nil)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	return verifiedPayload(message, maker.clock.Now())
+}
+
+// The `verifiedPayload` function decodes the payload of a token whose integrity was checked, and checks
+// that it hasn't expired at `now`.
+func verifiedPayload(message []byte, now time.Time) (*Payload, error) {
+	payload := &Payload{}
+	if err := json.Unmarshal(message, payload); err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	if err := payload.validAt(now); err != nil {
+		return nil, err
+	}
+
+	return payload, nil
+}
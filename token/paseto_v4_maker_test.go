@@ -0,0 +1,190 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"go-backend/clock"
+	"go-backend/util"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// the test vectors 4-E-1 and 4-S-1 of the PASETO specification
+const (
+	pasetoV4LocalVectorKey    = "707172737475767778797a7b7c7d7e7f808182838485868788898a8b8c8d8e8f"
+	pasetoV4LocalVectorToken  = "v4.local.AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAQAr68PS4AXe7If_ZgesdkUMvSwscFlAl1pk5HC0e8kApeaqMfGo_7OpBnwJOAbY9V7WU6abu74MmcUE8YWAiaArVI8XJ5hOb_4v9RmDkneN0S92dx0OW4pgy7omxgf3S8c3LlQg"
+	pasetoV4PublicVectorKey   = "b4cbfb43df4ce210727d953e4a713307fa19bb7d9f85041438d9e11b942a37741eb9dbbbbc047c03fd70604e0071f0987e16b28b757225c11f00415d0e20b1a2"
+	pasetoV4PublicVectorToken = "v4.public.eyJkYXRhIjoidGhpcyBpcyBhIHNpZ25lZCBtZXNzYWdlIiwiZXhwIjoiMjAyMi0wMS0wMVQwMDowMDowMCswMDowMCJ9bg_XBBzds8lTZShVlwwKSgeKpLT3yukTw6JUz3W4h_ExsQV-P0V54zemZDcAxFaSeef1QlXEFtkqxT1ciiQEDA"
+)
+
+func TestPasetoV4Vectors(t *testing.T) {
+	key, err := hex.DecodeString(pasetoV4LocalVectorKey)
+	require.NoError(t, err)
+	message := []byte(`{"data":"this is a secret message","exp":"2022-01-01T00:00:00+00:00"}`)
+
+	token, err := pasetoV4Encrypt(key, message, nil, nil, make([]byte, pasetoV4NonceSize))
+	require.NoError(t, err)
+	require.Equal(t, pasetoV4LocalVectorToken, token)
+
+	decrypted, _, err := pasetoV4Decrypt(key, token, nil)
+	require.NoError(t, err)
+	require.Equal(t, message, decrypted)
+
+	privateKey, err := ParseEd25519PrivateKey(pasetoV4PublicVectorKey)
+	require.NoError(t, err)
+	message = []byte(`{"data":"this is a signed message","exp":"2022-01-01T00:00:00+00:00"}`)
+
+	token = pasetoV4Sign(privateKey, message, nil, nil)
+	require.Equal(t, pasetoV4PublicVectorToken, token)
+
+	verified, _, err := pasetoV4Verify(privateKey.Public().(ed25519.PublicKey), token, nil)
+	require.NoError(t, err)
+	require.Equal(t, message, verified)
+}
+
+func TestPasetoV4LocalMaker(t *testing.T) {
+	maker, err := NewPasetoV4LocalMaker(util.RandomString(32))
+	require.NoError(t, err)
+
+	username := util.RandomOwner()
+	sessionID := uuid.New()
+	scopes := []string{ScopeAccountsRead}
+
+	token, _, err := maker.CreateToken(Claims{Username: username, SessionID: sessionID, Scopes: scopes}, time.Minute)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(token, pasetoV4LocalHeader))
+
+	payload, err := maker.VerifyToken(token)
+	require.NoError(t, err)
+	require.Equal(t, username, payload.Username)
+	require.Equal(t, sessionID, payload.SessionID)
+	require.Equal(t, scopes, payload.Scopes)
+
+	// the tokens of another key or another version aren't valid
+	other, err := NewPasetoV4LocalMaker(util.RandomString(32))
+	require.NoError(t, err)
+	_, err = other.VerifyToken(token)
+	require.ErrorIs(t, err, ErrInvalidToken)
+
+	v2, err := NewPasetoMaker(util.RandomString(32))
+	require.NoError(t, err)
+	v2Token, _, err := v2.CreateToken(Claims{Username: username}, time.Minute)
+	require.NoError(t, err)
+	_, err = maker.VerifyToken(v2Token)
+	require.ErrorIs(t, err, ErrInvalidToken)
+
+	_, err = NewPasetoV4LocalMaker(util.RandomString(31))
+	require.Error(t, err)
+}
+
+func TestPasetoV4PublicMaker(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	maker, err := NewPasetoV4PublicMaker(hex.EncodeToString(privateKey.Seed()))
+	require.NoError(t, err)
+	require.Equal(t, hex.EncodeToString(publicKey), maker.(*PasetoV4PublicMaker).PublicKey())
+
+	username := util.RandomOwner()
+	impersonator := util.RandomOwner()
+	token, _, err := maker.CreateToken(Claims{Username: username, Impersonator: impersonator}, time.Minute)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(token, pasetoV4PublicHeader))
+
+	// another service verifies the token with the public key alone, without issuing any
+	verifier, err := NewPasetoV4Verifier(hex.EncodeToString(publicKey))
+	require.NoError(t, err)
+
+	payload, err := verifier.VerifyToken(token)
+	require.NoError(t, err)
+	require.Equal(t, username, payload.Username)
+	require.Equal(t, impersonator, payload.Impersonator)
+
+	_, _, err = verifier.CreateToken(Claims{Username: username}, time.Minute)
+	require.ErrorIs(t, err, ErrVerifyOnly)
+
+	// a token whose payload was changed isn't valid anymore
+	tampered := []byte(token)
+	tampered[len(pasetoV4PublicHeader)+2] ^= 1
+	_, err = verifier.VerifyToken(string(tampered))
+	require.ErrorIs(t, err, ErrInvalidToken)
+
+	otherPublicKey, _, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	other, err := NewPasetoV4Verifier(hex.EncodeToString(otherPublicKey))
+	require.NoError(t, err)
+	_, err = other.VerifyToken(token)
+	require.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestExpiredPasetoV4Token(t *testing.T) {
+	_, privateKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	maker, err := NewPasetoV4PublicMaker(hex.EncodeToString(privateKey))
+	require.NoError(t, err)
+
+	token, _, err := maker.CreateToken(Claims{Username: util.RandomOwner()}, -time.Minute)
+	require.NoError(t, err)
+
+	payload, err := maker.VerifyToken(token)
+	require.ErrorIs(t, err, ErrExpiredToken)
+	require.Nil(t, payload)
+}
+
+func TestNewMaker(t *testing.T) {
+	symmetricKey := util.RandomString(32)
+	_, privateKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	maker, err := NewMaker(MakerConfig{SymmetricKey: symmetricKey})
+	require.NoError(t, err)
+	require.IsType(t, &PasetoMaker{}, maker)
+
+	maker, err = NewMaker(MakerConfig{Kind: KindPasetoV4Local, SymmetricKey: symmetricKey})
+	require.NoError(t, err)
+	require.IsType(t, &PasetoV4LocalMaker{}, maker)
+
+	maker, err = NewMaker(MakerConfig{Kind: KindPasetoV4Public, PrivateKey: hex.EncodeToString(privateKey)})
+	require.NoError(t, err)
+	_, _, err = maker.CreateToken(Claims{Username: util.RandomOwner()}, time.Minute)
+	require.NoError(t, err)
+
+	maker, err = NewMaker(MakerConfig{Kind: KindPasetoV4Public, PublicKey: hex.EncodeToString(privateKey.Public().(ed25519.PublicKey))})
+	require.NoError(t, err)
+	_, _, err = maker.CreateToken(Claims{Username: util.RandomOwner()}, time.Minute)
+	require.ErrorIs(t, err, ErrVerifyOnly)
+
+	_, err = NewMaker(MakerConfig{Kind: KindPasetoV4Public})
+	require.Error(t, err)
+
+	_, err = NewMaker(MakerConfig{Kind: "jwt", SymmetricKey: symmetricKey})
+	require.Error(t, err)
+}
+
+func TestPasetoTokenExpiresByClock(t *testing.T) {
+	issuedAt := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+
+	for _, kind := range []string{KindPasetoV2Local, KindPasetoV4Local} {
+		fake := clock.NewFake(issuedAt)
+		maker, err := NewMaker(MakerConfig{Kind: kind, SymmetricKey: util.RandomString(32), Clock: fake})
+		require.NoError(t, err)
+
+		token, payload, err := maker.CreateToken(Claims{Username: util.RandomOwner()}, time.Minute)
+		require.NoError(t, err)
+		require.Equal(t, issuedAt, payload.IssuedAt)
+		require.Equal(t, issuedAt.Add(time.Minute), payload.ExpiredAt)
+
+		fake.Advance(time.Minute)
+		_, err = maker.VerifyToken(token)
+		require.NoError(t, err)
+
+		fake.Advance(time.Second)
+		_, err = maker.VerifyToken(token)
+		require.ErrorIs(t, err, ErrExpiredToken)
+	}
+}
@@ -12,31 +12,145 @@ var (
 	ErrInvalidToken = errors.New("invalid token")
 )
 
+// Scopes of the access tokens, a read and a write scope per resource of the API. A token with scopes can
+// only make the requests they allow, e.g. a read-only integration given accounts:read and
+// transfers:read can't move money.
+const (
+	ScopeAccountsRead       = "accounts:read"
+	ScopeAccountsWrite      = "accounts:write"
+	ScopeTransfersRead      = "transfers:read"
+	ScopeTransfersWrite     = "transfers:write"
+	ScopeNotificationsRead  = "notifications:read"
+	ScopeNotificationsWrite = "notifications:write"
+)
+
+// Scopes are the scopes tokens can be issued with.
+var Scopes = []string{
+	ScopeAccountsRead,
+	ScopeAccountsWrite,
+	ScopeTransfersRead,
+	ScopeTransfersWrite,
+	ScopeNotificationsRead,
+	ScopeNotificationsWrite,
+}
+
+// MetadataAuthMethod is the key of the metadata of the tokens telling how their user logged in, password
+// or the identity provider.
+const MetadataAuthMethod = "auth_method"
+
+// The Claims type is what a token asserts about its user, besides its id and lifetime.
+// @property {string} Role - the role of the user when the token was issued. The routes reserved to a role
+// read it from the database rather, so that revoking it takes effect at once.
+// @property {uuid.UUID} SessionID - the session the token was issued for, so that the requests made with
+// it count as activity of that session. uuid.Nil for the tokens not tied to a session.
+// @property {[]string} Scopes - the scopes the token is limited to, allowed every request when empty.
+// @property {string} Impersonator - the admin the token was issued to for impersonating the user, so that
+// the actions taken with it are told apart from those of the user.
+// @property {map[string]string} Metadata - arbitrary claims, e.g. MetadataAuthMethod.
+// @property {int64} OrgID - the organization of the user, whose users and accounts only the token reaches.
+type Claims struct {
+	Username     string
+	Role         string
+	OrgID        int64
+	SessionID    uuid.UUID
+	Scopes       []string
+	Impersonator string
+	Metadata     map[string]string
+}
+
+// The Payload type is the claims of a token.
+// @property {[]string} Scopes - the scopes the token is limited to, empty for the tokens allowed every
+// request, e.g. those of users logging in without asking for scopes and those issued before scopes.
+// @property {string} Impersonator - the admin the token was issued to for impersonating the user, empty
+// for the tokens of the user.
+// @property {string} Role - the role of the user, empty for the tokens issued before roles were added.
+// @property {string} KeyID - the id of the key the token was issued with, empty for the tokens issued
+// before the keys were given ids.
+// @property {int64} OrgID - the organization of the user, 0 for the tokens issued before the
+// organizations, whose users all belong to the default one.
 type Payload struct {
-	ID        uuid.UUID `json:"id"`
-	Username  string    `json:"username"`
-	IssuedAt  time.Time `json:"issued_at"`
-	ExpiredAt time.Time `json:"expired_at"`
+	ID           uuid.UUID         `json:"id"`
+	Username     string            `json:"username"`
+	Role         string            `json:"role,omitempty"`
+	OrgID        int64             `json:"org_id,omitempty"`
+	IssuedAt     time.Time         `json:"issued_at"`
+	ExpiredAt    time.Time         `json:"expired_at"`
+	SessionID    uuid.UUID         `json:"session_id"`
+	Scopes       []string          `json:"scopes,omitempty"`
+	Impersonator string            `json:"impersonator,omitempty"`
+	Metadata     map[string]string `json:"metadata,omitempty"`
+	KeyID        string            `json:"kid,omitempty"`
 }
 
-func NewPayload(username string, duration time.Duration) (*Payload, error) {
+// The `newPayload` function creates the payload of a token issued at `now`, the time of the clock of the
+// maker.
+func newPayload(claims Claims, duration time.Duration, now time.Time) (*Payload, error) {
 	tokenID, err := uuid.NewRandom()
 	if err != nil {
 		return nil, err
 	}
 
 	payload := &Payload{
-		ID:        tokenID,
-		Username:  username,
-		IssuedAt:  time.Now(),
-		ExpiredAt: time.Now().Add(duration),
+		ID:           tokenID,
+		Username:     claims.Username,
+		Role:         claims.Role,
+		OrgID:        claims.OrgID,
+		IssuedAt:     now,
+		ExpiredAt:    now.Add(duration),
+		SessionID:    claims.SessionID,
+		Scopes:       claims.Scopes,
+		Impersonator: claims.Impersonator,
+		Metadata:     claims.Metadata,
 	}
 
 	return payload, nil
 }
 
-func (payload *Payload) Valid() error {
-	if time.Now().After(payload.ExpiredAt) {
+// The `Claims` function returns the claims of the token, e.g. to issue another one asserting the same.
+func (payload *Payload) Claims() Claims {
+	return Claims{
+		Username:     payload.Username,
+		Role:         payload.Role,
+		OrgID:        payload.OrgID,
+		SessionID:    payload.SessionID,
+		Scopes:       payload.Scopes,
+		Impersonator: payload.Impersonator,
+		Metadata:     payload.Metadata,
+	}
+}
+
+// The `ValidScope` function reports whether tokens can be issued with `scope`.
+func ValidScope(scope string) bool {
+	for _, valid := range Scopes {
+		if scope == valid {
+			return true
+		}
+	}
+	return false
+}
+
+// The `Scoped` function reports whether the token is limited to its scopes.
+func (payload *Payload) Scoped() bool {
+	return len(payload.Scopes) > 0
+}
+
+// The `HasScope` function reports whether the token allows the requests of `scope`, which the tokens
+// without scopes allow whatever the scope.
+func (payload *Payload) HasScope(scope string) bool {
+	if !payload.Scoped() {
+		return true
+	}
+	for _, granted := range payload.Scopes {
+		if granted == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// The `validAt` function reports whether the token has expired at `now`.
+func (payload *Payload) validAt(now time.Time) error {
+	if now.After(payload.ExpiredAt) {
 		return ErrExpiredToken
 	}
 	return nil
@@ -0,0 +1,111 @@
+package token
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// The keyFooter type is the footer of the tokens issued with a key that has an id. The footer isn't
+// encrypted, so the key verifying a token is found before decrypting it, but it is authenticated along
+// with the payload.
+type keyFooter struct {
+	KeyID string `json:"kid"`
+}
+
+// The `newKeyFooter` function encodes the footer of the tokens of the key, nil for a key without an id.
+func newKeyFooter(keyID string) ([]byte, error) {
+	if keyID == "" {
+		return nil, nil
+	}
+	return json.Marshal(keyFooter{KeyID: keyID})
+}
+
+// The `tokenKeyID` function returns the id of the key a PASETO token was issued with, read from its
+// footer, or an empty string when it has none.
+func tokenKeyID(token string) string {
+	parts := strings.Split(token, ".")
+	if len(parts) != 4 {
+		return ""
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(parts[3])
+	if err != nil {
+		return ""
+	}
+	var footer keyFooter
+	if err := json.Unmarshal(data, &footer); err != nil {
+		return ""
+	}
+	return footer.KeyID
+}
+
+// The RotatingMaker type creates tokens with the current key and verifies them with the current key or
+// the previous ones, chosen by the id of the key in their footer, so that a key is rotated without
+// invalidating every session at once: the tokens of the previous key stay valid until they expire.
+// @property {Maker} Maker - the maker of the current key, creating the tokens.
+// @property {map[string]Maker} verifiers - the makers of the previous keys, by id, only verifying tokens.
+type RotatingMaker struct {
+	Maker
+	keyID     string
+	verifiers map[string]Maker
+}
+
+// The function creates a maker issuing tokens with the `current` maker of the key `keyID`, and verifying
+// those of the previous keys with the `verifiers`.
+func NewRotatingMaker(current Maker, keyID string, verifiers map[string]Maker) Maker {
+	return &RotatingMaker{
+		Maker:     current,
+		keyID:     keyID,
+		verifiers: verifiers,
+	}
+}
+
+// The VerifyToken function verifies the token with the key of its id. The tokens without an id, issued
+// before the keys were given ids, are tried with every key.
+func (maker RotatingMaker) VerifyToken(token string) (*Payload, error) {
+	keyID := tokenKeyID(token)
+	if keyID != "" {
+		if keyID == maker.keyID {
+			return maker.Maker.VerifyToken(token)
+		}
+		verifier, ok := maker.verifiers[keyID]
+		if !ok {
+			return nil, ErrInvalidToken
+		}
+		return verifier.VerifyToken(token)
+	}
+
+	payload, err := maker.Maker.VerifyToken(token)
+	if !errors.Is(err, ErrInvalidToken) {
+		return payload, err
+	}
+	for _, verifier := range maker.verifiers {
+		payload, err = verifier.VerifyToken(token)
+		if !errors.Is(err, ErrInvalidToken) {
+			return payload, err
+		}
+	}
+	return nil, ErrInvalidToken
+}
+
+// The `parseVerificationKeys` function parses the previous keys of the TOKEN_VERIFICATION_KEYS config,
+// formatted as a comma separated list of `id=key`, e.g. `2024-01=...,2024-02=...`.
+func parseVerificationKeys(value string) (map[string]string, error) {
+	keys := make(map[string]string)
+	if strings.TrimSpace(value) == "" {
+		return keys, nil
+	}
+
+	for _, item := range strings.Split(value, ",") {
+		keyID, key, ok := strings.Cut(strings.TrimSpace(item), "=")
+		if !ok || keyID == "" || key == "" {
+			return nil, fmt.Errorf("invalid verification key %q, expected id=key", item)
+		}
+		keys[keyID] = key
+	}
+
+	return keys, nil
+}
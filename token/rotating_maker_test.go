@@ -0,0 +1,111 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"go-backend/util"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotatingMaker(t *testing.T) {
+	oldKey := util.RandomString(32)
+	newKey := util.RandomString(32)
+
+	// the tokens issued before the keys were given ids
+	unkeyed, err := NewMaker(MakerConfig{SymmetricKey: oldKey})
+	require.NoError(t, err)
+	unkeyedToken, _, err := unkeyed.CreateToken(Claims{Username: util.RandomOwner()}, time.Minute)
+	require.NoError(t, err)
+
+	old, err := NewMaker(MakerConfig{SymmetricKey: oldKey, KeyID: "1"})
+	require.NoError(t, err)
+	oldToken, payload, err := old.CreateToken(Claims{Username: util.RandomOwner()}, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, "1", payload.KeyID)
+	require.Equal(t, "1", tokenKeyID(oldToken))
+
+	rotated, err := NewMaker(MakerConfig{SymmetricKey: newKey, KeyID: "2", VerificationKeys: "1=" + oldKey})
+	require.NoError(t, err)
+	newToken, _, err := rotated.CreateToken(Claims{Username: util.RandomOwner()}, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, "2", tokenKeyID(newToken))
+
+	for _, token := range []string{unkeyedToken, oldToken, newToken} {
+		_, err = rotated.VerifyToken(token)
+		require.NoError(t, err)
+	}
+
+	payload, err = rotated.VerifyToken(newToken)
+	require.NoError(t, err)
+	require.Equal(t, "2", payload.KeyID)
+
+	// the tokens of the new key aren't valid for the makers still on the old one
+	_, err = old.VerifyToken(newToken)
+	require.ErrorIs(t, err, ErrInvalidToken)
+
+	// once the old key is dropped, its tokens aren't valid anymore
+	dropped, err := NewMaker(MakerConfig{SymmetricKey: newKey, KeyID: "2"})
+	require.NoError(t, err)
+	_, err = dropped.VerifyToken(oldToken)
+	require.ErrorIs(t, err, ErrInvalidToken)
+	_, err = dropped.VerifyToken(unkeyedToken)
+	require.ErrorIs(t, err, ErrInvalidToken)
+
+	expiredToken, _, err := old.CreateToken(Claims{Username: util.RandomOwner()}, -time.Minute)
+	require.NoError(t, err)
+	_, err = rotated.VerifyToken(expiredToken)
+	require.ErrorIs(t, err, ErrExpiredToken)
+}
+
+func TestRotatingPublicMaker(t *testing.T) {
+	oldPublicKey, oldPrivateKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	_, newPrivateKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	old, err := NewMaker(MakerConfig{Kind: KindPasetoV4Public, PrivateKey: hex.EncodeToString(oldPrivateKey), KeyID: "1"})
+	require.NoError(t, err)
+	oldToken, _, err := old.CreateToken(Claims{Username: util.RandomOwner()}, time.Minute)
+	require.NoError(t, err)
+
+	// the previous public keys verify the tokens signed before the rotation
+	rotated, err := NewMaker(MakerConfig{
+		Kind:             KindPasetoV4Public,
+		PrivateKey:       hex.EncodeToString(newPrivateKey),
+		KeyID:            "2",
+		VerificationKeys: "1=" + hex.EncodeToString(oldPublicKey),
+	})
+	require.NoError(t, err)
+	payload, err := rotated.VerifyToken(oldToken)
+	require.NoError(t, err)
+	require.Equal(t, "1", payload.KeyID)
+}
+
+func TestNewRotatingMakerConfig(t *testing.T) {
+	key := util.RandomString(32)
+
+	_, err := NewMaker(MakerConfig{SymmetricKey: key, KeyID: "1", VerificationKeys: "1=" + util.RandomString(32)})
+	require.Error(t, err)
+
+	_, err = NewMaker(MakerConfig{SymmetricKey: key, KeyID: "2", VerificationKeys: "1=" + util.RandomString(31)})
+	require.Error(t, err)
+
+	_, err = NewMaker(MakerConfig{SymmetricKey: key, KeyID: "2", VerificationKeys: "1"})
+	require.Error(t, err)
+}
+
+func TestParseVerificationKeys(t *testing.T) {
+	keys, err := parseVerificationKeys(" 1=abc, 2=d=f ")
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"1": "abc", "2": "d=f"}, keys)
+
+	keys, err = parseVerificationKeys("")
+	require.NoError(t, err)
+	require.Empty(t, keys)
+
+	_, err = parseVerificationKeys("=abc")
+	require.Error(t, err)
+}
@@ -0,0 +1,67 @@
+package tracking
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// The `RecoverHandler` function captures the panics of the requests served by `next` before propagating
+// them, so that the server still handles them.
+func RecoverHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				CaptureRequestPanic(Request{Request: r, Route: r.Method + " " + r.URL.Path}, recovered)
+				panic(recovered)
+			}
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// The `GatewayErrorHandler` function captures the errors of the HTTP gateway responded with a 5xx
+// status, before responding like the default handler of the gateway.
+func GatewayErrorHandler(ctx context.Context, mux *runtime.ServeMux, marshaler runtime.Marshaler, w http.ResponseWriter, r *http.Request, err error) {
+	if code := runtime.HTTPStatusFromCode(status.Code(err)); code >= http.StatusInternalServerError {
+		CaptureRequestError(Request{Request: r, Route: r.Method + " " + r.URL.Path, Status: code}, cause(err))
+	}
+	runtime.DefaultHTTPErrorHandler(ctx, mux, marshaler, w, r, err)
+}
+
+// The `UnaryServerInterceptor` function captures the panics of the gRPC calls, which fail with Internal
+// instead of crashing the server, and the calls failing with a code mapped to a 5xx status.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, r interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (res interface{}, err error) {
+		req := Request{Route: info.FullMethod}
+
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				CaptureRequestPanic(req, recovered)
+				err = status.Error(codes.Internal, "internal error")
+			}
+		}()
+
+		res, err = handler(ctx, r)
+		if code := runtime.HTTPStatusFromCode(status.Code(err)); code >= http.StatusInternalServerError {
+			req.Status = code
+			CaptureRequestError(req, cause(err))
+		}
+		return res, err
+	}
+}
+
+// cause returns the error a gRPC status was made from when it wraps one, e.g. the internal errors whose
+// details aren't sent to the client, and the message of the status otherwise.
+func cause(err error) error {
+	if wrapped := errors.Unwrap(err); wrapped != nil {
+		return wrapped
+	}
+	return errors.New(status.Convert(err).Message())
+}
@@ -0,0 +1,104 @@
+package tracking
+
+import (
+	"context"
+	"fmt"
+	"go-backend/util"
+	"net/http"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/hibiken/asynq"
+)
+
+// flushTimeout is how long the events not sent yet are waited for on shutdown.
+const flushTimeout = 2 * time.Second
+
+// The `Init` function sends the errors captured from then on to the Sentry project of SENTRY_DSN, and
+// returns the function sending the events still buffered, to call before exiting. Nothing is captured
+// without a DSN, the capture functions being no-ops until a client is bound.
+func Init(config util.Config) (flush func(), err error) {
+	if config.SentryDSN == "" {
+		return func() {}, nil
+	}
+
+	err = sentry.Init(sentry.ClientOptions{
+		Dsn:              config.SentryDSN,
+		Environment:      config.SentryEnvironment,
+		Release:          util.CurrentBuild().GitSHA,
+		AttachStacktrace: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot init error tracking: %w", err)
+	}
+
+	return func() { sentry.Flush(flushTimeout) }, nil
+}
+
+// The Request type describes the request an error happened while serving.
+// @property {string} Route - the route of the request, e.g. `POST /api/v1/transfers`, which groups the
+// errors of the requests to different resources.
+// @property {string} Username - the authenticated user, empty for anonymous requests.
+// @property {int} Status - the status the request was responded with, 0 for a panic.
+type Request struct {
+	Request  *http.Request
+	Route    string
+	Username string
+	Status   int
+}
+
+// The `newRequestHub` function returns a hub whose scope describes the request, its sensitive headers
+// such as Authorization being left out.
+func newRequestHub(req Request) *sentry.Hub {
+	hub := sentry.CurrentHub().Clone()
+	hub.ConfigureScope(func(scope *sentry.Scope) {
+		if req.Request != nil {
+			scope.SetRequest(req.Request)
+		}
+		if req.Route != "" {
+			scope.SetTag("route", req.Route)
+		}
+		if req.Status != 0 {
+			scope.SetTag("status", fmt.Sprint(req.Status))
+		}
+		if req.Username != "" {
+			scope.SetUser(sentry.User{Username: req.Username})
+		}
+	})
+	return hub
+}
+
+// The `CaptureRequestError` function captures the error a request failed with, e.g. one responded with
+// a 5xx status.
+func CaptureRequestError(req Request, err error) {
+	newRequestHub(req).CaptureException(err)
+}
+
+// The `CaptureRequestPanic` function captures the value a request panicked with, along with the stack
+// trace of the panic. It must be called from the deferred function that recovered it.
+func CaptureRequestPanic(req Request, recovered interface{}) {
+	ctx := context.Background()
+	if req.Request != nil {
+		ctx = req.Request.Context()
+	}
+	newRequestHub(req).RecoverWithContext(ctx, recovered)
+}
+
+// The `CaptureTaskError` function captures the error a background task failed with, tagged with its
+// type, queue and retry so that the failures of a task retried several times can be told apart.
+func CaptureTaskError(ctx context.Context, task *asynq.Task, err error) {
+	hub := sentry.CurrentHub().Clone()
+	hub.ConfigureScope(func(scope *sentry.Scope) {
+		scope.SetTag("task_type", task.Type())
+		if id, ok := asynq.GetTaskID(ctx); ok {
+			scope.SetTag("task_id", id)
+		}
+		if queue, ok := asynq.GetQueueName(ctx); ok {
+			scope.SetTag("queue", queue)
+		}
+		if retry, ok := asynq.GetRetryCount(ctx); ok {
+			scope.SetExtra("retry", retry)
+		}
+	})
+	hub.CaptureException(err)
+}
@@ -0,0 +1,157 @@
+package tracking
+
+import (
+	"context"
+	"errors"
+	"go-backend/testutil/trackingtest"
+	"go-backend/util"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestInitWithoutDSN(t *testing.T) {
+	flush, err := Init(util.Config{})
+	require.NoError(t, err)
+	require.Nil(t, sentry.CurrentHub().Client())
+	flush()
+}
+
+func TestCaptureRequestError(t *testing.T) {
+	transport := trackingtest.Record(t)
+
+	r := httptest.NewRequest(http.MethodPost, "/api/v1/transfers", nil)
+	r.Header.Set("Authorization", "Bearer secret")
+	CaptureRequestError(Request{
+		Request:  r,
+		Route:    "POST /api/v1/transfers",
+		Username: "ada",
+		Status:   http.StatusInternalServerError,
+	}, errors.New("connection refused"))
+
+	events := transport.Events()
+	require.Len(t, events, 1)
+	require.Equal(t, "connection refused", events[0].Exception[0].Value)
+	require.Equal(t, "ada", events[0].User.Username)
+	require.Equal(t, "POST /api/v1/transfers", events[0].Tags["route"])
+	require.Equal(t, "500", events[0].Tags["status"])
+	require.True(t, strings.HasSuffix(events[0].Request.URL, "/api/v1/transfers"))
+	require.NotContains(t, events[0].Request.Headers, "Authorization")
+}
+
+func TestCaptureTaskError(t *testing.T) {
+	transport := trackingtest.Record(t)
+
+	CaptureTaskError(context.Background(), asynq.NewTask("task:run_export", nil), errors.New("export failed"))
+
+	events := transport.Events()
+	require.Len(t, events, 1)
+	require.Equal(t, "export failed", events[0].Exception[0].Value)
+	require.Equal(t, "task:run_export", events[0].Tags["task_type"])
+}
+
+func TestRecoverHandler(t *testing.T) {
+	transport := trackingtest.Record(t)
+
+	handler := RecoverHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+
+	require.PanicsWithValue(t, "boom", func() {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/v1/login_user", nil))
+	})
+
+	events := transport.Events()
+	require.Len(t, events, 1)
+	require.Equal(t, "boom", events[0].Message)
+	require.Equal(t, "GET /v1/login_user", events[0].Tags["route"])
+}
+
+// wrappedStatus is a status hiding the error it was made from, like the internal errors of gapi.
+type wrappedStatus struct {
+	err error
+}
+
+func (e *wrappedStatus) Error() string { return "internal error" }
+func (e *wrappedStatus) GRPCStatus() *status.Status {
+	return status.New(codes.Internal, "internal error")
+}
+func (e *wrappedStatus) Unwrap() error { return e.err }
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	testCases := []struct {
+		name    string
+		handler grpc.UnaryHandler
+		code    codes.Code
+		event   string
+	}{
+		{
+			name: "OK",
+			handler: func(ctx context.Context, req interface{}) (interface{}, error) {
+				return "ok", nil
+			},
+			code: codes.OK,
+		},
+		{
+			name: "ClientError",
+			handler: func(ctx context.Context, req interface{}) (interface{}, error) {
+				return nil, status.Error(codes.NotFound, "user not found")
+			},
+			code: codes.NotFound,
+		},
+		{
+			name: "InternalError",
+			handler: func(ctx context.Context, req interface{}) (interface{}, error) {
+				return nil, &wrappedStatus{err: errors.New("connection refused")}
+			},
+			code:  codes.Internal,
+			event: "connection refused",
+		},
+		{
+			name: "Unavailable",
+			handler: func(ctx context.Context, req interface{}) (interface{}, error) {
+				return nil, status.Error(codes.Unavailable, "standby")
+			},
+			code:  codes.Unavailable,
+			event: "standby",
+		},
+		{
+			name: "Panic",
+			handler: func(ctx context.Context, req interface{}) (interface{}, error) {
+				panic(errors.New("boom"))
+			},
+			code:  codes.Internal,
+			event: "boom",
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			transport := trackingtest.Record(t)
+
+			info := &grpc.UnaryServerInfo{FullMethod: "/pb.SimpleBank/LoginUser"}
+			_, err := UnaryServerInterceptor()(context.Background(), nil, info, tc.handler)
+			require.Equal(t, tc.code, status.Code(err))
+
+			events := transport.Events()
+			if tc.event == "" {
+				require.Empty(t, events)
+				return
+			}
+
+			require.Len(t, events, 1)
+			require.Equal(t, tc.event, events[0].Exception[0].Value)
+			require.Equal(t, info.FullMethod, events[0].Tags["route"])
+		})
+	}
+}
@@ -0,0 +1,103 @@
+// Package usage counts the calls users make to the API per calendar month, the period of the quotas of
+// the API plans. The counts are kept in Redis so that they add up across the replicas of the server.
+package usage
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// retention is how long the count of a period is kept once it is over, for the support team to look
+// back at the last month.
+const retention = 35 * 24 * time.Hour
+
+// The `Period` function returns the month, in UTC, the calls made at `at` are counted in, e.g. 2026-10.
+func Period(at time.Time) string {
+	return at.UTC().Format("2006-01")
+}
+
+// The `PeriodEnd` function returns when the period of `at` is over and the counts start again from 0.
+func PeriodEnd(at time.Time) time.Time {
+	year, month, _ := at.UTC().Date()
+	return time.Date(year, month+1, 1, 0, 0, 0, 0, time.UTC)
+}
+
+// The Meter interface counts the calls of every user per period.
+type Meter interface {
+	// Increment counts a call of the user at `at`, and returns the calls of the user in the period so far.
+	Increment(ctx context.Context, username string, at time.Time) (int64, error)
+	// Count returns the calls of the user in the period of `at`.
+	Count(ctx context.Context, username string, at time.Time) (int64, error)
+}
+
+// The RedisMeter type counts the calls in Redis, with a key per user and period.
+type RedisMeter struct {
+	client *redis.Client
+}
+
+// The function creates a meter counting the calls with `client`.
+func NewRedisMeter(client *redis.Client) *RedisMeter {
+	return &RedisMeter{client: client}
+}
+
+func key(username string, at time.Time) string {
+	return fmt.Sprintf("usage:%s:%s", username, Period(at))
+}
+
+func (meter *RedisMeter) Increment(ctx context.Context, username string, at time.Time) (int64, error) {
+	pipe := meter.client.TxPipeline()
+	calls := pipe.Incr(ctx, key(username, at))
+	pipe.ExpireAt(ctx, key(username, at), PeriodEnd(at).Add(retention))
+	_, err := pipe.Exec(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return calls.Val(), nil
+}
+
+func (meter *RedisMeter) Count(ctx context.Context, username string, at time.Time) (int64, error) {
+	calls, err := meter.client.Get(ctx, key(username, at)).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	return calls, err
+}
+
+// The MemoryMeter type counts the calls in memory, for the tests and the single server deployments. The
+// counts of the past periods are dropped as the new ones start.
+type MemoryMeter struct {
+	mu     sync.Mutex
+	period string
+	calls  map[string]int64
+}
+
+// The function creates a meter counting the calls in memory.
+func NewMemoryMeter() *MemoryMeter {
+	return &MemoryMeter{calls: make(map[string]int64)}
+}
+
+func (meter *MemoryMeter) Increment(ctx context.Context, username string, at time.Time) (int64, error) {
+	meter.mu.Lock()
+	defer meter.mu.Unlock()
+
+	if period := Period(at); period != meter.period {
+		meter.period = period
+		meter.calls = make(map[string]int64)
+	}
+	meter.calls[username]++
+	return meter.calls[username], nil
+}
+
+func (meter *MemoryMeter) Count(ctx context.Context, username string, at time.Time) (int64, error) {
+	meter.mu.Lock()
+	defer meter.mu.Unlock()
+
+	if Period(at) != meter.period {
+		return 0, nil
+	}
+	return meter.calls[username], nil
+}
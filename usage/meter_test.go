@@ -0,0 +1,51 @@
+package usage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeriod(t *testing.T) {
+	at := time.Date(2026, time.December, 31, 23, 30, 0, 0, time.UTC)
+	require.Equal(t, "2026-12", Period(at))
+	require.Equal(t, time.Date(2027, time.January, 1, 0, 0, 0, 0, time.UTC), PeriodEnd(at))
+
+	// the periods are months in UTC, whatever the zone of the time
+	east := time.FixedZone("UTC+2", 2*60*60)
+	require.Equal(t, "2026-12", Period(time.Date(2027, time.January, 1, 1, 0, 0, 0, east)))
+}
+
+func TestMemoryMeter(t *testing.T) {
+	ctx := context.Background()
+	meter := NewMemoryMeter()
+	october := time.Date(2026, time.October, 16, 12, 0, 0, 0, time.UTC)
+
+	for i := int64(1); i <= 3; i++ {
+		calls, err := meter.Increment(ctx, "alice", october)
+		require.NoError(t, err)
+		require.Equal(t, i, calls)
+	}
+	_, err := meter.Increment(ctx, "bob", october)
+	require.NoError(t, err)
+
+	calls, err := meter.Count(ctx, "alice", october)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), calls)
+
+	calls, err = meter.Count(ctx, "carol", october)
+	require.NoError(t, err)
+	require.Zero(t, calls)
+
+	// the counts start again from 0 with the next period
+	november := october.AddDate(0, 1, 0)
+	calls, err = meter.Increment(ctx, "alice", november)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), calls)
+
+	calls, err = meter.Count(ctx, "bob", november)
+	require.NoError(t, err)
+	require.Zero(t, calls)
+}
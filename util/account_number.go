@@ -0,0 +1,58 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The account numbers are IBAN-like: the GO country code, 2 check digits, the BANK bank code and the id
+// of the account on 10 digits, e.g. GO82BANK0000000042. The check digits are computed the way the IBAN
+// ones are, with ISO 7064 MOD 97-10, so that a mistyped digit or two swapped digits are caught. The
+// accounts table computes the same numbers in its generated account_number column.
+const (
+	accountNumberCountry = "GO"
+	accountNumberBank    = "BANK"
+	accountNumberDigits  = 10
+)
+
+// AccountNumberLength is the length of the account numbers.
+const AccountNumberLength = len(accountNumberCountry) + 2 + len(accountNumberBank) + accountNumberDigits
+
+// The `AccountNumber` function returns the account number of the account with the id.
+func AccountNumber(id int64) string {
+	serial := fmt.Sprintf("%0*d", accountNumberDigits, id)
+	check := 98 - mod97(accountNumberBank+serial+accountNumberCountry+"00")
+	return fmt.Sprintf("%s%02d%s%s", accountNumberCountry, check, accountNumberBank, serial)
+}
+
+// The `ValidAccountNumber` function reports whether the number is a well formed account number whose
+// check digits are right. It doesn't tell whether an account has this number.
+func ValidAccountNumber(number string) bool {
+	if len(number) != AccountNumberLength ||
+		!strings.HasPrefix(number, accountNumberCountry) ||
+		number[4:4+len(accountNumberBank)] != accountNumberBank {
+		return false
+	}
+	for _, r := range number[2:4] + number[4+len(accountNumberBank):] {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	// the number is valid when moving its first 4 characters to its end gives 1 modulo 97
+	return mod97(number[4:]+number[:4]) == 1
+}
+
+// mod97 returns the remainder of the division by 97 of the number written by the digits and upper case
+// letters of s, each letter standing for 2 digits from 10 for A to 35 for Z.
+func mod97(s string) int {
+	remainder := 0
+	for _, r := range s {
+		if r >= 'A' && r <= 'Z' {
+			remainder = (remainder*100 + int(r-'A'+10)) % 97
+			continue
+		}
+		remainder = (remainder*10 + int(r-'0')) % 97
+	}
+	return remainder
+}
@@ -0,0 +1,42 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountNumber(t *testing.T) {
+	number := AccountNumber(42)
+	require.Len(t, number, AccountNumberLength)
+	require.Equal(t, "GO82BANK0000000042", number)
+	require.True(t, ValidAccountNumber(number))
+
+	for id := int64(1); id <= 1000; id++ {
+		require.True(t, ValidAccountNumber(AccountNumber(id)), "account %d", id)
+	}
+}
+
+func TestValidAccountNumber(t *testing.T) {
+	number := AccountNumber(1234567)
+
+	// a mistyped digit
+	mistyped := []byte(number)
+	mistyped[len(mistyped)-1] = '8'
+	// two swapped digits
+	swapped := []byte(number)
+	swapped[len(swapped)-2], swapped[len(swapped)-1] = swapped[len(swapped)-1], swapped[len(swapped)-2]
+
+	for _, invalid := range []string{
+		"",
+		"1234567",
+		number[:len(number)-1],
+		string(mistyped),
+		string(swapped),
+		"XX" + number[2:],
+		number[:4] + "BNAK" + number[8:],
+		number[:10] + "A" + number[11:],
+	} {
+		require.False(t, ValidAccountNumber(invalid), invalid)
+	}
+}
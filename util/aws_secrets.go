@@ -0,0 +1,174 @@
+package util
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	awsSecretsTimeout = 10 * time.Second
+	awsSecretsService = "secretsmanager"
+	awsSigningScheme  = "AWS4-HMAC-SHA256"
+)
+
+// The AWSCredentials type holds the access key requests to AWS are signed with.
+// @property {string} SessionToken - the token of temporary credentials, empty for the keys of a user.
+type AWSCredentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// The AWSSecretsProvider type reads the secrets of AWS Secrets Manager, whose secret strings are JSON
+// objects of the values by key.
+type AWSSecretsProvider struct {
+	region      string
+	credentials AWSCredentials
+	endpoint    string
+	client      *http.Client
+	now         func() time.Time
+}
+
+// The `NewAWSSecretsProvider` function creates a provider reading the secrets of Secrets Manager in
+// `region`.
+func NewAWSSecretsProvider(region string, credentials AWSCredentials) (*AWSSecretsProvider, error) {
+	if region == "" || credentials.AccessKeyID == "" || credentials.SecretAccessKey == "" {
+		return nil, errors.New("AWS_REGION, AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY are required to read the secrets from AWS")
+	}
+
+	return &AWSSecretsProvider{
+		region:      region,
+		credentials: credentials,
+		endpoint:    fmt.Sprintf("https://%s.%s.amazonaws.com/", awsSecretsService, region),
+		client:      &http.Client{Timeout: awsSecretsTimeout},
+		now:         time.Now,
+	}, nil
+}
+
+type awsSecretValueResponse struct {
+	SecretString string `json:"SecretString"`
+	VersionID    string `json:"VersionId"`
+	Message      string `json:"message"`
+}
+
+// The `GetSecret` method reads the current version of the secret whose name or ARN is `name`.
+func (provider *AWSSecretsProvider) GetSecret(ctx context.Context, name string) (Secret, error) {
+	body, err := json.Marshal(map[string]string{"SecretId": name})
+	if err != nil {
+		return Secret{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, provider.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return Secret{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+	signAWSRequest(req, body, provider.credentials, provider.region, awsSecretsService, provider.now())
+
+	res, err := provider.client.Do(req)
+	if err != nil {
+		return Secret{}, err
+	}
+	defer res.Body.Close()
+
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return Secret{}, err
+	}
+
+	var value awsSecretValueResponse
+	if err := json.Unmarshal(resBody, &value); err != nil {
+		return Secret{}, fmt.Errorf("secrets manager responded with %d: %w", res.StatusCode, err)
+	}
+	if res.StatusCode != http.StatusOK {
+		return Secret{}, fmt.Errorf("secrets manager responded with %d: %s", res.StatusCode, value.Message)
+	}
+
+	var values map[string]string
+	if err := json.Unmarshal([]byte(value.SecretString), &values); err != nil {
+		return Secret{}, fmt.Errorf("secret %s isn't a JSON object of strings: %w", name, err)
+	}
+
+	return Secret{Values: values, Version: value.VersionID}, nil
+}
+
+// The `signAWSRequest` function signs a request to an AWS service with the Signature Version 4, signing
+// its host and its X-Amz-* headers along with the body.
+func signAWSRequest(req *http.Request, body []byte, credentials AWSCredentials, region string, service string, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	if credentials.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", credentials.SessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for key, values := range req.Header {
+		key = strings.ToLower(key)
+		if key == "content-type" || strings.HasPrefix(key, "x-amz-") {
+			headers[key] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		sha256Hex(body),
+	}, "\n")
+
+	scope := strings.Join([]string{date, region, service, "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{awsSigningScheme, amzDate, scope, sha256Hex([]byte(canonicalRequest))}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+credentials.SecretAccessKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		awsSigningScheme, credentials.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
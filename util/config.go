@@ -1,44 +1,382 @@
 package util
 
 import (
+	"context"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/spf13/viper"
 )
 
-// The Config type contains fields for database driver, database source, and server address.
-// @property {string} DBDriver - DBDriver is a string property that represents the database driver to
-// be used. It is likely used in a configuration file for a Go application. The `mapstructure` tag
-// indicates that this property can be mapped to an environment variable or a configuration file key.
+// The Config type contains fields for database source, connection pool sizing, and server address.
 // @property {string} DBSource - DBSource is a property that specifies the connection string or data
-// source name for the database. It is used to connect to the database using the specified driver.
+// source name for the database. It is used to connect to the database with the pgx connection pool.
+// @property {string} DBFailoverSources - the space separated connection URLs of the nodes the primary
+// database fails over to, in order, once DBSource can't be reached. There is no failover when empty.
+// @property {string} DBReplicaSource - the connection string of a read replica of the database.
+// @property {bool} ReadFromReplica - whether the read-only queries are served by the replica at
+// DBReplicaSource, falling back to the primary database while the replica is down.
+// @property {int32} DBMaxConns - the maximum size of the connection pool, the pgx default when 0.
+// @property {int32} DBMinConns - the number of connections the pool keeps open even when idle.
+// @property {time.Duration} DBMaxConnLifetime - how long a connection is reused before being closed,
+// the pgx default when 0.
+// @property {time.Duration} DBMaxConnIdleTime - how long an idle connection is kept open, the pgx
+// default when 0.
+// @property {time.Duration} DBQueryTimeout - how long each attempt of a query may take, there is no
+// timeout when 0. DBTxTimeout likewise times out each attempt of a transaction as a whole.
+// @property {int} DBMaxRetries - how many times the queries and transactions failing with a transient
+// error, e.g. a serialization failure of concurrent transfers, are retried. They aren't retried when 0.
+// @property {time.Duration} DBRetryBackoff - the longest wait before the first retry, drawn at random
+// below it and doubled for each of the next retries until DBRetryMaxBackoff.
+// @property {string} TransferIsolation - the isolation level of the transactions of the transfers:
+// read_committed (the default), repeatable_read, or serializable for the deployments that prefer the
+// correctness of every transfer over their throughput. The transfers failing with a serialization
+// failure are retried up to DBMaxRetries times.
 // @property {string} ServerAddress - The `ServerAddress` property is a string that represents the
 // address of the server. It is used to specify the network address on which the server should listen
 // for incoming requests. This property is typically used in web applications to specify the IP address
 // and port number on which the server should listen for incoming HTTP
+// @property {time.Duration} RequestTimeout - how long the HTTP requests may take, their queries being
+// cancelled past it, unless ROUTE_TIMEOUTS sets another timeout for their route. There is no timeout when 0.
+// @property {int64} MaxRequestBodyBytes - the largest body of the HTTP requests, larger ones being
+// rejected with 413. The bodies aren't limited when 0.
+// @property {bool} LeaderElection - whether the instances elect the active one, the others being
+// standbys that serve reads and reject writes, e.g. for an active/passive deployment across regions.
+// @property {string} InstanceID - the id of the instance in the leader election, the hostname when
+// empty.
+// @property {string} AdvertiseAddress - the address clients of the standbys are sent to for their
+// writes while this instance is the leader.
+// @property {time.Duration} AccountCacheTTL - how long the accounts read are cached in the Redis at
+// RedisAddress, the cache is disabled when 0.
+// @property {time.Duration} PaymentRequestTTL - how long a payment request can be accepted by its payer
+// before it expires.
+// @property {int64} TransferApprovalThreshold - transfers of at least this amount await the approval of
+// their owner or of a banker before being made, there is no approval when 0.
+// @property {time.Duration} PaymentLinkTTL - how long a payment link can be paid, unless its merchant sets
+// an earlier expiry.
+// @property {time.Duration} TransferApprovalTTL - how long a transfer can be approved before it expires.
+// @property {time.Duration} CardHoldTTL - how long the hold placed by a card authorization reserves its
+// amount before it expires unless captured.
+// @property {time.Duration} ChequeClearingPeriod - how long the amount of a cheque deposited is held on
+// the account, during which the cheque can bounce.
+// @property {bool} TransferQueueEnabled - whether the transfers made while the database can't be reached
+// are queued in the Redis at RedisAddress, and made once it is back, rather than rejected.
+// @property {int64} LoginMaxFailures - the failed logins from a client within LoginFailureWindow that lock a
+// user out of it for LoginLockoutDuration, the logins aren't throttled when 0.
+// @property {int64} LoginClientMaxFailures - the failed logins from a client within LoginFailureWindow,
+// whatever the user, after which it can't log in until they fall out of the window.
+// @property {time.Duration} NotificationDispatchInterval - how often the notifications queued for the
+// email and webhook channels are sent.
+// @property {string} MailDriver - how the emails are sent: smtp, sendgrid, or log (the default) to only
+// write them to the log.
+// @property {string} MailFromAddress - the address the emails are sent from, with MailFromName as the
+// name shown.
+// @property {int} SMTPPort - the port of the SMTP server at SMTPHost, the smtp driver authenticating with
+// SMTPUsername and SMTPPassword when the username is set.
+// @property {string} SendGridAPIKey - the API key of the sendgrid driver.
+// @property {string} JSONFieldCasing - the casing of the fields of the JSON responses, snake_case (the
+// default) or camelCase, which a request can override with the X-JSON-Casing header.
+// @property {string} SecretsProvider - where the TOKEN_SYMMETRIC_KEY, TOKEN_PRIVATE_KEY,
+// TOKEN_VERIFICATION_KEYS and the database credentials are read from rather than the config: vault, aws,
+// or empty to read them from the config.
+// @property {string} SecretsName - the path of the secret in Vault, or its name or ARN in AWS Secrets
+// Manager, whose keys override the config of the same name.
+// @property {time.Duration} SecretsCacheTTL - how long the secret is cached before it is read again, so
+// that rotated database credentials are used by the connections opened afterwards.
+// @property {string} VaultAddress - the address of Vault, read with VaultToken from the KV version 2
+// engine mounted at VaultMount.
+// @property {string} AWSRegion - the region of AWS Secrets Manager, read with the AWSAccessKeyID,
+// AWSSecretAccessKey and, for temporary credentials, AWSSessionToken.
+// @property {string} SentryDSN - the DSN of the Sentry project the panics, the server errors and the failed
+// tasks are reported to, with SentryEnvironment as their environment. They aren't reported when empty.
+// @property {string} GoogleClientID - the id of the Google OAuth client users log in with, along with
+// GoogleClientSecret. Users can't log in with Google when empty, and likewise with GitHubClientID for
+// GitHub.
+// @property {string} CaptchaProvider - the CAPTCHA users solve to sign up, hcaptcha or recaptcha, checked
+// with the secret key of the site, CaptchaSecret. Signups aren't checked when empty.
+// @property {int64} SignupMaxPerClient - the users a client can register within SignupWindow, counted in
+// memory by each server. The signups aren't limited when 0.
+// @property {string} APIPlans - the monthly quotas of calls of the API plans, e.g. basic=10000,pro=1000000.
+// The users given a plan, the machine clients, are rejected past its quota, the others aren't limited.
+// @property {string} TokenKind - the kind of the access and refresh tokens: paseto_v2_local (the default)
+// or paseto_v4_local, encrypted with TokenSymmetricKey, or paseto_v4_public, signed with the Ed25519
+// TokenPrivateKey in hex so that other services verify them with its TokenPublicKey. A service given only
+// the public key verifies tokens without issuing any. Changing it invalidates the tokens already issued.
+// @property {string} TokenKeyID - the id of the current key, written in the tokens, so that the key is
+// rotated without invalidating every session at once: the previous keys are kept in
+// TokenVerificationKeys, e.g. 2024-01=<key>, verifying the tokens they issued until these expire. The
+// previous keys are symmetric keys, or public keys for paseto_v4_public.
+// @property {time.Duration} ImpersonationTokenDuration - how long the tokens admins are issued to
+// impersonate a user are valid, the longest they can ask for.
+// @property {time.Duration} UserDeletionCoolingOff - how long after a user asks for the deletion of their
+// data it is deleted, during which they can cancel it.
+// @property {string} OAuthCallbackBaseURL - the public URL of the server, e.g. https://bank.example.com,
+// the identity providers send the users back to.
+// @property {string} WebhookSigningKey - the key signing the notifications posted to the webhooks of the
+// users, required to post them.
+// @property {string} ExportSigningKey - the key signing the download URLs of the export jobs, valid for
+// ExportURLDuration, required to serve the API.
+// @property {string} OAuthLinkKey - the key signing the identities waiting for the password of the user
+// owning their email to be linked to them, required to log in with an identity provider.
+// @property {string} KafkaRESTProxyURL - the URL of the Kafka REST Proxy the user.registered,
+// account.created and transfer.completed events are produced to for the data platform, with the
+// credentials of the proxy when it requires some. The events aren't streamed when empty.
+// @property {string} KafkaTopicPrefix - the prefix of the topics of the streamed events, each type of
+// event going to its own topic, e.g. bank.transfer.completed.
+// @property {*SecretCache} Secrets - the cache of the secret once it was read, nil without a provider.
 type Config struct {
-	DBDriver             string        `mapstructure:"DB_DRIVER"`
-	DBSource             string        `mapstructure:"DB_SOURCE"`
-	ServerAddress        string        `mapstructure:"SERVER_ADDRESS"`
-	GRPCServerAddress    string        `mapstructure:"GRPC_SERVER_ADDRESS"`
-	TokenSymmetricKey    string        `mapstructure:"TOKEN_SYMMETRIC_KEY"`
-	AccessTokenDuration  time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
-	RefreshTokenDuration time.Duration `mapstructure:"REFRESH_TOKEN_DURATION"`
+	DBSource                     string        `mapstructure:"DB_SOURCE"`
+	DBFailoverSources            string        `mapstructure:"DB_FAILOVER_SOURCES"`
+	DBReplicaSource              string        `mapstructure:"DB_REPLICA_SOURCE"`
+	ReadFromReplica              bool          `mapstructure:"READ_FROM_REPLICA"`
+	DBMaxConns                   int32         `mapstructure:"DB_MAX_CONNS"`
+	DBMinConns                   int32         `mapstructure:"DB_MIN_CONNS"`
+	DBMaxConnLifetime            time.Duration `mapstructure:"DB_MAX_CONN_LIFETIME"`
+	DBMaxConnIdleTime            time.Duration `mapstructure:"DB_MAX_CONN_IDLE_TIME"`
+	DBQueryTimeout               time.Duration `mapstructure:"DB_QUERY_TIMEOUT"`
+	DBTxTimeout                  time.Duration `mapstructure:"DB_TX_TIMEOUT"`
+	DBMaxRetries                 int           `mapstructure:"DB_MAX_RETRIES"`
+	DBRetryBackoff               time.Duration `mapstructure:"DB_RETRY_BACKOFF"`
+	DBRetryMaxBackoff            time.Duration `mapstructure:"DB_RETRY_MAX_BACKOFF"`
+	TransferIsolation            string        `mapstructure:"TRANSFER_ISOLATION"`
+	ServerAddress                string        `mapstructure:"SERVER_ADDRESS"`
+	GRPCServerAddress            string        `mapstructure:"GRPC_SERVER_ADDRESS"`
+	RedisAddress                 string        `mapstructure:"REDIS_ADDRESS"`
+	TokenSymmetricKey            string        `mapstructure:"TOKEN_SYMMETRIC_KEY"`
+	TokenKind                    string        `mapstructure:"TOKEN_KIND"`
+	TokenPrivateKey              string        `mapstructure:"TOKEN_PRIVATE_KEY"`
+	TokenPublicKey               string        `mapstructure:"TOKEN_PUBLIC_KEY"`
+	TokenKeyID                   string        `mapstructure:"TOKEN_KEY_ID"`
+	TokenVerificationKeys        string        `mapstructure:"TOKEN_VERIFICATION_KEYS"`
+	AccessTokenDuration          time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
+	RefreshTokenDuration         time.Duration `mapstructure:"REFRESH_TOKEN_DURATION"`
+	SessionIdleTimeout           time.Duration `mapstructure:"SESSION_IDLE_TIMEOUT"`
+	ImpersonationTokenDuration   time.Duration `mapstructure:"IMPERSONATION_TOKEN_DURATION"`
+	UserDeletionCoolingOff       time.Duration `mapstructure:"USER_DELETION_COOLING_OFF"`
+	ShutdownTimeout              time.Duration `mapstructure:"SHUTDOWN_TIMEOUT"`
+	DrainPeriod                  time.Duration `mapstructure:"DRAIN_PERIOD"`
+	RequestTimeout               time.Duration `mapstructure:"REQUEST_TIMEOUT"`
+	RouteTimeouts                string        `mapstructure:"ROUTE_TIMEOUTS"`
+	MaxRequestBodyBytes          int64         `mapstructure:"MAX_REQUEST_BODY_BYTES"`
+	LeaderElection               bool          `mapstructure:"LEADER_ELECTION"`
+	InstanceID                   string        `mapstructure:"INSTANCE_ID"`
+	AdvertiseAddress             string        `mapstructure:"ADVERTISE_ADDRESS"`
+	LeaderLeaseDuration          time.Duration `mapstructure:"LEADER_LEASE_DURATION"`
+	AccountCacheTTL              time.Duration `mapstructure:"ACCOUNT_CACHE_TTL"`
+	ExportURLDuration            time.Duration `mapstructure:"EXPORT_URL_DURATION"`
+	ExportSigningKey             string        `mapstructure:"EXPORT_SIGNING_KEY"`
+	RunMigrations                bool          `mapstructure:"RUN_MIGRATIONS"`
+	ProjectionInterval           time.Duration `mapstructure:"PROJECTION_INTERVAL"`
+	EndOfDayInterval             time.Duration `mapstructure:"END_OF_DAY_INTERVAL"`
+	PaginationPolicies           string        `mapstructure:"PAGINATION_POLICIES"`
+	ReviewAmountThreshold        int64         `mapstructure:"REVIEW_AMOUNT_THRESHOLD"`
+	ReviewSLA                    time.Duration `mapstructure:"REVIEW_SLA"`
+	PaymentRequestTTL            time.Duration `mapstructure:"PAYMENT_REQUEST_TTL"`
+	PaymentLinkTTL               time.Duration `mapstructure:"PAYMENT_LINK_TTL"`
+	TransferApprovalThreshold    int64         `mapstructure:"TRANSFER_APPROVAL_THRESHOLD"`
+	TransferApprovalTTL          time.Duration `mapstructure:"TRANSFER_APPROVAL_TTL"`
+	CardHoldTTL                  time.Duration `mapstructure:"CARD_HOLD_TTL"`
+	ChequeClearingPeriod         time.Duration `mapstructure:"CHEQUE_CLEARING_PERIOD"`
+	TransferQueueEnabled         bool          `mapstructure:"TRANSFER_QUEUE_ENABLED"`
+	LoginMaxFailures             int64         `mapstructure:"LOGIN_MAX_FAILURES"`
+	LoginClientMaxFailures       int64         `mapstructure:"LOGIN_CLIENT_MAX_FAILURES"`
+	LoginFailureWindow           time.Duration `mapstructure:"LOGIN_FAILURE_WINDOW"`
+	LoginLockoutDuration         time.Duration `mapstructure:"LOGIN_LOCKOUT_DURATION"`
+	NotificationDispatchInterval time.Duration `mapstructure:"NOTIFICATION_DISPATCH_INTERVAL"`
+	MailDriver                   string        `mapstructure:"MAIL_DRIVER"`
+	MailFromAddress              string        `mapstructure:"MAIL_FROM_ADDRESS"`
+	MailFromName                 string        `mapstructure:"MAIL_FROM_NAME"`
+	SMTPHost                     string        `mapstructure:"SMTP_HOST"`
+	SMTPPort                     int           `mapstructure:"SMTP_PORT"`
+	SMTPUsername                 string        `mapstructure:"SMTP_USERNAME"`
+	SMTPPassword                 string        `mapstructure:"SMTP_PASSWORD"`
+	SendGridAPIKey               string        `mapstructure:"SENDGRID_API_KEY"`
+	JSONFieldCasing              string        `mapstructure:"JSON_FIELD_CASING"`
+	SecretsProvider              string        `mapstructure:"SECRETS_PROVIDER"`
+	SecretsName                  string        `mapstructure:"SECRETS_NAME"`
+	SecretsCacheTTL              time.Duration `mapstructure:"SECRETS_CACHE_TTL"`
+	VaultAddress                 string        `mapstructure:"VAULT_ADDR"`
+	VaultToken                   string        `mapstructure:"VAULT_TOKEN"`
+	VaultMount                   string        `mapstructure:"VAULT_MOUNT"`
+	AWSRegion                    string        `mapstructure:"AWS_REGION"`
+	AWSAccessKeyID               string        `mapstructure:"AWS_ACCESS_KEY_ID"`
+	AWSSecretAccessKey           string        `mapstructure:"AWS_SECRET_ACCESS_KEY"`
+	AWSSessionToken              string        `mapstructure:"AWS_SESSION_TOKEN"`
+	SentryDSN                    string        `mapstructure:"SENTRY_DSN"`
+	SentryEnvironment            string        `mapstructure:"SENTRY_ENVIRONMENT"`
+	GoogleClientID               string        `mapstructure:"GOOGLE_CLIENT_ID"`
+	GoogleClientSecret           string        `mapstructure:"GOOGLE_CLIENT_SECRET"`
+	GitHubClientID               string        `mapstructure:"GITHUB_CLIENT_ID"`
+	GitHubClientSecret           string        `mapstructure:"GITHUB_CLIENT_SECRET"`
+	OAuthCallbackBaseURL         string        `mapstructure:"OAUTH_CALLBACK_BASE_URL"`
+	OAuthLinkKey                 string        `mapstructure:"OAUTH_LINK_KEY"`
+	WebhookSigningKey            string        `mapstructure:"WEBHOOK_SIGNING_KEY"`
+	CaptchaProvider              string        `mapstructure:"CAPTCHA_PROVIDER"`
+	CaptchaSecret                string        `mapstructure:"CAPTCHA_SECRET"`
+	SignupMaxPerClient           int64         `mapstructure:"SIGNUP_MAX_PER_CLIENT"`
+	SignupWindow                 time.Duration `mapstructure:"SIGNUP_WINDOW"`
+	APIPlans                     string        `mapstructure:"API_PLANS"`
+	KafkaRESTProxyURL            string        `mapstructure:"KAFKA_REST_PROXY_URL"`
+	KafkaTopicPrefix             string        `mapstructure:"KAFKA_TOPIC_PREFIX"`
+	Secrets                      *SecretCache  `mapstructure:"-"`
 }
 
+const (
+	defaultDBMaxRetries                 = 3
+	defaultDBRetryBackoff               = 50 * time.Millisecond
+	defaultDBRetryMaxBackoff            = time.Second
+	defaultShutdownTimeout              = 10 * time.Second
+	defaultDrainPeriod                  = 5 * time.Second
+	defaultRequestTimeout               = 10 * time.Second
+	defaultMaxRequestBodyBytes          = 1 << 20
+	defaultLeaderLease                  = 15 * time.Second
+	defaultExportURLDuration            = 15 * time.Minute
+	defaultProjectionInterval           = time.Second
+	defaultEndOfDayInterval             = 10 * time.Minute
+	defaultSessionIdleTimeout           = 30 * time.Minute
+	defaultImpersonationTokenDuration   = 15 * time.Minute
+	defaultUserDeletionCoolingOff       = 30 * 24 * time.Hour
+	defaultReviewSLA                    = 24 * time.Hour
+	defaultPaymentRequestTTL            = 7 * 24 * time.Hour
+	defaultPaymentLinkTTL               = 30 * 24 * time.Hour
+	defaultTransferApprovalTTL          = 24 * time.Hour
+	defaultCardHoldTTL                  = 7 * 24 * time.Hour
+	defaultChequeClearingPeriod         = 3 * 24 * time.Hour
+	defaultNotificationDispatchInterval = 5 * time.Second
+	defaultLoginMaxFailures             = 5
+	defaultLoginClientMaxFailures       = 50
+	defaultSignupMaxPerClient           = 10
+	defaultSignupWindow                 = time.Hour
+	defaultLoginFailureWindow           = 15 * time.Minute
+	defaultLoginLockoutDuration         = 30 * time.Minute
+	defaultSecretsCacheTTL              = 5 * time.Minute
+	defaultVaultMount                   = "secret"
+	defaultKafkaTopicPrefix             = "bank."
+)
+
 func LoadConfig(path string) (config Config, err error) {
 	if os.Getenv("G_ACTIONS") == "true" {
 		// GITHUB ACTIONS ENV VARIABLES
-		config.DBDriver = os.Getenv("DB_DRIVER")
 		config.DBSource = os.Getenv("DB_SOURCE")
+		config.DBFailoverSources = os.Getenv("DB_FAILOVER_SOURCES")
+		config.DBReplicaSource = os.Getenv("DB_REPLICA_SOURCE")
+		config.ReadFromReplica = os.Getenv("READ_FROM_REPLICA") == "true"
+		config.DBQueryTimeout, _ = time.ParseDuration(os.Getenv("DB_QUERY_TIMEOUT"))
+		config.DBTxTimeout, _ = time.ParseDuration(os.Getenv("DB_TX_TIMEOUT"))
+		config.DBMaxRetries = defaultDBMaxRetries
+		config.DBRetryBackoff = defaultDBRetryBackoff
+		config.DBRetryMaxBackoff = defaultDBRetryMaxBackoff
+		config.TransferIsolation = os.Getenv("TRANSFER_ISOLATION")
 		config.ServerAddress = os.Getenv("SERVER_ADDRESS")
 		config.GRPCServerAddress = os.Getenv("GRPC_SERVER_ADDRESS")
+		config.RedisAddress = os.Getenv("REDIS_ADDRESS")
 		config.TokenSymmetricKey = os.Getenv("TOKEN_SYMMETRIC_KEY")
+		config.TokenKind = os.Getenv("TOKEN_KIND")
+		config.TokenPrivateKey = os.Getenv("TOKEN_PRIVATE_KEY")
+		config.TokenPublicKey = os.Getenv("TOKEN_PUBLIC_KEY")
+		config.TokenKeyID = os.Getenv("TOKEN_KEY_ID")
+		config.TokenVerificationKeys = os.Getenv("TOKEN_VERIFICATION_KEYS")
 		config.AccessTokenDuration = time.Hour
 		config.RefreshTokenDuration = time.Hour * 24
+		config.SessionIdleTimeout = defaultSessionIdleTimeout
+		config.ImpersonationTokenDuration = defaultImpersonationTokenDuration
+		config.UserDeletionCoolingOff = defaultUserDeletionCoolingOff
+		config.ShutdownTimeout = defaultShutdownTimeout
+		config.DrainPeriod = defaultDrainPeriod
+		config.RequestTimeout = defaultRequestTimeout
+		config.RouteTimeouts = os.Getenv("ROUTE_TIMEOUTS")
+		config.MaxRequestBodyBytes = defaultMaxRequestBodyBytes
+		config.LeaderElection = os.Getenv("LEADER_ELECTION") == "true"
+		config.InstanceID = os.Getenv("INSTANCE_ID")
+		config.AdvertiseAddress = os.Getenv("ADVERTISE_ADDRESS")
+		config.LeaderLeaseDuration = defaultLeaderLease
+		config.AccountCacheTTL, _ = time.ParseDuration(os.Getenv("ACCOUNT_CACHE_TTL"))
+		config.ExportURLDuration = defaultExportURLDuration
+		config.RunMigrations = os.Getenv("RUN_MIGRATIONS") == "true"
+		config.ProjectionInterval = defaultProjectionInterval
+		config.EndOfDayInterval = defaultEndOfDayInterval
+		config.PaginationPolicies = os.Getenv("PAGINATION_POLICIES")
+		config.ReviewSLA = defaultReviewSLA
+		config.PaymentRequestTTL = defaultPaymentRequestTTL
+		config.PaymentLinkTTL = defaultPaymentLinkTTL
+		config.TransferApprovalTTL = defaultTransferApprovalTTL
+		config.CardHoldTTL = defaultCardHoldTTL
+		config.ChequeClearingPeriod = defaultChequeClearingPeriod
+		config.TransferQueueEnabled = os.Getenv("TRANSFER_QUEUE_ENABLED") == "true"
+		config.LoginMaxFailures = defaultLoginMaxFailures
+		config.LoginClientMaxFailures = defaultLoginClientMaxFailures
+		config.SignupMaxPerClient = defaultSignupMaxPerClient
+		config.SignupWindow = defaultSignupWindow
+		config.LoginFailureWindow = defaultLoginFailureWindow
+		config.LoginLockoutDuration = defaultLoginLockoutDuration
+		config.NotificationDispatchInterval = defaultNotificationDispatchInterval
+		config.MailDriver = os.Getenv("MAIL_DRIVER")
+		config.MailFromAddress = os.Getenv("MAIL_FROM_ADDRESS")
+		config.MailFromName = os.Getenv("MAIL_FROM_NAME")
+		config.SMTPHost = os.Getenv("SMTP_HOST")
+		config.SMTPPort, _ = strconv.Atoi(os.Getenv("SMTP_PORT"))
+		config.SMTPUsername = os.Getenv("SMTP_USERNAME")
+		config.SMTPPassword = os.Getenv("SMTP_PASSWORD")
+		config.SendGridAPIKey = os.Getenv("SENDGRID_API_KEY")
+		config.JSONFieldCasing = os.Getenv("JSON_FIELD_CASING")
+		config.SecretsProvider = os.Getenv("SECRETS_PROVIDER")
+		config.SecretsName = os.Getenv("SECRETS_NAME")
+		config.SecretsCacheTTL = defaultSecretsCacheTTL
+		config.VaultAddress = os.Getenv("VAULT_ADDR")
+		config.VaultToken = os.Getenv("VAULT_TOKEN")
+		config.VaultMount = defaultVaultMount
+		config.AWSRegion = os.Getenv("AWS_REGION")
+		config.AWSAccessKeyID = os.Getenv("AWS_ACCESS_KEY_ID")
+		config.AWSSecretAccessKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
+		config.AWSSessionToken = os.Getenv("AWS_SESSION_TOKEN")
+		config.SentryDSN = os.Getenv("SENTRY_DSN")
+		config.SentryEnvironment = os.Getenv("SENTRY_ENVIRONMENT")
+		config.GoogleClientID = os.Getenv("GOOGLE_CLIENT_ID")
+		config.GoogleClientSecret = os.Getenv("GOOGLE_CLIENT_SECRET")
+		config.GitHubClientID = os.Getenv("GITHUB_CLIENT_ID")
+		config.GitHubClientSecret = os.Getenv("GITHUB_CLIENT_SECRET")
+		config.OAuthCallbackBaseURL = os.Getenv("OAUTH_CALLBACK_BASE_URL")
+		config.ExportSigningKey = os.Getenv("EXPORT_SIGNING_KEY")
+		config.OAuthLinkKey = os.Getenv("OAUTH_LINK_KEY")
+		config.WebhookSigningKey = os.Getenv("WEBHOOK_SIGNING_KEY")
+		config.CaptchaProvider = os.Getenv("CAPTCHA_PROVIDER")
+		config.CaptchaSecret = os.Getenv("CAPTCHA_SECRET")
+		config.APIPlans = os.Getenv("API_PLANS")
+		config.KafkaRESTProxyURL = os.Getenv("KAFKA_REST_PROXY_URL")
+		config.KafkaTopicPrefix = defaultKafkaTopicPrefix
 	} else {
 		viper.SetConfigFile(path)
+		viper.SetDefault("DB_MAX_RETRIES", defaultDBMaxRetries)
+		viper.SetDefault("DB_RETRY_BACKOFF", defaultDBRetryBackoff)
+		viper.SetDefault("DB_RETRY_MAX_BACKOFF", defaultDBRetryMaxBackoff)
+		viper.SetDefault("SHUTDOWN_TIMEOUT", defaultShutdownTimeout)
+		viper.SetDefault("DRAIN_PERIOD", defaultDrainPeriod)
+		viper.SetDefault("REQUEST_TIMEOUT", defaultRequestTimeout)
+		viper.SetDefault("MAX_REQUEST_BODY_BYTES", defaultMaxRequestBodyBytes)
+		viper.SetDefault("LEADER_LEASE_DURATION", defaultLeaderLease)
+		viper.SetDefault("EXPORT_URL_DURATION", defaultExportURLDuration)
+		viper.SetDefault("PROJECTION_INTERVAL", defaultProjectionInterval)
+		viper.SetDefault("END_OF_DAY_INTERVAL", defaultEndOfDayInterval)
+		viper.SetDefault("SESSION_IDLE_TIMEOUT", defaultSessionIdleTimeout)
+		viper.SetDefault("IMPERSONATION_TOKEN_DURATION", defaultImpersonationTokenDuration)
+		viper.SetDefault("USER_DELETION_COOLING_OFF", defaultUserDeletionCoolingOff)
+		viper.SetDefault("REVIEW_SLA", defaultReviewSLA)
+		viper.SetDefault("PAYMENT_REQUEST_TTL", defaultPaymentRequestTTL)
+		viper.SetDefault("PAYMENT_LINK_TTL", defaultPaymentLinkTTL)
+		viper.SetDefault("TRANSFER_APPROVAL_TTL", defaultTransferApprovalTTL)
+		viper.SetDefault("CARD_HOLD_TTL", defaultCardHoldTTL)
+		viper.SetDefault("CHEQUE_CLEARING_PERIOD", defaultChequeClearingPeriod)
+		viper.SetDefault("LOGIN_MAX_FAILURES", defaultLoginMaxFailures)
+		viper.SetDefault("LOGIN_CLIENT_MAX_FAILURES", defaultLoginClientMaxFailures)
+		viper.SetDefault("SIGNUP_MAX_PER_CLIENT", defaultSignupMaxPerClient)
+		viper.SetDefault("SIGNUP_WINDOW", defaultSignupWindow)
+		viper.SetDefault("LOGIN_FAILURE_WINDOW", defaultLoginFailureWindow)
+		viper.SetDefault("LOGIN_LOCKOUT_DURATION", defaultLoginLockoutDuration)
+		viper.SetDefault("NOTIFICATION_DISPATCH_INTERVAL", defaultNotificationDispatchInterval)
+		viper.SetDefault("SECRETS_CACHE_TTL", defaultSecretsCacheTTL)
+		viper.SetDefault("VAULT_MOUNT", defaultVaultMount)
+		viper.SetDefault("KAFKA_TOPIC_PREFIX", defaultKafkaTopicPrefix)
 		viper.AutomaticEnv()
 		err = viper.ReadInConfig()
 		if err != nil {
@@ -46,7 +384,12 @@ func LoadConfig(path string) (config Config, err error) {
 		}
 
 		err = viper.Unmarshal(&config)
+		if err != nil {
+			return
+		}
 	}
 
+	// the secrets override the config they were read with
+	err = config.loadSecrets(context.Background())
 	return
 }
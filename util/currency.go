@@ -1,11 +1,26 @@
 package util
 
+import (
+	"strconv"
+	"strings"
+)
+
 const (
 	USD = "USD"
 	EUR = "EUR"
 	CAD = "CAD"
 )
 
+// SupportedCurrencies lists the currencies the accounts can hold.
+var SupportedCurrencies = []string{USD, EUR, CAD}
+
+// currencyExponents are the numbers of decimals of the supported currencies, from ISO 4217.
+var currencyExponents = map[string]int{
+	USD: 2,
+	EUR: 2,
+	CAD: 2,
+}
+
 // The function checks if a given currency is supported and returns a boolean value.
 func IsSupportedCurrency(currency string) bool {
 	switch currency {
@@ -14,3 +29,37 @@ func IsSupportedCurrency(currency string) bool {
 	}
 	return false
 }
+
+// The `CurrencyExponent` function returns the number of decimals the currency is written with, two for
+// the currencies missing from the catalog.
+func CurrencyExponent(currency string) int {
+	if exponent, ok := currencyExponents[currency]; ok {
+		return exponent
+	}
+	return 2
+}
+
+// The `FormatAmount` function writes an amount of the currency for display, with its thousands separated
+// and the decimals of the currency, e.g. "1,234.00 CAD". The amounts of the bank being whole units of
+// their currency, their decimals are zeros.
+func FormatAmount(amount int64, currency string) string {
+	var b strings.Builder
+	magnitude := uint64(amount)
+	if amount < 0 {
+		b.WriteByte('-')
+		magnitude = uint64(-amount)
+	}
+
+	digits := strconv.FormatUint(magnitude, 10)
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(digit)
+	}
+	if exponent := CurrencyExponent(currency); exponent > 0 {
+		b.WriteString("." + strings.Repeat("0", exponent))
+	}
+	b.WriteString(" " + currency)
+	return b.String()
+}
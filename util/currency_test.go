@@ -14,3 +14,23 @@ func TestIsSupportedCurrency(t *testing.T) {
 	invalidCurr := "hi"
 	require.False(t, IsSupportedCurrency(invalidCurr))
 }
+
+func TestFormatAmount(t *testing.T) {
+	testCases := []struct {
+		amount   int64
+		currency string
+		display  string
+	}{
+		{amount: 0, currency: USD, display: "0.00 USD"},
+		{amount: 42, currency: EUR, display: "42.00 EUR"},
+		{amount: 1234, currency: CAD, display: "1,234.00 CAD"},
+		{amount: -1234567, currency: USD, display: "-1,234,567.00 USD"},
+		{amount: 100000, currency: USD, display: "100,000.00 USD"},
+		{amount: -9223372036854775808, currency: USD, display: "-9,223,372,036,854,775,808.00 USD"},
+	}
+
+	for _, tc := range testCases {
+		require.Equal(t, tc.display, FormatAmount(tc.amount, tc.currency))
+	}
+	require.Equal(t, 2, CurrencyExponent("XYZ"))
+}
@@ -0,0 +1,35 @@
+package util
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// cursorPrefix versions the cursors, so that their content can change without the old ones being
+// misread.
+const cursorPrefix = "v1:"
+
+// ErrInvalidCursor is returned when decoding a cursor which wasn't made by `EncodeCursor`.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// The `EncodeCursor` function writes the position of a keyset page, the id of the last item of the
+// previous page, as an opaque cursor the clients send back as is to get the next page.
+func EncodeCursor(id int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(cursorPrefix + strconv.FormatInt(id, 10)))
+}
+
+// The `DecodeCursor` function returns the id a cursor made by `EncodeCursor` holds.
+func DecodeCursor(cursor string) (int64, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || !strings.HasPrefix(string(data), cursorPrefix) {
+		return 0, ErrInvalidCursor
+	}
+
+	id, err := strconv.ParseInt(strings.TrimPrefix(string(data), cursorPrefix), 10, 64)
+	if err != nil || id < 1 {
+		return 0, ErrInvalidCursor
+	}
+	return id, nil
+}
@@ -0,0 +1,23 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursor(t *testing.T) {
+	for _, id := range []int64{1, 42, 9223372036854775807} {
+		cursor := EncodeCursor(id)
+		require.NotContains(t, cursor, "=")
+
+		decoded, err := DecodeCursor(cursor)
+		require.NoError(t, err)
+		require.Equal(t, id, decoded)
+	}
+
+	for _, cursor := range []string{"", "42", "not base64!", EncodeCursor(0), "djI6NDI"} {
+		_, err := DecodeCursor(cursor)
+		require.ErrorIs(t, err, ErrInvalidCursor, cursor)
+	}
+}
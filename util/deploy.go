@@ -0,0 +1,86 @@
+package util
+
+import (
+	"encoding/json"
+	"net/http"
+	"runtime/debug"
+	"sync/atomic"
+)
+
+// GitSHA and BuildTime identify the build of the binary, they are set at link time, e.g.
+// `go build -ldflags "-X go-backend/util.GitSHA=$(git rev-parse HEAD)"`. GitSHA falls back to the
+// revision recorded by the go command when the binary is built from a checkout.
+var (
+	GitSHA    string
+	BuildTime string
+)
+
+// The BuildInfo type identifies the build of the running binary.
+// @property {string} GitSHA - the commit the binary was built from, "unknown" when it wasn't recorded.
+// @property {string} BuildTime - when the binary was built, "unknown" when it wasn't recorded.
+type BuildInfo struct {
+	GitSHA    string `json:"git_sha"`
+	BuildTime string `json:"build_time"`
+}
+
+// The CurrentBuild function returns the build of the running binary.
+func CurrentBuild() BuildInfo {
+	info := BuildInfo{GitSHA: GitSHA, BuildTime: BuildTime}
+
+	if buildInfo, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range buildInfo.Settings {
+			switch {
+			case setting.Key == "vcs.revision" && info.GitSHA == "":
+				info.GitSHA = setting.Value
+			case setting.Key == "vcs.time" && info.BuildTime == "":
+				info.BuildTime = setting.Value
+			}
+		}
+	}
+
+	if info.GitSHA == "" {
+		info.GitSHA = "unknown"
+	}
+	if info.BuildTime == "" {
+		info.BuildTime = "unknown"
+	}
+	return info
+}
+
+// The VersionHandler function serves the build of the running binary, so that a rolling deploy can
+// check which version each instance runs.
+func VersionHandler(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, CurrentBuild())
+}
+
+// The Readiness type reports whether the server should receive new requests. It stops being ready once
+// it starts draining on shutdown, while it still serves the requests routed to it before the load
+// balancer noticed.
+type Readiness struct {
+	draining atomic.Bool
+}
+
+// The Drain function marks the server as no longer ready.
+func (readiness *Readiness) Drain() {
+	readiness.draining.Store(true)
+}
+
+// The Ready function reports whether the server is ready, i.e. it isn't draining.
+func (readiness *Readiness) Ready() bool {
+	return !readiness.draining.Load()
+}
+
+// The ServeHTTP function serves the readiness probe, which fails with 503 while the server is draining.
+func (readiness *Readiness) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !readiness.Ready() {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"status": "draining"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ready"})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,29 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCurrentBuild(t *testing.T) {
+	defer func(gitSHA, buildTime string) {
+		GitSHA, BuildTime = gitSHA, buildTime
+	}(GitSHA, BuildTime)
+
+	GitSHA = "0123abc"
+	BuildTime = "2023-09-01T10:00:00Z"
+	require.Equal(t, BuildInfo{GitSHA: "0123abc", BuildTime: "2023-09-01T10:00:00Z"}, CurrentBuild())
+
+	// test binaries don't record a revision
+	GitSHA, BuildTime = "", ""
+	require.Equal(t, BuildInfo{GitSHA: "unknown", BuildTime: "unknown"}, CurrentBuild())
+}
+
+func TestReadiness(t *testing.T) {
+	var readiness Readiness
+	require.True(t, readiness.Ready())
+
+	readiness.Drain()
+	require.False(t, readiness.Ready())
+}
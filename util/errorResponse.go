@@ -1,8 +1,207 @@
 package util
 
-import "github.com/gin-gonic/gin"
+import (
+	"encoding/json"
+	"errors"
+	"go-backend/i18n"
+	"net/http"
+	"reflect"
+	"strings"
 
-// The function returns a Gin H map with an error message as its value.
-func ErrorResponse(err error) gin.H {
-	return gin.H{"error": err.Error()}
+	"github.com/go-playground/validator/v10"
+)
+
+// Machine-readable codes of the error responses. Clients should branch on the code rather than on the
+// message, which is meant for humans and may change. Errors can carry a more specific code, such as
+// CURRENCY_MISMATCH, with `WithErrorCode`.
+const (
+	ErrorCodeInvalidArgument      = "INVALID_ARGUMENT"
+	ErrorCodeValidationFailed     = "VALIDATION_FAILED"
+	ErrorCodeUnauthenticated      = "UNAUTHENTICATED"
+	ErrorCodePermissionDenied     = "PERMISSION_DENIED"
+	ErrorCodeNotFound             = "NOT_FOUND"
+	ErrorCodeAlreadyExists        = "ALREADY_EXISTS"
+	ErrorCodeConflict             = "CONFLICT"
+	ErrorCodeFailedPrecondition   = "FAILED_PRECONDITION"
+	ErrorCodeInternal             = "INTERNAL"
+	ErrorCodeUnavailable          = "UNAVAILABLE"
+	ErrorCodeLocked               = "LOCKED"
+	ErrorCodePayloadTooLarge      = "PAYLOAD_TOO_LARGE"
+	ErrorCodeDeadlineExceeded     = "DEADLINE_EXCEEDED"
+	ErrorCodePreconditionRequired = "PRECONDITION_REQUIRED"
+	ErrorCodeCaptchaFailed        = "CAPTCHA_FAILED"
+	ErrorCodeResourceExhausted    = "RESOURCE_EXHAUSTED"
+	ErrorCodeSignatureRequired    = "SIGNATURE_REQUIRED"
+	ErrorCodeSignatureInvalid     = "SIGNATURE_INVALID"
+	ErrorCodeInsufficientScope    = "INSUFFICIENT_SCOPE"
+)
+
+// statusErrorCodes are the codes of the errors that don't carry their own, by HTTP status.
+var statusErrorCodes = map[int]string{
+	http.StatusBadRequest:            ErrorCodeInvalidArgument,
+	http.StatusUnauthorized:          ErrorCodeUnauthenticated,
+	http.StatusForbidden:             ErrorCodePermissionDenied,
+	http.StatusNotFound:              ErrorCodeNotFound,
+	http.StatusConflict:              ErrorCodeConflict,
+	http.StatusInternalServerError:   ErrorCodeInternal,
+	http.StatusServiceUnavailable:    ErrorCodeUnavailable,
+	http.StatusLocked:                ErrorCodeLocked,
+	http.StatusRequestEntityTooLarge: ErrorCodePayloadTooLarge,
+	http.StatusGatewayTimeout:        ErrorCodeDeadlineExceeded,
+	http.StatusPreconditionRequired:  ErrorCodePreconditionRequired,
+	http.StatusTooManyRequests:       ErrorCodeResourceExhausted,
+}
+
+// The FieldError type describes why a field of the request failed its validation.
+// @property {string} Field - the name of the field in the request, e.g. `from_account_id`.
+// @property {string} Code - the validation that failed, e.g. `REQUIRED` or `CURRENCY`.
+// @property {string} Message - the reason of the failure.
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+
+	// describes the failure in a language, for the field errors of validations
+	localize func(language string) string
+}
+
+// The ErrorBody type is the body of every error response of the API.
+// @property {string} Code - the machine-readable code of the error.
+// @property {string} Message - the description of the error.
+// @property {[]FieldError} FieldErrors - the fields of the request that failed their validation, if any.
+type ErrorBody struct {
+	Code        string       `json:"code"`
+	Message     string       `json:"message"`
+	FieldErrors []FieldError `json:"field_errors"`
+
+	// whether the message is made of the messages of the field errors
+	fieldMessages bool
+}
+
+type codedError struct {
+	code string
+	err  error
+}
+
+func (e *codedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *codedError) Unwrap() error {
+	return e.err
+}
+
+// The `WithErrorCode` function attaches a machine-readable code to an error, which its error response
+// uses instead of the code of its status.
+func WithErrorCode(code string, err error) error {
+	return &codedError{code: code, err: err}
+}
+
+// The function returns the body of the error response with the given status. Validation errors list
+// the fields that failed under the VALIDATION_FAILED code, with a message per field in plain words. The
+// messages are in English, `Localize` writing them in another language.
+func ErrorResponse(status int, err error) ErrorBody {
+	body := ErrorBody{
+		Code:        statusErrorCodes[status],
+		Message:     err.Error(),
+		FieldErrors: []FieldError{},
+	}
+	if body.Code == "" {
+		body.Code = ErrorCodeInternal
+	}
+
+	var validationErrs validator.ValidationErrors
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &validationErrs):
+		for _, fieldErr := range validationErrs {
+			fieldErr := fieldErr
+			body.FieldErrors = append(body.FieldErrors, FieldError{
+				Field:   fieldErr.Field(),
+				Code:    strings.ToUpper(fieldErr.Tag()),
+				Message: ValidationMessage(fieldErr),
+				localize: func(language string) string {
+					return LocalizedValidationMessage(language, fieldErr)
+				},
+			})
+		}
+	case errors.As(err, &typeErr):
+		key := "validation.type." + jsonTypeName(typeErr.Type)
+		field := typeErr.Field
+		body.FieldErrors = append(body.FieldErrors, FieldError{
+			Field:   field,
+			Code:    "TYPE",
+			Message: i18n.Message(i18n.DefaultLanguage, key, "field", field),
+			localize: func(language string) string {
+				return i18n.Message(language, key, "field", field)
+			},
+		})
+	}
+
+	if len(body.FieldErrors) > 0 {
+		body.Code = ErrorCodeValidationFailed
+		body.Message = joinFieldMessages(body.FieldErrors)
+		body.fieldMessages = true
+	}
+
+	var coded *codedError
+	if errors.As(err, &coded) {
+		body.Code = coded.code
+	}
+
+	return body
+}
+
+// The `Localize` method returns the body with its messages in the language. The messages of the field
+// errors of validations are written in the language, and the others, written in English by the API,
+// are replaced by the message of their code in the language, when its catalog has one. Bodies in the
+// default language are left as they are, keeping their detailed messages.
+func (body ErrorBody) Localize(language string) ErrorBody {
+	if language == i18n.DefaultLanguage {
+		return body
+	}
+
+	localized := body
+	localized.FieldErrors = make([]FieldError, 0, len(body.FieldErrors))
+	for _, fieldErr := range body.FieldErrors {
+		if fieldErr.localize != nil {
+			fieldErr.Message = fieldErr.localize(language)
+		} else if message, ok := i18n.Lookup(language, "error."+fieldErr.Code); ok {
+			fieldErr.Message = message
+		}
+		localized.FieldErrors = append(localized.FieldErrors, fieldErr)
+	}
+
+	if body.fieldMessages {
+		localized.Message = joinFieldMessages(localized.FieldErrors)
+	} else if message, ok := i18n.Lookup(language, "error."+body.Code); ok {
+		localized.Message = message
+	}
+	return localized
+}
+
+// joinFieldMessages makes the message of a validation failure out of those of its field errors.
+func joinFieldMessages(fieldErrs []FieldError) string {
+	messages := make([]string, 0, len(fieldErrs))
+	for _, fieldErr := range fieldErrs {
+		messages = append(messages, fieldErr.Message)
+	}
+	return strings.Join(messages, "; ")
+}
+
+// jsonTypeName names a Go type the way a client sending json thinks of it.
+func jsonTypeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Slice, reflect.Array:
+		return "list"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	}
+	return "string"
 }
@@ -0,0 +1,69 @@
+package util
+
+import (
+	"errors"
+	"go-backend/i18n"
+	"net/http"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorResponse(t *testing.T) {
+	err := errors.New("page_size must be between 1 and 100")
+	require.Equal(t, ErrorBody{
+		Code:        ErrorCodeInvalidArgument,
+		Message:     err.Error(),
+		FieldErrors: []FieldError{},
+	}, ErrorResponse(http.StatusBadRequest, err))
+
+	body := ErrorResponse(http.StatusBadRequest, WithErrorCode("CURRENCY_MISMATCH", err))
+	require.Equal(t, "CURRENCY_MISMATCH", body.Code)
+	require.Equal(t, err.Error(), body.Message)
+
+	body = ErrorResponse(http.StatusTeapot, err)
+	require.Equal(t, ErrorCodeInternal, body.Code)
+
+	request := struct {
+		Currency string `validate:"required"`
+		Amount   int64  `validate:"gt=0"`
+	}{Amount: -1}
+	err = validator.New().Struct(request)
+	require.Error(t, err)
+
+	body = ErrorResponse(http.StatusBadRequest, err)
+	require.Equal(t, ErrorCodeValidationFailed, body.Code)
+	require.Equal(t, "Currency is required; Amount must be greater than 0", body.Message)
+	require.Len(t, body.FieldErrors, 2)
+	require.Equal(t, "Currency", body.FieldErrors[0].Field)
+	require.Equal(t, "REQUIRED", body.FieldErrors[0].Code)
+	require.Equal(t, "Amount", body.FieldErrors[1].Field)
+	require.Equal(t, "GT", body.FieldErrors[1].Code)
+}
+
+func TestLocalizeErrorResponse(t *testing.T) {
+	request := struct {
+		Currency string `validate:"required"`
+		Amount   int64  `validate:"gt=0"`
+	}{Amount: -1}
+	err := validator.New().Struct(request)
+	require.Error(t, err)
+
+	body := ErrorResponse(http.StatusBadRequest, err)
+	require.Equal(t, body, body.Localize(i18n.English))
+
+	localized := body.Localize(i18n.French)
+	require.Equal(t, ErrorCodeValidationFailed, localized.Code)
+	require.Equal(t, "Currency est obligatoire; Amount doit être supérieur à 0", localized.Message)
+	require.Equal(t, "Currency est obligatoire", localized.FieldErrors[0].Message)
+	require.Equal(t, "Currency is required", body.FieldErrors[0].Message)
+
+	// the other errors get the message of their code, when the catalog has one
+	localized = ErrorResponse(http.StatusNotFound, errors.New("account 42 not found")).Localize(i18n.French)
+	require.Equal(t, ErrorCodeNotFound, localized.Code)
+	require.Equal(t, "la ressource n'existe pas", localized.Message)
+
+	localized = ErrorResponse(http.StatusBadRequest, WithErrorCode("SOMETHING_NEW", errors.New("oops"))).Localize(i18n.French)
+	require.Equal(t, "oops", localized.Message)
+}
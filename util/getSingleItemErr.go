@@ -1,21 +1,36 @@
 package util
 
 import (
-	"database/sql"
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
 )
 
+// Errors of the database mapped to HTTP statuses by `CheckError`. They are re-exported by the db package,
+// whose `TranslateError` function turns the postgres errors into them, and are declared here because util
+// can't import the db package.
+var (
+	ErrRecordNotFound      = pgx.ErrNoRows
+	ErrUniqueViolation     = errors.New("unique violation")
+	ErrForeignKeyViolation = errors.New("foreign key violation")
+)
+
+// The `CheckError` function responds with the status matching a translated database error and reports
+// whether the request can go on, i.e. whether there was no error.
 func CheckError(ctx *gin.Context, err error) bool {
-	if err != nil {
-		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, ErrorResponse(err))
-			return false
-		}
-		ctx.JSON(http.StatusInternalServerError, ErrorResponse(err))
-		return false
+	if err == nil {
+		return true
 	}
 
-	return true
+	switch {
+	case errors.Is(err, ErrRecordNotFound):
+		ctx.JSON(http.StatusNotFound, ErrorResponse(http.StatusNotFound, err))
+	case errors.Is(err, ErrUniqueViolation), errors.Is(err, ErrForeignKeyViolation):
+		ctx.JSON(http.StatusForbidden, ErrorResponse(http.StatusForbidden, WithErrorCode(ErrorCodeAlreadyExists, err)))
+	default:
+		ctx.JSON(http.StatusInternalServerError, ErrorResponse(http.StatusInternalServerError, err))
+	}
+	return false
 }
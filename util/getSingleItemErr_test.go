@@ -0,0 +1,35 @@
+package util
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testCases := []struct {
+		err    error
+		ok     bool
+		status int
+	}{
+		{err: nil, ok: true, status: http.StatusOK},
+		{err: ErrRecordNotFound, status: http.StatusNotFound},
+		{err: ErrUniqueViolation, status: http.StatusForbidden},
+		{err: ErrForeignKeyViolation, status: http.StatusForbidden},
+		{err: errors.New("connection refused"), status: http.StatusInternalServerError},
+	}
+
+	for _, tc := range testCases {
+		recorder := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(recorder)
+
+		require.Equal(t, tc.ok, CheckError(ctx, tc.err))
+		require.Equal(t, tc.status, recorder.Code)
+	}
+}
@@ -0,0 +1,97 @@
+package util
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// The `ParseRouteTimeouts` function parses the per route timeouts of the ROUTE_TIMEOUTS config,
+// formatted as a comma separated list of `METHOD path=duration`, the path being the route as registered,
+// e.g. `POST /api/v1/transfers/batch=30s,GET /api/v1/jobs/:id/download=1m`. A duration of 0 lifts the
+// timeout of the route.
+func ParseRouteTimeouts(value string) (map[string]time.Duration, error) {
+	timeouts := make(map[string]time.Duration)
+	if strings.TrimSpace(value) == "" {
+		return timeouts, nil
+	}
+
+	for _, item := range strings.Split(value, ",") {
+		route, duration, ok := strings.Cut(strings.TrimSpace(item), "=")
+		method, path, hasPath := strings.Cut(route, " ")
+		if !ok || !hasPath || method == "" || !strings.HasPrefix(path, "/") {
+			return nil, fmt.Errorf("invalid route timeout %q, expected METHOD path=duration", item)
+		}
+
+		timeout, err := time.ParseDuration(duration)
+		if err != nil || timeout < 0 {
+			return nil, fmt.Errorf("invalid duration %q in route timeout %q", duration, item)
+		}
+
+		timeouts[strings.ToUpper(method)+" "+path] = timeout
+	}
+
+	return timeouts, nil
+}
+
+// The `WithRequestTimeout` function returns the request with its context cancelled after `timeout`, so
+// that the queries it makes are cancelled past it, along with the function releasing the context. The
+// request is returned as is when `timeout` is 0.
+func WithRequestTimeout(r *http.Request, timeout time.Duration) (*http.Request, context.CancelFunc) {
+	if timeout <= 0 {
+		return r, func() {}
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	return r.WithContext(ctx), cancel
+}
+
+// The `ReadLimitedBody` function reads the body of the request up front, failing with an
+// `*http.MaxBytesError` when it is larger than `maxBytes`, and replaces it with the bytes read so that
+// the handlers can still read it. The body isn't limited when `maxBytes` is 0.
+func ReadLimitedBody(w http.ResponseWriter, r *http.Request, maxBytes int64) error {
+	if maxBytes <= 0 || r.Body == nil || r.Body == http.NoBody {
+		return nil
+	}
+	if r.ContentLength > maxBytes {
+		return &http.MaxBytesError{Limit: maxBytes}
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBytes))
+	if err != nil {
+		return err
+	}
+
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	return nil
+}
+
+// The `LimitRequests` function limits the requests served by `next` to `timeout` and their bodies to
+// `maxBodyBytes`, rejecting larger bodies with 413. Either limit is lifted when it is 0.
+func LimitRequests(next http.Handler, timeout time.Duration, maxBodyBytes int64) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := ReadLimitedBody(w, r, maxBodyBytes); err != nil {
+			status := http.StatusBadRequest
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				status = http.StatusRequestEntityTooLarge
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			json.NewEncoder(w).Encode(ErrorResponse(status, err))
+			return
+		}
+
+		r, cancel := WithRequestTimeout(r, timeout)
+		defer cancel()
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,83 @@
+package util
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRouteTimeouts(t *testing.T) {
+	timeouts, err := ParseRouteTimeouts("")
+	require.NoError(t, err)
+	require.Empty(t, timeouts)
+
+	timeouts, err = ParseRouteTimeouts("post /api/v1/transfers/batch=30s, GET /api/v1/jobs/:id/download=0")
+	require.NoError(t, err)
+	require.Equal(t, map[string]time.Duration{
+		"POST /api/v1/transfers/batch":  30 * time.Second,
+		"GET /api/v1/jobs/:id/download": 0,
+	}, timeouts)
+
+	invalid := []string{
+		"POST /api/v1/transfers",
+		"/api/v1/transfers=5s",
+		"POST api/v1/transfers=5s",
+		"POST /api/v1/transfers=soon",
+		"POST /api/v1/transfers=-5s",
+	}
+	for _, value := range invalid {
+		_, err := ParseRouteTimeouts(value)
+		require.Error(t, err, value)
+	}
+}
+
+func TestLimitRequests(t *testing.T) {
+	var body string
+	var deadline time.Time
+	handler := LimitRequests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		body = string(data)
+		deadline, _ = r.Context().Deadline()
+	}), time.Minute, 8)
+
+	testCases := []struct {
+		name   string
+		body   io.Reader
+		length int64
+		status int
+	}{
+		{name: "OK", body: strings.NewReader("12345678"), length: 8, status: http.StatusOK},
+		{name: "TooLarge", body: strings.NewReader("123456789"), length: 9, status: http.StatusRequestEntityTooLarge},
+		// a body without a length is only found to be too large once read
+		{name: "TooLargeChunked", body: io.MultiReader(strings.NewReader("12345"), strings.NewReader("6789")), length: -1, status: http.StatusRequestEntityTooLarge},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			body, deadline = "", time.Time{}
+
+			request := httptest.NewRequest(http.MethodPost, "/v1/create_user", tc.body)
+			request.ContentLength = tc.length
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.status, recorder.Code)
+			if tc.status != http.StatusOK {
+				require.Contains(t, recorder.Body.String(), ErrorCodePayloadTooLarge)
+				require.Empty(t, body)
+				return
+			}
+
+			require.Equal(t, "12345678", body)
+			require.WithinDuration(t, time.Now().Add(time.Minute), deadline, time.Second)
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package util
+
+import "net"
+
+// sharedAddressSpace is the range carrier-grade NATs use, which isn't reachable from the internet either.
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// The function reports whether the IP is a public address of the internet, rather than a loopback,
+// private, link-local, multicast or unspecified one, so that the URLs users give can't reach the hosts of
+// the bank's own network, e.g. the metadata service of the cloud at 169.254.169.254.
+func IsPublicIP(ip net.IP) bool {
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	}
+	return !ip.IsLoopback() &&
+		!ip.IsPrivate() &&
+		!ip.IsLinkLocalUnicast() &&
+		!ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() &&
+		!ip.IsMulticast() &&
+		!ip.IsUnspecified() &&
+		!sharedAddressSpace.Contains(ip)
+}
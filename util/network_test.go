@@ -0,0 +1,32 @@
+package util
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsPublicIP(t *testing.T) {
+	for _, ip := range []string{"93.184.216.34", "2606:2800:220:1:248:1893:25c8:1946"} {
+		require.True(t, IsPublicIP(net.ParseIP(ip)), ip)
+	}
+
+	for _, ip := range []string{
+		"127.0.0.1",
+		"::1",
+		"10.0.0.1",
+		"172.16.5.4",
+		"192.168.1.1",
+		"169.254.169.254",
+		"fe80::1",
+		"fd00::1",
+		"100.64.0.1",
+		"0.0.0.0",
+		"::",
+		"224.0.0.1",
+		"::ffff:127.0.0.1",
+	} {
+		require.False(t, IsPublicIP(net.ParseIP(ip)), ip)
+	}
+}
@@ -0,0 +1,72 @@
+package util
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// The PaginationPolicy type bounds the page size accepted by a listing endpoint.
+// @property {int32} MinPageSize - the smallest page size a client may request.
+// @property {int32} DefaultPageSize - the page size used when the client doesn't send one.
+// @property {int32} MaxPageSize - the largest page size a client may request.
+type PaginationPolicy struct {
+	MinPageSize     int32
+	DefaultPageSize int32
+	MaxPageSize     int32
+}
+
+// The `Validate` function checks that the bounds of the policy are consistent.
+func (policy PaginationPolicy) Validate() error {
+	if policy.MinPageSize < 1 {
+		return fmt.Errorf("min page size must be at least 1, got %d", policy.MinPageSize)
+	}
+	if policy.DefaultPageSize < policy.MinPageSize || policy.DefaultPageSize > policy.MaxPageSize {
+		return fmt.Errorf("default page size %d is not between %d and %d", policy.DefaultPageSize, policy.MinPageSize, policy.MaxPageSize)
+	}
+	return nil
+}
+
+// The `ParsePaginationPolicies` function parses the per endpoint pagination policies of the
+// PAGINATION_POLICIES config, formatted as a comma separated list of `endpoint=min:default:max`, e.g.
+// `accounts=5:10:50,admin=10:50:200`.
+func ParsePaginationPolicies(value string) (map[string]PaginationPolicy, error) {
+	policies := make(map[string]PaginationPolicy)
+	if strings.TrimSpace(value) == "" {
+		return policies, nil
+	}
+
+	for _, item := range strings.Split(value, ",") {
+		endpoint, bounds, ok := strings.Cut(strings.TrimSpace(item), "=")
+		if !ok || endpoint == "" {
+			return nil, fmt.Errorf("invalid pagination policy %q, expected endpoint=min:default:max", item)
+		}
+
+		fields := strings.Split(bounds, ":")
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("invalid pagination policy %q, expected endpoint=min:default:max", item)
+		}
+
+		var sizes [3]int32
+		for i, field := range fields {
+			size, err := strconv.ParseInt(field, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("invalid page size %q in pagination policy %q", field, item)
+			}
+			sizes[i] = int32(size)
+		}
+
+		policy := PaginationPolicy{
+			MinPageSize:     sizes[0],
+			DefaultPageSize: sizes[1],
+			MaxPageSize:     sizes[2],
+		}
+		if err := policy.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid pagination policy for %s: %w", endpoint, err)
+		}
+
+		policies[endpoint] = policy
+	}
+
+	return policies, nil
+}
@@ -0,0 +1,33 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePaginationPolicies(t *testing.T) {
+	policies, err := ParsePaginationPolicies("")
+	require.NoError(t, err)
+	require.Empty(t, policies)
+
+	policies, err = ParsePaginationPolicies("accounts=5:10:50, admin=10:50:200")
+	require.NoError(t, err)
+	require.Equal(t, map[string]PaginationPolicy{
+		"accounts": {MinPageSize: 5, DefaultPageSize: 10, MaxPageSize: 50},
+		"admin":    {MinPageSize: 10, DefaultPageSize: 50, MaxPageSize: 200},
+	}, policies)
+
+	invalid := []string{
+		"accounts",
+		"=5:10:50",
+		"accounts=5:10",
+		"accounts=a:10:50",
+		"accounts=0:10:50",
+		"accounts=5:100:50",
+	}
+	for _, value := range invalid {
+		_, err := ParsePaginationPolicies(value)
+		require.Error(t, err, value)
+	}
+}
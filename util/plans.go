@@ -0,0 +1,32 @@
+package util
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// The `ParseAPIPlans` function parses the monthly quotas of the API plans of the API_PLANS config,
+// formatted as a comma separated list of `plan=calls`, e.g. `basic=10000,pro=1000000`.
+func ParseAPIPlans(value string) (map[string]int64, error) {
+	plans := make(map[string]int64)
+	if strings.TrimSpace(value) == "" {
+		return plans, nil
+	}
+
+	for _, item := range strings.Split(value, ",") {
+		plan, calls, ok := strings.Cut(strings.TrimSpace(item), "=")
+		if !ok || plan == "" {
+			return nil, fmt.Errorf("invalid API plan %q, expected plan=calls", item)
+		}
+
+		quota, err := strconv.ParseInt(calls, 10, 64)
+		if err != nil || quota < 1 {
+			return nil, fmt.Errorf("invalid quota %q in API plan %q", calls, item)
+		}
+
+		plans[plan] = quota
+	}
+
+	return plans, nil
+}
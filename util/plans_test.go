@@ -0,0 +1,29 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAPIPlans(t *testing.T) {
+	plans, err := ParseAPIPlans("")
+	require.NoError(t, err)
+	require.Empty(t, plans)
+
+	plans, err = ParseAPIPlans("basic=10000, pro=1000000")
+	require.NoError(t, err)
+	require.Equal(t, map[string]int64{"basic": 10000, "pro": 1000000}, plans)
+
+	invalid := []string{
+		"basic",
+		"=10000",
+		"basic=many",
+		"basic=0",
+		"basic=-5",
+	}
+	for _, value := range invalid {
+		_, err := ParseAPIPlans(value)
+		require.Error(t, err, value)
+	}
+}
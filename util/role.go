@@ -0,0 +1,11 @@
+package util
+
+const (
+	DepositorRole = "depositor"
+	AdminRole     = "admin"
+	// OrgAdminRole is the role of the users administering their organization, e.g. granting the role to
+	// other users of the organization.
+	OrgAdminRole = "org_admin"
+	// SystemRole is the role of the user owning the system accounts of the bank.
+	SystemRole = "system"
+)
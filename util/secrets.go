@@ -0,0 +1,174 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// Providers the secrets of the config can be read from, with SECRETS_PROVIDER.
+const (
+	SecretsProviderVault = "vault"
+	SecretsProviderAWS   = "aws"
+)
+
+// Keys of the secret read from the provider, which override the config of the same name. DB_USERNAME and
+// DB_PASSWORD replace the credentials of DB_SOURCE, and of every connection to the database opened
+// afterwards so that rotated credentials are picked up.
+const (
+	SecretTokenSymmetricKey     = "TOKEN_SYMMETRIC_KEY"
+	SecretTokenPrivateKey       = "TOKEN_PRIVATE_KEY"
+	SecretTokenVerificationKeys = "TOKEN_VERIFICATION_KEYS"
+	SecretDBSource              = "DB_SOURCE"
+	SecretDBUsername            = "DB_USERNAME"
+	SecretDBPassword            = "DB_PASSWORD"
+)
+
+// The Secret type holds the values of a secret read from a provider.
+// @property {map[string]string} Values - the values of the secret by key.
+// @property {string} Version - the version of the secret, which changes when it is rotated.
+type Secret struct {
+	Values  map[string]string
+	Version string
+}
+
+// The SecretProvider interface reads secrets from a secret manager.
+type SecretProvider interface {
+	GetSecret(ctx context.Context, name string) (Secret, error)
+}
+
+// The `NewSecretProvider` function creates the provider configured by SECRETS_PROVIDER, or returns nil
+// when the secrets are read from the config itself.
+func NewSecretProvider(config Config) (SecretProvider, error) {
+	switch config.SecretsProvider {
+	case "":
+		return nil, nil
+	case SecretsProviderVault:
+		return NewVaultProvider(config.VaultAddress, config.VaultToken, config.VaultMount)
+	case SecretsProviderAWS:
+		return NewAWSSecretsProvider(config.AWSRegion, AWSCredentials{
+			AccessKeyID:     config.AWSAccessKeyID,
+			SecretAccessKey: config.AWSSecretAccessKey,
+			SessionToken:    config.AWSSessionToken,
+		})
+	}
+	return nil, fmt.Errorf("unknown secrets provider %s", config.SecretsProvider)
+}
+
+// The SecretCache type caches a secret read from a provider for a while, so that a rotated secret is
+// picked up without reading it on every use.
+type SecretCache struct {
+	provider SecretProvider
+	name     string
+	ttl      time.Duration
+	now      func() time.Time
+
+	mu        sync.Mutex
+	secret    Secret
+	fetchedAt time.Time
+	fetched   bool
+}
+
+// The `NewSecretCache` function creates a cache of the secret `name`, read again from the provider once
+// it is older than `ttl`.
+func NewSecretCache(provider SecretProvider, name string, ttl time.Duration) *SecretCache {
+	return &SecretCache{
+		provider: provider,
+		name:     name,
+		ttl:      ttl,
+		now:      time.Now,
+	}
+}
+
+// The `Get` method returns the secret, read from the provider when the cached one is too old. The cached
+// secret is still returned while the provider can't be reached, so that an outage of the secret manager
+// doesn't take the database connections down with it.
+func (cache *SecretCache) Get(ctx context.Context) (Secret, error) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	if cache.fetched && cache.now().Sub(cache.fetchedAt) < cache.ttl {
+		return cache.secret, nil
+	}
+
+	secret, err := cache.provider.GetSecret(ctx, cache.name)
+	if err != nil {
+		if cache.fetched {
+			return cache.secret, nil
+		}
+		return Secret{}, fmt.Errorf("cannot read secret %s: %w", cache.name, err)
+	}
+
+	cache.secret = secret
+	cache.fetchedAt = cache.now()
+	cache.fetched = true
+	return secret, nil
+}
+
+// The `DBCredentials` method returns the DB_USERNAME and DB_PASSWORD of the secret, `ok` being false
+// when the secret doesn't hold them.
+func (cache *SecretCache) DBCredentials(ctx context.Context) (username string, password string, ok bool, err error) {
+	secret, err := cache.Get(ctx)
+	if err != nil {
+		return "", "", false, err
+	}
+
+	username, ok = secret.Values[SecretDBUsername]
+	if !ok {
+		return "", "", false, nil
+	}
+	return username, secret.Values[SecretDBPassword], true, nil
+}
+
+// The `loadSecrets` function overrides the config with the secret SECRETS_NAME of the SECRETS_PROVIDER,
+// keeping the cache of the secret in Secrets.
+func (config *Config) loadSecrets(ctx context.Context) error {
+	provider, err := NewSecretProvider(*config)
+	if err != nil || provider == nil {
+		return err
+	}
+
+	cache := NewSecretCache(provider, config.SecretsName, config.SecretsCacheTTL)
+	secret, err := cache.Get(ctx)
+	if err != nil {
+		return err
+	}
+
+	if key, ok := secret.Values[SecretTokenSymmetricKey]; ok {
+		config.TokenSymmetricKey = key
+	}
+	if key, ok := secret.Values[SecretTokenPrivateKey]; ok {
+		config.TokenPrivateKey = key
+	}
+	if keys, ok := secret.Values[SecretTokenVerificationKeys]; ok {
+		config.TokenVerificationKeys = keys
+	}
+	if source, ok := secret.Values[SecretDBSource]; ok {
+		config.DBSource = source
+	}
+	if username, ok := secret.Values[SecretDBUsername]; ok {
+		config.DBSource, err = withCredentials(config.DBSource, username, secret.Values[SecretDBPassword])
+		if err != nil {
+			return err
+		}
+	}
+
+	config.Secrets = cache
+	return nil
+}
+
+// The `withCredentials` function replaces the credentials of a database connection URL.
+func withCredentials(source string, username string, password string) (string, error) {
+	u, err := url.Parse(source)
+	if err != nil {
+		return "", fmt.Errorf("cannot parse DB_SOURCE: %w", err)
+	}
+	if u.Scheme == "" {
+		return "", fmt.Errorf("DB_SOURCE must be a URL for its credentials to be read from the secrets")
+	}
+
+	u.User = url.UserPassword(username, password)
+	return u.String(), nil
+}
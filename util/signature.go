@@ -0,0 +1,21 @@
+package util
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// The function signs a message with the given key using HMAC-SHA256 and returns the hex encoded
+// signature.
+func Sign(key string, message string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(message))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// The function checks in constant time that the signature was produced by `Sign` for the message.
+func VerifySignature(key string, message string, signature string) bool {
+	expected := Sign(key, message)
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
@@ -0,0 +1,20 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignature(t *testing.T) {
+	key := RandomString(32)
+	message := RandomString(16)
+
+	signature := Sign(key, message)
+	require.NotEmpty(t, signature)
+	require.True(t, VerifySignature(key, message, signature))
+
+	require.False(t, VerifySignature(key, RandomString(16), signature))
+	require.False(t, VerifySignature(RandomString(32), message, signature))
+	require.False(t, VerifySignature(key, message, ""))
+}
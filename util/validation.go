@@ -0,0 +1,93 @@
+package util
+
+import (
+	"go-backend/i18n"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// The `ValidationMessage` function describes why a field failed its validation in plain words, e.g.
+// "currency must be one of USD, EUR, CAD", instead of the message of the validator which names the Go
+// struct and the tag.
+func ValidationMessage(fieldErr validator.FieldError) string {
+	return LocalizedValidationMessage(i18n.DefaultLanguage, fieldErr)
+}
+
+// The `LocalizedValidationMessage` function describes why a field failed its validation like
+// `ValidationMessage`, in the language, e.g. "currency doit être l'une des valeurs USD, EUR, CAD" in
+// French.
+func LocalizedValidationMessage(language string, fieldErr validator.FieldError) string {
+	field := fieldErr.Field()
+	param := fieldErr.Param()
+
+	switch tag := fieldErr.Tag(); tag {
+	case "required", "alphanum", "email", "hexadecimal", "uuid":
+		return i18n.Message(language, "validation."+tag, "field", field)
+	case "required_if", "required_unless":
+		other, value, _ := strings.Cut(param, " ")
+		return i18n.Message(language, "validation."+tag, "field", field, "other", strings.ToLower(other), "value", value)
+	case "required_without", "excluded_with":
+		return i18n.Message(language, "validation."+tag, "field", field, "fields", fieldNames(language, param, "or"))
+	case "required_without_all":
+		return i18n.Message(language, "validation."+tag, "field", field, "fields", fieldNames(language, param, "and"))
+	case "min", "max", "len":
+		return i18n.Message(language, "validation."+tag+sizeUnit(fieldErr.Kind()), "field", field, "param", param)
+	case "gt", "gte", "lt", "lte":
+		return i18n.Message(language, "validation."+tag, "field", field, "param", param)
+	case "gtfield":
+		key := "validation.gtfield"
+		if fieldErr.Type() == reflect.TypeOf(time.Time{}) {
+			key = "validation.gtfield.time"
+		}
+		return i18n.Message(language, key, "field", field, "other", strings.ToLower(param))
+	case "oneof":
+		return i18n.Message(language, "validation.oneof", "field", field, "values", strings.Join(strings.Fields(param), ", "))
+	case "currency":
+		return i18n.Message(language, "validation.currency", "field", field, "values", strings.Join(SupportedCurrencies, ", "))
+	case "account_number":
+		return i18n.Message(language, "validation.account_number", "field", field, "example", AccountNumber(42))
+	}
+
+	return i18n.Message(language, "validation.failed", "field", field, "tag", fieldErr.Tag())
+}
+
+// sizeUnit is the suffix of the message keys of the `min`, `max` and `len` validations, whose bounds
+// count characters for strings and items for collections.
+func sizeUnit(kind reflect.Kind) string {
+	switch kind {
+	case reflect.String:
+		return ".string"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return ".items"
+	}
+	return ""
+}
+
+// fieldNames writes the struct fields of the param of a validation naming several of them the way the
+// requests name them, joined by the conjunction in the language: "BeneficiaryID ToAccountNumber"
+// becomes "beneficiary_id or to_account_number" in English.
+func fieldNames(language string, param string, conjunction string) string {
+	fields := strings.Fields(param)
+	for i, field := range fields {
+		fields[i] = snakeCase(field)
+	}
+	return strings.Join(fields, " "+i18n.Message(language, "conjunction."+conjunction)+" ")
+}
+
+// snakeCase writes the name of a struct field, e.g. the param of a validation, the way the requests
+// name it: "BeneficiaryID" becomes "beneficiary_id".
+func snakeCase(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		upper := unicode.IsUpper(r)
+		if upper && i > 0 && !unicode.IsUpper(rune(name[i-1])) {
+			b.WriteByte('_')
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
@@ -0,0 +1,80 @@
+package util
+
+import (
+	"go-backend/i18n"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidationMessage(t *testing.T) {
+	validate := validator.New()
+	validate.RegisterValidation("currency", func(fieldLevel validator.FieldLevel) bool {
+		return IsSupportedCurrency(fieldLevel.Field().String())
+	})
+	validate.RegisterValidation("account_number", func(fieldLevel validator.FieldLevel) bool {
+		return ValidAccountNumber(fieldLevel.Field().String())
+	})
+
+	now := time.Now()
+	request := struct {
+		Owner         string    `validate:"required"`
+		Username      string    `validate:"alphanum,min=6"`
+		Currency      string    `validate:"currency"`
+		Amount        int64     `validate:"gt=0"`
+		PageSize      int32     `validate:"max=100"`
+		Order         string    `validate:"oneof=asc desc"`
+		Email         string    `validate:"email"`
+		From          time.Time `validate:"required"`
+		To            time.Time `validate:"gtfield=From"`
+		AccountID     int64     `validate:"required_without=BeneficiaryID"`
+		BeneficiaryID int64
+		Memo          string `validate:"excluded_with=Username"`
+		ToAccountID   int64  `validate:"required_without_all=BeneficiaryID AccountNumber"`
+		AccountNumber string `validate:"account_number"`
+		Reference     string `validate:"excluded_with=Username Memo"`
+	}{
+		Username:  "ab",
+		Currency:  "JPY",
+		PageSize:  1000,
+		Order:     "up",
+		Email:     "nope",
+		From:      now,
+		To:        now.Add(-time.Hour),
+		Memo:      "memo",
+		Reference: "INV-42",
+	}
+
+	err := validate.Struct(request)
+	require.Error(t, err)
+
+	var messages []string
+	for _, fieldErr := range err.(validator.ValidationErrors) {
+		messages = append(messages, ValidationMessage(fieldErr))
+	}
+	require.Equal(t, []string{
+		"Owner is required",
+		"Username must be at least 6 characters long",
+		"Currency must be one of USD, EUR, CAD",
+		"Amount must be greater than 0",
+		"PageSize must be at most 100",
+		"Order must be one of asc, desc",
+		"Email must be a valid email address",
+		"To must be after from",
+		"AccountID is required without beneficiary_id",
+		"Memo can't be set along with username",
+		"ToAccountID is required without beneficiary_id and account_number",
+		"AccountNumber must be a valid account number, e.g. GO82BANK0000000042",
+		"Reference can't be set along with username or memo",
+	}, messages)
+	var french []string
+	for _, fieldErr := range err.(validator.ValidationErrors) {
+		french = append(french, LocalizedValidationMessage(i18n.French, fieldErr))
+	}
+	require.Equal(t, "Owner est obligatoire", french[0])
+	require.Equal(t, "Username doit comporter au moins 6 caractères", french[1])
+	require.Equal(t, "To doit être postérieur à from", french[7])
+	require.Equal(t, "Reference ne peut pas être renseigné en même temps que username ou memo", french[12])
+}
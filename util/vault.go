@@ -0,0 +1,83 @@
+package util
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const vaultTimeout = 10 * time.Second
+
+// The VaultProvider type reads the secrets of a KV version 2 secrets engine of HashiCorp Vault.
+type VaultProvider struct {
+	address string
+	token   string
+	mount   string
+	client  *http.Client
+}
+
+// The `NewVaultProvider` function creates a provider reading the secrets of the engine mounted at `mount`
+// on the Vault at `address`, authenticated with `token`.
+func NewVaultProvider(address string, token string, mount string) (*VaultProvider, error) {
+	if address == "" || token == "" {
+		return nil, errors.New("VAULT_ADDR and VAULT_TOKEN are required to read the secrets from Vault")
+	}
+
+	return &VaultProvider{
+		address: strings.TrimSuffix(address, "/"),
+		token:   token,
+		mount:   strings.Trim(mount, "/"),
+		client:  &http.Client{Timeout: vaultTimeout},
+	}, nil
+}
+
+type vaultSecretResponse struct {
+	Data struct {
+		Data     map[string]string `json:"data"`
+		Metadata struct {
+			Version int64 `json:"version"`
+		} `json:"metadata"`
+	} `json:"data"`
+	Errors []string `json:"errors"`
+}
+
+// The `GetSecret` method reads the latest version of the secret at the path `name` of the engine.
+func (provider *VaultProvider) GetSecret(ctx context.Context, name string) (Secret, error) {
+	endpoint := fmt.Sprintf("%s/v1/%s/data/%s", provider.address, provider.mount, strings.TrimPrefix(name, "/"))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return Secret{}, err
+	}
+	req.Header.Set("X-Vault-Token", provider.token)
+
+	res, err := provider.client.Do(req)
+	if err != nil {
+		return Secret{}, err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return Secret{}, err
+	}
+
+	var secret vaultSecretResponse
+	if err := json.Unmarshal(body, &secret); err != nil {
+		return Secret{}, fmt.Errorf("vault responded with %d: %w", res.StatusCode, err)
+	}
+	if res.StatusCode != http.StatusOK {
+		return Secret{}, fmt.Errorf("vault responded with %d: %s", res.StatusCode, strings.Join(secret.Errors, ", "))
+	}
+
+	return Secret{
+		Values:  secret.Data.Data,
+		Version: strconv.FormatInt(secret.Data.Metadata.Version, 10),
+	}, nil
+}
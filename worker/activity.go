@@ -0,0 +1,75 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	db "go-backend/db/sqlc"
+	"time"
+)
+
+// ActivityProjection maintains the account_daily_activity and account_counterparty_activity tables from
+// the transfer.sent and transfer.received events, so that the activity of an account is read without
+// aggregating its entries. Each event is one side of a transfer, counted in the totals of its day, in UTC,
+// and of the counterparty of the account. The activity of a deleted account is dropped with it.
+var ActivityProjection = Projection{
+	Name:  "activity",
+	Apply: applyActivityEvent,
+}
+
+func applyActivityEvent(ctx context.Context, q *db.Queries, event db.Event) error {
+	switch event.Type {
+	case db.EventTransferSent, db.EventTransferReceived:
+		var payload db.TransferPartyEvent
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return err
+		}
+
+		return recordActivityTransfer(ctx, q, payload)
+
+	case db.EventAccountDeleted:
+		var payload db.AccountEvent
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return err
+		}
+
+		err := q.DeleteAccountDailyActivity(ctx, payload.AccountID)
+		if err != nil {
+			return err
+		}
+
+		return q.DeleteAccountCounterpartyActivity(ctx, payload.AccountID)
+	}
+
+	// events the projection doesn't care about
+	return nil
+}
+
+// The `recordActivityTransfer` function adds a side of a transfer to the activity of its account, the
+// amount being a debit when negative and a credit otherwise.
+func recordActivityTransfer(ctx context.Context, q *db.Queries, transfer db.TransferPartyEvent) error {
+	var credit, debit int64
+	if transfer.Amount < 0 {
+		debit = -transfer.Amount
+	} else {
+		credit = transfer.Amount
+	}
+
+	err := q.AddAccountDailyActivity(ctx, db.AddAccountDailyActivityParams{
+		AccountID: transfer.AccountID,
+		Day:       transfer.CreatedAt.UTC().Truncate(24 * time.Hour),
+		Credits:   credit,
+		Debits:    debit,
+	})
+	if err != nil {
+		return err
+	}
+
+	return q.AddAccountCounterpartyActivity(ctx, db.AddAccountCounterpartyActivityParams{
+		AccountID:             transfer.AccountID,
+		CounterpartyAccountID: transfer.CounterpartyAccountID,
+		CounterpartyOwner:     transfer.CounterpartyOwner,
+		Sent:                  debit,
+		Received:              credit,
+		LastTransferAt:        transfer.CreatedAt,
+	})
+}
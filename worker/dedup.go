@@ -0,0 +1,74 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	db "go-backend/db/sqlc"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+// taskRetention is how long redis keeps a processed task, during which enqueuing a task with the same
+// ID is rejected by asynq. Past it, the processed_tasks table still prevents the task from running again.
+const taskRetention = 24 * time.Hour
+
+// The `TaskID` function derives the ID of a task from its type and the business keys of its side
+// effects, e.g. `task:run_export:<job id>`, so that enqueuing the same work twice yields the same ID.
+func TaskID(taskType string, keys ...string) string {
+	return strings.Join(append([]string{taskType}, keys...), ":")
+}
+
+// The `enqueue` function enqueues the task under the given ID. A task that is already enqueued, running
+// or retained under the same ID is not enqueued again, which is not an error.
+func (distributor *RedisTaskDistributor) enqueue(ctx context.Context, task *asynq.Task, id string) error {
+	info, err := distributor.client.EnqueueContext(ctx, task, asynq.TaskID(id), asynq.Retention(taskRetention))
+	if err != nil {
+		if errors.Is(err, asynq.ErrTaskIDConflict) || errors.Is(err, asynq.ErrDuplicateTask) {
+			log.Printf("skipped duplicate task: type=%s id=%s", task.Type(), id)
+			return nil
+		}
+		return fmt.Errorf("failed to enqueue task: %w", err)
+	}
+
+	log.Printf("enqueued task: type=%s id=%s payload=%s queue=%s max_retry=%d", task.Type(), id, task.Payload(), info.Queue, info.MaxRetry)
+	return nil
+}
+
+// The `deduplicate` function wraps a task handler so that a task whose ID was already processed is
+// skipped, e.g. when it was enqueued again after redis forgot it. The ID is recorded once the handler
+// succeeds, so the handlers must still tolerate running twice if recording it fails.
+func (processor *RedisTaskProcessor) deduplicate(handler asynq.HandlerFunc) asynq.HandlerFunc {
+	return func(ctx context.Context, task *asynq.Task) error {
+		id, ok := asynq.GetTaskID(ctx)
+		if !ok {
+			return handler(ctx, task)
+		}
+
+		processed, err := processor.store.IsTaskProcessed(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to check whether task was processed: %w", err)
+		}
+		if processed {
+			log.Printf("skipped processed task: type=%s id=%s", task.Type(), id)
+			return nil
+		}
+
+		err = handler(ctx, task)
+		if err != nil {
+			return err
+		}
+
+		err = processor.store.CreateProcessedTask(ctx, db.CreateProcessedTaskParams{
+			ID:   id,
+			Type: task.Type(),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to record processed task: %w", err)
+		}
+		return nil
+	}
+}
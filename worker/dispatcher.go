@@ -0,0 +1,267 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	db "go-backend/db/sqlc"
+	"go-backend/mail"
+	"go-backend/signing"
+	"go-backend/util"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+const (
+	dispatchBatchSize = 50
+	// how long a claimed delivery is left to its dispatcher before another one sends it again
+	dispatchLease = time.Minute
+	// a delivery still failing after this many attempts is given up
+	dispatchMaxAttempts = 8
+)
+
+// The NotificationSender interface sends a notification through a channel other than the app.
+type NotificationSender interface {
+	Send(ctx context.Context, delivery db.NotificationDelivery) error
+}
+
+// The NotificationDispatcher type sends the notifications queued by the NotificationProjection for the
+// email and webhook channels. The deliveries are claimed from the database, so several dispatchers can
+// run without sending a notification twice, and a failed delivery is retried with a growing delay.
+type NotificationDispatcher struct {
+	store    db.Store
+	interval time.Duration
+	senders  map[string]NotificationSender
+}
+
+// The function creates a dispatcher polling for deliveries every `interval`, sending them with the
+// sender of their channel.
+func NewNotificationDispatcher(store db.Store, interval time.Duration, senders map[string]NotificationSender) *NotificationDispatcher {
+	return &NotificationDispatcher{
+		store:    store,
+		interval: interval,
+		senders:  senders,
+	}
+}
+
+// The `Run` function sends the due deliveries on every tick until the context is cancelled.
+func (dispatcher *NotificationDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(dispatcher.interval)
+	defer ticker.Stop()
+
+	for {
+		err := dispatcher.Dispatch(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("notification dispatch failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// The `Dispatch` function sends batches of due deliveries until none is left.
+func (dispatcher *NotificationDispatcher) Dispatch(ctx context.Context) error {
+	for {
+		deliveries, err := dispatcher.store.ClaimNotificationDeliveries(ctx, db.ClaimNotificationDeliveriesParams{
+			LeaseUntil: time.Now().Add(dispatchLease),
+			RowLimit:   dispatchBatchSize,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, delivery := range deliveries {
+			err = dispatcher.deliver(ctx, delivery)
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(deliveries) < dispatchBatchSize {
+			return nil
+		}
+	}
+}
+
+// The `deliver` function sends a delivery and records its outcome. Only the errors recording it are
+// returned, a failed send being retried later.
+func (dispatcher *NotificationDispatcher) deliver(ctx context.Context, delivery db.NotificationDelivery) error {
+	sender, ok := dispatcher.senders[delivery.Channel]
+	if !ok {
+		return dispatcher.store.RecordNotificationDeliveryFailure(ctx, db.RecordNotificationDeliveryFailureParams{
+			Status:        db.NotificationDeliveryFailed,
+			LastError:     fmt.Sprintf("no sender for channel %s", delivery.Channel),
+			NextAttemptAt: delivery.NextAttemptAt,
+			ID:            delivery.ID,
+		})
+	}
+
+	err := sender.Send(ctx, delivery)
+	if err == nil {
+		return dispatcher.store.MarkNotificationDelivered(ctx, delivery.ID)
+	}
+	if ctx.Err() != nil {
+		// the lease expires and the delivery is sent again by the next dispatcher
+		return ctx.Err()
+	}
+
+	status := db.NotificationDeliveryPending
+	if delivery.Attempts+1 >= dispatchMaxAttempts {
+		status = db.NotificationDeliveryFailed
+	}
+
+	return dispatcher.store.RecordNotificationDeliveryFailure(ctx, db.RecordNotificationDeliveryFailureParams{
+		Status:        status,
+		LastError:     err.Error(),
+		NextAttemptAt: time.Now().Add(dispatchBackoff(delivery.Attempts)),
+		ID:            delivery.ID,
+	})
+}
+
+// The `dispatchBackoff` function returns how long to wait before attempting a delivery again, doubling
+// from 30 seconds after each failed attempt.
+func dispatchBackoff(attempts int32) time.Duration {
+	return 30 * time.Second << attempts
+}
+
+// The notificationMessage type is the body of a notification sent outside the app.
+type notificationMessage struct {
+	ID        int64           `json:"id"`
+	EventID   int64           `json:"event_id"`
+	Type      string          `json:"type"`
+	Payload   json.RawMessage `json:"payload"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+func newNotificationMessage(delivery db.NotificationDelivery) notificationMessage {
+	return notificationMessage{
+		ID:        delivery.ID,
+		EventID:   delivery.EventID,
+		Type:      delivery.Type,
+		Payload:   delivery.Payload,
+		CreatedAt: delivery.CreatedAt,
+	}
+}
+
+// Headers of the notifications posted to the webhooks, signed like the signed requests of the API are:
+// the signature is the hex encoded HMAC-SHA256 of signing.Message with the WEBHOOK_SIGNING_KEY config, so
+// that the receiver can tell the notifications come from the bank.
+const (
+	webhookSignatureHeaderKey          = "X-Signature"
+	webhookSignatureTimestampHeaderKey = "X-Signature-Timestamp"
+)
+
+// The WebhookSender type posts the notifications as json to the webhook URL of the user, signed with
+// the key of the sender. Any status other than 2xx is a failed delivery.
+type WebhookSender struct {
+	client *http.Client
+	key    string
+}
+
+// The function creates a webhook sender signing the notifications with `key` and giving up on a request
+// after `timeout`. The webhooks are only posted to public addresses: the address a host resolves to is
+// checked when it is dialed, redirects included, so that a user can't have the worker reach the hosts of
+// the bank's own network.
+func NewWebhookSender(timeout time.Duration, key string) *WebhookSender {
+	return newWebhookSender(timeout, key, util.IsPublicIP)
+}
+
+// newWebhookSender creates a webhook sender dialing the addresses `allowed` accepts only.
+func newWebhookSender(timeout time.Duration, key string, allowed func(ip net.IP) bool) *WebhookSender {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network string, address string, conn syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			if ip == nil || !allowed(ip) {
+				return fmt.Errorf("webhook address %s isn't public", host)
+			}
+			return nil
+		},
+	}
+
+	return &WebhookSender{
+		client: &http.Client{
+			Timeout: timeout,
+			// the proxies of the environment aren't used, since they would dial the webhook instead
+			Transport: &http.Transport{
+				DialContext:         dialer.DialContext,
+				TLSHandshakeTimeout: timeout,
+			},
+		},
+		key: key,
+	}
+}
+
+func (sender *WebhookSender) Send(ctx context.Context, delivery db.NotificationDelivery) error {
+	body, err := json.Marshal(newNotificationMessage(delivery))
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Destination, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	// lets the receiver ignore a notification it was sent already, the delivery being retried until it
+	// is acknowledged
+	request.Header.Set("Idempotency-Key", fmt.Sprintf("notification-%d", delivery.ID))
+
+	timestamp := time.Now().Unix()
+	message := signing.Message(timestamp, request.Method, request.URL.RequestURI(), body)
+	request.Header.Set(webhookSignatureTimestampHeaderKey, strconv.FormatInt(timestamp, 10))
+	request.Header.Set(webhookSignatureHeaderKey, util.Sign(sender.key, message))
+
+	response, err := sender.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with status %d", response.StatusCode)
+	}
+
+	return nil
+}
+
+// The EmailSender type emails the notifications to the address of the user, rendered with the
+// notification template.
+type EmailSender struct {
+	mailer mail.Sender
+}
+
+// The function creates an email sender sending the emails with `mailer`.
+func NewEmailSender(mailer mail.Sender) *EmailSender {
+	return &EmailSender{
+		mailer: mailer,
+	}
+}
+
+func (sender *EmailSender) Send(ctx context.Context, delivery db.NotificationDelivery) error {
+	data, err := mail.NewNotificationData(delivery.Type, delivery.Payload, delivery.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	message, err := mail.Render(mail.TemplateNotification, data)
+	if err != nil {
+		return err
+	}
+
+	message.To = []string{delivery.Destination}
+	return sender.mailer.Send(ctx, message)
+}
@@ -0,0 +1,64 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	db "go-backend/db/sqlc"
+	"go-backend/signing"
+	"go-backend/util"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookSenderSigns(t *testing.T) {
+	key := util.RandomString(32)
+	delivery := db.NotificationDelivery{
+		ID:        7,
+		EventID:   3,
+		Type:      db.EventTransferReceived,
+		Payload:   json.RawMessage(`{"amount":10}`),
+		CreatedAt: time.Now(),
+	}
+
+	received := make(chan *http.Request, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		timestamp, err := strconv.ParseInt(r.Header.Get(webhookSignatureTimestampHeaderKey), 10, 64)
+		require.NoError(t, err)
+		require.True(t, signing.Fresh(timestamp, time.Now()))
+		message := signing.Message(timestamp, r.Method, r.URL.RequestURI(), body)
+		require.True(t, util.VerifySignature(key, message, r.Header.Get(webhookSignatureHeaderKey)))
+
+		received <- r
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	// the test server listens on the loopback
+	sender := newWebhookSender(time.Second, key, func(ip net.IP) bool { return true })
+	delivery.Destination = server.URL + "/hook?user=1"
+	require.NoError(t, sender.Send(context.Background(), delivery))
+	require.Equal(t, "notification-7", (<-received).Header.Get("Idempotency-Key"))
+}
+
+func TestWebhookSenderRefusesPrivateAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("the webhook was posted to the loopback")
+	}))
+	defer server.Close()
+
+	sender := NewWebhookSender(time.Second, util.RandomString(32))
+	for _, destination := range []string{server.URL, "http://localhost:1/hook"} {
+		err := sender.Send(context.Background(), db.NotificationDelivery{ID: 1, Destination: destination})
+		require.ErrorContains(t, err, "isn't public")
+	}
+}
@@ -0,0 +1,45 @@
+package worker
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+)
+
+// The TaskDistributor interface enqueues background tasks so that they can be picked up by the task
+// processor, keeping slow work off the request path.
+type TaskDistributor interface {
+	DistributeTaskRunExport(ctx context.Context, payload *PayloadRunExport, opts ...asynq.Option) error
+	DistributeTaskQueuedTransfer(ctx context.Context, payload *PayloadQueuedTransfer, opts ...asynq.Option) error
+	DistributeTaskExternalTransfer(ctx context.Context, payload *PayloadExternalTransfer, opts ...asynq.Option) error
+	DistributeTaskDeleteUserData(ctx context.Context, payload *PayloadDeleteUserData, opts ...asynq.Option) error
+	QueuedTransfer(ctx context.Context, id uuid.UUID) (QueuedTransferTask, error)
+	Close() error
+}
+
+type RedisTaskDistributor struct {
+	client    *asynq.Client
+	inspector *asynq.Inspector
+}
+
+// The function creates a new task distributor backed by an asynq client connected to redis, and an
+// inspector looking up the tasks it enqueued.
+func NewRedisTaskDistributor(redisOpt asynq.RedisClientOpt) TaskDistributor {
+	client := asynq.NewClient(redisOpt)
+	inspector := asynq.NewInspector(redisOpt)
+	return &RedisTaskDistributor{
+		client:    client,
+		inspector: inspector,
+	}
+}
+
+// The `Close` function releases the connections to redis held by the underlying asynq client and
+// inspector.
+func (distributor *RedisTaskDistributor) Close() error {
+	err := distributor.client.Close()
+	if inspectorErr := distributor.inspector.Close(); err == nil {
+		err = inspectorErr
+	}
+	return err
+}
@@ -0,0 +1,480 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"go-backend/clock"
+	db "go-backend/db/sqlc"
+	"log"
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+const endOfDayBatchSize = 100
+
+// externalTransferStaleAfter is the time after which an external transfer still initiated or processing
+// is taken for one whose next step was never enqueued, e.g. as redis was down, and is enqueued again. It
+// is well over the submission and settlement delays of the rails.
+const externalTransferStaleAfter = 10 * time.Minute
+
+// The EndOfDay type runs the end-of-day batches of the bank for every business day that is over.
+// Business days follow UTC. The batches checkpoint their progress, so a run interrupted by a crash or a
+// shutdown resumes where it left off on the next tick, and a completed run is not applied again.
+type EndOfDay struct {
+	store       db.Store
+	distributor TaskDistributor
+	interval    time.Duration
+	clock       clock.Clock
+}
+
+// The function creates an end-of-day runner checking for a business day to close every `interval`.
+func NewEndOfDay(store db.Store, interval time.Duration) *EndOfDay {
+	return &EndOfDay{
+		store:    store,
+		interval: interval,
+		clock:    clock.Real,
+	}
+}
+
+// The `SetClock` function sets the clock the business days and the expiries follow, for tests to
+// fast-forward time.
+func (endOfDay *EndOfDay) SetClock(clock clock.Clock) {
+	endOfDay.clock = clock
+}
+
+// The `SetTaskDistributor` function sets the distributor the stuck external transfers are enqueued again
+// with. They are left as they are without one.
+func (endOfDay *EndOfDay) SetTaskDistributor(distributor TaskDistributor) {
+	endOfDay.distributor = distributor
+}
+
+// The `Run` function closes the business days that are over on every tick until the context is
+// cancelled. The payment requests, the payment links, the pending transfers and the holds past their expiry are expired
+// on every tick too, rather than once a day, after the cheques whose clearing period is over are cleared.
+// The standing orders due are run on every tick as well, and the external transfers stuck in a status
+// enqueued again.
+func (endOfDay *EndOfDay) Run(ctx context.Context) {
+	ticker := endOfDay.clock.NewTicker(endOfDay.interval)
+	defer ticker.Stop()
+
+	for {
+		err := endOfDay.catchUp(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("end of day failed: %v", err)
+		}
+
+		err = endOfDay.expirePaymentRequests(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("cannot expire payment requests: %v", err)
+		}
+
+		err = endOfDay.expirePaymentLinks(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("cannot expire payment links: %v", err)
+		}
+
+		err = endOfDay.expirePendingTransfers(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("cannot expire pending transfers: %v", err)
+		}
+
+		err = endOfDay.clearChequeDeposits(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("cannot clear cheque deposits: %v", err)
+		}
+
+		err = endOfDay.expireHolds(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("cannot expire holds: %v", err)
+		}
+
+		err = endOfDay.runStandingOrders(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("cannot run standing orders: %v", err)
+		}
+
+		err = endOfDay.redispatchExternalTransfers(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("cannot redispatch external transfers: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C():
+		}
+	}
+}
+
+// The `catchUp` function closes, in order, every business day over since the last one whose ledger was
+// reconciled, so that the days missed while the runner was down are closed too. Only the previous
+// business day is closed when no day was ever closed. A day that fails to close stops the catch-up,
+// which resumes from that day on the next tick.
+func (endOfDay *EndOfDay) catchUp(ctx context.Context) error {
+	last := previousBusinessDate(endOfDay.clock.Now())
+	first := last
+
+	run, err := endOfDay.store.GetLastCompletedBatchRun(ctx, db.BatchLedgerReconciliation)
+	if err == nil {
+		first = run.BusinessDate.AddDate(0, 0, 1)
+	} else if !errors.Is(err, db.ErrRecordNotFound) {
+		return err
+	}
+
+	for businessDate := first; !businessDate.After(last); businessDate = businessDate.AddDate(0, 0, 1) {
+		err = endOfDay.Close(ctx, businessDate)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// The `Close` function runs every end-of-day batch for the business date. The interest of the business
+// date is booked once it is over, so it shows in the balance snapshot of the next day, and the monthly
+// fee on the last day of a month. The ledger is reconciled last, once the postings of the day are booked.
+func (endOfDay *EndOfDay) Close(ctx context.Context, businessDate time.Time) error {
+	err := endOfDay.snapshotBalances(ctx, businessDate)
+	if err != nil {
+		return err
+	}
+
+	err = endOfDay.capitalizeInterest(ctx, businessDate)
+	if err != nil {
+		return err
+	}
+
+	err = endOfDay.chargeMonthlyFee(ctx, businessDate)
+	if err != nil {
+		return err
+	}
+
+	return endOfDay.reconcileLedger(ctx, businessDate)
+}
+
+// The `snapshotBalances` function records the closing balance of every account for the business date,
+// which the balance history of the accounts is read from.
+func (endOfDay *EndOfDay) snapshotBalances(ctx context.Context, businessDate time.Time) error {
+	for {
+		result, err := endOfDay.store.SnapshotBalancesTx(ctx, db.SnapshotBalancesTxParams{
+			BusinessDate: businessDate,
+			Limit:        endOfDayBatchSize,
+		})
+		if err != nil {
+			return err
+		}
+
+		if result.Done {
+			return nil
+		}
+	}
+}
+
+// The `capitalizeInterest` function credits the daily interest of the business date to every account,
+// on its balance snapshot of that day at the rate in effect at its end. Nothing is credited while no
+// rate was published.
+func (endOfDay *EndOfDay) capitalizeInterest(ctx context.Context, businessDate time.Time) error {
+	rate, err := endOfDay.store.GetActiveBankParameter(ctx, db.GetActiveBankParameterParams{
+		Name: db.ParameterInterestRate,
+		At:   businessDate.AddDate(0, 0, 1),
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	for {
+		result, err := endOfDay.store.CapitalizeInterestTx(ctx, db.CapitalizeInterestTxParams{
+			BusinessDate: businessDate,
+			RateBps:      rate.Value,
+			Limit:        endOfDayBatchSize,
+		})
+		if err != nil {
+			return err
+		}
+
+		if result.Done {
+			return nil
+		}
+	}
+}
+
+// The `chargeMonthlyFee` function charges the monthly fee in effect at the end of the business date to
+// every account, when it is the last day of a month. Nothing is charged while no fee was published.
+func (endOfDay *EndOfDay) chargeMonthlyFee(ctx context.Context, businessDate time.Time) error {
+	nextDate := businessDate.AddDate(0, 0, 1)
+	if nextDate.Day() != 1 {
+		return nil
+	}
+
+	fee, err := endOfDay.store.GetActiveBankParameter(ctx, db.GetActiveBankParameterParams{
+		Name: db.ParameterMonthlyFee,
+		At:   nextDate,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if fee.Value == 0 {
+		return nil
+	}
+
+	for {
+		result, err := endOfDay.store.ChargeMonthlyFeeTx(ctx, db.ChargeMonthlyFeeTxParams{
+			BusinessDate: businessDate,
+			Fee:          fee.Value,
+			Limit:        endOfDayBatchSize,
+		})
+		if err != nil {
+			return err
+		}
+
+		if result.Done {
+			return nil
+		}
+	}
+}
+
+// The `reconcileLedger` function reconciles the ledger once per business date, its run being completed
+// once the anomalies found are recorded. A run interrupted before is started again on the next tick.
+func (endOfDay *EndOfDay) reconcileLedger(ctx context.Context, businessDate time.Time) error {
+	run, err := endOfDay.store.LockBatchRun(ctx, db.LockBatchRunParams{
+		Name:         db.BatchLedgerReconciliation,
+		BusinessDate: businessDate,
+	})
+	if err != nil {
+		return err
+	}
+	if run.CompletedAt.Valid {
+		return nil
+	}
+
+	result, err := endOfDay.store.ReconcileLedgerTx(ctx)
+	if err != nil {
+		return err
+	}
+	if len(result.Anomalies) > 0 {
+		log.Printf("ledger reconciliation of %s found %d anomalies", businessDate.Format("2006-01-02"), len(result.Anomalies))
+	}
+
+	return endOfDay.store.CompleteBatchRun(ctx, db.CompleteBatchRunParams{
+		Name:         db.BatchLedgerReconciliation,
+		BusinessDate: businessDate,
+	})
+}
+
+// The `expirePaymentRequests` function expires the pending payment requests past their expiry, which
+// can no longer be accepted.
+func (endOfDay *EndOfDay) expirePaymentRequests(ctx context.Context) error {
+	count, err := endOfDay.store.ExpirePaymentRequests(ctx, endOfDay.clock.Now())
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		log.Printf("expired %d payment requests", count)
+	}
+	return nil
+}
+
+// The `expirePaymentLinks` function expires the active payment links past their expiry, which can no
+// longer be paid.
+func (endOfDay *EndOfDay) expirePaymentLinks(ctx context.Context) error {
+	count, err := endOfDay.store.ExpirePaymentLinks(ctx, endOfDay.clock.Now())
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		log.Printf("expired %d payment links", count)
+	}
+	return nil
+}
+
+// The `expirePendingTransfers` function expires the transfers awaiting approval past their expiry, which
+// are then never made.
+func (endOfDay *EndOfDay) expirePendingTransfers(ctx context.Context) error {
+	count, err := endOfDay.store.ExpirePendingTransfers(ctx, endOfDay.clock.Now())
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		log.Printf("expired %d pending transfers", count)
+	}
+	return nil
+}
+
+// The `clearChequeDeposits` function clears the pending cheques whose clearing period is over, which
+// releases the holds on their amounts. A cheque bounced meanwhile is skipped.
+func (endOfDay *EndOfDay) clearChequeDeposits(ctx context.Context) error {
+	count := 0
+	for {
+		deposits, err := endOfDay.store.ListDueChequeDeposits(ctx, db.ListDueChequeDepositsParams{
+			ClearsAt: endOfDay.clock.Now(),
+			Limit:    endOfDayBatchSize,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, deposit := range deposits {
+			_, err = endOfDay.store.ClearChequeDepositTx(ctx, deposit.ID)
+			if err != nil {
+				if errors.Is(err, db.ErrChequeDepositClosed) {
+					continue
+				}
+				return err
+			}
+			count++
+		}
+
+		if len(deposits) < endOfDayBatchSize {
+			break
+		}
+	}
+
+	if count > 0 {
+		log.Printf("cleared %d cheque deposits", count)
+	}
+	return nil
+}
+
+// The `expireHolds` function expires the authorized holds past their expiry, which then no longer
+// reserve their amount on the held balance of their account and can't be captured.
+func (endOfDay *EndOfDay) expireHolds(ctx context.Context) error {
+	holds, err := endOfDay.store.ExpireHoldsTx(ctx, endOfDay.clock.Now())
+	if err != nil {
+		return err
+	}
+	if len(holds) > 0 {
+		log.Printf("expired %d holds", len(holds))
+	}
+	return nil
+}
+
+// standingOrderFailures are the errors a run of a standing order fails with for a reason of its own, e.g.
+// a lack of funds, rather than of the database. Such a run is recorded as failed and skipped, while the
+// other runs are tried again on the next tick.
+var standingOrderFailures = []error{
+	db.ErrInsufficientAvailableBalance,
+	db.ErrAccountVersionMismatch,
+	db.ErrAccountClosed,
+	db.ErrStandingOrderNotOwner,
+}
+
+// The `runStandingOrders` function makes the transfers of the standing orders due. The runs missed while
+// the runner was down are made once, rather than once per missed period. A run whose transfer fails for
+// a reason of its own is recorded as failed on the standing order and skipped until its next run.
+func (endOfDay *EndOfDay) runStandingOrders(ctx context.Context) error {
+	count := 0
+	failed := 0
+	for {
+		now := endOfDay.clock.Now()
+		orders, err := endOfDay.store.ListDueStandingOrders(ctx, db.ListDueStandingOrdersParams{
+			NextRunAt: now,
+			Limit:     endOfDayBatchSize,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, order := range orders {
+			_, runErr := endOfDay.store.RunStandingOrderTx(ctx, db.RunStandingOrderTxParams{ID: order.ID, Now: now})
+			if runErr == nil {
+				count++
+				continue
+			}
+			if errors.Is(runErr, db.ErrStandingOrderCancelled) || errors.Is(runErr, db.ErrStandingOrderNotDue) {
+				continue
+			}
+			if !isStandingOrderFailure(runErr) {
+				return runErr
+			}
+
+			runCount, nextRunAt := db.NextStandingOrderRun(order, now)
+			_, err = endOfDay.store.RecordStandingOrderRun(ctx, db.RecordStandingOrderRunParams{
+				RunCount:    runCount,
+				NextRunAt:   nextRunAt,
+				LastFailure: runErr.Error(),
+				ID:          order.ID,
+				DueAt:       order.NextRunAt,
+			})
+			// it was cancelled or run meanwhile
+			if err != nil && !errors.Is(err, db.ErrRecordNotFound) {
+				return err
+			}
+			failed++
+		}
+
+		if len(orders) < endOfDayBatchSize {
+			break
+		}
+	}
+
+	if count > 0 || failed > 0 {
+		log.Printf("ran %d standing orders, %d failed", count+failed, failed)
+	}
+	return nil
+}
+
+// The `redispatchExternalTransfers` function enqueues again the next step of the external transfers that
+// didn't move out of initiated or processing for externalTransferStaleAfter. A step still enqueued or
+// already processed isn't run twice, as it is identified by the transfer and its status.
+func (endOfDay *EndOfDay) redispatchExternalTransfers(ctx context.Context) error {
+	if endOfDay.distributor == nil {
+		return nil
+	}
+
+	count := 0
+	staleBefore := endOfDay.clock.Now().Add(-externalTransferStaleAfter)
+	after := int64(0)
+	for {
+		transfers, err := endOfDay.store.ListStaleExternalTransfers(ctx, db.ListStaleExternalTransfersParams{
+			UpdatedAt: staleBefore,
+			ID:        after,
+			Limit:     endOfDayBatchSize,
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, external := range transfers {
+			err = endOfDay.distributor.DistributeTaskExternalTransfer(ctx, &PayloadExternalTransfer{
+				ID:     external.ID,
+				Status: external.Status,
+			}, asynq.Queue(QueueCritical))
+			if err != nil {
+				return err
+			}
+			after = external.ID
+			count++
+		}
+
+		if len(transfers) < endOfDayBatchSize {
+			break
+		}
+	}
+
+	if count > 0 {
+		log.Printf("redispatched %d external transfers", count)
+	}
+	return nil
+}
+
+func isStandingOrderFailure(err error) bool {
+	for _, failure := range standingOrderFailures {
+		if errors.Is(err, failure) {
+			return true
+		}
+	}
+	return false
+}
+
+// previousBusinessDate returns the last UTC day that is over at `now`.
+func previousBusinessDate(now time.Time) time.Time {
+	now = now.UTC()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, -1)
+}
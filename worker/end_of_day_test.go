@@ -0,0 +1,237 @@
+package worker
+
+import (
+	"context"
+	"go-backend/clock"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/hibiken/asynq"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEndOfDayRunFollowsClock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	start := time.Date(2026, 10, 16, 23, 50, 0, 0, time.UTC)
+	fake := clock.NewFake(start)
+	store := mockdb.NewMockStore(ctrl)
+
+	businessDates := make(chan time.Time, 10)
+	store.EXPECT().
+		SnapshotBalancesTx(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		DoAndReturn(func(_ context.Context, arg db.SnapshotBalancesTxParams) (db.BatchTxResult, error) {
+			businessDates <- arg.BusinessDate
+			return db.BatchTxResult{Done: true}, nil
+		})
+	store.EXPECT().GetLastCompletedBatchRun(gomock.Any(), gomock.Any()).AnyTimes().Return(db.BatchRun{}, db.ErrRecordNotFound)
+	store.EXPECT().GetActiveBankParameter(gomock.Any(), gomock.Any()).AnyTimes().Return(db.BankParameter{}, db.ErrRecordNotFound)
+	store.EXPECT().
+		LockBatchRun(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		Return(db.BatchRun{CompletedAt: pgtype.Timestamptz{Time: start, Valid: true}}, nil)
+
+	expiredAt := make(chan time.Time, 10)
+	store.EXPECT().
+		ExpirePaymentRequests(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		DoAndReturn(func(_ context.Context, now time.Time) (int64, error) {
+			expiredAt <- now
+			return 0, nil
+		})
+	store.EXPECT().ExpirePaymentLinks(gomock.Any(), gomock.Any()).AnyTimes().Return(int64(0), nil)
+	store.EXPECT().ExpirePendingTransfers(gomock.Any(), gomock.Any()).AnyTimes().Return(int64(0), nil)
+	store.EXPECT().ListDueChequeDeposits(gomock.Any(), gomock.Any()).AnyTimes().Return(nil, nil)
+	store.EXPECT().ExpireHoldsTx(gomock.Any(), gomock.Any()).AnyTimes().Return(nil, nil)
+	store.EXPECT().ListDueStandingOrders(gomock.Any(), gomock.Any()).AnyTimes().Return(nil, nil)
+
+	endOfDay := NewEndOfDay(store, 10*time.Minute)
+	endOfDay.SetClock(fake)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		endOfDay.Run(ctx)
+		close(done)
+	}()
+
+	require.Equal(t, time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC), <-businessDates)
+	require.Equal(t, start, <-expiredAt)
+
+	// the next tick is past midnight, closing the day that just ended
+	fake.Advance(10 * time.Minute)
+	require.Equal(t, time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC), <-businessDates)
+	require.Equal(t, start.Add(10*time.Minute), <-expiredAt)
+
+	cancel()
+	<-done
+}
+
+func TestEndOfDayCatchesUpMissedDays(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// the runner was down from the last day of September until the second of October
+	now := time.Date(2026, 10, 2, 8, 0, 0, 0, time.UTC)
+	store := mockdb.NewMockStore(ctrl)
+
+	store.EXPECT().
+		GetLastCompletedBatchRun(gomock.Any(), gomock.Eq(db.BatchLedgerReconciliation)).
+		Times(1).
+		Return(db.BatchRun{BusinessDate: time.Date(2026, 9, 28, 0, 0, 0, 0, time.UTC)}, nil)
+
+	var snapshotted, capitalized, charged, reconciled []time.Time
+	store.EXPECT().
+		SnapshotBalancesTx(gomock.Any(), gomock.Any()).
+		Times(3).
+		DoAndReturn(func(_ context.Context, arg db.SnapshotBalancesTxParams) (db.BatchTxResult, error) {
+			snapshotted = append(snapshotted, arg.BusinessDate)
+			return db.BatchTxResult{Done: true}, nil
+		})
+	store.EXPECT().
+		GetActiveBankParameter(gomock.Any(), gomock.Any()).
+		Times(4).
+		DoAndReturn(func(_ context.Context, arg db.GetActiveBankParameterParams) (db.BankParameter, error) {
+			switch arg.Name {
+			case db.ParameterInterestRate:
+				return db.BankParameter{Name: arg.Name, Value: 100}, nil
+			case db.ParameterMonthlyFee:
+				return db.BankParameter{Name: arg.Name, Value: 500}, nil
+			}
+			return db.BankParameter{}, db.ErrRecordNotFound
+		})
+	store.EXPECT().
+		CapitalizeInterestTx(gomock.Any(), gomock.Any()).
+		Times(3).
+		DoAndReturn(func(_ context.Context, arg db.CapitalizeInterestTxParams) (db.BatchTxResult, error) {
+			require.Equal(t, int64(100), arg.RateBps)
+			capitalized = append(capitalized, arg.BusinessDate)
+			return db.BatchTxResult{Done: true}, nil
+		})
+	store.EXPECT().
+		ChargeMonthlyFeeTx(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, arg db.ChargeMonthlyFeeTxParams) (db.BatchTxResult, error) {
+			require.Equal(t, int64(500), arg.Fee)
+			charged = append(charged, arg.BusinessDate)
+			return db.BatchTxResult{Done: true}, nil
+		})
+	store.EXPECT().
+		LockBatchRun(gomock.Any(), gomock.Any()).
+		Times(3).
+		DoAndReturn(func(_ context.Context, arg db.LockBatchRunParams) (db.BatchRun, error) {
+			return db.BatchRun{Name: arg.Name, BusinessDate: arg.BusinessDate}, nil
+		})
+	store.EXPECT().ReconcileLedgerTx(gomock.Any()).Times(3).Return(db.ReconcileLedgerTxResult{}, nil)
+	store.EXPECT().
+		CompleteBatchRun(gomock.Any(), gomock.Any()).
+		Times(3).
+		DoAndReturn(func(_ context.Context, arg db.CompleteBatchRunParams) error {
+			reconciled = append(reconciled, arg.BusinessDate)
+			return nil
+		})
+
+	endOfDay := NewEndOfDay(store, time.Hour)
+	endOfDay.SetClock(clock.NewFake(now))
+
+	require.NoError(t, endOfDay.catchUp(context.Background()))
+
+	missed := []time.Time{
+		time.Date(2026, 9, 29, 0, 0, 0, 0, time.UTC),
+		time.Date(2026, 9, 30, 0, 0, 0, 0, time.UTC),
+		time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC),
+	}
+	require.Equal(t, missed, snapshotted)
+	require.Equal(t, missed, capitalized)
+	require.Equal(t, missed, reconciled)
+	// the fee is only charged for the last day of September
+	require.Equal(t, missed[1:2], charged)
+}
+
+func TestEndOfDayRunStandingOrders(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2026, 10, 16, 8, 0, 0, 0, time.UTC)
+	store := mockdb.NewMockStore(ctrl)
+
+	paid := db.StandingOrder{ID: 1, Frequency: db.StandingOrderMonthly, StartsAt: now.AddDate(0, -1, 0), RunCount: 1, NextRunAt: now}
+	broke := db.StandingOrder{ID: 2, Frequency: db.StandingOrderWeekly, StartsAt: now.AddDate(0, 0, -1), NextRunAt: now.AddDate(0, 0, -1)}
+	cancelled := db.StandingOrder{ID: 3, Frequency: db.StandingOrderWeekly, StartsAt: now, NextRunAt: now}
+	store.EXPECT().
+		ListDueStandingOrders(gomock.Any(), gomock.Eq(db.ListDueStandingOrdersParams{NextRunAt: now, Limit: endOfDayBatchSize})).
+		Times(1).
+		Return([]db.StandingOrder{broke, paid, cancelled}, nil)
+
+	store.EXPECT().RunStandingOrderTx(gomock.Any(), gomock.Eq(db.RunStandingOrderTxParams{ID: paid.ID, Now: now})).Times(1)
+	store.EXPECT().
+		RunStandingOrderTx(gomock.Any(), gomock.Eq(db.RunStandingOrderTxParams{ID: broke.ID, Now: now})).
+		Times(1).
+		Return(db.RunStandingOrderTxResult{}, db.ErrInsufficientAvailableBalance)
+	store.EXPECT().
+		RunStandingOrderTx(gomock.Any(), gomock.Eq(db.RunStandingOrderTxParams{ID: cancelled.ID, Now: now})).
+		Times(1).
+		Return(db.RunStandingOrderTxResult{}, db.ErrStandingOrderCancelled)
+
+	// the run lacking funds is skipped until the next week
+	store.EXPECT().
+		RecordStandingOrderRun(gomock.Any(), gomock.Eq(db.RecordStandingOrderRunParams{
+			RunCount:    1,
+			NextRunAt:   broke.StartsAt.AddDate(0, 0, 7),
+			LastFailure: db.ErrInsufficientAvailableBalance.Error(),
+			ID:          broke.ID,
+			DueAt:       broke.NextRunAt,
+		})).
+		Times(1)
+
+	endOfDay := NewEndOfDay(store, time.Hour)
+	endOfDay.SetClock(clock.NewFake(now))
+	require.NoError(t, endOfDay.runStandingOrders(context.Background()))
+}
+
+// The recordingDistributor type records the external transfers enqueued, the other tasks being unused.
+type recordingDistributor struct {
+	TaskDistributor
+	externalTransfers []PayloadExternalTransfer
+}
+
+func (distributor *recordingDistributor) DistributeTaskExternalTransfer(ctx context.Context, payload *PayloadExternalTransfer, opts ...asynq.Option) error {
+	distributor.externalTransfers = append(distributor.externalTransfers, *payload)
+	return nil
+}
+
+func TestEndOfDayRedispatchExternalTransfers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2026, 10, 16, 8, 0, 0, 0, time.UTC)
+	store := mockdb.NewMockStore(ctrl)
+
+	stuck := db.ExternalTransfer{ID: 4, Status: db.ExternalTransferInitiated, UpdatedAt: now.Add(-time.Hour)}
+	settling := db.ExternalTransfer{ID: 9, Status: db.ExternalTransferProcessing, UpdatedAt: now.Add(-time.Hour)}
+	store.EXPECT().
+		ListStaleExternalTransfers(gomock.Any(), gomock.Eq(db.ListStaleExternalTransfersParams{
+			UpdatedAt: now.Add(-externalTransferStaleAfter),
+			Limit:     endOfDayBatchSize,
+		})).
+		Times(1).
+		Return([]db.ExternalTransfer{stuck, settling}, nil)
+
+	distributor := &recordingDistributor{}
+	endOfDay := NewEndOfDay(store, time.Hour)
+	endOfDay.SetClock(clock.NewFake(now))
+	endOfDay.SetTaskDistributor(distributor)
+	require.NoError(t, endOfDay.redispatchExternalTransfers(context.Background()))
+
+	// each transfer is moved out of the status it is stuck in
+	require.Equal(t, []PayloadExternalTransfer{
+		{ID: stuck.ID, Status: db.ExternalTransferInitiated},
+		{ID: settling.ID, Status: db.ExternalTransferProcessing},
+	}, distributor.externalTransfers)
+}
@@ -0,0 +1,186 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	db "go-backend/db/sqlc"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// streamedEventVersion is the version of the schema of the streamed events, written in
+// doc/events/events.schema.json, increased when a change breaks their consumers.
+const streamedEventVersion = 1
+
+// streamedEventTypes are the types of the streamed events by the type of the domain event they are made
+// from. The other domain events aren't streamed.
+var streamedEventTypes = map[string]string{
+	db.EventUserCreated:       "user.registered",
+	db.EventAccountCreated:    "account.created",
+	db.EventTransferCompleted: "transfer.completed",
+}
+
+// The StreamedEvent type is the value of the messages produced to the event stream, the domain event
+// being its data.
+// @property {int64} ID - the id of the domain event, which a consumer can ignore a message it was sent
+// already with, as the events are produced at least once.
+// @property {int} SchemaVersion - the version of the schema of the event.
+type StreamedEvent struct {
+	ID            int64           `json:"id"`
+	Type          string          `json:"type"`
+	SchemaVersion int             `json:"schema_version"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+	Data          json.RawMessage `json:"data"`
+}
+
+// The EventProducer interface produces a message to a topic of the event stream.
+type EventProducer interface {
+	Produce(ctx context.Context, topic string, key string, value json.RawMessage) error
+}
+
+// The `NewEventStreamProjection` function creates the projection producing the user.created,
+// account.created and transfer.completed domain events to the event stream for the data platform, as the
+// user.registered, account.created and transfer.completed events. Each type goes to its own topic, named
+// after it with `topicPrefix`, e.g. bank.transfer.completed, and the messages are keyed by the user or
+// account they concern so that the events of each are read in order. A failed message stops the batch,
+// which is produced again, so the events are produced at least once. The events recorded before the
+// stream was enabled are produced too, from the first one.
+func NewEventStreamProjection(producer EventProducer, topicPrefix string) Projection {
+	return Projection{
+		Name: "event_stream",
+		Apply: func(ctx context.Context, q *db.Queries, event db.Event) error {
+			return streamEvent(ctx, producer, topicPrefix, event)
+		},
+	}
+}
+
+func streamEvent(ctx context.Context, producer EventProducer, topicPrefix string, event db.Event) error {
+	streamedType, ok := streamedEventTypes[event.Type]
+	if !ok {
+		return nil
+	}
+
+	key, err := streamedEventKey(event)
+	if err != nil {
+		return err
+	}
+
+	value, err := json.Marshal(StreamedEvent{
+		ID:            event.ID,
+		Type:          streamedType,
+		SchemaVersion: streamedEventVersion,
+		OccurredAt:    event.CreatedAt,
+		Data:          event.Payload,
+	})
+	if err != nil {
+		return err
+	}
+
+	return producer.Produce(ctx, topicPrefix+streamedType, key, value)
+}
+
+// The `streamedEventKey` function returns the key of the message of the event: the username of the user
+// it concerns, or the id of the account, the one the money was sent from for the transfers.
+func streamedEventKey(event db.Event) (string, error) {
+	switch event.Type {
+	case db.EventUserCreated:
+		var payload db.UserCreatedEvent
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return "", err
+		}
+		return payload.Username, nil
+	case db.EventAccountCreated:
+		var payload db.AccountEvent
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return "", err
+		}
+		return strconv.FormatInt(payload.AccountID, 10), nil
+	case db.EventTransferCompleted:
+		var payload db.TransferCompletedEvent
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return "", err
+		}
+		return strconv.FormatInt(payload.FromAccountID, 10), nil
+	}
+	return "", fmt.Errorf("no key for event %s", event.Type)
+}
+
+// The KafkaRESTProducer type produces the messages to Kafka through a Kafka REST Proxy, with the v2 API
+// and json values. The credentials of the proxy, when it requires some, are given in its URL.
+type KafkaRESTProducer struct {
+	baseURL string
+	client  *http.Client
+}
+
+// The function creates a producer posting to the REST Proxy at `baseURL`, e.g. http://kafka-rest:8082,
+// giving up on a request after `timeout`.
+func NewKafkaRESTProducer(baseURL string, timeout time.Duration) *KafkaRESTProducer {
+	return &KafkaRESTProducer{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+type kafkaRecords struct {
+	Records []kafkaRecord `json:"records"`
+}
+
+type kafkaRecord struct {
+	Key   string          `json:"key"`
+	Value json.RawMessage `json:"value"`
+}
+
+type kafkaOffsets struct {
+	Offsets []struct {
+		Partition int32   `json:"partition"`
+		Offset    int64   `json:"offset"`
+		ErrorCode *int    `json:"error_code"`
+		Error     *string `json:"error"`
+	} `json:"offsets"`
+}
+
+func (producer *KafkaRESTProducer) Produce(ctx context.Context, topic string, key string, value json.RawMessage) error {
+	body, err := json.Marshal(kafkaRecords{Records: []kafkaRecord{{Key: key, Value: value}}})
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, producer.baseURL+"/topics/"+url.PathEscape(topic), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/vnd.kafka.json.v2+json")
+	request.Header.Set("Accept", "application/vnd.kafka.v2+json")
+
+	response, err := producer.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("kafka rest proxy responded with status %d", response.StatusCode)
+	}
+
+	// the proxy responds with 200 even when a record failed, telling it in its offset
+	var offsets kafkaOffsets
+	if err := json.NewDecoder(response.Body).Decode(&offsets); err != nil {
+		return fmt.Errorf("invalid response of the kafka rest proxy: %w", err)
+	}
+	for _, offset := range offsets.Offsets {
+		if offset.ErrorCode != nil || offset.Error != nil {
+			message := ""
+			if offset.Error != nil {
+				message = *offset.Error
+			}
+			return fmt.Errorf("cannot produce to topic %s: %s", topic, message)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	db "go-backend/db/sqlc"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventStreamProjection(t *testing.T) {
+	var paths []string
+	var records []kafkaRecords
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "application/vnd.kafka.json.v2+json", r.Header.Get("Content-Type"))
+
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		var record kafkaRecords
+		require.NoError(t, json.Unmarshal(body, &record))
+
+		paths = append(paths, r.URL.Path)
+		records = append(records, record)
+		w.Write([]byte(`{"offsets":[{"partition":0,"offset":1,"error_code":null,"error":null}]}`))
+	}))
+	defer server.Close()
+
+	projection := NewEventStreamProjection(NewKafkaRESTProducer(server.URL, time.Second), "bank.")
+	createdAt := time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC)
+	events := []db.Event{
+		{ID: 1, Type: db.EventUserCreated, Payload: json.RawMessage(`{"username":"ada","full_name":"Ada","email":"ada@bank.com"}`), CreatedAt: createdAt},
+		{ID: 2, Type: db.EventAccountCreated, Payload: json.RawMessage(`{"account_id":7,"owner":"ada","currency":"CAD","balance":0}`), CreatedAt: createdAt},
+		{ID: 3, Type: db.EventTransferSent, Payload: json.RawMessage(`{}`), CreatedAt: createdAt},
+		{ID: 4, Type: db.EventTransferCompleted, Payload: json.RawMessage(`{"transfer_id":9,"from_account_id":7,"to_account_id":8,"amount":10}`), CreatedAt: createdAt},
+	}
+	for _, event := range events {
+		require.NoError(t, projection.Apply(context.Background(), nil, event))
+	}
+
+	// the other events aren't streamed
+	require.Equal(t, []string{"/topics/bank.user.registered", "/topics/bank.account.created", "/topics/bank.transfer.completed"}, paths)
+	require.Equal(t, "ada", records[0].Records[0].Key)
+	require.Equal(t, "7", records[1].Records[0].Key)
+	require.Equal(t, "7", records[2].Records[0].Key)
+
+	var streamed StreamedEvent
+	require.NoError(t, json.Unmarshal(records[2].Records[0].Value, &streamed))
+	require.Equal(t, StreamedEvent{
+		ID:            4,
+		Type:          "transfer.completed",
+		SchemaVersion: 1,
+		OccurredAt:    createdAt,
+		Data:          events[3].Payload,
+	}, streamed)
+}
+
+func TestKafkaRESTProducerErrors(t *testing.T) {
+	testCases := []struct {
+		name   string
+		status int
+		body   string
+	}{
+		{name: "Status", status: http.StatusInternalServerError, body: `{"error_code":50001}`},
+		{name: "RecordError", status: http.StatusOK, body: `{"offsets":[{"partition":null,"offset":null,"error_code":40403,"error":"unknown topic"}]}`},
+		{name: "InvalidResponse", status: http.StatusOK, body: `oops`},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.status)
+				w.Write([]byte(tc.body))
+			}))
+			defer server.Close()
+
+			err := NewKafkaRESTProducer(server.URL, time.Second).Produce(context.Background(), "bank.account.created", "7", json.RawMessage(`{}`))
+			require.Error(t, err)
+		})
+	}
+}
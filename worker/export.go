@@ -0,0 +1,322 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	db "go-backend/db/sqlc"
+	"strconv"
+	"time"
+)
+
+const (
+	ExportKindStatement = "statement"
+	ExportKindGDPR      = "gdpr"
+	ExportKindEntries   = "entries"
+)
+
+const (
+	JobStatusPending   = "pending"
+	JobStatusRunning   = "running"
+	JobStatusSucceeded = "succeeded"
+	JobStatusFailed    = "failed"
+	JobStatusCancelled = "cancelled"
+)
+
+const exportPageSize = 100
+
+// errJobCancelled is returned by an exporter when the job was cancelled while it was running.
+var errJobCancelled = errors.New("job has been cancelled")
+
+// The ExportParams type holds the kind specific parameters of an export job, stored as json on the job.
+// @property {int64} AccountID - the account to export, required for statements and entries.
+// @property {time.Time} From - start (inclusive) of the statement period.
+// @property {time.Time} To - end (exclusive) of the statement period.
+type ExportParams struct {
+	AccountID int64     `json:"account_id,omitempty"`
+	From      time.Time `json:"from,omitempty"`
+	To        time.Time `json:"to,omitempty"`
+}
+
+// exportResult is the generated file of a finished export.
+type exportResult struct {
+	data        []byte
+	contentType string
+}
+
+// exporter generates the file for a single job, reporting progress back to the jobs table. Reporting
+// progress fails with errJobCancelled once the job has been cancelled so that the export stops early.
+type exporter struct {
+	store db.Store
+	job   db.Job
+}
+
+func (exporter *exporter) run(ctx context.Context) (exportResult, error) {
+	var params ExportParams
+	if err := json.Unmarshal(exporter.job.Params, &params); err != nil {
+		return exportResult{}, fmt.Errorf("invalid export params: %w", err)
+	}
+
+	// the job is marked running up front, as an export with nothing to report, e.g. of a user without
+	// accounts, can only be completed once running
+	if err := exporter.reportProgress(ctx, 0, 1); err != nil {
+		return exportResult{}, err
+	}
+
+	switch exporter.job.Kind {
+	case ExportKindEntries:
+		return exporter.exportEntries(ctx, params)
+	case ExportKindStatement:
+		return exporter.exportStatement(ctx, params)
+	case ExportKindGDPR:
+		return exporter.exportGDPR(ctx)
+	}
+	return exportResult{}, fmt.Errorf("unsupported export kind %s", exporter.job.Kind)
+}
+
+func (exporter *exporter) reportProgress(ctx context.Context, done int64, total int64) error {
+	progress := int32(100)
+	if total > 0 {
+		progress = int32(done * 100 / total)
+	}
+	// keep 100 for when the result has been stored
+	if progress >= 100 {
+		progress = 99
+	}
+
+	_, err := exporter.store.UpdateJobProgress(ctx, db.UpdateJobProgressParams{
+		ID:       exporter.job.ID,
+		Progress: progress,
+	})
+	if errors.Is(err, db.ErrRecordNotFound) {
+		return errJobCancelled
+	}
+	return err
+}
+
+func (exporter *exporter) ownedAccount(ctx context.Context, accountID int64) (db.Account, error) {
+	account, err := exporter.store.GetAccount(ctx, accountID)
+	if err != nil {
+		return account, err
+	}
+
+	// the accounts shared with the user are exported whatever their role, as they can read them
+	role, err := db.AccountRole(ctx, exporter.store, account, exporter.job.Username)
+	if err != nil {
+		return account, err
+	}
+	if role == "" {
+		return account, fmt.Errorf("account [%d] doesn't belong to %s", accountID, exporter.job.Username)
+	}
+	return account, nil
+}
+
+func (exporter *exporter) exportEntries(ctx context.Context, params ExportParams) (exportResult, error) {
+	if _, err := exporter.ownedAccount(ctx, params.AccountID); err != nil {
+		return exportResult{}, err
+	}
+
+	total, err := exporter.store.CountEntries(ctx, params.AccountID)
+	if err != nil {
+		return exportResult{}, err
+	}
+
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+	writer.Write([]string{"id", "account_id", "amount", "created_at"})
+
+	for offset := int64(0); ; offset += exportPageSize {
+		entries, err := exporter.store.ListEntries(ctx, db.ListEntriesParams{
+			AccountID: params.AccountID,
+			Limit:     exportPageSize,
+			Offset:    int32(offset),
+		})
+		if err != nil {
+			return exportResult{}, err
+		}
+
+		for _, entry := range entries {
+			writer.Write(entryRecord(entry))
+		}
+
+		if err := exporter.reportProgress(ctx, offset+int64(len(entries)), total); err != nil {
+			return exportResult{}, err
+		}
+		if len(entries) < exportPageSize {
+			break
+		}
+	}
+
+	writer.Flush()
+	return exportResult{data: buffer.Bytes(), contentType: "text/csv"}, writer.Error()
+}
+
+func (exporter *exporter) exportStatement(ctx context.Context, params ExportParams) (exportResult, error) {
+	account, err := exporter.ownedAccount(ctx, params.AccountID)
+	if err != nil {
+		return exportResult{}, err
+	}
+
+	total, err := exporter.store.CountEntriesInRange(ctx, db.CountEntriesInRangeParams{
+		AccountID: params.AccountID,
+		FromTime:  params.From,
+		ToTime:    params.To,
+	})
+	if err != nil {
+		return exportResult{}, err
+	}
+
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+	writer.Write([]string{"account_id", strconv.FormatInt(account.ID, 10)})
+	writer.Write([]string{"currency", account.Currency})
+	writer.Write([]string{"from", params.From.Format(time.RFC3339)})
+	writer.Write([]string{"to", params.To.Format(time.RFC3339)})
+	writer.Write([]string{"id", "account_id", "amount", "created_at"})
+
+	var credits, debits int64
+	for offset := int64(0); ; offset += exportPageSize {
+		entries, err := exporter.store.ListEntriesInRange(ctx, db.ListEntriesInRangeParams{
+			AccountID: params.AccountID,
+			FromTime:  params.From,
+			ToTime:    params.To,
+			RowLimit:  exportPageSize,
+			RowOffset: int32(offset),
+		})
+		if err != nil {
+			return exportResult{}, err
+		}
+
+		for _, entry := range entries {
+			if entry.Amount > 0 {
+				credits += entry.Amount
+			} else {
+				debits -= entry.Amount
+			}
+			writer.Write(entryRecord(entry))
+		}
+
+		if err := exporter.reportProgress(ctx, offset+int64(len(entries)), total); err != nil {
+			return exportResult{}, err
+		}
+		if len(entries) < exportPageSize {
+			break
+		}
+	}
+
+	writer.Write([]string{"total_credits", strconv.FormatInt(credits, 10)})
+	writer.Write([]string{"total_debits", strconv.FormatInt(debits, 10)})
+	writer.Flush()
+	return exportResult{data: buffer.Bytes(), contentType: "text/csv"}, writer.Error()
+}
+
+// gdprExport holds all personal data kept about a user.
+type gdprExport struct {
+	Username          string              `json:"username"`
+	FullName          string              `json:"full_name"`
+	Email             string              `json:"email"`
+	PasswordChangedAt time.Time           `json:"password_changed_at"`
+	CreatedAt         time.Time           `json:"created_at"`
+	Accounts          []gdprAccountExport `json:"accounts"`
+	Beneficiaries     []db.Beneficiary    `json:"beneficiaries"`
+	// null when the user never changed the defaults
+	NotificationPreferences *db.NotificationPreference `json:"notification_preferences"`
+}
+
+type gdprAccountExport struct {
+	db.Account
+	Entries []db.Entry `json:"entries"`
+}
+
+func (exporter *exporter) exportGDPR(ctx context.Context) (exportResult, error) {
+	user, err := exporter.store.GetUser(ctx, exporter.job.Username)
+	if err != nil {
+		return exportResult{}, err
+	}
+
+	export := gdprExport{
+		Username:          user.Username,
+		FullName:          user.FullName,
+		Email:             user.Email,
+		PasswordChangedAt: user.PasswordChangedAt,
+		CreatedAt:         user.CreatedAt,
+		Accounts:          []gdprAccountExport{},
+		Beneficiaries:     []db.Beneficiary{},
+	}
+
+	for offset := int32(0); ; offset += exportPageSize {
+		page, err := exporter.store.ListBeneficiaries(ctx, db.ListBeneficiariesParams{
+			Owner:  user.Username,
+			Limit:  exportPageSize,
+			Offset: offset,
+		})
+		if err != nil {
+			return exportResult{}, err
+		}
+		export.Beneficiaries = append(export.Beneficiaries, page...)
+		if len(page) < exportPageSize {
+			break
+		}
+	}
+
+	preferences, err := exporter.store.GetNotificationPreferences(ctx, user.Username)
+	if err == nil {
+		export.NotificationPreferences = &preferences
+	} else if !errors.Is(err, db.ErrRecordNotFound) {
+		return exportResult{}, err
+	}
+
+	var accounts []db.Account
+	for offset := int32(0); ; offset += exportPageSize {
+		page, err := exporter.store.ListAccounts(ctx, db.ListAccountsParams{
+			Owner:  user.Username,
+			Limit:  exportPageSize,
+			Offset: offset,
+		})
+		if err != nil {
+			return exportResult{}, err
+		}
+		accounts = append(accounts, page...)
+		if len(page) < exportPageSize {
+			break
+		}
+	}
+
+	for i, account := range accounts {
+		accountExport := gdprAccountExport{Account: account, Entries: []db.Entry{}}
+		for offset := int32(0); ; offset += exportPageSize {
+			entries, err := exporter.store.ListEntries(ctx, db.ListEntriesParams{
+				AccountID: account.ID,
+				Limit:     exportPageSize,
+				Offset:    offset,
+			})
+			if err != nil {
+				return exportResult{}, err
+			}
+			accountExport.Entries = append(accountExport.Entries, entries...)
+			if len(entries) < exportPageSize {
+				break
+			}
+		}
+		export.Accounts = append(export.Accounts, accountExport)
+
+		if err := exporter.reportProgress(ctx, int64(i+1), int64(len(accounts))); err != nil {
+			return exportResult{}, err
+		}
+	}
+
+	data, err := json.MarshalIndent(export, "", "  ")
+	return exportResult{data: data, contentType: "application/json"}, err
+}
+
+func entryRecord(entry db.Entry) []string {
+	return []string{
+		strconv.FormatInt(entry.ID, 10),
+		strconv.FormatInt(entry.AccountID, 10),
+		strconv.FormatInt(entry.Amount, 10),
+		entry.CreatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,148 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	db "go-backend/db/sqlc"
+	"log"
+	"sync"
+	"time"
+)
+
+// LeaderLeaseName is the name of the lease held by the active instance, the one serving writes.
+const LeaderLeaseName = "active"
+
+// leaseReleaseTimeout bounds the release of the lease on shutdown, once the context is cancelled.
+const leaseReleaseTimeout = 5 * time.Second
+
+// The Leadership interface tells whether this instance is the active one, or a standby that serves
+// read-only requests and redirects writes to the leader.
+type Leadership interface {
+	IsLeader() bool
+	// LeaderAddress returns the address the leader serves writes at, empty when no instance holds
+	// the lease.
+	LeaderAddress() string
+}
+
+// The LeaderElector type campaigns for the leader lease in the database. The instance holding the lease
+// is active and the others are standbys, one takes over once the lease of the leader expires, e.g.
+// after its region went down. An instance steps down as soon as its lease could have expired, even when
+// it can't reach the database to learn whether another instance took over.
+type LeaderElector struct {
+	store    db.Store
+	holder   string
+	address  string
+	duration time.Duration
+
+	mutex         sync.RWMutex
+	validUntil    time.Time
+	leaderAddress string
+}
+
+// The function creates an elector campaigning for the lease as `holder`, which must be unique per
+// instance, and advertising `address` to the clients of the standbys. The lease is renewed every third
+// of its `duration`.
+func NewLeaderElector(store db.Store, holder string, address string, duration time.Duration) *LeaderElector {
+	return &LeaderElector{
+		store:    store,
+		holder:   holder,
+		address:  address,
+		duration: duration,
+	}
+}
+
+// The `Run` function campaigns for the lease until the context is cancelled, then releases it so that a
+// standby takes over without waiting for it to expire.
+func (elector *LeaderElector) Run(ctx context.Context) {
+	ticker := time.NewTicker(elector.duration / 3)
+	defer ticker.Stop()
+
+	for {
+		err := elector.Campaign(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("leader election failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			elector.release()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// The `Campaign` function takes or renews the lease, or learns which instance holds it.
+func (elector *LeaderElector) Campaign(ctx context.Context) error {
+	start := time.Now()
+	_, err := elector.store.AcquireLeaderLease(ctx, db.AcquireLeaderLeaseParams{
+		Name:       LeaderLeaseName,
+		Holder:     elector.holder,
+		Address:    elector.address,
+		DurationMs: elector.duration.Milliseconds(),
+	})
+	if err == nil {
+		// the lease started at the earliest when the query was sent
+		elector.set(start.Add(elector.duration), elector.address)
+		return nil
+	}
+
+	if !errors.Is(err, db.ErrRecordNotFound) {
+		return err
+	}
+
+	lease, err := elector.store.GetLeaderLease(ctx, LeaderLeaseName)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			// released in the meantime, it is taken on the next tick
+			elector.set(time.Time{}, "")
+			return nil
+		}
+		return err
+	}
+
+	elector.set(time.Time{}, lease.Address)
+	return nil
+}
+
+// The `IsLeader` function reports whether this instance holds a lease that can't have expired yet.
+func (elector *LeaderElector) IsLeader() bool {
+	elector.mutex.RLock()
+	defer elector.mutex.RUnlock()
+
+	return time.Now().Before(elector.validUntil)
+}
+
+// The `LeaderAddress` function returns the address of the leader as last seen.
+func (elector *LeaderElector) LeaderAddress() string {
+	elector.mutex.RLock()
+	defer elector.mutex.RUnlock()
+
+	return elector.leaderAddress
+}
+
+func (elector *LeaderElector) set(validUntil time.Time, leaderAddress string) {
+	elector.mutex.Lock()
+	defer elector.mutex.Unlock()
+
+	elector.validUntil = validUntil
+	elector.leaderAddress = leaderAddress
+}
+
+func (elector *LeaderElector) release() {
+	if !elector.IsLeader() {
+		return
+	}
+	elector.set(time.Time{}, "")
+
+	ctx, cancel := context.WithTimeout(context.Background(), leaseReleaseTimeout)
+	defer cancel()
+
+	err := elector.store.ReleaseLeaderLease(ctx, db.ReleaseLeaderLeaseParams{
+		Name:   LeaderLeaseName,
+		Holder: elector.holder,
+	})
+	if err != nil {
+		log.Printf("cannot release leader lease: %v", err)
+	}
+}
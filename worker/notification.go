@@ -0,0 +1,242 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	db "go-backend/db/sqlc"
+)
+
+// NotificationProjection delivers the transfer.sent and transfer.received events to the owner of the
+// account they describe, so the sender and the recipient of a transfer each get their own notification.
+// The payment_request.created events are delivered to the payer asked for the money, the
+// queued_transfer.processed events to the owner of the transfer, telling them its final status, the
+// external_transfer.updated events to the owner of the external transfer, on every change of its status,
+// the session.new_device events to the user who logged in and the user.locked events to the user locked out
+// after too many failed logins. A transfer leaving an account below its low balance threshold, the one
+// set on the account or else the one of its owner, also notifies them with an account.low_balance
+// notification, and a transfer to a beneficiary taking the spending of its category beyond the monthly
+// budget of the account with a budget.exceeded notification.
+//
+// The notifications are listed in the app and queued for the email and webhook channels according to
+// the preferences of the user, the NotificationDispatcher sending the queued ones.
+var NotificationProjection = Projection{
+	Name:  "notifications",
+	Apply: applyNotificationEvent,
+}
+
+func applyNotificationEvent(ctx context.Context, q *db.Queries, event db.Event) error {
+	switch event.Type {
+	case db.EventTransferSent, db.EventTransferReceived:
+		var payload db.TransferPartyEvent
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return err
+		}
+
+		preferences, err := db.NotificationPreferencesOf(ctx, q, payload.Owner)
+		if err != nil {
+			return err
+		}
+
+		err = notify(ctx, q, preferences, event, event.Type, event.Payload)
+		if err != nil || event.Type != db.EventTransferSent {
+			return err
+		}
+
+		err = notifyLowBalance(ctx, q, preferences, event, payload)
+		if err != nil {
+			return err
+		}
+
+		return notifyBudgetExceeded(ctx, q, preferences, event, payload)
+	case db.EventPaymentRequestCreated:
+		var payload db.PaymentRequestEvent
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return err
+		}
+
+		return notifyUser(ctx, q, payload.Payer, event)
+	case db.EventQueuedTransferProcessed:
+		var payload db.QueuedTransferEvent
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return err
+		}
+
+		return notifyUser(ctx, q, payload.Owner, event)
+	case db.EventExternalTransferUpdated:
+		var payload db.ExternalTransferEvent
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return err
+		}
+
+		return notifyUser(ctx, q, payload.Owner, event)
+	case db.EventSessionNewDevice:
+		var payload db.NewDeviceEvent
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return err
+		}
+
+		return notifyUser(ctx, q, payload.Username, event)
+	case db.EventUserLocked:
+		var payload db.UserLockedEvent
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return err
+		}
+
+		return notifyUser(ctx, q, payload.Username, event)
+	}
+
+	// events the projection doesn't care about
+	return nil
+}
+
+// The `notifyLowBalance` function notifies the owner of an account with an account.low_balance
+// notification when the sent transfer left it below its low balance threshold.
+func notifyLowBalance(ctx context.Context, q *db.Queries, preferences db.NotificationPreference, event db.Event, transfer db.TransferPartyEvent) error {
+	threshold, err := db.LowBalanceThresholdOf(ctx, q, transfer.AccountID, preferences)
+	if err != nil || !leftBelowThreshold(transfer, threshold) {
+		return err
+	}
+
+	lowBalance, err := json.Marshal(db.LowBalanceNotification{
+		AccountID:  transfer.AccountID,
+		Owner:      transfer.Owner,
+		Currency:   transfer.Currency,
+		Balance:    transfer.Balance,
+		Threshold:  threshold,
+		TransferID: transfer.TransferID,
+	})
+	if err != nil {
+		return err
+	}
+
+	return notify(ctx, q, preferences, event, db.NotificationAccountLowBalance, lowBalance)
+}
+
+// The `notifyBudgetExceeded` function notifies the owner of an account with a budget.exceeded
+// notification when the sent transfer, to a beneficiary of theirs, took the spending of its category this
+// month from within the budget of the account to beyond it. The transfers to accounts which aren't
+// beneficiaries of the owner, or only uncategorized ones, aren't budgeted.
+func notifyBudgetExceeded(ctx context.Context, q *db.Queries, preferences db.NotificationPreference, event db.Event, transfer db.TransferPartyEvent) error {
+	beneficiary, err := q.GetBeneficiaryByAccount(ctx, db.GetBeneficiaryByAccountParams{
+		Owner:     transfer.Owner,
+		AccountID: transfer.CounterpartyAccountID,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if beneficiary.Category == "" {
+		return nil
+	}
+
+	budget, err := q.GetBudget(ctx, db.GetBudgetParams{
+		AccountID: transfer.AccountID,
+		Category:  beneficiary.Category,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	spent, err := q.SumCategorySpending(ctx, db.SumCategorySpendingParams{
+		AccountID: transfer.AccountID,
+		Owner:     transfer.Owner,
+		Category:  budget.Category,
+		Since:     db.BudgetMonthStart(transfer.CreatedAt),
+		Until:     transfer.CreatedAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	// the amount of a sent transfer is negative
+	before := spent + transfer.Amount
+	if spent <= budget.MonthlyLimit || before > budget.MonthlyLimit {
+		return nil
+	}
+
+	exceeded, err := json.Marshal(db.BudgetExceededNotification{
+		AccountID:    transfer.AccountID,
+		Owner:        transfer.Owner,
+		Currency:     transfer.Currency,
+		Category:     budget.Category,
+		Spent:        spent,
+		MonthlyLimit: budget.MonthlyLimit,
+		TransferID:   transfer.TransferID,
+	})
+	if err != nil {
+		return err
+	}
+
+	return notify(ctx, q, preferences, event, db.NotificationBudgetExceeded, exceeded)
+}
+
+// The `leftBelowThreshold` function reports whether the transfer took the account from at or above the
+// threshold to below it, so that the owner isn't notified again by every transfer from a low account.
+func leftBelowThreshold(transfer db.TransferPartyEvent, threshold int64) bool {
+	// the amount of a sent transfer is negative
+	before := transfer.Balance - transfer.Amount
+	return threshold > 0 && transfer.Balance < threshold && before >= threshold
+}
+
+// The `notifyUser` function notifies the user of the event according to their preferences.
+func notifyUser(ctx context.Context, q *db.Queries, username string, event db.Event) error {
+	preferences, err := db.NotificationPreferencesOf(ctx, q, username)
+	if err != nil {
+		return err
+	}
+
+	return notify(ctx, q, preferences, event, event.Type, event.Payload)
+}
+
+// The `notify` function lists the notification in the app and queues it for every other channel the
+// user chose. The destinations are the ones set when the event is projected.
+func notify(ctx context.Context, q *db.Queries, preferences db.NotificationPreference, event db.Event, notificationType string, payload json.RawMessage) error {
+	if preferences.InApp {
+		err := q.CreateNotification(ctx, db.CreateNotificationParams{
+			Username:  preferences.Username,
+			EventID:   event.ID,
+			Type:      notificationType,
+			Payload:   payload,
+			CreatedAt: event.CreatedAt,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	delivery := db.CreateNotificationDeliveryParams{
+		Username:  preferences.Username,
+		EventID:   event.ID,
+		Type:      notificationType,
+		Payload:   payload,
+		CreatedAt: event.CreatedAt,
+	}
+
+	if preferences.Email {
+		user, err := q.GetUser(ctx, preferences.Username)
+		if err != nil {
+			return err
+		}
+
+		delivery.Channel = db.NotificationChannelEmail
+		delivery.Destination = user.Email
+		err = q.CreateNotificationDelivery(ctx, delivery)
+		if err != nil {
+			return err
+		}
+	}
+
+	if preferences.WebhookURL != "" {
+		delivery.Channel = db.NotificationChannelWebhook
+		delivery.Destination = preferences.WebhookURL
+		return q.CreateNotificationDelivery(ctx, delivery)
+	}
+
+	return nil
+}
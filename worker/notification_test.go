@@ -0,0 +1,118 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"go-backend/clock"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotificationProjection(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	fake := clock.NewFake(now)
+
+	sent := db.TransferPartyEvent{TransferID: 1, AccountID: 1, Owner: "alice", Currency: "USD", Amount: -10, Balance: 90, CounterpartyAccountID: 2, CounterpartyOwner: "bob"}
+	received := db.TransferPartyEvent{TransferID: 1, AccountID: 2, Owner: "bob", Currency: "USD", Amount: 10, Balance: 10, CounterpartyAccountID: 1, CounterpartyOwner: "alice"}
+	events := []db.Event{
+		newTestEvent(t, 1, db.EventTransferSent, sent, now.Add(-time.Second)),
+		newTestEvent(t, 2, db.EventTransferReceived, received, now.Add(-time.Second)),
+	}
+
+	dbtx := &fakeNotificationDB{}
+	queries := db.New(dbtx)
+
+	// like the database, the store applies the events after the checkpoint recorded before the settling time
+	var checkpoint int64
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		ProjectEventsTx(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		DoAndReturn(func(ctx context.Context, arg db.ProjectEventsTxParams) (int, error) {
+			var applied int
+			for _, event := range events {
+				if event.ID <= checkpoint || !event.CreatedAt.Before(arg.SettledBefore) || applied == int(arg.Limit) {
+					continue
+				}
+				if err := arg.Apply(ctx, queries, event); err != nil {
+					return applied, err
+				}
+				checkpoint = event.ID
+				applied++
+			}
+			return applied, nil
+		})
+
+	projector := NewProjector(store, time.Second, NotificationProjection)
+	projector.SetClock(fake)
+
+	// the events haven't settled yet
+	require.NoError(t, projector.catchUp(context.Background(), NotificationProjection))
+	require.Empty(t, dbtx.notifications)
+
+	fake.Advance(db.EventSettleDelay)
+	require.NoError(t, projector.catchUp(context.Background(), NotificationProjection))
+	require.Equal(t, []db.CreateNotificationParams{
+		{Username: "alice", EventID: 1, Type: db.EventTransferSent, Payload: events[0].Payload, CreatedAt: events[0].CreatedAt},
+		{Username: "bob", EventID: 2, Type: db.EventTransferReceived, Payload: events[1].Payload, CreatedAt: events[1].CreatedAt},
+	}, dbtx.notifications)
+
+	// the checkpoint moved past the events, so they don't notify the users again
+	fake.Advance(time.Minute)
+	require.NoError(t, projector.catchUp(context.Background(), NotificationProjection))
+	require.Len(t, dbtx.notifications, 2)
+}
+
+func newTestEvent(t *testing.T, id int64, eventType string, payload interface{}, createdAt time.Time) db.Event {
+	data, err := json.Marshal(payload)
+	require.NoError(t, err)
+
+	return db.Event{ID: id, Type: eventType, Payload: data, CreatedAt: createdAt}
+}
+
+// The fakeNotificationDB type records the notifications the projection creates. The users it looks up
+// have no preferences, alerts, beneficiaries or budgets, so they are only notified in the app.
+type fakeNotificationDB struct {
+	notifications []db.CreateNotificationParams
+}
+
+func (fake *fakeNotificationDB) Exec(_ context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	if !strings.HasPrefix(sql, "-- name: CreateNotification :exec") {
+		return pgconn.CommandTag{}, errors.New("unexpected statement: " + sql)
+	}
+
+	fake.notifications = append(fake.notifications, db.CreateNotificationParams{
+		Username:  args[0].(string),
+		EventID:   args[1].(int64),
+		Type:      args[2].(string),
+		Payload:   args[3].(json.RawMessage),
+		CreatedAt: args[4].(time.Time),
+	})
+	return pgconn.NewCommandTag("INSERT 0 1"), nil
+}
+
+func (fake *fakeNotificationDB) Query(_ context.Context, sql string, _ ...interface{}) (pgx.Rows, error) {
+	return nil, errors.New("unexpected query: " + sql)
+}
+
+func (fake *fakeNotificationDB) QueryRow(context.Context, string, ...interface{}) pgx.Row {
+	return noRow{}
+}
+
+type noRow struct{}
+
+func (noRow) Scan(...interface{}) error {
+	return pgx.ErrNoRows
+}
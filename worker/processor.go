@@ -0,0 +1,88 @@
+package worker
+
+import (
+	"context"
+	db "go-backend/db/sqlc"
+	"go-backend/tracking"
+	"log"
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+const (
+	QueueCritical = "critical"
+	QueueDefault  = "default"
+)
+
+// The TaskProcessor interface picks up tasks from redis and processes them.
+type TaskProcessor interface {
+	Start() error
+	Shutdown()
+	ProcessTaskRunExport(ctx context.Context, task *asynq.Task) error
+	ProcessTaskQueuedTransfer(ctx context.Context, task *asynq.Task) error
+	ProcessTaskExternalTransfer(ctx context.Context, task *asynq.Task) error
+	ProcessTaskDeleteUserData(ctx context.Context, task *asynq.Task) error
+}
+
+type RedisTaskProcessor struct {
+	server      *asynq.Server
+	distributor TaskDistributor
+	store       db.Store
+	transfers   QueuedTransferHandler
+}
+
+// The function creates a new task processor that consumes the critical and default queues, and enqueues
+// the next steps of the external transfers. The queued transfers are made with `transfers`, they are left
+// in the queue when it is nil.
+func NewRedisTaskProcessor(redisOpt asynq.RedisClientOpt, store db.Store, transfers QueuedTransferHandler) TaskProcessor {
+	queues := map[string]int{
+		QueueCritical: 10,
+		QueueDefault:  5,
+	}
+	server := asynq.NewServer(redisOpt, asynq.Config{
+		Queues: queues,
+		ErrorHandler: asynq.ErrorHandlerFunc(func(ctx context.Context, task *asynq.Task, err error) {
+			log.Printf("process task failed: type=%s payload=%s err=%v", task.Type(), task.Payload(), err)
+			tracking.CaptureTaskError(ctx, task, err)
+		}),
+		RetryDelayFunc: func(n int, err error, task *asynq.Task) time.Duration {
+			if task.Type() == TaskQueuedTransfer {
+				return queuedTransferRetryDelay
+			}
+			return asynq.DefaultRetryDelayFunc(n, err, task)
+		},
+	})
+
+	return &RedisTaskProcessor{
+		server:      server,
+		distributor: NewRedisTaskDistributor(redisOpt),
+		store:       store,
+		transfers:   transfers,
+	}
+}
+
+// The `Start` function registers the task handlers and starts processing in the background. Tasks that
+// were already processed are skipped.
+func (processor *RedisTaskProcessor) Start() error {
+	mux := asynq.NewServeMux()
+	mux.HandleFunc(TaskRunExport, processor.deduplicate(processor.ProcessTaskRunExport))
+	mux.HandleFunc(TaskExternalTransfer, processor.deduplicate(processor.ProcessTaskExternalTransfer))
+	mux.HandleFunc(TaskDeleteUserData, processor.deduplicate(processor.ProcessTaskDeleteUserData))
+	if processor.transfers != nil {
+		mux.HandleFunc(TaskQueuedTransfer, processor.deduplicate(processor.ProcessTaskQueuedTransfer))
+	}
+
+	return processor.server.Start(mux)
+}
+
+// The `Shutdown` function stops pulling new tasks and waits for the active ones to finish, before closing
+// the connections of the distributor they enqueue with.
+func (processor *RedisTaskProcessor) Shutdown() {
+	processor.server.Shutdown()
+
+	err := processor.distributor.Close()
+	if err != nil {
+		log.Printf("cannot close task distributor: %v", err)
+	}
+}
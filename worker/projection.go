@@ -0,0 +1,246 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-backend/clock"
+	db "go-backend/db/sqlc"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const projectionBatchSize = 100
+
+// The Projection type maintains read tables from the domain events recorded by the store.
+// @property {string} Name - the name under which the checkpoint of the projection is stored.
+// @property Apply - applies a single event to the read tables, within the transaction of the batch.
+// @property Refresh - optional periodic maintenance, for values that change with time alone.
+// @property {time.Duration} RefreshInterval - how often `Refresh` runs.
+type Projection struct {
+	Name            string
+	Apply           func(ctx context.Context, q *db.Queries, event db.Event) error
+	Refresh         func(ctx context.Context, store db.Store) error
+	RefreshInterval time.Duration
+}
+
+// The Projector type keeps a set of projections up to date by polling the events table.
+type Projector struct {
+	store       db.Store
+	interval    time.Duration
+	projections []Projection
+	clock       clock.Clock
+}
+
+// The function creates a projector polling for new events every `interval`.
+func NewProjector(store db.Store, interval time.Duration, projections ...Projection) *Projector {
+	return &Projector{
+		store:       store,
+		interval:    interval,
+		projections: projections,
+		clock:       clock.Real,
+	}
+}
+
+// The `SetClock` function sets the clock the polls and the settling of the events follow, for tests to
+// fast-forward time.
+func (projector *Projector) SetClock(clock clock.Clock) {
+	projector.clock = clock
+}
+
+// The `Run` function applies new events to every projection until the context is cancelled. A failing
+// batch is rolled back and retried on the next tick, so the read tables never skip an event.
+func (projector *Projector) Run(ctx context.Context) {
+	ticker := projector.clock.NewTicker(projector.interval)
+	defer ticker.Stop()
+
+	lastRefresh := make([]time.Time, len(projector.projections))
+	for {
+		for i, projection := range projector.projections {
+			err := projector.catchUp(ctx, projection)
+			if err != nil && ctx.Err() == nil {
+				log.Printf("projection %s failed: %v", projection.Name, err)
+			}
+
+			if projection.Refresh != nil && projector.clock.Now().Sub(lastRefresh[i]) >= projection.RefreshInterval {
+				err := projection.Refresh(ctx, projector.store)
+				if err != nil {
+					if ctx.Err() == nil {
+						log.Printf("projection %s refresh failed: %v", projection.Name, err)
+					}
+				} else {
+					lastRefresh[i] = projector.clock.Now()
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C():
+		}
+	}
+}
+
+// The `catchUp` function applies batches of events to the projection until it has seen every event
+// settled by the time of the clock.
+func (projector *Projector) catchUp(ctx context.Context, projection Projection) error {
+	settledBefore := projector.clock.Now().Add(-db.EventSettleDelay)
+	for {
+		applied, err := projector.store.ProjectEventsTx(ctx, db.ProjectEventsTxParams{
+			Projection:    projection.Name,
+			Limit:         projectionBatchSize,
+			SettledBefore: settledBefore,
+			Apply:         projection.Apply,
+		})
+		if err != nil {
+			return err
+		}
+
+		if applied < projectionBatchSize {
+			return nil
+		}
+	}
+}
+
+// OverviewProjection maintains the user_overview and account_overview tables used by the admin
+// dashboard, so that it doesn't need to aggregate the ledger.
+var OverviewProjection = Projection{
+	Name:            "overview",
+	Apply:           applyOverviewEvent,
+	Refresh:         refreshOverview,
+	RefreshInterval: time.Hour,
+}
+
+func applyOverviewEvent(ctx context.Context, q *db.Queries, event db.Event) error {
+	switch event.Type {
+	case db.EventUserCreated:
+		var payload db.UserCreatedEvent
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return err
+		}
+
+		return q.UpsertUserOverview(ctx, db.UpsertUserOverviewParams{
+			Username:  payload.Username,
+			FullName:  payload.FullName,
+			Email:     payload.Email,
+			CreatedAt: event.CreatedAt,
+		})
+
+	case db.EventAccountCreated:
+		var payload db.AccountEvent
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return err
+		}
+
+		err := q.UpsertAccountOverview(ctx, db.UpsertAccountOverviewParams{
+			AccountID: payload.AccountID,
+			Owner:     payload.Owner,
+			Currency:  payload.Currency,
+			Balance:   payload.Balance,
+			CreatedAt: event.CreatedAt,
+		})
+		if err != nil {
+			return err
+		}
+
+		return q.AddUserOverviewAccountCount(ctx, db.AddUserOverviewAccountCountParams{
+			Delta:    1,
+			Username: payload.Owner,
+		})
+
+	case db.EventAccountUpdated:
+		var payload db.AccountEvent
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return err
+		}
+
+		return q.SetAccountOverviewBalance(ctx, db.SetAccountOverviewBalanceParams{
+			AccountID: payload.AccountID,
+			Balance:   payload.Balance,
+		})
+
+	case db.EventAccountDeleted:
+		var payload db.AccountEvent
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return err
+		}
+
+		owner, err := q.DeleteAccountOverview(ctx, payload.AccountID)
+		if err != nil {
+			if errors.Is(err, db.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+
+		return q.AddUserOverviewAccountCount(ctx, db.AddUserOverviewAccountCountParams{
+			Delta:    -1,
+			Username: owner,
+		})
+
+	case db.EventTransferCompleted:
+		var payload db.TransferCompletedEvent
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return err
+		}
+
+		err := recordOverviewTransfer(ctx, q, payload.FromAccountID, payload.FromAccountBalance, payload)
+		if err != nil {
+			return err
+		}
+
+		return recordOverviewTransfer(ctx, q, payload.ToAccountID, payload.ToAccountBalance, payload)
+	}
+
+	// events the projection doesn't care about
+	return nil
+}
+
+// The `recordOverviewTransfer` function applies one side of a transfer to the overview of the account
+// and of its owner.
+func recordOverviewTransfer(ctx context.Context, q *db.Queries, accountID int64, balance int64, transfer db.TransferCompletedEvent) error {
+	activityAt := pgtype.Timestamptz{Time: transfer.CreatedAt, Valid: true}
+
+	err := q.RecordAccountOverviewTransfer(ctx, db.RecordAccountOverviewTransferParams{
+		Balance:    balance,
+		ActivityAt: activityAt,
+		AccountID:  accountID,
+	})
+	if err != nil {
+		return fmt.Errorf("cannot record transfer %d on account %d: %w", transfer.TransferID, accountID, err)
+	}
+
+	err = q.TouchUserOverview(ctx, db.TouchUserOverviewParams{
+		ActivityAt: activityAt,
+		AccountID:  accountID,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = q.AddAccountDailyVolume(ctx, db.AddAccountDailyVolumeParams{
+		AccountID: accountID,
+		Day:       transfer.CreatedAt.UTC().Truncate(24 * time.Hour),
+		Volume:    transfer.Amount,
+	})
+	if err != nil {
+		return err
+	}
+
+	return q.RefreshAccountOverviewVolume(ctx, pgtype.Int8{Int64: accountID, Valid: true})
+}
+
+// The `refreshOverview` function drops the days that left the 30 day window from the volume of every
+// account, since no event marks their expiry.
+func refreshOverview(ctx context.Context, store db.Store) error {
+	err := store.DeleteStaleAccountDailyVolume(ctx)
+	if err != nil {
+		return err
+	}
+
+	return store.RefreshAccountOverviewVolume(ctx, pgtype.Int8{})
+}
@@ -0,0 +1,83 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	db "go-backend/db/sqlc"
+	"log"
+	"strconv"
+
+	"github.com/hibiken/asynq"
+)
+
+const TaskDeleteUserData = "task:delete_user_data"
+
+// The PayloadDeleteUserData type is the scheduled deletion of the data of a user.
+// @property {int64} ScheduledFor - the end of the cooling-off period of the deletion, in unix seconds, so
+// that a deletion scheduled again after a cancelled one is a task of its own.
+type PayloadDeleteUserData struct {
+	Username     string `json:"username"`
+	ScheduledFor int64  `json:"scheduled_for"`
+}
+
+// The `DistributeTaskDeleteUserData` function enqueues the deletion of the data of a user, to be
+// processed at the end of its cooling-off period with `asynq.ProcessAt`. The task is identified by the
+// user and the end of the period, so a deletion is enqueued once.
+func (distributor *RedisTaskDistributor) DistributeTaskDeleteUserData(ctx context.Context, payload *PayloadDeleteUserData, opts ...asynq.Option) error {
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal task payload: %w", err)
+	}
+
+	task := asynq.NewTask(TaskDeleteUserData, jsonPayload, opts...)
+	return distributor.enqueue(ctx, task, TaskID(TaskDeleteUserData, payload.Username, strconv.FormatInt(payload.ScheduledFor, 10)))
+}
+
+// The `ProcessTaskDeleteUserData` function deletes the data of the user once the cooling-off period of
+// the deletion is over. A deletion the user cancelled is skipped. A deletion an account still holding
+// money prevents is failed for the user to see, rather than retried.
+func (processor *RedisTaskProcessor) ProcessTaskDeleteUserData(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadDeleteUserData
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal task payload: %w", asynq.SkipRetry)
+	}
+
+	result, err := processor.store.DeleteUserDataTx(ctx, payload.Username)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return processor.skipUserDeletion(ctx, task, payload)
+		}
+		if errors.Is(err, db.ErrAccountNotEmpty) {
+			_, failErr := processor.store.FailUserDeletion(ctx, db.FailUserDeletionParams{
+				Username: payload.Username,
+				Error:    err.Error(),
+			})
+			if failErr != nil {
+				return fmt.Errorf("failed to fail user deletion: %w", failErr)
+			}
+			return fmt.Errorf("failed to delete user data: %v: %w", err, asynq.SkipRetry)
+		}
+		return fmt.Errorf("failed to delete user data: %w", err)
+	}
+
+	log.Printf("processed task: type=%s username=%s closed_accounts=%d", task.Type(), payload.Username, len(result.ClosedAccounts))
+	return nil
+}
+
+// The `skipUserDeletion` function skips the task of a deletion that is no longer scheduled, e.g. cancelled
+// by the user, or scheduled again for another time, which another task processes. A deletion still
+// scheduled for the time of the task isn't due yet by the clock of the database, which is retried.
+func (processor *RedisTaskProcessor) skipUserDeletion(ctx context.Context, task *asynq.Task, payload PayloadDeleteUserData) error {
+	deletion, err := processor.store.GetUserDeletion(ctx, payload.Username)
+	if err != nil && !errors.Is(err, db.ErrRecordNotFound) {
+		return fmt.Errorf("failed to get user deletion: %w", err)
+	}
+	if err == nil && deletion.Status == db.UserDeletionScheduled && deletion.ScheduledFor.Unix() == payload.ScheduledFor {
+		return fmt.Errorf("user deletion isn't due until %s", deletion.ScheduledFor)
+	}
+
+	log.Printf("skipped task: type=%s username=%s", task.Type(), payload.Username)
+	return nil
+}
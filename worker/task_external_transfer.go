@@ -0,0 +1,134 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	db "go-backend/db/sqlc"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+const TaskExternalTransfer = "task:external_transfer"
+
+const (
+	// ExternalTransferSubmissionDelay is the time an initiated external transfer waits before it is
+	// submitted to its rail and processing.
+	ExternalTransferSubmissionDelay = 5 * time.Second
+	// achSettlementDelay and wireSettlementDelay are the time a processing external transfer takes to
+	// settle, much shorter than on the real rails for the delay to show while trying the API.
+	achSettlementDelay  = 2 * time.Minute
+	wireSettlementDelay = 20 * time.Second
+)
+
+// ExternalTransferClosedAccount is the failure reason of the external transfers to an account number
+// ending with 0000, which the simulated receiving bank returns as closed. The others settle.
+const ExternalTransferClosedAccount = "account closed at the receiving bank"
+
+// The PayloadExternalTransfer type is an external transfer to move out of its current status.
+// @property {string} Status - the status the transfer is moved out of, initiated or processing, so that
+// each step of the transfer is a task of its own.
+type PayloadExternalTransfer struct {
+	ID     int64  `json:"id"`
+	Status string `json:"status"`
+}
+
+// The `DistributeTaskExternalTransfer` function enqueues the next step of an external transfer. The task
+// is identified by the transfer and the status it is moved out of, so a step is enqueued once.
+func (distributor *RedisTaskDistributor) DistributeTaskExternalTransfer(ctx context.Context, payload *PayloadExternalTransfer, opts ...asynq.Option) error {
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal task payload: %w", err)
+	}
+
+	task := asynq.NewTask(TaskExternalTransfer, jsonPayload, opts...)
+	return distributor.enqueue(ctx, task, TaskID(TaskExternalTransfer, strconv.FormatInt(payload.ID, 10), payload.Status))
+}
+
+// The `ProcessTaskExternalTransfer` function simulates the rail of an external transfer. An initiated
+// transfer is submitted and processing, its settlement being enqueued after the delay of its rail, and a
+// processing transfer is settled or returned by the receiving bank. A transfer that already moved out of
+// the status is skipped, except for enqueuing its settlement again in case it was lost.
+func (processor *RedisTaskProcessor) ProcessTaskExternalTransfer(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadExternalTransfer
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal task payload: %w", asynq.SkipRetry)
+	}
+
+	external, err := processor.store.GetExternalTransfer(ctx, payload.ID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return fmt.Errorf("external transfer doesn't exist: %w", asynq.SkipRetry)
+		}
+		return fmt.Errorf("failed to get external transfer: %w", err)
+	}
+
+	switch {
+	case payload.Status == db.ExternalTransferInitiated && external.Status == db.ExternalTransferInitiated:
+		external, err = processor.advanceExternalTransfer(ctx, external.ID, db.ExternalTransferProcessing, "")
+		if err != nil {
+			return err
+		}
+		fallthrough
+	case payload.Status == db.ExternalTransferInitiated && external.Status == db.ExternalTransferProcessing:
+		err = processor.distributor.DistributeTaskExternalTransfer(ctx, &PayloadExternalTransfer{
+			ID:     external.ID,
+			Status: db.ExternalTransferProcessing,
+		}, asynq.ProcessIn(settlementDelay(external.Rail)), asynq.Queue(QueueCritical))
+		if err != nil {
+			return fmt.Errorf("failed to enqueue settlement: %w", err)
+		}
+	case payload.Status == db.ExternalTransferProcessing && external.Status == db.ExternalTransferProcessing:
+		status, reason := simulateSettlement(external)
+		external, err = processor.advanceExternalTransfer(ctx, external.ID, status, reason)
+		if err != nil {
+			return err
+		}
+	default:
+		log.Printf("skipped task: type=%s external_transfer=%d status=%s", task.Type(), external.ID, external.Status)
+		return nil
+	}
+
+	log.Printf("processed task: type=%s external_transfer=%d status=%s", task.Type(), external.ID, external.Status)
+	return nil
+}
+
+// The `advanceExternalTransfer` function moves the external transfer to the status. A transfer that
+// can't reach it was advanced concurrently, which isn't retried.
+func (processor *RedisTaskProcessor) advanceExternalTransfer(ctx context.Context, id int64, status string, reason string) (db.ExternalTransfer, error) {
+	result, err := processor.store.AdvanceExternalTransferTx(ctx, db.AdvanceExternalTransferTxParams{
+		ID:            id,
+		Status:        status,
+		FailureReason: reason,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrExternalTransferStatus) {
+			return result.ExternalTransfer, fmt.Errorf("failed to advance external transfer: %v: %w", err, asynq.SkipRetry)
+		}
+		return result.ExternalTransfer, fmt.Errorf("failed to advance external transfer: %w", err)
+	}
+
+	return result.ExternalTransfer, nil
+}
+
+// The `settlementDelay` function returns the time an external transfer processes on its rail.
+func settlementDelay(rail string) time.Duration {
+	if rail == db.ExternalTransferWire {
+		return wireSettlementDelay
+	}
+	return achSettlementDelay
+}
+
+// The `simulateSettlement` function stands for the receiving bank, returning the final status of a
+// processing external transfer and why it failed.
+func simulateSettlement(external db.ExternalTransfer) (string, string) {
+	if strings.HasSuffix(external.AccountNumber, "0000") {
+		return db.ExternalTransferFailed, ExternalTransferClosedAccount
+	}
+	return db.ExternalTransferSettled, ""
+}
@@ -0,0 +1,108 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+)
+
+const TaskQueuedTransfer = "task:queued_transfer"
+
+const (
+	// queuedTransferRetryDelay is the time between two attempts at a queued transfer, short for the
+	// transfer to be made soon after the database is back.
+	queuedTransferRetryDelay = 15 * time.Second
+	// QueuedTransferMaxRetry is the number of attempts at a queued transfer, a day of retries, after
+	// which the transfer is archived and never made.
+	QueuedTransferMaxRetry = int(24 * time.Hour / queuedTransferRetryDelay)
+)
+
+// ErrTaskNotFound is returned when looking up a task that redis no longer holds, or never did.
+var ErrTaskNotFound = errors.New("task not found")
+
+// The PayloadQueuedTransfer type is a transfer accepted while the database was unavailable, made once it
+// is back. The checks of the transfer are only made then, the payload holding the request as it was
+// received.
+// @property {uuid.UUID} ID - the tracking ID returned to the client, which identifies the task.
+// @property {time.Time} QueuedAt - when the transfer was accepted.
+type PayloadQueuedTransfer struct {
+	ID                uuid.UUID `json:"id"`
+	Owner             string    `json:"owner"`
+	FromAccountID     int64     `json:"from_account_id"`
+	ToAccountID       int64     `json:"to_account_id"`
+	BeneficiaryID     int64     `json:"beneficiary_id"`
+	Amount            int64     `json:"amount"`
+	Currency          string    `json:"currency"`
+	Memo              string    `json:"memo"`
+	ExternalReference string    `json:"external_reference"`
+	QueuedAt          time.Time `json:"queued_at"`
+}
+
+// The QueuedTransferTask type is a queued transfer still held by redis.
+// @property {bool} Archived - whether the task ran out of retries, the transfer never being made.
+type QueuedTransferTask struct {
+	Payload  PayloadQueuedTransfer
+	Archived bool
+}
+
+// The QueuedTransferHandler interface makes the queued transfers, it is implemented by the service so that
+// they are checked like any transfer. It must return an error while the database is still unavailable,
+// for the task to be retried, and tolerate being called again for a transfer it already made.
+type QueuedTransferHandler interface {
+	ProcessQueuedTransfer(ctx context.Context, payload *PayloadQueuedTransfer) error
+}
+
+// The `DistributeTaskQueuedTransfer` function enqueues a transfer accepted while the database was
+// unavailable. The task is identified by the tracking ID of the transfer, so a transfer sent again with
+// the same idempotency key is queued once.
+func (distributor *RedisTaskDistributor) DistributeTaskQueuedTransfer(ctx context.Context, payload *PayloadQueuedTransfer, opts ...asynq.Option) error {
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal task payload: %w", err)
+	}
+
+	task := asynq.NewTask(TaskQueuedTransfer, jsonPayload, opts...)
+	return distributor.enqueue(ctx, task, TaskID(TaskQueuedTransfer, payload.ID.String()))
+}
+
+// The `QueuedTransfer` function returns the queued transfer with the tracking ID while redis holds it,
+// or ErrTaskNotFound.
+func (distributor *RedisTaskDistributor) QueuedTransfer(ctx context.Context, id uuid.UUID) (QueuedTransferTask, error) {
+	info, err := distributor.inspector.GetTaskInfo(QueueCritical, TaskID(TaskQueuedTransfer, id.String()))
+	if err != nil {
+		if errors.Is(err, asynq.ErrTaskNotFound) || errors.Is(err, asynq.ErrQueueNotFound) {
+			return QueuedTransferTask{}, ErrTaskNotFound
+		}
+		return QueuedTransferTask{}, fmt.Errorf("failed to get task: %w", err)
+	}
+
+	var task QueuedTransferTask
+	if err := json.Unmarshal(info.Payload, &task.Payload); err != nil {
+		return task, fmt.Errorf("failed to unmarshal task payload: %w", err)
+	}
+	task.Archived = info.State == asynq.TaskStateArchived
+	return task, nil
+}
+
+// The `ProcessTaskQueuedTransfer` function makes a queued transfer with the handler. The task is retried
+// while the handler fails, e.g. until the database is back.
+func (processor *RedisTaskProcessor) ProcessTaskQueuedTransfer(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadQueuedTransfer
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal task payload: %w", asynq.SkipRetry)
+	}
+
+	err := processor.transfers.ProcessQueuedTransfer(ctx, &payload)
+	if err != nil {
+		return fmt.Errorf("failed to process queued transfer: %w", err)
+	}
+
+	log.Printf("processed task: type=%s queued_transfer=%s", task.Type(), payload.ID)
+	return nil
+}
@@ -0,0 +1,87 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	db "go-backend/db/sqlc"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+)
+
+const TaskRunExport = "task:run_export"
+
+type PayloadRunExport struct {
+	JobID uuid.UUID `json:"job_id"`
+}
+
+// The `DistributeTaskRunExport` function enqueues a task generating the export file of a job. The task
+// is identified by the job, so a job is exported once however many times it is enqueued.
+func (distributor *RedisTaskDistributor) DistributeTaskRunExport(ctx context.Context, payload *PayloadRunExport, opts ...asynq.Option) error {
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal task payload: %w", err)
+	}
+
+	task := asynq.NewTask(TaskRunExport, jsonPayload, opts...)
+	return distributor.enqueue(ctx, task, TaskID(TaskRunExport, payload.JobID.String()))
+}
+
+// The `ProcessTaskRunExport` function generates the export file of a job, reporting progress as it goes
+// and stopping early when the job gets cancelled. Export failures are stored on the job and not retried.
+func (processor *RedisTaskProcessor) ProcessTaskRunExport(ctx context.Context, task *asynq.Task) error {
+	var payload PayloadRunExport
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal task payload: %w", asynq.SkipRetry)
+	}
+
+	job, err := processor.store.GetJob(ctx, payload.JobID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return fmt.Errorf("job doesn't exist: %w", asynq.SkipRetry)
+		}
+		return fmt.Errorf("failed to get job: %w", err)
+	}
+
+	if job.Status != JobStatusPending && job.Status != JobStatusRunning {
+		log.Printf("skipped task: type=%s job=%s status=%s", task.Type(), job.ID, job.Status)
+		return nil
+	}
+
+	exporter := &exporter{store: processor.store, job: job}
+	result, err := exporter.run(ctx)
+	if err != nil {
+		if errors.Is(err, errJobCancelled) {
+			log.Printf("cancelled task: type=%s job=%s", task.Type(), job.ID)
+			return nil
+		}
+
+		_, failErr := processor.store.FailJob(ctx, db.FailJobParams{
+			ID:    job.ID,
+			Error: err.Error(),
+		})
+		if failErr != nil && !errors.Is(failErr, db.ErrRecordNotFound) {
+			return fmt.Errorf("failed to mark job as failed: %w", failErr)
+		}
+		return fmt.Errorf("failed to run export: %v: %w", err, asynq.SkipRetry)
+	}
+
+	_, err = processor.store.CompleteJob(ctx, db.CompleteJobParams{
+		ID:                job.ID,
+		Result:            result.data,
+		ResultContentType: result.contentType,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			log.Printf("cancelled task: type=%s job=%s", task.Type(), job.ID)
+			return nil
+		}
+		return fmt.Errorf("failed to complete job: %w", err)
+	}
+
+	log.Printf("processed task: type=%s job=%s kind=%s", task.Type(), job.ID, job.Kind)
+	return nil
+}
@@ -0,0 +1,48 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	mockdb "go-backend/db/mock"
+	db "go-backend/db/sqlc"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessTaskRunExportWithoutAccounts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	job := db.Job{ID: uuid.New(), Username: "alice", Kind: ExportKindGDPR, Status: JobStatusPending, Params: []byte("{}")}
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetJob(gomock.Any(), gomock.Eq(job.ID)).Times(1).Return(job, nil)
+	store.EXPECT().GetUser(gomock.Any(), gomock.Eq(job.Username)).Times(1).Return(db.User{Username: job.Username}, nil)
+	store.EXPECT().ListBeneficiaries(gomock.Any(), gomock.Any()).Times(1).Return([]db.Beneficiary{}, nil)
+	store.EXPECT().GetNotificationPreferences(gomock.Any(), gomock.Eq(job.Username)).Times(1).Return(db.NotificationPreference{}, db.ErrRecordNotFound)
+	store.EXPECT().ListAccounts(gomock.Any(), gomock.Any()).Times(1).Return([]db.Account{}, nil)
+
+	// the job is running before it completes though the export had no progress to report
+	gomock.InOrder(
+		store.EXPECT().
+			UpdateJobProgress(gomock.Any(), gomock.Eq(db.UpdateJobProgressParams{ID: job.ID, Progress: 0})).
+			Times(1).
+			Return(db.Job{ID: job.ID, Status: JobStatusRunning}, nil),
+		store.EXPECT().
+			CompleteJob(gomock.Any(), gomock.Any()).
+			Times(1).
+			DoAndReturn(func(_ context.Context, arg db.CompleteJobParams) (db.Job, error) {
+				require.Equal(t, "application/json", arg.ResultContentType)
+				return db.Job{ID: job.ID, Status: JobStatusSucceeded}, nil
+			}),
+	)
+
+	payload, err := json.Marshal(PayloadRunExport{JobID: job.ID})
+	require.NoError(t, err)
+
+	processor := &RedisTaskProcessor{store: store}
+	require.NoError(t, processor.ProcessTaskRunExport(context.Background(), asynq.NewTask(TaskRunExport, payload)))
+}